@@ -0,0 +1,280 @@
+// Package approval gates destructive commands behind a human confirmation.
+//
+// Each destructive command belongs to a Class (nuke, rollback, rig-remove).
+// The town's settings/config.json maps classes to a Policy:
+//
+//	"approvals": {
+//	  "policies": {"rollback": "token", "rig-remove": "confirm"},
+//	  "token_ttl": "10m"
+//	}
+//
+// Under "confirm", an interactive human can type a confirmation phrase, or
+// the caller can present an approval token. Under "token", only a token is
+// accepted. Tokens are minted by `gt approve <class>` (humans at a terminal
+// only), are single-use, and expire after token_ttl. Unlisted classes are
+// allowed without a gate, so existing automation keeps working until a town
+// opts in.
+package approval
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// EnvToken is the environment variable checked for an approval token when
+// no --approval flag is given.
+const EnvToken = "GT_APPROVAL"
+
+// DefaultTokenTTL is how long minted tokens stay valid.
+const DefaultTokenTTL = 10 * time.Minute
+
+// Class identifies a group of destructive commands sharing a policy.
+type Class string
+
+// Command classes.
+const (
+	ClassNuke      Class = "nuke"       // gt polecat nuke
+	ClassRollback  Class = "rollback"   // gt dolt rollback, gt dolt restore
+	ClassRigRemove Class = "rig-remove" // gt rig remove
+)
+
+// Classes lists all known command classes.
+var Classes = []Class{ClassNuke, ClassRollback, ClassRigRemove}
+
+// ParseClass validates a class name.
+func ParseClass(s string) (Class, error) {
+	for _, c := range Classes {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown operation %q (valid: nuke, rollback, rig-remove)", s)
+}
+
+// Policy controls how a class of commands is gated.
+type Policy string
+
+// Policies.
+const (
+	PolicyAllow   Policy = "allow"   // no gate
+	PolicyConfirm Policy = "confirm" // confirmation phrase or token
+	PolicyToken   Policy = "token"   // token only
+)
+
+// Errors returned by Check.
+var (
+	ErrApprovalRequired = errors.New("approval required")
+	ErrInvalidToken     = errors.New("invalid or expired approval token")
+)
+
+// PolicyFor returns the configured policy for a class. Missing or unknown
+// values fall back to PolicyAllow.
+func PolicyFor(cfg *config.ApprovalsConfig, class Class) Policy {
+	if cfg == nil {
+		return PolicyAllow
+	}
+	switch p := Policy(cfg.Policies[string(class)]); p {
+	case PolicyConfirm, PolicyToken:
+		return p
+	default:
+		return PolicyAllow
+	}
+}
+
+// TokenTTL returns the configured token lifetime.
+func TokenTTL(cfg *config.ApprovalsConfig) time.Duration {
+	if cfg == nil || cfg.TokenTTL == "" {
+		return DefaultTokenTTL
+	}
+	d, err := config.ParseDuration(cfg.TokenTTL)
+	if err != nil || d <= 0 {
+		return DefaultTokenTTL
+	}
+	return d
+}
+
+// LoadConfig reads the approvals section of the town settings.
+func LoadConfig(townRoot string) (*config.ApprovalsConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.Approvals, nil
+}
+
+// agentEnvVars are identity variables gt sets in every agent session
+// (see config.AgentEnv). Humans at their own shell have none of them.
+var agentEnvVars = []string{"GT_ROLE", "GT_RIG", "GT_POLECAT", "GT_CREW", "BD_ACTOR"}
+
+// IsHuman reports whether the caller looks like a human at a terminal:
+// stdin is interactive and no agent identity variable is set. An empty
+// GT_ROLE alone is not enough, since an agent can unset it.
+func IsHuman(getenv func(string) string, interactive bool) bool {
+	if !interactive {
+		return false
+	}
+	for _, key := range agentEnvVars {
+		if getenv(key) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// CanMint reports whether the caller may mint approval tokens. Only a human,
+// as determined by IsHuman, may: roles come from GT_ROLE, which any agent
+// can set as easily as unset, so no role (not even the mayor's) is trusted.
+func CanMint(getenv func(string) string, interactive bool) bool {
+	return IsHuman(getenv, interactive)
+}
+
+// record is a stored token. Only the hash is persisted.
+type record struct {
+	Hash      string    `json:"hash"`
+	Class     Class     `json:"class"`
+	CreatedBy string    `json:"created_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type store struct {
+	Tokens []record `json:"tokens"`
+}
+
+// storePath returns the path of the token store.
+func storePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "approvals.json")
+}
+
+// withStore runs fn with the token store loaded under an exclusive lock and
+// saves the result. Expired tokens are pruned on every access.
+func withStore(townRoot string, now time.Time, fn func(s *store) error) error {
+	path := storePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking approvals: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	var s store
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading approvals: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("parsing approvals: %w", err)
+		}
+	}
+
+	live := s.Tokens[:0]
+	for _, r := range s.Tokens {
+		if now.Before(r.ExpiresAt) {
+			live = append(live, r)
+		}
+	}
+	s.Tokens = live
+
+	if err := fn(&s); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSONWithPerm(path, &s, 0600)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Mint creates a single-use token for class, valid for ttl.
+func Mint(townRoot string, class Class, ttl time.Duration, createdBy string) (string, time.Time, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("generating token: %w", err)
+	}
+	token := string(class) + "-" + hex.EncodeToString(buf)
+
+	now := time.Now()
+	expires := now.Add(ttl)
+	err := withStore(townRoot, now, func(s *store) error {
+		s.Tokens = append(s.Tokens, record{
+			Hash:      hashToken(token),
+			Class:     class,
+			CreatedBy: createdBy,
+			ExpiresAt: expires,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expires, nil
+}
+
+// Consume validates token for class and removes it so it cannot be reused.
+func Consume(townRoot string, class Class, token string) error {
+	hash := hashToken(token)
+	return withStore(townRoot, time.Now(), func(s *store) error {
+		for i, r := range s.Tokens {
+			if r.Hash == hash && r.Class == class {
+				s.Tokens = append(s.Tokens[:i], s.Tokens[i+1:]...)
+				return nil
+			}
+		}
+		return ErrInvalidToken
+	})
+}
+
+// Request describes an attempt to run a gated command.
+type Request struct {
+	TownRoot string
+	Class    Class
+	Target   string // what will be destroyed, shown in the confirmation phrase
+	Token    string // approval token, if any
+
+	// Confirm prompts for the phrase and returns what was typed. Nil when
+	// the caller is not an interactive human (e.g. an agent).
+	Confirm func(phrase string) (string, error)
+}
+
+// Phrase returns the confirmation phrase for a request.
+func (r Request) Phrase() string {
+	return string(r.Class) + " " + r.Target
+}
+
+// Check enforces policy for the request. It returns nil when the command
+// may proceed.
+func Check(policy Policy, req Request) error {
+	if policy == PolicyAllow {
+		return nil
+	}
+	if req.Token != "" {
+		return Consume(req.TownRoot, req.Class, req.Token)
+	}
+	if policy == PolicyConfirm && req.Confirm != nil {
+		typed, err := req.Confirm(req.Phrase())
+		if err != nil {
+			return err
+		}
+		if typed != req.Phrase() {
+			return fmt.Errorf("%w: confirmation phrase did not match", ErrApprovalRequired)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w for %s (policy %q): ask a human to run 'gt approve %s', then pass --approval <token>",
+		ErrApprovalRequired, req.Class, policy, req.Class)
+}
@@ -0,0 +1,156 @@
+package approval
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPolicyFor(t *testing.T) {
+	cfg := &config.ApprovalsConfig{Policies: map[string]string{
+		"nuke":       "confirm",
+		"rollback":   "token",
+		"rig-remove": "bogus",
+	}}
+	tests := []struct {
+		cfg   *config.ApprovalsConfig
+		class Class
+		want  Policy
+	}{
+		{nil, ClassNuke, PolicyAllow},
+		{cfg, ClassNuke, PolicyConfirm},
+		{cfg, ClassRollback, PolicyToken},
+		{cfg, ClassRigRemove, PolicyAllow},
+	}
+	for _, tt := range tests {
+		if got := PolicyFor(tt.cfg, tt.class); got != tt.want {
+			t.Errorf("PolicyFor(%v, %s) = %s, want %s", tt.cfg, tt.class, got, tt.want)
+		}
+	}
+}
+
+func TestTokenTTL(t *testing.T) {
+	if got := TokenTTL(nil); got != DefaultTokenTTL {
+		t.Errorf("TokenTTL(nil) = %v", got)
+	}
+	if got := TokenTTL(&config.ApprovalsConfig{TokenTTL: "2m"}); got != 2*time.Minute {
+		t.Errorf("TokenTTL(2m) = %v", got)
+	}
+	if got := TokenTTL(&config.ApprovalsConfig{TokenTTL: "1d"}); got != 24*time.Hour {
+		t.Errorf("TokenTTL(1d) = %v", got)
+	}
+	if got := TokenTTL(&config.ApprovalsConfig{TokenTTL: "nope"}); got != DefaultTokenTTL {
+		t.Errorf("TokenTTL(invalid) = %v", got)
+	}
+}
+
+func TestMintAndConsume(t *testing.T) {
+	townRoot := t.TempDir()
+
+	token, _, err := Mint(townRoot, ClassRollback, time.Minute, "mayor")
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+
+	// Wrong class is rejected and does not consume the token.
+	if err := Consume(townRoot, ClassNuke, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Consume(wrong class) = %v, want ErrInvalidToken", err)
+	}
+	if err := Consume(townRoot, ClassRollback, token); err != nil {
+		t.Errorf("Consume: %v", err)
+	}
+	// Single use.
+	if err := Consume(townRoot, ClassRollback, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("second Consume = %v, want ErrInvalidToken", err)
+	}
+
+	// Only hashes are stored.
+	data, err := os.ReadFile(filepath.Join(townRoot, ".runtime", "approvals.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), token) {
+		t.Error("plaintext token written to disk")
+	}
+}
+
+func TestConsume_Expired(t *testing.T) {
+	townRoot := t.TempDir()
+	token, _, err := Mint(townRoot, ClassNuke, time.Nanosecond, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := Consume(townRoot, ClassNuke, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Consume(expired) = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	req := Request{TownRoot: townRoot, Class: ClassRigRemove, Target: "gastown"}
+
+	if err := Check(PolicyAllow, req); err != nil {
+		t.Errorf("allow: %v", err)
+	}
+
+	// No token, no interactive human.
+	if err := Check(PolicyConfirm, req); !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("confirm without prompt = %v, want ErrApprovalRequired", err)
+	}
+
+	// Correct phrase.
+	req.Confirm = func(phrase string) (string, error) { return phrase, nil }
+	if err := Check(PolicyConfirm, req); err != nil {
+		t.Errorf("confirm with phrase: %v", err)
+	}
+
+	// Wrong phrase.
+	req.Confirm = func(string) (string, error) { return "yes", nil }
+	if err := Check(PolicyConfirm, req); !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("confirm wrong phrase = %v", err)
+	}
+
+	// Token policy ignores the prompt.
+	req.Confirm = func(phrase string) (string, error) { return phrase, nil }
+	if err := Check(PolicyToken, req); !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("token policy with prompt = %v, want ErrApprovalRequired", err)
+	}
+
+	token, _, err := Mint(townRoot, ClassRigRemove, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Token = token
+	if err := Check(PolicyToken, req); err != nil {
+		t.Errorf("token policy with token: %v", err)
+	}
+}
+
+func TestCanMint(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		interactive bool
+		want        bool
+	}{
+		{"human at terminal", nil, true, true},
+		{"no terminal", nil, false, false},
+		{"mayor", map[string]string{"GT_ROLE": "mayor"}, true, false},
+		{"claimed mayor without other agent vars", map[string]string{"GT_ROLE": "mayor"}, false, false},
+		{"polecat", map[string]string{"GT_ROLE": "gastown/polecats/toast"}, true, false},
+		{"agent with GT_ROLE unset", map[string]string{"GT_RIG": "gastown", "GT_POLECAT": "toast"}, true, false},
+		{"crew with only BD_ACTOR", map[string]string{"BD_ACTOR": "gastown/crew/max"}, true, false},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := CanMint(getenv, tt.interactive); got != tt.want {
+			t.Errorf("%s: CanMint = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
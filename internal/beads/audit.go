@@ -24,6 +24,11 @@ type DetachOptions struct {
 	Operation string // "detach", "burn", "squash" - defaults to "detach"
 	Agent     string // Who is performing the detach
 	Reason    string // Optional reason for the detach
+
+	// ExpectedMolecule, if set, is the molecule the caller read as attached.
+	// The detach fails with an *AttachmentConflictError if a different
+	// molecule has been attached since, rather than detaching it.
+	ExpectedMolecule string
 }
 
 // DetachMoleculeWithAudit removes molecule attachment from a pinned bead and logs the operation.
@@ -48,6 +53,11 @@ func (b *Beads) DetachMoleculeWithAudit(pinnedBeadID string, opts DetachOptions)
 	if attachment == nil {
 		return issue, nil // Nothing to detach
 	}
+	if opts.ExpectedMolecule != "" {
+		if err := checkAttachment(pinnedBeadID, attachment, opts.ExpectedMolecule, ""); err != nil {
+			return nil, err
+		}
+	}
 
 	// Log the detach operation
 	operation := opts.Operation
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/runtime"
 )
 
@@ -50,6 +51,7 @@ type Issue struct {
 	CreatedBy   string   `json:"created_by,omitempty"`
 	UpdatedAt   string   `json:"updated_at"`
 	ClosedAt    string   `json:"closed_at,omitempty"`
+	CloseReason string   `json:"close_reason,omitempty"`
 	Parent      string   `json:"parent,omitempty"`
 	Assignee    string   `json:"assignee,omitempty"`
 	Children    []string `json:"children,omitempty"`
@@ -244,7 +246,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.RunCmd(cmd)
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
@@ -288,7 +290,7 @@ func (b *Beads) runWithRouting(args ...string) ([]byte, error) { //nolint:unpara
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.RunCmd(cmd)
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
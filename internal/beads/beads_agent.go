@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gofrs/flock"
 )
@@ -39,6 +40,7 @@ type AgentFields struct {
 	CleanupStatus     string // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
 	ActiveMR          string // Currently active merge request bead ID (for traceability)
 	NotificationLevel string // DND mode: verbose, normal, muted (default: normal)
+	LastHeartbeat     string // RFC3339 time of the agent's last `gt heartbeat` (empty if never)
 	// Note: RoleBead field removed - role definitions are now config-based.
 	// See internal/config/roles/*.toml and config-based-roles.md.
 }
@@ -95,6 +97,12 @@ func FormatAgentDescription(title string, fields *AgentFields) string {
 		lines = append(lines, "notification_level: null")
 	}
 
+	// Only written once an agent has sent a heartbeat, so agents that never
+	// do keep their existing description.
+	if fields.LastHeartbeat != "" {
+		lines = append(lines, fmt.Sprintf("last_heartbeat: %s", fields.LastHeartbeat))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -136,12 +144,45 @@ func ParseAgentFields(description string) *AgentFields {
 			fields.ActiveMR = value
 		case "notification_level":
 			fields.NotificationLevel = value
+		case "last_heartbeat":
+			fields.LastHeartbeat = value
 		}
 	}
 
 	return fields
 }
 
+// HeartbeatStaleThreshold is how old an agent's heartbeat may get while it
+// has hooked work before patrols treat the agent as wedged.
+const HeartbeatStaleThreshold = 30 * time.Minute
+
+// ParseHeartbeat parses a last_heartbeat value. ok is false if the agent has
+// never sent a heartbeat (or the value is unparseable), in which case callers
+// fall back to tmux liveness.
+func ParseHeartbeat(value string) (t time.Time, ok bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// HeartbeatAge returns how long ago the agent last sent a heartbeat.
+// ok is false if there is no usable heartbeat (see ParseHeartbeat).
+func (f *AgentFields) HeartbeatAge(now time.Time) (age time.Duration, ok bool) {
+	if f == nil {
+		return 0, false
+	}
+	t, ok := ParseHeartbeat(f.LastHeartbeat)
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(t), true
+}
+
 // CreateAgentBead creates an agent bead for tracking agent lifecycle.
 // The ID format is: <prefix>-<rig>-<role>-<name> (e.g., gt-gastown-polecat-Toast)
 // Use AgentBeadID() helper to generate correct IDs.
@@ -345,6 +386,7 @@ func (b *Beads) ResetAgentBeadForReuse(id, reason string) error {
 	fields.ActiveMR = ""      // Clear active_mr
 	fields.CleanupStatus = "" // Clear cleanup_status
 	fields.AgentState = "nuked"
+	fields.LastHeartbeat = ""
 
 	// Update description with cleared fields
 	description := FormatAgentDescription(issue.Title, fields)
@@ -448,6 +490,7 @@ type AgentFieldUpdates struct {
 	CleanupStatus     *string
 	ActiveMR          *string
 	NotificationLevel *string
+	LastHeartbeat     *string
 }
 
 // UpdateAgentDescriptionFields atomically updates one or more agent description
@@ -488,6 +531,9 @@ func (b *Beads) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdate
 	if updates.NotificationLevel != nil {
 		fields.NotificationLevel = *updates.NotificationLevel
 	}
+	if updates.LastHeartbeat != nil {
+		fields.LastHeartbeat = *updates.LastHeartbeat
+	}
 
 	description := FormatAgentDescription(issue.Title, fields)
 	return b.Update(id, UpdateOptions{Description: &description})
@@ -514,6 +560,13 @@ func (b *Beads) UpdateAgentNotificationLevel(id string, level string) error {
 	return b.UpdateAgentDescriptionFields(id, AgentFieldUpdates{NotificationLevel: &level})
 }
 
+// UpdateAgentHeartbeat records that the agent is alive and making progress
+// by setting the last_heartbeat field to at.
+func (b *Beads) UpdateAgentHeartbeat(id string, at time.Time) error {
+	ts := at.UTC().Format(time.RFC3339)
+	return b.UpdateAgentDescriptionFields(id, AgentFieldUpdates{LastHeartbeat: &ts})
+}
+
 // GetAgentNotificationLevel returns the notification level for an agent.
 // Returns "normal" if not set (the default).
 func (b *Beads) GetAgentNotificationLevel(id string) (string, error) {
@@ -594,6 +647,7 @@ func (b *Beads) CloseAndClearAgentBead(id, reason string) error {
 	fields.ActiveMR = ""     // Clear active_mr
 	fields.CleanupStatus = "" // Clear cleanup_status
 	fields.AgentState = "closed"
+	fields.LastHeartbeat = ""
 
 	// Update description with cleared fields
 	description := FormatAgentDescription(issue.Title, fields)
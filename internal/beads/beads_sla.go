@@ -0,0 +1,141 @@
+// Package beads provides SLA timers on beads.
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SLA labels. A bead with timers carries LabelSLA, so beads with timers can
+// be listed with an exact label filter, plus "sla:<name>:<deadline>" per
+// timer and "sla-breached:<name>" once a breach has been flagged.
+const (
+	LabelSLA          = "gt:sla"
+	slaPrefix         = "sla:"
+	slaBreachedPrefix = "sla-breached:"
+)
+
+// SLATimer is a named deadline on a bead, such as "review" due 24h after
+// the bead was submitted.
+type SLATimer struct {
+	Name     string    `json:"name"`
+	Deadline time.Time `json:"deadline"`
+	Breached bool      `json:"breached,omitempty"` // Breach already flagged
+}
+
+// Overdue reports whether the timer's deadline has passed.
+func (t SLATimer) Overdue(now time.Time) bool {
+	return !now.Before(t.Deadline)
+}
+
+// ValidateSLAName checks that name can be used in an SLA label.
+func ValidateSLAName(name string) error {
+	if name == "" {
+		return fmt.Errorf("SLA name must not be empty")
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("invalid SLA name %q: use lowercase letters, digits, - and _", name)
+		}
+	}
+	return nil
+}
+
+// ParseSLATimers reads a bead's SLA timers from its labels, sorted by
+// deadline.
+func ParseSLATimers(labels []string) []SLATimer {
+	breached := make(map[string]bool)
+	for _, l := range labels {
+		if strings.HasPrefix(l, slaBreachedPrefix) {
+			breached[strings.TrimPrefix(l, slaBreachedPrefix)] = true
+		}
+	}
+	var timers []SLATimer
+	for _, l := range labels {
+		if !strings.HasPrefix(l, slaPrefix) {
+			continue
+		}
+		name, deadline, ok := strings.Cut(strings.TrimPrefix(l, slaPrefix), ":")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(labelTimeFormat, deadline)
+		if err != nil {
+			continue
+		}
+		timers = append(timers, SLATimer{Name: name, Deadline: t, Breached: breached[name]})
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].Deadline.Before(timers[j].Deadline) })
+	return timers
+}
+
+func slaLabel(name string, deadline time.Time) string {
+	return slaPrefix + name + ":" + deadline.UTC().Format(labelTimeFormat)
+}
+
+// slaTimerLabels returns the labels of the named timer among labels.
+func slaTimerLabels(labels []string, name string) []string {
+	var out []string
+	for _, l := range labels {
+		if strings.HasPrefix(l, slaPrefix+name+":") || l == slaBreachedPrefix+name {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// SetSLA starts (or restarts) the named SLA timer on a bead.
+func (b *Beads) SetSLA(id, name string, deadline time.Time) error {
+	if err := ValidateSLAName(name); err != nil {
+		return err
+	}
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	return b.Update(id, UpdateOptions{
+		RemoveLabels: slaTimerLabels(issue.Labels, name),
+		AddLabels:    []string{LabelSLA, slaLabel(name, deadline)},
+	})
+}
+
+// ClearSLA stops the named SLA timer on a bead, as when the work it tracks
+// is done. Returns false if the bead had no such timer.
+func (b *Beads) ClearSLA(id, name string) (bool, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return false, err
+	}
+	remove := slaTimerLabels(issue.Labels, name)
+	if len(remove) == 0 {
+		return false, nil
+	}
+	if len(ParseSLATimers(issue.Labels)) == 1 {
+		remove = append(remove, LabelSLA)
+	}
+	return true, b.Update(id, UpdateOptions{RemoveLabels: remove})
+}
+
+// MarkSLABreached records that the named timer's breach has been flagged,
+// so it is escalated once.
+func (b *Beads) MarkSLABreached(id, name string) error {
+	return b.Update(id, UpdateOptions{AddLabels: []string{slaBreachedPrefix + name}})
+}
+
+// ListSLA returns the beads in this database with SLA timers that are not
+// closed.
+func (b *Beads) ListSLA() ([]*Issue, error) {
+	issues, err := b.List(ListOptions{Label: LabelSLA, Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	open := issues[:0]
+	for _, issue := range issues {
+		if issue.Status != "closed" {
+			open = append(open, issue)
+		}
+	}
+	return open, nil
+}
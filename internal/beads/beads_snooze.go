@@ -0,0 +1,135 @@
+// Package beads provides bead snoozing.
+package beads
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Snooze labels. A snoozed bead carries LabelSnoozed, so snoozed beads can
+// be listed with an exact label filter, plus one label per wake condition.
+const (
+	LabelSnoozed      = "gt:snoozed"
+	snoozeUntilPrefix = "snooze-until:"
+	snoozeOnPrefix    = "snooze-on:"
+)
+
+// labelTimeFormat is the timestamp format used in timer labels, always in
+// UTC. It has no letters, so label case folding can't corrupt it.
+const labelTimeFormat = "20060102-150405"
+
+// Snooze is when a snoozed bead returns to ready queues: once Until passes
+// or once the OnBead bead closes, whichever is set and happens first.
+type Snooze struct {
+	Until  time.Time `json:"until,omitzero"`
+	OnBead string    `json:"on_bead,omitempty"`
+}
+
+// ParseSnooze reads a bead's snooze from its labels. Returns false if the
+// bead isn't snoozed.
+func ParseSnooze(labels []string) (Snooze, bool) {
+	var s Snooze
+	snoozed := false
+	for _, l := range labels {
+		switch {
+		case l == LabelSnoozed:
+			snoozed = true
+		case strings.HasPrefix(l, snoozeUntilPrefix):
+			if t, err := time.Parse(labelTimeFormat, strings.TrimPrefix(l, snoozeUntilPrefix)); err == nil {
+				s.Until = t
+			}
+		case strings.HasPrefix(l, snoozeOnPrefix):
+			s.OnBead = strings.TrimPrefix(l, snoozeOnPrefix)
+		}
+	}
+	return s, snoozed
+}
+
+// Labels returns the labels that record s on a bead.
+func (s Snooze) Labels() []string {
+	labels := []string{LabelSnoozed}
+	if !s.Until.IsZero() {
+		labels = append(labels, snoozeUntilPrefix+s.Until.UTC().Format(labelTimeFormat))
+	}
+	if s.OnBead != "" {
+		labels = append(labels, snoozeOnPrefix+s.OnBead)
+	}
+	return labels
+}
+
+// Awake reports whether a wake condition has been met. closed reports
+// whether a bead is closed; it is only called when OnBead is set.
+func (s Snooze) Awake(now time.Time, closed func(id string) bool) bool {
+	if !s.Until.IsZero() && !now.Before(s.Until) {
+		return true
+	}
+	if s.OnBead != "" && closed(s.OnBead) {
+		return true
+	}
+	return s.Until.IsZero() && s.OnBead == ""
+}
+
+// snoozeLabels returns the snooze labels among labels.
+func snoozeLabels(labels []string) []string {
+	var out []string
+	for _, l := range labels {
+		if l == LabelSnoozed || strings.HasPrefix(l, snoozeUntilPrefix) || strings.HasPrefix(l, snoozeOnPrefix) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// SnoozeIssue hides a bead from ready queues until s wakes it, replacing
+// any earlier snooze.
+func (b *Beads) SnoozeIssue(id string, s Snooze) error {
+	if s.Until.IsZero() && s.OnBead == "" {
+		return fmt.Errorf("snooze needs a time or a bead to wait for")
+	}
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	if issue.Status == "closed" {
+		return fmt.Errorf("%s is closed", id)
+	}
+	return b.Update(id, UpdateOptions{
+		RemoveLabels: snoozeLabels(issue.Labels),
+		AddLabels:    s.Labels(),
+	})
+}
+
+// UnsnoozeIssue returns a snoozed bead to ready queues. Returns false if the
+// bead wasn't snoozed.
+func (b *Beads) UnsnoozeIssue(id string) (bool, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return false, err
+	}
+	labels := snoozeLabels(issue.Labels)
+	if len(labels) == 0 {
+		return false, nil
+	}
+	return true, b.Update(id, UpdateOptions{RemoveLabels: labels})
+}
+
+// SnoozedIDs returns the IDs of beads in this database that are snoozed and
+// not yet awake. A bead whose wake-on bead can't be read stays snoozed.
+func (b *Beads) SnoozedIDs(now time.Time) (map[string]bool, error) {
+	issues, err := b.List(ListOptions{Label: LabelSnoozed, Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	closed := func(id string) bool {
+		issue, err := b.Show(id)
+		return err == nil && issue.Status == "closed"
+	}
+	ids := make(map[string]bool)
+	for _, issue := range issues {
+		if s, ok := ParseSnooze(issue.Labels); ok && !s.Awake(now, closed) {
+			ids[issue.ID] = true
+		}
+	}
+	return ids, nil
+}
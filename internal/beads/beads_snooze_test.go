@@ -0,0 +1,87 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozeLabelsRoundTrip(t *testing.T) {
+	s := Snooze{Until: time.Date(2026, 3, 12, 9, 30, 0, 0, time.UTC), OnBead: "gt-xyz89"}
+	labels := append([]string{"gt:task"}, s.Labels()...)
+	got, ok := ParseSnooze(labels)
+	if !ok || !got.Until.Equal(s.Until) || got.OnBead != s.OnBead {
+		t.Errorf("ParseSnooze(%v) = %+v, %v; want %+v", labels, got, ok, s)
+	}
+	if _, ok := ParseSnooze([]string{"gt:task"}); ok {
+		t.Error("bead without gt:snoozed reported as snoozed")
+	}
+	if got := snoozeLabels(labels); len(got) != 3 {
+		t.Errorf("snoozeLabels = %v, want the 3 snooze labels", got)
+	}
+}
+
+func TestSnoozeAwake(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	closed := map[string]bool{"gt-done": true}
+	isClosed := func(id string) bool { return closed[id] }
+
+	tests := []struct {
+		name string
+		s    Snooze
+		want bool
+	}{
+		{"time not reached", Snooze{Until: now.Add(time.Hour)}, false},
+		{"time passed", Snooze{Until: now.Add(-time.Minute)}, true},
+		{"waiting bead open", Snooze{OnBead: "gt-open"}, false},
+		{"waiting bead closed", Snooze{OnBead: "gt-done"}, true},
+		{"bead closed before time", Snooze{Until: now.Add(time.Hour), OnBead: "gt-done"}, true},
+		{"no condition", Snooze{}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Awake(now, isClosed); got != tt.want {
+			t.Errorf("%s: Awake = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSLATimers(t *testing.T) {
+	review := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	triage := time.Date(2026, 3, 10, 16, 0, 0, 0, time.UTC)
+	labels := []string{
+		LabelSLA,
+		slaLabel("review", review),
+		slaLabel("triage", triage),
+		slaBreachedPrefix + "triage",
+		"sla:bogus",
+		"sla:bad:not-a-time",
+	}
+	timers := ParseSLATimers(labels)
+	if len(timers) != 2 {
+		t.Fatalf("got %d timers, want 2: %+v", len(timers), timers)
+	}
+	if timers[0].Name != "triage" || !timers[0].Breached || !timers[0].Deadline.Equal(triage) {
+		t.Errorf("timers[0] = %+v, want breached triage first", timers[0])
+	}
+	if timers[1].Name != "review" || timers[1].Breached {
+		t.Errorf("timers[1] = %+v, want unbreached review", timers[1])
+	}
+	if !timers[0].Overdue(triage) || timers[1].Overdue(triage) {
+		t.Error("Overdue should be true from the deadline on")
+	}
+	if got := slaTimerLabels(labels, "triage"); len(got) != 2 {
+		t.Errorf("slaTimerLabels(triage) = %v, want deadline and breached labels", got)
+	}
+}
+
+func TestValidateSLAName(t *testing.T) {
+	for _, name := range []string{"review", "first-response", "p0_fix"} {
+		if err := ValidateSLAName(name); err != nil {
+			t.Errorf("ValidateSLAName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Review", "a:b", "has space"} {
+		if err := ValidateSLAName(name); err == nil {
+			t.Errorf("ValidateSLAName(%q) succeeded, want error", name)
+		}
+	}
+}
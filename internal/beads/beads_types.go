@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // typesSentinel is a marker file indicating custom types have been configured.
@@ -111,7 +112,7 @@ func EnsureCustomTypes(beadsDir string) error {
 	cmd.Dir = beadsDir
 	// Set BEADS_DIR explicitly to ensure bd operates on the correct database
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := proc.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("configure custom types in %s: %s: %w",
 			beadsDir, strings.TrimSpace(string(output)), err)
 	}
@@ -1,7 +1,9 @@
 package beads
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 // --- SynthesisFields (not covered in beads_test.go) ---
@@ -253,6 +255,33 @@ func TestParseAgentFieldsFromDescription(t *testing.T) {
 	}
 }
 
+func TestAgentFieldsLastHeartbeat(t *testing.T) {
+	// Agents that never sent a heartbeat keep the existing description.
+	desc := FormatAgentDescription("Polecat Toast", &AgentFields{RoleType: "polecat", Rig: "gastown"})
+	if strings.Contains(desc, "last_heartbeat") {
+		t.Errorf("description without heartbeat should omit last_heartbeat:\n%s", desc)
+	}
+	if _, ok := ParseAgentFields(desc).HeartbeatAge(time.Now()); ok {
+		t.Error("HeartbeatAge ok = true for agent without heartbeat")
+	}
+
+	// The RFC3339 value contains colons; it must survive a round trip.
+	fields := &AgentFields{RoleType: "polecat", Rig: "gastown", LastHeartbeat: "2026-01-02T15:04:05Z"}
+	got := ParseAgentFields(FormatAgentDescription("Polecat Toast", fields))
+	if got.LastHeartbeat != fields.LastHeartbeat {
+		t.Fatalf("LastHeartbeat = %q, want %q", got.LastHeartbeat, fields.LastHeartbeat)
+	}
+	now := time.Date(2026, 1, 2, 15, 14, 5, 0, time.UTC)
+	age, ok := got.HeartbeatAge(now)
+	if !ok || age != 10*time.Minute {
+		t.Errorf("HeartbeatAge = %v, %v; want 10m, true", age, ok)
+	}
+
+	if _, ok := (&AgentFields{LastHeartbeat: "garbage"}).HeartbeatAge(now); ok {
+		t.Error("HeartbeatAge ok = true for unparseable value")
+	}
+}
+
 // helper - strings.Contains alias for readability in checks
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 || indexSubstring(s, substr) >= 0)
@@ -2,6 +2,7 @@
 package beads
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -164,11 +165,78 @@ func (b *Beads) lockBead(beadID string) (func(), error) {
 	return lock.FlockAcquire(lockPath)
 }
 
-// AttachMolecule attaches a molecule to a pinned bead by updating its description.
-// The moleculeID is the root issue ID of the molecule to attach.
-// Uses advisory file locking to prevent concurrent read-modify-write races.
+// ErrAttachmentConflict is matched (via errors.Is) by every
+// *AttachmentConflictError.
+var ErrAttachmentConflict = errors.New("molecule attachment conflict")
+
+// AttachmentConflictError reports that a bead's attachment was not what the
+// caller expected, typically because another agent attached or detached a
+// molecule in the meantime.
+type AttachmentConflictError struct {
+	BeadID     string
+	Expected   string // Molecule the caller expected attached ("" = none)
+	Current    string // Molecule actually attached ("" = none)
+	AttachedAt string // When Current was attached
+}
+
+func (e *AttachmentConflictError) Error() string {
+	switch {
+	case e.Current == "":
+		return fmt.Sprintf("%s: expected molecule %s attached, but nothing is", e.BeadID, e.Expected)
+	case e.Expected == "":
+		return fmt.Sprintf("%s already has molecule %s attached (since %s)", e.BeadID, e.Current, e.AttachedAt)
+	default:
+		return fmt.Sprintf("%s has molecule %s attached (since %s), expected %s", e.BeadID, e.Current, e.AttachedAt, e.Expected)
+	}
+}
+
+// Is makes errors.Is(err, ErrAttachmentConflict) true.
+func (e *AttachmentConflictError) Is(target error) bool {
+	return target == ErrAttachmentConflict
+}
+
+// AttachOptions are the optimistic-concurrency expectations for AttachMoleculeWithOptions.
+type AttachOptions struct {
+	// ExpectedMolecule is the molecule the caller believes is attached now
+	// ("" = none). Attaching fails with a conflict if it isn't.
+	ExpectedMolecule string
+	// ExpectedAttachedAt, if set, must also match the current attached_at,
+	// catching a detach and re-attach of the same molecule.
+	ExpectedAttachedAt string
+	// Force replaces whatever is attached without checking.
+	Force bool
+}
+
+// checkAttachment returns an *AttachmentConflictError if current (nil when
+// nothing is attached) doesn't match what the caller expected.
+func checkAttachment(beadID string, current *AttachmentFields, expected, expectedAt string) error {
+	var cur, curAt string
+	if current != nil {
+		cur, curAt = current.AttachedMolecule, current.AttachedAt
+	}
+	if cur != expected || (expectedAt != "" && curAt != expectedAt) {
+		return &AttachmentConflictError{BeadID: beadID, Expected: expected, Current: cur, AttachedAt: curAt}
+	}
+	return nil
+}
+
+// AttachMolecule attaches a molecule to a pinned bead that has none attached.
+// Re-attaching the molecule that is already attached is a no-op; a different
+// attached molecule is an *AttachmentConflictError.
 // Returns the updated issue.
 func (b *Beads) AttachMolecule(pinnedBeadID, moleculeID string) (*Issue, error) {
+	return b.AttachMoleculeWithOptions(pinnedBeadID, moleculeID, AttachOptions{})
+}
+
+// AttachMoleculeWithOptions attaches a molecule to a pinned bead by updating
+// its description. The moleculeID is the root issue ID of the molecule to attach.
+//
+// Uses advisory file locking to serialize concurrent read-modify-write
+// attach/detach operations, checks the current attachment against opts
+// under the lock, and re-reads the bead after writing to catch writers that
+// bypassed the lock. Any mismatch is an *AttachmentConflictError.
+// Returns the updated issue.
+func (b *Beads) AttachMoleculeWithOptions(pinnedBeadID, moleculeID string, opts AttachOptions) (*Issue, error) {
 	// Acquire per-bead lock to serialize concurrent attach/detach operations
 	unlock, err := b.lockBead(pinnedBeadID)
 	if err != nil {
@@ -187,6 +255,16 @@ func (b *Beads) AttachMolecule(pinnedBeadID, moleculeID string) (*Issue, error)
 		return nil, fmt.Errorf("issue %s is not pinned (status: %s)", pinnedBeadID, issue.Status)
 	}
 
+	current := ParseAttachmentFields(issue)
+	if !opts.Force {
+		if current != nil && current.AttachedMolecule == moleculeID && opts.ExpectedMolecule == "" {
+			return issue, nil // Already attached (e.g., a retried attach)
+		}
+		if err := checkAttachment(pinnedBeadID, current, opts.ExpectedMolecule, opts.ExpectedAttachedAt); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build attachment fields with current timestamp
 	fields := &AttachmentFields{
 		AttachedMolecule: moleculeID,
@@ -201,8 +279,15 @@ func (b *Beads) AttachMolecule(pinnedBeadID, moleculeID string) (*Issue, error)
 		return nil, fmt.Errorf("updating pinned bead: %w", err)
 	}
 
-	// Re-fetch to return updated state
-	return b.Show(pinnedBeadID)
+	// Re-fetch to return updated state, and verify our write stuck
+	updated, err := b.Show(pinnedBeadID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAttachment(pinnedBeadID, ParseAttachmentFields(updated), moleculeID, fields.AttachedAt); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 // DetachMolecule removes molecule attachment from a pinned bead.
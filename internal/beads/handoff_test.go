@@ -1,6 +1,8 @@
 package beads
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -65,3 +67,40 @@ func TestClearMailResultZeroValues(t *testing.T) {
 		t.Errorf("expected zero values, got Closed=%d Cleared=%d", result.Closed, result.Cleared)
 	}
 }
+
+func TestCheckAttachment(t *testing.T) {
+	attached := &AttachmentFields{AttachedMolecule: "mol-a", AttachedAt: "2026-01-01T00:00:00Z"}
+
+	tests := []struct {
+		name       string
+		current    *AttachmentFields
+		expected   string
+		expectedAt string
+		conflict   string // substring of the error, "" for no conflict
+	}{
+		{"nothing attached, none expected", nil, "", "", ""},
+		{"expected molecule attached", attached, "mol-a", "", ""},
+		{"expected molecule and time", attached, "mol-a", "2026-01-01T00:00:00Z", ""},
+		{"double attach", attached, "", "", "already has molecule mol-a attached"},
+		{"different molecule", attached, "mol-b", "", "expected mol-b"},
+		{"detached meanwhile", nil, "mol-a", "", "but nothing is"},
+		{"re-attached meanwhile", attached, "mol-a", "2025-12-31T00:00:00Z", "expected mol-a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAttachment("gt-hook", tt.current, tt.expected, tt.expectedAt)
+			if tt.conflict == "" {
+				if err != nil {
+					t.Errorf("checkAttachment() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrAttachmentConflict) {
+				t.Fatalf("checkAttachment() = %v, want ErrAttachmentConflict", err)
+			}
+			if !strings.Contains(err.Error(), tt.conflict) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.conflict)
+			}
+		})
+	}
+}
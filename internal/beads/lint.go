@@ -0,0 +1,197 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LintSeverity ranks how serious a convention violation is.
+type LintSeverity string
+
+// Lint severities.
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintRule describes one bead convention.
+type LintRule struct {
+	Name        string       `json:"name"`
+	Severity    LintSeverity `json:"severity"`
+	Description string       `json:"description"`
+	Fixable     bool         `json:"fixable"` // auto-fix is safe for this rule
+}
+
+// Lint rule names.
+const (
+	LintRuleAgentLabel   = "agent-label"
+	LintRuleOrphanedWisp = "orphaned-wisp"
+	LintRuleOrphanedStep = "orphaned-step"
+	LintRuleHandoffOwner = "handoff-owner"
+	LintRuleIDPrefix     = "id-prefix"
+)
+
+// LintRules lists every rule in reporting order.
+var LintRules = []LintRule{
+	{LintRuleIDPrefix, LintError, "Bead ID prefix matches the rig's beads prefix", false},
+	{LintRuleOrphanedStep, LintError, "Molecule steps have an existing parent", false},
+	{LintRuleAgentLabel, LintWarning, "Agent beads carry the gt:agent label", true},
+	{LintRuleOrphanedWisp, LintWarning, "Open wisps belong to an open parent", true},
+	{LintRuleHandoffOwner, LintWarning, "Handoff beads have an owner", true},
+}
+
+// LookupLintRule returns the rule with the given name.
+func LookupLintRule(name string) (LintRule, bool) {
+	for _, r := range LintRules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return LintRule{}, false
+}
+
+// LintFinding is a single convention violation.
+type LintFinding struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	BeadID   string       `json:"bead"`
+	Message  string       `json:"message"`
+	Fixable  bool         `json:"fixable"`
+
+	// FixError is why --fix failed to apply the fix, if it did.
+	FixError string `json:"fix_error,omitempty"`
+
+	// fix applies the correction; nil when the finding can't be auto-fixed.
+	fix func(b *Beads) error
+}
+
+// Fix applies the finding's safe auto-fix.
+func (f LintFinding) Fix(b *Beads) error {
+	if f.fix == nil {
+		return fmt.Errorf("%s: no safe fix for rule %s", f.BeadID, f.Rule)
+	}
+	return f.fix(b)
+}
+
+// LintOptions configures LintIssues.
+type LintOptions struct {
+	// Prefix is the rig's beads prefix without the trailing hyphen
+	// (e.g. "gt"). The id-prefix rule is skipped when empty.
+	Prefix string
+
+	// Rules limits linting to the named rules. Empty runs all rules.
+	Rules []string
+}
+
+// LintIssues checks a rig's issues against bead conventions. issues should
+// be the full set for the rig (all statuses), so parent lookups resolve.
+func LintIssues(issues []*Issue, opts LintOptions) []LintFinding {
+	enabled := func(name string) bool {
+		if len(opts.Rules) == 0 {
+			return true
+		}
+		for _, r := range opts.Rules {
+			if r == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	byID := make(map[string]*Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+
+	var findings []LintFinding
+	add := func(rule string, issue *Issue, msg string, fix func(b *Beads) error) {
+		r, _ := LookupLintRule(rule)
+		findings = append(findings, LintFinding{
+			Rule:     rule,
+			Severity: r.Severity,
+			BeadID:   issue.ID,
+			Message:  msg,
+			Fixable:  r.Fixable && fix != nil,
+			fix:      fix,
+		})
+	}
+
+	for _, issue := range issues {
+		if enabled(LintRuleIDPrefix) && opts.Prefix != "" {
+			if got := ExtractPrefix(issue.ID); got != opts.Prefix+"-" {
+				add(LintRuleIDPrefix, issue,
+					fmt.Sprintf("prefix %q does not match rig prefix %q", strings.TrimSuffix(got, "-"), opts.Prefix), nil)
+			}
+		}
+
+		if enabled(LintRuleAgentLabel) && issue.Type == "agent" && !HasLabel(issue, "gt:agent") {
+			id := issue.ID
+			add(LintRuleAgentLabel, issue, "agent bead is missing the gt:agent label", func(b *Beads) error {
+				return b.Update(id, UpdateOptions{AddLabels: []string{"gt:agent"}})
+			})
+		}
+
+		if enabled(LintRuleOrphanedWisp) && issue.Ephemeral && isOpenStatus(issue.Status) && issue.Parent != "" {
+			parent, ok := byID[issue.Parent]
+			if !ok || parent.Status == "closed" {
+				reason := "parent " + issue.Parent + " does not exist"
+				if ok {
+					reason = "parent " + issue.Parent + " is closed"
+				}
+				id := issue.ID
+				add(LintRuleOrphanedWisp, issue, "open wisp whose "+reason, func(b *Beads) error {
+					return b.CloseWithReason("gt lint: orphaned wisp", id)
+				})
+			}
+		}
+
+		if enabled(LintRuleOrphanedStep) && isMoleculeStep(issue) {
+			if issue.Parent == "" {
+				add(LintRuleOrphanedStep, issue, "molecule step has no parent", nil)
+			} else if _, ok := byID[issue.Parent]; !ok {
+				add(LintRuleOrphanedStep, issue, "molecule step's parent "+issue.Parent+" does not exist", nil)
+			}
+		}
+
+		if enabled(LintRuleHandoffOwner) && isHandoffBead(issue) && issue.Assignee == "" && issue.CreatedBy == "" {
+			role := strings.TrimSpace(strings.TrimSuffix(issue.Title, " Handoff"))
+			var fix func(b *Beads) error
+			if role != "" {
+				id := issue.ID
+				fix = func(b *Beads) error {
+					return b.Update(id, UpdateOptions{Assignee: &role})
+				}
+			}
+			add(LintRuleHandoffOwner, issue, "handoff bead has no assignee or creator", fix)
+		}
+	}
+
+	order := make(map[string]int, len(LintRules))
+	for i, r := range LintRules {
+		order[r.Name] = i
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Rule != findings[j].Rule {
+			return order[findings[i].Rule] < order[findings[j].Rule]
+		}
+		return findings[i].BeadID < findings[j].BeadID
+	})
+	return findings
+}
+
+func isOpenStatus(status string) bool {
+	return status != "closed" && status != "tombstone"
+}
+
+// isMoleculeStep reports whether an issue was instantiated from a molecule
+// template (see InstantiateMolecule's provenance metadata).
+func isMoleculeStep(issue *Issue) bool {
+	return strings.Contains(issue.Description, "instantiated_from: ")
+}
+
+// isHandoffBead reports whether an issue is a role handoff bead
+// (see HandoffBeadTitle).
+func isHandoffBead(issue *Issue) bool {
+	return issue.Status == StatusPinned && strings.HasSuffix(issue.Title, " Handoff")
+}
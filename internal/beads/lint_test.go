@@ -0,0 +1,66 @@
+package beads
+
+import "testing"
+
+func lintRules(findings []LintFinding) map[string]string {
+	got := make(map[string]string)
+	for _, f := range findings {
+		got[f.BeadID] = f.Rule
+	}
+	return got
+}
+
+func TestLintIssues(t *testing.T) {
+	issues := []*Issue{
+		{ID: "gt-agent-ok", Type: "agent", Labels: []string{"gt:agent"}},
+		{ID: "gt-agent-bad", Type: "agent"},
+		{ID: "gt-mol", Status: "closed"},
+		{ID: "gt-wisp-closed-parent", Ephemeral: true, Status: "open", Parent: "gt-mol"},
+		{ID: "gt-wisp-missing-parent", Ephemeral: true, Status: "open", Parent: "gt-gone"},
+		{ID: "gt-wisp-done", Ephemeral: true, Status: "closed", Parent: "gt-gone"},
+		{ID: "gt-step-ok", Parent: "gt-mol", Description: "instantiated_from: gt-tmpl\ntemplate_step: s1"},
+		{ID: "gt-step-bad", Description: "instantiated_from: gt-tmpl\ntemplate_step: s2"},
+		{ID: "gt-handoff", Status: StatusPinned, Title: "witness Handoff"},
+		{ID: "gt-handoff-owned", Status: StatusPinned, Title: "mayor Handoff", CreatedBy: "mayor"},
+		{ID: "bd-stray"},
+	}
+
+	got := lintRules(LintIssues(issues, LintOptions{Prefix: "gt"}))
+	want := map[string]string{
+		"gt-agent-bad":           LintRuleAgentLabel,
+		"gt-wisp-closed-parent":  LintRuleOrphanedWisp,
+		"gt-wisp-missing-parent": LintRuleOrphanedWisp,
+		"gt-step-bad":            LintRuleOrphanedStep,
+		"gt-handoff":             LintRuleHandoffOwner,
+		"bd-stray":               LintRuleIDPrefix,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got findings %v, want %v", got, want)
+	}
+	for id, rule := range want {
+		if got[id] != rule {
+			t.Errorf("%s: rule %q, want %q", id, got[id], rule)
+		}
+	}
+}
+
+func TestLintIssues_RuleFilterAndFixable(t *testing.T) {
+	issues := []*Issue{
+		{ID: "bd-agent", Type: "agent"},
+	}
+	findings := LintIssues(issues, LintOptions{Prefix: "gt", Rules: []string{LintRuleAgentLabel}})
+	if len(findings) != 1 || findings[0].Rule != LintRuleAgentLabel {
+		t.Fatalf("findings = %+v, want only agent-label", findings)
+	}
+	if !findings[0].Fixable || findings[0].Severity != LintWarning {
+		t.Errorf("agent-label finding = %+v, want fixable warning", findings[0])
+	}
+
+	findings = LintIssues(issues, LintOptions{Prefix: "gt", Rules: []string{LintRuleIDPrefix}})
+	if len(findings) != 1 || findings[0].Fixable || findings[0].Severity != LintError {
+		t.Errorf("id-prefix finding = %+v, want unfixable error", findings)
+	}
+	if err := findings[0].Fix(nil); err == nil {
+		t.Error("Fix on unfixable finding should error")
+	}
+}
@@ -4,8 +4,10 @@ package beads
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // MoleculeStep represents a parsed step from a molecule definition.
@@ -18,6 +20,11 @@ type MoleculeStep struct {
 	Tier         string         // Optional tier hint: haiku, sonnet, opus
 	Type         string         // Step type: "task" (default), "wait", etc.
 	Backoff      *BackoffConfig // Backoff configuration for wait-type steps
+
+	// Optional automation hints (see StepMetadata).
+	ExpectedDuration string // How long the step should take (e.g., "15m")
+	Automation       string // Command that does the step's work
+	Verification     string // Command whose success means the step is done
 }
 
 // BackoffConfig defines exponential backoff parameters for wait-type steps.
@@ -49,9 +56,25 @@ var typeLineRegex = regexp.MustCompile(`(?i)^Type:\s*(\w+)\s*$`)
 // Parses backoff configuration for wait-type steps.
 var backoffLineRegex = regexp.MustCompile(`(?i)^Backoff:\s*(.+)$`)
 
+// expectedDurationLineRegex matches "ExpectedDuration: 15m" lines.
+var expectedDurationLineRegex = regexp.MustCompile(`(?i)^ExpectedDuration:\s*(\S+)\s*$`)
+
+// automationLineRegex matches "Automation: <command>" lines.
+var automationLineRegex = regexp.MustCompile(`(?i)^Automation:\s*(.+)$`)
+
+// verificationLineRegex matches "Verification: <command>" lines.
+var verificationLineRegex = regexp.MustCompile(`(?i)^Verification:\s*(.+)$`)
+
 // templateVarRegex matches {{variable}} placeholders.
 var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 
+// varsHeaderRegex matches the "## Vars" header that opens a variable declaration block.
+var varsHeaderRegex = regexp.MustCompile(`(?i)^##\s*Vars\s*$`)
+
+// varDeclRegex matches "name: required", "name: optional" or "name: default=<value>"
+// lines in a "## Vars" block, optionally written as a list item.
+var varDeclRegex = regexp.MustCompile(`(?i)^(?:[-*]\s*)?(\w+)\s*:\s*(required|optional|default\s*=(.*))$`)
+
 // ParseMoleculeSteps extracts step definitions from a molecule's description.
 //
 // The expected format is:
@@ -62,6 +85,11 @@ var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 //	Tier: haiku|sonnet|opus  # optional
 //	Type: task|wait  # optional, default is "task"
 //	Backoff: base=30s, multiplier=2, max=10m  # optional, for wait-type steps
+//	ExpectedDuration: 15m  # optional
+//	Automation: <command>  # optional, run by gt mol step auto
+//	Verification: <command>  # optional, success closes the step
+//
+// A "## Vars" block (see ParseMoleculeVars) ends the step before it.
 //
 // Returns an empty slice if no steps are found.
 func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
@@ -127,6 +155,20 @@ func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
 				continue
 			}
 
+			// Check for automation hint lines
+			if matches := expectedDurationLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.ExpectedDuration = matches[1]
+				continue
+			}
+			if matches := automationLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Automation = strings.TrimSpace(matches[1])
+				continue
+			}
+			if matches := verificationLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Verification = strings.TrimSpace(matches[1])
+				continue
+			}
+
 			// Regular instruction line
 			instructionLines = append(instructionLines, line)
 		}
@@ -149,6 +191,12 @@ func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
 	}
 
 	for _, line := range lines {
+		// A "## Vars" block ends the current step; its lines are not instructions
+		if varsHeaderRegex.MatchString(line) {
+			finalizeStep()
+			continue
+		}
+
 		// Check for step header
 		if matches := stepHeaderRegex.FindStringSubmatch(line); matches != nil {
 			// Finalize previous step if any
@@ -236,9 +284,114 @@ func ExpandTemplateVars(text string, ctx map[string]string) string {
 	})
 }
 
+// MoleculeVar is a template variable declared in a molecule's "## Vars" block.
+type MoleculeVar struct {
+	Name     string
+	Required bool   // Instantiation fails if no value is given
+	Default  string // Used when no value is given (optional vars only)
+}
+
+// ParseMoleculeVars extracts variable declarations from a molecule's description.
+//
+// The expected format is:
+//
+//	## Vars
+//	- feature: required
+//	- base_branch: default=main
+//	- notes: optional
+//
+// Optional variables without a default expand to "". The block runs until
+// the next "## " header. Returns nil if there is no block.
+func ParseMoleculeVars(description string) []MoleculeVar {
+	var vars []MoleculeVar
+	inBlock := false
+	for _, line := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if varsHeaderRegex.MatchString(trimmed) {
+			inBlock = true
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") {
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			continue
+		}
+		matches := varDeclRegex.FindStringSubmatch(trimmed)
+		if matches == nil {
+			continue
+		}
+		v := MoleculeVar{Name: matches[1]}
+		switch kind := strings.ToLower(matches[2]); {
+		case kind == "required":
+			v.Required = true
+		case strings.HasPrefix(kind, "default"):
+			v.Default = strings.TrimSpace(matches[3])
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// MissingVarsError reports template variables an instantiation had no value for.
+type MissingVarsError struct {
+	Molecule string
+	Vars     []string // Sorted
+}
+
+func (e *MissingVarsError) Error() string {
+	return fmt.Sprintf("molecule %s: missing variables: %s (provide a value for each, e.g. --var %s=<value>)",
+		e.Molecule, strings.Join(e.Vars, ", "), e.Vars[0])
+}
+
+// ResolveMoleculeVars builds the context for expanding the given texts.
+// Declared defaults fill in values not in ctx, and optional variables
+// without a default expand to "". Required variables without a value, and
+// variables used in texts but never declared nor given, are returned as a
+// *MissingVarsError so nothing is instantiated with {{var}} literals left in.
+func ResolveMoleculeVars(molID string, decls []MoleculeVar, ctx map[string]string, texts ...string) (map[string]string, error) {
+	resolved := make(map[string]string, len(ctx)+len(decls))
+	for k, v := range ctx {
+		resolved[k] = v
+	}
+
+	missing := make(map[string]bool)
+	declared := make(map[string]bool, len(decls))
+	for _, d := range decls {
+		declared[d.Name] = true
+		if _, ok := resolved[d.Name]; ok {
+			continue
+		}
+		if d.Required {
+			missing[d.Name] = true
+			continue
+		}
+		resolved[d.Name] = d.Default
+	}
+	for _, text := range texts {
+		for _, m := range templateVarRegex.FindAllStringSubmatch(text, -1) {
+			if _, ok := resolved[m[1]]; !ok && !declared[m[1]] {
+				missing[m[1]] = true
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, &MissingVarsError{Molecule: molID, Vars: names}
+	}
+	return resolved, nil
+}
+
 // InstantiateOptions configures molecule instantiation behavior.
 type InstantiateOptions struct {
-	// Context map for {{variable}} substitution
+	// Context map for {{variable}} substitution. Checked against the
+	// molecule's "## Vars" declarations before anything is created.
 	Context map[string]string
 }
 
@@ -257,11 +410,15 @@ type InstantiateOptions struct {
 // For each step, this creates:
 //   - A child issue with ID "{parent.ID}.{step.Ref}"
 //   - Title from step title
-//   - Description from step instructions (with template vars expanded)
+//   - Description from step instructions (with template vars expanded),
+//     followed by provenance and any step metadata (see StepMetadata)
 //   - Type: task
 //   - Priority: inherited from parent
 //   - Dependencies wired according to template
 //
+// Template variables are resolved first (see ResolveMoleculeVars): if any
+// variable is missing, a *MissingVarsError is returned and nothing is created.
+//
 // The function is atomic via bd CLI - either all issues are created or none.
 // Returns the created step issues.
 func (b *Beads) InstantiateMolecule(mol *Issue, parent *Issue, opts InstantiateOptions) ([]*Issue, error) {
@@ -294,16 +451,22 @@ func (b *Beads) InstantiateMolecule(mol *Issue, parent *Issue, opts InstantiateO
 
 // instantiateFromChildren creates steps from template child issues (new format).
 func (b *Beads) instantiateFromChildren(mol *Issue, parent *Issue, templates []*Issue, opts InstantiateOptions) ([]*Issue, error) {
+	texts := make([]string, len(templates))
+	for i, tmpl := range templates {
+		texts[i] = tmpl.Description
+	}
+	vars, err := ResolveMoleculeVars(mol.ID, ParseMoleculeVars(mol.Description), opts.Context, texts...)
+	if err != nil {
+		return nil, err
+	}
+
 	var createdIssues []*Issue
 	templateToNew := make(map[string]string) // template ID -> new issue ID
 
 	// First pass: create all child issues
 	for _, tmpl := range templates {
 		// Expand template variables in description
-		description := tmpl.Description
-		if opts.Context != nil {
-			description = ExpandTemplateVars(description, opts.Context)
-		}
+		description := ExpandTemplateVars(tmpl.Description, vars)
 
 		// Add provenance metadata
 		if description != "" {
@@ -386,16 +549,23 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 		}
 	}
 
+	// Resolve template variables before creating anything
+	texts := make([]string, 0, len(steps))
+	for _, step := range steps {
+		texts = append(texts, step.Instructions, step.Automation, step.Verification)
+	}
+	vars, err := ResolveMoleculeVars(mol.ID, ParseMoleculeVars(mol.Description), opts.Context, texts...)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create child issues for each step
 	var createdIssues []*Issue
 	stepIssueIDs := make(map[string]string) // step ref -> issue ID
 
 	for _, step := range steps {
 		// Expand template variables in instructions
-		instructions := step.Instructions
-		if opts.Context != nil {
-			instructions = ExpandTemplateVars(instructions, opts.Context)
-		}
+		instructions := ExpandTemplateVars(step.Instructions, vars)
 
 		// Build description with provenance metadata
 		description := instructions
@@ -406,6 +576,11 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 		if step.Tier != "" {
 			description += fmt.Sprintf("\ntier: %s", step.Tier)
 		}
+		description += FormatStepMetadata(StepMetadata{
+			ExpectedDuration: step.ExpectedDuration,
+			Automation:       ExpandTemplateVars(step.Automation, vars),
+			Verification:     ExpandTemplateVars(step.Verification, vars),
+		})
 
 		// Create the child issue
 		childOpts := CreateOptions{
@@ -490,6 +665,11 @@ func ValidateMolecule(mol *Issue) error {
 
 	// Validate Needs references
 	for _, step := range steps {
+		if step.ExpectedDuration != "" {
+			if d, err := time.ParseDuration(step.ExpectedDuration); err != nil || d <= 0 {
+				return fmt.Errorf("step %q has invalid ExpectedDuration %q", step.Ref, step.ExpectedDuration)
+			}
+		}
 		for _, need := range step.Needs {
 			if !stepMap[need] {
 				return fmt.Errorf("step %q depends on unknown step %q", step.Ref, need)
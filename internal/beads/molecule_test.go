@@ -1,6 +1,7 @@
 package beads
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -681,6 +682,41 @@ Needs: check-reality`
 	}
 }
 
+func TestParseMoleculeSteps_WithAutomation(t *testing.T) {
+	desc := `## Step: generate
+Regenerate the bindings.
+ExpectedDuration: 10m
+Automation: make generate
+Verification: git diff --exit-code -- gen/
+
+## Step: review
+Review the change.
+Needs: generate`
+
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+	if steps[0].ExpectedDuration != "10m" || steps[0].Automation != "make generate" ||
+		steps[0].Verification != "git diff --exit-code -- gen/" {
+		t.Errorf("step[0] = %+v", steps[0])
+	}
+	if steps[0].Instructions != "Regenerate the bindings." {
+		t.Errorf("step[0].Instructions = %q, want hint lines stripped", steps[0].Instructions)
+	}
+	if steps[1].ExpectedDuration != "" || steps[1].Automation != "" {
+		t.Errorf("step[1] = %+v, want no hints", steps[1])
+	}
+
+	mol := &Issue{ID: "mol-x", Type: "molecule", Description: strings.Replace(desc, "10m", "soon", 1)}
+	if err := ValidateMolecule(mol); err == nil {
+		t.Error("ValidateMolecule() = nil, want error for invalid ExpectedDuration")
+	}
+}
+
 func TestParseMoleculeSteps_WithBackoff(t *testing.T) {
 	desc := `## Step: await-signal
 Wait for a wake signal with exponential backoff.
@@ -787,3 +823,100 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+func TestParseMoleculeVars(t *testing.T) {
+	desc := `Build a feature.
+
+## Vars
+- feature: required
+- base_branch: default=main
+notes: optional
+not a declaration
+
+## Step: implement
+Implement {{feature}} on {{base_branch}}.`
+
+	vars := ParseMoleculeVars(desc)
+	want := []MoleculeVar{
+		{Name: "feature", Required: true},
+		{Name: "base_branch", Default: "main"},
+		{Name: "notes"},
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("ParseMoleculeVars() = %+v, want %+v", vars, want)
+	}
+
+	// The Vars block must not leak into the steps
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 1 || steps[0].Instructions != "Implement {{feature}} on {{base_branch}}." {
+		t.Errorf("steps = %+v", steps)
+	}
+}
+
+func TestParseMoleculeSteps_VarsBlockEndsStep(t *testing.T) {
+	desc := `## Step: design
+Plan the work.
+
+## Vars
+feature: required`
+
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 1 || steps[0].Instructions != "Plan the work." {
+		t.Errorf("steps = %+v", steps)
+	}
+}
+
+func TestResolveMoleculeVars(t *testing.T) {
+	decls := []MoleculeVar{
+		{Name: "feature", Required: true},
+		{Name: "base_branch", Default: "main"},
+		{Name: "notes"},
+	}
+	text := "Implement {{feature}} on {{base_branch}}. {{notes}}"
+
+	got, err := ResolveMoleculeVars("mol-x", decls, map[string]string{"feature": "auth"}, text)
+	if err != nil {
+		t.Fatalf("ResolveMoleculeVars() error = %v", err)
+	}
+	if expanded := ExpandTemplateVars(text, got); expanded != "Implement auth on main. " {
+		t.Errorf("expanded = %q", expanded)
+	}
+
+	// Given values override defaults
+	got, err = ResolveMoleculeVars("mol-x", decls, map[string]string{"feature": "auth", "base_branch": "dev"}, text)
+	if err != nil || got["base_branch"] != "dev" {
+		t.Errorf("base_branch = %q (err %v), want dev", got["base_branch"], err)
+	}
+}
+
+func TestResolveMoleculeVars_Missing(t *testing.T) {
+	decls := []MoleculeVar{{Name: "feature", Required: true}}
+
+	_, err := ResolveMoleculeVars("mol-x", decls, nil, "Implement {{feature}} in {{target_file}}")
+	var missing *MissingVarsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("error = %v, want *MissingVarsError", err)
+	}
+	if want := []string{"feature", "target_file"}; !reflect.DeepEqual(missing.Vars, want) {
+		t.Errorf("missing = %v, want %v", missing.Vars, want)
+	}
+	if !strings.Contains(err.Error(), "mol-x") || !strings.Contains(err.Error(), "feature, target_file") {
+		t.Errorf("error message = %q", err.Error())
+	}
+}
+
+func TestResolveMoleculeVars_Undeclared(t *testing.T) {
+	// Molecules without a Vars block still fail on variables nobody provided
+	if _, err := ResolveMoleculeVars("mol-x", nil, map[string]string{"a": "1"}, "{{a}} {{b}}"); err == nil {
+		t.Error("expected error for unset {{b}}")
+	}
+	if _, err := ResolveMoleculeVars("mol-x", nil, nil, "no variables"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StepMetadata is the optional structured metadata of a molecule step,
+// stored as "key: value" lines in the step issue's description:
+//
+//	expected_duration: 15m
+//	automation: make generate
+//	verification: go test ./...
+//
+// Patrols compare a step's running time against ExpectedDuration, and
+// gt mol step auto runs Automation and Verification, closing the step when
+// verification succeeds.
+type StepMetadata struct {
+	ExpectedDuration string // Go duration; empty when not set
+	Automation       string // Command that does the step's work
+	Verification     string // Command whose success means the step is done
+}
+
+// Expected returns the parsed expected duration, or 0 when unset or invalid.
+func (m StepMetadata) Expected() time.Duration {
+	d, err := time.ParseDuration(m.ExpectedDuration)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// ParseStepMetadata extracts step metadata from a step issue's description.
+func ParseStepMetadata(description string) StepMetadata {
+	var m StepMetadata
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+		value := strings.TrimSpace(line[colonIdx+1:])
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "expected_duration", "expected-duration":
+			m.ExpectedDuration = value
+		case "automation":
+			m.Automation = value
+		case "verification":
+			m.Verification = value
+		}
+	}
+	return m
+}
+
+// FormatStepMetadata formats the set fields as description lines, each
+// preceded by a newline so the result can be appended to a description.
+func FormatStepMetadata(m StepMetadata) string {
+	var b strings.Builder
+	if m.ExpectedDuration != "" {
+		fmt.Fprintf(&b, "\nexpected_duration: %s", m.ExpectedDuration)
+	}
+	if m.Automation != "" {
+		fmt.Fprintf(&b, "\nautomation: %s", m.Automation)
+	}
+	if m.Verification != "" {
+		fmt.Fprintf(&b, "\nverification: %s", m.Verification)
+	}
+	return b.String()
+}
+
+// OverdueStep is a step that has been running longer than expected.
+type OverdueStep struct {
+	Step     *Issue
+	Running  time.Duration
+	Expected time.Duration
+}
+
+// FindOverdueStep returns the molecule's current step if it has an expected
+// duration and has run past it, or nil. The current step is the first step
+// in progress, or failing that the first open one; it started when the
+// latest closed step closed, or when it was created if that is later.
+func FindOverdueStep(steps []*Issue, now time.Time) *OverdueStep {
+	var current *Issue
+	var lastClose time.Time
+	for _, s := range steps {
+		switch s.Status {
+		case "closed":
+			if t, err := time.Parse(time.RFC3339, s.ClosedAt); err == nil && t.After(lastClose) {
+				lastClose = t
+			}
+		case "in_progress", "hooked":
+			if current == nil || current.Status == "open" {
+				current = s
+			}
+		case "open":
+			if current == nil {
+				current = s
+			}
+		}
+	}
+	if current == nil {
+		return nil
+	}
+	expected := ParseStepMetadata(current.Description).Expected()
+	if expected == 0 {
+		return nil
+	}
+	start := lastClose
+	if created, err := time.Parse(time.RFC3339, current.CreatedAt); err == nil && created.After(start) {
+		start = created
+	}
+	if start.IsZero() {
+		return nil
+	}
+	if running := now.Sub(start); running > expected {
+		return &OverdueStep{Step: current, Running: running, Expected: expected}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStepMetadataRoundTrip(t *testing.T) {
+	m := StepMetadata{ExpectedDuration: "15m", Automation: "make gen", Verification: "go test ./..."}
+	desc := "Do the thing.\n\ninstantiated_from: mol-x\nstep: gen" + FormatStepMetadata(m)
+	if got := ParseStepMetadata(desc); got != m {
+		t.Errorf("ParseStepMetadata() = %+v, want %+v", got, m)
+	}
+	if got := m.Expected(); got != 15*time.Minute {
+		t.Errorf("Expected() = %v, want 15m", got)
+	}
+	if got := (StepMetadata{ExpectedDuration: "soon"}).Expected(); got != 0 {
+		t.Errorf("Expected() for invalid duration = %v, want 0", got)
+	}
+	if FormatStepMetadata(StepMetadata{}) != "" {
+		t.Error("FormatStepMetadata of empty metadata is not empty")
+	}
+}
+
+func TestFindOverdueStep(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	steps := []*Issue{
+		{ID: "gt-mol.1", Status: "closed", CreatedAt: "2026-03-01T10:00:00Z", ClosedAt: "2026-03-01T11:00:00Z"},
+		{ID: "gt-mol.2", Status: "open", CreatedAt: "2026-03-01T10:00:00Z", Description: "expected_duration: 30m"},
+		{ID: "gt-mol.3", Status: "open", CreatedAt: "2026-03-01T10:00:00Z", Description: "expected_duration: 5m"},
+	}
+
+	// Step 2 started when step 1 closed, an hour ago.
+	got := FindOverdueStep(steps, now)
+	if got == nil || got.Step.ID != "gt-mol.2" || got.Running != time.Hour || got.Expected != 30*time.Minute {
+		t.Fatalf("FindOverdueStep() = %+v, want gt-mol.2 running 1h", got)
+	}
+	if got := FindOverdueStep(steps, now.Add(-40*time.Minute)); got != nil {
+		t.Errorf("FindOverdueStep() 20m in = %+v, want nil", got)
+	}
+
+	// An in-progress step is current even when an earlier open one exists.
+	steps[2].Status = "in_progress"
+	if got := FindOverdueStep(steps, now); got == nil || got.Step.ID != "gt-mol.3" {
+		t.Errorf("FindOverdueStep() = %+v, want in-progress gt-mol.3", got)
+	}
+
+	// Steps without an expected duration are never overdue.
+	steps[2].Description = ""
+	if got := FindOverdueStep(steps, now); got != nil {
+		t.Errorf("FindOverdueStep() = %+v, want nil without expected_duration", got)
+	}
+}
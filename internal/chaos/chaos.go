@@ -0,0 +1,305 @@
+// Package chaos provides fault injection hooks for doltserver and daemon paths.
+//
+// Hooks are compiled in only when building with -tags chaos; in default
+// builds Enabled is false and every hook is a no-op. When compiled in,
+// faults are activated by the GT_CHAOS environment variable, which holds
+// either a named scenario or a comma-separated list of fault specs:
+//
+//	GT_CHAOS=flaky-connections gt dolt status
+//	GT_CHAOS="dolt.start=fail,dolt.merge=delay:5s" gt done
+//	GT_CHAOS="dolt.conn=drop@0.3" gt sling gt-abc gastown
+//
+// A fault spec is point=kind[:arg][@probability]. Probability defaults to 1.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvVar is the environment variable that activates fault injection.
+const EnvVar = "GT_CHAOS"
+
+// Point identifies a code path where faults can be injected.
+type Point string
+
+// Injection points.
+const (
+	DoltStart        Point = "dolt.start"         // doltserver.Start
+	DoltMerge        Point = "dolt.merge"         // doltserver.MergePolecatBranch
+	DoltConn         Point = "dolt.conn"          // doltserver SQL connections
+	DoltState        Point = "dolt.state"         // doltserver.LoadState
+	DaemonDoltStart  Point = "daemon.dolt-start"  // daemon Dolt server (re)start
+	DaemonDoltHealth Point = "daemon.dolt-health" // daemon Dolt health check
+)
+
+// Points lists all known injection points.
+var Points = []Point{DoltStart, DoltMerge, DoltConn, DoltState, DaemonDoltStart, DaemonDoltHealth}
+
+// Kind is the type of fault to inject.
+type Kind string
+
+// Fault kinds.
+const (
+	// KindFail returns a generic injected error.
+	KindFail Kind = "fail"
+	// KindDelay sleeps for the fault's Delay before continuing normally.
+	KindDelay Kind = "delay"
+	// KindDrop returns a connection-dropped error.
+	KindDrop Kind = "drop"
+	// KindReadOnly returns Dolt's read-only manifest error, exercising
+	// the read-only recovery paths.
+	KindReadOnly Kind = "readonly"
+	// KindStale makes state loaders return stale state (see Stale).
+	KindStale Kind = "stale"
+)
+
+// ErrInjected is wrapped by every error returned from an injected fault.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Fault is a single fault bound to an injection point.
+type Fault struct {
+	Point       Point
+	Kind        Kind
+	Delay       time.Duration // for KindDelay
+	Probability float64       // 0 < p <= 1
+}
+
+// String returns the fault in spec form.
+func (f Fault) String() string {
+	s := string(f.Point) + "=" + string(f.Kind)
+	if f.Kind == KindDelay {
+		s += ":" + f.Delay.String()
+	}
+	if f.Probability < 1 {
+		s += "@" + strconv.FormatFloat(f.Probability, 'f', -1, 64)
+	}
+	return s
+}
+
+// Scenario is a named set of faults with a default command to run under them.
+type Scenario struct {
+	Name        string
+	Description string
+	Faults      []Fault
+	// Command is the gt subcommand (without "gt") run by `gt chaos run`
+	// when none is given. Nil means the caller must supply one.
+	Command []string
+}
+
+// Scenarios are the built-in chaos scenarios.
+var Scenarios = []Scenario{
+	{
+		Name:        "dolt-crash-restart",
+		Description: "Daemon health checks fail intermittently and restarts sometimes fail; verifies backoff and escalation",
+		Faults: []Fault{
+			{Point: DaemonDoltHealth, Kind: KindFail, Probability: 0.5},
+			{Point: DaemonDoltStart, Kind: KindFail, Probability: 0.3},
+		},
+		Command: []string{"daemon", "run"},
+	},
+	{
+		Name:        "dolt-start-fail",
+		Description: "gt dolt start fails before spawning the server",
+		Faults:      []Fault{{Point: DoltStart, Kind: KindFail, Probability: 1}},
+		Command:     []string{"dolt", "start"},
+	},
+	{
+		Name:        "slow-merge",
+		Description: "Polecat branch merges stall for 10s; verifies done/merge-queue timeouts",
+		Faults:      []Fault{{Point: DoltMerge, Kind: KindDelay, Delay: 10 * time.Second, Probability: 1}},
+	},
+	{
+		Name:        "flaky-connections",
+		Description: "30% of Dolt SQL connections are dropped; verifies retry paths",
+		Faults:      []Fault{{Point: DoltConn, Kind: KindDrop, Probability: 0.3}},
+		Command:     []string{"dolt", "status"},
+	},
+	{
+		Name:        "read-only",
+		Description: "Dolt SQL writes fail with the read-only manifest error; verifies recovery",
+		Faults:      []Fault{{Point: DoltConn, Kind: KindReadOnly, Probability: 1}},
+	},
+	{
+		Name:        "stale-state",
+		Description: "Dolt state file reports a running server with a dead PID; verifies split-brain detection",
+		Faults:      []Fault{{Point: DoltState, Kind: KindStale, Probability: 1}},
+		Command:     []string{"dolt", "status"},
+	},
+}
+
+// FindScenario returns the built-in scenario with the given name.
+func FindScenario(name string) (*Scenario, bool) {
+	for i := range Scenarios {
+		if Scenarios[i].Name == name {
+			return &Scenarios[i], true
+		}
+	}
+	return nil, false
+}
+
+// ParseSpec parses a GT_CHAOS value: a scenario name or a comma-separated
+// list of point=kind[:arg][@probability] fault specs.
+func ParseSpec(spec string) ([]Fault, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	if s, ok := FindScenario(spec); ok {
+		return s.Faults, nil
+	}
+
+	var faults []Fault
+	for _, part := range strings.Split(spec, ",") {
+		f, err := parseFault(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		faults = append(faults, f)
+	}
+	return faults, nil
+}
+
+func parseFault(s string) (Fault, error) {
+	pointStr, rest, ok := strings.Cut(s, "=")
+	if !ok {
+		return Fault{}, fmt.Errorf("invalid fault %q: want point=kind (or a scenario name)", s)
+	}
+	f := Fault{Point: Point(pointStr), Probability: 1}
+	if !knownPoint(f.Point) {
+		return Fault{}, fmt.Errorf("unknown injection point %q", pointStr)
+	}
+
+	if kindArg, prob, ok := strings.Cut(rest, "@"); ok {
+		p, err := strconv.ParseFloat(prob, 64)
+		if err != nil || p <= 0 || p > 1 {
+			return Fault{}, fmt.Errorf("invalid probability %q in %q: want 0 < p <= 1", prob, s)
+		}
+		f.Probability = p
+		rest = kindArg
+	}
+
+	kind, arg, _ := strings.Cut(rest, ":")
+	f.Kind = Kind(kind)
+	switch f.Kind {
+	case KindFail, KindDrop, KindReadOnly, KindStale:
+	case KindDelay:
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return Fault{}, fmt.Errorf("invalid delay in %q: %w", s, err)
+		}
+		f.Delay = d
+	default:
+		return Fault{}, fmt.Errorf("unknown fault kind %q in %q", kind, s)
+	}
+	return f, nil
+}
+
+func knownPoint(p Point) bool {
+	for _, known := range Points {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Injector evaluates faults at injection points.
+type Injector struct {
+	faults []Fault
+	rand   func() float64
+	sleep  func(time.Duration)
+}
+
+// NewInjector returns an Injector for the given faults.
+func NewInjector(faults []Fault) *Injector {
+	return &Injector{faults: faults, rand: rand.Float64, sleep: time.Sleep}
+}
+
+// Inject applies any fail, delay, drop, or read-only fault configured for p.
+// Returns nil if no fault fires.
+func (i *Injector) Inject(p Point) error {
+	for _, f := range i.faults {
+		if f.Point != p || f.Kind == KindStale || !i.fires(f) {
+			continue
+		}
+		switch f.Kind {
+		case KindDelay:
+			i.sleep(f.Delay)
+		case KindFail:
+			return fmt.Errorf("%w: %s failed", ErrInjected, p)
+		case KindDrop:
+			return fmt.Errorf("%w: %s: connection reset by peer", ErrInjected, p)
+		case KindReadOnly:
+			return fmt.Errorf("%w: %s: cannot update manifest: database is read only", ErrInjected, p)
+		}
+	}
+	return nil
+}
+
+// Stale reports whether a stale-state fault fires for p.
+func (i *Injector) Stale(p Point) bool {
+	for _, f := range i.faults {
+		if f.Point == p && f.Kind == KindStale && i.fires(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Injector) fires(f Fault) bool {
+	return f.Probability >= 1 || i.rand() < f.Probability
+}
+
+var (
+	activeOnce     sync.Once
+	activeInjector *Injector
+)
+
+// active returns the process-wide injector configured from GT_CHAOS.
+func active() *Injector {
+	activeOnce.Do(func() {
+		faults, err := ParseSpec(os.Getenv(EnvVar))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", EnvVar, err)
+			faults = nil
+		}
+		activeInjector = NewInjector(faults)
+	})
+	return activeInjector
+}
+
+// Inject applies any fault configured for p via GT_CHAOS.
+// Always returns nil in builds without the chaos tag.
+func Inject(p Point) error {
+	if !Enabled {
+		return nil
+	}
+	return active().Inject(p)
+}
+
+// Stale reports whether a stale-state fault is active for p.
+// Always false in builds without the chaos tag.
+func Stale(p Point) bool {
+	if !Enabled {
+		return false
+	}
+	return active().Stale(p)
+}
+
+// ScenarioNames returns the names of all built-in scenarios, sorted.
+func ScenarioNames() []string {
+	names := make([]string, 0, len(Scenarios))
+	for _, s := range Scenarios {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return names
+}
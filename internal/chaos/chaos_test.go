@@ -0,0 +1,126 @@
+package chaos
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSpec_Scenario(t *testing.T) {
+	faults, err := ParseSpec("flaky-connections")
+	if err != nil {
+		t.Fatalf("ParseSpec: %v", err)
+	}
+	if len(faults) != 1 || faults[0].Point != DoltConn || faults[0].Kind != KindDrop {
+		t.Errorf("faults = %+v", faults)
+	}
+}
+
+func TestParseSpec_RawFaults(t *testing.T) {
+	faults, err := ParseSpec("dolt.start=fail, dolt.merge=delay:250ms@0.5")
+	if err != nil {
+		t.Fatalf("ParseSpec: %v", err)
+	}
+	if len(faults) != 2 {
+		t.Fatalf("got %d faults, want 2", len(faults))
+	}
+	if faults[0] != (Fault{Point: DoltStart, Kind: KindFail, Probability: 1}) {
+		t.Errorf("faults[0] = %+v", faults[0])
+	}
+	want := Fault{Point: DoltMerge, Kind: KindDelay, Delay: 250 * time.Millisecond, Probability: 0.5}
+	if faults[1] != want {
+		t.Errorf("faults[1] = %+v, want %+v", faults[1], want)
+	}
+	if got := faults[1].String(); got != "dolt.merge=delay:250ms@0.5" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParseSpec_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"no-such-scenario",
+		"bogus.point=fail",
+		"dolt.start=explode",
+		"dolt.merge=delay:soon",
+		"dolt.conn=drop@2",
+		"dolt.conn=drop@0",
+	} {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("ParseSpec(%q) should fail", spec)
+		}
+	}
+	if faults, err := ParseSpec(""); err != nil || faults != nil {
+		t.Errorf("empty spec = %v, %v; want nil, nil", faults, err)
+	}
+}
+
+func TestScenariosAreValid(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, s := range Scenarios {
+		if seen[s.Name] {
+			t.Errorf("duplicate scenario %q", s.Name)
+		}
+		seen[s.Name] = true
+		for _, f := range s.Faults {
+			// Every built-in fault must round-trip through the spec parser.
+			parsed, err := ParseSpec(f.String())
+			if err != nil || len(parsed) != 1 || parsed[0] != f {
+				t.Errorf("scenario %s fault %v does not round-trip: %v %v", s.Name, f, parsed, err)
+			}
+		}
+	}
+}
+
+func TestInjector_Inject(t *testing.T) {
+	var slept time.Duration
+	inj := NewInjector([]Fault{
+		{Point: DoltStart, Kind: KindFail, Probability: 1},
+		{Point: DoltMerge, Kind: KindDelay, Delay: time.Second, Probability: 1},
+		{Point: DoltConn, Kind: KindReadOnly, Probability: 1},
+		{Point: DoltState, Kind: KindStale, Probability: 1},
+	})
+	inj.sleep = func(d time.Duration) { slept += d }
+
+	if err := inj.Inject(DoltStart); !errors.Is(err, ErrInjected) {
+		t.Errorf("DoltStart err = %v, want ErrInjected", err)
+	}
+	if err := inj.Inject(DoltMerge); err != nil || slept != time.Second {
+		t.Errorf("DoltMerge err = %v slept = %v", err, slept)
+	}
+	err := inj.Inject(DoltConn)
+	if err == nil || !strings.Contains(err.Error(), "database is read only") {
+		t.Errorf("DoltConn err = %v, want read-only error", err)
+	}
+	if err := inj.Inject(DaemonDoltStart); err != nil {
+		t.Errorf("unconfigured point err = %v", err)
+	}
+	if err := inj.Inject(DoltState); err != nil {
+		t.Errorf("stale faults must not fail Inject, got %v", err)
+	}
+	if !inj.Stale(DoltState) || inj.Stale(DoltStart) {
+		t.Error("Stale should fire only for the stale point")
+	}
+}
+
+func TestInjector_Probability(t *testing.T) {
+	inj := NewInjector([]Fault{{Point: DoltConn, Kind: KindDrop, Probability: 0.3}})
+	inj.rand = func() float64 { return 0.5 }
+	if err := inj.Inject(DoltConn); err != nil {
+		t.Errorf("roll above probability should not fire, got %v", err)
+	}
+	inj.rand = func() float64 { return 0.1 }
+	if err := inj.Inject(DoltConn); err == nil {
+		t.Error("roll below probability should fire")
+	}
+}
+
+func TestPackageHooksDisabledByDefault(t *testing.T) {
+	if Enabled {
+		t.Skip("built with -tags chaos")
+	}
+	t.Setenv(EnvVar, "dolt.start=fail")
+	if err := Inject(DoltStart); err != nil {
+		t.Errorf("Inject must be a no-op without the chaos tag, got %v", err)
+	}
+}
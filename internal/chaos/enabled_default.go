@@ -0,0 +1,7 @@
+//go:build !chaos
+
+package chaos
+
+// Enabled reports whether fault injection is compiled into this binary.
+// Build with -tags chaos to enable it.
+const Enabled = false
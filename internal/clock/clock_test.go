@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	f := NewFake(start)
+
+	if !f.Now().Equal(start) {
+		t.Fatalf("Now = %v, want %v", f.Now(), start)
+	}
+
+	f.Sleep(30 * time.Second)
+	f.Sleep(time.Minute)
+	if got := f.Since(start); got != 90*time.Second {
+		t.Errorf("Since after sleeps = %v, want 90s", got)
+	}
+	if sleeps := f.Sleeps(); len(sleeps) != 2 || sleeps[0] != 30*time.Second || sleeps[1] != time.Minute {
+		t.Errorf("Sleeps = %v, want [30s 1m]", sleeps)
+	}
+
+	f.Advance(time.Hour)
+	if got := f.Since(start); got != time.Hour+90*time.Second {
+		t.Errorf("Since after Advance = %v", got)
+	}
+
+	f.Set(start)
+	if !f.Now().Equal(start) {
+		t.Errorf("Now after Set = %v, want %v", f.Now(), start)
+	}
+}
+
+func TestReal(t *testing.T) {
+	var c Clock = Real{}
+	before := time.Now()
+	if c.Now().Before(before) {
+		t.Error("Real.Now is before time.Now")
+	}
+	if c.Since(before) < 0 {
+		t.Error("Real.Since is negative")
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var ackCmd = &cobra.Command{
+	Use:     "ack <nudge-id>",
+	GroupID: GroupComm,
+	Short:   "Acknowledge a nudge that asked for a receipt",
+	Long: `Acknowledge a nudge sent with 'gt nudge --require-ack'.
+
+Such nudges end with "(acknowledge with: gt ack nudge-<hex>)". Run that
+command once you have read the nudge and acted on it. Nudges that are not
+acknowledged in time are reported by 'gt patrol digest' and escalated by
+'gt escalate stale'.
+
+Examples:
+  gt ack nudge-1a2b3c4d`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runAck,
+}
+
+func init() {
+	rootCmd.AddCommand(ackCmd)
+}
+
+func runAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	by := detectSender()
+	r, err := nudge.Ack(townRoot, args[0], by)
+	if err != nil {
+		return err
+	}
+
+	late := ""
+	if r.AckRequired() && r.AckedAt.After(r.AckBy) {
+		late = style.Dim.Render(fmt.Sprintf(" (%s late)", r.AckedAt.Sub(r.AckBy).Round(time.Second)))
+	}
+	fmt.Printf("%s Acknowledged %s from %s%s\n", style.Success.Render("✓"), r.ID, r.Sender, late)
+	if r.Escalation != "" {
+		fmt.Printf("  Escalation %s was raised for the missed ack: %s\n", r.Escalation,
+			style.Dim.Render("gt escalate close "+r.Escalation+" --reason acknowledged"))
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	adoptDoltLink    bool
+	adoptDoltPrefix  string
+	adoptDoltForce   bool
+	adoptDoltUpgrade bool
+	adoptDoltJSON    bool
+)
+
+var adoptExternalDoltCmd = &cobra.Command{
+	Use:     "adopt-external-dolt <path> <rig>",
+	GroupID: GroupServices,
+	Short:   "Register an existing Dolt database as a rig",
+	Long: `Adopt a Dolt database from outside the town, such as a clone from
+DoltHub, as a rig's beads database.
+
+The database's schema is checked against what bd expects first. One
+without an issues table or its core columns is refused unless --force;
+tables and columns bd's schema upgrade can add (labels, dependencies,
+config) are reported, and --upgrade runs the upgrade (bd migrate) once the
+database is served.
+
+The database is then moved to .dolt-data/<rig> (or symlinked there with
+--link, leaving it in place), the rig is registered in mayor/rigs.json with
+its issue prefix, a routes.jsonl entry is added, and the rig's
+metadata.json is pointed at the Dolt server. The prefix is read from the
+database's issue_prefix config unless given with --prefix.
+
+The Dolt server only discovers databases at startup, so a running server
+is stopped for the adoption and started again afterwards. With --upgrade
+the server is started if it wasn't running.
+
+The rig is registered without a git repository; attach one later with
+'gt rig add <rig> --adopt'.
+
+Examples:
+  gt adopt-external-dolt ~/clones/beads-archive archive
+  gt adopt-external-dolt ./shared-db shared --link --prefix sh
+  gt adopt-external-dolt ~/clones/old-tracker legacy --upgrade`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runAdoptExternalDolt,
+}
+
+func init() {
+	adoptExternalDoltCmd.Flags().BoolVar(&adoptDoltLink, "link", false, "Symlink the database into .dolt-data instead of moving it")
+	adoptExternalDoltCmd.Flags().StringVar(&adoptDoltPrefix, "prefix", "", "Issue prefix (default: the database's issue_prefix, or the rig name)")
+	adoptExternalDoltCmd.Flags().BoolVar(&adoptDoltForce, "force", false, "Adopt even if the schema isn't a beads schema")
+	adoptExternalDoltCmd.Flags().BoolVar(&adoptDoltUpgrade, "upgrade", false, "Run bd's schema upgrade after adopting")
+	adoptExternalDoltCmd.Flags().BoolVar(&adoptDoltJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(adoptExternalDoltCmd)
+}
+
+// adoptDoltOutput is the JSON output of gt adopt-external-dolt.
+type adoptDoltOutput struct {
+	*doltserver.AdoptResult
+	Upgraded *doltserver.SchemaCheck `json:"upgraded,omitempty"`
+}
+
+func runAdoptExternalDolt(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	source, rigName := args[0], args[1]
+
+	running, _, _ := doltserver.IsRunning(townRoot)
+	if running {
+		if !adoptDoltJSON {
+			fmt.Println("Stopping Dolt server for the adoption...")
+		}
+		if err := doltserver.Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+	}
+
+	result, err := doltserver.AdoptExternalDatabase(townRoot, rigName, source, doltserver.AdoptOptions{
+		Link:   adoptDoltLink,
+		Prefix: adoptDoltPrefix,
+		Force:  adoptDoltForce,
+	})
+	if running || (adoptDoltUpgrade && err == nil) {
+		if startErr := doltserver.Start(townRoot); startErr != nil {
+			return errors.Join(err, fmt.Errorf("starting Dolt server: %w", startErr))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	out := adoptDoltOutput{AdoptResult: result}
+	var upgradeErr error
+	if adoptDoltUpgrade {
+		out.Upgraded, upgradeErr = doltserver.UpgradeAdoptedSchema(townRoot, rigName)
+	}
+
+	if adoptDoltJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+		return upgradeErr
+	}
+
+	verb := "Moved"
+	if result.Linked {
+		verb = "Linked"
+	}
+	fmt.Printf("%s Adopted %s as rig %s (prefix %s-)\n", style.Success.Render("✓"), result.Source, rigName, result.Prefix)
+	fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("%s to %s", verb, result.Target)))
+	fmt.Printf("    %s\n", style.Dim.Render("beads: "+result.BeadsDir))
+	printSchemaCheck("Schema", result.Schema)
+	for _, w := range result.Warnings {
+		style.PrintWarning("%s", w)
+	}
+
+	switch {
+	case upgradeErr != nil:
+		return fmt.Errorf("rig adopted but the schema upgrade failed: %w", upgradeErr)
+	case out.Upgraded != nil:
+		printSchemaCheck("After upgrade", out.Upgraded)
+	case !result.Schema.OK():
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Run 'bd migrate' in %s with the server up to upgrade the schema", result.BeadsDir)))
+	}
+	if !running && !adoptDoltUpgrade {
+		fmt.Printf("\nStart server with: %s\n", style.Dim.Render("gt dolt start"))
+	}
+	return nil
+}
+
+// printSchemaCheck prints how a schema differs from what bd expects.
+func printSchemaCheck(label string, c *doltserver.SchemaCheck) {
+	if c.OK() {
+		fmt.Printf("    %s\n", style.Dim.Render(label+": matches bd"))
+		return
+	}
+	if len(c.Incompatible) > 0 {
+		fmt.Printf("    %s %s: not a beads schema (%s)\n", style.Warning.Render("⚠"), label, strings.Join(c.Incompatible, ", "))
+	}
+	if len(c.Missing) > 0 {
+		fmt.Printf("    %s %s: missing %s\n", style.Warning.Render("⚠"), label, strings.Join(c.Missing, ", "))
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// rootAgent is the global --agent/--plain flag.
+var rootAgent bool
+
+// plainOutputExemptCommands write output consumed verbatim by other
+// programs (shell completion scripts, Claude hook responses, data
+// exports), so it is never rewritten. Keyed by command path below the root.
+var plainOutputExemptCommands = []string{
+	"completion",
+	"__complete",
+	"signal",
+	"tap",
+	"dolt export-branch-diff",
+}
+
+// plainOutputDrainTimeout bounds how long Execute waits for rewritten
+// output to drain. A background child that inherited stdout keeps the
+// pipe open, so EOF may never come.
+const plainOutputDrainTimeout = 2 * time.Second
+
+// stopPlainOutput restores stdout/stderr and flushes rewritten output.
+// Nil when output is not being rewritten.
+var stopPlainOutput func()
+
+// usePlainOutput reports whether cmd's output should be rewritten by
+// ui.PlainText: agent mode or a non-TTY stdout, unless the command emits
+// JSON (--json) or is exempt.
+func usePlainOutput(cmd *cobra.Command) bool {
+	if !ui.ShouldUsePlainOutput() {
+		return false
+	}
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Value.String() == "true" {
+		return false
+	}
+	path := strings.TrimPrefix(buildCommandPath(cmd), cmd.Root().Name()+" ")
+	for _, exempt := range plainOutputExemptCommands {
+		if path == exempt || strings.HasPrefix(path, exempt+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// startPlainOutput routes os.Stdout and os.Stderr through ui.PlainText
+// until stopPlainOutput is called. Output is passed through unchanged if
+// the pipes can't be created.
+func startPlainOutput() {
+	origOut, origErr := os.Stdout, os.Stderr
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		_ = outR.Close()
+		_ = outW.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	copyPlain := func(dst, src *os.File) {
+		_ = ui.CopyPlain(dst, src)
+		done <- struct{}{}
+	}
+	go copyPlain(origOut, outR)
+	go copyPlain(origErr, errR)
+	os.Stdout, os.Stderr = outW, errW
+
+	stopPlainOutput = func() {
+		os.Stdout, os.Stderr = origOut, origErr
+		_ = outW.Close()
+		_ = errW.Close()
+		timeout := time.After(plainOutputDrainTimeout)
+		for i := 0; i < 2; i++ {
+			select {
+			case <-done:
+			case <-timeout:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/approval"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var approveTTL time.Duration
+
+var approveCmd = &cobra.Command{
+	Use:     "approve <operation>",
+	GroupID: GroupConfig,
+	Short:   "Mint a short-lived token authorizing a destructive command",
+	Long: `Mint a single-use approval token for a class of destructive commands.
+
+Operations:
+  nuke         gt polecat nuke
+  rollback     gt dolt rollback
+  rig-remove   gt rig remove
+
+Which operations require approval is configured in settings/config.json:
+
+  "approvals": {
+    "policies": {"nuke": "confirm", "rollback": "token", "rig-remove": "token"},
+    "token_ttl": "10m"
+  }
+
+Policies:
+  allow     No gate (default for unlisted operations)
+  confirm   A human types a confirmation phrase, or a token is presented
+  token     A token is required
+
+Only a human at an interactive terminal (no agent session variables such
+as GT_ROLE, GT_RIG or BD_ACTOR set) can mint tokens; agents, including the
+mayor, cannot approve their own destructive commands. Pass the token to the gated
+command with --approval <token> or the GT_APPROVAL environment variable.
+
+Examples:
+  gt approve rollback                 # Token valid for the configured TTL
+  gt approve nuke --ttl 2m            # Shorter-lived token`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApprove,
+}
+
+func init() {
+	approveCmd.Flags().DurationVar(&approveTTL, "ttl", 0, "Token lifetime (default from settings, or 10m)")
+	rootCmd.AddCommand(approveCmd)
+}
+
+func runApprove(cmd *cobra.Command, args []string) error {
+	class, err := approval.ParseClass(args[0])
+	if err != nil {
+		return err
+	}
+
+	role := os.Getenv("GT_ROLE")
+	if !approval.CanMint(os.Getenv, term.IsTerminal(int(os.Stdin.Fd()))) {
+		if role != "" {
+			return fmt.Errorf("role %q cannot mint approval tokens; ask a human", role)
+		}
+		return fmt.Errorf("approval tokens can only be minted by a human at an interactive terminal")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	ttl := approveTTL
+	if ttl <= 0 {
+		cfg, err := approval.LoadConfig(townRoot)
+		if err != nil {
+			return err
+		}
+		ttl = approval.TokenTTL(cfg)
+	}
+
+	token, expires, err := approval.Mint(townRoot, class, ttl, os.Getenv("USER"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Approval token for %s (single use, expires %s):\n\n",
+		style.Bold.Render("✓"), class, expires.Format("15:04:05"))
+	fmt.Printf("  %s\n\n", token)
+	fmt.Printf("Pass it with --approval %s or GT_APPROVAL=%s\n", token, token)
+	return nil
+}
+
+// requireApproval enforces the town's approval policy for a destructive
+// command. Interactive humans may type the confirmation phrase instead of
+// presenting a token; agents never get a prompt.
+func requireApproval(townRoot string, class approval.Class, target, token string) error {
+	cfg, err := approval.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		token = os.Getenv(approval.EnvToken)
+	}
+
+	req := approval.Request{
+		TownRoot: townRoot,
+		Class:    class,
+		Target:   target,
+		Token:    token,
+	}
+	if approval.IsHuman(os.Getenv, term.IsTerminal(int(os.Stdin.Fd()))) {
+		req.Confirm = promptConfirmationPhrase
+	}
+	return approval.Check(approval.PolicyFor(cfg, class), req)
+}
+
+func promptConfirmationPhrase(phrase string) (string, error) {
+	fmt.Printf("%s This operation requires confirmation.\n", style.Warning.Render("⚠"))
+	fmt.Printf("Type %q to continue: ", phrase)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading confirmation: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -25,7 +26,12 @@ prefix-based routing.
 Subcommands:
   move    Move a bead from one repository to another
   show    Show details of a bead (routes by prefix)
-  read    Alias for show`,
+  read    Alias for show
+  link    Record that a bead in one rig blocks a bead in another
+  unlink  Remove a cross-rig dependency
+  links   List or graph cross-rig dependencies
+  snooze  Hide a bead from ready queues until a time or another bead closes
+  sla     SLA timers on beads, escalated when breached`,
 }
 
 var beadMoveCmd = &cobra.Command{
@@ -39,6 +45,9 @@ and closes the source bead with a reference to the new location.
 The target prefix determines which repository receives the bead.
 Common prefixes: gt- (gastown), bd- (beads), hq- (headquarters)
 
+Only the bead's fields are copied. To move a bead together with its wisps,
+comments, and audit trail, use 'gt mv bead'.
+
 Examples:
   gt bead move gt-abc123 bd-     # Move gt-abc123 to beads repo as bd-*
   gt bead move hq-xyz bd-        # Move hq-xyz to beads repo
@@ -116,7 +125,7 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 
 	// Get source bead details
 	showCmd := exec.Command("bd", "show", sourceID, "--json")
-	output, err := showCmd.Output()
+	output, err := proc.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("getting bead %s: %w", sourceID, err)
 	}
@@ -170,7 +179,7 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 	// Create the new bead
 	createCmd := exec.Command("bd", createArgs...)
 	createCmd.Stderr = os.Stderr
-	newIDBytes, err := createCmd.Output()
+	newIDBytes, err := proc.Output(createCmd)
 	if err != nil {
 		return fmt.Errorf("creating new bead: %w", err)
 	}
@@ -182,11 +191,11 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 	closeReason := fmt.Sprintf("Moved to %s", newID)
 	closeCmd := exec.Command("bd", "close", sourceID, "--reason", closeReason)
 	closeCmd.Stderr = os.Stderr
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		// Clean up the new bead since we couldn't close the source
 		fmt.Fprintf(os.Stderr, "Warning: failed to close source bead: %v\n", err)
 		cleanupCmd := exec.Command("bd", "close", newID, "--reason", "Cleanup: source bead close failed during move")
-		if cleanupErr := cleanupCmd.Run(); cleanupErr != nil {
+		if cleanupErr := proc.RunCmd(cleanupCmd); cleanupErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: also failed to clean up new bead %s: %v\n", newID, cleanupErr)
 			fmt.Fprintf(os.Stderr, "Both %s and %s remain open - manual cleanup needed\n", sourceID, newID)
 		} else {
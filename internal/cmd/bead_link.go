@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadLinksRig    string
+	beadLinksFormat string
+)
+
+var beadLinkCmd = &cobra.Command{
+	Use:   "link <blocker> <blocked>",
+	Short: "Record that a bead in one rig blocks a bead in another",
+	Long: `Record a cross-rig dependency: <blocker> must close before <blocked>.
+
+Each rig's beads database only knows its own issues, so a backend bead
+blocking a frontend bead can't be a bd dependency. Cross-rig links are kept
+in a town-level table in the hq database instead. Both beads are given as
+rig:bead-id; use hq for town-level beads.
+
+gt sling refuses to sling a bead while any bead linked as blocking it is
+still open (--force overrides).
+
+Examples:
+  gt bead link backend:be-abc12 frontend:fe-xyz89
+  gt bead links --format dot | dot -Tsvg > links.svg`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runBeadLink,
+}
+
+var beadUnlinkCmd = &cobra.Command{
+	Use:   "unlink <blocker> <blocked>",
+	Short: "Remove a cross-rig dependency",
+	Long: `Remove the cross-rig link recorded by gt bead link.
+
+Examples:
+  gt bead unlink backend:be-abc12 frontend:fe-xyz89`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runBeadUnlink,
+}
+
+var beadLinksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "List or graph cross-rig dependencies",
+	Long: `List cross-rig dependencies with the current status of each bead.
+
+--format selects the output:
+  text      One line per link, open blockers highlighted (default)
+  json      Links with bead status, for scripts
+  dot       Graphviz digraph, edges from blocker to blocked
+  mermaid   Mermaid flowchart, for markdown docs and PRs
+
+In graphs, beads are grouped by rig and open beads are drawn bold.
+
+Examples:
+  gt bead links
+  gt bead links --rig frontend
+  gt bead links --format dot | dot -Tsvg > links.svg
+  gt bead links --format mermaid`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBeadLinks,
+}
+
+func init() {
+	beadLinksCmd.Flags().StringVar(&beadLinksRig, "rig", "", "Only links with a bead in this rig")
+	beadLinksCmd.Flags().StringVar(&beadLinksFormat, "format", "text", "Output format: text, json, dot, mermaid")
+	beadCmd.AddCommand(beadLinkCmd)
+	beadCmd.AddCommand(beadUnlinkCmd)
+	beadCmd.AddCommand(beadLinksCmd)
+}
+
+// parseLinkArgs parses and checks the two bead references of link/unlink.
+func parseLinkArgs(args []string) (blocker, blocked doltserver.BeadRef, err error) {
+	if blocker, err = doltserver.ParseBeadRef(args[0]); err != nil {
+		return blocker, blocked, err
+	}
+	if blocked, err = doltserver.ParseBeadRef(args[1]); err != nil {
+		return blocker, blocked, err
+	}
+	return blocker, blocked, nil
+}
+
+// rigBeadsDir returns the directory to run bd in for a rig's beads. The hq
+// rig is the town itself.
+func rigBeadsDir(townRoot, rig string) string {
+	if rig == "hq" {
+		return townRoot
+	}
+	return filepath.Join(townRoot, rig)
+}
+
+// beadRefStatus returns the status of the referenced bead.
+func beadRefStatus(townRoot string, ref doltserver.BeadRef) (string, error) {
+	dir := rigBeadsDir(townRoot, ref.Rig)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("unknown rig %q", ref.Rig)
+	}
+	issue, err := beads.New(dir).Show(ref.Bead)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+	return issue.Status, nil
+}
+
+func runBeadLink(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	blocker, blocked, err := parseLinkArgs(args)
+	if err != nil {
+		return err
+	}
+	for _, ref := range []doltserver.BeadRef{blocker, blocked} {
+		if _, err := beadRefStatus(townRoot, ref); err != nil {
+			return err
+		}
+	}
+
+	added, err := doltserver.AddRigLink(townRoot, doltserver.RigLink{
+		Blocker:   blocker,
+		Blocked:   blocked,
+		CreatedBy: detectSender(),
+	})
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Printf("%s %s already blocks %s\n", style.Dim.Render("○"), blocker, blocked)
+		return nil
+	}
+	fmt.Printf("%s %s blocks %s\n", style.Success.Render("✓"), blocker, blocked)
+	return nil
+}
+
+func runBeadUnlink(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	blocker, blocked, err := parseLinkArgs(args)
+	if err != nil {
+		return err
+	}
+	removed, err := doltserver.RemoveRigLink(townRoot, blocker, blocked)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("no link from %s to %s", blocker, blocked)
+	}
+	fmt.Printf("%s %s no longer blocks %s\n", style.Success.Render("✓"), blocker, blocked)
+	return nil
+}
+
+// rigLinkView is a link with the current status of both beads.
+type rigLinkView struct {
+	doltserver.RigLink
+	BlockerStatus string `json:"blocker_status"`
+	BlockedStatus string `json:"blocked_status"`
+}
+
+func runBeadLinks(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	switch beadLinksFormat {
+	case "text", "json", "dot", "mermaid":
+	default:
+		return fmt.Errorf("unknown format %q: want text, json, dot, or mermaid", beadLinksFormat)
+	}
+	links, err := doltserver.ListRigLinks(townRoot)
+	if err != nil {
+		return err
+	}
+
+	statuses := map[doltserver.BeadRef]string{}
+	status := func(ref doltserver.BeadRef) string {
+		if s, ok := statuses[ref]; ok {
+			return s
+		}
+		s, err := beadRefStatus(townRoot, ref)
+		if err != nil {
+			s = "unknown"
+		}
+		statuses[ref] = s
+		return s
+	}
+	views := []rigLinkView{}
+	for _, l := range links {
+		if beadLinksRig != "" && l.Blocker.Rig != beadLinksRig && l.Blocked.Rig != beadLinksRig {
+			continue
+		}
+		views = append(views, rigLinkView{RigLink: l, BlockerStatus: status(l.Blocker), BlockedStatus: status(l.Blocked)})
+	}
+
+	switch beadLinksFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(views)
+	case "dot":
+		fmt.Print(rigLinksDOT(views))
+	case "mermaid":
+		fmt.Print(rigLinksMermaid(views))
+	default:
+		printRigLinks(views)
+	}
+	return nil
+}
+
+func printRigLinks(views []rigLinkView) {
+	if len(views) == 0 {
+		fmt.Printf("%s No cross-rig links\n", style.Dim.Render("○"))
+		return
+	}
+	for _, v := range views {
+		icon := style.Dim.Render("○")
+		if v.BlockerStatus != "closed" {
+			icon = style.Warning.Render("⚠")
+		}
+		fmt.Printf("%s %s (%s) → %s (%s)\n", icon, v.Blocker, v.BlockerStatus, v.Blocked, v.BlockedStatus)
+	}
+}
+
+// linkGraphNodes returns the beads in views grouped by rig, rigs and beads
+// sorted, with each bead's status.
+func linkGraphNodes(views []rigLinkView) (rigs []string, byRig map[string][]string, statuses map[doltserver.BeadRef]string) {
+	byRig = map[string][]string{}
+	statuses = map[doltserver.BeadRef]string{}
+	add := func(ref doltserver.BeadRef, status string) {
+		if _, ok := statuses[ref]; ok {
+			return
+		}
+		statuses[ref] = status
+		byRig[ref.Rig] = append(byRig[ref.Rig], ref.Bead)
+	}
+	for _, v := range views {
+		add(v.Blocker, v.BlockerStatus)
+		add(v.Blocked, v.BlockedStatus)
+	}
+	for rig, ids := range byRig {
+		sort.Strings(ids)
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+	return rigs, byRig, statuses
+}
+
+// rigLinksDOT renders links as a Graphviz digraph with one cluster per rig.
+func rigLinksDOT(views []rigLinkView) string {
+	rigs, byRig, statuses := linkGraphNodes(views)
+	var b strings.Builder
+	b.WriteString("digraph rig_links {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, rig := range rigs {
+		fmt.Fprintf(&b, "  subgraph %q {\n    label=%q;\n", "cluster_"+rig, rig)
+		for _, id := range byRig[rig] {
+			ref := doltserver.BeadRef{Rig: rig, Bead: id}
+			attrs := ""
+			if statuses[ref] != "closed" {
+				attrs = ", style=bold"
+			}
+			fmt.Fprintf(&b, "    %q [label=%q%s];\n", ref.String(), id+"\\n"+statuses[ref], attrs)
+		}
+		b.WriteString("  }\n")
+	}
+	for _, v := range views {
+		fmt.Fprintf(&b, "  %q -> %q;\n", v.Blocker.String(), v.Blocked.String())
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// rigLinksMermaid renders links as a Mermaid flowchart with one subgraph
+// per rig.
+func rigLinksMermaid(views []rigLinkView) string {
+	rigs, byRig, statuses := linkGraphNodes(views)
+	nodeID := func(ref doltserver.BeadRef) string {
+		return strings.NewReplacer(":", "__", "-", "_", ".", "_").Replace(ref.String())
+	}
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	var open []string
+	for _, rig := range rigs {
+		fmt.Fprintf(&b, "  subgraph %s\n", rig)
+		for _, id := range byRig[rig] {
+			ref := doltserver.BeadRef{Rig: rig, Bead: id}
+			fmt.Fprintf(&b, "    %s[\"%s<br/>%s\"]\n", nodeID(ref), id, statuses[ref])
+			if statuses[ref] != "closed" {
+				open = append(open, nodeID(ref))
+			}
+		}
+		b.WriteString("  end\n")
+	}
+	for _, v := range views {
+		fmt.Fprintf(&b, "  %s --> %s\n", nodeID(v.Blocker), nodeID(v.Blocked))
+	}
+	if len(open) > 0 {
+		fmt.Fprintf(&b, "  classDef open font-weight:bold\n  class %s open\n", strings.Join(open, ","))
+	}
+	return b.String()
+}
+
+// checkCrossRigBlockers returns an error if a bead linked as blocking beadID
+// in another rig is still open. Links that can't be read only warn, so a
+// Dolt hiccup doesn't stop slinging.
+func checkCrossRigBlockers(townRoot, beadID string) error {
+	links, err := doltserver.ListRigLinks(townRoot)
+	if err != nil {
+		style.PrintWarning("could not check cross-rig blockers: %v", err)
+		return nil
+	}
+	var open []string
+	for _, blocker := range doltserver.BlockersOf(links, beadID) {
+		status, err := beadRefStatus(townRoot, blocker)
+		if err != nil {
+			style.PrintWarning("cross-rig blocker %s: %v", blocker, err)
+			continue
+		}
+		if status != "closed" {
+			open = append(open, fmt.Sprintf("%s (%s)", blocker, status))
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("bead %s is blocked by open bead(s) in other rigs: %s\n"+
+			"Use --force to sling anyway, or 'gt bead unlink' to drop the dependency",
+			beadID, strings.Join(open, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func testLinkViews() []rigLinkView {
+	return []rigLinkView{
+		{
+			RigLink:       doltserver.RigLink{Blocker: doltserver.BeadRef{Rig: "backend", Bead: "be-1"}, Blocked: doltserver.BeadRef{Rig: "frontend", Bead: "fe-2"}},
+			BlockerStatus: "open",
+			BlockedStatus: "open",
+		},
+		{
+			RigLink:       doltserver.RigLink{Blocker: doltserver.BeadRef{Rig: "infra", Bead: "in-3"}, Blocked: doltserver.BeadRef{Rig: "frontend", Bead: "fe-2"}},
+			BlockerStatus: "closed",
+			BlockedStatus: "open",
+		},
+	}
+}
+
+func TestRigLinksDOT(t *testing.T) {
+	out := rigLinksDOT(testLinkViews())
+	for _, want := range []string{
+		"digraph rig_links {",
+		`subgraph "cluster_backend"`,
+		`"backend:be-1" [label="be-1\\nopen", style=bold];`,
+		`"infra:in-3" [label="in-3\\nclosed"];`,
+		`"backend:be-1" -> "frontend:fe-2";`,
+		`"infra:in-3" -> "frontend:fe-2";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, `"frontend:fe-2" [`) != 1 {
+		t.Errorf("blocked bead should be declared once:\n%s", out)
+	}
+}
+
+func TestRigLinksMermaid(t *testing.T) {
+	out := rigLinksMermaid(testLinkViews())
+	for _, want := range []string{
+		"flowchart LR",
+		"  subgraph frontend",
+		`    backend__be_1["be-1<br/>open"]`,
+		"  backend__be_1 --> frontend__fe_2",
+		"  class backend__be_1,frontend__fe_2 open",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
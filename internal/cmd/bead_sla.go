@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadSLAFrom      string
+	beadSLAListJSON  bool
+	beadSLAEscalate  bool
+	beadSLASeverity  string
+	beadSLACheckJSON bool
+)
+
+var beadSLACmd = &cobra.Command{
+	Use:   "sla",
+	Short: "SLA timers on beads, escalated when breached",
+	Long: `Set, clear and check SLA timers on beads.
+
+An SLA timer is a named deadline on a bead, such as "review within 24h of
+submit". Timers are stored as labels on the bead (gt:sla, and
+sla:<name>:<deadline> per timer), so they travel with the bead.
+
+The sla_check daemon patrol runs 'gt bead sla check --escalate', which
+escalates each breached timer once through the escalation routes in
+settings/escalation.json (mail, email, sms, slack) and marks it
+sla-breached:<name>. Clear a timer when the work it tracks is done.
+
+Examples:
+  gt bead sla set gt-mr123 review 24h
+  gt bead sla clear gt-mr123 review
+  gt bead sla list
+  gt bead sla check --escalate`,
+	RunE: requireSubcommand,
+}
+
+var beadSLASetCmd = &cobra.Command{
+	Use:   "set <bead-id> <name> <duration>",
+	Short: "Start an SLA timer on a bead",
+	Long: `Start the named SLA timer on a bead, due <duration> after now (or after
+--from). Setting a timer that exists restarts it.
+
+Examples:
+  gt bead sla set gt-mr123 review 24h
+  gt bead sla set gt-abc12 triage 4h --from "2026-03-10 09:00"`,
+	Args:         cobra.ExactArgs(3),
+	SilenceUsage: true,
+	RunE:         runBeadSLASet,
+}
+
+var beadSLAClearCmd = &cobra.Command{
+	Use:          "clear <bead-id> <name>",
+	Short:        "Stop an SLA timer on a bead",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runBeadSLAClear,
+}
+
+var beadSLAListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List open beads with SLA timers, soonest deadline first",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBeadSLAList,
+}
+
+var beadSLACheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Find breached SLA timers and escalate them",
+	Long: `Find SLA timers past their deadline on open beads.
+
+Without --escalate, breaches are only reported. With --escalate, each
+breach not yet flagged is escalated (at --severity) and marked
+sla-breached:<name> so it is escalated once.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBeadSLACheck,
+}
+
+func init() {
+	beadSLASetCmd.Flags().StringVar(&beadSLAFrom, "from", "", "Start the timer at this time instead of now")
+	beadSLAListCmd.Flags().BoolVar(&beadSLAListJSON, "json", false, "Output as JSON")
+	beadSLACheckCmd.Flags().BoolVar(&beadSLAEscalate, "escalate", false, "Escalate breaches not yet flagged and mark them")
+	beadSLACheckCmd.Flags().StringVar(&beadSLASeverity, "severity", config.SeverityMedium, "Severity of breach escalations")
+	beadSLACheckCmd.Flags().BoolVar(&beadSLACheckJSON, "json", false, "Output as JSON")
+	beadSLACmd.AddCommand(beadSLASetCmd)
+	beadSLACmd.AddCommand(beadSLAClearCmd)
+	beadSLACmd.AddCommand(beadSLAListCmd)
+	beadSLACmd.AddCommand(beadSLACheckCmd)
+	beadCmd.AddCommand(beadSLACmd)
+}
+
+func runBeadSLASet(cmd *cobra.Command, args []string) error {
+	id, name := args[0], args[1]
+	d, err := timefmt.ParseDuration(args[2])
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid SLA duration %q", args[2])
+	}
+	start := time.Now()
+	if beadSLAFrom != "" {
+		if start, err = timefmt.Parse(beadSLAFrom); err != nil {
+			return fmt.Errorf("--from: %w", err)
+		}
+	}
+	deadline := start.Add(d)
+	if err := beads.New(resolveBeadDir(id)).SetSLA(id, name, deadline); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s due %s\n", style.Success.Render("✓"), id, name, timefmt.TimestampAgo(deadline))
+	return nil
+}
+
+func runBeadSLAClear(cmd *cobra.Command, args []string) error {
+	id, name := args[0], args[1]
+	removed, err := beads.New(resolveBeadDir(id)).ClearSLA(id, name)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%s has no %s SLA timer", id, name)
+	}
+	fmt.Printf("%s Cleared %s SLA on %s\n", style.Success.Render("✓"), name, id)
+	return nil
+}
+
+// SLAEntry is one SLA timer on an open bead.
+type SLAEntry struct {
+	Bead  string `json:"bead"`
+	Title string `json:"title"`
+	beads.SLATimer
+	Escalation string `json:"escalation,omitempty"` // Escalation bead created by this check
+	Error      string `json:"error,omitempty"`
+
+	dir string // Beads directory the bead lives in
+}
+
+// beadDatabaseDirs returns the town's beads directories, one per database,
+// from the town's routes.
+func beadDatabaseDirs(townRoot string) []string {
+	dirs := []string{townRoot}
+	seen := map[string]bool{townRoot: true}
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	for _, r := range routes {
+		dir := r.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(townRoot, dir)
+		}
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// collectSLATimers returns the SLA timers on open beads across the town,
+// soonest deadline first.
+func collectSLATimers(townRoot string) []*SLAEntry {
+	var entries []*SLAEntry
+	for _, dir := range beadDatabaseDirs(townRoot) {
+		issues, err := beads.New(dir).ListSLA()
+		if err != nil {
+			style.PrintWarning("%s: %v", dir, err)
+			continue
+		}
+		for _, issue := range issues {
+			for _, t := range beads.ParseSLATimers(issue.Labels) {
+				entries = append(entries, &SLAEntry{Bead: issue.ID, Title: issue.Title, SLATimer: t, dir: dir})
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Deadline.Before(entries[j].Deadline) })
+	return entries
+}
+
+func runBeadSLAList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	entries := collectSLATimers(townRoot)
+	if beadSLAListJSON {
+		if entries == nil {
+			entries = []*SLAEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No SLA timers on open beads\n", style.Dim.Render("○"))
+		return nil
+	}
+	now := time.Now()
+	for _, e := range entries {
+		icon := style.Success.Render("○")
+		switch {
+		case e.Overdue(now) && e.Breached:
+			icon = style.Error.Render("✗")
+		case e.Overdue(now):
+			icon = style.Warning.Render("⚠")
+		}
+		fmt.Printf("%s %-12s %-10s due %s  %s\n", icon, e.Bead, e.Name, timefmt.TimestampAgo(e.Deadline), style.Dim.Render(e.Title))
+	}
+	return nil
+}
+
+func runBeadSLACheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	severity := strings.ToLower(beadSLASeverity)
+	if !config.IsValidSeverity(severity) {
+		return fmt.Errorf("invalid severity '%s': must be critical, high, medium, or low", beadSLASeverity)
+	}
+
+	now := time.Now()
+	breaches := []*SLAEntry{}
+	for _, e := range collectSLATimers(townRoot) {
+		if e.Overdue(now) {
+			breaches = append(breaches, e)
+		}
+	}
+
+	var failed int
+	if beadSLAEscalate {
+		var escalationConfig *config.EscalationConfig
+		for _, e := range breaches {
+			if e.Breached {
+				continue
+			}
+			if escalationConfig == nil {
+				if escalationConfig, err = config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot)); err != nil {
+					return fmt.Errorf("loading escalation config: %w", err)
+				}
+			}
+			if err := escalateSLABreach(townRoot, escalationConfig, e, severity, now); err != nil {
+				e.Error = err.Error()
+				failed++
+			}
+		}
+	}
+
+	if beadSLACheckJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(breaches); err != nil {
+			return err
+		}
+	} else {
+		printSLABreaches(breaches, now)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to escalate %d SLA breach(es)", failed)
+	}
+	return nil
+}
+
+// escalateSLABreach escalates a breached timer and marks it breached.
+func escalateSLABreach(townRoot string, cfg *config.EscalationConfig, e *SLAEntry, severity string, now time.Time) error {
+	description := fmt.Sprintf("SLA breached: %s %s overdue by %s", e.Bead, e.Name, timefmt.Duration(now.Sub(e.Deadline)))
+	reason := fmt.Sprintf("%s (%s) was due %s.\nClear the timer with: gt bead sla clear %s %s",
+		e.Bead, e.Title, timefmt.Timestamp(e.Deadline), e.Bead, e.Name)
+	source := "sla:" + e.Name
+
+	issue, err := beads.New(beads.ResolveBeadsDir(townRoot)).CreateEscalationBead(description, &beads.EscalationFields{
+		Severity:    severity,
+		Reason:      reason,
+		Source:      source,
+		EscalatedBy: "daemon",
+		EscalatedAt: now.Format(time.RFC3339),
+		RelatedBead: e.Bead,
+	})
+	if err != nil {
+		return fmt.Errorf("creating escalation bead: %w", err)
+	}
+	e.Escalation = issue.ID
+	routeEscalation(townRoot, cfg, issue.ID, severity, description, reason, source, e.Bead, "daemon", true)
+
+	if err := beads.New(e.dir).MarkSLABreached(e.Bead, e.Name); err != nil {
+		return fmt.Errorf("marking %s breached: %w", e.Bead, err)
+	}
+	e.Breached = true
+	return nil
+}
+
+func printSLABreaches(breaches []*SLAEntry, now time.Time) {
+	if len(breaches) == 0 {
+		fmt.Printf("%s No breached SLA timers\n", style.Success.Render("✓"))
+		return
+	}
+	for _, e := range breaches {
+		detail := fmt.Sprintf("overdue by %s", timefmt.Duration(now.Sub(e.Deadline)))
+		switch {
+		case e.Error != "":
+			fmt.Printf("%s %s %s: %s\n", style.Error.Render("✗"), e.Bead, e.Name, e.Error)
+		case e.Escalation != "":
+			fmt.Printf("%s %s %s %s, escalated as %s\n", style.Error.Render("✗"), e.Bead, e.Name, detail, e.Escalation)
+		case e.Breached:
+			fmt.Printf("%s %s %s %s %s\n", style.Error.Render("✗"), e.Bead, e.Name, detail, style.Dim.Render("(escalated)"))
+		default:
+			fmt.Printf("%s %s %s %s\n", style.Warning.Render("⚠"), e.Bead, e.Name, detail)
+		}
+	}
+}
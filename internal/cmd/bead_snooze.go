@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
+)
+
+var beadSnoozeUntilClosed string
+
+var beadSnoozeCmd = &cobra.Command{
+	Use:   "snooze <bead-id> [<duration>|<time>]",
+	Short: "Hide a bead from ready queues until a time or until another bead closes",
+	Long: `Hide an open bead from gt ready until it wakes.
+
+A bead wakes when the given time passes (a duration such as 4h or 2d, or a
+time such as 2026-03-12 or "2026-03-12 09:00"), or when the bead given with
+--until-closed is closed, whichever comes first. Snoozing again replaces
+the earlier snooze.
+
+The snooze is stored as labels on the bead (gt:snoozed, snooze-until:,
+snooze-on:), so 'bd list --label gt:snoozed' shows every snoozed bead.
+
+Examples:
+  gt bead snooze gt-abc12 2d
+  gt bead snooze gt-abc12 "2026-03-12 09:00"
+  gt bead snooze gt-abc12 --until-closed gt-xyz89
+  gt bead unsnooze gt-abc12`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE:         runBeadSnooze,
+}
+
+var beadUnsnoozeCmd = &cobra.Command{
+	Use:          "unsnooze <bead-id>",
+	Short:        "Return a snoozed bead to ready queues",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runBeadUnsnooze,
+}
+
+func init() {
+	beadSnoozeCmd.Flags().StringVar(&beadSnoozeUntilClosed, "until-closed", "", "Wake when this bead is closed")
+	beadCmd.AddCommand(beadSnoozeCmd)
+	beadCmd.AddCommand(beadUnsnoozeCmd)
+}
+
+// parseWakeTime reads a snooze time given as a duration from now or as a
+// time.
+func parseWakeTime(s string, now time.Time) (time.Time, error) {
+	if d, err := timefmt.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("snooze duration must be positive")
+		}
+		return now.Add(d), nil
+	}
+	t, err := timefmt.Parse(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid snooze time %q: want a duration (4h, 2d) or a time", s)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("snooze time %s is in the past", timefmt.Timestamp(t))
+	}
+	return t, nil
+}
+
+func runBeadSnooze(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	s := beads.Snooze{OnBead: beadSnoozeUntilClosed}
+	if len(args) == 2 {
+		until, err := parseWakeTime(args[1], time.Now())
+		if err != nil {
+			return err
+		}
+		s.Until = until
+	}
+	if s.Until.IsZero() && s.OnBead == "" {
+		return fmt.Errorf("give a duration or time to snooze until, or --until-closed <bead>")
+	}
+	if s.OnBead == id {
+		return fmt.Errorf("a bead can't wait for itself to close")
+	}
+
+	bd := beads.New(resolveBeadDir(id))
+	if s.OnBead != "" {
+		if _, err := bd.Show(s.OnBead); err != nil {
+			return fmt.Errorf("--until-closed: %w", err)
+		}
+	}
+	if err := bd.SnoozeIssue(id, s); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Snoozed %s %s\n", style.Success.Render("✓"), id, describeSnooze(s))
+	return nil
+}
+
+func runBeadUnsnooze(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	removed, err := beads.New(resolveBeadDir(id)).UnsnoozeIssue(id)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		fmt.Printf("%s %s is not snoozed\n", style.Dim.Render("○"), id)
+		return nil
+	}
+	fmt.Printf("%s %s is back in ready queues\n", style.Success.Render("✓"), id)
+	return nil
+}
+
+// describeSnooze says when a snooze wakes.
+func describeSnooze(s beads.Snooze) string {
+	switch {
+	case !s.Until.IsZero() && s.OnBead != "":
+		return fmt.Sprintf("until %s or until %s closes", timefmt.Timestamp(s.Until), s.OnBead)
+	case s.OnBead != "":
+		return fmt.Sprintf("until %s closes", s.OnBead)
+	default:
+		return fmt.Sprintf("until %s", timefmt.Timestamp(s.Until))
+	}
+}
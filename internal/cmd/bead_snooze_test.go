@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/timefmt"
+)
+
+func TestParseWakeTime(t *testing.T) {
+	defer timefmt.SetUTC(false)
+	timefmt.SetUTC(true)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if got, err := parseWakeTime("2d", now); err != nil || !got.Equal(now.Add(48*time.Hour)) {
+		t.Errorf("parseWakeTime(2d) = %v, %v", got, err)
+	}
+	want := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+	if got, err := parseWakeTime("2026-03-12 09:00", now); err != nil || !got.Equal(want) {
+		t.Errorf("parseWakeTime(time) = %v, %v; want %v", got, err, want)
+	}
+	for _, bad := range []string{"2026-03-01", "-1h", "soon"} {
+		if _, err := parseWakeTime(bad, now); err == nil {
+			t.Errorf("parseWakeTime(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // MinBeadsVersion is the minimum required beads version for Gas Town.
@@ -96,7 +98,7 @@ func getBeadsVersion() (string, error) {
 
 	// Version check doesn't need database access.
 	cmd := exec.CommandContext(ctx, "bd", "version")
-	output, err := cmd.Output()
+	output, err := proc.OutputContext(ctx, cmd)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return "", fmt.Errorf("bd version check timed out")
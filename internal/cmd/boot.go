@@ -11,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/boot"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -376,7 +377,7 @@ func runDegradedTriage(b *boot.Boot) (action, target string, err error) {
 // and should not be interrupted for "stale work" - it's supposed to be idle.
 func isDeaconInBackoff() bool {
 	cmd := exec.Command("bd", "show", "hq-deacon", "--json")
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		// Can't check - assume not in backoff (conservative)
 		return false
@@ -403,7 +404,7 @@ func isDeaconInBackoff() bool {
 func getDeaconHookBead() string {
 	// The deacon agent bead is hq-deacon (town-level)
 	cmd := exec.Command("bd", "slot", "show", "hq-deacon", "--json")
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		// If we can't check, assume no hook (may false-positive nudge on bd failure)
 		return ""
@@ -432,7 +433,7 @@ func getDeaconHookBead() string {
 // TODO(steveyegge/beads#1456): Replace with `bd mol last-activity` when available.
 func getMoleculeLastActivity(molID string) (time.Time, error) {
 	cmd := exec.Command("bd", "mol", "current", molID, "--json")
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 var catJSON bool
@@ -52,7 +53,7 @@ func runCat(cmd *cobra.Command, args []string) error {
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 
-	return bdCmd.Run()
+	return proc.RunCmd(bdCmd)
 }
 
 // isBeadID checks if a string looks like a bead ID.
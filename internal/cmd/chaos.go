@@ -0,0 +1,135 @@
+//go:build chaos
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/chaos"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// gt chaos is only compiled into dev builds made with -tags chaos.
+var chaosCmd = &cobra.Command{
+	Use:     "chaos",
+	GroupID: GroupDiag,
+	Short:   "Run gt commands under injected faults (dev builds only)",
+	RunE:    requireSubcommand,
+	Long: `Run gt commands with fault injection enabled.
+
+This command exists only in binaries built with -tags chaos. Faults are
+injected into doltserver and daemon paths (server start, SQL connections,
+branch merges, state loading) to verify crash-restart, split-brain
+detection, and merge-queue behavior before they happen in production.
+
+Faults can also be activated directly with GT_CHAOS:
+  GT_CHAOS=flaky-connections gt dolt status
+  GT_CHAOS="dolt.merge=delay:5s,dolt.conn=drop@0.2" gt done
+
+Examples:
+  gt chaos list                              # Show scenarios and injection points
+  gt chaos run stale-state                   # Run the scenario's default command
+  gt chaos run slow-merge -- done            # Run 'gt done' under slow merges`,
+}
+
+var chaosListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List chaos scenarios and injection points",
+	RunE:  runChaosList,
+}
+
+var chaosRunCmd = &cobra.Command{
+	Use:   "run <scenario> [-- <gt args>...]",
+	Short: "Run a gt command under a chaos scenario",
+	Long: `Run a gt subcommand with the scenario's faults active.
+
+The scenario may be a built-in name (see 'gt chaos list') or a raw fault
+spec such as "dolt.conn=drop@0.5". Arguments after -- are passed to gt;
+without them, the scenario's default command is used.
+
+The command's exit code is propagated.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runChaosRun,
+}
+
+func init() {
+	chaosCmd.AddCommand(chaosListCmd)
+	chaosCmd.AddCommand(chaosRunCmd)
+	rootCmd.AddCommand(chaosCmd)
+}
+
+func runChaosList(cmd *cobra.Command, args []string) error {
+	fmt.Printf("%s\n\n", style.Bold.Render("Scenarios:"))
+	for _, name := range chaos.ScenarioNames() {
+		s, _ := chaos.FindScenario(name)
+		fmt.Printf("  %s\n", style.Bold.Render(s.Name))
+		fmt.Printf("    %s\n", s.Description)
+		faults := make([]string, len(s.Faults))
+		for i, f := range s.Faults {
+			faults[i] = f.String()
+		}
+		fmt.Printf("    faults:  %s\n", style.Dim.Render(strings.Join(faults, ", ")))
+		if len(s.Command) > 0 {
+			fmt.Printf("    command: %s\n", style.Dim.Render("gt "+strings.Join(s.Command, " ")))
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Injection points:"))
+	for _, p := range chaos.Points {
+		fmt.Printf("  %s\n", p)
+	}
+	return nil
+}
+
+func runChaosRun(cmd *cobra.Command, args []string) error {
+	spec := args[0]
+	faults, err := chaos.ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	gtArgs := args[1:]
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		gtArgs = args[dash:]
+	}
+	if len(gtArgs) == 0 {
+		s, ok := chaos.FindScenario(spec)
+		if !ok || len(s.Command) == 0 {
+			return fmt.Errorf("scenario %q has no default command; pass one after --", spec)
+		}
+		gtArgs = s.Command
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt binary: %w", err)
+	}
+
+	fmt.Printf("%s Running %s under %d fault(s): %s\n\n",
+		style.Bold.Render("⚡"), style.Bold.Render("gt "+strings.Join(gtArgs, " ")), len(faults), spec)
+
+	child := exec.Command(self, gtArgs...) //nolint:gosec // G204: re-executes this binary
+	child.Env = append(os.Environ(), chaos.EnvVar+"="+spec)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	runErr := child.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil:
+		fmt.Printf("\n%s Command succeeded under chaos\n", style.Bold.Render("✓"))
+		return nil
+	case errors.As(runErr, &exitErr):
+		fmt.Printf("\n%s Command exited %d under chaos\n", style.Bold.Render("✗"), exitErr.ExitCode())
+		return NewSilentExit(exitErr.ExitCode())
+	default:
+		return fmt.Errorf("running gt: %w", runErr)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 var closeCmd = &cobra.Command{
@@ -55,5 +56,5 @@ func runClose(cmd *cobra.Command, args []string) error {
 	bdCmd.Stdin = os.Stdin
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return proc.RunCmd(bdCmd)
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -327,7 +328,7 @@ func createCompactReportBead(report *compactReport, markdown string) (string, er
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := proc.CombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating report bead: %w\nOutput: %s", err, string(output))
 	}
@@ -336,7 +337,7 @@ func createCompactReportBead(report *compactReport, markdown string) (string, er
 
 	// Auto-close (audit record, not work)
 	closeCmd := exec.Command("bd", "close", beadID, "--reason=daily compaction report")
-	_ = closeCmd.Run()
+	_ = proc.RunCmd(closeCmd)
 
 	return beadID, nil
 }
@@ -420,7 +421,7 @@ func queryCompactionReports(startDate, endDate string) ([]*compactReport, error)
 		"--json",
 		"--limit=0",
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := proc.Output(listCmd)
 	if err != nil {
 		return nil, fmt.Errorf("listing event beads: %w", err)
 	}
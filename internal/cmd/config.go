@@ -29,7 +29,10 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config profile use <name>       Switch config profile (dev/staging/prod)
+  gt config defaults show            Show default flag values per command
+  gt config show                     Show effective timing thresholds`,
 }
 
 // Agent subcommands
@@ -321,7 +324,7 @@ func runConfigAgentSet(cmd *cobra.Command, args []string) error {
 
 	// Load town settings
 	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	townSettings, err := config.LoadTownSettingsBase(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
 	}
@@ -380,7 +383,7 @@ func runConfigAgentRemove(cmd *cobra.Command, args []string) error {
 
 	// Load town settings
 	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	townSettings, err := config.LoadTownSettingsBase(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
 	}
@@ -431,8 +434,13 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Set new default
+	// Set new default, in the settings file itself rather than the
+	// profile-applied settings shown above.
 	name := args[0]
+	townSettings, err = config.LoadTownSettingsBase(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
 
 	// Verify agent exists
 	isValid := false
@@ -489,8 +497,13 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Set new domain
+	// Set new domain, in the settings file itself rather than the
+	// profile-applied settings shown above.
 	domain := args[0]
+	townSettings, err = config.LoadTownSettingsBase(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
 
 	// Basic validation - domain should not be empty and should not start with @
 	if domain == "" {
@@ -562,7 +575,7 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	}
 
 	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	townSettings, err := config.LoadTownSettingsBase(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
 	}
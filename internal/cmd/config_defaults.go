@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var configDefaultsShowJSON bool
+
+var configDefaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Default flag values per command",
+	Long: `Set default flag values for commands you run often.
+
+Defaults live in a "defaults" object mapping a command path (the command
+without "gt") to flag values, in the town's settings/config.json or in your
+user config at ~/.config/gastown/config.json:
+
+  "defaults": {
+    "mail inbox": {"json": "true"},
+    "patrol digest": {"rig": "gastown"},
+    "stale": {"threshold": "8"}
+  }
+
+A default can also come from the environment as GT_DEFAULT_<COMMAND>_<FLAG>,
+e.g. GT_DEFAULT_MAIL_INBOX_JSON=true.
+
+Precedence, highest first: the command line, the environment, town
+settings, user config. A default only applies to the exact command path;
+persistent flags must be set for each command that uses them.
+
+Examples:
+  gt config defaults show
+  gt config defaults show mail inbox
+  gt config defaults show --json`,
+	RunE: requireSubcommand,
+}
+
+var configDefaultsShowCmd = &cobra.Command{
+	Use:   "show [command...]",
+	Short: "Show effective flag defaults and where they come from",
+	Long: `Show the flag defaults in effect and the source of each.
+
+With no arguments, lists every default configured in town settings and user
+config. With a command, lists every flag of that command that has a
+default, including ones set in the environment.`,
+	RunE: runConfigDefaultsShow,
+}
+
+func init() {
+	configDefaultsShowCmd.Flags().BoolVar(&configDefaultsShowJSON, "json", false, "Output as JSON")
+	configDefaultsCmd.AddCommand(configDefaultsShowCmd)
+	configCmd.AddCommand(configDefaultsCmd)
+}
+
+// userSettingsPath returns the path of the per-user config file.
+func userSettingsPath() string {
+	return filepath.Join(state.ConfigDir(), "config.json")
+}
+
+// loadFlagDefaults loads the configured town and user flag defaults. Town
+// defaults are empty outside a workspace.
+func loadFlagDefaults() (town, user config.FlagDefaults, err error) {
+	if townRoot, wsErr := workspace.FindFromCwd(); wsErr == nil && townRoot != "" {
+		settings, loadErr := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if loadErr != nil {
+			return nil, nil, loadErr
+		}
+		town = settings.Defaults
+	}
+	userSettings, err := config.LoadUserSettings(userSettingsPath())
+	if err != nil {
+		return town, nil, err
+	}
+	return town, userSettings.Defaults, nil
+}
+
+// commandKey returns the path used to look up a command's defaults: the
+// command as typed, without the root command name.
+func commandKey(cmd *cobra.Command) string {
+	return strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+}
+
+// applyFlagDefaults sets configured defaults on every flag of cmd not given
+// on the command line. Bad values are reported and skipped.
+func applyFlagDefaults(cmd *cobra.Command) {
+	town, user, err := loadFlagDefaults()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ flag defaults: %v\n", err)
+	}
+	key := commandKey(cmd)
+	flags := cmd.Flags()
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			return
+		}
+		d, ok := config.ResolveFlagDefault(key, f.Name, town, user)
+		if !ok {
+			return
+		}
+		if err := flags.Set(f.Name, d.Value); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ ignoring %s default for gt %s --%s: %v\n", d.Source, key, f.Name, err)
+		}
+	})
+}
+
+// flagDefaultRow is one line of gt config defaults show.
+type flagDefaultRow struct {
+	Command string `json:"command"`
+	config.EffectiveFlagDefault
+	Problem string `json:"problem,omitempty"`
+}
+
+func runConfigDefaultsShow(cmd *cobra.Command, args []string) error {
+	town, user, err := loadFlagDefaults()
+	if err != nil {
+		return fmt.Errorf("loading flag defaults: %w", err)
+	}
+
+	var rows []flagDefaultRow
+	if len(args) > 0 {
+		target, _, err := rootCmd.Find(args)
+		if err != nil || commandKey(target) != strings.Join(args, " ") {
+			return fmt.Errorf("unknown command: gt %s", strings.Join(args, " "))
+		}
+		rows = commandFlagDefaults(target, town, user)
+	} else {
+		rows = configuredFlagDefaults(town, user)
+	}
+
+	if configDefaultsShowJSON {
+		if rows == nil {
+			rows = []flagDefaultRow{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Flag defaults (command line > env > town > user)"))
+	if len(rows) == 0 {
+		fmt.Printf("\n  %s\n", style.Dim.Render("none set"))
+		return nil
+	}
+	lastCommand := ""
+	for _, r := range rows {
+		if r.Command != lastCommand {
+			fmt.Printf("\n  %s\n", style.Bold.Render("gt "+r.Command))
+			lastCommand = r.Command
+		}
+		source := r.Source
+		if len(r.Shadowed) > 0 {
+			source += ", overrides " + strings.Join(r.Shadowed, ", ")
+		}
+		line := fmt.Sprintf("    --%s=%s  %s", r.Flag, r.Value, style.Dim.Render("("+source+")"))
+		if r.Problem != "" {
+			line += "  " + style.Warning.Render("⚠ "+r.Problem)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// commandFlagDefaults returns the defaults in effect for each of target's
+// flags, including inherited ones.
+func commandFlagDefaults(target *cobra.Command, town, user config.FlagDefaults) []flagDefaultRow {
+	key := commandKey(target)
+	names := map[string]bool{}
+	var rows []flagDefaultRow
+	visit := func(f *pflag.Flag) {
+		if names[f.Name] {
+			return
+		}
+		names[f.Name] = true
+		if d, ok := config.ResolveFlagDefault(key, f.Name, town, user); ok {
+			rows = append(rows, flagDefaultRow{Command: key, EffectiveFlagDefault: d})
+		}
+	}
+	target.Flags().VisitAll(visit)
+	target.InheritedFlags().VisitAll(visit)
+
+	// Configured flags the command doesn't have would otherwise go unnoticed.
+	for _, row := range configuredFlagDefaults(town, user) {
+		if row.Command == key && !names[row.Flag] {
+			row.Problem = "unknown flag"
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Flag < rows[j].Flag })
+	return rows
+}
+
+// configuredFlagDefaults returns every default set in town or user config,
+// flagging ones that name a command or flag gt doesn't have.
+func configuredFlagDefaults(town, user config.FlagDefaults) []flagDefaultRow {
+	flagsByCommand := map[string]map[string]bool{}
+	for _, defaults := range []config.FlagDefaults{town, user} {
+		for command, flags := range defaults {
+			if flagsByCommand[command] == nil {
+				flagsByCommand[command] = map[string]bool{}
+			}
+			for flag := range flags {
+				flagsByCommand[command][flag] = true
+			}
+		}
+	}
+
+	var rows []flagDefaultRow
+	for command, flags := range flagsByCommand {
+		var target *cobra.Command
+		if c, _, err := rootCmd.Find(strings.Fields(command)); err == nil && commandKey(c) == command {
+			target = c
+		}
+		for flag := range flags {
+			d, _ := config.ResolveFlagDefault(command, flag, town, user)
+			row := flagDefaultRow{Command: command, EffectiveFlagDefault: d}
+			switch {
+			case target == nil:
+				row.Problem = "unknown command"
+			case target.Flags().Lookup(flag) == nil && target.InheritedFlags().Lookup(flag) == nil:
+				row.Problem = "unknown flag"
+			}
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Command != rows[j].Command {
+			return rows[i].Command < rows[j].Command
+		}
+		return rows[i].Flag < rows[j].Flag
+	})
+	return rows
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestApplyFlagDefaultsPrecedence(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	var jsonOut bool
+	var rig string
+	c := &cobra.Command{Use: "flagdefaultstest", Run: func(*cobra.Command, []string) {}}
+	c.Flags().BoolVar(&jsonOut, "json", false, "")
+	c.Flags().StringVar(&rig, "rig", "", "")
+	rootCmd.AddCommand(c)
+	t.Cleanup(func() { rootCmd.RemoveCommand(c) })
+
+	t.Setenv(config.FlagDefaultEnvVar("flagdefaultstest", "json"), "true")
+	t.Setenv(config.FlagDefaultEnvVar("flagdefaultstest", "rig"), "gastown")
+	if err := c.Flags().Parse([]string{"--rig", "beads"}); err != nil {
+		t.Fatal(err)
+	}
+	applyFlagDefaults(c)
+
+	if !jsonOut {
+		t.Error("--json default from env not applied")
+	}
+	if rig != "beads" {
+		t.Errorf("--rig = %q, command line should win over env", rig)
+	}
+}
+
+func TestConfiguredFlagDefaultsProblems(t *testing.T) {
+	town := config.FlagDefaults{
+		"config defaults show": {"json": "true", "nope": "1"},
+		"no such command":      {"json": "true"},
+	}
+	rows := configuredFlagDefaults(town, nil)
+	problems := map[string]string{}
+	for _, r := range rows {
+		problems[r.Command+" --"+r.Flag] = r.Problem
+	}
+	want := map[string]string{
+		"config defaults show --json": "",
+		"config defaults show --nope": "unknown flag",
+		"no such command --json":      "unknown command",
+	}
+	for k, v := range want {
+		if got, ok := problems[k]; !ok || got != v {
+			t.Errorf("%s: problem = %q (present %v), want %q", k, got, ok, v)
+		}
+	}
+}
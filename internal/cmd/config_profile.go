@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configProfileListJSON bool
+	configProfileDiffJSON bool
+)
+
+var configProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Switch between named config profiles (dev, staging, prod)",
+	Long: `Manage named config profiles.
+
+A profile overrides parts of settings/config.json and mayor/daemon.json for
+one environment. Profiles are defined in settings/config.json:
+
+  "profiles": {
+    "dev": {
+      "description": "Laptop: cheap agents, slow patrols",
+      "settings": {"default_agent": "claude-haiku"},
+      "daemon": {"patrols": {"session_prune": {"interval": "5m"}}}
+    },
+    "prod": {
+      "settings": {"dolt_stats": {"retention": {"archive_days": 7}}}
+    }
+  },
+  "active_profile": "prod"
+
+Objects merge key by key; any other value replaces the file's value. The
+active profile is active_profile, unless GT_PROFILE is set in the
+environment (GT_PROFILE=none runs without a profile). Every command and
+the daemon load settings with the active profile applied.
+
+Commands that change settings (gt config set, gt config agent set, ...)
+edit the file itself, never the profile-applied values.
+
+Examples:
+  gt config profile list
+  gt config profile use dev
+  gt config profile diff prod       # What prod changes
+  gt config profile clear`,
+	RunE: requireSubcommand,
+}
+
+var configProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List config profiles and show which is active",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigProfileList,
+}
+
+var configProfileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile the town's active profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigProfileUse,
+}
+
+var configProfileClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Run without an active profile",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigProfileClear,
+}
+
+var configProfileDiffCmd = &cobra.Command{
+	Use:   "diff [name]",
+	Short: "Show the effective settings a profile changes",
+	Long: `Show every setting a profile changes, compared with running without
+a profile. Defaults to the active profile.
+
+Examples:
+  gt config profile diff
+  gt config profile diff staging --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigProfileDiff,
+}
+
+func init() {
+	configProfileListCmd.Flags().BoolVar(&configProfileListJSON, "json", false, "Output as JSON")
+	configProfileDiffCmd.Flags().BoolVar(&configProfileDiffJSON, "json", false, "Output as JSON")
+
+	configProfileCmd.AddCommand(configProfileListCmd)
+	configProfileCmd.AddCommand(configProfileUseCmd)
+	configProfileCmd.AddCommand(configProfileClearCmd)
+	configProfileCmd.AddCommand(configProfileDiffCmd)
+	configCmd.AddCommand(configProfileCmd)
+}
+
+// ConfigProfileItem represents a profile in list output.
+type ConfigProfileItem struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Sections    []string `json:"sections,omitempty"`
+	Active      bool     `json:"active"`
+	Source      string   `json:"source,omitempty"` // "env" or "settings" when active
+}
+
+func loadBaseTownSettings() (string, *config.TownSettings, error) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return "", nil, fmt.Errorf("finding town root: %w", err)
+	}
+	settings, err := config.LoadTownSettingsBase(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return townRoot, settings, nil
+}
+
+func runConfigProfileList(cmd *cobra.Command, args []string) error {
+	_, settings, err := loadBaseTownSettings()
+	if err != nil {
+		return err
+	}
+	active, source := config.ActiveProfileName(settings)
+
+	names := make([]string, 0, len(settings.Profiles))
+	for name := range settings.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := []ConfigProfileItem{}
+	for _, name := range names {
+		p := settings.Profiles[name]
+		item := ConfigProfileItem{Name: name}
+		if p != nil {
+			item.Description = p.Description
+			for _, section := range []string{config.ProfileSectionSettings, config.ProfileSectionDaemon} {
+				if len(p.Section(section)) > 0 {
+					item.Sections = append(item.Sections, section)
+				}
+			}
+		}
+		if name == active {
+			item.Active = true
+			item.Source = source
+		}
+		items = append(items, item)
+	}
+
+	if configProfileListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+	if len(items) == 0 {
+		fmt.Println("No config profiles defined (see 'gt config profile --help')")
+		return nil
+	}
+	for _, item := range items {
+		marker := " "
+		if item.Active {
+			marker = style.Success.Render("*")
+		}
+		line := fmt.Sprintf("%s %s", marker, style.Bold.Render(item.Name))
+		if item.Description != "" {
+			line += "  " + style.Dim.Render(item.Description)
+		}
+		if item.Active && item.Source == "env" {
+			line += "  " + style.Dim.Render("(from "+config.ProfileEnvVar+")")
+		}
+		fmt.Println(line)
+	}
+	if active != "" {
+		if _, ok := settings.Profiles[active]; !ok {
+			fmt.Printf("\n%s Active profile %q is not defined\n", style.Warning.Render("⚠"), active)
+		}
+	}
+	return nil
+}
+
+func runConfigProfileUse(cmd *cobra.Command, args []string) error {
+	townRoot, settings, err := loadBaseTownSettings()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if _, err := settings.Profile(name); err != nil {
+		return err
+	}
+	settings.ActiveProfile = name
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Active profile set to '%s'\n", style.Success.Render("✓"), style.Bold.Render(name))
+	warnProfileEnvOverride()
+	return nil
+}
+
+func runConfigProfileClear(cmd *cobra.Command, args []string) error {
+	townRoot, settings, err := loadBaseTownSettings()
+	if err != nil {
+		return err
+	}
+	if settings.ActiveProfile == "" {
+		fmt.Println("No active profile")
+		warnProfileEnvOverride()
+		return nil
+	}
+	settings.ActiveProfile = ""
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Active profile cleared\n", style.Success.Render("✓"))
+	warnProfileEnvOverride()
+	return nil
+}
+
+// warnProfileEnvOverride notes that GT_PROFILE overrides active_profile
+// in this shell.
+func warnProfileEnvOverride() {
+	if env := os.Getenv(config.ProfileEnvVar); env != "" {
+		fmt.Printf("%s %s=%s overrides the active profile in this environment\n",
+			style.Warning.Render("⚠"), config.ProfileEnvVar, env)
+	}
+}
+
+func runConfigProfileDiff(cmd *cobra.Command, args []string) error {
+	townRoot, settings, err := loadBaseTownSettings()
+	if err != nil {
+		return err
+	}
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else if name, _ = config.ActiveProfileName(settings); name == "" {
+		return fmt.Errorf("no active profile; name one: gt config profile diff <name>")
+	}
+
+	changes, err := config.ProfileDiff(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if configProfileDiffJSON {
+		if changes == nil {
+			changes = []config.ProfileChange{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("Profile %s changes no settings\n", style.Bold.Render(name))
+		return nil
+	}
+	fmt.Printf("Profile %s changes:\n", style.Bold.Render(name))
+	section := ""
+	for _, c := range changes {
+		if c.Section != section {
+			section = c.Section
+			fmt.Printf("\n  %s\n", style.Dim.Render(profileSectionFile(section)))
+		}
+		from := c.From
+		if from == "" {
+			from = style.Dim.Render("(unset)")
+		}
+		fmt.Printf("    %s: %s → %s\n", c.Path, from, c.To)
+	}
+	return nil
+}
+
+func profileSectionFile(section string) string {
+	switch section {
+	case config.ProfileSectionDaemon:
+		return "mayor/daemon.json"
+	default:
+		return "settings/config.json"
+	}
+}
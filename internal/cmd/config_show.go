@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var configShowJSON bool
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show effective timing thresholds",
+	Long: `Show every duration setting in effect and whether it is the default.
+
+Durations are read from mayor/daemon.json (guards, patrol intervals, and
+the managed Dolt server's restart timings), from settings/config.json (the
+Dolt server's startup timeout) and from the Deacon's role bead (stuck-agent
+thresholds). Values are written as human-friendly strings
+such as "30s", "5m", "1h30m" or "2d"; daemon.json files that still hold
+nanosecond counts keep working.
+
+An invalid value makes the daemon ignore daemon.json (and gt ignore
+settings/config.json), so this command reports the parse error instead of
+showing the defaults as if they applied.
+
+Examples:
+  gt config show
+  gt config show --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runConfigShow,
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configShowJSON, "json", false, "Output as JSON")
+	configCmd.AddCommand(configShowCmd)
+}
+
+// configShowSection is one source of settings in gt config show.
+type configShowSection struct {
+	Source   string          `json:"source"`
+	Settings []daemon.Timing `json:"settings"`
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	patrolConfig, err := daemon.ReadPatrolConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", settingsPath, err)
+	}
+
+	stuck := deacon.LoadStuckConfig(townRoot)
+	defaults := deacon.DefaultStuckConfig()
+	sections := []configShowSection{
+		{Source: "mayor/daemon.json", Settings: daemon.EffectiveTimings(townRoot, patrolConfig)},
+		{Source: "settings/config.json", Settings: daemon.TownTimings(townSettings)},
+		{Source: "deacon role bead", Settings: []daemon.Timing{
+			{Key: "ping_timeout", Value: config.Dur(stuck.PingTimeout), Default: stuck.PingTimeout == defaults.PingTimeout},
+			{Key: "kill_cooldown", Value: config.Dur(stuck.Cooldown), Default: stuck.Cooldown == defaults.Cooldown},
+		}},
+	}
+
+	if configShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sections)
+	}
+
+	for i, s := range sections {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", style.Bold.Render(s.Source))
+		width := 0
+		for _, t := range s.Settings {
+			width = max(width, len(t.Key))
+		}
+		for _, t := range s.Settings {
+			line := fmt.Sprintf("  %-*s  %s", width, t.Key, t.Value)
+			if t.Default {
+				line += "  " + style.Dim.Render("(default)")
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
@@ -18,8 +18,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
+	"github.com/steveyegge/gastown/internal/ui"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -471,7 +474,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	createCmd.Stdout = &stdout
 	createCmd.Stderr = &stderr
 
-	if err := createCmd.Run(); err != nil {
+	if err := proc.RunCmd(createCmd); err != nil {
 		return fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
 	}
 
@@ -487,7 +490,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		var depStderr bytes.Buffer
 		depCmd.Stderr = &depStderr
 
-		if err := depCmd.Run(); err != nil {
+		if err := proc.RunCmd(depCmd); err != nil {
 			errMsg := strings.TrimSpace(depStderr.String())
 			if errMsg == "" {
 				errMsg = err.Error()
@@ -546,7 +549,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -582,7 +585,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		reopenArgs := []string{"update", convoyID, "--status=open"}
 		reopenCmd := exec.Command("bd", reopenArgs...)
 		reopenCmd.Dir = townBeads
-		if err := reopenCmd.Run(); err != nil {
+		if err := proc.RunCmd(reopenCmd); err != nil {
 			return fmt.Errorf("couldn't reopen convoy: %w", err)
 		}
 		reopened = true
@@ -598,7 +601,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		var depStderr bytes.Buffer
 		depCmd.Stderr = &depStderr
 
-		if err := depCmd.Run(); err != nil {
+		if err := proc.RunCmd(depCmd); err != nil {
 			errMsg := strings.TrimSpace(depStderr.String())
 			if errMsg == "" {
 				errMsg = err.Error()
@@ -648,7 +651,7 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 			fmt.Printf("%s Auto-closed %d convoy(s):\n", style.Bold.Render("✓"), len(closed))
 		}
 		for _, c := range closed {
-			fmt.Printf("  🚚 %s: %s\n", c.ID, c.Title)
+			fmt.Printf("  🚚 %s: %s\n", c.ID, ui.TruncateField(c.Title))
 		}
 	}
 
@@ -664,7 +667,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -735,7 +738,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -762,7 +765,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -825,7 +828,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 				if t.Status == "in_progress" || t.Status == "hooked" {
 					status = "▶"
 				}
-				fmt.Printf("    %s %s: %s [%s]\n", status, t.ID, t.Title, t.Status)
+				fmt.Printf("    %s %s: %s [%s]\n", status, t.ID, ui.TruncateField(t.Title), t.Status)
 			}
 			fmt.Printf("\n  Use %s to close anyway.\n", style.Bold.Render("--force"))
 			return fmt.Errorf("convoy has %d open issue(s)", len(openIssues))
@@ -847,7 +850,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -922,7 +925,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -1031,7 +1034,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -1067,8 +1070,7 @@ func findConvoyWorktrees(tracked []trackedIssueInfo) []convoyWorktreeInfo {
 		return nil
 	}
 
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		return nil
 	}
@@ -1229,7 +1231,7 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := proc.RunCmd(listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -1342,7 +1344,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := proc.RunCmd(listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -1392,7 +1394,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			closeCmd := exec.Command("bd", closeArgs...)
 			closeCmd.Dir = townBeads
 
-			if err := closeCmd.Run(); err != nil {
+			if err := proc.RunCmd(closeCmd); err != nil {
 				style.PrintWarning("couldn't close convoy %s: %v", convoy.ID, err)
 				continue
 			}
@@ -1416,7 +1418,7 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return
 	}
 
@@ -1505,7 +1507,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -1646,7 +1648,7 @@ func showAllConvoyStatus(townBeads string) error {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := proc.RunCmd(listCmd); err != nil {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -1705,7 +1707,7 @@ func runConvoyList(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := proc.RunCmd(listCmd); err != nil {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -1957,7 +1959,7 @@ func getTrackedIssues(townBeads, convoyID string) ([]trackedIssueInfo, error) {
 
 	var stdout bytes.Buffer
 	depCmd.Stdout = &stdout
-	if err := depCmd.Run(); err != nil {
+	if err := proc.RunCmd(depCmd); err != nil {
 		return nil, fmt.Errorf("querying tracked issues for %s: %w", convoyID, err)
 	}
 
@@ -2070,7 +2072,7 @@ func getExternalIssueDetails(townBeads, rigName, issueID string) *issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return nil
 	}
 	if stdout.Len() == 0 {
@@ -2131,7 +2133,7 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		// Batch failed - fall back to individual lookups for robustness
 		// This handles cases where some IDs are invalid/missing
 		for _, id := range issueIDs {
@@ -2162,7 +2164,7 @@ func getIssueDetails(issueID string) *issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return nil
 	}
 	// Handle bd exit 0 bug: empty stdout means not found
@@ -2243,7 +2245,7 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 			cmd.Dir = beadsDir
 			var stdout bytes.Buffer
 			cmd.Stdout = &stdout
-			if err := cmd.Run(); err != nil {
+			if err := proc.RunCmd(cmd); err != nil {
 				resultChan <- rigResult{}
 				return
 			}
@@ -2360,7 +2362,7 @@ func resolveConvoyNumber(townBeads string, n int) (string, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := proc.RunCmd(listCmd); err != nil {
 		return "", fmt.Errorf("listing convoys: %w", err)
 	}
 
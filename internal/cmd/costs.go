@@ -17,8 +17,12 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/redact"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -64,7 +68,8 @@ Examples:
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs forecast     # Project month-end spend against budgets, with --what-if <tier>`,
 	RunE: runCosts,
 }
 
@@ -82,6 +87,12 @@ due to database availability.
 Session costs are aggregated daily by 'gt costs digest' into a single
 permanent "Cost Report YYYY-MM-DD" bead for audit purposes.
 
+If "transcripts": {"archive": true} is set in settings/config.json, the
+transcript is also archived to .runtime/transcripts/<session>/ after
+secrets are redacted (built-in patterns, entropy detection, and the
+configured transcripts.redaction rules for the session's role). A
+<transcript>.redactions.json report beside it counts what was redacted.
+
 Examples:
   gt costs record --session gt-gastown-toast
   gt costs record --session gt-gastown-toast --work-item gt-abc123`,
@@ -219,14 +230,46 @@ type TokenUsage struct {
 	OutputTokens             int
 }
 
+// ModelPrice is a model's price per million tokens.
+type ModelPrice struct {
+	InputPerMillion       float64 `json:"input"`
+	OutputPerMillion      float64 `json:"output"`
+	CacheReadPerMillion   float64 `json:"cache_read"`   // 90% discount on input price
+	CacheCreatePerMillion float64 `json:"cache_create"` // 25% premium on input price
+}
+
+// PricingTable maps model IDs to prices. The "default" entry prices models
+// the table doesn't list.
+type PricingTable map[string]ModelPrice
+
+// Cost converts token usage to USD under the table.
+func (t PricingTable) Cost(usage *TokenUsage) float64 {
+	if usage == nil {
+		return 0.0
+	}
+
+	// Look up pricing for the model
+	pricing, ok := t[usage.Model]
+	if !ok {
+		pricing = t["default"]
+	}
+
+	// Calculate cost (prices are per million tokens)
+	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
+	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
+	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
+	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
+
+	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+}
+
+// currentPricingVersion names modelPricing. Recorded costs carry it so they
+// can be told apart from costs priced under earlier tables.
+const currentPricingVersion = "2025-01"
+
 // Model pricing per million tokens (as of Jan 2025).
 // See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
+var modelPricing = PricingTable{
 	// Claude Opus 4.5
 	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
 	// Claude Sonnet 4
@@ -248,12 +291,34 @@ func runCosts(cmd *cobra.Command, args []string) error {
 }
 
 func runLiveCosts() error {
-	t := tmux.NewTmux()
+	costs, total, err := liveSessionCosts(tmux.NewTmux())
+	if err != nil {
+		return err
+	}
+
+	if costsJSON {
+		return outputCostsJSON(CostsOutput{
+			Sessions: costs,
+			Total:    total,
+		})
+	}
+
+	return outputCostsHuman(costs, total)
+}
+
+// LiveSessionCosts returns the current cost of each running Gas Town session,
+// sorted by session name, and their total.
+func LiveSessionCosts() ([]SessionCost, float64, error) {
+	return liveSessionCosts(tmux.NewTmux())
+}
 
+// liveSessionCosts returns the current cost of each Gas Town session, sorted
+// by session name, and their total.
+func liveSessionCosts(t *tmux.Tmux) ([]SessionCost, float64, error) {
 	// Get all tmux sessions
 	sessions, err := t.ListSessions()
 	if err != nil {
-		return fmt.Errorf("listing sessions: %w", err)
+		return nil, 0, fmt.Errorf("listing sessions: %w", err)
 	}
 
 	var costs []SessionCost
@@ -306,14 +371,7 @@ func runLiveCosts() error {
 		return costs[i].Session < costs[j].Session
 	})
 
-	if costsJSON {
-		return outputCostsJSON(CostsOutput{
-			Sessions: costs,
-			Total:    total,
-		})
-	}
-
-	return outputCostsHuman(costs, total)
+	return costs, total, nil
 }
 
 func runCostsFromLedger() error {
@@ -438,8 +496,7 @@ func querySessionEvents() []CostEntry {
 	beadsLocations := []string{townRoot}
 
 	// Load rigs to find all rig beads locations
-	rigsConfigPath := filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err == nil && rigsConfig != nil {
 		for rigName := range rigsConfig.Rigs {
 			rigPath := filepath.Join(townRoot, rigName)
@@ -491,7 +548,7 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 	listCmd := exec.Command("bd", listArgs...)
 	listCmd.Dir = location
-	listOutput, err := listCmd.Output()
+	listOutput, err := proc.Output(listCmd)
 	if err != nil {
 		// If bd fails (e.g., no beads database), return empty list
 		return nil, nil
@@ -515,7 +572,7 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 	showCmd := exec.Command("bd", showArgs...)
 	showCmd.Dir = location
-	showOutput, err := showCmd.Output()
+	showOutput, err := proc.Output(showCmd)
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
 	}
@@ -574,7 +631,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	listOutput, err := proc.Output(listCmd)
 	if err != nil {
 		return nil, nil
 	}
@@ -595,7 +652,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
+	showOutput, err := proc.Output(showCmd)
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
 	}
@@ -625,7 +682,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 		}
 
 		// Check date is within range
-		digestDate, err := time.Parse("2006-01-02", digest.Date)
+		digestDate, err := timefmt.Parse(digest.Date)
 		if err != nil {
 			continue
 		}
@@ -797,46 +854,77 @@ func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
 	return usage, nil
 }
 
-// calculateCost converts token usage to USD cost based on model pricing.
+// calculateCost converts token usage to USD cost based on current model pricing.
 func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
-	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+	return modelPricing.Cost(usage)
 }
 
 // extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
 // This reads the most recent transcript file and sums all token usage.
 func extractCostFromWorkDir(workDir string) (float64, error) {
+	usage, err := extractUsageFromWorkDir(workDir)
+	if err != nil {
+		return 0, err
+	}
+	return calculateCost(usage), nil
+}
+
+// extractUsageFromWorkDir sums the token usage in the most recent Claude Code
+// transcript for a working directory.
+func extractUsageFromWorkDir(workDir string) (*TokenUsage, error) {
 	projectDir, err := getClaudeProjectDir(workDir)
 	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
+		return nil, fmt.Errorf("getting project dir: %w", err)
 	}
 
 	transcriptPath, err := findLatestTranscript(projectDir)
 	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
+		return nil, fmt.Errorf("finding transcript: %w", err)
 	}
 
 	usage, err := parseTranscriptUsage(transcriptPath)
 	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
+		return nil, fmt.Errorf("parsing transcript: %w", err)
 	}
+	return usage, nil
+}
 
-	return calculateCost(usage), nil
+// archiveSessionTranscript copies the session's latest transcript, redacted
+// with the rules for its role, into the town's transcript archive. It does
+// nothing unless transcripts.archive is set in the town settings.
+func archiveSessionTranscript(session, role, workDir string) error {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Transcripts == nil || !settings.Transcripts.Archive {
+		return nil
+	}
+
+	projectDir, err := getClaudeProjectDir(workDir)
+	if err != nil {
+		return fmt.Errorf("getting project dir: %w", err)
+	}
+	transcriptPath, err := findLatestTranscript(projectDir)
+	if err != nil {
+		return fmt.Errorf("finding transcript: %w", err)
+	}
+	r, err := redact.New(settings.Transcripts.Redaction, role)
+	if err != nil {
+		return err
+	}
+	archived, err := redact.ArchiveTranscript(townRoot, session, role, transcriptPath, r)
+	if err != nil {
+		return err
+	}
+	if archived.Redactions.Total > 0 {
+		fmt.Printf("%s Archived transcript for %s, redacted %s\n", style.Dim.Render("○"), session, archived.Redactions)
+	}
+	return nil
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -942,8 +1030,45 @@ type CostLogEntry struct {
 	CostUSD   float64   `json:"cost_usd"`
 	EndedAt   time.Time `json:"ended_at"`
 	WorkItem  string    `json:"work_item,omitempty"`
+
+	// Event marks non-cost entries. Empty for session cost records;
+	// CostEventDowngrade for cost policy downgrades (CostUSD is then the
+	// session's cost when the downgrade was applied, not a new charge).
+	Event        string  `json:"event,omitempty"`
+	Action       string  `json:"action,omitempty"`
+	ThresholdUSD float64 `json:"threshold_usd,omitempty"`
+	Agent        string  `json:"agent,omitempty"`
+
+	// Model is the session's model for cost records, or the model switched
+	// to for downgrades.
+	Model string `json:"model,omitempty"`
+
+	// PricingVersion is the pricing table CostUSD was computed with. Empty
+	// in records from before tables were versioned.
+	PricingVersion string `json:"pricing_version,omitempty"`
+
+	// Token usage behind CostUSD, so costs can be repriced for another
+	// model (gt costs forecast --what-if). Zero in older records.
+	InputTokens       int `json:"input_tokens,omitempty"`
+	OutputTokens      int `json:"output_tokens,omitempty"`
+	CacheReadTokens   int `json:"cache_read_tokens,omitempty"`
+	CacheCreateTokens int `json:"cache_create_tokens,omitempty"`
+}
+
+// Usage returns the entry's recorded token usage.
+func (e CostLogEntry) Usage() TokenUsage {
+	return TokenUsage{
+		Model:                    e.Model,
+		InputTokens:              e.InputTokens,
+		OutputTokens:             e.OutputTokens,
+		CacheReadInputTokens:     e.CacheReadTokens,
+		CacheCreationInputTokens: e.CacheCreateTokens,
+	}
 }
 
+// CostEventDowngrade is the CostLogEntry event for cost policy downgrades.
+const CostEventDowngrade = "downgrade"
+
 // getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
 func getCostsLogPath() string {
 	home, err := os.UserHomeDir()
@@ -989,20 +1114,28 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 
 	// Extract cost from Claude transcript
 	var cost float64
+	usage := &TokenUsage{}
 	if workDir != "" {
-		var err error
-		cost, err = extractCostFromWorkDir(workDir)
-		if err != nil {
+		if u, err := extractUsageFromWorkDir(workDir); err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
 			}
-			cost = 0.0
+		} else {
+			usage = u
+			cost = calculateCost(usage)
 		}
 	}
 
 	// Parse session name
 	role, rig, worker := parseSessionName(session)
 
+	// Archive the transcript (redacted) if the town is configured to
+	if workDir != "" {
+		if err := archiveSessionTranscript(session, role, workDir); err != nil && costsVerbose {
+			fmt.Fprintf(os.Stderr, "[costs] could not archive transcript: %v\n", err)
+		}
+	}
+
 	// Build log entry
 	entry := CostLogEntry{
 		SessionID: session,
@@ -1012,8 +1145,33 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		CostUSD:   cost,
 		EndedAt:   time.Now(),
 		WorkItem:  recordWorkItem,
+
+		Model:             usage.Model,
+		PricingVersion:    currentPricingVersion,
+		InputTokens:       usage.InputTokens,
+		OutputTokens:      usage.OutputTokens,
+		CacheReadTokens:   usage.CacheReadInputTokens,
+		CacheCreateTokens: usage.CacheCreationInputTokens,
+	}
+
+	if err := appendCostLogEntry(entry); err != nil {
+		return err
+	}
+
+	// Output confirmation (silent if cost is zero and no work item)
+	if cost > 0 || recordWorkItem != "" {
+		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
+		if recordWorkItem != "" {
+			fmt.Printf(" (work: %s)", recordWorkItem)
+		}
+		fmt.Println()
 	}
 
+	return nil
+}
+
+// appendCostLogEntry appends an entry to the costs log file.
+func appendCostLogEntry(entry CostLogEntry) error {
 	// Marshal to JSON
 	entryJSON, err := json.Marshal(entry)
 	if err != nil {
@@ -1042,16 +1200,6 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	if _, err := f.Write(append(entryJSON, '\n')); err != nil {
 		return fmt.Errorf("writing to costs log: %w", err)
 	}
-
-	// Output confirmation (silent if cost is zero and no work item)
-	if cost > 0 || recordWorkItem != "" {
-		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
-		if recordWorkItem != "" {
-			fmt.Printf(" (work: %s)", recordWorkItem)
-		}
-		fmt.Println()
-	}
-
 	return nil
 }
 
@@ -1143,7 +1291,7 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 	var targetDate time.Time
 
 	if digestDate != "" {
-		parsed, err := time.Parse("2006-01-02", digestDate)
+		parsed, err := timefmt.Parse(digestDate)
 		if err != nil {
 			return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
 		}
@@ -1154,7 +1302,7 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("specify --yesterday or --date YYYY-MM-DD")
 	}
 
-	dateStr := targetDate.Format("2006-01-02")
+	dateStr := timefmt.Date(targetDate)
 
 	// Query session cost entries for target date
 	costEntries, err := querySessionCostEntries(targetDate)
@@ -1235,7 +1383,7 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 		return nil, fmt.Errorf("reading costs log: %w", err)
 	}
 
-	targetDay := targetDate.Format("2006-01-02")
+	targetDay := timefmt.Date(targetDate)
 	var entries []CostEntry
 
 	// Parse each line as a CostLogEntry
@@ -1255,7 +1403,12 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 		}
 
 		// Filter by target date
-		if logEntry.EndedAt.Format("2006-01-02") != targetDay {
+		if timefmt.Date(logEntry.EndedAt) != targetDay {
+			continue
+		}
+
+		// Skip event entries (downgrades); they don't add cost
+		if logEntry.Event != "" {
 			continue
 		}
 
@@ -1334,7 +1487,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := proc.CombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
 	}
@@ -1343,7 +1496,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 
 	// Auto-close the digest (it's an audit record, not work)
 	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily cost digest")
-	_ = closeCmd.Run() // Best effort
+	_ = proc.RunCmd(closeCmd) // Best effort
 
 	return digestID, nil
 }
@@ -1362,7 +1515,7 @@ func deleteSessionCostEntries(targetDate time.Time) (int, error) {
 		return 0, fmt.Errorf("reading costs log: %w", err)
 	}
 
-	targetDay := targetDate.Format("2006-01-02")
+	targetDay := timefmt.Date(targetDate)
 	var keepLines []string
 	deletedCount := 0
 
@@ -1382,7 +1535,7 @@ func deleteSessionCostEntries(targetDate time.Time) (int, error) {
 		}
 
 		// Remove entries from target date
-		if logEntry.EndedAt.Format("2006-01-02") == targetDay {
+		if timefmt.Date(logEntry.EndedAt) == targetDay {
 			deletedCount++
 			continue
 		}
@@ -1419,7 +1572,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	listOutput, err := proc.Output(listCmd)
 	if err != nil {
 		fmt.Println(style.Dim.Render("No events found or bd command failed"))
 		return nil
@@ -1442,7 +1595,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
+	showOutput, err := proc.Output(showCmd)
 	if err != nil {
 		return fmt.Errorf("showing events: %w", err)
 	}
@@ -1487,7 +1640,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	closedMigrated := 0
 	for _, event := range openEvents {
 		closeCmd := exec.Command("bd", "close", event.ID, "--reason=migrated to log-file architecture")
-		if err := closeCmd.Run(); err != nil {
+		if err := proc.RunCmd(closeCmd); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: could not close %s: %v\n", event.ID, err)
 			continue
 		}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/costpolicy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsEnforceDryRun bool
+
+var costsEnforceCmd = &cobra.Command{
+	Use:   "enforce",
+	Short: "Downgrade sessions whose cost crossed a policy threshold",
+	Long: `Apply the town's cost policy to running sessions.
+
+For each running session whose cost has crossed a threshold in
+cost_policy (settings/config.json), apply the threshold's action once:
+
+  nudge    Type "/model <model>" into the agent, switching models
+           without restarting the session
+  respawn  Restart the session with a cheaper agent config, resuming
+           the conversation where the agent supports it
+
+Each downgrade is recorded in the costs log (~/.gt/costs.jsonl) as a
+"downgrade" event, which also keeps a threshold from applying twice to
+the same session.
+
+Example policy:
+
+  "cost_policy": {
+    "thresholds": [
+      {"usd": 10, "action": "nudge", "model": "sonnet"},
+      {"usd": 25, "action": "respawn", "agent": "claude-haiku", "roles": ["polecat"]}
+    ]
+  }
+
+Enable the daemon's cost_enforce patrol to run this periodically.
+
+Examples:
+  gt costs enforce            # Apply the policy now
+  gt costs enforce --dry-run  # Show what would be downgraded`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCostsEnforce,
+}
+
+func init() {
+	costsEnforceCmd.Flags().BoolVar(&costsEnforceDryRun, "dry-run", false, "Show what would be downgraded without acting")
+	costsEnforceCmd.Flags().BoolVar(&costsJSON, "json", false, "Output downgrades as JSON")
+	costsCmd.AddCommand(costsEnforceCmd)
+}
+
+// CostDowngrade is a cost policy action applied (or planned) for a session.
+type CostDowngrade struct {
+	Session      string  `json:"session"`
+	Role         string  `json:"role"`
+	CostUSD      float64 `json:"cost_usd"`
+	ThresholdUSD float64 `json:"threshold_usd"`
+	Action       string  `json:"action"`
+	Model        string  `json:"model,omitempty"`
+	Agent        string  `json:"agent,omitempty"`
+	Resumed      bool    `json:"resumed,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// ErrNoCostPolicy is returned by EnforceCostPolicy when the town has no
+// cost_policy thresholds configured.
+var ErrNoCostPolicy = errors.New("no cost_policy thresholds configured")
+
+func runCostsEnforce(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	downgrades, err := EnforceCostPolicy(townRoot, costsEnforceDryRun)
+	if errors.Is(err, ErrNoCostPolicy) {
+		if !costsJSON {
+			fmt.Println(style.Dim.Render("No cost_policy thresholds configured"))
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(downgrades)
+	}
+	printCostDowngrades(downgrades)
+	return nil
+}
+
+// EnforceCostPolicy applies the town's cost policy to running sessions and
+// returns the downgrades applied (or, with dryRun, the ones that would be).
+// A failed downgrade is reported in its Error field rather than as an error.
+func EnforceCostPolicy(townRoot string, dryRun bool) ([]CostDowngrade, error) {
+	policy, err := costpolicy.LoadConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := costpolicy.Validate(policy); err != nil {
+		return nil, err
+	}
+	if policy == nil || len(policy.Thresholds) == 0 {
+		return nil, ErrNoCostPolicy
+	}
+
+	t := tmux.NewTmux()
+	costs, _, err := liveSessionCosts(t)
+	if err != nil {
+		return nil, err
+	}
+	logEntries := readCostLogEntries()
+
+	downgrades := []CostDowngrade{}
+	for _, sc := range costs {
+		if !sc.Running || sc.Cost == 0 {
+			continue
+		}
+		var since time.Time
+		if created, err := t.GetSessionCreatedUnix(sc.Session); err == nil {
+			since = time.Unix(created, 0)
+		}
+		applied := appliedCostThreshold(logEntries, sc.Session, since)
+		step := costpolicy.NextStep(policy, sc.Role, sc.Cost, applied)
+		if step == nil {
+			continue
+		}
+
+		d := CostDowngrade{
+			Session:      sc.Session,
+			Role:         sc.Role,
+			CostUSD:      sc.Cost,
+			ThresholdUSD: step.USD,
+			Action:       step.Action,
+			Model:        step.Model,
+			Agent:        step.Agent,
+		}
+		if !dryRun {
+			applyCostDowngrade(t, &d)
+			if d.Error == "" {
+				if err := appendCostLogEntry(CostLogEntry{
+					SessionID:    sc.Session,
+					Role:         sc.Role,
+					Rig:          sc.Rig,
+					Worker:       sc.Worker,
+					CostUSD:      sc.Cost,
+					EndedAt:      time.Now(),
+					Event:        CostEventDowngrade,
+					Action:       step.Action,
+					ThresholdUSD: step.USD,
+					Model:        step.Model,
+					Agent:        step.Agent,
+				}); err != nil {
+					d.Error = err.Error()
+				}
+			}
+		}
+		downgrades = append(downgrades, d)
+	}
+	return downgrades, nil
+}
+
+// applyCostDowngrade performs a downgrade, recording any failure in d.Error.
+func applyCostDowngrade(t *tmux.Tmux, d *CostDowngrade) {
+	switch d.Action {
+	case costpolicy.ActionNudge:
+		if err := t.NudgeSession(d.Session, costpolicy.NudgeCommand(d.Model)); err != nil {
+			d.Error = err.Error()
+		}
+
+	case costpolicy.ActionRespawn:
+		opts := restartOptions{Agent: d.Agent}
+		if workDir, err := getTmuxSessionWorkDir(d.Session); err == nil {
+			opts.ResumeSessionID = latestTranscriptSessionID(workDir)
+		}
+		restartCmd, err := buildRestartCommandWithOptions(d.Session, opts)
+		if err != nil {
+			d.Error = err.Error()
+			return
+		}
+		pane, err := getSessionPane(d.Session)
+		if err != nil {
+			d.Error = fmt.Sprintf("getting pane: %v", err)
+			return
+		}
+		if err := respawnSessionPane(t, d.Session, pane, restartCmd); err != nil {
+			d.Error = err.Error()
+			return
+		}
+		d.Resumed = opts.ResumeSessionID != "" && strings.Contains(restartCmd, opts.ResumeSessionID)
+	}
+}
+
+// latestTranscriptSessionID returns the runtime session ID of the most recent
+// Claude Code transcript for workDir (transcripts are named <session-id>.jsonl),
+// or "" if there is none.
+func latestTranscriptSessionID(workDir string) string {
+	projectDir, err := getClaudeProjectDir(workDir)
+	if err != nil {
+		return ""
+	}
+	path, err := findLatestTranscript(projectDir)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.Base(path), ".jsonl")
+}
+
+// readCostLogEntries reads all parseable entries from the costs log.
+func readCostLogEntries() []CostLogEntry {
+	data, err := os.ReadFile(getCostsLogPath())
+	if err != nil {
+		return nil
+	}
+	var entries []CostLogEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var entry CostLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// appliedCostThreshold returns the highest cost policy threshold already
+// applied to a session since it was created. Session names are reused across
+// agent lifetimes, so downgrades from before since belong to an earlier
+// session and are ignored.
+func appliedCostThreshold(entries []CostLogEntry, sessionName string, since time.Time) float64 {
+	var applied float64
+	for _, e := range entries {
+		if e.Event != CostEventDowngrade || e.SessionID != sessionName || e.EndedAt.Before(since) {
+			continue
+		}
+		if e.ThresholdUSD > applied {
+			applied = e.ThresholdUSD
+		}
+	}
+	return applied
+}
+
+func printCostDowngrades(downgrades []CostDowngrade) {
+	if len(downgrades) == 0 {
+		fmt.Printf("%s No sessions over a cost threshold\n", style.Success.Render("✓"))
+		return
+	}
+	verb := ""
+	if costsEnforceDryRun {
+		verb = "would "
+	}
+	for _, d := range downgrades {
+		target := d.Model
+		if d.Action == costpolicy.ActionRespawn {
+			target = d.Agent
+		}
+		if d.Error != "" {
+			fmt.Printf("%s %s ($%.2f ≥ $%.2f): %s to %s failed: %s\n", style.Error.Render("✗"),
+				d.Session, d.CostUSD, d.ThresholdUSD, d.Action, target, d.Error)
+			continue
+		}
+		note := ""
+		if d.Action == costpolicy.ActionRespawn && !costsEnforceDryRun && !d.Resumed {
+			note = style.Dim.Render(" (fresh session; resume unavailable)")
+		}
+		fmt.Printf("%s %s ($%.2f ≥ $%.2f): %s%s to %s%s\n", style.Warning.Render("⚠"),
+			d.Session, d.CostUSD, d.ThresholdUSD, verb, d.Action, target, note)
+	}
+}
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costpolicy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsForecastWhatIf string
+
+var costsForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Project this month's spend and what a cheaper model would save",
+	Long: `Project spend to the end of the month from the cost log (~/.gt/costs.jsonl).
+
+The burn rate is the average daily spend over the last 7 days; the trend
+compares it with the 7 days before. The projection is month-to-date spend
+plus the burn rate for each remaining day, in total and per rig and role,
+compared with the budget in the town's cost_policy:
+
+  "cost_policy": {
+    "budget": {
+      "monthly_usd": 500,
+      "rigs": {"gastown": 300},
+      "roles": {"polecat": 250}
+    }
+  }
+
+With --what-if, this month's sessions are repriced from their recorded
+token usage as if they had run on another model tier (haiku, sonnet, opus)
+or model ID, giving the savings to expect before switching. Sessions
+recorded before token usage was logged cannot be repriced; the coverage
+line shows how much of the spend the estimate rests on.
+
+Examples:
+  gt costs forecast
+  gt costs forecast --what-if haiku
+  gt costs forecast --what-if sonnet --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCostsForecast,
+}
+
+func init() {
+	costsForecastCmd.Flags().StringVar(&costsForecastWhatIf, "what-if", "", "Reprice this month's sessions as another model tier (haiku, sonnet, opus) or model ID")
+	costsForecastCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+	costsCmd.AddCommand(costsForecastCmd)
+}
+
+// modelTiers maps the model tiers used in molecule steps and cost policies
+// to the model whose pricing stands for them.
+var modelTiers = map[string]string{
+	"opus":   "claude-opus-4-5-20251101",
+	"sonnet": "claude-sonnet-4-20250514",
+	"haiku":  "claude-3-5-haiku-20241022",
+}
+
+// forecastWindow is how far back the burn rate looks.
+const forecastWindow = 7 * 24 * time.Hour
+
+// CostForecast is the output of gt costs forecast.
+type CostForecast struct {
+	Month       string         `json:"month"` // YYYY-MM
+	DaysElapsed float64        `json:"days_elapsed"`
+	DaysInMonth int            `json:"days_in_month"`
+	Total       ForecastLine   `json:"total"`
+	Rigs        []ForecastLine `json:"rigs,omitempty"`
+	Roles       []ForecastLine `json:"roles,omitempty"`
+	WhatIf      *CostWhatIf    `json:"what_if,omitempty"`
+}
+
+// ForecastLine is the projection for the town, one rig, or one role.
+type ForecastLine struct {
+	Name        string  `json:"name,omitempty"`
+	MonthToDate float64 `json:"month_to_date_usd"`
+	DailyRate   float64 `json:"daily_rate_usd"`
+	// TrendPct is the change in spend over the last 7 days against the 7
+	// before, or 0 when there is nothing to compare with.
+	TrendPct  float64 `json:"trend_pct"`
+	Projected float64 `json:"projected_usd"`
+	Budget    float64 `json:"budget_usd,omitempty"`
+}
+
+// OverBudget reports whether the projection exceeds a set budget.
+func (l ForecastLine) OverBudget() bool {
+	return l.Budget > 0 && l.Projected > l.Budget
+}
+
+// CostWhatIf is the month repriced for another model.
+type CostWhatIf struct {
+	Tier     string `json:"tier"`
+	Model    string `json:"model"`
+	Sessions int    `json:"sessions"` // Sessions with token usage to reprice
+
+	// ActualUSD is what the repriced sessions cost; RepricedUSD is what they
+	// would have cost on Model.
+	ActualUSD   float64 `json:"actual_usd"`
+	RepricedUSD float64 `json:"repriced_usd"`
+
+	// Coverage is the fraction of month-to-date spend that was repriced.
+	Coverage float64 `json:"coverage"`
+
+	// Projected applies the repriced/actual ratio to the month's projection.
+	Projected  float64 `json:"projected_usd"`
+	SavingsUSD float64 `json:"savings_usd"`
+}
+
+func runCostsForecast(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	policy, err := costpolicy.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := costpolicy.Validate(policy); err != nil {
+		return err
+	}
+	var budget *config.CostBudget
+	if policy != nil {
+		budget = policy.Budget
+	}
+
+	whatIfModel := ""
+	if costsForecastWhatIf != "" {
+		whatIfModel, err = resolveModelTier(costsForecastWhatIf)
+		if err != nil {
+			return err
+		}
+	}
+
+	f := buildCostForecast(readCostLogEntries(), time.Now(), budget, costsForecastWhatIf, whatIfModel)
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(f)
+	}
+	printCostForecast(f)
+	return nil
+}
+
+// resolveModelTier returns the priced model for a tier name or model ID.
+func resolveModelTier(tier string) (string, error) {
+	if model, ok := modelTiers[strings.ToLower(tier)]; ok {
+		return model, nil
+	}
+	if _, ok := modelPricing[tier]; ok && tier != "default" {
+		return tier, nil
+	}
+	var known []string
+	for name := range modelTiers {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+	return "", fmt.Errorf("unknown model tier %q (want %s, or a model ID)", tier, strings.Join(known, ", "))
+}
+
+// buildCostForecast projects the month containing now from the cost log.
+// Non-cost entries (downgrade events) are ignored.
+func buildCostForecast(entries []CostLogEntry, now time.Time, budget *config.CostBudget, tier, tierModel string) *CostForecast {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	f := &CostForecast{
+		Month:       monthStart.Format("2006-01"),
+		DaysElapsed: now.Sub(monthStart).Hours() / 24,
+		DaysInMonth: int(monthEnd.Sub(monthStart).Hours()/24 + 0.5),
+	}
+	daysLeft := monthEnd.Sub(now).Hours() / 24
+
+	var costs []CostLogEntry
+	earliest := now
+	for _, e := range entries {
+		if e.Event != "" || e.EndedAt.After(now) {
+			continue
+		}
+		costs = append(costs, e)
+		if e.EndedAt.Before(earliest) {
+			earliest = e.EndedAt
+		}
+	}
+
+	// A log younger than the window would understate the rate.
+	windowDays := forecastWindow.Hours() / 24
+	if age := now.Sub(earliest); age < forecastWindow {
+		windowDays = max(age.Hours()/24, 1)
+	}
+
+	project := func(name string, match func(CostLogEntry) bool, budgetUSD float64) ForecastLine {
+		l := ForecastLine{Name: name, Budget: budgetUSD}
+		var recent, prior float64
+		for _, e := range costs {
+			if !match(e) {
+				continue
+			}
+			if !e.EndedAt.Before(monthStart) {
+				l.MonthToDate += e.CostUSD
+			}
+			switch age := now.Sub(e.EndedAt); {
+			case age < forecastWindow:
+				recent += e.CostUSD
+			case age < 2*forecastWindow:
+				prior += e.CostUSD
+			}
+		}
+		l.DailyRate = recent / windowDays
+		if prior > 0 {
+			l.TrendPct = (recent - prior) / prior * 100
+		}
+		l.Projected = l.MonthToDate + l.DailyRate*daysLeft
+		return l
+	}
+
+	if budget == nil {
+		budget = &config.CostBudget{}
+	}
+	f.Total = project("", func(CostLogEntry) bool { return true }, budget.MonthlyUSD)
+
+	rigs := map[string]bool{}
+	roles := map[string]bool{}
+	for _, e := range costs {
+		if e.Rig != "" {
+			rigs[e.Rig] = true
+		}
+		if e.Role != "" {
+			roles[e.Role] = true
+		}
+	}
+	for _, rig := range sortedKeys(rigs) {
+		f.Rigs = append(f.Rigs, project(rig, func(e CostLogEntry) bool { return e.Rig == rig }, budget.Rigs[rig]))
+	}
+	for _, role := range sortedKeys(roles) {
+		f.Roles = append(f.Roles, project(role, func(e CostLogEntry) bool { return e.Role == role }, budget.Roles[role]))
+	}
+
+	if tierModel != "" {
+		f.WhatIf = repriceMonth(costs, monthStart, f.Total, tier, tierModel)
+	}
+	return f
+}
+
+// repriceMonth prices the month's sessions that recorded token usage as if
+// they had run on model, and scales the projection by the result.
+func repriceMonth(costs []CostLogEntry, monthStart time.Time, total ForecastLine, tier, model string) *CostWhatIf {
+	w := &CostWhatIf{Tier: tier, Model: model}
+	for _, e := range costs {
+		if e.EndedAt.Before(monthStart) || e.CostUSD <= 0 {
+			continue
+		}
+		usage := e.Usage()
+		if usage.InputTokens+usage.OutputTokens+usage.CacheReadInputTokens+usage.CacheCreationInputTokens == 0 {
+			continue
+		}
+		usage.Model = model
+		w.Sessions++
+		w.ActualUSD += e.CostUSD
+		w.RepricedUSD += calculateCost(&usage)
+	}
+	if w.ActualUSD == 0 {
+		return w
+	}
+	if total.MonthToDate > 0 {
+		w.Coverage = w.ActualUSD / total.MonthToDate
+	}
+	w.Projected = total.Projected * w.RepricedUSD / w.ActualUSD
+	w.SavingsUSD = total.Projected - w.Projected
+	return w
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printCostForecast(f *CostForecast) {
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Cost forecast for %s (day %.0f of %d)",
+		f.Month, f.DaysElapsed+0.5, f.DaysInMonth)))
+
+	t := f.Total
+	fmt.Printf("  Month to date:  $%.2f\n", t.MonthToDate)
+	fmt.Printf("  Burn rate:      $%.2f/day%s\n", t.DailyRate, formatTrend(t.TrendPct))
+	fmt.Printf("  Projected:      $%.2f\n", t.Projected)
+	if t.Budget > 0 {
+		fmt.Printf("  Budget:         $%.2f  %s\n", t.Budget, formatBudgetStatus(t))
+	}
+
+	printForecastLines("By rig", f.Rigs)
+	printForecastLines("By role", f.Roles)
+
+	if w := f.WhatIf; w != nil {
+		fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("What if sessions ran on %s (%s)", w.Tier, w.Model)))
+		if w.Sessions == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("No sessions this month recorded token usage; nothing to reprice"))
+			return
+		}
+		fmt.Printf("  Repriced:       %d sessions, $%.2f → $%.2f\n", w.Sessions, w.ActualUSD, w.RepricedUSD)
+		fmt.Printf("  Coverage:       %.0f%% of month-to-date spend\n", w.Coverage*100)
+		fmt.Printf("  Projected:      $%.2f\n", w.Projected)
+		if w.SavingsUSD >= 0 {
+			fmt.Printf("  Savings:        %s\n", style.Success.Render(fmt.Sprintf("~$%.2f this month", w.SavingsUSD)))
+		} else {
+			fmt.Printf("  Extra cost:     %s\n", style.Warning.Render(fmt.Sprintf("~$%.2f this month", -w.SavingsUSD)))
+		}
+	}
+}
+
+func printForecastLines(title string, lines []ForecastLine) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render(title))
+	width := 0
+	for _, l := range lines {
+		width = max(width, len(l.Name))
+	}
+	for _, l := range lines {
+		line := fmt.Sprintf("  %-*s  MTD $%8.2f  projected $%8.2f", width, l.Name, l.MonthToDate, l.Projected)
+		if l.Budget > 0 {
+			line += fmt.Sprintf("  budget $%.2f  %s", l.Budget, formatBudgetStatus(l))
+		}
+		fmt.Println(line)
+	}
+}
+
+func formatTrend(pct float64) string {
+	switch {
+	case pct > 0:
+		return style.Dim.Render(fmt.Sprintf(" (↑ %.0f%% vs prior week)", pct))
+	case pct < 0:
+		return style.Dim.Render(fmt.Sprintf(" (↓ %.0f%% vs prior week)", -pct))
+	}
+	return ""
+}
+
+func formatBudgetStatus(l ForecastLine) string {
+	if l.OverBudget() {
+		return style.Warning.Render(fmt.Sprintf("⚠ over by $%.2f", l.Projected-l.Budget))
+	}
+	return style.Success.Render(fmt.Sprintf("✓ on track (%.0f%%)", l.Projected/l.Budget*100))
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	costsRecomputePricing string
+	costsRecomputeSince   string
+)
+
+var costsRecomputeCmd = &cobra.Command{
+	Use:   "recompute",
+	Short: "Reprice logged sessions under another pricing table",
+	Long: `Reprice the sessions in the cost log (~/.gt/costs.jsonl) from their
+recorded token usage under another pricing table, and report the
+difference per model and per rig. The log is not changed.
+
+--pricing takes a built-in pricing version (see below) or a JSON file
+mapping model IDs to prices per million tokens, with a "default" entry
+for models it doesn't list:
+
+  {
+    "claude-sonnet-4-20250514": {"input": 3, "output": 15, "cache_read": 0.3, "cache_create": 3.75},
+    "default": {"input": 2, "output": 10, "cache_read": 0.2, "cache_create": 2.5}
+  }
+
+Use it to compare costs before and after a price change, or under a
+hypothetical provider. Sessions recorded before token usage was logged
+cannot be repriced and are counted as skipped.
+
+Built-in pricing versions: ` + strings.Join(pricingVersionNames(), ", ") + `
+
+Examples:
+  gt costs recompute --pricing 2025-01
+  gt costs recompute --pricing ./provider-b.json --since 2026-03-01
+  gt costs recompute --pricing ./provider-b.json --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCostsRecompute,
+}
+
+func init() {
+	costsRecomputeCmd.Flags().StringVar(&costsRecomputePricing, "pricing", "", "Pricing version or JSON pricing file to reprice under (required)")
+	costsRecomputeCmd.Flags().StringVar(&costsRecomputeSince, "since", "", "Only reprice sessions ended on or after this date (YYYY-MM-DD)")
+	costsRecomputeCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+	_ = costsRecomputeCmd.MarkFlagRequired("pricing")
+	costsCmd.AddCommand(costsRecomputeCmd)
+}
+
+// pricingVersions are the pricing tables gastown has shipped, by version.
+// Add the outgoing table here under its version when prices change.
+var pricingVersions = map[string]PricingTable{
+	currentPricingVersion: modelPricing,
+}
+
+func pricingVersionNames() []string {
+	names := make([]string, 0, len(pricingVersions))
+	for name := range pricingVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadPricingTable resolves --pricing: a built-in version, or a JSON
+// pricing file.
+func loadPricingTable(spec string) (PricingTable, error) {
+	if table, ok := pricingVersions[spec]; ok {
+		return table, nil
+	}
+	data, err := os.ReadFile(spec) //nolint:gosec // G304: path is user-provided by design
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unknown pricing %q: not a pricing version (%s) or a file",
+				spec, strings.Join(pricingVersionNames(), ", "))
+		}
+		return nil, err
+	}
+	var table PricingTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("parsing pricing file %s: %w", spec, err)
+	}
+	if _, ok := table["default"]; !ok {
+		return nil, fmt.Errorf("pricing file %s has no \"default\" entry", spec)
+	}
+	return table, nil
+}
+
+// CostRecompute is the output of gt costs recompute.
+type CostRecompute struct {
+	Pricing  string `json:"pricing"`
+	Repriced int    `json:"repriced"` // sessions with token usage
+	Skipped  int    `json:"skipped"`  // sessions without token usage
+
+	// OriginalVersions counts the repriced sessions by the pricing version
+	// they were recorded under ("unversioned" for older records).
+	OriginalVersions map[string]int `json:"original_versions"`
+
+	Total   RecomputeLine   `json:"total"`
+	ByModel []RecomputeLine `json:"by_model,omitempty"`
+	ByRig   []RecomputeLine `json:"by_rig,omitempty"`
+}
+
+// RecomputeLine compares recorded and repriced cost for a group of sessions.
+type RecomputeLine struct {
+	Name        string  `json:"name,omitempty"`
+	Sessions    int     `json:"sessions"`
+	OriginalUSD float64 `json:"original_usd"`
+	RepricedUSD float64 `json:"repriced_usd"`
+}
+
+// DeltaUSD is the repriced cost minus the recorded cost.
+func (l RecomputeLine) DeltaUSD() float64 {
+	return l.RepricedUSD - l.OriginalUSD
+}
+
+// DeltaPct is the change as a percentage of the recorded cost.
+func (l RecomputeLine) DeltaPct() float64 {
+	if l.OriginalUSD == 0 {
+		return 0
+	}
+	return l.DeltaUSD() / l.OriginalUSD * 100
+}
+
+func (l *RecomputeLine) add(original, repriced float64) {
+	l.Sessions++
+	l.OriginalUSD += original
+	l.RepricedUSD += repriced
+}
+
+func runCostsRecompute(cmd *cobra.Command, args []string) error {
+	table, err := loadPricingTable(costsRecomputePricing)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if costsRecomputeSince != "" {
+		since, err = time.ParseInLocation("2006-01-02", costsRecomputeSince, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --since date %q (want YYYY-MM-DD)", costsRecomputeSince)
+		}
+	}
+
+	r := buildCostRecompute(readCostLogEntries(), table, costsRecomputePricing, since)
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	printCostRecompute(r)
+	return nil
+}
+
+// buildCostRecompute reprices the session cost entries ended at or after
+// since under table. Non-cost entries (downgrade events) are ignored.
+func buildCostRecompute(entries []CostLogEntry, table PricingTable, pricing string, since time.Time) *CostRecompute {
+	r := &CostRecompute{Pricing: pricing, OriginalVersions: map[string]int{}}
+	byModel := map[string]*RecomputeLine{}
+	byRig := map[string]*RecomputeLine{}
+	group := func(m map[string]*RecomputeLine, name string) *RecomputeLine {
+		if m[name] == nil {
+			m[name] = &RecomputeLine{Name: name}
+		}
+		return m[name]
+	}
+
+	for _, e := range entries {
+		if e.Event != "" || e.EndedAt.Before(since) {
+			continue
+		}
+		usage := e.Usage()
+		if usage.InputTokens+usage.OutputTokens+usage.CacheReadInputTokens+usage.CacheCreationInputTokens == 0 {
+			r.Skipped++
+			continue
+		}
+		repriced := table.Cost(&usage)
+		r.Repriced++
+		version := e.PricingVersion
+		if version == "" {
+			version = "unversioned"
+		}
+		r.OriginalVersions[version]++
+		r.Total.add(e.CostUSD, repriced)
+
+		model := e.Model
+		if model == "" {
+			model = "unknown"
+		}
+		group(byModel, model).add(e.CostUSD, repriced)
+		rig := e.Rig
+		if rig == "" {
+			rig = "town"
+		}
+		group(byRig, rig).add(e.CostUSD, repriced)
+	}
+
+	sortedLines := func(m map[string]*RecomputeLine) []RecomputeLine {
+		lines := make([]RecomputeLine, 0, len(m))
+		for _, l := range m {
+			lines = append(lines, *l)
+		}
+		sort.Slice(lines, func(i, j int) bool { return lines[i].Name < lines[j].Name })
+		return lines
+	}
+	r.ByModel = sortedLines(byModel)
+	r.ByRig = sortedLines(byRig)
+	return r
+}
+
+func printCostRecompute(r *CostRecompute) {
+	fmt.Printf("%s\n\n", style.Bold.Render("Costs repriced under "+r.Pricing))
+	if r.Repriced == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No logged sessions recorded token usage; nothing to reprice"))
+		return
+	}
+
+	t := r.Total
+	fmt.Printf("  Sessions:   %d repriced", r.Repriced)
+	if r.Skipped > 0 {
+		fmt.Printf(", %d skipped (no token usage)", r.Skipped)
+	}
+	fmt.Println()
+	var versions []string
+	for v, n := range r.OriginalVersions {
+		versions = append(versions, fmt.Sprintf("%s (%d)", v, n))
+	}
+	sort.Strings(versions)
+	fmt.Printf("  Recorded:   $%.2f under %s\n", t.OriginalUSD, strings.Join(versions, ", "))
+	fmt.Printf("  Repriced:   $%.2f\n", t.RepricedUSD)
+	fmt.Printf("  Delta:      %s\n", formatRecomputeDelta(t))
+
+	printRecomputeLines("By model", r.ByModel)
+	printRecomputeLines("By rig", r.ByRig)
+}
+
+func printRecomputeLines(title string, lines []RecomputeLine) {
+	fmt.Printf("\n%s\n", style.Bold.Render(title))
+	width := 0
+	for _, l := range lines {
+		width = max(width, len(l.Name))
+	}
+	for _, l := range lines {
+		fmt.Printf("  %-*s  %4d sessions  $%8.2f → $%8.2f  %s\n",
+			width, l.Name, l.Sessions, l.OriginalUSD, l.RepricedUSD, formatRecomputeDelta(l))
+	}
+}
+
+func formatRecomputeDelta(l RecomputeLine) string {
+	d := l.DeltaUSD()
+	switch {
+	case d > 0:
+		return style.Warning.Render(fmt.Sprintf("+$%.2f (%+.1f%%)", d, l.DeltaPct()))
+	case d < 0:
+		return style.Success.Render(fmt.Sprintf("-$%.2f (%+.1f%%)", -d, l.DeltaPct()))
+	}
+	return style.Dim.Render("no change")
+}
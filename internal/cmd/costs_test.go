@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"encoding/json"
+	"math"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -218,3 +221,140 @@ func TestCostDigestPayload_ExcludesSessions(t *testing.T) {
 		t.Errorf("by_role should have 3 entries, got %d", len(asDigest.ByRole))
 	}
 }
+
+func TestAppliedCostThreshold(t *testing.T) {
+	created := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	entries := []CostLogEntry{
+		// Cost record, not a downgrade
+		{SessionID: "gt-toast", CostUSD: 50, EndedAt: created.Add(time.Hour)},
+		// Downgrade from an earlier session with the same name
+		{SessionID: "gt-toast", Event: CostEventDowngrade, ThresholdUSD: 25, EndedAt: created.Add(-time.Hour)},
+		{SessionID: "gt-toast", Event: CostEventDowngrade, ThresholdUSD: 10, EndedAt: created.Add(time.Minute)},
+		{SessionID: "gt-nux", Event: CostEventDowngrade, ThresholdUSD: 40, EndedAt: created.Add(time.Minute)},
+	}
+
+	if got := appliedCostThreshold(entries, "gt-toast", created); got != 10 {
+		t.Errorf("appliedCostThreshold(gt-toast) = %v, want 10", got)
+	}
+	if got := appliedCostThreshold(entries, "gt-toast", time.Time{}); got != 25 {
+		t.Errorf("appliedCostThreshold(gt-toast, zero since) = %v, want 25", got)
+	}
+	if got := appliedCostThreshold(entries, "gt-furiosa", created); got != 0 {
+		t.Errorf("appliedCostThreshold(gt-furiosa) = %v, want 0", got)
+	}
+}
+
+func TestBuildCostForecast(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	entries := []CostLogEntry{
+		{Rig: "gastown", Role: "polecat", CostUSD: 3, EndedAt: day(14),
+			Model: modelTiers["sonnet"], InputTokens: 1_000_000},
+		{Rig: "beads", Role: "witness", CostUSD: 11, EndedAt: day(10)},
+		{Rig: "gastown", Role: "polecat", CostUSD: 7, EndedAt: day(3)},
+		{Rig: "gastown", Role: "polecat", CostUSD: 100, EndedAt: time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)},
+		{Rig: "gastown", Role: "polecat", CostUSD: 50, EndedAt: day(14), Event: CostEventDowngrade},
+	}
+	budget := &config.CostBudget{MonthlyUSD: 50, Rigs: map[string]float64{"beads": 100}}
+
+	f := buildCostForecast(entries, now, budget, "haiku", modelTiers["haiku"])
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if f.Month != "2026-03" || f.DaysInMonth != 31 {
+		t.Errorf("month = %s, %d days", f.Month, f.DaysInMonth)
+	}
+	tot := f.Total
+	if !near(tot.MonthToDate, 21) || !near(tot.DailyRate, 2) || !near(tot.TrendPct, 100) || !near(tot.Projected, 54) {
+		t.Errorf("total = %+v", tot)
+	}
+	if !tot.OverBudget() {
+		t.Error("total should be over its $50 budget")
+	}
+	if len(f.Rigs) != 2 || f.Rigs[0].Name != "beads" || f.Rigs[0].OverBudget() || f.Rigs[1].Budget != 0 {
+		t.Errorf("rigs = %+v", f.Rigs)
+	}
+
+	w := f.WhatIf
+	if w == nil || w.Sessions != 1 || !near(w.ActualUSD, 3) || !near(w.RepricedUSD, 1) {
+		t.Fatalf("what-if = %+v", w)
+	}
+	if !near(w.Coverage, 3.0/21) || !near(w.Projected, 18) || !near(w.SavingsUSD, 36) {
+		t.Errorf("what-if = %+v", w)
+	}
+}
+
+func TestResolveModelTier(t *testing.T) {
+	if got, err := resolveModelTier("Haiku"); err != nil || got != modelTiers["haiku"] {
+		t.Errorf("resolveModelTier(Haiku) = %q, %v", got, err)
+	}
+	if got, err := resolveModelTier("claude-sonnet-4-20250514"); err != nil || got != "claude-sonnet-4-20250514" {
+		t.Errorf("resolveModelTier(model ID) = %q, %v", got, err)
+	}
+	for _, bad := range []string{"economy", "default"} {
+		if _, err := resolveModelTier(bad); err == nil {
+			t.Errorf("resolveModelTier(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBuildCostRecompute(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	entries := []CostLogEntry{
+		{Rig: "gastown", CostUSD: 3, EndedAt: day(14), PricingVersion: currentPricingVersion,
+			Model: modelTiers["sonnet"], InputTokens: 1_000_000},
+		{Rig: "beads", CostUSD: 15, EndedAt: day(12),
+			Model: modelTiers["opus"], InputTokens: 1_000_000},
+		{Rig: "beads", CostUSD: 11, EndedAt: day(10)},
+		{Rig: "gastown", CostUSD: 9, EndedAt: day(1), Model: modelTiers["sonnet"], InputTokens: 3_000_000},
+		{Rig: "gastown", CostUSD: 50, EndedAt: day(14), Event: CostEventDowngrade},
+	}
+	table := PricingTable{
+		modelTiers["sonnet"]: {InputPerMillion: 6},
+		"default":            {InputPerMillion: 1},
+	}
+
+	r := buildCostRecompute(entries, table, "test", day(5))
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if r.Repriced != 2 || r.Skipped != 1 {
+		t.Fatalf("repriced %d, skipped %d; want 2, 1", r.Repriced, r.Skipped)
+	}
+	if r.OriginalVersions[currentPricingVersion] != 1 || r.OriginalVersions["unversioned"] != 1 {
+		t.Errorf("original versions = %v", r.OriginalVersions)
+	}
+	if !near(r.Total.OriginalUSD, 18) || !near(r.Total.RepricedUSD, 7) || !near(r.Total.DeltaUSD(), -11) {
+		t.Errorf("total = %+v", r.Total)
+	}
+	if len(r.ByModel) != 2 || r.ByModel[1].Name != modelTiers["sonnet"] || !near(r.ByModel[1].DeltaPct(), 100) {
+		t.Errorf("by model = %+v", r.ByModel)
+	}
+	if len(r.ByRig) != 2 || r.ByRig[0].Name != "beads" || !near(r.ByRig[0].RepricedUSD, 1) {
+		t.Errorf("by rig = %+v", r.ByRig)
+	}
+}
+
+func TestLoadPricingTable(t *testing.T) {
+	if table, err := loadPricingTable(currentPricingVersion); err != nil || len(table) != len(modelPricing) {
+		t.Errorf("loadPricingTable(%s) = %v, %v", currentPricingVersion, table, err)
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"default": {"input": 2, "output": 10}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := loadPricingTable(good)
+	if err != nil || table["default"].OutputPerMillion != 10 {
+		t.Errorf("loadPricingTable(file) = %v, %v", table, err)
+	}
+
+	noDefault := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(noDefault, []byte(`{"claude-x": {"input": 2}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{noDefault, "1999-01"} {
+		if _, err := loadPricingTable(bad); err == nil {
+			t.Errorf("loadPricingTable(%s) should fail", bad)
+		}
+	}
+}
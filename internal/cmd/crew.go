@@ -237,7 +237,8 @@ var crewPristineCmd = &cobra.Command{
 	Short: "Sync crew workspaces with remote",
 	Long: `Ensure crew workspace(s) are up-to-date.
 
-Runs git pull for the specified crew, or all crew workers.
+Runs git pull for the specified crew, or all crew workers, and rewrites
+each workspace's .runtime/env.sh (sourced by its direnv .envrc).
 Reports any uncommitted changes that may need attention.
 
 Examples:
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -129,7 +130,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			deleteArgs := []string{"delete", agentBeadID, "--force"}
 			deleteCmd := exec.Command("bd", deleteArgs...)
 			deleteCmd.Dir = r.Path
-			if output, err := deleteCmd.CombinedOutput(); err != nil {
+			if output, err := proc.CombinedOutput(deleteCmd); err != nil {
 				// Non-fatal: bead might not exist
 				if !strings.Contains(string(output), "no issue found") &&
 					!strings.Contains(string(output), "not found") {
@@ -144,7 +145,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			unassignArgs := []string{"list", "--assignee=" + agentAddr, "--format=id"}
 			unassignCmd := exec.Command("bd", unassignArgs...)
 			unassignCmd.Dir = r.Path
-			if output, err := unassignCmd.CombinedOutput(); err == nil {
+			if output, err := proc.CombinedOutput(unassignCmd); err == nil {
 				ids := strings.Fields(strings.TrimSpace(string(output)))
 				for _, id := range ids {
 					if id == "" {
@@ -152,7 +153,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 					}
 					updateCmd := exec.Command("bd", "update", id, "--unassign")
 					updateCmd.Dir = r.Path
-					if _, err := updateCmd.CombinedOutput(); err == nil {
+					if _, err := proc.CombinedOutput(updateCmd); err == nil {
 						fmt.Printf("Unassigned: %s\n", id)
 					}
 				}
@@ -172,7 +173,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			}
 			closeCmd := exec.Command("bd", closeArgs...)
 			closeCmd.Dir = r.Path
-			if output, err := closeCmd.CombinedOutput(); err != nil {
+			if output, err := proc.CombinedOutput(closeCmd); err != nil {
 				// Non-fatal: bead might not exist or already be closed
 				if !strings.Contains(string(output), "no issue found") &&
 					!strings.Contains(string(output), "already closed") {
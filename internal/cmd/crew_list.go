@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -21,6 +24,9 @@ type CrewListItem struct {
 	Path       string `json:"path"`
 	HasSession bool   `json:"has_session"`
 	GitClean   bool   `json:"git_clean"`
+
+	CreatedAt      time.Time  `json:"created_at"`
+	SessionStarted *time.Time `json:"session_started,omitempty"`
 }
 
 func runCrewList(cmd *cobra.Command, args []string) error {
@@ -67,14 +73,21 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 				gitClean = status.Clean
 			}
 
-			items = append(items, CrewListItem{
+			item := CrewListItem{
 				Name:       w.Name,
 				Rig:        r.Name,
 				Branch:     w.Branch,
 				Path:       w.ClonePath,
 				HasSession: hasSession,
 				GitClean:   gitClean,
-			})
+				CreatedAt:  w.CreatedAt,
+			}
+			if hasSession {
+				if started, err := session.SessionCreatedAt(sessionID); err == nil {
+					item.SessionStarted = &started
+				}
+			}
+			items = append(items, item)
 		}
 	}
 
@@ -104,6 +117,11 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("  %s %s/%s\n", status, item.Rig, item.Name)
 		fmt.Printf("    Branch: %s  Git: %s\n", item.Branch, gitStatus)
+		if item.SessionStarted != nil {
+			fmt.Printf("    Session: up %s  Created: %s\n", timefmt.Duration(time.Since(*item.SessionStarted)), timefmt.Ago(item.CreatedAt))
+		} else if !item.CreatedAt.IsZero() {
+			fmt.Printf("    Created: %s\n", timefmt.Ago(item.CreatedAt))
+		}
 		fmt.Printf("    %s\n", style.Dim.Render(item.Path))
 	}
 
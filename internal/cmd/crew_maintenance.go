@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -126,6 +127,12 @@ func runCrewPristine(cmd *cobra.Command, args []string) error {
 		} else if result.PullError != "" {
 			fmt.Printf("  %s git pull: %s\n", style.Bold.Render("✗"), result.PullError)
 		}
+
+		if result.EnvError != "" {
+			fmt.Printf("  %s workspace env: %s\n", style.Bold.Render("✗"), result.EnvError)
+		} else {
+			fmt.Printf("  %s %s\n", style.Dim.Render("✓"), rig.WorkspaceEnvFile)
+		}
 	}
 
 	return nil
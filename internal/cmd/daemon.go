@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -59,6 +62,8 @@ var daemonStatusCmd = &cobra.Command{
 
 Displays whether the daemon is running, its PID, uptime, heartbeat
 count, and whether the binary has been rebuilt since the daemon started.
+When the daemon's control API is enabled, the status (and the enabled
+patrols) comes from the daemon itself.
 
 Examples:
   gt daemon status`,
@@ -87,11 +92,43 @@ var daemonRunCmd = &cobra.Command{
 
 This is called internally by the daemon start process and supervisor
 services (launchd/systemd). Use 'gt daemon start' to start the daemon
-normally in the background.`,
+normally in the background.
+
+Only one daemon leads a town at a time. It holds a lease in
+daemon/leader.json that it renews every 30s. A second daemon exits naming
+the leader, or with --standby waits and takes over once the leader's
+lease lapses.`,
 	Hidden: true,
 	RunE:   runDaemonRun,
 }
 
+var daemonEnsureCmd = &cobra.Command{
+	Use:   "ensure",
+	Short: "Restart the daemon if it is down or hung (watchdog)",
+	Long: `Make sure the daemon is running and healthy, restarting it if not.
+
+Meant to be run from cron or a systemd timer, so that something supervises
+the daemon that supervises everything else. The daemon is healthy when its
+process is alive and it renewed its lease (daemon/leader.json) recently.
+A daemon that is running but has stopped renewing is hung: it is stopped
+and started again.
+
+Every restart is recorded in daemon/watchdog.json. If the daemon has been
+restarted more than 3 times in the last hour, ensure warns that it is
+flapping (gt doctor reports the same).
+
+Exits non-zero only if the daemon could not be started.
+
+Examples:
+  gt daemon ensure            # Check, restart if needed
+  gt daemon ensure --quiet    # Only print when something was done
+
+  # crontab: check every 2 minutes
+  */2 * * * * cd ~/gt && gt daemon ensure --quiet`,
+	SilenceUsage: true,
+	RunE:         runDaemonEnsure,
+}
+
 var daemonEnableSupervisorCmd = &cobra.Command{
 	Use:   "enable-supervisor",
 	Short: "Configure launchd/systemd for daemon auto-restart",
@@ -109,6 +146,10 @@ Examples:
 var (
 	daemonLogLines int
 	daemonLogFollow bool
+	daemonRunStandby bool
+
+	daemonEnsureQuiet           bool
+	daemonEnsureMaxHeartbeatAge time.Duration
 )
 
 func init() {
@@ -118,9 +159,14 @@ func init() {
 	daemonCmd.AddCommand(daemonLogsCmd)
 	daemonCmd.AddCommand(daemonRunCmd)
 	daemonCmd.AddCommand(daemonEnableSupervisorCmd)
+	daemonCmd.AddCommand(daemonEnsureCmd)
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonRunCmd.Flags().BoolVar(&daemonRunStandby, "standby", false, "Wait for leadership instead of exiting when another daemon leads")
+	daemonEnsureCmd.Flags().BoolVarP(&daemonEnsureQuiet, "quiet", "q", false, "Print nothing when the daemon is healthy")
+	daemonEnsureCmd.Flags().DurationVar(&daemonEnsureMaxHeartbeatAge, "max-heartbeat-age", 10*time.Minute,
+		"Heartbeat age that counts as hung, for daemons without a lease")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -140,11 +186,29 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("daemon already running (PID %d)", pid)
 	}
 
+	pid, raced, err := startDaemonProcess(townRoot)
+	if err != nil {
+		return err
+	}
+	if raced {
+		// Another daemon won the race - that's fine, report it
+		fmt.Printf("%s Daemon already running (PID %d)\n", style.Bold.Render("●"), pid)
+		return nil
+	}
+
+	fmt.Printf("%s Daemon started (PID %d)\n", style.Bold.Render("✓"), pid)
+	return nil
+}
+
+// startDaemonProcess launches 'gt daemon run' in the background and waits
+// for it to take the PID file. raced is true when a concurrently started
+// daemon won instead; pid is then the winner's.
+func startDaemonProcess(townRoot string) (pid int, raced bool, err error) {
 	// Start daemon in background
 	// We use 'gt daemon run' as the actual daemon process
 	gtPath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("finding executable: %w", err)
+		return 0, false, fmt.Errorf("finding executable: %w", err)
 	}
 
 	daemonCmd := exec.Command(gtPath, "daemon", "run")
@@ -156,34 +220,92 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 	daemonCmd.Stderr = nil
 
 	if err := daemonCmd.Start(); err != nil {
-		return fmt.Errorf("starting daemon: %w", err)
+		return 0, false, fmt.Errorf("starting daemon: %w", err)
 	}
 
 	// Wait a moment for the daemon to initialize and acquire the lock
 	time.Sleep(200 * time.Millisecond)
 
 	// Verify it started
-	running, pid, err = daemon.IsRunning(townRoot)
+	running, pid, err := daemon.IsRunning(townRoot)
 	if err != nil {
-		return fmt.Errorf("checking daemon status: %w", err)
+		return 0, false, fmt.Errorf("checking daemon status: %w", err)
 	}
 	if !running {
-		return fmt.Errorf("daemon failed to start (check logs with 'gt daemon logs')")
+		return 0, false, fmt.Errorf("daemon failed to start (check logs with 'gt daemon logs')")
 	}
 
 	// Check if our spawned process is the one that won the race.
 	// If another concurrent start won, our process would have exited after
 	// failing to acquire the lock, and the PID file would have a different PID.
-	if pid != daemonCmd.Process.Pid {
-		// Another daemon won the race - that's fine, report it
-		fmt.Printf("%s Daemon already running (PID %d)\n", style.Bold.Render("●"), pid)
+	return pid, pid != daemonCmd.Process.Pid, nil
+}
+
+func runDaemonEnsure(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	st, err := daemon.CheckWatchdog(townRoot, daemonEnsureMaxHeartbeatAge)
+	if err != nil {
+		return fmt.Errorf("checking daemon health: %w", err)
+	}
+
+	var reason string
+	switch st.Verdict {
+	case daemon.WatchdogHealthy:
+		if !daemonEnsureQuiet {
+			fmt.Printf("%s Daemon healthy (PID %d, last renewed %s ago)\n",
+				style.Success.Render("✓"), st.PID, st.Age.Round(time.Second))
+		}
+		warnIfDaemonFlapping(townRoot)
 		return nil
+	case daemon.WatchdogRemoteOwner:
+		if !daemonEnsureQuiet {
+			fmt.Printf("%s Daemon runs on %s (PID %d, last renewed %s ago)\n",
+				style.Dim.Render("○"), st.Lease.Host, st.Lease.PID, st.Age.Round(time.Second))
+		}
+		return nil
+	case daemon.WatchdogStale:
+		reason = fmt.Sprintf("hung: not renewed for %s", st.Age.Round(time.Second))
+		fmt.Printf("%s Daemon PID %d is %s, restarting\n", style.Warning.Render("⚠"), st.PID, reason)
+		if err := daemon.StopDaemon(townRoot); err != nil {
+			style.PrintWarning("stopping hung daemon: %v", err)
+		}
+	default:
+		reason = "not running"
+		fmt.Printf("%s Daemon is not running, starting\n", style.Warning.Render("⚠"))
 	}
 
-	fmt.Printf("%s Daemon started (PID %d)\n", style.Bold.Render("✓"), pid)
+	pid, _, err := startDaemonProcess(townRoot)
+	restart := daemon.WatchdogRestart{Reason: reason, OldPID: st.PID, NewPID: pid}
+	if err != nil {
+		restart.Reason += " (start failed)"
+	}
+	if recErr := daemon.RecordWatchdogRestart(townRoot, restart); recErr != nil {
+		style.PrintWarning("recording restart: %v", recErr)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Daemon started (PID %d)\n", style.Success.Render("✓"), pid)
+	warnIfDaemonFlapping(townRoot)
 	return nil
 }
 
+// warnIfDaemonFlapping prints a warning when the watchdog has been
+// restarting the daemon repeatedly.
+func warnIfDaemonFlapping(townRoot string) {
+	h, err := daemon.LoadWatchdogHistory(townRoot)
+	if err != nil || !h.Flapping(time.Now()) {
+		return
+	}
+	recent := h.RestartsSince(time.Now().Add(-daemon.FlapWindow))
+	style.PrintWarning("daemon is flapping: restarted %d times in the last hour (last: %s); check 'gt daemon logs'",
+		len(recent), recent[len(recent)-1].Reason)
+}
+
 func runDaemonStop(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -223,8 +345,26 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 			style.Bold.Render("running"),
 			pid)
 
-		// Load state for more details
+		// Ask the daemon itself when its control API is up; otherwise read
+		// the state file it saves after each heartbeat.
+		var patrols map[string]bool
 		state, err := daemon.LoadState(townRoot)
+		if client, clientErr := daemon.NewControlClient(townRoot); clientErr == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			status, statusErr := client.Status(ctx)
+			cancel()
+			if statusErr == nil {
+				state = &daemon.State{
+					Running:        true,
+					PID:            status.PID,
+					StartedAt:      status.StartedAt,
+					LastHeartbeat:  status.LastHeartbeat,
+					HeartbeatCount: status.HeartbeatCount,
+				}
+				err = nil
+				patrols = status.Patrols
+			}
+		}
 		if err == nil && !state.StartedAt.IsZero() {
 			fmt.Printf("  Started: %s\n", state.StartedAt.Format("2006-01-02 15:04:05"))
 			if !state.LastHeartbeat.IsZero() {
@@ -243,6 +383,24 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+		printDaemonLeader(townRoot)
+		if len(patrols) > 0 {
+			var enabled []string
+			for name, on := range patrols {
+				if on {
+					enabled = append(enabled, name)
+				}
+			}
+			sort.Strings(enabled)
+			fmt.Printf("  Patrols: %s\n", strings.Join(enabled, ", "))
+		}
+	} else if leader, err := daemon.CurrentLeader(townRoot); err == nil && leader != nil {
+		// No daemon here, but one on another host sharing the town leads it.
+		fmt.Printf("%s Daemon is %s on %s (PID %d)\n",
+			style.Bold.Render("●"),
+			style.Bold.Render("running"),
+			leader.Host, leader.PID)
+		printDaemonLeader(townRoot)
 	} else {
 		fmt.Printf("%s Daemon is %s\n",
 			style.Dim.Render("○"),
@@ -253,6 +411,29 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printDaemonLeader shows who holds the daemon leader lease and how long
+// ago it was renewed, flagging a lease the leader has stopped renewing.
+func printDaemonLeader(townRoot string) {
+	lease, err := daemon.LoadLease(townRoot)
+	if err != nil {
+		fmt.Printf("  Leader: %s\n", style.Warning.Render(err.Error()))
+		return
+	}
+	if lease == nil {
+		fmt.Printf("  Leader: %s\n", style.Dim.Render("no lease"))
+		return
+	}
+	now := time.Now()
+	line := fmt.Sprintf("PID %d on %s, renewed %s ago (leader since %s)",
+		lease.PID, lease.Host,
+		lease.Age(now).Round(time.Second),
+		lease.AcquiredAt.Local().Format("2006-01-02 15:04:05"))
+	if lease.Expired(now) {
+		line += " " + style.Warning.Render("⚠ expired")
+	}
+	fmt.Printf("  Leader: %s\n", line)
+}
+
 // getBinaryModTime returns the modification time of the current executable
 func getBinaryModTime() (time.Time, error) {
 	exePath, err := os.Executable()
@@ -300,11 +481,18 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 	}
 
 	config := daemon.DefaultConfig(townRoot)
+	config.Standby = daemonRunStandby
 	d, err := daemon.New(config)
 	if err != nil {
 		return fmt.Errorf("creating daemon: %w", err)
 	}
 
+	if daemonRunStandby {
+		if leader, err := daemon.CurrentLeader(townRoot); err == nil && leader != nil {
+			fmt.Printf("%s Standing by: daemon PID %d on %s leads this town; taking over if its lease lapses\n",
+				style.Dim.Render("○"), leader.PID, leader.Host)
+		}
+	}
 	return d.Run()
 }
 
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	daemonAgentsJSON   bool
+	daemonEventsLines  int
+	daemonEventsFollow bool
+)
+
+const daemonControlHelp = `These commands talk to the running daemon through its local control API,
+which is opt-in. Enable it in mayor/daemon.json and restart the daemon:
+
+  "control_api": {"enabled": true}
+
+The API listens on daemon/control.sock (set "socket" to change it).`
+
+var daemonTriggerCmd = &cobra.Command{
+	Use:   "trigger <job>",
+	Short: "Run a daemon job now",
+	Long: `Ask the running daemon to run one of its jobs immediately instead of
+waiting for its next tick, and wait for it to finish.
+
+Jobs: ` + strings.Join(daemon.ControlJobNames(), ", ") + `
+
+Patrol jobs (dolt_remotes, jsonl_export, change_feed, cost_enforce,
+backup_verify, analytics_export, session_prune, bead_archive,
+dolt_watchdog, branch_prune, sla_check, dolt_gc) only run if the patrol is
+enabled.
+
+` + daemonControlHelp + `
+
+Examples:
+  gt daemon trigger heartbeat
+  gt daemon trigger jsonl_export`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDaemonTrigger,
+}
+
+var daemonAgentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "List agent sessions seen by the daemon",
+	Long: `List the Gas Town agent sessions the running daemon sees.
+
+` + daemonControlHelp + `
+
+Examples:
+  gt daemon agents
+  gt daemon agents --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDaemonAgents,
+}
+
+var daemonEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show or stream town events from the daemon",
+	Long: `Show recent town events (.events.jsonl) from the running daemon, one
+JSON object per line. With --follow, keep streaming new events.
+
+` + daemonControlHelp + `
+
+Examples:
+  gt daemon events             # Last 20 events
+  gt daemon events -n 100      # Last 100 events
+  gt daemon events -f          # Stream new events`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDaemonEvents,
+}
+
+func init() {
+	daemonAgentsCmd.Flags().BoolVar(&daemonAgentsJSON, "json", false, "Output as JSON")
+	daemonEventsCmd.Flags().IntVarP(&daemonEventsLines, "lines", "n", 20, "Number of recent events to show")
+	daemonEventsCmd.Flags().BoolVarP(&daemonEventsFollow, "follow", "f", false, "Stream new events")
+
+	daemonCmd.AddCommand(daemonTriggerCmd)
+	daemonCmd.AddCommand(daemonAgentsCmd)
+	daemonCmd.AddCommand(daemonEventsCmd)
+}
+
+// daemonControlClient returns a control API client for the current town,
+// with a hint on how to enable the API if it is unavailable.
+func daemonControlClient() (*daemon.ControlClient, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	client, err := daemon.NewControlClient(townRoot)
+	if errors.Is(err, daemon.ErrControlAPIUnavailable) {
+		return nil, fmt.Errorf("%w\nStart the daemon with control_api enabled in mayor/daemon.json (see 'gt daemon trigger --help')", err)
+	}
+	return client, err
+}
+
+func runDaemonTrigger(cmd *cobra.Command, args []string) error {
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Running %s...\n", args[0])
+	result, err := client.RunJob(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s finished in %v\n", style.Success.Render("✓"), result.Job, result.Duration.Round(1e6))
+	return nil
+}
+
+func runDaemonAgents(cmd *cobra.Command, args []string) error {
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	agents, err := client.Agents(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if daemonAgentsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(agents)
+	}
+	if len(agents) == 0 {
+		fmt.Println(style.Dim.Render("No agent sessions"))
+		return nil
+	}
+	for _, a := range agents {
+		fmt.Printf("  %s %-28s %s\n", style.Success.Render("●"), a.Address, style.Dim.Render(a.Session))
+	}
+	return nil
+}
+
+func runDaemonEvents(cmd *cobra.Command, args []string) error {
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return client.Events(ctx, daemonEventsLines, daemonEventsFollow, func(line []byte) error {
+		_, err := fmt.Println(string(line))
+		return err
+	})
+}
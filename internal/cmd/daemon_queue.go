@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	daemonQueueJSON     bool
+	daemonQueueJob      string
+	daemonQueueIn       time.Duration
+	daemonQueuePriority int
+	daemonQueueAttempts int
+	daemonQueueReason   string
+)
+
+var daemonQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show the daemon's queue of deferred work",
+	Long: `Show the work the running daemon has deferred, in the order it will run:
+role restarts waiting out their backoff, and jobs or gt commands queued
+with 'gt daemon queue add'. Failed commands are retried with backoff
+until they run out of attempts.
+
+The queue is saved in daemon/work_queue.json, so it survives a daemon
+restart.
+
+` + daemonControlHelp + `
+
+Examples:
+  gt daemon queue
+  gt daemon queue --json
+  gt daemon queue add --in 30m -- mq retry gt-abc
+  gt daemon queue priority wq-3 10
+  gt daemon queue cancel wq-3`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDaemonQueue,
+}
+
+var daemonQueueAddCmd = &cobra.Command{
+	Use:   "add [--job <name> | -- <gt args>...]",
+	Short: "Queue a daemon job or gt command",
+	Long: `Queue a daemon job (--job) or a gt command (the arguments after --) to
+run later. The daemon runs queued work within about 15 seconds of it
+coming due; higher priorities run first.
+
+A failed command is retried with backoff, up to --attempts times.
+
+Examples:
+  gt daemon queue add --job jsonl_export --in 1h
+  gt daemon queue add --priority 5 -- mq retry gt-abc
+  gt daemon queue add --attempts 10 --reason "retry merge" -- mq retry gt-abc`,
+	SilenceUsage: true,
+	RunE:         runDaemonQueueAdd,
+}
+
+var daemonQueueCancelCmd = &cobra.Command{
+	Use:          "cancel <id>",
+	Short:        "Remove an item from the daemon's queue",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDaemonQueueCancel,
+}
+
+var daemonQueuePriorityCmd = &cobra.Command{
+	Use:   "priority <id> <priority>",
+	Short: "Change the priority of a queued item",
+	Long: `Change the priority of a queued item. Of the items that are due, higher
+priorities run first; the default is 0 and negative values are allowed.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runDaemonQueuePriority,
+}
+
+func init() {
+	daemonQueueCmd.Flags().BoolVar(&daemonQueueJSON, "json", false, "Output as JSON")
+	daemonQueueAddCmd.Flags().StringVar(&daemonQueueJob, "job", "", "Daemon job to run (see 'gt daemon trigger --help')")
+	daemonQueueAddCmd.Flags().DurationVar(&daemonQueueIn, "in", 0, "Delay before the item is due")
+	daemonQueueAddCmd.Flags().IntVar(&daemonQueuePriority, "priority", 0, "Priority (higher runs first)")
+	daemonQueueAddCmd.Flags().IntVar(&daemonQueueAttempts, "attempts", 0, "Attempts before a failing command is dropped (default 5)")
+	daemonQueueAddCmd.Flags().StringVar(&daemonQueueReason, "reason", "", "Why the item is queued, shown in the listing")
+
+	daemonQueueCmd.AddCommand(daemonQueueAddCmd)
+	daemonQueueCmd.AddCommand(daemonQueueCancelCmd)
+	daemonQueueCmd.AddCommand(daemonQueuePriorityCmd)
+	daemonCmd.AddCommand(daemonQueueCmd)
+}
+
+func runDaemonQueue(cmd *cobra.Command, args []string) error {
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	items, err := client.Queue(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if daemonQueueJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+	if len(items) == 0 {
+		fmt.Println(style.Dim.Render("No queued work"))
+		return nil
+	}
+	renderDaemonQueue(os.Stdout, items, time.Now())
+	return nil
+}
+
+// renderDaemonQueue writes items as a table, with due times relative to now.
+func renderDaemonQueue(w io.Writer, items []daemon.WorkItem, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tKIND\tTARGET\tPRIORITY\tDUE\tATTEMPTS\tNOTE")
+	for _, item := range items {
+		due := "now"
+		if wait := item.NotBefore.Sub(now); wait > 0 {
+			due = "in " + wait.Round(time.Second).String()
+		}
+		attempts := "-"
+		if item.MaxAttempts > 0 {
+			attempts = fmt.Sprintf("%d/%d", item.Attempts, item.MaxAttempts)
+		}
+		note := item.Reason
+		if item.LastError != "" {
+			note = "last error: " + item.LastError
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", item.ID, item.Kind,
+			truncateWithEllipsis(item.Target, 40), item.Priority, due, attempts, truncateWithEllipsis(note, 60))
+	}
+	_ = tw.Flush()
+}
+
+func runDaemonQueueAdd(cmd *cobra.Command, args []string) error {
+	if (daemonQueueJob == "") == (len(args) == 0) {
+		return fmt.Errorf("give either --job <name> or a gt command after --")
+	}
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	item, err := client.Enqueue(cmd.Context(), daemon.ControlQueueRequest{
+		Job:         daemonQueueJob,
+		Args:        args,
+		Reason:      daemonQueueReason,
+		Priority:    daemonQueuePriority,
+		Delay:       daemonQueueIn,
+		MaxAttempts: daemonQueueAttempts,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Queued %s: %s %s\n", style.Success.Render("✓"), item.ID, item.Kind, item.Target)
+	return nil
+}
+
+func runDaemonQueueCancel(cmd *cobra.Command, args []string) error {
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	item, err := client.CancelQueued(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Canceled %s: %s %s\n", style.Success.Render("✓"), item.ID, item.Kind, item.Target)
+	return nil
+}
+
+func runDaemonQueuePriority(cmd *cobra.Command, args []string) error {
+	priority, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid priority %q: must be an integer", args[1])
+	}
+	client, err := daemonControlClient()
+	if err != nil {
+		return err
+	}
+	item, err := client.SetQueuePriority(cmd.Context(), args[0], priority)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s priority is now %d\n", style.Success.Render("✓"), item.ID, item.Priority)
+	return nil
+}
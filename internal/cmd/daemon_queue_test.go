@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/daemon"
+)
+
+func TestRenderDaemonQueue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	items := []daemon.WorkItem{
+		{ID: "wq-2", Kind: daemon.WorkKindCommand, Target: "gt mq retry gt-abc", Priority: 5,
+			NotBefore: now.Add(-time.Minute), Attempts: 1, MaxAttempts: 5, LastError: "exit status 1"},
+		{ID: "wq-1", Kind: daemon.WorkKindRestart, Target: "deacon", Reason: "restart backoff",
+			NotBefore: now.Add(90 * time.Second)},
+	}
+
+	var buf bytes.Buffer
+	renderDaemonQueue(&buf, items, now)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header + 2:\n%s", len(lines), buf.String())
+	}
+	for _, want := range []string{"wq-2", "now", "1/5", "last error: exit status 1"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("row %q missing %q", lines[1], want)
+		}
+	}
+	for _, want := range []string{"wq-1", "in 1m30s", "restart backoff"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("row %q missing %q", lines[2], want)
+		}
+	}
+}
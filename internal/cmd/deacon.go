@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -1151,7 +1152,7 @@ func getAgentBeadUpdateTime(townRoot, beadID string) (time.Time, error) {
 	cmd := exec.Command("bd", "show", beadID, "--json")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -1187,7 +1188,7 @@ func updateAgentBeadState(townRoot, agent, state, _ string) { // reason unused b
 	// Use bd agent state command
 	cmd := exec.Command("bd", "agent", "state", beadID, state)
 	cmd.Dir = townRoot
-	_ = cmd.Run() // Best effort
+	_ = proc.RunCmd(cmd) // Best effort
 }
 
 // runDeaconStaleHooks finds and unhooks stale hooked beads.
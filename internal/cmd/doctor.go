@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -16,6 +19,7 @@ var (
 	doctorRig             string
 	doctorRestartSessions bool
 	doctorSlow            string
+	doctorDeep            bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -42,6 +46,7 @@ Town root protection:
 Infrastructure checks:
   - stale-binary             Check if gt binary is up to date with repo
   - daemon                   Check if daemon is running (fixable)
+  - daemon-restarts          Warn if the watchdog keeps restarting the daemon
   - repo-fingerprint         Check database has valid repo fingerprint (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
 
@@ -64,7 +69,7 @@ Crew workspace checks:
 Migration checks (fixable):
   - sparse-checkout          Detect legacy sparse checkout across all rigs
 
-Rig checks (with --rig flag):
+Rig checks (with --rig flag, or 'gt rig doctor <rig>'):
   - rig-is-git-repo          Verify rig is a valid git repository
   - git-exclude-configured   Check .git/info/exclude has Gas Town dirs (fixable)
   - bare-repo-exists         Verify .repo.git exists when worktrees depend on it (fixable)
@@ -89,6 +94,7 @@ Dolt checks:
   - dolt-metadata            Check dolt metadata tables exist
   - dolt-server-reachable    Check dolt sql-server is reachable
   - dolt-orphaned-databases  Detect orphaned dolt databases
+  - sync-mode                Check sync.mode matches between database and config.yaml (fixable)
 
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
@@ -97,9 +103,15 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
   - patrol-roles-have-prompts Verify role prompts exist
 
+With --rig, doctor checks only that rig: the rig checks above plus the
+checks that look at its beads directory, Dolt database metadata,
+worktrees, crew workspaces, and tmux sessions. Town-level state and other
+rigs are skipped, and --fix touches only that rig.
+
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+Use --deep with --rig to also run 'gt simulate' against the rig.`,
 	RunE: runDoctor,
 }
 
@@ -111,6 +123,7 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	doctorCmd.Flags().BoolVar(&doctorDeep, "deep", false, "Also simulate a full sling on the rig (requires --rig)")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -120,16 +133,93 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if doctorDeep && doctorRig == "" {
+		return fmt.Errorf("--deep requires --rig")
+	}
+	if doctorRig != "" {
+		rigNames, err := rigsconfig.Names(townRoot)
+		if err != nil {
+			return fmt.Errorf("loading rigs: %w", err)
+		}
+		if !slices.Contains(rigNames, doctorRig) {
+			return fmt.Errorf("rig %q not found in mayor/rigs.json", doctorRig)
+		}
+	}
 
-	// Create check context
-	ctx := &doctor.CheckContext{
+	// Parse slow threshold (0 = disabled)
+	var slowThreshold time.Duration
+	if doctorSlow != "" {
+		var err error
+		slowThreshold, err = time.ParseDuration(doctorSlow)
+		if err != nil {
+			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
+		}
+	}
+
+	// Run checks with streaming output
+	fmt.Println() // Initial blank line
+	report := RunDoctorChecks(DoctorOptions{
 		TownRoot:        townRoot,
-		RigName:         doctorRig,
+		Rig:             doctorRig,
+		Fix:             doctorFix,
 		Verbose:         doctorVerbose,
 		RestartSessions: doctorRestartSessions,
+		SlowThreshold:   slowThreshold,
+		Output:          os.Stdout,
+	})
+
+	// Print summary (checks were already printed during streaming)
+	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+
+	// Deep check: exercise the full sling pipeline on the rig
+	if doctorDeep {
+		fmt.Println()
+		if err := runSlingSimulation(os.Stdout, townRoot, doctorRig, false); err != nil {
+			return err
+		}
+	}
+
+	// Exit with error code if there are errors
+	if report.HasErrors() {
+		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	}
+
+	return nil
+}
+
+// DoctorOptions configures a doctor run.
+type DoctorOptions struct {
+	TownRoot        string
+	Rig             string // Check only this rig (empty = whole town)
+	Fix             bool   // Attempt automatic fixes
+	Verbose         bool
+	RestartSessions bool          // Restart patrol sessions when fixing stale settings
+	SlowThreshold   time.Duration // Mark checks slower than this (0 = disabled)
+	Output          io.Writer     // Stream per-check progress here (nil = silent)
+}
+
+// RunDoctorChecks runs all town checks, or only opts.Rig's checks when it
+// is set, and returns the report.
+func RunDoctorChecks(opts DoctorOptions) *doctor.Report {
+	ctx := &doctor.CheckContext{
+		TownRoot:        opts.TownRoot,
+		RigName:         opts.Rig,
+		Verbose:         opts.Verbose,
+		RestartSessions: opts.RestartSessions,
+		RigOnly:         opts.Rig != "",
+	}
+	d := newTownDoctor()
+	if opts.Rig != "" {
+		d = newRigDoctor()
 	}
+	if opts.Fix {
+		return d.FixStreaming(ctx, opts.Output, opts.SlowThreshold)
+	}
+	return d.RunStreaming(ctx, opts.Output, opts.SlowThreshold)
+}
 
-	// Create doctor and register checks
+// newTownDoctor creates a doctor with all town-level checks registered.
+func newTownDoctor() *doctor.Doctor {
 	d := doctor.NewDoctor()
 
 	// Register workspace-level checks first (fundamental)
@@ -144,6 +234,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewTownRootBranchCheck())
 	d.Register(doctor.NewPreCheckoutHookCheck())
 	d.Register(doctor.NewDaemonCheck())
+	d.Register(doctor.NewDaemonRestartsCheck())
 	d.Register(doctor.NewRepoFingerprintCheck())
 	d.Register(doctor.NewBootHealthCheck())
 	d.Register(doctor.NewBeadsDatabaseCheck())
@@ -172,6 +263,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewThemeCheck())
 	d.Register(doctor.NewCrashReportCheck())
 	d.Register(doctor.NewEnvVarsCheck())
+	d.Register(doctor.NewWorkspaceEnvCheck())
 
 	// Patrol system checks
 	d.Register(doctor.NewPatrolMoleculesExistCheck())
@@ -223,42 +315,40 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewDoltMetadataCheck())
 	d.Register(doctor.NewDoltServerReachableCheck())
 	d.Register(doctor.NewDoltOrphanedDatabaseCheck())
+	d.Register(doctor.NewSyncModeCheck())
+	d.Register(doctor.NewBackupRestoreCheck())
 
-	// Worktree gitdir validity (runs across all rigs, or specific rig with --rig)
+	// Worktree gitdir validity (runs across all rigs)
 	d.Register(doctor.NewWorktreeGitdirCheck())
 
-	// Rig-specific checks (only when --rig is specified)
-	if doctorRig != "" {
-		d.RegisterAll(doctor.RigChecks()...)
-	}
+	return d
+}
 
-	// Parse slow threshold (0 = disabled)
-	var slowThreshold time.Duration
-	if doctorSlow != "" {
-		var err error
-		slowThreshold, err = time.ParseDuration(doctorSlow)
-		if err != nil {
-			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
-		}
-	}
+// newRigDoctor creates a doctor for a rig-scoped run: the rig checks plus
+// the town checks that narrow to CheckContext.RigName when RigOnly is set.
+// Town-wide checks with nothing rig-specific to say are left out.
+func newRigDoctor() *doctor.Doctor {
+	d := doctor.NewDoctor()
 
-	// Run checks with streaming output
-	fmt.Println() // Initial blank line
-	var report *doctor.Report
-	if doctorFix {
-		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
-	} else {
-		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
-	}
+	d.RegisterAll(doctor.RigChecks()...)
+	d.Register(doctor.NewRigNameMismatchCheck())
 
-	// Print summary (checks were already printed during streaming)
-	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+	// Beads directory and Dolt database
+	d.Register(doctor.NewRepoFingerprintCheck())
+	d.Register(doctor.NewRoutingModeCheck())
+	d.Register(doctor.NewRigBeadsCheck())
+	d.Register(doctor.NewDoltServerReachableCheck())
+	d.Register(doctor.NewDoltMetadataCheck())
 
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
-	}
+	// Worktrees and clones
+	d.Register(doctor.NewWorktreeGitdirCheck())
+	d.Register(doctor.NewHooksPathAllRigsCheck())
+	d.Register(doctor.NewSparseCheckoutCheck())
+	d.Register(doctor.NewCrewStateCheck())
+	d.Register(doctor.NewCrewWorktreeCheck())
 
-	return nil
-}
+	// Sessions
+	d.Register(doctor.NewZombieSessionCheck())
 
+	return d
+}
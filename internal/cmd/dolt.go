@@ -1,19 +1,30 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/approval"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 var doltCmd = &cobra.Command{
@@ -30,6 +41,14 @@ Server configuration:
   - Port: 3307 (avoids conflict with MySQL on 3306)
   - User: root (default Dolt user, no password for localhost)
   - Data directory: .dolt-data/ (contains all rig databases)
+  - Max connections: 50
+
+Override any of these in the "dolt_server" section of settings/config.json:
+
+  "dolt_server": {"port": 3308, "user": "gt", "data_dir": "/data/dolt", "max_connections": 100}
+
+Set "startup_timeout" (default "30s") to give a large data directory longer
+to come up before 'gt dolt start' gives up.
 
 Each rig (hq, gastown, beads) has its own database subdirectory.`,
 }
@@ -57,22 +76,49 @@ var doltStartCmd = &cobra.Command{
 	Short: "Start the Dolt server",
 	Long: `Start the Dolt SQL server in the background.
 
-The server will run until stopped with 'gt dolt stop'.`,
+The server will run until stopped with 'gt dolt stop'.
+
+With --warm, representative bd queries are run against each database
+after start so the first agent queries don't pay the cold-cache penalty
+(see 'gt dolt warm').`,
 	RunE: runDoltStart,
 }
 
 var doltStopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the Dolt server",
-	Long:  `Stop the running Dolt SQL server.`,
-	RunE:  runDoltStop,
+	Long: `Stop the running Dolt SQL server.
+
+Before stopping, the server is drained: it is marked as draining (new
+polecat spawns are refused) and the stop waits for connected clients to
+finish and disconnect, up to --timeout. Whatever is still connected after
+the timeout is cut off. Use --allow-spawns to keep accepting spawns while
+draining, for quick restarts where a refused gt sling is worse.
+
+With --all-clients, every agent is first nudged to pause Dolt work so
+in-flight bd commands can finish. Use --force to skip draining entirely.
+
+Examples:
+  gt dolt stop                      # Drain for up to 30s, then stop
+  gt dolt stop --all-clients        # Nudge agents to pause, drain, stop
+  gt dolt stop --timeout 2m         # Wait longer for clients
+  gt dolt stop --allow-spawns       # Drain without refusing gt sling
+  gt dolt stop --force              # Stop immediately`,
+	RunE: runDoltStop,
 }
 
 var doltStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show Dolt server status",
-	Long:  `Show the current status of the Dolt SQL server.`,
-	RunE:  runDoltStatus,
+	Long: `Show the current status of the Dolt SQL server.
+
+To graph these figures instead, have the daemon serve them to Prometheus
+by enabling its metrics endpoint in mayor/daemon.json:
+
+  "metrics": {"enabled": true}
+
+Metrics are served on http://127.0.0.1:9464/metrics (set "listen" to change it).`,
+	RunE: runDoltStatus,
 }
 
 var doltLogsCmd = &cobra.Command{
@@ -84,12 +130,26 @@ var doltLogsCmd = &cobra.Command{
 
 var doltSQLCmd = &cobra.Command{
 	Use:   "sql",
-	Short: "Open Dolt SQL shell",
-	Long: `Open an interactive SQL shell to the Dolt database.
+	Short: "Open Dolt SQL shell or run a query",
+	Long: `Open an interactive SQL shell to the Dolt database, or run one query.
 
 Works in both embedded mode (no server) and server mode.
-For multi-client access, start the server first with 'gt dolt start'.`,
-	RunE: runDoltSQL,
+For multi-client access, start the server first with 'gt dolt start'.
+
+With -q/--query the query runs without a shell and its result is printed
+as a table, CSV, or JSON (an array of objects keyed by column, values as
+strings, NULL as ""). If the query has several statements, the last
+result set is printed. --database selects the rig database, for the
+query or the shell; without it the shell opens the first database when
+no server is running.
+
+Examples:
+  gt dolt sql
+  gt dolt sql --database gastown
+  gt dolt sql -q "SELECT id, title FROM issues WHERE status = 'open'" --database gastown
+  gt dolt sql -q "SHOW DATABASES" --format json`,
+	SilenceUsage: true,
+	RunE:         runDoltSQL,
 }
 
 var doltInitRigCmd = &cobra.Command{
@@ -101,9 +161,32 @@ Each rig (e.g., gastown, beads) gets its own database that will be
 served by the Dolt server. The rig name becomes the database name
 when connecting via MySQL protocol.
 
+With --template, the new database is also seeded from a town template at
+settings/rig-templates/<template>.toml: the town's standard molecule
+protos (cooked from formulas), a pinned handoff bead for each role, and a
+welcome epic with starter tasks. Seeding needs the Dolt server running and
+can be re-run; existing handoff beads and the welcome epic are kept.
+
+Template format:
+
+  molecules = ["mol-polecat-work"]
+
+  [[handoffs]]
+  role = "witness"
+  description = "Patrol {{rig}} and report stuck polecats."
+
+  [welcome]
+  title = "Welcome to {{rig}}"
+  description = "Start here."
+
+  [[welcome.children]]
+  title = "Record build and test commands for {{rig}}"
+
 Example:
   gt dolt init-rig gastown
-  gt dolt init-rig beads`,
+  gt dolt init-rig beads
+  gt dolt init-rig myproject --template standard
+  gt dolt init-rig myproject --template standard --prefix mp`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDoltInitRig,
 }
@@ -123,13 +206,31 @@ centralized .dolt-data/ directory structure.
 
 This command will:
 1. Detect existing dolt databases in .beads/dolt/ directories
-2. Move them to .dolt-data/<rigname>/
-3. Remove the old empty directories
+2. Back up each source .beads directory to migration-backup-YYYYMMDD-HHMMSS/
+3. Move them to .dolt-data/<rigname>/
+4. Remove the old empty directories
+
+Restore the backup with 'gt dolt rollback' if the migration goes wrong.
 
 Use --dry-run to preview what would be moved (source/target paths and sizes)
 without making any changes.
 
-After migration, start the server with 'gt dolt start'.`,
+By default every database found is migrated. To migrate one rig at a time,
+verify it, and continue, select rigs with --rig (repeatable; "hq" is the
+town database) or choose them at a prompt with --interactive. Rigs left
+out stay where they are and are offered again by the next run.
+
+Pre-flight doctor checks (town config, rigs registry, dolt binary, prefix
+conflicts) run first and abort the migration on failure; --skip-preflight
+bypasses them.
+
+After migration, start the server with 'gt dolt start'.
+
+Examples:
+  gt dolt migrate --dry-run          # Show everything that would move
+  gt dolt migrate --rig gastown      # Migrate one rig
+  gt dolt migrate --rig hq --rig beads
+  gt dolt migrate --interactive      # Pick databases at a prompt`,
 	RunE: runDoltMigrate,
 }
 
@@ -144,7 +245,27 @@ instead of connecting to the centralized Dolt server. It updates metadata.json w
   - dolt_mode: "server"
   - dolt_database: "<rigname>"
 
-Safe to run multiple times (idempotent). Preserves any existing fields in metadata.json.`,
+Safe to run multiple times (idempotent). Preserves any existing fields in metadata.json,
+and files that are already correct are not rewritten.
+
+Some rigs track .beads/metadata.json in git, where rewriting it would leave a
+dirty worktree (breaking gt done). For those, and for read-only files, the
+change is written to .runtime/metadata-proposals/<rig>.json instead and
+reported by gt doctor. With --commit (or commit_beads_metadata in
+settings/config.json), tracked files are updated and committed on their own
+with a standard message.
+
+With --check, nothing is written: each rig's metadata.json is compared with
+the expected configuration, every drifted field is listed (expected vs.
+actual), and the command exits non-zero if any rig has drifted. Use it in CI.
+--check also catches a dolt_database pointing at the wrong rig, which the
+update leaves alone because bd init owns that field ('gt doctor --fix'
+resets it).
+
+Examples:
+  gt dolt fix-metadata
+  gt dolt fix-metadata --check
+  gt dolt fix-metadata --check --json`,
 	RunE: runDoltFixMetadata,
 }
 
@@ -222,27 +343,55 @@ This command will:
 4. Reset metadata.json files to their pre-migration state
 5. Validate the restored state with bd list
 
-The backup directory is expected to be in the format created by the migration
-formula's backup step (migration-backup-YYYYMMDD-HHMMSS/).`,
+The backup directory is expected to be in the format created by 'gt dolt
+migrate' or the migration formula's backup step (migration-backup-YYYYMMDD-HHMMSS/).
+
+Pre-flight doctor checks run before restoring and abort on failure;
+--skip-preflight bypasses them.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runDoltRollback,
 }
 
+// doltSQLQueryTimeout bounds a query run with gt dolt sql -q.
+const doltSQLQueryTimeout = 5 * time.Minute
+
 var (
-	doltLogLines     int
-	doltLogFollow    bool
-	doltMigrateDry   bool
-	doltCleanupDry   bool
-	doltRollbackDry  bool
-	doltRollbackList bool
-	doltSyncDry      bool
-	doltSyncForce    bool
-	doltSyncDB       string
+	doltLogLines           int
+	doltLogFollow          bool
+	doltMigrateDry         bool
+	doltMigrateRigs        []string
+	doltMigrateInteractive bool
+	doltCleanupDry         bool
+	doltRollbackDry        bool
+	doltRollbackList       bool
+	doltRollbackApproval   string
+	doltSyncDry            bool
+	doltSyncForce          bool
+	doltSyncDB             string
+
+	doltInitRigTemplate string
+	doltInitRigPrefix   string
+
+	doltSQLQuery    string
+	doltSQLFormat   string
+	doltSQLDatabase string
+
+	doltStartWarm bool
+
+	doltFixMetadataCommit bool
+	doltFixMetadataCheck  bool
+	doltFixMetadataJSON   bool
+
+	doltStopForce       bool
+	doltStopAllClients  bool
+	doltStopTimeout     time.Duration
+	doltStopAllowSpawns bool
 )
 
 func init() {
 	doltCmd.AddCommand(doltInitCmd)
 	doltCmd.AddCommand(doltStartCmd)
+	doltStartCmd.Flags().BoolVar(&doltStartWarm, "warm", false, "Pre-warm server caches after start")
 	doltCmd.AddCommand(doltStopCmd)
 	doltCmd.AddCommand(doltStatusCmd)
 	doltCmd.AddCommand(doltLogsCmd)
@@ -256,15 +405,34 @@ func init() {
 	doltCmd.AddCommand(doltRollbackCmd)
 	doltCmd.AddCommand(doltSyncCmd)
 
+	doltSQLCmd.Flags().StringVarP(&doltSQLQuery, "query", "q", "", "Run a single query instead of opening a shell")
+	doltSQLCmd.Flags().StringVar(&doltSQLFormat, "format", "table", "Query output format: table, csv, json")
+	doltSQLCmd.Flags().StringVar(&doltSQLDatabase, "database", "", "Rig database to use")
+
+	doltInitRigCmd.Flags().StringVar(&doltInitRigTemplate, "template", "", "Seed the database from settings/rig-templates/<template>.toml")
+	doltInitRigCmd.Flags().StringVar(&doltInitRigPrefix, "prefix", "", "Beads issue prefix when seeding (default: derived from rig name)")
+
+	doltStopCmd.Flags().BoolVar(&doltStopForce, "force", false, "Stop immediately without draining connections")
+	doltStopCmd.Flags().BoolVar(&doltStopAllClients, "all-clients", false, "Nudge all agents to pause Dolt work before draining")
+	doltStopCmd.Flags().DurationVar(&doltStopTimeout, "timeout", doltserver.DefaultDrainTimeout, "How long to wait for clients to disconnect")
+	doltStopCmd.Flags().BoolVar(&doltStopAllowSpawns, "allow-spawns", false, "Keep accepting polecat spawns while draining")
+
+	doltFixMetadataCmd.Flags().BoolVar(&doltFixMetadataCommit, "commit", false, "Commit updates to metadata.json files tracked in git")
+	doltFixMetadataCmd.Flags().BoolVar(&doltFixMetadataCheck, "check", false, "Report drift without changing anything; exit 1 if any")
+	doltFixMetadataCmd.Flags().BoolVar(&doltFixMetadataJSON, "json", false, "Output --check results as JSON")
+
 	doltCleanupCmd.Flags().BoolVar(&doltCleanupDry, "dry-run", false, "Preview what would be removed without making changes")
 
 	doltLogsCmd.Flags().IntVarP(&doltLogLines, "lines", "n", 50, "Number of lines to show")
 	doltLogsCmd.Flags().BoolVarP(&doltLogFollow, "follow", "f", false, "Follow log output")
 
 	doltMigrateCmd.Flags().BoolVar(&doltMigrateDry, "dry-run", false, "Preview what would be migrated without making changes")
+	doltMigrateCmd.Flags().StringSliceVar(&doltMigrateRigs, "rig", nil, "Migrate only this rig (repeatable; \"hq\" for the town database)")
+	doltMigrateCmd.Flags().BoolVarP(&doltMigrateInteractive, "interactive", "i", false, "Choose which databases to migrate, one prompt per database")
 
 	doltRollbackCmd.Flags().BoolVar(&doltRollbackDry, "dry-run", false, "Show what would be restored without making changes")
 	doltRollbackCmd.Flags().BoolVar(&doltRollbackList, "list", false, "List available backups and exit")
+	doltRollbackCmd.Flags().StringVar(&doltRollbackApproval, "approval", "", "Approval token from 'gt approve rollback' (when required by town policy)")
 
 	doltSyncCmd.Flags().BoolVar(&doltSyncDry, "dry-run", false, "Preview what would be pushed without pushing")
 	doltSyncCmd.Flags().BoolVar(&doltSyncForce, "force", false, "Force-push to remotes")
@@ -318,6 +486,16 @@ func runDoltStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s All %d databases verified\n", style.Bold.Render("✓"), len(served))
 	}
 
+	if doltStartWarm {
+		results, err := doltserver.WarmDatabases(townRoot, served)
+		if err != nil {
+			fmt.Printf("  %s Could not warm databases: %v\n", style.Dim.Render("⚠"), err)
+			return nil
+		}
+		fmt.Println()
+		printWarmResults(results)
+	}
+
 	return nil
 }
 
@@ -327,9 +505,14 @@ func runDoltStop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	_, pid, _ := doltserver.IsRunning(townRoot)
+	running, pid, _ := doltserver.IsRunning(townRoot)
+
+	if running && !doltStopForce {
+		drainDoltServer(townRoot)
+	}
 
 	if err := doltserver.Stop(townRoot); err != nil {
+		_ = doltserver.SetDraining(townRoot, false)
 		return err
 	}
 
@@ -337,6 +520,73 @@ func runDoltStop(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// doltPauseNudge is sent to agents by 'gt dolt stop --all-clients'.
+const doltPauseNudge = "Dolt server is shutting down for maintenance. Finish or pause your current bd command and hold off on new bd/gt writes until it is back."
+
+// drainDoltServer marks the server as draining, optionally nudges agents to
+// pause, and waits for clients to disconnect. Failures are reported as
+// warnings: draining is best-effort and never blocks the stop.
+func drainDoltServer(townRoot string) {
+	var err error
+	if doltStopAllowSpawns {
+		err = doltserver.SetDrainingAllowSpawns(townRoot)
+	} else {
+		err = doltserver.SetDraining(townRoot, true)
+	}
+	if err != nil {
+		fmt.Printf("%s Could not mark server as draining: %v\n", style.Warning.Render("⚠"), err)
+	}
+
+	if doltStopAllClients {
+		nudged := nudgeAgentsToPause(townRoot)
+		fmt.Printf("%s Nudged %d agent(s) to pause Dolt work\n", style.Dim.Render("○"), nudged)
+	}
+
+	fmt.Printf("Draining connections (up to %s)...\n", doltStopTimeout)
+	last := -1
+	remaining, err := doltserver.DrainConnections(townRoot, doltStopTimeout, func(n int) {
+		if n != last && n > 0 {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d client connection(s) open", n)))
+		}
+		last = n
+	})
+	switch {
+	case err != nil:
+		fmt.Printf("%s Could not check connections, stopping anyway: %v\n", style.Warning.Render("⚠"), err)
+	case remaining > 0:
+		fmt.Printf("%s %d connection(s) still open after %s, stopping anyway\n", style.Warning.Render("⚠"), remaining, doltStopTimeout)
+	default:
+		fmt.Printf("%s All clients disconnected\n", style.Success.Render("✓"))
+	}
+}
+
+// nudgeAgentsToPause sends doltPauseNudge to every agent session, skipping
+// this session and agents in DND. Returns how many were nudged.
+func nudgeAgentsToPause(townRoot string) int {
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		fmt.Printf("%s Could not list agent sessions: %v\n", style.Warning.Render("⚠"), err)
+		return 0
+	}
+
+	sender := os.Getenv("BD_ACTOR")
+	t := tmux.NewTmux()
+	nudged := 0
+	for _, agent := range agents {
+		name := formatAgentName(agent)
+		if sender != "" && name == sender {
+			continue
+		}
+		if shouldSend, _, _ := shouldNudgeTarget(townRoot, name, false); !shouldSend {
+			continue
+		}
+		if err := t.NudgeSession(agent.Name, doltPauseNudge); err == nil {
+			nudged++
+		}
+	}
+	return nudged
+}
+
 func runDoltStatus(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -359,9 +609,16 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 		// Load state for more details
 		state, err := doltserver.LoadState(townRoot)
 		if err == nil && !state.StartedAt.IsZero() {
-			fmt.Printf("  Started: %s\n", state.StartedAt.Format("2006-01-02 15:04:05"))
+			fmt.Printf("  Started: %s\n", timefmt.TimestampAgo(state.StartedAt))
 			fmt.Printf("  Port: %d\n", state.Port)
 			fmt.Printf("  Data dir: %s\n", state.DataDir)
+			if state.Draining {
+				spawns := "new spawns refused"
+				if state.SpawnsAllowed {
+					spawns = "spawns allowed"
+				}
+				fmt.Printf("  %s Draining since %s (%s)\n", style.Warning.Render("⚠"), timefmt.Clock(state.DrainingSince), spawns)
+			}
 			if len(state.Databases) > 0 {
 				fmt.Printf("  Databases:\n")
 				for _, db := range state.Databases {
@@ -471,6 +728,13 @@ func runDoltSQL(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if doltSQLQuery != "" {
+		return runDoltSQLQuery(townRoot)
+	}
+	if cmd.Flags().Changed("format") {
+		return fmt.Errorf("--format applies to -q/--query")
+	}
+
 	config := doltserver.DefaultConfig(townRoot)
 
 	// Check if server is running - if so, connect via Dolt SQL client
@@ -478,33 +742,43 @@ func runDoltSQL(cmd *cobra.Command, args []string) error {
 	if running {
 		// Connect to running server using dolt sql client
 		// Using --no-tls since local server doesn't have TLS configured
-		sqlCmd := exec.Command("dolt",
+		sqlArgs := []string{
 			"--host", "127.0.0.1",
 			"--port", strconv.Itoa(config.Port),
 			"--user", config.User,
 			"--password", "",
 			"--no-tls",
-			"sql",
-		)
+		}
+		if doltSQLDatabase != "" {
+			sqlArgs = append(sqlArgs, "--use-db", doltSQLDatabase)
+		}
+		sqlCmd := exec.Command("dolt", append(sqlArgs, "sql")...)
 		sqlCmd.Stdin = os.Stdin
 		sqlCmd.Stdout = os.Stdout
 		sqlCmd.Stderr = os.Stderr
-		return sqlCmd.Run()
+		return proc.RunCmd(sqlCmd)
 	}
 
-	// Server not running - list databases and pick first one for embedded mode
-	databases, err := doltserver.ListDatabases(townRoot)
-	if err != nil {
-		return fmt.Errorf("listing databases: %w", err)
-	}
+	// Server not running - use the requested database, or the first one,
+	// in embedded mode
+	database := doltSQLDatabase
+	if database == "" {
+		databases, err := doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
 
-	if len(databases) == 0 {
-		return fmt.Errorf("no databases found in %s\nInitialize with: gt dolt init-rig <name>", config.DataDir)
+		if len(databases) == 0 {
+			return fmt.Errorf("no databases found in %s\nInitialize with: gt dolt init-rig <name>", config.DataDir)
+		}
+		database = databases[0]
+		fmt.Printf("Using database: %s (start server with 'gt dolt start' for multi-database access)\n\n", database)
 	}
 
-	// Use first database for embedded SQL shell
-	dbDir := doltserver.RigDatabaseDir(townRoot, databases[0])
-	fmt.Printf("Using database: %s (start server with 'gt dolt start' for multi-database access)\n\n", databases[0])
+	dbDir := doltserver.RigDatabaseDir(townRoot, database)
+	if _, err := os.Stat(filepath.Join(dbDir, ".dolt")); err != nil {
+		return fmt.Errorf("database %q not found in %s", database, config.DataDir)
+	}
 
 	sqlCmd := exec.Command("dolt", "sql")
 	sqlCmd.Dir = dbDir
@@ -512,7 +786,71 @@ func runDoltSQL(cmd *cobra.Command, args []string) error {
 	sqlCmd.Stdout = os.Stdout
 	sqlCmd.Stderr = os.Stderr
 
-	return sqlCmd.Run()
+	return proc.RunCmd(sqlCmd)
+}
+
+// runDoltSQLQuery runs gt dolt sql -q and prints the result in doltSQLFormat.
+func runDoltSQLQuery(townRoot string) error {
+	switch doltSQLFormat {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("unknown format %q: want table, csv, or json", doltSQLFormat)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), doltSQLQueryTimeout)
+	defer cancel()
+	rows, err := doltserver.Query(ctx, townRoot, doltSQLDatabase, doltSQLQuery)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	return writeSQLRows(os.Stdout, doltSQLFormat, rows)
+}
+
+// writeSQLRows writes query rows (header first) to w as a table, CSV, or a
+// JSON array of objects keyed by column.
+func writeSQLRows(w io.Writer, format string, rows [][]string) error {
+	switch format {
+	case "json":
+		out := []map[string]string{}
+		if len(rows) > 0 {
+			for _, row := range rows[1:] {
+				obj := make(map[string]string, len(rows[0]))
+				for i, col := range rows[0] {
+					if i < len(row) {
+						obj[col] = row[i]
+					}
+				}
+				out = append(out, obj)
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	n := len(rows) - 1
+	plural := "s"
+	if n == 1 {
+		plural = ""
+	}
+	_, err := fmt.Fprintln(w, style.Dim.Render(fmt.Sprintf("(%d row%s)", n, plural)))
+	return err
 }
 
 func runDoltInitRig(cmd *cobra.Command, args []string) error {
@@ -523,6 +861,16 @@ func runDoltInitRig(cmd *cobra.Command, args []string) error {
 
 	rigName := args[0]
 
+	// Load the template before creating anything so a typo doesn't leave
+	// an unseeded database behind.
+	var tmpl *rig.RigTemplate
+	if doltInitRigTemplate != "" {
+		tmpl, err = rig.LoadRigTemplate(townRoot, doltInitRigTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
 	serverWasRunning, created, err := doltserver.InitRig(townRoot, rigName)
 	if err != nil {
 		return err
@@ -532,6 +880,10 @@ func runDoltInitRig(cmd *cobra.Command, args []string) error {
 	rigDir := doltserver.RigDatabaseDir(townRoot, rigName)
 
 	if !created {
+		if tmpl != nil {
+			fmt.Printf("%s Rig database %q already exists\n", style.Bold.Render("✓"), rigName)
+			return seedRigFromTemplate(townRoot, rigName, tmpl)
+		}
 		fmt.Printf("%s Rig database %q already exists (no-op)\n", style.Bold.Render("✓"), rigName)
 		fmt.Printf("  Location: %s\n", rigDir)
 		return nil
@@ -547,6 +899,44 @@ func runDoltInitRig(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\nStart server with: %s\n", style.Dim.Render("gt dolt start"))
 	}
 
+	if tmpl != nil {
+		return seedRigFromTemplate(townRoot, rigName, tmpl)
+	}
+	return nil
+}
+
+// seedRigFromTemplate seeds a rig database from a town template and prints
+// what was created. Requires a running server, since seeding goes through bd.
+func seedRigFromTemplate(townRoot, rigName string, tmpl *rig.RigTemplate) error {
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("seeding from template %q needs the Dolt server: run 'gt dolt start', then re-run 'gt dolt init-rig %s --template %s'",
+			tmpl.Name, rigName, tmpl.Name)
+	}
+	beadsDir, err := doltserver.FindOrCreateRigBeadsDir(townRoot, rigName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSeeding %s from template %s...\n", style.Bold.Render(rigName), style.Bold.Render(tmpl.Name))
+	result, err := rig.SeedRigFromTemplate(townRoot, rigName, beadsDir, doltInitRigPrefix, tmpl)
+	if result != nil {
+		for _, m := range result.Molecules {
+			fmt.Printf("  %s Molecule proto %s\n", style.Success.Render("✓"), m)
+		}
+		for _, id := range result.Handoffs {
+			fmt.Printf("  %s Handoff bead %s\n", style.Success.Render("✓"), id)
+		}
+		if result.Welcome != "" {
+			fmt.Printf("  %s Welcome epic %s (%d tasks)\n", style.Success.Render("✓"), result.Welcome, len(result.Children))
+		}
+		for _, id := range result.Existing {
+			fmt.Printf("  %s %s already exists\n", style.Dim.Render("○"), id)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("seeding %s: %w", rigName, err)
+	}
+	fmt.Printf("%s Seeded %s (prefix %s)\n", style.Success.Render("✓"), rigName, result.Prefix)
 	return nil
 }
 
@@ -715,12 +1105,32 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Dolt server is running. Stop it first with: gt dolt stop")
 	}
 
+	if !doltMigrateDry {
+		if err := runPreflight(cmd, townRoot); err != nil {
+			return err
+		}
+	}
+
 	// Find databases to migrate
-	migrations := doltserver.FindMigratableDatabases(townRoot)
-	if len(migrations) == 0 {
+	found := doltserver.FindMigratableDatabases(townRoot)
+	if len(found) == 0 {
 		fmt.Println("No databases found to migrate.")
 		return nil
 	}
+	migrations, err := doltserver.FilterMigrations(found, doltMigrateRigs)
+	if err != nil {
+		return err
+	}
+	if doltMigrateInteractive {
+		if migrations, err = promptMigrations(migrations); err != nil {
+			return err
+		}
+		if len(migrations) == 0 {
+			fmt.Println("Nothing selected; no changes made.")
+			return nil
+		}
+		fmt.Println()
+	}
 
 	fmt.Printf("Found %d database(s) to migrate:\n\n", len(migrations))
 	for _, m := range migrations {
@@ -734,11 +1144,19 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Snapshot every source .beads directory before anything moves, so
+	// gt dolt rollback has something to restore even without the formula.
+	backupPath, err := doltserver.BackupForMigration(townRoot, migrations)
+	if err != nil {
+		return fmt.Errorf("pre-migration backup failed, nothing was migrated: %w", err)
+	}
+	fmt.Printf("%s Backed up .beads directories to %s\n\n", style.Bold.Render("✓"), backupPath)
+
 	// Perform migrations
 	for _, m := range migrations {
 		fmt.Printf("Migrating %s...\n", m.RigName)
 		if err := doltserver.MigrateRigFromBeads(townRoot, m.RigName, m.SourcePath); err != nil {
-			return fmt.Errorf("migrating %s: %w", m.RigName, err)
+			return fmt.Errorf("migrating %s: %w (restore with: gt dolt rollback %s)", m.RigName, err, backupPath)
 		}
 		fmt.Printf("  %s Migrated to %s\n", style.Bold.Render("✓"), m.TargetPath)
 	}
@@ -753,6 +1171,10 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\n%s Migration complete.\n", style.Bold.Render("✓"))
+	if remaining := len(found) - len(migrations); remaining > 0 {
+		fmt.Printf("  %d database(s) not selected remain in .beads/dolt/; run %s again to continue.\n",
+			remaining, style.Dim.Render("gt dolt migrate"))
+	}
 
 	// Auto-start the Dolt server to prevent split-brain risk.
 	// If bd commands are run before the server starts, they may silently create
@@ -797,6 +1219,21 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// promptMigrations asks, for each migration, whether to include it.
+// Requires an interactive terminal.
+func promptMigrations(migrations []doltserver.Migration) ([]doltserver.Migration, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("--interactive requires a terminal; use --rig to select databases")
+	}
+	var selected []doltserver.Migration
+	for _, m := range migrations {
+		if promptYesNo(fmt.Sprintf("Migrate %s (%s, %s)?", m.RigName, m.SourcePath, dirSizeHuman(m.SourcePath))) {
+			selected = append(selected, m)
+		}
+	}
+	return selected, nil
+}
+
 // dirSizeHuman returns a human-readable size string for a directory tree.
 func dirSizeHuman(path string) string {
 	var total int64
@@ -818,7 +1255,30 @@ func runDoltFixMetadata(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	updated, errs := doltserver.EnsureAllMetadata(townRoot)
+	if doltFixMetadataCheck {
+		return checkDoltMetadata(townRoot)
+	}
+
+	opts := doltserver.DefaultMetadataOptions(townRoot)
+	if doltFixMetadataCommit {
+		opts.Commit = true
+	}
+	results, errs := doltserver.UpdateAllMetadata(townRoot, opts)
+
+	var updated, unchanged []string
+	var proposed []*doltserver.MetadataResult
+	for _, r := range results {
+		switch r.Action {
+		case doltserver.MetadataWritten:
+			updated = append(updated, r.Rig)
+		case doltserver.MetadataCommitted:
+			updated = append(updated, r.Rig+" (committed)")
+		case doltserver.MetadataProposed:
+			proposed = append(proposed, r)
+		default:
+			unchanged = append(unchanged, r.Rig)
+		}
+	}
 
 	if len(updated) > 0 {
 		fmt.Printf("%s Updated metadata.json for %d rig(s):\n", style.Bold.Render("✓"), len(updated))
@@ -826,6 +1286,17 @@ func runDoltFixMetadata(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  - %s\n", name)
 		}
 	}
+	if len(unchanged) > 0 {
+		fmt.Printf("%s %d rig(s) already up to date\n", style.Dim.Render("○"), len(unchanged))
+	}
+	if len(proposed) > 0 {
+		fmt.Printf("%s metadata.json is tracked in git or read-only for %d rig(s); proposed changes:\n",
+			style.Warning.Render("⚠"), len(proposed))
+		for _, r := range proposed {
+			fmt.Printf("  - %s: %s\n", r.Rig, r.Proposal)
+		}
+		fmt.Printf("  Review and commit them, or rerun with %s\n", style.Bold.Render("--commit"))
+	}
 
 	if len(errs) > 0 {
 		fmt.Println()
@@ -834,13 +1305,67 @@ func runDoltFixMetadata(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if len(updated) == 0 && len(errs) == 0 {
+	if len(results) == 0 && len(errs) == 0 {
 		fmt.Println("No rig databases found. Nothing to update.")
 	}
 
 	return nil
 }
 
+// checkDoltMetadata reports metadata.json drift for every rig without
+// changing anything, failing if any rig has drifted.
+func checkDoltMetadata(townRoot string) error {
+	results, errs := doltserver.VerifyAllMetadata(townRoot)
+	drifted := 0
+	for _, v := range results {
+		if !v.OK() {
+			drifted++
+		}
+	}
+
+	if doltFixMetadataJSON {
+		if results == nil {
+			results = []*doltserver.MetadataVerification{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range results {
+			switch {
+			case v.OK():
+				fmt.Printf("%s %s\n", style.Success.Render("✓"), v.Rig)
+			case v.Missing:
+				fmt.Printf("%s %s: %s is missing\n", style.Error.Render("✗"), v.Rig, v.Path)
+			default:
+				fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), v.Rig, v.Path)
+				for _, d := range v.Drift {
+					actual := d.Actual
+					if actual == "" {
+						actual = "(missing)"
+					}
+					fmt.Printf("    %-14s expected %q, actual %q\n", d.Field, d.Expected, actual)
+				}
+			}
+		}
+		for _, err := range errs {
+			fmt.Printf("  %s %v\n", style.Dim.Render("⚠"), err)
+		}
+		if len(results) == 0 && len(errs) == 0 {
+			fmt.Println("No rig databases found. Nothing to check.")
+		} else if drifted > 0 {
+			fmt.Printf("\n%d rig(s) drifted. Run 'gt dolt fix-metadata' or 'gt doctor --fix' to repair.\n", drifted)
+		}
+	}
+
+	if drifted > 0 || len(errs) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
 func runDoltRecover(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -876,6 +1401,12 @@ func runDoltRollback(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if !doltRollbackDry && !doltRollbackList {
+		if err := runPreflight(cmd, townRoot); err != nil {
+			return err
+		}
+	}
+
 	// Find available backups
 	backups, err := doltserver.FindBackups(townRoot)
 	if err != nil {
@@ -935,6 +1466,11 @@ func runDoltRollback(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Town approval policy (gt approve rollback)
+	if err := requireApproval(townRoot, approval.ClassRollback, filepath.Base(backupPath), doltRollbackApproval); err != nil {
+		return err
+	}
+
 	// Stop Dolt server if running
 	running, _, _ := doltserver.IsRunning(townRoot)
 	if running {
@@ -972,7 +1508,7 @@ func runDoltRollback(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nValidating restored state...")
 	validateCmd := exec.Command("bd", "list", "--limit", "5")
 	validateCmd.Dir = townRoot
-	output, validateErr := validateCmd.CombinedOutput()
+	output, validateErr := proc.CombinedOutput(validateCmd)
 	if validateErr != nil {
 		fmt.Printf("  %s bd list returned an error: %v\n",
 			style.Dim.Render("⚠"), validateErr)
@@ -1124,5 +1660,3 @@ func runDoltSync(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
-
-
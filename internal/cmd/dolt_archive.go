@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltArchiveRigs []string
+	doltArchiveDays int
+	doltArchiveDry  bool
+	doltArchiveJSON bool
+)
+
+var doltArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move old closed wisps and digests out of the issues table",
+	Long: `Move closed wisps and digests out of each rig's issues table into
+its issues_archive table, then commit.
+
+Closed wisps (molecule steps, patrol cycles) and digests pile up in the
+issues table that agents and patrols query constantly. Archiving keeps the
+hot working set small while the beads stay searchable:
+
+  gt search --archived <query>
+
+A bead is archived once it has been closed for longer than
+dolt_stats.retention.archive_days in settings/config.json (default 3), or
+--days for this run. Its labels, dependencies, and events are removed with
+it. Run this before 'gt dolt prune', which deletes closed wisps outright.
+
+The daemon archives on a schedule when the bead_archive patrol is enabled
+in mayor/daemon.json.
+
+Examples:
+  gt dolt archive --dry-run          # Count what would be archived
+  gt dolt archive                    # Archive in all rigs
+  gt dolt archive --rig gastown --days 14`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltArchive,
+}
+
+func init() {
+	doltArchiveCmd.Flags().StringSliceVar(&doltArchiveRigs, "rig", nil, "Rig database(s) to archive (default: all)")
+	doltArchiveCmd.Flags().IntVar(&doltArchiveDays, "days", 0, "Archive beads closed more than this many days ago (overrides settings)")
+	doltArchiveCmd.Flags().BoolVar(&doltArchiveDry, "dry-run", false, "Count archivable beads without moving them")
+	doltArchiveCmd.Flags().BoolVar(&doltArchiveJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltArchiveCmd)
+}
+
+func runDoltArchive(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltArchiveRigs)
+	if err != nil {
+		return err
+	}
+	days := doltArchiveDays
+	if days == 0 {
+		settings, err := loadDoltStatsConfig(townRoot)
+		if err != nil {
+			return err
+		}
+		days = doltserver.ArchiveAfterDays(settings)
+	}
+
+	results := []*doltserver.ArchiveResult{}
+	var errs []error
+	for _, db := range databases {
+		res, err := doltserver.ArchiveClosed(townRoot, db, days, doltArchiveDry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, res)
+	}
+
+	if doltArchiveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printDoltArchive(results)
+	}
+	return errors.Join(errs...)
+}
+
+func printDoltArchive(results []*doltserver.ArchiveResult) {
+	var total int64
+	for _, r := range results {
+		total += r.Rows
+		if r.Rows == 0 {
+			fmt.Printf("  %s %s: nothing closed more than %d days ago\n", style.Dim.Render("○"), r.Database, r.Days)
+			continue
+		}
+		verb := "archived"
+		if doltArchiveDry {
+			verb = "would archive"
+		}
+		fmt.Printf("  %s %s: %s %d closed wisp(s)/digest(s)\n", style.Success.Render("✓"), r.Database, verb, r.Rows)
+	}
+	if doltArchiveDry && total > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("Dry run: nothing moved"))
+	}
+}
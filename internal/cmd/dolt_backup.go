@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/approval"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltBackupList bool
+	doltBackupKeep int
+	doltBackupJSON bool
+
+	doltBackupOffsite   bool
+	doltBackupNoOffsite bool
+
+	doltRestoreApproval string
+	doltRestoreOffsite  bool
+)
+
+var doltBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Snapshot all rig databases to .dolt-backups/",
+	Long: `Copy every database in the data directory, history included, to
+.dolt-backups/<YYYYMMDD-HHMMSS>/ in the town.
+
+The server is stopped while the databases are copied, so every copy is
+consistent, and restarted afterwards. Agents see the server go away for the
+length of the copy.
+
+After each backup, all but the newest backups are deleted. The number kept
+is dolt_server.backup_retention in settings/config.json (default 7), or
+--keep.
+
+Restore a backup with 'gt dolt restore <timestamp>'. 'gt dolt rollback'
+handles the migration backups of 'gt dolt migrate' instead.
+
+Offsite copies:
+  Local backups don't survive losing the machine. With an offsite target
+  set in settings/config.json, each backup is also uploaded to object
+  storage:
+
+    "dolt_server": {"offsite": {"url": "s3://bucket/gastown", "profile": "backup"}}
+
+  Targets are s3://, gs://, and az:// (through the aws, gcloud, and az
+  CLIs and their credentials) or file:// for a mounted drive. Files are
+  stored by content hash, so only files that changed since an earlier
+  upload are sent. After each upload, all but the newest offsite.retention
+  snapshots (default 30), and any older than offsite.retention_days, are
+  deleted from the target.
+
+Examples:
+  gt dolt backup                  # Snapshot, upload offsite, prune
+  gt dolt backup --keep 30        # Keep the newest 30 local backups
+  gt dolt backup --no-offsite     # Local snapshot only
+  gt dolt backup --list           # List local backups
+  gt dolt backup --list --offsite # List snapshots in object storage`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltBackup,
+}
+
+var doltRestoreCmd = &cobra.Command{
+	Use:   "restore <timestamp>",
+	Short: "Restore rig databases from a gt dolt backup snapshot",
+	Long: `Replace the databases in the data directory with the copies in
+.dolt-backups/<timestamp>/.
+
+This command will:
+1. Stop the Dolt server if running
+2. Back up the current databases to .dolt-backups/<now>-pre-restore/
+3. Replace each database in the backup with its copy
+4. Restart the Dolt server if it was running
+
+Databases created after the backup was taken are left alone. To undo a
+restore, restore the pre-restore backup.
+
+Pre-flight doctor checks run before restoring and abort on failure;
+--skip-preflight bypasses them. Town approval policy for rollback applies.
+
+With --offsite, a snapshot that isn't in .dolt-backups/ is first downloaded
+from the offsite target, then restored as above.
+
+Examples:
+  gt dolt backup --list
+  gt dolt restore 20260115-020000
+  gt dolt restore 20260115-020000 --offsite   # On a new machine`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltRestore,
+}
+
+func init() {
+	doltBackupCmd.Flags().BoolVar(&doltBackupList, "list", false, "List backups and exit")
+	doltBackupCmd.Flags().IntVar(&doltBackupKeep, "keep", 0, "Backups to keep (default: dolt_server.backup_retention, or 7)")
+	doltBackupCmd.Flags().BoolVar(&doltBackupJSON, "json", false, "Output as JSON")
+	doltBackupCmd.Flags().BoolVar(&doltBackupOffsite, "offsite", false, "With --list, list snapshots at the offsite target")
+	doltBackupCmd.Flags().BoolVar(&doltBackupNoOffsite, "no-offsite", false, "Don't upload the backup to the offsite target")
+	doltRestoreCmd.Flags().StringVar(&doltRestoreApproval, "approval", "", "Approval token from 'gt approve rollback' (when required by town policy)")
+	doltRestoreCmd.Flags().BoolVar(&doltRestoreOffsite, "offsite", false, "Download the snapshot from the offsite target if it isn't local")
+	doltCmd.AddCommand(doltBackupCmd)
+	doltCmd.AddCommand(doltRestoreCmd)
+}
+
+func runDoltBackup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if doltBackupList && doltBackupOffsite {
+		return listOffsiteBackups(townRoot)
+	}
+	if doltBackupList {
+		backups, err := doltserver.ListDataBackups(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing backups: %w", err)
+		}
+		if doltBackupJSON {
+			if backups == nil {
+				backups = []doltserver.DataBackup{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(backups)
+		}
+		printDataBackups(backups)
+		return nil
+	}
+
+	if doltBackupKeep < 0 {
+		return fmt.Errorf("--keep must be at least 1")
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); running && !doltBackupJSON {
+		fmt.Println("Stopping Dolt server for the copy...")
+	}
+	b, pruned, err := doltserver.CreateDataBackup(townRoot, doltBackupKeep)
+	if b == nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	if err != nil {
+		style.PrintWarning("%v", err)
+	}
+
+	var up *doltserver.OffsiteUpload
+	var upErr error
+	if doltserver.DefaultConfig(townRoot).Offsite != nil && !doltBackupNoOffsite {
+		up, upErr = doltserver.UploadDataBackup(townRoot, b.Timestamp)
+	}
+
+	if doltBackupJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			*doltserver.DataBackup
+			Offsite *doltserver.OffsiteUpload `json:"offsite,omitempty"`
+		}{b, up}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("%s Backed up %d database(s) (%s) to %s\n",
+			style.Success.Render("✓"), len(b.Databases), formatBytes(b.SizeBytes), b.Path)
+		for _, p := range pruned {
+			fmt.Printf("  %s Pruned %s\n", style.Dim.Render("○"), p.Timestamp)
+		}
+		if up != nil {
+			fmt.Printf("%s Uploaded %d of %d file(s) (%s, %s unchanged) to %s\n", style.Success.Render("✓"),
+				up.Uploaded, up.Files, formatBytes(up.UploadedBytes), formatBytes(up.SkippedBytes), up.URL)
+			for _, ts := range up.Pruned {
+				fmt.Printf("  %s Pruned offsite %s\n", style.Dim.Render("○"), ts)
+			}
+		}
+	}
+	if upErr != nil {
+		return fmt.Errorf("offsite upload failed (local backup %s is intact): %w", b.Timestamp, upErr)
+	}
+	return nil
+}
+
+func listOffsiteBackups(townRoot string) error {
+	snapshots, err := doltserver.ListOffsiteSnapshots(townRoot)
+	if err != nil {
+		return err
+	}
+	if doltBackupJSON {
+		if snapshots == nil {
+			snapshots = []string{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshots)
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("%s No offsite snapshots at %s\n", style.Dim.Render("○"), doltserver.DefaultConfig(townRoot).Offsite.URL)
+		return nil
+	}
+	for _, ts := range snapshots {
+		label := ""
+		if _, err := doltserver.FindDataBackup(townRoot, ts); err == nil {
+			label = style.Dim.Render(" (also local)")
+		}
+		fmt.Printf("  %s%s\n", style.Bold.Render(ts), label)
+	}
+	return nil
+}
+
+func printDataBackups(backups []doltserver.DataBackup) {
+	if len(backups) == 0 {
+		fmt.Printf("%s No backups. Run 'gt dolt backup' to take one.\n", style.Dim.Render("○"))
+		return
+	}
+	for i, b := range backups {
+		label := ""
+		if i == 0 {
+			label = " (most recent)"
+		}
+		fmt.Printf("  %s%s\n", style.Bold.Render(b.Timestamp), label)
+		fmt.Printf("    %d database(s), %s: %s\n", len(b.Databases), formatBytes(b.SizeBytes), strings.Join(b.Databases, ", "))
+		fmt.Printf("    %s\n", style.Dim.Render(b.Path))
+	}
+}
+
+func runDoltRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if doltRestoreOffsite {
+		if _, err := doltserver.FindDataBackup(townRoot, args[0]); err != nil {
+			fmt.Printf("Downloading %s from offsite...\n", args[0])
+			if _, err := doltserver.FetchOffsiteSnapshot(townRoot, args[0]); err != nil {
+				return fmt.Errorf("%w\nUse 'gt dolt backup --list --offsite' to see offsite snapshots", err)
+			}
+		}
+	}
+	if _, err := doltserver.FindDataBackup(townRoot, args[0]); err != nil {
+		return fmt.Errorf("%w\nUse 'gt dolt backup --list' to see available backups", err)
+	}
+	if err := runPreflight(cmd, townRoot); err != nil {
+		return err
+	}
+	if err := requireApproval(townRoot, approval.ClassRollback, args[0], doltRestoreApproval); err != nil {
+		return err
+	}
+
+	fmt.Printf("Restoring from %s...\n", args[0])
+	result, err := doltserver.RestoreDataBackup(townRoot, args[0])
+	if result != nil {
+		if result.PreRestore != nil {
+			fmt.Printf("  %s Current data backed up to %s\n", style.Success.Render("✓"), result.PreRestore.Timestamp)
+		}
+		for _, db := range result.Restored {
+			fmt.Printf("  %s Restored %s\n", style.Success.Render("✓"), db)
+		}
+		for _, db := range result.Kept {
+			fmt.Printf("  %s Kept %s (not in backup)\n", style.Dim.Render("○"), db)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	fmt.Printf("\n%s Restore complete from %s\n", style.Bold.Render("✓"), args[0])
+	fmt.Printf("To undo: gt dolt restore %s\n", result.PreRestore.Timestamp)
+	return nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltBranchesRigs []string
+	doltBranchesJSON bool
+)
+
+var doltBranchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "List Dolt branches with commits ahead of and behind main",
+	Long: `List the Dolt branches in each rig database other than main.
+
+Each branch is shown with the number of commits it has that main lacks
+(ahead) and that main has and it lacks (behind), its age, and, for
+polecat-<name>-<timestamp> branches, the owning polecat. A polecat
+branch's age is taken from the timestamp in its name; other branches show
+the time since their last commit.
+
+Examples:
+  gt dolt branches
+  gt dolt branches --rig gastown
+  gt dolt branches --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltBranches,
+}
+
+func init() {
+	doltBranchesCmd.Flags().StringSliceVar(&doltBranchesRigs, "rig", nil, "Rig database(s) to list (default: all)")
+	doltBranchesCmd.Flags().BoolVar(&doltBranchesJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltBranchesCmd)
+}
+
+func runDoltBranches(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltBranchesRigs)
+	if err != nil {
+		return err
+	}
+
+	branches := []doltserver.BranchInfo{}
+	var failed int
+	for _, db := range databases {
+		bs, err := doltserver.ListBranches(townRoot, db)
+		if err != nil {
+			style.PrintWarning("%s: %v", db, err)
+			failed++
+			continue
+		}
+		branches = append(branches, bs...)
+	}
+
+	if doltBranchesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(branches); err != nil {
+			return err
+		}
+	} else {
+		printDoltBranches(branches, time.Now())
+	}
+
+	if failed == len(databases) {
+		return fmt.Errorf("failed to list branches in any database")
+	}
+	return nil
+}
+
+func printDoltBranches(branches []doltserver.BranchInfo, now time.Time) {
+	if len(branches) == 0 {
+		fmt.Printf("%s No branches besides main\n", style.Success.Render("✓"))
+		return
+	}
+
+	fmt.Printf("%-12s %-40s %6s %6s %8s  %s\n", "DATABASE", "BRANCH", "AHEAD", "BEHIND", "AGE", "POLECAT")
+	for _, b := range branches {
+		if b.Error != "" {
+			fmt.Printf("%-12s %-40s %s\n", b.Database, b.Name, style.Error.Render(b.Error))
+			continue
+		}
+		polecat := b.Polecat
+		if polecat == "" {
+			polecat = style.Dim.Render("-")
+		}
+		fmt.Printf("%-12s %-40s %6d %6d %8s  %s\n", b.Database, b.Name, b.Ahead, b.Behind, branchAge(b, now), polecat)
+	}
+}
+
+// branchAge is how long ago a polecat branch was created, or for other
+// branches how long ago they were last committed to.
+func branchAge(b doltserver.BranchInfo, now time.Time) string {
+	since := b.Created
+	if since.IsZero() {
+		since = b.LastCommit
+	}
+	if since.IsZero() {
+		return "?"
+	}
+	d := now.Sub(since)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
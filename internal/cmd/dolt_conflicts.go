@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// doltConflictLabelPrefix labels a polecat's agent bead whose Dolt branch
+// failed to merge on conflicts: dolt-conflict:<branch>.
+const doltConflictLabelPrefix = "dolt-conflict:"
+
+var (
+	doltConflictsOurs   bool
+	doltConflictsTheirs bool
+	doltConflictsManual bool
+	doltConflictsJSON   bool
+)
+
+var doltConflictsCmd = &cobra.Command{
+	Use:   "conflicts <rig> [branch]",
+	Short: "List and resolve polecat Dolt branches that conflict with main",
+	Long: `List polecat Dolt branches whose merge into main would conflict, and
+resolve them.
+
+gt done aborts a conflicting merge rather than guessing: main is left
+unchanged, the branch is kept, and the polecat's agent bead is labeled
+dolt-conflict:<branch>. This command previews every polecat branch's
+merge (nothing is merged) and lists the tables that conflict.
+
+Resolve a branch with one of:
+
+  --ours     merge, keeping main's version of every conflicting row
+  --theirs   merge, keeping the polecat branch's version
+  --manual   print the steps to resolve row by row with 'gt dolt sql'
+
+--ours and --theirs merge while holding the rig's merge slot, delete the
+branch, and clear the dolt-conflict label.
+
+Examples:
+  gt dolt conflicts gastown
+  gt dolt conflicts gastown polecat-toast-1712345678
+  gt dolt conflicts gastown polecat-toast-1712345678 --theirs
+  gt dolt conflicts gastown polecat-toast-1712345678 --manual`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE:         runDoltConflicts,
+}
+
+func init() {
+	doltConflictsCmd.Flags().BoolVar(&doltConflictsOurs, "ours", false, "Resolve conflicts keeping main's rows")
+	doltConflictsCmd.Flags().BoolVar(&doltConflictsTheirs, "theirs", false, "Resolve conflicts keeping the polecat branch's rows")
+	doltConflictsCmd.Flags().BoolVar(&doltConflictsManual, "manual", false, "Print steps to resolve conflicts by hand")
+	doltConflictsCmd.Flags().BoolVar(&doltConflictsJSON, "json", false, "Output as JSON")
+	doltConflictsCmd.MarkFlagsMutuallyExclusive("ours", "theirs", "manual")
+	doltCmd.AddCommand(doltConflictsCmd)
+}
+
+func runDoltConflicts(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := args[0]
+	if _, err := doltTargetDatabases(townRoot, args[:1]); err != nil {
+		return err
+	}
+
+	strategy := ""
+	switch {
+	case doltConflictsOurs:
+		strategy = doltserver.ConflictsOurs
+	case doltConflictsTheirs:
+		strategy = doltserver.ConflictsTheirs
+	}
+	if (strategy != "" || doltConflictsManual) && len(args) < 2 {
+		return fmt.Errorf("--ours, --theirs, and --manual need a branch: gt dolt conflicts %s <branch>", rigName)
+	}
+
+	if strategy != "" {
+		branch := args[1]
+		err := withMergeSlot(townRoot, rigName, "gt-conflicts-"+branch, func() error {
+			return doltserver.ResolveMergeConflicts(townRoot, rigName, branch, strategy)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Merged %s into main (conflicts resolved: %s)\n", style.Success.Render("✓"), branch, strategy)
+		clearDoltConflictLabels(townRoot, rigName, branch)
+		return nil
+	}
+
+	found, err := doltserver.ListMergeConflicts(townRoot, rigName)
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		var match []doltserver.BranchConflicts
+		for _, b := range found {
+			if b.Branch == args[1] {
+				match = append(match, b)
+			}
+		}
+		found = match
+	}
+
+	if doltConflictsJSON {
+		if found == nil {
+			found = []doltserver.BranchConflicts{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(found)
+	}
+	if doltConflictsManual {
+		if len(found) == 0 {
+			fmt.Printf("%s %s merges into main without conflicts; use 'gt dolt reconcile-branches --merge'\n", style.Success.Render("✓"), args[1])
+			return nil
+		}
+		printManualConflictSteps(os.Stdout, found[0])
+		return nil
+	}
+	printBranchConflicts(os.Stdout, rigName, found)
+	return nil
+}
+
+// printBranchConflicts lists conflicting branches with their tables.
+func printBranchConflicts(w io.Writer, rigName string, found []doltserver.BranchConflicts) {
+	if len(found) == 0 {
+		fmt.Fprintf(w, "%s No polecat branches in %s conflict with main\n", style.Success.Render("✓"), rigName)
+		return
+	}
+	fmt.Fprintf(w, "%s %d polecat branch(es) in %s conflict with main:\n\n", style.Warning.Render("⚠"), len(found), rigName)
+	for _, b := range found {
+		fmt.Fprintf(w, "  %s\n", style.Bold.Render(b.Branch))
+		for _, c := range b.Conflicts {
+			fmt.Fprintf(w, "      %s: %d data, %d schema conflict(s)\n", c.Table, c.DataConflicts, c.SchemaConflicts)
+		}
+	}
+	fmt.Fprintf(w, "\nResolve with: %s\n", style.Dim.Render("gt dolt conflicts "+rigName+" <branch> --ours|--theirs|--manual"))
+}
+
+// printManualConflictSteps prints how to merge a conflicting branch and
+// resolve its rows by hand.
+func printManualConflictSteps(w io.Writer, b doltserver.BranchConflicts) {
+	fmt.Fprintf(w, "Resolving %s into main by hand:\n\n", style.Bold.Render(b.Branch))
+	fmt.Fprintf(w, "  1. Open a session: gt dolt sql\n")
+	fmt.Fprintf(w, "  2. Start the merge without committing:\n")
+	fmt.Fprintf(w, "       USE %s;\n       SET @@autocommit = 0;\n       CALL DOLT_MERGE('%s');\n", b.Database, b.Branch)
+	fmt.Fprintf(w, "  3. For each conflicting table, inspect and fix the rows, then clear them:\n")
+	for _, c := range b.Conflicts {
+		fmt.Fprintf(w, "       SELECT * FROM dolt_conflicts_%s;   -- %d conflict(s)\n", c.Table, c.DataConflicts)
+		fmt.Fprintf(w, "       DELETE FROM dolt_conflicts_%s;\n", c.Table)
+	}
+	fmt.Fprintf(w, "  4. Commit: CALL DOLT_COMMIT('-am', 'merge %s (conflicts resolved by hand)');\n", b.Branch)
+	fmt.Fprintf(w, "  5. Delete the branch: gt dolt reconcile-branches --rig %s --merge --include-orphaned\n", b.Database)
+	fmt.Fprintf(w, "\nTo abandon a half-done merge: CALL DOLT_MERGE('--abort');\n")
+}
+
+// recordDoltConflict labels the polecat's agent bead with the conflicting
+// branch and comments the tables, so the conflict is visible after the
+// polecat exits. Best-effort.
+func recordDoltConflict(bd *beads.Beads, agentBeadID string, conflict *doltserver.MergeConflictError) {
+	if agentBeadID == "" {
+		return
+	}
+	if err := bd.Update(agentBeadID, beads.UpdateOptions{
+		AddLabels: []string{doltConflictLabelPrefix + conflict.Branch},
+	}); err != nil {
+		style.PrintWarning("could not record Dolt conflict on %s: %v", agentBeadID, err)
+		return
+	}
+	var tables []string
+	for _, c := range conflict.Conflicts {
+		tables = append(tables, fmt.Sprintf("%s (%d)", c.Table, c.DataConflicts+c.SchemaConflicts))
+	}
+	comment := fmt.Sprintf("Dolt merge of %s into main conflicted in: %s. The merge was aborted; resolve with 'gt dolt conflicts %s %s'.",
+		conflict.Branch, strings.Join(tables, ", "), conflict.Database, conflict.Branch)
+	_, _ = bd.Run("comment", agentBeadID, comment)
+}
+
+// clearDoltConflictLabels removes the dolt-conflict label for branch from
+// the rig's beads once the branch has merged. Best-effort.
+func clearDoltConflictLabels(townRoot, rigName, branch string) {
+	bd := beads.New(filepath.Join(townRoot, rigName))
+	label := doltConflictLabelPrefix + branch
+	issues, err := bd.List(beads.ListOptions{Status: "all", Label: label, Priority: -1})
+	if err != nil {
+		return
+	}
+	for _, issue := range issues {
+		if err := bd.Update(issue.ID, beads.UpdateOptions{RemoveLabels: []string{label}}); err != nil {
+			style.PrintWarning("could not clear %s on %s: %v", label, issue.ID, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltDSNBranch   string
+	doltDSNReadOnly bool
+	doltDSNTLS      string
+	doltDSNTimeout  time.Duration
+)
+
+var doltDSNCmd = &cobra.Command{
+	Use:   "dsn [rig]",
+	Short: "Print a Go MySQL driver DSN for the town's Dolt server",
+	Long: `Print a go-sql-driver/mysql data source name for the town's Dolt server,
+for tools that connect to it directly.
+
+The DSN carries the options gt connects with (dial and I/O timeouts,
+multi-statement queries, parsed times). With a rig, that database is
+selected; --branch selects the rig database at a Dolt branch
+("<rig>/<branch>"), and --read-only makes every transaction read-only.
+
+Examples:
+  gt dolt dsn
+  gt dolt dsn gastown
+  gt dolt dsn gastown --branch polecat-toast-1712345678 --read-only
+  mysql-tool --dsn "$(gt dolt dsn gastown)"`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltDSN,
+}
+
+func init() {
+	doltDSNCmd.Flags().StringVar(&doltDSNBranch, "branch", "", "Select the rig database at this Dolt branch")
+	doltDSNCmd.Flags().BoolVar(&doltDSNReadOnly, "read-only", false, "Make every transaction read-only")
+	doltDSNCmd.Flags().StringVar(&doltDSNTLS, "tls", "", "TLS mode: true, skip-verify, or preferred (default: none)")
+	doltDSNCmd.Flags().DurationVar(&doltDSNTimeout, "timeout", 5*time.Second, "Dial timeout")
+	doltCmd.AddCommand(doltDSNCmd)
+}
+
+func runDoltDSN(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var database string
+	if len(args) == 1 {
+		if _, err := doltTargetDatabases(townRoot, args); err != nil {
+			return err
+		}
+		database = args[0]
+	} else if doltDSNBranch != "" {
+		return fmt.Errorf("--branch needs a rig: gt dolt dsn <rig> --branch %s", doltDSNBranch)
+	}
+
+	opts := doltserver.DefaultDSNOptions(database)
+	opts.Branch = doltDSNBranch
+	opts.ReadOnly = doltDSNReadOnly
+	opts.TLS = doltDSNTLS
+	opts.Timeout = doltDSNTimeout
+	dsn, err := doltserver.DSN(townRoot, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(dsn)
+	return nil
+}
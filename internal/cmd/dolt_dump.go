@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltDumpOut   string
+	doltDumpJSONL bool
+	doltDumpForce bool
+	doltDumpJSON  bool
+
+	doltLoadReplace bool
+	doltLoadJSON    bool
+)
+
+var doltDumpExportCmd = &cobra.Command{
+	Use:   "export <rig>",
+	Short: "Dump a rig's database to a SQL file",
+	Long: `Dump one rig's database, schema and data, to a SQL file with dolt dump,
+to archive the rig or move its beads to another town with 'gt dolt import'.
+
+The dump has no CREATE DATABASE or USE statement, so it loads into a rig
+of any name. Dolt history is not included; use 'gt dolt backup' or a Dolt
+remote to keep it.
+
+With --jsonl, only the issues table is exported, as bd's JSONL format.
+
+Examples:
+  gt dolt export gastown                        # Writes gastown.sql
+  gt dolt export gastown --out /archive/gastown-2026-10.sql
+  gt dolt export gastown --jsonl --out issues.jsonl`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltDumpExport,
+}
+
+var doltDumpImportCmd = &cobra.Command{
+	Use:   "import <rig> <file>",
+	Short: "Load a rig's database from a 'gt dolt export' dump",
+	Long: `Load a dump written by 'gt dolt export' into a rig's database and
+commit it.
+
+A SQL dump needs a database without tables. If the rig has no database
+yet, one is created. If it has tables (for example, a rig just added with
+'gt rig add'), use --replace: the database is backed up to
+exports/pruned-databases/ as for 'gt dolt drop-rig', its tables are dropped,
+and the dump is loaded in their place.
+
+A .jsonl file is handed to 'bd import', which merges the issues into the
+rig's existing beads by ID.
+
+Examples:
+  gt dolt import gastown gastown.sql
+  gt dolt import gastown gastown.sql --replace
+  gt dolt import gastown issues.jsonl`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runDoltDumpImport,
+}
+
+func init() {
+	doltDumpExportCmd.Flags().StringVarP(&doltDumpOut, "out", "o", "", "Output file (default: <rig>.sql, or <rig>.jsonl with --jsonl)")
+	doltDumpExportCmd.Flags().BoolVar(&doltDumpJSONL, "jsonl", false, "Export only the issues table as JSONL")
+	doltDumpExportCmd.Flags().BoolVarP(&doltDumpForce, "force", "f", false, "Overwrite an existing output file")
+	doltDumpExportCmd.Flags().BoolVar(&doltDumpJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltDumpExportCmd)
+
+	doltDumpImportCmd.Flags().BoolVar(&doltLoadReplace, "replace", false, "Back up and replace the rig's existing tables")
+	doltDumpImportCmd.Flags().BoolVar(&doltLoadJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltDumpImportCmd)
+}
+
+func runDoltDumpExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := args[0]
+	if _, err := doltTargetDatabases(townRoot, args); err != nil {
+		return err
+	}
+
+	format := doltserver.DumpFormatSQL
+	if doltDumpJSONL {
+		format = doltserver.DumpFormatJSONL
+	}
+	out := doltDumpOut
+	if out == "" {
+		out = rigName + "." + format
+	}
+	result, err := doltserver.ExportRigDump(townRoot, rigName, out, format, doltDumpForce)
+	if err != nil {
+		return err
+	}
+
+	if doltDumpJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	fmt.Printf("%s Exported %s (%s) → %s\n", style.Success.Render("✓"), rigName, formatBytes(result.Bytes), result.Path)
+	return nil
+}
+
+func runDoltDumpImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName, path := args[0], args[1]
+
+	result, err := doltserver.ImportRigDump(townRoot, rigName, path, doltLoadReplace)
+	if err != nil {
+		if result != nil && result.Backup != "" {
+			fmt.Fprintf(os.Stderr, "%s Backup of the previous database: %s\n", style.Warning.Render("⚠"), result.Backup)
+		}
+		return err
+	}
+
+	if doltLoadJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	if result.Created {
+		fmt.Printf("%s Created database %s\n", style.Success.Render("✓"), rigName)
+	}
+	if result.Backup != "" {
+		fmt.Printf("%s Backed up previous tables → %s\n", style.Success.Render("✓"), result.Backup)
+	}
+	fmt.Printf("%s Imported %s (%s) into %s\n", style.Success.Render("✓"), result.Path, formatBytes(result.Bytes), rigName)
+	return nil
+}
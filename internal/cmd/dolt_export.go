@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltExportRigs        []string
+	doltExportTables      []string
+	doltExportFormat      string
+	doltExportOut         string
+	doltExportIncremental bool
+	doltExportJSON        bool
+)
+
+var doltExportParquetCmd = &cobra.Command{
+	Use:   "export-parquet",
+	Short: "Export bead history to Parquet or CSV for analytics",
+	Long: `Export bead tables from each rig database to files for loading into a
+data warehouse (DuckDB, BigQuery, ...).
+
+Tables:
+  issues  Persistent issues
+  wisps   Ephemeral issues (molecule steps, patrols)
+  audit   The beads events table
+
+Each run writes one file per table and rig, named by export time:
+
+  <out>/<rig>/<table>/<table>-20260301T090000Z.parquet
+
+so a whole history can be read with a glob, e.g. in DuckDB:
+
+  SELECT * FROM read_parquet('exports/analytics/*/issues/*.parquet');
+
+With --incremental, only rows changed since the last export to the same
+directory are written (by updated_at, or created_at for audit). The export
+watermarks are kept in <out>/.watermarks.json. A row updated between runs
+is exported again, so keep the latest row per id when loading.
+
+The daemon runs incremental exports on a schedule when the analytics_export
+patrol is enabled in mayor/daemon.json.
+
+Examples:
+  gt dolt export-parquet                           # All tables, all rigs
+  gt dolt export-parquet --incremental             # Only what changed
+  gt dolt export-parquet --rig gastown --table issues --format csv
+  gt dolt export-parquet --out /data/gastown`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltExportParquet,
+}
+
+func init() {
+	doltExportParquetCmd.Flags().StringSliceVar(&doltExportRigs, "rig", nil, "Rig database(s) to export (default: all)")
+	doltExportParquetCmd.Flags().StringSliceVar(&doltExportTables, "table", doltserver.AnalyticsTables, "Tables to export (issues, wisps, audit)")
+	doltExportParquetCmd.Flags().StringVar(&doltExportFormat, "format", doltserver.AnalyticsFormatParquet, "Output format (parquet, csv)")
+	doltExportParquetCmd.Flags().StringVar(&doltExportOut, "out", "", "Export directory (default: <town>/exports/analytics)")
+	doltExportParquetCmd.Flags().BoolVar(&doltExportIncremental, "incremental", false, "Only export rows changed since the last export")
+	doltExportParquetCmd.Flags().BoolVar(&doltExportJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltExportParquetCmd)
+}
+
+func runDoltExportParquet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltExportRigs)
+	if err != nil {
+		return err
+	}
+
+	out := doltExportOut
+	if out != "" && !filepath.IsAbs(out) {
+		if out, err = filepath.Abs(out); err != nil {
+			return err
+		}
+	}
+	opts := doltserver.AnalyticsExportOptions{
+		OutDir:      out,
+		Tables:      doltExportTables,
+		Format:      doltExportFormat,
+		Incremental: doltExportIncremental,
+	}
+
+	var results []doltserver.AnalyticsExportResult
+	var failed int
+	for _, db := range databases {
+		res, err := doltserver.ExportAnalytics(townRoot, db, opts)
+		results = append(results, res...)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.Error.Render("✗"), db, err)
+		}
+	}
+
+	if doltExportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printAnalyticsExport(results)
+	}
+	if failed > 0 {
+		return fmt.Errorf("export failed for %d of %d database(s)", failed, len(databases))
+	}
+	return nil
+}
+
+func printAnalyticsExport(results []doltserver.AnalyticsExportResult) {
+	var rows int64
+	for _, r := range results {
+		if r.Path == "" {
+			fmt.Printf("%s %s/%s: nothing new\n", style.Dim.Render("○"), r.Database, r.Table)
+			continue
+		}
+		rows += r.Rows
+		fmt.Printf("%s %s/%s: %d row(s) → %s\n", style.Success.Render("✓"), r.Database, r.Table, r.Rows, r.Path)
+	}
+	fmt.Printf("\nExported %d row(s)\n", rows)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltBranchDiffRig     string
+	doltBranchDiffBranch  string
+	doltBranchDiffPolecat string
+	doltBranchDiffOut     string
+)
+
+var doltExportBranchDiffCmd = &cobra.Command{
+	Use:   "export-branch-diff",
+	Short: "Export the beads a Dolt branch changed as JSONL for review",
+	Long: `Export the beads a Dolt branch created, modified, or removed relative to
+main, one JSON object per line, so reviewers can see a polecat's bead
+changes without a SQL shell and attach them to the PR.
+
+Each line holds the bead ID, the change (added, modified, removed), the
+changed fields, and the bead before (at the branch's merge base with main)
+and after (on the branch). Writes the branch has not committed yet are
+included.
+
+Give the branch with --branch, or --polecat for the polecat's newest
+branch. Without --out the JSONL goes to stdout.
+
+Examples:
+  gt dolt export-branch-diff --rig gastown --branch polecat-nux-1773100000 --out diff.jsonl
+  gt dolt export-branch-diff --rig gastown --polecat nux | jq .id`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltExportBranchDiff,
+}
+
+func init() {
+	doltExportBranchDiffCmd.Flags().StringVar(&doltBranchDiffRig, "rig", "", "Rig database the branch is in (required)")
+	doltExportBranchDiffCmd.Flags().StringVar(&doltBranchDiffBranch, "branch", "", "Dolt branch to diff against main")
+	doltExportBranchDiffCmd.Flags().StringVar(&doltBranchDiffPolecat, "polecat", "", "Diff this polecat's newest branch")
+	doltExportBranchDiffCmd.Flags().StringVar(&doltBranchDiffOut, "out", "", "File to write (default: stdout)")
+	_ = doltExportBranchDiffCmd.MarkFlagRequired("rig")
+	doltCmd.AddCommand(doltExportBranchDiffCmd)
+}
+
+func runDoltExportBranchDiff(cmd *cobra.Command, args []string) error {
+	if (doltBranchDiffBranch == "") == (doltBranchDiffPolecat == "") {
+		return fmt.Errorf("give exactly one of --branch or --polecat")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !doltserver.DatabaseExists(townRoot, doltBranchDiffRig) {
+		return fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", doltBranchDiffRig)
+	}
+
+	branch := doltBranchDiffBranch
+	if doltBranchDiffPolecat != "" {
+		if branch, err = doltserver.FindPolecatBranch(townRoot, doltBranchDiffRig, doltBranchDiffPolecat); err != nil {
+			return fmt.Errorf("finding branch of %s: %w", doltBranchDiffPolecat, err)
+		}
+		if branch == "" {
+			return fmt.Errorf("polecat %s has no Dolt branch in %s", doltBranchDiffPolecat, doltBranchDiffRig)
+		}
+	}
+
+	if doltBranchDiffOut == "" || doltBranchDiffOut == "-" {
+		_, err := doltserver.ExportBranchDiff(townRoot, doltBranchDiffRig, branch, os.Stdout)
+		return err
+	}
+
+	var buf bytes.Buffer
+	n, err := doltserver.ExportBranchDiff(townRoot, doltBranchDiffRig, branch, &buf)
+	if err != nil {
+		return err
+	}
+	if err := util.AtomicWriteFile(doltBranchDiffOut, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", doltBranchDiffOut, err)
+	}
+	fmt.Printf("%s Wrote %d bead change(s) on %s/%s to %s\n", style.Success.Render("✓"), n, doltBranchDiffRig, branch, doltBranchDiffOut)
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltGCRigs []string
+	doltGCJSON bool
+)
+
+var doltGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Garbage-collect rig databases to reclaim disk space",
+	Long: `Run Dolt garbage collection on each rig database and report how much
+disk space it reclaimed.
+
+Dolt keeps every chunk a database ever wrote, so .dolt-data grows without
+bound under the write load of wisps and polecat branches. GC removes the
+chunks no branch or commit references any more.
+
+With the server running, GC runs through CALL DOLT_GC(), which closes the
+server's open connections when it finishes; agents reconnect on their
+next query. Without a server it runs 'dolt gc' in each database.
+
+The dolt_gc daemon patrol runs this when .dolt-data grows past a size
+threshold, when enabled in mayor/daemon.json:
+
+  "dolt_gc": {"enabled": true, "threshold_gb": 10}
+
+Examples:
+  gt dolt gc
+  gt dolt gc --rig gastown
+  gt dolt gc --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltGC,
+}
+
+func init() {
+	doltGCCmd.Flags().StringSliceVar(&doltGCRigs, "rig", nil, "Rig database(s) to collect (default: all)")
+	doltGCCmd.Flags().BoolVar(&doltGCJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltGCCmd)
+}
+
+func runDoltGC(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltGCRigs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]doltserver.GCResult, 0, len(databases))
+	for _, db := range databases {
+		if !doltGCJSON {
+			fmt.Printf("Collecting %s...\n", db)
+		}
+		results = append(results, doltserver.GCDatabase(townRoot, db))
+	}
+
+	if doltGCJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		printDoltGCResults(os.Stdout, results)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("gc failed for %d database(s)", failed)
+	}
+	return nil
+}
+
+// printDoltGCResults writes a before/after table with a total line.
+func printDoltGCResults(w io.Writer, results []doltserver.GCResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATABASE\tBEFORE\tAFTER\tRECLAIMED\tTIME")
+	var before, after int64
+	for _, r := range results {
+		before += r.BeforeBytes
+		after += r.AfterBytes
+		if r.Error != "" {
+			fmt.Fprintf(tw, "%s\t%s\t\t%s\t\n", r.Database, formatBytes(r.BeforeBytes), style.Error.Render("✗ "+r.Error))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%v\n", r.Database, formatBytes(r.BeforeBytes), formatBytes(r.AfterBytes),
+			formatBytes(max(r.Reclaimed(), 0)), r.Duration.Round(time.Millisecond))
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%s %s → %s (reclaimed %s)\n", style.Bold.Render("Total:"),
+		formatBytes(before), formatBytes(after), formatBytes(max(before-after, 0)))
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestPrintDoltGCResults(t *testing.T) {
+	var buf bytes.Buffer
+	printDoltGCResults(&buf, []doltserver.GCResult{
+		{Database: "gastown", BeforeBytes: 3 << 20, AfterBytes: 1 << 20},
+		{Database: "beads", BeforeBytes: 1 << 20, AfterBytes: 1 << 20, Error: "gc beads: database is locked"},
+	})
+	out := buf.String()
+	for _, want := range []string{"gastown", "3.0 MB", "2.0 MB", "database is locked", "Total:", "4.0 MB → 2.0 MB (reclaimed 2.0 MB)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
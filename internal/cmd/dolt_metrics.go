@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltMetricsSince   time.Duration
+	doltMetricsBuckets int
+	doltMetricsJSON    bool
+)
+
+var doltMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show Dolt server latency, connection, and disk trends",
+	Long: `Show how the Dolt server's query latency, connection count, and disk
+usage have trended, from the health history the daemon samples.
+
+gt dolt status shows the server's health right now; this shows whether it
+is drifting toward its limits, so you can plan capacity before slinging a
+large batch of polecats. The window is split into --buckets intervals to
+show the trend.
+
+Sampling is done by the dolt_metrics daemon patrol, when enabled in
+mayor/daemon.json:
+
+  "dolt_metrics": {"enabled": true, "retention_days": 14}
+
+Samples are taken every 5 minutes and kept in daemon/dolt-metrics.jsonl.
+
+Examples:
+  gt dolt metrics                # Last 24h
+  gt dolt metrics --since 168h   # Last week
+  gt dolt metrics --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltMetrics,
+}
+
+func init() {
+	doltMetricsCmd.Flags().DurationVar(&doltMetricsSince, "since", 24*time.Hour, "Show trends over this long")
+	doltMetricsCmd.Flags().IntVar(&doltMetricsBuckets, "buckets", 12, "Number of intervals to split the window into")
+	doltMetricsCmd.Flags().BoolVar(&doltMetricsJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltMetricsCmd)
+}
+
+// DoltMetricsReport is the output of gt dolt metrics.
+type DoltMetricsReport struct {
+	Since   time.Time                   `json:"since"`
+	Summary *doltserver.MetricsSummary  `json:"summary,omitempty"`
+	Trend   []doltserver.MetricsSummary `json:"trend"`
+}
+
+func runDoltMetrics(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if doltMetricsSince <= 0 {
+		return fmt.Errorf("--since must be positive, got %v", doltMetricsSince)
+	}
+
+	since := time.Now().Add(-doltMetricsSince)
+	samples, err := doltserver.LoadMetricsHistory(townRoot, since)
+	if err != nil {
+		return fmt.Errorf("reading metrics history: %w", err)
+	}
+	report := DoltMetricsReport{
+		Since:   since,
+		Summary: doltserver.SummarizeMetrics(samples),
+		Trend:   doltserver.BucketMetrics(samples, doltMetricsBuckets),
+	}
+	if report.Trend == nil {
+		report.Trend = []doltserver.MetricsSummary{}
+	}
+
+	if doltMetricsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printDoltMetrics(report)
+	return nil
+}
+
+func printDoltMetrics(report DoltMetricsReport) {
+	sum := report.Summary
+	if sum == nil {
+		fmt.Printf("%s No Dolt metrics samples since %s\n", style.Dim.Render("○"), report.Since.Format("Jan 2 15:04"))
+		fmt.Printf("  %s\n", style.Dim.Render(`Enable sampling in mayor/daemon.json: "dolt_metrics": {"enabled": true}`))
+		return
+	}
+
+	fmt.Printf("%s %s – %s (%d samples)\n\n", style.Bold.Render("Dolt metrics"),
+		sum.From.Format("Jan 2 15:04"), sum.To.Format("Jan 2 15:04"), sum.Samples)
+	if sum.Samples > sum.Down {
+		fmt.Printf("  Latency      avg %s  p95 %s  max %s\n",
+			formatLatencyMs(sum.LatencyAvgMs), formatLatencyMs(sum.LatencyP95Ms), formatLatencyMs(sum.LatencyMaxMs))
+		fmt.Printf("  Connections  avg %.1f  max %d of %d (%.0f%%)\n",
+			sum.ConnectionsAvg, sum.ConnectionsMax, sum.MaxConnections, connectionPct(sum.ConnectionsMax, sum.MaxConnections))
+		disk := fmt.Sprintf("%s → %s", formatBytes(sum.DiskFirstBytes), formatBytes(sum.DiskLastBytes))
+		if sum.DiskGrowthPerDay != 0 {
+			disk += fmt.Sprintf(" (%s%s/day)", signOf(sum.DiskGrowthPerDay), formatBytes(absInt64(int64(sum.DiskGrowthPerDay))))
+		}
+		fmt.Printf("  Disk         %s\n", disk)
+	}
+	health := style.Success.Render("✓ healthy throughout")
+	if sum.Unhealthy > 0 || sum.Down > 0 {
+		health = style.Warning.Render(fmt.Sprintf("⚠ %d unhealthy, %d down", sum.Unhealthy, sum.Down))
+	}
+	fmt.Printf("  Health       %s\n", health)
+
+	if len(report.Trend) < 2 {
+		return
+	}
+	fmt.Printf("\n  %-14s %10s %10s %8s %10s  %s\n", "FROM", "LAT AVG", "LAT MAX", "CONNS", "DISK", "")
+	for _, b := range report.Trend {
+		if b.Samples == b.Down {
+			fmt.Printf("  %-14s %s\n", b.From.Format("Jan 2 15:04"), style.Error.Render("server down"))
+			continue
+		}
+		note := ""
+		if b.Down > 0 || b.Unhealthy > 0 {
+			note = style.Warning.Render(fmt.Sprintf("%d unhealthy, %d down", b.Unhealthy, b.Down))
+		}
+		fmt.Printf("  %-14s %10s %10s %8d %10s  %s\n", b.From.Format("Jan 2 15:04"),
+			formatLatencyMs(b.LatencyAvgMs), formatLatencyMs(b.LatencyMaxMs), b.ConnectionsMax, formatBytes(b.DiskLastBytes), note)
+	}
+}
+
+func formatLatencyMs(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+func connectionPct(n, max int) float64 {
+	if max <= 0 {
+		return 0
+	}
+	return float64(n) / float64(max) * 100
+}
+
+func signOf(f float64) string {
+	if f < 0 {
+		return "-"
+	}
+	return "+"
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
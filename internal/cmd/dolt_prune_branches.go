@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltPruneBranchesRigs      []string
+	doltPruneBranchesOlderThan time.Duration
+	doltPruneBranchesDry       bool
+	doltPruneBranchesJSON      bool
+)
+
+var doltPruneBranchesCmd = &cobra.Command{
+	Use:   "prune-branches",
+	Short: "Delete Dolt branches left behind by dead polecats",
+	Long: `Delete polecat Dolt branches whose polecat is gone.
+
+Merging and deleting a polecat's Dolt branch are best-effort, so branches
+of polecats that crashed or were nuked pile up. Each polecat branch older
+than the prune age whose polecat has no running session is classified:
+
+  merged     The branch has no bead changes main lacks.
+  orphaned   The branch has bead changes, and none of the beads it touched
+             are closed on main.
+  stranded   The branch has bead changes for beads closed on main: its
+             work merged in git but its Dolt branch did not.
+
+Merged and orphaned branches are deleted. Stranded branches are kept;
+merge them with 'gt dolt reconcile-branches --merge' first.
+
+The age is taken from the timestamp in the branch name. It defaults to
+dolt_stats.retention.branch_days in settings/config.json (7 days).
+
+The branch_prune daemon patrol runs this daily when enabled in
+mayor/daemon.json.
+
+Examples:
+  gt dolt prune-branches --dry-run
+  gt dolt prune-branches --rig gastown --older-than 48h
+  gt dolt prune-branches --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltPruneBranches,
+}
+
+func init() {
+	doltPruneBranchesCmd.Flags().StringSliceVar(&doltPruneBranchesRigs, "rig", nil, "Rig database(s) to prune (default: all)")
+	doltPruneBranchesCmd.Flags().DurationVar(&doltPruneBranchesOlderThan, "older-than", 0, "Only prune branches older than this (default: branch_days setting, 7 days)")
+	doltPruneBranchesCmd.Flags().BoolVar(&doltPruneBranchesDry, "dry-run", false, "Show what would be pruned without changing anything")
+	doltPruneBranchesCmd.Flags().BoolVar(&doltPruneBranchesJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltPruneBranchesCmd)
+}
+
+// branchMerged is the prune state of a polecat branch with no bead changes
+// main lacks. The other states are shared with reconcile-branches.
+const branchMerged = "merged"
+
+// PrunableBranch is a dead polecat's Dolt branch old enough to prune.
+type PrunableBranch struct {
+	Database string    `json:"database"`
+	Branch   string    `json:"branch"`
+	Polecat  string    `json:"polecat"`
+	State    string    `json:"state"`
+	Created  time.Time `json:"created"`
+	Changes  int       `json:"changes"`
+	Deleted  bool      `json:"deleted,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// branchPruneSkips counts polecat branches left alone, by reason.
+type branchPruneSkips struct {
+	Recent   int
+	Active   int
+	Stranded int
+}
+
+func runDoltPruneBranches(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltPruneBranchesRigs)
+	if err != nil {
+		return err
+	}
+
+	olderThan := doltPruneBranchesOlderThan
+	if olderThan <= 0 {
+		statsConfig, err := loadDoltStatsConfig(townRoot)
+		if err != nil {
+			return err
+		}
+		olderThan = time.Duration(doltserver.BranchPruneAfterDays(statsConfig)) * 24 * time.Hour
+	}
+	cutoff := time.Now().Add(-olderThan)
+
+	found := []*PrunableBranch{}
+	var skips branchPruneSkips
+	for _, db := range databases {
+		bs, err := findPrunableBranches(townRoot, db, cutoff, &skips)
+		if err != nil {
+			style.PrintWarning("%s: %v", db, err)
+			continue
+		}
+		found = append(found, bs...)
+	}
+
+	if !doltPruneBranchesDry {
+		for _, b := range found {
+			if err := doltserver.DropPolecatBranch(townRoot, b.Database, b.Branch); err != nil {
+				b.Error = err.Error()
+				continue
+			}
+			b.Deleted = true
+		}
+	}
+
+	if doltPruneBranchesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(found); err != nil {
+			return err
+		}
+	} else {
+		printPrunableBranches(found, skips, olderThan)
+	}
+
+	var failed int
+	for _, b := range found {
+		if b.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to prune %d branch(es)", failed)
+	}
+	return nil
+}
+
+// findPrunableBranches returns the polecat branches in db created before
+// cutoff whose polecat is gone and that are merged or orphaned. Branches
+// left alone are counted in skips.
+func findPrunableBranches(townRoot, db string, cutoff time.Time, skips *branchPruneSkips) ([]*PrunableBranch, error) {
+	branches, err := doltserver.ListPolecatBranches(townRoot, db)
+	if err != nil {
+		return nil, fmt.Errorf("listing polecat branches: %w", err)
+	}
+	bd := beads.New(filepath.Join(townRoot, db))
+
+	var out []*PrunableBranch
+	for _, branch := range branches {
+		created, _ := doltserver.PolecatBranchCreated(branch)
+		if !created.Before(cutoff) {
+			skips.Recent++
+			continue
+		}
+		name, alive := findBranchPolecat(townRoot, db, doltserver.PolecatNameFromBranch(branch))
+		if alive {
+			skips.Active++
+			continue
+		}
+		changes, err := doltserver.BeadChangesOnBranch(townRoot, db, branch)
+		if err != nil {
+			return nil, err
+		}
+		closedOnMain := false
+		for _, c := range changes {
+			if issue, err := bd.Show(c.BeadID); err == nil && issue.Status == "closed" {
+				closedOnMain = true
+				break
+			}
+		}
+		state := classifyDeadPolecatBranch(len(changes), closedOnMain)
+		if state == branchStranded {
+			skips.Stranded++
+			continue
+		}
+		out = append(out, &PrunableBranch{
+			Database: db,
+			Branch:   branch,
+			Polecat:  name,
+			State:    state,
+			Created:  created,
+			Changes:  len(changes),
+		})
+	}
+	return out, nil
+}
+
+// classifyDeadPolecatBranch returns the prune state of a branch whose
+// polecat is gone.
+func classifyDeadPolecatBranch(changes int, workClosedOnMain bool) string {
+	if changes == 0 {
+		return branchMerged
+	}
+	return classifyPolecatBranch(false, workClosedOnMain)
+}
+
+func printPrunableBranches(found []*PrunableBranch, skips branchPruneSkips, olderThan time.Duration) {
+	if len(found) == 0 {
+		fmt.Printf("%s No dead polecat branches older than %s\n", style.Success.Render("✓"), formatDays(olderThan))
+	}
+	for _, b := range found {
+		detail := fmt.Sprintf("%s, polecat %s, created %s", b.State, b.Polecat, b.Created.Format("2006-01-02"))
+		if b.Changes > 0 {
+			detail += fmt.Sprintf(", %d bead change(s) dropped", b.Changes)
+		}
+		switch {
+		case b.Deleted:
+			fmt.Printf("%s Deleted %s/%s  %s\n", style.Success.Render("✓"), b.Database, b.Branch, style.Dim.Render(detail))
+		case b.Error != "":
+			fmt.Printf("%s %s/%s: %s\n", style.Error.Render("✗"), b.Database, b.Branch, b.Error)
+		default:
+			fmt.Printf("%s Would delete %s/%s  %s\n", style.Warning.Render("⚠"), b.Database, b.Branch, style.Dim.Render(detail))
+		}
+	}
+
+	if skips.Recent > 0 {
+		fmt.Printf("%s %d branch(es) newer than %s kept\n", style.Dim.Render("○"), skips.Recent, formatDays(olderThan))
+	}
+	if skips.Active > 0 {
+		fmt.Printf("%s %d branch(es) in use by running polecats kept\n", style.Dim.Render("○"), skips.Active)
+	}
+	if skips.Stranded > 0 {
+		fmt.Printf("%s %d stranded branch(es) kept; run %s first\n", style.Warning.Render("⚠"), skips.Stranded,
+			style.Bold.Render("gt dolt reconcile-branches --merge"))
+	}
+}
+
+// formatDays renders d in days when it is a whole number of days.
+func formatDays(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
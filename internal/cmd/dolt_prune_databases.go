@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltPruneDatabasesDry  bool
+	doltPruneDatabasesJSON bool
+)
+
+var doltPruneDatabasesCmd = &cobra.Command{
+	Use:   "prune-databases",
+	Short: "Back up and remove databases of deleted rigs",
+	Long: `Remove databases in .dolt-data/ whose rig no longer exists.
+
+Removing a rig leaves its database behind, and the Dolt server keeps
+serving it. A database is pruned when no rig in mayor/rigs.json has its
+name and no rig's metadata.json points at it. The town database (hq) is
+never pruned.
+
+Before dropping a database, a final backup of its directory (full history)
+is written to exports/pruned-databases/<db>-<timestamp>.tar.gz. A database
+whose backup fails is kept. To restore one, extract the tarball into
+.dolt-data/ and restart the server.
+
+'gt doctor' warns about these databases until they are pruned. For other
+unreferenced databases (partial setups, old naming), see 'gt dolt cleanup'.
+
+Examples:
+  gt dolt prune-databases --dry-run
+  gt dolt prune-databases
+  gt dolt prune-databases --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltPruneDatabases,
+}
+
+func init() {
+	doltPruneDatabasesCmd.Flags().BoolVar(&doltPruneDatabasesDry, "dry-run", false, "Show what would be pruned without changing anything")
+	doltPruneDatabasesCmd.Flags().BoolVar(&doltPruneDatabasesJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltPruneDatabasesCmd)
+}
+
+func runDoltPruneDatabases(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pruned, err := doltserver.PruneDeletedRigDatabases(townRoot, doltPruneDatabasesDry)
+	if err != nil {
+		return err
+	}
+
+	if doltPruneDatabasesJSON {
+		if pruned == nil {
+			pruned = []doltserver.DeletedRigDatabase{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(pruned); err != nil {
+			return err
+		}
+	} else if len(pruned) == 0 {
+		fmt.Printf("%s No databases of deleted rigs in .dolt-data/\n", style.Bold.Render("✓"))
+		return nil
+	}
+
+	var failed int
+	for _, db := range pruned {
+		if db.Error != "" {
+			failed++
+		}
+		if doltPruneDatabasesJSON {
+			continue
+		}
+		switch {
+		case db.Removed:
+			fmt.Printf("%s Pruned %s (%s)\n", style.Success.Render("✓"), db.Name, formatBytes(db.SizeBytes))
+			fmt.Printf("    %s\n", style.Dim.Render("backup: "+db.Backup))
+		case db.Error != "":
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), db.Name, db.Error)
+		default:
+			fmt.Printf("%s Would prune %s (%s)\n", style.Warning.Render("⚠"), db.Name, formatBytes(db.SizeBytes))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to prune %d database(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltReconcileRigs     []string
+	doltReconcileMerge    bool
+	doltReconcileOrphaned bool
+	doltReconcileJSON     bool
+)
+
+var doltReconcileCmd = &cobra.Command{
+	Use:   "reconcile-branches",
+	Short: "Find polecat Dolt branches whose bead updates never reached main",
+	Long: `Find polecat Dolt branches with bead changes that never reached main.
+
+gt done merges a polecat's Dolt branch into main, but a failed merge only
+warns, so the polecat's git work can land while its bead updates (status
+changes, MR beads, new issues) stay stranded on the branch.
+
+Each polecat branch with bead changes relative to main is classified:
+
+  stranded   The polecat is gone and beads it touched are closed on main:
+             its work merged in git but its Dolt branch did not.
+  orphaned   The polecat is gone and none of its beads are closed on main.
+
+Branches of polecats with a running session are in use and skipped.
+
+With --merge, stranded branches are merged into main again while holding
+the rig's merge slot, so the merge doesn't race the refinery. Add
+--include-orphaned to merge orphaned branches too.
+
+Examples:
+  gt dolt reconcile-branches
+  gt dolt reconcile-branches --rig gastown --json
+  gt dolt reconcile-branches --merge`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltReconcile,
+}
+
+func init() {
+	doltReconcileCmd.Flags().StringSliceVar(&doltReconcileRigs, "rig", nil, "Rig database(s) to check (default: all)")
+	doltReconcileCmd.Flags().BoolVar(&doltReconcileMerge, "merge", false, "Merge stranded branches into main under the merge slot")
+	doltReconcileCmd.Flags().BoolVar(&doltReconcileOrphaned, "include-orphaned", false, "With --merge, also merge orphaned branches")
+	doltReconcileCmd.Flags().BoolVar(&doltReconcileJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltReconcileCmd)
+}
+
+// Branch reconcile states.
+const (
+	branchStranded = "stranded"
+	branchOrphaned = "orphaned"
+	branchActive   = "active"
+)
+
+// BranchDiscrepancy is a polecat Dolt branch with bead changes main lacks.
+type BranchDiscrepancy struct {
+	Database     string                  `json:"database"`
+	Branch       string                  `json:"branch"`
+	Polecat      string                  `json:"polecat"`
+	State        string                  `json:"state"`
+	Changes      []doltserver.BeadChange `json:"changes"`
+	ClosedOnMain []string                `json:"closed_on_main,omitempty"`
+	Merged       bool                    `json:"merged,omitempty"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+func runDoltReconcile(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltReconcileRigs)
+	if err != nil {
+		return err
+	}
+
+	found := []*BranchDiscrepancy{}
+	active := 0
+	for _, db := range databases {
+		ds, err := findBranchDiscrepancies(townRoot, db)
+		if err != nil {
+			style.PrintWarning("%s: %v", db, err)
+			continue
+		}
+		for _, d := range ds {
+			if d.State == branchActive {
+				active++
+				continue
+			}
+			found = append(found, d)
+		}
+	}
+
+	if doltReconcileMerge {
+		for _, d := range found {
+			if d.State == branchStranded || doltReconcileOrphaned {
+				mergeUnderSlot(townRoot, d)
+			}
+		}
+	}
+
+	if doltReconcileJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(found)
+	}
+	printBranchDiscrepancies(found, active)
+	for _, d := range found {
+		if d.Error != "" {
+			return NewSilentExit(1)
+		}
+	}
+	return nil
+}
+
+// findBranchDiscrepancies classifies every polecat branch in db that has
+// bead changes relative to main.
+func findBranchDiscrepancies(townRoot, db string) ([]*BranchDiscrepancy, error) {
+	branches, err := doltserver.ListPolecatBranches(townRoot, db)
+	if err != nil {
+		return nil, fmt.Errorf("listing polecat branches: %w", err)
+	}
+	rigPath := filepath.Join(townRoot, db)
+	bd := beads.New(rigPath)
+
+	var out []*BranchDiscrepancy
+	for _, branch := range branches {
+		changes, err := doltserver.BeadChangesOnBranch(townRoot, db, branch)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		name, alive := findBranchPolecat(townRoot, db, doltserver.PolecatNameFromBranch(branch))
+		d := &BranchDiscrepancy{Database: db, Branch: branch, Polecat: name, Changes: changes}
+		if !alive {
+			for _, c := range changes {
+				if issue, err := bd.Show(c.BeadID); err == nil && issue.Status == "closed" {
+					d.ClosedOnMain = append(d.ClosedOnMain, c.BeadID)
+				}
+			}
+		}
+		d.State = classifyPolecatBranch(alive, len(d.ClosedOnMain) > 0)
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// classifyPolecatBranch returns the reconcile state of a polecat branch
+// with bead changes main lacks.
+func classifyPolecatBranch(polecatAlive, workClosedOnMain bool) string {
+	switch {
+	case polecatAlive:
+		return branchActive
+	case workClosedOnMain:
+		return branchStranded
+	default:
+		return branchOrphaned
+	}
+}
+
+// findBranchPolecat matches the lower-cased polecat name from a branch to
+// the rig's polecat directory, returning the polecat's name as spelled on
+// disk and whether its session is running. A polecat whose session state
+// can't be determined counts as running, so its branch is left alone.
+func findBranchPolecat(townRoot, rigName, lowerName string) (string, bool) {
+	entries, err := os.ReadDir(filepath.Join(townRoot, rigName, "polecats"))
+	if err != nil {
+		return lowerName, false
+	}
+	for _, e := range entries {
+		if e.IsDir() && strings.EqualFold(e.Name(), lowerName) {
+			return e.Name(), !isHookedAgentDead(rigName + "/polecats/" + e.Name())
+		}
+	}
+	return lowerName, false
+}
+
+// mergeUnderSlot merges a branch into main while holding the rig's merge
+// slot, recording the outcome on d.
+func mergeUnderSlot(townRoot string, d *BranchDiscrepancy) {
+	err := withMergeSlot(townRoot, d.Database, "gt-reconcile-"+d.Branch, func() error {
+		return doltserver.MergePolecatBranch(townRoot, d.Database, d.Branch)
+	})
+	if err != nil {
+		d.Error = err.Error()
+		return
+	}
+	d.Merged = true
+}
+
+// withMergeSlot runs merge while holder holds the rig's merge slot, so it
+// doesn't race the refinery.
+func withMergeSlot(townRoot, rigName, holder string, merge func() error) error {
+	bd := beads.New(filepath.Join(townRoot, rigName))
+	if _, err := bd.MergeSlotEnsureExists(); err != nil {
+		return fmt.Errorf("merge slot: %v", err)
+	}
+	status, err := bd.MergeSlotAcquire(holder, false)
+	if err != nil {
+		return fmt.Errorf("acquiring merge slot: %v", err)
+	}
+	if !status.Available && status.Holder != holder {
+		return fmt.Errorf("merge slot held by %s; try again later", status.Holder)
+	}
+	defer func() {
+		if err := bd.MergeSlotRelease(holder); err != nil {
+			style.PrintWarning("releasing merge slot: %v", err)
+		}
+	}()
+	return merge()
+}
+
+func printBranchDiscrepancies(found []*BranchDiscrepancy, active int) {
+	if len(found) == 0 {
+		fmt.Printf("%s No stranded polecat branches", style.Success.Render("✓"))
+		if active > 0 {
+			fmt.Printf(" (%d in use by running polecats)", active)
+		}
+		fmt.Println()
+		return
+	}
+
+	for _, d := range found {
+		icon := style.Warning.Render("⚠")
+		switch {
+		case d.Merged:
+			icon = style.Success.Render("✓")
+		case d.Error != "":
+			icon = style.Error.Render("✗")
+		}
+		fmt.Printf("%s %s/%s  %s  polecat %s, %d bead change(s)\n",
+			icon, d.Database, d.Branch, d.State, d.Polecat, len(d.Changes))
+		if len(d.ClosedOnMain) > 0 {
+			fmt.Printf("    closed on main: %s\n", strings.Join(d.ClosedOnMain, ", "))
+		}
+		for _, c := range d.Changes {
+			line := fmt.Sprintf("%s %s", c.BeadID, c.Kind)
+			if c.ToStatus != "" && c.Kind != doltserver.ChangeCreated {
+				line += fmt.Sprintf(" (%s → %s)", c.FromStatus, c.ToStatus)
+			}
+			fmt.Printf("    %s\n", style.Dim.Render(line))
+		}
+		switch {
+		case d.Merged:
+			fmt.Printf("    merged into main\n")
+		case d.Error != "":
+			fmt.Printf("    %s\n", style.Error.Render("merge failed: "+d.Error))
+		}
+	}
+	if active > 0 {
+		fmt.Printf("\n%s %d branch(es) in use by running polecats skipped\n", style.Dim.Render("○"), active)
+	}
+	if !doltReconcileMerge {
+		fmt.Printf("\nRun %s to merge stranded branches into main.\n", style.Bold.Render("gt dolt reconcile-branches --merge"))
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltRemoteName string
+	doltRemoteRigs []string
+	doltRemoteJSON bool
+	doltPushForce  bool
+)
+
+var doltRemoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "List the Dolt remotes configured on rig databases",
+	Long: `List, set, and remove the Dolt remotes of rig databases.
+
+A remote is where 'gt dolt push' sends a rig database and 'gt dolt pull'
+fetches it from, so a town's beads can be replicated to another machine
+or backed up off-box. Each rig database has its own remotes, stored in
+the database itself. A remote can be:
+
+  dolthub:<org>/<repo>             a DoltHub repository
+  aws://[<table>:<bucket>]/<path>  S3, with a DynamoDB table for locking
+  gs://<bucket>/<path>             Google Cloud Storage
+  /path/to/dir or file:///...      a local or mounted directory
+
+Examples:
+  gt dolt remote
+  gt dolt remote set gastown dolthub:acme/gastown
+  gt dolt remote set gastown /mnt/backup/dolt/gastown --name backup
+  gt dolt remote remove gastown --name backup`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltRemoteList,
+}
+
+var doltRemoteSetCmd = &cobra.Command{
+	Use:   "set <rig> <remote>",
+	Short: "Point a rig database's remote at a URL",
+	Long: `Add a remote to a rig database, replacing any remote of the same name.
+
+See 'gt dolt remote --help' for the remote forms accepted.
+
+Examples:
+  gt dolt remote set gastown dolthub:acme/gastown
+  gt dolt remote set gastown 'aws://[dolt-locks:town-backups]/gastown'`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runDoltRemoteSet,
+}
+
+var doltRemoteRemoveCmd = &cobra.Command{
+	Use:   "remove <rig>",
+	Short: "Remove a remote from a rig database",
+	Long: `Remove a remote from a rig database. Removing a remote that isn't
+configured is not an error.
+
+Examples:
+  gt dolt remote remove gastown
+  gt dolt remote remove gastown --name backup`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltRemoteRemove,
+}
+
+var doltPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push rig databases to their Dolt remote",
+	Long: `Commit each rig database's working changes and push main to its remote.
+
+Databases without the remote are skipped. A push is rejected when the
+remote has commits main doesn't; pull first, or use --force to overwrite
+the remote's history.
+
+Unlike 'gt dolt sync', this works with the server running and with any
+remote type (see 'gt dolt remote --help').
+
+Examples:
+  gt dolt push
+  gt dolt push --rig gastown --name backup
+  gt dolt push --force`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltPush,
+}
+
+var doltPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull rig databases from their Dolt remote",
+	Long: `Fetch each rig database's remote and merge its main into the local main.
+
+When only the remote has new commits, main is fast-forwarded. When both
+sides have, the merge is previewed first: if any table would conflict,
+nothing is merged and the conflicting tables are listed, so a pull never
+leaves a database with unresolved conflicts. Resolve those by hand with
+'gt dolt sql' (CALL DOLT_MERGE, then the dolt_conflicts tables).
+
+Databases without the remote are skipped.
+
+Examples:
+  gt dolt pull
+  gt dolt pull --rig gastown --name backup
+  gt dolt pull --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltPull,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{doltRemoteSetCmd, doltRemoteRemoveCmd, doltPushCmd, doltPullCmd} {
+		c.Flags().StringVar(&doltRemoteName, "name", doltserver.DefaultRemote, "Remote name")
+	}
+	doltPushCmd.Flags().Lookup("name").Usage = "Remote to push to"
+	doltPullCmd.Flags().Lookup("name").Usage = "Remote to pull from"
+	for _, c := range []*cobra.Command{doltPushCmd, doltPullCmd} {
+		c.Flags().StringSliceVar(&doltRemoteRigs, "rig", nil, "Rig database(s) to sync (default: all)")
+		c.Flags().BoolVar(&doltRemoteJSON, "json", false, "Output as JSON")
+	}
+	doltPushCmd.Flags().BoolVar(&doltPushForce, "force", false, "Overwrite the remote's history")
+	doltRemoteCmd.Flags().StringSliceVar(&doltRemoteRigs, "rig", nil, "Rig database(s) to list (default: all)")
+	doltRemoteCmd.Flags().BoolVar(&doltRemoteJSON, "json", false, "Output as JSON")
+
+	doltRemoteCmd.AddCommand(doltRemoteSetCmd)
+	doltRemoteCmd.AddCommand(doltRemoteRemoveCmd)
+	doltCmd.AddCommand(doltRemoteCmd)
+	doltCmd.AddCommand(doltPushCmd)
+	doltCmd.AddCommand(doltPullCmd)
+}
+
+func runDoltRemoteList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltRemoteRigs)
+	if err != nil {
+		return err
+	}
+
+	remotes := []doltserver.Remote{}
+	for _, db := range databases {
+		rs, err := doltserver.ListRemotes(townRoot, db)
+		if err != nil {
+			return err
+		}
+		remotes = append(remotes, rs...)
+	}
+
+	if doltRemoteJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(remotes)
+	}
+	if len(remotes) == 0 {
+		fmt.Println("No Dolt remotes configured.")
+		fmt.Printf("Add one with: %s\n", style.Dim.Render("gt dolt remote set <rig> <remote>"))
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATABASE\tREMOTE\tURL")
+	for _, r := range remotes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Database, r.Name, r.URL)
+	}
+	return tw.Flush()
+}
+
+func runDoltRemoteSet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := doltTargetDatabases(townRoot, args[:1]); err != nil {
+		return err
+	}
+	remote, err := doltserver.SetRemote(townRoot, args[0], doltRemoteName, args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s remote %s → %s\n", style.Success.Render("✓"), remote.Database, remote.Name, remote.URL)
+	return nil
+}
+
+func runDoltRemoteRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := doltTargetDatabases(townRoot, args); err != nil {
+		return err
+	}
+	if err := doltserver.RemoveRemote(townRoot, args[0], doltRemoteName); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed remote %s from %s\n", style.Success.Render("✓"), doltRemoteName, args[0])
+	return nil
+}
+
+func runDoltPush(cmd *cobra.Command, args []string) error {
+	return runDoltRemoteSync("push", func(townRoot, db string) doltserver.RemoteSyncResult {
+		return doltserver.PushRig(townRoot, db, doltRemoteName, doltPushForce)
+	})
+}
+
+func runDoltPull(cmd *cobra.Command, args []string) error {
+	return runDoltRemoteSync("pull", func(townRoot, db string) doltserver.RemoteSyncResult {
+		return doltserver.PullRig(townRoot, db, doltRemoteName)
+	})
+}
+
+// runDoltRemoteSync runs a push or pull over the target databases and
+// reports the results.
+func runDoltRemoteSync(op string, sync func(townRoot, db string) doltserver.RemoteSyncResult) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, doltRemoteRigs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]doltserver.RemoteSyncResult, 0, len(databases))
+	for _, db := range databases {
+		if !doltRemoteJSON {
+			fmt.Printf("%sing %s...\n", strings.ToUpper(op[:1])+op[1:], db)
+		}
+		results = append(results, sync(townRoot, db))
+	}
+
+	if doltRemoteJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		printDoltRemoteSync(os.Stdout, op, results)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s failed for %d database(s)", op, failed)
+	}
+	return nil
+}
+
+// printDoltRemoteSync writes one line per database, with conflicting
+// tables under a pull that was refused.
+func printDoltRemoteSync(w io.Writer, op string, results []doltserver.RemoteSyncResult) {
+	for _, r := range results {
+		target := r.Database + " ↔ " + r.Remote
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(w, "  %s %s: %s\n", style.Error.Render("✗"), target, r.Error)
+			for _, c := range r.Conflicts {
+				fmt.Fprintf(w, "      %s: %d data, %d schema conflict(s)\n", c.Table, c.DataConflicts, c.SchemaConflicts)
+			}
+		case r.Skipped:
+			fmt.Fprintf(w, "  %s %s — no remote %s\n", style.Dim.Render("○"), r.Database, r.Remote)
+		case r.Pushed:
+			fmt.Fprintf(w, "  %s %s pushed (%d new commit(s))\n", style.Success.Render("✓"), target, r.Outgoing)
+		case r.Merged && r.FastForward:
+			fmt.Fprintf(w, "  %s %s fast-forwarded %d commit(s)\n", style.Success.Render("✓"), target, r.Incoming)
+		case r.Merged:
+			fmt.Fprintf(w, "  %s %s merged %d commit(s) (%d local)\n", style.Success.Render("✓"), target, r.Incoming, r.Outgoing)
+		case op == "pull" && r.Outgoing > 0:
+			fmt.Fprintf(w, "  %s %s up to date (%d local commit(s) to push)\n", style.Success.Render("✓"), target, r.Outgoing)
+		default:
+			fmt.Fprintf(w, "  %s %s up to date\n", style.Success.Render("✓"), target)
+		}
+	}
+}
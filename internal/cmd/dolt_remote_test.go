@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestPrintDoltRemoteSync(t *testing.T) {
+	var buf bytes.Buffer
+	printDoltRemoteSync(&buf, "pull", []doltserver.RemoteSyncResult{
+		{Database: "gastown", Remote: "origin", Incoming: 3, Merged: true, FastForward: true},
+		{Database: "beads", Remote: "origin", Skipped: true},
+		{Database: "hq", Remote: "origin", Incoming: 2, Outgoing: 1, Error: "merging origin/main would conflict in 1 table(s); nothing was merged",
+			Conflicts: []doltserver.TableConflict{{Table: "issues", DataConflicts: 4}}},
+	})
+	out := buf.String()
+	for _, want := range []string{
+		"gastown ↔ origin fast-forwarded 3 commit(s)",
+		"beads — no remote origin",
+		"would conflict in 1 table(s)",
+		"issues: 4 data, 0 schema conflict(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/approval"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltDropRigForce    bool
+	doltDropRigJSON     bool
+	doltDropRigApproval string
+	doltRenameRigJSON   bool
+)
+
+var doltDropRigCmd = &cobra.Command{
+	Use:   "drop-rig <rig>",
+	Short: "Back up and drop a rig's database",
+	Long: `Drop a rig's database from the Dolt server.
+
+The rig must be idle: no tmux sessions, no polecat branches in the
+database, and no open connections to it. A final backup of the database
+directory (full history) is written to
+exports/pruned-databases/<rig>-<timestamp>.tar.gz first; nothing is
+dropped if it fails. The rig's metadata.json stops pointing bd at the
+server.
+
+The rig stays registered; use 'gt rig remove' to remove it entirely, or
+'gt dolt init-rig' to give it a fresh database.
+
+Examples:
+  gt dolt drop-rig gastown
+  gt dolt drop-rig gastown --force`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltDropRig,
+}
+
+var doltRenameRigCmd = &cobra.Command{
+	Use:   "rename-rig <old> <new>",
+	Short: "Rename a rig and its database",
+	Long: `Rename a rig and its Dolt database.
+
+Moves .dolt-data/<old> to .dolt-data/<new> and points the rig's
+metadata.json at it. A rig registered in mayor/rigs.json is renamed
+there too: its directory moves to <new>/, routes.jsonl follows, and its
+git worktrees are repaired for the new path. Bead IDs keep their prefix.
+
+The rig must have no tmux sessions and no polecat branches. The Dolt
+server holds database directories open, so a running server is stopped
+for the rename and started again afterwards.
+
+Examples:
+  gt dolt rename-rig gastown gt`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runDoltRenameRig,
+}
+
+func init() {
+	doltDropRigCmd.Flags().BoolVar(&doltDropRigForce, "force", false, "Drop even with running sessions, polecat branches, or open connections")
+	doltDropRigCmd.Flags().BoolVar(&doltDropRigJSON, "json", false, "Output as JSON")
+	doltDropRigCmd.Flags().StringVar(&doltDropRigApproval, "approval", "", "Approval token from 'gt approve rig-remove' (when required by town policy)")
+	doltRenameRigCmd.Flags().BoolVar(&doltRenameRigJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltDropRigCmd)
+	doltCmd.AddCommand(doltRenameRigCmd)
+}
+
+// checkRigSessionsStopped refuses when the rig has running tmux sessions.
+func checkRigSessionsStopped(rigName string) error {
+	sessions, err := findRigSessions(tmux.NewTmux(), rigName)
+	if err != nil {
+		return fmt.Errorf("could not verify session state for rig %s: %w", rigName, err)
+	}
+	if len(sessions) > 0 {
+		return fmt.Errorf("rig %s has %d running session(s); stop them first with 'gt rig shutdown %s'",
+			rigName, len(sessions), rigName)
+	}
+	return nil
+}
+
+func runDoltDropRig(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := args[0]
+
+	// Town approval policy (gt approve rig-remove)
+	if err := requireApproval(townRoot, approval.ClassRigRemove, rigName, doltDropRigApproval); err != nil {
+		return err
+	}
+	if !doltDropRigForce {
+		if err := checkRigSessionsStopped(rigName); err != nil {
+			return fmt.Errorf("%w (use --force to skip check)", err)
+		}
+	}
+
+	result, err := doltserver.DropRig(townRoot, rigName, doltDropRigForce)
+	var inUse *doltserver.RigInUseError
+	if errors.As(err, &inUse) {
+		return fmt.Errorf("%w\nMerge or drop the branches ('gt dolt branches'), or use --force", err)
+	}
+	if result == nil {
+		return err
+	}
+
+	if doltDropRigJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(result); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Dropped database %s\n", style.Success.Render("✓"), rigName)
+	fmt.Printf("    %s\n", style.Dim.Render("backup: "+result.Backup))
+	printRigMetadataResult(result.Metadata)
+	return nil
+}
+
+func runDoltRenameRig(cmd *cobra.Command, args []string) (err error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	from, to := args[0], args[1]
+
+	if err := checkRigSessionsStopped(from); err != nil {
+		return err
+	}
+
+	if running, _, _ := doltserver.IsRunning(townRoot); running {
+		if !doltRenameRigJSON {
+			fmt.Println("Stopping Dolt server for the rename...")
+		}
+		if err := doltserver.Stop(townRoot); err != nil {
+			return fmt.Errorf("stopping Dolt server: %w", err)
+		}
+		defer func() {
+			if startErr := doltserver.Start(townRoot); startErr != nil {
+				err = errors.Join(err, fmt.Errorf("restarting Dolt server: %w", startErr))
+			} else if !doltRenameRigJSON {
+				fmt.Println("Dolt server restarted")
+			}
+		}()
+	}
+
+	result, err := doltserver.RenameRig(townRoot, from, to)
+	if err != nil {
+		return err
+	}
+
+	if doltRenameRigJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	fmt.Printf("%s Renamed %s → %s\n", style.Success.Render("✓"), from, to)
+	if result.RigDir != "" {
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("rig directory: %s (%d worktree(s) repaired)", result.RigDir, result.Worktrees)))
+	}
+	if result.Routes > 0 {
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("routes.jsonl: %d route(s) updated", result.Routes)))
+	}
+	printRigMetadataResult(result.Metadata)
+	for _, w := range result.Warnings {
+		style.PrintWarning("%s", w)
+	}
+	return nil
+}
+
+// printRigMetadataResult reports a metadata.json update, if there was one.
+func printRigMetadataResult(res *doltserver.MetadataResult) {
+	if res == nil {
+		return
+	}
+	switch res.Action {
+	case doltserver.MetadataProposed:
+		fmt.Printf("    %s\n", style.Dim.Render("metadata.json is tracked; change proposed in "+res.Proposal))
+	case doltserver.MetadataUnchanged:
+	default:
+		fmt.Printf("    %s\n", style.Dim.Render("metadata.json "+res.Action+": "+res.Path))
+	}
+}
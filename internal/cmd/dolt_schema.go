@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltIndexesRigs  []string
+	doltIndexesApply bool
+	doltIndexesRuns  int
+	doltIndexesJSON  bool
+)
+
+var doltSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Inspect and tune rig database schemas",
+	RunE:  requireSubcommand,
+}
+
+var doltSchemaIndexesCmd = &cobra.Command{
+	Use:   "indexes",
+	Short: "Recommend (or create) indexes on hot bead columns",
+	Long: `Check each rig database for indexes on the columns bd filters and
+sorts on, and recommend the missing ones.
+
+As bead counts grow, bd list and bd ready slow down when the server has to
+scan every row. The advisor looks for an index leading with each hot
+column (issues.status, issues.assignee, issues.updated_at, labels.label)
+and recommends a CREATE INDEX for each one missing.
+
+Latency is measured by timing the open-bead count, the ready query, and a
+probe per hot column, averaged over --runs passes. With --apply, the
+recommended indexes are created in one Dolt commit per database and the
+queries are timed again, so the report shows before and after latency.
+
+Examples:
+  gt dolt schema indexes
+  gt dolt schema indexes --rig gastown
+  gt dolt schema indexes --apply
+  gt dolt schema indexes --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltSchemaIndexes,
+}
+
+func init() {
+	doltSchemaIndexesCmd.Flags().StringSliceVar(&doltIndexesRigs, "rig", nil, "Rig database(s) to check (default: all)")
+	doltSchemaIndexesCmd.Flags().BoolVar(&doltIndexesApply, "apply", false, "Create the recommended indexes")
+	doltSchemaIndexesCmd.Flags().IntVar(&doltIndexesRuns, "runs", 5, "Timed passes over the bench queries")
+	doltSchemaIndexesCmd.Flags().BoolVar(&doltIndexesJSON, "json", false, "Output as JSON")
+	doltSchemaCmd.AddCommand(doltSchemaIndexesCmd)
+	doltCmd.AddCommand(doltSchemaCmd)
+}
+
+func runDoltSchemaIndexes(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if doltIndexesRuns < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+	databases, err := doltTargetDatabases(townRoot, doltIndexesRigs)
+	if err != nil {
+		return err
+	}
+
+	results, err := doltserver.AdviseIndexes(townRoot, databases, doltIndexesApply, doltIndexesRuns)
+	if err != nil {
+		return err
+	}
+
+	if doltIndexesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printIndexAdvice(results, doltIndexesApply)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to check %d database(s)", failed)
+	}
+	return nil
+}
+
+// printIndexAdvice prints each database's recommendations and latency.
+func printIndexAdvice(results []doltserver.IndexAdvice, apply bool) {
+	pending := 0
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), r.Database, r.Error)
+			continue
+		}
+		latency := "bench " + timefmt.Duration(r.Before)
+		if r.Committed {
+			latency = fmt.Sprintf("bench %s → %s", timefmt.Duration(r.Before), timefmt.Duration(r.After))
+		}
+		if len(r.Recommendations) == 0 {
+			fmt.Printf("%s %s: hot columns indexed %s\n", style.Success.Render("✓"), r.Database, style.Dim.Render("("+latency+")"))
+			continue
+		}
+
+		fmt.Printf("%s %s: %d missing index(es) %s\n", style.Warning.Render("⚠"), r.Database,
+			len(r.Recommendations), style.Dim.Render("("+latency+")"))
+		for _, rec := range r.Recommendations {
+			mark := " "
+			if rec.Applied {
+				mark = style.Success.Render("✓")
+			} else {
+				pending++
+			}
+			fmt.Printf("  %s %s\n", mark, rec.Statement)
+		}
+	}
+	if pending > 0 && !apply {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf(
+			"Run 'gt dolt schema indexes --apply' to create %d index(es)", pending)))
+	}
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltStatsRigs     []string
+	doltStatsSince    time.Duration
+	doltStatsNoRecord bool
+	doltStatsJSON     bool
+
+	doltPruneRigs    []string
+	doltPruneTargets []string
+	doltPruneDays    int
+	doltPruneDry     bool
+	doltPruneJSON    bool
+)
+
+var doltStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show per-table row counts, sizes, and growth",
+	Long: `Show row counts and sizes for every table in each rig database.
+
+Wisp and audit tables grow much faster than the rest, and a single disk
+usage number hides which table is responsible. Each run records a snapshot
+to daemon/dolt-table-stats.jsonl; growth is reported against the snapshot
+closest to --since ago.
+
+Alerts for specific tables are configured in settings/config.json:
+
+  "dolt_stats": {
+    "alerts": [
+      {"table": "events", "max_rows": 1000000},
+      {"table": "issues", "max_bytes": 536870912, "databases": ["gastown"]}
+    ],
+    "retention": {"closed_wisp_days": 7, "event_days": 30}
+  }
+
+Use 'gt dolt prune' to delete old rows from known-safe tables.
+
+Examples:
+  gt dolt stats                  # All databases, growth over 24h
+  gt dolt stats --rig gastown    # One rig
+  gt dolt stats --since 168h     # Growth over the last week
+  gt dolt stats --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltStats,
+}
+
+var doltPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old rows from known-safe tables",
+	Long: `Delete rows past their retention period from tables that are safe
+to prune, then commit the deletion in each database.
+
+Targets:
+  wisps   Closed wisps (ephemeral issues), by closed_at (default 7 days)
+  events  Audit log entries in the events table (default 30 days)
+
+Retention comes from dolt_stats.retention in settings/config.json, or
+--days to override it for this run. Dolt keeps history, so pruned rows
+remain reachable through earlier commits until the database is GC'd.
+
+Examples:
+  gt dolt prune --dry-run             # Show what would be deleted
+  gt dolt prune                       # Prune all targets in all rigs
+  gt dolt prune --target events --days 14 --rig gastown`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltPrune,
+}
+
+func init() {
+	doltStatsCmd.Flags().StringSliceVar(&doltStatsRigs, "rig", nil, "Rig database(s) to report (default: all)")
+	doltStatsCmd.Flags().DurationVar(&doltStatsSince, "since", 24*time.Hour, "Report growth since this long ago")
+	doltStatsCmd.Flags().BoolVar(&doltStatsNoRecord, "no-record", false, "Don't record this run in the stats history")
+	doltStatsCmd.Flags().BoolVar(&doltStatsJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltStatsCmd)
+
+	doltPruneCmd.Flags().StringSliceVar(&doltPruneRigs, "rig", nil, "Rig database(s) to prune (default: all)")
+	doltPruneCmd.Flags().StringSliceVar(&doltPruneTargets, "target", doltserver.PruneTargets, "What to prune (wisps, events)")
+	doltPruneCmd.Flags().IntVar(&doltPruneDays, "days", 0, "Retention in days (overrides settings)")
+	doltPruneCmd.Flags().BoolVar(&doltPruneDry, "dry-run", false, "Count prunable rows without deleting")
+	doltPruneCmd.Flags().BoolVar(&doltPruneJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltPruneCmd)
+}
+
+// DoltTableReport is one table's stats with growth since the baseline snapshot.
+type DoltTableReport struct {
+	doltserver.TableStat
+	RowGrowth  int64 `json:"row_growth"`
+	ByteGrowth int64 `json:"byte_growth"`
+}
+
+// DoltStatsReport is the output of gt dolt stats.
+type DoltStatsReport struct {
+	Since  *time.Time              `json:"baseline_time,omitempty"`
+	Tables []DoltTableReport       `json:"tables"`
+	Alerts []doltserver.TableAlert `json:"alerts,omitempty"`
+	Errors map[string]string       `json:"errors,omitempty"`
+
+	// Warnings are non-fatal problems, such as failing to record history.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// DoltStatsOptions configures CollectDoltStats.
+type DoltStatsOptions struct {
+	Rigs     []string      // Rig databases to report (default: all)
+	Since    time.Duration // Report growth against the snapshot this long ago
+	NoRecord bool          // Don't append this run to the stats history
+}
+
+func runDoltStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	report, err := CollectDoltStats(townRoot, DoltStatsOptions{
+		Rigs:     doltStatsRigs,
+		Since:    doltStatsSince,
+		NoRecord: doltStatsNoRecord,
+	})
+	if err != nil {
+		return err
+	}
+
+	if doltStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printDoltStats(*report)
+	return nil
+}
+
+// CollectDoltStats gathers per-table stats for each rig database, computes
+// growth against the stats history, and evaluates the configured alerts.
+// Databases that can't be queried are reported in Errors.
+func CollectDoltStats(townRoot string, opts DoltStatsOptions) (*DoltStatsReport, error) {
+	databases, err := doltTargetDatabases(townRoot, opts.Rigs)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := loadDoltStatsConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	history, err := doltserver.LoadTableStatsHistory(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading stats history: %w", err)
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-opts.Since)
+	report := &DoltStatsReport{Tables: []DoltTableReport{}}
+	var all []doltserver.TableStat
+	for _, db := range databases {
+		stats, err := doltserver.GetTableStats(townRoot, db)
+		if err != nil {
+			if report.Errors == nil {
+				report.Errors = map[string]string{}
+			}
+			report.Errors[db] = err.Error()
+			continue
+		}
+		all = append(all, stats...)
+
+		baseline := doltserver.BaselineSnapshot(history, db, cutoff)
+		if baseline != nil && (report.Since == nil || baseline.Time.Before(*report.Since)) {
+			t := baseline.Time
+			report.Since = &t
+		}
+		for _, s := range stats {
+			r := DoltTableReport{TableStat: s}
+			if prev, ok := baseline.Find(s.Table); ok {
+				r.RowGrowth = s.Rows - prev.Rows
+				r.ByteGrowth = s.Bytes - prev.Bytes
+			}
+			report.Tables = append(report.Tables, r)
+		}
+
+		if !opts.NoRecord {
+			snap := doltserver.TableStatsSnapshot{Time: now, Database: db, Tables: stats}
+			if err := doltserver.RecordTableStats(townRoot, snap); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("recording stats for %s: %v", db, err))
+			}
+		}
+	}
+	if settings != nil {
+		report.Alerts = doltserver.CheckTableAlerts(settings.Alerts, all)
+	}
+	return report, nil
+}
+
+func printDoltStats(report DoltStatsReport) {
+	growthHeader := "GROWTH"
+	if report.Since != nil {
+		growthHeader = fmt.Sprintf("GROWTH (since %s)", report.Since.Format("Jan 2 15:04"))
+	}
+
+	db := ""
+	for _, t := range report.Tables {
+		if t.Database != db {
+			db = t.Database
+			fmt.Printf("\n%s\n", style.Bold.Render(db))
+			fmt.Printf("  %-28s %12s %10s  %s\n", "TABLE", "ROWS", "SIZE", growthHeader)
+		}
+		growth := style.Dim.Render("—")
+		if report.Since != nil && (t.RowGrowth != 0 || t.ByteGrowth != 0) {
+			growth = fmt.Sprintf("%+d rows", t.RowGrowth)
+		}
+		fmt.Printf("  %-28s %12d %10s  %s\n", t.Table, t.Rows, formatBytes(t.Bytes), growth)
+	}
+	for db, msg := range report.Errors {
+		fmt.Printf("\n%s %s: %s\n", style.Error.Render("✗"), db, msg)
+	}
+	for _, w := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "%s %s\n", style.Warning.Render("⚠"), w)
+	}
+
+	if len(report.Alerts) > 0 {
+		fmt.Println()
+		for _, a := range report.Alerts {
+			fmt.Printf("%s %s.%s: %s\n", style.Warning.Render("⚠"), a.Database, a.Table, a.Message)
+		}
+		fmt.Printf("  %s\n", style.Dim.Render("Run 'gt dolt prune --dry-run' to see what can be pruned"))
+	}
+}
+
+// DoltPruneOptions configures PruneDolt.
+type DoltPruneOptions struct {
+	Rigs    []string // Rig databases to prune (default: all)
+	Targets []string // Prune targets (default: all known-safe targets)
+	Days    int      // Retention in days, overriding settings (0 = use settings)
+	DryRun  bool     // Count prunable rows without deleting
+}
+
+func runDoltPrune(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	results, pruneErr := PruneDolt(townRoot, DoltPruneOptions{
+		Rigs:    doltPruneRigs,
+		Targets: doltPruneTargets,
+		Days:    doltPruneDays,
+		DryRun:  doltPruneDry,
+	})
+	if results == nil {
+		return pruneErr
+	}
+
+	if doltPruneJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printDoltPrune(results)
+	}
+	return pruneErr
+}
+
+// PruneDolt deletes rows past their retention period from known-safe tables
+// in each rig database. Per-database failures don't stop the run: the
+// results that succeeded are returned along with an error joining the
+// failures. Returns nil results only if nothing could be attempted.
+func PruneDolt(townRoot string, opts DoltPruneOptions) ([]*doltserver.PruneResult, error) {
+	databases, err := doltTargetDatabases(townRoot, opts.Rigs)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := loadDoltStatsConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = doltserver.PruneTargets
+	}
+
+	results := []*doltserver.PruneResult{}
+	var errs []error
+	for _, db := range databases {
+		for _, target := range targets {
+			days := opts.Days
+			if days == 0 {
+				days = doltserver.RetentionDays(settings, target)
+			}
+			res, err := doltserver.PruneTable(townRoot, db, target, days, opts.DryRun)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			results = append(results, res)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func printDoltPrune(results []*doltserver.PruneResult) {
+	var total int64
+	for _, r := range results {
+		total += r.Rows
+		if r.Rows == 0 {
+			fmt.Printf("  %s %s: no %s older than %d days\n", style.Dim.Render("○"), r.Database, r.Target, r.Days)
+			continue
+		}
+		verb := "pruned"
+		if doltPruneDry {
+			verb = "would prune"
+		}
+		fmt.Printf("  %s %s: %s %d %s older than %d days\n", style.Success.Render("✓"), r.Database, verb, r.Rows, r.Target, r.Days)
+	}
+	if doltPruneDry && total > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("Dry run: no rows deleted"))
+	}
+}
+
+// doltTargetDatabases returns rigs if set, after checking they exist, or all databases.
+func doltTargetDatabases(townRoot string, rigs []string) ([]string, error) {
+	if len(rigs) > 0 {
+		for _, db := range rigs {
+			if !doltserver.DatabaseExists(townRoot, db) {
+				return nil, fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", db)
+			}
+		}
+		return rigs, nil
+	}
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no Dolt databases found")
+	}
+	return databases, nil
+}
+
+func loadDoltStatsConfig(townRoot string) (*config.DoltStatsConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.DoltStats, nil
+}
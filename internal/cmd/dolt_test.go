@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 func TestDirSizeHuman(t *testing.T) {
@@ -46,3 +51,121 @@ func TestDirSizeHuman_NonexistentDir(t *testing.T) {
 		t.Errorf("nonexistent dir: got %q, want %q", got, "0 B")
 	}
 }
+
+func TestClassifyPolecatBranch(t *testing.T) {
+	tests := []struct {
+		alive, closed bool
+		want          string
+	}{
+		{true, true, branchActive},
+		{true, false, branchActive},
+		{false, true, branchStranded},
+		{false, false, branchOrphaned},
+	}
+	for _, tt := range tests {
+		if got := classifyPolecatBranch(tt.alive, tt.closed); got != tt.want {
+			t.Errorf("classifyPolecatBranch(%v, %v) = %s, want %s", tt.alive, tt.closed, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyDeadPolecatBranch(t *testing.T) {
+	tests := []struct {
+		changes int
+		closed  bool
+		want    string
+	}{
+		{0, false, branchMerged},
+		{2, true, branchStranded},
+		{2, false, branchOrphaned},
+	}
+	for _, tt := range tests {
+		if got := classifyDeadPolecatBranch(tt.changes, tt.closed); got != tt.want {
+			t.Errorf("classifyDeadPolecatBranch(%d, %v) = %s, want %s", tt.changes, tt.closed, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDays(t *testing.T) {
+	if got := formatDays(7 * 24 * time.Hour); got != "7d" {
+		t.Errorf("formatDays(7 days) = %q, want 7d", got)
+	}
+	if got := formatDays(36 * time.Hour); got != "36h0m0s" {
+		t.Errorf("formatDays(36h) = %q, want 36h0m0s", got)
+	}
+}
+
+func TestFindBranchPolecatGone(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats", "Nux"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	name, alive := findBranchPolecat(townRoot, "gastown", "toast")
+	if name != "toast" || alive {
+		t.Errorf("findBranchPolecat(toast) = %q, %v; want the branch name and not running", name, alive)
+	}
+}
+
+func TestBranchAge(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	polecat := doltserver.BranchInfo{Created: now.Add(-50 * time.Hour), LastCommit: now.Add(-time.Minute)}
+	if got := branchAge(polecat, now); got != "2d" {
+		t.Errorf("polecat branch age = %q, want 2d (from its name)", got)
+	}
+	other := doltserver.BranchInfo{LastCommit: now.Add(-90 * time.Minute)}
+	if got := branchAge(other, now); got != "1h" {
+		t.Errorf("branch age = %q, want 1h (from its last commit)", got)
+	}
+	if got := branchAge(doltserver.BranchInfo{}, now); got != "?" {
+		t.Errorf("unknown age = %q, want ?", got)
+	}
+}
+
+func TestWriteSQLRows(t *testing.T) {
+	rows := [][]string{{"id", "title"}, {"gt-1", "First, with comma"}, {"gt-2", ""}}
+
+	var buf bytes.Buffer
+	if err := writeSQLRows(&buf, "json", rows); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "id": "gt-1",
+    "title": "First, with comma"
+  },
+  {
+    "id": "gt-2",
+    "title": ""
+  }
+]
+`
+	if buf.String() != want {
+		t.Errorf("json = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeSQLRows(&buf, "csv", rows); err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,title\ngt-1,\"First, with comma\"\ngt-2,\n"; buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeSQLRows(&buf, "table", rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "gt-1  First") || !strings.Contains(lines[3], "(2 rows)") {
+		t.Errorf("table = %q", buf.String())
+	}
+
+	// A statement without a result set prints an empty JSON array.
+	buf.Reset()
+	if err := writeSQLRows(&buf, "json", nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("json without rows = %q, want []", buf.String())
+	}
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var doltTopInterval time.Duration
+
+// doltTopHistory is how many latency samples the trend line shows.
+const doltTopHistory = 30
+
+var doltTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live dashboard of Dolt server activity",
+	Long: `Show a live dashboard of the Dolt server, refreshed every interval:
+
+  - query latency, with a trend line of recent samples
+  - connections as a share of max_connections
+  - disk usage and branch counts per database, including polecat branches
+  - the client connections from information_schema.PROCESSLIST, longest
+    running first, with the statement each is running
+
+Unlike 'gt dolt status' it skips the read-only write probe, so it is
+cheap enough to leave running.
+
+Examples:
+  gt dolt top                 # Refresh every 2s
+  gt dolt top --interval 10s`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltTop,
+}
+
+func init() {
+	doltTopCmd.Flags().DurationVar(&doltTopInterval, "interval", 2*time.Second, "Refresh interval")
+	doltCmd.AddCommand(doltTopCmd)
+}
+
+// doltTopSnapshot is one refresh of the dashboard.
+type doltTopSnapshot struct {
+	Running        bool
+	PID            int
+	Port           int
+	MaxConnections int
+	Latency        time.Duration
+	LatencyErr     error
+	Processes      []doltserver.Process
+	ProcessErr     error
+	Databases      []doltserver.DatabaseMetrics
+}
+
+func runDoltTop(cmd *cobra.Command, args []string) error {
+	if doltTopInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", doltTopInterval)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(doltTopInterval)
+	defer ticker.Stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	var latencies []time.Duration
+
+	for {
+		snap := collectDoltTop(townRoot)
+		if snap.Running && snap.LatencyErr == nil {
+			latencies = append(latencies, snap.Latency)
+			if len(latencies) > doltTopHistory {
+				latencies = latencies[len(latencies)-doltTopHistory:]
+			}
+		}
+
+		width := 120
+		if isTTY {
+			fmt.Print("\033[H\033[2J") // ANSI: cursor home + clear screen
+			if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+				width = w
+			}
+		}
+		header := fmt.Sprintf("[%s] gt dolt top (every %v, Ctrl+C to stop)", time.Now().Format("15:04:05"), doltTopInterval)
+		fmt.Printf("%s\n\n", style.Dim.Render(header))
+		renderDoltTop(os.Stdout, snap, latencies, width)
+
+		select {
+		case <-sigChan:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectDoltTop gathers one snapshot. When the server is down only the
+// process check runs.
+func collectDoltTop(townRoot string) doltTopSnapshot {
+	config := doltserver.DefaultConfig(townRoot)
+	snap := doltTopSnapshot{Port: config.Port, MaxConnections: config.MaxConnections}
+	if snap.MaxConnections <= 0 {
+		snap.MaxConnections = 1000 // Dolt default
+	}
+
+	running, pid, err := doltserver.IsRunning(townRoot)
+	if err != nil || !running {
+		return snap
+	}
+	snap.Running, snap.PID = true, pid
+	snap.Latency, snap.LatencyErr = doltserver.MeasureQueryLatency(townRoot)
+	snap.Processes, snap.ProcessErr = doltserver.ListProcesses(townRoot)
+	snap.Databases, _ = doltserver.GetDatabaseMetrics(townRoot)
+	return snap
+}
+
+// renderDoltTop writes one frame of the dashboard, fitting statements to
+// width columns.
+func renderDoltTop(w io.Writer, snap doltTopSnapshot, latencies []time.Duration, width int) {
+	if !snap.Running {
+		fmt.Fprintf(w, "%s Dolt server is %s\n", style.Dim.Render("○"), style.Bold.Render("not running"))
+		fmt.Fprintf(w, "  Start with: %s\n", style.Dim.Render("gt dolt start"))
+		return
+	}
+
+	fmt.Fprintf(w, "%s Dolt server is %s (PID %d, port %d)\n",
+		style.Bold.Render("●"), style.Bold.Render("running"), snap.PID, snap.Port)
+
+	if snap.LatencyErr != nil {
+		fmt.Fprintf(w, "  Latency:     %s %v\n", style.Error.Render("✗"), snap.LatencyErr)
+	} else {
+		fmt.Fprintf(w, "  Latency:     %-8v %s\n", snap.Latency.Round(time.Millisecond), latencySparkline(latencies))
+	}
+
+	if snap.ProcessErr == nil {
+		conns := len(snap.Processes)
+		pct := float64(conns) / float64(snap.MaxConnections) * 100
+		line := fmt.Sprintf("%d / %d (%.0f%%)", conns, snap.MaxConnections, pct)
+		if pct >= 80 {
+			line = style.Warning.Render(line)
+		}
+		fmt.Fprintf(w, "  Connections: %s\n", line)
+	}
+
+	var total int64
+	for _, db := range snap.Databases {
+		total += db.SizeBytes
+	}
+	fmt.Fprintf(w, "  Disk usage:  %s\n", formatBytes(total))
+
+	if len(snap.Databases) > 0 {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "DATABASE\tSIZE\tBRANCHES\tPOLECAT BRANCHES")
+		for _, db := range snap.Databases {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", db.Name, formatBytes(db.SizeBytes),
+				branchCount(db.Branches), branchCount(db.PolecatBranches))
+		}
+		_ = tw.Flush()
+	}
+
+	fmt.Fprintln(w)
+	if snap.ProcessErr != nil {
+		fmt.Fprintf(w, "%s Processes: %v\n", style.Error.Render("✗"), snap.ProcessErr)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", style.Bold.Render(fmt.Sprintf("Processes (%d)", len(snap.Processes))))
+	if len(snap.Processes) == 0 {
+		return
+	}
+
+	// Lay out every column but the statement, then give the statement what
+	// is left of the line.
+	var table strings.Builder
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tUSER\tDB\tCOMMAND\tTIME\tSTATE\t")
+	for _, p := range snap.Processes {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%ds\t%s\t\n", p.ID, p.User, p.Database, p.Command, p.Seconds, p.State)
+	}
+	_ = tw.Flush()
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	for i, line := range lines {
+		info := "QUERY"
+		if i > 0 {
+			info = strings.Join(strings.Fields(snap.Processes[i-1].Info), " ")
+		}
+		if room := width - len(line); room > 0 {
+			line += truncateWithEllipsis(info, room)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// branchCount formats a DatabaseMetrics branch count, which is -1 when it
+// couldn't be read.
+func branchCount(n int) string {
+	if n < 0 {
+		return "?"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// latencySparkline draws samples as a bar per sample, scaled to the
+// slowest, followed by that slowest sample.
+func latencySparkline(samples []time.Duration) string {
+	if len(samples) < 2 {
+		return ""
+	}
+	bars := []rune("▁▂▃▄▅▆▇█")
+	var peak time.Duration
+	for _, s := range samples {
+		peak = max(peak, s)
+	}
+	var b strings.Builder
+	for _, s := range samples {
+		i := 0
+		if peak > 0 {
+			i = int(int64(s) * int64(len(bars)-1) / int64(peak))
+		}
+		b.WriteRune(bars[i])
+	}
+	return fmt.Sprintf("%s  %s", b.String(), style.Dim.Render(fmt.Sprintf("peak %v", peak.Round(time.Millisecond))))
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestLatencySparkline(t *testing.T) {
+	if got := latencySparkline([]time.Duration{time.Millisecond}); got != "" {
+		t.Errorf("one sample = %q, want empty", got)
+	}
+	got := latencySparkline([]time.Duration{0, 4 * time.Millisecond, 8 * time.Millisecond})
+	if !strings.HasPrefix(got, "▁▄█") {
+		t.Errorf("sparkline = %q, want prefix ▁▄█", got)
+	}
+	if !strings.Contains(got, "peak 8ms") {
+		t.Errorf("sparkline = %q, want the peak", got)
+	}
+}
+
+func TestRenderDoltTop(t *testing.T) {
+	var b strings.Builder
+	renderDoltTop(&b, doltTopSnapshot{
+		Running:        true,
+		PID:            1234,
+		Port:           3307,
+		MaxConnections: 50,
+		Latency:        3 * time.Millisecond,
+		Processes: []doltserver.Process{
+			{ID: 42, User: "root", Database: "gastown", Command: "Query", Seconds: 12,
+				Info: "SELECT * FROM issues WHERE status = 'open' AND assignee LIKE 'gastown/polecats/%'"},
+		},
+		Databases: []doltserver.DatabaseMetrics{
+			{Name: "gastown", SizeBytes: 2048, Branches: 5, PolecatBranches: 4},
+			{Name: "beads", SizeBytes: 1024, Branches: -1, PolecatBranches: -1},
+		},
+	}, nil, 60)
+	out := b.String()
+
+	for _, want := range []string{"PID 1234, port 3307", "1 / 50 (2%)", "3.0 KB", "gastown", "?", "Processes (1)", "QUERY"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "42 ") && len(line) > 60 {
+			t.Errorf("process line not fitted to width: %q", line)
+		}
+	}
+
+	b.Reset()
+	renderDoltTop(&b, doltTopSnapshot{}, nil, 80)
+	if !strings.Contains(b.String(), "not running") {
+		t.Errorf("want not running, got:\n%s", b.String())
+	}
+}
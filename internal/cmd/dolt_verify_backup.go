@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltVerifyBackupRig  string
+	doltVerifyBackupKeep bool
+	doltVerifyBackupJSON bool
+)
+
+var doltVerifyBackupCmd = &cobra.Command{
+	Use:   "verify-backup",
+	Short: "Restore a backup into a scratch directory and check it works",
+	Long: `Restore the latest backup of a rig database and prove it is usable.
+
+A backup that has never been restored is a hope, not a backup. This clones
+the database from its origin remote (the backup written by 'gt dolt sync')
+into a scratch directory, then:
+
+  1. Runs dolt fsck on the clone (skipped if dolt is too old to have it)
+  2. Queries the restored issues table
+  3. Serves the clone on a throwaway sql-server and runs bd list against it
+
+The scratch directory is removed afterwards unless --keep is given. Without
+--rig, a random database with a remote is chosen, so repeated runs cover
+every rig over time.
+
+The result is appended to daemon/dolt-restore-verify.jsonl, logged to the
+event feed, and reported by 'gt doctor'. The daemon runs this daily when
+the backup_verify patrol is enabled in mayor/daemon.json.
+
+Exits non-zero if verification fails.
+
+Examples:
+  gt dolt verify-backup                # Random rig
+  gt dolt verify-backup --rig gastown  # Specific rig
+  gt dolt verify-backup --keep         # Keep the scratch clone for inspection`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltVerifyBackup,
+}
+
+func init() {
+	doltVerifyBackupCmd.Flags().StringVar(&doltVerifyBackupRig, "rig", "", "Rig database to verify (default: random)")
+	doltVerifyBackupCmd.Flags().BoolVar(&doltVerifyBackupKeep, "keep", false, "Keep the scratch directory")
+	doltVerifyBackupCmd.Flags().BoolVar(&doltVerifyBackupJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltVerifyBackupCmd)
+}
+
+func runDoltVerifyBackup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	db, remote, err := pickVerifyTarget(townRoot, doltVerifyBackupRig)
+	if err != nil {
+		return err
+	}
+
+	if !doltVerifyBackupJSON {
+		fmt.Printf("Verifying backup of %s from %s...\n", style.Bold.Render(db), remote)
+	}
+	v, err := doltserver.VerifyRestore(db, remote, doltVerifyBackupKeep)
+	if err != nil {
+		return err
+	}
+
+	var warning string
+	if err := doltserver.RecordRestoreVerification(townRoot, v); err != nil {
+		warning = fmt.Sprintf("could not record result: %v", err)
+	}
+	logRestoreVerification(v)
+
+	if doltVerifyBackupJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	} else {
+		printRestoreVerification(v, doltVerifyBackupKeep)
+		if warning != "" {
+			fmt.Printf("%s %s\n", style.Warning.Render("⚠"), warning)
+		}
+	}
+
+	if !v.Passed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// pickVerifyTarget returns the database to verify and its remote: rig if
+// given, otherwise a random database that has a remote.
+func pickVerifyTarget(townRoot, rig string) (string, string, error) {
+	if rig == "" {
+		db, remote, err := doltserver.PickRestoreTarget(townRoot)
+		if err != nil {
+			return "", "", fmt.Errorf("listing databases: %w", err)
+		}
+		if db == "" {
+			return "", "", fmt.Errorf("no database has a backup remote (set one with 'gt dolt sync')")
+		}
+		return db, remote, nil
+	}
+
+	remotes, err := doltserver.BackedUpDatabases(townRoot)
+	if err != nil {
+		return "", "", fmt.Errorf("listing databases: %w", err)
+	}
+	remote, ok := remotes[rig]
+	if !ok {
+		return "", "", fmt.Errorf("database %q not found or has no origin remote", rig)
+	}
+	return rig, remote, nil
+}
+
+// logRestoreVerification records the result in the event feed.
+func logRestoreVerification(v *doltserver.RestoreVerification) {
+	if failed := v.Failed(); failed != nil {
+		_ = events.LogFeed(events.TypeBackupVerifyFailed, "gt",
+			events.BackupVerifyPayload(v.Database, failed.Name,
+				fmt.Sprintf("backup restore of %s failed at %s: %s", v.Database, failed.Name, failed.Detail)))
+		return
+	}
+	_ = events.LogFeed(events.TypeBackupVerified, "gt",
+		events.BackupVerifyPayload(v.Database, "",
+			fmt.Sprintf("backup of %s restored and verified (%d issues)", v.Database, v.Issues)))
+}
+
+func printRestoreVerification(v *doltserver.RestoreVerification, keep bool) {
+	for _, c := range v.Checks {
+		switch {
+		case c.Skipped:
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("○"), c.Name, style.Dim.Render("("+c.Detail+")"))
+		case c.OK:
+			detail := c.Detail
+			if c.Name == doltserver.RestoreCheckClone && !keep {
+				detail = ""
+			}
+			if detail != "" {
+				fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), c.Name, style.Dim.Render("("+detail+")"))
+			} else {
+				fmt.Printf("  %s %s\n", style.Success.Render("✓"), c.Name)
+			}
+		default:
+			fmt.Printf("  %s %s: %s\n", style.Error.Render("✗"), c.Name, c.Detail)
+		}
+	}
+
+	fmt.Println()
+	if v.Passed {
+		fmt.Printf("%s Backup of %s verified in %s\n", style.Success.Render("✓"), v.Database, v.Duration.Round(time.Second))
+	} else {
+		fmt.Printf("%s Backup of %s failed verification\n", style.Error.Render("✗"), v.Database)
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var (
+	doltVerifySyncRigs      []string
+	doltVerifySyncReconcile bool
+	doltVerifySyncYes       bool
+	doltVerifySyncJSON      bool
+)
+
+var doltVerifySyncCmd = &cobra.Command{
+	Use:   "verify-sync",
+	Short: "Detect rigs whose beads are split between embedded and server copies",
+	Long: `Cross-check each rig's bead storage for split-brain.
+
+A rig is split-brained when bd can write to a database the Dolt server
+doesn't serve: an embedded .beads/dolt/ copy left behind by an interrupted
+migration, or a metadata.json that no longer says dolt_mode "server".
+Agents then see different beads depending on which copy they hit.
+
+For the town database (hq) and each rig, verify-sync checks:
+
+  - metadata.json dolt_mode against whether .dolt-data/<rig> exists
+  - whether an embedded database still exists alongside the server copy
+  - whether the two copies have different issue counts
+
+With --reconcile, each divergent rig with both copies is merged after
+confirmation: the embedded copy is exported with dolt dump, and rows the
+server lacks are imported into the server database in one Dolt commit.
+Rows already on the server are never overwritten. The server database is
+backed up first, the embedded copy is moved aside to
+.beads/dolt.split-brain-<timestamp>/, and metadata.json is pointed at the
+server.
+
+Examples:
+  gt dolt verify-sync
+  gt dolt verify-sync --rig gastown --json
+  gt dolt verify-sync --reconcile
+  gt dolt verify-sync --reconcile --rig gastown --yes`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltVerifySync,
+}
+
+func init() {
+	doltVerifySyncCmd.Flags().StringSliceVar(&doltVerifySyncRigs, "rig", nil, "Rig(s) to check (default: hq and all rigs)")
+	doltVerifySyncCmd.Flags().BoolVar(&doltVerifySyncReconcile, "reconcile", false, "Merge embedded copies into the server database")
+	doltVerifySyncCmd.Flags().BoolVarP(&doltVerifySyncYes, "yes", "y", false, "With --reconcile, don't ask before each rig")
+	doltVerifySyncCmd.Flags().BoolVar(&doltVerifySyncJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltVerifySyncCmd)
+}
+
+func runDoltVerifySync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if doltVerifySyncReconcile && doltVerifySyncJSON {
+		return fmt.Errorf("--reconcile is interactive and can't be combined with --json")
+	}
+	if doltVerifySyncReconcile && !doltVerifySyncYes && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("--reconcile asks before each rig; use --yes when not on a terminal")
+	}
+
+	reports, err := doltserver.VerifySplitBrain(townRoot, doltVerifySyncRigs)
+	if err != nil {
+		return err
+	}
+
+	if doltVerifySyncJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		printSplitBrainReports(reports)
+	}
+
+	var divergent []*doltserver.SplitBrainReport
+	for _, r := range reports {
+		if r.Divergent() {
+			divergent = append(divergent, r)
+		}
+	}
+	if len(divergent) == 0 {
+		return nil
+	}
+	if !doltVerifySyncReconcile {
+		if !doltVerifySyncJSON {
+			fmt.Printf("\n%s\n", style.Dim.Render("Run 'gt dolt verify-sync --reconcile' to merge embedded copies into the server"))
+		}
+		return NewSilentExit(1)
+	}
+
+	failed := 0
+	for _, r := range divergent {
+		if !r.Reconcilable() {
+			fmt.Printf("%s %s: nothing to merge; fix metadata with 'gt dolt fix-metadata' or migrate with 'gt dolt migrate --rig %s'\n",
+				style.Warning.Render("⚠"), r.Rig, r.Rig)
+			continue
+		}
+		if !doltVerifySyncYes && !promptYesNo(fmt.Sprintf("Merge the embedded copy of %s (%s) into the server?", r.Rig, r.Embedded)) {
+			continue
+		}
+		res, err := doltserver.ReconcileSplitBrain(townRoot, r.Rig)
+		if err != nil {
+			fmt.Printf("%s %s: %v\n", style.Error.Render("✗"), r.Rig, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s: imported %d issue(s) from the embedded copy\n", style.Success.Render("✓"), r.Rig, res.Imported)
+		fmt.Printf("  Backup:  %s\n", res.Backup)
+		fmt.Printf("  Retired: %s\n", res.Retired)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to reconcile %d rig(s)", failed)
+	}
+	return nil
+}
+
+// printSplitBrainReports prints each rig's storage state and problems.
+func printSplitBrainReports(reports []*doltserver.SplitBrainReport) {
+	for _, r := range reports {
+		switch {
+		case r.Error != "" && !r.Divergent():
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), r.Rig, r.Error)
+		case !r.Divergent():
+			fmt.Printf("%s %s %s\n", style.Success.Render("✓"), r.Rig, style.Dim.Render(splitBrainSummary(r)))
+		default:
+			fmt.Printf("%s %s %s\n", style.Warning.Render("⚠"), r.Rig, style.Dim.Render(splitBrainSummary(r)))
+			for _, p := range r.Problems {
+				fmt.Printf("    %s\n", p)
+			}
+			if r.Error != "" {
+				fmt.Printf("    %s\n", style.Error.Render(r.Error))
+			}
+		}
+	}
+}
+
+// splitBrainSummary describes a rig's mode and issue counts in one line.
+func splitBrainSummary(r *doltserver.SplitBrainReport) string {
+	mode := r.DoltMode
+	if mode == "" {
+		mode = "unset"
+	}
+	s := "(dolt_mode " + mode
+	if r.ServerIssues >= 0 {
+		s += fmt.Sprintf(", server %d issues", r.ServerIssues)
+	}
+	if r.EmbeddedIssues >= 0 {
+		s += fmt.Sprintf(", embedded %d issues", r.EmbeddedIssues)
+	}
+	return s + ")"
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltWarmRigs []string
+	doltWarmJSON bool
+)
+
+var doltWarmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Pre-warm server caches with representative queries",
+	Long: `Run representative bd queries against each database to warm the server.
+
+The first bd queries after the Dolt server starts are slow while it loads
+chunk indexes from disk. Warming runs the queries agents hit first (the
+open-bead count and the ready query) so that cost is paid up front, and
+reports how long each database took.
+
+'gt dolt start --warm' warms after starting, and the daemon warms after
+every supervised restart.
+
+Examples:
+  gt dolt warm
+  gt dolt warm --rig gastown --rig beads
+  gt dolt warm --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltWarm,
+}
+
+func init() {
+	doltWarmCmd.Flags().StringSliceVar(&doltWarmRigs, "rig", nil, "Warm only these databases (repeatable)")
+	doltWarmCmd.Flags().BoolVar(&doltWarmJSON, "json", false, "Output as JSON")
+	doltCmd.AddCommand(doltWarmCmd)
+}
+
+func runDoltWarm(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start it with 'gt dolt start')")
+	}
+
+	results, err := doltserver.WarmDatabases(townRoot, doltWarmRigs)
+	if err != nil {
+		return err
+	}
+
+	if doltWarmJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printWarmResults(results)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to warm %d database(s)", failed)
+	}
+	return nil
+}
+
+// printWarmResults prints per-database warm-up times.
+func printWarmResults(results []doltserver.WarmResult) {
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Printf("  %s %s: %s\n", style.Error.Render("✗"), r.Database, r.Error)
+			continue
+		}
+		fmt.Printf("  %s Warmed %s in %s\n", style.Success.Render("✓"), r.Database, timefmt.Duration(r.Duration))
+	}
+}
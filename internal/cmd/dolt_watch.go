@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltWatchRigs     []string
+	doltWatchInterval time.Duration
+	doltWatchJSON     bool
+)
+
+var doltWatchTablesCmd = &cobra.Command{
+	Use:   "watch-tables",
+	Short: "Stream bead changes from Dolt commits",
+	Long: `Stream bead changes as they are committed to rig databases.
+
+Polls each database's HEAD and diffs the issues table (via dolt_diff)
+against the previous commit seen, printing beads that were created,
+changed status, or closed. Streaming starts from the current HEAD.
+
+For a persistent feed, enable the daemon's change_feed patrol in
+mayor/daemon.json; it publishes bead_created, bead_status_changed, and
+bead_closed events to .events.jsonl for patrols and webhooks:
+
+  "patrols": {
+    "change_feed": {"enabled": true}
+  }
+
+Examples:
+  gt dolt watch-tables                    # All databases
+  gt dolt watch-tables --rig gastown      # One rig
+  gt dolt watch-tables --json             # One JSON object per change`,
+	Args: cobra.NoArgs,
+	RunE: runDoltWatchTables,
+}
+
+func init() {
+	doltWatchTablesCmd.Flags().StringSliceVar(&doltWatchRigs, "rig", nil, "Rig database(s) to watch (default: all)")
+	doltWatchTablesCmd.Flags().DurationVar(&doltWatchInterval, "interval", 5*time.Second, "Poll interval")
+	doltWatchTablesCmd.Flags().BoolVar(&doltWatchJSON, "json", false, "Output changes as JSON lines")
+	doltCmd.AddCommand(doltWatchTablesCmd)
+}
+
+func runDoltWatchTables(cmd *cobra.Command, args []string) error {
+	if doltWatchInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", doltWatchInterval)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	databases := doltWatchRigs
+	if len(databases) == 0 {
+		if databases, err = doltserver.ListDatabases(townRoot); err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+	}
+	if len(databases) == 0 {
+		return fmt.Errorf("no Dolt databases found")
+	}
+
+	// In-memory checkpoints: the first poll records HEAD for each database.
+	checkpoints := doltserver.ChangeFeedCheckpoints{}
+	poll := func() {
+		for _, db := range databases {
+			changes, err := doltserver.PollBeadChanges(townRoot, db, checkpoints)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.Warning.Render("⚠"), db, err)
+				continue
+			}
+			for _, c := range changes {
+				printBeadChange(c)
+			}
+		}
+	}
+	poll()
+
+	if !doltWatchJSON {
+		fmt.Printf("%s Watching %d database(s) every %v (Ctrl+C to stop)\n",
+			style.Dim.Render("○"), len(databases), doltWatchInterval)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(doltWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sigChan:
+			return nil
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+func printBeadChange(c doltserver.BeadChange) {
+	if doltWatchJSON {
+		data, _ := json.Marshal(c)
+		fmt.Println(string(data))
+		return
+	}
+	ts := style.Dim.Render(time.Now().Format("15:04:05"))
+	switch c.Kind {
+	case doltserver.ChangeCreated:
+		fmt.Printf("%s %s %s/%s created: %s\n", ts, style.Success.Render("+"), c.Database, c.BeadID, c.Title)
+	case doltserver.ChangeClosed:
+		fmt.Printf("%s %s %s/%s closed: %s\n", ts, style.Dim.Render("✓"), c.Database, c.BeadID, c.Title)
+	default:
+		fmt.Printf("%s %s %s/%s %s → %s: %s\n", ts, style.Warning.Render("~"), c.Database, c.BeadID,
+			c.FromStatus, c.ToStatus, c.Title)
+	}
+}
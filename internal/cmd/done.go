@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -38,6 +39,12 @@ This is a convenience command for polecats that:
 3. Notifies the Witness with the exit outcome
 4. Exits the Claude session (polecats don't stay alive after completion)
 
+Completion report:
+  A report is generated from data and attached to the issue as a comment:
+  the branch's diff stat, bead changes on the polecat's Dolt branch, closed
+  molecule steps with timings, captured test output (--tests), and session
+  cost. Add your own prose with --summary; it is appended to the report.
+
 Exit statuses:
   COMPLETED      - Work done, MR submitted (default)
   ESCALATED      - Hit blocker, needs human intervention
@@ -53,6 +60,8 @@ Phase handoff workflow:
 Examples:
   gt done                              # Submit branch, notify COMPLETED, exit session
   gt done --issue gt-abc               # Explicit issue ID
+  gt done --summary "Fixed X by Y"     # Append prose to the completion report
+  gt done --tests /tmp/test.log        # Include captured test output
   gt done --status ESCALATED           # Signal blocker, skip MR
   gt done --status DEFERRED            # Pause work, skip MR
   gt done --phase-complete --gate g-x  # Phase done, waiting on gate g-x`,
@@ -67,6 +76,9 @@ var (
 	doneGate          string
 	doneCleanupStatus string
 	doneResume        bool
+	doneSummary       string
+	doneTests         string
+	doneNoReport      bool
 )
 
 // Valid exit types for gt done
@@ -85,6 +97,9 @@ func init() {
 	doneCmd.Flags().StringVar(&doneGate, "gate", "", "Gate bead ID to wait on (with --phase-complete)")
 	doneCmd.Flags().StringVar(&doneCleanupStatus, "cleanup-status", "", "Git cleanup status: clean, uncommitted, unpushed, stash, unknown (ZFC: agent-observed)")
 	doneCmd.Flags().BoolVar(&doneResume, "resume", false, "Resume from last checkpoint (auto-detected, for Witness recovery)")
+	doneCmd.Flags().StringVar(&doneSummary, "summary", "", "Prose appended to the generated completion report")
+	doneCmd.Flags().StringVar(&doneTests, "tests", "", "File with captured test output to include in the completion report")
+	doneCmd.Flags().BoolVar(&doneNoReport, "no-report", false, "Skip the generated completion report")
 
 	rootCmd.AddCommand(doneCmd)
 }
@@ -833,6 +848,34 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 	}
 
 notifyWitness:
+	// Attach the completion report before the Dolt merge: the bead diff
+	// reads the polecat's branch, and the comment itself lands on the branch
+	// and is merged with everything else.
+	if issueID != "" && !doneNoReport && checkpoints[CheckpointDoltMerged] == "" {
+		in := doneReportInputs{
+			townRoot:   townRoot,
+			rigName:    rigName,
+			issueID:    issueID,
+			exitType:   exitType,
+			branch:     branch,
+			bd:         beads.New(beads.ResolveBeadsDir(cwd)),
+			doltBranch: os.Getenv("BD_BRANCH"),
+			testsFile:  doneTests,
+			summary:    doneSummary,
+		}
+		if cwdAvailable {
+			in.git = g
+			in.baseRef = "origin/" + defaultBranch
+			in.workDir = cwd
+		}
+		report := buildDoneReport(in)
+		if _, err := in.bd.Run("comment", issueID, report.Markdown()); err != nil {
+			style.PrintWarning("could not attach completion report: %v", err)
+		} else {
+			fmt.Printf("%s Completion report attached to %s\n", style.Bold.Render("✓"), issueID)
+		}
+	}
+
 	// Branch-per-polecat: merge polecat's Dolt branch to main.
 	// This makes all beads changes (MR bead, issue updates) visible on main
 	// before the refinery or witness try to read them.
@@ -848,7 +891,13 @@ notifyWitness:
 		fmt.Printf("Merging Dolt branch %s to main...\n", bdBranch)
 		if err := doltserver.MergePolecatBranch(townRoot, rigName, bdBranch); err != nil {
 			mergeFailed = true
-			style.PrintWarning("could not merge Dolt branch: %v (data still on branch %s)", err, bdBranch)
+			var conflict *doltserver.MergeConflictError
+			if errors.As(err, &conflict) {
+				style.PrintWarning("%v", err)
+				recordDoltConflict(beads.New(beads.ResolveBeadsDir(cwd)), agentBeadID, conflict)
+			} else {
+				style.PrintWarning("could not merge Dolt branch: %v (data still on branch %s; 'gt dolt reconcile-branches --merge' retries it)", err, bdBranch)
+			}
 		} else {
 			fmt.Printf("%s Dolt branch merged to main\n", style.Bold.Render("✓"))
 		}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// doneReportTestLines is how many trailing lines of captured test output
+// go into the completion report.
+const doneReportTestLines = 30
+
+// DoneReport is the completion report gt done attaches to the issue. It is
+// built from data (git, Dolt, the molecule, the session transcript) rather
+// than written by the agent, so refinery reviewers get the same context from
+// every polecat. The agent's own prose (--summary) is appended at the end.
+type DoneReport struct {
+	Issue       string
+	Exit        string
+	Branch      string
+	DiffStat    string
+	BeadChanges []doltserver.BeadChange
+	Steps       []DoneStep
+	Tests       string
+	Cost        float64
+	Summary     string
+}
+
+// DoneStep is a closed molecule step and how long it took.
+type DoneStep struct {
+	ID       string
+	Title    string
+	ClosedAt time.Time
+	Duration time.Duration
+}
+
+// doneReportInputs are what buildDoneReport gathers the report from. Any
+// source that is unavailable is left out of the report.
+type doneReportInputs struct {
+	townRoot   string
+	rigName    string
+	issueID    string
+	exitType   string
+	branch     string
+	baseRef    string   // e.g. origin/main; empty skips the diff stat
+	git        *git.Git // nil when the worktree is gone
+	bd         *beads.Beads
+	doltBranch string // BD_BRANCH, before it is merged
+	workDir    string // for the session cost; empty skips it
+	testsFile  string
+	summary    string
+}
+
+// buildDoneReport collects the completion report. Failures to read any one
+// source are not fatal: the report is a best-effort summary.
+func buildDoneReport(in doneReportInputs) *DoneReport {
+	r := &DoneReport{Issue: in.issueID, Exit: in.exitType, Branch: in.branch, Summary: in.summary}
+
+	if in.git != nil && in.baseRef != "" {
+		if stat, err := in.git.DiffStat(in.baseRef, "HEAD"); err == nil {
+			r.DiffStat = stat
+		}
+	}
+	if in.doltBranch != "" {
+		if changes, err := doltserver.BeadChangesOnBranch(in.townRoot, in.rigName, in.doltBranch); err == nil {
+			r.BeadChanges = changes
+		}
+	}
+	if in.bd != nil {
+		r.Steps = closedMoleculeSteps(in.bd, in.issueID)
+	}
+	if in.testsFile != "" {
+		if data, err := os.ReadFile(in.testsFile); err == nil { //nolint:gosec // G304: path is the agent's own --tests flag
+			r.Tests = lastLines(string(data), doneReportTestLines)
+		}
+	}
+	if in.workDir != "" {
+		if cost, err := extractCostFromWorkDir(in.workDir); err == nil {
+			r.Cost = cost
+		}
+	}
+	return r
+}
+
+// closedMoleculeSteps returns the closed steps of the molecule attached to
+// issueID, in close order, each timed from the later of its creation and the
+// previous step's close.
+func closedMoleculeSteps(bd *beads.Beads, issueID string) []DoneStep {
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return nil
+	}
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil || fields.AttachedMolecule == "" {
+		return nil
+	}
+	children, err := bd.List(beads.ListOptions{Parent: fields.AttachedMolecule, Status: "closed", Priority: -1})
+	if err != nil {
+		return nil
+	}
+	return timeSteps(children)
+}
+
+// timeSteps orders closed step issues by close time and computes durations.
+func timeSteps(issues []*beads.Issue) []DoneStep {
+	type timed struct {
+		issue           *beads.Issue
+		created, closed time.Time
+	}
+	var ts []timed
+	for _, is := range issues {
+		closed, err := time.Parse(time.RFC3339, is.ClosedAt)
+		if err != nil {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339, is.CreatedAt)
+		ts = append(ts, timed{is, created, closed})
+	}
+	sort.SliceStable(ts, func(i, j int) bool { return ts[i].closed.Before(ts[j].closed) })
+
+	steps := make([]DoneStep, 0, len(ts))
+	var prev time.Time
+	for _, t := range ts {
+		start := t.created
+		if prev.After(start) {
+			start = prev
+		}
+		var d time.Duration
+		if !start.IsZero() && t.closed.After(start) {
+			d = t.closed.Sub(start)
+		}
+		steps = append(steps, DoneStep{ID: t.issue.ID, Title: t.issue.Title, ClosedAt: t.closed, Duration: d})
+		prev = t.closed
+	}
+	return steps
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown renders the report as a bead comment.
+func (r *DoneReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Completion report\n\n")
+	fmt.Fprintf(&b, "- Exit: %s\n", r.Exit)
+	fmt.Fprintf(&b, "- Branch: %s\n", r.Branch)
+	if r.Cost > 0 {
+		fmt.Fprintf(&b, "- Cost: $%.2f\n", r.Cost)
+	}
+
+	if r.DiffStat != "" {
+		b.WriteString("\n### Changes\n\n```\n")
+		b.WriteString(strings.TrimRight(r.DiffStat, "\n"))
+		b.WriteString("\n```\n")
+	}
+
+	if len(r.BeadChanges) > 0 {
+		b.WriteString("\n### Beads\n\n")
+		for _, c := range r.BeadChanges {
+			switch c.Kind {
+			case doltserver.ChangeStatusChanged:
+				fmt.Fprintf(&b, "- %s %s → %s: %s\n", c.BeadID, c.FromStatus, c.ToStatus, c.Title)
+			default:
+				fmt.Fprintf(&b, "- %s %s: %s\n", c.BeadID, c.Kind, c.Title)
+			}
+		}
+	}
+
+	if len(r.Steps) > 0 {
+		var total time.Duration
+		for _, s := range r.Steps {
+			total += s.Duration
+		}
+		fmt.Fprintf(&b, "\n### Steps (%d closed, %s)\n\n", len(r.Steps), total.Round(time.Second))
+		for _, s := range r.Steps {
+			fmt.Fprintf(&b, "- %s %s (%s)\n", s.ID, s.Title, s.Duration.Round(time.Second))
+		}
+	}
+
+	if r.Tests != "" {
+		b.WriteString("\n### Tests\n\n```\n")
+		b.WriteString(r.Tests)
+		b.WriteString("\n```\n")
+	}
+
+	if strings.TrimSpace(r.Summary) != "" {
+		b.WriteString("\n### Summary\n\n")
+		b.WriteString(strings.TrimSpace(r.Summary))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
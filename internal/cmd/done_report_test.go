@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestTimeSteps(t *testing.T) {
+	steps := timeSteps([]*beads.Issue{
+		{ID: "gt-s2", Title: "Implement", CreatedAt: "2026-03-01T10:00:00Z", ClosedAt: "2026-03-01T10:25:00Z"},
+		{ID: "gt-s1", Title: "Load context", CreatedAt: "2026-03-01T10:00:00Z", ClosedAt: "2026-03-01T10:05:00Z"},
+		{ID: "gt-s0", Title: "Never closed", CreatedAt: "2026-03-01T10:00:00Z"},
+	})
+	if len(steps) != 2 {
+		t.Fatalf("got %d steps, want 2", len(steps))
+	}
+	if steps[0].ID != "gt-s1" || steps[0].Duration != 5*time.Minute {
+		t.Errorf("step 0 = %+v, want gt-s1 taking 5m", steps[0])
+	}
+	// Steps are poured together, so later steps are timed from the previous close.
+	if steps[1].ID != "gt-s2" || steps[1].Duration != 20*time.Minute {
+		t.Errorf("step 1 = %+v, want gt-s2 taking 20m", steps[1])
+	}
+}
+
+func TestDoneReportMarkdown(t *testing.T) {
+	r := &DoneReport{
+		Issue:    "gt-abc",
+		Exit:     ExitCompleted,
+		Branch:   "polecat/toast-xyz",
+		DiffStat: " main.go | 4 ++--\n 1 file changed, 2 insertions(+), 2 deletions(-)\n",
+		BeadChanges: []doltserver.BeadChange{
+			{BeadID: "gt-new", Kind: doltserver.ChangeCreated, Title: "Follow-up"},
+			{BeadID: "gt-abc", Kind: doltserver.ChangeStatusChanged, FromStatus: "open", ToStatus: "in_progress", Title: "Fix parser"},
+		},
+		Steps:   []DoneStep{{ID: "gt-s1", Title: "Load context", Duration: 90 * time.Second}},
+		Tests:   "ok  \tpkg\t0.1s",
+		Cost:    1.234,
+		Summary: "  Fixed the parser.  ",
+	}
+	md := r.Markdown()
+	for _, want := range []string{
+		"- Exit: COMPLETED",
+		"- Cost: $1.23",
+		"1 file changed",
+		"- gt-new created: Follow-up",
+		"- gt-abc open → in_progress: Fix parser",
+		"### Steps (1 closed, 1m30s)",
+		"### Tests",
+		"### Summary\n\nFixed the parser.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("report missing %q:\n%s", want, md)
+		}
+	}
+
+	// Sections without data are left out.
+	md = (&DoneReport{Exit: ExitDeferred, Branch: "b"}).Markdown()
+	for _, absent := range []string{"Cost", "### Changes", "### Beads", "### Steps", "### Tests", "### Summary"} {
+		if strings.Contains(md, absent) {
+			t.Errorf("empty report contains %q:\n%s", absent, md)
+		}
+	}
+}
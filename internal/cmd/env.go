@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	envDoctorAll bool
+	envDoctorFix bool
+)
+
+var envCmd = &cobra.Command{
+	Use:     "env",
+	GroupID: GroupDiag,
+	Short:   "Inspect the agent environment contract",
+	Long: `Inspect the environment variables Gas Town gives agent sessions.
+
+Every spawn path starts agents with the same versioned set of variables
+(GT_ROLE, GT_RIG, BD_ACTOR, GT_ROOT, the Dolt server, BD_BRANCH for
+polecats, ...). The version is exported as GT_ENV_CONTRACT.
+
+Crew and polecat workspaces also get .runtime/env.sh with the same
+variables for human shells: source it, or run 'direnv allow' once to let
+the generated .envrc load it on cd.`,
+	RunE: requireSubcommand,
+}
+
+var envDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check agent environments against the contract",
+	Long: `Check that an agent's environment matches the contract for its role.
+
+Without --all, checks this process's environment, so run it inside an
+agent session or a shell that sourced a workspace's .runtime/env.sh. With
+--all, checks every running Gas Town tmux session and every crew and
+polecat workspace's env file; --fix rewrites missing or stale env files.
+
+Sessions started under an older contract (GT_ENV_CONTRACT differs) pick
+up the current one when they restart.
+
+Examples:
+  gt env doctor         # Check the current agent session
+  gt env doctor --all   # Check every running session and workspace
+  gt env doctor --all --fix`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runEnvDoctor,
+}
+
+func init() {
+	envDoctorCmd.Flags().BoolVar(&envDoctorAll, "all", false, "Check every running Gas Town session")
+	envDoctorCmd.Flags().BoolVar(&envDoctorFix, "fix", false, "With --all, rewrite missing or stale workspace env files")
+	envCmd.AddCommand(envDoctorCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnvDoctor(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if envDoctorFix && !envDoctorAll {
+		return fmt.Errorf("--fix requires --all")
+	}
+	if envDoctorAll {
+		d := doctor.NewDoctor()
+		d.Register(doctor.NewEnvVarsCheck())
+		d.Register(doctor.NewWorkspaceEnvCheck())
+		ctx := &doctor.CheckContext{TownRoot: townRoot}
+		var report *doctor.Report
+		if envDoctorFix {
+			report = d.Fix(ctx)
+		} else {
+			report = d.Run(ctx)
+		}
+		report.Print(os.Stdout, true, 0)
+		if !report.IsHealthy() {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+
+	if os.Getenv(EnvGTRole) == "" {
+		return fmt.Errorf("%s is not set: run inside an agent session or after sourcing a workspace's %s, or use --all", EnvGTRole, rig.WorkspaceEnvFile)
+	}
+	info, err := GetRole()
+	if err != nil {
+		return err
+	}
+
+	expected := config.AgentEnv(config.AgentEnvConfig{
+		Role:      string(info.Role),
+		Rig:       info.Rig,
+		AgentName: info.Polecat,
+		TownRoot:  townRoot,
+	})
+	problems := envDoctorProblems(expected, environMap(os.Environ()))
+
+	if len(problems) == 0 {
+		fmt.Printf("%s Environment matches contract v%d for %s\n",
+			style.Success.Render("✓"), config.EnvContractVersion, expected["GT_ROLE"])
+		return nil
+	}
+	fmt.Printf("%s Environment does not match contract v%d for %s:\n",
+		style.Error.Render("✗"), config.EnvContractVersion, expected["GT_ROLE"])
+	for _, p := range problems {
+		fmt.Printf("  %s\n", p)
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Restart the session to pick up the current contract: gt shutdown && gt up"))
+	return NewSilentExit(1)
+}
+
+// envDoctorProblems returns the contract violations in actual: missing or
+// mismatched variables, then forbidden ones.
+func envDoctorProblems(expected, actual map[string]string) []string {
+	problems := config.CheckAgentEnv(expected, actual)
+	for _, k := range config.ForbiddenEnvSet(actual) {
+		problems = append(problems, fmt.Sprintf("%s=%q is set (must be unset)", k, actual[k]))
+	}
+	return problems
+}
+
+// environMap converts os.Environ-style KEY=VALUE entries to a map.
+func environMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
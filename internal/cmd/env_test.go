@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvironMap(t *testing.T) {
+	got := environMap([]string{"GT_ROLE=mayor", "EMPTY=", "OPTS=a=b", "bogus"})
+	want := map[string]string{"GT_ROLE": "mayor", "EMPTY": "", "OPTS": "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("environMap = %v, want %v", got, want)
+	}
+}
+
+func TestEnvDoctorProblems(t *testing.T) {
+	expected := map[string]string{"GT_ROLE": "mayor", "GT_ENV_CONTRACT": "1"}
+	actual := map[string]string{"GT_ROLE": "mayor", "GT_ENV_CONTRACT": "1", "BEADS_DIR": "/town/.beads"}
+
+	want := []string{`BEADS_DIR="/town/.beads" is set (must be unset)`}
+	if got := envDoctorProblems(expected, actual); !reflect.DeepEqual(got, want) {
+		t.Errorf("envDoctorProblems = %q, want %q", got, want)
+	}
+
+	delete(actual, "BEADS_DIR")
+	if got := envDoctorProblems(expected, actual); len(got) != 0 {
+		t.Errorf("envDoctorProblems = %q, want none", got)
+	}
+}
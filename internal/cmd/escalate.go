@@ -113,6 +113,9 @@ When run without --dry-run, this command:
 3. Re-routes them according to the new severity level
 4. Sends mail to the new routing targets
 
+It also raises a medium escalation for each nudge sent with
+'gt nudge --require-ack' whose deadline passed without a 'gt ack'.
+
 Respects max_reescalations from config (default: 2) to prevent infinite escalation.
 
 The threshold is configured in settings/escalation.json.
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -95,18 +96,67 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating escalation bead: %w", err)
 	}
 
-	// Get routing actions for this severity
-	actions := escalationConfig.GetRouteForSeverity(severity)
-	targets := extractMailTargetsFromActions(actions)
+	actions, targets, suppressed := routeEscalation(townRoot, escalationConfig, issue.ID, severity, description,
+		escalateReason, escalateSource, escalateRelatedBead, agentID, escalateJSON)
+
+	// Output
+	if escalateJSON {
+		result := map[string]interface{}{
+			"id":       issue.ID,
+			"severity": severity,
+			"actions":  actions,
+			"targets":  targets,
+		}
+		if escalateSource != "" {
+			result["source"] = escalateSource
+		}
+		if len(suppressed) > 0 {
+			result["suppressed"] = suppressed
+		}
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		emoji := severityEmoji(severity)
+		fmt.Printf("%s Escalation created: %s\n", emoji, issue.ID)
+		fmt.Printf("  Severity: %s\n", severity)
+		if escalateSource != "" {
+			fmt.Printf("  Source: %s\n", escalateSource)
+		}
+		fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
+	}
+
+	return nil
+}
+
+// routeEscalation sends the notifications for escalation bead issueID:
+// mail to each mail: target of the severity's route (rate limited below
+// critical), the external actions (email:, sms:, slack), and the activity
+// feed event. Returns the route's actions, its mail targets, and the targets
+// whose mail was suppressed. quiet hides the suppression notices.
+func routeEscalation(townRoot string, cfg *config.EscalationConfig, issueID, severity, description, reason, source, related, from string, quiet bool) (actions, targets, suppressed []string) {
+	actions = cfg.GetRouteForSeverity(severity)
+	targets = extractMailTargetsFromActions(actions)
 
 	// Send mail to each target (actions with "mail:" prefix)
+	// Non-critical escalations are rate limited per target; the bead is
+	// still created so nothing is lost, only the repeat mail is held back.
 	router := mail.NewRouter(townRoot)
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description)
 	for _, target := range targets {
+		if severity != config.SeverityCritical {
+			if d, _ := ratelimit.Allow(townRoot, ratelimit.KindEscalation, target, subject); !d.Allowed {
+				suppressed = append(suppressed, target)
+				if !quiet {
+					fmt.Printf("%s Mail to %s suppressed (%s)\n", style.Dim.Render("○"), target, d)
+				}
+				continue
+			}
+		}
 		msg := &mail.Message{
-			From:    agentID,
+			From:    from,
 			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+			Subject: subject,
+			Body:    formatEscalationMailBody(issueID, severity, reason, from, related),
 			Type:    mail.TypeTask,
 		}
 
@@ -128,41 +178,18 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	executeExternalActions(actions, cfg, issueID, severity, description)
 
 	// Log to activity feed
-	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
+	payload := events.EscalationPayload(issueID, from, strings.Join(targets, ","), description)
 	payload["severity"] = severity
 	payload["actions"] = strings.Join(actions, ",")
-	if escalateSource != "" {
-		payload["source"] = escalateSource
+	if source != "" {
+		payload["source"] = source
 	}
-	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
+	_ = events.LogFeed(events.TypeEscalationSent, from, payload)
 
-	// Output
-	if escalateJSON {
-		result := map[string]interface{}{
-			"id":       issue.ID,
-			"severity": severity,
-			"actions":  actions,
-			"targets":  targets,
-		}
-		if escalateSource != "" {
-			result["source"] = escalateSource
-		}
-		out, _ := json.MarshalIndent(result, "", "  ")
-		fmt.Println(string(out))
-	} else {
-		emoji := severityEmoji(severity)
-		fmt.Printf("%s Escalation created: %s\n", emoji, issue.ID)
-		fmt.Printf("  Severity: %s\n", severity)
-		if escalateSource != "" {
-			fmt.Printf("  Source: %s\n", escalateSource)
-		}
-		fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
-	}
-
-	return nil
+	return actions, targets, suppressed
 }
 
 func runEscalateList(cmd *cobra.Command, args []string) error {
@@ -295,6 +322,18 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading escalation config: %w", err)
 	}
 
+	// Nudges sent with --require-ack and never acknowledged are escalated
+	// here too, so patrols running gt escalate stale pick them up.
+	nudgeBy := detectSender()
+	if nudgeBy == "" {
+		nudgeBy = "system"
+	}
+	if n, err := escalateUnackedNudges(townRoot, escalationConfig, nudgeBy, escalateDryRun, escalateStaleJSON); err != nil {
+		style.PrintWarning("checking unacknowledged nudges: %v", err)
+	} else if n > 0 && !escalateStaleJSON {
+		fmt.Println()
+	}
+
 	threshold := escalationConfig.GetStaleThreshold()
 	maxReescalations := escalationConfig.GetMaxReescalations()
 
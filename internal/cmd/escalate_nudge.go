@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// unackedNudgeSeverity is the severity of the escalation raised when a
+// nudge sent with --require-ack isn't acknowledged in time. If it stays
+// open, gt escalate stale bumps it like any other escalation.
+const unackedNudgeSeverity = config.SeverityMedium
+
+// escalateUnackedNudges raises one escalation per overdue nudge receipt not
+// yet escalated, and records the escalation on the receipt. With dryRun it
+// only reports them; quiet suppresses output. Returns how many were (or
+// would be) escalated.
+func escalateUnackedNudges(townRoot string, cfg *config.EscalationConfig, by string, dryRun, quiet bool) (int, error) {
+	overdue, err := nudge.OverdueReceipts(townRoot, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	count := 0
+	for _, r := range overdue {
+		if r.Escalation != "" {
+			continue
+		}
+		if count == 0 && !quiet {
+			if dryRun {
+				fmt.Println("Would escalate unacknowledged nudges:")
+			} else {
+				fmt.Println("Escalated unacknowledged nudges:")
+			}
+		}
+		description := fmt.Sprintf("Nudge %s to %s not acknowledged", r.ID, r.Target)
+		late := time.Since(r.AckBy).Round(time.Minute)
+		if dryRun {
+			if !quiet {
+				fmt.Printf("  %s %s (%s late)\n", severityEmoji(unackedNudgeSeverity), description, late)
+			}
+			count++
+			continue
+		}
+
+		reason := fmt.Sprintf("%s nudged %s at %s and asked for 'gt ack %s' by %s: %q",
+			r.Sender, r.Target, r.SentAt.Format(time.RFC3339), r.ID, r.AckBy.Format(time.RFC3339), r.Message)
+		source := "nudge:" + r.ID
+		issue, err := bd.CreateEscalationBead(description, &beads.EscalationFields{
+			Severity:    unackedNudgeSeverity,
+			Reason:      reason,
+			Source:      source,
+			EscalatedBy: by,
+			EscalatedAt: time.Now().Format(time.RFC3339),
+		})
+		if err != nil {
+			style.PrintWarning("failed to escalate %s: %v", r.ID, err)
+			continue
+		}
+		routeEscalation(townRoot, cfg, issue.ID, unackedNudgeSeverity, description, reason, source, "", by, quiet)
+		if err := nudge.MarkEscalated(townRoot, r.ID, issue.ID); err != nil {
+			style.PrintWarning("escalated %s as %s but could not record it: %v", r.ID, issue.ID, err)
+		}
+		if !quiet {
+			fmt.Printf("  %s %s → %s\n", severityEmoji(unackedNudgeSeverity), description, issue.ID)
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	flagsListJSON bool
+	flagsSetRig   string
+)
+
+var flagsCmd = &cobra.Command{
+	Use:     "flags",
+	GroupID: GroupConfig,
+	Short:   "Feature flags for code paths being rolled out or retired",
+	Long: `List and set feature flags.
+
+Feature flags switch code paths that are being rolled out or retired, so a
+town (or one rig) can opt in early or back out without a new gt release.
+Each flag has a default and a stage (experimental, beta, stable,
+deprecated). Town settings override the default for every rig; a rig's
+settings override the town:
+
+  settings/config.json:          "feature_flags": {"merge-queue": false}
+  <rig>/settings/config.json:    "feature_flags": {"merge-queue": true}
+
+The flags in effect are included in support bundles.
+
+Examples:
+  gt flags list
+  gt flags set dolt-prewarm off
+  gt flags set merge-queue on --rig gastown
+  gt flags set merge-queue default --rig gastown   # Drop the rig override`,
+	RunE: requireSubcommand,
+}
+
+var flagsListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List feature flags and their values for the town and each rig",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runFlagsList,
+}
+
+var flagsSetCmd = &cobra.Command{
+	Use:   "set <flag> <on|off|default>",
+	Short: "Turn a feature flag on or off for the town or a rig",
+	Long: `Set a feature flag in town settings, or in a rig's settings with --rig.
+
+"default" removes the override, so the flag falls back to the town value
+(for a rig) or the built-in default (for the town).`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runFlagsSet,
+}
+
+func init() {
+	flagsListCmd.Flags().BoolVar(&flagsListJSON, "json", false, "Output as JSON")
+	flagsSetCmd.Flags().StringVar(&flagsSetRig, "rig", "", "Set the flag for this rig only")
+	flagsCmd.AddCommand(flagsListCmd)
+	flagsCmd.AddCommand(flagsSetCmd)
+	rootCmd.AddCommand(flagsCmd)
+}
+
+func runFlagsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, _ := rigsconfig.Names(townRoot)
+	states := config.ResolveFeatureFlags(townRoot, rigs)
+
+	if flagsListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(states)
+	}
+
+	for _, s := range states {
+		if s.Rig != "" {
+			fmt.Printf("    %-20s %s %s\n", s.Rig, flagValue(s.Enabled), style.Dim.Render("(rig)"))
+			continue
+		}
+		f, _ := config.LookupFeatureFlag(s.Name)
+		stage := style.Dim.Render(f.Stage)
+		if f.Stage == config.FeatureStageDeprecated {
+			stage = style.Warning.Render(f.Stage)
+		}
+		fmt.Printf("  %-22s %s %-9s %s  %s\n", style.Bold.Render(s.Name), flagValue(s.Enabled), style.Dim.Render("("+s.Source+")"), stage, f.Description)
+		if f.Note != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(f.Note))
+		}
+	}
+	return nil
+}
+
+func runFlagsSet(cmd *cobra.Command, args []string) error {
+	f, err := config.LookupFeatureFlag(args[0])
+	if err != nil {
+		return err
+	}
+	var value *bool
+	switch args[1] {
+	case "on", "true":
+		v := true
+		value = &v
+	case "off", "false":
+		v := false
+		value = &v
+	case "default":
+	default:
+		return fmt.Errorf("invalid value %q (want on, off, or default)", args[1])
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if flagsSetRig == "" {
+		path := config.TownSettingsPath(townRoot)
+		settings, err := config.LoadTownSettingsBase(path)
+		if err != nil {
+			return fmt.Errorf("loading town settings: %w", err)
+		}
+		settings.FeatureFlags = setFeatureOverride(settings.FeatureFlags, f.Name, value)
+		if err := config.SaveTownSettings(path, settings); err != nil {
+			return fmt.Errorf("saving town settings: %w", err)
+		}
+	} else {
+		rigs, err := rigsconfig.Load(townRoot)
+		if err != nil {
+			return fmt.Errorf("loading rigs: %w", err)
+		}
+		if _, ok := rigs.Rigs[flagsSetRig]; !ok {
+			return fmt.Errorf("rig %q not found", flagsSetRig)
+		}
+		path := config.RigSettingsPath(filepath.Join(townRoot, flagsSetRig))
+		settings, err := config.LoadRigSettings(path)
+		if errors.Is(err, config.ErrNotFound) {
+			settings, err = config.NewRigSettings(), nil
+		}
+		if err != nil {
+			return fmt.Errorf("loading rig settings: %w", err)
+		}
+		settings.FeatureFlags = setFeatureOverride(settings.FeatureFlags, f.Name, value)
+		if err := config.SaveRigSettings(path, settings); err != nil {
+			return fmt.Errorf("saving rig settings: %w", err)
+		}
+	}
+
+	scope := "town"
+	if flagsSetRig != "" {
+		scope = flagsSetRig
+	}
+	effective := config.FeatureEnabled(townRoot, flagsSetRig, f.Name)
+	fmt.Printf("%s %s is %s for %s\n", style.Success.Render("✓"), f.Name, flagValue(effective), scope)
+	if f.Stage == config.FeatureStageDeprecated {
+		fmt.Printf("%s %s is deprecated", style.Warning.Render("⚠"), f.Name)
+		if f.Note != "" {
+			fmt.Printf(": %s", f.Note)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// setFeatureOverride sets name to *value in overrides, or removes it when
+// value is nil. Returns nil rather than an empty map so the key is omitted.
+func setFeatureOverride(overrides map[string]bool, name string, value *bool) map[string]bool {
+	if value == nil {
+		delete(overrides, name)
+	} else {
+		if overrides == nil {
+			overrides = make(map[string]bool)
+		}
+		overrides[name] = *value
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	return overrides
+}
+
+func flagValue(enabled bool) string {
+	if enabled {
+		return style.Success.Render("on ")
+	}
+	return style.Dim.Render("off")
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import "testing"
+
+func TestSetFeatureOverride(t *testing.T) {
+	on, off := true, false
+
+	got := setFeatureOverride(nil, "merge-queue", &off)
+	if v, ok := got["merge-queue"]; !ok || v {
+		t.Fatalf("set off = %v, want merge-queue: false", got)
+	}
+	got = setFeatureOverride(got, "dolt-prewarm", &on)
+	if len(got) != 2 || !got["dolt-prewarm"] {
+		t.Fatalf("set on = %v, want both flags", got)
+	}
+	got = setFeatureOverride(got, "merge-queue", nil)
+	if _, ok := got["merge-queue"]; ok || len(got) != 1 {
+		t.Fatalf("default = %v, want merge-queue removed", got)
+	}
+	if got = setFeatureOverride(got, "dolt-prewarm", nil); got != nil {
+		t.Errorf("removing the last override = %v, want nil", got)
+	}
+}
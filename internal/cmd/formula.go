@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/text/cases"
@@ -182,7 +183,7 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return proc.RunCmd(bdCmd)
 }
 
 // runFormulaShow delegates to bd formula show
@@ -196,7 +197,7 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return proc.RunCmd(bdCmd)
 }
 
 // runFormulaRun executes a formula by spawning a convoy of polecats.
@@ -408,7 +409,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 	createCmd := exec.Command("bd", createArgs...)
 	createCmd.Dir = townBeads
 	createCmd.Stderr = os.Stderr
-	if err := createCmd.Run(); err != nil {
+	if err := proc.RunCmd(createCmd); err != nil {
 		return fmt.Errorf("creating convoy bead: %w", err)
 	}
 
@@ -513,7 +514,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 		legCmd := exec.Command("bd", legArgs...)
 		legCmd.Dir = townBeads
 		legCmd.Stderr = os.Stderr
-		if err := legCmd.Run(); err != nil {
+		if err := proc.RunCmd(legCmd); err != nil {
 			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
 			continue
@@ -523,7 +524,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 		trackArgs := []string{"dep", "add", convoyID, legBeadID, "--type=tracks"}
 		trackCmd := exec.Command("bd", trackArgs...)
 		trackCmd.Dir = townBeads
-		if err := trackCmd.Run(); err != nil {
+		if err := proc.RunCmd(trackCmd); err != nil {
 			fmt.Printf("%s Failed to track leg %s: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
 		}
@@ -556,7 +557,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 		synCmd := exec.Command("bd", synArgs...)
 		synCmd.Dir = townBeads
 		synCmd.Stderr = os.Stderr
-		if err := synCmd.Run(); err != nil {
+		if err := proc.RunCmd(synCmd); err != nil {
 			fmt.Printf("%s Failed to create synthesis bead: %v\n",
 				style.Dim.Render("Warning:"), err)
 		} else {
@@ -564,14 +565,14 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 			trackArgs := []string{"dep", "add", convoyID, synthesisBeadID, "--type=tracks"}
 			trackCmd := exec.Command("bd", trackArgs...)
 			trackCmd.Dir = townBeads
-			_ = trackCmd.Run()
+			_ = proc.RunCmd(trackCmd)
 
 			// Add dependencies: synthesis depends on all legs
 			for _, legBeadID := range legBeads {
 				depArgs := []string{"dep", "add", synthesisBeadID, legBeadID}
 				depCmd := exec.Command("bd", depArgs...)
 				depCmd.Dir = townBeads
-				_ = depCmd.Run()
+				_ = proc.RunCmd(depCmd)
 			}
 
 			fmt.Printf("  %s Created synthesis: %s\n", style.Dim.Render("★"), synthesisBeadID)
@@ -609,7 +610,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 			commentArgs := []string{"comment", legBeadID, fmt.Sprintf("Failed to sling: %v", err)}
 			commentCmd := exec.Command("bd", commentArgs...)
 			commentCmd.Dir = townBeads
-			_ = commentCmd.Run()
+			_ = proc.RunCmd(commentCmd)
 			continue
 		}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -86,7 +87,7 @@ func runGateWake(cmd *cobra.Command, args []string) error {
 
 	// Get gate info
 	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gateOutput, err := proc.Output(gateCheck)
 	if err != nil {
 		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
 	}
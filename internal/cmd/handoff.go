@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -463,6 +464,23 @@ var claudeEnvVars = []string{
 // This needs to be the actual command to execute (e.g., claude), not a session attach command.
 // The command includes a cd to the correct working directory for the role.
 func buildRestartCommand(sessionName string) (string, error) {
+	return buildRestartCommandWithOptions(sessionName, restartOptions{})
+}
+
+// restartOptions customize buildRestartCommandWithOptions.
+type restartOptions struct {
+	// Agent replaces the session's current agent (GT_AGENT) for the restart.
+	Agent string
+
+	// ResumeSessionID resumes this runtime session (e.g. a Claude session ID)
+	// instead of starting a fresh conversation. Ignored when Agent is empty
+	// or the agent doesn't support flag-style resume.
+	ResumeSessionID string
+}
+
+// buildRestartCommandWithOptions is buildRestartCommand with an optional
+// agent override and conversation resume.
+func buildRestartCommandWithOptions(sessionName string, opts restartOptions) (string, error) {
 	// Detect town root from current directory
 	townRoot := detectTownRootFromCwd()
 	if townRoot == "" {
@@ -508,7 +526,10 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// If so, preserve it across handoff by using the override variant.
 	// Fall back to tmux session environment if process env doesn't have it,
 	// since exec env vars may not propagate through all agent runtimes.
-	currentAgent := os.Getenv("GT_AGENT")
+	currentAgent := opts.Agent
+	if currentAgent == "" {
+		currentAgent = os.Getenv("GT_AGENT")
+	}
 	if currentAgent == "" {
 		t := tmux.NewTmux()
 		if val, err := t.GetEnvironment(sessionName, "GT_AGENT"); err == nil && val != "" {
@@ -522,6 +543,13 @@ func buildRestartCommand(sessionName string) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("resolving agent config: %w", err)
 		}
+		if opts.Agent != "" && opts.ResumeSessionID != "" {
+			if rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, currentAgent); err == nil {
+				if resumeCmd := config.BuildResumeCommandWithConfig(rc, opts.ResumeSessionID, beacon); resumeCmd != "" {
+					runtimeCmd = resumeCmd
+				}
+			}
+		}
 	} else {
 		runtimeCmd = config.GetRuntimeCommandWithPrompt(rigPath, beacon)
 	}
@@ -702,6 +730,26 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 		return nil
 	}
 
+	if err := respawnSessionPane(t, targetSession, targetPane, restartCmd); err != nil {
+		return err
+	}
+
+	// If --watch, switch to that session
+	if handoffWatch {
+		fmt.Printf("Switching to %s...\n", targetSession)
+		// Use tmux switch-client to move our view to the target session
+		if err := exec.Command("tmux", "-u", "switch-client", "-t", targetSession).Run(); err != nil {
+			// Non-fatal - they can manually switch
+			fmt.Printf("Note: Could not auto-switch (use: tmux switch-client -t %s)\n", targetSession)
+		}
+	}
+
+	return nil
+}
+
+// respawnSessionPane replaces the process in a session's pane with restartCmd,
+// killing the old process tree and clearing scrollback first.
+func respawnSessionPane(t *tmux.Tmux, targetSession, targetPane, restartCmd string) error {
 	// Set remain-on-exit so the pane survives process death during handoff.
 	// Without this, killing processes causes tmux to destroy the pane before
 	// we can respawn it. This is essential for tmux session reuse.
@@ -738,17 +786,6 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 	if respawnErr != nil {
 		return fmt.Errorf("respawning pane: %w", respawnErr)
 	}
-
-	// If --watch, switch to that session
-	if handoffWatch {
-		fmt.Printf("Switching to %s...\n", targetSession)
-		// Use tmux switch-client to move our view to the target session
-		if err := exec.Command("tmux", "-u", "switch-client", "-t", targetSession).Run(); err != nil {
-			// Non-fatal - they can manually switch
-			fmt.Printf("Note: Could not auto-switch (use: tmux switch-client -t %s)\n", targetSession)
-		}
-	}
-
 	return nil
 }
 
@@ -823,7 +860,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return "", fmt.Errorf("creating handoff mail: %s", errMsg)
@@ -842,7 +879,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	hookCmd.Env = append(os.Environ(), "BEADS_DIR="+filepath.Join(townRoot, ".beads"))
 	hookCmd.Stderr = os.Stderr
 
-	if err := hookCmd.Run(); err != nil {
+	if err := proc.RunCmd(hookCmd); err != nil {
 		// Non-fatal: mail was created, just couldn't hook
 		style.PrintWarning("created mail %s but failed to auto-hook: %v", beadID, err)
 		return beadID, nil
@@ -889,7 +926,7 @@ func looksLikeBeadID(s string) bool {
 func hookBeadForHandoff(beadID string) error {
 	// Verify the bead exists first
 	verifyCmd := exec.Command("bd", "show", beadID, "--json")
-	if err := verifyCmd.Run(); err != nil {
+	if err := proc.RunCmd(verifyCmd); err != nil {
 		return fmt.Errorf("bead '%s' not found", beadID)
 	}
 
@@ -909,7 +946,7 @@ func hookBeadForHandoff(beadID string) error {
 	// Pin the bead using bd update (discovery-based approach)
 	pinCmd := exec.Command("bd", "update", beadID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := proc.RunCmd(pinCmd); err != nil {
 		return fmt.Errorf("pinning bead: %w", err)
 	}
 
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var heartbeatQuiet bool
+
+var heartbeatCmd = &cobra.Command{
+	Use:     "heartbeat",
+	GroupID: GroupAgents,
+	Short:   "Report that the current agent is alive and making progress",
+	Long: `Record a heartbeat on the current agent's bead.
+
+Sets last_heartbeat on the agent bead to the current time. A live tmux
+session can't tell "working" from "wedged"; heartbeat age can. The
+daemon and witness patrols treat an agent with hooked work whose
+heartbeat has gone stale as wedged.
+
+Agents that never send a heartbeat are judged by tmux liveness alone.
+Closing a molecule step with 'gt mol step done' sends a heartbeat
+automatically, so agents following a molecule only need to call this
+during long-running steps.
+
+Examples:
+  gt heartbeat       # Record a heartbeat
+  gt heartbeat -q    # Quiet (for scripts and hooks)`,
+	Args: cobra.NoArgs,
+	RunE: runHeartbeat,
+}
+
+func init() {
+	heartbeatCmd.Flags().BoolVarP(&heartbeatQuiet, "quiet", "q", false, "Suppress output")
+	rootCmd.AddCommand(heartbeatCmd)
+}
+
+func runHeartbeat(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	agentBeadID, err := sendHeartbeat(townRoot, cwd)
+	if err != nil {
+		return err
+	}
+	if !heartbeatQuiet {
+		fmt.Printf("%s Heartbeat recorded for %s\n", style.SuccessPrefix, agentBeadID)
+	}
+	return nil
+}
+
+// sendHeartbeat sets last_heartbeat on the agent bead for the role at cwd
+// and returns the agent bead ID.
+func sendHeartbeat(townRoot, cwd string) (string, error) {
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return "", fmt.Errorf("determining role: %w", err)
+	}
+	ctx := RoleContext{
+		Role:     roleInfo.Role,
+		Rig:      roleInfo.Rig,
+		Polecat:  roleInfo.Polecat,
+		TownRoot: townRoot,
+		WorkDir:  cwd,
+	}
+	agentBeadID := getAgentBeadID(ctx)
+	if agentBeadID == "" {
+		return "", fmt.Errorf("could not determine agent bead ID for role %s", roleInfo.Role)
+	}
+
+	if err := beads.New(townRoot).UpdateAgentHeartbeat(agentBeadID, time.Now()); err != nil {
+		return "", fmt.Errorf("recording heartbeat on %s: %w", agentBeadID, err)
+	}
+	return agentBeadID, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/idempotency"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// idempotentOp is a command running under an idempotency key (see
+// internal/idempotency). A nil *idempotentOp means no key was given, and
+// its methods do nothing.
+type idempotentOp struct {
+	townRoot string
+	key      string
+}
+
+// beginIdempotent claims the command's idempotency key: explicitKey if
+// set, else one derived from op and parts when derive is set. If the key
+// was used within the TTL it prints the earlier result and reports skip;
+// the caller returns without running the operation.
+func beginIdempotent(townRoot, op, explicitKey string, derive bool, parts ...string) (o *idempotentOp, skip bool, err error) {
+	key := explicitKey
+	if key == "" && derive {
+		key = idempotency.DeriveKey(op, parts...)
+	}
+	if key == "" || townRoot == "" {
+		return nil, false, nil
+	}
+	existing, err := idempotency.Begin(townRoot, key, op, idempotency.DefaultTTL)
+	if err != nil {
+		return nil, false, fmt.Errorf("checking idempotency key: %w", err)
+	}
+	if existing != nil {
+		fmt.Printf("%s Skipped: %s\n", style.Dim.Render("○"), existing)
+		fmt.Printf("  %s\n", style.Dim.Render("idempotency key "+key))
+		return nil, true, nil
+	}
+	return &idempotentOp{townRoot: townRoot, key: key}, false, nil
+}
+
+// finish records the operation's result, or releases the key if it failed
+// so a retry runs again.
+func (o *idempotentOp) finish(err error, result string) {
+	if o == nil {
+		return
+	}
+	if err != nil {
+		_ = idempotency.Release(o.townRoot, o.key)
+		return
+	}
+	if recErr := idempotency.Complete(o.townRoot, o.key, result); recErr != nil {
+		fmt.Printf("%s Could not record idempotency key: %v\n", style.Dim.Render("Warning:"), recErr)
+	}
+}
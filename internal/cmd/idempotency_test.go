@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBeginIdempotent(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if op, skip, err := beginIdempotent(townRoot, "nudge", "", false, "gastown/alpha", "hi"); op != nil || skip || err != nil {
+		t.Fatalf("without a key = %v, %v, %v; want no-op", op, skip, err)
+	}
+
+	op, skip, err := beginIdempotent(townRoot, "nudge", "", true, "gastown/alpha", "hi")
+	if err != nil || skip || op == nil {
+		t.Fatalf("first derived claim = %v, %v, %v; want a claim", op, skip, err)
+	}
+	op.finish(errors.New("session not found"), "")
+	op, skip, err = beginIdempotent(townRoot, "nudge", "", true, "gastown/alpha", "hi")
+	if err != nil || skip {
+		t.Fatalf("claim after a failure = %v, %v; want a fresh claim", skip, err)
+	}
+	op.finish(nil, "nudged gastown/alpha (immediate)")
+
+	if _, skip, _ := beginIdempotent(townRoot, "nudge", "", true, "gastown/alpha", "hi"); !skip {
+		t.Error("repeat after success was not skipped")
+	}
+	if _, skip, _ := beginIdempotent(townRoot, "nudge", "", true, "gastown/alpha", "other"); skip {
+		t.Error("different message was skipped")
+	}
+}
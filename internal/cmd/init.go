@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -158,7 +159,7 @@ func registerCustomTypes(workDir string) error {
 	// Try to set custom types
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		// Check for common expected errors
 		outStr := string(output)
@@ -13,6 +13,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deps"
@@ -213,7 +215,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 			Version: config.CurrentRigsVersion,
 			Rigs:    make(map[string]config.RigEntry),
 		}
-		if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		if err := rigsconfig.SaveFile(rigsPath, rigsConfig); err != nil {
 			return fmt.Errorf("writing rigs.json: %w", err)
 		}
 		fmt.Printf("   ✓ Created mayor/rigs.json\n")
@@ -350,7 +352,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		// Set beads routing mode to explicit (required by gt doctor).
 		routingCmd := exec.Command("bd", "config", "set", "routing.mode", "explicit")
 		routingCmd.Dir = absPath
-		if out, err := routingCmd.CombinedOutput(); err != nil {
+		if out, err := proc.CombinedOutput(routingCmd); err != nil {
 			fmt.Printf("   %s Could not set routing.mode: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(out)))
 		}
 	}
@@ -498,7 +500,7 @@ func initTownBeads(townPath string) error {
 	cmd := exec.Command("bd", "init", "--prefix", "hq", "--backend", "dolt", "--server")
 	cmd.Dir = townPath
 
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		// Check if beads is already initialized
 		if strings.Contains(string(output), "already initialized") {
@@ -517,7 +519,7 @@ func initTownBeads(townPath string) error {
 	// Explicitly set issue_prefix config (bd init --prefix may not persist it in newer versions).
 	prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", "hq")
 	prefixSetCmd.Dir = townPath
-	if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+	if prefixOutput, prefixErr := proc.CombinedOutput(prefixSetCmd); prefixErr != nil {
 		return fmt.Errorf("bd config set issue_prefix failed: %s", strings.TrimSpace(string(prefixOutput)))
 	}
 
@@ -531,7 +533,7 @@ func initTownBeads(townPath string) error {
 	// This allows bd create --id=hq-cv-xxx to pass prefix validation.
 	prefixCmd := exec.Command("bd", "config", "set", "allowed_prefixes", "hq,hq-cv")
 	prefixCmd.Dir = townPath
-	if prefixOutput, prefixErr := prefixCmd.CombinedOutput(); prefixErr != nil {
+	if prefixOutput, prefixErr := proc.CombinedOutput(prefixCmd); prefixErr != nil {
 		fmt.Printf("   %s Could not set allowed_prefixes: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(prefixOutput)))
 	}
 
@@ -575,7 +577,7 @@ func initTownBeads(townPath string) error {
 func ensureRepoFingerprint(beadsPath string) error {
 	cmd := exec.Command("bd", "migrate", "--update-repo-id")
 	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd migrate --update-repo-id: %s", strings.TrimSpace(string(output)))
 	}
@@ -589,7 +591,7 @@ func ensureRepoFingerprint(beadsPath string) error {
 func ensureCustomTypes(beadsPath string) error {
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
 	}
@@ -680,7 +682,7 @@ func ensureBeadsCustomTypes(workDir string, types []string) error {
 
 	cmd := exec.Command("bd", "config", "set", "types.custom", strings.Join(types, ","))
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom failed: %s", strings.TrimSpace(string(output)))
 	}
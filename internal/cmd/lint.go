@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	lintRigs  []string
+	lintRules []string
+	lintJSON  bool
+	lintFix   bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:     "lint",
+	GroupID: GroupDiag,
+	Short:   "Check Gas Town data for convention violations",
+	RunE:    requireSubcommand,
+}
+
+var lintBeadsCmd = &cobra.Command{
+	Use:   "beads",
+	Short: "Lint rig databases for bead convention violations",
+	Long: `Scan town and rig beads for convention violations.
+
+Rules:
+  id-prefix       error     Bead ID prefix matches the rig's beads prefix
+  orphaned-step   error     Molecule steps have an existing parent
+  agent-label     warning   Agent beads carry the gt:agent label (fixable)
+  orphaned-wisp   warning   Open wisps belong to an open parent (fixable: close)
+  handoff-owner   warning   Handoff beads have an owner (fixable: assign role)
+
+--fix applies the safe fixes; error-level rules are never auto-fixed.
+Exits 1 if any error-level violation remains.
+
+Examples:
+  gt lint beads                          # All sources
+  gt lint beads --rig gastown            # One rig
+  gt lint beads --rule agent-label --fix # Fix one rule
+  gt lint beads --json                   # Machine-readable findings`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runLintBeads,
+}
+
+func init() {
+	lintBeadsCmd.Flags().StringSliceVar(&lintRigs, "rig", nil, "Rig(s) to lint (default: town and all rigs; 'town' for town beads)")
+	lintBeadsCmd.Flags().StringSliceVar(&lintRules, "rule", nil, "Rule(s) to run (default: all)")
+	lintBeadsCmd.Flags().BoolVar(&lintJSON, "json", false, "Output findings as JSON")
+	lintBeadsCmd.Flags().BoolVar(&lintFix, "fix", false, "Apply safe auto-fixes")
+
+	lintCmd.AddCommand(lintBeadsCmd)
+	rootCmd.AddCommand(lintCmd)
+}
+
+// lintSource is one beads database to lint.
+type lintSource struct {
+	name   string
+	path   string
+	prefix string
+}
+
+// LintSourceResult is the lint outcome for one source.
+type LintSourceResult struct {
+	Source   string              `json:"source"`
+	Findings []beads.LintFinding `json:"findings"`
+	Fixed    []string            `json:"fixed,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+func runLintBeads(cmd *cobra.Command, args []string) error {
+	for _, name := range lintRules {
+		if _, ok := beads.LookupLintRule(name); !ok {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sources, err := lintSources(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var results []LintSourceResult
+	var errorCount, warningCount, fixedCount int
+	for _, src := range sources {
+		res := LintSourceResult{Source: src.name, Findings: []beads.LintFinding{}}
+		bd := beads.New(src.path)
+		issues, err := bd.List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			res.Error = err.Error()
+			results = append(results, res)
+			continue
+		}
+
+		findings := beads.LintIssues(issues, beads.LintOptions{Prefix: src.prefix, Rules: lintRules})
+		var fix func(beads.LintFinding) error
+		if lintFix {
+			fix = func(f beads.LintFinding) error { return f.Fix(bd) }
+		}
+		errs, warnings, fixed := recordLintFindings(&res, findings, fix)
+		errorCount += errs
+		warningCount += warnings
+		fixedCount += fixed
+		results = append(results, res)
+	}
+
+	if lintJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printLintResults(results, errorCount, warningCount, fixedCount)
+	}
+
+	if errorCount > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// recordLintFindings adds findings to res, first applying fix (if non-nil)
+// to the fixable ones. A fixed finding is listed in res.Fixed; one whose
+// fix failed stays a finding with the error in FixError. Returns the
+// error, warning and fixed counts.
+func recordLintFindings(res *LintSourceResult, findings []beads.LintFinding, fix func(beads.LintFinding) error) (errs, warnings, fixed int) {
+	for _, f := range findings {
+		if fix != nil && f.Fixable {
+			err := fix(f)
+			if err == nil {
+				res.Fixed = append(res.Fixed, f.BeadID)
+				fixed++
+				continue
+			}
+			f.FixError = err.Error()
+		}
+		res.Findings = append(res.Findings, f)
+		if f.Severity == beads.LintError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	return errs, warnings, fixed
+}
+
+// lintSources resolves --rig into beads databases with their ID prefixes.
+func lintSources(townRoot string) ([]lintSource, error) {
+	rigsConfig, err := rigsconfig.Load(townRoot)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigs, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).DiscoverRigs()
+	if err != nil {
+		return nil, fmt.Errorf("discovering rigs: %w", err)
+	}
+
+	all := []lintSource{{name: "town", path: beads.GetTownBeadsPath(townRoot), prefix: "hq"}}
+	for _, r := range rigs {
+		all = append(all, lintSource{
+			name:   r.Name,
+			path:   r.BeadsPath(),
+			prefix: beads.GetPrefixForRig(townRoot, r.Name),
+		})
+	}
+	if len(lintRigs) == 0 {
+		return all, nil
+	}
+
+	var selected []lintSource
+	for _, name := range lintRigs {
+		found := false
+		for _, src := range all {
+			if src.name == name {
+				selected = append(selected, src)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("rig not found: %s", name)
+		}
+	}
+	return selected, nil
+}
+
+func printLintResults(results []LintSourceResult, errorCount, warningCount, fixedCount int) {
+	for _, res := range results {
+		switch {
+		case res.Error != "":
+			fmt.Printf("%s %s\n", style.Dim.Render(res.Source+"/"), style.Warning.Render("(error: "+res.Error+")"))
+			continue
+		case len(res.Findings) == 0 && len(res.Fixed) == 0:
+			fmt.Printf("%s %s\n", style.Success.Render("✓"), res.Source)
+			continue
+		}
+
+		fmt.Printf("%s\n", style.Bold.Render(res.Source+"/"))
+		for _, f := range res.Findings {
+			mark := style.Warning.Render("⚠")
+			if f.Severity == beads.LintError {
+				mark = style.Error.Render("✗")
+			}
+			hint := ""
+			if f.Fixable {
+				hint = " " + style.Dim.Render("(fixable)")
+			}
+			fmt.Printf("  %s %s %s: %s%s\n", mark, f.BeadID, style.Dim.Render("["+f.Rule+"]"), f.Message, hint)
+			if f.FixError != "" {
+				fmt.Printf("    %s\n", style.Error.Render("fix failed: "+f.FixError))
+			}
+		}
+		if len(res.Fixed) > 0 {
+			fmt.Printf("  %s fixed: %s\n", style.Success.Render("✓"), strings.Join(res.Fixed, ", "))
+		}
+	}
+
+	fmt.Printf("\n%d error(s), %d warning(s)", errorCount, warningCount)
+	if fixedCount > 0 {
+		fmt.Printf(", %d fixed", fixedCount)
+	}
+	fmt.Println()
+	if !lintFix && hasFixableFindings(results) {
+		fmt.Printf("Run %s to apply safe fixes.\n", style.Bold.Render("gt lint beads --fix"))
+	}
+}
+
+func hasFixableFindings(results []LintSourceResult) bool {
+	for _, res := range results {
+		for _, f := range res.Findings {
+			if f.Fixable {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestRecordLintFindings_KeepsFailedFixes(t *testing.T) {
+	findings := []beads.LintFinding{
+		{Rule: "agent-label", Severity: beads.LintWarning, BeadID: "gt-1", Fixable: true},
+		{Rule: "orphaned-wisp", Severity: beads.LintWarning, BeadID: "gt-2", Fixable: true},
+		{Rule: "id-prefix", Severity: beads.LintError, BeadID: "xx-3"},
+	}
+	fix := func(f beads.LintFinding) error {
+		if f.BeadID == "gt-2" {
+			return errors.New("bd close: database is read-only")
+		}
+		return nil
+	}
+
+	res := LintSourceResult{Source: "gastown", Findings: []beads.LintFinding{}}
+	errs, warnings, fixed := recordLintFindings(&res, findings, fix)
+	if errs != 1 || warnings != 1 || fixed != 1 {
+		t.Errorf("counts = %d errors, %d warnings, %d fixed; want 1, 1, 1", errs, warnings, fixed)
+	}
+	if strings.Join(res.Fixed, ",") != "gt-1" {
+		t.Errorf("Fixed = %v, want [gt-1]", res.Fixed)
+	}
+	if len(res.Findings) != 2 || res.Findings[0].BeadID != "gt-2" || res.Findings[0].FixError != "bd close: database is read-only" {
+		t.Fatalf("Findings = %+v, want gt-2 with its fix error, then xx-3", res.Findings)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"fix_error":"bd close: database is read-only"`) {
+		t.Errorf("JSON missing fix_error: %s", data)
+	}
+}
+
+func TestRecordLintFindings_NoFix(t *testing.T) {
+	res := LintSourceResult{Findings: []beads.LintFinding{}}
+	findings := []beads.LintFinding{{Rule: "agent-label", Severity: beads.LintWarning, BeadID: "gt-1", Fixable: true}}
+	if _, warnings, fixed := recordLintFindings(&res, findings, nil); warnings != 1 || fixed != 0 {
+		t.Errorf("without --fix: %d warnings, %d fixed; want 1, 0", warnings, fixed)
+	}
+	if res.Findings[0].FixError != "" {
+		t.Errorf("FixError set without --fix: %q", res.Findings[0].FixError)
+	}
+}
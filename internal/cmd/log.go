@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crashlog"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -340,6 +342,7 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 		if crashSession != "" {
 			context += fmt.Sprintf(" (session: %s)", crashSession)
 		}
+		captureCrashReport(townRoot)
 	}
 
 	// Log the event
@@ -351,6 +354,29 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// captureCrashReport saves the dead pane's final output for polecats.
+// The pane-died hook runs while the dead pane still exists, so this is the
+// last chance to read it. Best-effort: failures are ignored.
+func captureCrashReport(townRoot string) {
+	rigName, polecatName := crashlog.ParseAgent(crashAgent)
+	if rigName == "" || crashSession == "" {
+		return
+	}
+	status := crashExitCode
+	report := &crashlog.Report{
+		Rig:        rigName,
+		Polecat:    polecatName,
+		Agent:      crashAgent,
+		Session:    crashSession,
+		ExitStatus: &status,
+		DetectedBy: crashlog.DetectedByHook,
+	}
+	if lines, err := tmux.NewTmux().CapturePaneLines(crashSession, crashlog.DefaultOutputLines); err == nil {
+		report.Output = crashlog.TrimOutput(lines)
+	}
+	_, _ = crashlog.Save(townRoot, report)
+}
+
 // LogEvent is a helper that logs an event from anywhere in the codebase.
 // It finds the town root and logs the event.
 func LogEvent(eventType townlog.EventType, agent, context string) error {
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
 )
 
 // getMailbox returns the mailbox for the given address.
@@ -109,7 +110,7 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 
 		// Show 1-based index for easy reference with 'gt mail read <n>'
 		indexStr := style.Dim.Render(fmt.Sprintf("%d.", i+1))
-		fmt.Printf("  %s %s %s%s%s%s\n", indexStr, readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
+		fmt.Printf("  %s %s %s%s%s%s\n", indexStr, readMarker, ui.TruncateField(msg.Subject), typeMarker, priorityMarker, wispMarker)
 		fmt.Printf("      %s from %s\n",
 			style.Dim.Render(msg.ID),
 			msg.From)
@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -133,6 +134,10 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	recipients, err := resolver.Resolve(to)
 	if err != nil {
 		// Fall back to legacy routing if resolver fails
+		if d, limited := mailRateLimited(townRoot, msg, to); limited {
+			fmt.Printf("%s Message to %s suppressed (%s)\n", style.Dim.Render("○"), to, d)
+			return nil
+		}
 		router := mail.NewRouter(workDir)
 		if err := router.Send(msg); err != nil {
 			return fmt.Errorf("sending message: %w", err)
@@ -147,6 +152,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	router := mail.NewRouter(workDir)
 	var recipientAddrs []string
 	var sendErrs []string
+	var suppressed []string
 
 	for _, rec := range recipients {
 		switch rec.Type {
@@ -170,6 +176,10 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 
 		default:
 			// Direct/agent messages: fan out to each recipient
+			if d, limited := mailRateLimited(townRoot, msg, rec.Address); limited {
+				suppressed = append(suppressed, fmt.Sprintf("%s (%s)", rec.Address, d))
+				continue
+			}
 			msgCopy := *msg
 			msgCopy.To = rec.Address
 			msgCopy.ID = "" // Each fan-out copy gets its own unique ID
@@ -182,11 +192,15 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(sendErrs) > 0 {
-		if len(recipientAddrs) == 0 {
+		if len(recipientAddrs) == 0 && len(suppressed) == 0 {
 			return fmt.Errorf("all sends failed: %s", strings.Join(sendErrs, "; "))
 		}
 		fmt.Fprintf(os.Stderr, "⚠ Some deliveries failed: %s\n", strings.Join(sendErrs, "; "))
 	}
+	if len(recipientAddrs) == 0 && len(suppressed) > 0 {
+		fmt.Printf("%s Message suppressed for all recipients: %s\n", style.Dim.Render("○"), strings.Join(suppressed, ", "))
+		return nil
+	}
 
 	// Log mail event to activity feed
 	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
@@ -205,10 +219,23 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
+	if len(suppressed) > 0 {
+		fmt.Printf("  %s %s\n", style.Dim.Render("Suppressed:"), strings.Join(suppressed, ", "))
+	}
 
 	return nil
 }
 
+// mailRateLimited checks a direct message to address against the per-recipient
+// rate limiter. Urgent mail is never limited.
+func mailRateLimited(townRoot string, msg *mail.Message, address string) (ratelimit.Decision, bool) {
+	if townRoot == "" || msg.Priority == mail.PriorityUrgent {
+		return ratelimit.Decision{Allowed: true}, false
+	}
+	d, _ := ratelimit.Allow(townRoot, ratelimit.KindMail, address, msg.Subject+"\n"+msg.Body)
+	return d, !d.Allowed
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
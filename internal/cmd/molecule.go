@@ -6,7 +6,8 @@ import (
 
 // Molecule command flags
 var (
-	moleculeJSON bool
+	moleculeJSON        bool
+	moleculeAttachForce bool
 )
 
 var moleculeCmd = &cobra.Command{
@@ -34,6 +35,9 @@ LIFECYCLE:
   gt mol burn          Discard attached molecule (no record)
   gt mol squash        Compress to digest (permanent record)
 
+ANALYTICS:
+  gt mol stats         Formula completion, step, and rejection stats
+
 TO DISPATCH WORK (with molecules):
   gt sling mol-xxx target   # Pour formula + sling to agent
   gt formulas               # List available formulas`,
@@ -69,9 +73,14 @@ is stored in the pinned bead's description and visible via 'bd show'.
 When called with a single argument from an agent working directory, the
 pinned bead ID is auto-detected from the current agent's hook.
 
+Attaching is guarded against concurrent agents: if a different molecule is
+already attached (or gets attached while this runs), the command fails with
+a conflict instead of replacing it. Re-attaching the same molecule is a no-op.
+
 Examples:
-  gt molecule attach gt-abc mol-xyz  # Explicit pinned bead
-  gt molecule attach mol-xyz         # Auto-detect from cwd`,
+  gt molecule attach gt-abc mol-xyz          # Explicit pinned bead
+  gt molecule attach mol-xyz                 # Auto-detect from cwd
+  gt molecule attach gt-abc mol-xyz --force  # Replace the current attachment`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runMoleculeAttach,
 }
@@ -228,6 +237,9 @@ func init() {
 	// Progress flags
 	moleculeProgressCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
 
+	// Attach flags
+	moleculeAttachCmd.Flags().BoolVar(&moleculeAttachForce, "force", false, "Replace a molecule that is already attached")
+
 	// Attachment flags
 	moleculeAttachmentCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
 
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
@@ -39,8 +40,12 @@ func runMoleculeAttach(cmd *cobra.Command, args []string) error {
 	b := beads.New(workDir)
 
 	// Attach the molecule
-	issue, err := b.AttachMolecule(pinnedBeadID, moleculeID)
+	issue, err := b.AttachMoleculeWithOptions(pinnedBeadID, moleculeID, beads.AttachOptions{Force: moleculeAttachForce})
 	if err != nil {
+		if errors.Is(err, beads.ErrAttachmentConflict) {
+			return fmt.Errorf("attaching molecule: %w\n  check it with 'gt mol attachment %s', then detach it or rerun with --force to replace it",
+				err, pinnedBeadID)
+		}
 		return fmt.Errorf("attaching molecule: %w", err)
 	}
 
@@ -53,6 +58,16 @@ func runMoleculeAttach(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// attachConflictHint adds what to do next to an attachment conflict error.
+// Other errors are returned unchanged.
+func attachConflictHint(pinnedBeadID string, err error) error {
+	if !errors.Is(err, beads.ErrAttachmentConflict) {
+		return err
+	}
+	return fmt.Errorf("%w\n  another agent changed the attachment; check it with 'gt mol attachment %s' before retrying",
+		err, pinnedBeadID)
+}
+
 // detectAgentBeadID detects the current agent's bead ID from the working directory.
 // Returns the agent bead ID (e.g., "hq-mayor", "gt-gastown-polecat-nux") or empty string if not detectable.
 func detectAgentBeadID() (string, error) {
@@ -120,11 +135,12 @@ func runMoleculeDetach(cmd *cobra.Command, args []string) error {
 
 	// Detach the molecule with audit logging
 	_, err = b.DetachMoleculeWithAudit(pinnedBeadID, beads.DetachOptions{
-		Operation: "detach",
-		Agent:     detectCurrentAgent(),
+		Operation:        "detach",
+		Agent:            detectCurrentAgent(),
+		ExpectedMolecule: previousMolecule,
 	})
 	if err != nil {
-		return fmt.Errorf("detaching molecule: %w", err)
+		return attachConflictHint(pinnedBeadID, fmt.Errorf("detaching molecule: %w", err))
 	}
 
 	fmt.Printf("%s Detached %s from %s\n", style.Bold.Render("✓"), previousMolecule, pinnedBeadID)
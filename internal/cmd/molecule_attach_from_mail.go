@@ -105,7 +105,7 @@ func runMoleculeAttachFromMail(cmd *cobra.Command, args []string) error {
 	// Attach the molecule to the hook
 	issue, err := b.AttachMolecule(hookBead.ID, moleculeID)
 	if err != nil {
-		return fmt.Errorf("attaching molecule: %w", err)
+		return attachConflictHint(hookBead.ID, fmt.Errorf("attaching molecule: %w", err))
 	}
 
 	// Mark mail as read
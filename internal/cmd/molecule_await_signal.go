@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -361,7 +362,7 @@ func parseIntSimple(s string) (int, error) {
 func updateAgentHeartbeat(agentBead, beadsDir string) error {
 	cmd := exec.Command("bd", "agent", "heartbeat", agentBead)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	return cmd.Run()
+	return proc.RunCmd(cmd)
 }
 
 // setAgentIdleCycles sets the idle:N label on an agent bead.
@@ -395,7 +396,7 @@ func setAgentIdleCycles(agentBead, beadsDir string, cycles int) error {
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return fmt.Errorf("setting idle label: %w", err)
 	}
 
@@ -427,7 +428,7 @@ func setAgentBackoffUntil(agentBead, beadsDir string, until time.Time) error {
 
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return fmt.Errorf("setting backoff-until label: %w", err)
 	}
 	return nil
@@ -466,7 +467,7 @@ func clearAgentBackoffUntil(agentBead, beadsDir string) error {
 
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return fmt.Errorf("clearing backoff-until label: %w", err)
 	}
 	return nil
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// polecatStepBranch is a polecat's Dolt branch, where its molecule steps
+// are closed. Main only sees those writes once gt done merges the branch,
+// so progress read from main lags the polecat's real position.
+type polecatStepBranch struct {
+	townRoot string
+	rig      string
+	branch   string
+}
+
+// findPolecatStepBranch returns the Dolt branch of the polecat identified
+// by target (<rig>/polecats/<name>). Returns nil if target isn't a polecat,
+// the polecat has no branch, or this process already reads a branch
+// through BD_BRANCH.
+func findPolecatStepBranch(townRoot, target string) *polecatStepBranch {
+	if os.Getenv("BD_BRANCH") != "" {
+		return nil
+	}
+	parts := strings.Split(target, "/")
+	if len(parts) != 3 || parts[1] != "polecats" {
+		return nil
+	}
+	branch, err := doltserver.FindPolecatBranch(townRoot, parts[0], parts[2])
+	if err != nil || branch == "" {
+		return nil
+	}
+	return &polecatStepBranch{townRoot: townRoot, rig: parts[0], branch: branch}
+}
+
+// apply replaces each step's status with its status on the branch. Steps
+// missing from the branch, or all of them if it can't be read, keep the
+// status from main.
+func (sb *polecatStepBranch) apply(steps []*beads.Issue) {
+	if sb == nil || len(steps) == 0 {
+		return
+	}
+	ids := make([]string, len(steps))
+	for i, s := range steps {
+		ids[i] = s.ID
+	}
+	statuses, err := doltserver.BeadStatusesOnBranch(sb.townRoot, sb.rig, sb.branch, ids)
+	if err != nil {
+		return
+	}
+	for _, s := range steps {
+		if status, ok := statuses[s.ID]; ok {
+			s.Status = status
+		}
+	}
+}
@@ -88,12 +88,13 @@ func runMoleculeBurn(cmd *cobra.Command, args []string) error {
 
 	// Detach the molecule with audit logging (this "burns" it by removing the attachment)
 	_, err = b.DetachMoleculeWithAudit(handoff.ID, beads.DetachOptions{
-		Operation: "burn",
-		Agent:     target,
-		Reason:    "molecule burned by agent",
+		Operation:        "burn",
+		Agent:            target,
+		Reason:           "molecule burned by agent",
+		ExpectedMolecule: moleculeID,
 	})
 	if err != nil {
-		return fmt.Errorf("detaching molecule: %w", err)
+		return attachConflictHint(handoff.ID, fmt.Errorf("detaching molecule: %w", err))
 	}
 
 	if moleculeJSON {
@@ -245,12 +246,13 @@ squashed_at: %s
 
 	// Detach the molecule from the handoff bead with audit logging
 	_, err = b.DetachMoleculeWithAudit(handoff.ID, beads.DetachOptions{
-		Operation: "squash",
-		Agent:     target,
-		Reason:    fmt.Sprintf("molecule squashed to digest %s", digestIssue.ID),
+		Operation:        "squash",
+		Agent:            target,
+		Reason:           fmt.Sprintf("molecule squashed to digest %s", digestIssue.ID),
+		ExpectedMolecule: moleculeID,
 	})
 	if err != nil {
-		return fmt.Errorf("detaching molecule: %w", err)
+		return attachConflictHint(handoff.ID, fmt.Errorf("detaching molecule: %w", err))
 	}
 
 	if moleculeJSON {
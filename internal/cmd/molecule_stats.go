@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/molstats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	moleculeStatsSince   string
+	moleculeStatsNoSteps bool
+)
+
+var moleculeStatsCmd = &cobra.Command{
+	Use:   "stats [formula]",
+	Short: "Show how well each formula's molecules perform",
+	Long: `Show effectiveness statistics for formulas, from past molecule runs.
+
+For each formula (proto) that has been slung:
+  - Runs, completion rate, and average time from sling to gt done
+  - Per step: how often it was done, skipped, or failed, and its average
+    duration (time from the previous step closing to this one closing)
+  - Review rejections: how often the run's branch failed to merge, overall
+    and split by whether each step was done or missed
+
+Runs come from formula sling events in the activity feed (.events.jsonl),
+so history only reaches back as far as the feed is retained. Step details
+come from the molecule's wisps and are missing once the wisps are cleaned up.
+A step counts as skipped when closed with a reason starting "skip", and as
+failed when the reason mentions "fail" or the step was left open.
+
+Examples:
+  gt mol stats                       # All formulas
+  gt mol stats mol-polecat-work      # One formula
+  gt mol stats --since 7d            # Runs slung in the last week
+  gt mol stats --no-steps            # Skip the per-step lookups (faster)
+  gt mol stats --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMoleculeStats,
+}
+
+func init() {
+	moleculeStatsCmd.Flags().StringVar(&moleculeStatsSince, "since", "", "Only runs slung within this duration (e.g., 24h, 7d)")
+	moleculeStatsCmd.Flags().BoolVar(&moleculeStatsNoSteps, "no-steps", false, "Don't look up step details in beads")
+	moleculeStatsCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeCmd.AddCommand(moleculeStatsCmd)
+}
+
+func runMoleculeStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var since time.Time
+	if moleculeStatsSince != "" {
+		d, err := parseDuration(moleculeStatsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+	formula := ""
+	if len(args) == 1 {
+		formula = args[0]
+	}
+
+	runs, err := loadMoleculeRuns(townRoot, since, formula)
+	if err != nil {
+		return err
+	}
+	if !moleculeStatsNoSteps {
+		loadMoleculeSteps(townRoot, runs)
+	}
+	stats := molstats.Compute(runs)
+
+	if moleculeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	if len(stats) == 0 {
+		fmt.Printf("%s No formula runs found in the activity feed\n", style.Dim.Render("○"))
+		return nil
+	}
+	printMoleculeStats(stats)
+	return nil
+}
+
+// loadMoleculeRuns reads formula runs from the town's activity feed.
+func loadMoleculeRuns(townRoot string, since time.Time, formula string) ([]molstats.Run, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading events feed: %w", err)
+	}
+	defer f.Close()
+	return moleculeRunsFromEvents(f, since, formula)
+}
+
+// moleculeRunsFromEvents builds runs from feed events: a sling carrying a
+// formula starts a run, gt done on the slung bead finishes it, and merge
+// events on the branch done reported count as reviews and rejections.
+func moleculeRunsFromEvents(r io.Reader, since time.Time, formula string) ([]molstats.Run, error) {
+	var runs []*molstats.Run
+	byBead := make(map[string]*molstats.Run)
+	branchOf := make(map[*molstats.Run]string)
+	reviews := make(map[string]int)
+	rejections := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		str := func(key string) string {
+			s, _ := e.Payload[key].(string)
+			return s
+		}
+
+		switch e.Type {
+		case events.TypeSling:
+			name, bead := str("formula"), str("bead")
+			if name == "" || bead == "" || (formula != "" && name != formula) {
+				continue
+			}
+			if !since.IsZero() && ts.Before(since) {
+				continue
+			}
+			if _, seen := byBead[bead]; seen {
+				continue // Re-sling of the same work; keep the first
+			}
+			mol := str("molecule")
+			if mol == "" {
+				mol = bead
+			}
+			run := &molstats.Run{Formula: name, Molecule: mol, Bead: bead, Started: ts}
+			runs = append(runs, run)
+			byBead[bead] = run
+		case events.TypeDone:
+			if run := byBead[str("bead")]; run != nil && run.Finished.IsZero() {
+				run.Finished = ts
+				branchOf[run] = str("branch")
+			}
+		case events.TypeMerged:
+			reviews[str("branch")]++
+		case events.TypeMergeFailed:
+			reviews[str("branch")]++
+			rejections[str("branch")]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events feed: %w", err)
+	}
+
+	result := make([]molstats.Run, len(runs))
+	for i, run := range runs {
+		if branch := branchOf[run]; branch != "" {
+			run.Reviews = reviews[branch]
+			run.Rejections = rejections[branch]
+		}
+		result[i] = *run
+	}
+	return result, nil
+}
+
+// loadMoleculeSteps fills in each run's steps from its molecule's children.
+// Runs whose wisps are gone keep no steps.
+func loadMoleculeSteps(townRoot string, runs []molstats.Run) {
+	for i := range runs {
+		b := beads.New(beads.ResolveHookDir(townRoot, runs[i].Molecule, ""))
+		children, err := b.List(beads.ListOptions{Parent: runs[i].Molecule, Status: "all", Priority: -1})
+		if err != nil || len(children) == 0 {
+			continue
+		}
+		steps := make([]molstats.Step, len(children))
+		for j, c := range children {
+			steps[j] = molstats.Step{
+				ID:          c.ID,
+				Title:       c.Title,
+				Status:      c.Status,
+				CloseReason: c.CloseReason,
+				ClosedAt:    parseBeadsTimestamp(c.ClosedAt),
+			}
+		}
+		runs[i].Steps = steps
+	}
+}
+
+func printMoleculeStats(stats []molstats.ProtoStats) {
+	for i, p := range stats {
+		if i > 0 {
+			fmt.Println()
+		}
+		line := fmt.Sprintf("%d run(s), %s complete", p.Runs, pct(p.CompletionRate))
+		if p.AvgSeconds > 0 {
+			line += ", avg " + formatStatSeconds(p.AvgSeconds)
+		}
+		if p.Reviewed > 0 {
+			line += fmt.Sprintf(", rejected %d/%d (%s)", p.Rejected, p.Reviewed, pct(p.RejectionRate))
+		}
+		fmt.Printf("%s  %s\n", style.Bold.Render(p.Formula), style.Dim.Render(line))
+
+		if len(p.Steps) == 0 {
+			continue
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  STEP\tDONE\tSKIP\tFAIL\tAVG\tREJECTED (MISSED/DONE)")
+		for _, s := range p.Steps {
+			avg := "-"
+			if s.AvgSeconds > 0 {
+				avg = formatStatSeconds(s.AvgSeconds)
+			}
+			rej := "-"
+			if s.ReviewedMissed+s.ReviewedDone > 0 {
+				rej = fmt.Sprintf("%s / %s", pctOf(s.RejectionRateMissed, s.ReviewedMissed), pctOf(s.RejectionRateDone, s.ReviewedDone))
+			}
+			fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\t%s\t%s\n", truncateStepTitle(s.Title), s.Done, s.Skipped, s.Failed, avg, rej)
+		}
+		_ = tw.Flush()
+
+		if s := p.MostSkipped(); s != nil {
+			fmt.Printf("  %s Most skipped: %s (%d of %d runs)\n", style.Warning.Render("⚠"), s.Title, s.Skipped, s.Runs)
+		}
+		if s := p.MostFailed(); s != nil {
+			fmt.Printf("  %s Most failed: %s (%d of %d runs)\n", style.Warning.Render("⚠"), s.Title, s.Failed, s.Runs)
+		}
+	}
+}
+
+func pct(r float64) string {
+	return fmt.Sprintf("%.0f%%", r*100)
+}
+
+// pctOf formats a rate, or "-" when it is over no runs.
+func pctOf(r float64, n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return pct(r)
+}
+
+func formatStatSeconds(s float64) string {
+	d := time.Duration(s * float64(time.Second))
+	if d >= time.Minute {
+		return d.Round(time.Minute).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+func truncateStepTitle(title string) string {
+	const max = 40
+	title = strings.TrimSpace(title)
+	if len([]rune(title)) <= max {
+		return title
+	}
+	return string([]rune(title)[:max-1]) + "…"
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMoleculeRunsFromEvents(t *testing.T) {
+	feed := strings.Join([]string{
+		`{"ts":"2026-03-01T09:00:00Z","type":"sling","payload":{"bead":"gt-abc","target":"gastown/Toast","formula":"mol-polecat-work","molecule":"gt-wisp-1"}}`,
+		`{"ts":"2026-03-01T09:05:00Z","type":"sling","payload":{"bead":"gt-def","target":"gastown/Nux"}}`,
+		`{"ts":"2026-03-01T09:10:00Z","type":"sling","payload":{"bead":"hq-wisp-2","target":"deacon","formula":"mol-deacon-patrol"}}`,
+		`not json`,
+		`{"ts":"2026-03-01T10:00:00Z","type":"done","payload":{"bead":"gt-abc","branch":"polecat/toast-1"}}`,
+		`{"ts":"2026-03-01T10:10:00Z","type":"merge_failed","payload":{"branch":"polecat/toast-1","reason":"tests failed"}}`,
+		`{"ts":"2026-03-01T10:30:00Z","type":"merged","payload":{"branch":"polecat/toast-1"}}`,
+		`{"ts":"2026-03-01T10:40:00Z","type":"merged","payload":{"branch":"polecat/other"}}`,
+	}, "\n")
+
+	runs, err := moleculeRunsFromEvents(strings.NewReader(feed), time.Time{}, "")
+	if err != nil {
+		t.Fatalf("moleculeRunsFromEvents: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2 (plain slings are not formula runs): %+v", len(runs), runs)
+	}
+
+	work := runs[0]
+	if work.Formula != "mol-polecat-work" || work.Molecule != "gt-wisp-1" || work.Bead != "gt-abc" {
+		t.Errorf("run = %+v", work)
+	}
+	if work.Finished.Sub(work.Started) != time.Hour {
+		t.Errorf("run took %v, want 1h", work.Finished.Sub(work.Started))
+	}
+	if work.Reviews != 2 || work.Rejections != 1 {
+		t.Errorf("reviews/rejections = %d/%d, want 2/1", work.Reviews, work.Rejections)
+	}
+
+	patrol := runs[1]
+	if patrol.Molecule != "hq-wisp-2" || !patrol.Finished.IsZero() {
+		t.Errorf("standalone formula run = %+v, want molecule hq-wisp-2, unfinished", patrol)
+	}
+
+	runs, _ = moleculeRunsFromEvents(strings.NewReader(feed), time.Time{}, "mol-deacon-patrol")
+	if len(runs) != 1 || runs[0].Formula != "mol-deacon-patrol" {
+		t.Errorf("formula filter = %+v", runs)
+	}
+	since := time.Date(2026, 3, 1, 9, 6, 0, 0, time.UTC)
+	runs, _ = moleculeRunsFromEvents(strings.NewReader(feed), since, "")
+	if len(runs) != 1 || runs[0].Bead != "hq-wisp-2" {
+		t.Errorf("since filter = %+v", runs)
+	}
+}
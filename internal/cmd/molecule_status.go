@@ -368,6 +368,9 @@ func runMoleculeStatus(cmd *cobra.Command, args []string) error {
 	agentBeadID := buildAgentBeadID(target, roleCtx.Role, townRoot)
 	var hookBead *beads.Issue
 
+	// A polecat's step closes land on its Dolt branch; read them there.
+	stepBranch := findPolecatStepBranch(townRoot, target)
+
 	if agentBeadID != "" {
 		// Resolve the correct beads directory for the agent bead using prefix-based
 		// routing. This matches how updateAgentHookBead resolves the directory when
@@ -423,7 +426,7 @@ func runMoleculeStatus(cmd *cobra.Command, args []string) error {
 
 			// Get progress if there's an attached molecule
 			if attachment.AttachedMolecule != "" {
-				progress, _ := getMoleculeProgressInfo(b, attachment.AttachedMolecule)
+				progress, _ := getMoleculeProgressInfoOnBranch(b, attachment.AttachedMolecule, stepBranch)
 				status.Progress = progress
 				status.NextAction = determineNextAction(status)
 			}
@@ -479,7 +482,7 @@ func runMoleculeStatus(cmd *cobra.Command, args []string) error {
 
 				// Get progress if there's an attached molecule
 				if attachment.AttachedMolecule != "" {
-					progress, _ := getMoleculeProgressInfo(b, attachment.AttachedMolecule)
+					progress, _ := getMoleculeProgressInfoOnBranch(b, attachment.AttachedMolecule, stepBranch)
 					status.Progress = progress
 					status.NextAction = determineNextAction(status)
 				}
@@ -531,6 +534,13 @@ func buildAgentIdentity(ctx RoleContext) string {
 
 // getMoleculeProgressInfo gets progress info for a molecule instance.
 func getMoleculeProgressInfo(b *beads.Beads, moleculeRootID string) (*MoleculeProgressInfo, error) {
+	return getMoleculeProgressInfoOnBranch(b, moleculeRootID, nil)
+}
+
+// getMoleculeProgressInfoOnBranch gets progress info for a molecule
+// instance, reading step status from a polecat's Dolt branch when branch
+// is non-nil.
+func getMoleculeProgressInfoOnBranch(b *beads.Beads, moleculeRootID string, branch *polecatStepBranch) (*MoleculeProgressInfo, error) {
 	// Get the molecule root issue
 	root, err := b.Show(moleculeRootID)
 	if err != nil {
@@ -552,6 +562,8 @@ func getMoleculeProgressInfo(b *beads.Beads, moleculeRootID string) (*MoleculePr
 		return nil, nil
 	}
 
+	branch.apply(children)
+
 	// Build progress info
 	progress := &MoleculeProgressInfo{
 		RootID:    moleculeRootID,
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -23,7 +24,7 @@ var moleculeStepDoneCmd = &cobra.Command{
 
 This command handles the step-to-step transition for polecats:
 
-1. Closes the completed step (bd close <step-id>)
+1. Closes the completed step (bd close <step-id>) and records a heartbeat
 2. Extracts the molecule ID from the step
 3. Finds the next ready step (dependency-aware)
 4. If next step exists:
@@ -116,6 +117,10 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		}
 		result.StepClosed = true
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
+
+		// Each completed step is a heartbeat: the agent is demonstrably making
+		// progress. Best-effort - a missing agent bead shouldn't block the step.
+		_, _ = sendHeartbeat(townRoot, cwd)
 	}
 
 	// Step 4: Find all ready steps (supports fan-out pattern)
@@ -291,7 +296,7 @@ func handleStepContinue(cwd, townRoot string, nextStep *beads.Issue, dryRun bool
 	pinCmd := exec.Command("bd", "update", nextStep.ID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Dir = gitRoot
 	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := proc.RunCmd(pinCmd); err != nil {
 		return fmt.Errorf("pinning next step: %w", err)
 	}
 
@@ -370,7 +375,7 @@ func handleParallelSteps(cwd, townRoot, _ string, steps []*beads.Issue, dryRun b
 		markCmd := exec.Command("bd", "update", step.ID, "--status=in_progress")
 		markCmd.Dir = gitRoot
 		markCmd.Stderr = os.Stderr
-		if err := markCmd.Run(); err != nil {
+		if err := proc.RunCmd(markCmd); err != nil {
 			style.PrintWarning("could not mark step %s as in_progress: %v", step.ID, err)
 		}
 	}
@@ -464,7 +469,7 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 			unpinCmd := exec.Command("bd", "update", pinnedBeads[0].ID, "--status=open")
 			unpinCmd.Dir = gitRoot
 			unpinCmd.Stderr = os.Stderr
-			if err := unpinCmd.Run(); err != nil {
+			if err := proc.RunCmd(unpinCmd); err != nil {
 				style.PrintWarning("could not unpin bead: %v", err)
 			} else {
 				fmt.Printf("%s Work unpinned\n", style.Bold.Render("✓"))
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	stepAutoTimeout time.Duration
+	stepAutoDryRun  bool
+)
+
+var moleculeStepAutoCmd = &cobra.Command{
+	Use:   "auto <step-id>",
+	Short: "Run a step's automation and close it if verification passes",
+	Long: `Run the automation and verification commands declared on a molecule step.
+
+Steps can carry optional metadata lines in their description (from the
+molecule's "Automation:" and "Verification:" lines):
+
+  automation: make generate
+  verification: git diff --exit-code -- gen/
+
+This command runs them in the current directory with sh -c:
+
+1. automation, if set. If it fails, the step is left open.
+2. verification, if set. If it succeeds, the step is completed exactly as
+   'gt mol step done' would, including auto-continuing to the next step.
+
+A step with automation but no verification is left open for the agent to
+check and close. The step ID is available to both commands as GT_STEP_ID.
+
+Examples:
+  gt mol step auto gt-abc.2
+  gt mol step auto gt-abc.2 --dry-run
+  gt mol step auto gt-abc.2 --timeout 30m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStepAuto,
+}
+
+func init() {
+	moleculeStepAutoCmd.Flags().DurationVar(&stepAutoTimeout, "timeout", 10*time.Minute, "Time limit for each command")
+	moleculeStepAutoCmd.Flags().BoolVarP(&stepAutoDryRun, "dry-run", "n", false, "Show the commands without running them")
+	moleculeStepCmd.AddCommand(moleculeStepAutoCmd)
+}
+
+func runMoleculeStepAuto(cmd *cobra.Command, args []string) error {
+	stepID := args[0]
+
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+	step, err := beads.New(workDir).Show(stepID)
+	if err != nil {
+		return fmt.Errorf("step not found: %w", err)
+	}
+	if step.Status == "closed" {
+		fmt.Printf("%s Step %s is already closed\n", style.Dim.Render("○"), stepID)
+		return nil
+	}
+
+	meta := beads.ParseStepMetadata(step.Description)
+	if meta.Automation == "" && meta.Verification == "" {
+		return fmt.Errorf("step %s has no automation or verification commands", stepID)
+	}
+
+	if stepAutoDryRun {
+		if meta.Automation != "" {
+			fmt.Printf("[dry-run] Would run automation: %s\n", meta.Automation)
+		}
+		if meta.Verification != "" {
+			fmt.Printf("[dry-run] Would run verification: %s\n", meta.Verification)
+			fmt.Printf("[dry-run] Would close step %s if it passes\n", stepID)
+		}
+		return nil
+	}
+
+	if meta.Automation != "" {
+		fmt.Printf("%s Running automation: %s\n", style.Bold.Render("→"), meta.Automation)
+		if err := runStepCommand(meta.Automation, stepID, stepAutoTimeout); err != nil {
+			return fmt.Errorf("automation for %s failed (step left open): %w", stepID, err)
+		}
+		fmt.Printf("%s Automation succeeded\n", style.Success.Render("✓"))
+	}
+
+	if meta.Verification == "" {
+		fmt.Printf("%s Step has no verification; check the result and run %s\n",
+			style.Dim.Render("○"), style.Bold.Render("gt mol step done "+stepID))
+		return nil
+	}
+
+	fmt.Printf("%s Running verification: %s\n", style.Bold.Render("→"), meta.Verification)
+	if err := runStepCommand(meta.Verification, stepID, stepAutoTimeout); err != nil {
+		return fmt.Errorf("verification for %s failed (step left open): %w", stepID, err)
+	}
+	fmt.Printf("%s Verification passed\n", style.Success.Render("✓"))
+
+	return runMoleculeStepDone(cmd, []string{stepID})
+}
+
+// runStepCommand runs a step's shell command in the current directory,
+// passing its output through.
+func runStepCommand(command, stepID string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	c := exec.CommandContext(ctx, "sh", "-c", command)
+	c.Env = append(os.Environ(), "GT_STEP_ID="+stepID)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return err
+	}
+	return nil
+}
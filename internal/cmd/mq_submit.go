@@ -87,6 +87,10 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if !config.FeatureEnabled(townRoot, rigName, config.FeatureMergeQueue) {
+		return fmt.Errorf("the merge queue is off for %s (feature flag %s; gt flags set %s on --rig %s)",
+			rigName, config.FeatureMergeQueue, config.FeatureMergeQueue, rigName)
+	}
 
 	// Initialize git for the current directory
 	cwd, err := os.Getwd()
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	mvBeadDryRun bool
+	mvBeadJSON   bool
+)
+
+var mvCmd = &cobra.Command{
+	Use:     "mv",
+	GroupID: GroupWork,
+	Short:   "Move work between rigs",
+	RunE:    requireSubcommand,
+}
+
+var mvBeadCmd = &cobra.Command{
+	Use:   "bead <bead-id> <rig>",
+	Short: "Move a bead to another rig's database, preserving its history",
+	Long: `Move a bead that landed in the wrong rig into another rig's database.
+
+Unlike 'gt bead move', which files a fresh copy, this moves the bead with
+its history through the Dolt server:
+
+  - The bead and its wisps (ephemeral children and attached molecule) are
+    copied under the target rig's prefix: gt-abc12 becomes bd-abc12
+  - Labels, comments, the events audit trail, and dependencies among the
+    moved beads come along
+  - The source bead is closed as a tombstone whose close reason and
+    moved_to: field point at the new ID; the moved wisps are removed
+  - A "moved" event is recorded in both databases, and each gets a commit
+
+Dependencies on beads that stay behind are not copied and are listed so
+they can be re-added with bd dep add. Beads that depend on the moved bead
+keep pointing at the tombstone. The source rig is found from the bead's
+prefix; use "hq" as the rig to move into the town database.
+
+Requires the Dolt server to be running.
+
+Examples:
+  gt mv bead gt-abc12 beads       # Move gt-abc12 into the beads rig
+  gt mv bead gt-abc12 beads -n    # Show what would move
+  gt mv bead bd-xyz9 hq           # Move into the town database`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runMvBead,
+}
+
+func init() {
+	mvBeadCmd.Flags().BoolVarP(&mvBeadDryRun, "dry-run", "n", false, "Show what would be moved")
+	mvBeadCmd.Flags().BoolVar(&mvBeadJSON, "json", false, "Output as JSON")
+	mvCmd.AddCommand(mvBeadCmd)
+	rootCmd.AddCommand(mvCmd)
+}
+
+func runMvBead(cmd *cobra.Command, args []string) error {
+	id, targetDB := args[0], args[1]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start it with 'gt dolt start')")
+	}
+
+	sourceDB, err := beadDatabase(townRoot, id)
+	if err != nil {
+		return err
+	}
+	if !doltserver.DatabaseExists(townRoot, targetDB) {
+		return fmt.Errorf("rig database %q not found", targetDB)
+	}
+
+	mv, err := doltserver.PlanBeadMove(townRoot, sourceDB, targetDB, id)
+	if err != nil {
+		return err
+	}
+
+	if !mvBeadDryRun {
+		if err := doltserver.MoveBead(townRoot, mv, detectSender()); err != nil {
+			return err
+		}
+	}
+
+	if mvBeadJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(mv)
+	}
+	printBeadMove(mv, mvBeadDryRun)
+	return nil
+}
+
+// beadDatabase returns the rig database holding a bead, from its prefix.
+func beadDatabase(townRoot, id string) (string, error) {
+	prefix := beads.ExtractPrefix(id)
+	if prefix == "" {
+		return "", fmt.Errorf("invalid bead ID %q", id)
+	}
+	if rigName := beads.GetRigNameForPrefix(townRoot, prefix); rigName != "" {
+		return rigName, nil
+	}
+	if beads.GetRigPathForPrefix(townRoot, prefix) == townRoot {
+		return "hq", nil
+	}
+	return "", fmt.Errorf("no rig found for prefix %s (check .beads/routes.jsonl)", prefix)
+}
+
+func printBeadMove(mv *doltserver.BeadMove, dryRun bool) {
+	if dryRun {
+		fmt.Printf("%s Would move %s (%s) to %s (%s)\n", style.Bold.Render("→"), mv.ID, mv.SourceDB, mv.NewID, mv.TargetDB)
+	} else {
+		fmt.Printf("%s Moved %s (%s) to %s (%s)\n", style.Success.Render("✓"), mv.ID, mv.SourceDB, mv.NewID, mv.TargetDB)
+	}
+
+	if wisps := mv.Wisps(); len(wisps) > 0 {
+		renamed := make([]string, len(wisps))
+		for i, w := range wisps {
+			renamed[i] = w + " → " + mv.IDs[w]
+		}
+		fmt.Printf("  Wisps: %s\n", strings.Join(renamed, ", "))
+	}
+
+	tables := make([]string, 0, len(mv.Rows))
+	for table := range mv.Rows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	counts := make([]string, 0, len(tables))
+	for _, table := range tables {
+		counts = append(counts, fmt.Sprintf("%s %d", table, mv.Rows[table]))
+	}
+	fmt.Printf("  Rows: %s\n", style.Dim.Render(strings.Join(counts, ", ")))
+
+	if len(mv.ExternalDeps) > 0 {
+		fmt.Printf("  %s Dependencies not copied (re-add with bd dep add):\n", style.Warning.Render("⚠"))
+		for _, dep := range mv.ExternalDeps {
+			fmt.Printf("    %s\n", dep)
+		}
+	}
+	if len(mv.Dependents) > 0 {
+		fmt.Printf("  %s Still depend on the tombstone %s: %s\n", style.Warning.Render("⚠"), mv.ID, strings.Join(mv.Dependents, ", "))
+	}
+}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -26,12 +28,20 @@ var namepoolCmd = &cobra.Command{
 By default, polecats get themed names from the Mad Max universe
 (furiosa, nux, slit, etc.). You can change the theme or add custom names.
 
+Town-wide themes can be defined in settings/config.json and selected
+like built-in themes:
+
+  "namepool_themes": {
+    "birds": ["heron", "wren", "kestrel", "osprey"]
+  }
+
 Examples:
   gt namepool              # Show current pool status
   gt namepool --list       # List available themes
   gt namepool themes       # Show theme names
   gt namepool set minerals # Set theme to 'minerals'
   gt namepool add ember    # Add custom name to pool
+  gt namepool check nux    # Check whether a name is free
   gt namepool reset        # Reset pool state`,
 	RunE: runNamepool,
 }
@@ -68,6 +78,21 @@ Duplicate names are silently ignored.`,
 	RunE: runNamepoolAdd,
 }
 
+var namepoolCheckCmd = &cobra.Command{
+	Use:   "check <name>",
+	Short: "Check whether a polecat name is free in this rig",
+	Long: `Check whether a name can be given to a new polecat in this rig.
+
+A name is unavailable if it is reserved for an infrastructure agent, has
+a polecat workspace, has a running tmux session, still has a polecat Dolt
+branch, or is reserved by a spawn in progress.
+
+Exits non-zero if the name is unavailable.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runNamepoolCheck,
+}
+
 var namepoolResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset the pool state (release all names)",
@@ -83,6 +108,7 @@ func init() {
 	namepoolCmd.AddCommand(namepoolThemesCmd)
 	namepoolCmd.AddCommand(namepoolSetCmd)
 	namepoolCmd.AddCommand(namepoolAddCmd)
+	namepoolCmd.AddCommand(namepoolCheckCmd)
 	namepoolCmd.AddCommand(namepoolResetCmd)
 	namepoolCmd.Flags().BoolVarP(&namepoolListFlag, "list", "l", false, "List available themes")
 }
@@ -117,6 +143,7 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 		// Use defaults
 		pool = polecat.NewNamePool(rigPath, rigName)
 	}
+	pool.SetTownThemes(polecat.LoadTownThemes(filepath.Dir(rigPath)))
 
 	if err := pool.Load(); err != nil {
 		// Pool doesn't exist yet, show defaults
@@ -137,6 +164,17 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 		fmt.Printf("In use: %s\n", strings.Join(activeNames, ", "))
 	}
 
+	if _, r, err := getRig(rigName); err == nil {
+		mgr := polecat.NewManager(r, git.NewGit(r.Path), nil)
+		if reservations, err := mgr.Reservations(); err == nil && len(reservations) > 0 {
+			var held []string
+			for _, res := range reservations {
+				held = append(held, fmt.Sprintf("%s (%s, until %s)", res.Name, res.Holder, res.ExpiresAt.Local().Format("15:04:05")))
+			}
+			fmt.Printf("Reserved: %s\n", strings.Join(held, ", "))
+		}
+	}
+
 	// Check if configured (already loaded above)
 	if settings.Namepool != nil {
 		fmt.Printf("(configured in settings/config.json)\n")
@@ -146,13 +184,14 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 }
 
 func runNamepoolThemes(cmd *cobra.Command, args []string) error {
-	themes := polecat.ListThemes()
+	townThemes := loadNamepoolTownThemes()
+	themes := polecat.ListThemesWith(townThemes)
 
 	if len(args) == 0 {
 		// List all themes
 		fmt.Println("Available themes:")
 		for _, theme := range themes {
-			names, _ := polecat.GetThemeNames(theme)
+			names, _ := polecat.GetThemeNamesWith(theme, townThemes)
 			fmt.Printf("\n  %s (%d names):\n", theme, len(names))
 			// Show first 10 names
 			preview := names
@@ -166,7 +205,7 @@ func runNamepoolThemes(cmd *cobra.Command, args []string) error {
 
 	// Show specific theme names
 	theme := args[0]
-	names, err := polecat.GetThemeNames(theme)
+	names, err := polecat.GetThemeNamesWith(theme, townThemes)
 	if err != nil {
 		return fmt.Errorf("unknown theme: %s (available: %s)", theme, strings.Join(themes, ", "))
 	}
@@ -187,7 +226,8 @@ func runNamepoolSet(cmd *cobra.Command, args []string) error {
 	theme := args[0]
 
 	// Validate theme
-	themes := polecat.ListThemes()
+	townThemes := loadNamepoolTownThemes()
+	themes := polecat.ListThemesWith(townThemes)
 	valid := false
 	for _, t := range themes {
 		if t == theme {
@@ -207,6 +247,7 @@ func runNamepoolSet(cmd *cobra.Command, args []string) error {
 
 	// Update pool
 	pool := polecat.NewNamePool(rigPath, rigName)
+	pool.SetTownThemes(townThemes)
 	if err := pool.Load(); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("loading pool: %w", err)
 	}
@@ -287,6 +328,31 @@ func runNamepoolAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runNamepoolCheck(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	rigName, _ := detectCurrentRigWithPath()
+	if rigName == "" {
+		return fmt.Errorf("not in a rig directory")
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+	collision, err := mgr.CheckName(name)
+	if err != nil {
+		return fmt.Errorf("checking name: %w", err)
+	}
+	if collision != nil {
+		fmt.Printf("Name '%s' is unavailable in rig '%s': %s\n", name, rigName, collision.Reason)
+		return NewSilentExit(1)
+	}
+	fmt.Printf("Name '%s' is available in rig '%s'\n", name, rigName)
+	return nil
+}
+
 func runNamepoolReset(cmd *cobra.Command, args []string) error {
 	rigName, rigPath := detectCurrentRigWithPath()
 	if rigName == "" {
@@ -310,6 +376,16 @@ func runNamepoolReset(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loadNamepoolTownThemes returns the town's namepool_themes, or nil outside
+// a workspace.
+func loadNamepoolTownThemes() map[string][]string {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	return polecat.LoadTownThemes(townRoot)
+}
+
 // detectCurrentRigWithPath determines the rig name and path from cwd.
 func detectCurrentRigWithPath() (string, string) {
 	cwd, err := os.Getwd()
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -26,6 +27,11 @@ var (
 	nudgeIfFreshFlag  bool
 	nudgeModeFlag     string
 	nudgePriorityFlag string
+
+	nudgeIdempotencyKeyFlag string
+	nudgeIdempotentFlag     bool
+
+	nudgeRequireAckFlag time.Duration
 )
 
 // Nudge delivery modes.
@@ -44,11 +50,14 @@ const (
 func init() {
 	rootCmd.AddCommand(nudgeCmd)
 	nudgeCmd.Flags().StringVarP(&nudgeMessageFlag, "message", "m", "", "Message to send")
-	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled")
+	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled or is rate limited")
 	nudgeCmd.Flags().BoolVar(&nudgeStdinFlag, "stdin", false, "Read message from stdin (avoids shell quoting issues)")
 	nudgeCmd.Flags().BoolVar(&nudgeIfFreshFlag, "if-fresh", false, "Only send if caller's tmux session is <60s old (suppresses compaction nudges)")
 	nudgeCmd.Flags().StringVar(&nudgeModeFlag, "mode", NudgeModeImmediate, "Delivery mode: immediate (default), queue, or wait-idle")
 	nudgeCmd.Flags().StringVar(&nudgePriorityFlag, "priority", nudge.PriorityNormal, "Queue priority: normal (default) or urgent")
+	nudgeCmd.Flags().StringVar(&nudgeIdempotencyKeyFlag, "idempotency-key", "", "Skip this nudge if the same key was used in the last 10 minutes")
+	nudgeCmd.Flags().BoolVar(&nudgeIdempotentFlag, "idempotent", false, "Skip this nudge if the same message went to the same target in the last 10 minutes")
+	nudgeCmd.Flags().DurationVar(&nudgeRequireAckFlag, "require-ack", 0, "Ask the target to run 'gt ack <nudge-id>' within this long (e.g. 10m)")
 }
 
 var nudgeCmd = &cobra.Command{
@@ -93,6 +102,18 @@ DND (Do Not Disturb):
   If the target has DND enabled (gt dnd on), the nudge is skipped.
   Use --force to override DND and send anyway.
 
+Receipts:
+  Every nudge gets an ID (nudge-<hex>) and a receipt recording whether it
+  was sent, queued or failed. With --require-ack, the message tells the
+  target to run 'gt ack <nudge-id>'; nudges not acknowledged in time are
+  listed by 'gt patrol digest' and escalated by 'gt escalate stale'.
+
+Rate limiting:
+  Nudges to the same recipient are subject to a cooldown (default 30s),
+  and a nudge identical to one sent recently is dropped as a duplicate.
+  Tune with "rate_limit" in settings/config.json. --force and
+  --priority=urgent bypass the limiter.
+
 Examples:
   gt nudge greenplace/furiosa "Check your mail and start working"
   gt nudge greenplace/alpha -m "What's your status?"
@@ -100,6 +121,11 @@ Examples:
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
   gt nudge channel:workers "New priority work available"
+  gt nudge gastown/alpha "Merge is blocked on you" --require-ack 15m
+
+  # Scripts that retry: repeats within 10 minutes are skipped
+  gt nudge gastown/alpha "Rebase on main" --idempotent
+  gt nudge gastown/alpha "Rebase on main" --idempotency-key rebase-42
 
   # Use --stdin for messages with special characters or formatting:
   gt nudge gastown/alpha --stdin <<'EOF'
@@ -119,11 +145,13 @@ const ifFreshMaxAge = 60 * time.Second
 // This is a var (not const) so tests can override it to avoid 15s waits.
 var waitIdleTimeout = 15 * time.Second
 
-// deliverNudge routes a nudge based on the --mode flag.
+// deliverNudge routes a nudge based on the --mode flag and returns the
+// receipt status: nudge.ReceiptSent when it reached the pane, or
+// nudge.ReceiptQueued when it was queued.
 // For "immediate" mode: sends directly via tmux (current behavior).
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
 // For "wait-idle" mode: waits for idle, then delivers or falls back to queue.
-func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
+func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) (string, error) {
 	townRoot, _ := workspace.FindFromCwd()
 
 	// For direct tmux delivery, prefix with sender attribution.
@@ -134,9 +162,9 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 	switch nudgeModeFlag {
 	case NudgeModeQueue:
 		if townRoot == "" {
-			return fmt.Errorf("--mode=queue requires a Gas Town workspace")
+			return "", fmt.Errorf("--mode=queue requires a Gas Town workspace")
 		}
-		return nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
+		return nudge.ReceiptQueued, nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
 			Sender:   sender,
 			Message:  message,
 			Priority: nudgePriorityFlag,
@@ -146,18 +174,18 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 		if townRoot == "" {
 			// wait-idle needs workspace for queue fallback — fail explicitly
 			// rather than silently degrading to immediate (destructive) delivery.
-			return fmt.Errorf("--mode=wait-idle requires a Gas Town workspace")
+			return "", fmt.Errorf("--mode=wait-idle requires a Gas Town workspace")
 		}
 		// Try to wait for idle
 		err := t.WaitForIdle(sessionName, waitIdleTimeout)
 		if err == nil {
 			// Agent is idle — safe to deliver directly
-			return t.NudgeSession(sessionName, prefixedMessage)
+			return nudge.ReceiptSent, t.NudgeSession(sessionName, prefixedMessage)
 		}
 		// Terminal errors (session gone, no server) — propagate, don't queue.
 		// Queueing a nudge for a dead session means it will never be delivered.
 		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
-			return fmt.Errorf("wait-idle: %w", err)
+			return "", fmt.Errorf("wait-idle: %w", err)
 		}
 		// Timeout (agent busy) — queue instead
 		if qErr := nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
@@ -168,13 +196,58 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 			// Queue failed — fall back to immediate as last resort.
 			// Better to interrupt than lose the message entirely.
 			fmt.Fprintf(os.Stderr, "Warning: queue fallback failed (%v), delivering immediately\n", qErr)
-			return t.NudgeSession(sessionName, prefixedMessage)
+			return nudge.ReceiptSent, t.NudgeSession(sessionName, prefixedMessage)
 		}
-		return nil
+		return nudge.ReceiptQueued, nil
 
 	default: // NudgeModeImmediate
-		return t.NudgeSession(sessionName, prefixedMessage)
+		return nudge.ReceiptSent, t.NudgeSession(sessionName, prefixedMessage)
+	}
+}
+
+// deliverTrackedNudge delivers a nudge with deliverNudge and records its
+// receipt. With --require-ack, the message names the nudge ID and asks the
+// target to acknowledge it. Returns the receipt, or nil outside a workspace.
+func deliverTrackedNudge(t *tmux.Tmux, sessionName, target, message, sender string) (*nudge.Receipt, error) {
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot == "" {
+		_, err := deliverNudge(t, sessionName, message, sender)
+		return nil, err
+	}
+
+	r := &nudge.Receipt{
+		ID:      nudge.NewReceiptID(),
+		Target:  target,
+		Session: sessionName,
+		Sender:  sender,
+		Message: message,
+		Mode:    nudgeModeFlag,
+		SentAt:  time.Now(),
+	}
+	if nudgeRequireAckFlag > 0 {
+		r.AckBy = r.SentAt.Add(nudgeRequireAckFlag)
+		message = fmt.Sprintf("%s (acknowledge with: gt ack %s)", message, r.ID)
+	}
+
+	status, err := deliverNudge(t, sessionName, message, sender)
+	if err != nil {
+		r.Status, r.Error = nudge.ReceiptFailed, err.Error()
+	} else {
+		r.Status = status
+	}
+	if recErr := nudge.RecordReceipt(townRoot, r); recErr != nil && r.AckRequired() {
+		fmt.Fprintf(os.Stderr, "Warning: could not record receipt for %s: %v\n", r.ID, recErr)
 	}
+	return r, err
+}
+
+// printNudgeReceipt prints the receipt line after a successful nudge when
+// an acknowledgement was required.
+func printNudgeReceipt(r *nudge.Receipt) {
+	if r == nil || !r.AckRequired() {
+		return
+	}
+	fmt.Printf("  %s %s: ack required by %s\n", style.Dim.Render("○"), r.ID, r.AckBy.Format("15:04:05"))
 }
 
 // validNudgeModes is the set of allowed --mode values.
@@ -190,7 +263,7 @@ var validNudgePriorities = map[string]bool{
 	nudge.PriorityUrgent: true,
 }
 
-func runNudge(cmd *cobra.Command, args []string) error {
+func runNudge(cmd *cobra.Command, args []string) (err error) {
 	// Validate --mode and --priority before doing anything else.
 	if !validNudgeModes[nudgeModeFlag] {
 		return fmt.Errorf("invalid --mode %q: must be one of immediate, queue, wait-idle", nudgeModeFlag)
@@ -261,6 +334,20 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Idempotency: a retried script repeating this nudge within the TTL is
+	// told the original result instead of nudging the agent again.
+	if townRoot, _ := workspace.FindFromCwd(); townRoot != "" {
+		op, skip, beginErr := beginIdempotent(townRoot, "nudge", nudgeIdempotencyKeyFlag, nudgeIdempotentFlag, target, message)
+		if beginErr != nil {
+			return beginErr
+		}
+		if skip {
+			return nil
+		}
+		result := fmt.Sprintf("nudged %s (%s)", target, nudgeModeFlag)
+		defer func() { op.finish(err, result) }()
+	}
+
 	// Handle channel syntax: channel:<name>
 	if strings.HasPrefix(target, "channel:") {
 		channelName := strings.TrimPrefix(target, "channel:")
@@ -278,6 +365,16 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Rate limit per recipient so patrols can't bury an agent in repeats.
+	// Urgent nudges are for interrupts and bypass the limiter.
+	if townRoot != "" && !nudgeForceFlag && nudgePriorityFlag != nudge.PriorityUrgent {
+		if d, _ := ratelimit.Allow(townRoot, ratelimit.KindNudge, target, message); !d.Allowed {
+			fmt.Printf("%s Nudge to %s suppressed (%s)\n", style.Dim.Render("○"), target, d)
+			fmt.Printf("  Use %s to override\n", style.Bold.Render("--force"))
+			return nil
+		}
+	}
+
 	t := tmux.NewTmux()
 
 	// Expand role shortcuts to session names
@@ -316,11 +413,13 @@ func runNudge(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		if err := deliverNudge(t, deaconSession, message, sender); err != nil {
+		receipt, err := deliverTrackedNudge(t, deaconSession, "deacon", message, sender)
+		if err != nil {
 			return fmt.Errorf("nudging deacon: %w", err)
 		}
 
 		fmt.Printf("%s Nudged deacon (%s)\n", style.Bold.Render("✓"), nudgeModeFlag)
+		printNudgeReceipt(receipt)
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -375,11 +474,13 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 
 		// Send nudge using the configured delivery mode
-		if err := deliverNudge(t, sessionName, message, sender); err != nil {
+		receipt, err := deliverTrackedNudge(t, sessionName, target, message, sender)
+		if err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
 		fmt.Printf("%s Nudged %s/%s (%s)\n", style.Bold.Render("✓"), rigName, polecatName, nudgeModeFlag)
+		printNudgeReceipt(receipt)
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -396,11 +497,13 @@ func runNudge(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("session %q not found", target)
 		}
 
-		if err := deliverNudge(t, target, message, sender); err != nil {
+		receipt, err := deliverTrackedNudge(t, target, target, message, sender)
+		if err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
 		fmt.Printf("✓ Nudged %s (%s)\n", target, nudgeModeFlag)
+		printNudgeReceipt(receipt)
 
 		// Log nudge event
 		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -482,7 +585,11 @@ func runNudgeChannel(channelName, message, sender string) error {
 			}
 		}
 
-		if err := deliverNudge(t, sessionName, message, sender); err != nil {
+		target := sessionName
+		if targetAddr != "" {
+			target = targetAddr
+		}
+		if _, err := deliverTrackedNudge(t, sessionName, target, message, sender); err != nil {
 			failed++
 			failures = append(failures, fmt.Sprintf("%s: %v", sessionName, err))
 			fmt.Printf("  %s %s\n", style.ErrorPrefix, sessionName)
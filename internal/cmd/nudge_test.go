@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -9,6 +11,23 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 )
 
+// chdirTestTown changes into a fresh town root so commands that find the
+// town from the working directory write their runtime state (nudge queue,
+// rate limiter) there. Run from the package directory, workspace.Find would
+// otherwise treat internal/ as a town because of internal/mayor.
+func chdirTestTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	return townRoot
+}
+
 func setupNudgeTestRegistry(t *testing.T) {
 	t.Helper()
 	reg := session.NewPrefixRegistry()
@@ -306,6 +325,7 @@ func TestNudgeValidModesAccepted(t *testing.T) {
 
 	// Shorten wait-idle timeout to avoid 15s test delay
 	waitIdleTimeout = 200 * time.Millisecond
+	chdirTestTown(t)
 
 	nudgeStdinFlag = false
 	nudgeMessageFlag = "test"
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/supportbundle"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var panicReason string
+
+var panicCmd = &cobra.Command{
+	Use:     "panic",
+	GroupID: GroupServices,
+	Short:   "Freeze the whole town and capture its state",
+	Long: `Freeze the whole town in one command when it melts down (runaway
+costs, corrupted merges, agents fighting each other).
+
+gt panic, in order:
+  1. Turns on maintenance mode: the daemon stops restarting agents and
+     triggering spawns, and new polecats are refused
+  2. Pauses the Deacon's patrol
+  3. Interrupts every agent and tells it to stop and wait
+  4. Tags HEAD of every Dolt database gt-panic-<time>, so the data can be
+     reset to this point (uncommitted changes are counted, not committed)
+  5. Writes a support bundle with daemon and Dolt logs, state and config
+     to .runtime/panic/ in the town
+  6. Prints a recovery checklist
+
+A step that fails is reported and the rest still run. Agents keep their
+sessions and hooks; nothing is killed or deleted.
+
+Run 'gt panic release' to resume.
+
+Examples:
+  gt panic
+  gt panic --reason "refinery merging broken builds"
+  gt panic release`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runPanic,
+}
+
+var panicReleaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Unfreeze the town after gt panic",
+	Long: `Leave maintenance mode: the daemon resumes its heartbeat, spawns are
+allowed again, the Deacon is resumed if gt panic paused it, and agents are
+told they may continue.
+
+The Dolt tag and support bundle from gt panic are kept.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runPanicRelease,
+}
+
+func init() {
+	panicCmd.Flags().StringVar(&panicReason, "reason", "", "Why the town is being frozen")
+	panicCmd.AddCommand(panicReleaseCmd)
+	rootCmd.AddCommand(panicCmd)
+}
+
+// panicBy identifies gt panic as the pauser, so release only resumes a
+// Deacon that gt panic paused.
+const panicBy = "gt panic"
+
+const panicFreezeNudge = "TOWN FROZEN (gt panic): stop what you are doing now. Do not run bd or gt writes, commit, push, or sling. Wait until you are told the town is released."
+
+const panicReleaseNudge = "Town released (gt panic release): you may resume your work. Check your hook with gt hook before continuing."
+
+func runPanic(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if state, _ := maintenance.Load(townRoot); state != nil {
+		fmt.Printf("%s Town already frozen since %s\n", style.Warning.Render("⚠"), state.Since.Local().Format(time.RFC1123))
+		printPanicChecklist(state)
+		return nil
+	}
+
+	now := time.Now().UTC()
+	stamp := now.Format("20060102-150405")
+	state := &maintenance.State{Reason: panicReason, Since: now, By: detectSender()}
+	failed := 0
+	step := func(err error, done string) {
+		if err != nil {
+			failed++
+			fmt.Printf("%s %v\n", style.Error.Render("✗"), err)
+			return
+		}
+		fmt.Printf("%s %s\n", style.Success.Render("✓"), done)
+	}
+
+	// Maintenance mode first: it stops the daemon and new spawns at once.
+	if err := maintenance.Enable(townRoot, state); err != nil {
+		return fmt.Errorf("enabling maintenance mode: %w", err)
+	}
+	fmt.Printf("%s Maintenance mode on: daemon heartbeat and new spawns stopped\n", style.Success.Render("✓"))
+
+	reason := "gt panic"
+	if panicReason != "" {
+		reason += ": " + panicReason
+	}
+	if paused, _, _ := deacon.IsPaused(townRoot); !paused {
+		err := deacon.Pause(townRoot, reason, panicBy)
+		if err != nil {
+			err = fmt.Errorf("pausing Deacon: %w", err)
+		}
+		step(err, "Deacon paused")
+	}
+
+	paused, errs := nudgeAllAgents(panicFreezeNudge, true)
+	for _, err := range errs {
+		step(err, "")
+	}
+	fmt.Printf("%s %d agent(s) interrupted and told to wait\n", style.Success.Render("✓"), paused)
+
+	var snaps []doltserver.DatabaseSnapshot
+	if running, _, _ := doltserver.IsRunning(townRoot); running {
+		state.DoltTag = "gt-panic-" + stamp
+		snaps, err = doltserver.TagDatabases(townRoot, state.DoltTag, reason)
+		if err != nil {
+			step(fmt.Errorf("snapshotting Dolt: %w", err), "")
+		} else {
+			tagged := 0
+			for _, s := range snaps {
+				if s.Error != "" {
+					step(fmt.Errorf("snapshotting %s: %s", s.Database, s.Error), "")
+					continue
+				}
+				tagged++
+				if s.Uncommitted > 0 {
+					fmt.Printf("  %s %s has %d table(s) with uncommitted changes (not in the tag)\n",
+						style.Warning.Render("⚠"), s.Database, s.Uncommitted)
+				}
+			}
+			fmt.Printf("%s Tagged %d Dolt database(s) %s\n", style.Success.Render("✓"), tagged, state.DoltTag)
+		}
+	} else {
+		fmt.Printf("%s Dolt server not running, no snapshot taken\n", style.Dim.Render("○"))
+	}
+
+	bundle, err := writePanicBundle(townRoot, "gt-panic-"+stamp, state, snaps)
+	if err == nil {
+		state.Bundle = bundle
+	}
+	step(err, "Support bundle written to "+bundle)
+
+	if err := maintenance.Enable(townRoot, state); err != nil {
+		step(fmt.Errorf("recording panic state: %w", err), "")
+	}
+	printPanicChecklist(state)
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runPanicRelease(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !maintenance.IsActive(townRoot) {
+		fmt.Printf("%s Town is not frozen\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	if err := maintenance.Disable(townRoot); err != nil {
+		return fmt.Errorf("leaving maintenance mode: %w", err)
+	}
+	fmt.Printf("%s Maintenance mode off: daemon heartbeat and spawns resume\n", style.Success.Render("✓"))
+
+	if paused, state, _ := deacon.IsPaused(townRoot); paused && state != nil && state.PausedBy == panicBy {
+		if err := deacon.Resume(townRoot); err != nil {
+			style.PrintWarning("resuming Deacon: %v", err)
+		} else {
+			fmt.Printf("%s Deacon resumed\n", style.Success.Render("✓"))
+		}
+	}
+
+	resumed, errs := nudgeAllAgents(panicReleaseNudge, false)
+	for _, err := range errs {
+		style.PrintWarning("%v", err)
+	}
+	fmt.Printf("%s %d agent(s) told to resume\n", style.Success.Render("✓"), resumed)
+	return nil
+}
+
+// nudgeAllAgents nudges every agent session except the caller's with
+// message, interrupting each first if interrupt is set. Returns how many
+// were reached.
+func nudgeAllAgents(message string, interrupt bool) (int, []error) {
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return 0, []error{fmt.Errorf("listing agent sessions: %w", err)}
+	}
+	self := os.Getenv("BD_ACTOR")
+	t := tmux.NewTmux()
+	var reached int
+	var errs []error
+	for _, agent := range agents {
+		name := formatAgentName(agent)
+		if self != "" && name == self {
+			continue
+		}
+		if interrupt {
+			// Escape stops the agent's current turn without ending its session.
+			_ = t.SendKeysRaw(agent.Name, "Escape")
+		}
+		if err := t.NudgeSession(agent.Name, message); err != nil {
+			errs = append(errs, fmt.Errorf("nudging %s: %w", name, err))
+			continue
+		}
+		reached++
+	}
+	return reached, errs
+}
+
+// writePanicBundle writes a support bundle, plus the panic state and Dolt
+// snapshot, to .runtime/panic/<name>.tar.gz and returns its path.
+func writePanicBundle(townRoot, name string, state *maintenance.State, snaps []doltserver.DatabaseSnapshot) (string, error) {
+	dir := filepath.Join(townRoot, ".runtime", "panic")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+	output := filepath.Join(dir, name+".tar.gz")
+
+	// Doctor checks are skipped: they are slow and may write to a town that
+	// is meant to stay still.
+	opts := supportbundle.DefaultOptions()
+	b, err := newTownBundle(townRoot, false, opts)
+	if err != nil {
+		return "", fmt.Errorf("collecting support bundle: %w", err)
+	}
+	if data, err := json.MarshalIndent(state, "", "  "); err == nil {
+		b.AddBytes("panic.json", data, opts.MaxArtifactBytes)
+	}
+	if data, err := json.MarshalIndent(snaps, "", "  "); err == nil && snaps != nil {
+		b.AddBytes("dolt-snapshot.json", data, opts.MaxArtifactBytes)
+	}
+	if err := writeBundle(b, output, name); err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func printPanicChecklist(state *maintenance.State) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Recovery checklist"))
+	n := 0
+	item := func(format string, args ...interface{}) {
+		n++
+		fmt.Printf("  %d. %s\n", n, fmt.Sprintf(format, args...))
+	}
+	if state.Bundle != "" {
+		item("Review the support bundle: tar -tzf %s", state.Bundle)
+	}
+	item("Check spend: gt costs --today, and gt costs forecast")
+	item("Check merges and branches: gt mq list <rig>, gt dolt reconcile-branches")
+	item("Check health: gt doctor, gt dolt status")
+	if state.DoltTag != "" {
+		item("If bead data is damaged, reset a database to the snapshot: gt dolt sql, then CALL DOLT_RESET('--hard', '%s')", state.DoltTag)
+	}
+	item("Stop anything still misbehaving: gt polecat nuke <rig>/<name>, gt down")
+	item("Resume: gt panic release")
+}
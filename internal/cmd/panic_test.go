@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/maintenance"
+)
+
+func TestWritePanicBundle(t *testing.T) {
+	townRoot := t.TempDir()
+	state := &maintenance.State{Reason: "runaway costs", DoltTag: "gt-panic-20261016-120000"}
+	snaps := []doltserver.DatabaseSnapshot{{Database: "gastown", Commit: "abc123"}}
+
+	path, err := writePanicBundle(townRoot, "gt-panic-20261016-120000", state, snaps)
+	if err != nil {
+		t.Fatalf("writePanicBundle: %v", err)
+	}
+	if want := filepath.Join(townRoot, ".runtime", "panic", "gt-panic-20261016-120000.tar.gz"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(data)
+	}
+	if got := files["gt-panic-20261016-120000/panic.json"]; !strings.Contains(got, "runaway costs") {
+		t.Errorf("panic.json = %q", got)
+	}
+	if got := files["gt-panic-20261016-120000/dolt-snapshot.json"]; !strings.Contains(got, "abc123") {
+		t.Errorf("dolt-snapshot.json = %q", got)
+	}
+}
+
+func TestPrintPanicChecklist(t *testing.T) {
+	out := captureStdout(t, func() {
+		printPanicChecklist(&maintenance.State{Bundle: "/town/.runtime/panic/b.tar.gz", DoltTag: "gt-panic-1"})
+	})
+	for _, want := range []string{
+		"1. Review the support bundle: tar -tzf /town/.runtime/panic/b.tar.gz",
+		"CALL DOLT_RESET('--hard', 'gt-panic-1')",
+		"Resume: gt panic release",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("checklist missing %q:\n%s", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() { printPanicChecklist(&maintenance.State{}) })
+	if strings.Contains(out, "DOLT_RESET") || strings.Contains(out, "support bundle") {
+		t.Errorf("checklist without tag or bundle mentions them:\n%s", out)
+	}
+}
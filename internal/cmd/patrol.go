@@ -11,7 +11,11 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
@@ -73,6 +77,14 @@ type PatrolDigest struct {
 	TotalCycles  int                      `json:"total_cycles"`
 	ByRole       map[string]int           `json:"by_role"`        // deacon, witness, refinery
 	Cycles       []PatrolCycleEntry       `json:"cycles"`
+
+	// Suppressed counts nudges, mail and escalations held back by the
+	// per-recipient rate limiter on this date.
+	Suppressed []ratelimit.SuppressedCount `json:"suppressed,omitempty"`
+
+	// Unacked lists nudges sent on this date that required 'gt ack' and
+	// were not acknowledged in time.
+	Unacked []*nudge.Receipt `json:"unacked,omitempty"`
 }
 
 // PatrolCycleEntry represents a single patrol cycle in the digest.
@@ -85,6 +97,24 @@ type PatrolCycleEntry struct {
 	ClosedAt    time.Time `json:"closed_at,omitempty"`
 }
 
+// PatrolDigestResult is the outcome of DigestPatrols.
+type PatrolDigestResult struct {
+	Digest PatrolDigest `json:"digest"`
+
+	// ExistingID is the bead of a digest already created for the date, in
+	// which case nothing else was done.
+	ExistingID string `json:"existing_id,omitempty"`
+
+	// BeadID is the digest bead created (empty for a dry run).
+	BeadID string `json:"bead_id,omitempty"`
+
+	// Deleted is the number of source digests deleted.
+	Deleted int `json:"deleted"`
+
+	// DeleteErr is set if deleting some source digests failed.
+	DeleteErr error `json:"-"`
+}
+
 // runPatrolDigest aggregates patrol cycle digests into a daily digest bead.
 func runPatrolDigest(cmd *cobra.Command, args []string) error {
 	// Determine target date
@@ -102,44 +132,24 @@ func runPatrolDigest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("specify --yesterday or --date YYYY-MM-DD")
 	}
 
-	dateStr := targetDate.Format("2006-01-02")
-
-	// Idempotency check: see if digest already exists for this date
-	existingID, err := findExistingPatrolDigest(dateStr)
+	result, err := DigestPatrols("", targetDate, patrolDigestDryRun)
 	if err != nil {
-		// Non-fatal: continue with creation attempt
-		if patrolDigestVerbose {
-			fmt.Fprintf(os.Stderr, "[patrol] warning: failed to check existing digest: %v\n", err)
-		}
-	} else if existingID != "" {
-		fmt.Printf("%s Patrol digest already exists for %s (bead: %s)\n",
-			style.Dim.Render("○"), dateStr, existingID)
-		return nil
+		return err
 	}
+	digest := result.Digest
+	dateStr := digest.Date
 
-	// Query ephemeral patrol digest beads for target date
-	cycles, err := queryPatrolDigests(targetDate)
-	if err != nil {
-		return fmt.Errorf("querying patrol digests: %w", err)
+	if result.ExistingID != "" {
+		fmt.Printf("%s Patrol digest already exists for %s (bead: %s)\n",
+			style.Dim.Render("○"), dateStr, result.ExistingID)
+		return nil
 	}
 
-	if len(cycles) == 0 {
+	if digest.TotalCycles == 0 {
 		fmt.Printf("%s No patrol digests found for %s\n", style.Dim.Render("○"), dateStr)
 		return nil
 	}
 
-	// Build digest
-	digest := PatrolDigest{
-		Date:   dateStr,
-		Cycles: cycles,
-		ByRole: make(map[string]int),
-	}
-
-	for _, c := range cycles {
-		digest.TotalCycles++
-		digest.ByRole[c.Role]++
-	}
-
 	if patrolDigestDryRun {
 		fmt.Printf("%s [DRY RUN] Would create Patrol Report %s:\n", style.Bold.Render("📊"), dateStr)
 		fmt.Printf("  Total cycles: %d\n", digest.TotalCycles)
@@ -152,35 +162,154 @@ func runPatrolDigest(cmd *cobra.Command, args []string) error {
 		for _, role := range roles {
 			fmt.Printf("    %s: %d cycles\n", role, digest.ByRole[role])
 		}
+		printSuppressedCounts(digest.Suppressed)
+		printUnackedNudges(digest.Unacked)
 		return nil
 	}
 
-	// Create permanent digest bead
-	digestID, err := createPatrolDigestBead(digest)
-	if err != nil {
-		return fmt.Errorf("creating digest bead: %w", err)
-	}
-
-	// Delete source digests (they're ephemeral)
-	deletedCount, deleteErr := deletePatrolDigests(targetDate)
-	if deleteErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to delete some source digests: %v\n", deleteErr)
+	if result.DeleteErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to delete some source digests: %v\n", result.DeleteErr)
 	}
 
-	fmt.Printf("%s Created Patrol Report %s (bead: %s)\n", style.Success.Render("✓"), dateStr, digestID)
+	fmt.Printf("%s Created Patrol Report %s (bead: %s)\n", style.Success.Render("✓"), dateStr, result.BeadID)
 	fmt.Printf("  Total: %d cycles\n", digest.TotalCycles)
 	for role, count := range digest.ByRole {
 		fmt.Printf("    %s: %d\n", role, count)
 	}
-	if deletedCount > 0 {
-		fmt.Printf("  Deleted %d source digests\n", deletedCount)
+	printSuppressedCounts(digest.Suppressed)
+	if result.Deleted > 0 {
+		fmt.Printf("  Deleted %d source digests\n", result.Deleted)
 	}
 
 	return nil
 }
 
+// printSuppressedCounts lists rate-limited messages per recipient.
+func printSuppressedCounts(counts []ratelimit.SuppressedCount) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Printf("  Suppressed by rate limit:\n")
+	for _, c := range counts {
+		fmt.Printf("    %s: %d\n", c.Recipient, c.Total)
+	}
+}
+
+// printUnackedNudges lists nudges whose required acknowledgement is overdue.
+func printUnackedNudges(receipts []*nudge.Receipt) {
+	if len(receipts) == 0 {
+		return
+	}
+	fmt.Printf("  Unacknowledged nudges:\n")
+	for _, r := range receipts {
+		line := fmt.Sprintf("%s → %s (due %s)", r.Sender, r.Target, r.AckBy.Format("15:04"))
+		if r.Escalation != "" {
+			line += ", escalated " + r.Escalation
+		}
+		fmt.Printf("    %s: %s\n", r.ID, line)
+	}
+}
+
+// DigestPatrols aggregates the ephemeral patrol cycle digests for targetDate
+// into a permanent "Patrol Report YYYY-MM-DD" bead and deletes the sources.
+// bd runs in dir (empty for the current directory). Idempotent: if a report
+// already exists for the date, only ExistingID is set. With dryRun, the
+// digest is built but nothing is created or deleted.
+func DigestPatrols(dir string, targetDate time.Time, dryRun bool) (*PatrolDigestResult, error) {
+	dateStr := targetDate.Format("2006-01-02")
+	result := &PatrolDigestResult{Digest: PatrolDigest{
+		Date:   dateStr,
+		ByRole: make(map[string]int),
+	}}
+
+	// Idempotency check: see if digest already exists for this date
+	existingID, err := findExistingPatrolDigest(dir, dateStr)
+	if err != nil {
+		// Non-fatal: continue with creation attempt
+		if patrolDigestVerbose {
+			fmt.Fprintf(os.Stderr, "[patrol] warning: failed to check existing digest: %v\n", err)
+		}
+	} else if existingID != "" {
+		result.ExistingID = existingID
+		return result, nil
+	}
+
+	// Query ephemeral patrol digest beads for target date
+	cycles, err := queryPatrolDigests(dir, targetDate)
+	if err != nil {
+		return nil, fmt.Errorf("querying patrol digests: %w", err)
+	}
+
+	// Build digest
+	result.Digest.Cycles = cycles
+	for _, c := range cycles {
+		result.Digest.TotalCycles++
+		result.Digest.ByRole[c.Role]++
+	}
+	result.Digest.Suppressed = suppressedMessages(dir, targetDate)
+	result.Digest.Unacked = unackedNudges(dir, targetDate)
+	if len(cycles) == 0 || dryRun {
+		return result, nil
+	}
+
+	// Create permanent digest bead
+	result.BeadID, err = createPatrolDigestBead(dir, result.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("creating digest bead: %w", err)
+	}
+
+	// Delete source digests (they're ephemeral)
+	result.Deleted, result.DeleteErr = deletePatrolDigests(dir, targetDate)
+	return result, nil
+}
+
+// suppressedMessages returns the rate limiter's suppressed counts for
+// targetDate in the town containing dir (empty for the current directory).
+func suppressedMessages(dir string, targetDate time.Time) []ratelimit.SuppressedCount {
+	var townRoot string
+	if dir == "" {
+		townRoot, _ = workspace.FindFromCwd()
+	} else {
+		townRoot, _ = workspace.Find(dir)
+	}
+	if townRoot == "" {
+		return nil
+	}
+	counts, err := ratelimit.Suppressed(townRoot, targetDate)
+	if err != nil && patrolDigestVerbose {
+		fmt.Fprintf(os.Stderr, "[patrol] warning: reading suppressed counts: %v\n", err)
+	}
+	return counts
+}
+
+// unackedNudges returns the overdue nudge receipts sent on targetDate in
+// the town containing dir (empty for the current directory).
+func unackedNudges(dir string, targetDate time.Time) []*nudge.Receipt {
+	var townRoot string
+	if dir == "" {
+		townRoot, _ = workspace.FindFromCwd()
+	} else {
+		townRoot, _ = workspace.Find(dir)
+	}
+	if townRoot == "" {
+		return nil
+	}
+	overdue, err := nudge.OverdueReceipts(townRoot, time.Now())
+	if err != nil && patrolDigestVerbose {
+		fmt.Fprintf(os.Stderr, "[patrol] warning: reading nudge receipts: %v\n", err)
+	}
+	dateStr := targetDate.Format("2006-01-02")
+	var unacked []*nudge.Receipt
+	for _, r := range overdue {
+		if r.SentAt.Local().Format("2006-01-02") == dateStr {
+			unacked = append(unacked, r)
+		}
+	}
+	return unacked
+}
+
 // queryPatrolDigests queries ephemeral patrol digest beads for a target date.
-func queryPatrolDigests(targetDate time.Time) ([]PatrolCycleEntry, error) {
+func queryPatrolDigests(dir string, targetDate time.Time) ([]PatrolCycleEntry, error) {
 	// List closed issues with "digest" label that are ephemeral
 	// Patrol digests have titles like "Digest: mol-deacon-patrol", "Digest: mol-witness-patrol"
 	listCmd := exec.Command("bd", "list",
@@ -189,7 +318,8 @@ func queryPatrolDigests(targetDate time.Time) ([]PatrolCycleEntry, error) {
 		"--json",
 		"--limit=0", // Get all
 	)
-	listOutput, err := listCmd.Output()
+	listCmd.Dir = dir
+	listOutput, err := proc.Output(listCmd)
 	if err != nil {
 		if patrolDigestVerbose {
 			fmt.Fprintf(os.Stderr, "[patrol] bd list failed: %v\n", err)
@@ -267,7 +397,7 @@ func extractPatrolRole(title string) string {
 }
 
 // createPatrolDigestBead creates a permanent bead for the daily patrol digest.
-func createPatrolDigestBead(digest PatrolDigest) (string, error) {
+func createPatrolDigestBead(dir string, digest PatrolDigest) (string, error) {
 	// Build description with aggregate data
 	var desc strings.Builder
 	desc.WriteString(fmt.Sprintf("Daily patrol aggregate for %s.\n\n", digest.Date))
@@ -286,6 +416,22 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 		desc.WriteString("\n")
 	}
 
+	if len(digest.Suppressed) > 0 {
+		desc.WriteString("## Suppressed by Rate Limit\n")
+		for _, c := range digest.Suppressed {
+			desc.WriteString(fmt.Sprintf("- %s: %d\n", c.Recipient, c.Total))
+		}
+		desc.WriteString("\n")
+	}
+
+	if len(digest.Unacked) > 0 {
+		desc.WriteString("## Unacknowledged Nudges\n")
+		for _, r := range digest.Unacked {
+			desc.WriteString(fmt.Sprintf("- %s: %s → %s\n", r.ID, r.Sender, r.Target))
+		}
+		desc.WriteString("\n")
+	}
+
 	// Build payload JSON with cycle details
 	payloadJSON, err := json.Marshal(digest)
 	if err != nil {
@@ -305,7 +451,8 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	bdCmd.Dir = dir
+	output, err := proc.CombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
 	}
@@ -314,14 +461,15 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 
 	// Auto-close the digest (it's an audit record, not work)
 	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily patrol digest")
-	_ = closeCmd.Run() // Best effort
+	closeCmd.Dir = dir
+	_ = proc.RunCmd(closeCmd) // Best effort
 
 	return digestID, nil
 }
 
 // findExistingPatrolDigest checks if a patrol digest already exists for the given date.
 // Returns the bead ID if found, empty string if not found.
-func findExistingPatrolDigest(dateStr string) (string, error) {
+func findExistingPatrolDigest(dir, dateStr string) (string, error) {
 	expectedTitle := fmt.Sprintf("Patrol Report %s", dateStr)
 
 	// Query event beads with patrol.digest category
@@ -330,7 +478,8 @@ func findExistingPatrolDigest(dateStr string) (string, error) {
 		"--json",
 		"--limit=50", // Recent events only
 	)
-	listOutput, err := listCmd.Output()
+	listCmd.Dir = dir
+	listOutput, err := proc.Output(listCmd)
 	if err != nil {
 		return "", err
 	}
@@ -354,9 +503,9 @@ func findExistingPatrolDigest(dateStr string) (string, error) {
 }
 
 // deletePatrolDigests deletes ephemeral patrol digest beads for a target date.
-func deletePatrolDigests(targetDate time.Time) (int, error) {
+func deletePatrolDigests(dir string, targetDate time.Time) (int, error) {
 	// Query patrol digests for the target date
-	cycles, err := queryPatrolDigests(targetDate)
+	cycles, err := queryPatrolDigests(dir, targetDate)
 	if err != nil {
 		return 0, err
 	}
@@ -374,7 +523,8 @@ func deletePatrolDigests(targetDate time.Time) (int, error) {
 	// Delete in batch
 	deleteArgs := append([]string{"delete", "--force"}, idsToDelete...)
 	deleteCmd := exec.Command("bd", deleteArgs...)
-	if err := deleteCmd.Run(); err != nil {
+	deleteCmd.Dir = dir
+	if err := proc.RunCmd(deleteCmd); err != nil {
 		return 0, fmt.Errorf("deleting patrol digests: %w", err)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -37,7 +38,7 @@ func findActivePatrol(cfg PatrolConfig) (patrolID, patrolLine string, found bool
 		cmdList.Stdout = &stdoutList
 		cmdList.Stderr = &stderrList
 
-		if err := cmdList.Run(); err != nil {
+		if err := proc.RunCmd(cmdList); err != nil {
 			if errMsg := strings.TrimSpace(stderrList.String()); errMsg != "" {
 				fmt.Fprintf(os.Stderr, "bd list: %s\n", errMsg)
 			}
@@ -76,7 +77,7 @@ func findPatrolByStatus(cfg PatrolConfig, status string) (patrolID, patrolLine s
 	cmdList.Stdout = &stdoutList
 	cmdList.Stderr = &stderrList
 
-	if err := cmdList.Run(); err != nil {
+	if err := proc.RunCmd(cmdList); err != nil {
 		if errMsg := strings.TrimSpace(stderrList.String()); errMsg != "" {
 			fmt.Fprintf(os.Stderr, "bd list: %s\n", errMsg)
 		}
@@ -142,7 +143,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	cmdSpawn.Stdout = &stdoutSpawn
 	cmdSpawn.Stderr = &stderrSpawn
 
-	if err := cmdSpawn.Run(); err != nil {
+	if err := proc.RunCmd(cmdSpawn); err != nil {
 		return "", fmt.Errorf("failed to create patrol wisp: %s", stderrSpawn.String())
 	}
 
@@ -179,7 +180,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	// Hook the wisp to the agent so gt mol status sees it
 	cmdPin := exec.Command("bd", "update", patrolID, "--status=hooked", "--assignee="+cfg.Assignee)
 	cmdPin.Dir = cfg.BeadsDir
-	if err := cmdPin.Run(); err != nil {
+	if err := proc.RunCmd(cmdPin); err != nil {
 		return patrolID, fmt.Errorf("created wisp %s but failed to hook", patrolID)
 	}
 
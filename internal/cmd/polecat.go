@@ -11,14 +11,18 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/approval"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/quarantine"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Polecat command flags
@@ -161,6 +165,7 @@ var (
 	polecatNukeAll           bool
 	polecatNukeDryRun        bool
 	polecatNukeForce         bool
+	polecatNukeApproval      string
 	polecatCheckRecoveryJSON bool
 )
 
@@ -310,6 +315,7 @@ func init() {
 	polecatNukeCmd.Flags().BoolVar(&polecatNukeAll, "all", false, "Nuke all polecats in the rig")
 	polecatNukeCmd.Flags().BoolVar(&polecatNukeDryRun, "dry-run", false, "Show what would be nuked without doing it")
 	polecatNukeCmd.Flags().BoolVarP(&polecatNukeForce, "force", "f", false, "Force nuke, bypassing all safety checks (LOSES WORK)")
+	polecatNukeCmd.Flags().StringVar(&polecatNukeApproval, "approval", "", "Approval token from 'gt approve nuke' (when required by town policy)")
 
 	// Check-recovery flags
 	polecatCheckRecoveryCmd.Flags().BoolVar(&polecatCheckRecoveryJSON, "json", false, "Output as JSON")
@@ -1125,6 +1131,17 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Town approval policy (gt approve nuke)
+	if !polecatNukeDryRun {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if err := requireApproval(townRoot, approval.ClassNuke, strings.Join(args, " "), polecatNukeApproval); err != nil {
+			return err
+		}
+	}
+
 	// Nuke each polecat
 	var nukeErrors []string
 	nuked := 0
@@ -1191,6 +1208,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 // 2. Delete worktree (via RemoveWithOptions with nuclear=true)
 // 3. Delete git branch
 // 4. Close agent bead
+// 5. Lift quarantine, keeping its evidence
 // This is the canonical cleanup path used by both `polecat nuke` and `polecat stale --cleanup`.
 func nukePolecatFull(polecatName, rigName string, mgr *polecat.Manager, r *rig.Rig) error {
 	t := tmux.NewTmux()
@@ -1264,12 +1282,18 @@ func nukePolecatFull(polecatName, rigName string, mgr *polecat.Manager, r *rig.R
 	}
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = filepath.Join(r.Path, "mayor", "rig")
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		fmt.Printf("  %s agent bead not found or already closed\n", style.Dim.Render("○"))
 	} else {
 		fmt.Printf("  %s closed agent bead %s\n", style.Success.Render("✓"), agentBeadID)
 	}
 
+	// Step 6: Lift quarantine, if any. Its evidence and quarantine-* Dolt
+	// branch are kept; only the record that blocks restarts is moved aside.
+	if rec, err := quarantine.Release(filepath.Dir(r.Path), rigName, polecatName); err == nil {
+		fmt.Printf("  %s lifted quarantine (evidence kept in %s)\n", style.Success.Render("✓"), rec.Snapshot)
+	}
+
 	return nil
 }
 
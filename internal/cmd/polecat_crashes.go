@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crashlog"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Polecat crashes command flags
+var (
+	polecatCrashesRig   string
+	polecatCrashesJSON  bool
+	polecatCrashesLines int
+)
+
+var polecatCrashesCmd = &cobra.Command{
+	Use:   "crashes [rig/polecat]",
+	Short: "List and inspect captured crash reports",
+	Long: `List and inspect crash reports captured when a polecat session dies.
+
+When a crash is detected (by the tmux pane-died hook or the daemon's
+heartbeat), the last lines of the pane and the process exit status are
+saved under daemon/crashes/<rig>/<polecat>/<timestamp>.json.
+
+Without arguments, lists reports newest first. With a rig/polecat
+argument, shows that polecat's most recent report including its output.
+
+Examples:
+  gt polecat crashes                     # All captured crashes
+  gt polecat crashes --rig gastown       # One rig
+  gt polecat crashes gastown/Toast       # Latest report for Toast
+  gt polecat crashes gastown/Toast -n 50 # Only the last 50 output lines`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runPolecatCrashes,
+}
+
+func init() {
+	polecatCrashesCmd.Flags().StringVar(&polecatCrashesRig, "rig", "", "Only show crashes for this rig")
+	polecatCrashesCmd.Flags().BoolVar(&polecatCrashesJSON, "json", false, "Output as JSON")
+	polecatCrashesCmd.Flags().IntVarP(&polecatCrashesLines, "lines", "n", 0, "Output lines to show when inspecting (default: all)")
+
+	polecatCmd.AddCommand(polecatCrashesCmd)
+}
+
+func runPolecatCrashes(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 1 {
+		rigName, polecatName, err := parseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		return showPolecatCrash(townRoot, rigName, polecatName)
+	}
+
+	reports, err := crashlog.List(townRoot, polecatCrashesRig)
+	if err != nil {
+		return fmt.Errorf("listing crash reports: %w", err)
+	}
+	if polecatCrashesJSON {
+		if reports == nil {
+			reports = []*crashlog.Report{}
+		}
+		// Listing omits output; inspect a single polecat for the full capture.
+		summaries := make([]crashlog.Report, len(reports))
+		for i, r := range reports {
+			summaries[i] = *r
+			summaries[i].Output = nil
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	}
+
+	if len(reports) == 0 {
+		fmt.Println("No crash reports captured.")
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Crash reports (%d)", len(reports))))
+	for _, r := range reports {
+		hook := ""
+		if r.HookBead != "" {
+			hook = " hook=" + r.HookBead
+		}
+		fmt.Printf("  %s %s  %s  %s\n",
+			style.Error.Render("✗"),
+			style.Dim.Render(r.Time.Local().Format("2006-01-02 15:04:05")),
+			r.Agent,
+			style.Dim.Render(fmt.Sprintf("exit=%s via=%s%s lines=%d",
+				crashExitStatus(r), r.DetectedBy, hook, len(r.Output))))
+	}
+	fmt.Printf("\nInspect with %s\n", style.Bold.Render("gt polecat crashes <rig>/<polecat>"))
+	return nil
+}
+
+func showPolecatCrash(townRoot, rigName, polecatName string) error {
+	reports, err := crashlog.List(townRoot, rigName)
+	if err != nil {
+		return fmt.Errorf("listing crash reports: %w", err)
+	}
+	var latest *crashlog.Report
+	for _, r := range reports {
+		if r.Polecat == polecatName {
+			latest = r
+			break
+		}
+	}
+	if latest == nil {
+		return fmt.Errorf("no crash reports for %s/%s", rigName, polecatName)
+	}
+
+	output := latest.Output
+	if polecatCrashesLines > 0 && len(output) > polecatCrashesLines {
+		output = output[len(output)-polecatCrashesLines:]
+	}
+
+	if polecatCrashesJSON {
+		r := *latest
+		r.Output = output
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Crash: "+latest.Agent))
+	fmt.Printf("  Time:        %s (%s ago)\n", latest.Time.Local().Format(time.RFC3339),
+		time.Since(latest.Time).Round(time.Second))
+	fmt.Printf("  Session:     %s\n", latest.Session)
+	fmt.Printf("  Exit status: %s\n", crashExitStatus(latest))
+	fmt.Printf("  Detected by: %s\n", latest.DetectedBy)
+	if latest.HookBead != "" {
+		fmt.Printf("  Hook bead:   %s\n", latest.HookBead)
+	}
+	fmt.Printf("  Report:      %s\n", style.Dim.Render(latest.Path))
+
+	if len(output) == 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("(no pane output captured)"))
+		return nil
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("Last %d line(s):", len(output))))
+	fmt.Println(strings.Join(output, "\n"))
+	return nil
+}
+
+func crashExitStatus(r *crashlog.Report) string {
+	if r.ExitStatus == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d", *r.ExitStatus)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/quarantine"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -100,6 +101,11 @@ func checkPolecatSafety(target polecatTarget) *SafetyCheckResult {
 		Polecat: fmt.Sprintf("%s/%s", target.rigName, target.polecatName),
 	}
 
+	// Check 0: Quarantine. The polecat is being held as evidence.
+	if quarantine.IsQuarantined(filepath.Dir(target.r.Path), target.rigName, target.polecatName) {
+		result.Reasons = append(result.Reasons, "is quarantined (review the evidence, then nuke with --force)")
+	}
+
 	// Get polecat info for branch name
 	polecatInfo, infoErr := target.mgr.Get(target.polecatName)
 
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -810,7 +811,7 @@ func queryAssignedIssues(rigPath, assignee, status string) ([]IssueInfo, error)
 
 	cmd := exec.Command("bd", args...)
 	cmd.Dir = rigPath
-	out, err := cmd.Output()
+	out, err := proc.Output(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/quarantine"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Polecat quarantine command flags
+var (
+	polecatQuarantineReason  string
+	polecatQuarantineList    bool
+	polecatQuarantineRelease bool
+	polecatQuarantineJSON    bool
+)
+
+var polecatQuarantineCmd = &cobra.Command{
+	Use:   "quarantine [rig/polecat]",
+	Short: "Isolate a misbehaving polecat, preserving evidence",
+	Long: `Freeze a polecat that has gone haywire without destroying what it did.
+
+Quarantine, in order:
+  1. Records the polecat as quarantined, so the daemon stops restarting it
+     and the witness refuses to nuke it
+  2. Suspends its session (SIGSTOP to the pane and all its children)
+  3. Renames its Dolt branch to quarantine-*, so further bd writes fail
+  4. Snapshots its transcript (tmux scrollback) and workspace (tarball,
+     git status, diff and log)
+  5. Sets agent_state=quarantined on its agent bead
+
+Evidence is kept under daemon/quarantine/<rig>/<polecat>/. When review is
+done, either nuke the polecat with 'gt polecat nuke --force' or resume it
+with --release, which restores its branch and continues its session.
+
+Examples:
+  gt polecat quarantine gastown/Toast --reason "rewriting unrelated files"
+  gt polecat quarantine --list
+  gt polecat quarantine gastown/Toast --release`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runPolecatQuarantine,
+}
+
+func init() {
+	polecatQuarantineCmd.Flags().StringVarP(&polecatQuarantineReason, "reason", "r", "", "Why the polecat is being quarantined")
+	polecatQuarantineCmd.Flags().BoolVar(&polecatQuarantineList, "list", false, "List quarantined polecats")
+	polecatQuarantineCmd.Flags().BoolVar(&polecatQuarantineRelease, "release", false, "Lift quarantine and resume the polecat")
+	polecatQuarantineCmd.Flags().BoolVar(&polecatQuarantineJSON, "json", false, "Output as JSON (with --list)")
+
+	polecatCmd.AddCommand(polecatQuarantineCmd)
+}
+
+func runPolecatQuarantine(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if polecatQuarantineList {
+		return listQuarantined(townRoot)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("requires a rig/polecat address (or --list)")
+	}
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	if polecatQuarantineRelease {
+		return releaseQuarantine(townRoot, rigName, polecatName)
+	}
+	return quarantinePolecat(townRoot, rigName, polecatName)
+}
+
+func quarantinePolecat(townRoot, rigName, polecatName string) error {
+	if quarantine.IsQuarantined(townRoot, rigName, polecatName) {
+		return fmt.Errorf("%s/%s is already quarantined", rigName, polecatName)
+	}
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	sessionName := polecat.NewSessionManager(t, r).SessionName(polecatName)
+	rec := &quarantine.Record{
+		Rig:       rigName,
+		Polecat:   polecatName,
+		Reason:    polecatQuarantineReason,
+		By:        detectActor(),
+		Time:      time.Now(),
+		Session:   sessionName,
+		AgentBead: polecatBeadIDForRig(r, rigName, polecatName),
+	}
+	if issue, fields, err := beads.New(r.Path).GetAgentBead(rec.AgentBead); err == nil && issue != nil {
+		rec.HookBead = issue.HookBead
+		if rec.HookBead == "" && fields != nil {
+			rec.HookBead = fields.HookBead
+		}
+	}
+	snapshot := quarantine.SnapshotDir(townRoot, rec)
+	rec.Snapshot = snapshot
+
+	// Step 1: Record first. From here on the daemon won't restart the
+	// polecat and the witness won't nuke it, even if a later step fails.
+	if err := quarantine.Save(townRoot, rec); err != nil {
+		return err
+	}
+	fmt.Printf("Quarantining %s/%s...\n", rigName, polecatName)
+	fmt.Printf("  %s recorded quarantine (daemon restarts and witness nukes blocked)\n", style.Success.Render("✓"))
+
+	// Step 2: Freeze the session, then capture its transcript.
+	running, _ := t.HasSession(sessionName)
+	var doltBranch string
+	if running {
+		if err := t.SignalPaneProcesses(sessionName, "STOP"); err != nil {
+			fmt.Printf("  %s suspend session: %v\n", style.Warning.Render("⚠"), err)
+		} else {
+			rec.Frozen = true
+			fmt.Printf("  %s suspended session %s\n", style.Success.Render("✓"), sessionName)
+		}
+		if transcript, err := t.CapturePaneAll(sessionName); err != nil {
+			fmt.Printf("  %s capture transcript: %v\n", style.Warning.Render("⚠"), err)
+		} else if err := quarantine.WriteTranscript(snapshot, transcript); err != nil {
+			fmt.Printf("  %s save transcript: %v\n", style.Warning.Render("⚠"), err)
+		} else {
+			fmt.Printf("  %s saved transcript\n", style.Success.Render("✓"))
+		}
+		doltBranch, _ = t.GetEnvironment(sessionName, "BD_BRANCH")
+	} else {
+		fmt.Printf("  %s no running session\n", style.Dim.Render("○"))
+	}
+
+	// Step 3: Revoke Dolt write access. BD_BRANCH is left pointing at the old
+	// name: clearing it would send the polecat's writes to main instead.
+	if doltBranch == "" {
+		doltBranch, _ = doltserver.FindPolecatBranch(townRoot, rigName, polecatName)
+	}
+	if doltBranch != "" {
+		to := doltserver.QuarantineBranchName(doltBranch)
+		if err := doltserver.RenameBranch(townRoot, rigName, doltBranch, to); err != nil {
+			fmt.Printf("  %s revoke Dolt branch: %v\n", style.Warning.Render("⚠"), err)
+		} else {
+			rec.DoltBranch, rec.QuarantineBranch = doltBranch, to
+			fmt.Printf("  %s renamed Dolt branch %s → %s\n", style.Success.Render("✓"), doltBranch, to)
+		}
+	} else {
+		fmt.Printf("  %s no Dolt branch\n", style.Dim.Render("○"))
+	}
+
+	// Step 4: Snapshot the workspace.
+	if info, err := mgr.Get(polecatName); err != nil {
+		fmt.Printf("  %s snapshot workspace: %v\n", style.Warning.Render("⚠"), err)
+	} else if err := quarantine.SnapshotWorkspace(info.ClonePath, snapshot); err != nil {
+		fmt.Printf("  %s snapshot workspace: %v\n", style.Warning.Render("⚠"), err)
+	} else {
+		fmt.Printf("  %s snapshotted workspace\n", style.Success.Render("✓"))
+	}
+
+	// Step 5: Mark the agent bead.
+	if err := mgr.SetAgentState(polecatName, quarantine.AgentState); err != nil {
+		fmt.Printf("  %s mark agent bead: %v\n", style.Warning.Render("⚠"), err)
+	} else {
+		fmt.Printf("  %s set %s agent_state=%s\n", style.Success.Render("✓"), rec.AgentBead, quarantine.AgentState)
+	}
+
+	if err := quarantine.Save(townRoot, rec); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s %s/%s quarantined. Evidence: %s\n", style.SuccessPrefix, rigName, polecatName, snapshot)
+	fmt.Printf("Review, then %s or %s\n",
+		style.Bold.Render(fmt.Sprintf("gt polecat nuke %s/%s --force", rigName, polecatName)),
+		style.Bold.Render(fmt.Sprintf("gt polecat quarantine %s/%s --release", rigName, polecatName)))
+	return nil
+}
+
+func releaseQuarantine(townRoot, rigName, polecatName string) error {
+	rec, err := quarantine.Load(townRoot, rigName, polecatName)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s/%s is not quarantined", rigName, polecatName)
+	}
+	if err != nil {
+		return err
+	}
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	// Restore write access before waking the session, so its first bd call
+	// after resuming lands on its branch.
+	if rec.QuarantineBranch != "" {
+		if err := doltserver.RenameBranch(townRoot, rigName, rec.QuarantineBranch, rec.DoltBranch); err != nil {
+			return fmt.Errorf("restoring Dolt branch: %w", err)
+		}
+		fmt.Printf("  %s restored Dolt branch %s\n", style.Success.Render("✓"), rec.DoltBranch)
+	}
+	if err := mgr.SetAgentState(polecatName, "working"); err != nil {
+		fmt.Printf("  %s mark agent bead: %v\n", style.Warning.Render("⚠"), err)
+	}
+	t := tmux.NewTmux()
+	if running, _ := t.HasSession(rec.Session); running && rec.Frozen {
+		if err := t.SignalPaneProcesses(rec.Session, "CONT"); err != nil {
+			fmt.Printf("  %s resume session: %v\n", style.Warning.Render("⚠"), err)
+		} else {
+			fmt.Printf("  %s resumed session %s\n", style.Success.Render("✓"), rec.Session)
+		}
+	}
+	if _, err := quarantine.Release(townRoot, rigName, polecatName); err != nil {
+		return err
+	}
+	fmt.Printf("%s Released %s/%s (evidence kept in %s)\n", style.SuccessPrefix, rigName, polecatName, rec.Snapshot)
+	return nil
+}
+
+func listQuarantined(townRoot string) error {
+	records, err := quarantine.List(townRoot, "")
+	if err != nil {
+		return fmt.Errorf("listing quarantined polecats: %w", err)
+	}
+	if polecatQuarantineJSON {
+		if records == nil {
+			records = []*quarantine.Record{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No quarantined polecats.")
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Quarantined polecats (%d)", len(records))))
+	for _, r := range records {
+		reason := r.Reason
+		if reason == "" {
+			reason = "(no reason given)"
+		}
+		fmt.Printf("  %s %s/%s  %s  %s\n",
+			style.Warning.Render("⚠"), r.Rig, r.Polecat,
+			style.Dim.Render(r.Time.Local().Format("2006-01-02 15:04:05")), reason)
+		fmt.Printf("    %s\n", style.Dim.Render(r.Snapshot))
+	}
+	return nil
+}
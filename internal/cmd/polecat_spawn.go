@@ -14,8 +14,10 @@ import (
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/maintenance"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -66,9 +68,13 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// A town frozen by gt panic takes no new polecats.
+	if maintenance.IsActive(townRoot) {
+		return nil, fmt.Errorf("town is frozen by gt panic; run 'gt panic release' before spawning")
+	}
+
 	// Load rig config
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -164,12 +170,14 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 		fmt.Printf("Repairing stale polecat %s with fresh worktree...\n", polecatName)
 		if _, err = polecatMgr.RepairWorktreeWithOptions(polecatName, opts.Force, addOpts); err != nil {
+			_ = polecatMgr.ReleaseReservation(polecatName)
 			return nil, fmt.Errorf("repairing stale polecat: %w", err)
 		}
 	} else if err == polecat.ErrPolecatNotFound {
 		// Create new polecat
 		fmt.Printf("Creating polecat %s...\n", polecatName)
 		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
+			_ = polecatMgr.ReleaseReservation(polecatName)
 			return nil, fmt.Errorf("creating polecat: %w", err)
 		}
 	} else {
@@ -179,6 +187,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// Get polecat object for path info
 	polecatObj, err := polecatMgr.Get(polecatName)
 	if err != nil {
+		_ = polecatMgr.Remove(polecatName, true)
 		return nil, fmt.Errorf("getting polecat after creation: %w", err)
 	}
 
@@ -191,6 +200,14 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			polecatName, err, rigName, polecatName)
 	}
 
+	// Spawn-time validation: run the rig's declared checks and spawn-check
+	// scripts now, so a broken workspace (missing deps, env file, submodules)
+	// fails the sling instead of being discovered by the agent.
+	if err := validateSpawnedWorkspace(r.Path, polecatObj.ClonePath); err != nil {
+		_ = polecatMgr.Remove(polecatName, true)
+		return nil, fmt.Errorf("polecat %s/%s: %w", rigName, polecatName, err)
+	}
+
 	// Branch-per-polecat: generate name but DEFER creation to after sling writes.
 	// DOLT_BRANCH forks from HEAD, but BD_DOLT_AUTO_COMMIT=off means writes
 	// stay in working set. Caller must call CreateDoltBranch() after all writes
@@ -240,8 +257,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	}
 
 	// Load rig config
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -324,8 +340,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 // forks from HEAD but BD_DOLT_AUTO_COMMIT=off leaves writes in working set only.
 //
 // On error, callers are responsible for cleaning up the spawned polecat (worktree,
-// agent bead) and unhooking any attached beads. See rollbackSlingArtifacts for the
-// standard cleanup pattern.
+// agent bead) and unhooking any attached beads, which slingSaga.fail does.
 func (s *SpawnedPolecatInfo) CreateDoltBranch() error {
 	if s.DoltBranch == "" {
 		return nil
@@ -366,8 +381,7 @@ func IsRigName(target string) (string, bool) {
 		return "", false
 	}
 
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		return "", false
 	}
@@ -382,6 +396,25 @@ func IsRigName(target string) (string, bool) {
 	return target, true
 }
 
+// validateSpawnedWorkspace runs the rig's spawn checks against a new
+// polecat worktree and returns an error carrying the report if any fail.
+func validateSpawnedWorkspace(rigPath, clonePath string) error {
+	v, err := rig.ValidateSpawnWorkspace(rigPath, clonePath)
+	if err != nil {
+		return fmt.Errorf("workspace validation: %w", err)
+	}
+	if len(v.Results) == 0 {
+		return nil
+	}
+	failed := v.Failed()
+	if len(failed) == 0 {
+		fmt.Printf("%s Workspace validation passed (%d checks)\n", style.Bold.Render("✓"), len(v.Results))
+		return nil
+	}
+	return fmt.Errorf("workspace validation failed (%d of %d checks):\n%s", len(failed), len(v.Results),
+		strings.TrimRight(v.Report(), "\n"))
+}
+
 // verifyWorktreeExists checks that a git worktree was actually created at the given path.
 // Returns an error if the worktree is missing or invalid.
 func verifyWorktreeExists(clonePath string) error {
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// skipPreflight is bound to --skip-preflight on every command with pre-flight checks.
+var skipPreflight bool
+
+// preflightChecks maps a command to the doctor checks that must pass
+// before it makes changes. Populated by requirePreflight.
+var preflightChecks = make(map[*cobra.Command]func() []doctor.Check)
+
+// Risky commands and the invariants they depend on. A check that fails with
+// an error aborts the command; warnings are let through.
+func init() {
+	requirePreflight(doltMigrateCmd, func() []doctor.Check {
+		return []doctor.Check{
+			doctor.NewTownConfigValidCheck(),
+			doctor.NewRigsRegistryValidCheck(),
+			doctor.NewDoltBinaryCheck(),
+			doctor.NewPrefixConflictCheck(),
+		}
+	})
+	requirePreflight(doltRollbackCmd, func() []doctor.Check {
+		return []doctor.Check{
+			doctor.NewTownConfigValidCheck(),
+			doctor.NewRigsRegistryValidCheck(),
+			doctor.NewDoltBinaryCheck(),
+		}
+	})
+	requirePreflight(doltRestoreCmd, func() []doctor.Check {
+		return []doctor.Check{
+			doctor.NewTownConfigValidCheck(),
+			doctor.NewRigsRegistryValidCheck(),
+			doctor.NewDoltBinaryCheck(),
+		}
+	})
+	requirePreflight(rigRemoveCmd, func() []doctor.Check {
+		return []doctor.Check{
+			doctor.NewTownConfigValidCheck(),
+			doctor.NewRigsRegistryValidCheck(),
+			doctor.NewRoutesCheck(),
+		}
+	})
+	// Only batch slings (several beads to a rig) run these; see runSling.
+	requirePreflight(slingCmd, func() []doctor.Check {
+		return []doctor.Check{
+			doctor.NewTownConfigValidCheck(),
+			doctor.NewRigsRegistryValidCheck(),
+			doctor.NewRoutesCheck(),
+			doctor.NewBeadsDatabaseCheck(),
+			doctor.NewDoltServerReachableCheck(),
+		}
+	})
+}
+
+// requirePreflight declares the doctor checks that must pass before cmd
+// runs, and gives cmd a --skip-preflight flag. The command's RunE calls
+// runPreflight once it knows the town root.
+func requirePreflight(cmd *cobra.Command, checks func() []doctor.Check) {
+	preflightChecks[cmd] = checks
+	cmd.Flags().BoolVar(&skipPreflight, "skip-preflight", false, "Skip the pre-flight doctor checks")
+}
+
+// runPreflight runs cmd's pre-flight checks. If any fails with an error, it
+// prints the doctor report to stderr and returns an error so the command
+// aborts before changing anything.
+func runPreflight(cmd *cobra.Command, townRoot string) error {
+	checks, ok := preflightChecks[cmd]
+	if !ok || skipPreflight {
+		return nil
+	}
+
+	d := doctor.NewDoctor()
+	d.RegisterAll(checks()...)
+	report := d.Run(&doctor.CheckContext{TownRoot: townRoot})
+	if !report.HasErrors() {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s Pre-flight checks failed for '%s'\n", style.Error.Render("✗"), cmd.CommandPath())
+	report.Print(os.Stderr, false, 0)
+	return fmt.Errorf("pre-flight checks failed: fix the errors above (gt doctor --fix may help) or rerun with --skip-preflight")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+)
+
+type stubPreflightCheck struct {
+	doctor.BaseCheck
+	status doctor.CheckStatus
+	ran    *int
+}
+
+func (c *stubPreflightCheck) Run(ctx *doctor.CheckContext) *doctor.CheckResult {
+	*c.ran++
+	return &doctor.CheckResult{Name: c.Name(), Status: c.status, Message: "stub"}
+}
+
+func TestRunPreflight(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  doctor.CheckStatus
+		skip    bool
+		wantErr bool
+		wantRan int
+	}{
+		{"passing check", doctor.StatusOK, false, false, 1},
+		{"warning lets command run", doctor.StatusWarning, false, false, 1},
+		{"error aborts", doctor.StatusError, false, true, 1},
+		{"skip-preflight bypasses", doctor.StatusError, true, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "risky"}
+			ran := 0
+			requirePreflight(cmd, func() []doctor.Check {
+				return []doctor.Check{&stubPreflightCheck{
+					BaseCheck: doctor.BaseCheck{CheckName: "stub"},
+					status:    tt.status,
+					ran:       &ran,
+				}}
+			})
+			defer delete(preflightChecks, cmd)
+			if tt.skip {
+				if err := cmd.Flags().Set("skip-preflight", "true"); err != nil {
+					t.Fatal(err)
+				}
+				defer func() { skipPreflight = false }()
+			}
+
+			err := runPreflight(cmd, t.TempDir())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runPreflight error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "--skip-preflight") {
+				t.Errorf("error %q should mention --skip-preflight", err)
+			}
+			if ran != tt.wantRan {
+				t.Errorf("check ran %d times, want %d", ran, tt.wantRan)
+			}
+		})
+	}
+}
+
+func TestRiskyCommandsDeclarePreflight(t *testing.T) {
+	for _, cmd := range []*cobra.Command{doltMigrateCmd, doltRollbackCmd, rigRemoveCmd, slingCmd} {
+		if _, ok := preflightChecks[cmd]; !ok {
+			t.Errorf("%s has no pre-flight checks", cmd.CommandPath())
+		}
+		if cmd.Flags().Lookup("skip-preflight") == nil {
+			t.Errorf("%s has no --skip-preflight flag", cmd.CommandPath())
+		}
+	}
+}
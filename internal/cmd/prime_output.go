@@ -10,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -54,12 +55,17 @@ func outputPrimeContext(ctx RoleContext) error {
 	townName, _ := workspace.GetTownName(ctx.TownRoot)
 
 	// Get default branch from rig config (default to "main" if not set)
+	// and the rig's prompt vars from its settings.
 	defaultBranch := "main"
+	var promptVars map[string]string
 	if ctx.Rig != "" && ctx.TownRoot != "" {
 		rigPath := filepath.Join(ctx.TownRoot, ctx.Rig)
 		if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil && rigCfg.DefaultBranch != "" {
 			defaultBranch = rigCfg.DefaultBranch
 		}
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+			promptVars = settings.PromptVars
+		}
 	}
 
 	data := templates.RoleData{
@@ -72,6 +78,7 @@ func outputPrimeContext(ctx RoleContext) error {
 		Polecat:       ctx.Polecat,
 		MayorSession:  session.MayorSessionName(),
 		DeaconSession: session.DeaconSessionName(),
+		Vars:          promptVars,
 	}
 
 	// Render and output
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	pruneSessionsDryRun      bool
+	pruneSessionsIncludeCrew bool
+	pruneSessionsMinAge      time.Duration
+	pruneSessionsJSON        bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:     "prune",
+	GroupID: GroupWork,
+	Short:   "Remove leftover state no agent owns",
+	RunE:    requireSubcommand,
+}
+
+var pruneSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Kill orphaned rig tmux sessions",
+	Long: `Kill tmux sessions that carry a rig prefix (gt-*, or another rig's
+prefix from rigs.json) but belong to no known agent.
+
+A session is an orphan when:
+  - its rig is not in mayor/rigs.json (the rig was removed), or
+  - it is a polecat with no <rig>/polecats/<name> directory, or
+  - it is a crew member with no <rig>/crew/<name> directory.
+
+Witness and refinery sessions of registered rigs, and town-level hq-*
+sessions, are never pruned. Orphaned crew sessions are only reported
+unless --include-crew is given, since crew workers are human-managed.
+Sessions younger than --min-age are kept, so a session started just before
+its polecat directory is created is not killed.
+
+With the session_prune patrol enabled in mayor/daemon.json, the daemon
+prunes on a schedule (never killing crew sessions), and
+'gt daemon trigger session_prune' runs it immediately.
+
+Examples:
+  gt prune sessions --dry-run       # Show orphans without killing
+  gt prune sessions                 # Kill confirmed orphans
+  gt prune sessions --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runPruneSessions,
+}
+
+func init() {
+	pruneSessionsCmd.Flags().BoolVar(&pruneSessionsDryRun, "dry-run", false, "Show what would be killed without killing")
+	pruneSessionsCmd.Flags().BoolVar(&pruneSessionsIncludeCrew, "include-crew", false, "Also kill orphaned crew sessions")
+	pruneSessionsCmd.Flags().DurationVar(&pruneSessionsMinAge, "min-age", session.DefaultPruneMinAge, "Keep orphans younger than this")
+	pruneSessionsCmd.Flags().BoolVar(&pruneSessionsJSON, "json", false, "Output as JSON")
+
+	pruneCmd.AddCommand(pruneSessionsCmd)
+	rootCmd.AddCommand(pruneCmd)
+}
+
+func runPruneSessions(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	result, err := session.PruneOrphanSessions(townRoot, session.PruneOptions{
+		DryRun:      pruneSessionsDryRun,
+		IncludeCrew: pruneSessionsIncludeCrew,
+		MinAge:      pruneSessionsMinAge,
+		Caller:      "gt prune sessions",
+	})
+	if err != nil {
+		return err
+	}
+
+	if pruneSessionsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(result.Orphans) == 0 {
+		fmt.Printf("%s No orphaned sessions (%d rig session(s) checked)\n", style.Bold.Render("✓"), result.Checked)
+		return nil
+	}
+
+	var failed int
+	for _, o := range result.Orphans {
+		switch {
+		case o.Killed:
+			fmt.Printf("%s Killed %s: %s\n", style.Success.Render("✓"), o.Session, o.Reason)
+		case o.Error != "":
+			failed++
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), o.Session, o.Error)
+		case o.Skipped != "":
+			fmt.Printf("%s Kept %s (%s): %s\n", style.Dim.Render("○"), o.Session, o.Skipped, o.Reason)
+		default:
+			fmt.Printf("%s Would kill %s: %s\n", style.Warning.Render("⚠"), o.Session, o.Reason)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to kill %d session(s)", failed)
+	}
+	return nil
+}
@@ -9,19 +9,23 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
-	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var readyJSON bool
 var readyRig string
+var readyAllRigs bool
+var readyRole string
+var readyWeights map[string]int
 
 var readyCmd = &cobra.Command{
 	Use:     "ready",
@@ -33,19 +37,34 @@ Aggregates ready issues from:
 - Town beads (hq-* items: convoys, cross-rig coordination)
 - Each rig's beads (project-level issues, MRs)
 
-Ready items have no blockers and can be worked immediately.
+Ready items have no blockers and can be worked immediately. Beads
+snoozed with 'gt bead snooze' are left out until they wake.
 Results are sorted by priority (highest first) then by source.
 
+With --all-rigs, sources are merged into a single town-wide queue ordered
+by what should be worked next: priority first, then rig weight (higher
+first, set with --weight), then age (oldest first). --role narrows the
+queue to work that role picks up:
+
+  polecat, crew   Tasks, bugs, features (no MRs, convoys, epics, molecules)
+  refinery        Merge requests
+  mayor           Convoys and epics
+
 Examples:
   gt ready              # Show all ready work
   gt ready --json       # Output as JSON
-  gt ready --rig=gastown  # Show only one rig`,
+  gt ready --rig=gastown  # Show only one rig
+  gt ready --all-rigs --role polecat --json     # Queue for the auto-scaler
+  gt ready --all-rigs --weight gastown=2        # Favor one rig at equal priority`,
 	RunE: runReady,
 }
 
 func init() {
 	readyCmd.Flags().BoolVar(&readyJSON, "json", false, "Output as JSON")
 	readyCmd.Flags().StringVar(&readyRig, "rig", "", "Filter to a specific rig")
+	readyCmd.Flags().BoolVar(&readyAllRigs, "all-rigs", false, "Merge all sources into one priority-ordered queue")
+	readyCmd.Flags().StringVar(&readyRole, "role", "", "With --all-rigs: only work for this role (polecat, crew, refinery, mayor)")
+	readyCmd.Flags().StringToIntVar(&readyWeights, "weight", nil, "With --all-rigs: rig weights for tie-breaking, e.g. gastown=2")
 	rootCmd.AddCommand(readyCmd)
 }
 
@@ -74,16 +93,101 @@ type ReadySummary struct {
 	P4Count  int            `json:"p4_count"`
 }
 
+// ReadyQueueItem is one entry in the merged town-wide ready queue.
+type ReadyQueueItem struct {
+	Position int    `json:"position"`
+	Source   string `json:"source"` // "town" or rig name
+	*beads.Issue
+}
+
+// ReadyQueue is the --all-rigs output of gt ready.
+type ReadyQueue struct {
+	Role     string            `json:"role,omitempty"`
+	Items    []ReadyQueueItem  `json:"items"`
+	Total    int               `json:"total"`
+	Errors   map[string]string `json:"errors,omitempty"`
+	TownRoot string            `json:"town_root,omitempty"`
+}
+
 func runReady(cmd *cobra.Command, args []string) error {
+	if !readyAllRigs && (readyRole != "" || len(readyWeights) > 0) {
+		return fmt.Errorf("--role and --weight require --all-rigs")
+	}
+	if readyAllRigs && readyRig != "" {
+		return fmt.Errorf("--all-rigs and --rig are mutually exclusive")
+	}
+	if readyRole != "" {
+		if _, ok := readyRoleFilters[readyRole]; !ok {
+			return fmt.Errorf("unknown role %q (valid: polecat, crew, refinery, mayor)", readyRole)
+		}
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	result, err := CollectReady(townRoot, readyRig)
+	if err != nil {
+		return err
+	}
+	sources := result.Sources
+
+	// Check for source errors
+	var failedSources []string
+	for _, src := range sources {
+		if src.Error != "" {
+			failedSources = append(failedSources, src.Name)
+		}
+	}
+
+	if readyAllRigs {
+		queue := buildReadyQueue(sources, readyRole, readyWeights)
+		queue.TownRoot = townRoot
+		if readyJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(queue)
+		}
+		printReadyQueue(queue)
+		if len(failedSources) > 0 {
+			if len(failedSources) == len(sources) {
+				return fmt.Errorf("all sources failed to load: %s", strings.Join(failedSources, ", "))
+			}
+			style.PrintWarning("some sources failed to load: %s (results may be incomplete)", strings.Join(failedSources, ", "))
+		}
+		return nil
+	}
+
+	// Output
+	if readyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if err := printReadyHuman(result); err != nil {
+		return err
+	}
+
+	// Surface source errors to the user
+	if len(failedSources) > 0 {
+		if len(failedSources) == len(sources) {
+			return fmt.Errorf("all sources failed to load: %s", strings.Join(failedSources, ", "))
+		}
+		style.PrintWarning("some sources failed to load: %s (results may be incomplete)", strings.Join(failedSources, ", "))
+	}
+
+	return nil
+}
+
+// CollectReady gathers ready work from town beads and every rig (or only
+// rigName, if set), with formula scaffolds, wisps, and identity beads
+// filtered out. Sources that fail to load carry an Error instead of issues.
+func CollectReady(townRoot, rigName string) (ReadyResult, error) {
 	// Load rigs config
-	rigsConfigPath := constants.MayorRigsPath(townRoot)
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -93,20 +197,20 @@ func runReady(cmd *cobra.Command, args []string) error {
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 	rigs, err := mgr.DiscoverRigs()
 	if err != nil {
-		return fmt.Errorf("discovering rigs: %w", err)
+		return ReadyResult{}, fmt.Errorf("discovering rigs: %w", err)
 	}
 
-	// Filter rigs if --rig flag provided
-	if readyRig != "" {
+	// Filter to one rig if requested
+	if rigName != "" {
 		var filtered []*rig.Rig
 		for _, r := range rigs {
-			if r.Name == readyRig {
+			if r.Name == rigName {
 				filtered = append(filtered, r)
 				break
 			}
 		}
 		if len(filtered) == 0 {
-			return fmt.Errorf("rig not found: %s", readyRig)
+			return ReadyResult{}, fmt.Errorf("rig not found: %s", rigName)
 		}
 		rigs = filtered
 	}
@@ -117,7 +221,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 	sources := make([]ReadySource, 0, len(rigs)+1)
 
 	// Fetch town beads (only if not filtering to a specific rig)
-	if readyRig == "" {
+	if rigName == "" {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -138,7 +242,10 @@ func runReady(cmd *cobra.Command, args []string) error {
 				wispIDs := getWispIDs(townBeadsPath)
 				filtered = filterWisps(filtered, wispIDs)
 				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				filtered = filterIdentityBeads(filtered)
+				// Hide beads snoozed with gt bead snooze until they wake
+				snoozedIDs, _ := townBeads.SnoozedIDs(time.Now())
+				src.Issues = filterSnoozed(filtered, snoozedIDs)
 			}
 			sources = append(sources, src)
 		}()
@@ -167,7 +274,10 @@ func runReady(cmd *cobra.Command, args []string) error {
 				wispIDs := getWispIDs(r.BeadsPath())
 				filtered = filterWisps(filtered, wispIDs)
 				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				filtered = filterIdentityBeads(filtered)
+				// Hide beads snoozed with gt bead snooze until they wake
+				snoozedIDs, _ := rigBeads.SnoozedIDs(time.Now())
+				src.Issues = filterSnoozed(filtered, snoozedIDs)
 			}
 			sources = append(sources, src)
 		}(r)
@@ -217,40 +327,11 @@ func runReady(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	result := ReadyResult{
+	return ReadyResult{
 		Sources:  sources,
 		Summary:  summary,
 		TownRoot: townRoot,
-	}
-
-	// Check for source errors
-	var failedSources []string
-	for _, src := range sources {
-		if src.Error != "" {
-			failedSources = append(failedSources, src.Name)
-		}
-	}
-
-	// Output
-	if readyJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(result)
-	}
-
-	if err := printReadyHuman(result); err != nil {
-		return err
-	}
-
-	// Surface source errors to the user
-	if len(failedSources) > 0 {
-		if len(failedSources) == len(sources) {
-			return fmt.Errorf("all sources failed to load: %s", strings.Join(failedSources, ", "))
-		}
-		style.PrintWarning("some sources failed to load: %s (results may be incomplete)", strings.Join(failedSources, ", "))
-	}
-
-	return nil
+	}, nil
 }
 
 func printReadyHuman(result ReadyResult) error {
@@ -326,6 +407,104 @@ func printReadyHuman(result ReadyResult) error {
 	return nil
 }
 
+// readyRoleFilters reports whether an issue is work the given role picks up.
+var readyRoleFilters = map[string]func(*beads.Issue) bool{
+	"polecat":  isReadyWorkItem,
+	"crew":     isReadyWorkItem,
+	"refinery": isReadyMergeRequest,
+	"mayor":    isReadyCoordination,
+}
+
+func isReadyMergeRequest(issue *beads.Issue) bool {
+	return issue.Type == "merge-request" || beads.HasLabel(issue, "gt:merge-request")
+}
+
+func isReadyCoordination(issue *beads.Issue) bool {
+	return issue.Type == "convoy" || issue.Type == "epic" || beads.HasLabel(issue, "gt:convoy")
+}
+
+func isReadyWorkItem(issue *beads.Issue) bool {
+	return !isReadyMergeRequest(issue) && !isReadyCoordination(issue) && issue.Type != "molecule"
+}
+
+// buildReadyQueue merges ready issues from every source into one queue,
+// ordered by priority, then rig weight (higher first), then age (oldest
+// first). Issues with unparseable creation times sort after dated ones.
+func buildReadyQueue(sources []ReadySource, role string, weights map[string]int) ReadyQueue {
+	queue := ReadyQueue{Role: role, Items: []ReadyQueueItem{}}
+	keep := readyRoleFilters[role]
+	for _, src := range sources {
+		if src.Error != "" {
+			if queue.Errors == nil {
+				queue.Errors = make(map[string]string)
+			}
+			queue.Errors[src.Name] = src.Error
+			continue
+		}
+		for _, issue := range src.Issues {
+			if keep != nil && !keep(issue) {
+				continue
+			}
+			queue.Items = append(queue.Items, ReadyQueueItem{Source: src.Name, Issue: issue})
+		}
+	}
+
+	created := func(item ReadyQueueItem) (time.Time, bool) {
+		t, err := time.Parse(time.RFC3339, item.CreatedAt)
+		return t, err == nil
+	}
+	sort.SliceStable(queue.Items, func(i, j int) bool {
+		a, b := queue.Items[i], queue.Items[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if wa, wb := weights[a.Source], weights[b.Source]; wa != wb {
+			return wa > wb
+		}
+		ta, okA := created(a)
+		tb, okB := created(b)
+		if okA != okB {
+			return okA
+		}
+		if !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.ID < b.ID
+	})
+
+	for i := range queue.Items {
+		queue.Items[i].Position = i + 1
+	}
+	queue.Total = len(queue.Items)
+	return queue
+}
+
+func printReadyQueue(queue ReadyQueue) {
+	if queue.Total == 0 {
+		if queue.Role != "" {
+			fmt.Printf("No ready work for %s across town.\n", queue.Role)
+		} else {
+			fmt.Println("No ready work across town.")
+		}
+		return
+	}
+
+	header := "Town ready queue"
+	if queue.Role != "" {
+		header += " for " + queue.Role
+	}
+	fmt.Printf("%s %s:\n\n", style.Bold.Render("📋"), header)
+	for _, item := range queue.Items {
+		title := item.Title
+		if len(title) > 60 {
+			title = title[:57] + "..."
+		}
+		fmt.Printf("  %3d. [P%d] %-10s %s %s\n", item.Position, item.Priority,
+			item.Source, style.Dim.Render(item.ID), title)
+	}
+	fmt.Printf("\nTotal: %d items ready\n", queue.Total)
+}
+
 // getFormulaNames reads the formulas directory and returns a set of formula names.
 // Formula names are derived from filenames by removing the ".formula.toml" suffix.
 func getFormulaNames(beadsPath string) map[string]bool {
@@ -479,3 +658,18 @@ func filterWisps(issues []*beads.Issue, wispIDs map[string]bool) []*beads.Issue
 	}
 	return filtered
 }
+
+// filterSnoozed removes snoozed beads that haven't woken yet.
+func filterSnoozed(issues []*beads.Issue, snoozedIDs map[string]bool) []*beads.Issue {
+	if len(snoozedIDs) == 0 {
+		return issues
+	}
+
+	filtered := make([]*beads.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if !snoozedIDs[issue.ID] {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -148,3 +149,82 @@ func TestFilterFormulaScaffolds_DotInNonScaffold(t *testing.T) {
 		t.Errorf("got %d issues, want 2 (non-formula dots should not filter)", len(filtered))
 	}
 }
+
+func TestBuildReadyQueue_Ordering(t *testing.T) {
+	sources := []ReadySource{
+		{Name: "town", Issues: []*beads.Issue{
+			{ID: "hq-1", Priority: 2, CreatedAt: "2025-01-03T00:00:00Z"},
+		}},
+		{Name: "alpha", Issues: []*beads.Issue{
+			{ID: "al-1", Priority: 1, CreatedAt: "2025-01-05T00:00:00Z"},
+			{ID: "al-2", Priority: 2, CreatedAt: "2025-01-01T00:00:00Z"},
+		}},
+		{Name: "beta", Issues: []*beads.Issue{
+			{ID: "be-1", Priority: 2, CreatedAt: "2025-01-04T00:00:00Z"},
+			{ID: "be-2", Priority: 2, CreatedAt: "not-a-date"},
+		}},
+		{Name: "broken", Error: "db unavailable"},
+	}
+
+	queue := buildReadyQueue(sources, "", map[string]int{"beta": 1})
+
+	var got []string
+	for _, item := range queue.Items {
+		got = append(got, item.ID)
+	}
+	// P1 first; among P2, weighted beta first (dated before undated),
+	// then the rest oldest first.
+	want := []string{"al-1", "be-1", "be-2", "al-2", "hq-1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("queue order = %v, want %v", got, want)
+	}
+	if queue.Total != 5 || queue.Items[0].Position != 1 || queue.Items[4].Position != 5 {
+		t.Errorf("total/positions wrong: %+v", queue)
+	}
+	if queue.Items[1].Source != "beta" {
+		t.Errorf("item source = %q, want beta", queue.Items[1].Source)
+	}
+	if queue.Errors["broken"] != "db unavailable" {
+		t.Errorf("errors = %v, want broken source recorded", queue.Errors)
+	}
+}
+
+func TestBuildReadyQueue_RoleFilter(t *testing.T) {
+	sources := []ReadySource{{Name: "gastown", Issues: []*beads.Issue{
+		{ID: "gt-task", Type: "task"},
+		{ID: "gt-mr", Type: "merge-request"},
+		{ID: "gt-cv", Type: "task", Labels: []string{"gt:convoy"}},
+		{ID: "gt-ep", Type: "epic"},
+		{ID: "gt-mol", Type: "molecule"},
+	}}}
+
+	tests := []struct {
+		role string
+		want []string
+	}{
+		{"polecat", []string{"gt-task"}},
+		{"refinery", []string{"gt-mr"}},
+		{"mayor", []string{"gt-cv", "gt-ep"}},
+	}
+	for _, tt := range tests {
+		queue := buildReadyQueue(sources, tt.role, nil)
+		var got []string
+		for _, item := range queue.Items {
+			got = append(got, item.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("role %s: got %v, want %v", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestFilterSnoozed(t *testing.T) {
+	issues := []*beads.Issue{{ID: "gt-a"}, {ID: "gt-b"}, {ID: "gt-c"}}
+	got := filterSnoozed(issues, map[string]bool{"gt-b": true})
+	if len(got) != 2 || got[0].ID != "gt-a" || got[1].ID != "gt-c" {
+		t.Errorf("filterSnoozed = %v, want gt-a and gt-c", got)
+	}
+	if got := filterSnoozed(issues, nil); len(got) != 3 {
+		t.Errorf("nil snoozed set removed issues: %v", got)
+	}
+}
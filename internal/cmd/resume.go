@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -94,7 +95,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 
 	// Check gate status
 	gateCheck := exec.Command("bd", "gate", "show", parked.GateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gateOutput, err := proc.Output(gateCheck)
 	gateNotFound := false
 	if err != nil {
 		// Gate might have been deleted (wisp cleanup) or is inaccessible
@@ -158,7 +159,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 		pinCmd := exec.Command("bd", "update", parked.BeadID, "--status=pinned", "--assignee="+agentID)
 		pinCmd.Dir = cloneRoot
 		pinCmd.Stderr = os.Stderr
-		if err := pinCmd.Run(); err != nil {
+		if err := proc.RunCmd(pinCmd); err != nil {
 			return fmt.Errorf("pinning bead: %w", err)
 		}
 
@@ -4,6 +4,7 @@ package cmd
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/approval"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -19,8 +21,10 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -109,6 +113,9 @@ kill them automatically.
 
 To fully remove a rig, delete the directory manually after unregistering.
 
+Pre-flight doctor checks (town config, rigs registry, routes) run first and
+abort on failure; --skip-preflight bypasses them.
+
 Examples:
   gt rig remove myproject                    # Unregister (fails if sessions running)
   gt rig remove myproject --force            # Kill sessions then unregister
@@ -311,6 +318,7 @@ var (
 	rigRestartNuclear  bool
 	rigListJSON        bool
 	rigRemoveForce     bool
+	rigRemoveApproval  string
 )
 
 func init() {
@@ -330,6 +338,7 @@ func init() {
 	rigListCmd.Flags().BoolVar(&rigListJSON, "json", false, "Output as JSON")
 
 	rigRemoveCmd.Flags().BoolVarP(&rigRemoveForce, "force", "f", false, "Kill running tmux sessions before removing (may lose uncommitted work)")
+	rigRemoveCmd.Flags().StringVar(&rigRemoveApproval, "approval", "", "Approval token from 'gt approve rig-remove' (when required by town policy)")
 
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
@@ -359,6 +368,9 @@ func init() {
 
 func runRigAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
+	if err := rigsconfig.ValidateName(name); err != nil {
+		return err
+	}
 
 	// Handle --adopt mode: register existing directory
 	if rigAddAdopt {
@@ -387,9 +399,11 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
+		if !errors.Is(err, rigsconfig.ErrNotFound) {
+			return fmt.Errorf("loading rigs config: %w", err)
+		}
 		// Create new if doesn't exist
 		rigsConfig = &config.RigsConfig{
 			Version: 1,
@@ -422,7 +436,7 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save updated rigs config
-	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+	if err := rigsconfig.Save(townRoot, rigsConfig); err != nil {
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
 
@@ -502,8 +516,7 @@ func runRigList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		fmt.Println("No rigs configured.")
 		return nil
@@ -619,12 +632,20 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		return fmt.Errorf("loading rigs config: %w", err)
 	}
 
+	// Town approval policy (gt approve rig-remove)
+	if err := requireApproval(townRoot, approval.ClassRigRemove, name, rigRemoveApproval); err != nil {
+		return err
+	}
+
+	if err := runPreflight(cmd, townRoot); err != nil {
+		return err
+	}
+
 	// Get the rig's beads prefix before removing (needed for route cleanup)
 	var beadsPrefix string
 	if entry, ok := rigsConfig.Rigs[name]; ok && entry.BeadsConfig != nil {
@@ -680,7 +701,7 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save updated config
-	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+	if err := rigsconfig.Save(townRoot, rigsConfig); err != nil {
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
 
@@ -709,9 +730,11 @@ func runRigAdopt(_ *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
+		if !errors.Is(err, rigsconfig.ErrNotFound) {
+			return fmt.Errorf("loading rigs config: %w", err)
+		}
 		rigsConfig = &config.RigsConfig{
 			Version: 1,
 			Rigs:    make(map[string]config.RigEntry),
@@ -741,7 +764,7 @@ func runRigAdopt(_ *cobra.Command, args []string) error {
 	}
 
 	// Save updated config
-	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+	if err := rigsconfig.Save(townRoot, rigsConfig); err != nil {
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
 
@@ -792,7 +815,7 @@ func runRigAdopt(_ *cobra.Command, args []string) error {
 				workDir := filepath.Dir(beadsDir)
 				bdCmd := exec.Command("bd", "config", "get", "issue_prefix")
 				bdCmd.Dir = workDir
-				if out, bdErr := bdCmd.Output(); bdErr == nil {
+				if out, bdErr := proc.Output(bdCmd); bdErr == nil {
 					detected := strings.TrimSpace(string(out))
 					if detected != "" {
 						if rigAddPrefix != "" && strings.TrimSuffix(rigAddPrefix, "-") != detected {
@@ -1124,8 +1147,7 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config and get rig
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -1198,8 +1220,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -1308,8 +1329,7 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config and get rig
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -1577,8 +1597,7 @@ func runRigStop(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -1704,8 +1723,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load rigs config
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var rigDoctorCmd = &cobra.Command{
+	Use:   "doctor <rig>",
+	Short: "Run health checks on a single rig",
+	Long: `Run doctor checks scoped to one rig.
+
+Equivalent to 'gt doctor --rig <rig>': checks the rig's structure, beads
+directory, Dolt database metadata, worktrees, crew workspaces, and tmux
+sessions, skipping town-level state and other rigs. With --fix, only the
+rig's problems are fixed.
+
+Examples:
+  gt rig doctor gastown
+  gt rig doctor gastown --fix
+  gt rig doctor gastown --deep -v`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRigDoctor,
+}
+
+func init() {
+	rigDoctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix the rig's issues")
+	rigDoctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
+	rigDoctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
+	rigDoctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
+	rigDoctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	rigDoctorCmd.Flags().BoolVar(&doctorDeep, "deep", false, "Also simulate a full sling on the rig")
+	rigCmd.AddCommand(rigDoctorCmd)
+}
+
+func runRigDoctor(cmd *cobra.Command, args []string) error {
+	doctorRig = args[0]
+	return runDoctor(cmd, args)
+}
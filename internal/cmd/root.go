@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timefmt"
 	"github.com/steveyegge/gastown/internal/ui"
 	"github.com/steveyegge/gastown/internal/version"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -51,6 +54,7 @@ var beadsExemptCommands = map[string]bool{
 	"hook":       true,
 	"prime":      true,
 	"nudge":      true,
+	"ack":        true,
 	"seance":     true,
 	"doctor":     true,
 	"dolt":       true,
@@ -59,12 +63,15 @@ var beadsExemptCommands = map[string]bool{
 	"feed":       true,
 	"rig":        true,
 	"config":     true,
+	"flags":      true,
 	"install":    true,
 	"tap":        true,
 	"dnd":        true,
 	"signal":        true, // Hook signal handlers must be fast, handle beads internally
 	"krc":           true, // KRC doesn't require beads
 	"run-migration": true, // Migration orchestrator handles its own beads checks
+	"support-bundle": true, // Diagnostics must work when beads is broken
+	"verify-install": true, // Checks bd itself, in a scratch town
 }
 
 // Commands exempt from the town root branch warning.
@@ -74,12 +81,25 @@ var branchCheckExemptCommands = map[string]bool{
 	"help":       true,
 	"completion": true,
 	"doctor":     true, // Used to fix the problem
+	"support-bundle": true, // Diagnostic
+	"verify-install": true, // Runs in a scratch town
 	"install":    true, // Initial setup
 	"git-init":   true, // Git setup
 }
 
+// rootUTC is the global --utc flag.
+var rootUTC bool
+
 // persistentPreRun runs before every command.
 func persistentPreRun(cmd *cobra.Command, args []string) error {
+	// Compare with the town's pinned gt version first: with redirect enabled
+	// this may re-exec the town-local binary, which then does all the rest.
+	if !isVersionCommand(cmd) {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			checkPinnedVersion(townRoot)
+		}
+	}
+
 	// Check if binary was built properly (via make build, not raw go build).
 	// Raw go build produces unsigned binaries that macOS may kill.
 	// Warning only - doesn't block execution.
@@ -96,6 +116,24 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Initialize CLI theme (dark/light mode support)
 	initCLITheme()
 
+	// Agent-safe output: no styling, ASCII glyphs (--agent, GT_ROLE, non-TTY)
+	if rootAgent {
+		ui.SetAgentMode(true)
+	}
+	if stopPlainOutput == nil && usePlainOutput(cmd) {
+		startPlainOutput()
+	}
+
+	// Times in output are local unless --utc (or GT_UTC=1) is given.
+	timefmt.SetUTC(rootUTC || os.Getenv("GT_UTC") == "1")
+
+	// Cap concurrent bd/dolt subprocesses for this process's role.
+	initSubprocessLimit(cmd)
+
+	// Fill flags not given on the command line from configured defaults
+	// (env, then town settings, then user config).
+	applyFlagDefaults(cmd)
+
 	// Initialize session prefix registry from rigs.json.
 	// Best-effort: if town root not found, the default "gt" prefix is used.
 	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
@@ -144,6 +182,29 @@ func initCLITheme() {
 	ui.ApplyThemeMode()
 }
 
+// initSubprocessLimit sets how many bd and dolt processes this gt process
+// runs at once: GT_MAX_SUBPROCS if set, else the town's subprocess_limits
+// entry for the caller's role (GT_ROLE, or "daemon" for gt daemon run).
+func initSubprocessLimit(cmd *cobra.Command) {
+	if n, err := strconv.Atoi(os.Getenv("GT_MAX_SUBPROCS")); err == nil && n > 0 {
+		proc.SetLimit(n)
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return
+	}
+	role := config.ExtractSimpleRole(os.Getenv("GT_ROLE"))
+	if cmd == daemonRunCmd {
+		role = "daemon"
+	}
+	proc.SetLimit(settings.SubprocessLimit(role))
+}
+
 // warnIfTownRootOffMain prints a warning if the town root is not on main branch.
 // This is a non-blocking warning to help catch accidental branch switches.
 func warnIfTownRootOffMain() {
@@ -240,7 +301,11 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if stopPlainOutput != nil {
+		stopPlainOutput()
+	}
+	if err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
@@ -283,6 +348,9 @@ func init() {
 
 	// Global flags can be added here
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	rootCmd.PersistentFlags().BoolVar(&rootUTC, "utc", false, "Show times in UTC instead of local time (or set GT_UTC=1)")
+	rootCmd.PersistentFlags().BoolVar(&rootAgent, "agent", false, "Agent-safe output: no styling, ASCII glyphs, capped fields (auto with GT_ROLE or non-TTY; GT_AGENT_MODE=0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&rootAgent, "plain", false, "Alias for --agent")
 }
 
 // buildCommandPath walks the command hierarchy to build the full command path.
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	searchRigs     []string
+	searchArchived bool
+	searchLimit    int
+	searchJSON     bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	GroupID: GroupWork,
+	Short:   "Search beads across rigs",
+	Long: `Search the beads of every rig database for an ID, or for text in a
+title or description (case-insensitive).
+
+By default only the live issues table is searched. With --archived, the
+closed wisps and digests moved out by 'gt dolt archive' are searched too
+and marked as archived.
+
+Examples:
+  gt search "merge conflict"
+  gt search gt-abc12 --archived
+  gt search patrol --rig gastown --archived --json`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVar(&searchRigs, "rig", nil, "Rig database(s) to search (default: all)")
+	searchCmd.Flags().BoolVar(&searchArchived, "archived", false, "Also search archived beads")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 50, "Maximum results per rig and table")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := doltTargetDatabases(townRoot, searchRigs)
+	if err != nil {
+		return err
+	}
+
+	opts := doltserver.SearchOptions{
+		Query:    strings.Join(args, " "),
+		Archived: searchArchived,
+		Limit:    searchLimit,
+	}
+	hits := []doltserver.SearchHit{}
+	var errs []error
+	for _, db := range databases {
+		res, err := doltserver.SearchBeads(townRoot, db, opts)
+		hits = append(hits, res...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if searchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(hits); err != nil {
+			return err
+		}
+		return errors.Join(errs...)
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("%s No beads match %q\n", style.Dim.Render("○"), opts.Query)
+		return errors.Join(errs...)
+	}
+	for _, h := range hits {
+		line := fmt.Sprintf("%s [%s] %s", style.Bold.Render(h.ID), h.Status, h.Title)
+		if h.Archived {
+			line += " " + style.Dim.Render(fmt.Sprintf("(archived %s)", h.ArchivedAt))
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("\n%d result(s)\n", len(hits))
+	return errors.Join(errs...)
+}
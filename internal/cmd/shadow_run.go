@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	shadowRunRig     string
+	shadowRunVars    []string
+	shadowRunPour    bool
+	shadowRunExec    string
+	shadowRunPromote bool
+	shadowRunKeep    bool
+	shadowRunJSON    bool
+
+	doltShadowJSON bool
+)
+
+var shadowRunCmd = &cobra.Command{
+	Use:     "shadow-run <formula>",
+	GroupID: GroupWork,
+	Short:   "Run a formula against a throwaway copy of a rig database",
+	Long: `Test a formula without touching production beads.
+
+shadow-run copies the rig's database into a shadow database on the town's
+Dolt server (<rig>_shadow_<timestamp>), points a scratch workspace at it,
+and instantiates the formula there with bd cook and bd mol wisp (or
+bd mol pour with --pour). --exec then runs a command in the scratch
+workspace to drive the molecule, standing in for the polecat that would
+work it. Its environment has:
+
+  BEADS_DIR            the scratch .beads, so bd reads and writes the shadow
+  GT_SHADOW_DB         the shadow database name
+  GT_SHADOW_MOLECULE   the molecule's root bead
+
+When the run finishes, shadow-run reports every row the run added,
+changed, or removed, and the beads it created, moved, or closed. The shadow
+is then dropped, unless --promote replays the changes onto the rig database
+or --keep leaves it for inspection. A kept shadow can be promoted or
+dropped later:
+
+  gt dolt shadow list
+  gt dolt shadow promote <shadow-db>
+  gt dolt shadow discard <shadow-db>
+
+Shadow databases are skipped by backups, exports, and patrols.
+
+Examples:
+  gt shadow-run mol-release --rig gastown --var version=1.2.0
+  gt shadow-run mol-cleanup --rig gastown --exec './drive-steps.sh'
+  gt shadow-run mol-cleanup --rig gastown --keep --json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runShadowRun,
+}
+
+var doltShadowCmd = &cobra.Command{
+	Use:   "shadow",
+	Short: "Manage shadow databases kept by gt shadow-run --keep",
+	RunE:  requireSubcommand,
+}
+
+var doltShadowListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List shadow databases",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoltShadowList,
+}
+
+var doltShadowPromoteCmd = &cobra.Command{
+	Use:   "promote <shadow-db>",
+	Short: "Replay a shadow's changes onto its rig database and drop it",
+	Long: `Replay the rows a shadow run changed onto the rig database it was
+copied from, commit, and drop the shadow.
+
+The replay runs in one transaction: if the rig database changed the same
+rows since the shadow was created, nothing is applied and the shadow is
+kept. Schema changes are never promoted.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltShadowPromote,
+}
+
+var doltShadowDiscardCmd = &cobra.Command{
+	Use:          "discard <shadow-db>...",
+	Short:        "Drop shadow databases",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoltShadowDiscard,
+}
+
+func init() {
+	shadowRunCmd.Flags().StringVar(&shadowRunRig, "rig", "", "Rig database to copy (required)")
+	shadowRunCmd.Flags().StringArrayVar(&shadowRunVars, "var", nil, "Formula variable (key=value), can be repeated")
+	shadowRunCmd.Flags().BoolVar(&shadowRunPour, "pour", false, "Pour a persistent molecule instead of a wisp")
+	shadowRunCmd.Flags().StringVar(&shadowRunExec, "exec", "", "Shell command that drives the molecule in the scratch workspace")
+	shadowRunCmd.Flags().BoolVar(&shadowRunPromote, "promote", false, "Apply the shadow's changes to the rig database afterwards")
+	shadowRunCmd.Flags().BoolVar(&shadowRunKeep, "keep", false, "Keep the shadow database and scratch workspace")
+	shadowRunCmd.Flags().BoolVar(&shadowRunJSON, "json", false, "Output the report as JSON")
+	_ = shadowRunCmd.MarkFlagRequired("rig")
+	rootCmd.AddCommand(shadowRunCmd)
+
+	doltShadowListCmd.Flags().BoolVar(&doltShadowJSON, "json", false, "Output as JSON")
+	doltShadowCmd.AddCommand(doltShadowListCmd)
+	doltShadowCmd.AddCommand(doltShadowPromoteCmd)
+	doltShadowCmd.AddCommand(doltShadowDiscardCmd)
+	doltCmd.AddCommand(doltShadowCmd)
+}
+
+// shadowRunResult is the --json output of gt shadow-run.
+type shadowRunResult struct {
+	*doltserver.ShadowReport
+	Molecule  string `json:"molecule"`
+	Workspace string `json:"workspace,omitempty"`
+	ExecError string `json:"exec_error,omitempty"`
+	Promoted  int    `json:"promoted_statements,omitempty"`
+	Kept      bool   `json:"kept,omitempty"`
+}
+
+func runShadowRun(cmd *cobra.Command, args []string) error {
+	formulaName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if shadowRunPromote && shadowRunKeep {
+		return fmt.Errorf("--promote and --keep are mutually exclusive")
+	}
+	if !doltserver.DatabaseExists(townRoot, shadowRunRig) {
+		return fmt.Errorf("database %q not found in .dolt-data/\nRun 'gt dolt list' to see available databases", shadowRunRig)
+	}
+
+	formulasDir, err := shadowFormulasDir(townRoot, shadowRunRig, formulaName)
+	if err != nil {
+		return err
+	}
+	if err := checkFormulaVars(formulaName, filepath.Dir(filepath.Dir(formulasDir)), shadowRunVars); err != nil {
+		return err
+	}
+
+	// Progress goes to stderr so --json output stays parseable.
+	progress := os.Stdout
+	if shadowRunJSON {
+		progress = os.Stderr
+	}
+
+	fmt.Fprintf(progress, "Copying %s into a shadow database...\n", shadowRunRig)
+	shadow, err := doltserver.CreateShadow(townRoot, shadowRunRig)
+	if err != nil {
+		return err
+	}
+	keep := shadowRunKeep
+	defer func() {
+		if keep {
+			return
+		}
+		if err := doltserver.DiscardShadow(townRoot, shadow.Database); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Could not drop %s: %v\n", style.Warning.Render("⚠"), shadow.Database, err)
+		}
+	}()
+	fmt.Fprintf(progress, "%s Shadow %s\n", style.Success.Render("✓"), shadow.Database)
+
+	scratch, err := os.MkdirTemp("", "gt-shadow-")
+	if err != nil {
+		return fmt.Errorf("creating scratch workspace: %w", err)
+	}
+	defer func() {
+		if !keep {
+			_ = os.RemoveAll(scratch)
+		}
+	}()
+	beadsDir, err := doltserver.WriteShadowBeadsDir(townRoot, shadow, scratch, formulasDir)
+	if err != nil {
+		return fmt.Errorf("creating scratch workspace: %w", err)
+	}
+	env := append(os.Environ(), "BEADS_DIR="+beadsDir, "GT_SHADOW_DB="+shadow.Database)
+
+	molID, err := shadowInstantiate(formulaName, scratch, env, progress)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(progress, "%s Molecule %s\n", style.Success.Render("✓"), molID)
+
+	result := &shadowRunResult{Molecule: molID, Kept: keep}
+	if keep {
+		result.Workspace = scratch
+	}
+	if shadowRunExec != "" {
+		fmt.Fprintf(progress, "Running: %s\n", shadowRunExec)
+		execCmd := exec.Command("sh", "-c", shadowRunExec)
+		execCmd.Dir = scratch
+		execCmd.Env = append(env, "GT_SHADOW_MOLECULE="+molID)
+		execCmd.Stdout = progress
+		execCmd.Stderr = os.Stderr
+		if err := execCmd.Run(); err != nil {
+			result.ExecError = err.Error()
+		}
+	}
+
+	report, err := doltserver.DiffShadow(townRoot, shadow)
+	if err != nil {
+		return err
+	}
+	result.ShadowReport = report
+
+	if shadowRunPromote {
+		if result.ExecError != "" {
+			return fmt.Errorf("not promoting: --exec failed: %s", result.ExecError)
+		}
+		n, err := doltserver.PromoteShadow(townRoot, shadow)
+		if err != nil {
+			// Keep the shadow so the run isn't lost.
+			keep = true
+			return fmt.Errorf("%w\nShadow kept: gt dolt shadow promote %s", err, shadow.Database)
+		}
+		result.Promoted = n
+	}
+
+	if shadowRunJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printShadowReport(report)
+		switch {
+		case shadowRunPromote:
+			fmt.Printf("\n%s Promoted %d change(s) into %s\n", style.Success.Render("✓"), result.Promoted, shadow.Source)
+		case keep:
+			fmt.Printf("\n%s Kept %s (workspace %s)\n", style.Dim.Render("○"), shadow.Database, scratch)
+			fmt.Printf("  Promote: gt dolt shadow promote %s\n", shadow.Database)
+			fmt.Printf("  Discard: gt dolt shadow discard %s\n", shadow.Database)
+		default:
+			fmt.Printf("\n%s Discarded %s\n", style.Dim.Render("○"), shadow.Database)
+		}
+	}
+	if result.ExecError != "" {
+		return fmt.Errorf("--exec failed: %s", result.ExecError)
+	}
+	return nil
+}
+
+// shadowFormulasDir returns the formulas directory holding formulaName,
+// preferring the rig's .beads/formulas. The whole directory is copied into
+// the scratch workspace so formulas that extend others still cook.
+func shadowFormulasDir(townRoot, rig, formulaName string) (string, error) {
+	rigFormulas := filepath.Join(townRoot, rig, ".beads", "formulas")
+	for _, ext := range []string{".formula.toml", ".formula.json"} {
+		if _, err := os.Stat(filepath.Join(rigFormulas, formulaName+ext)); err == nil {
+			return rigFormulas, nil
+		}
+	}
+	path, err := findFormulaFile(formulaName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
+// shadowInstantiate cooks formulaName and creates a wisp (or poured
+// molecule) from it in the scratch workspace, returning the root bead.
+func shadowInstantiate(formulaName, dir string, env []string, progress *os.File) (string, error) {
+	cookCmd := exec.Command("bd", "cook", formulaName)
+	cookCmd.Dir = dir
+	cookCmd.Env = env
+	cookCmd.Stdout = progress
+	cookCmd.Stderr = os.Stderr
+	if err := proc.RunCmd(cookCmd); err != nil {
+		return "", fmt.Errorf("cooking formula: %w", err)
+	}
+
+	verb := "wisp"
+	if shadowRunPour {
+		verb = "pour"
+	}
+	molArgs := []string{"mol", verb, formulaName}
+	for _, v := range shadowRunVars {
+		molArgs = append(molArgs, "--var", v)
+	}
+	molArgs = append(molArgs, "--json")
+	molCmd := exec.Command("bd", molArgs...)
+	molCmd.Dir = dir
+	molCmd.Env = env
+	molCmd.Stderr = os.Stderr
+	out, err := proc.Output(molCmd)
+	if err != nil {
+		return "", fmt.Errorf("creating %s: %w", verb, err)
+	}
+	return parseWispIDFromJSON(out)
+}
+
+func printShadowReport(report *doltserver.ShadowReport) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Shadow run changes in "+report.Database))
+	if !report.Changed() {
+		fmt.Printf("  %s\n", style.Dim.Render("(no rows changed)"))
+		return
+	}
+	for _, t := range report.Tables {
+		fmt.Printf("  %-20s +%d ~%d -%d\n", t.Table, t.Added, t.Modified, t.Removed)
+	}
+	if len(report.Beads) == 0 {
+		return
+	}
+	fmt.Println()
+	for _, c := range report.Beads {
+		switch c.Kind {
+		case doltserver.ChangeCreated:
+			fmt.Printf("  + %s %s\n", c.BeadID, c.Title)
+		case doltserver.ChangeClosed:
+			fmt.Printf("  ✓ %s %s\n", c.BeadID, c.Title)
+		default:
+			fmt.Printf("  ~ %s %s (%s → %s)\n", c.BeadID, c.Title, c.FromStatus, c.ToStatus)
+		}
+	}
+}
+
+func runDoltShadowList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	shadows, err := doltserver.ListShadows(townRoot)
+	if err != nil {
+		return err
+	}
+	if doltShadowJSON {
+		if shadows == nil {
+			shadows = []*doltserver.Shadow{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shadows)
+	}
+	if len(shadows) == 0 {
+		fmt.Println("No shadow databases")
+		return nil
+	}
+	for _, s := range shadows {
+		fmt.Printf("  %s  %s\n", s.Database, style.Dim.Render("copy of "+s.Source+", "+s.CreatedAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func runDoltShadowPromote(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	shadow := doltserver.ParseShadow(args[0])
+	if shadow == nil {
+		return fmt.Errorf("%s is not a shadow database", args[0])
+	}
+	n, err := doltserver.PromoteShadow(townRoot, shadow)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Promoted %d change(s) into %s\n", style.Success.Render("✓"), n, shadow.Source)
+	if err := doltserver.DiscardShadow(townRoot, shadow.Database); err != nil {
+		return err
+	}
+	fmt.Printf("%s Discarded %s\n", style.Dim.Render("○"), shadow.Database)
+	return nil
+}
+
+func runDoltShadowDiscard(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var errs []error
+	for _, db := range args {
+		if err := doltserver.DiscardShadow(townRoot, db); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Printf("%s Discarded %s\n", style.Success.Render("✓"), db)
+	}
+	return errors.Join(errs...)
+}
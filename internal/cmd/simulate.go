@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var simulateKeep bool
+
+var simulateCmd = &cobra.Command{
+	Use:     "simulate <rig>",
+	GroupID: GroupDiag,
+	Short:   "Dry-run a sling end-to-end without spawning an agent",
+	Long: `Run every step of a polecat sling against a real rig, with a no-op agent.
+
+The simulation:
+  1. Checks Dolt health and connection capacity
+  2. Creates a throwaway work bead
+  3. Allocates a polecat and creates its worktree
+  4. Hooks the bead and stores attachment metadata
+  5. Flushes the working set and creates the polecat's Dolt branch
+  6. Renders the polecat role context
+  7. Transitions agent and bead state as a started session would
+     (no tmux session or agent process is launched)
+  8. Runs the done path: drops the Dolt branch and closes the bead
+     (a real done merges it; the simulated work never reaches main)
+
+Everything created, including the bead, is deleted afterwards, whether or
+not a step failed.
+Use it as a post-upgrade smoke test, or via 'gt doctor --rig <rig> --deep'.
+
+Exits non-zero if any step fails.
+
+Examples:
+  gt simulate gastown          # Full pipeline smoke test
+  gt simulate gastown --keep   # Leave the polecat for inspection`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runSimulate,
+}
+
+func init() {
+	simulateCmd.Flags().BoolVar(&simulateKeep, "keep", false, "Skip cleanup (leave polecat, bead, and branch for inspection)")
+	rootCmd.AddCommand(simulateCmd)
+}
+
+func runSimulate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return runSlingSimulation(os.Stdout, townRoot, args[0], simulateKeep)
+}
+
+// simStep is one stage of a sling simulation.
+type simStep struct {
+	name string
+	run  func() error
+}
+
+// simResult records the outcome of a simStep.
+type simResult struct {
+	name    string
+	err     error
+	elapsed time.Duration
+}
+
+// runSimSteps runs steps in order, stopping at the first failure, then runs
+// every cleanup step regardless. Cleanup failures are reported but do not
+// mask the original failure.
+func runSimSteps(w io.Writer, steps, cleanup []simStep) (results []simResult, failed bool) {
+	for _, s := range steps {
+		r := runSimStep(w, s)
+		results = append(results, r)
+		if r.err != nil {
+			failed = true
+			break
+		}
+	}
+	if len(cleanup) > 0 {
+		fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Cleanup:"))
+		for _, s := range cleanup {
+			r := runSimStep(w, s)
+			results = append(results, r)
+			if r.err != nil {
+				failed = true
+			}
+		}
+	}
+	return results, failed
+}
+
+func runSimStep(w io.Writer, s simStep) simResult {
+	start := time.Now()
+	err := s.run()
+	r := simResult{name: s.name, err: err, elapsed: time.Since(start)}
+	if err != nil {
+		fmt.Fprintf(w, "  %s %s: %v\n", style.Error.Render("✗"), s.name, err)
+	} else {
+		fmt.Fprintf(w, "  %s %s %s\n", style.Success.Render("✓"), s.name, style.Dim.Render(r.elapsed.Round(time.Millisecond).String()))
+	}
+	return r
+}
+
+// slingSimBackend performs the side effects of a sling simulation. It is
+// an interface so tests can check the step list and cleanup without a rig.
+type slingSimBackend interface {
+	CheckCapacity() error
+	CreateBead() (*beads.Issue, error)
+	SpawnPolecat(beadID string) (*SpawnedPolecatInfo, error)
+	HookBead(beadID string, info *SpawnedPolecatInfo) error
+	StoreMetadata(beadID string) error
+	CreateDoltBranch(info *SpawnedPolecatInfo) error
+	RenderRole(info *SpawnedPolecatInfo) error
+	StartAgent(info *SpawnedPolecatInfo) error
+	DeleteDoltBranch(info *SpawnedPolecatInfo)
+	CloseBead(beadID, reason string) error
+	DeleteBead(beadID string) error
+	RemovePolecat(info *SpawnedPolecatInfo) error
+}
+
+// runSlingSimulation performs a full sling → done cycle on rigName with a
+// no-op agent and reports each step. Returns an error if any step fails.
+func runSlingSimulation(w io.Writer, townRoot, rigName string, keep bool) error {
+	rigsConfig, err := rigsconfig.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	r, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+	if err != nil {
+		return fmt.Errorf("rig '%s' not found", rigName)
+	}
+
+	backend := &rigSimBackend{
+		townRoot:   townRoot,
+		rigName:    rigName,
+		polecatMgr: polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux()),
+		bd:         beads.New(r.Path),
+	}
+	return simulateSling(w, backend, rigName, keep)
+}
+
+// simulateSling runs the simulation steps against b, then cleans up unless
+// keep is set.
+func simulateSling(w io.Writer, b slingSimBackend, rigName string, keep bool) error {
+	var (
+		issue         *beads.Issue
+		info          *SpawnedPolecatInfo
+		branchCreated bool
+	)
+
+	steps := []simStep{
+		{"capacity check", b.CheckCapacity},
+		{"create work bead", func() error {
+			var err error
+			issue, err = b.CreateBead()
+			return err
+		}},
+		{"create polecat workspace", func() error {
+			var err error
+			info, err = b.SpawnPolecat(issue.ID)
+			return err
+		}},
+		{"hook bead", func() error {
+			return b.HookBead(issue.ID, info)
+		}},
+		{"store bead metadata", func() error {
+			return b.StoreMetadata(issue.ID)
+		}},
+		{"create Dolt branch", func() error {
+			if err := b.CreateDoltBranch(info); err != nil {
+				return err
+			}
+			branchCreated = info.DoltBranch != ""
+			return nil
+		}},
+		{"render role context", func() error {
+			return b.RenderRole(info)
+		}},
+		{"start no-op agent", func() error {
+			return b.StartAgent(info)
+		}},
+		// A real done merges the polecat's branch into main; the simulated
+		// work must not land there, so the branch is dropped instead.
+		{"done: drop Dolt branch", func() error {
+			if branchCreated && !keep {
+				b.DeleteDoltBranch(info)
+				branchCreated = false
+			}
+			return nil
+		}},
+		{"done: close bead", func() error {
+			return b.CloseBead(issue.ID, "gt simulate complete")
+		}},
+	}
+
+	var cleanup []simStep
+	if !keep {
+		cleanup = []simStep{
+			{"remove Dolt branch", func() error {
+				if branchCreated {
+					b.DeleteDoltBranch(info)
+				}
+				return nil
+			}},
+			{"remove polecat", func() error {
+				if info == nil {
+					return nil
+				}
+				return b.RemovePolecat(info)
+			}},
+			{"delete work bead", func() error {
+				if issue == nil {
+					return nil
+				}
+				return b.DeleteBead(issue.ID)
+			}},
+		}
+	}
+
+	fmt.Fprintf(w, "%s Simulating sling to %s\n\n", style.Bold.Render("▶"), rigName)
+	_, failed := runSimSteps(w, steps, cleanup)
+
+	if keep && info != nil {
+		fmt.Fprintf(w, "\n%s Kept polecat %s (bead %s) for inspection\n", style.Dim.Render("○"), info.AgentID(), issue.ID)
+	}
+	if failed {
+		return fmt.Errorf("simulation failed for rig %s", rigName)
+	}
+	fmt.Fprintf(w, "\n%s Sling pipeline healthy for %s\n", style.Bold.Render("✓"), rigName)
+	return nil
+}
+
+// rigSimBackend is the slingSimBackend for a real rig.
+type rigSimBackend struct {
+	townRoot   string
+	rigName    string
+	polecatMgr *polecat.Manager
+	bd         *beads.Beads
+}
+
+func (b *rigSimBackend) CheckCapacity() error {
+	if err := b.polecatMgr.CheckDoltHealth(); err != nil {
+		return err
+	}
+	return b.polecatMgr.CheckDoltServerCapacity()
+}
+
+func (b *rigSimBackend) CreateBead() (*beads.Issue, error) {
+	return b.bd.Create(beads.CreateOptions{
+		Title:       "gt simulate: sling smoke test",
+		Type:        "task",
+		Priority:    4,
+		Description: "Throwaway bead created by gt simulate. Safe to delete.",
+		Actor:       "gt-simulate",
+	})
+}
+
+func (b *rigSimBackend) SpawnPolecat(beadID string) (*SpawnedPolecatInfo, error) {
+	info, err := SpawnPolecatForSling(b.rigName, SlingSpawnOptions{Create: true, HookBead: beadID})
+	if err != nil {
+		return nil, err
+	}
+	return info, verifyWorktreeExists(info.ClonePath)
+}
+
+func (b *rigSimBackend) HookBead(beadID string, info *SpawnedPolecatInfo) error {
+	return hookBeadWithRetry(beadID, info.AgentID(), beads.ResolveHookDir(b.townRoot, beadID, info.ClonePath))
+}
+
+func (b *rigSimBackend) StoreMetadata(beadID string) error {
+	return storeFieldsInBead(beadID, beadFieldUpdates{Dispatcher: "gt-simulate"})
+}
+
+func (b *rigSimBackend) CreateDoltBranch(info *SpawnedPolecatInfo) error {
+	return info.CreateDoltBranch()
+}
+
+func (b *rigSimBackend) RenderRole(info *SpawnedPolecatInfo) error {
+	tmpl, err := templates.New()
+	if err != nil {
+		return err
+	}
+	out, err := tmpl.RenderRole("polecat", templates.RoleData{
+		Role:          "polecat",
+		RigName:       b.rigName,
+		TownRoot:      b.townRoot,
+		WorkDir:       info.ClonePath,
+		DefaultBranch: info.BaseBranch,
+		Polecat:       info.PolecatName,
+	})
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(out) == "" {
+		return fmt.Errorf("polecat role context rendered empty")
+	}
+	return nil
+}
+
+func (b *rigSimBackend) StartAgent(info *SpawnedPolecatInfo) error {
+	if err := b.polecatMgr.SetAgentStateWithRetry(info.PolecatName, "working"); err != nil {
+		return err
+	}
+	return b.polecatMgr.SetState(info.PolecatName, polecat.StateWorking)
+}
+
+func (b *rigSimBackend) DeleteDoltBranch(info *SpawnedPolecatInfo) {
+	doltserver.DeletePolecatBranch(b.townRoot, b.rigName, info.DoltBranch)
+}
+
+func (b *rigSimBackend) CloseBead(beadID, reason string) error {
+	return b.bd.CloseWithReason(reason, beadID)
+}
+
+// DeleteBead hard-deletes the throwaway bead so simulations do not pile up
+// closed beads in the rig's history.
+func (b *rigSimBackend) DeleteBead(beadID string) error {
+	_, err := b.bd.Run("delete", beadID, "--hard", "--force")
+	return err
+}
+
+func (b *rigSimBackend) RemovePolecat(info *SpawnedPolecatInfo) error {
+	return b.polecatMgr.Remove(info.PolecatName, true)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestRunSimSteps_StopsOnFailureAndCleansUp(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) simStep {
+		return simStep{name, func() error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	var out bytes.Buffer
+	results, failed := runSimSteps(&out,
+		[]simStep{step("a", nil), step("b", errors.New("boom")), step("c", nil)},
+		[]simStep{step("cleanup-1", nil), step("cleanup-2", nil)},
+	)
+
+	if !failed {
+		t.Error("expected failure")
+	}
+	want := []string{"a", "b", "cleanup-1", "cleanup-2"}
+	if strings.Join(ran, ",") != strings.Join(want, ",") {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if len(results) != 4 || results[1].err == nil {
+		t.Errorf("results = %+v", results)
+	}
+	if !strings.Contains(out.String(), "b: boom") {
+		t.Errorf("output missing failure: %q", out.String())
+	}
+}
+
+func TestRunSimSteps_CleanupFailureFailsRun(t *testing.T) {
+	var out bytes.Buffer
+	_, failed := runSimSteps(&out,
+		[]simStep{{"ok", func() error { return nil }}},
+		[]simStep{{"cleanup", func() error { return errors.New("stuck") }}},
+	)
+	if !failed {
+		t.Error("cleanup failure should fail the simulation")
+	}
+}
+
+func TestRunSimSteps_AllPass(t *testing.T) {
+	var out bytes.Buffer
+	results, failed := runSimSteps(&out,
+		[]simStep{{"one", func() error { return nil }}, {"two", func() error { return nil }}},
+		nil,
+	)
+	if failed || len(results) != 2 {
+		t.Errorf("failed=%v results=%d", failed, len(results))
+	}
+	if strings.Contains(out.String(), "Cleanup:") {
+		t.Error("no cleanup header expected without cleanup steps")
+	}
+}
+
+// fakeSimBackend records the calls simulateSling makes. failAt names a
+// call that returns an error.
+type fakeSimBackend struct {
+	calls  []string
+	failAt string
+}
+
+func (f *fakeSimBackend) call(name string) error {
+	f.calls = append(f.calls, name)
+	if name == f.failAt {
+		return errors.New(name + " failed")
+	}
+	return nil
+}
+
+func (f *fakeSimBackend) CheckCapacity() error { return f.call("capacity") }
+func (f *fakeSimBackend) CreateBead() (*beads.Issue, error) {
+	return &beads.Issue{ID: "gt-sim1"}, f.call("create bead")
+}
+func (f *fakeSimBackend) SpawnPolecat(beadID string) (*SpawnedPolecatInfo, error) {
+	return &SpawnedPolecatInfo{RigName: "gastown", PolecatName: "Toast", DoltBranch: "polecat-toast"}, f.call("spawn " + beadID)
+}
+func (f *fakeSimBackend) HookBead(beadID string, _ *SpawnedPolecatInfo) error {
+	return f.call("hook " + beadID)
+}
+func (f *fakeSimBackend) StoreMetadata(beadID string) error { return f.call("metadata " + beadID) }
+func (f *fakeSimBackend) CreateDoltBranch(info *SpawnedPolecatInfo) error {
+	return f.call("create branch " + info.DoltBranch)
+}
+func (f *fakeSimBackend) RenderRole(*SpawnedPolecatInfo) error { return f.call("render") }
+func (f *fakeSimBackend) StartAgent(*SpawnedPolecatInfo) error { return f.call("start") }
+func (f *fakeSimBackend) DeleteDoltBranch(info *SpawnedPolecatInfo) {
+	_ = f.call("delete branch " + info.DoltBranch)
+}
+func (f *fakeSimBackend) CloseBead(beadID, _ string) error { return f.call("close " + beadID) }
+func (f *fakeSimBackend) DeleteBead(beadID string) error   { return f.call("delete bead " + beadID) }
+func (f *fakeSimBackend) RemovePolecat(info *SpawnedPolecatInfo) error {
+	return f.call("remove " + info.PolecatName)
+}
+
+func TestSimulateSling_DropsBranchAndDeletesBead(t *testing.T) {
+	f := &fakeSimBackend{}
+	if err := simulateSling(&bytes.Buffer{}, f, "gastown", false); err != nil {
+		t.Fatalf("simulateSling: %v", err)
+	}
+	want := []string{
+		"capacity", "create bead", "spawn gt-sim1", "hook gt-sim1", "metadata gt-sim1",
+		"create branch polecat-toast", "render", "start",
+		"delete branch polecat-toast", "close gt-sim1",
+		"remove Toast", "delete bead gt-sim1",
+	}
+	if strings.Join(f.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls =\n  %v\nwant\n  %v", f.calls, want)
+	}
+}
+
+func TestSimulateSling_FailureCleansUp(t *testing.T) {
+	f := &fakeSimBackend{failAt: "render"}
+	if err := simulateSling(&bytes.Buffer{}, f, "gastown", false); err == nil {
+		t.Fatal("expected simulation failure")
+	}
+	want := []string{
+		"capacity", "create bead", "spawn gt-sim1", "hook gt-sim1", "metadata gt-sim1",
+		"create branch polecat-toast", "render",
+		"delete branch polecat-toast", "remove Toast", "delete bead gt-sim1",
+	}
+	if strings.Join(f.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls =\n  %v\nwant\n  %v", f.calls, want)
+	}
+}
+
+func TestSimulateSling_KeepSkipsCleanup(t *testing.T) {
+	f := &fakeSimBackend{}
+	var out bytes.Buffer
+	if err := simulateSling(&out, f, "gastown", true); err != nil {
+		t.Fatalf("simulateSling: %v", err)
+	}
+	for _, c := range f.calls {
+		if strings.HasPrefix(c, "delete") || strings.HasPrefix(c, "remove") {
+			t.Errorf("--keep ran %q", c)
+		}
+	}
+	if !strings.Contains(out.String(), "Kept polecat gastown/polecats/Toast (bead gt-sim1)") {
+		t.Errorf("output missing kept notice: %q", out.String())
+	}
+}
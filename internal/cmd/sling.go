@@ -10,9 +10,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -97,7 +97,17 @@ Batch Slinging:
 
   When multiple beads are provided with a rig target, each bead gets its own
   polecat. This parallelizes work dispatch without running gt sling N times.
-  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.`,
+  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.
+  Batch slings run pre-flight doctor checks (routes, beads database, Dolt
+  server reachability) first; --skip-preflight bypasses them.
+
+Idempotency (for scripts that retry):
+  gt sling gt-abc gastown --idempotent              # Key derived from the arguments
+  gt sling gt-abc gastown --idempotency-key deploy-42
+
+  A sling repeated with the same key within 10 minutes does nothing and
+  reports the original result. A sling that fails doesn't keep its key,
+  so a retry after a failure runs again.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSling,
 }
@@ -124,6 +134,13 @@ var (
 	slingNoBoot        bool   // --no-boot: skip wakeRigAgents (avoid witness/refinery boot and lock contention)
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
+
+	slingIdempotencyKey string // --idempotency-key: skip repeats of this sling within the TTL
+	slingIdempotent     bool   // --idempotent: derive the idempotency key from the arguments
+
+	// slingHooked records "bead → agent" for each bead this sling hooked,
+	// as the result remembered under an idempotency key.
+	slingHooked []string
 )
 
 func init() {
@@ -148,11 +165,13 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingNoBoot, "no-boot", false, "Skip rig boot after polecat spawn (avoids witness/refinery lock contention)")
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
+	slingCmd.Flags().StringVar(&slingIdempotencyKey, "idempotency-key", "", "Skip this sling if the same key was used in the last 10 minutes")
+	slingCmd.Flags().BoolVar(&slingIdempotent, "idempotent", false, "Skip this sling if the same beads and target were slung in the last 10 minutes")
 
 	rootCmd.AddCommand(slingCmd)
 }
 
-func runSling(cmd *cobra.Command, args []string) error {
+func runSling(cmd *cobra.Command, args []string) (err error) {
 	// Polecats cannot sling - check early before writing anything
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
 		return fmt.Errorf("polecats cannot sling (use gt done for handoff)")
@@ -225,12 +244,32 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Idempotency: a retried script repeating this sling within the TTL is
+	// told the original result instead of slinging the bead again.
+	slingHooked = nil
+	if !slingDryRun {
+		keyParts := append(append([]string{}, args...), "on="+slingOnTarget)
+		op, skip, beginErr := beginIdempotent(townRoot, "sling", slingIdempotencyKey, slingIdempotent, keyParts...)
+		if beginErr != nil {
+			return beginErr
+		}
+		if skip {
+			return nil
+		}
+		defer func() { op.finish(err, strings.Join(slingHooked, ", ")) }()
+	}
+
 	// Batch mode detection: multiple beads with rig target
 	// Pattern: gt sling gt-abc gt-def gt-ghi gastown
 	// When len(args) > 2 and last arg is a rig, sling each bead to its own polecat
 	if len(args) > 2 {
 		lastArg := args[len(args)-1]
 		if rigName, isRig := IsRigName(lastArg); isRig {
+			if !slingDryRun {
+				if err := runPreflight(cmd, townRoot); err != nil {
+					return err
+				}
+			}
 			return runBatchSling(args[:len(args)-1], rigName, townBeadsDir)
 		}
 	}
@@ -323,6 +362,14 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Cross-rig dependencies: don't start work an open bead in another rig
+	// still blocks. Checked before resolveTarget for the same reason as above.
+	if !force {
+		if err := checkCrossRigBlockers(townRoot, beadID); err != nil {
+			return err
+		}
+	}
+
 	// Resolve target agent using shared dispatch logic
 	var target string
 	if len(args) > 1 {
@@ -351,6 +398,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 	newPolecatInfo := resolved.NewPolecatInfo
 	isSelfSling := resolved.IsSelfSling
 
+	// From here on, a failure must not leave a half-set-up polecat behind.
+	saga := newSlingSaga(newPolecatInfo)
+
 	// Inject base_branch var for formula instantiation (non-main only; formula default handles main)
 	if newPolecatInfo != nil && newPolecatInfo.BaseBranch != "" && newPolecatInfo.BaseBranch != "main" {
 		slingVars = append(slingVars, fmt.Sprintf("base_branch=%s", newPolecatInfo.BaseBranch))
@@ -361,7 +411,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// Skip for self-sling (user knows what they're doing) and --force overrides.
 	if strings.Contains(targetAgent, "/polecats/") && !force && !isSelfSling {
 		if err := checkCrossRigGuard(beadID, targetAgent, townRoot); err != nil {
-			return err
+			return saga.fail("cross-rig check", err)
 		}
 	}
 
@@ -413,7 +463,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		// Unhook the bead from old owner (set status back to open)
 		unhookCmd := exec.Command("bd", "update", beadID, "--status=open", "--assignee=")
 		unhookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, "")
-		if err := unhookCmd.Run(); err != nil {
+		if err := proc.RunCmd(unhookCmd); err != nil {
 			fmt.Printf("%s Could not unhook bead from old owner: %v\n", style.Dim.Render("Warning:"), err)
 		}
 	}
@@ -493,20 +543,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 		result, err := InstantiateFormulaOnBead(formulaName, beadID, info.Title, hookWorkDir, townRoot, false, slingVars)
 		if err != nil {
-			// If we spawned a fresh polecat (rig target), rollback the partial artifacts.
-			// Otherwise, a wisp creation failure (e.g., missing required vars) leaves an orphaned polecat.
-			if newPolecatInfo != nil {
-				fmt.Printf("%s Formula instantiation failed, rolling back spawned polecat %s...\n",
-					style.Warning.Render("⚠"), newPolecatInfo.PolecatName)
-				rollbackSlingArtifactsFn(newPolecatInfo, beadID, hookWorkDir)
-				// Under --force, if this bead was previously pinned, rollback's unhook would otherwise
-				// clear the pinned state. Restore pinned state so we don't lose the original hook.
-				if force && originalStatus == "pinned" {
-					restorePinnedBead(townRoot, beadID, originalAssignee)
-				}
-			}
-			return fmt.Errorf("instantiating formula %s: %w", formulaName, err)
+			// A wisp creation failure (e.g., missing required vars) would otherwise
+			// leave an orphaned polecat.
+			return saga.fail("formula instantiation", fmt.Errorf("instantiating formula %s: %w", formulaName, err))
 		}
+		saga.didWisp(townRoot, result.WispRootID, hookWorkDir)
 
 		fmt.Printf("%s Formula wisp created: %s\n", style.Bold.Render("✓"), result.WispRootID)
 		fmt.Printf("%s Formula bonded to %s\n", style.Bold.Render("✓"), beadID)
@@ -533,21 +574,28 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 	if err := hookBeadWithRetry(beadID, targetAgent, hookDir); err != nil {
-		return err
+		return saga.fail("hook", err)
 	}
+	saga.didHook(townRoot, beadID, hookWorkDir, originalStatus, originalAssignee)
 
 	fmt.Printf("%s Work attached to hook (status=hooked)\n", style.Bold.Render("✓"))
+	slingHooked = append(slingHooked, beadID+" → "+targetAgent)
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	payload := events.SlingPayload(beadID, targetAgent)
+	if attachedMoleculeID != "" {
+		payload["formula"] = formulaName
+		payload["molecule"] = attachedMoleculeID
+	}
+	_ = events.LogFeed(events.TypeSling, actor, payload)
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
 	// error when polecat redirect setup fails (GH #gt-mzyk5: agent bead created in rig beads
 	// but updateAgentHookBead looks in polecat's local beads if redirect is missing).
-	if !hookSetAtomically {
-		updateAgentHookBead(targetAgent, beadID, hookWorkDir, townBeadsDir)
+	if !hookSetAtomically && updateAgentHookBead(targetAgent, beadID, hookWorkDir, townBeadsDir) {
+		saga.didAgentHook(townRoot, targetAgent, hookWorkDir)
 	}
 
 	// Store all attachment fields in a single read-modify-write cycle.
@@ -563,6 +611,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
 	} else {
+		saga.didAttach(townRoot, beadID, hookWorkDir)
 		if slingArgs != "" {
 			fmt.Printf("%s Args stored in bead (durable)\n", style.Bold.Render("✓"))
 		}
@@ -586,9 +635,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// from HEAD — ensuring the polecat's branch includes all writes.
 	if newPolecatInfo != nil && newPolecatInfo.DoltBranch != "" {
 		if err := newPolecatInfo.CreateDoltBranch(); err != nil {
-			rollbackSlingArtifactsFn(newPolecatInfo, beadID, hookWorkDir)
-			return fmt.Errorf("creating Dolt branch: %w", err)
+			return saga.fail("Dolt branch creation", fmt.Errorf("creating Dolt branch: %w", err))
 		}
+		saga.didDoltBranch(townRoot, newPolecatInfo)
 	}
 
 	// Start polecat session now that attached_molecule is set.
@@ -597,11 +646,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if freshlySpawned {
 		pane, err := newPolecatInfo.StartSession()
 		if err != nil {
-			// Rollback: session failed, clean up zombie artifacts (worktree, hooked bead).
 			// Without rollback, next sling attempt fails with "bead already hooked" (gt-jn40ft).
-			fmt.Printf("%s Session failed, rolling back spawned polecat %s...\n", style.Warning.Render("⚠"), newPolecatInfo.PolecatName)
-			rollbackSlingArtifactsFn(newPolecatInfo, beadID, hookWorkDir)
-			return fmt.Errorf("starting polecat session: %w", err)
+			saga.didStartSession(newPolecatInfo)
+			return saga.fail("session start", fmt.Errorf("starting polecat session: %w", err))
 		}
 		targetPane = pane
 	}
@@ -669,54 +716,3 @@ func checkCrossRigGuard(beadID, targetAgent, townRoot string) error {
 
 	return nil
 }
-
-// rollbackSlingArtifactsFn is a seam for tests. Production uses rollbackSlingArtifacts.
-var rollbackSlingArtifactsFn = rollbackSlingArtifacts
-
-func restorePinnedBead(townRoot, beadID, assignee string) {
-	if townRoot == "" || beadID == "" {
-		return
-	}
-	dir := beads.ResolveHookDir(townRoot, beadID, "")
-	cmd := exec.Command("bd", "update", beadID, "--status=pinned", "--assignee="+assignee)
-	if dir != "" {
-		cmd.Dir = dir
-	}
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("  %s Could not restore pinned state for bead %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
-	} else {
-		fmt.Printf("  %s Restored pinned state for bead %s\n", style.Dim.Render("○"), beadID)
-	}
-}
-
-// rollbackSlingArtifacts cleans up artifacts left by a partial sling when session start fails.
-// This prevents zombie polecats that block subsequent sling attempts with "bead already hooked".
-// Cleanup is best-effort: each step logs warnings but continues to clean as much as possible.
-func rollbackSlingArtifacts(spawnInfo *SpawnedPolecatInfo, beadID, hookWorkDir string) {
-	townRoot, err := workspace.FindFromCwdOrError()
-
-	// 1. Unhook the bead (set status back to open so it can be re-slung).
-	// Some failure modes happen before any bead is hooked (e.g., wisp creation fails).
-	if beadID != "" {
-		if err != nil {
-			fmt.Printf("  %s Could not find workspace to unhook bead %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
-		} else {
-			unhookDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-			unhookCmd := exec.Command("bd", "update", beadID, "--status=open", "--assignee=")
-			unhookCmd.Dir = unhookDir
-			if err := unhookCmd.Run(); err != nil {
-				fmt.Printf("  %s Could not unhook bead %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
-			} else {
-				fmt.Printf("  %s Unhooked bead %s\n", style.Dim.Render("○"), beadID)
-			}
-		}
-	}
-
-	// 2. Clean up Dolt branch if it was created
-	if err == nil && spawnInfo.DoltBranch != "" && townRoot != "" {
-		doltserver.DeletePolecatBranch(townRoot, spawnInfo.RigName, spawnInfo.DoltBranch)
-	}
-
-	// 3. Clean up the spawned polecat (worktree, agent bead, etc.)
-	cleanupSpawnedPolecat(spawnInfo, spawnInfo.RigName)
-}
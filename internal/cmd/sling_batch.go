@@ -6,14 +6,8 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
-	"github.com/steveyegge/gastown/internal/git"
-	"github.com/steveyegge/gastown/internal/polecat"
-	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
-	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // runBatchSling handles slinging multiple beads to a rig.
@@ -119,6 +113,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		targetAgent := spawnInfo.AgentID()
 		hookWorkDir := spawnInfo.ClonePath
+		saga := newSlingSaga(spawnInfo)
 
 		// Auto-convoy: check if issue is already tracked
 		if !slingNoConvoy {
@@ -166,27 +161,37 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 				fmt.Printf("  %s Formula %s applied\n", style.Bold.Render("✓"), formulaName)
 				beadToHook = result.BeadToHook
 				attachedMoleculeID = result.WispRootID
+				saga.didWisp(townRoot, result.WispRootID, hookWorkDir)
 			}
 		}
 
 		// Hook the bead (or wisp compound if formula was applied) with retry
 		hookDir := beads.ResolveHookDir(townRoot, beadToHook, hookWorkDir)
 		if err := hookBeadWithRetry(beadToHook, targetAgent, hookDir); err != nil {
-			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "hook failed"})
 			fmt.Printf("  %s Failed to hook bead: %v\n", style.Dim.Render("✗"), err)
 			// Clean up orphaned polecat to avoid leaving spawned-but-unhookable polecats
-			cleanupSpawnedPolecat(spawnInfo, rigName)
+			_ = saga.fail("hook", err)
+			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "hook failed"})
 			continue
 		}
+		saga.didHook(townRoot, beadToHook, hookWorkDir, info.Status, info.Assignee)
 
 		fmt.Printf("  %s Work attached to %s\n", style.Bold.Render("✓"), spawnInfo.PolecatName)
+		slingHooked = append(slingHooked, beadID+" → "+targetAgent)
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		payload := events.SlingPayload(beadToHook, targetAgent)
+		if attachedMoleculeID != "" {
+			payload["formula"] = formulaName
+			payload["molecule"] = attachedMoleculeID
+		}
+		_ = events.LogFeed(events.TypeSling, actor, payload)
 
 		// Update agent bead state
-		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
+		if updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir) {
+			saga.didAgentHook(townRoot, targetAgent, hookWorkDir)
+		}
 
 		// Store all attachment fields in a single read-modify-write cycle.
 		// This eliminates the race condition where sequential independent updates
@@ -200,6 +205,8 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
 		if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
 			fmt.Printf("  %s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
+		} else {
+			saga.didAttach(townRoot, beadToHook, hookWorkDir)
 		}
 
 		// Create Dolt branch AFTER all sling writes are complete.
@@ -208,10 +215,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		if spawnInfo.DoltBranch != "" {
 			if err := spawnInfo.CreateDoltBranch(); err != nil {
 				fmt.Printf("  %s Could not create Dolt branch: %v, cleaning up...\n", style.Dim.Render("✗"), err)
-				rollbackSlingArtifactsFn(spawnInfo, beadToHook, hookWorkDir)
-				results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false})
+				_ = saga.fail("Dolt branch creation", err)
+				results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "Dolt branch creation failed"})
 				continue
 			}
+			saga.didDoltBranch(townRoot, spawnInfo)
 		}
 
 		// Start polecat session now that molecule/bead is attached.
@@ -219,8 +227,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		pane, err := spawnInfo.StartSession()
 		if err != nil {
 			fmt.Printf("  %s Could not start session: %v, cleaning up partial state...\n", style.Dim.Render("✗"), err)
-			rollbackSlingArtifactsFn(spawnInfo, beadToHook, hookWorkDir)
-			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false})
+			saga.didStartSession(spawnInfo)
+			_ = saga.fail("session start", err)
+			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "session start failed"})
 			continue
 		} else {
 			fmt.Printf("  %s Session started for %s\n", style.Bold.Render("▶"), spawnInfo.PolecatName)
@@ -263,33 +272,3 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 	return nil
 }
-
-// cleanupSpawnedPolecat removes a polecat that was spawned but whose hook failed,
-// preventing orphaned polecats from accumulating.
-func cleanupSpawnedPolecat(spawnInfo *SpawnedPolecatInfo, rigName string) {
-	townRoot, err := workspace.FindFromCwdOrError()
-	if err != nil {
-		return
-	}
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
-	if err != nil {
-		return
-	}
-	g := git.NewGit(townRoot)
-	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
-	r, err := rigMgr.GetRig(rigName)
-	if err != nil {
-		return
-	}
-	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
-	polecatMgr := polecat.NewManager(r, polecatGit, t)
-	if err := polecatMgr.Remove(spawnInfo.PolecatName, true); err != nil {
-		fmt.Printf("  %s Could not clean up orphaned polecat %s: %v\n",
-			style.Dim.Render("Warning:"), spawnInfo.PolecatName, err)
-	} else {
-		fmt.Printf("  %s Cleaned up orphaned polecat %s\n",
-			style.Dim.Render("○"), spawnInfo.PolecatName)
-	}
-}
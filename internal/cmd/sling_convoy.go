@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -35,7 +36,7 @@ func isTrackedByConvoy(beadID string) string {
 	depCmd := exec.Command("bd", "dep", "list", beadID, "--direction=up", "--type=tracks", "--json")
 	depCmd.Dir = townRoot
 
-	out, err := depCmd.Output()
+	out, err := proc.Output(depCmd)
 	if err == nil {
 		var trackers []struct {
 			ID        string `json:"id"`
@@ -68,7 +69,7 @@ func findConvoyByDescription(townRoot, beadID string) string {
 	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
 	listCmd.Dir = townBeads
 
-	out, err := listCmd.Output()
+	out, err := proc.Output(listCmd)
 	if err != nil {
 		return ""
 	}
@@ -108,7 +109,7 @@ func convoyTracksBead(beadsDir, convoyID, beadID string) bool {
 	depCmd := exec.Command("bd", "dep", "list", convoyID, "--direction=down", "--type=tracks", "--json")
 	depCmd.Dir = beadsDir
 
-	out, err := depCmd.Output()
+	out, err := proc.Output(depCmd)
 	if err != nil {
 		return false
 	}
@@ -170,7 +171,7 @@ func getConvoyInfoForIssue(issueID string) *ConvoyInfo {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return &ConvoyInfo{ID: convoyID} // Return basic info even if details fail
 	}
 
@@ -244,7 +245,7 @@ func createAutoConvoy(beadID, beadTitle string, owned bool, mergeStrategy string
 	createCmd.Dir = townBeads
 	createCmd.Stderr = os.Stderr
 
-	if err := createCmd.Run(); err != nil {
+	if err := proc.RunCmd(createCmd); err != nil {
 		return "", fmt.Errorf("creating convoy: %w", err)
 	}
 
@@ -256,11 +257,11 @@ func createAutoConvoy(beadID, beadTitle string, owned bool, mergeStrategy string
 	depCmd.Dir = townRoot
 	depCmd.Stderr = os.Stderr
 
-	if err := depCmd.Run(); err != nil {
+	if err := proc.RunCmd(depCmd); err != nil {
 		// Tracking failed — delete the orphan convoy to prevent accumulation
 		delCmd := exec.Command("bd", "close", convoyID, "-r", "tracking dep failed")
 		delCmd.Dir = townRoot
-		_ = delCmd.Run()
+		_ = proc.RunCmd(delCmd)
 		return "", fmt.Errorf("adding tracking relation for %s: %w", beadID, err)
 	}
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -63,7 +64,7 @@ func verifyFormulaExists(formulaName string, workDir string) error {
 	if workDir != "" {
 		cmd.Dir = workDir
 	}
-	if out, err := cmd.Output(); err == nil && len(out) > 0 {
+	if out, err := proc.Output(cmd); err == nil && len(out) > 0 {
 		return nil
 	}
 
@@ -72,7 +73,7 @@ func verifyFormulaExists(formulaName string, workDir string) error {
 	if workDir != "" {
 		cmd.Dir = workDir
 	}
-	if out, err := cmd.Output(); err == nil && len(out) > 0 {
+	if out, err := proc.Output(cmd); err == nil && len(out) > 0 {
 		return nil
 	}
 
@@ -131,13 +132,8 @@ func runSlingFormula(args []string) error {
 
 	fmt.Printf("%s Slinging formula %s to %s...\n", style.Bold.Render("🎯"), formulaName, targetAgent)
 
-	rollbackSpawned := func(beadID string) {
-		if resolved.NewPolecatInfo == nil {
-			return
-		}
-		fmt.Printf("%s Rolling back spawned polecat %s...\n", style.Warning.Render("⚠"), resolved.NewPolecatInfo.PolecatName)
-		rollbackSlingArtifactsFn(resolved.NewPolecatInfo, beadID, formulaWorkDir)
-	}
+	// From here on, a failure must not leave a half-set-up polecat behind.
+	saga := newSlingSaga(resolved.NewPolecatInfo)
 
 	if slingDryRun {
 		fmt.Printf("Would cook formula: %s\n", formulaName)
@@ -158,15 +154,18 @@ func runSlingFormula(args []string) error {
 		formulaWorkDir = townRoot
 	}
 
+	if err := checkFormulaVars(formulaName, formulaWorkDir, slingVars); err != nil {
+		return saga.fail("formula check", err)
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
 	cookArgs := []string{"cook", formulaName}
 	cookCmd := exec.Command("bd", cookArgs...)
 	cookCmd.Dir = formulaWorkDir
 	cookCmd.Stderr = os.Stderr
-	if err := cookCmd.Run(); err != nil {
-		rollbackSpawned("")
-		return fmt.Errorf("cooking formula: %w", err)
+	if err := proc.RunCmd(cookCmd); err != nil {
+		return saga.fail("formula cook", fmt.Errorf("cooking formula: %w", err))
 	}
 
 	// Step 2: Create wisp instance (ephemeral)
@@ -180,18 +179,17 @@ func runSlingFormula(args []string) error {
 	wispCmd := exec.Command("bd", wispArgs...)
 	wispCmd.Dir = formulaWorkDir
 	wispCmd.Stderr = os.Stderr // Show wisp errors to user
-	wispOut, err := wispCmd.Output()
+	wispOut, err := proc.Output(wispCmd)
 	if err != nil {
-		rollbackSpawned("")
-		return fmt.Errorf("creating wisp: %w", err)
+		return saga.fail("wisp creation", fmt.Errorf("creating wisp: %w", err))
 	}
 
 	// Parse wisp output to get the root ID
 	wispRootID, err := parseWispIDFromJSON(wispOut)
 	if err != nil {
-		rollbackSpawned("")
-		return fmt.Errorf("parsing wisp output: %w", err)
+		return saga.fail("wisp creation", fmt.Errorf("parsing wisp output: %w", err))
 	}
+	saga.didWisp(townRoot, wispRootID, formulaWorkDir)
 
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 
@@ -199,19 +197,24 @@ func runSlingFormula(args []string) error {
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookDir := beads.ResolveHookDir(townRoot, wispRootID, "")
 	if err := hookBeadWithRetry(wispRootID, targetAgent, hookDir); err != nil {
-		return err
+		return saga.fail("hook", err)
 	}
+	saga.didHook(townRoot, wispRootID, "", "open", "")
 	fmt.Printf("%s Attached to hook (status=hooked)\n", style.Bold.Render("✓"))
+	slingHooked = append(slingHooked, wispRootID+" → "+targetAgent)
 
 	// Log sling event to activity feed (formula slinging)
 	actor := detectActor()
 	payload := events.SlingPayload(wispRootID, targetAgent)
 	payload["formula"] = formulaName
+	payload["molecule"] = wispRootID
 	_ = events.LogFeed(events.TypeSling, actor, payload)
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Note: formula slinging uses town root as workDir (no polecat-specific path)
-	updateAgentHookBead(targetAgent, wispRootID, "", townBeadsDir)
+	if updateAgentHookBead(targetAgent, wispRootID, "", townBeadsDir) {
+		saga.didAgentHook(townRoot, targetAgent, "")
+	}
 
 	// Store all attachment fields in a single read-modify-write cycle.
 	// NOTE: For standalone formula sling, the wisp IS the work - do NOT store
@@ -243,10 +246,9 @@ func runSlingFormula(args []string) error {
 	// from HEAD — ensuring the polecat's branch includes all writes.
 	if resolved.NewPolecatInfo != nil && resolved.NewPolecatInfo.DoltBranch != "" {
 		if err := resolved.NewPolecatInfo.CreateDoltBranch(); err != nil {
-			// Rollback: unhook wisp, delete Dolt branch, clean up polecat worktree/agent bead
-			rollbackSlingArtifactsFn(resolved.NewPolecatInfo, wispRootID, "")
-			return fmt.Errorf("creating Dolt branch: %w", err)
+			return saga.fail("Dolt branch creation", fmt.Errorf("creating Dolt branch: %w", err))
 		}
+		saga.didDoltBranch(townRoot, resolved.NewPolecatInfo)
 	}
 
 	// Start spawned polecat session now that hook is set.
@@ -254,9 +256,8 @@ func runSlingFormula(args []string) error {
 	if resolved.NewPolecatInfo != nil {
 		pane, err := resolved.NewPolecatInfo.StartSession()
 		if err != nil {
-			// Rollback: unhook wisp, delete Dolt branch, clean up polecat worktree/agent bead
-			rollbackSlingArtifactsFn(resolved.NewPolecatInfo, wispRootID, "")
-			return fmt.Errorf("starting polecat session: %w", err)
+			saga.didStartSession(resolved.NewPolecatInfo)
+			return saga.fail("session start", fmt.Errorf("starting polecat session: %w", err))
 		}
 		targetPane = pane
 	}
@@ -17,6 +17,8 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -44,30 +46,17 @@ func resolveBeadDir(beadID string) string {
 
 // resolveBeadDirFromRigsJSON looks up the rig directory from rigs.json using prefix.
 func resolveBeadDirFromRigsJSON(townRoot, prefix string) string {
-	rigsPath := townRoot + "/mayor/rigs.json"
-	data, err := os.ReadFile(rigsPath)
+	rigs, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		return ""
 	}
-	var rigsFile struct {
-		Rigs map[string]struct {
-			Beads struct {
-				Prefix string `json:"prefix"`
-			} `json:"beads"`
-		} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &rigsFile); err != nil {
-		return ""
-	}
 	// prefix includes trailing hyphen (e.g., "bd-"), rigs.json stores without (e.g., "bd")
-	trimmedPrefix := strings.TrimSuffix(prefix, "-")
-	for rigName, rigConfig := range rigsFile.Rigs {
-		if rigConfig.Beads.Prefix == trimmedPrefix {
-			// Return mayor/rig path within the rig (where .beads/ lives)
-			return townRoot + "/" + rigName + "/mayor/rig"
-		}
+	rigName := rigsconfig.RigForPrefix(rigs, prefix)
+	if rigName == "" {
+		return ""
 	}
-	return ""
+	// Return mayor/rig path within the rig (where .beads/ lives)
+	return townRoot + "/" + rigName + "/mayor/rig"
 }
 
 // beadInfo holds status and assignee for a bead.
@@ -86,7 +75,7 @@ type beadInfo struct {
 func verifyBeadExists(beadID string) error {
 	cmd := exec.Command("bd", "show", beadID, "--json", "--allow-stale")
 	cmd.Dir = resolveBeadDir(beadID)
-	out, err := cmd.Output()
+	out, err := proc.Output(cmd)
 	if err != nil {
 		return fmt.Errorf("bead '%s' not found (bd show failed)", beadID)
 	}
@@ -104,7 +93,7 @@ func verifyBeadExists(beadID string) error {
 func getBeadInfo(beadID string) (*beadInfo, error) {
 	cmd := exec.Command("bd", "show", beadID, "--json", "--allow-stale")
 	cmd.Dir = resolveBeadDir(beadID)
-	out, err := cmd.Output()
+	out, err := proc.Output(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("bead '%s' not found", beadID)
 	}
@@ -147,7 +136,7 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 		// Read the bead once
 		showCmd := exec.Command("bd", "show", beadID, "--json", "--allow-stale")
 		showCmd.Dir = resolveBeadDir(beadID)
-		out, err := showCmd.Output()
+		out, err := proc.Output(showCmd)
 		if err != nil {
 			return fmt.Errorf("fetching bead: %w", err)
 		}
@@ -198,7 +187,7 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
 	updateCmd.Dir = resolveBeadDir(beadID)
 	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := proc.RunCmd(updateCmd); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
@@ -404,7 +393,8 @@ func agentIDToBeadID(agentID, townRoot string) string {
 // For cross-database scenarios (agent in rig db, hook bead in town db),
 // the slot set may fail - this is handled gracefully with a warning.
 // The work is still correctly attached via `bd update <bead> --assignee=<agent>`.
-func updateAgentHookBead(agentID, beadID, workDir, townBeadsDir string) {
+// Reports whether the hook was set.
+func updateAgentHookBead(agentID, beadID, workDir, townBeadsDir string) bool {
 	_ = townBeadsDir // Not used - BEADS_DIR breaks redirect mechanism
 
 	// Determine the directory to run bd commands from:
@@ -415,7 +405,7 @@ func updateAgentHookBead(agentID, beadID, workDir, townBeadsDir string) {
 	if err != nil {
 		// Not in a Gas Town workspace - can't update agent bead
 		fmt.Fprintf(os.Stderr, "Warning: couldn't find town root to update agent hook: %v\n", err)
-		return
+		return false
 	}
 	if bdWorkDir == "" {
 		bdWorkDir = townRoot
@@ -429,7 +419,7 @@ func updateAgentHookBead(agentID, beadID, workDir, townBeadsDir string) {
 	//   greenplace/witness -> gt-greenplace-witness
 	agentBeadID := agentIDToBeadID(agentID, townRoot)
 	if agentBeadID == "" {
-		return
+		return false
 	}
 
 	// Resolve the correct working directory for the agent bead.
@@ -450,8 +440,9 @@ func updateAgentHookBead(agentID, beadID, workDir, townBeadsDir string) {
 		if strings.Contains(agentBeadID, "-dog-") {
 			fmt.Fprintf(os.Stderr, "  (Old dog? Recreate with: gt dog rm <name> && gt dog add <name>)\n")
 		}
-		return
+		return false
 	}
+	return true
 }
 
 // wakeRigAgents wakes the witness for a rig after polecat dispatch.
@@ -522,6 +513,45 @@ func isPolecatTarget(target string) bool {
 	return len(parts) >= 3 && parts[1] == "polecats"
 }
 
+// checkFormulaVars returns an error listing the formula's required variables
+// that vars (name=value, as passed to bd mol wisp --var) leaves unset, so the
+// molecule is never poured with {{var}} placeholders in its steps. The formula
+// is looked up in workDir's .beads/formulas first, then the usual search
+// paths; formulas that can't be found or parsed here are left to bd.
+func checkFormulaVars(formulaName, workDir string, vars []string) error {
+	path := ""
+	for _, ext := range []string{".formula.toml", ".formula.json"} {
+		candidate := filepath.Join(workDir, ".beads", "formulas", formulaName+ext)
+		if _, err := os.Stat(candidate); err == nil {
+			path = candidate
+			break
+		}
+	}
+	if path == "" {
+		found, err := findFormulaFile(formulaName)
+		if err != nil {
+			return nil
+		}
+		path = found
+	}
+	f, err := parseFormulaFile(path)
+	if err != nil {
+		return nil
+	}
+
+	provided := make(map[string]string, len(vars))
+	for _, v := range vars {
+		if name, value, ok := strings.Cut(v, "="); ok {
+			provided[name] = value
+		}
+	}
+	if missing := f.MissingVars(provided); len(missing) > 0 {
+		return fmt.Errorf("formula %s is missing required variables: %s (pass each with --var <name>=<value>)",
+			formulaName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // FormulaOnBeadResult contains the result of instantiating a formula on a bead.
 type FormulaOnBeadResult struct {
 	WispRootID string // The wisp root ID (compound root after bonding)
@@ -545,22 +575,26 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+	// Fail before cooking if the formula needs variables nobody supplied
+	wispVars := append([]string{"feature=" + title, "issue=" + beadID}, extraVars...)
+	if err := checkFormulaVars(formulaName, formulaWorkDir, wispVars); err != nil {
+		return nil, err
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	if !skipCook {
 		cookCmd := exec.Command("bd", "cook", formulaName)
 		cookCmd.Dir = formulaWorkDir
 		cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 		cookCmd.Stderr = os.Stderr
-		if err := cookCmd.Run(); err != nil {
+		if err := proc.RunCmd(cookCmd); err != nil {
 			return nil, fmt.Errorf("cooking formula %s: %w", formulaName, err)
 		}
 	}
 
 	// Step 2: Create wisp with feature and issue variables from bead
-	featureVar := fmt.Sprintf("feature=%s", title)
-	issueVar := fmt.Sprintf("issue=%s", beadID)
-	wispArgs := []string{"mol", "wisp", formulaName, "--var", featureVar, "--var", issueVar}
-	for _, variable := range extraVars {
+	wispArgs := []string{"mol", "wisp", formulaName}
+	for _, variable := range wispVars {
 		wispArgs = append(wispArgs, "--var", variable)
 	}
 	wispArgs = append(wispArgs, "--json")
@@ -568,7 +602,7 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	wispCmd.Dir = formulaWorkDir
 	wispCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	wispCmd.Stderr = os.Stderr
-	wispOut, err := wispCmd.Output()
+	wispOut, err := proc.Output(wispCmd)
 	if err != nil {
 		return nil, fmt.Errorf("creating wisp for formula %s: %w", formulaName, err)
 	}
@@ -584,7 +618,7 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 	bondCmd := exec.Command("bd", bondArgs...)
 	bondCmd.Dir = formulaWorkDir
 	bondCmd.Stderr = os.Stderr
-	bondOut, err := bondCmd.Output()
+	bondOut, err := proc.Output(bondCmd)
 	if err != nil {
 		return nil, fmt.Errorf("bonding formula to bead: %w", err)
 	}
@@ -611,7 +645,7 @@ func CookFormula(formulaName, workDir, townRoot string) error {
 	cookCmd.Dir = workDir
 	cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	cookCmd.Stderr = os.Stderr
-	return cookCmd.Run()
+	return proc.RunCmd(cookCmd)
 }
 
 // isHookedAgentDead checks if the tmux session for a hooked assignee is dead.
@@ -646,7 +680,7 @@ func hookBeadWithRetry(beadID, targetAgent, hookDir string) error {
 		hookCmd := exec.Command("bd", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
 		hookCmd.Dir = hookDir
 		hookCmd.Stderr = os.Stderr
-		if err := hookCmd.Run(); err != nil {
+		if err := proc.RunCmd(hookCmd); err != nil {
 			lastErr = err
 			// Fail fast on config/init errors — retrying won't help (gt-2ra)
 			if isSlingConfigError(err) {
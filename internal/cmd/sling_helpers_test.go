@@ -85,6 +85,7 @@ func TestNudgeRefinerySessionName(t *testing.T) {
 func TestWakeRigAgentsDoesNotNudgeRefinery(t *testing.T) {
 	logPath := filepath.Join(t.TempDir(), "nudge.log")
 	t.Setenv("GT_TEST_NUDGE_LOG", logPath)
+	chdirTestTown(t)
 
 	// wakeRigAgents calls exec.Command("gt", "rig", "boot", ...) and tmux.NudgeSession.
 	// The boot command and witness nudge will fail silently (no real rig/tmux).
@@ -108,6 +109,7 @@ func TestWakeRigAgentsDoesNotNudgeRefinery(t *testing.T) {
 func TestNudgeRefineryNoOpWithoutLog(t *testing.T) {
 	// Ensure test log is NOT set so we exercise the real tmux path
 	t.Setenv("GT_TEST_NUDGE_LOG", "")
+	chdirTestTown(t)
 
 	// Should not panic even though no tmux session exists
 	nudgeRefinery("nonexistent-rig", "test message")
@@ -139,3 +141,41 @@ func TestIsSlingConfigError(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckFormulaVars(t *testing.T) {
+	workDir := t.TempDir()
+	formulasDir := filepath.Join(workDir, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	toml := `formula = "mol-review"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "review"
+title = "Review {{pr_url}} against {{base_branch}}"
+
+[vars.pr_url]
+required = true
+
+[vars.base_branch]
+required = true
+default = "main"
+`
+	if err := os.WriteFile(filepath.Join(formulasDir, "mol-review.formula.toml"), []byte(toml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := checkFormulaVars("mol-review", workDir, []string{"issue=gt-123"})
+	if err == nil || !strings.Contains(err.Error(), "missing required variables: pr_url") {
+		t.Errorf("checkFormulaVars() = %v, want missing pr_url", err)
+	}
+	if err := checkFormulaVars("mol-review", workDir, []string{"pr_url=https://example.com/pr/1"}); err != nil {
+		t.Errorf("checkFormulaVars() = %v, want nil", err)
+	}
+	// Unknown formulas are left for bd to report
+	if err := checkFormulaVars("mol-nonexistent", workDir, nil); err != nil {
+		t.Errorf("checkFormulaVars(unknown) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// slingSaga makes slinging work to a freshly spawned polecat all-or-nothing.
+//
+// A sling to a rig touches the polecat's worktree and agent bead, the work
+// bead, a formula wisp, a Dolt branch and a tmux session. Stopping part way
+// leaves debris the daemon and witness later mistake for a live polecat, and
+// a re-sling fails with "bead already hooked". Each completed side effect is
+// recorded with the action that undoes it; when a later step fails, fail
+// undoes them newest first and reports the step that failed.
+//
+// A nil saga (the work went to an existing agent) records nothing, and its
+// fail returns the error unchanged.
+type slingSaga struct {
+	polecat string
+	done    []slingSagaStep
+}
+
+// slingSagaStep is a completed sling step and how to undo it. Steps that
+// changed a bead also record the bead and the work dir bd is run from to
+// undo them.
+type slingSagaStep struct {
+	name    string
+	undo    func() error
+	beadID  string
+	workDir string
+}
+
+// SlingStepError is returned when a sling to a fresh polecat fails. It names
+// the failed step and what the rollback did.
+type SlingStepError struct {
+	Step       string   // The step that failed
+	Err        error    // Why it failed
+	RolledBack []string // Completed steps that were undone, newest first
+	Leftover   []string // Completed steps that could not be undone
+}
+
+func (e *SlingStepError) Error() string {
+	msg := fmt.Sprintf("sling failed at %s: %v", e.Step, e.Err)
+	if len(e.Leftover) > 0 {
+		msg += fmt.Sprintf("\nrollback incomplete, clean up by hand: %s", strings.Join(e.Leftover, "; "))
+	}
+	return msg
+}
+
+func (e *SlingStepError) Unwrap() error {
+	return e.Err
+}
+
+// undoSlingStepFn is a seam for tests. Production runs the step's undo.
+var undoSlingStepFn = func(step slingSagaStep) error {
+	return step.undo()
+}
+
+// newSlingSaga starts a saga for a sling that spawned spawnInfo, with the
+// spawn as its first step. Returns nil if nothing was spawned.
+func newSlingSaga(spawnInfo *SpawnedPolecatInfo) *slingSaga {
+	if spawnInfo == nil {
+		return nil
+	}
+	s := &slingSaga{polecat: spawnInfo.PolecatName}
+	s.did("spawn polecat "+spawnInfo.PolecatName, func() error {
+		return removeSpawnedPolecat(spawnInfo)
+	})
+	return s
+}
+
+// did records a completed step and the action that undoes it.
+func (s *slingSaga) did(name string, undo func() error) {
+	s.didOnBead(name, "", "", undo)
+}
+
+// didOnBead records a completed step that changed beadID, run from workDir.
+func (s *slingSaga) didOnBead(name, beadID, workDir string, undo func() error) {
+	if s == nil {
+		return
+	}
+	s.done = append(s.done, slingSagaStep{name: name, undo: undo, beadID: beadID, workDir: workDir})
+}
+
+// fail undoes every completed step, newest first, and returns a
+// *SlingStepError for the step that failed. Undo is best-effort: a step
+// that cannot be undone is reported and the rest still run.
+func (s *slingSaga) fail(step string, err error) error {
+	if s == nil {
+		return err
+	}
+	fmt.Printf("%s Sling failed at %s, rolling back polecat %s...\n", style.Warning.Render("⚠"), step, s.polecat)
+
+	stepErr := &SlingStepError{Step: step, Err: err}
+	for i := len(s.done) - 1; i >= 0; i-- {
+		d := s.done[i]
+		if undoErr := undoSlingStepFn(d); undoErr != nil {
+			fmt.Printf("  %s Could not undo %s: %v\n", style.Error.Render("✗"), d.name, undoErr)
+			stepErr.Leftover = append(stepErr.Leftover, fmt.Sprintf("%s (%v)", d.name, undoErr))
+			continue
+		}
+		fmt.Printf("  %s Undid %s\n", style.Dim.Render("○"), d.name)
+		stepErr.RolledBack = append(stepErr.RolledBack, d.name)
+	}
+	s.done = nil
+	return stepErr
+}
+
+// didHook records hooking beadID. Undoing it puts the bead back to open, or
+// to pinned if it was pinned before the sling.
+func (s *slingSaga) didHook(townRoot, beadID, hookWorkDir, priorStatus, priorAssignee string) {
+	s.didOnBead("hook "+beadID, beadID, hookWorkDir, func() error {
+		status, assignee := "open", ""
+		if priorStatus == "pinned" {
+			status, assignee = "pinned", priorAssignee
+		}
+		cmd := exec.Command("bd", "update", beadID, "--status="+status, "--assignee="+assignee)
+		cmd.Dir = beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
+		if out, err := proc.CombinedOutput(cmd); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+}
+
+// didWisp records creating a formula wisp. Undoing it closes the wisp and
+// its steps.
+func (s *slingSaga) didWisp(townRoot, wispID, workDir string) {
+	s.did("create wisp "+wispID, func() error {
+		b := beads.New(beads.ResolveHookDir(townRoot, wispID, workDir))
+		closeDescendants(b, wispID)
+		return b.CloseWithReason("sling rolled back", wispID)
+	})
+}
+
+// didAttach records storing attachment fields (attached_molecule,
+// dispatcher, args) on beadID. Undoing it clears them.
+func (s *slingSaga) didAttach(townRoot, beadID, hookWorkDir string) {
+	s.didOnBead("attach fields to "+beadID, beadID, hookWorkDir, func() error {
+		b := beads.New(beads.ResolveHookDir(townRoot, beadID, hookWorkDir))
+		_, err := b.DetachMolecule(beadID)
+		return err
+	})
+}
+
+// didAgentHook records setting the hook slot on agentID's agent bead.
+// Undoing it clears the slot.
+func (s *slingSaga) didAgentHook(townRoot, agentID, workDir string) {
+	agentBeadID := agentIDToBeadID(agentID, townRoot)
+	if workDir == "" {
+		workDir = townRoot
+	}
+	s.didOnBead("set hook on "+agentBeadID, agentBeadID, workDir, func() error {
+		return beads.New(beads.ResolveHookDir(townRoot, agentBeadID, workDir)).ClearHookBead(agentBeadID)
+	})
+}
+
+// didStartSession records a session start that failed part way. Undoing
+// it kills the polecat's tmux session if one was left behind.
+func (s *slingSaga) didStartSession(spawnInfo *SpawnedPolecatInfo) {
+	s.did("start session "+spawnInfo.SessionName, func() error {
+		t := tmux.NewTmux()
+		if running, err := t.HasSession(spawnInfo.SessionName); err != nil || !running {
+			return err
+		}
+		return t.KillSessionWithProcesses(spawnInfo.SessionName)
+	})
+}
+
+// didDoltBranch records creating the polecat's Dolt branch.
+func (s *slingSaga) didDoltBranch(townRoot string, spawnInfo *SpawnedPolecatInfo) {
+	s.did("create Dolt branch "+spawnInfo.DoltBranch, func() error {
+		doltserver.DeletePolecatBranch(townRoot, spawnInfo.RigName, spawnInfo.DoltBranch)
+		return nil
+	})
+}
+
+// removeSpawnedPolecat removes a spawned polecat's worktree and agent bead.
+func removeSpawnedPolecat(spawnInfo *SpawnedPolecatInfo) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	rigsConfig, err := rigsconfig.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	g := git.NewGit(townRoot)
+	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
+	r, err := rigMgr.GetRig(spawnInfo.RigName)
+	if err != nil {
+		return err
+	}
+	polecatMgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+	return polecatMgr.Remove(spawnInfo.PolecatName, true)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSlingSagaUndoesNewestFirst(t *testing.T) {
+	var undone []string
+	step := func(name string, err error) func() error {
+		return func() error {
+			undone = append(undone, name)
+			return err
+		}
+	}
+
+	s := &slingSaga{polecat: "Toast"}
+	s.did("spawn polecat Toast", step("spawn", nil))
+	s.did("hook gt-abc", step("hook", errors.New("dolt down")))
+	s.did("create Dolt branch polecat-toast", step("branch", nil))
+
+	cause := errors.New("tmux died")
+	err := s.fail("session start", cause)
+
+	var stepErr *SlingStepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("fail returned %T, want *SlingStepError", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("SlingStepError should wrap the step's error")
+	}
+	if got := strings.Join(undone, ","); got != "branch,hook,spawn" {
+		t.Errorf("undo order = %s, want branch,hook,spawn", got)
+	}
+	if len(stepErr.RolledBack) != 2 || len(stepErr.Leftover) != 1 || !strings.Contains(stepErr.Leftover[0], "hook gt-abc") {
+		t.Errorf("rolled back %v, leftover %v", stepErr.RolledBack, stepErr.Leftover)
+	}
+	if !strings.Contains(err.Error(), "session start") || !strings.Contains(err.Error(), "clean up by hand") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// A second failure has nothing left to undo.
+	undone = nil
+	_ = s.fail("again", cause)
+	if len(undone) != 0 {
+		t.Errorf("steps undone twice: %v", undone)
+	}
+}
+
+func TestNilSlingSagaPassesErrorThrough(t *testing.T) {
+	var s *slingSaga
+	s.did("anything", func() error { t.Fatal("undo ran on nil saga"); return nil })
+	cause := errors.New("boom")
+	if err := s.fail("hook", cause); err != cause {
+		t.Errorf("fail = %v, want the original error", err)
+	}
+}
+
+func TestSlingSagaAgentHookUndoTargetsAgentBead(t *testing.T) {
+	townRoot := t.TempDir()
+	s := &slingSaga{polecat: "Toast"}
+	s.didAgentHook(townRoot, "mayor", "")
+
+	if len(s.done) != 1 {
+		t.Fatalf("recorded %d steps, want 1", len(s.done))
+	}
+	step := s.done[0]
+	if step.beadID != "hq-mayor" || step.workDir != townRoot {
+		t.Errorf("agent hook step bead=%q dir=%q, want hq-mayor in %s", step.beadID, step.workDir, townRoot)
+	}
+	if step.name != "set hook on hq-mayor" {
+		t.Errorf("step name = %q", step.name)
+	}
+}
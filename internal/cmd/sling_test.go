@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"github.com/steveyegge/gastown/internal/config"
 	"os"
 	"path/filepath"
@@ -417,14 +418,14 @@ exit /b 0
 	prevDryRun := slingDryRun
 	prevHookRaw := slingHookRawBead
 	prevSpawn := spawnPolecatForSling
-	prevRollback := rollbackSlingArtifactsFn
+	prevUndo := undoSlingStepFn
 	t.Cleanup(func() {
 		slingNoConvoy = prevNoConvoy
 		slingNoBoot = prevNoBoot
 		slingDryRun = prevDryRun
 		slingHookRawBead = prevHookRaw
 		spawnPolecatForSling = prevSpawn
-		rollbackSlingArtifactsFn = prevRollback
+		undoSlingStepFn = prevUndo
 	})
 
 	slingDryRun = false
@@ -441,26 +442,141 @@ exit /b 0
 		}, nil
 	}
 
-	rollbackCalled := false
-	rollbackSlingArtifactsFn = func(spawnInfo *SpawnedPolecatInfo, beadID, hookWorkDir string) {
-		rollbackCalled = true
-		if spawnInfo == nil || spawnInfo.PolecatName != "Toast" {
-			t.Fatalf("unexpected spawnInfo in rollback: %+v", spawnInfo)
-		}
-		if beadID != "gt-abc123" {
-			t.Fatalf("unexpected beadID in rollback: %q", beadID)
-		}
-		if want := filepath.Join(townRoot, "fake-polecat"); hookWorkDir != want {
-			t.Fatalf("unexpected hookWorkDir in rollback: got %q want %q", hookWorkDir, want)
-		}
+	var undone []string
+	undoSlingStepFn = func(step slingSagaStep) error {
+		undone = append(undone, step.name)
+		return nil
 	}
 
 	err = runSling(nil, []string{"gt-abc123", "gastown"})
 	if err == nil {
 		t.Fatalf("expected error from runSling")
 	}
-	if !rollbackCalled {
-		t.Fatalf("expected rollbackSlingArtifacts to be called")
+	var stepErr *SlingStepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "formula instantiation" {
+		t.Fatalf("expected SlingStepError at formula instantiation, got %v", err)
+	}
+	// The bead was never hooked, so only the spawn is undone.
+	if strings.Join(undone, ",") != "spawn polecat Toast" {
+		t.Fatalf("undone steps = %v, want [spawn polecat Toast]", undone)
+	}
+}
+
+// TestSlingRollsBackHookOnSessionStartFailure checks that a sling failing
+// after the bead was hooked undoes the hook on the right bead, from the
+// polecat's work dir.
+func TestSlingRollsBackHookOnSessionStartFailure(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor", "rig"), 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	rigs := &config.RigsConfig{
+		Version: 1,
+		Rigs: map[string]config.RigEntry{
+			"gastown": {
+				GitURL:      "git@github.com:test/gastown.git",
+				AddedAt:     time.Now().Truncate(time.Second),
+				BeadsConfig: &config.BeadsConfig{Repo: "local", Prefix: "gt-"},
+			},
+		},
+	}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatalf("SaveRigsConfig: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "mayor", "rig"), 0755); err != nil {
+		t.Fatalf("mkdir rig beads dir: %v", err)
+	}
+
+	// Stub bd: every command succeeds, so the sling gets as far as
+	// starting the session, which fails because the polecat doesn't exist.
+	binDir := filepath.Join(townRoot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("mkdir binDir: %v", err)
+	}
+	bdScript := `#!/bin/sh
+if [ "$1" = "show" ]; then
+  echo '[{"title":"Test issue","status":"open","assignee":"","description":""}]'
+fi
+exit 0
+`
+	bdScriptWindows := `@echo off
+if "%1"=="show" echo [{"title":"Test issue","status":"open","assignee":"","description":""}]
+exit /b 0
+`
+	_ = writeBDStub(t, binDir, bdScript, bdScriptWindows)
+
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(EnvGTRole, "mayor")
+	t.Setenv("GT_POLECAT", "")
+	t.Setenv("GT_CREW", "")
+	t.Setenv("TMUX_PANE", "")
+	t.Setenv("GT_TEST_NO_NUDGE", "1")
+	t.Setenv("GT_TEST_SKIP_HOOK_VERIFY", "1")
+	t.Chdir(filepath.Join(townRoot, "mayor", "rig"))
+
+	prevNoConvoy := slingNoConvoy
+	prevNoBoot := slingNoBoot
+	prevDryRun := slingDryRun
+	prevHookRaw := slingHookRawBead
+	prevSpawn := spawnPolecatForSling
+	prevUndo := undoSlingStepFn
+	t.Cleanup(func() {
+		slingNoConvoy = prevNoConvoy
+		slingNoBoot = prevNoBoot
+		slingDryRun = prevDryRun
+		slingHookRawBead = prevHookRaw
+		spawnPolecatForSling = prevSpawn
+		undoSlingStepFn = prevUndo
+	})
+
+	slingDryRun = false
+	slingNoConvoy = true
+	slingNoBoot = true
+	slingHookRawBead = true
+
+	fakeWorkDir := filepath.Join(townRoot, "fake-polecat")
+	if err := os.MkdirAll(fakeWorkDir, 0755); err != nil {
+		t.Fatalf("mkdir fakeWorkDir: %v", err)
+	}
+	spawnPolecatForSling = func(rigName string, opts SlingSpawnOptions) (*SpawnedPolecatInfo, error) {
+		return &SpawnedPolecatInfo{
+			RigName:     rigName,
+			PolecatName: "Toast",
+			ClonePath:   fakeWorkDir,
+			SessionName: "gt-gastown-p-Toast",
+		}, nil
+	}
+
+	var undone []slingSagaStep
+	undoSlingStepFn = func(step slingSagaStep) error {
+		undone = append(undone, step)
+		return nil
+	}
+
+	err := runSling(nil, []string{"gt-abc123", "gastown"})
+	var stepErr *SlingStepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "session start" {
+		t.Fatalf("expected SlingStepError at session start, got %v", err)
+	}
+
+	var hook *slingSagaStep
+	for i := range undone {
+		if undone[i].name == "hook gt-abc123" {
+			hook = &undone[i]
+		}
+	}
+	if hook == nil {
+		t.Fatalf("hook was not undone; undone steps: %+v", undone)
+	}
+	if hook.beadID != "gt-abc123" {
+		t.Errorf("hook undo beadID = %q, want gt-abc123", hook.beadID)
+	}
+	if hook.workDir != fakeWorkDir {
+		t.Errorf("hook undo workDir = %q, want %q", hook.workDir, fakeWorkDir)
+	}
+	if last := undone[len(undone)-1].name; last != "spawn polecat Toast" {
+		t.Errorf("last undone step = %q, want the spawn", last)
 	}
 }
 
@@ -559,12 +675,12 @@ exit /b 0
 	prevNoBoot := slingNoBoot
 	prevDryRun := slingDryRun
 	prevSpawn := spawnPolecatForSling
-	prevRollback := rollbackSlingArtifactsFn
+	prevUndo := undoSlingStepFn
 	t.Cleanup(func() {
 		slingNoBoot = prevNoBoot
 		slingDryRun = prevDryRun
 		spawnPolecatForSling = prevSpawn
-		rollbackSlingArtifactsFn = prevRollback
+		undoSlingStepFn = prevUndo
 	})
 
 	slingDryRun = false
@@ -583,26 +699,22 @@ exit /b 0
 		}, nil
 	}
 
-	rollbackCalled := false
-	rollbackSlingArtifactsFn = func(spawnInfo *SpawnedPolecatInfo, beadID, hookWorkDir string) {
-		rollbackCalled = true
-		if spawnInfo == nil || spawnInfo.PolecatName != "Toast" {
-			t.Fatalf("unexpected spawnInfo in rollback: %+v", spawnInfo)
-		}
-		if beadID != "" {
-			t.Fatalf("unexpected beadID in rollback: %q", beadID)
-		}
-		if hookWorkDir != fakeWorkDir {
-			t.Fatalf("unexpected hookWorkDir in rollback: got %q want %q", hookWorkDir, fakeWorkDir)
-		}
+	var undone []string
+	undoSlingStepFn = func(step slingSagaStep) error {
+		undone = append(undone, step.name)
+		return nil
 	}
 
 	err = runSlingFormula([]string{"mol-anything", "gastown"})
 	if err == nil {
 		t.Fatalf("expected error from runSlingFormula")
 	}
-	if !rollbackCalled {
-		t.Fatalf("expected rollbackSlingArtifactsFn to be called")
+	var stepErr *SlingStepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "wisp creation" {
+		t.Fatalf("expected SlingStepError at wisp creation, got %v", err)
+	}
+	if strings.Join(undone, ",") != "spawn polecat Toast" {
+		t.Fatalf("undone steps = %v, want [spawn polecat Toast]", undone)
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -190,6 +191,21 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	status, err := CollectTownStatus(townRoot, statusFast)
+	if err != nil {
+		return err
+	}
+
+	// Output
+	if statusJSON {
+		return outputStatusJSON(status)
+	}
+	return outputStatusText(status)
+}
+
+// CollectTownStatus gathers the town status reported by gt status. With fast,
+// it skips mail, hook, and merge queue lookups (gt status --fast).
+func CollectTownStatus(townRoot string, fast bool) (TownStatus, error) {
 	// Load town config
 	townConfigPath := constants.MayorTownPath(townRoot)
 	townConfig, err := config.LoadTownConfig(townConfigPath)
@@ -199,8 +215,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	}
 
 	// Load rigs config
-	rigsConfigPath := constants.MayorRigsPath(townRoot)
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		// Empty config if file doesn't exist
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
@@ -242,7 +257,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	// Discover rigs
 	rigs, err := mgr.DiscoverRigs()
 	if err != nil {
-		return fmt.Errorf("discovering rigs: %w", err)
+		return TownStatus{}, fmt.Errorf("discovering rigs: %w", err)
 	}
 
 	// Pre-fetch agent beads across all rig-specific beads DBs.
@@ -349,7 +364,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			Source:   overseerConfig.Source,
 		}
 		// Get overseer mail count (skip in --fast mode)
-		if !statusFast {
+		if !fast {
 			if mailbox, err := mailRouter.GetMailbox("overseer"); err == nil {
 				_, unread, _ := mailbox.Count()
 				overseerInfo.UnreadMail = unread
@@ -371,7 +386,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, statusFast)
+		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, fast)
 	}()
 
 	// Process all rigs in parallel
@@ -402,7 +417,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			// Discover hooks for all agents in this rig
 			// In --fast mode, skip expensive handoff bead lookups. Hook info comes from
 			// preloaded agent beads via discoverRigAgents instead.
-			if !statusFast {
+			if !fast {
 				rs.Hooks = discoverRigHooks(r, rs.Crews)
 			}
 			activeHooks := 0
@@ -414,11 +429,11 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			rigActiveHooks[idx] = activeHooks
 
 			// Discover runtime state for all agents in this rig
-			rs.Agents = discoverRigAgents(allSessions, r, rs.Crews, allAgentBeads, allHookBeads, mailRouter, statusFast)
+			rs.Agents = discoverRigAgents(allSessions, r, rs.Crews, allAgentBeads, allHookBeads, mailRouter, fast)
 
 			// Get MQ summary if rig has a refinery
 			// Skip in --fast mode to avoid expensive bd queries
-			if !statusFast {
+			if !fast {
 				rs.MQ = getMQSummary(r)
 			}
 
@@ -442,11 +457,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	}
 	status.Summary.RigCount = len(rigs)
 
-	// Output
-	if statusJSON {
-		return outputStatusJSON(status)
-	}
-	return outputStatusText(status)
+	return status, nil
 }
 
 func outputStatusJSON(status TownStatus) error {
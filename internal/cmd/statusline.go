@@ -11,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -180,8 +181,7 @@ func runMayorStatusLine(t *tmux.Tmux) error {
 	// Load registered rigs to validate against
 	registeredRigs := make(map[string]bool)
 	if townRoot != "" {
-		rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-		if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
+		if rigsConfig, err := rigsconfig.Load(townRoot); err == nil {
 			for rigName := range rigsConfig.Rigs {
 				registeredRigs[rigName] = true
 			}
@@ -426,8 +426,7 @@ func runDeaconStatusLine(t *tmux.Tmux) error {
 	// Load registered rigs to validate against
 	registeredRigs := make(map[string]bool)
 	if townRoot != "" {
-		rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-		if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
+		if rigsConfig, err := rigsconfig.Load(townRoot); err == nil {
 			for rigName := range rigsConfig.Rigs {
 				registeredRigs[rigName] = true
 			}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/redact"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/supportbundle"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	supportBundleOutput      string
+	supportBundleNoDoctor    bool
+	supportBundleLogBytes    int64
+	supportBundleMaxBytes    int64
+	supportBundleEventLines  int
+	supportBundleTranscripts int
+)
+
+var supportBundleCmd = &cobra.Command{
+	Use:     "support-bundle",
+	GroupID: GroupDiag,
+	Short:   "Package sanitized diagnostics for a bug report",
+	Long: `Collect diagnostics from this town into a single tarball.
+
+The bundle contains:
+  - doctor results (unless --no-doctor)
+  - daemon and Dolt log tails
+  - daemon and Dolt state files
+  - town, rig, daemon, and messaging config
+  - the most recent events from .events.jsonl
+  - gt, bd, dolt, tmux, and git versions
+  - with --transcripts N, the N most recently archived session transcripts
+    and their redaction reports (see 'gt costs record --help')
+
+Secrets (tokens, passwords, API keys, URL credentials, high-entropy
+strings, and any transcripts.redaction rules in the town settings) are
+redacted and every artifact is size-limited. A manifest.json at the root of the bundle
+lists each artifact with its source, size, truncation, and redaction count.
+
+Review the bundle before attaching it to a public issue.
+
+Examples:
+  gt support-bundle                         # Write gt-support-<timestamp>.tar.gz
+  gt support-bundle -o /tmp/bundle.tar.gz   # Choose the output path
+  gt support-bundle --no-doctor             # Skip doctor checks (faster)
+  gt support-bundle --transcripts 5         # Include recent archived transcripts`,
+	RunE: runSupportBundle,
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVarP(&supportBundleOutput, "output", "o", "", "Output path (default gt-support-<timestamp>.tar.gz)")
+	supportBundleCmd.Flags().BoolVar(&supportBundleNoDoctor, "no-doctor", false, "Skip running doctor checks")
+	supportBundleCmd.Flags().Int64Var(&supportBundleLogBytes, "log-bytes", supportbundle.DefaultLogTailBytes, "Bytes to keep from the end of each log")
+	supportBundleCmd.Flags().Int64Var(&supportBundleMaxBytes, "max-artifact-bytes", supportbundle.DefaultMaxArtifactBytes, "Size limit for each config/state artifact")
+	supportBundleCmd.Flags().IntVar(&supportBundleEventLines, "events", supportbundle.DefaultEventLines, "Number of recent events to include")
+	supportBundleCmd.Flags().IntVar(&supportBundleTranscripts, "transcripts", 0, "Number of recently archived transcripts to include")
+	rootCmd.AddCommand(supportBundleCmd)
+}
+
+func runSupportBundle(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	name := defaultBundleName()
+	output := supportBundleOutput
+	if output == "" {
+		output = name + ".tar.gz"
+	}
+	b, err := newTownBundle(townRoot, !supportBundleNoDoctor, supportbundle.Options{
+		MaxArtifactBytes: supportBundleMaxBytes,
+		LogTailBytes:     supportBundleLogBytes,
+		EventLines:       supportBundleEventLines,
+		Transcripts:      supportBundleTranscripts,
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeBundle(b, output, name); err != nil {
+		return err
+	}
+
+	var collected, missing, redactions int
+	for _, e := range b.Manifest().Entries {
+		if e.Error != "" {
+			missing++
+			continue
+		}
+		collected++
+		redactions += e.Redactions
+	}
+
+	fmt.Printf("%s Wrote %s\n", style.Bold.Render("✓"), output)
+	fmt.Printf("  %d artifact(s), %d not present, %d secret(s) redacted\n", collected, missing, redactions)
+	fmt.Printf("  %s\n", style.Dim.Render("Review the bundle before sharing: tar -tzf "+output))
+	return nil
+}
+
+// defaultBundleName returns the timestamped name of a new bundle.
+func defaultBundleName() string {
+	return "gt-support-" + time.Now().UTC().Format("20060102-150405")
+}
+
+// newTownBundle collects the town's diagnostics into a redacting bundle,
+// with doctor results if withDoctor is set.
+func newTownBundle(townRoot string, withDoctor bool, opts supportbundle.Options) (*supportbundle.Bundle, error) {
+	redactCfg, err := redact.LoadConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := redact.New(redactCfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	b := supportbundle.New(Version)
+	b.SetRedactor(redactor)
+	b.AddBytes("version.txt", collectToolVersions(), opts.MaxArtifactBytes)
+
+	if withDoctor {
+		fmt.Printf("%s Running doctor checks...\n", style.Dim.Render("○"))
+		b.AddBytes("doctor.txt", runDoctorForBundle(townRoot), opts.MaxArtifactBytes)
+	}
+
+	supportbundle.CollectTown(b, townRoot, opts)
+	return b, nil
+}
+
+// writeBundle writes b to the tarball at output, with its files under name.
+func writeBundle(b *supportbundle.Bundle, output, name string) error {
+	f, err := os.Create(output) //nolint:gosec // G304: path is user-specified
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", output, err)
+	}
+	if err := b.Write(f, name); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", output, err)
+	}
+	return nil
+}
+
+// runDoctorForBundle runs the town doctor checks (read-only) and returns the
+// rendered report.
+func runDoctorForBundle(townRoot string) []byte {
+	ctx := &doctor.CheckContext{TownRoot: townRoot, Verbose: true}
+	report := newTownDoctor().Run(ctx)
+
+	var buf bytes.Buffer
+	report.Print(&buf, true, 0)
+	return buf.Bytes()
+}
+
+// collectToolVersions reports gt's build info and the versions of the
+// external tools gt depends on.
+func collectToolVersions() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "gt %s (%s)\n", Version, Build)
+	if commit := resolveCommitHash(); commit != "" {
+		fmt.Fprintf(&buf, "commit: %s\n", commit)
+	}
+	if branch := resolveBranch(); branch != "" {
+		fmt.Fprintf(&buf, "branch: %s\n", branch)
+	}
+	buf.WriteString("\n")
+
+	for _, tool := range [][]string{
+		{"bd", "version"},
+		{"dolt", "version"},
+		{"tmux", "-V"},
+		{"git", "--version"},
+	} {
+		fmt.Fprintf(&buf, "%s: %s\n", tool[0], toolVersion(tool[0], tool[1:]...))
+	}
+	return buf.Bytes()
+}
+
+func toolVersion(name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "unavailable (" + err.Error() + ")"
+	}
+	// Only the first line is useful; dolt appends upgrade notices.
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
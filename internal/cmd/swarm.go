@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,7 +13,9 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/swarm"
@@ -184,8 +185,7 @@ func getSwarmRig(rigName string) (*rig.Rig, string, error) {
 		return nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -207,8 +207,7 @@ func getAllRigs() ([]*rig.Rig, string, error) {
 		return nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
+	rigsConfig, err := rigsconfig.Load(townRoot)
 	if err != nil {
 		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
 	}
@@ -237,7 +236,7 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 	beadsPath := r.BeadsPath()
 	checkCmd := exec.Command("bd", "show", swarmEpic, "--json")
 	checkCmd.Dir = beadsPath
-	if err := checkCmd.Run(); err != nil {
+	if err := proc.RunCmd(checkCmd); err != nil {
 		// Epic doesn't exist, create it as a swarm molecule
 		createArgs := []string{
 			"create",
@@ -250,7 +249,7 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 		createCmd.Dir = beadsPath
 		var stdout bytes.Buffer
 		createCmd.Stdout = &stdout
-		if err := createCmd.Run(); err != nil {
+		if err := proc.RunCmd(createCmd); err != nil {
 			return fmt.Errorf("creating swarm epic: %w", err)
 		}
 	}
@@ -286,7 +285,7 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 		statusCmd.Dir = beadsPath
 		var statusOut bytes.Buffer
 		statusCmd.Stdout = &statusOut
-		if err := statusCmd.Run(); err != nil {
+		if err := proc.RunCmd(statusCmd); err != nil {
 			return fmt.Errorf("getting swarm status: %w", err)
 		}
 
@@ -327,7 +326,7 @@ func runSwarmStart(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := proc.RunCmd(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -343,7 +342,7 @@ func runSwarmStart(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	statusCmd.Stdout = &stdout
 
-	if err := statusCmd.Run(); err != nil {
+	if err := proc.RunCmd(statusCmd); err != nil {
 		return fmt.Errorf("getting swarm status: %w", err)
 	}
 
@@ -406,7 +405,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", epicID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := proc.RunCmd(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -425,7 +424,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	statusCmd.Stdout = &stdout
 
-	if err := statusCmd.Run(); err != nil {
+	if err := proc.RunCmd(statusCmd); err != nil {
 		return fmt.Errorf("getting epic status: %w", err)
 	}
 
@@ -567,7 +566,7 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() to ensure we read from git-synced location
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := proc.RunCmd(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -588,7 +587,7 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 
-	return bdCmd.Run()
+	return proc.RunCmd(bdCmd)
 }
 
 func runSwarmList(cmd *cobra.Command, args []string) error {
@@ -638,7 +637,7 @@ func runSwarmList(cmd *cobra.Command, args []string) error {
 		var stdout bytes.Buffer
 		bdCmd.Stdout = &stdout
 
-		if err := bdCmd.Run(); err != nil {
+		if err := proc.RunCmd(bdCmd); err != nil {
 			continue
 		}
 
@@ -715,7 +714,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() for git-synced beads
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := proc.RunCmd(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -731,7 +730,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	statusCmd.Stdout = &stdout
 
-	if err := statusCmd.Run(); err != nil {
+	if err := proc.RunCmd(statusCmd); err != nil {
 		return fmt.Errorf("getting swarm status: %w", err)
 	}
 
@@ -781,7 +780,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	}
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		style.PrintWarning("couldn't close swarm epic in beads: %v", err)
 	}
 
@@ -805,7 +804,7 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 		// Use BeadsPath() for git-synced beads
 		checkCmd := exec.Command("bd", "show", swarmID, "--json")
 		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
+		if err := proc.RunCmd(checkCmd); err == nil {
 			foundRig = r
 			break
 		}
@@ -820,7 +819,7 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 	checkCmd.Dir = foundRig.BeadsPath()
 	var stdout bytes.Buffer
 	checkCmd.Stdout = &stdout
-	if err := checkCmd.Run(); err != nil {
+	if err := proc.RunCmd(checkCmd); err != nil {
 		return fmt.Errorf("checking swarm status: %w", err)
 	}
 
@@ -840,7 +839,7 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 	}
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing swarm: %w", err)
 	}
 
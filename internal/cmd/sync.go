@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	syncModeRigs       []string
+	syncExportRigs     []string
+	syncExportAll      bool
+	syncExportNoCommit bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:     "sync",
+	GroupID: GroupServices,
+	Short:   "Manage beads JSONL sync",
+	RunE:    requireSubcommand,
+	Long:    `Manage how beads data is synced between the Dolt server and issues.jsonl.`,
+}
+
+var syncModeCmd = &cobra.Command{
+	Use:   "mode",
+	Short: "Get or set the JSONL sync mode per rig",
+	RunE:  requireSubcommand,
+	Long: `Get or set bd's sync.mode for rig databases.
+
+Modes:
+  git-portable          Export JSONL on every write (default bd behavior)
+  realtime              Export JSONL immediately after each change
+  dolt-native           No JSONL export; Dolt is the only store
+  belt-and-suspenders   Dolt plus JSONL export
+
+The mode is read from and written to each database's config table through
+the Dolt server. 'set' also updates the rig's .beads/config.yaml so the two
+agree; 'get' reports rigs where they disagree.`,
+}
+
+var syncModeGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show the sync mode for each rig",
+	Long: `Show the sync mode stored in each rig database and in its config.yaml.
+
+Examples:
+  gt sync mode get                    # All databases
+  gt sync mode get --rig gastown      # One rig`,
+	Args: cobra.NoArgs,
+	RunE: runSyncModeGet,
+}
+
+var syncModeSetCmd = &cobra.Command{
+	Use:   "set <mode>",
+	Short: "Set the sync mode for rigs",
+	Long: `Set the sync mode in the rig database and its config.yaml.
+
+Without --rig, every database on the Dolt server is updated.
+
+Examples:
+  gt sync mode set dolt-native                  # All databases
+  gt sync mode set git-portable --rig gastown   # One rig`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSyncModeSet,
+}
+
+var syncExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Regenerate issues.jsonl from the Dolt database",
+	Long: `Regenerate each rig's .beads/issues.jsonl with 'bd export' and commit it.
+
+With sync.mode=dolt-native, bd stops exporting JSONL on every write, so the
+git-tracked issues.jsonl goes stale. This command refreshes it on demand;
+the daemon's jsonl_export patrol runs it on a schedule.
+
+By default, exports every rig whose sync mode is dolt-native. Files are only
+rewritten and committed when their contents change. Only issues.jsonl is
+committed, even if other changes are staged in the rig's clone.
+
+To schedule it (every 30m by default), enable the patrol in mayor/daemon.json:
+
+  "patrols": {
+    "jsonl_export": {"enabled": true}
+  }
+
+Examples:
+  gt sync export                    # All dolt-native rigs
+  gt sync export --all              # Every database
+  gt sync export --rig gastown      # One rig
+  gt sync export --no-commit        # Write files without committing`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runSyncExport,
+}
+
+func init() {
+	syncModeGetCmd.Flags().StringSliceVar(&syncModeRigs, "rig", nil, "Rig database(s) to show (default: all)")
+	syncModeSetCmd.Flags().StringSliceVar(&syncModeRigs, "rig", nil, "Rig database(s) to update (default: all)")
+
+	syncExportCmd.Flags().StringSliceVar(&syncExportRigs, "rig", nil, "Rig database(s) to export")
+	syncExportCmd.Flags().BoolVar(&syncExportAll, "all", false, "Export every database, not just dolt-native ones")
+	syncExportCmd.Flags().BoolVar(&syncExportNoCommit, "no-commit", false, "Write issues.jsonl without committing it")
+
+	syncModeCmd.AddCommand(syncModeGetCmd)
+	syncModeCmd.AddCommand(syncModeSetCmd)
+	syncCmd.AddCommand(syncModeCmd)
+	syncCmd.AddCommand(syncExportCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncModeTargets returns the databases selected by --rig, or all databases.
+func syncModeTargets(townRoot string) ([]string, error) {
+	if len(syncModeRigs) > 0 {
+		return syncModeRigs, nil
+	}
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no Dolt databases found")
+	}
+	return databases, nil
+}
+
+func runSyncModeGet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	targets, err := syncModeTargets(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var mismatches int
+	for _, db := range targets {
+		s := doltserver.GetSyncModeStatus(townRoot, db)
+		switch {
+		case s.Error != nil:
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), db, s.Error)
+		case s.Mismatch():
+			mismatches++
+			fmt.Printf("  %s %s: db=%s config.yaml=%s\n", style.Warning.Render("⚠"), db,
+				orUnset(s.DBMode), orUnset(s.ConfigMode))
+		default:
+			fmt.Printf("  %s %s: %s\n", style.Success.Render("✓"), db, orUnset(s.DBMode))
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n%d rig(s) disagree between the database and config.yaml.\n", mismatches)
+		fmt.Printf("Run %s to reconcile.\n", style.Bold.Render("gt sync mode set <mode> --rig <rig>"))
+	}
+	return nil
+}
+
+func runSyncModeSet(cmd *cobra.Command, args []string) error {
+	mode := args[0]
+	if err := doltserver.ValidateSyncMode(mode); err != nil {
+		return err
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	targets, err := syncModeTargets(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, db := range targets {
+		if mode == doltserver.SyncModeDoltNative && !config.FeatureEnabled(townRoot, db, config.FeatureDoltNativeSync) {
+			fmt.Printf("  %s %s: %s is off (gt flags set %s on)\n", style.Error.Render("✗"), db, config.FeatureDoltNativeSync, config.FeatureDoltNativeSync)
+			failed = append(failed, db)
+			continue
+		}
+		if err := doltserver.SetSyncMode(townRoot, db, mode); err != nil {
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), db, err)
+			failed = append(failed, db)
+			continue
+		}
+		fmt.Printf("  %s %s: %s\n", style.Success.Render("✓"), db, mode)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to set sync mode for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func runSyncExport(cmd *cobra.Command, args []string) error {
+	if syncExportAll && len(syncExportRigs) > 0 {
+		return fmt.Errorf("--all and --rig are mutually exclusive")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var targets []string
+	switch {
+	case len(syncExportRigs) > 0:
+		targets = syncExportRigs
+	case syncExportAll:
+		if targets, err = doltserver.ListDatabases(townRoot); err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+	default:
+		if targets, err = doltserver.DoltNativeDatabases(townRoot); err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+		if len(targets) == 0 {
+			fmt.Printf("%s No rigs use sync.mode=dolt-native (use --all to export every database)\n",
+				style.Dim.Render("○"))
+			return nil
+		}
+	}
+
+	var failed []string
+	for _, db := range targets {
+		res, err := doltserver.ExportJSONL(townRoot, db, !syncExportNoCommit)
+		switch {
+		case err != nil:
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), db, err)
+			failed = append(failed, db)
+		case res.Committed:
+			fmt.Printf("  %s %s: exported and committed\n", style.Success.Render("✓"), db)
+		case res.Changed:
+			fmt.Printf("  %s %s: exported %s\n", style.Success.Render("✓"), db, style.Dim.Render("(not committed)"))
+		default:
+			fmt.Printf("  %s %s: up to date\n", style.Dim.Render("○"), db)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to export: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func orUnset(s string) string {
+	if s == "" {
+		return "(unset)"
+	}
+	return s
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -327,7 +328,7 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 	showCmd.Dir = townBeads
 	var showOut bytes.Buffer
 	showCmd.Stdout = &showOut
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("reading convoy '%s': %w", convoyID, err)
 	}
 	var convoys []struct {
@@ -357,7 +358,7 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 	closeCmd.Dir = townBeads
 	closeCmd.Stderr = os.Stderr
 
-	if err := closeCmd.Run(); err != nil {
+	if err := proc.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -381,7 +382,7 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := proc.RunCmd(showCmd); err != nil {
 		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -575,7 +576,7 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	createCmd.Stdout = &stdout
 	createCmd.Stderr = os.Stderr
 
-	if err := createCmd.Run(); err != nil {
+	if err := proc.RunCmd(createCmd); err != nil {
 		return "", fmt.Errorf("creating synthesis bead: %w", err)
 	}
 
@@ -596,7 +597,7 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	depArgs := []string{"dep", "add", convoyID, result.ID, "--type=tracks"}
 	depCmd := exec.Command("bd", depArgs...)
 	depCmd.Dir = townBeads
-	_ = depCmd.Run() // Non-fatal if this fails
+	_ = proc.RunCmd(depCmd) // Non-fatal if this fails
 
 	return result.ID, nil
 }
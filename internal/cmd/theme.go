@@ -437,8 +437,8 @@ func runThemeCLI(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid CLI theme '%s' (valid: auto, dark, light)", mode)
 	}
 
-	// Load existing settings
-	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	// Load existing settings (without a profile applied, since we save them)
+	settings, err := config.LoadTownSettingsBase(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading settings: %w", err)
 	}
@@ -0,0 +1,452 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var traceJSON bool
+
+var traceCmd = &cobra.Command{
+	Use:     "trace <bead-id>",
+	GroupID: GroupDiag,
+	Short:   "Show the end-to-end timeline of one work item",
+	Long: `Reconstruct the pipeline one bead went through, as a timeline.
+
+Each entry shows when it happened and how long it came after the previous
+one, and the longest gap is marked, so it is easy to see where the item
+spent its time (e.g. six hours waiting for the refinery).
+
+The trace is assembled from:
+  - the bead itself: created and closed
+  - the activity feed (.events.jsonl): sling, hook, status changes, gt done,
+    nudges to the assigned polecat while it held the work, and merge events
+    on the branch gt done reported
+  - the attached molecule: each closed step
+  - the merge-request bead: submitted and closed
+  - the cost log (~/.gt/costs.jsonl): session costs attributed to the bead
+
+Anything that has been cleaned up (wisps, rotated feed entries) is missing
+from the trace.
+
+Examples:
+  gt trace gt-abc12
+  gt trace gt-abc12 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(traceCmd)
+}
+
+// Trace phases.
+const (
+	tracePhaseCreated     = "created"
+	tracePhaseSlung       = "slung"
+	tracePhaseHooked      = "hooked"
+	tracePhaseUnhooked    = "unhooked"
+	tracePhaseStatus      = "status"
+	tracePhaseStep        = "step"
+	tracePhaseNudge       = "nudge"
+	tracePhaseDone        = "done"
+	tracePhaseSubmitted   = "submitted"
+	tracePhaseReview      = "review"
+	tracePhaseMerged      = "merged"
+	tracePhaseMergeFailed = "merge_failed"
+	tracePhaseMRClosed    = "mr_closed"
+	tracePhaseClosed      = "closed"
+)
+
+// BeadTrace is the reconstructed pipeline for one bead.
+type BeadTrace struct {
+	Bead     string       `json:"bead"`
+	Title    string       `json:"title,omitempty"`
+	Status   string       `json:"status,omitempty"`
+	Worker   string       `json:"worker,omitempty"`
+	Branches []string     `json:"branches,omitempty"`
+	MR       string       `json:"mr,omitempty"`
+	CostUSD  float64      `json:"cost_usd,omitempty"`
+	Entries  []TraceEntry `json:"entries"`
+}
+
+// TraceEntry is one point on a bead's timeline.
+type TraceEntry struct {
+	Time    time.Time     `json:"time"`
+	Phase   string        `json:"phase"`
+	Actor   string        `json:"actor,omitempty"`
+	Summary string        `json:"summary"`
+	Gap     time.Duration `json:"gap_ns"` // time since the previous entry
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	beadID := args[0]
+
+	bd := beads.New(beads.ResolveHookDir(townRoot, beadID, ""))
+	issue, err := bd.Show(beadID)
+	if err != nil {
+		return fmt.Errorf("looking up %s: %w", beadID, err)
+	}
+
+	t := &BeadTrace{Bead: beadID}
+	t.addIssue(issue)
+
+	feed, err := loadTraceEvents(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read events feed: %v\n", err)
+	}
+	t.addEvents(feed)
+	t.addSteps(closedMoleculeSteps(bd, beadID))
+	if mr := findMRForIssue(bd, beadID); mr != nil {
+		t.addMR(mr)
+	}
+	for _, e := range readCostLogEntries() {
+		if e.WorkItem == beadID && e.Event == "" {
+			t.CostUSD += e.CostUSD
+		}
+	}
+	t.finish()
+
+	if traceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	}
+	printTrace(t)
+	return nil
+}
+
+// loadTraceEvents reads the town's activity feed.
+func loadTraceEvents(townRoot string) ([]events.Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return parseTraceEvents(f)
+}
+
+// parseTraceEvents parses feed events, skipping malformed lines.
+func parseTraceEvents(r io.Reader) ([]events.Event, error) {
+	var evs []events.Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		evs = append(evs, e)
+	}
+	return evs, scanner.Err()
+}
+
+// findMRForIssue returns the most recent merge-request bead for issueID.
+func findMRForIssue(bd *beads.Beads, issueID string) *beads.Issue {
+	mrs, err := bd.List(beads.ListOptions{Status: "all", Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		return nil
+	}
+	var latest *beads.Issue
+	for _, mr := range mrs {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil || fields.SourceIssue != issueID {
+			continue
+		}
+		if latest == nil || mr.CreatedAt > latest.CreatedAt {
+			latest = mr
+		}
+	}
+	return latest
+}
+
+func (t *BeadTrace) add(ts time.Time, phase, actor, summary string) {
+	if ts.IsZero() {
+		return
+	}
+	t.Entries = append(t.Entries, TraceEntry{Time: ts, Phase: phase, Actor: actor, Summary: summary})
+}
+
+func (t *BeadTrace) addBranch(branch string) {
+	if branch == "" {
+		return
+	}
+	for _, b := range t.Branches {
+		if b == branch {
+			return
+		}
+	}
+	t.Branches = append(t.Branches, branch)
+}
+
+// addIssue records the bead's own creation and close.
+func (t *BeadTrace) addIssue(issue *beads.Issue) {
+	t.Title = issue.Title
+	t.Status = issue.Status
+	t.Worker = issue.Assignee
+	t.add(parseBeadsTimestamp(issue.CreatedAt), tracePhaseCreated, issue.CreatedBy, "Created")
+	if closed := parseBeadsTimestamp(issue.ClosedAt); !closed.IsZero() {
+		summary := "Closed"
+		if issue.CloseReason != "" {
+			summary += ": " + issue.CloseReason
+		}
+		t.add(closed, tracePhaseClosed, "", summary)
+	}
+}
+
+// addEvents picks the bead's events out of the feed. Events naming the bead
+// come first; they identify the polecat it was slung to and the branches
+// gt done reported, which pick out the merge events and the nudges that
+// belong to this item.
+func (t *BeadTrace) addEvents(feed []events.Event) {
+	var workStart, workEnd time.Time
+	for _, e := range feed {
+		if payloadString(e, "bead") != t.Bead {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		switch e.Type {
+		case events.TypeSling:
+			target := payloadString(e, "target")
+			t.Worker = target
+			if workStart.IsZero() {
+				workStart = ts
+			}
+			summary := "Slung to " + target
+			if formula := payloadString(e, "formula"); formula != "" {
+				summary += " (" + formula + ")"
+			}
+			t.add(ts, tracePhaseSlung, e.Actor, summary)
+		case events.TypeHook:
+			t.add(ts, tracePhaseHooked, e.Actor, "Hooked by "+e.Actor)
+		case events.TypeUnhook:
+			t.add(ts, tracePhaseUnhooked, e.Actor, "Unhooked")
+		case events.TypeBeadStatusChanged:
+			t.add(ts, tracePhaseStatus, e.Actor,
+				fmt.Sprintf("Status %s → %s", payloadString(e, "from_status"), payloadString(e, "status")))
+		case events.TypeDone:
+			branch := payloadString(e, "branch")
+			t.addBranch(branch)
+			workEnd = ts
+			summary := "gt done"
+			if branch != "" {
+				summary += " on " + branch
+			}
+			t.add(ts, tracePhaseDone, e.Actor, summary)
+		}
+	}
+
+	for _, e := range feed {
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		switch e.Type {
+		case events.TypeMergeStarted, events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
+			if !t.hasBranch(payloadString(e, "branch")) {
+				continue
+			}
+			t.addMergeEvent(e, ts)
+		case events.TypeNudge, events.TypePolecatNudged:
+			if t.Worker == "" || workStart.IsZero() || ts.Before(workStart) || (!workEnd.IsZero() && ts.After(workEnd)) {
+				continue
+			}
+			if !sameAgent(payloadString(e, "rig"), payloadString(e, "target"), t.Worker) {
+				continue
+			}
+			t.add(ts, tracePhaseNudge, e.Actor, "Nudged: "+truncateTrace(payloadString(e, "reason"), 60))
+		}
+	}
+}
+
+func (t *BeadTrace) addMergeEvent(e events.Event, ts time.Time) {
+	reason := payloadString(e, "reason")
+	switch e.Type {
+	case events.TypeMergeStarted:
+		t.add(ts, tracePhaseReview, e.Actor, "Merge started")
+	case events.TypeMerged:
+		t.add(ts, tracePhaseMerged, e.Actor, "Merged")
+	case events.TypeMergeFailed:
+		t.add(ts, tracePhaseMergeFailed, e.Actor, "Merge failed: "+reason)
+	case events.TypeMergeSkipped:
+		t.add(ts, tracePhaseMergeFailed, e.Actor, "Merge skipped: "+reason)
+	}
+}
+
+func (t *BeadTrace) hasBranch(branch string) bool {
+	for _, b := range t.Branches {
+		if b == branch && b != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// addSteps records each closed molecule step.
+func (t *BeadTrace) addSteps(steps []DoneStep) {
+	for _, s := range steps {
+		t.add(s.ClosedAt, tracePhaseStep, "", "Step closed: "+s.Title)
+	}
+}
+
+// addMR records the merge request's submission and close.
+func (t *BeadTrace) addMR(mr *beads.Issue) {
+	t.MR = mr.ID
+	fields := beads.ParseMRFields(mr)
+	if fields != nil {
+		t.addBranch(fields.Branch)
+	}
+	summary := "Submitted " + mr.ID
+	if fields != nil && fields.Target != "" {
+		summary += " → " + fields.Target
+	}
+	t.add(parseBeadsTimestamp(mr.CreatedAt), tracePhaseSubmitted, mr.CreatedBy, summary)
+	if closed := parseBeadsTimestamp(mr.ClosedAt); !closed.IsZero() {
+		reason := mr.CloseReason
+		if fields != nil && fields.CloseReason != "" {
+			reason = fields.CloseReason
+		}
+		if reason == "" {
+			reason = "closed"
+		}
+		t.add(closed, tracePhaseMRClosed, "", mr.ID+" "+reason)
+	}
+}
+
+// finish orders the timeline and fills in the gaps between entries.
+func (t *BeadTrace) finish() {
+	sort.SliceStable(t.Entries, func(i, j int) bool { return t.Entries[i].Time.Before(t.Entries[j].Time) })
+	for i := 1; i < len(t.Entries); i++ {
+		t.Entries[i].Gap = t.Entries[i].Time.Sub(t.Entries[i-1].Time)
+	}
+}
+
+// Bottleneck returns the index of the entry preceded by the longest gap, or
+// -1 when the timeline has fewer than two entries.
+func (t *BeadTrace) Bottleneck() int {
+	idx := -1
+	var longest time.Duration
+	for i := 1; i < len(t.Entries); i++ {
+		if t.Entries[i].Gap > longest {
+			idx, longest = i, t.Entries[i].Gap
+		}
+	}
+	return idx
+}
+
+// Total returns the time from the first entry to the last.
+func (t *BeadTrace) Total() time.Duration {
+	if len(t.Entries) < 2 {
+		return 0
+	}
+	return t.Entries[len(t.Entries)-1].Time.Sub(t.Entries[0].Time)
+}
+
+// sameAgent reports whether a nudge target names worker. Targets come as
+// full addresses (gastown/polecats/Toast), short ones (gastown/Toast), or a
+// bare polecat name with the rig in a separate field.
+func sameAgent(rig, target, worker string) bool {
+	if target == "" || worker == "" {
+		return false
+	}
+	if target == worker {
+		return true
+	}
+	wParts := strings.Split(worker, "/")
+	tParts := strings.Split(target, "/")
+	if wParts[len(wParts)-1] != tParts[len(tParts)-1] {
+		return false
+	}
+	if len(tParts) > 1 {
+		rig = tParts[0]
+	}
+	return rig != "" && rig == wParts[0]
+}
+
+func payloadString(e events.Event, key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+func truncateTrace(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
+
+func printTrace(t *BeadTrace) {
+	fmt.Printf("%s %s", style.Bold.Render(t.Bead), t.Title)
+	if t.Status != "" {
+		fmt.Printf(" %s", style.Dim.Render("["+t.Status+"]"))
+	}
+	fmt.Println()
+
+	var meta []string
+	if t.Worker != "" {
+		meta = append(meta, "Worker: "+t.Worker)
+	}
+	if len(t.Branches) > 0 {
+		meta = append(meta, "Branch: "+strings.Join(t.Branches, ", "))
+	}
+	if t.MR != "" {
+		meta = append(meta, "MR: "+t.MR)
+	}
+	if t.CostUSD > 0 {
+		meta = append(meta, fmt.Sprintf("Cost: $%.2f", t.CostUSD))
+	}
+	if len(meta) > 0 {
+		fmt.Println(style.Dim.Render(strings.Join(meta, "  ")))
+	}
+	fmt.Println()
+
+	if len(t.Entries) == 0 {
+		fmt.Printf("%s No history found\n", style.Dim.Render("○"))
+		return
+	}
+
+	bottleneck := t.Bottleneck()
+	var day string
+	for i, e := range t.Entries {
+		local := e.Time.Local()
+		if d := local.Format("2006-01-02"); d != day {
+			fmt.Printf("%s\n", style.Bold.Render("─── "+d+" ───"))
+			day = d
+		}
+		gap := ""
+		if i > 0 {
+			gap = "+" + formatDuration(e.Gap)
+		}
+		line := fmt.Sprintf("  %s  %10s  %-12s %s", local.Format("15:04:05"), gap, e.Phase, e.Summary)
+		if i == bottleneck {
+			line += "  " + style.Warning.Render("◀ longest wait")
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\nTotal: %s", formatDuration(t.Total()))
+	if bottleneck > 0 {
+		prev, cur := t.Entries[bottleneck-1], t.Entries[bottleneck]
+		fmt.Printf("  Longest wait: %s between %s and %s", formatDuration(cur.Gap), prev.Phase, cur.Phase)
+	}
+	fmt.Println()
+}
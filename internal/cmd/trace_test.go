@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestBeadTraceTimeline(t *testing.T) {
+	feed := `{"ts":"2026-03-01T09:05:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-abc","target":"gastown/polecats/Toast","formula":"mol-polecat-work"}}
+{"ts":"2026-03-01T09:06:00Z","type":"hook","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc"}}
+{"ts":"2026-03-01T08:00:00Z","type":"nudge","actor":"witness","payload":{"rig":"gastown","target":"gastown/Toast","reason":"before the sling"}}
+{"ts":"2026-03-01T09:30:00Z","type":"polecat_nudged","actor":"witness","payload":{"rig":"gastown","target":"Toast","reason":"idle"}}
+{"ts":"2026-03-01T09:31:00Z","type":"nudge","actor":"witness","payload":{"rig":"gastown","target":"gastown/polecats/Nux","reason":"someone else"}}
+not json
+{"ts":"2026-03-01T10:00:00Z","type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc","branch":"polecat/Toast/gt-abc"}}
+{"ts":"2026-03-01T12:00:00Z","type":"nudge","actor":"witness","payload":{"rig":"gastown","target":"gastown/Toast","reason":"next job"}}
+{"ts":"2026-03-01T16:00:00Z","type":"merge_started","actor":"gastown/refinery","payload":{"branch":"polecat/Toast/gt-abc"}}
+{"ts":"2026-03-01T16:05:00Z","type":"merged","actor":"gastown/refinery","payload":{"branch":"polecat/Toast/gt-abc"}}
+{"ts":"2026-03-01T16:06:00Z","type":"merged","actor":"gastown/refinery","payload":{"branch":"polecat/Nux/gt-xyz"}}
+`
+	evs, err := parseTraceEvents(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &BeadTrace{Bead: "gt-abc"}
+	tr.addIssue(&beads.Issue{ID: "gt-abc", Title: "Fix login", Status: "closed",
+		CreatedAt: "2026-03-01T09:00:00Z", ClosedAt: "2026-03-01T16:05:30Z", CloseReason: "merged"})
+	tr.addEvents(evs)
+	tr.addSteps([]DoneStep{{Title: "implement", ClosedAt: time.Date(2026, 3, 1, 9, 50, 0, 0, time.UTC)}})
+	tr.addMR(&beads.Issue{ID: "gt-mr1", CreatedAt: "2026-03-01T10:01:00Z",
+		Description: "branch: polecat/Toast/gt-abc\ntarget: main\nsource_issue: gt-abc"})
+	tr.finish()
+
+	var phases []string
+	for _, e := range tr.Entries {
+		phases = append(phases, e.Phase)
+	}
+	want := []string{
+		tracePhaseCreated, tracePhaseSlung, tracePhaseHooked, tracePhaseNudge, tracePhaseStep,
+		tracePhaseDone, tracePhaseSubmitted, tracePhaseReview, tracePhaseMerged, tracePhaseClosed,
+	}
+	if strings.Join(phases, ",") != strings.Join(want, ",") {
+		t.Fatalf("phases = %v, want %v", phases, want)
+	}
+	if tr.Worker != "gastown/polecats/Toast" || tr.MR != "gt-mr1" || len(tr.Branches) != 1 {
+		t.Errorf("trace = worker %q, MR %q, branches %v", tr.Worker, tr.MR, tr.Branches)
+	}
+
+	b := tr.Bottleneck()
+	if b < 0 || tr.Entries[b].Phase != tracePhaseReview || tr.Entries[b].Gap != 5*time.Hour+59*time.Minute {
+		t.Errorf("bottleneck = %d (%+v), want the wait for merge_started", b, tr.Entries[b])
+	}
+	if got := tr.Total(); got != 7*time.Hour+5*time.Minute+30*time.Second {
+		t.Errorf("Total() = %s", got)
+	}
+}
+
+func TestSameAgent(t *testing.T) {
+	tests := []struct {
+		rig, target, worker string
+		want                bool
+	}{
+		{"", "gastown/polecats/Toast", "gastown/polecats/Toast", true},
+		{"gastown", "gastown/Toast", "gastown/polecats/Toast", true},
+		{"gastown", "Toast", "gastown/polecats/Toast", true},
+		{"beads", "Toast", "gastown/polecats/Toast", false},
+		{"", "Toast", "gastown/polecats/Toast", false},
+		{"gastown", "gastown/Nux", "gastown/polecats/Toast", false},
+	}
+	for _, tt := range tests {
+		if got := sameAgent(tt.rig, tt.target, tt.worker); got != tt.want {
+			t.Errorf("sameAgent(%q, %q, %q) = %v, want %v", tt.rig, tt.target, tt.worker, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/feed"
+	"github.com/steveyegge/gastown/internal/tui/town"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:     "tui",
+	GroupID: GroupDiag,
+	Short:   "Full-screen town dashboard with agents, ready work, Dolt, and events",
+	Long: `Open a full-screen terminal UI for the whole town.
+
+Panes:
+  Agents   Town and rig agents, whether they're running, and hooked work
+  Ready    The town-wide ready queue, highest priority first
+  Dolt     Dolt server health (connections, latency, disk, warnings)
+  Events   Recent town events
+
+Panes refresh every few seconds from the same code behind 'gt status',
+'gt ready --all-rigs', and 'gt dolt status', so what you see matches
+their --json output.
+
+Keys:
+  tab / shift+tab   Move between panes
+  j/k or ↑/↓        Select a row
+  p                 Peek at the selected agent's session
+  n                 Nudge the selected agent (type a message, enter to send)
+  s                 Sling the selected ready bead (edit the target, enter to send)
+  r                 Refresh now
+  ?                 Show all keys
+  q                 Quit
+
+Examples:
+  gt tui`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	m := town.New(tuiProviders(townRoot))
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("running TUI: %w", err)
+	}
+	return nil
+}
+
+// tuiProviders wires the TUI panes and actions to the town at townRoot.
+func tuiProviders(townRoot string) town.Providers {
+	t := tmux.NewTmux()
+	return town.Providers{
+		Agents: func() ([]town.Agent, error) { return tuiAgents(townRoot) },
+		Ready:  func() ([]town.ReadyItem, error) { return tuiReady(townRoot) },
+		Dolt:   func() (*town.DoltHealth, error) { return tuiDolt(townRoot) },
+		Events: func(n int) ([]town.Event, error) { return tuiEvents(townRoot, n) },
+		Peek:   t.CapturePane,
+		Nudge: func(session, message string) error {
+			_, err := runGTSubcommand(townRoot, "nudge", session, "-m", message)
+			return err
+		},
+		Sling: func(bead, target string) (string, error) {
+			return runGTSubcommand(townRoot, "sling", bead, target)
+		},
+	}
+}
+
+// tuiAgents lists town and rig agents from gt status (fast mode).
+func tuiAgents(townRoot string) ([]town.Agent, error) {
+	status, err := CollectTownStatus(townRoot, true)
+	if err != nil {
+		return nil, err
+	}
+	agents := status.Agents
+	for _, r := range status.Rigs {
+		agents = append(agents, r.Agents...)
+	}
+
+	out := make([]town.Agent, 0, len(agents))
+	for _, a := range agents {
+		work := a.WorkTitle
+		if work == "" {
+			work = a.HookBead
+		}
+		out = append(out, town.Agent{
+			Address:    a.Address,
+			Session:    a.Session,
+			Role:       a.Role,
+			Running:    a.Running,
+			Work:       work,
+			State:      a.State,
+			UnreadMail: a.UnreadMail,
+		})
+	}
+	return out, nil
+}
+
+// tuiReady returns the town-wide ready queue from gt ready --all-rigs.
+func tuiReady(townRoot string) ([]town.ReadyItem, error) {
+	result, err := CollectReady(townRoot, "")
+	if err != nil {
+		return nil, err
+	}
+	queue := buildReadyQueue(result.Sources, "", nil)
+	if len(queue.Errors) > 0 && len(queue.Errors) == len(result.Sources) {
+		return nil, fmt.Errorf("all ready sources failed to load")
+	}
+
+	items := make([]town.ReadyItem, 0, len(queue.Items))
+	for _, item := range queue.Items {
+		items = append(items, town.ReadyItem{
+			ID:       item.ID,
+			Title:    item.Title,
+			Priority: item.Priority,
+			Source:   item.Source,
+		})
+	}
+	return items, nil
+}
+
+// tuiDolt returns Dolt server health from gt dolt status.
+func tuiDolt(townRoot string) (*town.DoltHealth, error) {
+	running, _, err := doltserver.IsRunning(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !running {
+		return &town.DoltHealth{}, nil
+	}
+	metrics := doltserver.GetHealthMetrics(townRoot)
+	return &town.DoltHealth{
+		Running:        true,
+		Healthy:        metrics.Healthy,
+		ReadOnly:       metrics.ReadOnly,
+		Connections:    metrics.Connections,
+		MaxConnections: metrics.MaxConnections,
+		Latency:        metrics.QueryLatency,
+		Disk:           metrics.DiskUsageHuman,
+		Warnings:       metrics.Warnings,
+	}, nil
+}
+
+// tuiEvents returns up to n of the most recent feed-visible town events,
+// newest first, formatted the same way as gt feed.
+func tuiEvents(townRoot string, n int) ([]town.Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	// Keep only the last n parsed events while scanning.
+	var recent []*feed.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if e := feed.ParseGtEventLine(scanner.Text()); e != nil {
+			recent = append(recent, e)
+			if len(recent) > n {
+				recent = recent[1:]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]town.Event, 0, len(recent))
+	for i := len(recent) - 1; i >= 0; i-- {
+		e := recent[i]
+		out = append(out, town.Event{Time: e.Time, Type: e.Type, Actor: e.Actor, Summary: e.Message})
+	}
+	return out, nil
+}
+
+// runGTSubcommand runs this gt binary with args in townRoot and returns its
+// combined output. On failure, the error includes the output.
+func runGTSubcommand(townRoot string, args ...string) (string, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locating gt binary: %w", err)
+	}
+	c := exec.Command(self, args...) //nolint:gosec // G204: re-executes this binary
+	c.Dir = townRoot
+	c.Env = os.Environ()
+	out, err := c.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		if output != "" {
+			return output, fmt.Errorf("%w: %s", err, output)
+		}
+		return output, err
+	}
+	return output, nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -120,7 +121,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 			doltDetail = err.Error()
 		} else {
 			doltOK = true
-			doltDetail = fmt.Sprintf("started (port %d)", doltserver.DefaultPort)
+			doltDetail = fmt.Sprintf("started (port %d)", doltserver.DefaultConfig(townRoot).Port)
 		}
 	}()
 
@@ -513,8 +514,7 @@ func discoverRigs(townRoot string) []string {
 	var rigs []string
 
 	// Try rigs.json first
-	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
+	if rigsConfig, err := rigsconfig.Load(townRoot); err == nil {
 		for name := range rigsConfig.Rigs {
 			rigs = append(rigs, name)
 		}
@@ -0,0 +1,433 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	verifyInstallKeep bool
+	verifyInstallJSON bool
+)
+
+var verifyInstallCmd = &cobra.Command{
+	Use:     "verify-install",
+	GroupID: GroupDiag,
+	Short:   "Smoke-test gt, bd and dolt end to end on a throwaway rig",
+	Long: `Exercise the full stack on a scratch town and report what works.
+
+Run this right after installing or upgrading gt, bd, or dolt. It needs no
+workspace: everything happens in a temporary town with its own Dolt server
+on a free local port, so a running town is not touched.
+
+Stages:
+  tools      gt, bd and dolt are on PATH (versions reported)
+  init-rig   create the rig database
+  server     start the scratch Dolt server
+  bead       bd init the rig and create a bead
+  molecule   cook a one-step formula and pour a molecule from it
+  branch     update the bead on a polecat Dolt branch and merge it to main
+  cost       price a fake session and round-trip it through a cost ledger
+  cleanup    stop the server and remove the scratch town
+
+Each stage is timed. A failed stage skips the ones after it, except
+cleanup, which always runs. --keep leaves the scratch town (and stops the
+server) for inspection.
+
+Exits non-zero if any stage fails.
+
+Examples:
+  gt verify-install
+  gt verify-install --json
+  gt verify-install --keep`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runVerifyInstall,
+}
+
+func init() {
+	verifyInstallCmd.Flags().BoolVar(&verifyInstallKeep, "keep", false, "Keep the scratch town")
+	verifyInstallCmd.Flags().BoolVar(&verifyInstallJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(verifyInstallCmd)
+}
+
+// Verify-install stages, in the order they run.
+const (
+	verifyStageTools    = "tools"
+	verifyStageInitRig  = "init-rig"
+	verifyStageServer   = "server"
+	verifyStageBead     = "bead"
+	verifyStageMolecule = "molecule"
+	verifyStageBranch   = "branch"
+	verifyStageCost     = "cost"
+	verifyStageCleanup  = "cleanup"
+)
+
+// verifyInstallRig is the scratch rig's name, database and bead prefix.
+const verifyInstallRig = "verify"
+
+// verifyInstallFormula is the one-step formula poured by the molecule stage.
+const verifyInstallFormula = "mol-verify-install"
+
+// verifyStage is the outcome of one verify-install stage.
+type verifyStage struct {
+	Name     string        `json:"name"`
+	OK       bool          `json:"ok"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// verifyInstallReport is the result of gt verify-install.
+type verifyInstallReport struct {
+	Passed   bool          `json:"passed"`
+	Town     string        `json:"town,omitempty"` // Set with --keep
+	Duration time.Duration `json:"duration_ns"`
+	Stages   []verifyStage `json:"stages"`
+}
+
+// verifyStep is a stage to run. run returns a detail for the report, or
+// an error if the stage failed.
+type verifyStep struct {
+	name string
+	run  func() (string, error)
+}
+
+// runVerifySteps runs steps in order, timing each. After a failure the
+// remaining steps are reported as skipped, except always, which runs last
+// regardless.
+func runVerifySteps(steps []verifyStep, always verifyStep) []verifyStage {
+	stages := make([]verifyStage, 0, len(steps)+1)
+	failed := ""
+	for _, s := range append(steps, always) {
+		if failed != "" && s.name != always.name {
+			stages = append(stages, verifyStage{Name: s.name, Skipped: true, Detail: "after " + failed + " failed"})
+			continue
+		}
+		start := time.Now()
+		detail, err := s.run()
+		stage := verifyStage{Name: s.name, OK: err == nil, Detail: detail, Duration: time.Since(start)}
+		if err != nil {
+			stage.Detail = err.Error()
+			if failed == "" {
+				failed = s.name
+			}
+		}
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
+func runVerifyInstall(cmd *cobra.Command, args []string) error {
+	town, err := os.MkdirTemp("", "gt-verify-install-")
+	if err != nil {
+		return fmt.Errorf("creating scratch town: %w", err)
+	}
+
+	v := &verifyInstallRun{town: town}
+	steps := []verifyStep{
+		{verifyStageTools, v.tools},
+		{verifyStageInitRig, v.initRig},
+		{verifyStageServer, v.startServer},
+		{verifyStageBead, v.createBead},
+		{verifyStageMolecule, v.pourMolecule},
+		{verifyStageBranch, v.mergeBranch},
+		{verifyStageCost, v.costEntry},
+	}
+
+	if !verifyInstallJSON {
+		fmt.Printf("Verifying install in %s...\n", style.Dim.Render(town))
+	}
+	start := time.Now()
+	report := &verifyInstallReport{Stages: runVerifySteps(steps, verifyStep{verifyStageCleanup, v.cleanup})}
+	report.Duration = time.Since(start)
+	report.Passed = true
+	for _, s := range report.Stages {
+		if !s.OK && !s.Skipped {
+			report.Passed = false
+		}
+	}
+	if verifyInstallKeep {
+		report.Town = town
+	}
+
+	if verifyInstallJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printVerifyInstallReport(report)
+	}
+
+	if !report.Passed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// verifyInstallRun carries state between the stages of one run.
+type verifyInstallRun struct {
+	town     string
+	beadsDir string
+	beadID   string
+	started  bool
+}
+
+// env returns the environment for bd calls against the scratch rig.
+func (v *verifyInstallRun) env(extra ...string) []string {
+	env := make([]string, 0, len(os.Environ())+len(extra)+1)
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, "BEADS_DIR=") && !strings.HasPrefix(e, "BD_BRANCH=") {
+			env = append(env, e)
+		}
+	}
+	return append(append(env, "BEADS_DIR="+v.beadsDir), extra...)
+}
+
+// bd runs bd in the scratch rig and returns its trimmed output.
+func (v *verifyInstallRun) bd(extraEnv []string, args ...string) (string, error) {
+	c := exec.Command("bd", args...)
+	c.Dir = filepath.Dir(v.beadsDir)
+	c.Env = v.env(extraEnv...)
+	out, err := proc.CombinedOutput(c)
+	if err != nil {
+		return "", fmt.Errorf("bd %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (v *verifyInstallRun) tools() (string, error) {
+	var versions []string
+	for _, tool := range []struct{ name, arg string }{{"gt", "version"}, {"bd", "version"}, {"dolt", "version"}} {
+		path, err := exec.LookPath(tool.name)
+		if err != nil {
+			return "", fmt.Errorf("%s not found in PATH", tool.name)
+		}
+		out, err := exec.Command(path, tool.arg).CombinedOutput() //nolint:gosec // G204: fixed tool names
+		if err != nil {
+			return "", fmt.Errorf("%s %s: %s", tool.name, tool.arg, strings.TrimSpace(string(out)))
+		}
+		line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		versions = append(versions, line)
+	}
+	return strings.Join(versions, "; "), nil
+}
+
+func (v *verifyInstallRun) initRig() (string, error) {
+	port, err := verifyFreePort()
+	if err != nil {
+		return "", fmt.Errorf("finding a free port: %w", err)
+	}
+	settings := config.NewTownSettings()
+	settings.DoltServer = &config.DoltServerConfig{Port: port}
+	settingsPath := config.TownSettingsPath(v.town)
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		return "", err
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return "", fmt.Errorf("writing town settings: %w", err)
+	}
+	if _, _, err := doltserver.InitRig(v.town, verifyInstallRig); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("database %s, port %d", verifyInstallRig, port), nil
+}
+
+func (v *verifyInstallRun) startServer() (string, error) {
+	if err := doltserver.Start(v.town); err != nil {
+		return "", err
+	}
+	v.started = true
+	served, missing, err := doltserver.VerifyDatabasesWithRetry(v.town, 5)
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("server is not serving %s", strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("%d database(s) served", len(served)), nil
+}
+
+func (v *verifyInstallRun) createBead() (string, error) {
+	beadsDir, err := doltserver.FindOrCreateRigBeadsDir(v.town, verifyInstallRig)
+	if err != nil {
+		return "", err
+	}
+	v.beadsDir = beadsDir
+	if err := doltserver.EnsureMetadata(v.town, verifyInstallRig); err != nil {
+		return "", fmt.Errorf("writing metadata.json: %w", err)
+	}
+	if out, err := v.bd(nil, "init", "--prefix", verifyInstallRig, "--backend", "dolt", "--server"); err != nil && !strings.Contains(out+err.Error(), "already initialized") {
+		return "", err
+	}
+	if _, err := v.bd(nil, "config", "set", "issue_prefix", verifyInstallRig); err != nil {
+		return "", err
+	}
+	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
+		return "", fmt.Errorf("ensuring custom types: %w", err)
+	}
+
+	issue, err := beads.NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir).Create(beads.CreateOptions{
+		Title:    "verify-install smoke test",
+		Priority: 4,
+		Actor:    "gt",
+	})
+	if err != nil {
+		return "", err
+	}
+	v.beadID = issue.ID
+	return issue.ID, nil
+}
+
+func (v *verifyInstallRun) pourMolecule() (string, error) {
+	formula := fmt.Sprintf(`formula = %q
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "check"
+title = "Check the install"
+`, verifyInstallFormula)
+	formulasDir := filepath.Join(v.beadsDir, "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(formulasDir, verifyInstallFormula+".formula.toml"), []byte(formula), 0644); err != nil { //nolint:gosec // G306: formulas are not sensitive
+		return "", err
+	}
+	if _, err := v.bd(nil, "cook", verifyInstallFormula); err != nil {
+		return "", err
+	}
+	out, err := v.bd(nil, "mol", "pour", verifyInstallFormula, "--json")
+	if err != nil {
+		return "", err
+	}
+	return parseWispIDFromJSON([]byte(out))
+}
+
+func (v *verifyInstallRun) mergeBranch() (string, error) {
+	if err := doltserver.CommitServerWorkingSet(v.town, verifyInstallRig, "verify-install: before branch"); err != nil {
+		return "", err
+	}
+	branch := doltserver.PolecatBranchName("verify")
+	if err := doltserver.CreatePolecatBranch(v.town, verifyInstallRig, branch); err != nil {
+		return "", err
+	}
+	if _, err := v.bd([]string{"BD_BRANCH=" + branch}, "update", v.beadID, "--status", "in_progress"); err != nil {
+		doltserver.DeletePolecatBranch(v.town, verifyInstallRig, branch)
+		return "", err
+	}
+	if err := doltserver.MergePolecatBranch(v.town, verifyInstallRig, branch); err != nil {
+		return "", err
+	}
+	issue, err := beads.NewWithBeadsDir(filepath.Dir(v.beadsDir), v.beadsDir).Show(v.beadID)
+	if err != nil {
+		return "", err
+	}
+	if issue.Status != "in_progress" {
+		return "", fmt.Errorf("%s is %s on main after merge, want in_progress", v.beadID, issue.Status)
+	}
+	return branch + " merged", nil
+}
+
+func (v *verifyInstallRun) costEntry() (string, error) {
+	usage := &TokenUsage{Model: "default", InputTokens: 1_000_000, OutputTokens: 100_000}
+	entry := CostLogEntry{
+		SessionID:    "verify-install",
+		Role:         "polecat",
+		Rig:          verifyInstallRig,
+		CostUSD:      calculateCost(usage),
+		EndedAt:      time.Now().UTC(),
+		WorkItem:     v.beadID,
+		Model:        usage.Model,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+	}
+	if entry.CostUSD <= 0 {
+		return "", fmt.Errorf("default pricing gave $%.2f for %d input tokens", entry.CostUSD, usage.InputTokens)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	ledger := filepath.Join(v.town, "costs.jsonl")
+	if err := os.WriteFile(ledger, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: scratch ledger
+		return "", err
+	}
+	raw, err := os.ReadFile(ledger)
+	if err != nil {
+		return "", err
+	}
+	var got CostLogEntry
+	if err := json.Unmarshal(raw, &got); err != nil {
+		return "", fmt.Errorf("reading back cost entry: %w", err)
+	}
+	if got.CostUSD != entry.CostUSD || got.Usage() != *usage {
+		return "", fmt.Errorf("cost entry did not round-trip")
+	}
+	return fmt.Sprintf("$%.2f", got.CostUSD), nil
+}
+
+func (v *verifyInstallRun) cleanup() (string, error) {
+	if v.started {
+		if err := doltserver.Stop(v.town); err != nil {
+			return "", fmt.Errorf("stopping scratch server: %w", err)
+		}
+	}
+	if verifyInstallKeep {
+		return "kept " + v.town, nil
+	}
+	if err := os.RemoveAll(v.town); err != nil {
+		return "", fmt.Errorf("removing scratch town: %w", err)
+	}
+	return "", nil
+}
+
+// verifyFreePort asks the kernel for an unused local TCP port.
+func verifyFreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func printVerifyInstallReport(r *verifyInstallReport) {
+	for _, s := range r.Stages {
+		took := style.Dim.Render(fmt.Sprintf("%6s", s.Duration.Round(10*time.Millisecond)))
+		switch {
+		case s.Skipped:
+			fmt.Printf("  %s %-9s %6s %s\n", style.Dim.Render("○"), s.Name, "", style.Dim.Render("("+s.Detail+")"))
+		case s.OK && s.Detail != "":
+			fmt.Printf("  %s %-9s %s %s\n", style.Success.Render("✓"), s.Name, took, style.Dim.Render(s.Detail))
+		case s.OK:
+			fmt.Printf("  %s %-9s %s\n", style.Success.Render("✓"), s.Name, took)
+		default:
+			fmt.Printf("  %s %-9s %s %s\n", style.Error.Render("✗"), s.Name, took, s.Detail)
+		}
+	}
+
+	fmt.Println()
+	if r.Passed {
+		fmt.Printf("%s Install verified in %s\n", style.Success.Render("✓"), r.Duration.Round(100*time.Millisecond))
+	} else {
+		fmt.Printf("%s Install verification failed\n", style.Error.Render("✗"))
+	}
+}
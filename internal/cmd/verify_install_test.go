@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunVerifySteps_AllPass(t *testing.T) {
+	var ran []string
+	step := func(name string) verifyStep {
+		return verifyStep{name, func() (string, error) {
+			ran = append(ran, name)
+			return name + " ok", nil
+		}}
+	}
+
+	stages := runVerifySteps([]verifyStep{step("a"), step("b")}, step("cleanup"))
+
+	if len(ran) != 3 || ran[2] != "cleanup" {
+		t.Fatalf("ran = %v, want a, b, cleanup", ran)
+	}
+	for _, s := range stages {
+		if !s.OK || s.Skipped || s.Detail != s.Name+" ok" {
+			t.Errorf("stage %+v, want OK with detail", s)
+		}
+	}
+}
+
+func TestRunVerifySteps_FailureSkipsRestButCleansUp(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) verifyStep {
+		return verifyStep{name, func() (string, error) {
+			ran = append(ran, name)
+			return "", err
+		}}
+	}
+
+	stages := runVerifySteps([]verifyStep{
+		step("a", nil),
+		step("b", errors.New("boom")),
+		step("c", nil),
+	}, step("cleanup", nil))
+
+	if want := []string{"a", "b", "cleanup"}; len(ran) != len(want) || ran[0] != want[0] || ran[1] != want[1] || ran[2] != want[2] {
+		t.Fatalf("ran = %v, want %v", ran, want)
+	}
+	if len(stages) != 4 {
+		t.Fatalf("got %d stages, want 4", len(stages))
+	}
+	if b := stages[1]; b.OK || b.Detail != "boom" {
+		t.Errorf("stage b = %+v, want failed with error detail", b)
+	}
+	if c := stages[2]; !c.Skipped || c.Detail != "after b failed" {
+		t.Errorf("stage c = %+v, want skipped after b", c)
+	}
+	if cleanup := stages[3]; !cleanup.OK || cleanup.Skipped {
+		t.Errorf("cleanup = %+v, want run and OK", cleanup)
+	}
+}
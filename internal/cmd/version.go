@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/version"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Version information - set at build time via ldflags
@@ -42,6 +44,7 @@ and the git revision the binary was built from (if available).`,
 		} else {
 			fmt.Printf("gt version %s (%s)\n", Version, Build)
 		}
+		printTownPin(commit)
 	},
 }
 
@@ -54,6 +57,28 @@ func init() {
 	}
 }
 
+// printTownPin shows the town's pinned gt version, if any, and whether
+// this binary matches it.
+func printTownPin(commit string) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	pin, err := version.LoadPin(townRoot)
+	if err != nil || pin == nil {
+		return
+	}
+	pinned := pin.Version
+	if pin.Commit != "" {
+		pinned += "@" + version.ShortCommit(pin.Commit)
+	}
+	if version.CheckPin(pin, Version, commit) == nil {
+		fmt.Printf("town pin: %s (matches)\n", pinned)
+	} else {
+		fmt.Printf("town pin: %s (%s)\n", pinned, style.Warning.Render("this binary differs"))
+	}
+}
+
 func resolveCommitHash() string {
 	if Commit != "" {
 		return Commit
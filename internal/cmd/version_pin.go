@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/version"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// pinRedirectEnv is set when gt re-execs the town's pinned binary, so the
+// pinned binary never redirects again (even if the manifest is out of date).
+const pinRedirectEnv = "GT_PIN_REDIRECTED"
+
+var (
+	versionPinBinary   string
+	versionPinRedirect bool
+)
+
+var versionPinCmd = &cobra.Command{
+	Use:   "pin [version]",
+	Short: "Pin the gt version this town expects",
+	Long: `Record in the town which gt version agents are expected to run.
+
+The pin is written to mayor/gt-version.json. Every gt command run inside the
+town compares itself with it and warns when it is a different version (or,
+for the same version, a different build commit), which catches agents that
+invoke an older system-wide gt.
+
+Without an argument, pins the version and commit of the running binary.
+
+With --binary, records a town-local gt binary. With --redirect as well, gt
+commands whose version doesn't match re-exec that binary instead of warning.
+
+Examples:
+  gt version pin                                   # Pin this binary's version
+  gt version pin 0.7.0                             # Pin an explicit version
+  gt version pin --binary bin/gt --redirect        # Prefer the town's own gt
+  gt version unpin`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVersionPin,
+}
+
+var versionUnpinCmd = &cobra.Command{
+	Use:   "unpin",
+	Short: "Remove the town's pinned gt version",
+	Args:  cobra.NoArgs,
+	RunE:  runVersionUnpin,
+}
+
+func init() {
+	versionPinCmd.Flags().StringVar(&versionPinBinary, "binary", "", "Town-local gt binary (relative to the town root or absolute)")
+	versionPinCmd.Flags().BoolVar(&versionPinRedirect, "redirect", false, "Re-exec the town-local binary when a different gt version runs")
+	versionCmd.AddCommand(versionPinCmd)
+	versionCmd.AddCommand(versionUnpinCmd)
+}
+
+func runVersionPin(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pin := &version.Pin{Version: Version, Commit: resolveCommitHash(), PinnedAt: time.Now().UTC()}
+	if len(args) == 1 {
+		pin.Version = strings.TrimPrefix(args[0], "v")
+		pin.Commit = "" // An explicit version pins no particular build
+	}
+
+	if versionPinBinary != "" {
+		binary := versionPinBinary
+		if !filepath.IsAbs(binary) {
+			binary = filepath.Join(townRoot, binary)
+		}
+		info, err := os.Stat(binary)
+		if err != nil {
+			return fmt.Errorf("town-local binary: %w", err)
+		}
+		if info.IsDir() || info.Mode()&0111 == 0 {
+			return fmt.Errorf("town-local binary %s is not an executable file", binary)
+		}
+		if rel, err := filepath.Rel(townRoot, binary); err == nil && !strings.HasPrefix(rel, "..") {
+			binary = rel // Keep the manifest portable when the town moves
+		}
+		pin.Binary = binary
+	}
+	if versionPinRedirect {
+		if pin.Binary == "" {
+			return fmt.Errorf("--redirect needs a town-local binary: pass --binary")
+		}
+		pin.Redirect = true
+	}
+
+	if err := version.SavePin(townRoot, pin); err != nil {
+		return fmt.Errorf("writing %s: %w", version.PinFile, err)
+	}
+
+	pinned := pin.Version
+	if pin.Commit != "" {
+		pinned += "@" + version.ShortCommit(pin.Commit)
+	}
+	fmt.Printf("%s Pinned gt %s for this town\n", style.Success.Render("✓"), pinned)
+	if pin.Binary != "" {
+		mode := "warn on mismatch"
+		if pin.Redirect {
+			mode = "redirect on mismatch"
+		}
+		fmt.Printf("  Town binary: %s (%s)\n", pin.Binary, mode)
+	}
+	return nil
+}
+
+func runVersionUnpin(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := version.RemovePin(townRoot); err != nil {
+		return fmt.Errorf("removing %s: %w", version.PinFile, err)
+	}
+	fmt.Printf("%s Town gt version unpinned\n", style.Success.Render("✓"))
+	return nil
+}
+
+// isVersionCommand reports whether cmd is gt version or one of its
+// subcommands, which must run with any binary so a pin can be fixed.
+func isVersionCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == versionCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPinnedVersion compares the running binary with the town's pinned
+// version. On a mismatch it re-execs the town-local binary if the pin asks
+// for that, and otherwise (or if that fails) prints a warning. Never blocks.
+func checkPinnedVersion(townRoot string) {
+	pin, err := version.LoadPin(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s town version pin: %v\n", style.WarningPrefix, err)
+		return
+	}
+	mismatch := version.CheckPin(pin, Version, resolveCommitHash())
+	if mismatch == nil {
+		return
+	}
+
+	if pin.Redirect && os.Getenv(pinRedirectEnv) == "" {
+		err := execPinnedBinary(pin.BinaryPath(townRoot))
+		// Only returns on failure
+		fmt.Fprintf(os.Stderr, "%s could not switch to the town's gt binary: %v\n", style.WarningPrefix, err)
+	}
+
+	relation := "differs from"
+	if mismatch.Older {
+		relation = "is older than"
+	}
+	fmt.Fprintf(os.Stderr, "%s gt binary %s %s the town's pinned version %s\n",
+		style.WarningPrefix, mismatch.Running, relation, mismatch.Pinned)
+	if bin := pin.BinaryPath(townRoot); bin != "" {
+		fmt.Fprintf(os.Stderr, "    %s Use the town's binary: %s\n", style.ArrowPrefix, bin)
+	} else {
+		fmt.Fprintf(os.Stderr, "    %s Install gt %s, or re-pin with 'gt version pin'\n", style.ArrowPrefix, mismatch.Pinned)
+	}
+}
+
+// execPinnedBinary replaces this process with the town-local gt binary,
+// passing the same arguments. Returns only on failure.
+func execPinnedBinary(binary string) error {
+	if binary == "" {
+		return fmt.Errorf("no town-local binary in %s", version.PinFile)
+	}
+	target, err := os.Stat(binary)
+	if err != nil {
+		return err
+	}
+	if self, err := os.Executable(); err == nil {
+		if info, err := os.Stat(self); err == nil && os.SameFile(info, target) {
+			return fmt.Errorf("%s is this binary; the pin is out of date", binary)
+		}
+	}
+
+	if err := os.Setenv(pinRedirectEnv, "1"); err != nil {
+		return err
+	}
+	args := append([]string{binary}, os.Args[1:]...)
+	return syscall.Exec(binary, args, os.Environ())
+}
@@ -520,6 +520,23 @@ func BuildResumeCommand(agentName, sessionID string) string {
 	}
 }
 
+// BuildResumeCommandWithConfig builds a command that resumes sessionID using
+// rc's command and args, so a session can be picked up under a different
+// agent config (e.g. a cheaper model) than the one that started it.
+// Returns empty string if rc's provider doesn't support flag-style resume.
+func BuildResumeCommandWithConfig(rc *RuntimeConfig, sessionID, prompt string) string {
+	if sessionID == "" {
+		return ""
+	}
+	resolved := normalizeRuntimeConfig(rc)
+	info := GetAgentPresetByName(resolved.Provider)
+	if info == nil || info.ResumeFlag == "" || info.ResumeStyle == "subcommand" {
+		return ""
+	}
+	resolved.Args = append(append([]string(nil), resolved.Args...), info.ResumeFlag, sessionID)
+	return resolved.BuildCommandWithPrompt(prompt)
+}
+
 // SupportsSessionResume checks if an agent supports session resumption.
 func SupportsSessionResume(agentName string) bool {
 	info := GetAgentPresetByName(agentName)
@@ -341,6 +341,33 @@ func TestBuildResumeCommand(t *testing.T) {
 	}
 }
 
+func TestBuildResumeCommandWithConfig(t *testing.T) {
+	t.Parallel()
+	rc := &RuntimeConfig{
+		Provider: "claude",
+		Command:  "claude",
+		Args:     []string{"--dangerously-skip-permissions", "--model", "sonnet"},
+	}
+
+	result := BuildResumeCommandWithConfig(rc, "session-123", "resume prompt")
+	for _, s := range []string{"claude", "--model sonnet", "--resume session-123", "resume prompt"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("BuildResumeCommandWithConfig() = %q, missing %q", result, s)
+		}
+	}
+	if len(rc.Args) != 3 {
+		t.Errorf("BuildResumeCommandWithConfig mutated rc.Args: %v", rc.Args)
+	}
+
+	if got := BuildResumeCommandWithConfig(rc, "", "prompt"); got != "" {
+		t.Errorf("empty session ID: got %q, want empty", got)
+	}
+	codex := &RuntimeConfig{Provider: "codex", Command: "codex"}
+	if got := BuildResumeCommandWithConfig(codex, "codex-sess", "prompt"); got != "" {
+		t.Errorf("subcommand-style resume: got %q, want empty", got)
+	}
+}
+
 func TestSupportsSessionResume(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
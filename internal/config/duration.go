@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// day is the unit of the "d" suffix ParseDuration accepts.
+const day = 24 * time.Hour
+
+// Duration is a time.Duration in a config file. It is written as a
+// human-friendly string ("30s", "5m", "1h30m", "2d") and read from one; in
+// JSON it also accepts a number of nanoseconds, which is how config files
+// written before it existed stored durations.
+type Duration struct {
+	time.Duration
+}
+
+// Dur returns d as a Duration, for config literals.
+func Dur(d time.Duration) Duration {
+	return Duration{Duration: d}
+}
+
+// Or returns the duration, or fallback when it is unset (zero).
+func (d Duration) Or(fallback time.Duration) time.Duration {
+	if d.Duration <= 0 {
+		return fallback
+	}
+	return d.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// UnmarshalJSON accepts a duration string or a legacy nanosecond count.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s: want a string such as \"5m\"", data)
+	}
+	if n < 0 {
+		return fmt.Errorf("invalid duration %s: must not be negative", data)
+	}
+	d.Duration = time.Duration(n)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler for Duration.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(FormatDuration(d.Duration)), nil
+}
+
+// String returns the duration as FormatDuration writes it.
+func (d Duration) String() string {
+	return FormatDuration(d.Duration)
+}
+
+// ParseDuration parses a config duration: anything time.ParseDuration
+// accepts ("90s", "1h30m"), optionally led by a number of days ("2d",
+// "1d12h"). Negative durations are rejected.
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %q: must not be negative", strings.TrimSpace(s))
+	}
+	return d, nil
+}
+
+// parseDuration is ParseDuration without the sign check.
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	rest := s
+	var days time.Duration
+	if i := strings.IndexByte(rest, 'd'); i > 0 {
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days = time.Duration(n) * day
+		rest = rest[i+1:]
+	}
+	var d time.Duration
+	if rest != "" || days == 0 {
+		var err error
+		if d, err = time.ParseDuration(rest); err != nil {
+			return 0, fmt.Errorf("invalid duration %q: want a value such as 30s, 5m, 1h30m or 2d", s)
+		}
+	}
+	if days < 0 {
+		return days - d, nil
+	}
+	return days + d, nil
+}
+
+// FormatDuration writes d the way ParseDuration reads it, without zero
+// units: 5m, 1h30m, 2d, 1d6h. Durations that aren't whole seconds use
+// time.Duration's format.
+func FormatDuration(d time.Duration) string {
+	if d <= 0 || d%time.Second != 0 {
+		return d.String()
+	}
+	var b strings.Builder
+	for _, u := range []struct {
+		unit   time.Duration
+		suffix string
+	}{{day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if n := d / u.unit; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, u.suffix)
+			d -= n * u.unit
+		}
+	}
+	return b.String()
+}
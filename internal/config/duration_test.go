@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30s", 30 * time.Second, false},
+		{"5m", 5 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"2d", 48 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{" 10m ", 10 * time.Minute, false},
+		{"0", 0, false},
+		{"-5m", 0, true},
+		{"-1d", 0, true},
+		{"5 minutes", 0, true},
+		{"15", 0, true},
+		{"xd", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v, err %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input time.Duration
+		want  string
+	}{
+		{5 * time.Minute, "5m"},
+		{90 * time.Minute, "1h30m"},
+		{48 * time.Hour, "2d"},
+		{30*time.Hour + 15*time.Second, "1d6h15s"},
+		{500 * time.Millisecond, "500ms"},
+		{0, "0s"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.input); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+		if got, err := ParseDuration(FormatDuration(tt.input)); err != nil || got != tt.input {
+			t.Errorf("round trip of %v = %v, %v", tt.input, got, err)
+		}
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	t.Parallel()
+	var cfg struct {
+		Interval Duration `json:"interval"`
+		Legacy   Duration `json:"legacy"`
+		Unset    Duration `json:"unset"`
+	}
+	if err := json.Unmarshal([]byte(`{"interval":"1h30m","legacy":300000000000,"unset":null}`), &cfg); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if cfg.Interval.Duration != 90*time.Minute || cfg.Legacy.Duration != 5*time.Minute || cfg.Unset.Duration != 0 {
+		t.Errorf("got %+v", cfg)
+	}
+	if got := cfg.Unset.Or(time.Minute); got != time.Minute {
+		t.Errorf("Or on unset = %v, want 1m", got)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"interval":"1h30m","legacy":"5m","unset":"0s"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	for _, bad := range []string{`{"interval":"-5m"}`, `{"interval":-1}`, `{"interval":"soon"}`, `{"interval":1.5}`} {
+		if err := json.Unmarshal([]byte(bad), &cfg); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", bad)
+		}
+	}
+}
@@ -5,9 +5,52 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 )
 
+// EnvContractVersion is the version of the agent environment contract: the
+// variables AgentEnv gives every agent session. It is exported to sessions
+// as GT_ENV_CONTRACT so gt env doctor can tell a session started under an
+// older contract from a misconfigured one. Bump it whenever a variable is
+// added, removed, or changes meaning, and update the table below.
+//
+// Version 1:
+//
+//	GT_ENV_CONTRACT             all roles: the contract version
+//	GT_ROLE                     all roles: mayor, deacon, deacon/boot, <rig>/witness,
+//	                            <rig>/refinery, <rig>/polecats/<name>, <rig>/crew/<name>
+//	BD_ACTOR, GIT_AUTHOR_NAME   all roles: who beads and git record as the actor
+//	GT_RIG                      rig-level roles
+//	GT_POLECAT, GT_CREW         polecats, crew: the agent's name
+//	BEADS_AGENT_NAME            polecats and crew: <rig>/<name>
+//	GT_ROOT                     when the town root is known
+//	GIT_CEILING_DIRECTORIES     when the town root is known: stops git at the town
+//	GT_DOLT_HOST, GT_DOLT_PORT,
+//	GT_DOLT_USER                when the town root is known: the town's Dolt server
+//	BD_DOLT_AUTO_COMMIT=off     polecats: changes merge at gt done instead
+//	BD_BRANCH                   polecats with a Dolt branch (branch-per-polecat)
+//	GT_BRANCH, GT_POLECAT_PATH  polecats: git branch and worktree, for gt done
+//	                            when the worktree is already gone
+//	CLAUDE_CONFIG_DIR           when a runtime config dir is configured
+//	GT_SESSION_ID_ENV           when the runtime reports its session ID
+//	NODE_OPTIONS=""             all roles: cleared, see AgentEnv
+//
+// The variables in ForbiddenAgentEnv must not be set.
+const EnvContractVersion = 1
+
+// ForbiddenAgentEnv lists variables agent sessions must not have set.
+// BEADS_DIR pins bd to one database, which breaks prefix-based routing
+// across rigs.
+var ForbiddenAgentEnv = []string{"BEADS_DIR"}
+
+// DefaultDoltPort and DefaultDoltUser are how agents reach the town's Dolt
+// server when town settings don't override them.
+const (
+	DefaultDoltPort = 3307
+	DefaultDoltUser = "root"
+)
+
 // AgentEnvConfig specifies the configuration for generating agent environment variables.
 // This is the single source of truth for all agent environment configuration.
 type AgentEnvConfig struct {
@@ -31,12 +74,23 @@ type AgentEnvConfig struct {
 	// SessionIDEnv is the environment variable name that holds the session ID.
 	// Sets GT_SESSION_ID_ENV so the runtime knows where to find the session ID.
 	SessionIDEnv string
+
+	// DoltBranch is the polecat's Dolt branch (sets BD_BRANCH).
+	DoltBranch string
+
+	// GitBranch is the polecat's git branch (sets GT_BRANCH).
+	GitBranch string
+
+	// WorkDir is the polecat's worktree (sets GT_POLECAT_PATH).
+	WorkDir string
 }
 
 // AgentEnv returns all environment variables for an agent based on the config.
 // This is the single source of truth for agent environment variables.
+// See EnvContractVersion for the variables and when each is set.
 func AgentEnv(cfg AgentEnvConfig) map[string]string {
 	env := make(map[string]string)
+	env["GT_ENV_CONTRACT"] = strconv.Itoa(EnvContractVersion)
 
 	// Set role-specific variables
 	// GT_ROLE is set in compound format (e.g., "beads/crew/jane") so that
@@ -80,6 +134,17 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		// via DOLT_MERGE. Without this, concurrent polecats cause manifest
 		// contention leading to Dolt read-only mode (gt-5cc2p).
 		env["BD_DOLT_AUTO_COMMIT"] = "off"
+		if cfg.DoltBranch != "" {
+			env["BD_BRANCH"] = cfg.DoltBranch
+		}
+		// GT_BRANCH and GT_POLECAT_PATH let gt done find the branch and
+		// worktree when the polecat's cwd was deleted before it finished.
+		if cfg.GitBranch != "" {
+			env["GT_BRANCH"] = cfg.GitBranch
+		}
+		if cfg.WorkDir != "" {
+			env["GT_POLECAT_PATH"] = cfg.WorkDir
+		}
 
 	case "crew":
 		env["GT_ROLE"] = fmt.Sprintf("%s/crew/%s", cfg.Rig, cfg.AgentName)
@@ -97,6 +162,11 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		// This stops accidental commits to the umbrella when running git commands from
 		// intermediate directories (e.g., polecats/) that don't have their own .git.
 		env["GIT_CEILING_DIRECTORIES"] = cfg.TownRoot
+
+		port, user := DoltServerSettings(cfg.TownRoot).Endpoint()
+		env["GT_DOLT_HOST"] = "127.0.0.1"
+		env["GT_DOLT_PORT"] = strconv.Itoa(port)
+		env["GT_DOLT_USER"] = user
 	}
 
 	// Set BEADS_AGENT_NAME for polecat/crew (uses same format as BD_ACTOR)
@@ -127,6 +197,41 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 	return env
 }
 
+// CheckAgentEnv compares an agent's actual environment against the expected
+// contract from AgentEnv and returns one problem per missing or mismatched
+// variable, sorted by variable name. Forbidden variables are reported by
+// ForbiddenEnvSet.
+func CheckAgentEnv(expected, actual map[string]string) []string {
+	keys := make([]string, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, k := range keys {
+		actualVal, exists := actual[k]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("missing %s (expected %q)", k, expected[k]))
+		} else if actualVal != expected[k] {
+			problems = append(problems, fmt.Sprintf("%s=%q (expected %q)", k, actualVal, expected[k]))
+		}
+	}
+	return problems
+}
+
+// ForbiddenEnvSet returns the variables of ForbiddenAgentEnv that are set
+// to a non-empty value in env.
+func ForbiddenEnvSet(env map[string]string) []string {
+	var set []string
+	for _, k := range ForbiddenAgentEnv {
+		if env[k] != "" {
+			set = append(set, k)
+		}
+	}
+	return set
+}
+
 // AgentEnvSimple is a convenience function for simple role-based env var lookup.
 // Use this when you only need role, rig, and agentName without advanced options.
 func AgentEnvSimple(role, rig, agentName string) map[string]string {
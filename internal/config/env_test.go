@@ -1,6 +1,10 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -169,6 +173,75 @@ func TestAgentEnv_EmptyTownRootOmitted(t *testing.T) {
 	// Other keys should still be set
 	assertEnv(t, env, "GT_ROLE", "myrig/polecats/Toast") // compound format
 	assertEnv(t, env, "GT_RIG", "myrig")
+	assertNotSet(t, env, "GT_DOLT_PORT")
+}
+
+func TestAgentEnv_ContractVersion(t *testing.T) {
+	t.Parallel()
+	for _, role := range []string{"mayor", "deacon", "boot", "witness", "refinery", "polecat", "crew"} {
+		env := AgentEnvSimple(role, "myrig", "Toast")
+		assertEnv(t, env, "GT_ENV_CONTRACT", strconv.Itoa(EnvContractVersion))
+	}
+}
+
+func TestAgentEnv_PolecatBranches(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{
+		Role:       "polecat",
+		Rig:        "myrig",
+		AgentName:  "Toast",
+		DoltBranch: "polecat-Toast-1700000000",
+		GitBranch:  "polecat/Toast",
+		WorkDir:    "/town/myrig/polecats/Toast",
+	})
+	assertEnv(t, env, "BD_BRANCH", "polecat-Toast-1700000000")
+	assertEnv(t, env, "GT_BRANCH", "polecat/Toast")
+	assertEnv(t, env, "GT_POLECAT_PATH", "/town/myrig/polecats/Toast")
+
+	// Branch and worktree only apply to polecats.
+	crew := AgentEnv(AgentEnvConfig{Role: "crew", Rig: "myrig", AgentName: "jane", DoltBranch: "x", WorkDir: "/w"})
+	assertNotSet(t, crew, "BD_BRANCH")
+	assertNotSet(t, crew, "GT_POLECAT_PATH")
+}
+
+func TestAgentEnv_DoltServer(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{Role: "mayor", TownRoot: t.TempDir()})
+	assertEnv(t, env, "GT_DOLT_HOST", "127.0.0.1")
+	assertEnv(t, env, "GT_DOLT_PORT", strconv.Itoa(DefaultDoltPort))
+	assertEnv(t, env, "GT_DOLT_USER", DefaultDoltUser)
+
+	townRoot := t.TempDir()
+	settings := `{"type": "town-settings", "version": 1, "dolt_server": {"port": 3400, "user": "gastown"}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(TownSettingsPath(townRoot), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env = AgentEnv(AgentEnvConfig{Role: "mayor", TownRoot: townRoot})
+	assertEnv(t, env, "GT_DOLT_PORT", "3400")
+	assertEnv(t, env, "GT_DOLT_USER", "gastown")
+}
+
+func TestCheckAgentEnv(t *testing.T) {
+	t.Parallel()
+	expected := map[string]string{"GT_ROLE": "mayor", "GT_ENV_CONTRACT": "1", "NODE_OPTIONS": ""}
+	actual := map[string]string{"GT_ROLE": "deacon", "NODE_OPTIONS": "", "BEADS_DIR": "/x/.beads"}
+
+	want := []string{
+		`missing GT_ENV_CONTRACT (expected "1")`,
+		`GT_ROLE="deacon" (expected "mayor")`,
+	}
+	if got := CheckAgentEnv(expected, actual); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckAgentEnv = %q, want %q", got, want)
+	}
+	if got := ForbiddenEnvSet(actual); !reflect.DeepEqual(got, []string{"BEADS_DIR"}) {
+		t.Errorf("ForbiddenEnvSet = %q, want [BEADS_DIR]", got)
+	}
+	if got := ForbiddenEnvSet(map[string]string{"BEADS_DIR": ""}); len(got) != 0 {
+		t.Errorf("empty BEADS_DIR should be allowed, got %q", got)
+	}
 }
 
 func TestShellQuote(t *testing.T) {
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FeatureFlag is a switch for a code path that is being rolled out or
+// retired. Each flag has a default, which town settings can override for
+// every rig and a rig's settings can override for that rig alone:
+//
+//	settings/config.json:       {"feature_flags": {"merge-queue": false}}
+//	<rig>/settings/config.json: {"feature_flags": {"merge-queue": true}}
+type FeatureFlag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+	Stage       string `json:"stage"` // FeatureStage*
+
+	// Note explains a deprecated flag: what replaces it and when it goes.
+	Note string `json:"note,omitempty"`
+}
+
+// Feature flag rollout stages.
+const (
+	FeatureStageExperimental = "experimental"
+	FeatureStageBeta         = "beta"
+	FeatureStageStable       = "stable"
+	FeatureStageDeprecated   = "deprecated"
+)
+
+// Feature flag names.
+const (
+	// FeatureDoltNativeSync allows rigs to switch to bd's dolt-native sync
+	// mode (gt sync mode set dolt-native).
+	FeatureDoltNativeSync = "dolt-native-sync"
+
+	// FeatureMergeQueue allows gt mq submit to queue branches for the
+	// refinery.
+	FeatureMergeQueue = "merge-queue"
+
+	// FeatureDoltPrewarm has the daemon warm a rig's database after it
+	// restarts the Dolt server.
+	FeatureDoltPrewarm = "dolt-prewarm"
+)
+
+// FeatureFlags lists every feature flag gt knows, by name.
+var FeatureFlags = []FeatureFlag{
+	{
+		Name:        FeatureDoltNativeSync,
+		Description: "Allow the dolt-native sync mode (no JSONL in git)",
+		Default:     true,
+		Stage:       FeatureStageBeta,
+	},
+	{
+		Name:        FeatureDoltPrewarm,
+		Description: "Warm rig databases after the daemon restarts the Dolt server",
+		Default:     true,
+		Stage:       FeatureStageBeta,
+	},
+	{
+		Name:        FeatureMergeQueue,
+		Description: "Submit branches to the refinery merge queue with gt mq submit",
+		Default:     true,
+		Stage:       FeatureStageStable,
+	},
+}
+
+// LookupFeatureFlag returns the named flag, or an error naming the known
+// flags.
+func LookupFeatureFlag(name string) (FeatureFlag, error) {
+	for _, f := range FeatureFlags {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	names := make([]string, 0, len(FeatureFlags))
+	for _, f := range FeatureFlags {
+		names = append(names, f.Name)
+	}
+	return FeatureFlag{}, fmt.Errorf("unknown feature flag %q (known: %s)", name, strings.Join(names, ", "))
+}
+
+// Feature flag sources, from highest to lowest precedence.
+const (
+	FeatureSourceRig     = "rig"
+	FeatureSourceTown    = "town"
+	FeatureSourceDefault = "default"
+)
+
+// FeatureFlagState is a flag's effective value for the town or one rig.
+type FeatureFlagState struct {
+	Name    string `json:"name"`
+	Rig     string `json:"rig,omitempty"` // Empty for the town-wide value
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"` // FeatureSource*
+	Stage   string `json:"stage"`
+}
+
+// ResolveFeatureFlag returns f's value given the town and rig overrides,
+// the rig's taking precedence. Either map may be nil.
+func ResolveFeatureFlag(f FeatureFlag, rig string, town, rigFlags map[string]bool) FeatureFlagState {
+	s := FeatureFlagState{Name: f.Name, Rig: rig, Enabled: f.Default, Source: FeatureSourceDefault, Stage: f.Stage}
+	if v, ok := town[f.Name]; ok {
+		s.Enabled, s.Source = v, FeatureSourceTown
+	}
+	if v, ok := rigFlags[f.Name]; ok {
+		s.Enabled, s.Source = v, FeatureSourceRig
+	}
+	return s
+}
+
+// FeatureEnabled reports whether the named flag is on for a rig (or, with
+// an empty rig, town-wide). Settings that can't be read leave the defaults
+// in place; unknown flags are off.
+func FeatureEnabled(townRoot, rig, name string) bool {
+	f, err := LookupFeatureFlag(name)
+	if err != nil {
+		return false
+	}
+	town, rigFlags := loadFeatureOverrides(townRoot, rig)
+	return ResolveFeatureFlag(f, rig, town, rigFlags).Enabled
+}
+
+// ResolveFeatureFlags returns every flag's town-wide value, each followed
+// by its value for the rigs (sorted) that override it.
+func ResolveFeatureFlags(townRoot string, rigs []string) []FeatureFlagState {
+	town, _ := loadFeatureOverrides(townRoot, "")
+	sorted := append([]string(nil), rigs...)
+	sort.Strings(sorted)
+	rigFlags := make(map[string]map[string]bool, len(sorted))
+	for _, rig := range sorted {
+		_, rigFlags[rig] = loadFeatureOverrides(townRoot, rig)
+	}
+
+	var states []FeatureFlagState
+	for _, f := range FeatureFlags {
+		states = append(states, ResolveFeatureFlag(f, "", town, nil))
+		for _, rig := range sorted {
+			if _, ok := rigFlags[rig][f.Name]; ok {
+				states = append(states, ResolveFeatureFlag(f, rig, town, rigFlags[rig]))
+			}
+		}
+	}
+	return states
+}
+
+// loadFeatureOverrides reads the feature flag overrides from town settings
+// and, if rig is set, the rig's settings.
+func loadFeatureOverrides(townRoot, rig string) (town, rigFlags map[string]bool) {
+	if settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil {
+		town = settings.FeatureFlags
+	}
+	if rig != "" {
+		if settings, err := LoadRigSettings(RigSettingsPath(filepath.Join(townRoot, rig))); err == nil {
+			rigFlags = settings.FeatureFlags
+		}
+	}
+	return town, rigFlags
+}
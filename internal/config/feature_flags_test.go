@@ -0,0 +1,72 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveFeatureFlag_Precedence(t *testing.T) {
+	f := FeatureFlag{Name: "x", Default: true, Stage: FeatureStageBeta}
+
+	if s := ResolveFeatureFlag(f, "", nil, nil); !s.Enabled || s.Source != FeatureSourceDefault {
+		t.Errorf("no overrides = %+v, want default on", s)
+	}
+	town := map[string]bool{"x": false}
+	if s := ResolveFeatureFlag(f, "gastown", town, nil); s.Enabled || s.Source != FeatureSourceTown {
+		t.Errorf("town override = %+v, want off from town", s)
+	}
+	rig := map[string]bool{"x": true}
+	if s := ResolveFeatureFlag(f, "gastown", town, rig); !s.Enabled || s.Source != FeatureSourceRig || s.Rig != "gastown" {
+		t.Errorf("rig override = %+v, want on from rig", s)
+	}
+}
+
+func TestLookupFeatureFlag(t *testing.T) {
+	if _, err := LookupFeatureFlag(FeatureMergeQueue); err != nil {
+		t.Errorf("LookupFeatureFlag(%s): %v", FeatureMergeQueue, err)
+	}
+	if _, err := LookupFeatureFlag("no-such-flag"); err == nil {
+		t.Error("expected error for unknown flag")
+	}
+}
+
+func TestFeatureEnabled_FromSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	town := NewTownSettings()
+	town.FeatureFlags = map[string]bool{FeatureMergeQueue: false}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+	rig := NewRigSettings()
+	rig.FeatureFlags = map[string]bool{FeatureMergeQueue: true}
+	if err := SaveRigSettings(RigSettingsPath(filepath.Join(townRoot, "gastown")), rig); err != nil {
+		t.Fatal(err)
+	}
+
+	if FeatureEnabled(townRoot, "", FeatureMergeQueue) {
+		t.Error("town-wide merge-queue should be off")
+	}
+	if FeatureEnabled(townRoot, "beads", FeatureMergeQueue) {
+		t.Error("rig without settings should inherit the town's off")
+	}
+	if !FeatureEnabled(townRoot, "gastown", FeatureMergeQueue) {
+		t.Error("rig override should turn merge-queue on")
+	}
+	if !FeatureEnabled(townRoot, "gastown", FeatureDoltPrewarm) {
+		t.Error("unset flag should take its default")
+	}
+	if FeatureEnabled(townRoot, "gastown", "no-such-flag") {
+		t.Error("unknown flag should be off")
+	}
+
+	states := ResolveFeatureFlags(townRoot, []string{"gastown", "beads"})
+	var rigStates []FeatureFlagState
+	for _, s := range states {
+		if s.Rig != "" {
+			rigStates = append(rigStates, s)
+		}
+	}
+	if len(states) != len(FeatureFlags)+1 || len(rigStates) != 1 || rigStates[0].Rig != "gastown" || rigStates[0].Name != FeatureMergeQueue {
+		t.Errorf("ResolveFeatureFlags = %+v, want every flag plus gastown's merge-queue override", states)
+	}
+}
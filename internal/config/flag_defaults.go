@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FlagDefaults maps a command path to default values for its flags:
+//
+//	{"mail inbox": {"json": "true"}, "patrol digest": {"rig": "gastown"}}
+//
+// The command path is the command as typed without the leading "gt". Flag
+// names are long names without dashes, and values are given as on the
+// command line.
+type FlagDefaults map[string]map[string]string
+
+// Flag default sources, from highest to lowest precedence. A flag given on
+// the command line always wins over all of them.
+const (
+	FlagDefaultSourceEnv  = "env"
+	FlagDefaultSourceTown = "town"
+	FlagDefaultSourceUser = "user"
+)
+
+// UserSettings is the per-user config file (~/.config/gastown/config.json).
+// It applies in every town; town settings take precedence over it.
+type UserSettings struct {
+	// Defaults sets default flag values per command. See FlagDefaults.
+	Defaults FlagDefaults `json:"defaults,omitempty"`
+}
+
+// LoadUserSettings loads user settings, returning empty settings if the
+// file does not exist.
+func LoadUserSettings(path string) (*UserSettings, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted config location
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &UserSettings{}, nil
+		}
+		return nil, fmt.Errorf("reading user settings: %w", err)
+	}
+	var settings UserSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("parsing user settings %s: %w", path, err)
+	}
+	return &settings, nil
+}
+
+// FlagDefaultEnvVar returns the environment variable that sets a default for
+// a command's flag: GT_DEFAULT_<COMMAND>_<FLAG>, upper-cased, with anything
+// other than letters and digits replaced by "_" (for "mail inbox" --json:
+// GT_DEFAULT_MAIL_INBOX_JSON).
+func FlagDefaultEnvVar(cmdPath, flag string) string {
+	name := strings.ToUpper(cmdPath + "_" + flag)
+	return "GT_DEFAULT_" + strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// EffectiveFlagDefault is the default in effect for one flag.
+type EffectiveFlagDefault struct {
+	Flag   string `json:"flag"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // FlagDefaultSource*
+
+	// Shadowed lists the lower-precedence sources that also set the flag.
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
+// ResolveFlagDefault returns the default for a command's flag, taking the
+// environment over town over user, or ok=false when none of them set it.
+func ResolveFlagDefault(cmdPath, flag string, town, user FlagDefaults) (d EffectiveFlagDefault, ok bool) {
+	type candidate struct {
+		source string
+		value  string
+		set    bool
+	}
+	envValue, envSet := os.LookupEnv(FlagDefaultEnvVar(cmdPath, flag))
+	townValue, townSet := town[cmdPath][flag]
+	userValue, userSet := user[cmdPath][flag]
+	candidates := []candidate{
+		{FlagDefaultSourceEnv, envValue, envSet},
+		{FlagDefaultSourceTown, townValue, townSet},
+		{FlagDefaultSourceUser, userValue, userSet},
+	}
+
+	for _, c := range candidates {
+		if !c.set {
+			continue
+		}
+		if !ok {
+			d = EffectiveFlagDefault{Flag: flag, Value: c.value, Source: c.source}
+			ok = true
+			continue
+		}
+		d.Shadowed = append(d.Shadowed, c.source)
+	}
+	return d, ok
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFlagDefaultEnvVar(t *testing.T) {
+	if got := FlagDefaultEnvVar("mail inbox", "json"); got != "GT_DEFAULT_MAIL_INBOX_JSON" {
+		t.Errorf("FlagDefaultEnvVar = %q", got)
+	}
+	if got := FlagDefaultEnvVar("patrol digest", "dry-run"); got != "GT_DEFAULT_PATROL_DIGEST_DRY_RUN" {
+		t.Errorf("FlagDefaultEnvVar = %q", got)
+	}
+}
+
+func TestResolveFlagDefault(t *testing.T) {
+	town := FlagDefaults{"stale": {"threshold": "8", "json": "true"}}
+	user := FlagDefaults{"stale": {"threshold": "4", "rig": "gastown"}}
+	t.Setenv(FlagDefaultEnvVar("stale", "json"), "false")
+
+	tests := []struct {
+		flag     string
+		want     string
+		source   string
+		shadowed string
+		ok       bool
+	}{
+		{"json", "false", FlagDefaultSourceEnv, "town", true},
+		{"threshold", "8", FlagDefaultSourceTown, "user", true},
+		{"rig", "gastown", FlagDefaultSourceUser, "", true},
+		{"quiet", "", "", "", false},
+	}
+	for _, tt := range tests {
+		d, ok := ResolveFlagDefault("stale", tt.flag, town, user)
+		if ok != tt.ok || d.Value != tt.want || d.Source != tt.source || strings.Join(d.Shadowed, ",") != tt.shadowed {
+			t.Errorf("ResolveFlagDefault(%q) = %+v, %v", tt.flag, d, ok)
+		}
+	}
+}
+
+func TestLoadUserSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	settings, err := LoadUserSettings(path)
+	if err != nil || len(settings.Defaults) != 0 {
+		t.Fatalf("missing file: %+v, %v", settings, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"defaults": {"mail inbox": {"json": "true"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings, err = LoadUserSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Defaults["mail inbox"]["json"] != "true" {
+		t.Errorf("Defaults = %v", settings.Defaults)
+	}
+
+	if err := os.WriteFile(path, []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadUserSettings(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -219,6 +220,37 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Spawn != nil {
+		if err := validateSpawnConfig(c.Spawn); err != nil {
+			return err
+		}
+	}
+	for key := range c.PromptVars {
+		if !promptVarKeyRe.MatchString(key) {
+			return fmt.Errorf("prompt var %q: key must be letters, digits, and underscores, not starting with a digit", key)
+		}
+	}
+	return nil
+}
+
+// promptVarKeyRe matches prompt var keys usable as {{ .Vars.key }}.
+var promptVarKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateSpawnConfig validates spawn checks.
+func validateSpawnConfig(c *SpawnConfig) error {
+	for i, check := range c.Checks {
+		if check.Name == "" {
+			return fmt.Errorf("spawn check %d: name is required", i)
+		}
+		if (check.Path == "") == (check.Run == "") {
+			return fmt.Errorf("spawn check %q: exactly one of path or run is required", check.Name)
+		}
+		if check.Timeout != "" {
+			if _, err := time.ParseDuration(check.Timeout); err != nil {
+				return fmt.Errorf("spawn check %q: invalid timeout: %w", check.Name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -428,6 +460,10 @@ func LoadDaemonPatrolConfig(path string) (*DaemonPatrolConfig, error) {
 		}
 		return nil, fmt.Errorf("reading daemon patrol config: %w", err)
 	}
+	data, err = ApplyProfile(daemonConfigTownRoot(path), ProfileSectionDaemon, data)
+	if err != nil {
+		return nil, fmt.Errorf("applying config profile: %w", err)
+	}
 
 	var config DaemonPatrolConfig
 	if err := json.Unmarshal(data, &config); err != nil {
@@ -865,7 +901,40 @@ func RigSettingsPath(rigPath string) string {
 }
 
 // LoadOrCreateTownSettings loads town settings or creates defaults if missing.
+// The active config profile (GT_PROFILE, or active_profile) is applied, so
+// the result is the effective settings. Code that edits and saves settings
+// must load them with LoadTownSettingsBase instead.
 func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
+	base, err := LoadTownSettingsBase(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := activeProfile(base)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || len(p.Settings) == 0 {
+		return base, nil
+	}
+
+	data, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergeSection(data, p.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("applying config profile: %w", err)
+	}
+	var settings TownSettings
+	if err := json.Unmarshal(merged, &settings); err != nil {
+		return nil, fmt.Errorf("applying config profile: %w", err)
+	}
+	return &settings, nil
+}
+
+// LoadTownSettingsBase loads town settings as written in the file, without
+// applying a config profile, or defaults if missing.
+func LoadTownSettingsBase(path string) (*TownSettings, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -881,6 +950,50 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	return &settings, nil
 }
 
+// doltServerCacheEntry is a cached dolt_server section, valid while the
+// settings file and the selected profile are unchanged.
+type doltServerCacheEntry struct {
+	modTime time.Time
+	size    int64
+	profile string
+	section *DoltServerConfig
+}
+
+var (
+	doltServerCacheMu sync.Mutex
+	doltServerCache   = map[string]doltServerCacheEntry{}
+)
+
+// DoltServerSettings returns the dolt_server section of a town's settings,
+// with the active profile applied, or nil if it is unset or the settings
+// can't be read. It sits on hot paths (every doltserver.DefaultConfig), so
+// loads are cached per file and revalidated against the file's
+// modification time and size. Callers get their own copy.
+func DoltServerSettings(townRoot string) *DoltServerConfig {
+	path := TownSettingsPath(townRoot)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	profile := os.Getenv(ProfileEnvVar)
+
+	doltServerCacheMu.Lock()
+	cached, ok := doltServerCache[path]
+	doltServerCacheMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() && cached.profile == profile {
+		return cached.section.clone()
+	}
+
+	settings, err := LoadOrCreateTownSettings(path)
+	if err != nil {
+		return nil
+	}
+	doltServerCacheMu.Lock()
+	doltServerCache[path] = doltServerCacheEntry{modTime: info.ModTime(), size: info.Size(), profile: profile, section: settings.DoltServer}
+	doltServerCacheMu.Unlock()
+	return settings.DoltServer.clone()
+}
+
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
 	if settings.Type != "town-settings" && settings.Type != "" {
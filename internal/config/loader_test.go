@@ -328,6 +328,36 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid spawn checks",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Spawn: &SpawnConfig{Checks: []SpawnCheck{
+					{Name: "deps", Path: "node_modules"},
+					{Name: "submodules", Run: "git submodule status | grep -v '^-'", Timeout: "30s"},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "spawn check with path and run",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Spawn:   &SpawnConfig{Checks: []SpawnCheck{{Name: "both", Path: ".env", Run: "true"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "spawn check without name",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Spawn:   &SpawnConfig{Checks: []SpawnCheck{{Path: ".env"}}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -372,6 +402,24 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid prompt vars",
+			settings: &RigSettings{
+				Type:       "rig-settings",
+				Version:    1,
+				PromptVars: map[string]string{"test_command": "make test", "Docs2": "docs/"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "prompt var key not an identifier",
+			settings: &RigSettings{
+				Type:       "rig-settings",
+				Version:    1,
+				PromptVars: map[string]string{"test-command": "make test"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2046,6 +2094,50 @@ func TestAddRigToDaemonPatrols(t *testing.T) {
 	})
 }
 
+func TestDoltServerSettings(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	if got := DoltServerSettings(townRoot); got != nil {
+		t.Fatalf("DoltServerSettings without settings = %+v, want nil", got)
+	}
+	if port, user := (*DoltServerConfig)(nil).Endpoint(); port != DefaultDoltPort || user != DefaultDoltUser {
+		t.Errorf("nil Endpoint = %d, %q", port, user)
+	}
+
+	path := TownSettingsPath(townRoot)
+	write := func(data string, mtime time.Time) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write(`{"type":"town-settings","version":1,"dolt_server":{"port":3400,"user":"gastown"}}`, start)
+
+	s := DoltServerSettings(townRoot)
+	if port, user := s.Endpoint(); port != 3400 || user != "gastown" {
+		t.Fatalf("Endpoint = %d, %q, want 3400, gastown", port, user)
+	}
+	// Callers get their own copy of the cached section.
+	s.Port = 1
+	if port, _ := DoltServerSettings(townRoot).Endpoint(); port != 3400 {
+		t.Errorf("modifying a returned section changed the cache: port %d", port)
+	}
+
+	// Rewriting the file invalidates the cache; out-of-range ports fall
+	// back to the default.
+	write(`{"type":"town-settings","version":1,"dolt_server":{"port":99999,"user":"gastown"}}`, start.Add(time.Minute))
+	if port, _ := DoltServerSettings(townRoot).Endpoint(); port != DefaultDoltPort {
+		t.Errorf("port after rewrite = %d, want default %d", port, DefaultDoltPort)
+	}
+}
+
 func TestSaveTownSettings(t *testing.T) {
 	t.Parallel()
 	t.Run("saves valid town settings", func(t *testing.T) {
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// ProfileEnvVar selects a town config profile, overriding active_profile in
+// settings/config.json. Set it to "none" to run without a profile.
+const ProfileEnvVar = "GT_PROFILE"
+
+// Config files a profile can overlay.
+const (
+	ProfileSectionSettings = "settings" // settings/config.json
+	ProfileSectionDaemon   = "daemon"   // mayor/daemon.json
+)
+
+// ConfigProfile is a named set of overrides for one environment (dev,
+// staging, prod). Each section is a JSON object merged over the matching
+// config file when the profile is active: objects merge key by key, and any
+// other value replaces the file's value.
+type ConfigProfile struct {
+	Description string `json:"description,omitempty"`
+
+	// Settings overrides settings/config.json.
+	Settings json.RawMessage `json:"settings,omitempty"`
+
+	// Daemon overrides mayor/daemon.json (patrol intervals, daemon settings).
+	Daemon json.RawMessage `json:"daemon,omitempty"`
+}
+
+// Section returns the profile's overrides for a config file, or nil.
+func (p *ConfigProfile) Section(section string) json.RawMessage {
+	switch section {
+	case ProfileSectionSettings:
+		return p.Settings
+	case ProfileSectionDaemon:
+		return p.Daemon
+	}
+	return nil
+}
+
+// ActiveProfileName returns the profile in effect for settings and where it
+// was selected: "env" (GT_PROFILE), "settings" (active_profile), or "" when
+// no profile is active.
+func ActiveProfileName(settings *TownSettings) (name, source string) {
+	if env, ok := os.LookupEnv(ProfileEnvVar); ok && env != "" {
+		if env == "none" {
+			return "", "env"
+		}
+		return env, "env"
+	}
+	if settings != nil && settings.ActiveProfile != "" {
+		return settings.ActiveProfile, "settings"
+	}
+	return "", ""
+}
+
+// Profile returns the named profile, or an error naming the defined ones.
+func (s *TownSettings) Profile(name string) (*ConfigProfile, error) {
+	if p, ok := s.Profiles[name]; ok && p != nil {
+		return p, nil
+	}
+	names := make([]string, 0, len(s.Profiles))
+	for n := range s.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("config profile %q not found: no profiles defined in settings/config.json", name)
+	}
+	return nil, fmt.Errorf("config profile %q not found (defined: %v)", name, names)
+}
+
+// activeProfile returns the active profile of the town whose base settings
+// are base, or nil when none is active.
+func activeProfile(base *TownSettings) (*ConfigProfile, error) {
+	name, source := ActiveProfileName(base)
+	if name == "" {
+		return nil, nil
+	}
+	p, err := base.Profile(name)
+	if err != nil {
+		if source == "env" {
+			return nil, fmt.Errorf("%w (selected by %s)", err, ProfileEnvVar)
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// ApplyProfile merges the active profile's overrides for section over data,
+// the raw contents of that config file. Returns data unchanged when no
+// profile is active or it doesn't override the section.
+func ApplyProfile(townRoot, section string, data []byte) ([]byte, error) {
+	base, err := LoadTownSettingsBase(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	p, err := activeProfile(base)
+	if err != nil || p == nil {
+		return data, err
+	}
+	return mergeSection(data, p.Section(section))
+}
+
+// daemonConfigTownRoot returns the town root of a mayor/daemon.json path.
+func daemonConfigTownRoot(path string) string {
+	return filepath.Dir(filepath.Dir(path))
+}
+
+// mergeSection merges a profile section over data. Profile bookkeeping keys
+// are ignored so a profile can't select or redefine profiles.
+func mergeSection(data []byte, overlay json.RawMessage) ([]byte, error) {
+	if len(bytes.TrimSpace(overlay)) == 0 {
+		return data, nil
+	}
+	var over map[string]interface{}
+	if err := json.Unmarshal(overlay, &over); err != nil {
+		return nil, fmt.Errorf("parsing profile overrides: %w", err)
+	}
+	delete(over, "profiles")
+	delete(over, "active_profile")
+
+	base := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &base); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(mergeJSONObjects(base, over))
+}
+
+// mergeJSONObjects merges over into base in place and returns base.
+func mergeJSONObjects(base, over map[string]interface{}) map[string]interface{} {
+	for k, v := range over {
+		if vm, ok := v.(map[string]interface{}); ok {
+			if bm, ok := base[k].(map[string]interface{}); ok {
+				base[k] = mergeJSONObjects(bm, vm)
+				continue
+			}
+		}
+		base[k] = v
+	}
+	return base
+}
+
+// ProfileChange is one effective setting a profile changes.
+type ProfileChange struct {
+	Section string `json:"section"`
+	Path    string `json:"path"`           // Dot-separated JSON path, e.g. dolt_stats.retention.archive_days
+	From    string `json:"from,omitempty"` // JSON value without the profile ("" if unset)
+	To      string `json:"to"`             // JSON value with the profile
+}
+
+// ProfileDiff returns the settings profile name changes in the town's
+// config files, relative to running without a profile.
+func ProfileDiff(townRoot, name string) ([]ProfileChange, error) {
+	settingsPath := TownSettingsPath(townRoot)
+	base, err := LoadTownSettingsBase(settingsPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	p, err := base.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{
+		ProfileSectionSettings: settingsPath,
+		ProfileSectionDaemon:   DaemonPatrolConfigPath(townRoot),
+	}
+	var changes []ProfileChange
+	for _, section := range []string{ProfileSectionSettings, ProfileSectionDaemon} {
+		data, err := os.ReadFile(files[section]) //nolint:gosec // G304: path is constructed internally
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		merged, err := mergeSection(data, p.Section(section))
+		if err != nil {
+			return nil, fmt.Errorf("profile %s, %s: %w", name, section, err)
+		}
+		var before, after interface{}
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := json.Unmarshal(data, &before); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", files[section], err)
+			}
+		}
+		if err := json.Unmarshal(merged, &after); err != nil {
+			return nil, err
+		}
+		for _, c := range diffJSON("", before, after) {
+			c.Section = section
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// diffJSON returns the leaf values that differ between two decoded JSON
+// documents, sorted by path.
+func diffJSON(path string, before, after interface{}) []ProfileChange {
+	am, aok := after.(map[string]interface{})
+	bm, _ := before.(map[string]interface{})
+	if aok && (bm != nil || before == nil) {
+		keys := make([]string, 0, len(am))
+		for k := range am {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var changes []ProfileChange
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			changes = append(changes, diffJSON(p, bm[k], am[k])...)
+		}
+		return changes
+	}
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	c := ProfileChange{Path: path, To: compactJSON(after)}
+	if before != nil {
+		c.From = compactJSON(before)
+	}
+	return []ProfileChange{c}
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
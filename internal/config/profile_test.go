@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const profileTownSettings = `{
+  "type": "town-settings",
+  "version": 1,
+  "default_agent": "claude",
+  "cli_theme": "dark",
+  "dolt_stats": {"retention": {"closed_wisp_days": 7}},
+  "profiles": {
+    "dev": {
+      "description": "laptop",
+      "settings": {
+        "default_agent": "claude-haiku",
+        "dolt_stats": {"retention": {"archive_days": 1}},
+        "active_profile": "prod"
+      },
+      "daemon": {"heartbeat": {"interval": "10m"}}
+    },
+    "prod": {}
+  },
+  "active_profile": "prod"
+}`
+
+func writeProfileTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for path, data := range map[string]string{
+		TownSettingsPath(townRoot):       profileTownSettings,
+		DaemonPatrolConfigPath(townRoot): `{"type": "daemon-patrol-config", "version": 1, "heartbeat": {"enabled": true, "interval": "3m"}}`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+func TestLoadOrCreateTownSettings_AppliesProfile(t *testing.T) {
+	townRoot := writeProfileTown(t)
+	t.Setenv(ProfileEnvVar, "dev")
+
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings: %v", err)
+	}
+	if settings.DefaultAgent != "claude-haiku" || settings.CLITheme != "dark" {
+		t.Errorf("default_agent=%q cli_theme=%q, want claude-haiku and dark", settings.DefaultAgent, settings.CLITheme)
+	}
+	r := settings.DoltStats.Retention
+	if r.ArchiveDays != 1 || r.ClosedWispDays != 7 {
+		t.Errorf("retention = %+v, want archive_days from profile and closed_wisp_days kept", r)
+	}
+	if settings.ActiveProfile != "prod" {
+		t.Errorf("profile overrode active_profile: %q", settings.ActiveProfile)
+	}
+
+	base, err := LoadTownSettingsBase(TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.DefaultAgent != "claude" {
+		t.Errorf("base default_agent = %q, want claude", base.DefaultAgent)
+	}
+}
+
+func TestLoadOrCreateTownSettings_ProfileSelection(t *testing.T) {
+	townRoot := writeProfileTown(t)
+	path := TownSettingsPath(townRoot)
+
+	// active_profile (prod) has no settings overrides.
+	t.Setenv(ProfileEnvVar, "")
+	settings, err := LoadOrCreateTownSettings(path)
+	if err != nil || settings.DefaultAgent != "claude" {
+		t.Errorf("with prod: default_agent=%q err=%v", settings.DefaultAgent, err)
+	}
+
+	t.Setenv(ProfileEnvVar, "none")
+	if name, source := ActiveProfileName(settings); name != "" || source != "env" {
+		t.Errorf("GT_PROFILE=none: active = %q from %q", name, source)
+	}
+
+	t.Setenv(ProfileEnvVar, "staging")
+	if _, err := LoadOrCreateTownSettings(path); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestLoadDaemonPatrolConfig_AppliesProfile(t *testing.T) {
+	townRoot := writeProfileTown(t)
+	t.Setenv(ProfileEnvVar, "dev")
+
+	cfg, err := LoadDaemonPatrolConfig(DaemonPatrolConfigPath(townRoot))
+	if err != nil {
+		t.Fatalf("LoadDaemonPatrolConfig: %v", err)
+	}
+	if cfg.Heartbeat == nil || cfg.Heartbeat.Interval != "10m" || !cfg.Heartbeat.Enabled {
+		t.Errorf("heartbeat = %+v, want enabled with the profile's 10m interval", cfg.Heartbeat)
+	}
+}
+
+func TestProfileDiff(t *testing.T) {
+	townRoot := writeProfileTown(t)
+
+	changes, err := ProfileDiff(townRoot, "dev")
+	if err != nil {
+		t.Fatalf("ProfileDiff: %v", err)
+	}
+	want := []ProfileChange{
+		{Section: ProfileSectionSettings, Path: "default_agent", From: `"claude"`, To: `"claude-haiku"`},
+		{Section: ProfileSectionSettings, Path: "dolt_stats.retention.archive_days", To: "1"},
+		{Section: ProfileSectionDaemon, Path: "heartbeat.interval", From: `"3m"`, To: `"10m"`},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	if _, err := ProfileDiff(townRoot, "staging"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -75,31 +74,6 @@ type RoleHealthConfig struct {
 	StuckThreshold Duration `toml:"stuck_threshold"`
 }
 
-// Duration is a wrapper for time.Duration that supports TOML marshaling.
-type Duration struct {
-	time.Duration
-}
-
-// UnmarshalText implements encoding.TextUnmarshaler for Duration.
-func (d *Duration) UnmarshalText(text []byte) error {
-	parsed, err := time.ParseDuration(string(text))
-	if err != nil {
-		return fmt.Errorf("invalid duration %q: %w", string(text), err)
-	}
-	d.Duration = parsed
-	return nil
-}
-
-// MarshalText implements encoding.TextMarshaler for Duration.
-func (d Duration) MarshalText() ([]byte, error) {
-	return []byte(d.Duration.String()), nil
-}
-
-// String returns the duration as a string.
-func (d Duration) String() string {
-	return d.Duration.String()
-}
-
 // AllRoles returns the list of all known role names.
 func AllRoles() []string {
 	return []string{"mayor", "deacon", "dog", "witness", "refinery", "polecat", "crew"}
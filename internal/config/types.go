@@ -81,6 +81,78 @@ type TownSettings struct {
 
 	// Convoy configures convoy behavior settings.
 	Convoy *ConvoyConfig `json:"convoy,omitempty"`
+
+	// Approvals gates destructive commands (nuke, rollback, rig remove)
+	// behind a confirmation phrase or a token minted by gt approve.
+	Approvals *ApprovalsConfig `json:"approvals,omitempty"`
+
+	// CostPolicy downgrades sessions to cheaper models as their cost crosses
+	// per-session thresholds. Enforced by gt costs enforce.
+	CostPolicy *CostPolicyConfig `json:"cost_policy,omitempty"`
+
+	// DoltServer overrides the Dolt server's port, user, data directory
+	// and connection limit. Unset fields keep the defaults.
+	DoltServer *DoltServerConfig `json:"dolt_server,omitempty"`
+
+	// DoltStats configures per-table growth alerts and pruning retention
+	// for gt dolt stats and gt dolt prune.
+	DoltStats *DoltStatsConfig `json:"dolt_stats,omitempty"`
+
+	// RateLimit throttles nudges, mail and escalations per recipient so
+	// aggressive patrols can't drown an agent's context.
+	RateLimit *RateLimitConfig `json:"rate_limit,omitempty"`
+
+	// SubprocessLimits caps how many bd and dolt processes one gt process
+	// runs at once, keyed by role ("mayor", "deacon", "witness",
+	// "refinery", "polecat", "crew", "daemon") with "default" for the rest.
+	// Unset roles use the built-in limit. GT_MAX_SUBPROCS overrides it.
+	// Example: {"default": 8, "daemon": 4, "deacon": 12}
+	SubprocessLimits map[string]int `json:"subprocess_limits,omitempty"`
+
+	// CommitBeadsMetadata lets gastown commit its metadata.json updates in
+	// rigs that track .beads/metadata.json in git. Without it, such updates
+	// are left as a proposed change (reported by gt doctor) so they don't
+	// dirty the worktree.
+	CommitBeadsMetadata bool `json:"commit_beads_metadata,omitempty"`
+
+	// Transcripts configures archiving of agent session transcripts and the
+	// redaction applied to them and to support bundles.
+	Transcripts *TranscriptsConfig `json:"transcripts,omitempty"`
+
+	// NamepoolThemes defines town-wide polecat name themes, keyed by theme
+	// name. Rigs select one with namepool.style like a built-in theme.
+	NamepoolThemes map[string][]string `json:"namepool_themes,omitempty"`
+
+	// Defaults sets default flag values per command, over any in the user's
+	// ~/.config/gastown/config.json. See FlagDefaults.
+	Defaults FlagDefaults `json:"defaults,omitempty"`
+
+	// Profiles defines named config overlays (e.g. dev, staging, prod) for
+	// this file and mayor/daemon.json. See ConfigProfile.
+	Profiles map[string]*ConfigProfile `json:"profiles,omitempty"`
+
+	// ActiveProfile is the profile applied by default. GT_PROFILE
+	// overrides it. Set with gt config profile use.
+	ActiveProfile string `json:"active_profile,omitempty"`
+
+	// FeatureFlags overrides feature flag defaults for every rig, keyed by
+	// flag name. See FeatureFlag.
+	FeatureFlags map[string]bool `json:"feature_flags,omitempty"`
+}
+
+// SubprocessLimit returns the configured bd/dolt process limit for role,
+// falling back to the "default" entry; 0 means use the built-in limit.
+func (s *TownSettings) SubprocessLimit(role string) int {
+	if s == nil {
+		return 0
+	}
+	if n, ok := s.SubprocessLimits[role]; ok && n > 0 {
+		return n
+	}
+	if n := s.SubprocessLimits["default"]; n > 0 {
+		return n
+	}
+	return 0
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -170,6 +242,235 @@ func DefaultFeedCuratorConfig() *FeedCuratorConfig {
 	}
 }
 
+// ApprovalsConfig configures approval policies for destructive commands.
+type ApprovalsConfig struct {
+	// Policies maps a command class ("nuke", "rollback", "rig-remove") to a
+	// policy: "allow" (no gate), "confirm" (interactive confirmation phrase or
+	// approval token), or "token" (approval token only).
+	// Unlisted classes default to "allow".
+	Policies map[string]string `json:"policies,omitempty"`
+	// TokenTTL is how long tokens minted by gt approve remain valid, in
+	// config duration syntax ("10m", "1d"). Default: "10m".
+	TokenTTL string `json:"token_ttl,omitempty"`
+}
+
+// RateLimitConfig configures the per-recipient message rate limiter used by
+// gt nudge, gt mail send and gt escalate.
+type RateLimitConfig struct {
+	// Disabled turns the limiter off.
+	Disabled bool `json:"disabled,omitempty"`
+	// Cooldowns is the minimum time between two messages of a kind
+	// ("nudge", "mail", "escalation") to the same recipient. "0" disables
+	// the cooldown for that kind.
+	// Defaults: nudge "30s", mail "0", escalation "5m".
+	Cooldowns map[string]string `json:"cooldowns,omitempty"`
+	// DedupWindow suppresses a message identical to one already sent to the
+	// same recipient within the window. "0" disables deduplication.
+	// Default: "10m".
+	DedupWindow string `json:"dedup_window,omitempty"`
+}
+
+// CostPolicyConfig configures budget-aware model downgrades.
+type CostPolicyConfig struct {
+	// Thresholds are the downgrade steps. When a session's cost crosses a
+	// step's USD, the step's action is applied once for that session.
+	Thresholds []CostThreshold `json:"thresholds,omitempty"`
+
+	// Budget is the monthly spend gt costs forecast compares projections
+	// against.
+	Budget *CostBudget `json:"budget,omitempty"`
+}
+
+// CostBudget is a monthly spend budget in USD, for the town and optionally
+// per rig and per role. Zero or missing means no budget.
+type CostBudget struct {
+	MonthlyUSD float64            `json:"monthly_usd,omitempty"`
+	Rigs       map[string]float64 `json:"rigs,omitempty"`
+	Roles      map[string]float64 `json:"roles,omitempty"`
+}
+
+// CostThreshold is one step of a cost policy.
+type CostThreshold struct {
+	// USD is the per-session cost at which the step applies.
+	USD float64 `json:"usd"`
+	// Action is "nudge" (switch the running agent with /model) or "respawn"
+	// (restart the session with Agent, resuming the conversation).
+	Action string `json:"action"`
+	// Model is the model to switch to for the nudge action (e.g. "sonnet").
+	Model string `json:"model,omitempty"`
+	// Agent is the agent (built-in preset or custom agent in Agents) to
+	// respawn with for the respawn action.
+	Agent string `json:"agent,omitempty"`
+	// Roles limits the step to these roles (e.g. "polecat"). Empty means all.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// DoltServerConfig configures the town's Dolt SQL server. Zero values use
+// the defaults: port 3307, user root, data directory .dolt-data in the town
+// root, and 50 connections.
+type DoltServerConfig struct {
+	// Port is the MySQL protocol port the server listens on.
+	Port int `json:"port,omitempty"`
+
+	// User is the MySQL user clients connect as. The server doesn't create
+	// it; add it with CREATE USER and grant it access to the databases.
+	User string `json:"user,omitempty"`
+
+	// DataDir holds the rig databases. A relative path is relative to the
+	// town root.
+	DataDir string `json:"data_dir,omitempty"`
+
+	// MaxConnections is the most simultaneous connections the server
+	// accepts.
+	MaxConnections int `json:"max_connections,omitempty"`
+
+	// BackupRetention is how many gt dolt backup snapshots to keep; older
+	// ones are pruned after each backup.
+	BackupRetention int `json:"backup_retention,omitempty"`
+
+	// Offsite copies each gt dolt backup snapshot to object storage, so
+	// backups survive losing the machine.
+	Offsite *DoltOffsiteConfig `json:"offsite,omitempty"`
+
+	// StartupTimeout is how long gt dolt start waits for the server to
+	// answer queries (default "30s"). Raise it for large data directories
+	// or slow disks.
+	StartupTimeout Duration `json:"startup_timeout,omitempty"`
+}
+
+// Endpoint returns the port and user clients reach the server with: the
+// defaults, overridden by the set fields of c. Out-of-range ports are
+// ignored. Both the server (doltserver.DefaultConfig) and agent sessions
+// (AgentEnv) resolve the endpoint here, so they can't disagree.
+func (c *DoltServerConfig) Endpoint() (port int, user string) {
+	port, user = DefaultDoltPort, DefaultDoltUser
+	if c == nil {
+		return port, user
+	}
+	if c.Port > 0 && c.Port <= 65535 {
+		port = c.Port
+	}
+	if c.User != "" {
+		user = c.User
+	}
+	return port, user
+}
+
+// clone returns a copy of c that shares nothing mutable with it.
+func (c *DoltServerConfig) clone() *DoltServerConfig {
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	if c.Offsite != nil {
+		offsite := *c.Offsite
+		cp.Offsite = &offsite
+	}
+	return &cp
+}
+
+// DoltOffsiteConfig configures the object storage target for gt dolt backup.
+// Credentials are never stored here: uploads go through the provider's CLI
+// (aws, gcloud, az), which uses its own credential chain, optionally pointed
+// at a profile or credentials file.
+type DoltOffsiteConfig struct {
+	// URL is the target: s3://bucket/prefix, gs://bucket/prefix,
+	// az://container/prefix, or file:///path for a mounted drive.
+	URL string `json:"url"`
+
+	// Profile is the AWS CLI profile (s3) to use.
+	Profile string `json:"profile,omitempty"`
+
+	// CredentialsFile is an AWS shared credentials file (s3) or a service
+	// account key file (gs).
+	CredentialsFile string `json:"credentials_file,omitempty"`
+
+	// Account is the storage account (az). The az CLI's login, or
+	// AZURE_STORAGE_KEY / AZURE_STORAGE_SAS_TOKEN in the environment,
+	// authorizes it.
+	Account string `json:"account,omitempty"`
+
+	// Retention is how many snapshots to keep in object storage (default
+	// 30). Older ones are deleted after each upload.
+	Retention int `json:"retention,omitempty"`
+
+	// RetentionDays also deletes snapshots older than this many days. The
+	// newest snapshot is always kept. 0 keeps snapshots regardless of age.
+	RetentionDays int `json:"retention_days,omitempty"`
+}
+
+// DoltStatsConfig configures Dolt table growth monitoring.
+type DoltStatsConfig struct {
+	// Alerts flag tables that grow past a limit (e.g. the events audit log
+	// over 1M rows).
+	Alerts []DoltTableAlert `json:"alerts,omitempty"`
+
+	// Retention controls what gt dolt prune deletes. Zero values use the
+	// defaults (closed wisps after 7 days, audit events after 30 days).
+	Retention *DoltRetentionConfig `json:"retention,omitempty"`
+}
+
+// DoltTableAlert is a size limit for one table.
+type DoltTableAlert struct {
+	// Table is the table name (e.g. "events", "issues").
+	Table string `json:"table"`
+	// Databases limits the alert to these rig databases. Empty means all.
+	Databases []string `json:"databases,omitempty"`
+	// MaxRows alerts when the table has more rows than this. 0 disables.
+	MaxRows int64 `json:"max_rows,omitempty"`
+	// MaxBytes alerts when the table's data length exceeds this. 0 disables.
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+}
+
+// DoltRetentionConfig sets how long prunable rows are kept.
+type DoltRetentionConfig struct {
+	// ClosedWispDays is how long closed wisps are kept after closing.
+	ClosedWispDays int `json:"closed_wisp_days,omitempty"`
+	// EventDays is how long audit events are kept.
+	EventDays int `json:"event_days,omitempty"`
+	// ArchiveDays is how long closed wisps and digests stay in the issues
+	// table before gt dolt archive moves them to issues_archive.
+	ArchiveDays int `json:"archive_days,omitempty"`
+	// BranchDays is how old a polecat's Dolt branch must be, once its
+	// polecat is gone, before gt dolt prune-branches deletes it.
+	BranchDays int `json:"branch_days,omitempty"`
+}
+
+// TranscriptsConfig configures transcript archiving and redaction.
+type TranscriptsConfig struct {
+	// Archive copies each session's transcript, redacted, into
+	// .runtime/transcripts/ when gt costs record runs at session end.
+	Archive bool `json:"archive,omitempty"`
+
+	// Redaction adds to the built-in secret patterns. Applied to archived
+	// transcripts and to support bundles.
+	Redaction *RedactionConfig `json:"redaction,omitempty"`
+}
+
+// RedactionConfig configures secret redaction. Built-in patterns (API keys,
+// tokens, passwords, URL credentials) always apply.
+type RedactionConfig struct {
+	// Patterns are extra regular expressions whose matches are redacted.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Denylist are literal strings that are always redacted.
+	Denylist []string `json:"denylist,omitempty"`
+
+	// EntropyThreshold is the Shannon entropy (bits per character) above
+	// which a long token-like string is treated as a secret. 0 uses the
+	// default; negative disables entropy detection.
+	EntropyThreshold float64 `json:"entropy_threshold,omitempty"`
+
+	// EntropyMinLength is the shortest string checked for entropy.
+	// 0 uses the default.
+	EntropyMinLength int `json:"entropy_min_length,omitempty"`
+
+	// Roles adds per-role rules (keyed by role: mayor, deacon, witness,
+	// refinery, polecat, crew). Patterns and denylist entries are added to
+	// the town-wide ones; non-zero entropy settings replace them.
+	Roles map[string]*RedactionConfig `json:"roles,omitempty"`
+}
+
 // ConvoyConfig configures convoy behavior settings.
 type ConvoyConfig struct {
 	// NotifyOnComplete controls whether convoy completion pushes a notification
@@ -177,13 +478,16 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
-// ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
+// ParseDurationOrDefault parses a duration with ParseDuration, returning
+// fallback for empty input. An invalid or negative value also returns
+// fallback, with a warning on stderr so the typo doesn't go unnoticed.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
 		return fallback
 	}
-	d, err := time.ParseDuration(s)
+	d, err := ParseDuration(s)
 	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using %s\n", err, FormatDuration(fallback))
 		return fallback
 	}
 	return d
@@ -316,6 +620,34 @@ type WorkflowConfig struct {
 	DefaultFormula string `json:"default_formula,omitempty"`
 }
 
+// SpawnConfig represents polecat spawn settings for a rig.
+type SpawnConfig struct {
+	// Checks validate a new polecat's workspace after it is cloned and its
+	// setup hooks have run, before the agent is launched. If any check
+	// fails, the sling fails and the polecat is removed.
+	Checks []SpawnCheck `json:"checks,omitempty"`
+}
+
+// SpawnCheck is a single spawn-time workspace check. Exactly one of Path
+// or Run must be set.
+type SpawnCheck struct {
+	// Name identifies the check in reports.
+	Name string `json:"name"`
+
+	// Path must exist, relative to the polecat's worktree
+	// (e.g. "node_modules", ".env", "vendor/lib/.git").
+	Path string `json:"path,omitempty"`
+
+	// Run is a shell command run in the worktree; it must exit 0.
+	Run string `json:"run,omitempty"`
+
+	// Timeout bounds Run (e.g. "2m"). Defaults to 60s.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Hint tells the operator how to fix a failure.
+	Hint string `json:"hint,omitempty"`
+}
+
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
 type RigSettings struct {
 	Type       string            `json:"type"`                  // "rig-settings"
@@ -325,6 +657,7 @@ type RigSettings struct {
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
+	Spawn      *SpawnConfig      `json:"spawn,omitempty"`       // polecat spawn validation
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
@@ -345,6 +678,17 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// PromptVars are project-specific values merged into the polecat and
+	// crew role templates, so their context carries rig guidance without
+	// forking the templates. Keys must be identifiers; templates read them
+	// as {{ .Vars.key }} and list them all under Project Guidance.
+	// Example: {"test_command": "make test", "coding_standards": "https://..."}
+	PromptVars map[string]string `json:"prompt_vars,omitempty"`
+
+	// FeatureFlags overrides feature flags for this rig, over the town's
+	// feature_flags. See FeatureFlag.
+	FeatureFlags map[string]bool `json:"feature_flags,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -948,7 +1292,8 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 
 // NamepoolConfig represents namepool settings for themed polecat names.
 type NamepoolConfig struct {
-	// Style picks from a built-in theme (e.g., "mad-max", "minerals", "wasteland").
+	// Style picks from a built-in theme (e.g., "mad-max", "minerals", "wasteland")
+	// or a town theme from namepool_themes in settings/config.json.
 	// If empty, defaults to "mad-max".
 	Style string `json:"style,omitempty"`
 
@@ -26,7 +26,7 @@ func TestParseDurationOrDefault(t *testing.T) {
 		{"valid composite", "1m30s", 0, 90 * time.Second},
 		{"empty string returns fallback", "", 42 * time.Second, 42 * time.Second},
 		{"invalid string returns fallback", "not-a-duration", 7 * time.Second, 7 * time.Second},
-		{"negative duration parses", "-5s", 10 * time.Second, -5 * time.Second},
+		{"negative duration returns fallback", "-5s", 10 * time.Second, 10 * time.Second},
 		{"zero duration parses", "0s", 10 * time.Second, 0},
 		{"bare number returns fallback", "15", 3 * time.Second, 3 * time.Second},
 		{"whitespace returns fallback", "  ", 1 * time.Second, 1 * time.Second},
@@ -581,3 +581,20 @@ func TestParseDurationOrDefault_AllWebTimeoutDefaults(t *testing.T) {
 	}
 }
 
+
+func TestTownSettingsSubprocessLimit(t *testing.T) {
+	t.Parallel()
+	s := &TownSettings{SubprocessLimits: map[string]int{"default": 6, "daemon": 3, "crew": 0}}
+	for role, want := range map[string]int{"daemon": 3, "mayor": 6, "crew": 6} {
+		if got := s.SubprocessLimit(role); got != want {
+			t.Errorf("SubprocessLimit(%q) = %d, want %d", role, got, want)
+		}
+	}
+	if got := (&TownSettings{}).SubprocessLimit("mayor"); got != 0 {
+		t.Errorf("unset SubprocessLimit = %d, want 0", got)
+	}
+	var nilSettings *TownSettings
+	if got := nilSettings.SubprocessLimit("mayor"); got != 0 {
+		t.Errorf("nil SubprocessLimit = %d, want 0", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // CheckConvoysForIssue finds any convoys tracking the given issue and triggers
@@ -75,7 +76,7 @@ func getTrackingConvoys(townRoot, issueID string) []string {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return nil
 	}
 
@@ -100,7 +101,7 @@ func isConvoyClosed(townRoot, convoyID string) bool {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return false
 	}
 
@@ -184,7 +185,7 @@ func getConvoyTrackedIssues(townRoot, convoyID string) []trackedIssue {
 	var stdout bytes.Buffer
 	depCmd.Stdout = &stdout
 
-	if err := depCmd.Run(); err != nil {
+	if err := proc.RunCmd(depCmd); err != nil {
 		return nil
 	}
 
@@ -249,7 +250,7 @@ func batchShowIssues(townRoot string, issueIDs []string) map[string]trackedIssue
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return result
 	}
 
@@ -0,0 +1,117 @@
+// Package costpolicy decides when a session's cost calls for switching it
+// to a cheaper model.
+//
+// The policy lives in the town settings (cost_policy) as a list of
+// thresholds. Each threshold applies at most once per session: the highest
+// threshold already applied is tracked by the caller (gt costs enforce
+// records downgrades in the cost log) and passed back to NextStep.
+package costpolicy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Actions a threshold can take.
+const (
+	// ActionNudge types "/model <model>" into the running agent, switching
+	// models without restarting the session.
+	ActionNudge = "nudge"
+
+	// ActionRespawn restarts the session with a cheaper agent config and
+	// resumes the conversation.
+	ActionRespawn = "respawn"
+)
+
+// LoadConfig reads the cost_policy section of the town settings.
+// Returns nil if no policy is configured.
+func LoadConfig(townRoot string) (*config.CostPolicyConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.CostPolicy, nil
+}
+
+// Validate checks that every threshold is well-formed.
+func Validate(cfg *config.CostPolicyConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if b := cfg.Budget; b != nil {
+		if b.MonthlyUSD < 0 {
+			return fmt.Errorf("cost_policy.budget.monthly_usd must not be negative")
+		}
+		for rig, usd := range b.Rigs {
+			if usd < 0 {
+				return fmt.Errorf("cost_policy.budget.rigs[%s] must not be negative", rig)
+			}
+		}
+		for role, usd := range b.Roles {
+			if usd < 0 {
+				return fmt.Errorf("cost_policy.budget.roles[%s] must not be negative", role)
+			}
+		}
+	}
+	for i, t := range cfg.Thresholds {
+		if t.USD <= 0 {
+			return fmt.Errorf("cost_policy.thresholds[%d]: usd must be positive", i)
+		}
+		switch t.Action {
+		case ActionNudge:
+			if t.Model == "" {
+				return fmt.Errorf("cost_policy.thresholds[%d]: nudge requires model", i)
+			}
+		case ActionRespawn:
+			if t.Agent == "" {
+				return fmt.Errorf("cost_policy.thresholds[%d]: respawn requires agent", i)
+			}
+		default:
+			return fmt.Errorf("cost_policy.thresholds[%d]: unknown action %q (want %s or %s)",
+				i, t.Action, ActionNudge, ActionRespawn)
+		}
+	}
+	return nil
+}
+
+// NextStep returns the threshold to apply to a session of the given role
+// whose cost is cost, or nil if none is due. applied is the highest threshold
+// (in USD) already applied to the session; only thresholds above it qualify.
+// If the cost jumped past several thresholds at once, only the highest
+// applies.
+func NextStep(cfg *config.CostPolicyConfig, role string, cost, applied float64) *config.CostThreshold {
+	if cfg == nil {
+		return nil
+	}
+	var due []config.CostThreshold
+	for _, t := range cfg.Thresholds {
+		if t.USD <= applied || t.USD > cost || !appliesToRole(t, role) {
+			continue
+		}
+		due = append(due, t)
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].USD > due[j].USD })
+	return &due[0]
+}
+
+func appliesToRole(t config.CostThreshold, role string) bool {
+	if len(t.Roles) == 0 {
+		return true
+	}
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// NudgeCommand returns the text typed into the agent to switch it to model.
+func NudgeCommand(model string) string {
+	return "/model " + model
+}
@@ -0,0 +1,71 @@
+package costpolicy
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    config.CostThreshold
+		wantErr bool
+	}{
+		{"nudge ok", config.CostThreshold{USD: 5, Action: ActionNudge, Model: "sonnet"}, false},
+		{"respawn ok", config.CostThreshold{USD: 5, Action: ActionRespawn, Agent: "claude-haiku"}, false},
+		{"zero usd", config.CostThreshold{USD: 0, Action: ActionNudge, Model: "sonnet"}, true},
+		{"nudge without model", config.CostThreshold{USD: 5, Action: ActionNudge}, true},
+		{"respawn without agent", config.CostThreshold{USD: 5, Action: ActionRespawn}, true},
+		{"unknown action", config.CostThreshold{USD: 5, Action: "kill"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&config.CostPolicyConfig{Thresholds: []config.CostThreshold{tt.step}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := Validate(nil); err != nil {
+		t.Errorf("Validate(nil) = %v, want nil", err)
+	}
+}
+
+func TestNextStep(t *testing.T) {
+	cfg := &config.CostPolicyConfig{Thresholds: []config.CostThreshold{
+		{USD: 20, Action: ActionRespawn, Agent: "claude-haiku", Roles: []string{"polecat"}},
+		{USD: 5, Action: ActionNudge, Model: "sonnet"},
+	}}
+
+	tests := []struct {
+		name    string
+		role    string
+		cost    float64
+		applied float64
+		want    float64 // USD of the expected step; 0 for none
+	}{
+		{"below all thresholds", "polecat", 4.99, 0, 0},
+		{"crosses first", "polecat", 5, 0, 5},
+		{"first already applied", "polecat", 10, 5, 0},
+		{"crosses second", "polecat", 25, 5, 20},
+		{"jumps past both", "polecat", 25, 0, 20},
+		{"second limited to polecats", "crew", 25, 5, 0},
+		{"all applied", "polecat", 100, 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextStep(cfg, tt.role, tt.cost, tt.applied)
+			switch {
+			case tt.want == 0 && got != nil:
+				t.Errorf("NextStep() = %+v, want nil", got)
+			case tt.want != 0 && (got == nil || got.USD != tt.want):
+				t.Errorf("NextStep() = %+v, want step at $%.2f", got, tt.want)
+			}
+		})
+	}
+
+	if got := NextStep(nil, "polecat", 100, 0); got != nil {
+		t.Errorf("NextStep(nil) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,192 @@
+// Package crashlog stores forensic reports for crashed agent sessions.
+//
+// When an agent's process dies, the tmux pane (and with it the session's
+// final output) disappears quickly. Reports capture the last lines of the
+// pane and the process exit status at detection time and are stored under
+// <town>/daemon/crashes/<rig>/<polecat>/<timestamp>.json.
+package crashlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultOutputLines is how many pane lines are captured per report.
+const DefaultOutputLines = 200
+
+// MaxReportsPerAgent bounds how many reports are kept for one agent.
+const MaxReportsPerAgent = 20
+
+// Detection sources.
+const (
+	DetectedByHook   = "pane-died-hook" // tmux pane-died hook (gt log crash)
+	DetectedByDaemon = "daemon"         // daemon heartbeat crash detection
+)
+
+// timeFormat is used for report file names; it sorts chronologically.
+const timeFormat = "20060102T150405.000Z"
+
+// Report is a captured crash.
+type Report struct {
+	Rig        string    `json:"rig,omitempty"`
+	Polecat    string    `json:"polecat,omitempty"`
+	Agent      string    `json:"agent"`
+	Session    string    `json:"session,omitempty"`
+	ExitStatus *int      `json:"exit_status,omitempty"` // nil if the process status was unavailable
+	HookBead   string    `json:"hook_bead,omitempty"`
+	DetectedBy string    `json:"detected_by"`
+	Time       time.Time `json:"time"`
+	Output     []string  `json:"output,omitempty"` // last pane lines, oldest first
+
+	// Path is where the report is stored (set by Save and List).
+	Path string `json:"-"`
+}
+
+// Dir returns the directory holding all crash reports.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "crashes")
+}
+
+// agentDir returns the report directory for a rig/polecat, or for a
+// non-polecat agent under "_town".
+func agentDir(townRoot string, r *Report) string {
+	if r.Rig != "" && r.Polecat != "" {
+		return filepath.Join(Dir(townRoot), r.Rig, r.Polecat)
+	}
+	name := strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(r.Agent)
+	if name == "" {
+		name = "unknown"
+	}
+	return filepath.Join(Dir(townRoot), "_town", name)
+}
+
+// Save writes the report and prunes old reports for the same agent.
+func Save(townRoot string, r *Report) (string, error) {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	dir := agentDir(townRoot, r)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating crash dir: %w", err)
+	}
+	path := filepath.Join(dir, r.Time.UTC().Format(timeFormat)+".json")
+	if err := util.AtomicWriteJSON(path, r); err != nil {
+		return "", fmt.Errorf("writing crash report: %w", err)
+	}
+	r.Path = path
+	prune(dir, MaxReportsPerAgent)
+	return path, nil
+}
+
+// prune removes the oldest reports in dir beyond keep.
+func prune(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= keep {
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+}
+
+// Load reads a single report.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the crash dir or user-provided
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	r.Path = path
+	return &r, nil
+}
+
+// List returns reports newest first. If rig is non-empty, only reports for
+// that rig's polecats are returned. Unreadable reports are skipped.
+func List(townRoot, rig string) ([]*Report, error) {
+	root := Dir(townRoot)
+	if rig != "" {
+		root = filepath.Join(root, rig)
+	}
+	var reports []*Report
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		if r, err := Load(path); err == nil {
+			reports = append(reports, r)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Time.After(reports[j].Time)
+	})
+	return reports, nil
+}
+
+// RecentlyCaptured reports whether a report for rig/polecat was saved within
+// the window, so a second detector doesn't record the same crash twice.
+func RecentlyCaptured(townRoot, rig, polecat string, window time.Duration) bool {
+	reports, err := List(townRoot, rig)
+	if err != nil {
+		return false
+	}
+	cutoff := time.Now().Add(-window)
+	for _, r := range reports {
+		if r.Polecat == polecat && r.Time.After(cutoff) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAgent splits a polecat agent ID ("rig/name" or "rig/polecats/name")
+// into rig and polecat. Returns empty strings for other agent IDs, including
+// rig singletons like "rig/witness".
+func ParseAgent(agent string) (rig, polecat string) {
+	parts := strings.Split(agent, "/")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "" &&
+		parts[1] != "witness" && parts[1] != "refinery":
+		return parts[0], parts[1]
+	case len(parts) == 3 && parts[1] == "polecats":
+		return parts[0], parts[2]
+	}
+	return "", ""
+}
+
+// TrimOutput drops trailing blank lines from captured pane output.
+func TrimOutput(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
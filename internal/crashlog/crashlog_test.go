@@ -0,0 +1,105 @@
+package crashlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveListLoad(t *testing.T) {
+	townRoot := t.TempDir()
+	status := 137
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first := &Report{Rig: "gastown", Polecat: "Toast", Agent: "gastown/Toast", ExitStatus: &status,
+		DetectedBy: DetectedByHook, Time: base, Output: []string{"panic: boom"}}
+	path, err := Save(townRoot, first)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if want := filepath.Join(Dir(townRoot), "gastown", "Toast"); filepath.Dir(path) != want {
+		t.Errorf("report dir = %s, want %s", filepath.Dir(path), want)
+	}
+
+	second := &Report{Rig: "gastown", Polecat: "Nux", Agent: "gastown/Nux", DetectedBy: DetectedByDaemon,
+		Time: base.Add(time.Minute)}
+	if _, err := Save(townRoot, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(townRoot, &Report{Rig: "beads", Polecat: "Ace", Agent: "beads/Ace", Time: base}); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := List(townRoot, "gastown")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(reports) != 2 || reports[0].Polecat != "Nux" || reports[1].Polecat != "Toast" {
+		t.Fatalf("List(gastown) = %+v, want Nux then Toast", reports)
+	}
+	if reports[1].ExitStatus == nil || *reports[1].ExitStatus != 137 || reports[1].Output[0] != "panic: boom" {
+		t.Errorf("round-trip lost fields: %+v", reports[1])
+	}
+	if reports[0].ExitStatus != nil {
+		t.Error("unknown exit status should stay nil")
+	}
+
+	all, err := List(townRoot, "")
+	if err != nil || len(all) != 3 {
+		t.Errorf("List(all) = %d reports, %v; want 3", len(all), err)
+	}
+	if none, err := List(townRoot, "missing"); err != nil || len(none) != 0 {
+		t.Errorf("List(missing) = %v, %v", none, err)
+	}
+}
+
+func TestSavePrunesOldReports(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < MaxReportsPerAgent+5; i++ {
+		r := &Report{Rig: "gastown", Polecat: "Toast", Agent: "gastown/Toast", Time: base.Add(time.Duration(i) * time.Second)}
+		if _, err := Save(townRoot, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(Dir(townRoot), "gastown", "Toast"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxReportsPerAgent {
+		t.Errorf("kept %d reports, want %d", len(entries), MaxReportsPerAgent)
+	}
+}
+
+func TestRecentlyCaptured(t *testing.T) {
+	townRoot := t.TempDir()
+	if RecentlyCaptured(townRoot, "gastown", "Toast", time.Minute) {
+		t.Error("no reports yet")
+	}
+	if _, err := Save(townRoot, &Report{Rig: "gastown", Polecat: "Toast", Agent: "gastown/Toast"}); err != nil {
+		t.Fatal(err)
+	}
+	if !RecentlyCaptured(townRoot, "gastown", "Toast", time.Minute) {
+		t.Error("expected fresh report to count")
+	}
+	if RecentlyCaptured(townRoot, "gastown", "Nux", time.Minute) {
+		t.Error("other polecat should not match")
+	}
+}
+
+func TestParseAgent(t *testing.T) {
+	tests := []struct{ agent, rig, polecat string }{
+		{"gastown/Toast", "gastown", "Toast"},
+		{"gastown/polecats/Toast", "gastown", "Toast"},
+		{"gastown/witness", "", ""},
+		{"gastown/crew/max", "", ""},
+		{"mayor", "", ""},
+	}
+	for _, tt := range tests {
+		rig, polecat := ParseAgent(tt.agent)
+		if rig != tt.rig || polecat != tt.polecat {
+			t.Errorf("ParseAgent(%q) = %q, %q; want %q, %q", tt.agent, rig, polecat, tt.rig, tt.polecat)
+		}
+	}
+}
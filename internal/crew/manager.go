@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/vcs"
 )
 
 // Common errors
@@ -93,6 +94,16 @@ func NewManager(r *rig.Rig, g *git.Git) *Manager {
 	}
 }
 
+// workspace opens a crew clone with the rig's version control backend,
+// falling back to git if the rig's backend is unknown.
+func (m *Manager) workspace(path string) vcs.Workspace {
+	backend, err := vcs.For(m.rig.VCS())
+	if err != nil {
+		return git.NewGit(path)
+	}
+	return backend.Open(path)
+}
+
 // crewDir returns the directory for a crew worker.
 func (m *Manager) crewDir(name string) string {
 	return filepath.Join(m.rig.Path, "crew", name)
@@ -158,8 +169,17 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		return nil, fmt.Errorf("creating crew dir: %w", err)
 	}
 
+	backend, err := vcs.For(m.rig.VCS())
+	if err != nil {
+		return nil, err
+	}
+
 	// Clone the rig repo
-	if m.rig.LocalRepo != "" {
+	if backend.Name() != vcs.Git {
+		if err := backend.Clone(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
+			return nil, fmt.Errorf("cloning rig (%s): %w", backend.Name(), err)
+		}
+	} else if m.rig.LocalRepo != "" {
 		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
 			fmt.Printf("Warning: could not clone with local repo reference: %v\n", err)
 			if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
@@ -178,7 +198,7 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		fmt.Printf("Warning: could not sync remotes from rig: %v\n", err)
 	}
 
-	crewGit := git.NewGit(crewPath)
+	crewGit := backend.Open(crewPath)
 	branchName := m.rig.DefaultBranch()
 
 	// Optionally create a working branch
@@ -228,9 +248,16 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
 	}
 
+	// Write .runtime/env.sh and .envrc so humans working in the clone get
+	// the same GT_* variables as the crew agent.
+	addTownRoot := filepath.Dir(m.rig.Path)
+	if err := m.writeWorkspaceEnv(name); err != nil {
+		// Non-fatal - log warning but continue
+		fmt.Printf("Warning: could not write workspace env: %v\n", err)
+	}
+
 	// Install runtime settings in the shared crew parent directory.
 	// Settings are passed to Claude Code via --settings flag.
-	addTownRoot := filepath.Dir(m.rig.Path)
 	addRuntimeConfig := config.ResolveRoleAgentConfig("crew", addTownRoot, m.rig.Path)
 	crewSettingsDir := config.RoleSettingsDir("crew", m.rig.Path)
 	if err := runtime.EnsureSettingsForRole(crewSettingsDir, crewPath, "crew", addRuntimeConfig); err != nil {
@@ -328,8 +355,7 @@ func (m *Manager) Remove(name string, force bool) error {
 	crewPath := m.crewDir(name)
 
 	if !force {
-		crewGit := git.NewGit(crewPath)
-		hasChanges, err := crewGit.HasUncommittedChanges()
+		hasChanges, err := m.workspace(crewPath).HasUncommittedChanges()
 		if err == nil && hasChanges {
 			return ErrHasChanges
 		}
@@ -491,6 +517,9 @@ func (m *Manager) Rename(oldName, newName string) error {
 		return fmt.Errorf("saving state: %w", err)
 	}
 
+	// GT_ROLE and friends carry the name; rewrite the workspace env (best-effort)
+	_ = m.writeWorkspaceEnv(newName)
+
 	return nil
 }
 
@@ -505,7 +534,7 @@ func (m *Manager) Pristine(name string) (*PristineResult, error) {
 	}
 
 	crewPath := m.crewDir(name)
-	crewGit := git.NewGit(crewPath)
+	crewGit := m.workspace(crewPath)
 
 	result := &PristineResult{
 		Name: name,
@@ -528,9 +557,20 @@ func (m *Manager) Pristine(name string) (*PristineResult, error) {
 	// Note: With Dolt backend, beads changes are persisted immediately - no sync needed
 	result.Synced = true
 
+	// Refresh the workspace env file in case the contract or town settings changed
+	if err := m.writeWorkspaceEnv(name); err != nil {
+		result.EnvError = err.Error()
+	}
+
 	return result, nil
 }
 
+// writeWorkspaceEnv writes the crew worker's .runtime/env.sh and .envrc.
+func (m *Manager) writeWorkspaceEnv(name string) error {
+	env := rig.WorkspaceEnv(filepath.Dir(m.rig.Path), m.rig.Name, "crew", name, "")
+	return rig.WriteWorkspaceEnv(m.crewDir(name), env)
+}
+
 // PristineResult captures the results of a pristine operation.
 type PristineResult struct {
 	Name       string `json:"name"`
@@ -539,6 +579,7 @@ type PristineResult struct {
 	PullError  string `json:"pull_error,omitempty"`
 	Synced     bool   `json:"synced"`
 	SyncError  string `json:"sync_error,omitempty"`
+	EnvError   string `json:"env_error,omitempty"`
 }
 
 // setupSharedBeads creates a redirect file so the crew worker uses the rig's shared .beads database.
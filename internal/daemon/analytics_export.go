@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const defaultAnalyticsExportInterval = time.Hour
+
+// analyticsExportInterval returns the configured export interval, or the default (1h).
+func analyticsExportInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AnalyticsExport != nil {
+		return config.Patrols.AnalyticsExport.Interval.Or(defaultAnalyticsExportInterval)
+	}
+	return defaultAnalyticsExportInterval
+}
+
+// exportAnalytics writes an incremental analytics export of each configured
+// rig (by default, every database). Non-fatal: errors are logged but don't
+// stop the patrol.
+func (d *Daemon) exportAnalytics() {
+	if !IsPatrolEnabled(d.patrolConfig, "analytics_export") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		d.logger.Printf("analytics_export: dolt server not configured, skipping")
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	config := d.patrolConfig.Patrols.AnalyticsExport
+
+	databases := config.Databases
+	if len(databases) == 0 {
+		var err error
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			d.logger.Printf("analytics_export: error discovering databases: %v", err)
+			return
+		}
+	}
+
+	opts := doltserver.AnalyticsExportOptions{
+		OutDir:      config.OutDir,
+		Tables:      config.Tables,
+		Format:      config.Format,
+		Incremental: true,
+	}
+	var rows int64
+	var files int
+	for _, db := range databases {
+		results, err := doltserver.ExportAnalytics(townRoot, db, opts)
+		for _, r := range results {
+			if r.Path != "" {
+				rows += r.Rows
+				files++
+			}
+		}
+		if err != nil {
+			d.logger.Printf("analytics_export: %s: %v", db, err)
+		}
+	}
+
+	d.logger.Printf("analytics_export: exported %d row(s) to %d file(s) from %d database(s)", rows, files, len(databases))
+}
@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultBackupVerifyInterval = 24 * time.Hour
+
+// backupVerifyInterval returns the configured verification interval, or the default (24h).
+func backupVerifyInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.BackupVerify != nil {
+		return config.Patrols.BackupVerify.Interval.Or(defaultBackupVerifyInterval)
+	}
+	return defaultBackupVerifyInterval
+}
+
+// verifyBackupRestore runs gt dolt verify-backup, which restores a random
+// rig's backup into a scratch directory and records pass/fail in the event
+// log and for gt doctor. Non-fatal: failures are logged but don't stop the patrol.
+func (d *Daemon) verifyBackupRestore() {
+	if !IsPatrolEnabled(d.patrolConfig, "backup_verify") {
+		return
+	}
+
+	cmd := exec.Command(d.gtPath, "dolt", "verify-backup")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt, dolt, and bd
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("backup_verify: %v: %s", err, strings.TrimSpace(string(output)))
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	d.logger.Printf("backup_verify: %s", lines[len(lines)-1])
+}
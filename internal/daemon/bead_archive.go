@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const defaultBeadArchiveInterval = 24 * time.Hour
+
+// beadArchiveInterval returns the configured archive interval, or the default (24h).
+func beadArchiveInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.BeadArchive != nil {
+		return config.Patrols.BeadArchive.Interval.Or(defaultBeadArchiveInterval)
+	}
+	return defaultBeadArchiveInterval
+}
+
+// archiveClosedBeads moves closed wisps and digests past the town's archive
+// age out of each configured rig's issues table (by default, every
+// database). Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) archiveClosedBeads() {
+	if !IsPatrolEnabled(d.patrolConfig, "bead_archive") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		d.logger.Printf("bead_archive: dolt server not configured, skipping")
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	databases := d.patrolConfig.Patrols.BeadArchive.Databases
+	if len(databases) == 0 {
+		var err error
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			d.logger.Printf("bead_archive: error discovering databases: %v", err)
+			return
+		}
+	}
+
+	var statsConfig *config.DoltStatsConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		statsConfig = settings.DoltStats
+	} else {
+		d.logger.Printf("bead_archive: loading town settings: %v (using default archive age)", err)
+	}
+	days := doltserver.ArchiveAfterDays(statsConfig)
+
+	var total int64
+	for _, db := range databases {
+		res, err := doltserver.ArchiveClosed(townRoot, db, days, false)
+		if err != nil {
+			d.logger.Printf("bead_archive: %s: %v", db, err)
+			continue
+		}
+		total += res.Rows
+	}
+	d.logger.Printf("bead_archive: archived %d bead(s) closed over %d days from %d database(s)", total, days, len(databases))
+}
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultBranchPruneInterval = 24 * time.Hour
+
+// branchPruneInterval returns the configured prune interval, or the default (24h).
+func branchPruneInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.BranchPrune != nil {
+		return config.Patrols.BranchPrune.Interval.Or(defaultBranchPruneInterval)
+	}
+	return defaultBranchPruneInterval
+}
+
+// pruneDeadPolecatBranches runs gt dolt prune-branches, which deletes merged
+// and orphaned Dolt branches of polecats that are gone. Non-fatal: failures
+// are logged but don't stop the patrol.
+func (d *Daemon) pruneDeadPolecatBranches() {
+	if !IsPatrolEnabled(d.patrolConfig, "branch_prune") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		d.logger.Printf("branch_prune: dolt server not configured, skipping")
+		return
+	}
+
+	cmd := exec.Command(d.gtPath, "dolt", "prune-branches")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt and bd
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("branch_prune: %v: %s", err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		d.logger.Printf("branch_prune: %s", line)
+	}
+}
@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+const defaultChangeFeedInterval = time.Minute
+
+// changeFeedInterval returns the configured poll interval, or the default (1m).
+func changeFeedInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.ChangeFeed != nil {
+		return config.Patrols.ChangeFeed.Interval.Or(defaultChangeFeedInterval)
+	}
+	return defaultChangeFeedInterval
+}
+
+// beadChangeEventType maps a change kind to its event type.
+func beadChangeEventType(kind string) string {
+	switch kind {
+	case doltserver.ChangeCreated:
+		return events.TypeBeadCreated
+	case doltserver.ChangeClosed:
+		return events.TypeBeadClosed
+	default:
+		return events.TypeBeadStatusChanged
+	}
+}
+
+// pollChangeFeed publishes bead changes committed since the last checkpoint
+// for each watched database, then advances the checkpoints.
+// Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) pollChangeFeed() {
+	if !IsPatrolEnabled(d.patrolConfig, "change_feed") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		d.logger.Printf("change_feed: dolt server not configured, skipping")
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	databases := d.patrolConfig.Patrols.ChangeFeed.Databases
+	if len(databases) == 0 {
+		var err error
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			d.logger.Printf("change_feed: error listing databases: %v", err)
+			return
+		}
+	}
+
+	checkpoints, err := doltserver.LoadChangeFeedCheckpoints(townRoot)
+	if err != nil {
+		d.logger.Printf("change_feed: loading checkpoints: %v", err)
+		return
+	}
+
+	published := 0
+	for _, db := range databases {
+		changes, err := doltserver.PollBeadChanges(townRoot, db, checkpoints)
+		if err != nil {
+			d.logger.Printf("change_feed: %s: %v", db, err)
+			continue
+		}
+		for _, c := range changes {
+			_ = events.LogAudit(beadChangeEventType(c.Kind), "daemon",
+				events.BeadChangePayload(c.Database, c.BeadID, c.Title, c.FromStatus, c.ToStatus, c.Commit))
+			published++
+		}
+	}
+
+	if err := doltserver.SaveChangeFeedCheckpoints(townRoot, checkpoints); err != nil {
+		d.logger.Printf("change_feed: saving checkpoints: %v", err)
+	}
+	if published > 0 {
+		d.logger.Printf("change_feed: published %d bead change(s)", published)
+	}
+}
@@ -0,0 +1,619 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// The control API is a small HTTP/JSON API the daemon serves on a unix
+// socket, so gt can ask the running daemon instead of re-reading state
+// itself. Endpoints:
+//
+//	GET  /v1/status           daemon status and patrol configuration
+//	GET  /v1/agents           Gas Town tmux sessions
+//	POST /v1/jobs/{name}      run a daemon job now (see controlJobs)
+//	POST /v1/spawn            sling a bead to a rig ({"bead", "rig"})
+//	POST /v1/nuke             nuke a polecat ({"polecat", "force"})
+//	GET  /v1/events           recent events; ?follow=1 streams new ones
+//	GET  /v1/queue            the work queue, in run order
+//	POST /v1/queue            queue a job or gt command (ControlQueueRequest)
+//	DELETE /v1/queue/{id}     cancel a queued item
+//	POST /v1/queue/{id}/priority  reprioritize a queued item ({"priority"})
+//
+// Access control is the socket's file mode (0600): only the town owner can
+// connect.
+
+// ControlSocketPath returns the control API socket path for a town.
+func ControlSocketPath(townRoot string, config *DaemonPatrolConfig) string {
+	if config != nil && config.ControlAPI != nil && config.ControlAPI.Socket != "" {
+		if filepath.IsAbs(config.ControlAPI.Socket) {
+			return config.ControlAPI.Socket
+		}
+		return filepath.Join(townRoot, config.ControlAPI.Socket)
+	}
+	return filepath.Join(townRoot, "daemon", "control.sock")
+}
+
+// IsControlAPIEnabled reports whether the control API is enabled (opt-in).
+func IsControlAPIEnabled(config *DaemonPatrolConfig) bool {
+	return config != nil && config.ControlAPI != nil && config.ControlAPI.Enabled
+}
+
+// ControlStatus is the response of GET /v1/status.
+type ControlStatus struct {
+	PID                int             `json:"pid"`
+	StartedAt          time.Time       `json:"started_at"`
+	LastHeartbeat      time.Time       `json:"last_heartbeat,omitempty"`
+	HeartbeatCount     int64           `json:"heartbeat_count"`
+	ShutdownInProgress bool            `json:"shutdown_in_progress"`
+	Patrols            map[string]bool `json:"patrols"`
+	Jobs               []string        `json:"jobs"`
+}
+
+// ControlAgent is one entry of GET /v1/agents.
+type ControlAgent struct {
+	Session string `json:"session"`
+	Role    string `json:"role"`
+	Rig     string `json:"rig,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address"`
+}
+
+// ControlJobResult is the response of POST /v1/jobs/{name}.
+type ControlJobResult struct {
+	Job      string        `json:"job"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// ControlSpawnRequest is the body of POST /v1/spawn.
+type ControlSpawnRequest struct {
+	Bead string `json:"bead"`
+	Rig  string `json:"rig"`
+}
+
+// ControlNukeRequest is the body of POST /v1/nuke.
+type ControlNukeRequest struct {
+	Polecat  string `json:"polecat"` // rig/name
+	Force    bool   `json:"force,omitempty"`
+	Approval string `json:"approval,omitempty"` // token from gt approve nuke, if the town requires one
+}
+
+// ControlCommandResult is the response of spawn and nuke, which run gt.
+type ControlCommandResult struct {
+	Output string `json:"output"`
+}
+
+// ControlQueueRequest is the body of POST /v1/queue. Exactly one of Job
+// and Args is set.
+type ControlQueueRequest struct {
+	Job         string        `json:"job,omitempty"`  // a job name (see controlJobs)
+	Args        []string      `json:"args,omitempty"` // gt arguments
+	Reason      string        `json:"reason,omitempty"`
+	Priority    int           `json:"priority,omitempty"`
+	Delay       time.Duration `json:"delay_ns,omitempty"`
+	MaxAttempts int           `json:"max_attempts,omitempty"`
+}
+
+// ControlPriorityRequest is the body of POST /v1/queue/{id}/priority.
+type ControlPriorityRequest struct {
+	Priority int `json:"priority"`
+}
+
+// controlError is the body of every non-2xx response.
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// controlPatrols are the patrols reported in ControlStatus.
+var controlPatrols = []string{
+	"deacon", "witness", "refinery",
+	"dolt_remotes", "jsonl_export", "change_feed", "cost_enforce", "backup_verify",
+	"analytics_export", "session_prune", "bead_archive", "dolt_watchdog",
+	"branch_prune", "sla_check", "dolt_gc", "dolt_metrics",
+}
+
+// controlJobs are the jobs that can be triggered through the control API.
+// Jobs run on the daemon's main loop, serialized with the tickers. A job
+// tied to an opt-in patrol (patrol != "") only runs if that patrol is enabled.
+var controlJobs = map[string]struct {
+	patrol string
+	run    func(d *Daemon, state *State)
+}{
+	"heartbeat":     {run: func(d *Daemon, state *State) { d.heartbeat(state) }},
+	"dolt_health":   {run: func(d *Daemon, _ *State) { d.ensureDoltServerRunning() }},
+	"lifecycle":     {run: func(d *Daemon, _ *State) { d.processLifecycleRequests() }},
+	"dolt_remotes":  {patrol: "dolt_remotes", run: func(d *Daemon, _ *State) { d.pushDoltRemotes() }},
+	"jsonl_export":  {patrol: "jsonl_export", run: func(d *Daemon, _ *State) { d.exportJSONL() }},
+	"change_feed":   {patrol: "change_feed", run: func(d *Daemon, _ *State) { d.pollChangeFeed() }},
+	"cost_enforce":  {patrol: "cost_enforce", run: func(d *Daemon, _ *State) { d.enforceCostPolicy() }},
+	"backup_verify": {patrol: "backup_verify", run: func(d *Daemon, _ *State) { d.verifyBackupRestore() }},
+
+	"analytics_export": {patrol: "analytics_export", run: func(d *Daemon, _ *State) { d.exportAnalytics() }},
+	"session_prune":    {patrol: "session_prune", run: func(d *Daemon, _ *State) { d.pruneOrphanSessions() }},
+	"bead_archive":     {patrol: "bead_archive", run: func(d *Daemon, _ *State) { d.archiveClosedBeads() }},
+	"dolt_watchdog":    {patrol: "dolt_watchdog", run: func(d *Daemon, _ *State) { d.watchDoltServer() }},
+	"branch_prune":     {patrol: "branch_prune", run: func(d *Daemon, _ *State) { d.pruneDeadPolecatBranches() }},
+	"sla_check":        {patrol: "sla_check", run: func(d *Daemon, _ *State) { d.checkSLATimers() }},
+	"dolt_gc":          {patrol: "dolt_gc", run: func(d *Daemon, _ *State) { d.collectDoltGarbage() }},
+	"dolt_metrics":     {patrol: "dolt_metrics", run: func(d *Daemon, _ *State) { d.sampleDoltMetrics() }},
+}
+
+// ControlJobNames returns the names of the jobs the control API can trigger.
+func ControlJobNames() []string {
+	names := make([]string, 0, len(controlJobs))
+	for name := range controlJobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// controlJobRequest asks the main loop to run a job.
+type controlJobRequest struct {
+	name string
+	done chan struct{}
+}
+
+// controlServer is a running control API server.
+type controlServer struct {
+	srv    *http.Server
+	cancel context.CancelFunc // cancels in-flight requests (event streams)
+}
+
+// Close stops the server, ending event streams and giving other in-flight
+// requests a moment to finish.
+func (c *controlServer) Close() {
+	c.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = c.srv.Shutdown(ctx)
+}
+
+// startControlServer starts serving the control API on its unix socket.
+// The caller closes the returned server.
+func (d *Daemon) startControlServer() (*controlServer, error) {
+	socket := ControlSocketPath(d.config.TownRoot, d.patrolConfig)
+	if err := os.MkdirAll(filepath.Dir(socket), 0755); err != nil {
+		return nil, err
+	}
+	// A socket left by a daemon that didn't shut down cleanly blocks Listen.
+	// The daemon lock guarantees no other daemon is serving it.
+	_ = os.Remove(socket)
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", socket, err)
+	}
+	if err := os.Chmod(socket, 0600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("restricting %s: %w", socket, err)
+	}
+
+	baseCtx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{
+		Handler:           d.controlHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("control API: %v", err)
+		}
+		_ = os.Remove(socket)
+	}()
+	d.logger.Printf("Control API listening on %s", socket)
+	return &controlServer{srv: srv, cancel: cancel}, nil
+}
+
+// controlHandler returns the HTTP handler for the control API.
+func (d *Daemon) controlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", d.handleControlStatus)
+	mux.HandleFunc("GET /v1/agents", d.handleControlAgents)
+	mux.HandleFunc("POST /v1/jobs/{name}", d.handleControlJob)
+	mux.HandleFunc("POST /v1/spawn", d.handleControlSpawn)
+	mux.HandleFunc("POST /v1/nuke", d.handleControlNuke)
+	mux.HandleFunc("GET /v1/events", d.handleControlEvents)
+	mux.HandleFunc("GET /v1/queue", d.handleControlQueue)
+	mux.HandleFunc("POST /v1/queue", d.handleControlEnqueue)
+	mux.HandleFunc("DELETE /v1/queue/{id}", d.handleControlCancelQueued)
+	mux.HandleFunc("POST /v1/queue/{id}/priority", d.handleControlQueuePriority)
+	return mux
+}
+
+func (d *Daemon) handleControlStatus(w http.ResponseWriter, r *http.Request) {
+	status := ControlStatus{
+		PID:                os.Getpid(),
+		ShutdownInProgress: d.isShutdownInProgress(),
+		Patrols:            make(map[string]bool, len(controlPatrols)),
+		Jobs:               ControlJobNames(),
+	}
+	// The main loop owns the in-memory state; read what it last saved.
+	if state, err := LoadState(d.config.TownRoot); err == nil {
+		status.StartedAt = state.StartedAt
+		status.LastHeartbeat = state.LastHeartbeat
+		status.HeartbeatCount = state.HeartbeatCount
+	}
+	for _, p := range controlPatrols {
+		status.Patrols[p] = IsPatrolEnabled(d.patrolConfig, p)
+	}
+	writeControlJSON(w, http.StatusOK, status)
+}
+
+func (d *Daemon) handleControlAgents(w http.ResponseWriter, r *http.Request) {
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		writeControlError(w, http.StatusInternalServerError, fmt.Errorf("listing sessions: %w", err))
+		return
+	}
+	agents := []ControlAgent{}
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // not a Gas Town session
+		}
+		agents = append(agents, ControlAgent{
+			Session: name,
+			Role:    string(id.Role),
+			Rig:     id.Rig,
+			Name:    id.Name,
+			Address: id.Address(),
+		})
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Session < agents[j].Session })
+	writeControlJSON(w, http.StatusOK, agents)
+}
+
+func (d *Daemon) handleControlJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	job, ok := controlJobs[name]
+	if !ok {
+		writeControlError(w, http.StatusNotFound,
+			fmt.Errorf("unknown job %q (available: %s)", name, strings.Join(ControlJobNames(), ", ")))
+		return
+	}
+	if job.patrol != "" && !IsPatrolEnabled(d.patrolConfig, job.patrol) {
+		writeControlError(w, http.StatusConflict,
+			fmt.Errorf("patrol %s is not enabled in mayor/daemon.json", job.patrol))
+		return
+	}
+	if d.isShutdownInProgress() {
+		writeControlError(w, http.StatusConflict, fmt.Errorf("shutdown in progress"))
+		return
+	}
+
+	req := controlJobRequest{name: name, done: make(chan struct{})}
+	start := time.Now()
+	select {
+	case d.controlJobs <- req:
+	case <-r.Context().Done():
+		return
+	case <-d.ctx.Done():
+		writeControlError(w, http.StatusServiceUnavailable, fmt.Errorf("daemon shutting down"))
+		return
+	}
+	select {
+	case <-req.done:
+		writeControlJSON(w, http.StatusOK, ControlJobResult{Job: name, Duration: time.Since(start)})
+	case <-r.Context().Done():
+		// Client went away; the job still finishes on the main loop.
+	}
+}
+
+// runControlJob runs a job requested through the control API. Called from
+// the main loop.
+func (d *Daemon) runControlJob(req controlJobRequest, state *State) {
+	defer close(req.done)
+	if d.isShutdownInProgress() {
+		return
+	}
+	d.logger.Printf("control API: running job %s", req.name)
+	controlJobs[req.name].run(d, state)
+}
+
+func (d *Daemon) handleControlSpawn(w http.ResponseWriter, r *http.Request) {
+	var req ControlSpawnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+	if req.Bead == "" || req.Rig == "" {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("bead and rig are required"))
+		return
+	}
+	if err := validateControlArgs(req.Bead, req.Rig); err != nil {
+		writeControlError(w, http.StatusBadRequest, err)
+		return
+	}
+	d.runControlCommand(w, r, "sling", req.Bead, req.Rig)
+}
+
+func (d *Daemon) handleControlNuke(w http.ResponseWriter, r *http.Request) {
+	var req ControlNukeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+	if !strings.Contains(req.Polecat, "/") {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("polecat must be <rig>/<name>, got %q", req.Polecat))
+		return
+	}
+	if err := validateControlArgs(req.Polecat, req.Approval); err != nil {
+		writeControlError(w, http.StatusBadRequest, err)
+		return
+	}
+	args := []string{"polecat", "nuke", req.Polecat}
+	if req.Force {
+		args = append(args, "--force")
+	}
+	if req.Approval != "" {
+		args = append(args, "--approval", req.Approval)
+	}
+	d.runControlCommand(w, r, args...)
+}
+
+// validateControlArgs rejects request values that gt would parse as flags,
+// so a bead, rig or polecat of "--force" can't change what the command does.
+func validateControlArgs(values ...string) error {
+	for _, v := range values {
+		if strings.HasPrefix(v, "-") {
+			return fmt.Errorf("invalid argument %q: must not start with '-'", v)
+		}
+	}
+	return nil
+}
+
+func (d *Daemon) handleControlQueue(w http.ResponseWriter, r *http.Request) {
+	if d.workQueue == nil {
+		writeControlError(w, http.StatusServiceUnavailable, fmt.Errorf("work queue not available"))
+		return
+	}
+	items := d.workQueue.Items()
+	if items == nil {
+		items = []WorkItem{}
+	}
+	writeControlJSON(w, http.StatusOK, items)
+}
+
+func (d *Daemon) handleControlEnqueue(w http.ResponseWriter, r *http.Request) {
+	if d.workQueue == nil {
+		writeControlError(w, http.StatusServiceUnavailable, fmt.Errorf("work queue not available"))
+		return
+	}
+	var req ControlQueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+	if (req.Job == "") == (len(req.Args) == 0) {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("exactly one of job and args is required"))
+		return
+	}
+	if req.Delay < 0 || req.MaxAttempts < 0 {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("delay and max_attempts must not be negative"))
+		return
+	}
+
+	item := WorkItem{
+		Reason:      req.Reason,
+		Priority:    req.Priority,
+		NotBefore:   d.clock().Now().Add(req.Delay),
+		MaxAttempts: req.MaxAttempts,
+	}
+	if req.Job != "" {
+		job, ok := controlJobs[req.Job]
+		if !ok {
+			writeControlError(w, http.StatusNotFound,
+				fmt.Errorf("unknown job %q (available: %s)", req.Job, strings.Join(ControlJobNames(), ", ")))
+			return
+		}
+		if job.patrol != "" && !IsPatrolEnabled(d.patrolConfig, job.patrol) {
+			writeControlError(w, http.StatusConflict,
+				fmt.Errorf("patrol %s is not enabled in mayor/daemon.json", job.patrol))
+			return
+		}
+		item.Kind, item.Target = WorkKindJob, req.Job
+	} else {
+		item.Kind, item.Target, item.Args = WorkKindCommand, "gt "+strings.Join(req.Args, " "), req.Args
+		if item.MaxAttempts == 0 {
+			item.MaxAttempts = defaultWorkAttempts
+		}
+	}
+
+	queued, err := d.workQueue.Add(item)
+	if err != nil {
+		writeControlError(w, http.StatusInternalServerError, fmt.Errorf("saving work queue: %w", err))
+		return
+	}
+	writeControlJSON(w, http.StatusOK, queued)
+}
+
+func (d *Daemon) handleControlCancelQueued(w http.ResponseWriter, r *http.Request) {
+	if d.workQueue == nil {
+		writeControlError(w, http.StatusServiceUnavailable, fmt.Errorf("work queue not available"))
+		return
+	}
+	item, err := d.workQueue.Remove(r.PathValue("id"))
+	if err != nil {
+		writeControlError(w, workQueueErrorStatus(err), err)
+		return
+	}
+	writeControlJSON(w, http.StatusOK, item)
+}
+
+func (d *Daemon) handleControlQueuePriority(w http.ResponseWriter, r *http.Request) {
+	if d.workQueue == nil {
+		writeControlError(w, http.StatusServiceUnavailable, fmt.Errorf("work queue not available"))
+		return
+	}
+	var req ControlPriorityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+	item, err := d.workQueue.SetPriority(r.PathValue("id"), req.Priority)
+	if err != nil {
+		writeControlError(w, workQueueErrorStatus(err), err)
+		return
+	}
+	writeControlJSON(w, http.StatusOK, item)
+}
+
+// workQueueErrorStatus maps a WorkQueue error to an HTTP status.
+func workQueueErrorStatus(err error) int {
+	if errors.Is(err, ErrWorkItemNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// runControlCommand runs gt with args and writes its combined output.
+// Spawns and nukes go through gt so the API shares the CLI's logic.
+func (d *Daemon) runControlCommand(w http.ResponseWriter, r *http.Request, args ...string) {
+	cmd := exec.CommandContext(r.Context(), d.gtPath, args...) //nolint:gosec // G204: args are validated above
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		writeControlError(w, http.StatusInternalServerError,
+			fmt.Errorf("gt %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output))))
+		return
+	}
+	writeControlJSON(w, http.StatusOK, ControlCommandResult{Output: string(output)})
+}
+
+// controlEventsPollInterval is how often a followed event stream checks for
+// new events.
+const controlEventsPollInterval = 500 * time.Millisecond
+
+// handleControlEvents writes the last ?tail= events (default 20) from
+// .events.jsonl as JSON lines, then with ?follow=1 keeps streaming new ones
+// until the client disconnects.
+func (d *Daemon) handleControlEvents(w http.ResponseWriter, r *http.Request) {
+	tail := 20
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeControlError(w, http.StatusBadRequest, fmt.Errorf("invalid tail %q", v))
+			return
+		}
+		tail = n
+	}
+	follow := r.URL.Query().Get("follow") == "1" || r.URL.Query().Get("follow") == "true"
+
+	path := filepath.Join(d.config.TownRoot, events.EventsFile)
+	lines, offset, err := readEventTail(path, tail)
+	if err != nil {
+		writeControlError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	for _, line := range lines {
+		_, _ = w.Write(append(line, '\n'))
+	}
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	if !follow {
+		return
+	}
+
+	ticker := time.NewTicker(controlEventsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		lines, offset, err = readEventsFrom(path, offset)
+		if err != nil {
+			return
+		}
+		for _, line := range lines {
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				return
+			}
+		}
+		if len(lines) > 0 && flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// readEventTail returns the last n complete lines of the events file and the
+// offset just past them. A missing file has no events.
+func readEventTail(path string, n int) ([][]byte, int64, error) {
+	lines, offset, err := readEventsFrom(path, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, offset, nil
+}
+
+// readEventsFrom returns the complete lines of the events file after offset
+// and the offset just past the last complete line. A partially written last
+// line is left for the next read. If the file shrank (rotated), reading
+// restarts from the beginning.
+func readEventsFrom(path string, offset int64) ([][]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	var lines [][]byte
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // EOF, possibly with a partial line we'll pick up later
+		}
+		offset += int64(len(line))
+		if line = []byte(strings.TrimSpace(string(line))); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines, offset, nil
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeControlError(w http.ResponseWriter, status int, err error) {
+	writeControlJSON(w, status, controlError{Error: err.Error()})
+}
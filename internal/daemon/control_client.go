@@ -0,0 +1,204 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// ErrControlAPIUnavailable means the daemon's control API can't be used:
+// it is disabled, or the daemon isn't running. Callers fall back to doing
+// the work themselves.
+var ErrControlAPIUnavailable = errors.New("daemon control API unavailable")
+
+// ControlClient talks to a running daemon's control API.
+type ControlClient struct {
+	socket string
+	http   *http.Client
+}
+
+// NewControlClient returns a client for the town's daemon, or
+// ErrControlAPIUnavailable if the control API is disabled or not listening.
+func NewControlClient(townRoot string) (*ControlClient, error) {
+	config := LoadPatrolConfig(townRoot)
+	if !IsControlAPIEnabled(config) {
+		return nil, ErrControlAPIUnavailable
+	}
+	socket := ControlSocketPath(townRoot, config)
+	if _, err := os.Stat(socket); err != nil {
+		return nil, ErrControlAPIUnavailable
+	}
+	return &ControlClient{
+		socket: socket,
+		http: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}},
+	}, nil
+}
+
+// Status returns the daemon's status.
+func (c *ControlClient) Status(ctx context.Context) (*ControlStatus, error) {
+	var status ControlStatus
+	if err := c.do(ctx, http.MethodGet, "/v1/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Agents lists the Gas Town sessions the daemon sees.
+func (c *ControlClient) Agents(ctx context.Context) ([]ControlAgent, error) {
+	var agents []ControlAgent
+	if err := c.do(ctx, http.MethodGet, "/v1/agents", nil, &agents); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// RunJob runs a daemon job now and waits for it to finish.
+func (c *ControlClient) RunJob(ctx context.Context, name string) (*ControlJobResult, error) {
+	var result ControlJobResult
+	if err := c.do(ctx, http.MethodPost, "/v1/jobs/"+url.PathEscape(name), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Spawn asks the daemon to sling bead to rig, returning gt's output.
+func (c *ControlClient) Spawn(ctx context.Context, bead, rig string) (string, error) {
+	var result ControlCommandResult
+	err := c.do(ctx, http.MethodPost, "/v1/spawn", ControlSpawnRequest{Bead: bead, Rig: rig}, &result)
+	return result.Output, err
+}
+
+// Nuke asks the daemon to nuke a polecat (rig/name), returning gt's output.
+// approval is a token from gt approve nuke, or "" if the town doesn't
+// require one.
+func (c *ControlClient) Nuke(ctx context.Context, polecat string, force bool, approval string) (string, error) {
+	var result ControlCommandResult
+	req := ControlNukeRequest{Polecat: polecat, Force: force, Approval: approval}
+	err := c.do(ctx, http.MethodPost, "/v1/nuke", req, &result)
+	return result.Output, err
+}
+
+// Queue lists the daemon's work queue in run order.
+func (c *ControlClient) Queue(ctx context.Context) ([]WorkItem, error) {
+	var items []WorkItem
+	if err := c.do(ctx, http.MethodGet, "/v1/queue", nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Enqueue queues a job or gt command, returning the queued item.
+func (c *ControlClient) Enqueue(ctx context.Context, req ControlQueueRequest) (*WorkItem, error) {
+	var item WorkItem
+	if err := c.do(ctx, http.MethodPost, "/v1/queue", req, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CancelQueued removes a queued item, returning it.
+func (c *ControlClient) CancelQueued(ctx context.Context, id string) (*WorkItem, error) {
+	var item WorkItem
+	if err := c.do(ctx, http.MethodDelete, "/v1/queue/"+url.PathEscape(id), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SetQueuePriority changes a queued item's priority, returning the item.
+func (c *ControlClient) SetQueuePriority(ctx context.Context, id string, priority int) (*WorkItem, error) {
+	var item WorkItem
+	path := "/v1/queue/" + url.PathEscape(id) + "/priority"
+	if err := c.do(ctx, http.MethodPost, path, ControlPriorityRequest{Priority: priority}, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Events calls fn with each of the last tail events (raw JSON lines). With
+// follow, it keeps calling fn for new events until ctx is canceled or fn
+// returns an error.
+func (c *ControlClient) Events(ctx context.Context, tail int, follow bool, fn func(line []byte) error) error {
+	q := url.Values{"tail": {strconv.Itoa(tail)}}
+	if follow {
+		q.Set("follow", "1")
+	}
+	resp, err := c.request(ctx, http.MethodGet, "/v1/events?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out.
+func (c *ControlClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding control API response: %w", err)
+	}
+	return nil
+}
+
+// request sends a request and returns the response, converting non-2xx
+// responses into errors.
+func (c *ControlClient) request(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	// The host is ignored; the transport always dials the socket.
+	req, err := http.NewRequestWithContext(ctx, method, "http://daemon"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrControlAPIUnavailable, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		var e controlError
+		if err := json.NewDecoder(resp.Body).Decode(&e); err == nil && e.Error != "" {
+			return nil, errors.New(e.Error)
+		}
+		return nil, fmt.Errorf("control API: %s", resp.Status)
+	}
+	return resp, nil
+}
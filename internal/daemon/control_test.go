@@ -0,0 +1,289 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// newControlTestDaemon starts a control API for a daemon in a temp town,
+// with a fake main loop that acknowledges jobs without running them.
+func newControlTestDaemon(t *testing.T, patrols *PatrolsConfig) (*Daemon, *ControlClient, chan string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	config := &DaemonPatrolConfig{
+		Patrols:    patrols,
+		ControlAPI: &ControlAPIConfig{Enabled: true},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(PatrolConfigFile(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		config:       &Config{TownRoot: townRoot},
+		patrolConfig: config,
+		logger:       log.New(io.Discard, "", 0),
+		ctx:          ctx,
+		cancel:       cancel,
+		controlJobs:  make(chan controlJobRequest),
+		workQueue:    NewWorkQueue(townRoot),
+	}
+	ran := make(chan string, 10)
+	go func() {
+		for {
+			select {
+			case job := <-d.controlJobs:
+				ran <- job.name
+				close(job.done)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	srv, err := d.startControlServer()
+	if err != nil {
+		t.Fatalf("startControlServer: %v", err)
+	}
+	t.Cleanup(func() {
+		srv.Close()
+		cancel()
+	})
+
+	client, err := NewControlClient(townRoot)
+	if err != nil {
+		t.Fatalf("NewControlClient: %v", err)
+	}
+	return d, client, ran
+}
+
+func TestControlAPIUnavailable(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := NewControlClient(townRoot); err != ErrControlAPIUnavailable {
+		t.Errorf("NewControlClient(no config) error = %v, want ErrControlAPIUnavailable", err)
+	}
+}
+
+func TestControlSocketPath(t *testing.T) {
+	if got := ControlSocketPath("/town", nil); got != "/town/daemon/control.sock" {
+		t.Errorf("default socket = %q", got)
+	}
+	config := &DaemonPatrolConfig{ControlAPI: &ControlAPIConfig{Socket: "run/gt.sock"}}
+	if got := ControlSocketPath("/town", config); got != "/town/run/gt.sock" {
+		t.Errorf("relative socket = %q", got)
+	}
+	config.ControlAPI.Socket = "/var/run/gt.sock"
+	if got := ControlSocketPath("/town", config); got != "/var/run/gt.sock" {
+		t.Errorf("absolute socket = %q", got)
+	}
+}
+
+func TestControlStatus(t *testing.T) {
+	d, client, _ := newControlTestDaemon(t, &PatrolsConfig{ChangeFeed: &ChangeFeedConfig{Enabled: true}})
+	started := time.Now().Truncate(time.Second)
+	if err := SaveState(d.config.TownRoot, &State{Running: true, StartedAt: started, HeartbeatCount: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := client.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.PID != os.Getpid() || status.HeartbeatCount != 7 || !status.StartedAt.Equal(started) {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if !status.Patrols["change_feed"] || status.Patrols["cost_enforce"] {
+		t.Errorf("patrols = %v, want change_feed on and cost_enforce off", status.Patrols)
+	}
+	if len(status.Jobs) != len(controlJobs) {
+		t.Errorf("jobs = %v", status.Jobs)
+	}
+}
+
+func TestControlRunJob(t *testing.T) {
+	_, client, ran := newControlTestDaemon(t, &PatrolsConfig{JSONLExport: &JSONLExportConfig{Enabled: true}})
+	ctx := context.Background()
+
+	if _, err := client.RunJob(ctx, "jsonl_export"); err != nil {
+		t.Fatalf("RunJob(jsonl_export): %v", err)
+	}
+	if got := <-ran; got != "jsonl_export" {
+		t.Errorf("main loop ran %q, want jsonl_export", got)
+	}
+
+	if _, err := client.RunJob(ctx, "cost_enforce"); err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Errorf("RunJob(disabled patrol) error = %v, want not enabled", err)
+	}
+	if _, err := client.RunJob(ctx, "bogus"); err == nil || !strings.Contains(err.Error(), "unknown job") {
+		t.Errorf("RunJob(bogus) error = %v, want unknown job", err)
+	}
+}
+
+func TestControlSpawnNukeValidation(t *testing.T) {
+	_, client, _ := newControlTestDaemon(t, nil)
+	ctx := context.Background()
+
+	if _, err := client.Spawn(ctx, "", "gastown"); err == nil {
+		t.Error("expected error for spawn without bead")
+	}
+	if _, err := client.Nuke(ctx, "toast", false, ""); err == nil {
+		t.Error("expected error for nuke without rig")
+	}
+	if _, err := client.Spawn(ctx, "--help", "gastown"); err == nil {
+		t.Error("expected error for spawn with a flag-like bead")
+	}
+	if _, err := client.Spawn(ctx, "gt-abc", "-x"); err == nil {
+		t.Error("expected error for spawn with a flag-like rig")
+	}
+	if _, err := client.Nuke(ctx, "-x/toast", false, ""); err == nil {
+		t.Error("expected error for nuke with a flag-like polecat")
+	}
+}
+
+func TestControlNukePassesApproval(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as gt")
+	}
+	d, client, _ := newControlTestDaemon(t, nil)
+	d.gtPath = filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(d.gtPath, []byte("#!/bin/sh\necho \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := client.Nuke(context.Background(), "gastown/toast", true, "nuke-abc123")
+	if err != nil {
+		t.Fatalf("Nuke: %v", err)
+	}
+	if want := "polecat nuke gastown/toast --force --approval nuke-abc123"; strings.TrimSpace(out) != want {
+		t.Errorf("gt args = %q, want %q", strings.TrimSpace(out), want)
+	}
+}
+
+func TestControlQueue(t *testing.T) {
+	_, client, _ := newControlTestDaemon(t, &PatrolsConfig{JSONLExport: &JSONLExportConfig{Enabled: true}})
+	ctx := context.Background()
+
+	job, err := client.Enqueue(ctx, ControlQueueRequest{Job: "jsonl_export", Delay: time.Hour})
+	if err != nil {
+		t.Fatalf("Enqueue(job): %v", err)
+	}
+	cmd, err := client.Enqueue(ctx, ControlQueueRequest{Args: []string{"mq", "retry", "gt-abc"}})
+	if err != nil {
+		t.Fatalf("Enqueue(command): %v", err)
+	}
+	if cmd.Kind != WorkKindCommand || cmd.MaxAttempts != defaultWorkAttempts {
+		t.Errorf("command item = %+v, want kind command with %d attempts", cmd, defaultWorkAttempts)
+	}
+
+	if _, err := client.Enqueue(ctx, ControlQueueRequest{Job: "cost_enforce"}); err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Errorf("Enqueue(disabled patrol) error = %v, want not enabled", err)
+	}
+	if _, err := client.Enqueue(ctx, ControlQueueRequest{}); err == nil {
+		t.Error("expected error for enqueue without job or args")
+	}
+
+	if _, err := client.SetQueuePriority(ctx, job.ID, 5); err != nil {
+		t.Fatalf("SetQueuePriority: %v", err)
+	}
+	items, err := client.Queue(ctx)
+	if err != nil {
+		t.Fatalf("Queue: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != job.ID {
+		t.Fatalf("Queue = %+v, want reprioritized %s first", items, job.ID)
+	}
+
+	if _, err := client.CancelQueued(ctx, cmd.ID); err != nil {
+		t.Fatalf("CancelQueued: %v", err)
+	}
+	if _, err := client.CancelQueued(ctx, cmd.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("CancelQueued twice error = %v, want not found", err)
+	}
+	if items, _ := client.Queue(ctx); len(items) != 1 {
+		t.Errorf("Queue after cancel has %d items, want 1", len(items))
+	}
+}
+
+func TestControlEvents(t *testing.T) {
+	d, client, _ := newControlTestDaemon(t, nil)
+	path := filepath.Join(d.config.TownRoot, events.EventsFile)
+	if err := os.WriteFile(path, []byte("{\"n\":1}\n{\"n\":2}\n{\"n\":3}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	err := client.Events(context.Background(), 2, false, func(line []byte) error {
+		got = append(got, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Events: %v", err)
+	}
+	if strings.Join(got, ",") != `{"n":2},{"n":3}` {
+		t.Errorf("tail = %v", got)
+	}
+
+	// Follow: an event appended after the stream starts is delivered.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	lines := make(chan string, 10)
+	go func() {
+		_ = client.Events(ctx, 0, true, func(line []byte) error {
+			lines <- string(line)
+			return nil
+		})
+	}()
+	time.Sleep(100 * time.Millisecond)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{\"n\":4}\n")
+	_ = f.Close()
+
+	select {
+	case line := <-lines:
+		if line != `{"n":4}` {
+			t.Errorf("followed event = %s", line)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for followed event")
+	}
+}
+
+func TestReadEventsFromPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte("{\"a\":1}\n{\"b\":"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lines, offset, err := readEventsFrom(path, 0)
+	if err != nil || len(lines) != 1 || offset != 8 {
+		t.Fatalf("readEventsFrom = %d lines, offset %d, err %v", len(lines), offset, err)
+	}
+
+	// Completing the line makes it available from the saved offset.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString("2}\n")
+	_ = f.Close()
+	lines, _, _ = readEventsFrom(path, offset)
+	if len(lines) != 1 || string(lines[0]) != `{"b":2}` {
+		t.Errorf("after completion got %q", lines)
+	}
+}
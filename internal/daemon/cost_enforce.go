@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultCostEnforceInterval = 5 * time.Minute
+
+// costEnforceInterval returns the configured enforcement interval, or the default (5m).
+func costEnforceInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.CostEnforce != nil {
+		return config.Patrols.CostEnforce.Interval.Or(defaultCostEnforceInterval)
+	}
+	return defaultCostEnforceInterval
+}
+
+// enforceCostPolicy runs gt costs enforce to downgrade sessions that crossed
+// a cost_policy threshold. Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) enforceCostPolicy() {
+	if !IsPatrolEnabled(d.patrolConfig, "cost_enforce") {
+		return
+	}
+
+	cmd := exec.Command(d.gtPath, "costs", "enforce")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("cost_enforce: %v: %s", err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" && !strings.Contains(line, "No sessions over a cost threshold") {
+			d.logger.Printf("cost_enforce: %s", line)
+		}
+	}
+}
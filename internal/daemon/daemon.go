@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -12,23 +13,28 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/clock"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crashlog"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/execrec"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/maintenance"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/quarantine"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -52,6 +58,10 @@ type Daemon struct {
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
 
+	// doltWatchdog restarts the gt dolt start server if it dies; created
+	// on first use. Only accessed from heartbeat loop goroutine.
+	doltWatchdog *doltWatchdog
+
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
@@ -67,12 +77,43 @@ type Daemon struct {
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	syncFailures map[string]int
 
+	// staleHeartbeats maps polecat sessions to the last_heartbeat value already
+	// reported as stale, so the witness is notified once per stale heartbeat.
+	// Only accessed from heartbeat loop goroutine - no sync needed.
+	staleHeartbeats map[string]string
+
+	// Role session health (witness, refinery, deacon, mayor), keyed by
+	// agent ID: when the daemon last started each session, whether it was
+	// seen alive (so a vanished session counts as a crash), and which crash
+	// loops the mayor was told about. Only accessed from heartbeat loop
+	// goroutine - no sync needed.
+	roleLastStarted       map[string]time.Time
+	roleSeenAlive         map[string]bool
+	roleCrashLoopReported map[string]bool
+
 	// PATCH-006: Resolved binary paths to avoid PATH issues in subprocesses.
 	gtPath string
 	bdPath string
 
 	// Restart tracking with exponential backoff to prevent crash loops
 	restartTracker *RestartTracker
+
+	// workQueue holds deferred actions (pending restarts, scheduled jobs
+	// and commands); see workqueue.go.
+	workQueue *WorkQueue
+
+	// runner executes bd subprocesses. Selected via GT_EXEC_MODE so daemon
+	// interactions can be recorded and replayed in tests (see execrec).
+	// Nil means live execution.
+	runner execrec.Executor
+
+	// clockOverride is the clock for heartbeats, grace periods, and staleness
+	// checks. Nil means the package clock (see SetClock).
+	clockOverride clock.Clock
+
+	// controlJobs carries jobs triggered through the control API to the
+	// main loop. Nil (never ready) when the control API is disabled.
+	controlJobs chan controlJobRequest
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -140,6 +181,16 @@ func New(config *Config) (*Daemon, error) {
 		logger.Printf("Warning: failed to load restart state: %v", err)
 	}
 
+	workQueue := NewWorkQueue(config.TownRoot)
+	if err := workQueue.Load(); err != nil {
+		logger.Printf("Warning: failed to load work queue: %v", err)
+	}
+
+	runner, err := execrec.FromEnv()
+	if err != nil {
+		logger.Printf("Warning: %v (falling back to live execution)", err)
+	}
+
 	return &Daemon{
 		config:         config,
 		patrolConfig:   patrolConfig,
@@ -151,29 +202,46 @@ func New(config *Config) (*Daemon, error) {
 		gtPath:         gtPath,
 		bdPath:         bdPath,
 		restartTracker: restartTracker,
+		workQueue:      workQueue,
+		runner:         runner,
 	}, nil
 }
 
+// executor returns the executor for bd subprocesses, defaulting to live execution.
+func (d *Daemon) executor() execrec.Executor {
+	if d.runner == nil {
+		return execrec.Live{}
+	}
+	return d.runner
+}
+
+// clock returns the daemon's clock, defaulting to the package clock.
+func (d *Daemon) clock() clock.Clock {
+	if d.clockOverride == nil {
+		return clk
+	}
+	return d.clockOverride
+}
+
+// bdCommand returns the resolved bd binary path, falling back to PATH lookup.
+func (d *Daemon) bdCommand() string {
+	if d.bdPath == "" {
+		return "bd"
+	}
+	return d.bdPath
+}
+
 // Run starts the daemon main loop.
 func (d *Daemon) Run() error {
 	d.logger.Printf("Daemon starting (PID %d)", os.Getpid())
 
-	// Acquire exclusive lock to prevent multiple daemons from running.
-	// This prevents the TOCTOU race condition where multiple concurrent starts
-	// can all pass the IsRunning() check before any writes the PID file.
-	// Uses gofrs/flock for cross-platform compatibility (Unix + Windows).
-	lockFile := filepath.Join(d.config.TownRoot, "daemon", "daemon.lock")
-	fileLock := flock.New(lockFile)
-
-	// Try to acquire exclusive lock (non-blocking)
-	locked, err := fileLock.TryLock()
+	// Become the town's leader daemon (or wait for it in standby mode).
+	fileLock, lease, err := d.acquireLeadership()
 	if err != nil {
-		return fmt.Errorf("acquiring lock: %w", err)
-	}
-	if !locked {
-		return fmt.Errorf("daemon already running (lock held by another process)")
+		return err
 	}
 	defer func() { _ = fileLock.Unlock() }()
+	defer releaseLease(d.config.TownRoot, lease)
 
 	// Pre-flight check: all rigs must be on Dolt backend.
 	if err := d.checkAllRigsDolt(); err != nil {
@@ -199,7 +267,7 @@ func (d *Daemon) Run() error {
 	state := &State{
 		Running:   true,
 		PID:       os.Getpid(),
-		StartedAt: time.Now(),
+		StartedAt: d.clock().Now(),
 	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
@@ -245,6 +313,17 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Renew the leader lease well within its TTL from its own goroutine:
+	// patrols run synchronously in the loop below and some (backup verify,
+	// dolt gc, JSONL export) can outlast the TTL on their own.
+	leaseLost, stopLease := d.renewLeaseLoop(lease)
+	defer stopLease()
+
+	// Run deferred work (restarts waiting out backoff, scheduled jobs and
+	// commands) when it comes due rather than on the next heartbeat.
+	workQueueTicker := time.NewTicker(workQueueInterval)
+	defer workQueueTicker.Stop()
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -271,6 +350,162 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
 	}
 
+	// Start scheduled JSONL export ticker if configured. Rigs running with
+	// sync.mode=dolt-native skip per-write export, so this keeps the
+	// git-tracked issues.jsonl from going stale (default every 30 min).
+	var jsonlExportTicker *time.Ticker
+	var jsonlExportChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "jsonl_export") {
+		interval := jsonlExportInterval(d.patrolConfig)
+		jsonlExportTicker = time.NewTicker(interval)
+		jsonlExportChan = jsonlExportTicker.C
+		defer jsonlExportTicker.Stop()
+		d.logger.Printf("JSONL export ticker started (interval %v)", interval)
+	}
+
+	// Start bead change feed ticker if configured. Diffs the issues table
+	// between Dolt commits and publishes bead events (default every 1 min).
+	var changeFeedTicker *time.Ticker
+	var changeFeedChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "change_feed") {
+		interval := changeFeedInterval(d.patrolConfig)
+		changeFeedTicker = time.NewTicker(interval)
+		changeFeedChan = changeFeedTicker.C
+		defer changeFeedTicker.Stop()
+		d.logger.Printf("Bead change feed ticker started (interval %v)", interval)
+	}
+
+	// Start cost policy enforcement ticker if configured. Downgrades sessions
+	// whose cost crossed a cost_policy threshold (default every 5 min).
+	var costEnforceTicker *time.Ticker
+	var costEnforceChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "cost_enforce") {
+		interval := costEnforceInterval(d.patrolConfig)
+		costEnforceTicker = time.NewTicker(interval)
+		costEnforceChan = costEnforceTicker.C
+		defer costEnforceTicker.Stop()
+		d.logger.Printf("Cost enforcement ticker started (interval %v)", interval)
+	}
+
+	// Start backup restore verification ticker if configured. Restores a
+	// random rig's backup into a scratch directory (default daily).
+	var backupVerifyTicker *time.Ticker
+	var backupVerifyChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "backup_verify") {
+		interval := backupVerifyInterval(d.patrolConfig)
+		backupVerifyTicker = time.NewTicker(interval)
+		backupVerifyChan = backupVerifyTicker.C
+		defer backupVerifyTicker.Stop()
+		d.logger.Printf("Backup restore verification ticker started (interval %v)", interval)
+	}
+
+	// Start analytics export ticker if configured. Writes incremental
+	// Parquet/CSV exports of bead history for data warehouses (default hourly).
+	var analyticsExportTicker *time.Ticker
+	var analyticsExportChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "analytics_export") {
+		interval := analyticsExportInterval(d.patrolConfig)
+		analyticsExportTicker = time.NewTicker(interval)
+		analyticsExportChan = analyticsExportTicker.C
+		defer analyticsExportTicker.Stop()
+		d.logger.Printf("Analytics export ticker started (interval %v)", interval)
+	}
+
+	// Start session prune ticker if configured. Kills rig tmux sessions
+	// left behind by removed rigs and nuked polecats.
+	var sessionPruneTicker *time.Ticker
+	var sessionPruneChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "session_prune") {
+		interval := sessionPruneInterval(d.patrolConfig)
+		sessionPruneTicker = time.NewTicker(interval)
+		sessionPruneChan = sessionPruneTicker.C
+		defer sessionPruneTicker.Stop()
+		d.logger.Printf("Session prune ticker started (interval %v)", interval)
+	}
+
+	// Start bead archive ticker if configured. Moves old closed wisps and
+	// digests out of the hot issues tables (default daily).
+	var beadArchiveTicker *time.Ticker
+	var beadArchiveChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "bead_archive") {
+		interval := beadArchiveInterval(d.patrolConfig)
+		beadArchiveTicker = time.NewTicker(interval)
+		beadArchiveChan = beadArchiveTicker.C
+		defer beadArchiveTicker.Stop()
+		d.logger.Printf("Bead archive ticker started (interval %v)", interval)
+	}
+
+	// Start branch prune ticker if configured. Deletes Dolt branches left
+	// behind by dead polecats (default daily).
+	var branchPruneTicker *time.Ticker
+	var branchPruneChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "branch_prune") {
+		interval := branchPruneInterval(d.patrolConfig)
+		branchPruneTicker = time.NewTicker(interval)
+		branchPruneChan = branchPruneTicker.C
+		defer branchPruneTicker.Stop()
+		d.logger.Printf("Branch prune ticker started (interval %v)", interval)
+	}
+
+	// Start Dolt GC ticker if configured. Garbage-collects the rig databases
+	// once .dolt-data passes a size threshold (checked hourly by default).
+	var doltGCTicker *time.Ticker
+	var doltGCChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "dolt_gc") {
+		interval := doltGCInterval(d.patrolConfig)
+		doltGCTicker = time.NewTicker(interval)
+		doltGCChan = doltGCTicker.C
+		defer doltGCTicker.Stop()
+		d.logger.Printf("Dolt GC ticker started (interval %v)", interval)
+	}
+
+	// Start Dolt metrics sampling ticker if configured. Keeps a rolling
+	// history of server health for gt dolt metrics (default every 5m).
+	var doltMetricsTicker *time.Ticker
+	var doltMetricsChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "dolt_metrics") {
+		interval := doltMetricsInterval(d.patrolConfig)
+		doltMetricsTicker = time.NewTicker(interval)
+		doltMetricsChan = doltMetricsTicker.C
+		defer doltMetricsTicker.Stop()
+		d.logger.Printf("Dolt metrics sampling ticker started (interval %v)", interval)
+	}
+
+	// Start SLA check ticker if configured. Escalates SLA timers on beads
+	// that passed their deadline (default every 15m).
+	var slaCheckTicker *time.Ticker
+	var slaCheckChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "sla_check") {
+		interval := slaCheckInterval(d.patrolConfig)
+		slaCheckTicker = time.NewTicker(interval)
+		slaCheckChan = slaCheckTicker.C
+		defer slaCheckTicker.Stop()
+		d.logger.Printf("SLA check ticker started (interval %v)", interval)
+	}
+
+	// Start the local control API if configured. gt uses it as a thin client
+	// for status, jobs, and spawns while the daemon is running.
+	if IsControlAPIEnabled(d.patrolConfig) {
+		d.controlJobs = make(chan controlJobRequest)
+		ctrl, err := d.startControlServer()
+		if err != nil {
+			d.logger.Printf("Warning: failed to start control API: %v", err)
+		} else {
+			defer ctrl.Close()
+		}
+	}
+
+	// Start the metrics endpoint if configured, so Dolt health can be
+	// scraped by Prometheus.
+	if IsMetricsEnabled(d.patrolConfig) {
+		srv, err := d.startMetricsServer()
+		if err != nil {
+			d.logger.Printf("Warning: failed to start metrics endpoint: %v", err)
+		} else {
+			defer stopMetricsServer(srv)
+		}
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -308,6 +543,87 @@ func (d *Daemon) Run() error {
 				d.pushDoltRemotes()
 			}
 
+		case <-jsonlExportChan:
+			// Scheduled JSONL export for dolt-native rigs.
+			if !d.isShutdownInProgress() {
+				d.exportJSONL()
+			}
+
+		case <-changeFeedChan:
+			// Bead change feed from dolt_diff between checkpoints.
+			if !d.isShutdownInProgress() {
+				d.pollChangeFeed()
+			}
+
+		case <-costEnforceChan:
+			// Budget-aware model downgrades per the town cost_policy.
+			if !d.isShutdownInProgress() {
+				d.enforceCostPolicy()
+			}
+
+		case <-backupVerifyChan:
+			// Prove the Dolt backups actually restore.
+			if !d.isShutdownInProgress() {
+				d.verifyBackupRestore()
+			}
+
+		case <-analyticsExportChan:
+			// Incremental bead history export for analytics pipelines.
+			if !d.isShutdownInProgress() {
+				d.exportAnalytics()
+			}
+
+		case <-sessionPruneChan:
+			// Orphaned rig tmux sessions no agent owns.
+			if !d.isShutdownInProgress() {
+				d.pruneOrphanSessions()
+			}
+
+		case <-beadArchiveChan:
+			// Keep closed wisps and digests out of the hot issues tables.
+			if !d.isShutdownInProgress() {
+				d.archiveClosedBeads()
+			}
+
+		case <-branchPruneChan:
+			// Dolt branches of polecats that crashed or were nuked.
+			if !d.isShutdownInProgress() {
+				d.pruneDeadPolecatBranches()
+			}
+
+		case <-doltGCChan:
+			// Unreferenced chunks piling up in .dolt-data.
+			if !d.isShutdownInProgress() {
+				d.collectDoltGarbage()
+			}
+
+		case <-doltMetricsChan:
+			// Health history for capacity planning.
+			if !d.isShutdownInProgress() {
+				d.sampleDoltMetrics()
+			}
+
+		case <-slaCheckChan:
+			// Beads whose SLA timers ran out.
+			if !d.isShutdownInProgress() {
+				d.checkSLATimers()
+			}
+
+		case err := <-leaseLost:
+			// Another daemon took over while this one was stalled; two
+			// leaders would double every restart and nudge.
+			d.logger.Printf("%v; shutting down", err)
+			return d.shutdown(state)
+
+		case <-workQueueTicker.C:
+			if !d.isShutdownInProgress() {
+				d.runDueWork(state)
+			}
+
+		case job := <-d.controlJobs:
+			// Job triggered through the control API.
+			d.runControlJob(job, state)
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -339,11 +655,19 @@ func (d *Daemon) heartbeat(state *State) {
 		return
 	}
 
+	// Skip heartbeat while gt panic has the town frozen, so the daemon
+	// neither restarts interrupted agents nor triggers spawns.
+	if maintenance.IsActive(d.config.TownRoot) {
+		d.logger.Println("Maintenance mode (gt panic), skipping heartbeat")
+		return
+	}
+
 	d.logger.Println("Heartbeat starting (recovery-focused)")
 
 	// 0. Ensure Dolt server is running (if configured)
 	// This must happen before beads operations that depend on Dolt.
 	d.ensureDoltServerRunning()
+	d.watchDoltServer()
 
 	// 1. Ensure Deacon is running (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
@@ -426,7 +750,7 @@ func (d *Daemon) heartbeat(state *State) {
 	d.pruneStaleBranches()
 
 	// Update state
-	state.LastHeartbeat = time.Now()
+	state.LastHeartbeat = d.clock().Now()
 	state.HeartbeatCount++
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
@@ -540,7 +864,7 @@ func (d *Daemon) ensureBootRunning() {
 // runDegradedBootTriage performs mechanical Boot logic without AI reasoning.
 // This is for degraded mode when tmux is unavailable.
 func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
-	startTime := time.Now()
+	startTime := d.clock().Now()
 	status := &boot.Status{
 		Running:   true,
 		StartedAt: startTime,
@@ -562,7 +886,7 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	}
 
 	status.Running = false
-	status.CompletedAt = time.Now()
+	status.CompletedAt = d.clock().Now()
 
 	if err := b.SaveStatus(status); err != nil {
 		d.logger.Printf("Warning: failed to save Boot status: %v", err)
@@ -574,44 +898,27 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 func (d *Daemon) ensureDeaconRunning() {
 	const agentID = "deacon"
 
-	// Check restart tracker for backoff/crash loop
-	if d.restartTracker != nil {
-		if d.restartTracker.IsInCrashLoop(agentID) {
-			d.logger.Printf("Deacon is in crash loop, skipping restart (use 'gt daemon clear-backoff deacon' to reset)")
-			return
-		}
-		if !d.restartTracker.CanRestart(agentID) {
-			remaining := d.restartTracker.GetBackoffRemaining(agentID)
-			d.logger.Printf("Deacon restart in backoff, %s remaining", remaining.Round(time.Second))
-			return
-		}
+	// Crash detection, startup grace, and restart backoff/crash loop
+	if !d.checkRoleSession("deacon", agentID, d.getDeaconSessionName()) {
+		return
 	}
 
 	mgr := deacon.NewManager(d.config.TownRoot)
 
 	if err := mgr.Start(""); err != nil {
 		if err == deacon.ErrAlreadyRunning {
-			// Deacon is running - record success to reset backoff
-			if d.restartTracker != nil {
-				d.restartTracker.RecordSuccess(agentID)
-			}
 			return
 		}
 		d.logger.Printf("Error starting Deacon: %v", err)
 		return
 	}
 
-	// Record this restart attempt for backoff tracking
-	if d.restartTracker != nil {
-		d.restartTracker.RecordRestart(agentID)
-		if err := d.restartTracker.Save(); err != nil {
-			d.logger.Printf("Warning: failed to save restart state: %v", err)
-		}
-	}
+	// Record this restart attempt for grace period and backoff tracking
+	d.recordRoleStart(agentID)
 
 	// Track when we started the Deacon to prevent race condition in checkDeaconHeartbeat.
 	// The heartbeat file will still be stale until the Deacon runs a full patrol cycle.
-	d.deaconLastStarted = time.Now()
+	d.deaconLastStarted = d.clock().Now()
 	d.logger.Println("Deacon started successfully")
 }
 
@@ -637,7 +944,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 
 	// Check if we recently started a Deacon
 	if !d.deaconLastStarted.IsZero() {
-		timeSinceStart := time.Since(d.deaconLastStarted)
+		timeSinceStart := d.clock().Since(d.deaconLastStarted)
 
 		if hb == nil {
 			// No heartbeat file exists
@@ -725,6 +1032,8 @@ func (d *Daemon) restartStuckDeacon(sessionName string) {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
 		}
 	}
+	// Stuck, not crashed: don't record the killed session as a crash.
+	d.setRoleSeenAlive("deacon", false)
 	// Spawn new Deacon immediately
 	d.ensureDeaconRunning()
 }
@@ -748,6 +1057,12 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		return
 	}
 
+	// Crash detection, startup grace, and restart backoff/crash loop
+	agentID := rigName + "/witness"
+	if !d.checkRoleSession("witness", agentID, session.WitnessSessionName(session.PrefixFor(rigName))) {
+		return
+	}
+
 	// Manager.Start() handles: zombie detection, session creation, env vars, theming,
 	// startup readiness waits, and crucially - startup/propulsion nudges (GUPP).
 	// It returns ErrAlreadyRunning if Claude is already running in tmux.
@@ -766,6 +1081,7 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		d.logger.Printf("Error starting witness for %s: %v", rigName, err)
 		return
 	}
+	d.recordRoleStart(agentID)
 
 	d.logger.Printf("Witness session for %s started successfully", rigName)
 }
@@ -789,6 +1105,12 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 		return
 	}
 
+	// Crash detection, startup grace, and restart backoff/crash loop
+	agentID := rigName + "/refinery"
+	if !d.checkRoleSession("refinery", agentID, session.RefinerySessionName(session.PrefixFor(rigName))) {
+		return
+	}
+
 	// Manager.Start() handles: zombie detection, session creation, env vars, theming,
 	// WaitForClaudeReady, and crucially - startup/propulsion nudges (GUPP).
 	// It returns ErrAlreadyRunning if Claude is already running in tmux.
@@ -807,6 +1129,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 		d.logger.Printf("Error starting refinery for %s: %v", rigName, err)
 		return
 	}
+	d.recordRoleStart(agentID)
 
 	d.logger.Printf("Refinery session for %s started successfully", rigName)
 }
@@ -814,6 +1137,13 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 // ensureMayorRunning ensures the Mayor is running.
 // Uses mayor.Manager for consistent startup behavior (zombie detection, GUPP, etc.).
 func (d *Daemon) ensureMayorRunning() {
+	const agentID = "mayor"
+
+	// Crash detection, startup grace, and restart backoff/crash loop
+	if !d.checkRoleSession("mayor", agentID, session.MayorSessionName()) {
+		return
+	}
+
 	mgr := mayor.NewManager(d.config.TownRoot)
 
 	if err := mgr.Start(""); err != nil {
@@ -824,6 +1154,7 @@ func (d *Daemon) ensureMayorRunning() {
 		d.logger.Printf("Error starting Mayor: %v", err)
 		return
 	}
+	d.recordRoleStart(agentID)
 
 	d.logger.Println("Mayor started successfully")
 }
@@ -875,23 +1206,10 @@ func (d *Daemon) killRefinerySessions() {
 
 // getKnownRigs returns list of registered rig names.
 func (d *Daemon) getKnownRigs() []string {
-	rigsPath := filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
+	rigs, err := rigsconfig.Names(d.config.TownRoot)
 	if err != nil {
 		return nil
 	}
-
-	var parsed struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		return nil
-	}
-
-	var rigs []string
-	for name := range parsed.Rigs {
-		rigs = append(rigs, name)
-	}
 	return rigs
 }
 
@@ -1005,8 +1323,8 @@ func (d *Daemon) triggerPendingSpawns() {
 		d.logger.Printf("Triggered %d/%d pending spawn(s)", triggered, len(pending))
 	}
 
-	// Prune stale pending spawns (older than 5 minutes - likely dead sessions)
-	pruned, _ := polecat.PruneStalePending(d.config.TownRoot, 5*time.Minute)
+	// Prune stale pending spawns (likely dead sessions)
+	pruned, _ := polecat.PruneStalePending(d.config.TownRoot, pendingSpawnTTL(d.patrolConfig))
 	if pruned > 0 {
 		d.logger.Printf("Pruned %d stale pending spawn(s)", pruned)
 	}
@@ -1057,6 +1375,97 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 	return nil
 }
 
+// acquireLeadership takes the daemon.lock flock and the leader lease. The
+// flock prevents the TOCTOU race where concurrent starts all pass the
+// IsRunning() check before any writes the PID file; the lease covers hosts
+// sharing the town directory and records who leads. When another daemon
+// leads, returns an error naming it, or in standby mode polls until its
+// lease frees up.
+func (d *Daemon) acquireLeadership() (*flock.Flock, *Lease, error) {
+	// Uses gofrs/flock for cross-platform compatibility (Unix + Windows).
+	fileLock := flock.New(filepath.Join(d.config.TownRoot, "daemon", "daemon.lock"))
+	host := leaseHost()
+	standingBy := false
+	for {
+		lease, err := d.tryLeadership(fileLock, host)
+		if err == nil {
+			if standingBy {
+				d.logger.Printf("Acquired daemon leadership, leaving standby")
+			}
+			return fileLock, lease, nil
+		}
+		var held *LeaseHeldError
+		if !d.config.Standby || !errors.As(err, &held) {
+			return nil, nil, err
+		}
+		if !standingBy {
+			d.logger.Printf("Standing by: %v", err)
+			standingBy = true
+		}
+		select {
+		case <-d.ctx.Done():
+			return nil, nil, d.ctx.Err()
+		case <-time.After(leaseRenewInterval):
+		}
+	}
+}
+
+// renewLeaseLoop renews lease every leaseRenewInterval in a goroutine until
+// stop is called; stop waits for it to exit, so the lease is not rewritten
+// after releaseLease. If another daemon takes the lease over, the
+// ErrLeaseLost error is sent on lost and renewal stops; other renewal
+// errors are logged and retried on the next tick.
+func (d *Daemon) renewLeaseLoop(lease *Lease) (lost <-chan error, stop func()) {
+	ctx, cancel := context.WithCancel(d.ctx)
+	lostCh := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(leaseRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := renewLease(d.config.TownRoot, lease, d.clock().Now()); err != nil {
+				if errors.Is(err, ErrLeaseLost) {
+					lostCh <- err
+					return
+				}
+				d.logger.Printf("Warning: renewing leader lease: %v", err)
+			}
+		}
+	}()
+	return lostCh, func() {
+		cancel()
+		<-done
+	}
+}
+
+// tryLeadership makes one non-blocking attempt at the flock and the lease.
+func (d *Daemon) tryLeadership(fileLock *flock.Flock, host string) (*Lease, error) {
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring lock: %w", err)
+	}
+	if !locked {
+		// Name the holder when its lease says who it is.
+		now := d.clock().Now()
+		if cur, err := LoadLease(d.config.TownRoot); err == nil && cur != nil && cur.live(now, host) {
+			return nil, &LeaseHeldError{Holder: cur, Age: cur.Age(now)}
+		}
+		return nil, &LeaseHeldError{Holder: &Lease{Host: host}}
+	}
+	lease, err := acquireLease(d.config.TownRoot, os.Getpid(), host, d.clock().Now())
+	if err != nil {
+		_ = fileLock.Unlock()
+		return nil, err
+	}
+	return lease, nil
+}
+
 // Stop signals the daemon to stop.
 func (d *Daemon) Stop() {
 	d.cancel()
@@ -1145,13 +1554,7 @@ func IsRunning(townRoot string) (bool, int, error) {
 	}
 
 	// Check if process is alive
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false, 0, nil
-	}
-
-	// On Unix, FindProcess always succeeds. Send signal 0 to check if alive.
-	if err := process.Signal(syscall.Signal(0)); err != nil {
+	if !procs.Alive(pid) {
 		// Process not running, clean up stale PID file
 		if err := os.Remove(pidFile); err == nil {
 			// Successfully cleaned up stale file
@@ -1174,17 +1577,12 @@ func IsRunning(townRoot string) (bool, int, error) {
 
 // isGasTownDaemon checks if a PID is actually a gt daemon run process.
 // This prevents false positives from PID reuse.
-// Uses ps command for cross-platform compatibility (Linux, macOS).
 func isGasTownDaemon(pid int) bool {
-	// Use ps to get command for the PID (works on Linux and macOS)
-	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=")
-	output, err := cmd.Output()
+	cmdline, err := procs.Cmdline(pid)
 	if err != nil {
 		return false
 	}
 
-	cmdline := strings.TrimSpace(string(output))
-
 	// Check if it's "gt daemon run" or "/path/to/gt daemon run"
 	return strings.Contains(cmdline, "gt") && strings.Contains(cmdline, "daemon") && strings.Contains(cmdline, "run")
 }
@@ -1201,23 +1599,18 @@ func StopDaemon(townRoot string) error {
 		return fmt.Errorf("daemon is not running")
 	}
 
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("finding process: %w", err)
-	}
-
 	// Send SIGTERM for graceful shutdown
-	if err := process.Signal(syscall.SIGTERM); err != nil {
+	if err := procs.Terminate(pid); err != nil {
 		return fmt.Errorf("sending SIGTERM: %w", err)
 	}
 
 	// Wait a bit for graceful shutdown
-	time.Sleep(constants.ShutdownNotifyDelay)
+	clk.Sleep(constants.ShutdownNotifyDelay)
 
 	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	if procs.Alive(pid) {
 		// Still running, force kill
-		_ = process.Signal(syscall.SIGKILL)
+		_ = procs.Kill(pid)
 	}
 
 	// Clean up PID file
@@ -1270,23 +1663,18 @@ func KillOrphanedDaemons() (int, error) {
 
 	killed := 0
 	for _, pid := range pids {
-		process, err := os.FindProcess(pid)
-		if err != nil {
-			continue
-		}
-
 		// Try SIGTERM first
-		if err := process.Signal(syscall.SIGTERM); err != nil {
+		if err := procs.Terminate(pid); err != nil {
 			continue
 		}
 
 		// Wait for graceful shutdown
-		time.Sleep(200 * time.Millisecond)
+		clk.Sleep(200 * time.Millisecond)
 
 		// Check if still alive
-		if err := process.Signal(syscall.Signal(0)); err == nil {
+		if procs.Alive(pid) {
 			// Still alive, force kill
-			_ = process.Signal(syscall.SIGKILL)
+			_ = procs.Kill(pid)
 		}
 
 		killed++
@@ -1348,6 +1736,12 @@ func listPolecatWorktrees(polecatsDir string) ([]string, error) {
 // checkPolecatHealth checks a single polecat's session health.
 // If the polecat has work-on-hook but the tmux session is dead, it's restarted.
 func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
+	// A quarantined polecat is frozen on purpose; its stopped session would
+	// look wedged or dead, and restarting it would destroy the evidence.
+	if quarantine.IsQuarantined(d.config.TownRoot, rigName, polecatName) {
+		return
+	}
+
 	// Build the expected tmux session name
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
@@ -1358,9 +1752,20 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 		return
 	}
 
+	// A session whose pane has exited (remain-on-exit) is a crash too. Capture
+	// the pane before killing the session so the final output survives.
+	var deadPane *crashlog.Report
 	if sessionAlive {
-		// Session is alive - nothing to do
-		return
+		dead, status, err := d.tmux.PaneDeadStatus(sessionName)
+		if err != nil || !dead {
+			// Session is alive - but it may be wedged rather than working.
+			d.checkPolecatHeartbeat(rigName, polecatName, sessionName)
+			return
+		}
+		deadPane = &crashlog.Report{ExitStatus: &status}
+		if lines, err := d.tmux.CapturePaneLines(sessionName, crashlog.DefaultOutputLines); err == nil {
+			deadPane.Output = crashlog.TrimOutput(lines)
+		}
 	}
 
 	// Session is dead. Check if the polecat has work-on-hook.
@@ -1384,14 +1789,21 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// TOCTOU guard: re-verify session is still dead before restarting.
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
-	sessionRevived, err := d.tmux.HasSession(sessionName)
-	if err == nil && sessionRevived {
-		return // Session came back - no restart needed
+	if deadPane == nil {
+		sessionRevived, err := d.tmux.HasSession(sessionName)
+		if err == nil && sessionRevived {
+			return // Session came back - no restart needed
+		}
 	}
 
 	// Polecat has work but session is dead - this is a crash!
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
+	d.captureCrashReport(rigName, polecatName, sessionName, info.HookBead, deadPane)
+	if deadPane != nil {
+		// Clear the dead pane's session so the restart can recreate it.
+		_ = d.tmux.KillSession(sessionName)
+	}
 
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
@@ -1406,12 +1818,95 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	}
 }
 
+// checkPolecatHeartbeat flags a live polecat whose heartbeat has gone stale
+// while it has hooked work. tmux can't tell a wedged agent from a working one;
+// heartbeat age can. Agents that have never sent a heartbeat are left to tmux
+// liveness. The witness is notified once per stale heartbeat rather than the
+// session being restarted, since a long step isn't necessarily a hung one.
+func (d *Daemon) checkPolecatHeartbeat(rigName, polecatName, sessionName string) {
+	prefix := beads.GetPrefixForRig(d.config.TownRoot, rigName)
+	agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
+	info, err := d.getAgentBeadInfo(agentBeadID)
+	if err != nil {
+		return
+	}
+
+	hb, ok := beads.ParseHeartbeat(info.LastHeartbeat)
+	if !ok || info.HookBead == "" || d.clock().Since(hb) <= beads.HeartbeatStaleThreshold {
+		delete(d.staleHeartbeats, sessionName)
+		return
+	}
+	if d.staleHeartbeats[sessionName] == info.LastHeartbeat {
+		return // Already reported this heartbeat
+	}
+	if d.staleHeartbeats == nil {
+		d.staleHeartbeats = make(map[string]string)
+	}
+	d.staleHeartbeats[sessionName] = info.LastHeartbeat
+
+	age := d.clock().Since(hb).Round(time.Minute)
+	d.logger.Printf("STALE HEARTBEAT: polecat %s/%s has hook_bead=%s but no heartbeat for %v (session %s alive)",
+		rigName, polecatName, info.HookBead, age, sessionName)
+	d.notifyWitnessOfStaleHeartbeat(rigName, polecatName, info.HookBead, age)
+}
+
+// notifyWitnessOfStaleHeartbeat tells the rig's witness a polecat looks wedged.
+func (d *Daemon) notifyWitnessOfStaleHeartbeat(rigName, polecatName, hookBead string, age time.Duration) {
+	witnessAddr := rigName + "/witness"
+	subject := fmt.Sprintf("STALE_HEARTBEAT: %s/%s no heartbeat for %v", rigName, polecatName, age)
+	body := fmt.Sprintf(`Polecat %s has a live session but has not sent a heartbeat for %v.
+
+hook_bead: %s
+
+The agent may be wedged. Inspect the session and nudge or restart it.`,
+		polecatName, age, hookBead)
+
+	cmd := exec.Command(d.gtPath, "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify witness of stale heartbeat: %v", err)
+	}
+}
+
+// crashCaptureWindow is how recently a pane-died hook report must have been
+// saved for the daemon to skip recording the same crash again.
+const crashCaptureWindow = 5 * time.Minute
+
+// captureCrashReport stores a forensic report for a crashed polecat under
+// daemon/crashes. deadPane carries the exit status and output when the pane
+// was still readable; otherwise the report records only what the daemon
+// knows (the pane-died hook may already have captured the output).
+func (d *Daemon) captureCrashReport(rigName, polecatName, sessionName, hookBead string, deadPane *crashlog.Report) {
+	townRoot := d.config.TownRoot
+	if deadPane == nil && crashlog.RecentlyCaptured(townRoot, rigName, polecatName, crashCaptureWindow) {
+		return
+	}
+	report := &crashlog.Report{
+		Rig:        rigName,
+		Polecat:    polecatName,
+		Agent:      fmt.Sprintf("%s/%s", rigName, polecatName),
+		Session:    sessionName,
+		HookBead:   hookBead,
+		DetectedBy: crashlog.DetectedByDaemon,
+	}
+	if deadPane != nil {
+		report.ExitStatus = deadPane.ExitStatus
+		report.Output = deadPane.Output
+	}
+	if path, err := crashlog.Save(townRoot, report); err != nil {
+		d.logger.Printf("Warning: saving crash report for %s/%s: %v", rigName, polecatName, err)
+	} else {
+		d.logger.Printf("Crash report saved: %s", path)
+	}
+}
+
 // recordSessionDeath records a session death and checks for mass death pattern.
 func (d *Daemon) recordSessionDeath(sessionName string) {
 	d.deathsMu.Lock()
 	defer d.deathsMu.Unlock()
 
-	now := time.Now()
+	now := d.clock().Now()
 
 	// Add this death
 	d.recentDeaths = append(d.recentDeaths, sessionDeath{
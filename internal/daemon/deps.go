@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// Package-level dependencies on the clock and the process table, used by
+// the package's free functions (IsRunning, StopDaemon, ...) and as the
+// default for Daemon and RestartTracker. Tests in this and other packages
+// replace them with fakes through SetClock and SetProcessTable.
+var (
+	clk   clock.Clock = clock.Real{}
+	procs proc.Table  = proc.OS{}
+)
+
+// SetClock replaces the package clock. Returns a func that restores the
+// previous one. Not safe to call while other goroutines use the package.
+func SetClock(c clock.Clock) (restore func()) {
+	prev := clk
+	clk = c
+	return func() { clk = prev }
+}
+
+// SetProcessTable replaces the process table used to check and signal the
+// daemon and Dolt server processes. Returns a func that restores the
+// previous one.
+func SetProcessTable(t proc.Table) (restore func()) {
+	prev := procs
+	procs = t
+	return func() { procs = prev }
+}
@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestRestartTracker_BackoffUsesClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	defer SetClock(fake)()
+
+	rt := NewRestartTracker(t.TempDir())
+	rt.RecordRestart("gt-witness")
+	if rt.CanRestart("gt-witness") {
+		t.Fatal("CanRestart right after a restart = true, want false")
+	}
+	if got := rt.GetBackoffRemaining("gt-witness"); got != initialBackoff {
+		t.Errorf("GetBackoffRemaining = %v, want %v", got, initialBackoff)
+	}
+
+	fake.Advance(initialBackoff + time.Second)
+	if !rt.CanRestart("gt-witness") {
+		t.Error("CanRestart after backoff = false, want true")
+	}
+
+	// Second restart doubles the backoff.
+	rt.RecordRestart("gt-witness")
+	if got := rt.GetBackoffRemaining("gt-witness"); got != 2*initialBackoff {
+		t.Errorf("second backoff = %v, want %v", got, 2*initialBackoff)
+	}
+
+	// A restart after the stability period starts over.
+	fake.Advance(stabilityPeriod + time.Minute)
+	rt.RecordRestart("gt-witness")
+	if got := rt.GetBackoffRemaining("gt-witness"); got != initialBackoff {
+		t.Errorf("backoff after stable period = %v, want %v", got, initialBackoff)
+	}
+}
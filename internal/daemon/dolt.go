@@ -12,6 +12,12 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/chaos"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 const doltCmdTimeout = 15 * time.Second
@@ -47,27 +53,27 @@ type DoltServerConfig struct {
 	AutoRestart bool `json:"auto_restart,omitempty"`
 
 	// RestartDelay is the initial delay before restarting after crash (default 5s).
-	RestartDelay time.Duration `json:"restart_delay,omitempty"`
+	RestartDelay config.Duration `json:"restart_delay,omitempty"`
 
 	// MaxRestartDelay is the maximum backoff delay (default 5min).
-	MaxRestartDelay time.Duration `json:"max_restart_delay,omitempty"`
+	MaxRestartDelay config.Duration `json:"max_restart_delay,omitempty"`
 
 	// MaxRestartsInWindow is the maximum number of restarts allowed within
 	// RestartWindow before escalating instead of retrying (default 5).
 	MaxRestartsInWindow int `json:"max_restarts_in_window,omitempty"`
 
 	// RestartWindow is the time window for counting restarts (default 10min).
-	RestartWindow time.Duration `json:"restart_window,omitempty"`
+	RestartWindow config.Duration `json:"restart_window,omitempty"`
 
 	// HealthyResetInterval is how long the server must stay healthy before
 	// the backoff counter resets (default 5min).
-	HealthyResetInterval time.Duration `json:"healthy_reset_interval,omitempty"`
+	HealthyResetInterval config.Duration `json:"healthy_reset_interval,omitempty"`
 
 	// HealthCheckInterval is how often to run the Dolt health check,
 	// independent of the general daemon heartbeat. This enables fast
 	// detection of Dolt server crashes without changing the overall
 	// heartbeat frequency. Default 30s.
-	HealthCheckInterval time.Duration `json:"health_check_interval,omitempty"`
+	HealthCheckInterval config.Duration `json:"health_check_interval,omitempty"`
 }
 
 // DefaultDoltServerConfig returns sensible defaults for Dolt server config.
@@ -79,12 +85,12 @@ func DefaultDoltServerConfig(townRoot string) *DoltServerConfig {
 		DataDir:              filepath.Join(townRoot, "dolt"),
 		LogFile:              filepath.Join(townRoot, "daemon", "dolt-server.log"),
 		AutoRestart:          true,
-		RestartDelay:         5 * time.Second,
-		MaxRestartDelay:      5 * time.Minute,
+		RestartDelay:         config.Dur(5 * time.Second),
+		MaxRestartDelay:      config.Dur(5 * time.Minute),
 		MaxRestartsInWindow:  5,
-		RestartWindow:        10 * time.Minute,
-		HealthyResetInterval: 5 * time.Minute,
-		HealthCheckInterval:  DefaultDoltHealthCheckInterval,
+		RestartWindow:        config.Dur(10 * time.Minute),
+		HealthyResetInterval: config.Dur(5 * time.Minute),
+		HealthCheckInterval:  config.Dur(DefaultDoltHealthCheckInterval),
 	}
 }
 
@@ -131,6 +137,7 @@ type DoltServerManager struct {
 	readOnlyAlertFn    func(error)
 	crashAlertFn       func(int)
 	listDatabasesFn    func() ([]string, error)
+	warmFn             func()
 }
 
 // NewDoltServerManager creates a new Dolt server manager.
@@ -178,8 +185,8 @@ func (m *DoltServerManager) IsExternal() bool {
 // HealthCheckInterval returns the configured health check interval,
 // falling back to DefaultDoltHealthCheckInterval if not explicitly set.
 func (m *DoltServerManager) HealthCheckInterval() time.Duration {
-	if m.config != nil && m.config.HealthCheckInterval > 0 {
-		return m.config.HealthCheckInterval
+	if m.config != nil {
+		return m.config.HealthCheckInterval.Or(DefaultDoltHealthCheckInterval)
 	}
 	return DefaultDoltHealthCheckInterval
 }
@@ -224,7 +231,7 @@ func (m *DoltServerManager) isRunning() (int, bool) {
 	}
 	// First check our tracked process
 	if m.process != nil {
-		if isProcessAlive(m.process) {
+		if procs.Alive(m.process.Pid) {
 			return m.process.Pid, true
 		}
 		// Process died, clear it
@@ -243,12 +250,7 @@ func (m *DoltServerManager) isRunning() (int, bool) {
 	}
 
 	// Verify process is alive and is dolt
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return 0, false
-	}
-
-	if !isProcessAlive(process) {
+	if !procs.Alive(pid) {
 		// Process not running, clean up stale PID file
 		_ = os.Remove(m.pidFile())
 		return 0, false
@@ -260,21 +262,18 @@ func (m *DoltServerManager) isRunning() (int, bool) {
 		return 0, false
 	}
 
-	m.process = process
+	if process, err := os.FindProcess(pid); err == nil {
+		m.process = process
+	}
 	return pid, true
 }
 
 // isDoltSqlServer checks if a PID is actually a dolt sql-server process.
 func isDoltSqlServer(pid int) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	cmd := exec.CommandContext(ctx, "ps", "-p", strconv.Itoa(pid), "-o", "command=")
-	output, err := cmd.Output()
+	cmdline, err := procs.Cmdline(pid)
 	if err != nil {
 		return false
 	}
-
-	cmdline := strings.TrimSpace(string(output))
 	return strings.Contains(cmdline, "dolt") && strings.Contains(cmdline, "sql-server")
 }
 
@@ -390,27 +389,63 @@ func (m *DoltServerManager) restartWithBackoff() error {
 	// Advance the backoff for next time
 	m.advanceBackoff()
 
-	return m.startLocked()
+	if err := m.startLocked(); err != nil {
+		return err
+	}
+
+	// Warm caches in the background so agents reconnecting after the
+	// restart don't pay the cold-start penalty on their first queries.
+	go m.warm()
+	return nil
+}
+
+// warm runs representative queries against every database to load the
+// server's chunk indexes, logging the warm-up time per database.
+func (m *DoltServerManager) warm() {
+	if m.warmFn != nil {
+		m.warmFn()
+		return
+	}
+	databases, err := doltserver.ListDatabases(m.townRoot)
+	if err != nil {
+		m.logger("Dolt warm-up after restart failed: listing databases: %v", err)
+		return
+	}
+	var warm []string
+	for _, db := range databases {
+		if config.FeatureEnabled(m.townRoot, db, config.FeatureDoltPrewarm) {
+			warm = append(warm, db)
+		}
+	}
+	if len(warm) == 0 {
+		return
+	}
+	results, err := doltserver.WarmDatabases(m.townRoot, warm)
+	if err != nil {
+		m.logger("Dolt warm-up after restart failed: %v", err)
+		return
+	}
+	for _, r := range results {
+		if r.Error != "" {
+			m.logger("Dolt warm-up: %s failed after %v: %s", r.Database, r.Duration.Round(time.Millisecond), r.Error)
+			continue
+		}
+		m.logger("Dolt warm-up: %s warmed in %v", r.Database, r.Duration.Round(time.Millisecond))
+	}
 }
 
 // getBackoffDelay returns the current backoff delay.
 func (m *DoltServerManager) getBackoffDelay() time.Duration {
 	if m.currentDelay <= 0 {
-		return m.config.RestartDelay
+		return m.config.RestartDelay.Duration
 	}
 	return m.currentDelay
 }
 
 // advanceBackoff doubles the current delay up to MaxRestartDelay.
 func (m *DoltServerManager) advanceBackoff() {
-	baseDelay := m.config.RestartDelay
-	if baseDelay <= 0 {
-		baseDelay = 5 * time.Second
-	}
-	maxDelay := m.config.MaxRestartDelay
-	if maxDelay <= 0 {
-		maxDelay = 5 * time.Minute
-	}
+	baseDelay := m.config.RestartDelay.Or(5 * time.Second)
+	maxDelay := m.config.MaxRestartDelay.Or(5 * time.Minute)
 
 	if m.currentDelay <= 0 {
 		m.currentDelay = baseDelay
@@ -423,10 +458,7 @@ func (m *DoltServerManager) advanceBackoff() {
 
 // pruneRestartTimes removes restart timestamps outside the configured window.
 func (m *DoltServerManager) pruneRestartTimes(now time.Time) {
-	window := m.config.RestartWindow
-	if window <= 0 {
-		window = 10 * time.Minute
-	}
+	window := m.config.RestartWindow.Or(10 * time.Minute)
 	cutoff := now.Add(-window)
 	pruned := m.restartTimes[:0]
 	for _, t := range m.restartTimes {
@@ -442,10 +474,7 @@ func (m *DoltServerManager) pruneRestartTimes(now time.Time) {
 // Must be called with m.mu held.
 func (m *DoltServerManager) maybeResetBackoff() {
 	now := m.now()
-	resetInterval := m.config.HealthyResetInterval
-	if resetInterval <= 0 {
-		resetInterval = 5 * time.Minute
-	}
+	resetInterval := m.config.HealthyResetInterval.Or(5 * time.Minute)
 
 	if m.lastHealthyTime.IsZero() {
 		m.lastHealthyTime = now
@@ -590,20 +619,12 @@ func sendDoltAlertMail(townRoot, recipient, subject, body string, logger func(fo
 // sendDoltAlertToWitnesses sends a Dolt alert to all rig witnesses.
 // Discovers rigs from mayor/rigs.json and sends to each <rig>/witness.
 func sendDoltAlertToWitnesses(townRoot, subject, body string, logger func(format string, v ...interface{})) {
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
+	rigNames, err := rigsconfig.Names(townRoot)
 	if err != nil {
 		return // No rigs.json, nothing to notify
 	}
 
-	var parsed struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		return
-	}
-
-	for rigName := range parsed.Rigs {
+	for _, rigName := range rigNames {
 		recipient := rigName + "/witness"
 		sendDoltAlertMail(townRoot, recipient, subject, body, logger)
 	}
@@ -649,6 +670,9 @@ func (m *DoltServerManager) startLocked() error {
 	if m.startFn != nil {
 		return m.startFn()
 	}
+	if err := chaos.Inject(chaos.DaemonDoltStart); err != nil {
+		return err
+	}
 
 	// Re-check if the server is already running to close the TOCTOU window.
 	// Another goroutine may have started the server while we were waiting
@@ -749,13 +773,8 @@ func (m *DoltServerManager) stopLocked() {
 
 	m.logger("Stopping Dolt SQL server (PID %d)...", pid)
 
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return // Already gone
-	}
-
 	// Send termination signal for graceful shutdown
-	if err := sendTermSignal(process); err != nil {
+	if err := procs.Terminate(pid); err != nil {
 		m.logger("Warning: failed to send termination signal: %v", err)
 	}
 
@@ -763,7 +782,7 @@ func (m *DoltServerManager) stopLocked() {
 	done := make(chan struct{})
 	go func() {
 		for i := 0; i < 50; i++ {
-			if !isProcessAlive(process) {
+			if !procs.Alive(pid) {
 				close(done)
 				return
 			}
@@ -777,7 +796,7 @@ func (m *DoltServerManager) stopLocked() {
 	case <-time.After(5 * time.Second):
 		// Force kill
 		m.logger("Dolt SQL server did not stop gracefully, forcing termination")
-		_ = sendKillSignal(process)
+		_ = procs.Kill(pid)
 	}
 
 	// Clean up
@@ -800,6 +819,9 @@ func (m *DoltServerManager) checkHealthLocked() error {
 	if m.healthCheckFn != nil {
 		return m.healthCheckFn()
 	}
+	if err := chaos.Inject(chaos.DaemonDoltHealth); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
 	// 1. Connectivity + latency: time a SELECT 1
 	ctx, cancel := context.WithTimeout(context.Background(), doltCmdTimeout)
 	defer cancel()
@@ -811,7 +833,7 @@ func (m *DoltServerManager) checkHealthLocked() error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return fmt.Errorf("health check failed: %w (%s)", err, strings.TrimSpace(stderr.String()))
 	}
 
@@ -840,7 +862,7 @@ func (m *DoltServerManager) checkConnectionCount() {
 	)
 	cmd.Dir = m.config.DataDir
 
-	output, err := cmd.Output()
+	output, err := proc.OutputContext(ctx, cmd)
 	if err != nil {
 		return // non-fatal
 	}
@@ -921,7 +943,7 @@ func (m *DoltServerManager) checkWriteHealthLocked() error {
 	cmd := exec.CommandContext(ctx, "dolt", "sql", "-q", query)
 	cmd.Dir = m.config.DataDir
 
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutputContext(ctx, cmd)
 	if err != nil {
 		errMsg := strings.TrimSpace(string(output))
 		if isReadOnlyError(errMsg) {
@@ -993,7 +1015,7 @@ func (m *DoltServerManager) getDoltVersion() (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), doltCmdTimeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, "dolt", "version")
-	output, err := cmd.Output()
+	output, err := proc.OutputContext(ctx, cmd)
 	if err != nil {
 		return "", err
 	}
@@ -1017,7 +1039,7 @@ func (m *DoltServerManager) listDatabases() ([]string, error) {
 	)
 	cmd.Dir = m.config.DataDir
 
-	output, err := cmd.Output()
+	output, err := proc.OutputContext(ctx, cmd)
 	if err != nil {
 		return nil, err
 	}
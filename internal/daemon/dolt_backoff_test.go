@@ -10,13 +10,15 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestAdvanceBackoff(t *testing.T) {
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			RestartDelay:    5 * time.Second,
-			MaxRestartDelay: 5 * time.Minute,
+			RestartDelay:    config.Dur(5 * time.Second),
+			MaxRestartDelay: config.Dur(5 * time.Minute),
 		},
 		logger: func(format string, v ...interface{}) {},
 	}
@@ -67,7 +69,7 @@ func TestAdvanceBackoff(t *testing.T) {
 func TestGetBackoffDelay_InitialValue(t *testing.T) {
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			RestartDelay: 5 * time.Second,
+			RestartDelay: config.Dur(5 * time.Second),
 		},
 		logger: func(format string, v ...interface{}) {},
 	}
@@ -83,7 +85,7 @@ func TestPruneRestartTimes(t *testing.T) {
 	now := time.Now()
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			RestartWindow: 10 * time.Minute,
+			RestartWindow: config.Dur(10 * time.Minute),
 		},
 		logger: func(format string, v ...interface{}) {},
 		restartTimes: []time.Time{
@@ -104,7 +106,7 @@ func TestPruneRestartTimes(t *testing.T) {
 func TestMaybeResetBackoff(t *testing.T) {
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			HealthyResetInterval: 5 * time.Minute,
+			HealthyResetInterval: config.Dur(5 * time.Minute),
 		},
 		logger:       func(format string, v ...interface{}) {},
 		currentDelay: 40 * time.Second,
@@ -136,7 +138,7 @@ func TestMaybeResetBackoff(t *testing.T) {
 func TestMaybeResetBackoff_NoResetIfNotLongEnough(t *testing.T) {
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			HealthyResetInterval: 5 * time.Minute,
+			HealthyResetInterval: config.Dur(5 * time.Minute),
 		},
 		logger:          func(format string, v ...interface{}) {},
 		currentDelay:    40 * time.Second,
@@ -158,7 +160,7 @@ func TestMaybeResetBackoff_AccumulatesAcrossHeartbeats(t *testing.T) {
 	// reset, allowing the delta to accumulate across multiple heartbeat calls.
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			HealthyResetInterval: 10 * time.Minute,
+			HealthyResetInterval: config.Dur(10 * time.Minute),
 		},
 		logger:       func(format string, v ...interface{}) {},
 		currentDelay: 40 * time.Second,
@@ -201,19 +203,19 @@ func TestMaybeResetBackoff_AccumulatesAcrossHeartbeats(t *testing.T) {
 func TestDefaultConfig_BackoffFields(t *testing.T) {
 	cfg := DefaultDoltServerConfig("/tmp/test")
 
-	if cfg.MaxRestartDelay != 5*time.Minute {
+	if cfg.MaxRestartDelay.Duration != 5*time.Minute {
 		t.Errorf("expected MaxRestartDelay 5m, got %v", cfg.MaxRestartDelay)
 	}
 	if cfg.MaxRestartsInWindow != 5 {
 		t.Errorf("expected MaxRestartsInWindow 5, got %d", cfg.MaxRestartsInWindow)
 	}
-	if cfg.RestartWindow != 10*time.Minute {
+	if cfg.RestartWindow.Duration != 10*time.Minute {
 		t.Errorf("expected RestartWindow 10m, got %v", cfg.RestartWindow)
 	}
-	if cfg.HealthyResetInterval != 5*time.Minute {
+	if cfg.HealthyResetInterval.Duration != 5*time.Minute {
 		t.Errorf("expected HealthyResetInterval 5m, got %v", cfg.HealthyResetInterval)
 	}
-	if cfg.HealthCheckInterval != DefaultDoltHealthCheckInterval {
+	if cfg.HealthCheckInterval.Duration != DefaultDoltHealthCheckInterval {
 		t.Errorf("expected HealthCheckInterval %v, got %v", DefaultDoltHealthCheckInterval, cfg.HealthCheckInterval)
 	}
 }
@@ -237,7 +239,7 @@ func TestHealthCheckInterval_Configured(t *testing.T) {
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
 			Enabled:             true,
-			HealthCheckInterval: 15 * time.Second,
+			HealthCheckInterval: config.Dur(15 * time.Second),
 		},
 		logger: func(format string, v ...interface{}) {},
 	}
@@ -269,10 +271,10 @@ func TestRestartingFlag_PreventsConcurrentRestarts(t *testing.T) {
 			Enabled:             true,
 			Port:                13306, // Non-standard port to avoid conflicts
 			Host:                "127.0.0.1",
-			RestartDelay:        50 * time.Millisecond,
-			MaxRestartDelay:     100 * time.Millisecond,
+			RestartDelay:        config.Dur(50 * time.Millisecond),
+			MaxRestartDelay:     config.Dur(100 * time.Millisecond),
 			MaxRestartsInWindow: 10,
-			RestartWindow:       10 * time.Minute,
+			RestartWindow:       config.Dur(10 * time.Minute),
 		},
 		logger: func(format string, v ...interface{}) {},
 	}
@@ -304,7 +306,7 @@ func TestRestartingFlag_PreventsConcurrentRestarts(t *testing.T) {
 
 func TestStartLocked_SkipsIfAlreadyRunning(t *testing.T) {
 	if runtime.GOOS == "windows" {
-		t.Skip("isProcessAlive uses Signal(nil) on Windows which doesn't reliably detect live processes")
+		t.Skip("process liveness uses Signal(nil) on Windows which doesn't reliably detect live processes")
 	}
 	// Verify that startLocked() re-checks isRunning() to close the TOCTOU window.
 	// If the server is already running (m.process is alive), startLocked() should
@@ -318,11 +320,11 @@ func TestStartLocked_SkipsIfAlreadyRunning(t *testing.T) {
 	var logMessages []string
 	m := &DoltServerManager{
 		config: &DoltServerConfig{
-			Enabled:  true,
-			Port:     13307,
-			Host:     "127.0.0.1",
-			DataDir:  filepath.Join(tmpDir, "dolt"),
-			LogFile:  filepath.Join(daemonDir, "dolt-server.log"),
+			Enabled: true,
+			Port:    13307,
+			Host:    "127.0.0.1",
+			DataDir: filepath.Join(tmpDir, "dolt"),
+			LogFile: filepath.Join(daemonDir, "dolt-server.log"),
 		},
 		townRoot: tmpDir,
 		logger: func(format string, v ...interface{}) {
@@ -519,13 +521,13 @@ func newTestManager(t *testing.T) *DoltServerManager {
 			Enabled:              true,
 			Port:                 13306,
 			Host:                 "127.0.0.1",
-			RestartDelay:         10 * time.Millisecond,
-			MaxRestartDelay:      100 * time.Millisecond,
+			RestartDelay:         config.Dur(10 * time.Millisecond),
+			MaxRestartDelay:      config.Dur(100 * time.Millisecond),
 			MaxRestartsInWindow:  5,
-			RestartWindow:        10 * time.Minute,
-			HealthyResetInterval: 50 * time.Millisecond,
+			RestartWindow:        config.Dur(10 * time.Minute),
+			HealthyResetInterval: config.Dur(50 * time.Millisecond),
 		},
-		townRoot:      tmpDir,
+		townRoot:         tmpDir,
 		logger:           func(format string, v ...interface{}) { t.Logf(format, v...) },
 		runningFn:        func() (int, bool) { return 0, false },
 		healthCheckFn:    func() error { return nil },
@@ -669,7 +671,7 @@ func TestEnsureRunning_HealthyResetsBackoff(t *testing.T) {
 	baseTime := time.Now()
 
 	m := newTestManager(t)
-	m.config.HealthyResetInterval = 100 * time.Millisecond
+	m.config.HealthyResetInterval = config.Dur(100 * time.Millisecond)
 	m.runningFn = func() (int, bool) { return 1234, true }
 	m.healthCheckFn = func() error { return nil }
 	m.nowFn = func() time.Time {
@@ -1130,3 +1132,32 @@ func TestEnsureRunning_HealthyAfterReadOnlyRecovery(t *testing.T) {
 		t.Error("expected DOLT_UNHEALTHY signal to be cleared after recovery")
 	}
 }
+
+// TestRestartWithBackoff_WarmsAfterStart verifies that a supervised restart
+// warms the server's caches, and a failed start does not.
+func TestRestartWithBackoff_WarmsAfterStart(t *testing.T) {
+	m := newTestManager(t)
+	m.sleepFn = func(time.Duration) {}
+	warmed := make(chan struct{}, 1)
+	m.warmFn = func() { warmed <- struct{}{} }
+
+	m.startFn = func() error { return fmt.Errorf("dolt not found in PATH") }
+	if err := m.EnsureRunning(); err == nil {
+		t.Fatal("expected start error")
+	}
+	select {
+	case <-warmed:
+		t.Fatal("warmed after a failed start")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.startFn = func() error { return nil }
+	if err := m.EnsureRunning(); err != nil {
+		t.Fatalf("EnsureRunning: %v", err)
+	}
+	select {
+	case <-warmed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server was not warmed after restart")
+	}
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const (
+	defaultDoltGCInterval  = time.Hour
+	defaultDoltGCThreshold = 10 // GB
+)
+
+// doltGCInterval returns the configured check interval, or the default (1h).
+func doltGCInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DoltGC != nil {
+		return config.Patrols.DoltGC.Interval.Or(defaultDoltGCInterval)
+	}
+	return defaultDoltGCInterval
+}
+
+// doltGCThresholdBytes returns the configured disk usage threshold, or the
+// default (10 GB).
+func doltGCThresholdBytes(config *DaemonPatrolConfig) int64 {
+	gb := float64(defaultDoltGCThreshold)
+	if config != nil && config.Patrols != nil && config.Patrols.DoltGC != nil {
+		if config.Patrols.DoltGC.ThresholdGB > 0 {
+			gb = config.Patrols.DoltGC.ThresholdGB
+		}
+	}
+	return int64(gb * (1 << 30))
+}
+
+// collectDoltGarbage runs gt dolt gc when .dolt-data has grown past the
+// threshold. Non-fatal: failures are logged but don't stop the patrol.
+func (d *Daemon) collectDoltGarbage() {
+	if !IsPatrolEnabled(d.patrolConfig, "dolt_gc") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		d.logger.Printf("dolt_gc: dolt server not configured, skipping")
+		return
+	}
+
+	metrics := doltserver.GetHealthMetrics(d.config.TownRoot)
+	threshold := doltGCThresholdBytes(d.patrolConfig)
+	if metrics.DiskUsageBytes < threshold {
+		return
+	}
+	d.logger.Printf("dolt_gc: disk usage %s is over the %.1f GB threshold, collecting",
+		metrics.DiskUsageHuman, float64(threshold)/(1<<30))
+
+	cmd := exec.Command(d.gtPath, "dolt", "gc")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt and dolt
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("dolt_gc: %v: %s", err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		d.logger.Printf("dolt_gc: %s", line)
+	}
+}
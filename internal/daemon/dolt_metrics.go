@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const (
+	defaultDoltMetricsInterval  = 5 * time.Minute
+	defaultDoltMetricsRetention = 14 // days
+)
+
+// doltMetricsInterval returns the configured sampling interval, or the
+// default (5m).
+func doltMetricsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DoltMetrics != nil {
+		return config.Patrols.DoltMetrics.Interval.Or(defaultDoltMetricsInterval)
+	}
+	return defaultDoltMetricsInterval
+}
+
+// doltMetricsRetention returns how long samples are kept, or the default
+// (14 days).
+func doltMetricsRetention(config *DaemonPatrolConfig) time.Duration {
+	days := defaultDoltMetricsRetention
+	if config != nil && config.Patrols != nil && config.Patrols.DoltMetrics != nil {
+		if config.Patrols.DoltMetrics.RetentionDays > 0 {
+			days = config.Patrols.DoltMetrics.RetentionDays
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// sampleDoltMetrics appends a Dolt health sample to the metrics history
+// and drops samples past the retention window. A down server is recorded
+// too, so outages show in the trend. Non-fatal: failures are logged.
+func (d *Daemon) sampleDoltMetrics() {
+	if !IsPatrolEnabled(d.patrolConfig, "dolt_metrics") {
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	snap := collectDoltMetrics(townRoot)
+	now := d.clock().Now()
+	if err := doltserver.RecordMetricsSample(townRoot, doltserver.NewMetricsSample(now, snap.Health)); err != nil {
+		d.logger.Printf("dolt_metrics: recording sample: %v", err)
+		return
+	}
+	if _, err := doltserver.PruneMetricsHistory(townRoot, now.Add(-doltMetricsRetention(d.patrolConfig))); err != nil {
+		d.logger.Printf("dolt_metrics: pruning history: %v", err)
+	}
+}
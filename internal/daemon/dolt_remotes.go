@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 const (
@@ -19,9 +21,7 @@ const (
 // doltRemotesInterval returns the configured push interval, or the default (15m).
 func doltRemotesInterval(config *DaemonPatrolConfig) time.Duration {
 	if config != nil && config.Patrols != nil && config.Patrols.DoltRemotes != nil {
-		if config.Patrols.DoltRemotes.Interval > 0 {
-			return config.Patrols.DoltRemotes.Interval
-		}
+		return config.Patrols.DoltRemotes.Interval.Or(defaultDoltRemotesInterval)
 	}
 	return defaultDoltRemotesInterval
 }
@@ -127,7 +127,7 @@ func (d *Daemon) runDoltSQL(dataDir, query string) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return fmt.Errorf("%s", errMsg)
@@ -148,15 +148,13 @@ func (d *Daemon) discoverDatabasesWithRemotes(dataDir, remote string) ([]string,
 
 	var databases []string
 	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
 		name := entry.Name()
 		// Skip hidden directories
 		if strings.HasPrefix(name, ".") {
 			continue
 		}
-		// Check if this directory is a Dolt database (has .dolt subdirectory)
+		// Check if this directory is a Dolt database (has .dolt subdirectory).
+		// Stat follows symlinks, so linked databases are included.
 		doltDir := filepath.Join(dataDir, name, ".dolt")
 		if _, err := os.Stat(doltDir); os.IsNotExist(err) {
 			continue
@@ -179,7 +177,7 @@ func (d *Daemon) databaseHasRemote(dataDir, db, remote string) bool {
 	cmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "csv", "-q", query)
 	cmd.Dir = dataDir
 
-	output, err := cmd.Output()
+	output, err := proc.OutputContext(ctx, cmd)
 	if err != nil {
 		return false
 	}
@@ -0,0 +1,224 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Dolt watchdog defaults. The first restart is immediate; each further one
+// waits twice as long as the last, up to the max delay.
+const (
+	defaultDoltWatchdogBaseDelay  = time.Minute
+	defaultDoltWatchdogMaxDelay   = 30 * time.Minute
+	defaultDoltWatchdogNudgeAfter = 3
+
+	// doltWatchdogStableAfter is how long the server must stay up after a
+	// restart before the backoff resets.
+	doltWatchdogStableAfter = 10 * time.Minute
+
+	// doltWatchdogUnreachableChecks is how many heartbeats in a row a live
+	// server process may refuse connections before it counts as hung.
+	doltWatchdogUnreachableChecks = 2
+)
+
+// doltWatchdog restarts the server started by gt dolt start when it dies.
+// The dolt_server patrol manages its own server; this covers towns that
+// don't use it, where a dead server leaves every bd write open to split-brain.
+// Only accessed from the heartbeat loop goroutine - no sync needed.
+type doltWatchdog struct {
+	townRoot   string
+	logf       func(format string, v ...interface{})
+	maxDelay   time.Duration
+	nudgeAfter int
+
+	attempts    int       // restarts since the server was last stable
+	lastRestart time.Time // when the last restart was attempted
+	nextAttempt time.Time // no restart before this
+	unreachable int       // consecutive checks a live server refused connections
+	nudged      bool      // mayor told about this run of failures
+
+	// Hooks (nil = use doltserver; set only in tests)
+	stateFn     func() (*doltserver.State, error)
+	runningFn   func() (bool, error)
+	reachableFn func() error
+	startFn     func() error
+	stopFn      func() error
+	nudgeFn     func(message string) error
+	eventFn     func(payload map[string]interface{})
+}
+
+// doltWatchdogSettings returns the max restart delay and the number of
+// restarts before the mayor is nudged.
+func doltWatchdogSettings(config *DaemonPatrolConfig) (time.Duration, int) {
+	maxDelay, nudgeAfter := defaultDoltWatchdogMaxDelay, defaultDoltWatchdogNudgeAfter
+	if config != nil && config.Patrols != nil && config.Patrols.DoltWatchdog != nil {
+		c := config.Patrols.DoltWatchdog
+		maxDelay = c.MaxRestartDelay.Or(maxDelay)
+		if c.NudgeAfter > 0 {
+			nudgeAfter = c.NudgeAfter
+		}
+	}
+	return maxDelay, nudgeAfter
+}
+
+// watchDoltServer runs the Dolt watchdog for this heartbeat.
+func (d *Daemon) watchDoltServer() {
+	if !IsPatrolEnabled(d.patrolConfig, "dolt_watchdog") {
+		return
+	}
+	if d.doltServer != nil && d.doltServer.IsEnabled() {
+		return // The dolt_server patrol restarts its own server.
+	}
+	if d.doltWatchdog == nil {
+		maxDelay, nudgeAfter := doltWatchdogSettings(d.patrolConfig)
+		d.doltWatchdog = &doltWatchdog{
+			townRoot:   d.config.TownRoot,
+			logf:       d.logger.Printf,
+			maxDelay:   maxDelay,
+			nudgeAfter: nudgeAfter,
+			nudgeFn: func(message string) error {
+				return d.tmux.NudgeSession(session.MayorSessionName(), message)
+			},
+		}
+	}
+	d.doltWatchdog.check(d.clock().Now())
+}
+
+// check restarts the server if it should be running and is dead or hung.
+// A server stopped with gt dolt stop records that it is not running and is
+// left alone.
+func (w *doltWatchdog) check(now time.Time) {
+	state, err := w.state()
+	if err != nil {
+		w.logf("dolt_watchdog: reading server state: %v", err)
+		return
+	}
+	if state == nil || !state.Running || state.Draining {
+		w.reset()
+		return
+	}
+
+	running, err := w.running()
+	if err != nil {
+		w.logf("dolt_watchdog: checking server: %v", err)
+		return
+	}
+	reason := "server process not running"
+	if running {
+		reachErr := w.reachable()
+		if reachErr == nil {
+			w.unreachable = 0
+			if w.attempts > 0 && now.Sub(w.lastRestart) >= doltWatchdogStableAfter {
+				w.logf("dolt_watchdog: server stable for %v after restart, backoff reset", doltWatchdogStableAfter)
+				w.reset()
+			}
+			return
+		}
+		w.unreachable++
+		if w.unreachable < doltWatchdogUnreachableChecks {
+			w.logf("dolt_watchdog: server not reachable (%d/%d): %v", w.unreachable, doltWatchdogUnreachableChecks, reachErr)
+			return
+		}
+		reason = fmt.Sprintf("server not reachable for %d checks", w.unreachable)
+	}
+
+	if now.Before(w.nextAttempt) {
+		w.logf("dolt_watchdog: %s; next restart attempt in %v", reason, w.nextAttempt.Sub(now).Round(time.Second))
+		return
+	}
+	w.restart(now, reason, running)
+}
+
+// restart restarts the server and advances the backoff, nudging the mayor
+// once the restarts keep failing to stick.
+func (w *doltWatchdog) restart(now time.Time, reason string, running bool) {
+	w.attempts++
+	w.lastRestart = now
+	delay := defaultDoltWatchdogBaseDelay << (w.attempts - 1)
+	if w.attempts > 16 || delay > w.maxDelay {
+		delay = w.maxDelay
+	}
+	w.nextAttempt = now.Add(delay)
+
+	if running {
+		if err := w.stop(); err != nil {
+			w.logf("dolt_watchdog: stopping hung server: %v", err)
+		}
+	}
+	err := w.start()
+	payload := map[string]interface{}{"reason": reason, "attempt": w.attempts}
+	if err != nil {
+		payload["error"] = err.Error()
+		w.logf("dolt_watchdog: %s; restart attempt %d failed: %v (next attempt in %v)", reason, w.attempts, err, delay)
+	} else {
+		w.unreachable = 0
+		w.logf("dolt_watchdog: %s; restarted server (attempt %d)", reason, w.attempts)
+	}
+	w.logEvent(payload)
+
+	if w.attempts >= w.nudgeAfter && !w.nudged {
+		msg := fmt.Sprintf("DOLT WATCHDOG: the Dolt server has needed %d restarts (%s). bd writes may be failing or split-brained. Check 'gt dolt status' and daemon/dolt.log.", w.attempts, reason)
+		if err := w.nudgeFn(msg); err != nil {
+			w.logf("dolt_watchdog: nudging mayor: %v", err)
+			return
+		}
+		w.nudged = true
+	}
+}
+
+// reset clears the backoff once the server is stable or not meant to run.
+func (w *doltWatchdog) reset() {
+	w.attempts = 0
+	w.lastRestart = time.Time{}
+	w.nextAttempt = time.Time{}
+	w.unreachable = 0
+	w.nudged = false
+}
+
+func (w *doltWatchdog) state() (*doltserver.State, error) {
+	if w.stateFn != nil {
+		return w.stateFn()
+	}
+	return doltserver.LoadState(w.townRoot)
+}
+
+func (w *doltWatchdog) running() (bool, error) {
+	if w.runningFn != nil {
+		return w.runningFn()
+	}
+	running, _, err := doltserver.IsRunning(w.townRoot)
+	return running, err
+}
+
+func (w *doltWatchdog) reachable() error {
+	if w.reachableFn != nil {
+		return w.reachableFn()
+	}
+	return doltserver.CheckServerReachable(w.townRoot)
+}
+
+func (w *doltWatchdog) start() error {
+	if w.startFn != nil {
+		return w.startFn()
+	}
+	return doltserver.Start(w.townRoot)
+}
+
+func (w *doltWatchdog) stop() error {
+	if w.stopFn != nil {
+		return w.stopFn()
+	}
+	return doltserver.Stop(w.townRoot)
+}
+
+func (w *doltWatchdog) logEvent(payload map[string]interface{}) {
+	if w.eventFn != nil {
+		w.eventFn(payload)
+		return
+	}
+	_ = events.LogFeed(events.TypeDoltRestart, "daemon", payload)
+}
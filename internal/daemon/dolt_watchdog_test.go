@@ -0,0 +1,194 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// fakeDoltServer is the server a test watchdog sees.
+type fakeDoltServer struct {
+	state     *doltserver.State
+	running   bool
+	reachErr  error
+	startErr  error
+	starts    int
+	stops     int
+	nudges    []string
+	events    int
+	logs      []string
+	startKeep bool // whether a successful start leaves the server running
+}
+
+func newTestWatchdog(s *fakeDoltServer) *doltWatchdog {
+	return &doltWatchdog{
+		logf:       func(format string, v ...interface{}) { s.logs = append(s.logs, fmt.Sprintf(format, v...)) },
+		maxDelay:   defaultDoltWatchdogMaxDelay,
+		nudgeAfter: defaultDoltWatchdogNudgeAfter,
+		stateFn:    func() (*doltserver.State, error) { return s.state, nil },
+		runningFn:  func() (bool, error) { return s.running, nil },
+		reachableFn: func() error {
+			return s.reachErr
+		},
+		startFn: func() error {
+			s.starts++
+			if s.startErr != nil {
+				return s.startErr
+			}
+			s.running = s.startKeep
+			s.reachErr = nil
+			return nil
+		},
+		stopFn: func() error {
+			s.stops++
+			s.running = false
+			return nil
+		},
+		nudgeFn: func(message string) error {
+			s.nudges = append(s.nudges, message)
+			return nil
+		},
+		eventFn: func(payload map[string]interface{}) { s.events++ },
+	}
+}
+
+func TestDoltWatchdog_LeavesStoppedServerAlone(t *testing.T) {
+	for name, state := range map[string]*doltserver.State{
+		"never started": nil,
+		"gt dolt stop":  {Running: false},
+		"draining":      {Running: true, Draining: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := &fakeDoltServer{state: state}
+			newTestWatchdog(s).check(time.Now())
+			if s.starts != 0 {
+				t.Errorf("started %d times, want 0", s.starts)
+			}
+		})
+	}
+}
+
+func TestDoltWatchdog_RestartsDeadServer(t *testing.T) {
+	s := &fakeDoltServer{state: &doltserver.State{Running: true}, startKeep: true}
+	w := newTestWatchdog(s)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	w.check(now)
+	if s.starts != 1 || s.stops != 0 || s.events != 1 {
+		t.Fatalf("starts=%d stops=%d events=%d, want one start, no stop, one event", s.starts, s.stops, s.events)
+	}
+
+	// Healthy afterwards: no more restarts, and the backoff resets once the
+	// server has stayed up.
+	w.check(now.Add(3 * time.Minute))
+	if s.starts != 1 || w.attempts != 1 {
+		t.Fatalf("starts=%d attempts=%d after healthy check", s.starts, w.attempts)
+	}
+	w.check(now.Add(doltWatchdogStableAfter))
+	if w.attempts != 0 {
+		t.Errorf("attempts = %d after stable period, want 0", w.attempts)
+	}
+}
+
+func TestDoltWatchdog_BackoffAndNudge(t *testing.T) {
+	s := &fakeDoltServer{state: &doltserver.State{Running: true}, startErr: errors.New("port in use")}
+	w := newTestWatchdog(s)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Heartbeats every 3 minutes. Restarts are attempted at 0, then after
+	// waiting 1m (at 3m), 2m (at 6m), 4m (at 12m).
+	var attemptsAt []time.Duration
+	for i := 0; i <= 5; i++ {
+		elapsed := time.Duration(i) * 3 * time.Minute
+		before := s.starts
+		w.check(now.Add(elapsed))
+		if s.starts > before {
+			attemptsAt = append(attemptsAt, elapsed)
+		}
+	}
+	want := []time.Duration{0, 3 * time.Minute, 6 * time.Minute, 12 * time.Minute}
+	if fmt.Sprint(attemptsAt) != fmt.Sprint(want) {
+		t.Errorf("restart attempts at %v, want %v", attemptsAt, want)
+	}
+
+	// The mayor is nudged once, on the third attempt.
+	if len(s.nudges) != 1 {
+		t.Errorf("nudges = %d, want 1", len(s.nudges))
+	}
+}
+
+func TestDoltWatchdog_BackoffCapped(t *testing.T) {
+	s := &fakeDoltServer{state: &doltserver.State{Running: true}, startErr: errors.New("fail")}
+	w := newTestWatchdog(s)
+	w.maxDelay = 5 * time.Minute
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		w.check(now)
+		now = w.nextAttempt
+	}
+	if got := w.nextAttempt.Sub(w.lastRestart); got != 5*time.Minute {
+		t.Errorf("delay = %v, want capped at 5m", got)
+	}
+}
+
+func TestDoltWatchdog_HungServer(t *testing.T) {
+	s := &fakeDoltServer{
+		state:     &doltserver.State{Running: true},
+		running:   true,
+		reachErr:  errors.New("connection refused"),
+		startKeep: true,
+	}
+	w := newTestWatchdog(s)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// One unreachable check is tolerated.
+	w.check(now)
+	if s.starts != 0 {
+		t.Fatalf("restarted after one unreachable check")
+	}
+
+	// The second stops the hung process and starts a new one.
+	w.check(now.Add(3 * time.Minute))
+	if s.stops != 1 || s.starts != 1 {
+		t.Errorf("stops=%d starts=%d, want 1 and 1", s.stops, s.starts)
+	}
+}
+
+func TestWatchDoltServer_StandsDownForDoltServerPatrol(t *testing.T) {
+	d := &Daemon{
+		config:     &Config{TownRoot: t.TempDir()},
+		doltServer: NewDoltServerManager(t.TempDir(), &DoltServerConfig{Enabled: true}, nil),
+	}
+	d.watchDoltServer()
+	if d.doltWatchdog != nil {
+		t.Error("watchdog ran while the dolt_server patrol manages the server")
+	}
+
+	d.doltServer = nil
+	d.patrolConfig = &DaemonPatrolConfig{Patrols: &PatrolsConfig{DoltWatchdog: &DoltWatchdogConfig{Enabled: false}}}
+	d.watchDoltServer()
+	if d.doltWatchdog != nil {
+		t.Error("watchdog ran while disabled")
+	}
+}
+
+func TestDoltWatchdogSettings(t *testing.T) {
+	maxDelay, nudgeAfter := doltWatchdogSettings(nil)
+	if maxDelay != defaultDoltWatchdogMaxDelay || nudgeAfter != defaultDoltWatchdogNudgeAfter {
+		t.Errorf("defaults = %v, %d", maxDelay, nudgeAfter)
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{DoltWatchdog: &DoltWatchdogConfig{
+		Enabled: true, MaxRestartDelay: config.Dur(time.Hour), NudgeAfter: 5,
+	}}}
+	maxDelay, nudgeAfter = doltWatchdogSettings(cfg)
+	if maxDelay != time.Hour || nudgeAfter != 5 {
+		t.Errorf("configured = %v, %d", maxDelay, nudgeAfter)
+	}
+	if !IsPatrolEnabled(nil, "dolt_watchdog") {
+		t.Error("dolt_watchdog should be enabled by default")
+	}
+}
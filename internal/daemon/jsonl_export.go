@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+const defaultJSONLExportInterval = 30 * time.Minute
+
+// jsonlExportInterval returns the configured export interval, or the default (30m).
+func jsonlExportInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.JSONLExport != nil {
+		return config.Patrols.JSONLExport.Interval.Or(defaultJSONLExportInterval)
+	}
+	return defaultJSONLExportInterval
+}
+
+// exportJSONL regenerates issues.jsonl for each configured rig (by default,
+// every rig with sync.mode=dolt-native) and commits changed files.
+// Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) exportJSONL() {
+	if !IsPatrolEnabled(d.patrolConfig, "jsonl_export") {
+		return
+	}
+	if d.doltServer == nil || !d.doltServer.IsEnabled() {
+		d.logger.Printf("jsonl_export: dolt server not configured, skipping")
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	config := d.patrolConfig.Patrols.JSONLExport
+
+	databases := config.Databases
+	if len(databases) == 0 {
+		var err error
+		databases, err = doltserver.DoltNativeDatabases(townRoot)
+		if err != nil {
+			d.logger.Printf("jsonl_export: error discovering databases: %v", err)
+			return
+		}
+	}
+	if len(databases) == 0 {
+		return
+	}
+
+	var changed, committed int
+	for _, db := range databases {
+		res, err := doltserver.ExportJSONL(townRoot, db, !config.NoCommit)
+		if err != nil {
+			d.logger.Printf("jsonl_export: %s: %v", db, err)
+			continue
+		}
+		if res.Changed {
+			changed++
+		}
+		if res.Committed {
+			committed++
+		}
+	}
+
+	d.logger.Printf("jsonl_export: exported %d database(s), %d changed, %d committed", len(databases), changed, committed)
+}
@@ -0,0 +1,180 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Leader lease timing. The leader renews its lease every leaseRenewInterval;
+// a lease not renewed within leaseTTL is considered abandoned and may be
+// taken over by another daemon.
+const (
+	leaseRenewInterval = 30 * time.Second
+	leaseTTL           = 3 * leaseRenewInterval
+)
+
+// ErrLeaseLost is returned when renewing a lease that another daemon has
+// taken over.
+var ErrLeaseLost = errors.New("daemon leader lease lost")
+
+// Lease records which daemon process leads a town. The daemon.lock flock
+// already keeps two daemons on one host apart; the lease also covers towns
+// on shared filesystems, where flock may not work across hosts, and tells
+// gt daemon status who holds leadership and how recently it checked in.
+type Lease struct {
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+}
+
+// Age returns how long ago the lease was last renewed.
+func (l *Lease) Age(now time.Time) time.Duration {
+	return now.Sub(l.RenewedAt)
+}
+
+// Expired reports whether the holder has stopped renewing the lease.
+func (l *Lease) Expired(now time.Time) bool {
+	return l.Age(now) > leaseTTL
+}
+
+func (l *Lease) ownedBy(pid int, host string) bool {
+	return l.PID == pid && l.Host == host
+}
+
+// live reports whether the lease still protects its holder: it is not
+// expired, and if held on this host, the holder process is alive.
+func (l *Lease) live(now time.Time, host string) bool {
+	if l.Expired(now) {
+		return false
+	}
+	if l.Host == host && !procs.Alive(l.PID) {
+		return false
+	}
+	return true
+}
+
+// LeaseHeldError is returned when another daemon holds a live lease.
+type LeaseHeldError struct {
+	Holder *Lease
+	Age    time.Duration
+}
+
+func (e *LeaseHeldError) Error() string {
+	if e.Holder.PID == 0 {
+		// The flock is held but the holder has not written a lease yet.
+		return "daemon already running (lock held by another process)"
+	}
+	return fmt.Sprintf("daemon already running as PID %d on %s (lease renewed %s ago)",
+		e.Holder.PID, e.Holder.Host, e.Age.Round(time.Second))
+}
+
+// LeaseFile returns the path to the daemon leader lease.
+func LeaseFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "leader.json")
+}
+
+// LoadLease reads the town's leader lease. Returns nil, nil when no daemon
+// has ever held one (or the last leader released it).
+func LoadLease(townRoot string) (*Lease, error) {
+	data, err := os.ReadFile(LeaseFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var lease Lease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(LeaseFile(townRoot)), err)
+	}
+	return &lease, nil
+}
+
+func saveLease(townRoot string, lease *Lease) error {
+	path := LeaseFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, lease)
+}
+
+// acquireLease takes leadership for pid on host, unless another daemon
+// holds a live lease (*LeaseHeldError). The lease is read back after
+// writing, so of two daemons racing for an abandoned lease only the one
+// whose write landed last proceeds.
+func acquireLease(townRoot string, pid int, host string, now time.Time) (*Lease, error) {
+	cur, err := LoadLease(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil && !cur.ownedBy(pid, host) && cur.live(now, host) {
+		return nil, &LeaseHeldError{Holder: cur, Age: cur.Age(now)}
+	}
+
+	lease := &Lease{PID: pid, Host: host, AcquiredAt: now, RenewedAt: now}
+	if err := saveLease(townRoot, lease); err != nil {
+		return nil, fmt.Errorf("writing leader lease: %w", err)
+	}
+	got, err := LoadLease(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if got == nil {
+		return nil, fmt.Errorf("leader lease disappeared while acquiring it")
+	}
+	if !got.ownedBy(pid, host) {
+		return nil, &LeaseHeldError{Holder: got, Age: got.Age(now)}
+	}
+	return lease, nil
+}
+
+// renewLease extends lease. If another daemon has taken the lease over
+// (because this one stalled past leaseTTL), returns an error wrapping
+// ErrLeaseLost and this daemon must stop acting as leader.
+func renewLease(townRoot string, lease *Lease, now time.Time) error {
+	cur, err := LoadLease(townRoot)
+	if err != nil {
+		return err
+	}
+	if cur != nil && !cur.ownedBy(lease.PID, lease.Host) {
+		return fmt.Errorf("%w to PID %d on %s", ErrLeaseLost, cur.PID, cur.Host)
+	}
+	lease.RenewedAt = now
+	return saveLease(townRoot, lease)
+}
+
+// releaseLease removes lease if this daemon still holds it.
+func releaseLease(townRoot string, lease *Lease) {
+	if cur, err := LoadLease(townRoot); err == nil && cur != nil && cur.ownedBy(lease.PID, lease.Host) {
+		_ = os.Remove(LeaseFile(townRoot))
+	}
+}
+
+// leaseHost returns the host name recorded in leases.
+func leaseHost() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "unknown"
+	}
+	return host
+}
+
+// CurrentLeader returns the live lease of the daemon leading the town, or
+// nil if no daemon holds one (never started, stopped, or stopped renewing).
+func CurrentLeader(townRoot string) (*Lease, error) {
+	lease, err := LoadLease(townRoot)
+	if err != nil || lease == nil {
+		return nil, err
+	}
+	if !lease.live(clk.Now(), leaseHost()) {
+		return nil, nil
+	}
+	return lease, nil
+}
@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestLeaseAcquireRenewRelease(t *testing.T) {
+	townRoot := t.TempDir()
+	defer SetProcessTable(proc.NewFakeTable(100, 200))()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	lease, err := acquireLease(townRoot, 100, "host-a", now)
+	if err != nil {
+		t.Fatalf("acquireLease() error = %v", err)
+	}
+
+	// A second daemon is turned away while the leader renews.
+	_, err = acquireLease(townRoot, 200, "host-a", now.Add(leaseRenewInterval))
+	var held *LeaseHeldError
+	if !errors.As(err, &held) || held.Holder.PID != 100 {
+		t.Fatalf("second acquireLease() error = %v, want LeaseHeldError for PID 100", err)
+	}
+	if err := renewLease(townRoot, lease, now.Add(leaseRenewInterval)); err != nil {
+		t.Fatalf("renewLease() error = %v", err)
+	}
+	if _, err := acquireLease(townRoot, 200, "host-b", now.Add(leaseTTL)); !errors.As(err, &held) {
+		t.Fatalf("acquireLease() from another host within TTL error = %v, want LeaseHeldError", err)
+	}
+
+	releaseLease(townRoot, lease)
+	if got, _ := LoadLease(townRoot); got != nil {
+		t.Errorf("lease still present after release: %+v", got)
+	}
+}
+
+func TestLeaseTakeover(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("expired", func(t *testing.T) {
+		townRoot := t.TempDir()
+		defer SetProcessTable(proc.NewFakeTable(100, 200))()
+		stale, _ := acquireLease(townRoot, 100, "host-a", now)
+
+		later := now.Add(leaseTTL + time.Second)
+		if _, err := acquireLease(townRoot, 200, "host-b", later); err != nil {
+			t.Fatalf("acquireLease() over expired lease error = %v", err)
+		}
+		// The stalled leader learns it lost on its next renewal.
+		if err := renewLease(townRoot, stale, later); !errors.Is(err, ErrLeaseLost) {
+			t.Errorf("renewLease() by old leader error = %v, want ErrLeaseLost", err)
+		}
+		// And must not remove the new leader's lease on the way out.
+		releaseLease(townRoot, stale)
+		if got, _ := LoadLease(townRoot); got == nil || got.PID != 200 {
+			t.Errorf("lease after old leader release = %+v, want PID 200", got)
+		}
+	})
+
+	t.Run("dead holder on same host", func(t *testing.T) {
+		townRoot := t.TempDir()
+		defer SetProcessTable(proc.NewFakeTable(200))()
+		if _, err := acquireLease(townRoot, 100, "host-a", now); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := acquireLease(townRoot, 200, "host-a", now.Add(time.Second)); err != nil {
+			t.Errorf("acquireLease() over dead holder error = %v", err)
+		}
+	})
+}
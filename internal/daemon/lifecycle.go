@@ -733,6 +733,9 @@ type AgentBeadInfo struct {
 	RoleType   string // Parsed from description: role_type
 	Rig        string // Parsed from description: rig
 	LastUpdate string `json:"updated_at"`
+	// LastHeartbeat is parsed from description: last_heartbeat (empty if the
+	// agent has never sent one).
+	LastHeartbeat string
 	// Note: RoleBead field removed - role definitions are now config-based
 }
 
@@ -750,11 +753,7 @@ func (d *Daemon) getAgentBeadState(agentBeadID string) (string, error) {
 
 // getAgentBeadInfo fetches and parses an agent bead by ID.
 func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
-	cmd := exec.Command(d.bdPath, "show", agentBeadID, "--json")
-	cmd.Dir = d.config.TownRoot
-	cmd.Env = os.Environ() // Inherit PATH to find bd executable
-
-	output, err := cmd.Output()
+	output, err := d.executor().Output(d.config.TownRoot, d.bdCommand(), "show", agentBeadID, "--json")
 	if err != nil {
 		return nil, fmt.Errorf("bd show %s: %w", agentBeadID, err)
 	}
@@ -804,6 +803,7 @@ func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
 		info.State = fields.AgentState
 		info.RoleType = fields.RoleType
 		info.Rig = fields.Rig
+		info.LastHeartbeat = fields.LastHeartbeat
 	}
 
 	// Use HookBead from database column directly (not from description)
@@ -817,11 +817,7 @@ func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
 // Used for TOCTOU re-verification before taking destructive action on agents.
 // Returns empty string on error or if no hook_bead is set.
 func (d *Daemon) getAgentHookBead(agentBeadID string) string {
-	cmd := exec.Command(d.bdPath, "show", agentBeadID, "--json")
-	cmd.Dir = d.config.TownRoot
-	cmd.Env = os.Environ()
-
-	output, err := cmd.Output()
+	output, err := d.executor().Output(d.config.TownRoot, d.bdCommand(), "show", agentBeadID, "--json")
 	if err != nil {
 		return ""
 	}
@@ -919,11 +915,7 @@ func (d *Daemon) checkGUPPViolations() {
 func (d *Daemon) checkRigGUPPViolations(rigName string) {
 	// List polecat agent beads for this rig
 	// Pattern: <prefix>-<rig>-polecat-<name> (e.g., gt-gastown-polecat-Toast)
-	cmd := exec.Command(d.bdPath, "list", "--label=gt:agent", "--json")
-	cmd.Dir = d.config.TownRoot
-	cmd.Env = os.Environ() // Inherit PATH to find bd executable
-
-	output, err := cmd.Output()
+	output, err := d.executor().Output(d.config.TownRoot, d.bdCommand(), "list", "--label=gt:agent", "--json")
 	if err != nil {
 		d.logger.Printf("Warning: bd list failed for GUPP check: %v", err)
 		return
@@ -975,24 +967,70 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 				d.logger.Printf("GUPP violation: agent %s has hook_bead=%s but hasn't updated in %v (timeout: %v)",
 					agent.ID, agent.HookBead, age.Round(time.Minute), GUPPViolationTimeout)
 
-				// Notify the witness for this rig
-				d.notifyWitnessOfGUPP(rigName, agent.ID, agent.HookBead, age)
+				// Notify the witness for this rig, naming the step to resume
+				step := d.nextOpenStep(rigName, agent.HookBead)
+				d.notifyWitnessOfGUPP(rigName, agent.ID, agent.HookBead, step, age)
+			}
+		}
+	}
+}
+
+// nextOpenStep returns the first ready step of the molecule attached to
+// hookBead (or of hookBead itself, when it is the molecule root). Returns nil
+// if there is no molecule or no step is ready.
+func (d *Daemon) nextOpenStep(rigName, hookBead string) *beads.Issue {
+	dir := filepath.Join(d.config.TownRoot, rigName)
+
+	molID := hookBead
+	if out, err := d.executor().Output(dir, d.bdCommand(), "show", hookBead, "--json"); err == nil {
+		var issues []*beads.Issue
+		if json.Unmarshal(out, &issues) == nil && len(issues) > 0 {
+			if fields := beads.ParseAttachmentFields(issues[0]); fields != nil && fields.AttachedMolecule != "" {
+				molID = fields.AttachedMolecule
 			}
 		}
 	}
+
+	out, err := d.executor().Output(dir, d.bdCommand(), "ready", "--mol", molID, "--json", "-n", "1")
+	if err != nil {
+		return nil
+	}
+	var steps []*beads.Issue
+	if err := json.Unmarshal(out, &steps); err != nil || len(steps) == 0 {
+		return nil
+	}
+	return steps[0]
+}
+
+// guppNudge is the nudge the witness should send a stalled polecat. When the
+// next open step is known it names it, so the polecat can resume without
+// re-deriving where it left off.
+func guppNudge(hookBead string, step *beads.Issue) string {
+	if step == nil {
+		return fmt.Sprintf("You have %s on your hook. Run `gt hook` and continue the work.", hookBead)
+	}
+	return fmt.Sprintf("You have %s on your hook. Next open step: %s %q. Run `bd show %s`, do it, then close it with `bd close %s`.",
+		hookBead, step.ID, step.Title, step.ID, step.ID)
 }
 
 // notifyWitnessOfGUPP sends a mail to the rig's witness about a GUPP violation.
-func (d *Daemon) notifyWitnessOfGUPP(rigName, agentID, hookBead string, stuckDuration time.Duration) {
+// step is the polecat's next open step, or nil if unknown.
+func (d *Daemon) notifyWitnessOfGUPP(rigName, agentID, hookBead string, step *beads.Issue, stuckDuration time.Duration) {
 	witnessAddr := rigName + "/witness"
 	subject := fmt.Sprintf("GUPP_VIOLATION: %s stuck for %v", agentID, stuckDuration.Round(time.Minute))
+	nextStep := "unknown"
+	if step != nil {
+		nextStep = fmt.Sprintf("%s (%s)", step.ID, step.Title)
+	}
 	body := fmt.Sprintf(`Agent %s has work on hook but isn't progressing.
 
 hook_bead: %s
+next_step: %s
 stuck_duration: %v
 
-Action needed: Check if agent is alive and responsive. Consider restarting if stuck.`,
-		agentID, hookBead, stuckDuration.Round(time.Minute))
+Action needed: Check if agent is alive and responsive. Consider restarting if stuck.
+Suggested nudge: %s`,
+		agentID, hookBead, nextStep, stuckDuration.Round(time.Minute), guppNudge(hookBead, step))
 
 	cmd := exec.Command(d.gtPath, "mail", "send", witnessAddr, "-s", subject, "-m", body)
 	cmd.Dir = d.config.TownRoot
@@ -1018,11 +1056,7 @@ func (d *Daemon) checkOrphanedWork() {
 
 // checkRigOrphanedWork checks polecats in a specific rig for orphaned work.
 func (d *Daemon) checkRigOrphanedWork(rigName string) {
-	cmd := exec.Command(d.bdPath, "list", "--label=gt:agent", "--json")
-	cmd.Dir = d.config.TownRoot
-	cmd.Env = os.Environ() // Inherit PATH to find bd executable
-
-	output, err := cmd.Output()
+	output, err := d.executor().Output(d.config.TownRoot, d.bdCommand(), "list", "--label=gt:agent", "--json")
 	if err != nil {
 		d.logger.Printf("Warning: bd list failed for orphaned work check: %v", err)
 		return
@@ -1,12 +1,17 @@
 package daemon
 
 import (
+	"bytes"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/execrec"
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // testDaemon creates a minimal Daemon for testing.
@@ -433,3 +438,102 @@ func TestSyncWorkspace_DirtyTreeAutoStash(t *testing.T) {
 		t.Errorf("expected 0 sync failures after successful sync, got %d", got)
 	}
 }
+
+// replayDaemon returns a test daemon whose bd calls are served from a
+// recorded execrec fixture in testdata/.
+func replayDaemon(t *testing.T, fixture string) (*Daemon, *execrec.Replayer) {
+	t.Helper()
+	replayer, err := execrec.LoadReplayer(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatalf("loading fixture: %v", err)
+	}
+	d := testDaemon()
+	d.runner = replayer
+	return d, replayer
+}
+
+func TestGetAgentBeadInfo_Replay(t *testing.T) {
+	d, replayer := replayDaemon(t, "bd_show_agent.json")
+
+	info, err := d.getAgentBeadInfo("gt-gastown-polecat-toast")
+	if err != nil {
+		t.Fatalf("getAgentBeadInfo: %v", err)
+	}
+	if info.State != "stuck" {
+		t.Errorf("State = %q, want %q", info.State, "stuck")
+	}
+	if info.RoleType != "polecat" || info.Rig != "gastown" {
+		t.Errorf("RoleType/Rig = %q/%q, want polecat/gastown", info.RoleType, info.Rig)
+	}
+	if info.HookBead != "gt-abc12" {
+		t.Errorf("HookBead = %q, want %q", info.HookBead, "gt-abc12")
+	}
+
+	if _, err := d.getAgentBeadInfo("gt-xyz99"); err == nil {
+		t.Error("expected error for non-agent bead")
+	}
+	if _, err := d.getAgentBeadInfo("gt-missing"); err == nil {
+		t.Error("expected error for missing bead")
+	}
+
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("fixture has %d unreplayed interactions: %+v", len(unused), unused)
+	}
+}
+
+func TestGetAgentHookBead_Replay(t *testing.T) {
+	d, _ := replayDaemon(t, "bd_show_agent.json")
+
+	if got := d.getAgentHookBead("gt-gastown-polecat-toast"); got != "gt-abc12" {
+		t.Errorf("getAgentHookBead = %q, want %q", got, "gt-abc12")
+	}
+	// Unrecorded commands fail rather than silently running bd.
+	if got := d.getAgentHookBead("gt-gastown-polecat-toast"); got != "" {
+		t.Errorf("second call should exhaust the fixture, got %q", got)
+	}
+}
+
+func TestCheckRigGUPPViolations_Replay(t *testing.T) {
+	d, replayer := replayDaemon(t, "bd_list_agents.json")
+	d.tmux = tmux.NewTmux()
+	var logs bytes.Buffer
+	d.logger = log.New(&logs, "", 0)
+	// Any attempt to mail the witness would fail and be logged.
+	d.gtPath = filepath.Join(t.TempDir(), "gt-missing")
+
+	// The polecat has hooked work but its session does not exist, so no
+	// GUPP violation is raised and the witness is not mailed.
+	d.checkRigGUPPViolations("gastown")
+
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("bd list was not called: %+v", unused)
+	}
+	if strings.Contains(logs.String(), "GUPP violation") {
+		t.Errorf("GUPP violation raised for a polecat without a session:\n%s", logs.String())
+	}
+}
+
+func TestNextOpenStep_Replay(t *testing.T) {
+	d, replayer := replayDaemon(t, "bd_next_step.json")
+
+	step := d.nextOpenStep("gastown", "gt-abc12")
+	if step == nil {
+		t.Fatal("nextOpenStep = nil, want the molecule's ready step")
+	}
+	if step.ID != "gt-wisp-m1.3" || step.Title != "Run the test suite" {
+		t.Errorf("step = %s %q, want gt-wisp-m1.3 \"Run the test suite\"", step.ID, step.Title)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("unused interactions: %+v", unused)
+	}
+
+	msg := guppNudge("gt-abc12", step)
+	for _, want := range []string{"gt-wisp-m1.3", "Run the test suite", "bd close gt-wisp-m1.3"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("nudge %q missing %q", msg, want)
+		}
+	}
+	if msg := guppNudge("gt-abc12", nil); !strings.Contains(msg, "gt hook") {
+		t.Errorf("fallback nudge %q should point at gt hook", msg)
+	}
+}
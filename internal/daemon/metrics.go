@@ -0,0 +1,168 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// The metrics endpoint serves Dolt health in the Prometheus text format on
+// GET /metrics, so operators can graph it instead of polling gt dolt status.
+// Unlike the control API it listens on TCP, since scrapers run elsewhere;
+// it only reports figures and takes no actions.
+
+const (
+	defaultMetricsListen = "127.0.0.1:9464"
+
+	// metricsCacheTTL is how long a collected snapshot is served. Collecting
+	// probes the server with a test write and walks .dolt-data, which is too
+	// much to repeat for every scrape when several scrapers poll.
+	metricsCacheTTL = 15 * time.Second
+)
+
+// IsMetricsEnabled reports whether the metrics endpoint is enabled (opt-in).
+func IsMetricsEnabled(config *DaemonPatrolConfig) bool {
+	return config != nil && config.Metrics != nil && config.Metrics.Enabled
+}
+
+// metricsListenAddr returns the configured listen address, or the default.
+func metricsListenAddr(config *DaemonPatrolConfig) string {
+	if config != nil && config.Metrics != nil && config.Metrics.Listen != "" {
+		return config.Metrics.Listen
+	}
+	return defaultMetricsListen
+}
+
+// doltMetricsSnapshot is one collection of Dolt health figures.
+type doltMetricsSnapshot struct {
+	Up        bool
+	Health    *doltserver.HealthMetrics    // nil when the server is down
+	Databases []doltserver.DatabaseMetrics // nil when the server is down
+	Duration  time.Duration                // time taken to collect
+}
+
+// collectDoltMetrics gathers a snapshot. Only the process check runs when
+// the server is down, so a dead server doesn't stall the scrape on query
+// timeouts.
+func collectDoltMetrics(townRoot string) doltMetricsSnapshot {
+	start := clk.Now()
+	var snap doltMetricsSnapshot
+	if running, _, err := doltserver.IsRunning(townRoot); err == nil && running {
+		snap.Up = true
+		snap.Health = doltserver.GetHealthMetrics(townRoot)
+		snap.Databases, _ = doltserver.GetDatabaseMetrics(townRoot)
+	}
+	snap.Duration = clk.Since(start)
+	return snap
+}
+
+// writeDoltMetrics writes snap in the Prometheus text exposition format.
+func writeDoltMetrics(w io.Writer, snap doltMetricsSnapshot) {
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, value)
+	}
+	perDB := func(name, help string, value func(doltserver.DatabaseMetrics) (float64, bool)) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, db := range snap.Databases {
+			if v, ok := value(db); ok {
+				fmt.Fprintf(w, "%s{database=\"%s\"} %g\n", name, escapeLabelValue(db.Name), v)
+			}
+		}
+	}
+
+	gauge("gt_dolt_up", "Whether the Dolt server is running.", boolGauge(snap.Up))
+	gauge("gt_dolt_scrape_duration_seconds", "Time taken to collect these metrics.", snap.Duration.Seconds())
+	if h := snap.Health; h != nil {
+		gauge("gt_dolt_healthy", "Whether the Dolt server is within resource limits.", boolGauge(h.Healthy))
+		gauge("gt_dolt_read_only", "Whether the Dolt server is in read-only mode.", boolGauge(h.ReadOnly))
+		gauge("gt_dolt_query_latency_seconds", "Round-trip time of a SELECT 1.", h.QueryLatency.Seconds())
+		gauge("gt_dolt_connections", "Active connections to the Dolt server.", float64(h.Connections))
+		gauge("gt_dolt_max_connections", "Configured maximum connections.", float64(h.MaxConnections))
+		gauge("gt_dolt_disk_usage_bytes", "Total size of .dolt-data.", float64(h.DiskUsageBytes))
+		gauge("gt_dolt_warnings", "Number of active health warnings.", float64(len(h.Warnings)))
+	}
+	if snap.Databases != nil {
+		perDB("gt_dolt_database_size_bytes", "On-disk size of each database.", func(db doltserver.DatabaseMetrics) (float64, bool) {
+			return float64(db.SizeBytes), true
+		})
+		perDB("gt_dolt_branches", "Branches in each database, including main.", func(db doltserver.DatabaseMetrics) (float64, bool) {
+			return float64(db.Branches), db.Branches >= 0
+		})
+		perDB("gt_dolt_polecat_branches", "Polecat branches in each database.", func(db doltserver.DatabaseMetrics) (float64, bool) {
+			return float64(db.PolecatBranches), db.PolecatBranches >= 0
+		})
+	}
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+// metricsHandler serves GET /metrics, collecting at most once per
+// metricsCacheTTL.
+func (d *Daemon) metricsHandler() http.Handler {
+	var (
+		mu        sync.Mutex
+		last      doltMetricsSnapshot
+		collected time.Time
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if collected.IsZero() || d.clock().Since(collected) >= metricsCacheTTL {
+			last = collectDoltMetrics(d.config.TownRoot)
+			collected = d.clock().Now()
+		}
+		snap := last
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writeDoltMetrics(w, snap)
+	})
+	return mux
+}
+
+// startMetricsServer starts serving the metrics endpoint. The caller
+// shuts down the returned server.
+func (d *Daemon) startMetricsServer() (*http.Server, error) {
+	addr := metricsListenAddr(d.patrolConfig)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", addr, err)
+	}
+	srv := &http.Server{
+		Handler:           d.metricsHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("metrics: %v", err)
+		}
+	}()
+	d.logger.Printf("Metrics listening on http://%s/metrics", ln.Addr())
+	return srv, nil
+}
+
+// stopMetricsServer shuts the metrics server down.
+func stopMetricsServer(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
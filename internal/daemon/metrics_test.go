@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestWriteDoltMetrics(t *testing.T) {
+	var b strings.Builder
+	writeDoltMetrics(&b, doltMetricsSnapshot{
+		Up: true,
+		Health: &doltserver.HealthMetrics{
+			Connections:    3,
+			MaxConnections: 50,
+			QueryLatency:   12 * time.Millisecond,
+			Healthy:        true,
+			Warnings:       []string{"disk"},
+		},
+		Databases: []doltserver.DatabaseMetrics{
+			{Name: "gastown", SizeBytes: 2048, Branches: 5, PolecatBranches: 4},
+			{Name: "beads", SizeBytes: 10, Branches: -1, PolecatBranches: -1},
+		},
+	})
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE gt_dolt_up gauge\ngt_dolt_up 1\n",
+		"gt_dolt_healthy 1\n",
+		"gt_dolt_read_only 0\n",
+		"gt_dolt_query_latency_seconds 0.012\n",
+		"gt_dolt_connections 3\n",
+		"gt_dolt_max_connections 50\n",
+		"gt_dolt_warnings 1\n",
+		`gt_dolt_database_size_bytes{database="gastown"} 2048`,
+		`gt_dolt_database_size_bytes{database="beads"} 10`,
+		`gt_dolt_branches{database="gastown"} 5`,
+		`gt_dolt_polecat_branches{database="gastown"} 4`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `gt_dolt_branches{database="beads"}`) {
+		t.Errorf("unknown branch count should be omitted:\n%s", out)
+	}
+}
+
+func TestWriteDoltMetrics_Down(t *testing.T) {
+	var b strings.Builder
+	writeDoltMetrics(&b, doltMetricsSnapshot{})
+	out := b.String()
+	if !strings.Contains(out, "gt_dolt_up 0\n") {
+		t.Errorf("want gt_dolt_up 0:\n%s", out)
+	}
+	if strings.Contains(out, "gt_dolt_connections") || strings.Contains(out, "gt_dolt_branches") {
+		t.Errorf("down server should only report up:\n%s", out)
+	}
+}
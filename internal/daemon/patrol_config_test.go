@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -90,7 +93,7 @@ func TestDoltRemotesInterval(t *testing.T) {
 		Patrols: &PatrolsConfig{
 			DoltRemotes: &DoltRemotesConfig{
 				Enabled:  true,
-				Interval: 5 * 60 * 1000000000, // 5 minutes in nanoseconds
+				Interval: config.Dur(5 * 60 * 1000000000), // 5 minutes in nanoseconds
 			},
 		},
 	}
@@ -98,3 +101,278 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_JSONLExport(t *testing.T) {
+	// jsonl_export is opt-in, like dolt_remotes
+	if IsPatrolEnabled(nil, "jsonl_export") {
+		t.Error("expected jsonl_export to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "jsonl_export") {
+		t.Error("expected jsonl_export to be disabled by default")
+	}
+
+	config.Patrols.JSONLExport = &JSONLExportConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "jsonl_export") {
+		t.Error("expected jsonl_export to be enabled when configured")
+	}
+}
+
+func TestJSONLExportInterval(t *testing.T) {
+	if got := jsonlExportInterval(nil); got != defaultJSONLExportInterval {
+		t.Errorf("expected default interval %v, got %v", defaultJSONLExportInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			JSONLExport: &JSONLExportConfig{
+				Enabled:  true,
+				Interval: config.Dur(10 * time.Minute),
+			},
+		},
+	}
+	if got := jsonlExportInterval(config); got != 10*time.Minute {
+		t.Errorf("expected 10m interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_CostEnforce(t *testing.T) {
+	// cost_enforce is opt-in: downgrading models needs an explicit policy
+	if IsPatrolEnabled(nil, "cost_enforce") {
+		t.Error("expected cost_enforce to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "cost_enforce") {
+		t.Error("expected cost_enforce to be disabled by default")
+	}
+
+	config.Patrols.CostEnforce = &CostEnforceConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "cost_enforce") {
+		t.Error("expected cost_enforce to be enabled when configured")
+	}
+}
+
+func TestCostEnforceInterval(t *testing.T) {
+	if got := costEnforceInterval(nil); got != defaultCostEnforceInterval {
+		t.Errorf("expected default interval %v, got %v", defaultCostEnforceInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			CostEnforce: &CostEnforceConfig{
+				Enabled:  true,
+				Interval: config.Dur(time.Minute),
+			},
+		},
+	}
+	if got := costEnforceInterval(config); got != time.Minute {
+		t.Errorf("expected 1m interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_BackupVerify(t *testing.T) {
+	// backup_verify is opt-in: it clones from remotes and runs a scratch server
+	if IsPatrolEnabled(nil, "backup_verify") {
+		t.Error("expected backup_verify to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "backup_verify") {
+		t.Error("expected backup_verify to be disabled by default")
+	}
+
+	config.Patrols.BackupVerify = &BackupVerifyConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "backup_verify") {
+		t.Error("expected backup_verify to be enabled when configured")
+	}
+}
+
+func TestBackupVerifyInterval(t *testing.T) {
+	if got := backupVerifyInterval(nil); got != defaultBackupVerifyInterval {
+		t.Errorf("expected default interval %v, got %v", defaultBackupVerifyInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			BackupVerify: &BackupVerifyConfig{
+				Enabled:  true,
+				Interval: config.Dur(6 * time.Hour),
+			},
+		},
+	}
+	if got := backupVerifyInterval(config); got != 6*time.Hour {
+		t.Errorf("expected 6h interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_AnalyticsExport(t *testing.T) {
+	// analytics_export is opt-in: it writes files outside the beads databases
+	if IsPatrolEnabled(nil, "analytics_export") {
+		t.Error("expected analytics_export to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "analytics_export") {
+		t.Error("expected analytics_export to be disabled by default")
+	}
+
+	config.Patrols.AnalyticsExport = &AnalyticsExportConfig{Enabled: true}
+	config.Patrols.AnalyticsExport.Interval.Duration = 15 * time.Minute
+	if !IsPatrolEnabled(config, "analytics_export") {
+		t.Error("expected analytics_export to be enabled when configured")
+	}
+	if got := analyticsExportInterval(config); got != 15*time.Minute {
+		t.Errorf("expected 15m interval, got %v", got)
+	}
+	if got := analyticsExportInterval(nil); got != defaultAnalyticsExportInterval {
+		t.Errorf("expected default interval %v, got %v", defaultAnalyticsExportInterval, got)
+	}
+}
+
+func TestIsPatrolEnabled_SessionPrune(t *testing.T) {
+	// session_prune is opt-in: it kills tmux sessions
+	if IsPatrolEnabled(nil, "session_prune") {
+		t.Error("expected session_prune to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "session_prune") {
+		t.Error("expected session_prune to be disabled by default")
+	}
+
+	config.Patrols.SessionPrune = &SessionPruneConfig{Enabled: true}
+	config.Patrols.SessionPrune.Interval.Duration = 10 * time.Minute
+	if !IsPatrolEnabled(config, "session_prune") {
+		t.Error("expected session_prune to be enabled when configured")
+	}
+	if got := sessionPruneInterval(config); got != 10*time.Minute {
+		t.Errorf("expected 10m interval, got %v", got)
+	}
+	if got := sessionPruneInterval(nil); got != defaultSessionPruneInterval {
+		t.Errorf("expected default interval %v, got %v", defaultSessionPruneInterval, got)
+	}
+}
+
+func TestIsPatrolEnabled_BeadArchive(t *testing.T) {
+	// bead_archive is opt-in: it moves rows out of the issues tables
+	if IsPatrolEnabled(nil, "bead_archive") {
+		t.Error("expected bead_archive to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "bead_archive") {
+		t.Error("expected bead_archive to be disabled by default")
+	}
+
+	config.Patrols.BeadArchive = &BeadArchiveConfig{Enabled: true}
+	config.Patrols.BeadArchive.Interval.Duration = 6 * time.Hour
+	if !IsPatrolEnabled(config, "bead_archive") {
+		t.Error("expected bead_archive to be enabled when configured")
+	}
+	if got := beadArchiveInterval(config); got != 6*time.Hour {
+		t.Errorf("expected 6h interval, got %v", got)
+	}
+	if got := beadArchiveInterval(nil); got != defaultBeadArchiveInterval {
+		t.Errorf("expected default interval %v, got %v", defaultBeadArchiveInterval, got)
+	}
+}
+
+func TestIsPatrolEnabled_BranchPrune(t *testing.T) {
+	// branch_prune is opt-in: it force-deletes Dolt branches
+	if IsPatrolEnabled(nil, "branch_prune") {
+		t.Error("expected branch_prune to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "branch_prune") {
+		t.Error("expected branch_prune to be disabled by default")
+	}
+
+	config.Patrols.BranchPrune = &BranchPruneConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "branch_prune") {
+		t.Error("expected branch_prune to be enabled when configured")
+	}
+}
+
+func TestBranchPruneInterval(t *testing.T) {
+	if got := branchPruneInterval(nil); got != defaultBranchPruneInterval {
+		t.Errorf("expected default interval %v, got %v", defaultBranchPruneInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			BranchPrune: &BranchPruneConfig{
+				Enabled:  true,
+				Interval: config.Dur(12 * time.Hour),
+			},
+		},
+	}
+	if got := branchPruneInterval(config); got != 12*time.Hour {
+		t.Errorf("expected 12h interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_SLACheck(t *testing.T) {
+	// sla_check is opt-in: it creates escalations
+	if IsPatrolEnabled(nil, "sla_check") {
+		t.Error("expected sla_check to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "sla_check") {
+		t.Error("expected sla_check to be disabled by default")
+	}
+
+	config.Patrols.SLACheck = &SLACheckConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "sla_check") {
+		t.Error("expected sla_check to be enabled when configured")
+	}
+	if got := slaCheckInterval(config); got != defaultSLACheckInterval {
+		t.Errorf("expected default interval %v, got %v", defaultSLACheckInterval, got)
+	}
+	config.Patrols.SLACheck.Interval.Duration = 5 * time.Minute
+	if got := slaCheckInterval(config); got != 5*time.Minute {
+		t.Errorf("expected 5m interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_DoltGC(t *testing.T) {
+	// dolt_gc is opt-in: DOLT_GC drops every server connection
+	if IsPatrolEnabled(nil, "dolt_gc") {
+		t.Error("expected dolt_gc to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "dolt_gc") {
+		t.Error("expected dolt_gc to be disabled by default")
+	}
+
+	config.Patrols.DoltGC = &DoltGCConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "dolt_gc") {
+		t.Error("expected dolt_gc to be enabled when configured")
+	}
+	if got := doltGCInterval(config); got != defaultDoltGCInterval {
+		t.Errorf("expected default interval %v, got %v", defaultDoltGCInterval, got)
+	}
+	if got := doltGCThresholdBytes(config); got != 10<<30 {
+		t.Errorf("expected default threshold 10 GB, got %d", got)
+	}
+	config.Patrols.DoltGC.ThresholdGB = 0.5
+	if got := doltGCThresholdBytes(config); got != 512<<20 {
+		t.Errorf("expected 512 MB threshold, got %d", got)
+	}
+}
@@ -3,7 +3,6 @@
 package daemon
 
 import (
-	"os"
 	"os/exec"
 	"syscall"
 )
@@ -15,18 +14,3 @@ func setSysProcAttr(cmd *exec.Cmd) {
 		Setpgid: true,
 	}
 }
-
-// isProcessAlive checks if a process is still running.
-func isProcessAlive(p *os.Process) bool {
-	return p.Signal(syscall.Signal(0)) == nil
-}
-
-// sendTermSignal sends SIGTERM for graceful shutdown.
-func sendTermSignal(p *os.Process) error {
-	return p.Signal(syscall.SIGTERM)
-}
-
-// sendKillSignal sends SIGKILL for forced termination.
-func sendKillSignal(p *os.Process) error {
-	return p.Signal(syscall.SIGKILL)
-}
@@ -97,7 +97,7 @@ func (rt *RestartTracker) CanRestart(agentID string) bool {
 	}
 
 	// Check backoff period
-	return time.Now().After(info.BackoffUntil)
+	return clk.Now().After(info.BackoffUntil)
 }
 
 // RecordRestart records a restart attempt and calculates next backoff.
@@ -105,7 +105,7 @@ func (rt *RestartTracker) RecordRestart(agentID string) {
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
 
-	now := time.Now()
+	now := clk.Now()
 	info, exists := rt.state.Agents[agentID]
 	if !exists {
 		info = &AgentRestartInfo{}
@@ -153,7 +153,7 @@ func (rt *RestartTracker) RecordSuccess(agentID string) {
 	}
 
 	// If agent has been stable for the stability period, reset tracking
-	if time.Since(info.LastRestart) > stabilityPeriod {
+	if clk.Since(info.LastRestart) > stabilityPeriod {
 		info.RestartCount = 0
 		info.CrashLoopSince = time.Time{}
 		info.BackoffUntil = time.Time{}
@@ -182,7 +182,7 @@ func (rt *RestartTracker) GetBackoffRemaining(agentID string) time.Duration {
 		return 0
 	}
 
-	remaining := time.Until(info.BackoffUntil)
+	remaining := info.BackoffUntil.Sub(clk.Now())
 	if remaining < 0 {
 		return 0
 	}
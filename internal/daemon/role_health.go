@@ -0,0 +1,204 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/crashlog"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// roleRestartPolicy is how the daemon restarts one role's session when it
+// dies. Polecats are handled by checkPolecatHealth, which restarts only
+// those with hooked work.
+type roleRestartPolicy struct {
+	// StartupGrace is how long after the daemon starts a session it is left
+	// alone, so a slow start isn't mistaken for a dead agent and killed.
+	StartupGrace time.Duration
+
+	// EscalateCrashLoop mails the mayor once when the role starts
+	// crash-looping, since nothing else will notice its patrol stopped.
+	EscalateCrashLoop bool
+}
+
+// roleRestartPolicies holds the restart policy of each persistent role.
+// Every role gets the restart tracker's exponential backoff and crash-loop
+// detection.
+var roleRestartPolicies = map[string]roleRestartPolicy{
+	"deacon":   {StartupGrace: deaconGracePeriod},
+	"witness":  {StartupGrace: 2 * time.Minute, EscalateCrashLoop: true},
+	"refinery": {StartupGrace: 2 * time.Minute, EscalateCrashLoop: true},
+	"mayor":    {StartupGrace: 2 * time.Minute},
+}
+
+// roleSessionState is what the daemon observed about a role's session.
+type roleSessionState struct {
+	Exists   bool // tmux session exists
+	PaneDead bool // the session's pane has exited (remain-on-exit)
+	WasAlive bool // the daemon saw the session alive on an earlier heartbeat
+
+	SinceStart time.Duration // since the daemon last started it; 0 if never
+}
+
+// Role health actions.
+const (
+	roleHealthy  = "healthy"  // Running: nothing to do
+	roleStarting = "starting" // Inside its startup grace period: leave alone
+	roleStart    = "start"    // Not running and never seen alive: start it
+	roleCrashed  = "crashed"  // Died after running: capture forensics, restart
+)
+
+// classifyRoleSession decides what to do with a role's session.
+func classifyRoleSession(policy roleRestartPolicy, s roleSessionState) string {
+	if s.SinceStart > 0 && s.SinceStart < policy.StartupGrace {
+		return roleStarting
+	}
+	switch {
+	case s.Exists && !s.PaneDead:
+		return roleHealthy
+	case s.PaneDead || s.WasAlive:
+		return roleCrashed
+	default:
+		return roleStart
+	}
+}
+
+// checkRoleSession runs before the daemon (re)starts a role session and
+// reports whether it should go ahead. A session that died after running is
+// recorded as a crash (with its final pane output when the pane survived)
+// and counts toward mass-death detection; restarts then go through the
+// restart tracker's backoff, and a crash-looping role is left down.
+func (d *Daemon) checkRoleSession(role, agentID, sessionName string) bool {
+	policy := roleRestartPolicies[role]
+
+	state := roleSessionState{WasAlive: d.roleSeenAlive[agentID]}
+	if started, ok := d.roleLastStarted[agentID]; ok {
+		state.SinceStart = d.clock().Since(started)
+	}
+	exists, err := d.tmux.HasSession(sessionName)
+	if err != nil {
+		d.logger.Printf("Error checking %s session %s: %v", role, sessionName, err)
+		return false
+	}
+	state.Exists = exists
+	var deadPane *crashlog.Report
+	if exists {
+		if dead, status, err := d.tmux.PaneDeadStatus(sessionName); err == nil && dead {
+			state.PaneDead = true
+			deadPane = &crashlog.Report{ExitStatus: &status}
+			if lines, err := d.tmux.CapturePaneLines(sessionName, crashlog.DefaultOutputLines); err == nil {
+				deadPane.Output = crashlog.TrimOutput(lines)
+			}
+		}
+	}
+
+	switch classifyRoleSession(policy, state) {
+	case roleStarting:
+		return false
+	case roleHealthy:
+		d.setRoleSeenAlive(agentID, true)
+		if d.restartTracker != nil {
+			d.restartTracker.RecordSuccess(agentID)
+		}
+		d.dropRoleRestart(agentID)
+		return true // Manager.Start still catches a zombie agent
+	case roleCrashed:
+		d.logger.Printf("CRASH DETECTED: %s session %s died", agentID, sessionName)
+		d.captureRoleCrashReport(agentID, sessionName, deadPane)
+		d.recordSessionDeath(sessionName)
+		d.setRoleSeenAlive(agentID, false)
+		if deadPane != nil {
+			// Clear the dead pane's session so the restart can recreate it.
+			_ = d.tmux.KillSession(sessionName)
+		}
+	}
+
+	if d.restartTracker == nil {
+		return true
+	}
+	if d.restartTracker.IsInCrashLoop(agentID) {
+		d.logger.Printf("%s is in crash loop, skipping restart", agentID)
+		if policy.EscalateCrashLoop && !d.roleCrashLoopReported[agentID] {
+			d.escalateRoleCrashLoop(agentID)
+		}
+		return false
+	}
+	if !d.restartTracker.CanRestart(agentID) {
+		remaining := d.restartTracker.GetBackoffRemaining(agentID)
+		d.logger.Printf("%s restart in backoff, %s remaining", agentID, remaining.Round(time.Second))
+		d.queueRoleRestart(agentID, remaining)
+		return false
+	}
+	return true
+}
+
+// recordRoleStart records that the daemon started a role session, for the
+// startup grace period and restart backoff.
+func (d *Daemon) recordRoleStart(agentID string) {
+	if d.roleLastStarted == nil {
+		d.roleLastStarted = make(map[string]time.Time)
+	}
+	d.roleLastStarted[agentID] = d.clock().Now()
+	d.setRoleSeenAlive(agentID, true)
+	delete(d.roleCrashLoopReported, agentID)
+	d.dropRoleRestart(agentID)
+	if d.restartTracker != nil {
+		d.restartTracker.RecordRestart(agentID)
+		if err := d.restartTracker.Save(); err != nil {
+			d.logger.Printf("Warning: failed to save restart state: %v", err)
+		}
+	}
+}
+
+func (d *Daemon) setRoleSeenAlive(agentID string, alive bool) {
+	if d.roleSeenAlive == nil {
+		d.roleSeenAlive = make(map[string]bool)
+	}
+	d.roleSeenAlive[agentID] = alive
+}
+
+// captureRoleCrashReport stores a forensic report for a crashed role
+// session under daemon/crashes/_town/.
+func (d *Daemon) captureRoleCrashReport(agentID, sessionName string, deadPane *crashlog.Report) {
+	report := &crashlog.Report{
+		Agent:      agentID,
+		Session:    sessionName,
+		DetectedBy: crashlog.DetectedByDaemon,
+	}
+	if deadPane != nil {
+		report.ExitStatus = deadPane.ExitStatus
+		report.Output = deadPane.Output
+	}
+	if path, err := crashlog.Save(d.config.TownRoot, report); err != nil {
+		d.logger.Printf("Warning: saving crash report for %s: %v", agentID, err)
+	} else {
+		d.logger.Printf("Crash report saved: %s", path)
+	}
+	_ = events.LogFeed(events.TypeSessionDeath, "daemon",
+		events.SessionDeathPayload(sessionName, agentID, "crashed", "daemon"))
+}
+
+// escalateRoleCrashLoop tells the mayor a role stopped restarting, once per
+// crash loop.
+func (d *Daemon) escalateRoleCrashLoop(agentID string) {
+	if d.roleCrashLoopReported == nil {
+		d.roleCrashLoopReported = make(map[string]bool)
+	}
+	d.roleCrashLoopReported[agentID] = true
+
+	subject := fmt.Sprintf("CRASH_LOOP: %s stopped restarting", agentID)
+	body := fmt.Sprintf(`%s crashed repeatedly and the daemon stopped restarting it, so its
+patrol is not running.
+
+Crash reports: gt polecat crashes
+Once the cause is fixed, start it by hand. The daemon resumes managing it
+after it has stayed up for %v.`, agentID, stabilityPeriod)
+	cmd := exec.Command(d.gtPath, "mail", "send", "mayor/", "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify mayor of %s crash loop: %v", agentID, err)
+	}
+}
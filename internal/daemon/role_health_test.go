@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestClassifyRoleSession(t *testing.T) {
+	policy := roleRestartPolicy{StartupGrace: 2 * time.Minute}
+	tests := []struct {
+		name  string
+		state roleSessionState
+		want  string
+	}{
+		{"never started", roleSessionState{}, roleStart},
+		{"running", roleSessionState{Exists: true, WasAlive: true}, roleHealthy},
+		{"dead pane", roleSessionState{Exists: true, PaneDead: true}, roleCrashed},
+		{"session vanished", roleSessionState{WasAlive: true}, roleCrashed},
+		{"inside startup grace", roleSessionState{WasAlive: true, SinceStart: time.Minute}, roleStarting},
+		{"grace expired", roleSessionState{WasAlive: true, SinceStart: 3 * time.Minute}, roleCrashed},
+	}
+	for _, tt := range tests {
+		if got := classifyRoleSession(policy, tt.state); got != tt.want {
+			t.Errorf("%s: classifyRoleSession = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoleRestartPolicies(t *testing.T) {
+	for _, role := range []string{"deacon", "witness", "refinery", "mayor"} {
+		if p, ok := roleRestartPolicies[role]; !ok || p.StartupGrace <= 0 {
+			t.Errorf("role %s has no restart policy with a startup grace", role)
+		}
+	}
+}
+
+func TestRecordRoleStart(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := &Daemon{
+		config:         &Config{TownRoot: townRoot},
+		logger:         log.New(io.Discard, "", 0),
+		restartTracker: NewRestartTracker(townRoot),
+	}
+
+	d.recordRoleStart("gastown/witness")
+
+	if !d.roleSeenAlive["gastown/witness"] {
+		t.Error("started session not marked alive")
+	}
+	if !d.roleLastStarted["gastown/witness"].Equal(fake.Now()) {
+		t.Errorf("roleLastStarted = %v, want %v", d.roleLastStarted["gastown/witness"], fake.Now())
+	}
+	if d.restartTracker.CanRestart("gastown/witness") {
+		t.Error("expected restart backoff after a start")
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "daemon", "restart_state.json")); err != nil {
+		t.Errorf("restart state not saved: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+const defaultSessionPruneInterval = 30 * time.Minute
+
+// sessionPruneInterval returns the configured prune interval, or the default (30m).
+func sessionPruneInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.SessionPrune != nil {
+		return config.Patrols.SessionPrune.Interval.Or(defaultSessionPruneInterval)
+	}
+	return defaultSessionPruneInterval
+}
+
+// sessionPruneMinAge returns the configured minimum orphan age, or the
+// default (session.DefaultPruneMinAge).
+func sessionPruneMinAge(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.SessionPrune != nil {
+		return config.Patrols.SessionPrune.MinAge.Or(session.DefaultPruneMinAge)
+	}
+	return session.DefaultPruneMinAge
+}
+
+// pruneOrphanSessions kills rig tmux sessions that no known agent owns.
+// Orphaned crew sessions are left for a human. Non-fatal: errors are
+// logged but don't stop the patrol.
+func (d *Daemon) pruneOrphanSessions() {
+	if !IsPatrolEnabled(d.patrolConfig, "session_prune") {
+		return
+	}
+
+	result, err := session.PruneOrphanSessions(d.config.TownRoot, session.PruneOptions{
+		MinAge: sessionPruneMinAge(d.patrolConfig),
+		Caller: "daemon",
+	})
+	if err != nil {
+		d.logger.Printf("session_prune: %v", err)
+		return
+	}
+
+	var killed int
+	for _, o := range result.Orphans {
+		switch {
+		case o.Killed:
+			killed++
+			d.logger.Printf("session_prune: killed %s (%s)", o.Session, o.Reason)
+		case o.Error != "":
+			d.logger.Printf("session_prune: killing %s: %s", o.Session, o.Error)
+		}
+	}
+	d.logger.Printf("session_prune: killed %d of %d orphaned session(s) (%d checked)", killed, len(result.Orphans), result.Checked)
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultSLACheckInterval = 15 * time.Minute
+
+// slaCheckInterval returns the configured SLA check interval, or the default (15m).
+func slaCheckInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.SLACheck != nil {
+		return config.Patrols.SLACheck.Interval.Or(defaultSLACheckInterval)
+	}
+	return defaultSLACheckInterval
+}
+
+// checkSLATimers runs gt bead sla check --escalate, which escalates each
+// SLA timer on an open bead that passed its deadline, once. Non-fatal:
+// failures are logged but don't stop the patrol.
+func (d *Daemon) checkSLATimers() {
+	if !IsPatrolEnabled(d.patrolConfig, "sla_check") {
+		return
+	}
+
+	args := []string{"bead", "sla", "check", "--escalate"}
+	if cfg := d.patrolConfig.Patrols.SLACheck; cfg.Severity != "" {
+		args = append(args, "--severity", cfg.Severity)
+	}
+	cmd := exec.Command(d.gtPath, args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt and bd
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("sla_check: %v: %s", err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if strings.Contains(line, "escalated as") {
+			d.logger.Printf("sla_check: %s", line)
+		}
+	}
+}
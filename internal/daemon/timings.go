@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// defaultPendingSpawnTTL is how long a spawn may stay pending by default.
+const defaultPendingSpawnTTL = 5 * time.Minute
+
+// pendingSpawnTTL returns the configured pending spawn TTL, or the default (5m).
+func pendingSpawnTTL(cfg *DaemonPatrolConfig) time.Duration {
+	if cfg != nil && cfg.Guards != nil {
+		return cfg.Guards.PendingSpawnTTL.Or(defaultPendingSpawnTTL)
+	}
+	return defaultPendingSpawnTTL
+}
+
+// Timing is one duration setting from mayor/daemon.json with its
+// effective value.
+type Timing struct {
+	// Key is the setting's path in daemon.json, e.g. patrols.sla_check.interval.
+	Key   string          `json:"key"`
+	Value config.Duration `json:"value"`
+
+	// Default is true when the value is the built-in default.
+	Default bool `json:"default"`
+}
+
+// timingSettings maps each daemon.json duration setting to the function
+// the daemon uses to resolve it.
+var timingSettings = []struct {
+	key       string
+	effective func(cfg *DaemonPatrolConfig) time.Duration
+}{
+	{"guards.pending_spawn_ttl", pendingSpawnTTL},
+	{"patrols.dolt_remotes.interval", doltRemotesInterval},
+	{"patrols.jsonl_export.interval", jsonlExportInterval},
+	{"patrols.change_feed.interval", changeFeedInterval},
+	{"patrols.cost_enforce.interval", costEnforceInterval},
+	{"patrols.backup_verify.interval", backupVerifyInterval},
+	{"patrols.analytics_export.interval", analyticsExportInterval},
+	{"patrols.session_prune.interval", sessionPruneInterval},
+	{"patrols.session_prune.min_age", sessionPruneMinAge},
+	{"patrols.bead_archive.interval", beadArchiveInterval},
+	{"patrols.branch_prune.interval", branchPruneInterval},
+	{"patrols.sla_check.interval", slaCheckInterval},
+	{"patrols.dolt_gc.interval", doltGCInterval},
+	{"patrols.dolt_metrics.interval", doltMetricsInterval},
+	{"patrols.dolt_watchdog.max_restart_delay", func(cfg *DaemonPatrolConfig) time.Duration {
+		d, _ := doltWatchdogSettings(cfg)
+		return d
+	}},
+}
+
+// EffectiveTimings returns the daemon's duration settings with cfg (from
+// LoadPatrolConfig; nil for none) applied: the guards, the patrol
+// intervals, and the managed Dolt server's restart timings.
+func EffectiveTimings(townRoot string, cfg *DaemonPatrolConfig) []Timing {
+	timings := make([]Timing, 0, len(timingSettings)+5)
+	for _, s := range timingSettings {
+		d := s.effective(cfg)
+		timings = append(timings, Timing{Key: s.key, Value: config.Dur(d), Default: d == s.effective(nil)})
+	}
+
+	defaults := DefaultDoltServerConfig(townRoot)
+	set := &DoltServerConfig{}
+	if cfg != nil && cfg.Patrols != nil && cfg.Patrols.DoltServer != nil {
+		set = cfg.Patrols.DoltServer
+	}
+	for _, f := range []struct {
+		key      string
+		set, def config.Duration
+	}{
+		{"restart_delay", set.RestartDelay, defaults.RestartDelay},
+		{"max_restart_delay", set.MaxRestartDelay, defaults.MaxRestartDelay},
+		{"restart_window", set.RestartWindow, defaults.RestartWindow},
+		{"healthy_reset_interval", set.HealthyResetInterval, defaults.HealthyResetInterval},
+		{"health_check_interval", set.HealthCheckInterval, defaults.HealthCheckInterval},
+	} {
+		d := f.set.Or(f.def.Duration)
+		timings = append(timings, Timing{
+			Key:     "patrols.dolt_server." + f.key,
+			Value:   config.Dur(d),
+			Default: d == f.def.Duration,
+		})
+	}
+	return timings
+}
+
+// TownTimings returns the duration settings in town settings (from
+// config.LoadOrCreateTownSettings) that the daemon and gt dolt act on.
+func TownTimings(settings *config.TownSettings) []Timing {
+	var set config.Duration
+	if settings != nil && settings.DoltServer != nil {
+		set = settings.DoltServer.StartupTimeout
+	}
+	d := set.Or(doltserver.DefaultStartupTimeout)
+	return []Timing{{
+		Key:     "dolt_server.startup_timeout",
+		Value:   config.Dur(d),
+		Default: d == doltserver.DefaultStartupTimeout,
+	}}
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestEffectiveTimings(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{
+  "guards": {"pending_spawn_ttl": "10m"},
+  "patrols": {
+    "sla_check": {"enabled": true, "interval": "1h30m"},
+    "jsonl_export": {"enabled": true, "interval": 600000000000},
+    "dolt_server": {"enabled": true, "restart_window": "2d"}
+  }
+}`
+	if err := os.WriteFile(PatrolConfigFile(townRoot), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ReadPatrolConfig(townRoot)
+	if err != nil {
+		t.Fatalf("ReadPatrolConfig: %v", err)
+	}
+
+	got := map[string]Timing{}
+	for _, tm := range EffectiveTimings(townRoot, cfg) {
+		got[tm.Key] = tm
+	}
+	for key, want := range map[string]time.Duration{
+		"guards.pending_spawn_ttl":           10 * time.Minute,
+		"patrols.sla_check.interval":         90 * time.Minute,
+		"patrols.jsonl_export.interval":      10 * time.Minute,
+		"patrols.dolt_server.restart_window": 48 * time.Hour,
+		"patrols.dolt_gc.interval":           defaultDoltGCInterval,
+		"patrols.dolt_server.restart_delay":  DefaultDoltServerConfig(townRoot).RestartDelay.Duration,
+	} {
+		tm, ok := got[key]
+		if !ok {
+			t.Errorf("missing %s", key)
+			continue
+		}
+		if tm.Value.Duration != want {
+			t.Errorf("%s = %v, want %v", key, tm.Value, want)
+		}
+	}
+	if got["guards.pending_spawn_ttl"].Default || !got["patrols.dolt_gc.interval"].Default {
+		t.Errorf("default markers wrong: %+v", got)
+	}
+}
+
+func TestReadPatrolConfigInvalidDuration(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := ReadPatrolConfig(townRoot); cfg != nil || err != nil {
+		t.Errorf("missing file = %v, %v; want nil, nil", cfg, err)
+	}
+	data := `{"patrols": {"sla_check": {"enabled": true, "interval": "5 minutes"}}}`
+	if err := os.WriteFile(PatrolConfigFile(townRoot), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadPatrolConfig(townRoot); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+	if LoadPatrolConfig(townRoot) != nil {
+		t.Error("LoadPatrolConfig should return nil for an invalid file")
+	}
+}
+
+func TestTownTimings(t *testing.T) {
+	tm := TownTimings(nil)
+	if len(tm) != 1 || tm[0].Key != "dolt_server.startup_timeout" || tm[0].Value.Duration != doltserver.DefaultStartupTimeout || !tm[0].Default {
+		t.Errorf("TownTimings(nil) = %+v, want default startup timeout", tm)
+	}
+
+	settings := config.NewTownSettings()
+	settings.DoltServer = &config.DoltServerConfig{StartupTimeout: config.Dur(2 * time.Minute)}
+	tm = TownTimings(settings)
+	if tm[0].Value.Duration != 2*time.Minute || tm[0].Default {
+		t.Errorf("TownTimings = %+v, want 2m, not default", tm)
+	}
+}
@@ -10,10 +10,12 @@ package daemon
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -30,6 +32,10 @@ type Config struct {
 
 	// PidFile is the path to the PID file.
 	PidFile string `json:"pid_file"`
+
+	// Standby makes a daemon that finds another one leading the town wait
+	// for the leader lease instead of exiting.
+	Standby bool `json:"standby,omitempty"`
 }
 
 // DefaultConfig returns the default daemon configuration.
@@ -113,11 +119,48 @@ type PatrolConfig struct {
 
 // PatrolsConfig holds configuration for all patrols.
 type PatrolsConfig struct {
-	Refinery    *PatrolConfig      `json:"refinery,omitempty"`
-	Witness     *PatrolConfig      `json:"witness,omitempty"`
-	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
-	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
-	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	Refinery     *PatrolConfig       `json:"refinery,omitempty"`
+	Witness      *PatrolConfig       `json:"witness,omitempty"`
+	Deacon       *PatrolConfig       `json:"deacon,omitempty"`
+	DoltServer   *DoltServerConfig   `json:"dolt_server,omitempty"`
+	DoltRemotes  *DoltRemotesConfig  `json:"dolt_remotes,omitempty"`
+	JSONLExport  *JSONLExportConfig  `json:"jsonl_export,omitempty"`
+	ChangeFeed   *ChangeFeedConfig   `json:"change_feed,omitempty"`
+	CostEnforce  *CostEnforceConfig  `json:"cost_enforce,omitempty"`
+	BackupVerify *BackupVerifyConfig `json:"backup_verify,omitempty"`
+
+	AnalyticsExport *AnalyticsExportConfig `json:"analytics_export,omitempty"`
+
+	SessionPrune *SessionPruneConfig `json:"session_prune,omitempty"`
+
+	BeadArchive *BeadArchiveConfig `json:"bead_archive,omitempty"`
+
+	DoltWatchdog *DoltWatchdogConfig `json:"dolt_watchdog,omitempty"`
+
+	BranchPrune *BranchPruneConfig `json:"branch_prune,omitempty"`
+
+	SLACheck *SLACheckConfig `json:"sla_check,omitempty"`
+
+	DoltGC *DoltGCConfig `json:"dolt_gc,omitempty"`
+
+	DoltMetrics *DoltMetricsConfig `json:"dolt_metrics,omitempty"`
+}
+
+// DoltWatchdogConfig holds configuration for the dolt_watchdog patrol.
+// Each heartbeat, this patrol restarts the server started by gt dolt start
+// if it has died or stopped accepting connections, with exponential
+// backoff between restarts. Enabled by default; it stands down when the
+// dolt_server patrol manages the server.
+type DoltWatchdogConfig struct {
+	// Enabled controls whether the watchdog runs.
+	Enabled bool `json:"enabled"`
+
+	// MaxRestartDelay caps the backoff between restarts (default 30m).
+	MaxRestartDelay config.Duration `json:"max_restart_delay,omitempty"`
+
+	// NudgeAfter is how many restarts without the server staying up
+	// before the mayor is nudged (default 3).
+	NudgeAfter int `json:"nudge_after,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -127,7 +170,7 @@ type DoltRemotesConfig struct {
 	Enabled bool `json:"enabled"`
 
 	// Interval is how often to push (default 15m).
-	Interval time.Duration `json:"interval,omitempty"`
+	Interval config.Duration `json:"interval,omitempty"`
 
 	// Databases lists specific database names to push.
 	// If empty, auto-discovers databases with configured remotes.
@@ -140,12 +183,212 @@ type DoltRemotesConfig struct {
 	Branch string `json:"branch,omitempty"`
 }
 
+// JSONLExportConfig holds configuration for the jsonl_export patrol.
+// This patrol periodically regenerates each rig's issues.jsonl and commits
+// it, for rigs where sync.mode=dolt-native disables per-write export.
+type JSONLExportConfig struct {
+	// Enabled controls whether scheduled export runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to export (default 30m).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// Databases lists specific rig databases to export.
+	// If empty, exports every database with sync.mode=dolt-native.
+	Databases []string `json:"databases,omitempty"`
+
+	// NoCommit writes issues.jsonl without committing it to git.
+	NoCommit bool `json:"no_commit,omitempty"`
+}
+
+// ChangeFeedConfig holds configuration for the change_feed patrol.
+// This patrol diffs each rig's issues table between Dolt commits and
+// publishes bead created/status-changed/closed events to the events log.
+type ChangeFeedConfig struct {
+	// Enabled controls whether the change feed runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to poll for new commits (default 1m).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// Databases lists specific rig databases to watch.
+	// If empty, watches every database on the server.
+	Databases []string `json:"databases,omitempty"`
+}
+
+// CostEnforceConfig holds configuration for the cost_enforce patrol.
+// This patrol runs gt costs enforce, which downgrades sessions whose cost
+// crossed a threshold in the town's cost_policy settings.
+type CostEnforceConfig struct {
+	// Enabled controls whether cost enforcement runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to check session costs (default 5m).
+	Interval config.Duration `json:"interval,omitempty"`
+}
+
+// BackupVerifyConfig holds configuration for the backup_verify patrol.
+// This patrol runs gt dolt verify-backup, which restores a random rig's
+// backup into a scratch directory and checks that it is usable.
+type BackupVerifyConfig struct {
+	// Enabled controls whether restore verification runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to verify a backup (default 24h).
+	Interval config.Duration `json:"interval,omitempty"`
+}
+
+// BranchPruneConfig holds configuration for the branch_prune patrol.
+// This patrol runs gt dolt prune-branches, which deletes merged and
+// orphaned Dolt branches of dead polecats older than the town's
+// dolt_stats.retention.branch_days.
+type BranchPruneConfig struct {
+	// Enabled controls whether branch pruning runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to prune (default 24h).
+	Interval config.Duration `json:"interval,omitempty"`
+}
+
+// SLACheckConfig holds configuration for the sla_check patrol.
+// This patrol runs gt bead sla check --escalate, which escalates SLA
+// timers on open beads once they pass their deadline.
+type SLACheckConfig struct {
+	// Enabled controls whether SLA checks run.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to check (default 15m).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// Severity of breach escalations (default medium).
+	Severity string `json:"severity,omitempty"`
+}
+
+// DoltGCConfig holds configuration for the dolt_gc patrol.
+// This patrol runs gt dolt gc, garbage-collecting every rig database,
+// when .dolt-data grows past the threshold.
+type DoltGCConfig struct {
+	// Enabled controls whether the patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to check disk usage (default 1h).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// ThresholdGB is the .dolt-data size that triggers a collection
+	// (default 10).
+	ThresholdGB float64 `json:"threshold_gb,omitempty"`
+}
+
+// DoltMetricsConfig holds configuration for the dolt_metrics patrol.
+// This patrol samples Dolt server health (latency, connections, disk) into
+// daemon/dolt-metrics.jsonl for gt dolt metrics to report trends from.
+type DoltMetricsConfig struct {
+	// Enabled controls whether the patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to take a sample (default 5m).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// RetentionDays is how long samples are kept (default 14).
+	RetentionDays int `json:"retention_days,omitempty"`
+}
+
+// AnalyticsExportConfig holds configuration for the analytics_export patrol.
+// This patrol runs incremental gt dolt export-parquet exports of bead
+// history for loading into a data warehouse.
+type AnalyticsExportConfig struct {
+	// Enabled controls whether scheduled export runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to export (default 1h).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// Databases lists specific rig databases to export.
+	// If empty, exports every database.
+	Databases []string `json:"databases,omitempty"`
+
+	// Tables lists the tables to export (issues, wisps, audit; default all).
+	Tables []string `json:"tables,omitempty"`
+
+	// Format is parquet (default) or csv.
+	Format string `json:"format,omitempty"`
+
+	// OutDir is the export directory (default <town>/exports/analytics).
+	OutDir string `json:"out_dir,omitempty"`
+}
+
+// SessionPruneConfig holds configuration for the session_prune patrol.
+// This patrol runs gt prune sessions, killing rig tmux sessions that no
+// known agent owns. Orphaned crew sessions are never killed by the patrol.
+type SessionPruneConfig struct {
+	// Enabled controls whether scheduled pruning runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to prune (default 30m).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// MinAge keeps orphans younger than this (default 5m).
+	MinAge config.Duration `json:"min_age,omitempty"`
+}
+
+// BeadArchiveConfig holds configuration for the bead_archive patrol.
+// This patrol runs gt dolt archive, moving old closed wisps and digests
+// out of each rig's issues table into issues_archive.
+type BeadArchiveConfig struct {
+	// Enabled controls whether scheduled archiving runs.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to archive (default 24h).
+	Interval config.Duration `json:"interval,omitempty"`
+
+	// Databases lists specific rig databases to archive.
+	// If empty, archives every database.
+	Databases []string `json:"databases,omitempty"`
+}
+
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
 	Type      string         `json:"type"`
 	Version   int            `json:"version"`
 	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
 	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+
+	// ControlAPI enables the daemon's local control API (see control.go).
+	ControlAPI *ControlAPIConfig `json:"control_api,omitempty"`
+
+	// Metrics enables the Prometheus metrics endpoint (see metrics.go).
+	Metrics *MetricsConfig `json:"metrics,omitempty"`
+
+	// Guards tunes the daemon's built-in safety timeouts.
+	Guards *GuardsConfig `json:"guards,omitempty"`
+}
+
+// GuardsConfig tunes timeouts the daemon applies outside any patrol.
+type GuardsConfig struct {
+	// PendingSpawnTTL is how long a polecat spawn may stay pending before
+	// the daemon assumes its session died and drops it (default 5m).
+	PendingSpawnTTL config.Duration `json:"pending_spawn_ttl,omitempty"`
+}
+
+// ControlAPIConfig configures the daemon's local control API, an HTTP/JSON
+// API served on a unix socket that gt uses as a thin client when the daemon
+// is running.
+type ControlAPIConfig struct {
+	// Enabled controls whether the daemon serves the control API.
+	Enabled bool `json:"enabled"`
+
+	// Socket is the unix socket path (default daemon/control.sock).
+	Socket string `json:"socket,omitempty"`
+}
+
+// MetricsConfig configures the daemon's Prometheus metrics endpoint, which
+// serves Dolt server health on GET /metrics.
+type MetricsConfig struct {
+	// Enabled controls whether the daemon serves metrics.
+	Enabled bool `json:"enabled"`
+
+	// Listen is the TCP address to serve on (default 127.0.0.1:9464).
+	Listen string `json:"listen,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -153,25 +396,46 @@ func PatrolConfigFile(townRoot string) string {
 	return filepath.Join(townRoot, "mayor", "daemon.json")
 }
 
-// LoadPatrolConfig loads patrol configuration from mayor/daemon.json.
-// Returns nil if the file doesn't exist or can't be parsed.
+// LoadPatrolConfig loads patrol configuration from mayor/daemon.json, with
+// the active config profile's daemon overrides applied. If the profile
+// can't be applied (e.g. GT_PROFILE names an unknown profile), the file is
+// used as written. Returns nil if the file doesn't exist or can't be parsed.
 func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
+	config, err := ReadPatrolConfig(townRoot)
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// ReadPatrolConfig is LoadPatrolConfig, but reports why mayor/daemon.json
+// couldn't be parsed (e.g. an invalid duration). Returns nil, nil if the
+// file doesn't exist.
+func ReadPatrolConfig(townRoot string) (*DaemonPatrolConfig, error) {
 	configFile := PatrolConfigFile(townRoot)
 	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	if profiled, err := config.ApplyProfile(townRoot, config.ProfileSectionDaemon, data); err == nil {
+		data = profiled
 	}
 
 	var config DaemonPatrolConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil
+		return nil, fmt.Errorf("parsing %s: %w", configFile, err)
 	}
-	return &config
+	return &config, nil
 }
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, jsonl_export, change_feed, cost_enforce,
+// backup_verify, analytics_export, session_prune, bead_archive, branch_prune,
+// sla_check, dolt_gc) default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -182,6 +446,72 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "jsonl_export" {
+		if config == nil || config.Patrols == nil || config.Patrols.JSONLExport == nil {
+			return false
+		}
+		return config.Patrols.JSONLExport.Enabled
+	}
+	if patrol == "change_feed" {
+		if config == nil || config.Patrols == nil || config.Patrols.ChangeFeed == nil {
+			return false
+		}
+		return config.Patrols.ChangeFeed.Enabled
+	}
+	if patrol == "cost_enforce" {
+		if config == nil || config.Patrols == nil || config.Patrols.CostEnforce == nil {
+			return false
+		}
+		return config.Patrols.CostEnforce.Enabled
+	}
+	if patrol == "backup_verify" {
+		if config == nil || config.Patrols == nil || config.Patrols.BackupVerify == nil {
+			return false
+		}
+		return config.Patrols.BackupVerify.Enabled
+	}
+	if patrol == "analytics_export" {
+		if config == nil || config.Patrols == nil || config.Patrols.AnalyticsExport == nil {
+			return false
+		}
+		return config.Patrols.AnalyticsExport.Enabled
+	}
+	if patrol == "session_prune" {
+		if config == nil || config.Patrols == nil || config.Patrols.SessionPrune == nil {
+			return false
+		}
+		return config.Patrols.SessionPrune.Enabled
+	}
+	if patrol == "bead_archive" {
+		if config == nil || config.Patrols == nil || config.Patrols.BeadArchive == nil {
+			return false
+		}
+		return config.Patrols.BeadArchive.Enabled
+	}
+	if patrol == "branch_prune" {
+		if config == nil || config.Patrols == nil || config.Patrols.BranchPrune == nil {
+			return false
+		}
+		return config.Patrols.BranchPrune.Enabled
+	}
+	if patrol == "sla_check" {
+		if config == nil || config.Patrols == nil || config.Patrols.SLACheck == nil {
+			return false
+		}
+		return config.Patrols.SLACheck.Enabled
+	}
+	if patrol == "dolt_gc" {
+		if config == nil || config.Patrols == nil || config.Patrols.DoltGC == nil {
+			return false
+		}
+		return config.Patrols.DoltGC.Enabled
+	}
+	if patrol == "dolt_metrics" {
+		if config == nil || config.Patrols == nil || config.Patrols.DoltMetrics == nil {
+			return false
+		}
+		return config.Patrols.DoltMetrics.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -200,6 +530,10 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "dolt_watchdog":
+		if config.Patrols.DoltWatchdog != nil {
+			return config.Patrols.DoltWatchdog.Enabled
+		}
 	}
 	return true // Default: enabled
 }
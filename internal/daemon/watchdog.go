@@ -0,0 +1,172 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// The daemon supervises everything else; the watchdog (gt daemon ensure,
+// run from cron or a systemd timer) supervises the daemon. It checks that a
+// daemon is running and still renewing its lease, restarts it if not, and
+// keeps a history of those restarts so flapping is visible.
+
+// watchdogHistoryWindow is how long restart records are kept.
+const watchdogHistoryWindow = 24 * time.Hour
+
+// The daemon is flapping when the watchdog has restarted it more than
+// FlapThreshold times within FlapWindow.
+const (
+	FlapThreshold = 3
+	FlapWindow    = time.Hour
+)
+
+// Watchdog verdicts.
+const (
+	WatchdogHealthy     = "healthy"      // running and renewing its lease
+	WatchdogNotRunning  = "not_running"  // no daemon process
+	WatchdogStale       = "stale"        // running but the lease has lapsed (hung)
+	WatchdogRemoteOwner = "remote_owner" // another host's daemon leads the town
+)
+
+// WatchdogStatus is the watchdog's view of the daemon.
+type WatchdogStatus struct {
+	Verdict string
+	PID     int           // local daemon PID, if running
+	Lease   *Lease        // current lease, if any
+	Age     time.Duration // lease age (or heartbeat age without a lease)
+}
+
+// CheckWatchdog decides whether the town's daemon is healthy. A daemon is
+// healthy when its process is alive and its lease was renewed within the
+// lease TTL. A daemon that predates leases is judged by its state
+// heartbeat instead, against maxHeartbeatAge.
+func CheckWatchdog(townRoot string, maxHeartbeatAge time.Duration) (*WatchdogStatus, error) {
+	now := clk.Now()
+	lease, err := LoadLease(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	st := &WatchdogStatus{Lease: lease}
+	if lease != nil {
+		st.Age = lease.Age(now)
+	}
+
+	running, pid, _ := IsRunning(townRoot)
+	if !running {
+		// A daemon on another host may lead a town on a shared filesystem;
+		// starting one here would only make it wait for the lease.
+		if lease != nil && lease.Host != leaseHost() && lease.live(now, leaseHost()) {
+			st.Verdict = WatchdogRemoteOwner
+			return st, nil
+		}
+		st.Verdict = WatchdogNotRunning
+		return st, nil
+	}
+	st.PID = pid
+
+	if lease != nil && lease.PID == pid && lease.Host == leaseHost() {
+		if lease.Expired(now) {
+			st.Verdict = WatchdogStale
+		} else {
+			st.Verdict = WatchdogHealthy
+		}
+		return st, nil
+	}
+
+	// No lease of our own (e.g. a standby daemon, or an older daemon
+	// binary): fall back to the heartbeat in state.json.
+	state, err := LoadState(townRoot)
+	if err == nil && !state.LastHeartbeat.IsZero() {
+		st.Age = now.Sub(state.LastHeartbeat)
+		if st.Age > maxHeartbeatAge {
+			st.Verdict = WatchdogStale
+			return st, nil
+		}
+	}
+	st.Verdict = WatchdogHealthy
+	return st, nil
+}
+
+// WatchdogRestart records one restart performed by the watchdog.
+type WatchdogRestart struct {
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason"`
+	OldPID int       `json:"old_pid,omitempty"`
+	NewPID int       `json:"new_pid,omitempty"`
+}
+
+// WatchdogHistory is the watchdog's restart log.
+type WatchdogHistory struct {
+	Restarts []WatchdogRestart `json:"restarts"`
+}
+
+// WatchdogFile returns the path to the watchdog restart history.
+func WatchdogFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "watchdog.json")
+}
+
+// LoadWatchdogHistory reads the watchdog restart history. A missing file is
+// an empty history.
+func LoadWatchdogHistory(townRoot string) (*WatchdogHistory, error) {
+	h := &WatchdogHistory{}
+	data, err := os.ReadFile(WatchdogFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(WatchdogFile(townRoot)), err)
+	}
+	return h, nil
+}
+
+// RecordWatchdogRestart appends a restart to the history, dropping records
+// older than a day.
+func RecordWatchdogRestart(townRoot string, r WatchdogRestart) error {
+	h, err := LoadWatchdogHistory(townRoot)
+	if err != nil {
+		// A corrupt history shouldn't block recording new restarts.
+		h = &WatchdogHistory{}
+	}
+	if r.At.IsZero() {
+		r.At = clk.Now()
+	}
+	cutoff := r.At.Add(-watchdogHistoryWindow)
+	kept := h.Restarts[:0]
+	for _, old := range h.Restarts {
+		if old.At.After(cutoff) {
+			kept = append(kept, old)
+		}
+	}
+	h.Restarts = append(kept, r)
+
+	path := WatchdogFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, h)
+}
+
+// RestartsSince returns the restarts at or after since.
+func (h *WatchdogHistory) RestartsSince(since time.Time) []WatchdogRestart {
+	var out []WatchdogRestart
+	for _, r := range h.Restarts {
+		if !r.At.Before(since) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Flapping reports whether the daemon has been restarted more than
+// FlapThreshold times within FlapWindow before now.
+func (h *WatchdogHistory) Flapping(now time.Time) bool {
+	return len(h.RestartsSince(now.Add(-FlapWindow))) > FlapThreshold
+}
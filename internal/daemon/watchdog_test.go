@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestWatchdogHistoryFlapping(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// A restart from yesterday is pruned when the next one is recorded.
+	if err := RecordWatchdogRestart(townRoot, WatchdogRestart{At: now.Add(-25 * time.Hour), Reason: "not running"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < FlapThreshold; i++ {
+		at := now.Add(time.Duration(i-FlapThreshold) * 10 * time.Minute)
+		if err := RecordWatchdogRestart(townRoot, WatchdogRestart{At: at, Reason: "not running", NewPID: 100 + i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err := LoadWatchdogHistory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Restarts) != FlapThreshold {
+		t.Fatalf("history has %d restarts, want %d (old one pruned)", len(h.Restarts), FlapThreshold)
+	}
+	if h.Flapping(now) {
+		t.Errorf("Flapping() = true at %d restarts, want false", FlapThreshold)
+	}
+
+	if err := RecordWatchdogRestart(townRoot, WatchdogRestart{At: now, Reason: "hung"}); err != nil {
+		t.Fatal(err)
+	}
+	h, _ = LoadWatchdogHistory(townRoot)
+	if !h.Flapping(now) {
+		t.Errorf("Flapping() = false at %d restarts in an hour, want true", FlapThreshold+1)
+	}
+	if h.Flapping(now.Add(FlapWindow)) {
+		t.Error("Flapping() = true an hour later, want false")
+	}
+}
+
+func TestCheckWatchdogNotRunning(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	defer SetClock(clock.NewFake(now))()
+	defer SetProcessTable(proc.NewFakeTable())()
+
+	townRoot := t.TempDir()
+	st, err := CheckWatchdog(townRoot, 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Verdict != WatchdogNotRunning {
+		t.Errorf("Verdict = %q with no daemon, want %q", st.Verdict, WatchdogNotRunning)
+	}
+
+	// A live lease held by another host means the town is supervised elsewhere.
+	if _, err := acquireLease(townRoot, 4242, "some-other-host", now); err != nil {
+		t.Fatal(err)
+	}
+	st, err = CheckWatchdog(townRoot, 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Verdict != WatchdogRemoteOwner {
+		t.Errorf("Verdict = %q with a remote lease, want %q", st.Verdict, WatchdogRemoteOwner)
+	}
+
+	// Once that lease lapses, the daemon is simply not running.
+	defer SetClock(clock.NewFake(now.Add(2 * leaseTTL)))()
+	st, _ = CheckWatchdog(townRoot, 10*time.Minute)
+	if st.Verdict != WatchdogNotRunning {
+		t.Errorf("Verdict = %q with an expired remote lease, want %q", st.Verdict, WatchdogNotRunning)
+	}
+}
@@ -0,0 +1,354 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The work queue holds actions the daemon deferred: role restarts waiting
+// out their backoff, and jobs or gt commands scheduled for later through
+// the control API (gt daemon queue add), which are retried on failure. It
+// persists to daemon/work_queue.json so deferred work survives a daemon
+// restart, and gt daemon queue shows what the daemon is waiting to do.
+
+// Work item kinds.
+const (
+	WorkKindRestart = "restart" // restart a role session (Target is its agent ID)
+	WorkKindJob     = "job"     // run a daemon job (Target is its name, see controlJobs)
+	WorkKindCommand = "command" // run gt with Args
+)
+
+const (
+	// workQueueInterval is how often the main loop runs due work items.
+	workQueueInterval = 15 * time.Second
+
+	// defaultWorkAttempts is how often a failing command item is tried
+	// before it is dropped.
+	defaultWorkAttempts = 5
+)
+
+// ErrWorkItemNotFound means no queued item has the given ID.
+var ErrWorkItemNotFound = errors.New("work item not found")
+
+// WorkItem is one deferred action.
+type WorkItem struct {
+	ID     string   `json:"id"`
+	Kind   string   `json:"kind"`
+	Target string   `json:"target"`
+	Args   []string `json:"args,omitempty"` // gt arguments, for command items
+	Reason string   `json:"reason,omitempty"`
+
+	// Priority orders the items that are due; higher runs first.
+	Priority  int       `json:"priority"`
+	NotBefore time.Time `json:"not_before"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Attempts counts failed runs; the item is dropped once it reaches
+	// MaxAttempts (0 means run once).
+	Attempts    int    `json:"attempts,omitempty"`
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// workQueueState is the persisted form of the queue.
+type workQueueState struct {
+	NextID int        `json:"next_id"`
+	Items  []WorkItem `json:"items"`
+}
+
+// WorkQueue is the daemon's persistent queue of deferred actions. It is
+// safe for concurrent use: the main loop runs items while control API
+// handlers list, reprioritize, and cancel them.
+type WorkQueue struct {
+	mu    sync.Mutex
+	path  string
+	state workQueueState
+}
+
+// WorkQueueFile returns the path to the work queue file.
+func WorkQueueFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "work_queue.json")
+}
+
+// NewWorkQueue returns an empty queue persisted under townRoot.
+func NewWorkQueue(townRoot string) *WorkQueue {
+	return &WorkQueue{path: WorkQueueFile(townRoot)}
+}
+
+// Load reads the queue from disk. A missing file is an empty queue.
+func (q *WorkQueue) Load() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &q.state)
+}
+
+// save persists the queue. Callers hold q.mu.
+func (q *WorkQueue) save() error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(q.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(q.path, data, 0600)
+}
+
+// Add queues item and returns it as queued. If an item of the same kind
+// and target is already queued, that item takes item's schedule and reason
+// instead, so repeated deferrals don't pile up.
+func (q *WorkQueue) Add(item WorkItem) (WorkItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if i := q.indexOfTarget(item.Kind, item.Target); i >= 0 {
+		existing := &q.state.Items[i]
+		existing.NotBefore = item.NotBefore
+		if item.Reason != "" {
+			existing.Reason = item.Reason
+		}
+		return *existing, q.save()
+	}
+
+	q.state.NextID++
+	item.ID = fmt.Sprintf("wq-%d", q.state.NextID)
+	item.CreatedAt = clk.Now()
+	if item.NotBefore.IsZero() {
+		item.NotBefore = item.CreatedAt
+	}
+	q.state.Items = append(q.state.Items, item)
+	return item, q.save()
+}
+
+// Items returns the queued items in the order they would run.
+func (q *WorkQueue) Items() []WorkItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := append([]WorkItem(nil), q.state.Items...)
+	sortWorkItems(items)
+	return items
+}
+
+// Due returns the items whose time has come, in run order.
+func (q *WorkQueue) Due(now time.Time) []WorkItem {
+	var due []WorkItem
+	for _, item := range q.Items() {
+		if !item.NotBefore.After(now) {
+			due = append(due, item)
+		}
+	}
+	return due
+}
+
+// Remove removes the item with the given ID and returns it.
+func (q *WorkQueue) Remove(id string) (WorkItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, item := range q.state.Items {
+		if item.ID == id {
+			q.state.Items = append(q.state.Items[:i], q.state.Items[i+1:]...)
+			return item, q.save()
+		}
+	}
+	return WorkItem{}, fmt.Errorf("%w: %s", ErrWorkItemNotFound, id)
+}
+
+// RemoveTarget removes the item of the given kind and target, if queued.
+func (q *WorkQueue) RemoveTarget(kind, target string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if i := q.indexOfTarget(kind, target); i >= 0 {
+		q.state.Items = append(q.state.Items[:i], q.state.Items[i+1:]...)
+		_ = q.save()
+	}
+}
+
+// SetPriority changes the priority of the item with the given ID.
+func (q *WorkQueue) SetPriority(id string, priority int) (WorkItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := range q.state.Items {
+		if q.state.Items[i].ID == id {
+			q.state.Items[i].Priority = priority
+			return q.state.Items[i], q.save()
+		}
+	}
+	return WorkItem{}, fmt.Errorf("%w: %s", ErrWorkItemNotFound, id)
+}
+
+// Requeue puts back an item that failed, to be retried after a backoff
+// that doubles with each attempt. It reports false, leaving the item out,
+// once the item has used its attempts.
+func (q *WorkQueue) Requeue(item WorkItem, runErr error) (WorkItem, bool) {
+	item.Attempts++
+	item.LastError = runErr.Error()
+	if item.Attempts >= max(item.MaxAttempts, 1) {
+		return item, false
+	}
+	backoff := initialBackoff
+	for i := 1; i < item.Attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	item.NotBefore = clk.Now().Add(min(backoff, maxBackoff))
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.indexOfTarget(item.Kind, item.Target) < 0 {
+		q.state.Items = append(q.state.Items, item)
+		_ = q.save()
+	}
+	return item, true
+}
+
+// indexOfTarget returns the index of the item of the given kind and
+// target, or -1. Callers hold q.mu.
+func (q *WorkQueue) indexOfTarget(kind, target string) int {
+	for i, item := range q.state.Items {
+		if item.Kind == kind && item.Target == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortWorkItems sorts items into run order: higher priority first, then
+// earliest due, then oldest.
+func sortWorkItems(items []WorkItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.NotBefore.Equal(b.NotBefore) {
+			return a.NotBefore.Before(b.NotBefore)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+// queueRoleRestart records that a role's restart is waiting out its
+// backoff, and runs it as soon as the backoff ends rather than on the next
+// heartbeat.
+func (d *Daemon) queueRoleRestart(agentID string, wait time.Duration) {
+	if d.workQueue == nil {
+		return
+	}
+	_, err := d.workQueue.Add(WorkItem{
+		Kind:      WorkKindRestart,
+		Target:    agentID,
+		Reason:    "restart backoff",
+		NotBefore: d.clock().Now().Add(wait),
+	})
+	if err != nil {
+		d.logger.Printf("Warning: saving work queue: %v", err)
+	}
+}
+
+// dropRoleRestart removes a queued restart of a role that is running again.
+func (d *Daemon) dropRoleRestart(agentID string) {
+	if d.workQueue != nil {
+		d.workQueue.RemoveTarget(WorkKindRestart, agentID)
+	}
+}
+
+// runDueWork runs the work items that are due, in priority order. Each is
+// taken off the queue before it runs, so a restart that is deferred again
+// queues afresh; a failed command is requeued with backoff.
+func (d *Daemon) runDueWork(state *State) {
+	if d.workQueue == nil {
+		return
+	}
+	for _, item := range d.workQueue.Due(d.clock().Now()) {
+		if d.isShutdownInProgress() {
+			return
+		}
+		if _, err := d.workQueue.Remove(item.ID); err != nil {
+			continue // canceled since Due
+		}
+		d.logger.Printf("work queue: running %s %s (%s)", item.Kind, item.Target, item.ID)
+		err := d.runWorkItem(item, state)
+		if err == nil {
+			continue
+		}
+		if retry, ok := d.workQueue.Requeue(item, err); ok {
+			d.logger.Printf("work queue: %s failed (attempt %d), retrying at %s: %v",
+				item.ID, retry.Attempts, retry.NotBefore.Format(time.RFC3339), err)
+		} else {
+			d.logger.Printf("work queue: %s failed, giving up after %d attempt(s): %v", item.ID, retry.Attempts, err)
+		}
+	}
+}
+
+// runWorkItem runs one work item.
+func (d *Daemon) runWorkItem(item WorkItem, state *State) error {
+	switch item.Kind {
+	case WorkKindRestart:
+		return d.restartRole(item.Target)
+	case WorkKindJob:
+		job, ok := controlJobs[item.Target]
+		if !ok {
+			return fmt.Errorf("unknown job %q", item.Target)
+		}
+		if job.patrol != "" && !IsPatrolEnabled(d.patrolConfig, job.patrol) {
+			return fmt.Errorf("patrol %s is not enabled", job.patrol)
+		}
+		job.run(d, state)
+		return nil
+	case WorkKindCommand:
+		cmd := exec.Command(d.gtPath, item.Args...) //nolint:gosec // G204: args come from the town owner via the control socket
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("%w: %s", err, msg)
+			}
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown work item kind %q", item.Kind)
+	}
+}
+
+// restartRole runs the ensure check for a role's agent ID, which restarts
+// the session if it is still down and out of backoff.
+func (d *Daemon) restartRole(agentID string) error {
+	switch agentID {
+	case "deacon":
+		d.ensureDeaconRunning()
+		return nil
+	case "mayor":
+		d.ensureMayorRunning()
+		return nil
+	}
+	rig, role, ok := strings.Cut(agentID, "/")
+	switch {
+	case ok && role == "witness":
+		d.ensureWitnessRunning(rig)
+	case ok && role == "refinery":
+		d.ensureRefineryRunning(rig)
+	default:
+		return fmt.Errorf("no restart for agent %q", agentID)
+	}
+	return nil
+}
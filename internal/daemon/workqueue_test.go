@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestWorkQueue_OrderAndDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	defer SetClock(clock.NewFake(now))()
+
+	q := NewWorkQueue(t.TempDir())
+	later, _ := q.Add(WorkItem{Kind: WorkKindJob, Target: "jsonl_export", NotBefore: now.Add(time.Hour)})
+	low, _ := q.Add(WorkItem{Kind: WorkKindRestart, Target: "deacon"})
+	high, _ := q.Add(WorkItem{Kind: WorkKindRestart, Target: "mayor", Priority: 10})
+
+	var ids []string
+	for _, item := range q.Due(now) {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) != 2 || ids[0] != high.ID || ids[1] != low.ID {
+		t.Errorf("Due = %v, want [%s %s]", ids, high.ID, low.ID)
+	}
+	if due := q.Due(now.Add(time.Hour)); len(due) != 3 {
+		t.Errorf("Due in an hour has %d items, want 3", len(due))
+	}
+
+	if _, err := q.SetPriority(later.ID, 20); err != nil {
+		t.Fatal(err)
+	}
+	if items := q.Items(); items[0].ID != later.ID {
+		t.Errorf("Items()[0] = %s, want reprioritized %s", items[0].ID, later.ID)
+	}
+	if _, err := q.SetPriority("wq-99", 1); !errors.Is(err, ErrWorkItemNotFound) {
+		t.Errorf("SetPriority(unknown) error = %v, want ErrWorkItemNotFound", err)
+	}
+}
+
+func TestWorkQueue_AddDedupesTarget(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	defer SetClock(clock.NewFake(now))()
+
+	q := NewWorkQueue(t.TempDir())
+	first, _ := q.Add(WorkItem{Kind: WorkKindRestart, Target: "gastown/witness", NotBefore: now.Add(time.Minute)})
+	second, _ := q.Add(WorkItem{Kind: WorkKindRestart, Target: "gastown/witness", NotBefore: now.Add(2 * time.Minute)})
+
+	if second.ID != first.ID {
+		t.Errorf("re-adding the same target got %s, want existing %s", second.ID, first.ID)
+	}
+	if items := q.Items(); len(items) != 1 || !items[0].NotBefore.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("Items = %+v, want one item rescheduled to +2m", items)
+	}
+
+	q.RemoveTarget(WorkKindRestart, "gastown/witness")
+	if items := q.Items(); len(items) != 0 {
+		t.Errorf("Items after RemoveTarget = %+v, want none", items)
+	}
+}
+
+func TestWorkQueue_Requeue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	defer SetClock(clock.NewFake(now))()
+
+	q := NewWorkQueue(t.TempDir())
+	item, _ := q.Add(WorkItem{Kind: WorkKindCommand, Target: "gt mq retry", MaxAttempts: 3})
+	runErr := errors.New("exit status 1")
+
+	for attempt, wantWait := range []time.Duration{initialBackoff, 2 * initialBackoff} {
+		if _, err := q.Remove(item.ID); err != nil {
+			t.Fatal(err)
+		}
+		retry, ok := q.Requeue(item, runErr)
+		if !ok {
+			t.Fatalf("attempt %d: Requeue dropped the item, want a retry", attempt+1)
+		}
+		if got := retry.NotBefore.Sub(now); got != wantWait {
+			t.Errorf("attempt %d: retry in %v, want %v", attempt+1, got, wantWait)
+		}
+		if retry.LastError != runErr.Error() {
+			t.Errorf("LastError = %q, want %q", retry.LastError, runErr)
+		}
+		item = retry
+	}
+
+	if _, err := q.Remove(item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := q.Requeue(item, runErr); ok {
+		t.Error("Requeue after the last attempt kept the item, want it dropped")
+	}
+	if items := q.Items(); len(items) != 0 {
+		t.Errorf("Items = %+v, want none", items)
+	}
+}
+
+func TestWorkQueue_Persists(t *testing.T) {
+	townRoot := t.TempDir()
+	q := NewWorkQueue(townRoot)
+	added, err := q.Add(WorkItem{Kind: WorkKindJob, Target: "heartbeat", Reason: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewWorkQueue(townRoot)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	items := loaded.Items()
+	if len(items) != 1 || items[0].ID != added.ID || items[0].Reason != "test" {
+		t.Fatalf("loaded Items = %+v, want %+v", items, added)
+	}
+
+	// IDs keep counting after a reload, so a canceled ID isn't reused.
+	next, _ := loaded.Add(WorkItem{Kind: WorkKindJob, Target: "lifecycle"})
+	if next.ID != "wq-2" {
+		t.Errorf("next ID after reload = %s, want wq-2", next.ID)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // Default parameters for re-dispatch rate-limiting.
@@ -325,7 +326,7 @@ func getBeadStatusForRedispatch(townRoot, beadID string) string {
 	cmd := exec.Command("bd", "show", beadID, "--json")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		return ""
 	}
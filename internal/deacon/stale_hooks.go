@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -157,7 +158,7 @@ func listHookedBeads(townRoot string) ([]*HookedBead, error) {
 	cmd := exec.Command("bd", "list", "--status=hooked", "--json", "--limit=0")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		// No hooked beads is not an error
 		if strings.Contains(string(output), "no issues found") {
@@ -250,5 +251,5 @@ func assigneeToWorktreePath(townRoot, assignee string) string {
 func unhookBead(townRoot, beadID string) error {
 	cmd := exec.Command("bd", "update", beadID, "--status=open")
 	cmd.Dir = townRoot
-	return cmd.Run()
+	return proc.RunCmd(cmd)
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // Default parameters for stuck-session detection.
@@ -41,31 +42,38 @@ func DefaultStuckConfig() *StuckConfig {
 // Returns defaults if no role bead exists or if fields aren't configured.
 // Per ZFC: agents control their own thresholds via their role beads.
 func LoadStuckConfig(townRoot string) *StuckConfig {
-	config := DefaultStuckConfig()
+	cfg := DefaultStuckConfig()
 
 	// Load from hq-deacon-role bead
 	bd := beads.NewWithBeadsDir(townRoot, beads.ResolveBeadsDir(townRoot))
 	roleConfig, err := bd.GetRoleConfig(beads.RoleBeadIDTown("deacon"))
 	if err != nil || roleConfig == nil {
-		return config
+		return cfg
 	}
 
 	// Override defaults with role bead values
-	if roleConfig.PingTimeout != "" {
-		if d, err := time.ParseDuration(roleConfig.PingTimeout); err == nil {
-			config.PingTimeout = d
-		}
-	}
+	cfg.PingTimeout = roleDuration("ping_timeout", roleConfig.PingTimeout, cfg.PingTimeout)
 	if roleConfig.ConsecutiveFailures > 0 {
-		config.ConsecutiveFailures = roleConfig.ConsecutiveFailures
-	}
-	if roleConfig.KillCooldown != "" {
-		if d, err := time.ParseDuration(roleConfig.KillCooldown); err == nil {
-			config.Cooldown = d
-		}
+		cfg.ConsecutiveFailures = roleConfig.ConsecutiveFailures
 	}
+	cfg.Cooldown = roleDuration("kill_cooldown", roleConfig.KillCooldown, cfg.Cooldown)
 
-	return config
+	return cfg
+}
+
+// roleDuration parses the role bead's key setting, returning fallback if
+// it is unset. An invalid or negative value is reported on stderr and
+// also falls back.
+func roleDuration(key, value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := config.ParseDuration(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: deacon role bead %s: %v; using %s\n", key, err, config.FormatDuration(fallback))
+		return fallback
+	}
+	return d
 }
 
 // AgentHealthState tracks the health check state for a single agent.
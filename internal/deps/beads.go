@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // MinBeadsVersion is the minimum compatible beads version for this Gas Town release.
@@ -38,7 +40,7 @@ func CheckBeads() (BeadsStatus, string) {
 
 	// Get version
 	cmd := exec.Command("bd", "version")
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		return BeadsUnknown, ""
 	}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // AgentBeadsCheck verifies that agent beads exist for all agents.
@@ -276,7 +277,7 @@ func (c *AgentBeadsCheck) Fix(ctx *CheckContext) error {
 func addLabelToBead(townRoot, id, label string) error {
 	cmd := exec.Command("bd", "update", id, "--add-labels="+label)
 	cmd.Dir = townRoot
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := proc.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
 	}
 	return nil
@@ -0,0 +1,130 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// backupVerifyMaxAge is how old the newest restore verification may be
+// before it is reported as stale. The backup_verify patrol runs daily.
+const backupVerifyMaxAge = 7 * 24 * time.Hour
+
+// BackupRestoreCheck reports the results of restore verification
+// (gt dolt verify-backup): whether backups have ever been restored, whether
+// the latest restore of each database passed, and whether the last run is
+// recent.
+type BackupRestoreCheck struct {
+	BaseCheck
+}
+
+// NewBackupRestoreCheck creates a new backup restore verification check.
+func NewBackupRestoreCheck() *BackupRestoreCheck {
+	return &BackupRestoreCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "backup-restore",
+			CheckDescription: "Check that Dolt backups have been restored and verified recently",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run reads the restore verification history.
+func (c *BackupRestoreCheck) Run(ctx *CheckContext) *CheckResult {
+	history, err := doltserver.LoadRestoreVerifications(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Could not read restore verification history: %v", err),
+			Category: c.CheckCategory,
+		}
+	}
+
+	if len(history) == 0 {
+		return c.neverVerified(ctx)
+	}
+
+	// Latest result per database.
+	latest := make(map[string]doltserver.RestoreVerification)
+	for _, v := range history {
+		if prev, ok := latest[v.Database]; !ok || v.Time.After(prev.Time) {
+			latest[v.Database] = v
+		}
+	}
+	var databases []string
+	var newest time.Time
+	for db, v := range latest {
+		databases = append(databases, db)
+		if v.Time.After(newest) {
+			newest = v.Time
+		}
+	}
+	sort.Strings(databases)
+
+	var details []string
+	for _, db := range databases {
+		v := latest[db]
+		if failed := v.Failed(); failed != nil {
+			details = append(details, fmt.Sprintf("%s: %s failed %s: %s", db, failed.Name, v.Time.Local().Format("2006-01-02 15:04"), failed.Detail))
+		}
+	}
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusError,
+			Message:  fmt.Sprintf("Latest backup restore failed for %d database(s)", len(details)),
+			Details:  details,
+			FixHint:  "Check the backup remote, then re-run 'gt dolt verify-backup --rig <rig>'",
+			Category: c.CheckCategory,
+		}
+	}
+
+	if age := time.Since(newest); age > backupVerifyMaxAge {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Last backup restore verification was %s ago", age.Round(time.Hour)),
+			FixHint:  "Run 'gt dolt verify-backup', or enable the backup_verify patrol in mayor/daemon.json",
+			Category: c.CheckCategory,
+		}
+	}
+
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusOK,
+		Message:  fmt.Sprintf("Backups restore cleanly (%d database(s) verified, last %s)", len(databases), newest.Local().Format("2006-01-02 15:04")),
+		Category: c.CheckCategory,
+	}
+}
+
+// neverVerified warns if there are backups that have never been restored.
+func (c *BackupRestoreCheck) neverVerified(ctx *CheckContext) *CheckResult {
+	if _, err := os.Stat(doltserver.DefaultConfig(ctx.TownRoot).DataDir); os.IsNotExist(err) {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No Dolt data directory (dolt not in use)",
+			Category: c.CheckCategory,
+		}
+	}
+	remotes, err := doltserver.BackedUpDatabases(ctx.TownRoot)
+	if err != nil || len(remotes) == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No backup remotes configured",
+			Category: c.CheckCategory,
+		}
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusWarning,
+		Message:  fmt.Sprintf("%d database(s) have backups that have never been restored", len(remotes)),
+		FixHint:  "Run 'gt dolt verify-backup', or enable the backup_verify patrol in mayor/daemon.json",
+		Category: c.CheckCategory,
+	}
+}
@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func TestBackupRestoreCheck_NoDoltData(t *testing.T) {
+	result := NewBackupRestoreCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK when dolt is not in use", result.Status)
+	}
+}
+
+func TestBackupRestoreCheck_History(t *testing.T) {
+	passed := []doltserver.RestoreCheck{{Name: doltserver.RestoreCheckClone, OK: true}}
+	failed := []doltserver.RestoreCheck{{Name: doltserver.RestoreCheckClone, Detail: "remote not found"}}
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name string
+		runs []doltserver.RestoreVerification
+		want CheckStatus
+	}{
+		{"recent pass", []doltserver.RestoreVerification{
+			{Time: now.Add(-time.Hour), Database: "gastown", Passed: true, Checks: passed},
+		}, StatusOK},
+		{"latest failed", []doltserver.RestoreVerification{
+			{Time: now.Add(-2 * time.Hour), Database: "gastown", Passed: true, Checks: passed},
+			{Time: now.Add(-time.Hour), Database: "gastown", Checks: failed},
+		}, StatusError},
+		{"failure superseded by pass", []doltserver.RestoreVerification{
+			{Time: now.Add(-2 * time.Hour), Database: "gastown", Checks: failed},
+			{Time: now.Add(-time.Hour), Database: "gastown", Passed: true, Checks: passed},
+		}, StatusOK},
+		{"stale", []doltserver.RestoreVerification{
+			{Time: now.Add(-backupVerifyMaxAge - time.Hour), Database: "gastown", Passed: true, Checks: passed},
+		}, StatusWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			for i := range tt.runs {
+				if err := doltserver.RecordRestoreVerification(townRoot, &tt.runs[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			result := NewBackupRestoreCheck().Run(&CheckContext{TownRoot: townRoot})
+			if result.Status != tt.want {
+				t.Errorf("Status = %v (%s), want %v", result.Status, result.Message, tt.want)
+			}
+		})
+	}
+}
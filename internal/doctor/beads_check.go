@@ -2,7 +2,6 @@ package doctor
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +9,9 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // BeadsDatabaseCheck verifies that the beads database is properly initialized.
@@ -135,7 +137,7 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 		cmd.Dir = ctx.TownRoot
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
+		if err := proc.RunCmd(cmd); err != nil {
 			return err
 		}
 	}
@@ -158,7 +160,7 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 			cmd.Dir = ctx.RigPath()
 			var stderr bytes.Buffer
 			cmd.Stderr = &stderr
-			if err := cmd.Run(); err != nil {
+			if err := proc.RunCmd(cmd); err != nil {
 				return err
 			}
 		}
@@ -275,8 +277,7 @@ func (c *PrefixMismatchCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Load rigs.json
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigsConfig, err := loadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(ctx.TownRoot)
 	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -356,8 +357,7 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 	}
 
 	// Load rigs.json
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigsConfig, err := loadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(ctx.TownRoot)
 	if err != nil {
 		return nil // Nothing to fix
 	}
@@ -380,7 +380,7 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 
 		// Ensure BeadsConfig exists
 		if rigEntry.BeadsConfig == nil {
-			rigEntry.BeadsConfig = &rigsConfigBeadsConfig{}
+			rigEntry.BeadsConfig = &config.BeadsConfig{}
 		}
 
 		if rigEntry.BeadsConfig.Prefix != routePrefix {
@@ -391,54 +391,12 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 	}
 
 	if modified {
-		return saveRigsConfig(rigsPath, rigsConfig)
+		return rigsconfig.Save(ctx.TownRoot, rigsConfig)
 	}
 
 	return nil
 }
 
-// rigsConfigEntry is a local type for loading rigs.json without importing config package
-// to avoid circular dependencies and keep the check self-contained.
-type rigsConfigEntry struct {
-	GitURL      string                 `json:"git_url"`
-	LocalRepo   string                 `json:"local_repo,omitempty"`
-	AddedAt     string                 `json:"added_at"` // Keep as string to preserve format
-	BeadsConfig *rigsConfigBeadsConfig `json:"beads,omitempty"`
-}
-
-type rigsConfigBeadsConfig struct {
-	Repo   string `json:"repo"`
-	Prefix string `json:"prefix"`
-}
-
-type rigsConfigFile struct {
-	Version int                         `json:"version"`
-	Rigs    map[string]rigsConfigEntry  `json:"rigs"`
-}
-
-func loadRigsConfig(path string) (*rigsConfigFile, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	var cfg rigsConfigFile
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
-	}
-
-	return &cfg, nil
-}
-
-func saveRigsConfig(path string, cfg *rigsConfigFile) error {
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(path, data, 0644)
-}
-
 // beadShower is an interface for fetching bead information.
 // Allows mocking in tests.
 type beadShower interface {
@@ -457,7 +415,7 @@ type realLabelAdder struct{}
 func (r *realLabelAdder) AddLabel(townRoot, id, label string) error {
 	cmd := exec.Command("bd", "label", "add", id, label)
 	cmd.Dir = townRoot
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := proc.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("adding %s label to %s: %s", label, id, strings.TrimSpace(string(output)))
 	}
 	return nil
@@ -707,7 +665,7 @@ func (c *DatabasePrefixCheck) Run(ctx *CheckContext) *CheckResult {
 func (c *DatabasePrefixCheck) getDBPrefix(rigPath string) (string, error) {
 	cmd := exec.Command("bd", "config", "get", "issue_prefix")
 	cmd.Dir = rigPath
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -727,7 +685,7 @@ func (c *DatabasePrefixCheck) Fix(ctx *CheckContext) error {
 	for _, m := range c.mismatches {
 		cmd := exec.Command("bd", "config", "set", "issue_prefix", m.routesPrefix)
 		cmd.Dir = filepath.Join(ctx.TownRoot, m.rigPath)
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := proc.CombinedOutput(cmd); err != nil {
 			return fmt.Errorf("updating %s: %s", m.rigPath, strings.TrimSpace(string(output)))
 		}
 	}
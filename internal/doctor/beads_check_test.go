@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 func TestNewBeadsDatabaseCheck(t *testing.T) {
@@ -335,7 +336,7 @@ func TestPrefixMismatchCheck_Fix(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	cfg, err := loadRigsConfig(rigsPath)
+	cfg, err := rigsconfig.LoadFile(rigsPath)
 	if err != nil {
 		t.Fatalf("failed to load fixed rigs.json: %v (content: %s)", err, data)
 	}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // SettingsCheck verifies each rig has a settings/ directory.
@@ -646,7 +647,7 @@ func (c *CustomTypesCheck) Run(ctx *CheckContext) *CheckResult {
 	// Use Output() not CombinedOutput() to avoid capturing bd's stderr messages
 	cmd := exec.Command("bd", "config", "get", "types.custom")
 	cmd.Dir = ctx.TownRoot
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		// If config key doesn't exist, types are not configured
 		c.townRoot = ctx.TownRoot
@@ -719,7 +720,7 @@ func parseConfigOutput(output []byte) string {
 func (c *CustomTypesCheck) Fix(ctx *CheckContext) error {
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = c.townRoot
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
 	}
@@ -42,7 +42,7 @@ func NewCrewStateCheck() *CrewStateCheck {
 func (c *CrewStateCheck) Run(ctx *CheckContext) *CheckResult {
 	c.invalidCrews = nil
 
-	crewDirs := c.findAllCrewDirs(ctx.TownRoot)
+	crewDirs := c.findAllCrewDirs(ctx)
 	if len(crewDirs) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -179,9 +179,10 @@ type crewDir struct {
 	crewName string
 }
 
-// findAllCrewDirs finds all crew directories in the workspace.
-func (c *CrewStateCheck) findAllCrewDirs(townRoot string) []crewDir {
+// findAllCrewDirs finds all crew directories in the rigs in scope.
+func (c *CrewStateCheck) findAllCrewDirs(ctx *CheckContext) []crewDir {
 	var dirs []crewDir
+	townRoot := ctx.TownRoot
 
 	entries, err := os.ReadDir(townRoot)
 	if err != nil {
@@ -189,7 +190,7 @@ func (c *CrewStateCheck) findAllCrewDirs(townRoot string) []crewDir {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == "mayor" {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == "mayor" || !ctx.InScope(entry.Name()) {
 			continue
 		}
 
@@ -250,7 +251,7 @@ func NewCrewWorktreeCheck() *CrewWorktreeCheck {
 func (c *CrewWorktreeCheck) Run(ctx *CheckContext) *CheckResult {
 	c.staleWorktrees = nil
 
-	worktrees := c.findCrewWorktrees(ctx.TownRoot)
+	worktrees := c.findCrewWorktrees(ctx)
 	if len(worktrees) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -298,8 +299,9 @@ func (c *CrewWorktreeCheck) Fix(ctx *CheckContext) error {
 // findCrewWorktrees finds cross-rig worktrees in crew directories.
 // These are worktrees with hyphenated names (e.g., "beads-dave") that
 // indicate they were created via `gt worktree` for cross-rig work.
-func (c *CrewWorktreeCheck) findCrewWorktrees(townRoot string) []staleWorktree {
+func (c *CrewWorktreeCheck) findCrewWorktrees(ctx *CheckContext) []staleWorktree {
 	var worktrees []staleWorktree
+	townRoot := ctx.TownRoot
 
 	entries, err := os.ReadDir(townRoot)
 	if err != nil {
@@ -307,7 +309,7 @@ func (c *CrewWorktreeCheck) findCrewWorktrees(townRoot string) []staleWorktree {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == "mayor" {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == "mayor" || !ctx.InScope(entry.Name()) {
 			continue
 		}
 
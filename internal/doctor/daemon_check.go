@@ -110,3 +110,59 @@ func itoa(i int) string {
 	}
 	return s
 }
+
+// DaemonRestartsCheck warns when the watchdog (gt daemon ensure) has had to
+// restart the daemon repeatedly, which means it is crashing or hanging.
+type DaemonRestartsCheck struct {
+	BaseCheck
+}
+
+// NewDaemonRestartsCheck creates a new daemon restarts check.
+func NewDaemonRestartsCheck() *DaemonRestartsCheck {
+	return &DaemonRestartsCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "daemon-restarts",
+			CheckDescription: "Check the daemon is not flapping under the watchdog",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run counts watchdog restarts in the last hour.
+func (c *DaemonRestartsCheck) Run(ctx *CheckContext) *CheckResult {
+	h, err := daemon.LoadWatchdogHistory(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read watchdog history",
+			Details: []string{err.Error()},
+		}
+	}
+
+	now := time.Now()
+	recent := h.RestartsSince(now.Add(-daemon.FlapWindow))
+	if !h.Flapping(now) {
+		msg := "No watchdog restarts in the last hour"
+		if len(recent) > 0 {
+			msg = itoa(len(recent)) + " watchdog restart(s) in the last hour"
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: msg,
+		}
+	}
+
+	details := make([]string, 0, len(recent))
+	for _, r := range recent {
+		details = append(details, r.At.Local().Format("15:04:05")+" "+r.Reason)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: "Daemon restarted " + itoa(len(recent)) + " times in the last hour",
+		Details: details,
+		FixHint: "Check 'gt daemon logs' for why it keeps dying or hanging",
+	}
+}
@@ -121,19 +121,14 @@ func (c *EnvVarsCheck) Run(ctx *CheckContext) *CheckResult {
 
 		checkedCount++
 
-		// Compare each expected var
-		for key, expectedVal := range expected {
-			actualVal, exists := actual[key]
-			if !exists {
-				mismatches = append(mismatches, fmt.Sprintf("%s: missing %s (expected %q)", sess, key, expectedVal))
-			} else if actualVal != expectedVal {
-				mismatches = append(mismatches, fmt.Sprintf("%s: %s=%q (expected %q)", sess, key, actualVal, expectedVal))
-			}
+		for _, problem := range config.CheckAgentEnv(expected, actual) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %s", sess, problem))
 		}
 
-		// Check for BEADS_DIR - this breaks routing-based lookups
-		if beadsDir, exists := actual["BEADS_DIR"]; exists && beadsDir != "" {
-			beadsDirWarnings = append(beadsDirWarnings, fmt.Sprintf("%s: BEADS_DIR=%q (breaks prefix routing)", sess, beadsDir))
+		// Check for BEADS_DIR and other forbidden vars - these break
+		// routing-based lookups
+		for _, key := range config.ForbiddenEnvSet(actual) {
+			beadsDirWarnings = append(beadsDirWarnings, fmt.Sprintf("%s: %s=%q (breaks prefix routing)", sess, key, actual[key]))
 		}
 	}
 
@@ -44,6 +44,9 @@ func (c *HooksPathAllRigsCheck) Run(ctx *CheckContext) *CheckResult {
 	totalClones := 0
 
 	for _, rigPath := range rigs {
+		if !ctx.InScope(filepath.Base(rigPath)) {
+			continue
+		}
 		clonePaths := findRigClones(rigPath)
 		for _, clonePath := range clonePaths {
 			// Skip if no .githooks directory (repo doesn't use hooks)
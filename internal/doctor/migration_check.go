@@ -11,11 +11,12 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
-	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // DoltMetadataCheck verifies that all rig .beads/metadata.json files have
-// proper Dolt server configuration (backend, dolt_mode, dolt_database).
+// proper Dolt server configuration (backend, dolt_mode, dolt_database),
+// reporting each field that has drifted.
 // Missing or incomplete metadata causes the split-brain problem where bd
 // opens isolated local databases instead of the centralized Dolt server.
 type DoltMetadataCheck struct {
@@ -41,7 +42,7 @@ func (c *DoltMetadataCheck) Run(ctx *CheckContext) *CheckResult {
 	c.missingMetadata = nil
 
 	// Check if dolt data directory exists (no point checking if dolt isn't in use)
-	doltDataDir := filepath.Join(ctx.TownRoot, ".dolt-data")
+	doltDataDir := doltserver.DefaultConfig(ctx.TownRoot).DataDir
 	if _, err := os.Stat(doltDataDir); os.IsNotExist(err) {
 		return &CheckResult{
 			Name:     c.Name(),
@@ -51,46 +52,53 @@ func (c *DoltMetadataCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	var missing []string
+	var problems [][]string // details per rig needing a fix, parallel to c.missingMetadata
 	var ok int
 
-	// Check town-level beads (hq database)
-	townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
-	if _, err := os.Stat(filepath.Join(doltDataDir, "hq")); err == nil {
-		if !c.hasDoltMetadata(townBeadsDir, "hq") {
-			missing = append(missing, "hq (town root .beads/)")
-			c.missingMetadata = append(c.missingMetadata, "hq")
-		} else {
+	check := func(rigName string) {
+		v, err := doltserver.VerifyMetadata(ctx.TownRoot, rigName)
+		switch {
+		case err != nil:
+			problems = append(problems, []string{rigName + ": " + err.Error()})
+		case v.OK():
 			ok++
+			return
+		case v.Missing:
+			relPath, _ := filepath.Rel(ctx.TownRoot, v.Path)
+			problems = append(problems, []string{"Missing dolt config: " + rigName + " (" + relPath + ")"})
+		default:
+			var lines []string
+			for _, d := range v.Drift {
+				actual := d.Actual
+				if actual == "" {
+					actual = "(missing)"
+				}
+				lines = append(lines, fmt.Sprintf("%s: %s is %q, expected %q", rigName, d.Field, actual, d.Expected))
+			}
+			problems = append(problems, lines)
 		}
+		c.missingMetadata = append(c.missingMetadata, rigName)
+	}
+
+	// Check town-level beads (hq database)
+	if _, err := os.Stat(filepath.Join(doltDataDir, "hq")); err == nil && ctx.IncludeTown() {
+		check("hq")
 	}
 
 	// Check rig-level beads
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigs := c.loadRigs(rigsPath)
-	for rigName := range rigs {
-		// Only check rigs that have a dolt database
-		if _, err := os.Stat(filepath.Join(doltDataDir, rigName)); os.IsNotExist(err) {
+	rigNames, _ := rigsconfig.Names(ctx.TownRoot)
+	for _, rigName := range rigNames {
+		if !ctx.InScope(rigName) {
 			continue
 		}
-
-		beadsDir := c.findRigBeadsDir(ctx.TownRoot, rigName)
-		if beadsDir == "" {
-			missing = append(missing, rigName+" (no .beads directory)")
-			c.missingMetadata = append(c.missingMetadata, rigName)
+		// Only check rigs that have a dolt database
+		if _, err := os.Stat(filepath.Join(doltDataDir, rigName)); os.IsNotExist(err) {
 			continue
 		}
-
-		if !c.hasDoltMetadata(beadsDir, rigName) {
-			relPath, _ := filepath.Rel(ctx.TownRoot, beadsDir)
-			missing = append(missing, rigName+" ("+relPath+")")
-			c.missingMetadata = append(c.missingMetadata, rigName)
-		} else {
-			ok++
-		}
+		check(rigName)
 	}
 
-	if len(missing) == 0 {
+	if len(problems) == 0 {
 		return &CheckResult{
 			Name:     c.Name(),
 			Status:   StatusOK,
@@ -99,17 +107,25 @@ func (c *DoltMetadataCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	details := make([]string, len(missing))
-	for i, m := range missing {
-		details[i] = "Missing dolt config: " + m
+	var details []string
+	fixHint := "Run 'gt dolt fix-metadata' to update all metadata.json files"
+	for i, lines := range problems {
+		details = append(details, lines...)
+		// A pending proposal means the file is tracked in git (or
+		// read-only), so the fix was deliberately not applied in place.
+		proposal := doltserver.MetadataProposalPath(ctx.TownRoot, c.missingMetadata[i])
+		if _, err := os.Stat(proposal); err == nil {
+			details = append(details, "  metadata.json is tracked in git; proposed change at "+proposal)
+			fixHint = "Review and commit the proposed metadata.json changes, or run 'gt dolt fix-metadata --commit'"
+		}
 	}
 
 	return &CheckResult{
 		Name:     c.Name(),
 		Status:   StatusWarning,
-		Message:  fmt.Sprintf("%d rig(s) missing Dolt server metadata", len(missing)),
+		Message:  fmt.Sprintf("%d rig(s) with missing or drifted Dolt server metadata", len(problems)),
 		Details:  details,
-		FixHint:  "Run 'gt dolt fix-metadata' to update all metadata.json files",
+		FixHint:  fixHint,
 		Category: c.CheckCategory,
 	}
 }
@@ -129,31 +145,9 @@ func (c *DoltMetadataCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
-// hasDoltMetadata checks if a beads directory has proper dolt server config.
-func (c *DoltMetadataCheck) hasDoltMetadata(beadsDir, expectedDB string) bool {
-	metadataPath := filepath.Join(beadsDir, "metadata.json")
-	data, err := os.ReadFile(metadataPath)
-	if err != nil {
-		return false
-	}
-
-	var metadata struct {
-		Backend      string `json:"backend"`
-		DoltMode     string `json:"dolt_mode"`
-		DoltDatabase string `json:"dolt_database"`
-		JsonlExport  string `json:"jsonl_export"`
-	}
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		return false
-	}
-
-	return metadata.Backend == "dolt" &&
-		metadata.DoltMode == "server" &&
-		metadata.DoltDatabase == expectedDB &&
-		metadata.JsonlExport == "issues.jsonl"
-}
-
 // writeDoltMetadata writes dolt server config to a rig's metadata.json.
+// Like gt dolt fix-metadata, it leaves a file tracked in git alone unless the
+// town allows committing it (see doltserver.WriteMetadataFile).
 func (c *DoltMetadataCheck) writeDoltMetadata(townRoot, rigName string) error {
 	// Use FindOrCreateRigBeadsDir to atomically resolve and create the directory,
 	// avoiding the TOCTOU race in the stat-then-use pattern.
@@ -184,50 +178,22 @@ func (c *DoltMetadataCheck) writeDoltMetadata(townRoot, rigName string) error {
 		return fmt.Errorf("marshaling metadata: %w", err)
 	}
 
-	if err := util.AtomicWriteFile(metadataPath, append(data, '\n'), 0600); err != nil {
-		return fmt.Errorf("writing metadata.json: %w", err)
+	res, err := doltserver.WriteMetadataFile(townRoot, rigName, beadsDir, append(data, '\n'), doltserver.DefaultMetadataOptions(townRoot))
+	if err != nil {
+		return err
+	}
+	if res.Action == doltserver.MetadataProposed {
+		return fmt.Errorf("metadata.json is tracked in git or read-only; proposed change written to %s", res.Proposal)
 	}
 
 	return nil
 }
 
-// findRigBeadsDir delegates to the canonical read-only implementation in doltserver.
-func (c *DoltMetadataCheck) findRigBeadsDir(townRoot, rigName string) string {
-	return doltserver.FindRigBeadsDir(townRoot, rigName)
-}
-
 // findOrCreateRigBeadsDir delegates to the atomic resolve-and-create implementation.
 func (c *DoltMetadataCheck) findOrCreateRigBeadsDir(townRoot, rigName string) (string, error) {
 	return doltserver.FindOrCreateRigBeadsDir(townRoot, rigName)
 }
 
-// loadRigs loads the rigs configuration from rigs.json.
-func (c *DoltMetadataCheck) loadRigs(rigsPath string) map[string]struct{} {
-	return loadRigNames(rigsPath)
-}
-
-// loadRigNames loads rig names from rigs.json.
-func loadRigNames(rigsPath string) map[string]struct{} {
-	rigs := make(map[string]struct{})
-
-	data, err := os.ReadFile(rigsPath)
-	if err != nil {
-		return rigs
-	}
-
-	var config struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return rigs
-	}
-
-	for name := range config.Rigs {
-		rigs[name] = struct{}{}
-	}
-	return rigs
-}
-
 // DoltServerReachableCheck detects the split-brain risk: metadata.json says
 // dolt_mode=server but the Dolt server is not actually accepting connections.
 // In this state, bd commands may silently create isolated local databases
@@ -312,9 +278,8 @@ func (c *DoltServerReachableCheck) findServerModeRigsByAddr(townRoot string) map
 	}
 
 	// Check rig-level beads
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigs := loadRigNames(rigsPath)
-	for rigName := range rigs {
+	rigNames, _ := rigsconfig.Names(townRoot)
+	for _, rigName := range rigNames {
 		// Check mayor/rig/.beads first (canonical), then rig/.beads
 		beadsDir := filepath.Join(townRoot, rigName, "mayor", "rig", ".beads")
 		if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
@@ -403,25 +368,45 @@ func (c *DoltOrphanedDatabaseCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
+	// Databases of rigs removed from rigs.json are still being served;
+	// call them out so they get pruned rather than ignored.
+	deletedRig := make(map[string]bool)
+	if deleted, err := doltserver.FindDeletedRigDatabases(ctx.TownRoot); err == nil {
+		for _, d := range deleted {
+			deletedRig[d.Name] = true
+		}
+	}
+
 	details := make([]string, len(orphans))
 	for i, o := range orphans {
 		details[i] = fmt.Sprintf("Orphaned: %s (%s)", o.Name, formatBytes(o.SizeBytes))
+		if deletedRig[o.Name] {
+			details[i] += " - rig no longer in rigs.json"
+		}
 		c.orphanNames = append(c.orphanNames, o.Name)
 	}
 
+	fixHint := "Run 'gt dolt cleanup' to remove orphaned databases"
+	if len(deletedRig) > 0 {
+		fixHint = "Run 'gt dolt prune-databases' to back up and remove databases of deleted rigs"
+	}
 	return &CheckResult{
 		Name:     c.Name(),
 		Status:   StatusWarning,
 		Message:  fmt.Sprintf("%d orphaned database(s) in .dolt-data/", len(orphans)),
 		Details:  details,
-		FixHint:  "Run 'gt dolt cleanup' to remove orphaned databases",
+		FixHint:  fixHint,
 		Category: c.CheckCategory,
 	}
 }
 
-// Fix removes orphaned databases.
+// Fix removes orphaned databases, writing a final backup of each first
+// (see doltserver.PrunedDatabasesDir).
 func (c *DoltOrphanedDatabaseCheck) Fix(ctx *CheckContext) error {
 	for _, name := range c.orphanNames {
+		if _, err := doltserver.BackupDatabaseDir(ctx.TownRoot, name); err != nil {
+			return fmt.Errorf("backing up orphaned database %s: %w", name, err)
+		}
 		if err := doltserver.RemoveDatabase(ctx.TownRoot, name); err != nil {
 			return fmt.Errorf("removing orphaned database %s: %w", name, err)
 		}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
 // setupDoltDB creates a fake Dolt database directory under .dolt-data/.
@@ -228,6 +230,10 @@ func TestDoltOrphanedDatabaseCheck_DetectsOrphans(t *testing.T) {
 	if result.FixHint == "" {
 		t.Error("expected a fix hint")
 	}
+	// beads_wy is not a registered rig name, so it is a deleted rig's database
+	if result.Details[0] != "Orphaned: beads_wy (4 B) - rig no longer in rigs.json" {
+		t.Errorf("unexpected detail: %s", result.Details[0])
+	}
 }
 
 func TestDoltOrphanedDatabaseCheck_Fix(t *testing.T) {
@@ -270,6 +276,12 @@ func TestDoltOrphanedDatabaseCheck_Fix(t *testing.T) {
 	if _, err := os.Stat(hqPath); err != nil {
 		t.Errorf("expected hq database to survive Fix, but got error: %v", err)
 	}
+
+	// Verify each orphan was backed up before removal
+	backups, _ := filepath.Glob(filepath.Join(townRoot, "exports", "pruned-databases", "orphan*.tar.gz"))
+	if len(backups) != 2 {
+		t.Errorf("expected 2 backups, got %v", backups)
+	}
 }
 
 func TestDoltOrphanedDatabaseCheck_NoDoltData(t *testing.T) {
@@ -297,3 +309,32 @@ func TestDoltOrphanedDatabaseCheck_Name(t *testing.T) {
 		t.Errorf("expected name 'dolt-orphaned-databases', got %q", check.Name())
 	}
 }
+
+func TestDoltMetadataCheck_RigOnly(t *testing.T) {
+	townRoot := t.TempDir()
+	setupRigsJSON(t, townRoot, []string{"gastown", "beads"})
+	for _, db := range []string{"hq", "gastown", "beads"} {
+		setupDoltDB(t, townRoot, db)
+	}
+	// Only the beads rig has metadata; hq and gastown are missing theirs.
+	setupRigMetadata(t, townRoot, "beads", "beads")
+	if err := doltserver.EnsureMetadata(townRoot, "beads"); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewDoltMetadataCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning || len(check.missingMetadata) != 2 {
+		t.Fatalf("town run: status %v, missing %v; want warning for hq and gastown", result.Status, check.missingMetadata)
+	}
+
+	result = check.Run(&CheckContext{TownRoot: townRoot, RigName: "gastown", RigOnly: true})
+	if result.Status != StatusWarning || len(check.missingMetadata) != 1 || check.missingMetadata[0] != "gastown" {
+		t.Fatalf("rig run: status %v, missing %v; want warning for gastown only", result.Status, check.missingMetadata)
+	}
+
+	result = check.Run(&CheckContext{TownRoot: townRoot, RigName: "beads", RigOnly: true})
+	if result.Status != StatusOK {
+		t.Errorf("rig run for healthy rig: status %v, details %v", result.Status, result.Details)
+	}
+}
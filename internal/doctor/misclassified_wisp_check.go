@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // CheckMisclassifiedWisps detects issues that should be marked as wisps but aren't.
@@ -218,7 +219,7 @@ func (c *CheckMisclassifiedWisps) Fix(ctx *CheckContext) error {
 		closeReason := fmt.Sprintf("Closed by doctor: %s (should have been ephemeral wisp)", wisp.reason)
 		cmd := exec.Command("bd", "close", wisp.id, "--reason", closeReason)
 		cmd.Dir = workDir
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := proc.CombinedOutput(cmd); err != nil {
 			lastErr = fmt.Errorf("%s/%s: %v (%s)", wisp.rigName, wisp.id, err, string(output))
 		}
 	}
@@ -168,6 +168,15 @@ func isCrewSession(sess string) bool {
 	return identity.Role == session.RoleCrew
 }
 
+// sessionInRig reports whether sess belongs to an agent of rigName.
+func sessionInRig(sess, rigName string) bool {
+	identity, err := session.ParseSessionName(sess)
+	if err != nil {
+		return false
+	}
+	return identity.Rig == rigName
+}
+
 // getValidRigs returns a list of valid rig names from the workspace.
 func (c *OrphanSessionCheck) getValidRigs(townRoot string) []string {
 	var rigs []string
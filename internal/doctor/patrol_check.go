@@ -3,6 +3,7 @@ package doctor
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 	"github.com/steveyegge/gastown/internal/templates"
 )
 
@@ -96,7 +99,7 @@ func (c *PatrolMoleculesExistCheck) checkPatrolFormulas(rigPath string) []string
 	// This checks .beads/formulas/, ~/.beads/formulas/, and $GT_ROOT/.beads/formulas/
 	cmd := exec.Command("bd", "formula", "list")
 	cmd.Dir = rigPath
-	output, err := cmd.Output()
+	output, err := proc.Output(cmd)
 	if err != nil {
 		// Can't check formulas, assume all missing
 		return patrolFormulas
@@ -511,23 +514,9 @@ func (c *PatrolRolesHavePromptsCheck) Fix(ctx *CheckContext) error {
 
 // discoverRigs finds all registered rigs.
 func discoverRigs(townRoot string) ([]string, error) {
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No rigs configured
-		}
-		return nil, err
-	}
-
-	var rigsConfig config.RigsConfig
-	if err := json.Unmarshal(data, &rigsConfig); err != nil {
-		return nil, err
-	}
-
-	var rigs []string
-	for name := range rigsConfig.Rigs {
-		rigs = append(rigs, name)
+	rigs, err := rigsconfig.Names(townRoot)
+	if errors.Is(err, rigsconfig.ErrNotFound) {
+		return nil, nil // No rigs configured
 	}
-	return rigs, nil
+	return rigs, err
 }
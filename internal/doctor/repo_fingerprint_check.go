@@ -11,6 +11,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // bdDoctorResult represents the JSON output from bd doctor --json.
@@ -56,7 +57,7 @@ func (c *RepoFingerprintCheck) Run(ctx *CheckContext) *CheckResult {
 
 	// Check town-level beads
 	townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
-	if _, err := os.Stat(townBeadsDir); err == nil {
+	if _, err := os.Stat(townBeadsDir); err == nil && ctx.IncludeTown() {
 		result := c.checkBeadsDir(filepath.Dir(townBeadsDir), "town")
 		if result.Status != StatusOK {
 			return result
@@ -91,7 +92,7 @@ func (c *RepoFingerprintCheck) checkBeadsDir(workDir, location string) *CheckRes
 	cmd.Stderr = &stderr
 
 	// bd doctor exits with non-zero if there are warnings, so ignore exit code
-	_ = cmd.Run()
+	_ = proc.RunCmd(cmd)
 
 	// Parse JSON output
 	var result bdDoctorResult
@@ -167,7 +168,7 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 	cmd.Dir = filepath.Dir(c.beadsDir) // Parent of .beads directory
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return fmt.Errorf("bd migrate --update-repo-id failed: %v: %s", err, stderr.String())
 	}
 
@@ -52,7 +52,7 @@ func (c *RigBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 	for _, r := range routes {
 		// Extract rig name from path (first component)
 		parts := strings.Split(r.Path, "/")
-		if len(parts) >= 1 && parts[0] != "." {
+		if len(parts) >= 1 && parts[0] != "." && ctx.InScope(parts[0]) {
 			rigName := parts[0]
 			prefix := strings.TrimSuffix(r.Prefix, "-")
 			if _, exists := rigSet[rigName]; !exists {
@@ -123,7 +123,7 @@ func (c *RigBeadsCheck) Fix(ctx *CheckContext) error {
 	})
 	for _, r := range routes {
 		parts := strings.Split(r.Path, "/")
-		if len(parts) >= 1 && parts[0] != "." {
+		if len(parts) >= 1 && parts[0] != "." && ctx.InScope(parts[0]) {
 			rigName := parts[0]
 			prefix := strings.TrimSuffix(r.Prefix, "-")
 			if _, exists := rigSet[rigName]; !exists {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // RigIsGitRepoCheck verifies the rig has a valid mayor/rig git clone.
@@ -835,7 +836,7 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check if bd command works
 	cmd := exec.Command("bd", "stats", "--json")
 	cmd.Dir = c.rigPath
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -1021,7 +1022,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		// Gas Town rigs use Dolt server mode via the shared town Dolt sql-server.
 		cmd := exec.Command("bd", "init", "--prefix", prefix, "--backend", "dolt", "--server")
 		cmd.Dir = rigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := proc.CombinedOutput(cmd); err != nil {
 			// bd might not be installed - create minimal config.yaml
 			configPath := filepath.Join(rigBeadsDir, "config.yaml")
 			configContent := fmt.Sprintf("prefix: %s\n", prefix)
@@ -1034,7 +1035,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 			// Configure custom types for Gas Town (beads v0.46.0+)
 			configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 			configCmd.Dir = rigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+			_, _ = proc.CombinedOutput(configCmd) // Ignore errors - older beads don't need this
 		}
 		return nil
 	}
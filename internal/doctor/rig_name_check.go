@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // RigNameMismatchCheck detects when a rig's config.json has a name or beads
@@ -99,8 +101,7 @@ func (c *RigNameMismatchCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Check 2: config beads prefix vs rigs.json prefix
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigsConfig, rigsErr := loadRigsConfig(rigsPath)
+	rigsConfig, rigsErr := rigsconfig.Load(ctx.TownRoot)
 	if rigsErr == nil && cfg.Beads != nil && cfg.Beads.Prefix != "" {
 		if entry, ok := rigsConfig.Rigs[ctx.RigName]; ok && entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
 			if cfg.Beads.Prefix != entry.BeadsConfig.Prefix {
@@ -149,8 +150,7 @@ func (c *RigNameMismatchCheck) Fix(ctx *CheckContext) error {
 	}
 
 	// Fix prefix to match rigs.json
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigsConfig, rigsErr := loadRigsConfig(rigsPath)
+	rigsConfig, rigsErr := rigsconfig.Load(ctx.TownRoot)
 	if rigsErr == nil && cfg.Beads != nil && cfg.Beads.Prefix != "" {
 		if entry, ok := rigsConfig.Rigs[ctx.RigName]; ok && entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
 			if cfg.Beads.Prefix != entry.BeadsConfig.Prefix {
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // RigRoutesJSONLCheck detects and fixes routes.jsonl files in rig .beads directories.
@@ -126,9 +126,8 @@ func (c *RigRoutesJSONLCheck) findRigDirectories(townRoot string) []string {
 	seen := make(map[string]bool)
 
 	// Source 1: rigs.json registry
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	if rigsConfig, err := config.LoadRigsConfig(rigsPath); err == nil {
-		for rigName := range rigsConfig.Rigs {
+	if rigNames, err := rigsconfig.Names(townRoot); err == nil {
+		for _, rigName := range rigNames {
 			rigPath := filepath.Join(townRoot, rigName)
 			if _, err := os.Stat(rigPath); err == nil && !seen[rigPath] {
 				rigDirs = append(rigDirs, rigPath)
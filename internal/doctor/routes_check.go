@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // determineRigBeadsPath returns the correct route path for a rig based on its actual layout.
@@ -114,8 +114,7 @@ func (c *RoutesCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Load rigs registry
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(ctx.TownRoot)
 	if err != nil {
 		// No rigs config - check for missing town/convoy routes and validate existing routes
 		if missingTownRoute || missingConvoyRoute {
@@ -341,8 +340,7 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	}
 
 	// Load rigs registry
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	rigsConfig, err := rigsconfig.Load(ctx.TownRoot)
 	if err != nil {
 		// No rigs config - just write town root route if we added it
 		if modified {
@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // RoutingModeCheck detects when beads routing.mode is set to "auto", which can
@@ -34,10 +36,12 @@ func NewRoutingModeCheck() *RoutingModeCheck {
 // Run checks if routing.mode is set to "explicit".
 func (c *RoutingModeCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check town-level beads config
-	townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
-	result := c.checkRoutingMode(townBeadsDir, "town")
-	if result.Status != StatusOK {
-		return result
+	if ctx.IncludeTown() {
+		townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
+		result := c.checkRoutingMode(townBeadsDir, "town")
+		if result.Status != StatusOK {
+			return result
+		}
 	}
 
 	// Also check rig-level beads if specified
@@ -67,7 +71,7 @@ func (c *RoutingModeCheck) checkRoutingMode(beadsDir, location string) *CheckRes
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		// If the config key doesn't exist, that means it defaults to "auto"
 		if strings.Contains(stderr.String(), "not found") || strings.Contains(stderr.String(), "not set") {
 			return &CheckResult{
@@ -117,9 +121,11 @@ func (c *RoutingModeCheck) checkRoutingMode(beadsDir, location string) *CheckRes
 // Fix sets routing.mode to "explicit" in both town and rig beads.
 func (c *RoutingModeCheck) Fix(ctx *CheckContext) error {
 	// Fix town-level beads
-	townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
-	if err := c.setRoutingMode(townBeadsDir); err != nil {
-		return fmt.Errorf("fixing town beads: %w", err)
+	if ctx.IncludeTown() {
+		townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
+		if err := c.setRoutingMode(townBeadsDir); err != nil {
+			return fmt.Errorf("fixing town beads: %w", err)
+		}
 	}
 
 	// Also fix rig-level beads if specified
@@ -139,7 +145,7 @@ func (c *RoutingModeCheck) setRoutingMode(beadsDir string) error {
 	cmd.Dir = filepath.Dir(beadsDir)
 	cmd.Env = append(cmd.Environ(), "BEADS_DIR="+beadsDir)
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := proc.CombinedOutput(cmd); err != nil {
 		return fmt.Errorf("bd config set failed: %s", strings.TrimSpace(string(output)))
 	}
 
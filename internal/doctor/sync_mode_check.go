@@ -0,0 +1,110 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// SyncModeCheck detects rigs whose bd sync.mode differs between the Dolt
+// database config table and .beads/config.yaml. bd reads both, so a
+// mismatch means JSONL export behaves differently depending on which
+// source wins for a given command.
+type SyncModeCheck struct {
+	FixableCheck
+	mismatched []doltserver.SyncModeStatus // Cached during Run for use in Fix
+}
+
+// NewSyncModeCheck creates a new sync mode consistency check.
+func NewSyncModeCheck() *SyncModeCheck {
+	return &SyncModeCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "sync-mode",
+				CheckDescription: "Check that sync.mode matches between the database and config.yaml",
+				CheckCategory:    CategoryConfig,
+			},
+		},
+	}
+}
+
+// Run compares the sync mode of every database with its config.yaml.
+func (c *SyncModeCheck) Run(ctx *CheckContext) *CheckResult {
+	c.mismatched = nil
+
+	if _, err := os.Stat(doltserver.DefaultConfig(ctx.TownRoot).DataDir); os.IsNotExist(err) {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No Dolt data directory (dolt not in use)",
+			Category: c.CheckCategory,
+		}
+	}
+	if running, _, _ := doltserver.IsRunning(ctx.TownRoot); !running {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "Dolt server not running (skipped)",
+			Category: c.CheckCategory,
+		}
+	}
+
+	statuses, err := doltserver.ListSyncModeStatuses(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Could not list databases: %v", err),
+			Category: c.CheckCategory,
+		}
+	}
+
+	var details []string
+	for _, s := range statuses {
+		if s.Mismatch() {
+			c.mismatched = append(c.mismatched, s)
+			details = append(details, fmt.Sprintf("%s: db=%s config.yaml=%s", s.Database, orUnsetMode(s.DBMode), orUnsetMode(s.ConfigMode)))
+		}
+	}
+
+	if len(c.mismatched) == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  fmt.Sprintf("sync.mode consistent across %d database(s)", len(statuses)),
+			Category: c.CheckCategory,
+		}
+	}
+
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusWarning,
+		Message:  fmt.Sprintf("%d rig(s) have mismatched sync.mode", len(c.mismatched)),
+		Details:  details,
+		FixHint:  "Run 'gt doctor --fix' to apply config.yaml's mode, or 'gt sync mode set <mode> --rig <rig>'",
+		Category: c.CheckCategory,
+	}
+}
+
+// Fix applies config.yaml's mode to the database. If config.yaml has no
+// mode, the database value is written back to config.yaml instead.
+func (c *SyncModeCheck) Fix(ctx *CheckContext) error {
+	for _, s := range c.mismatched {
+		mode := s.ConfigMode
+		if mode == "" {
+			mode = s.DBMode
+		}
+		if err := doltserver.SetSyncMode(ctx.TownRoot, s.Database, mode); err != nil {
+			return fmt.Errorf("fixing %s: %w", s.Database, err)
+		}
+	}
+	return nil
+}
+
+func orUnsetMode(mode string) string {
+	if mode == "" {
+		return "(unset)"
+	}
+	return mode
+}
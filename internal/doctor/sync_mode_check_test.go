@@ -0,0 +1,14 @@
+package doctor
+
+import "testing"
+
+func TestSyncModeCheck_NoDoltData(t *testing.T) {
+	check := NewSyncModeCheck()
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK when dolt is not in use", result.Status)
+	}
+	if !check.CanFix() {
+		t.Error("sync-mode check should be fixable")
+	}
+}
@@ -63,6 +63,7 @@ type CheckContext struct {
 	RigName         string // Rig name (empty for town-level checks)
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+	RigOnly         bool   // Scope checks to RigName, skipping town-level beads and other rigs
 }
 
 // RigPath returns the full path to the rig directory.
@@ -74,6 +75,18 @@ func (ctx *CheckContext) RigPath() string {
 	return ctx.TownRoot + "/" + ctx.RigName
 }
 
+// IncludeTown reports whether checks should examine town-level state
+// (town beads, the hq database). False for a rig-scoped run.
+func (ctx *CheckContext) IncludeTown() bool {
+	return !ctx.RigOnly || ctx.RigName == ""
+}
+
+// InScope reports whether a check iterating over all rigs should examine
+// rigName. Every rig is in scope unless the run is rig-scoped.
+func (ctx *CheckContext) InScope(rigName string) bool {
+	return !ctx.RigOnly || ctx.RigName == "" || rigName == ctx.RigName
+}
+
 // DefaultSlowThreshold is the default duration above which a check is considered slow.
 const DefaultSlowThreshold = 1 * time.Second
 
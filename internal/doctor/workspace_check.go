@@ -2,9 +2,13 @@ package doctor
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // TownConfigExistsCheck verifies mayor/town.json exists.
@@ -160,22 +164,10 @@ func (c *RigsRegistryExistsCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix creates an empty rigs.json file.
 func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-
-	emptyRigs := struct {
-		Version int                    `json:"version"`
-		Rigs    map[string]interface{} `json:"rigs"`
-	}{
-		Version: 1,
-		Rigs:    make(map[string]interface{}),
-	}
-
-	data, err := json.MarshalIndent(emptyRigs, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling empty rigs.json: %w", err)
-	}
-
-	return os.WriteFile(rigsPath, data, 0644)
+	return rigsconfig.Save(ctx.TownRoot, &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs:    make(map[string]config.RigEntry),
+	})
 }
 
 // RigsRegistryValidCheck verifies mayor/rigs.json is valid and rigs exist.
@@ -197,19 +189,11 @@ func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
 	}
 }
 
-// rigsConfig represents the structure of mayor/rigs.json.
-type rigsConfig struct {
-	Version int                    `json:"version"`
-	Rigs    map[string]interface{} `json:"rigs"`
-}
-
 // Run validates mayor/rigs.json and checks that registered rigs exist.
 func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-
-	data, err := os.ReadFile(rigsPath)
+	rigNames, err := rigsconfig.Names(ctx.TownRoot)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, rigsconfig.ErrNotFound) {
 			return &CheckResult{
 				Name:    c.Name(),
 				Status:  StatusOK,
@@ -219,23 +203,13 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
-			Message: "Cannot read mayor/rigs.json",
-			Details: []string{err.Error()},
-		}
-	}
-
-	var config rigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusError,
-			Message: "mayor/rigs.json is not valid JSON",
+			Message: "mayor/rigs.json is invalid",
 			Details: []string{err.Error()},
-			FixHint: "Fix JSON syntax in mayor/rigs.json",
+			FixHint: "Fix the syntax or rig names in mayor/rigs.json",
 		}
 	}
 
-	if len(config.Rigs) == 0 {
+	if len(rigNames) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
@@ -247,7 +221,7 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 	var missing []string
 	var found int
 
-	for rigName := range config.Rigs {
+	for _, rigName := range rigNames {
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
 		if _, err := os.Stat(rigPath); os.IsNotExist(err) {
 			missing = append(missing, rigName)
@@ -268,7 +242,7 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusWarning,
-			Message: fmt.Sprintf("%d of %d registered rig(s) missing", len(missing), len(config.Rigs)),
+			Message: fmt.Sprintf("%d of %d registered rig(s) missing", len(missing), len(rigNames)),
 			Details: details,
 			FixHint: "Run 'gt doctor --fix' to remove missing rigs from registry",
 		}
@@ -287,30 +261,17 @@ func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
-
-	data, err := os.ReadFile(rigsPath)
+	rigs, err := rigsconfig.Load(ctx.TownRoot)
 	if err != nil {
-		return fmt.Errorf("reading rigs.json: %w", err)
-	}
-
-	var config rigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("parsing rigs.json: %w", err)
+		return fmt.Errorf("loading rigs.json: %w", err)
 	}
 
 	// Remove missing rigs
 	for _, rig := range c.missingRigs {
-		delete(config.Rigs, rig)
-	}
-
-	// Write back
-	newData, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling rigs.json: %w", err)
+		delete(rigs.Rigs, rig)
 	}
 
-	return os.WriteFile(rigsPath, newData, 0644)
+	return rigsconfig.Save(ctx.TownRoot, rigs)
 }
 
 // MayorExistsCheck verifies the mayor/ directory structure.
@@ -0,0 +1,128 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// workspaceEnvTarget is a crew or polecat worktree and the env it should have.
+type workspaceEnvTarget struct {
+	path string
+	env  map[string]string
+}
+
+// WorkspaceEnvCheck verifies that every crew and polecat worktree has a
+// current .runtime/env.sh, the file humans source (or direnv loads via
+// .envrc) to get the workspace's GT_* variables.
+type WorkspaceEnvCheck struct {
+	FixableCheck
+	stale []workspaceEnvTarget
+}
+
+// NewWorkspaceEnvCheck creates a new workspace env check.
+func NewWorkspaceEnvCheck() *WorkspaceEnvCheck {
+	return &WorkspaceEnvCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "workspace-env",
+				CheckDescription: "Check crew and polecat workspaces have a current .runtime/env.sh",
+				CheckCategory:    CategoryConfig,
+			},
+		},
+	}
+}
+
+// Run compares each workspace's env file with the one gt would write now.
+func (c *WorkspaceEnvCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+	var details []string
+	total := 0
+
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigName := filepath.Base(rigPath)
+		if !ctx.InScope(rigName) {
+			continue
+		}
+		for _, t := range workspaceEnvTargets(ctx.TownRoot, rigPath) {
+			total++
+			if problem := rig.CheckWorkspaceEnv(t.path, t.env); problem != "" {
+				c.stale = append(c.stale, t)
+				relPath, err := filepath.Rel(ctx.TownRoot, t.path)
+				if err != nil {
+					relPath = t.path
+				}
+				details = append(details, fmt.Sprintf("%s: %s", relPath, problem))
+			}
+		}
+	}
+
+	if len(c.stale) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("All %d workspace(s) have a current env file", total),
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d of %d workspace(s) have a missing or stale env file", len(c.stale), total),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' or 'gt crew pristine' to rewrite them",
+	}
+}
+
+// Fix rewrites the missing or stale env files.
+func (c *WorkspaceEnvCheck) Fix(ctx *CheckContext) error {
+	for _, t := range c.stale {
+		if err := rig.WriteWorkspaceEnv(t.path, t.env); err != nil {
+			return fmt.Errorf("%s: %w", t.path, err)
+		}
+	}
+	return nil
+}
+
+// workspaceEnvTargets returns the rig's crew clones (crew/<name>/) and
+// polecat worktrees (polecats/<name>/<rig>/) with the env each should have.
+func workspaceEnvTargets(townRoot, rigPath string) []workspaceEnvTarget {
+	rigName := filepath.Base(rigPath)
+	var targets []workspaceEnvTarget
+
+	crewDir := filepath.Join(rigPath, "crew")
+	if entries, err := os.ReadDir(crewDir); err == nil {
+		for _, e := range entries {
+			path := filepath.Join(crewDir, e.Name())
+			if !e.IsDir() || !isGitWorktree(path) {
+				continue
+			}
+			targets = append(targets, workspaceEnvTarget{
+				path: path,
+				env:  rig.WorkspaceEnv(townRoot, rigName, "crew", e.Name(), ""),
+			})
+		}
+	}
+
+	polecatsDir := filepath.Join(rigPath, "polecats")
+	if entries, err := os.ReadDir(polecatsDir); err == nil {
+		for _, e := range entries {
+			path := filepath.Join(polecatsDir, e.Name(), rigName)
+			if !e.IsDir() || !isGitWorktree(path) {
+				continue
+			}
+			targets = append(targets, workspaceEnvTarget{
+				path: path,
+				env:  rig.WorkspaceEnv(townRoot, rigName, "polecat", e.Name(), path),
+			})
+		}
+	}
+	return targets
+}
+
+// isGitWorktree reports whether path has a .git file or directory.
+func isGitWorktree(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
@@ -71,6 +71,11 @@ func (c *ZombieSessionCheck) Run(ctx *CheckContext) *CheckResult {
 			continue
 		}
 
+		// In a rig-scoped run, only the rig's own agents
+		if !ctx.IncludeTown() && !sessionInRig(sess, ctx.RigName) {
+			continue
+		}
+
 		// Check if Claude is running in this session
 		if t.IsAgentAlive(sess) {
 			healthyCount++
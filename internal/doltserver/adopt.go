@@ -0,0 +1,250 @@
+package doltserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// schemaUpgradeTimeout bounds bd migrate on an adopted database.
+const schemaUpgradeTimeout = 5 * time.Minute
+
+// beadsSchema lists the tables and columns bd needs, by table. The issues
+// table is required as is; bd's schema upgrade creates the others.
+var beadsSchema = map[string][]string{
+	"issues":       {"id", "title", "status", "priority", "issue_type", "created_at", "updated_at"},
+	"labels":       {"issue_id", "label"},
+	"dependencies": {"issue_id", "depends_on_id", "type"},
+	"config":       {"key", "value"},
+}
+
+// SchemaCheck compares a database's schema with what bd expects.
+type SchemaCheck struct {
+	// Missing lists absent tables ("labels") and columns ("issues.status")
+	// that bd's schema upgrade can add.
+	Missing []string `json:"missing,omitempty"`
+
+	// Incompatible lists problems no upgrade fixes: a missing issues table
+	// or core issues column, which means this isn't a beads database.
+	Incompatible []string `json:"incompatible,omitempty"`
+}
+
+// OK reports whether the schema matches bd's expectations.
+func (c *SchemaCheck) OK() bool {
+	return len(c.Missing) == 0 && len(c.Incompatible) == 0
+}
+
+// checkBeadsSchema compares the table and column rows (tbl, col) returned
+// by query against beadsSchema.
+func checkBeadsSchema(query func(string) ([][]string, error)) (*SchemaCheck, error) {
+	rows, err := query("SELECT table_name AS tbl, column_name AS col FROM information_schema.columns WHERE table_schema = DATABASE()")
+	if err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+	have := map[string]map[string]bool{}
+	for _, rec := range csvRecords(rows) {
+		tbl := strings.ToLower(rec["tbl"])
+		if have[tbl] == nil {
+			have[tbl] = map[string]bool{}
+		}
+		have[tbl][strings.ToLower(rec["col"])] = true
+	}
+
+	check := &SchemaCheck{}
+	tables := make([]string, 0, len(beadsSchema))
+	for t := range beadsSchema {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		cols, ok := have[table]
+		switch {
+		case !ok && table == "issues":
+			check.Incompatible = append(check.Incompatible, "no issues table")
+			continue
+		case !ok:
+			check.Missing = append(check.Missing, table)
+			continue
+		}
+		for _, col := range beadsSchema[table] {
+			if cols[col] {
+				continue
+			}
+			if table == "issues" {
+				check.Incompatible = append(check.Incompatible, table+"."+col)
+			} else {
+				check.Missing = append(check.Missing, table+"."+col)
+			}
+		}
+	}
+	return check, nil
+}
+
+// AdoptOptions controls AdoptExternalDatabase.
+type AdoptOptions struct {
+	// Link symlinks .dolt-data/<rig> to the database instead of moving it.
+	Link bool
+
+	// Prefix is the rig's issue prefix; by default it is read from the
+	// database's issue_prefix config, falling back to the rig name.
+	Prefix string
+
+	// Force adopts a database whose schema is not bd's.
+	Force bool
+}
+
+// AdoptResult describes an adopted database.
+type AdoptResult struct {
+	Rig      string       `json:"rig"`
+	Source   string       `json:"source"`
+	Target   string       `json:"target"`
+	Linked   bool         `json:"linked"`
+	Prefix   string       `json:"prefix"`
+	BeadsDir string       `json:"beads_dir"`
+	Schema   *SchemaCheck `json:"schema"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+// AdoptExternalDatabase registers an existing Dolt database, such as a
+// clone from DoltHub, as a rig: it checks the schema against bd's, moves
+// (or with opts.Link, symlinks) the database to .dolt-data/<rig>, registers
+// the rig in rigs.json with its issue prefix, adds a routes.jsonl entry, and
+// writes the rig's metadata.json.
+//
+// The Dolt server must be stopped, as it only discovers databases at
+// startup. A database whose issues table is missing or lacks core columns
+// is refused unless opts.Force is set; tables and columns bd's schema
+// upgrade can add are reported in the result's Schema.
+func AdoptExternalDatabase(townRoot, rigName, source string, opts AdoptOptions) (*AdoptResult, error) {
+	if err := validateRigName(rigName); err != nil {
+		return nil, err
+	}
+	if err := rigsconfig.ValidateName(rigName); err != nil {
+		return nil, err
+	}
+	if running, _, _ := IsRunning(townRoot); running {
+		return nil, fmt.Errorf("the Dolt server is running; stop it before adopting a database")
+	}
+
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(source, ".dolt")); err != nil {
+		return nil, fmt.Errorf("%s is not a Dolt database (no .dolt directory)", source)
+	}
+	dataDir := DefaultConfig(townRoot).DataDir
+	if rel, err := filepath.Rel(dataDir, source); err == nil && !strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("%s is already in the data directory %s", source, dataDir)
+	}
+	target := RigDatabaseDir(townRoot, rigName)
+	if _, err := os.Lstat(target); err == nil {
+		return nil, fmt.Errorf("database %q already exists at %s", rigName, target)
+	}
+
+	rigs, err := rigsconfig.Load(townRoot)
+	if errors.Is(err, rigsconfig.ErrNotFound) {
+		rigs = &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	} else if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	if _, taken := rigs.Rigs[rigName]; taken {
+		return nil, fmt.Errorf("rig %q is already registered", rigName)
+	}
+
+	query := func(q string) ([][]string, error) { return embeddedQueryCSV(source, q) }
+	schema, err := checkBeadsSchema(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(schema.Incompatible) > 0 && !opts.Force {
+		return nil, fmt.Errorf("%s doesn't look like a beads database (%s); use --force to adopt it anyway",
+			source, strings.Join(schema.Incompatible, ", "))
+	}
+
+	prefix := strings.TrimSuffix(opts.Prefix, "-")
+	if prefix == "" {
+		prefix = embeddedIssuePrefix(query)
+	}
+	if prefix == "" {
+		prefix = rigName
+	}
+	if owner := rigsconfig.RigForPrefix(rigs, prefix); owner != "" {
+		return nil, fmt.Errorf("issue prefix %q is already used by rig %q; choose another with --prefix", prefix, owner)
+	}
+
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating data directory: %w", err)
+	}
+	if opts.Link {
+		err = os.Symlink(source, target)
+	} else {
+		err = moveDir(source, target)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("adding database to %s: %w", dataDir, err)
+	}
+	result := &AdoptResult{Rig: rigName, Source: source, Target: target, Linked: opts.Link, Prefix: prefix, Schema: schema}
+
+	rigs.Rigs[rigName] = config.RigEntry{
+		AddedAt:     clk.Now(),
+		BeadsConfig: &config.BeadsConfig{Prefix: prefix},
+	}
+	if err := rigsconfig.Save(townRoot, rigs); err != nil {
+		return result, fmt.Errorf("database added but registering the rig failed: %w", err)
+	}
+
+	if err := EnsureMetadata(townRoot, rigName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("writing metadata.json: %v", err))
+	}
+	result.BeadsDir = FindRigBeadsDir(townRoot, rigName)
+	route := beads.Route{Prefix: prefix + "-", Path: filepath.ToSlash(filepath.Join(rigName, "mayor", "rig"))}
+	if err := beads.AppendRoute(townRoot, route); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("updating routes.jsonl: %v", err))
+	}
+	return result, nil
+}
+
+// embeddedIssuePrefix is issuePrefix through query, returning "" if the
+// database has none.
+func embeddedIssuePrefix(query func(string) ([][]string, error)) string {
+	rows, err := query("SELECT `value` AS prefix FROM config WHERE `key` = 'issue_prefix'")
+	if err != nil {
+		return ""
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSpace(recs[0]["prefix"]), "-")
+}
+
+// UpgradeAdoptedSchema runs bd's schema upgrade (bd migrate) against an
+// adopted rig through the running server, then checks the schema again.
+func UpgradeAdoptedSchema(townRoot, rigName string) (*SchemaCheck, error) {
+	if running, _, _ := IsRunning(townRoot); !running {
+		return nil, fmt.Errorf("the schema upgrade needs the Dolt server; run 'gt dolt start'")
+	}
+	beadsDir := FindRigBeadsDir(townRoot, rigName)
+	ctx, cancel := context.WithTimeout(context.Background(), schemaUpgradeTimeout)
+	defer cancel()
+	if stdout, stderr, err := runner.Run(ctx, proc.Cmd{
+		Name: "bd",
+		Args: []string{"migrate"},
+		Dir:  filepath.Dir(beadsDir),
+		Env:  append(os.Environ(), "BEADS_DIR="+beadsDir),
+	}); err != nil {
+		return nil, fmt.Errorf("bd migrate: %w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
+	}
+	return checkBeadsSchema(func(q string) ([][]string, error) { return doltQueryCSV(townRoot, rigName, q) })
+}
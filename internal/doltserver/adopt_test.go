@@ -0,0 +1,106 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// fakeSchema answers schema and issue_prefix queries for an external database.
+func fakeSchema(columns, prefix string) func() {
+	return SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		if strings.Contains(query, "issue_prefix") {
+			return []byte("prefix\n" + prefix + "\n"), nil, nil
+		}
+		return []byte("tbl,col\n" + columns), nil, nil
+	}})
+}
+
+const beadsColumns = "issues,id\nissues,title\nissues,status\nissues,priority\nissues,issue_type\nissues,created_at\nissues,updated_at\n" +
+	"labels,issue_id\nlabels,label\ndependencies,issue_id\ndependencies,depends_on_id\n"
+
+func TestAdoptExternalDatabase(t *testing.T) {
+	defer fakeSchema(beadsColumns, "ar-")()
+	townRoot := t.TempDir()
+	setupRigsJSON(t, townRoot, []string{"gastown"})
+	source := setupDoltDB(t, t.TempDir(), "clone")
+
+	if _, err := AdoptExternalDatabase(townRoot, "hq", source, AdoptOptions{}); err == nil {
+		t.Error("adopting as hq succeeded")
+	}
+	if _, err := AdoptExternalDatabase(townRoot, "gastown", source, AdoptOptions{}); err == nil {
+		t.Error("adopting as a registered rig succeeded")
+	}
+
+	result, err := AdoptExternalDatabase(townRoot, "archive", source, AdoptOptions{})
+	if err != nil {
+		t.Fatalf("AdoptExternalDatabase: %v", err)
+	}
+	if len(result.Warnings) > 0 {
+		t.Errorf("warnings: %v", result.Warnings)
+	}
+	if !DatabaseExists(townRoot, "archive") {
+		t.Error("database was not moved into .dolt-data")
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("source still present after move: %v", err)
+	}
+	if result.Prefix != "ar" {
+		t.Errorf("prefix = %q, want ar from issue_prefix", result.Prefix)
+	}
+	if want := []string{"config", "dependencies.type"}; !reflect.DeepEqual(result.Schema.Missing, want) {
+		t.Errorf("missing = %v, want %v", result.Schema.Missing, want)
+	}
+
+	rigs, err := rigsconfig.Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rigsconfig.Prefix(rigs, "archive") != "ar" {
+		t.Errorf("rigs.json entry = %+v", rigs.Rigs["archive"])
+	}
+	if db := readExistingDoltDatabase(FindRigBeadsDir(townRoot, "archive")); db != "archive" {
+		t.Errorf("metadata dolt_database = %q, want archive", db)
+	}
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil || len(routes) != 1 || routes[0] != (beads.Route{Prefix: "ar-", Path: "archive/mayor/rig"}) {
+		t.Errorf("routes = %+v, %v", routes, err)
+	}
+
+	// A second database claiming the same prefix is refused.
+	other := setupDoltDB(t, t.TempDir(), "other")
+	if _, err := AdoptExternalDatabase(townRoot, "other", other, AdoptOptions{}); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("duplicate prefix: err = %v", err)
+	}
+}
+
+func TestAdoptExternalDatabaseLinkAndSchema(t *testing.T) {
+	defer fakeSchema("widgets,id\nwidgets,name\n", "")()
+	townRoot := t.TempDir()
+	source := setupDoltDB(t, t.TempDir(), "widgets")
+
+	if _, err := AdoptExternalDatabase(townRoot, "widgets", source, AdoptOptions{}); err == nil || !strings.Contains(err.Error(), "no issues table") {
+		t.Fatalf("non-beads database: err = %v", err)
+	}
+
+	result, err := AdoptExternalDatabase(townRoot, "widgets", source, AdoptOptions{Link: true, Force: true, Prefix: "wd"})
+	if err != nil {
+		t.Fatalf("AdoptExternalDatabase --link --force: %v", err)
+	}
+	if target, err := os.Readlink(result.Target); err != nil || target != source {
+		t.Errorf("link = %q, %v; want %s", target, err, source)
+	}
+	if !DatabaseExists(townRoot, "widgets") {
+		t.Error("linked database not visible in .dolt-data")
+	}
+	if result.Prefix != "wd" || len(result.Schema.Incompatible) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,219 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// analyticsExportTimeout bounds a single table export query.
+const analyticsExportTimeout = 5 * time.Minute
+
+// Analytics export formats. Dolt writes both natively via dolt sql -r.
+const (
+	AnalyticsFormatParquet = "parquet"
+	AnalyticsFormatCSV     = "csv"
+)
+
+// AnalyticsWatermarkFile is the name of the incremental-export state file
+// kept in the export directory.
+const AnalyticsWatermarkFile = ".watermarks.json"
+
+// analyticsTable describes one exportable table: the beads table it reads,
+// the rows it selects, and the timestamp column used as the watermark.
+type analyticsTable struct {
+	table     string
+	where     string
+	watermark string
+}
+
+// analyticsTables are the exportable tables by name. Issues and wisps both
+// live in the issues table; wisps are the ephemeral rows. Audit is the
+// beads events table.
+var analyticsTables = map[string]analyticsTable{
+	"issues": {table: "issues", where: "COALESCE(ephemeral, 0) = 0", watermark: "updated_at"},
+	"wisps":  {table: "issues", where: "ephemeral = 1", watermark: "updated_at"},
+	"audit":  {table: "events", where: "1 = 1", watermark: "created_at"},
+}
+
+// AnalyticsExportDir returns the default analytics export directory.
+func AnalyticsExportDir(townRoot string) string {
+	return filepath.Join(townRoot, "exports", "analytics")
+}
+
+// AnalyticsTables lists the exportable table names in export order.
+var AnalyticsTables = []string{"issues", "wisps", "audit"}
+
+// AnalyticsExportOptions configures ExportAnalytics.
+type AnalyticsExportOptions struct {
+	// OutDir is the export root (default: AnalyticsExportDir).
+	// Files go to <OutDir>/<db>/<table>/.
+	OutDir string
+
+	// Tables to export (default: all of AnalyticsTables).
+	Tables []string
+
+	// Format is parquet (default) or csv.
+	Format string
+
+	// Incremental exports only rows changed since the last export to OutDir.
+	Incremental bool
+}
+
+// AnalyticsExportResult describes one table exported from one database.
+type AnalyticsExportResult struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	Path     string `json:"path,omitempty"` // Empty when there were no rows to export
+	Rows     int64  `json:"rows"`
+	Since    string `json:"since,omitempty"` // Exclusive lower watermark (incremental only)
+	Until    string `json:"until,omitempty"` // Inclusive upper watermark
+}
+
+// analyticsWatermarks maps "<db>/<table>" to the highest watermark exported.
+type analyticsWatermarks map[string]string
+
+// ExportAnalytics writes the selected tables of a rig database to files for
+// loading into a warehouse (DuckDB, BigQuery, ...). Each run writes one file
+// per table, named by export time, so a directory of runs can be read with
+// a glob such as <OutDir>/*/issues/*.parquet.
+//
+// In incremental mode only rows whose watermark column (updated_at, or
+// created_at for audit) is newer than the previous export to OutDir are
+// written. An updated row is exported again, so consumers should keep the
+// latest row per id. Watermarks are saved only after a file is written.
+func ExportAnalytics(townRoot, rigDB string, opts AnalyticsExportOptions) ([]AnalyticsExportResult, error) {
+	if opts.OutDir == "" {
+		opts.OutDir = AnalyticsExportDir(townRoot)
+	}
+	format := opts.Format
+	if format == "" {
+		format = AnalyticsFormatParquet
+	}
+	if format != AnalyticsFormatParquet && format != AnalyticsFormatCSV {
+		return nil, fmt.Errorf("unknown export format %q (want %s or %s)", format, AnalyticsFormatParquet, AnalyticsFormatCSV)
+	}
+	tables := opts.Tables
+	if len(tables) == 0 {
+		tables = AnalyticsTables
+	}
+	for _, name := range tables {
+		if _, ok := analyticsTables[name]; !ok {
+			return nil, fmt.Errorf("unknown export table %q (want %s)", name, strings.Join(AnalyticsTables, ", "))
+		}
+	}
+
+	marks, err := loadAnalyticsWatermarks(opts.OutDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := clk.Now().UTC().Format("20060102T150405Z")
+	var results []AnalyticsExportResult
+	for _, name := range tables {
+		t := analyticsTables[name]
+		key := rigDB + "/" + name
+		res := AnalyticsExportResult{Database: rigDB, Table: name}
+
+		where := t.where
+		if opts.Incremental && marks[key] != "" {
+			res.Since = marks[key]
+			where += fmt.Sprintf(" AND `%s` > '%s'", t.watermark, sqlEscape(res.Since))
+		}
+
+		// Fix the upper bound first so rows written during the export are
+		// left for the next run rather than skipped.
+		rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+			"SELECT COUNT(*) AS n, MAX(`%s`) AS hi FROM `%s` WHERE %s", t.watermark, t.table, where))
+		if err != nil {
+			return results, fmt.Errorf("counting %s in %s: %w", name, rigDB, err)
+		}
+		if recs := csvRecords(rows); len(recs) > 0 {
+			res.Rows, _ = strconv.ParseInt(recs[0]["n"], 10, 64)
+			res.Until = recs[0]["hi"]
+		}
+		if res.Rows == 0 {
+			results = append(results, res)
+			continue
+		}
+		where += fmt.Sprintf(" AND `%s` <= '%s'", t.watermark, sqlEscape(res.Until))
+
+		path := filepath.Join(opts.OutDir, rigDB, name, fmt.Sprintf("%s-%s.%s", name, stamp, format))
+		query := fmt.Sprintf("SELECT * FROM `%s`.`%s` WHERE %s ORDER BY `%s`", rigDB, t.table, where, t.watermark)
+		if err := exportQuery(townRoot, query, format, path); err != nil {
+			return results, fmt.Errorf("exporting %s from %s: %w", name, rigDB, err)
+		}
+		res.Path = path
+		results = append(results, res)
+
+		marks[key] = res.Until
+		if err := saveAnalyticsWatermarks(opts.OutDir, marks); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// exportQuery runs query on the server and writes its result in format to
+// path, replacing the file only once the whole result has been written.
+func exportQuery(townRoot, query, format, path string) error {
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), analyticsExportTimeout)
+	defer cancel()
+
+	output, stderr, err := runDolt(ctx, config.DataDir, "sql", "-r", format, "-q", query)
+	if err != nil {
+		if len(stderr) > 0 {
+			return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stderr)))
+		}
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, output, 0644); err != nil { //nolint:gosec // G306: exports are meant to be shared
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func loadAnalyticsWatermarks(outDir string) (analyticsWatermarks, error) {
+	marks := make(analyticsWatermarks)
+	data, err := os.ReadFile(filepath.Join(outDir, AnalyticsWatermarkFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return marks, nil
+		}
+		return nil, fmt.Errorf("reading export watermarks: %w", err)
+	}
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", AnalyticsWatermarkFile, err)
+	}
+	return marks, nil
+}
+
+func saveAnalyticsWatermarks(outDir string, marks analyticsWatermarks) error {
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, AnalyticsWatermarkFile), append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: not sensitive
+		return fmt.Errorf("saving export watermarks: %w", err)
+	}
+	return nil
+}
+
+// sqlEscape escapes a value for use inside a single-quoted SQL string.
+func sqlEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", "''")
+}
@@ -0,0 +1,81 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestExportAnalytics_Incremental(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)))()
+	var queries []string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		queries = append(queries, query)
+		if strings.Contains(query, "COUNT(*)") {
+			return []byte("n,hi\n2,2026-03-01 08:30:00\n"), nil, nil
+		}
+		return []byte("PAR1 rows"), nil, nil
+	}})()
+
+	townRoot := t.TempDir()
+	outDir := filepath.Join(townRoot, "out")
+	opts := AnalyticsExportOptions{OutDir: outDir, Tables: []string{"issues"}, Incremental: true}
+
+	results, err := ExportAnalytics(townRoot, "gastown", opts)
+	if err != nil {
+		t.Fatalf("ExportAnalytics: %v", err)
+	}
+	want := filepath.Join(outDir, "gastown", "issues", "issues-20260301T090000Z.parquet")
+	if len(results) != 1 || results[0].Path != want || results[0].Rows != 2 || results[0].Since != "" {
+		t.Fatalf("results = %+v, want 2 rows in %s", results, want)
+	}
+	if data, err := os.ReadFile(want); err != nil || string(data) != "PAR1 rows" {
+		t.Errorf("export file = %q, %v", data, err)
+	}
+	if !strings.Contains(queries[1], "`updated_at` <= '2026-03-01 08:30:00'") {
+		t.Errorf("export query not bounded by the counted watermark: %s", queries[1])
+	}
+
+	// The next run only asks for rows past the saved watermark
+	queries = nil
+	results, err = ExportAnalytics(townRoot, "gastown", opts)
+	if err != nil {
+		t.Fatalf("second ExportAnalytics: %v", err)
+	}
+	if results[0].Since != "2026-03-01 08:30:00" || !strings.Contains(queries[0], "`updated_at` > '2026-03-01 08:30:00'") {
+		t.Errorf("second run since = %q, count query = %s", results[0].Since, queries[0])
+	}
+}
+
+func TestExportAnalytics_NothingNew(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return []byte("n,hi\n0,\n"), nil, nil
+	}})()
+
+	outDir := t.TempDir()
+	results, err := ExportAnalytics(t.TempDir(), "gastown", AnalyticsExportOptions{OutDir: outDir, Tables: []string{"audit"}})
+	if err != nil {
+		t.Fatalf("ExportAnalytics: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "" || results[0].Rows != 0 {
+		t.Errorf("results = %+v, want no file", results)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, AnalyticsWatermarkFile)); !os.IsNotExist(err) {
+		t.Errorf("watermarks written for an empty export: %v", err)
+	}
+}
+
+func TestExportAnalytics_RejectsUnknownTableAndFormat(t *testing.T) {
+	if _, err := ExportAnalytics(t.TempDir(), "gastown", AnalyticsExportOptions{Tables: []string{"labels"}}); err == nil {
+		t.Error("expected error for unknown table")
+	}
+	if _, err := ExportAnalytics(t.TempDir(), "gastown", AnalyticsExportOptions{Format: "xlsx"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
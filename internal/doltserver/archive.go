@@ -0,0 +1,220 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ArchiveTable is the per-rig table that closed wisps and digests are moved
+// to once they are old enough. It has the issues table's columns plus
+// archived_at.
+const ArchiveTable = "issues_archive"
+
+// DefaultArchiveAfterDays is how long closed wisps and digests stay in the
+// issues table before gt dolt archive moves them out. It is shorter than the
+// closed-wisp prune retention so archiving gets to them first.
+const DefaultArchiveAfterDays = 3
+
+// ArchiveAfterDays returns the archive age from cfg, or the default.
+func ArchiveAfterDays(cfg *config.DoltStatsConfig) int {
+	if cfg != nil && cfg.Retention != nil && cfg.Retention.ArchiveDays > 0 {
+		return cfg.Retention.ArchiveDays
+	}
+	return DefaultArchiveAfterDays
+}
+
+// archivableCondition selects closed wisps and digests closed before cutoff.
+// Squashed-molecule digests are ephemeral; daily patrol and cost digests are
+// persistent beads labeled "digest".
+func archivableCondition(cutoff string) string {
+	return fmt.Sprintf("status = 'closed' AND closed_at < '%s' AND "+
+		"(ephemeral = 1 OR id IN (SELECT issue_id FROM labels WHERE label = 'digest'))", sqlEscape(cutoff))
+}
+
+// ArchiveResult describes beads archived (or archivable) in one database.
+type ArchiveResult struct {
+	Database  string `json:"database"`
+	Days      int    `json:"archive_after_days"`
+	Rows      int64  `json:"rows"`
+	Committed bool   `json:"committed,omitempty"`
+}
+
+// ArchiveClosed moves closed wisps and digests closed more than days ago
+// from the issues table into ArchiveTable, creating it on first use, and
+// commits. With dryRun, only counts the beads. Labels, dependencies, and
+// events of archived beads go with them through the beads schema's ON
+// DELETE CASCADE foreign keys; the archive keeps the bead rows themselves,
+// which is what gt search --archived reads.
+func ArchiveClosed(townRoot, rigDB string, days int, dryRun bool) (*ArchiveResult, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("archive age must be at least 1 day, got %d", days)
+	}
+	result := &ArchiveResult{Database: rigDB, Days: days}
+
+	// A fixed cutoff (not NOW()) so the copy and the delete select the
+	// same rows.
+	now := clk.Now().UTC()
+	cutoff := now.Add(-time.Duration(days) * 24 * time.Hour).Format("2006-01-02 15:04:05")
+	where := archivableCondition(cutoff)
+
+	rows, err := doltQueryCSV(townRoot, rigDB, "SELECT COUNT(*) AS n FROM issues WHERE "+where)
+	if err != nil {
+		return nil, fmt.Errorf("counting archivable beads in %s: %w", rigDB, err)
+	}
+	if recs := csvRecords(rows); len(recs) > 0 {
+		result.Rows, _ = strconv.ParseInt(recs[0]["n"], 10, 64)
+	}
+	if dryRun || result.Rows == 0 {
+		return result, nil
+	}
+
+	cols, err := ensureArchiveTable(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = "`" + c.Name + "`"
+	}
+	list := strings.Join(names, ", ")
+
+	msg := fmt.Sprintf("gt dolt archive: %d closed wisps/digests older than %d days", result.Rows, days)
+	script := fmt.Sprintf("REPLACE INTO `%s` (%s, archived_at) SELECT %s, '%s' FROM issues WHERE %s; "+
+		"DELETE FROM issues WHERE %s; CALL DOLT_COMMIT('-Am', '%s')",
+		ArchiveTable, list, list, now.Format("2006-01-02 15:04:05"), where, where, msg)
+	if _, err := doltQueryCSV(townRoot, rigDB, script); err != nil {
+		return nil, fmt.Errorf("archiving beads in %s: %w", rigDB, err)
+	}
+	result.Committed = true
+	return result, nil
+}
+
+// ensureArchiveTable creates ArchiveTable in rigDB if needed and returns the
+// issues columns it can hold. Columns added to issues after the archive was
+// created are not archived.
+func ensureArchiveTable(townRoot, rigDB string) ([]tableColumn, error) {
+	exists, err := tableExists(townRoot, rigDB, ArchiveTable)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		// CREATE TABLE ... LIKE copies columns and keys but not the foreign
+		// keys, so archived rows don't depend on anything in the hot tables.
+		if _, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+			"CREATE TABLE `%s` LIKE issues; ALTER TABLE `%s` ADD COLUMN archived_at DATETIME",
+			ArchiveTable, ArchiveTable)); err != nil {
+			return nil, fmt.Errorf("creating %s in %s: %w", ArchiveTable, rigDB, err)
+		}
+	}
+
+	issueCols, err := tableColumns(townRoot, rigDB, "issues")
+	if err != nil {
+		return nil, err
+	}
+	archiveCols, err := tableColumns(townRoot, rigDB, ArchiveTable)
+	if err != nil {
+		return nil, err
+	}
+	var cols []tableColumn
+	for _, c := range sharedColumns(archiveCols, issueCols) {
+		if c.Name != "archived_at" {
+			cols = append(cols, c)
+		}
+	}
+	return cols, nil
+}
+
+// tableExists reports whether rigDB has a table named table.
+func tableExists(townRoot, rigDB, table string) (bool, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT COUNT(*) AS n FROM information_schema.tables WHERE table_schema = '%s' AND table_name = '%s'",
+		sqlEscape(rigDB), sqlEscape(table)))
+	if err != nil {
+		return false, fmt.Errorf("checking for %s in %s: %w", table, rigDB, err)
+	}
+	recs := csvRecords(rows)
+	return len(recs) > 0 && recs[0]["n"] != "0", nil
+}
+
+// SearchHit is a bead matching a search, from the issues table or the archive.
+type SearchHit struct {
+	Database   string `json:"database"`
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	Type       string `json:"type"`
+	ClosedAt   string `json:"closed_at,omitempty"`
+	Archived   bool   `json:"archived,omitempty"`
+	ArchivedAt string `json:"archived_at,omitempty"`
+}
+
+// SearchOptions configures SearchBeads.
+type SearchOptions struct {
+	// Query matches the bead ID exactly, or a substring of the title or
+	// description (case-insensitive).
+	Query string
+
+	// Archived also searches ArchiveTable.
+	Archived bool
+
+	// Limit caps the hits per table (default 50).
+	Limit int
+}
+
+// SearchBeads finds beads in rigDB matching opts.Query, most recently
+// updated first. Archived hits follow the live ones.
+func SearchBeads(townRoot, rigDB string, opts SearchOptions) ([]SearchHit, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, fmt.Errorf("empty search query")
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	q := sqlEscape(opts.Query)
+	like := strings.NewReplacer("%", `\%`, "_", `\_`).Replace(strings.ToLower(q))
+	match := fmt.Sprintf("(id = '%s' OR LOWER(title) LIKE '%%%s%%' OR LOWER(description) LIKE '%%%s%%')", q, like, like)
+
+	hits, err := searchTable(townRoot, rigDB, "issues", "'' AS archived_at", match, limit)
+	if err != nil || !opts.Archived {
+		return hits, err
+	}
+	exists, err := tableExists(townRoot, rigDB, ArchiveTable)
+	if err != nil || !exists {
+		return hits, err
+	}
+	archived, err := searchTable(townRoot, rigDB, ArchiveTable, "archived_at", match, limit)
+	if err != nil {
+		return hits, err
+	}
+	for i := range archived {
+		archived[i].Archived = true
+	}
+	return append(hits, archived...), nil
+}
+
+func searchTable(townRoot, rigDB, table, archivedAt, match string, limit int) ([]SearchHit, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT id, title, status, issue_type, COALESCE(closed_at, '') AS closed_at, %s "+
+			"FROM `%s` WHERE %s ORDER BY updated_at DESC LIMIT %d", archivedAt, table, match, limit))
+	if err != nil {
+		return nil, fmt.Errorf("searching %s in %s: %w", table, rigDB, err)
+	}
+	var hits []SearchHit
+	for _, rec := range csvRecords(rows) {
+		hits = append(hits, SearchHit{
+			Database:   rigDB,
+			ID:         rec["id"],
+			Title:      rec["title"],
+			Status:     rec["status"],
+			Type:       rec["issue_type"],
+			ClosedAt:   rec["closed_at"],
+			ArchivedAt: rec["archived_at"],
+		})
+	}
+	return hits, nil
+}
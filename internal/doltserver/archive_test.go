@@ -0,0 +1,115 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestArchiveClosed(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+	var queries []string
+	archiveExists := false
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		queries = append(queries, query)
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			if archiveExists {
+				return []byte("n\n1\n"), nil, nil
+			}
+			return []byte("n\n0\n"), nil, nil
+		case strings.Contains(query, "CREATE TABLE"):
+			archiveExists = true
+			return nil, nil, nil
+		case strings.Contains(query, "information_schema.columns") && strings.Contains(query, ArchiveTable):
+			return []byte("name,extra\nid,\ntitle,\nstatus,\narchived_at,\n"), nil, nil
+		case strings.Contains(query, "information_schema.columns"):
+			return []byte("name,extra\nid,\ntitle,\nstatus,\nnew_col,\n"), nil, nil
+		case strings.Contains(query, "COUNT(*)"):
+			return []byte("n\n4\n"), nil, nil
+		}
+		return nil, nil, nil
+	}})()
+
+	res, err := ArchiveClosed(t.TempDir(), "gastown", 3, false)
+	if err != nil {
+		t.Fatalf("ArchiveClosed: %v", err)
+	}
+	if res.Rows != 4 || !res.Committed {
+		t.Errorf("result = %+v, want 4 rows committed", res)
+	}
+	if !strings.Contains(queries[0], "closed_at < '2026-03-07 12:00:00'") {
+		t.Errorf("count query has wrong cutoff: %s", queries[0])
+	}
+
+	move := queries[len(queries)-1]
+	for _, want := range []string{
+		"REPLACE INTO `issues_archive` (`id`, `title`, `status`, archived_at) SELECT `id`, `title`, `status`, '2026-03-10 12:00:00'",
+		"DELETE FROM issues WHERE status = 'closed' AND closed_at < '2026-03-07 12:00:00'",
+		"CALL DOLT_COMMIT",
+	} {
+		if !strings.Contains(move, want) {
+			t.Errorf("archive script missing %q:\n%s", want, move)
+		}
+	}
+}
+
+func TestArchiveClosed_DryRun(t *testing.T) {
+	var queries int
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		queries++
+		return []byte("n\n7\n"), nil, nil
+	}})()
+
+	res, err := ArchiveClosed(t.TempDir(), "gastown", 3, true)
+	if err != nil {
+		t.Fatalf("ArchiveClosed: %v", err)
+	}
+	if res.Rows != 7 || res.Committed || queries != 1 {
+		t.Errorf("dry run = %+v after %d queries, want 7 rows counted in one query", res, queries)
+	}
+	if _, err := ArchiveClosed(t.TempDir(), "gastown", 0, true); err == nil {
+		t.Error("expected error for 0 days")
+	}
+}
+
+func TestSearchBeads_Archived(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			return []byte("n\n1\n"), nil, nil
+		case strings.Contains(query, "FROM `issues_archive`"):
+			return []byte("id,title,status,issue_type,closed_at,archived_at\ngt-w1,Patrol 100%,closed,task,2026-03-01,2026-03-05\n"), nil, nil
+		default:
+			return []byte("id,title,status,issue_type,closed_at,archived_at\ngt-a1,Patrol fix,open,bug,,\n"), nil, nil
+		}
+	}})()
+
+	hits, err := SearchBeads(t.TempDir(), "gastown", SearchOptions{Query: "patrol", Archived: true})
+	if err != nil {
+		t.Fatalf("SearchBeads: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Archived || !hits[1].Archived || hits[1].ArchivedAt != "2026-03-05" {
+		t.Errorf("hits = %+v, want one live then one archived", hits)
+	}
+
+	if _, err := SearchBeads(t.TempDir(), "gastown", SearchOptions{Query: "  "}); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestArchiveAfterDays(t *testing.T) {
+	if got := ArchiveAfterDays(nil); got != DefaultArchiveAfterDays {
+		t.Errorf("ArchiveAfterDays(nil) = %d", got)
+	}
+	cfg := &config.DoltStatsConfig{Retention: &config.DoltRetentionConfig{ArchiveDays: 10}}
+	if got := ArchiveAfterDays(cfg); got != 10 {
+		t.Errorf("ArchiveAfterDays = %d, want 10", got)
+	}
+}
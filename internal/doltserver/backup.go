@@ -0,0 +1,287 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DataBackup is a snapshot of every database in the data directory, taken
+// by gt dolt backup. Unlike the migration backups of gt dolt rollback, it
+// holds the Dolt databases themselves, history included.
+type DataBackup struct {
+	// Timestamp names the backup: YYYYMMDD-HHMMSS, plus "-pre-restore" for
+	// the backup RestoreDataBackup takes of the data it replaces.
+	Timestamp string `json:"timestamp"`
+
+	// Path is the backup directory, <town>/.dolt-backups/<timestamp>.
+	Path string `json:"path"`
+
+	CreatedAt time.Time `json:"created_at"`
+	Databases []string  `json:"databases"`
+	SizeBytes int64     `json:"size_bytes"`
+}
+
+// dataBackupManifest is the file in each backup directory describing it.
+const dataBackupManifest = "backup.json"
+
+// preRestoreSuffix marks the backup taken before a restore.
+const preRestoreSuffix = "-pre-restore"
+
+// DataBackupsDir returns where gt dolt backup stores its snapshots.
+func DataBackupsDir(townRoot string) string {
+	return filepath.Join(townRoot, ".dolt-backups")
+}
+
+// CreateDataBackup copies every database in the data directory to
+// .dolt-backups/<timestamp>, then prunes all but the newest keep backups
+// (Config.BackupRetention if keep is 0) and returns the new backup and the
+// pruned ones. The databases are copied with the server stopped, so each
+// copy is consistent; a running server is stopped first and restarted
+// afterwards.
+func CreateDataBackup(townRoot string, keep int) (*DataBackup, []DataBackup, error) {
+	restart, err := pauseServer(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := copyDataDir(townRoot, clk.Now().UTC().Format("20060102-150405"))
+	if rerr := resumeServer(townRoot, restart); rerr != nil {
+		return b, nil, errors.Join(err, rerr)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if keep == 0 {
+		keep = DefaultConfig(townRoot).BackupRetention
+	}
+	pruned, err := PruneDataBackups(townRoot, keep)
+	if err != nil {
+		return b, pruned, fmt.Errorf("pruning old backups: %w", err)
+	}
+	return b, pruned, nil
+}
+
+// ListDataBackups returns the backups in .dolt-backups, newest first.
+// Directories without a readable manifest are skipped.
+func ListDataBackups(townRoot string) ([]DataBackup, error) {
+	entries, err := os.ReadDir(DataBackupsDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var backups []DataBackup
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		b, err := loadDataBackup(filepath.Join(DataBackupsDir(townRoot), e.Name()))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, *b)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp > backups[j].Timestamp
+	})
+	return backups, nil
+}
+
+// FindDataBackup returns the backup named timestamp.
+func FindDataBackup(townRoot, timestamp string) (*DataBackup, error) {
+	if err := validateBackupName(timestamp); err != nil {
+		return nil, err
+	}
+	b, err := loadDataBackup(filepath.Join(DataBackupsDir(townRoot), timestamp))
+	if err != nil {
+		return nil, fmt.Errorf("backup %s not found: %w", timestamp, err)
+	}
+	return b, nil
+}
+
+// validateBackupName rejects backup names that would escape .dolt-backups.
+func validateBackupName(timestamp string) error {
+	if timestamp == "" || strings.ContainsAny(timestamp, `/\`) || strings.HasPrefix(timestamp, ".") {
+		return fmt.Errorf("invalid backup name %q", timestamp)
+	}
+	return nil
+}
+
+// PruneDataBackups removes all but the newest keep backups and returns the
+// ones removed. Pre-restore backups count toward keep like any other.
+func PruneDataBackups(townRoot string, keep int) ([]DataBackup, error) {
+	if keep < 1 {
+		return nil, fmt.Errorf("retention must keep at least one backup, got %d", keep)
+	}
+	backups, err := ListDataBackups(townRoot)
+	if err != nil || len(backups) <= keep {
+		return nil, err
+	}
+	var removed []DataBackup
+	for _, b := range backups[keep:] {
+		if err := os.RemoveAll(b.Path); err != nil {
+			return removed, fmt.Errorf("removing backup %s: %w", b.Timestamp, err)
+		}
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
+
+// RestoreResult reports what RestoreDataBackup changed.
+type RestoreResult struct {
+	Backup     *DataBackup `json:"backup"`
+	PreRestore *DataBackup `json:"pre_restore,omitempty"`
+	Restored   []string    `json:"restored"`
+
+	// Kept lists databases created after the backup was taken. They have
+	// nothing to restore to and are left as they are.
+	Kept []string `json:"kept,omitempty"`
+}
+
+// RestoreDataBackup replaces the databases in the data directory with the
+// copies in backup timestamp. A running server is stopped first and
+// restarted afterwards. The current data is itself backed up (as
+// <now>-pre-restore) before anything is replaced, so a restore can be
+// undone by restoring that.
+func RestoreDataBackup(townRoot, timestamp string) (*RestoreResult, error) {
+	b, err := FindDataBackup(townRoot, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range b.Databases {
+		if _, err := os.Stat(filepath.Join(b.Path, db, ".dolt")); err != nil {
+			return nil, fmt.Errorf("backup %s is incomplete: database %s missing", timestamp, db)
+		}
+	}
+
+	restart, err := pauseServer(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	result, err := restoreDataDir(townRoot, b)
+	if rerr := resumeServer(townRoot, restart); rerr != nil {
+		return result, errors.Join(err, rerr)
+	}
+	return result, err
+}
+
+// restoreDataDir does the work of RestoreDataBackup with the server stopped.
+func restoreDataDir(townRoot string, b *DataBackup) (*RestoreResult, error) {
+	result := &RestoreResult{Backup: b}
+	pre, err := copyDataDir(townRoot, clk.Now().UTC().Format("20060102-150405")+preRestoreSuffix)
+	if err != nil {
+		return result, fmt.Errorf("backing up current data before restore: %w", err)
+	}
+	result.PreRestore = pre
+
+	dataDir := DefaultConfig(townRoot).DataDir
+	for _, db := range b.Databases {
+		if err := replaceDir(filepath.Join(dataDir, db), filepath.Join(b.Path, db)); err != nil {
+			return result, fmt.Errorf("restoring %s (current data is in %s): %w", db, pre.Path, err)
+		}
+		result.Restored = append(result.Restored, db)
+	}
+
+	inBackup := map[string]bool{}
+	for _, db := range b.Databases {
+		inBackup[db] = true
+	}
+	for _, db := range pre.Databases {
+		if !inBackup[db] {
+			result.Kept = append(result.Kept, db)
+		}
+	}
+	return result, nil
+}
+
+// copyDataDir copies every database in the data directory to a new backup
+// directory named name and writes its manifest. The server must not be
+// running.
+func copyDataDir(townRoot, name string) (*DataBackup, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no databases to back up in %s", DefaultConfig(townRoot).DataDir)
+	}
+
+	dir := filepath.Join(DataBackupsDir(townRoot), name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", name)
+	}
+	// Copy into a temporary directory so an interrupted backup is never
+	// mistaken for a complete one.
+	tmp := dir + ".tmp"
+	_ = os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	b := &DataBackup{Timestamp: name, Path: dir, CreatedAt: clk.Now().UTC(), Databases: databases}
+	for _, db := range databases {
+		if err := copyDir(filepath.Join(tmp, db), RigDatabaseDir(townRoot, db)); err != nil {
+			return nil, fmt.Errorf("copying %s: %w", db, err)
+		}
+	}
+	b.SizeBytes = dirSize(tmp)
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, dataBackupManifest), data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// loadDataBackup reads the manifest of the backup in dir.
+func loadDataBackup(dir string) (*DataBackup, error) {
+	data, err := os.ReadFile(filepath.Join(dir, dataBackupManifest))
+	if err != nil {
+		return nil, err
+	}
+	var b DataBackup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", dataBackupManifest, err)
+	}
+	// The directory is authoritative if the backups were moved.
+	b.Path = dir
+	b.Timestamp = filepath.Base(dir)
+	return &b, nil
+}
+
+// pauseServer stops the server if it is running and reports whether it
+// should be restarted.
+func pauseServer(townRoot string) (bool, error) {
+	running, _, err := IsRunning(townRoot)
+	if err != nil || !running {
+		return false, err
+	}
+	if err := Stop(townRoot); err != nil {
+		return false, fmt.Errorf("stopping Dolt server: %w", err)
+	}
+	return true, nil
+}
+
+// resumeServer restarts the server if pauseServer stopped it.
+func resumeServer(townRoot string, restart bool) error {
+	if !restart {
+		return nil
+	}
+	if err := Start(townRoot); err != nil {
+		return fmt.Errorf("restarting Dolt server: %w", err)
+	}
+	return nil
+}
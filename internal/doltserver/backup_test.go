@@ -0,0 +1,211 @@
+package doltserver
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestCreateDataBackup(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	setupDoltDB(t, dataDir, "hq")
+	setupDoltDB(t, dataDir, "gastown")
+
+	b, pruned, err := CreateDataBackup(townRoot, 0)
+	if err != nil {
+		t.Fatalf("CreateDataBackup: %v", err)
+	}
+	if len(pruned) != 0 {
+		t.Errorf("pruned = %+v, want none", pruned)
+	}
+	if b.Timestamp != "20260310-120000" || len(b.Databases) != 2 || b.SizeBytes == 0 {
+		t.Errorf("backup = %+v", b)
+	}
+	for _, db := range []string{"hq", "gastown"} {
+		if _, err := os.Stat(filepath.Join(b.Path, db, ".dolt", "manifest")); err != nil {
+			t.Errorf("%s not copied: %v", db, err)
+		}
+	}
+
+	backups, err := ListDataBackups(townRoot)
+	if err != nil || len(backups) != 1 || backups[0].Timestamp != b.Timestamp {
+		t.Fatalf("ListDataBackups = %+v, %v", backups, err)
+	}
+
+	// A second backup in the same second would overwrite the first.
+	if _, _, err := CreateDataBackup(townRoot, 0); err == nil {
+		t.Error("expected error for duplicate backup name")
+	}
+}
+
+func TestLinkedDatabase_ListedAndBackedUp(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	setupDoltDB(t, dataDir, "gastown")
+	// A database registered with gt adopt-external-dolt --link.
+	source := setupDoltDB(t, t.TempDir(), "widgets")
+	if err := os.Symlink(source, filepath.Join(dataDir, "widgets")); err != nil {
+		t.Fatal(err)
+	}
+
+	dbs, err := ListDatabases(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"gastown", "widgets"}; !reflect.DeepEqual(dbs, want) {
+		t.Fatalf("ListDatabases = %v, want %v", dbs, want)
+	}
+
+	b, _, err := CreateDataBackup(townRoot, 0)
+	if err != nil {
+		t.Fatalf("CreateDataBackup: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(b.Path, "widgets", ".dolt", "manifest")); err != nil {
+		t.Errorf("linked database not copied: %v", err)
+	}
+	if size := dirSize(filepath.Join(dataDir, "widgets")); size == 0 {
+		t.Error("dirSize of a linked database = 0")
+	}
+
+	archive, err := BackupDatabaseDir(townRoot, "widgets")
+	if err != nil {
+		t.Fatalf("BackupDatabaseDir: %v", err)
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	names := map[string]bool{}
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names[hdr.Name] = true
+	}
+	if !names["widgets/.dolt/manifest"] {
+		t.Errorf("archive entries = %v, want widgets/.dolt/manifest", names)
+	}
+}
+
+func TestCreateDataBackup_NoDatabases(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, _, err := CreateDataBackup(townRoot, 0); err == nil {
+		t.Error("expected error with no databases")
+	}
+	if _, err := os.Stat(DataBackupsDir(townRoot)); err == nil {
+		entries, _ := os.ReadDir(DataBackupsDir(townRoot))
+		if len(entries) != 0 {
+			t.Errorf("failed backup left %d entries behind", len(entries))
+		}
+	}
+}
+
+func TestCreateDataBackup_Retention(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "hq")
+	writeDoltServerSettings(t, townRoot, &config.DoltServerConfig{BackupRetention: 2})
+
+	var stamps []string
+	for i := 0; i < 4; i++ {
+		b, _, err := CreateDataBackup(townRoot, 0)
+		if err != nil {
+			t.Fatalf("backup %d: %v", i, err)
+		}
+		stamps = append(stamps, b.Timestamp)
+		fake.Advance(time.Hour)
+	}
+
+	backups, err := ListDataBackups(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].Timestamp != stamps[3] || backups[1].Timestamp != stamps[2] {
+		t.Errorf("kept %+v, want newest two of %v", backups, stamps)
+	}
+
+	// An explicit keep overrides the configured retention.
+	_, pruned, err := CreateDataBackup(townRoot, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != 2 {
+		t.Errorf("pruned %d backups, want 2", len(pruned))
+	}
+}
+
+func TestRestoreDataBackup(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	hqPath := setupDoltDB(t, dataDir, "hq")
+
+	b, _, err := CreateDataBackup(townRoot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Change the data after the backup and add a new database.
+	manifest := filepath.Join(hqPath, ".dolt", "manifest")
+	if err := os.WriteFile(manifest, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setupDoltDB(t, dataDir, "newrig")
+	fake.Advance(time.Hour)
+
+	result, err := RestoreDataBackup(townRoot, b.Timestamp)
+	if err != nil {
+		t.Fatalf("RestoreDataBackup: %v", err)
+	}
+	if data, _ := os.ReadFile(manifest); string(data) != "test" {
+		t.Errorf("hq manifest = %q, want restored %q", data, "test")
+	}
+	if len(result.Restored) != 1 || result.Restored[0] != "hq" {
+		t.Errorf("Restored = %v", result.Restored)
+	}
+	if len(result.Kept) != 1 || result.Kept[0] != "newrig" {
+		t.Errorf("Kept = %v, want [newrig]", result.Kept)
+	}
+
+	// The replaced data is kept in a pre-restore backup.
+	pre := result.PreRestore
+	if pre == nil || pre.Timestamp != "20260310-130000-pre-restore" {
+		t.Fatalf("PreRestore = %+v", pre)
+	}
+	if data, _ := os.ReadFile(filepath.Join(pre.Path, "hq", ".dolt", "manifest")); string(data) != "changed" {
+		t.Errorf("pre-restore hq manifest = %q, want %q", data, "changed")
+	}
+}
+
+func TestFindDataBackup_Invalid(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, name := range []string{"", "../etc", ".hidden", "20260101-000000"} {
+		if _, err := FindDataBackup(townRoot, name); err == nil {
+			t.Errorf("FindDataBackup(%q) succeeded, want error", name)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package doltserver
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// BeadMovedEvent is the event_type recorded in both databases' events
+// tables when a bead is moved.
+const BeadMovedEvent = "moved"
+
+// beadMoveTables are the per-issue tables copied with a moved bead's issues
+// rows, in insert order. Each is keyed by issue_id.
+var beadMoveTables = []string{"labels", "dependencies", "comments", "events"}
+
+// validBeadIDRe matches bead IDs and database names safe to interpolate into SQL.
+var validBeadIDRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// BeadMove describes moving a bead, and the wisps hanging off it, from one
+// rig database to another. Built by PlanBeadMove and applied by MoveBead.
+type BeadMove struct {
+	SourceDB string            `json:"source_db"`
+	TargetDB string            `json:"target_db"`
+	ID       string            `json:"id"`
+	NewID    string            `json:"new_id"`
+	IDs      map[string]string `json:"ids"`  // Old ID -> new ID, for the bead and its wisps
+	Rows     map[string]int64  `json:"rows"` // Rows to copy per table
+
+	// ExternalDeps are dependencies on beads that stay in the source
+	// database ("<new-id> -> <dep-id> (<type>)"). They are not copied.
+	ExternalDeps []string `json:"external_deps,omitempty"`
+
+	// Dependents are source beads that depend on the moved bead. They keep
+	// pointing at the tombstone left in the source.
+	Dependents []string `json:"dependents,omitempty"`
+}
+
+// Wisps returns the old IDs of the wisps moved with the bead, sorted.
+func (mv *BeadMove) Wisps() []string {
+	var wisps []string
+	for old := range mv.IDs {
+		if old != mv.ID {
+			wisps = append(wisps, old)
+		}
+	}
+	sort.Strings(wisps)
+	return wisps
+}
+
+// oldIDs returns every moved ID, sorted.
+func (mv *BeadMove) oldIDs() []string {
+	ids := make([]string, 0, len(mv.IDs))
+	for old := range mv.IDs {
+		ids = append(ids, old)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// newIDs returns every new ID, sorted.
+func (mv *BeadMove) newIDs() []string {
+	ids := make([]string, 0, len(mv.IDs))
+	for _, id := range mv.IDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// PlanBeadMove works out what moving id from sourceDB to targetDB involves:
+// the wisps that move with it (ephemeral parent-child descendants and its
+// attached molecule), their new IDs under the target's issue prefix, and
+// the rows to copy. Nothing is written. Fails if the bead is missing or
+// closed, or a new ID is already taken in the target.
+func PlanBeadMove(townRoot, sourceDB, targetDB, id string) (*BeadMove, error) {
+	for _, s := range []string{sourceDB, targetDB, id} {
+		if !validBeadIDRe.MatchString(s) {
+			return nil, fmt.Errorf("invalid name %q", s)
+		}
+	}
+	if sourceDB == targetDB {
+		return nil, fmt.Errorf("%s is already in %s", id, targetDB)
+	}
+
+	srcPrefix, err := issuePrefix(townRoot, sourceDB)
+	if err != nil {
+		return nil, err
+	}
+	dstPrefix, err := issuePrefix(townRoot, targetDB)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := doltQueryCSV(townRoot, sourceDB, fmt.Sprintf(
+		"SELECT id, status, description FROM issues WHERE id = '%s'", id))
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s in %s: %w", id, sourceDB, err)
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("bead %s not found in %s", id, sourceDB)
+	}
+	if status := recs[0]["status"]; status == "closed" || status == "tombstone" {
+		return nil, fmt.Errorf("cannot move %s bead %s", status, id)
+	}
+
+	// The bead plus its attached molecule are the roots; their ephemeral
+	// descendants are the wisps that move with it.
+	roots := []string{id}
+	if fields := beads.ParseAttachmentFields(&beads.Issue{Description: recs[0]["description"]}); fields != nil &&
+		fields.AttachedMolecule != "" && validBeadIDRe.MatchString(fields.AttachedMolecule) {
+		roots = append(roots, fields.AttachedMolecule)
+	}
+	moved, err := collectWisps(townRoot, sourceDB, id, roots)
+	if err != nil {
+		return nil, err
+	}
+
+	mv := &BeadMove{
+		SourceDB: sourceDB,
+		TargetDB: targetDB,
+		ID:       id,
+		IDs:      make(map[string]string, len(moved)),
+		Rows:     make(map[string]int64),
+	}
+	for _, old := range moved {
+		newID, err := rewriteIDPrefix(old, srcPrefix, dstPrefix)
+		if err != nil {
+			return nil, err
+		}
+		mv.IDs[old] = newID
+	}
+	mv.NewID = mv.IDs[id]
+
+	taken, err := doltQueryCSV(townRoot, targetDB, fmt.Sprintf(
+		"SELECT id FROM issues WHERE id IN (%s)", sqlList(mv.newIDs())))
+	if err != nil {
+		return nil, fmt.Errorf("checking IDs in %s: %w", targetDB, err)
+	}
+	if recs := csvRecords(taken); len(recs) > 0 {
+		return nil, fmt.Errorf("%s already exists in %s", recs[0]["id"], targetDB)
+	}
+
+	if err := mv.countRows(townRoot); err != nil {
+		return nil, err
+	}
+	return mv, nil
+}
+
+// collectWisps returns id plus the ephemeral parent-child descendants of
+// roots in rigDB. Roots other than id are included only if they are wisps.
+func collectWisps(townRoot, rigDB, id string, roots []string) ([]string, error) {
+	seen := map[string]bool{id: true}
+	moved := []string{id}
+	frontier := []string{id}
+
+	if len(roots) > 1 {
+		rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+			"SELECT id FROM issues WHERE ephemeral = 1 AND id IN (%s)", sqlList(roots[1:])))
+		if err != nil {
+			return nil, fmt.Errorf("looking up attached molecule in %s: %w", rigDB, err)
+		}
+		for _, rec := range csvRecords(rows) {
+			if !seen[rec["id"]] {
+				seen[rec["id"]] = true
+				moved = append(moved, rec["id"])
+				frontier = append(frontier, rec["id"])
+			}
+		}
+	}
+
+	for len(frontier) > 0 {
+		rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+			"SELECT d.issue_id AS id FROM dependencies d JOIN issues i ON i.id = d.issue_id "+
+				"WHERE d.type = 'parent-child' AND i.ephemeral = 1 AND d.depends_on_id IN (%s)", sqlList(frontier)))
+		if err != nil {
+			return nil, fmt.Errorf("finding wisps of %s in %s: %w", id, rigDB, err)
+		}
+		frontier = nil
+		for _, rec := range csvRecords(rows) {
+			if child := rec["id"]; child != "" && !seen[child] && validBeadIDRe.MatchString(child) {
+				seen[child] = true
+				moved = append(moved, child)
+				frontier = append(frontier, child)
+			}
+		}
+	}
+	return moved, nil
+}
+
+// countRows fills in Rows, ExternalDeps, and Dependents.
+func (mv *BeadMove) countRows(townRoot string) error {
+	ids := sqlList(mv.oldIDs())
+	mv.Rows["issues"] = int64(len(mv.IDs))
+	for _, table := range beadMoveTables {
+		where := fmt.Sprintf("issue_id IN (%s)", ids)
+		if table == "dependencies" {
+			where += fmt.Sprintf(" AND depends_on_id IN (%s)", ids)
+		}
+		rows, err := doltQueryCSV(townRoot, mv.SourceDB, fmt.Sprintf("SELECT COUNT(*) AS n FROM `%s` WHERE %s", table, where))
+		if err != nil {
+			return fmt.Errorf("counting %s in %s: %w", table, mv.SourceDB, err)
+		}
+		if recs := csvRecords(rows); len(recs) > 0 {
+			mv.Rows[table], _ = strconv.ParseInt(recs[0]["n"], 10, 64)
+		}
+	}
+
+	rows, err := doltQueryCSV(townRoot, mv.SourceDB, fmt.Sprintf(
+		"SELECT issue_id, depends_on_id, type FROM dependencies WHERE issue_id IN (%s) AND depends_on_id NOT IN (%s)", ids, ids))
+	if err != nil {
+		return fmt.Errorf("listing dependencies in %s: %w", mv.SourceDB, err)
+	}
+	for _, rec := range csvRecords(rows) {
+		mv.ExternalDeps = append(mv.ExternalDeps,
+			fmt.Sprintf("%s -> %s (%s)", mv.IDs[rec["issue_id"]], rec["depends_on_id"], rec["type"]))
+	}
+
+	rows, err = doltQueryCSV(townRoot, mv.SourceDB, fmt.Sprintf(
+		"SELECT DISTINCT issue_id FROM dependencies WHERE depends_on_id = '%s' AND issue_id NOT IN (%s)", mv.ID, ids))
+	if err != nil {
+		return fmt.Errorf("listing dependents in %s: %w", mv.SourceDB, err)
+	}
+	for _, rec := range csvRecords(rows) {
+		mv.Dependents = append(mv.Dependents, rec["issue_id"])
+	}
+	return nil
+}
+
+// MoveBead applies a planned move through the server. The bead, its wisps,
+// their labels, dependencies within the moved set, comments, and events are
+// copied into the target under their new IDs, and a "moved" event is
+// recorded there; then the source bead is closed as a tombstone pointing at
+// the new ID (moved_to: in its description), its wisps are deleted, and a
+// "moved" event is recorded in the source. Each database gets a Dolt commit.
+// If updating the source fails, the copy is removed from the target.
+func MoveBead(townRoot string, mv *BeadMove, actor string) error {
+	src := make(map[string][]tableColumn)
+	shared := make(map[string][]tableColumn)
+	for _, table := range append([]string{"issues"}, beadMoveTables...) {
+		srcCols, err := tableColumns(townRoot, mv.SourceDB, table)
+		if err != nil {
+			return err
+		}
+		dstCols, err := tableColumns(townRoot, mv.TargetDB, table)
+		if err != nil {
+			return err
+		}
+		src[table] = srcCols
+		shared[table] = sharedColumns(srcCols, dstCols)
+	}
+
+	if err := doltSQLScript(townRoot, buildBeadCopyScript(mv, shared, actor)); err != nil {
+		return fmt.Errorf("copying %s into %s: %w", mv.ID, mv.TargetDB, err)
+	}
+	if err := doltSQLScript(townRoot, buildBeadTombstoneScript(mv, src, actor)); err != nil {
+		undo := fmt.Sprintf("USE `%s`;\nDELETE FROM issues WHERE id IN (%s);\nCALL DOLT_COMMIT('-Am', %s);\n",
+			mv.TargetDB, sqlList(mv.newIDs()), sqlString(fmt.Sprintf("gt mv bead: undo copy of %s", mv.ID)))
+		if undoErr := doltSQLScript(townRoot, undo); undoErr != nil {
+			return fmt.Errorf("updating %s in %s: %w (and removing copy %s from %s failed: %v)",
+				mv.ID, mv.SourceDB, err, mv.NewID, mv.TargetDB, undoErr)
+		}
+		return fmt.Errorf("updating %s in %s: %w", mv.ID, mv.SourceDB, err)
+	}
+	return nil
+}
+
+// buildBeadCopyScript returns the SQL that copies the moved rows into the
+// target database. cols maps each table to the columns both databases share.
+func buildBeadCopyScript(mv *BeadMove, cols map[string][]tableColumn, actor string) string {
+	ids := sqlList(mv.oldIDs())
+	var b strings.Builder
+	fmt.Fprintf(&b, "USE `%s`;\n", mv.TargetDB)
+
+	for _, table := range append([]string{"issues"}, beadMoveTables...) {
+		var names, exprs []string
+		for _, c := range cols[table] {
+			// Let the target assign its own surrogate keys.
+			if c.AutoIncrement && table != "issues" {
+				continue
+			}
+			names = append(names, "`"+c.Name+"`")
+			exprs = append(exprs, mv.columnExpr(table, c.Name))
+		}
+		if len(names) == 0 {
+			continue
+		}
+		key := "issue_id"
+		if table == "issues" {
+			key = "id"
+		}
+		where := fmt.Sprintf("`%s` IN (%s)", key, ids)
+		if table == "dependencies" {
+			where += fmt.Sprintf(" AND `depends_on_id` IN (%s)", ids)
+		}
+		fmt.Fprintf(&b, "INSERT INTO `%s` (%s) SELECT %s FROM `%s`.`%s` WHERE %s;\n",
+			table, strings.Join(names, ", "), strings.Join(exprs, ", "), mv.SourceDB, table, where)
+	}
+
+	b.WriteString(movedEventSQL(cols["events"], mv.NewID, actor, mv.ID, mv.NewID,
+		fmt.Sprintf("moved from %s (%s)", mv.SourceDB, mv.ID)))
+	fmt.Fprintf(&b, "CALL DOLT_COMMIT('-Am', %s);\n",
+		sqlString(fmt.Sprintf("gt mv bead: %s from %s as %s", mv.ID, mv.SourceDB, mv.NewID)))
+	return b.String()
+}
+
+// buildBeadTombstoneScript returns the SQL that turns the source bead into a
+// tombstone pointing at its new ID and removes the moved wisps. cols maps
+// each table to the source database's columns.
+func buildBeadTombstoneScript(mv *BeadMove, cols map[string][]tableColumn, actor string) string {
+	has := make(map[string]bool)
+	for _, c := range cols["issues"] {
+		has[c.Name] = true
+	}
+	reason := fmt.Sprintf("Moved to %s (%s)", mv.NewID, mv.TargetDB)
+
+	sets := []string{"`status` = 'closed'"}
+	if has["closed_at"] {
+		sets = append(sets, "`closed_at` = NOW()")
+	}
+	if has["updated_at"] {
+		sets = append(sets, "`updated_at` = NOW()")
+	}
+	if has["close_reason"] {
+		sets = append(sets, "`close_reason` = "+sqlString(reason))
+	}
+	if has["description"] {
+		sets = append(sets, fmt.Sprintf("`description` = CONCAT(COALESCE(`description`, ''), %s)",
+			sqlString("\n\nmoved_to: "+mv.NewID)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "USE `%s`;\n", mv.SourceDB)
+	b.WriteString(movedEventSQL(cols["events"], mv.ID, actor, mv.ID, mv.NewID,
+		fmt.Sprintf("moved to %s (%s)", mv.TargetDB, mv.NewID)))
+	fmt.Fprintf(&b, "UPDATE issues SET %s WHERE id = '%s';\n", strings.Join(sets, ", "), mv.ID)
+	if wisps := mv.Wisps(); len(wisps) > 0 {
+		fmt.Fprintf(&b, "DELETE FROM issues WHERE id IN (%s);\n", sqlList(wisps))
+	}
+	fmt.Fprintf(&b, "CALL DOLT_COMMIT('-Am', %s);\n",
+		sqlString(fmt.Sprintf("gt mv bead: %s moved to %s as %s", mv.ID, mv.TargetDB, mv.NewID)))
+	return b.String()
+}
+
+// columnExpr returns the SELECT expression for a copied column, rewriting
+// moved IDs to their new values.
+func (mv *BeadMove) columnExpr(table, column string) string {
+	quoted := "`" + column + "`"
+	switch {
+	case table == "issues" && column == "id",
+		table != "issues" && (column == "issue_id" || column == "depends_on_id"):
+		var b strings.Builder
+		b.WriteString("CASE " + quoted)
+		for _, old := range mv.oldIDs() {
+			fmt.Fprintf(&b, " WHEN '%s' THEN '%s'", old, mv.IDs[old])
+		}
+		b.WriteString(" ELSE " + quoted + " END")
+		return b.String()
+	case table == "issues" && column == "description":
+		// Keep references such as attached_molecule pointing at the moved wisps.
+		expr := quoted
+		for _, old := range mv.oldIDs() {
+			if old != mv.ID {
+				expr = fmt.Sprintf("REPLACE(%s, '%s', '%s')", expr, old, mv.IDs[old])
+			}
+		}
+		return expr
+	}
+	return quoted
+}
+
+// movedEventSQL returns an INSERT recording a move in an events table with
+// the given columns, or "" if the table lacks the required columns.
+func movedEventSQL(cols []tableColumn, issueID, actor, oldValue, newValue, comment string) string {
+	values := map[string]string{
+		"issue_id":   sqlString(issueID),
+		"event_type": sqlString(BeadMovedEvent),
+		"actor":      sqlString(actor),
+		"old_value":  sqlString(oldValue),
+		"new_value":  sqlString(newValue),
+		"comment":    sqlString(comment),
+		"created_at": "NOW()",
+	}
+	var names, exprs []string
+	for _, c := range cols {
+		if v, ok := values[c.Name]; ok {
+			names = append(names, "`"+c.Name+"`")
+			exprs = append(exprs, v)
+		}
+	}
+	if !containsString(names, "`issue_id`") || !containsString(names, "`event_type`") {
+		return ""
+	}
+	return fmt.Sprintf("INSERT INTO `events` (%s) VALUES (%s);\n", strings.Join(names, ", "), strings.Join(exprs, ", "))
+}
+
+// tableColumn is a column of a beads table.
+type tableColumn struct {
+	Name          string
+	AutoIncrement bool
+}
+
+// tableColumns returns the columns of table in rigDB, in table order.
+func tableColumns(townRoot, rigDB, table string) ([]tableColumn, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT column_name AS name, extra FROM information_schema.columns "+
+			"WHERE table_schema = '%s' AND table_name = '%s' ORDER BY ordinal_position", rigDB, table))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s columns in %s: %w", table, rigDB, err)
+	}
+	var cols []tableColumn
+	for _, rec := range csvRecords(rows) {
+		cols = append(cols, tableColumn{
+			Name:          rec["name"],
+			AutoIncrement: strings.Contains(strings.ToLower(rec["extra"]), "auto_increment"),
+		})
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("table %s not found in %s", table, rigDB)
+	}
+	return cols, nil
+}
+
+// sharedColumns returns the target columns that also exist in the source,
+// so a move between databases on different schema versions copies what
+// both understand.
+func sharedColumns(src, dst []tableColumn) []tableColumn {
+	inSrc := make(map[string]bool, len(src))
+	for _, c := range src {
+		inSrc[c.Name] = true
+	}
+	var shared []tableColumn
+	for _, c := range dst {
+		if inSrc[c.Name] {
+			shared = append(shared, c)
+		}
+	}
+	return shared
+}
+
+// issuePrefix returns a database's issue_prefix from its config table.
+func issuePrefix(townRoot, rigDB string) (string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, "SELECT `value` AS prefix FROM config WHERE `key` = 'issue_prefix'")
+	if err != nil {
+		return "", fmt.Errorf("reading issue prefix of %s: %w", rigDB, err)
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 || recs[0]["prefix"] == "" {
+		return "", fmt.Errorf("database %s has no issue_prefix", rigDB)
+	}
+	return strings.TrimSuffix(recs[0]["prefix"], "-"), nil
+}
+
+// rewriteIDPrefix replaces the source prefix of id with the target prefix:
+// gt-abc12 with prefixes gt and bd becomes bd-abc12.
+func rewriteIDPrefix(id, srcPrefix, dstPrefix string) (string, error) {
+	if !strings.HasPrefix(id, srcPrefix+"-") {
+		return "", fmt.Errorf("%s does not have the source prefix %s-", id, srcPrefix)
+	}
+	return dstPrefix + "-" + strings.TrimPrefix(id, srcPrefix+"-"), nil
+}
+
+// sqlList formats validated IDs as a SQL IN list.
+func sqlList(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "'" + id + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sqlString quotes s as a SQL string literal.
+func sqlString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
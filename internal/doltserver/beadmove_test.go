@@ -0,0 +1,104 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteIDPrefix(t *testing.T) {
+	got, err := rewriteIDPrefix("gt-abc12.3", "gt", "bd")
+	if err != nil || got != "bd-abc12.3" {
+		t.Errorf("rewriteIDPrefix = %q, %v; want bd-abc12.3", got, err)
+	}
+	got, err = rewriteIDPrefix("gt-wisp-x1", "gt", "bd")
+	if err != nil || got != "bd-wisp-x1" {
+		t.Errorf("rewriteIDPrefix = %q, %v; want bd-wisp-x1", got, err)
+	}
+	if _, err := rewriteIDPrefix("hq-abc", "gt", "bd"); err == nil {
+		t.Error("rewriteIDPrefix should reject an ID without the source prefix")
+	}
+}
+
+func TestSharedColumns(t *testing.T) {
+	src := []tableColumn{{Name: "id"}, {Name: "title"}, {Name: "old_only"}}
+	dst := []tableColumn{{Name: "id"}, {Name: "new_only"}, {Name: "title"}}
+	got := sharedColumns(src, dst)
+	if len(got) != 2 || got[0].Name != "id" || got[1].Name != "title" {
+		t.Errorf("sharedColumns = %v, want [id title]", got)
+	}
+}
+
+func TestSQLString(t *testing.T) {
+	if got := sqlString(`it's a \ test`); got != `'it''s a \\ test'` {
+		t.Errorf("sqlString = %s", got)
+	}
+}
+
+func testBeadMove() *BeadMove {
+	return &BeadMove{
+		SourceDB: "gastown",
+		TargetDB: "beads",
+		ID:       "gt-abc",
+		NewID:    "bd-abc",
+		IDs:      map[string]string{"gt-abc": "bd-abc", "gt-wisp-1": "bd-wisp-1"},
+	}
+}
+
+func TestBuildBeadCopyScript(t *testing.T) {
+	mv := testBeadMove()
+	cols := map[string][]tableColumn{
+		"issues":       {{Name: "id"}, {Name: "title"}, {Name: "description"}},
+		"labels":       {{Name: "issue_id"}, {Name: "label"}},
+		"dependencies": {{Name: "issue_id"}, {Name: "depends_on_id"}, {Name: "type"}},
+		"comments":     {{Name: "id", AutoIncrement: true}, {Name: "issue_id"}, {Name: "text"}},
+		"events":       {{Name: "id", AutoIncrement: true}, {Name: "issue_id"}, {Name: "event_type"}, {Name: "actor"}, {Name: "comment"}},
+	}
+	script := buildBeadCopyScript(mv, cols, "mayor")
+
+	for _, want := range []string{
+		"USE `beads`;",
+		"INSERT INTO `issues` (`id`, `title`, `description`) SELECT CASE `id` WHEN 'gt-abc' THEN 'bd-abc' WHEN 'gt-wisp-1' THEN 'bd-wisp-1' ELSE `id` END, `title`, REPLACE(`description`, 'gt-wisp-1', 'bd-wisp-1') FROM `gastown`.`issues` WHERE `id` IN ('gt-abc', 'gt-wisp-1');",
+		"AND `depends_on_id` IN ('gt-abc', 'gt-wisp-1');",
+		"INSERT INTO `comments` (`issue_id`, `text`)",
+		"INSERT INTO `events` (`issue_id`, `event_type`, `actor`, `comment`) VALUES ('bd-abc', 'moved', 'mayor', 'moved from gastown (gt-abc)');",
+		"CALL DOLT_COMMIT('-Am', 'gt mv bead: gt-abc from gastown as bd-abc');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("copy script missing %q\n%s", want, script)
+		}
+	}
+}
+
+func TestBuildBeadTombstoneScript(t *testing.T) {
+	mv := testBeadMove()
+	cols := map[string][]tableColumn{
+		"issues": {{Name: "id"}, {Name: "status"}, {Name: "closed_at"}, {Name: "close_reason"}, {Name: "description"}},
+		"events": {{Name: "issue_id"}, {Name: "event_type"}, {Name: "new_value"}},
+	}
+	script := buildBeadTombstoneScript(mv, cols, "mayor")
+
+	for _, want := range []string{
+		"USE `gastown`;",
+		"INSERT INTO `events` (`issue_id`, `event_type`, `new_value`) VALUES ('gt-abc', 'moved', 'bd-abc');",
+		"`status` = 'closed', `closed_at` = NOW(), `close_reason` = 'Moved to bd-abc (beads)'",
+		"moved_to: bd-abc",
+		"DELETE FROM issues WHERE id IN ('gt-wisp-1');",
+		"CALL DOLT_COMMIT(",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("tombstone script missing %q\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "updated_at") {
+		t.Error("tombstone script sets updated_at, which the source table lacks")
+	}
+}
+
+func TestPlanBeadMoveRejectsBadInput(t *testing.T) {
+	if _, err := PlanBeadMove(t.TempDir(), "gastown", "gastown", "gt-abc"); err == nil {
+		t.Error("expected error moving a bead into its own database")
+	}
+	if _, err := PlanBeadMove(t.TempDir(), "gastown", "beads", "gt-abc'; DROP TABLE issues; --"); err == nil {
+		t.Error("expected error for unsafe bead ID")
+	}
+}
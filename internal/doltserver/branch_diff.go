@@ -0,0 +1,113 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// BranchBeadDiff is one bead a branch created, modified, or removed
+// relative to main, as written by ExportBranchDiff. Before is the bead at
+// the branch's merge base with main and After the bead on the branch; each
+// holds the issues columns that are set.
+type BranchBeadDiff struct {
+	Database string            `json:"database"`
+	Branch   string            `json:"branch"`
+	ID       string            `json:"id"`
+	Change   string            `json:"change"` // added, modified, removed
+	Fields   []string          `json:"changed_fields,omitempty"`
+	Before   map[string]string `json:"before,omitempty"`
+	After    map[string]string `json:"after,omitempty"`
+}
+
+// diffMetaColumns are DOLT_DIFF columns that describe the diff rather than
+// the bead.
+var diffMetaColumns = map[string]bool{"commit": true, "commit_date": true}
+
+// BranchBeadDiffs returns the beads branchName creates, modifies, or
+// removes relative to main, including writes still in the branch's
+// uncommitted working set, sorted by bead ID.
+func BranchBeadDiffs(townRoot, rigDB, branchName string) ([]BranchBeadDiff, error) {
+	recs, err := QueryOnBranch(townRoot, rigDB, branchName,
+		"SELECT * FROM DOLT_DIFF(DOLT_MERGE_BASE('main', 'HEAD'), 'WORKING', 'issues')")
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []BranchBeadDiff
+	for _, rec := range recs {
+		d := BranchBeadDiff{
+			Database: rigDB,
+			Branch:   branchName,
+			Change:   rec["diff_type"],
+			Before:   diffSide(rec, "from_"),
+			After:    diffSide(rec, "to_"),
+		}
+		d.ID = d.After["id"]
+		if d.ID == "" {
+			d.ID = d.Before["id"]
+		}
+		if d.Change == "modified" {
+			d.Fields = changedFields(d.Before, d.After)
+			if len(d.Fields) == 0 {
+				continue
+			}
+		}
+		diffs = append(diffs, d)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].ID < diffs[j].ID })
+	return diffs, nil
+}
+
+// diffSide collects the bead columns of one side of a DOLT_DIFF row, keyed
+// without the side prefix. Empty columns are left out; a side with no
+// columns (the before of an added bead) is nil.
+func diffSide(rec map[string]string, prefix string) map[string]string {
+	var side map[string]string
+	for col, v := range rec {
+		name, ok := strings.CutPrefix(col, prefix)
+		if !ok || diffMetaColumns[name] || v == "" {
+			continue
+		}
+		if side == nil {
+			side = make(map[string]string)
+		}
+		side[name] = v
+	}
+	return side
+}
+
+// changedFields returns the columns whose values differ, sorted.
+func changedFields(before, after map[string]string) []string {
+	var fields []string
+	for k, v := range after {
+		if before[k] != v {
+			fields = append(fields, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ExportBranchDiff writes the beads branchName creates, modifies, or
+// removes relative to main to w as JSON lines, one bead per line, and
+// returns how many it wrote.
+func ExportBranchDiff(townRoot, rigDB, branchName string, w io.Writer) (int, error) {
+	diffs, err := BranchBeadDiffs(townRoot, rigDB, branchName)
+	if err != nil {
+		return 0, err
+	}
+	enc := json.NewEncoder(w)
+	for i := range diffs {
+		if err := enc.Encode(&diffs[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(diffs), nil
+}
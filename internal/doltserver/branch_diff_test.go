@@ -0,0 +1,60 @@
+package doltserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestExportBranchDiff(t *testing.T) {
+	r := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return []byte("to_id,to_title,to_status,to_commit,from_id,from_title,from_status,from_commit,diff_type\n" +
+			"gt-b,Fix it,closed,WORKING,gt-b,Fix it,open,abc,modified\n" +
+			"gt-a,New bug,open,WORKING,,,,abc,added\n" +
+			"gt-c,Same,open,WORKING,gt-c,Same,open,abc,modified\n" +
+			",,,WORKING,gt-d,Gone,open,abc,removed\n"), nil, nil
+	}}
+	defer SetRunner(r)()
+
+	var buf bytes.Buffer
+	n, err := ExportBranchDiff(t.TempDir(), "gastown", "polecat-nux-1700000000", &buf)
+	if err != nil {
+		t.Fatalf("ExportBranchDiff: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("wrote %d beads, want 3 (unchanged gt-c skipped)", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var diffs []BranchBeadDiff
+	for _, line := range lines {
+		var d BranchBeadDiff
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		diffs = append(diffs, d)
+	}
+	if diffs[0].ID != "gt-a" || diffs[0].Change != "added" || diffs[0].Before != nil || diffs[0].After["title"] != "New bug" {
+		t.Errorf("added bead = %+v", diffs[0])
+	}
+	if diffs[1].ID != "gt-b" || len(diffs[1].Fields) != 1 || diffs[1].Fields[0] != "status" {
+		t.Errorf("modified bead = %+v, want only status changed", diffs[1])
+	}
+	if _, ok := diffs[1].After["commit"]; ok {
+		t.Error("diff metadata column exported as a bead field")
+	}
+	if diffs[2].ID != "gt-d" || diffs[2].Change != "removed" || diffs[2].After != nil {
+		t.Errorf("removed bead = %+v", diffs[2])
+	}
+
+	query := r.Calls()[0].Args[len(r.Calls()[0].Args)-1]
+	if !strings.HasPrefix(query, "USE `gastown/polecat-nux-1700000000`;") {
+		t.Errorf("query not run on the branch's revision database: %q", query)
+	}
+	if _, err := ExportBranchDiff(t.TempDir(), "gastown", "x'; DROP", &buf); err == nil {
+		t.Error("ExportBranchDiff accepted an invalid branch name")
+	}
+}
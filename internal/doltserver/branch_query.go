@@ -0,0 +1,54 @@
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BranchDatabase returns the revision database that selects rigDB at
+// branch, quoted for use in USE or as a table qualifier: `rig/branch`.
+// Queries through it read the branch's working set, including writes the
+// polecat hasn't committed, without checking the branch out or setting
+// BD_BRANCH.
+func BranchDatabase(rigDB, branch string) (string, error) {
+	if err := validateBranchName(branch); err != nil {
+		return "", fmt.Errorf("reading Dolt branch in %s: %w", rigDB, err)
+	}
+	return fmt.Sprintf("`%s/%s`", rigDB, branch), nil
+}
+
+// QueryOnBranch runs query against rigDB at branch and returns the rows
+// keyed by column.
+func QueryOnBranch(townRoot, rigDB, branch, query string) ([]map[string]string, error) {
+	db, err := BranchDatabase(rigDB, branch)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := doltQueryCSV(townRoot, db, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying %s branch %s: %w", rigDB, branch, err)
+	}
+	return csvRecords(rows), nil
+}
+
+// BeadStatusesOnBranch returns the status each of ids has on branch, keyed
+// by bead ID. Beads that don't exist on the branch are left out.
+func BeadStatusesOnBranch(townRoot, rigDB, branch string, ids []string) (map[string]string, error) {
+	if len(ids) == 0 {
+		return map[string]string{}, nil
+	}
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "'" + strings.ReplaceAll(id, "'", "''") + "'"
+	}
+	recs, err := QueryOnBranch(townRoot, rigDB, branch,
+		"SELECT id, status FROM issues WHERE id IN ("+strings.Join(quoted, ", ")+")")
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]string, len(recs))
+	for _, rec := range recs {
+		statuses[rec["id"]] = rec["status"]
+	}
+	return statuses, nil
+}
@@ -0,0 +1,43 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestBeadStatusesOnBranch(t *testing.T) {
+	var query string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query = c.Args[len(c.Args)-1]
+		return []byte("id,status\ngt-mol.1,closed\ngt-mol.2,in_progress\n"), nil, nil
+	}})()
+
+	statuses, err := BeadStatusesOnBranch(t.TempDir(), "gastown", "polecat-toast-1712345678", []string{"gt-mol.1", "gt-mol.2", "it's"})
+	if err != nil {
+		t.Fatalf("BeadStatusesOnBranch: %v", err)
+	}
+	if !strings.HasPrefix(query, "USE `gastown/polecat-toast-1712345678`;") {
+		t.Errorf("query = %s, want branch revision database", query)
+	}
+	if !strings.Contains(query, "'it''s'") {
+		t.Errorf("query = %s, want quoted IDs", query)
+	}
+	if statuses["gt-mol.1"] != "closed" || statuses["gt-mol.2"] != "in_progress" || len(statuses) != 2 {
+		t.Errorf("statuses = %v", statuses)
+	}
+}
+
+func TestQueryOnBranch_InvalidBranch(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		t.Errorf("unexpected dolt call: %v", c.Args)
+		return nil, nil, nil
+	}})()
+	if _, err := QueryOnBranch(t.TempDir(), "gastown", "bad'; DROP", "SELECT 1"); err == nil {
+		t.Error("invalid branch name accepted")
+	}
+	if statuses, err := BeadStatusesOnBranch(t.TempDir(), "gastown", "polecat-toast", nil); err != nil || len(statuses) != 0 {
+		t.Errorf("no IDs: statuses = %v, err = %v", statuses, err)
+	}
+}
@@ -0,0 +1,90 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BranchInfo describes a Dolt branch in a rig database relative to main.
+type BranchInfo struct {
+	Database   string    `json:"database"`
+	Name       string    `json:"name"`
+	Hash       string    `json:"hash"`
+	Polecat    string    `json:"polecat,omitempty"` // Owning polecat for polecat-<name>-<ts> branches
+	Created    time.Time `json:"created,omitzero"`  // From the branch name; polecat branches only
+	LastCommit time.Time `json:"last_commit,omitzero"`
+	Ahead      int       `json:"ahead"`  // Commits on the branch that main lacks
+	Behind     int       `json:"behind"` // Commits on main that the branch lacks
+	Error      string    `json:"error,omitempty"`
+}
+
+// doltTimeLayouts are the layouts Dolt uses for DATETIME columns in CSV
+// output and over the SQL server.
+var doltTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	time.RFC3339Nano,
+}
+
+func parseDoltTime(s string) (time.Time, bool) {
+	for _, layout := range doltTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ListBranches returns every branch in rigDB other than main, with its
+// commit counts ahead of and behind main. A branch whose counts can't be
+// read is returned with Error set.
+func ListBranches(townRoot, rigDB string) ([]BranchInfo, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB,
+		"SELECT name, hash, latest_commit_date FROM dolt_branches WHERE name <> 'main' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("listing branches in %s: %w", rigDB, err)
+	}
+
+	var branches []BranchInfo
+	for _, rec := range csvRecords(rows) {
+		b := BranchInfo{Database: rigDB, Name: rec["name"], Hash: rec["hash"]}
+		b.LastCommit, _ = parseDoltTime(rec["latest_commit_date"])
+		if polecat := PolecatNameFromBranch(b.Name); polecat != "" {
+			b.Polecat = polecat
+			b.Created, _ = PolecatBranchCreated(b.Name)
+		}
+		if b.Ahead, b.Behind, err = branchAheadBehind(townRoot, rigDB, b.Name); err != nil {
+			b.Error = err.Error()
+		}
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
+
+// branchAheadBehind counts the commits on branch that main lacks and the
+// commits on main that branch lacks.
+func branchAheadBehind(townRoot, rigDB, branch string) (ahead, behind int, err error) {
+	if err := validateBranchName(branch); err != nil {
+		return 0, 0, err
+	}
+	query := fmt.Sprintf("SELECT "+
+		"(SELECT COUNT(*) FROM DOLT_LOG('main..%[1]s')) AS ahead, "+
+		"(SELECT COUNT(*) FROM DOLT_LOG('%[1]s..main')) AS behind", branch)
+	rows, err := doltQueryCSV(townRoot, rigDB, query)
+	if err != nil {
+		return 0, 0, fmt.Errorf("comparing %s with main: %w", branch, err)
+	}
+	recs := csvRecords(rows)
+	if len(recs) != 1 {
+		return 0, 0, fmt.Errorf("comparing %s with main: no result", branch)
+	}
+	if ahead, err = strconv.Atoi(strings.TrimSpace(recs[0]["ahead"])); err != nil {
+		return 0, 0, fmt.Errorf("comparing %s with main: bad ahead count %q", branch, recs[0]["ahead"])
+	}
+	if behind, err = strconv.Atoi(strings.TrimSpace(recs[0]["behind"])); err != nil {
+		return 0, 0, fmt.Errorf("comparing %s with main: bad behind count %q", branch, recs[0]["behind"])
+	}
+	return ahead, behind, nil
+}
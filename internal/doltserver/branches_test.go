@@ -0,0 +1,50 @@
+package doltserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestListBranches(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		switch {
+		case strings.Contains(query, "dolt_branches"):
+			return []byte("name,hash,latest_commit_date\n" +
+				"feature,abc,2026-03-09 08:00:00.123\n" +
+				"polecat-nux-1700000000,def,2026-03-10 11:00:00\n"), nil, nil
+		case strings.Contains(query, "main..feature"):
+			return nil, []byte("branch not found"), errors.New("exit status 1")
+		default:
+			return []byte("ahead,behind\n3,12\n"), nil, nil
+		}
+	}})()
+
+	branches, err := ListBranches(t.TempDir(), "gastown")
+	if err != nil {
+		t.Fatalf("ListBranches: %v", err)
+	}
+	if len(branches) != 2 {
+		t.Fatalf("got %d branches, want 2", len(branches))
+	}
+
+	feature := branches[0]
+	if feature.Error == "" || feature.Polecat != "" {
+		t.Errorf("feature = %+v, want an error and no polecat", feature)
+	}
+	if want := time.Date(2026, 3, 9, 8, 0, 0, 123000000, time.UTC); !feature.LastCommit.Equal(want) {
+		t.Errorf("LastCommit = %v, want %v", feature.LastCommit, want)
+	}
+
+	pc := branches[1]
+	if pc.Polecat != "nux" || pc.Ahead != 3 || pc.Behind != 12 || pc.Error != "" {
+		t.Errorf("polecat branch = %+v, want nux, 3 ahead, 12 behind", pc)
+	}
+	if !pc.Created.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Created = %v, want the timestamp from the branch name", pc.Created)
+	}
+}
@@ -0,0 +1,221 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Bead change kinds reported by the change feed.
+const (
+	ChangeCreated       = "created"
+	ChangeStatusChanged = "status_changed"
+	ChangeClosed        = "closed"
+)
+
+// BeadChange is a single change to the issues table between two commits.
+type BeadChange struct {
+	Database   string `json:"database"`
+	BeadID     string `json:"bead"`
+	Kind       string `json:"kind"`
+	Title      string `json:"title,omitempty"`
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status,omitempty"`
+	Commit     string `json:"commit"`
+}
+
+// HeadCommit returns the commit hash of HEAD for a rig database.
+func HeadCommit(townRoot, rigDB string) (string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, "SELECT DOLT_HASHOF('HEAD') AS head")
+	if err != nil {
+		return "", fmt.Errorf("reading HEAD for %s: %w", rigDB, err)
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 || recs[0]["head"] == "" {
+		return "", fmt.Errorf("reading HEAD for %s: empty result", rigDB)
+	}
+	return recs[0]["head"], nil
+}
+
+// BeadChangesBetween returns bead creations, status changes, and closes in
+// the issues table between two commits. Edits that leave the status alone
+// (title, description, labels) are not reported.
+func BeadChangesBetween(townRoot, rigDB, fromCommit, toCommit string) ([]BeadChange, error) {
+	if fromCommit == toCommit {
+		return nil, nil
+	}
+	query := fmt.Sprintf(
+		"SELECT diff_type, from_id, to_id, from_status, to_status, to_title FROM DOLT_DIFF('%s', '%s', 'issues')",
+		fromCommit, toCommit)
+	rows, err := doltQueryCSV(townRoot, rigDB, query)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s %s..%s: %w", rigDB, shortHash(fromCommit), shortHash(toCommit), err)
+	}
+
+	var changes []BeadChange
+	for _, rec := range csvRecords(rows) {
+		kind := classifyBeadChange(rec["diff_type"], rec["from_status"], rec["to_status"])
+		if kind == "" {
+			continue
+		}
+		changes = append(changes, BeadChange{
+			Database:   rigDB,
+			BeadID:     rec["to_id"],
+			Kind:       kind,
+			Title:      rec["to_title"],
+			FromStatus: rec["from_status"],
+			ToStatus:   rec["to_status"],
+			Commit:     toCommit,
+		})
+	}
+	return changes, nil
+}
+
+// BeadChangesOnBranch returns the bead creations, status changes, and closes
+// a polecat's Dolt branch makes relative to main, including writes still in
+// the branch's uncommitted working set. Call it before the branch is merged
+// and deleted.
+func BeadChangesOnBranch(townRoot, rigDB, branchName string) ([]BeadChange, error) {
+	recs, err := QueryOnBranch(townRoot, rigDB, branchName,
+		"SELECT diff_type, from_id, to_id, from_status, to_status, to_title "+
+			"FROM DOLT_DIFF(DOLT_MERGE_BASE('main', 'HEAD'), 'WORKING', 'issues')")
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []BeadChange
+	for _, rec := range recs {
+		kind := classifyBeadChange(rec["diff_type"], rec["from_status"], rec["to_status"])
+		if kind == "" {
+			continue
+		}
+		changes = append(changes, BeadChange{
+			Database:   rigDB,
+			BeadID:     rec["to_id"],
+			Kind:       kind,
+			Title:      rec["to_title"],
+			FromStatus: rec["from_status"],
+			ToStatus:   rec["to_status"],
+			Commit:     branchName,
+		})
+	}
+	return changes, nil
+}
+
+// classifyBeadChange maps a dolt_diff row to a change kind, or "" if the row
+// is not interesting (removed rows, edits that don't touch status).
+func classifyBeadChange(diffType, fromStatus, toStatus string) string {
+	switch diffType {
+	case "added":
+		return ChangeCreated
+	case "modified":
+		if fromStatus == toStatus {
+			return ""
+		}
+		if toStatus == "closed" {
+			return ChangeClosed
+		}
+		return ChangeStatusChanged
+	default:
+		return ""
+	}
+}
+
+// csvRecords converts CSV rows with a header line into maps keyed by column.
+func csvRecords(rows [][]string) []map[string]string {
+	if len(rows) < 2 {
+		return nil
+	}
+	header := rows[0]
+	recs := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		rec := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(row) {
+				rec[col] = row[i]
+			}
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// isMissingCommitErr reports whether err means a commit no longer exists.
+func isMissingCommitErr(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "invalid ref spec") ||
+		strings.Contains(msg, "could not resolve")
+}
+
+func shortHash(h string) string {
+	if len(h) > 8 {
+		return h[:8]
+	}
+	return h
+}
+
+// ChangeFeedFile returns the path of the change feed checkpoint file.
+func ChangeFeedFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "changefeed.json")
+}
+
+// ChangeFeedCheckpoints maps rig database names to the last commit whose
+// changes have been published.
+type ChangeFeedCheckpoints map[string]string
+
+// LoadChangeFeedCheckpoints reads the checkpoint file. A missing file yields
+// an empty map.
+func LoadChangeFeedCheckpoints(townRoot string) (ChangeFeedCheckpoints, error) {
+	data, err := os.ReadFile(ChangeFeedFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ChangeFeedCheckpoints{}, nil
+		}
+		return nil, err
+	}
+	cp := ChangeFeedCheckpoints{}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ChangeFeedFile(townRoot), err)
+	}
+	return cp, nil
+}
+
+// SaveChangeFeedCheckpoints writes the checkpoint file atomically.
+func SaveChangeFeedCheckpoints(townRoot string, cp ChangeFeedCheckpoints) error {
+	path := ChangeFeedFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, cp)
+}
+
+// PollBeadChanges advances the checkpoint for rigDB to the current HEAD and
+// returns the bead changes since the previous checkpoint. The first poll for
+// a database only records HEAD, so enabling the feed doesn't replay history.
+// cp is updated in place; the caller saves it.
+func PollBeadChanges(townRoot, rigDB string, cp ChangeFeedCheckpoints) ([]BeadChange, error) {
+	head, err := HeadCommit(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	prev, ok := cp[rigDB]
+	if !ok || prev == "" {
+		cp[rigDB] = head
+		return nil, nil
+	}
+	changes, err := BeadChangesBetween(townRoot, rigDB, prev, head)
+	if err != nil {
+		if isMissingCommitErr(err) {
+			// Checkpoint commit vanished (rollback, gc); restart from HEAD.
+			cp[rigDB] = head
+			return nil, nil
+		}
+		return nil, err
+	}
+	cp[rigDB] = head
+	return changes, nil
+}
@@ -0,0 +1,92 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestClassifyBeadChange(t *testing.T) {
+	tests := []struct {
+		diffType, from, to string
+		want               string
+	}{
+		{"added", "", "open", ChangeCreated},
+		{"modified", "open", "in_progress", ChangeStatusChanged},
+		{"modified", "in_progress", "closed", ChangeClosed},
+		{"modified", "open", "open", ""},
+		{"removed", "open", "", ""},
+	}
+	for _, tt := range tests {
+		if got := classifyBeadChange(tt.diffType, tt.from, tt.to); got != tt.want {
+			t.Errorf("classifyBeadChange(%q, %q, %q) = %q, want %q", tt.diffType, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCSVRecords(t *testing.T) {
+	rows := [][]string{
+		{"diff_type", "to_id", "to_status"},
+		{"added", "gt-1", "open"},
+		{"modified", "gt-2"},
+	}
+	recs := csvRecords(rows)
+	if len(recs) != 2 {
+		t.Fatalf("got %d records, want 2", len(recs))
+	}
+	if recs[0]["to_id"] != "gt-1" || recs[0]["to_status"] != "open" {
+		t.Errorf("record 0 = %v", recs[0])
+	}
+	if _, ok := recs[1]["to_status"]; ok {
+		t.Errorf("short row should omit missing columns, got %v", recs[1])
+	}
+	if csvRecords(rows[:1]) != nil {
+		t.Error("header-only input should yield no records")
+	}
+}
+
+func TestChangeFeedCheckpointsRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	cp, err := LoadChangeFeedCheckpoints(townRoot)
+	if err != nil || len(cp) != 0 {
+		t.Fatalf("missing file: %v, %v", cp, err)
+	}
+	cp["gastown"] = "abc123"
+	if err := SaveChangeFeedCheckpoints(townRoot, cp); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadChangeFeedCheckpoints(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["gastown"] != "abc123" {
+		t.Errorf("checkpoint = %q, want abc123", got["gastown"])
+	}
+}
+
+func TestBeadChangesOnBranch(t *testing.T) {
+	var query string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query = c.Args[len(c.Args)-1]
+		return []byte("diff_type,from_id,to_id,from_status,to_status,to_title\n" +
+			"added,,gt-new,,open,Follow-up\n" +
+			"modified,gt-1,gt-1,in_progress,closed,Fix parser\n" +
+			"modified,gt-2,gt-2,open,open,Edited only\n"), nil, nil
+	}})()
+
+	changes, err := BeadChangesOnBranch(t.TempDir(), "gastown", "polecat-toast")
+	if err != nil {
+		t.Fatalf("BeadChangesOnBranch: %v", err)
+	}
+	if !strings.HasPrefix(query, "USE `gastown/polecat-toast`;") || !strings.Contains(query, "'WORKING'") {
+		t.Errorf("query = %s, want branch revision database and working set", query)
+	}
+	if len(changes) != 2 || changes[0].Kind != ChangeCreated || changes[1].BeadID != "gt-1" || changes[1].Kind != ChangeClosed {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	if _, err := BeadChangesOnBranch(t.TempDir(), "gastown", "bad'; DROP"); err == nil {
+		t.Error("invalid branch name accepted")
+	}
+}
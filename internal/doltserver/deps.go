@@ -0,0 +1,48 @@
+package doltserver
+
+import (
+	"context"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// Package-level dependencies on the clock, the process table, and command
+// execution. Tests in this and other packages replace them with fakes
+// through SetClock, SetProcessTable, and SetRunner.
+var (
+	clk    clock.Clock = clock.Real{}
+	procs  proc.Table  = proc.OS{}
+	runner proc.Runner = proc.Exec{}
+)
+
+// SetClock replaces the clock used for timestamps, backoff, and timeouts
+// (e.g. PolecatBranchName). Returns a func that restores the previous one.
+// Not safe to call while other goroutines use the package.
+func SetClock(c clock.Clock) (restore func()) {
+	prev := clk
+	clk = c
+	return func() { clk = prev }
+}
+
+// SetProcessTable replaces the process table used to check and stop the
+// server process. Returns a func that restores the previous one.
+func SetProcessTable(t proc.Table) (restore func()) {
+	prev := procs
+	procs = t
+	return func() { procs = prev }
+}
+
+// SetRunner replaces the runner for dolt sql commands. Returns a func that
+// restores the previous one.
+func SetRunner(r proc.Runner) (restore func()) {
+	prev := runner
+	runner = r
+	return func() { runner = prev }
+}
+
+// runDolt runs a dolt command in dir through the package runner and returns
+// its stdout and stderr.
+func runDolt(ctx context.Context, dir string, args ...string) ([]byte, []byte, error) {
+	return runner.Run(ctx, proc.Cmd{Name: "dolt", Args: args, Dir: dir})
+}
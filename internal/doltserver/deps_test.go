@@ -0,0 +1,85 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestPolecatBranchName_FakeClock(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Unix(1700000000, 0)))()
+
+	if got := PolecatBranchName("Furiosa"); got != "polecat-furiosa-1700000000" {
+		t.Errorf("PolecatBranchName = %q, want polecat-furiosa-1700000000", got)
+	}
+}
+
+func TestDoltSQLWithRetry_BackoffUsesClock(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	defer SetClock(fake)()
+	r := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return nil, []byte("cannot update manifest"), errors.New("exit status 1")
+	}}
+	defer SetRunner(r)()
+
+	err := doltSQLWithRetry(t.TempDir(), "gastown", "SELECT 1")
+	if err == nil || !strings.Contains(err.Error(), "after 5 retries") {
+		t.Fatalf("doltSQLWithRetry = %v, want retries exhausted", err)
+	}
+
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second}
+	if got := fake.Sleeps(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("backoff sleeps = %v, want %v", got, want)
+	}
+	if calls := r.Calls(); len(calls) != 5 || calls[0].Args[len(calls[0].Args)-1] != "USE gastown; SELECT 1" {
+		t.Errorf("calls = %v, want 5 dolt sql invocations", calls)
+	}
+}
+
+func TestDoltQueryCSV_FakeRunner(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return []byte("name\nmain\npolecat-nux-1700000000\n"), nil, nil
+	}})()
+
+	names, err := ListPolecatBranchNames(t.TempDir(), "gastown")
+	if err != nil {
+		t.Fatalf("ListPolecatBranchNames: %v", err)
+	}
+	if len(names) != 1 || names[0] != "nux" {
+		t.Errorf("names = %v, want [nux]", names)
+	}
+}
+
+func TestQuarantinePolecatBranch(t *testing.T) {
+	r := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if strings.Contains(c.Args[len(c.Args)-1], "dolt_branches") {
+			return []byte("name\npolecat-toast-1700000000\npolecat-toast-1700000500\npolecat-toaster-1700000900\n"), nil, nil
+		}
+		return nil, nil, nil
+	}}
+	defer SetRunner(r)()
+
+	branch, err := FindPolecatBranch(t.TempDir(), "gastown", "Toast")
+	if err != nil || branch != "polecat-toast-1700000500" {
+		t.Fatalf("FindPolecatBranch = %q, %v; want polecat-toast-1700000500", branch, err)
+	}
+	to := QuarantineBranchName(branch)
+	if to != "quarantine-toast-1700000500" {
+		t.Errorf("QuarantineBranchName = %q", to)
+	}
+	if err := RenameBranch(t.TempDir(), "gastown", branch, to); err != nil {
+		t.Fatalf("RenameBranch: %v", err)
+	}
+	calls := r.Calls()
+	if got := calls[len(calls)-1].Args; got[len(got)-1] != "USE gastown; CALL DOLT_BRANCH('-m', 'polecat-toast-1700000500', 'quarantine-toast-1700000500')" {
+		t.Errorf("rename query = %q", got[len(got)-1])
+	}
+	if err := RenameBranch(t.TempDir(), "gastown", "x'; DROP", to); err == nil {
+		t.Error("RenameBranch accepted an invalid branch name")
+	}
+}
@@ -3,10 +3,12 @@
 // The Dolt server provides multi-client access to beads databases,
 // avoiding the single-writer limitation of embedded Dolt mode.
 //
-// Server configuration:
+// Server configuration (defaults; override in the dolt_server section of
+// settings/config.json):
 //   - Port: 3307 (avoids conflict with MySQL on 3306)
 //   - User: root (default Dolt user, no password for localhost)
 //   - Data directory: ~/gt/.dolt-data/ (contains all rig databases)
+//   - Max connections: 50
 //
 // Each rig (hq, gastown, beads) has its own database subdirectory:
 //
@@ -38,6 +40,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -46,7 +49,10 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/chaos"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // EnsureDoltIdentity configures dolt global identity (user.name, user.email)
@@ -99,7 +105,7 @@ func EnsureDoltIdentity() error {
 // and (false, error) when dolt itself fails unexpectedly.
 func doltConfigMissing(key string) (bool, error) {
 	cmd := exec.Command("dolt", "config", "--global", "--get", key)
-	out, err := cmd.Output()
+	out, err := proc.Output(cmd)
 	if err == nil {
 		// Command succeeded — key exists if output is non-empty
 		return len(bytes.TrimSpace(out)) == 0, nil
@@ -122,9 +128,11 @@ func setDoltGlobalConfig(key, value string) error {
 
 // Default configuration
 const (
-	DefaultPort           = 3307
-	DefaultUser           = "root" // Default Dolt user (no password for local access)
-	DefaultMaxConnections = 50     // Conservative default to prevent connection storms
+	DefaultPort           = config.DefaultDoltPort
+	DefaultUser           = config.DefaultDoltUser // Default Dolt user (no password for local access)
+	DefaultMaxConnections = 50                     // Conservative default to prevent connection storms
+
+	DefaultBackupRetention = 7 // gt dolt backup snapshots kept
 )
 
 // metadataMu provides per-path mutexes for EnsureMetadata goroutine synchronization.
@@ -163,12 +171,25 @@ type Config struct {
 	// Set to 0 to use the Dolt default (1000). Gas Town defaults to 50 to prevent
 	// connection storms during mass polecat slings.
 	MaxConnections int
+
+	// BackupRetention is how many data backups CreateDataBackup keeps.
+	BackupRetention int
+
+	// StartupTimeout is how long Start waits for the server to answer
+	// queries.
+	StartupTimeout time.Duration
+
+	// Offsite is the object storage target backups are uploaded to, or nil.
+	Offsite *config.DoltOffsiteConfig
 }
 
-// DefaultConfig returns the default Dolt server configuration.
+// DefaultConfig returns the town's Dolt server configuration: the defaults,
+// overridden by the dolt_server section of town settings. Settings that
+// can't be read leave the defaults in place. The section is cached by
+// config.DoltServerSettings, so this is cheap to call repeatedly.
 func DefaultConfig(townRoot string) *Config {
 	daemonDir := filepath.Join(townRoot, "daemon")
-	return &Config{
+	cfg := &Config{
 		TownRoot:       townRoot,
 		Port:           DefaultPort,
 		User:           DefaultUser,
@@ -176,6 +197,36 @@ func DefaultConfig(townRoot string) *Config {
 		LogFile:        filepath.Join(daemonDir, "dolt.log"),
 		PidFile:        filepath.Join(daemonDir, "dolt.pid"),
 		MaxConnections: DefaultMaxConnections,
+
+		BackupRetention: DefaultBackupRetention,
+		StartupTimeout:  DefaultStartupTimeout,
+	}
+	applyServerSettings(cfg, config.DoltServerSettings(townRoot))
+	return cfg
+}
+
+// applyServerSettings overrides cfg with the set fields of s. Out-of-range
+// values are ignored.
+func applyServerSettings(cfg *Config, s *config.DoltServerConfig) {
+	cfg.Port, cfg.User = s.Endpoint()
+	if s == nil {
+		return
+	}
+	if s.DataDir != "" {
+		cfg.DataDir = s.DataDir
+		if !filepath.IsAbs(s.DataDir) {
+			cfg.DataDir = filepath.Join(cfg.TownRoot, s.DataDir)
+		}
+	}
+	if s.MaxConnections > 0 {
+		cfg.MaxConnections = s.MaxConnections
+	}
+	if s.BackupRetention > 0 {
+		cfg.BackupRetention = s.BackupRetention
+	}
+	cfg.StartupTimeout = s.StartupTimeout.Or(cfg.StartupTimeout)
+	if s.Offsite != nil && s.Offsite.URL != "" {
+		cfg.Offsite = s.Offsite
 	}
 }
 
@@ -204,6 +255,21 @@ type State struct {
 
 	// Databases is the list of available databases (rig names).
 	Databases []string `json:"databases,omitempty"`
+
+	// Draining is set while 'gt dolt stop' waits for clients to disconnect.
+	// New polecat spawns are refused while it is set, unless SpawnsAllowed.
+	Draining bool `json:"draining,omitempty"`
+
+	// SpawnsAllowed keeps polecat spawns going during a drain
+	// ('gt dolt stop --allow-spawns').
+	SpawnsAllowed bool `json:"spawns_allowed,omitempty"`
+
+	// DrainingSince is when draining started.
+	DrainingSince time.Time `json:"draining_since,omitempty"`
+
+	// Version counts the writes to the state file. SaveState refuses to
+	// overwrite a newer version than the one it was given.
+	Version int64 `json:"version,omitempty"`
 }
 
 // StateFile returns the path to the state file.
@@ -211,34 +277,43 @@ func StateFile(townRoot string) string {
 	return filepath.Join(townRoot, "daemon", "dolt-state.json")
 }
 
-// LoadState loads Dolt server state from disk.
+// LoadState loads Dolt server state from disk. To change the state, use
+// UpdateState, which holds the state lock between the read and the write.
 func LoadState(townRoot string) (*State, error) {
-	stateFile := StateFile(townRoot)
-	data, err := os.ReadFile(stateFile)
+	state, err := readState(townRoot)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &State{}, nil
-		}
 		return nil, err
 	}
-
-	var state State
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, err
+	if chaos.Stale(chaos.DoltState) {
+		// Simulate a state file left behind by a crashed server.
+		state.Running = true
+		state.PID = stalePID
+		state.StartedAt = clk.Now().Add(-24 * time.Hour)
 	}
-	return &state, nil
+	return state, nil
 }
 
-// SaveState saves Dolt server state to disk using atomic write.
-func SaveState(townRoot string, state *State) error {
-	stateFile := StateFile(townRoot)
+// stalePID is a PID that is never alive, used for injected stale state.
+const stalePID = 0x7ffffffe
 
-	// Ensure daemon directory exists
-	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+// SaveState saves Dolt server state to disk using atomic write, under the
+// state lock. If the file was rewritten since state was loaded (its Version
+// is newer), ErrStateConflict is returned and nothing is written; a State
+// with Version 0 replaces whatever is there. On success state.Version is
+// advanced to the saved version.
+func SaveState(townRoot string, state *State) error {
+	saved, err := UpdateState(townRoot, func(current *State) error {
+		if state.Version != 0 && current.Version != state.Version {
+			return fmt.Errorf("%w (loaded version %d, now %d)", ErrStateConflict, state.Version, current.Version)
+		}
+		*current = *state
+		return nil
+	})
+	if err != nil {
 		return err
 	}
-
-	return util.AtomicWriteJSON(stateFile, state)
+	state.Version = saved.Version
+	return nil
 }
 
 // IsRunning checks if a Dolt server is running for the given town.
@@ -248,25 +323,15 @@ func IsRunning(townRoot string) (bool, int, error) {
 	config := DefaultConfig(townRoot)
 
 	// First check PID file
-	data, err := os.ReadFile(config.PidFile)
-	if err == nil {
-		pidStr := strings.TrimSpace(string(data))
-		pid, err := strconv.Atoi(pidStr)
-		if err == nil {
-			// Check if process is alive
-			process, err := os.FindProcess(pid)
-			if err == nil {
-				// On Unix, FindProcess always succeeds. Send signal 0 to check if alive.
-				if err := process.Signal(syscall.Signal(0)); err == nil {
-					// Verify it's actually a dolt process
-					if isDoltProcess(pid) {
-						return true, pid, nil
-					}
-				}
-			}
+	if _, err := os.Stat(config.PidFile); err == nil {
+		pid := readPIDFile(config.PidFile)
+		// Check if process is alive and is actually a dolt process
+		if pid > 0 && procs.Alive(pid) && isDoltProcess(pid) {
+			return true, pid, nil
 		}
-		// PID file is stale, clean it up
-		_ = os.Remove(config.PidFile)
+		// PID file is stale, clean it up unless a concurrent start has
+		// just rewritten it
+		removePIDFile(townRoot, config.PidFile, pid)
 	}
 
 	// No valid PID file - check if port is in use by dolt anyway
@@ -308,19 +373,12 @@ func HasServerModeMetadata(townRoot string) []string {
 	}
 
 	// Check rig-level beads
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
+	rigNames, err := rigsconfig.Names(townRoot)
 	if err != nil {
 		return serverRigs
 	}
-	var config struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return serverRigs
-	}
 
-	for rigName := range config.Rigs {
+	for _, rigName := range rigNames {
 		beadsDir := FindRigBeadsDir(townRoot, rigName)
 		if beadsDir != "" && hasServerMode(beadsDir) {
 			serverRigs = append(serverRigs, rigName)
@@ -349,21 +407,8 @@ func hasServerMode(beadsDir string) bool {
 // findDoltServerOnPort finds a dolt sql-server process listening on the given port.
 // Returns the PID or 0 if not found.
 func findDoltServerOnPort(port int) int {
-	// Use lsof to find process on port
-	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-t")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	// Parse first PID from output
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 || lines[0] == "" {
-		return 0
-	}
-
-	pid, err := strconv.Atoi(lines[0])
-	if err != nil {
+	pid, err := procs.ListenerPID(port)
+	if err != nil || pid == 0 {
 		return 0
 	}
 
@@ -377,18 +422,29 @@ func findDoltServerOnPort(port int) int {
 
 // isDoltProcess checks if a PID is actually a dolt sql-server process.
 func isDoltProcess(pid int) bool {
-	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=")
-	output, err := cmd.Output()
+	cmdline, err := procs.Cmdline(pid)
 	if err != nil {
 		return false
 	}
+	return isDoltServerCmdline(cmdline)
+}
 
-	cmdline := strings.TrimSpace(string(output))
-	return strings.Contains(cmdline, "dolt") && strings.Contains(cmdline, "sql-server")
+// isDoltServerCmdline reports whether a command line from proc.Table.Cmdline
+// is a dolt sql-server. On Windows only the executable path is available,
+// so any dolt process counts.
+func isDoltServerCmdline(cmdline string) bool {
+	if !strings.Contains(cmdline, "dolt") {
+		return false
+	}
+	return strings.Contains(cmdline, "sql-server") || runtime.GOOS == "windows"
 }
 
 // Start starts the Dolt SQL server.
 func Start(townRoot string) error {
+	if err := chaos.Inject(chaos.DoltStart); err != nil {
+		return fmt.Errorf("starting Dolt server: %w", err)
+	}
+
 	config := DefaultConfig(townRoot)
 
 	// Ensure daemon directory exists
@@ -420,34 +476,26 @@ func Start(townRoot string) error {
 		if _, statErr := os.Stat(config.DataDir); os.IsNotExist(statErr) {
 			fmt.Fprintf(os.Stderr, "Warning: Dolt server (PID %d) is running but data directory %s does not exist — stopping orphaned server\n", pid, config.DataDir)
 			if stopErr := Stop(townRoot); stopErr != nil {
-				if pid > 0 {
-					if proc, findErr := os.FindProcess(pid); findErr == nil {
-						_ = proc.Kill()
-						time.Sleep(100 * time.Millisecond)
-					}
+				if pid > 0 && procs.Kill(pid) == nil {
+					clk.Sleep(100 * time.Millisecond)
 				}
 			}
 			// Fall through to start a new server
 		} else {
 			// Server is running with valid data dir - verify PID file is correct (gm-ouur fix)
 			// If PID file is stale/missing but server is on port, update it
-			pidFromFile := 0
-			if data, err := os.ReadFile(config.PidFile); err == nil {
-				pidFromFile, _ = strconv.Atoi(strings.TrimSpace(string(data)))
-			}
-			if pidFromFile != pid {
+			if pidFromFile := readPIDFile(config.PidFile); pidFromFile != pid {
 				// PID file is stale/wrong - update it
 				fmt.Printf("Updating stale PID file (was %d, actual %d)\n", pidFromFile, pid)
-				if err := os.WriteFile(config.PidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
+				if err := writePIDFile(townRoot, config.PidFile, pid); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: could not update PID file: %v\n", err)
 				}
 				// Update state too
-				state, _ := LoadState(townRoot)
-				if state != nil && state.PID != pid {
+				_, _ = UpdateState(townRoot, func(state *State) error {
 					state.PID = pid
 					state.Running = true
-					_ = SaveState(townRoot, state)
-				}
+					return nil
+				})
 			}
 			return fmt.Errorf("Dolt server already running (PID %d)", pid)
 		}
@@ -504,35 +552,50 @@ func Start(townRoot string) error {
 	}
 
 	// Write PID file
-	if err := os.WriteFile(config.PidFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil {
+	if err := writePIDFile(townRoot, config.PidFile, cmd.Process.Pid); err != nil {
 		// Try to kill the process we just started
 		_ = cmd.Process.Kill()
 		return fmt.Errorf("writing PID file: %w", err)
 	}
 
-	// Save state
-	state := &State{
+	// Save state, replacing whatever the previous server left
+	if err := SaveState(townRoot, &State{
 		Running:   true,
 		PID:       cmd.Process.Pid,
 		Port:      config.Port,
-		StartedAt: time.Now(),
+		StartedAt: clk.Now(),
 		DataDir:   config.DataDir,
 		Databases: databases,
-	}
-	if err := SaveState(townRoot, state); err != nil {
+	}); err != nil {
 		// Non-fatal - server is still running
 		fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", err)
 	}
 
-	// Wait briefly and verify it started
-	time.Sleep(500 * time.Millisecond)
+	// Reap the process if it exits, so a server that dies during startup
+	// is noticed at once instead of after the timeout.
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
 
-	running, _, err = IsRunning(townRoot)
+	phase, elapsed, err := waitForReady(config.StartupTimeout, exited,
+		func() error { return dialPort(config.Port) },
+		func() error { _, err := MeasureQueryLatency(townRoot); return err })
 	if err != nil {
-		return fmt.Errorf("verifying server started: %w", err)
-	}
-	if !running {
-		return fmt.Errorf("Dolt server failed to start (check logs with 'gt dolt logs')")
+		if phase != StartupPhaseExited {
+			_ = cmd.Process.Kill()
+		}
+		removePIDFile(townRoot, config.PidFile, cmd.Process.Pid)
+		_, _ = UpdateState(townRoot, func(state *State) error {
+			state.Running = false
+			return nil
+		})
+		return &StartupError{
+			Phase:   phase,
+			Port:    config.Port,
+			Elapsed: elapsed,
+			Err:     err,
+			LogFile: config.LogFile,
+			LogTail: logTail(config.LogFile, startupLogLines),
+		}
 	}
 
 	return nil
@@ -550,24 +613,22 @@ func cleanupStaleDoltLock(databaseDir string) error {
 		return nil // No lock file, nothing to clean
 	}
 
-	// Check if any process holds this file open using lsof
-	cmd := exec.Command("lsof", lockPath)
-	_, err := cmd.Output()
-	if err != nil {
-		// lsof returns exit code 1 when no process has the file open
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			// No process holds the lock - safe to remove stale lock
-			if err := os.Remove(lockPath); err != nil {
-				return fmt.Errorf("failed to remove stale LOCK file: %w", err)
-			}
-			return nil
-		}
-		// Other error - ignore, let dolt handle it
+	// Check if any process holds this file open. Where that can't be
+	// determined, try the removal anyway: platforms without the lookup
+	// (Windows) refuse to delete files that are open.
+	holders, err := procs.OpenedBy(lockPath)
+	if err != nil && !errors.Is(err, proc.ErrUnsupported) {
+		// Unknown - leave it, let dolt handle it
 		return nil
 	}
-
-	// lsof found processes - lock is legitimately held (likely by bd)
-	// This is not an error condition; dolt server will handle the conflict
+	if len(holders) > 0 {
+		// Lock is legitimately held (likely by bd). This is not an error
+		// condition; dolt server will handle the conflict
+		return nil
+	}
+	if err := os.Remove(lockPath); err != nil {
+		return fmt.Errorf("failed to remove stale LOCK file: %w", err)
+	}
 	return nil
 }
 
@@ -584,43 +645,42 @@ func Stop(townRoot string) error {
 		return fmt.Errorf("Dolt server is not running")
 	}
 
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("finding process: %w", err)
-	}
+	// Idle pooled connections would only hold up the shutdown.
+	closeServerPools()
 
 	// Send SIGTERM for graceful shutdown
-	if err := process.Signal(syscall.SIGTERM); err != nil {
+	if err := procs.Terminate(pid); err != nil {
 		return fmt.Errorf("sending SIGTERM: %w", err)
 	}
 
 	// Wait for graceful shutdown (dolt needs more time)
 	for i := 0; i < 10; i++ {
-		time.Sleep(500 * time.Millisecond)
-		if err := process.Signal(syscall.Signal(0)); err != nil {
+		clk.Sleep(500 * time.Millisecond)
+		if !procs.Alive(pid) {
 			// Process has exited
 			break
 		}
 	}
 
 	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	if procs.Alive(pid) {
 		// Still running, force kill
-		_ = process.Signal(syscall.SIGKILL)
-		time.Sleep(100 * time.Millisecond)
+		_ = procs.Kill(pid)
+		clk.Sleep(100 * time.Millisecond)
 	}
 
 	// Clean up PID file
-	_ = os.Remove(config.PidFile)
+	removePIDFile(townRoot, config.PidFile, 0)
 
 	// Update state - preserve historical info
-	state, _ := LoadState(townRoot)
-	if state == nil {
-		state = &State{}
-	}
-	state.Running = false
-	state.PID = 0
-	_ = SaveState(townRoot, state)
+	_, _ = UpdateState(townRoot, func(state *State) error {
+		state.Running = false
+		state.PID = 0
+		state.Draining = false
+		state.DrainingSince = time.Time{}
+		state.SpawnsAllowed = false
+		return nil
+	})
 
 	return nil
 }
@@ -652,7 +712,13 @@ func ListDatabases(townRoot string) ([]string, error) {
 
 	var databases []string
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if IsShadowDatabase(entry.Name()) {
+			continue
+		}
+		// Stat follows symlinks: databases registered with
+		// gt adopt-external-dolt --link are symlinks to directories.
+		info, err := os.Stat(filepath.Join(config.DataDir, entry.Name()))
+		if err != nil || !info.IsDir() {
 			continue
 		}
 		// Check if this directory is a valid Dolt database
@@ -710,7 +776,7 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 						break
 					}
 				}
-				time.Sleep(backoff)
+				clk.Sleep(backoff)
 			}
 			continue
 		}
@@ -724,11 +790,11 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 
 		// Capture stderr separately so it doesn't corrupt JSON parsing.
 		// Dolt commonly writes deprecation/manifest warnings to stderr.
-		// See also daemon/dolt.go:listDatabases() which uses cmd.Output()
+		// See also daemon/dolt.go:listDatabases() which uses proc.OutputContext(ctx, cmd)
 		// for the same reason.
 		var stderrBuf bytes.Buffer
 		cmd.Stderr = &stderrBuf
-		output, queryErr := cmd.Output()
+		output, queryErr := proc.OutputContext(ctx, cmd)
 		cancel()
 		if queryErr != nil {
 			stderrMsg := strings.TrimSpace(stderrBuf.String())
@@ -746,7 +812,7 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 						break
 					}
 				}
-				time.Sleep(backoff)
+				clk.Sleep(backoff)
 			}
 			continue
 		}
@@ -854,19 +920,12 @@ func jsonKeys(m map[string]json.RawMessage) []string {
 // Returns (serverWasRunning, created, err). created is false when the database
 // already existed on disk (idempotent no-op).
 func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err error) {
-	if rigName == "" {
-		return false, false, fmt.Errorf("rig name cannot be empty")
+	if err := validateRigName(rigName); err != nil {
+		return false, false, err
 	}
 
 	config := DefaultConfig(townRoot)
 
-	// Validate rig name (simple alphanumeric + underscore/dash)
-	for _, r := range rigName {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-') {
-			return false, false, fmt.Errorf("invalid rig name %q: must contain only alphanumeric, underscore, or dash", rigName)
-		}
-	}
-
 	rigDir := filepath.Join(config.DataDir, rigName)
 
 	// Check if already exists on disk — idempotent for callers like gt install.
@@ -891,9 +950,7 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 			if stopErr := Stop(townRoot); stopErr != nil {
 				// Force-kill if graceful stop fails (no PID file for orphaned server)
 				if runningPID > 0 {
-					if proc, err := os.FindProcess(runningPID); err == nil {
-						_ = proc.Kill()
-					}
+					_ = procs.Kill(runningPID)
 				}
 			}
 			running = false
@@ -915,7 +972,7 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 
 		cmd := exec.Command("dolt", "init")
 		cmd.Dir = rigDir
-		output, err := cmd.CombinedOutput()
+		output, err := proc.CombinedOutput(cmd)
 		if err != nil {
 			return false, false, fmt.Errorf("initializing Dolt database: %w\n%s", err, output)
 		}
@@ -1042,6 +1099,44 @@ func FindMigratableDatabases(townRoot string) []Migration {
 	return migrations
 }
 
+// FilterMigrations returns the migrations for the named rigs ("hq" for the
+// town database), in the order FindMigratableDatabases found them. Every
+// name must match a pending migration, so a typo or an already-migrated rig
+// is an error rather than a silent no-op. No names selects all migrations.
+func FilterMigrations(migrations []Migration, rigs []string) ([]Migration, error) {
+	if len(rigs) == 0 {
+		return migrations, nil
+	}
+	want := make(map[string]bool, len(rigs))
+	for _, r := range rigs {
+		want[r] = true
+	}
+	var selected []Migration
+	for _, m := range migrations {
+		if want[m.RigName] {
+			selected = append(selected, m)
+			delete(want, m.RigName)
+		}
+	}
+	if len(want) > 0 {
+		var unknown []string
+		for r := range want {
+			unknown = append(unknown, r)
+		}
+		sort.Strings(unknown)
+		var pending []string
+		for _, m := range migrations {
+			pending = append(pending, m.RigName)
+		}
+		if len(pending) == 0 {
+			pending = []string{"none"}
+		}
+		return nil, fmt.Errorf("no pending migration for %s (pending: %s)",
+			strings.Join(unknown, ", "), strings.Join(pending, ", "))
+	}
+	return selected, nil
+}
+
 // MigrateRigFromBeads migrates an existing beads Dolt database to the data directory.
 // This is used to migrate from the old per-rig .beads/dolt/<db_name> layout to the new
 // centralized .dolt-data/<rigname> layout.
@@ -1184,19 +1279,12 @@ func collectReferencedDatabases(townRoot string) map[string]bool {
 	}
 
 	// Check all rigs from rigs.json
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
+	rigNames, err := rigsconfig.Names(townRoot)
 	if err != nil {
 		return referenced
 	}
-	var config struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return referenced
-	}
 
-	for rigName := range config.Rigs {
+	for _, rigName := range rigNames {
 		beadsDir := FindRigBeadsDir(townRoot, rigName)
 		if beadsDir == "" {
 			continue
@@ -1250,19 +1338,12 @@ func FindBrokenWorkspaces(townRoot string) []BrokenWorkspace {
 	}
 
 	// Check rig-level beads via rigs.json
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	data, err := os.ReadFile(rigsPath)
+	rigNames, err := rigsconfig.Names(townRoot)
 	if err != nil {
 		return broken
 	}
-	var config struct {
-		Rigs map[string]interface{} `json:"rigs"`
-	}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return broken
-	}
 
-	for rigName := range config.Rigs {
+	for _, rigName := range rigNames {
 		beadsDir := FindRigBeadsDir(townRoot, rigName)
 		if beadsDir == "" {
 			continue
@@ -1353,13 +1434,24 @@ func RepairWorkspace(townRoot string, ws BrokenWorkspace) (string, error) {
 //
 // For the "hq" rig, it writes to <townRoot>/.beads/metadata.json.
 // For other rigs, it writes to <townRoot>/<rigName>/mayor/rig/.beads/metadata.json.
+//
+// The town's metadata options apply: a metadata.json tracked in git is only
+// committed if the town allows it, and is otherwise left with a proposed
+// change. See WriteMetadataFile.
 func EnsureMetadata(townRoot, rigName string) error {
+	_, err := UpdateMetadata(townRoot, rigName, DefaultMetadataOptions(townRoot))
+	return err
+}
+
+// UpdateMetadata is EnsureMetadata with explicit options, reporting what
+// was done.
+func UpdateMetadata(townRoot, rigName string, opts MetadataOptions) (*MetadataResult, error) {
 	// Use FindOrCreateRigBeadsDir to atomically resolve and create the directory,
 	// avoiding the TOCTOU race where the directory state changes between
 	// FindRigBeadsDir's Stat check and our subsequent file operations.
 	beadsDir, err := FindOrCreateRigBeadsDir(townRoot, rigName)
 	if err != nil {
-		return fmt.Errorf("resolving beads directory for rig %q: %w", rigName, err)
+		return nil, fmt.Errorf("resolving beads directory for rig %q: %w", rigName, err)
 	}
 
 	metadataPath := filepath.Join(beadsDir, "metadata.json")
@@ -1391,36 +1483,49 @@ func EnsureMetadata(townRoot, rigName string) error {
 	// Historical migrations may have left stale values (e.g., "beads.jsonl").
 	existing["jsonl_export"] = "issues.jsonl"
 
-	data, err := json.MarshalIndent(existing, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling metadata: %w", err)
+	// Point bd at a server moved off the default port or user in town
+	// settings; bd assumes the defaults otherwise.
+	if cfg := DefaultConfig(townRoot); cfg.Port != DefaultPort || cfg.User != DefaultUser {
+		existing["dolt_server_port"] = cfg.Port
+		existing["dolt_server_user"] = cfg.User
 	}
 
-	if err := util.AtomicWriteFile(metadataPath, append(data, '\n'), 0600); err != nil {
-		return fmt.Errorf("writing metadata.json: %w", err)
+	data, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling metadata: %w", err)
 	}
 
-	return nil
+	return WriteMetadataFile(townRoot, rigName, beadsDir, append(data, '\n'), opts)
 }
 
 // EnsureAllMetadata updates metadata.json for all rig databases known to the
 // Dolt server. This is the fix for the split-brain problem where worktrees
 // each have their own isolated database.
 func EnsureAllMetadata(townRoot string) (updated []string, errs []error) {
+	results, errs := UpdateAllMetadata(townRoot, DefaultMetadataOptions(townRoot))
+	for _, r := range results {
+		updated = append(updated, r.Rig)
+	}
+	return updated, errs
+}
+
+// UpdateAllMetadata is EnsureAllMetadata with explicit options, reporting
+// what was done for each database.
+func UpdateAllMetadata(townRoot string, opts MetadataOptions) (results []*MetadataResult, errs []error) {
 	databases, err := ListDatabases(townRoot)
 	if err != nil {
 		return nil, []error{fmt.Errorf("listing databases: %w", err)}
 	}
 
 	for _, dbName := range databases {
-		if err := EnsureMetadata(townRoot, dbName); err != nil {
+		if res, err := UpdateMetadata(townRoot, dbName, opts); err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", dbName, err))
 		} else {
-			updated = append(updated, dbName)
+			results = append(results, res)
 		}
 	}
 
-	return updated, errs
+	return results, errs
 }
 
 // FindRigBeadsDir returns the .beads directory path for a rig (read-only lookup).
@@ -1511,24 +1616,37 @@ func FindOrCreateRigBeadsDir(townRoot, rigName string) (string, error) {
 	return mayorBeads, nil
 }
 
-// GetActiveConnectionCount queries the Dolt server to get the number of active connections.
-// Uses `dolt sql` to query information_schema.PROCESSLIST, which avoids needing
-// a MySQL driver dependency. Returns 0 if the server is unreachable or the query fails.
+// GetActiveConnectionCount queries the Dolt server to get the number of active connections
+// from information_schema.PROCESSLIST. Returns 0 if the server is unreachable or the query fails.
 func GetActiveConnectionCount(townRoot string) (int, error) {
 	config := DefaultConfig(townRoot)
 
-	// Use dolt sql-client to query the server with a timeout to prevent
-	// hanging indefinitely if the Dolt server is unresponsive.
+	// Time out rather than hang if the Dolt server is unresponsive.
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	const query = "SELECT COUNT(*) AS cnt FROM information_schema.PROCESSLIST"
+	if rows, ok, err := serverQuery(ctx, townRoot, "", query); ok {
+		if err != nil {
+			return 0, fmt.Errorf("querying connection count: %w", err)
+		}
+		if len(rows) < 2 || len(rows[1]) == 0 {
+			return 0, fmt.Errorf("unexpected result from connection count query: %v", rows)
+		}
+		count, err := strconv.Atoi(rows[1][0])
+		if err != nil {
+			return 0, fmt.Errorf("parsing connection count %q: %w", rows[1][0], err)
+		}
+		return count, nil
+	}
+
 	cmd := exec.CommandContext(ctx,
 		"dolt", "sql",
 		"-r", "csv",
-		"-q", "SELECT COUNT(*) AS cnt FROM information_schema.PROCESSLIST",
+		"-q", query,
 	)
 	cmd.Dir = config.DataDir
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutputContext(ctx, cmd)
 	if err != nil {
 		return 0, fmt.Errorf("querying connection count: %w (output: %s)", err, strings.TrimSpace(string(output)))
 	}
@@ -1557,6 +1675,10 @@ func HasConnectionCapacity(townRoot string) (bool, int, error) {
 		maxConn = 1000 // Dolt default
 	}
 
+	if RefusingSpawns(townRoot) {
+		return false, 0, fmt.Errorf("Dolt server is draining for shutdown")
+	}
+
 	active, err := GetActiveConnectionCount(townRoot)
 	if err != nil {
 		// Fail closed: if we can't check, the server may be overloaded
@@ -1681,7 +1803,7 @@ func CheckReadOnly(townRoot string) (bool, error) {
 	cmd := exec.CommandContext(ctx, "dolt", "sql", "-q", query)
 	cmd.Dir = config.DataDir
 
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutputContext(ctx, cmd)
 	if err != nil {
 		msg := strings.TrimSpace(string(output))
 		if IsReadOnlyError(msg) {
@@ -1725,7 +1847,7 @@ func RecoverReadOnly(townRoot string) error {
 	}
 
 	// Brief pause for cleanup
-	time.Sleep(1 * time.Second)
+	clk.Sleep(1 * time.Second)
 
 	// Restart the server
 	if err := Start(townRoot); err != nil {
@@ -1746,7 +1868,7 @@ func RecoverReadOnly(townRoot string) error {
 				break
 			}
 		}
-		time.Sleep(backoff)
+		clk.Sleep(backoff)
 
 		readOnly, err = CheckReadOnly(townRoot)
 		if err != nil {
@@ -1794,12 +1916,20 @@ func doltSQLWithRecovery(townRoot, rigDB, query string) error {
 // MeasureQueryLatency times a SELECT 1 query against the Dolt server.
 func MeasureQueryLatency(townRoot string) (time.Duration, error) {
 	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	start := time.Now()
-	cmd := exec.Command("dolt", "sql", "-q", "SELECT 1")
+	start := clk.Now()
+	if _, ok, err := serverQuery(ctx, townRoot, "", "SELECT 1"); ok {
+		if err != nil {
+			return 0, fmt.Errorf("SELECT 1 failed: %w", err)
+		}
+		return clk.Since(start), nil
+	}
+	cmd := exec.CommandContext(ctx, "dolt", "sql", "-q", "SELECT 1")
 	cmd.Dir = config.DataDir
-	output, err := cmd.CombinedOutput()
-	elapsed := time.Since(start)
+	output, err := proc.CombinedOutputContext(ctx, cmd)
+	elapsed := clk.Since(start)
 
 	if err != nil {
 		return 0, fmt.Errorf("SELECT 1 failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
@@ -1810,6 +1940,10 @@ func MeasureQueryLatency(townRoot string) (time.Duration, error) {
 
 // dirSize returns the total size of a directory tree in bytes.
 func dirSize(path string) int64 {
+	// Walk does not follow a symlinked root, e.g. a linked database.
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
 	var total int64
 	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -1877,34 +2011,43 @@ func moveDir(src, dest string) error {
 // serverExecSQL executes a SQL statement against the Dolt server without targeting
 // a specific database. Used for server-level commands like CREATE DATABASE.
 func serverExecSQL(townRoot, query string) error {
+	if err := chaos.Inject(chaos.DoltConn); err != nil {
+		return err
+	}
 	config := DefaultConfig(townRoot)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "dolt", "sql", "-q", query)
-	cmd.Dir = config.DataDir
-	output, err := cmd.CombinedOutput()
+	if _, ok, err := serverQuery(ctx, townRoot, "", query); ok {
+		return err
+	}
+	stdout, stderr, err := runDolt(ctx, config.DataDir, "sql", "-q", query)
 	if err != nil {
-		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
 	}
 	return nil
 }
 
 // doltSQL executes a SQL statement against a specific rig database on the Dolt server.
-// Uses the dolt CLI from the data directory (auto-detects running server).
+// Without a running server it uses the dolt CLI from the data directory.
 // The USE prefix selects the database since --use-db is not available on all dolt versions.
 func doltSQL(townRoot, rigDB, query string) error {
+	if err := chaos.Inject(chaos.DoltConn); err != nil {
+		return err
+	}
 	config := DefaultConfig(townRoot)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
+	if _, ok, err := serverQuery(ctx, townRoot, rigDB, query); ok {
+		return err
+	}
+
 	// Prepend USE <db> to select the target database.
 	fullQuery := fmt.Sprintf("USE %s; %s", rigDB, query)
-	cmd := exec.CommandContext(ctx, "dolt", "sql", "-q", fullQuery)
-	cmd.Dir = config.DataDir
-	output, err := cmd.CombinedOutput()
+	stdout, stderr, err := runDolt(ctx, config.DataDir, "sql", "-q", fullQuery)
 	if err != nil {
-		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
 	}
 	return nil
 }
@@ -1931,7 +2074,7 @@ func doltSQLWithRetry(townRoot, rigDB, query string) error {
 						break
 					}
 				}
-				time.Sleep(backoff)
+				clk.Sleep(backoff)
 			}
 			continue
 		}
@@ -1976,7 +2119,7 @@ func validateBranchName(branchName string) error {
 // PolecatBranchName returns the Dolt branch name for a polecat.
 // Format: polecat-<name>-<unix-timestamp>
 func PolecatBranchName(polecatName string) string {
-	return fmt.Sprintf("polecat-%s-%d", strings.ToLower(polecatName), time.Now().Unix())
+	return fmt.Sprintf("polecat-%s-%d", strings.ToLower(polecatName), clk.Now().Unix())
 }
 
 // CreatePolecatBranch creates a Dolt branch for a polecat's isolated writes.
@@ -2026,15 +2169,20 @@ func CommitServerWorkingSet(townRoot, rigDB, message string) error {
 // (which defaults back to main), silently losing all polecat working set data.
 //
 // The script handles two scenarios:
-//  1. Fast-forward merge (no conflict): commit polecat working set, merge to main
-//  2. Conflict: disable autocommit, merge, resolve with --theirs (polecat wins), commit
+//  1. Clean merge (no conflict): commit polecat working set, merge to main
+//  2. Conflict: the merge is aborted and a *MergeConflictError lists the
+//     conflicting tables; main is unchanged and the branch is kept
 //
-// On conflict, a second script runs with autocommit disabled so conflicts can
-// be resolved rather than triggering an automatic rollback.
+// On conflict, a second script replays the merge with autocommit disabled
+// to read dolt_conflicts, then rolls it back. Resolve conflicts with
+// ResolveMergeConflicts ('gt dolt conflicts').
 func MergePolecatBranch(townRoot, rigDB, branchName string) error {
 	if err := validateBranchName(branchName); err != nil {
 		return fmt.Errorf("merging Dolt branch in %s: %w", rigDB, err)
 	}
+	if err := chaos.Inject(chaos.DoltMerge); err != nil {
+		return fmt.Errorf("merging %s to main in %s: %w", branchName, rigDB, err)
+	}
 
 	// Phase 1: Commit polecat working set and attempt merge.
 	// All in one connection so DOLT_CHECKOUT persists across statements.
@@ -2055,36 +2203,37 @@ CALL DOLT_MERGE('%s');
 			return fmt.Errorf("merging %s to main in %s: %w", branchName, rigDB, err)
 		}
 
-		// Phase 2: Conflict detected. Re-run merge with autocommit disabled
-		// so conflicts are staged (not rolled back) and can be resolved.
-		// --theirs: polecat state wins (latest mutations, always authoritative).
-		fmt.Printf("Dolt merge conflict on %s, auto-resolving (--theirs)...\n", branchName)
-		conflictScript := fmt.Sprintf(`USE %s;
-SET @@autocommit = 0;
-CALL DOLT_CHECKOUT('main');
-CALL DOLT_MERGE('%s');
-CALL DOLT_CONFLICTS_RESOLVE('--theirs', '.');
-CALL DOLT_COMMIT('-m', 'merge %s (conflicts auto-resolved)');
-SET @@autocommit = 1;
-`, rigDB, escaped, escaped)
-
-		if err := doltSQLScriptWithRetry(townRoot, conflictScript); err != nil {
-			return fmt.Errorf("conflict-resolving merge of %s in %s: %w", branchName, rigDB, err)
+		// Phase 2: Conflict detected. The failed merge rolled back with its
+		// transaction; replay it to read dolt_conflicts, then abort, so main
+		// is left as it was and the branch survives for 'gt dolt conflicts'.
+		conflicts, detectErr := detectMergeConflicts(townRoot, rigDB, branchName)
+		if detectErr != nil {
+			return fmt.Errorf("merging %s to main in %s: %w (conflicts could not be listed: %v)", branchName, rigDB, err, detectErr)
 		}
+		return &MergeConflictError{Database: rigDB, Branch: branchName, Conflicts: conflicts}
 	}
 
-	// Delete branch only after successful merge (either phase).
+	// Delete branch only after a successful merge.
 	// This prevents branch loss if the merge script fails partway through.
 	DeletePolecatBranch(townRoot, rigDB, branchName)
 	return nil
 }
 
-// doltSQLScript executes a multi-statement SQL script via a temp file.
-// Uses `dolt sql --file` for reliable multi-statement execution within a
-// single connection, preserving DOLT_CHECKOUT state across statements.
+// doltSQLScript executes a multi-statement SQL script on a single
+// connection, preserving DOLT_CHECKOUT state across statements. Without a
+// running server it goes through `dolt sql --file` and a temp file.
 func doltSQLScript(townRoot, script string) error {
+	if err := chaos.Inject(chaos.DoltConn); err != nil {
+		return err
+	}
 	config := DefaultConfig(townRoot)
 
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, ok, err := serverQuery(ctx, townRoot, "", script); ok {
+		return err
+	}
+
 	tmpFile, err := os.CreateTemp("", "dolt-script-*.sql")
 	if err != nil {
 		return fmt.Errorf("creating temp SQL file: %w", err)
@@ -2097,14 +2246,9 @@ func doltSQLScript(townRoot, script string) error {
 	}
 	tmpFile.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "dolt", "sql", "--file", tmpFile.Name())
-	cmd.Dir = config.DataDir
-	output, err := cmd.CombinedOutput()
+	stdout, stderr, err := runDolt(ctx, config.DataDir, "sql", "--file", tmpFile.Name())
 	if err != nil {
-		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
 	}
 	return nil
 }
@@ -2134,7 +2278,7 @@ func doltSQLScriptWithRetry(townRoot, script string) error {
 						break
 					}
 				}
-				time.Sleep(backoff)
+				clk.Sleep(backoff)
 			}
 			continue
 		}
@@ -2156,3 +2300,130 @@ func DeletePolecatBranch(townRoot, rigDB, branchName string) {
 		fmt.Printf("Warning: could not delete Dolt branch %s: %v\n", branchName, err)
 	}
 }
+
+// DropPolecatBranch force-deletes a polecat's Dolt branch, even if it has
+// commits main lacks. Unlike DeletePolecatBranch, failures are returned.
+func DropPolecatBranch(townRoot, rigDB, branchName string) error {
+	if err := validateBranchName(branchName); err != nil {
+		return fmt.Errorf("deleting Dolt branch in %s: %w", rigDB, err)
+	}
+	if PolecatNameFromBranch(branchName) == "" {
+		return fmt.Errorf("deleting Dolt branch in %s: %q is not a polecat branch", rigDB, branchName)
+	}
+	query := fmt.Sprintf("CALL DOLT_BRANCH('-D', '%s')", branchName)
+	if err := doltSQL(townRoot, rigDB, query); err != nil {
+		return fmt.Errorf("deleting Dolt branch %s in %s: %w", branchName, rigDB, err)
+	}
+	return nil
+}
+
+// polecatBranchRe parses branch names made by PolecatBranchName.
+var polecatBranchRe = regexp.MustCompile(`^polecat-(.+)-(\d+)$`)
+
+// PolecatNameFromBranch returns the polecat name a Dolt branch was created
+// for, or "" if the branch isn't a polecat branch.
+func PolecatNameFromBranch(branch string) string {
+	if m := polecatBranchRe.FindStringSubmatch(branch); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// PolecatBranchCreated returns when a polecat branch was created, from the
+// timestamp PolecatBranchName puts in its name.
+func PolecatBranchCreated(branch string) (time.Time, bool) {
+	m := polecatBranchRe.FindStringSubmatch(branch)
+	if m == nil {
+		return time.Time{}, false
+	}
+	ts, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(ts, 0), true
+}
+
+// DefaultBranchPruneAfterDays is how old a dead polecat's Dolt branch must
+// be before gt dolt prune-branches deletes it.
+const DefaultBranchPruneAfterDays = 7
+
+// BranchPruneAfterDays returns the branch prune age from cfg, or the default.
+func BranchPruneAfterDays(cfg *config.DoltStatsConfig) int {
+	if cfg != nil && cfg.Retention != nil && cfg.Retention.BranchDays > 0 {
+		return cfg.Retention.BranchDays
+	}
+	return DefaultBranchPruneAfterDays
+}
+
+// ListPolecatBranchNames returns the polecat names that still have a Dolt
+// branch in rigDB (unmerged or not yet cleaned up). Reusing such a name would
+// give a new polecat a branch prefix that collides with the old one's.
+func ListPolecatBranchNames(townRoot, rigDB string) ([]string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, "SELECT name FROM dolt_branches WHERE name LIKE 'polecat-%'")
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, rec := range csvRecords(rows) {
+		if name := PolecatNameFromBranch(rec["name"]); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// ListPolecatBranches returns the names of all polecat branches in rigDB.
+func ListPolecatBranches(townRoot, rigDB string) ([]string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, "SELECT name FROM dolt_branches WHERE name LIKE 'polecat-%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, rec := range csvRecords(rows) {
+		if PolecatNameFromBranch(rec["name"]) != "" {
+			branches = append(branches, rec["name"])
+		}
+	}
+	return branches, nil
+}
+
+// FindPolecatBranch returns the newest Dolt branch in rigDB created for
+// polecatName, or "" if it has none.
+func FindPolecatBranch(townRoot, rigDB, polecatName string) (string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, "SELECT name FROM dolt_branches WHERE name LIKE 'polecat-%'")
+	if err != nil {
+		return "", err
+	}
+	var newest string
+	for _, rec := range csvRecords(rows) {
+		branch := rec["name"]
+		if PolecatNameFromBranch(branch) == strings.ToLower(polecatName) && branch > newest {
+			newest = branch
+		}
+	}
+	return newest, nil
+}
+
+// QuarantineBranchName returns the name a polecat's Dolt branch is renamed
+// to when the polecat is quarantined.
+func QuarantineBranchName(branch string) string {
+	return "quarantine-" + strings.TrimPrefix(branch, "polecat-")
+}
+
+// RenameBranch renames a Dolt branch in rigDB. Renaming a polecat's branch
+// revokes its write access: bd calls still carrying the old BD_BRANCH fail
+// instead of landing on the branch, while its history stays intact.
+func RenameBranch(townRoot, rigDB, from, to string) error {
+	for _, name := range []string{from, to} {
+		if err := validateBranchName(name); err != nil {
+			return fmt.Errorf("renaming Dolt branch in %s: %w", rigDB, err)
+		}
+	}
+	query := fmt.Sprintf("CALL DOLT_BRANCH('-m', '%s', '%s')", from, to)
+	if err := doltSQLWithRecovery(townRoot, rigDB, query); err != nil {
+		return fmt.Errorf("renaming Dolt branch %s to %s in %s: %w", from, to, rigDB, err)
+	}
+	return nil
+}
@@ -11,6 +11,10 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // =============================================================================
@@ -852,6 +856,55 @@ func searchSubstr(s, substr string) bool {
 	return false
 }
 
+func TestFindDoltServerOnPort(t *testing.T) {
+	table := proc.NewFakeTable(100, 200)
+	table.SetCmdline(100, "/usr/local/bin/dolt sql-server --port 3307")
+	table.SetCmdline(200, "mysqld --port 3306")
+	table.Listen(3307, 100)
+	table.Listen(3306, 200)
+	defer SetProcessTable(table)()
+
+	if pid := findDoltServerOnPort(3307); pid != 100 {
+		t.Errorf("findDoltServerOnPort(3307) = %d, want 100", pid)
+	}
+	if pid := findDoltServerOnPort(3306); pid != 0 {
+		t.Errorf("findDoltServerOnPort(3306) = %d, want 0 (not dolt)", pid)
+	}
+	if pid := findDoltServerOnPort(3308); pid != 0 {
+		t.Errorf("findDoltServerOnPort(3308) = %d, want 0 (no listener)", pid)
+	}
+}
+
+func TestCleanupStaleDoltLock(t *testing.T) {
+	dbDir := t.TempDir()
+	lockPath := filepath.Join(dbDir, ".dolt", "noms", "LOCK")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	table := proc.NewFakeTable(100)
+	table.Open(lockPath, 100)
+	defer SetProcessTable(table)()
+
+	if err := cleanupStaleDoltLock(dbDir); err != nil {
+		t.Fatalf("cleanupStaleDoltLock: %v", err)
+	}
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("held LOCK was removed: %v", err)
+	}
+
+	_ = table.Kill(100)
+	if err := cleanupStaleDoltLock(dbDir); err != nil {
+		t.Fatalf("cleanupStaleDoltLock: %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("stale LOCK not removed: %v", err)
+	}
+}
+
 func TestFindMigratableDatabases_SkipsAlreadyMigrated(t *testing.T) {
 	townRoot := t.TempDir()
 
@@ -1567,6 +1620,35 @@ func TestDefaultConfig_MaxConnections(t *testing.T) {
 	}
 }
 
+func TestDefaultConfig_TownSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	writeDoltServerSettings(t, townRoot, &config.DoltServerConfig{
+		Port:           3308,
+		User:           "gt",
+		DataDir:        "data/dolt",
+		MaxConnections: 120,
+		StartupTimeout: config.Dur(2 * time.Minute),
+	})
+
+	cfg := DefaultConfig(townRoot)
+	if cfg.Port != 3308 || cfg.User != "gt" || cfg.MaxConnections != 120 || cfg.StartupTimeout != 2*time.Minute {
+		t.Errorf("config = %+v, want settings applied", cfg)
+	}
+	if want := filepath.Join(townRoot, "data", "dolt"); cfg.DataDir != want {
+		t.Errorf("DataDir = %q, want %q", cfg.DataDir, want)
+	}
+	if got := GetConnectionStringForRig(townRoot, "gastown"); got != "gt@tcp(127.0.0.1:3308)/gastown" {
+		t.Errorf("GetConnectionStringForRig = %q", got)
+	}
+
+	// Out-of-range values keep the defaults.
+	writeDoltServerSettings(t, townRoot, &config.DoltServerConfig{Port: 70000, MaxConnections: -1, DataDir: "/srv/dolt"})
+	cfg = DefaultConfig(townRoot)
+	if cfg.Port != DefaultPort || cfg.MaxConnections != DefaultMaxConnections || cfg.DataDir != "/srv/dolt" || cfg.StartupTimeout != DefaultStartupTimeout {
+		t.Errorf("config = %+v, want default port and connections, absolute data dir", cfg)
+	}
+}
+
 func TestHasConnectionCapacity_ZeroMax(t *testing.T) {
 	// When MaxConnections is 0, the function should use Dolt default (1000).
 	// Since we can't connect to a real server in unit tests, we just verify
@@ -2514,6 +2596,43 @@ func TestMergePolecatBranch_ValidBranchName(t *testing.T) {
 	}
 }
 
+func TestPolecatNameFromBranch(t *testing.T) {
+	tests := map[string]string{
+		PolecatBranchName("Furiosa"):  "furiosa",
+		"polecat-max-rock-1700000000": "max-rock",
+		"polecat-51-1700000000":       "51",
+		"polecat-nux":                 "",
+		"main":                        "",
+	}
+	for branch, want := range tests {
+		if got := PolecatNameFromBranch(branch); got != want {
+			t.Errorf("PolecatNameFromBranch(%q) = %q, want %q", branch, got, want)
+		}
+	}
+}
+
+func TestPolecatBranchCreated(t *testing.T) {
+	created, ok := PolecatBranchCreated("polecat-max-rock-1700000000")
+	if !ok || !created.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("PolecatBranchCreated = %v, %v; want %v", created, ok, time.Unix(1700000000, 0))
+	}
+	for _, branch := range []string{"polecat-nux", "main", "polecat-nux-99999999999999999999"} {
+		if _, ok := PolecatBranchCreated(branch); ok {
+			t.Errorf("PolecatBranchCreated(%q) ok, want not a polecat branch", branch)
+		}
+	}
+}
+
+func TestBranchPruneAfterDays(t *testing.T) {
+	if got := BranchPruneAfterDays(nil); got != DefaultBranchPruneAfterDays {
+		t.Errorf("BranchPruneAfterDays(nil) = %d", got)
+	}
+	cfg := &config.DoltStatsConfig{Retention: &config.DoltRetentionConfig{BranchDays: 2}}
+	if got := BranchPruneAfterDays(cfg); got != 2 {
+		t.Errorf("BranchPruneAfterDays = %d, want 2", got)
+	}
+}
+
 // =============================================================================
 // VerifyDatabases tests
 // =============================================================================
@@ -3100,3 +3219,25 @@ func TestFindOrphanedDatabases_EndToEnd(t *testing.T) {
 		t.Errorf("expected 0 orphans after cleanup, got %d", len(orphans))
 	}
 }
+
+func TestFilterMigrations(t *testing.T) {
+	all := []Migration{{RigName: "hq"}, {RigName: "gastown"}, {RigName: "beads"}}
+
+	got, err := FilterMigrations(all, nil)
+	if err != nil || len(got) != 3 {
+		t.Fatalf("no filter: %v, %v", got, err)
+	}
+
+	got, err = FilterMigrations(all, []string{"beads", "hq"})
+	if err != nil {
+		t.Fatalf("FilterMigrations: %v", err)
+	}
+	if len(got) != 2 || got[0].RigName != "hq" || got[1].RigName != "beads" {
+		t.Errorf("selected = %v, want hq then beads in discovery order", got)
+	}
+
+	if _, err := FilterMigrations(all, []string{"gastown", "gastwon"}); err == nil ||
+		!strings.Contains(err.Error(), "gastwon") || !strings.Contains(err.Error(), "pending: hq, gastown, beads") {
+		t.Errorf("unknown rig: err = %v", err)
+	}
+}
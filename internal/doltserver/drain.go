@@ -0,0 +1,103 @@
+package doltserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDrainTimeout is how long Stop's callers wait for clients to
+// disconnect before shutting the server down anyway.
+const DefaultDrainTimeout = 30 * time.Second
+
+// drainPollInterval is how often the connection count is rechecked while draining.
+const drainPollInterval = time.Second
+
+// SetDraining records in the server state whether the server is draining.
+// While draining, HasConnectionCapacity refuses new work so no new agents
+// connect while existing clients finish their transactions.
+func SetDraining(townRoot string, draining bool) error {
+	return setDrainState(townRoot, draining, false)
+}
+
+// SetDrainingAllowSpawns marks the server as draining like SetDraining, but
+// keeps accepting polecat spawns, for quick restarts where a refused sling
+// costs more than a new polecat briefly losing its connection.
+func SetDrainingAllowSpawns(townRoot string) error {
+	return setDrainState(townRoot, true, true)
+}
+
+func setDrainState(townRoot string, draining, allowSpawns bool) error {
+	_, err := UpdateState(townRoot, func(state *State) error {
+		state.Draining = draining
+		state.SpawnsAllowed = draining && allowSpawns
+		if draining {
+			state.DrainingSince = clk.Now()
+		} else {
+			state.DrainingSince = time.Time{}
+		}
+		return nil
+	})
+	return err
+}
+
+// IsDraining reports whether the server is marked as draining.
+func IsDraining(townRoot string) bool {
+	state, err := LoadState(townRoot)
+	return err == nil && state.Draining
+}
+
+// RefusingSpawns reports whether the server is draining and new polecat
+// spawns should be refused.
+func RefusingSpawns(townRoot string) bool {
+	state, err := LoadState(townRoot)
+	return err == nil && state.Draining && !state.SpawnsAllowed
+}
+
+// ClientConnectionCount returns the number of connections to the server that
+// are running a command, not counting the connection used to ask. Idle
+// connections (COMMAND 'Sleep'), such as those parked in a client's pool,
+// have no transaction in flight and are not counted.
+func ClientConnectionCount(townRoot string) (int, error) {
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output, stderr, err := runDolt(ctx, config.DataDir, "sql", "-r", "csv",
+		"-q", "SELECT COUNT(*) AS cnt FROM information_schema.PROCESSLIST WHERE ID <> CONNECTION_ID() AND COMMAND <> 'Sleep'")
+	if err != nil {
+		return 0, fmt.Errorf("querying connection count: %w (output: %s)", err, strings.TrimSpace(string(stderr)))
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return 0, fmt.Errorf("unexpected output from connection count query: %s", string(output))
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(lines[len(lines)-1]))
+	if err != nil {
+		return 0, fmt.Errorf("parsing connection count %q: %w", lines[len(lines)-1], err)
+	}
+	return count, nil
+}
+
+// DrainConnections waits up to timeout for all clients to disconnect.
+// Returns the number of connections still open when it gave up (0 once
+// drained). progress, if non-nil, is called with the count after each poll.
+func DrainConnections(townRoot string, timeout time.Duration, progress func(remaining int)) (int, error) {
+	deadline := clk.Now().Add(timeout)
+	for {
+		count, err := ClientConnectionCount(townRoot)
+		if err != nil {
+			return 0, err
+		}
+		if progress != nil {
+			progress(count)
+		}
+		if count == 0 || !clk.Now().Before(deadline) {
+			return count, nil
+		}
+		clk.Sleep(drainPollInterval)
+	}
+}
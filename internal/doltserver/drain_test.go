@@ -0,0 +1,150 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// connectionCounts returns a FakeRunner that answers successive connection
+// count queries with counts, repeating the last one.
+func connectionCounts(counts ...int) *proc.FakeRunner {
+	i := 0
+	return &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		n := counts[len(counts)-1]
+		if i < len(counts) {
+			n = counts[i]
+		}
+		i++
+		return []byte(fmt.Sprintf("cnt\n%d\n", n)), nil, nil
+	}}
+}
+
+func TestDrainConnections_WaitsForClients(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	defer SetClock(fake)()
+	defer SetRunner(connectionCounts(3, 2, 0))()
+
+	var seen []int
+	remaining, err := DrainConnections(t.TempDir(), 30*time.Second, func(n int) { seen = append(seen, n) })
+	if err != nil {
+		t.Fatalf("DrainConnections: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("remaining = %d, want 0", remaining)
+	}
+	if fmt.Sprint(seen) != "[3 2 0]" {
+		t.Errorf("progress = %v, want [3 2 0]", seen)
+	}
+	if got := len(fake.Sleeps()); got != 2 {
+		t.Errorf("polled with %d sleeps, want 2", got)
+	}
+}
+
+func TestDrainConnections_Timeout(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Unix(0, 0)))()
+	defer SetRunner(connectionCounts(4))()
+
+	remaining, err := DrainConnections(t.TempDir(), 5*time.Second, nil)
+	if err != nil {
+		t.Fatalf("DrainConnections: %v", err)
+	}
+	if remaining != 4 {
+		t.Errorf("remaining = %d, want 4", remaining)
+	}
+}
+
+func TestDrainConnections_QueryError(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return nil, []byte("connection refused"), errors.New("exit status 1")
+	}})()
+
+	if _, err := DrainConnections(t.TempDir(), time.Second, nil); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("DrainConnections error = %v, want query failure", err)
+	}
+}
+
+func TestClientConnectionCount_SkipsIdleConnections(t *testing.T) {
+	// A processlist with the asking connection, a busy client, and an idle
+	// pooled one; the handler applies the query's filters to it.
+	rows := []struct {
+		id      int
+		command string
+	}{
+		{1, "Query"}, // the connection asking
+		{2, "Query"},
+		{3, "Sleep"},
+	}
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		n := 0
+		for _, r := range rows {
+			if strings.Contains(query, "ID <> CONNECTION_ID()") && r.id == 1 {
+				continue
+			}
+			if strings.Contains(query, "COMMAND <> 'Sleep'") && r.command == "Sleep" {
+				continue
+			}
+			n++
+		}
+		return []byte(fmt.Sprintf("cnt\n%d\n", n)), nil, nil
+	}})()
+
+	count, err := ClientConnectionCount(t.TempDir())
+	if err != nil {
+		t.Fatalf("ClientConnectionCount: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1 (idle connection counted?)", count)
+	}
+}
+
+func TestSetDraining_RefusesCapacity(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := SetDraining(townRoot, true); err != nil {
+		t.Fatalf("SetDraining: %v", err)
+	}
+	if !IsDraining(townRoot) {
+		t.Fatal("IsDraining = false after SetDraining(true)")
+	}
+	if ok, _, err := HasConnectionCapacity(townRoot); ok || err == nil || !strings.Contains(err.Error(), "draining") {
+		t.Errorf("HasConnectionCapacity = %v, %v; want refusal while draining", ok, err)
+	}
+
+	if err := SetDraining(townRoot, false); err != nil {
+		t.Fatalf("SetDraining: %v", err)
+	}
+	state, _ := LoadState(townRoot)
+	if state.Draining || !state.DrainingSince.IsZero() {
+		t.Errorf("state after SetDraining(false) = %+v", state)
+	}
+}
+
+func TestSetDrainingAllowSpawns(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := SetDrainingAllowSpawns(townRoot); err != nil {
+		t.Fatalf("SetDrainingAllowSpawns: %v", err)
+	}
+	if !IsDraining(townRoot) || RefusingSpawns(townRoot) {
+		t.Error("want draining without refusing spawns")
+	}
+
+	// A plain drain refuses spawns again.
+	if err := SetDraining(townRoot, true); err != nil {
+		t.Fatalf("SetDraining: %v", err)
+	}
+	if !RefusingSpawns(townRoot) {
+		t.Error("RefusingSpawns = false after SetDraining(true)")
+	}
+	if err := SetDraining(townRoot, false); err != nil {
+		t.Fatalf("SetDraining: %v", err)
+	}
+	if state, _ := LoadState(townRoot); state.SpawnsAllowed || RefusingSpawns(townRoot) {
+		t.Errorf("state after SetDraining(false) = %+v", state)
+	}
+}
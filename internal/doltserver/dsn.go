@@ -0,0 +1,146 @@
+package doltserver
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// DSNOptions configures a connection to a town's Dolt server. The zero
+// value connects with no database selected and the driver's defaults; use
+// DefaultDSNOptions for the settings gt itself connects with.
+type DSNOptions struct {
+	// Database is the rig database to select. Empty selects none.
+	Database string
+	// Branch qualifies Database as "<database>/<branch>", which Dolt
+	// resolves to that branch's head. Requires Database.
+	Branch string
+
+	// Timeout bounds dialing the server.
+	Timeout time.Duration
+	// ReadTimeout and WriteTimeout bound each network read and write.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// TLS selects transport security: "" or "false" for none, "true",
+	// "skip-verify", "preferred", or a name passed to RegisterTLSConfig.
+	TLS string
+
+	// ReadOnly makes every transaction on the connection read-only.
+	ReadOnly bool
+	// MultiStatements allows several ';'-separated statements per query.
+	MultiStatements bool
+	// ParseTime scans DATE and DATETIME columns into time.Time.
+	ParseTime bool
+}
+
+// DefaultDSNOptions returns the options gt connects with: multi-statement
+// queries, times parsed, and timeouts short enough that a wedged server
+// fails a command instead of hanging it.
+func DefaultDSNOptions(database string) DSNOptions {
+	return DSNOptions{
+		Database:        database,
+		Timeout:         5 * time.Second,
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		MultiStatements: true,
+		ParseTime:       true,
+	}
+}
+
+// RegisterTLSConfig registers a custom TLS configuration with the MySQL
+// driver under name, for use as DSNOptions.TLS.
+func RegisterTLSConfig(name string, config *tls.Config) error {
+	if err := mysql.RegisterTLSConfig(name, config); err != nil {
+		return fmt.Errorf("registering TLS config %q: %w", name, err)
+	}
+	return nil
+}
+
+// dsnConfig builds the driver configuration for townRoot's server.
+func dsnConfig(townRoot string, opts DSNOptions) (*mysql.Config, error) {
+	config := DefaultConfig(townRoot)
+	cfg := mysql.NewConfig()
+	cfg.User = config.User
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("127.0.0.1:%d", config.Port)
+	cfg.DBName = opts.Database
+	if opts.Branch != "" {
+		if opts.Database == "" {
+			return nil, fmt.Errorf("branch %q needs a database", opts.Branch)
+		}
+		if err := validateBranchName(opts.Branch); err != nil {
+			return nil, err
+		}
+		cfg.DBName = opts.Database + "/" + opts.Branch
+	}
+	cfg.Timeout = opts.Timeout
+	cfg.ReadTimeout = opts.ReadTimeout
+	cfg.WriteTimeout = opts.WriteTimeout
+	cfg.MultiStatements = opts.MultiStatements
+	cfg.ParseTime = opts.ParseTime
+	if opts.TLS != "" && opts.TLS != "false" {
+		cfg.TLSConfig = opts.TLS
+	}
+	if opts.ReadOnly {
+		cfg.Params = map[string]string{"transaction_read_only": "1"}
+	}
+	return cfg, nil
+}
+
+// DSN returns the go-sql-driver/mysql data source name for townRoot's Dolt
+// server with opts applied. Unlike GetConnectionStringForRig it carries
+// timeouts and connection parameters, so external tools connect the way gt
+// does.
+func DSN(townRoot string, opts DSNOptions) (string, error) {
+	cfg, err := dsnConfig(townRoot, opts)
+	if err != nil {
+		return "", fmt.Errorf("building Dolt DSN: %w", err)
+	}
+	return cfg.FormatDSN(), nil
+}
+
+// OpenDB opens a connection pool to townRoot's Dolt server with opts
+// applied and checks that the server answers. The caller owns the pool and
+// must Close it.
+func OpenDB(townRoot string, opts DSNOptions) (*sql.DB, error) {
+	cfg, err := dsnConfig(townRoot, opts)
+	if err != nil {
+		return nil, fmt.Errorf("building Dolt DSN: %w", err)
+	}
+	db, err := openPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connecting to Dolt server at %s: %w", cfg.Addr, err)
+	}
+	return db, nil
+}
+
+// openPool opens a pool for cfg without connecting.
+func openPool(cfg *mysql.Config) (*sql.DB, error) {
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("opening Dolt server connection: %w", err)
+	}
+	db := sql.OpenDB(connector)
+	// A few warm connections serve a CLI invocation; the server's own
+	// max_connections guards the town as a whole.
+	db.SetMaxOpenConns(4)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxIdleTime(time.Minute)
+	return db, nil
+}
@@ -0,0 +1,82 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestDSN_Defaults(t *testing.T) {
+	townRoot := t.TempDir()
+	dsn, err := DSN(townRoot, DefaultDSNOptions("hq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("ParseDSN(%q): %v", dsn, err)
+	}
+	if cfg.User != "root" || cfg.Addr != "127.0.0.1:3307" || cfg.DBName != "hq" {
+		t.Errorf("DSN %q: user=%q addr=%q db=%q", dsn, cfg.User, cfg.Addr, cfg.DBName)
+	}
+	if !cfg.MultiStatements || !cfg.ParseTime {
+		t.Errorf("DSN %q: multiStatements=%v parseTime=%v, want both", dsn, cfg.MultiStatements, cfg.ParseTime)
+	}
+	if cfg.Timeout != 5*time.Second || cfg.ReadTimeout != 30*time.Second || cfg.WriteTimeout != 30*time.Second {
+		t.Errorf("DSN %q: timeouts %v/%v/%v", dsn, cfg.Timeout, cfg.ReadTimeout, cfg.WriteTimeout)
+	}
+	if cfg.TLSConfig != "" || len(cfg.Params) != 0 {
+		t.Errorf("DSN %q: tls=%q params=%v, want none", dsn, cfg.TLSConfig, cfg.Params)
+	}
+}
+
+func TestDSN_BranchReadOnlyTLS(t *testing.T) {
+	townRoot := t.TempDir()
+	opts := DefaultDSNOptions("gastown")
+	opts.Branch = "polecat-toast-1712345678"
+	opts.ReadOnly = true
+	opts.TLS = "skip-verify"
+	dsn, err := DSN(townRoot, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("ParseDSN(%q): %v", dsn, err)
+	}
+	if cfg.DBName != "gastown/polecat-toast-1712345678" {
+		t.Errorf("DBName = %q, want branch-qualified", cfg.DBName)
+	}
+	if cfg.Params["transaction_read_only"] != "1" {
+		t.Errorf("Params = %v, want transaction_read_only=1", cfg.Params)
+	}
+	if cfg.TLSConfig != "skip-verify" {
+		t.Errorf("TLSConfig = %q, want skip-verify", cfg.TLSConfig)
+	}
+	if !strings.HasPrefix(dsn, "root@tcp(127.0.0.1:3307)/") {
+		t.Errorf("DSN %q has unexpected prefix", dsn)
+	}
+}
+
+func TestDSN_Errors(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := DSN(townRoot, DSNOptions{Branch: "main"}); err == nil {
+		t.Error("expected error for branch without database")
+	}
+	if _, err := DSN(townRoot, DSNOptions{Database: "hq", Branch: "bad'; DROP"}); err == nil {
+		t.Error("expected error for invalid branch name")
+	}
+}
+
+func TestOpenDB_UnknownTLSConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	opts := DefaultDSNOptions("hq")
+	opts.Timeout = 200 * time.Millisecond
+	opts.TLS = "unregistered-config"
+	if db, err := OpenDB(townRoot, opts); err == nil {
+		db.Close()
+		t.Fatal("expected error opening with an unregistered TLS config")
+	}
+}
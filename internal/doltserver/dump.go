@@ -0,0 +1,229 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// Rig dump formats.
+const (
+	// DumpFormatSQL is a dolt dump of every table's schema and data.
+	DumpFormatSQL = "sql"
+
+	// DumpFormatJSONL is bd's JSONL export of the issues table only.
+	DumpFormatJSONL = "jsonl"
+)
+
+// dumpTimeout bounds a single dump or import; large rigs take minutes.
+const dumpTimeout = 10 * time.Minute
+
+// DumpResult describes a rig dump written or loaded.
+type DumpResult struct {
+	Database string `json:"database"`
+	Path     string `json:"path"`
+	Format   string `json:"format"`
+	Bytes    int64  `json:"bytes"`
+
+	// Created is true when import created the database.
+	Created bool `json:"created,omitempty"`
+
+	// Backup is the archive of the database taken before import replaced
+	// its tables.
+	Backup string `json:"backup,omitempty"`
+}
+
+// DumpFormatForPath infers a dump's format from its file name: .jsonl is
+// DumpFormatJSONL, anything else DumpFormatSQL.
+func DumpFormatForPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".jsonl") {
+		return DumpFormatJSONL
+	}
+	return DumpFormatSQL
+}
+
+// ExportRigDump writes rigDB to outPath. DumpFormatSQL runs dolt dump,
+// producing schema and data without a CREATE DATABASE, so the dump loads
+// into a rig of any name. DumpFormatJSONL exports the issues table with
+// bd export. An existing outPath is only overwritten with force.
+func ExportRigDump(townRoot, rigDB, outPath, format string, force bool) (*DumpResult, error) {
+	if err := validateRigName(rigDB); err != nil {
+		return nil, err
+	}
+	if !DatabaseExists(townRoot, rigDB) {
+		return nil, fmt.Errorf("database %q not found in %s", rigDB, DefaultConfig(townRoot).DataDir)
+	}
+	out, err := filepath.Abs(outPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(out); err == nil && !force {
+		return nil, fmt.Errorf("%s already exists (use --force to overwrite)", out)
+	}
+
+	switch format {
+	case DumpFormatSQL:
+		ctx, cancel := context.WithTimeout(context.Background(), dumpTimeout)
+		defer cancel()
+		stdout, stderr, err := runDolt(ctx, RigDatabaseDir(townRoot, rigDB),
+			"dump", "-r", "sql", "-fn", out, "-f", "--no-create-db")
+		if err != nil {
+			return nil, fmt.Errorf("dumping %s: %w (output: %s)", rigDB, err, strings.TrimSpace(string(stdout)+string(stderr)))
+		}
+	case DumpFormatJSONL:
+		beadsDir := FindRigBeadsDir(townRoot, rigDB)
+		if _, err := os.Stat(beadsDir); err != nil {
+			return nil, fmt.Errorf("beads directory for %s: %w", rigDB, err)
+		}
+		if err := runBdExport(beadsDir, out); err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", rigDB, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown dump format %q (want %s or %s)", format, DumpFormatSQL, DumpFormatJSONL)
+	}
+
+	result := &DumpResult{Database: rigDB, Path: out, Format: format}
+	if info, err := os.Stat(out); err == nil {
+		result.Bytes = info.Size()
+	}
+	return result, nil
+}
+
+// ImportRigDump loads a dump written by ExportRigDump into rigDB, in the
+// format DumpFormatForPath infers from inPath.
+//
+// A SQL dump needs a database without tables: one that doesn't exist yet
+// is created, and with replace an existing one is backed up as for
+// DropRig and its tables dropped first. The load is committed. A JSONL
+// dump is handed to bd import, which merges the issues by ID.
+func ImportRigDump(townRoot, rigDB, inPath string, replace bool) (*DumpResult, error) {
+	if err := validateRigName(rigDB); err != nil {
+		return nil, err
+	}
+	in, err := filepath.Abs(inPath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(in)
+	if err != nil {
+		return nil, err
+	}
+	result := &DumpResult{Database: rigDB, Path: in, Format: DumpFormatForPath(in), Bytes: info.Size()}
+
+	if result.Format == DumpFormatJSONL {
+		beadsDir := FindRigBeadsDir(townRoot, rigDB)
+		if _, err := os.Stat(beadsDir); err != nil {
+			return nil, fmt.Errorf("beads directory for %s: %w", rigDB, err)
+		}
+		if err := runBdImport(beadsDir, in); err != nil {
+			return nil, fmt.Errorf("importing into %s: %w", rigDB, err)
+		}
+		return result, nil
+	}
+
+	dump, err := os.ReadFile(in) //nolint:gosec // G304: path is the user's dump file
+	if err != nil {
+		return nil, err
+	}
+
+	var drops strings.Builder
+	if !DatabaseExists(townRoot, rigDB) {
+		if _, _, err := InitRig(townRoot, rigDB); err != nil {
+			return nil, fmt.Errorf("creating database %s: %w", rigDB, err)
+		}
+		result.Created = true
+	} else {
+		tables, err := listTables(townRoot, rigDB)
+		if err != nil {
+			return nil, fmt.Errorf("listing tables in %s: %w", rigDB, err)
+		}
+		if len(tables) > 0 && !replace {
+			return nil, fmt.Errorf("database %q already has %d table(s); import into a new rig or use --replace", rigDB, len(tables))
+		}
+		if len(tables) > 0 {
+			if result.Backup, err = BackupDatabaseDir(townRoot, rigDB); err != nil {
+				return nil, fmt.Errorf("backing up before import: %w", err)
+			}
+			drops.WriteString("SET FOREIGN_KEY_CHECKS = 0;\n")
+			for _, t := range tables {
+				fmt.Fprintf(&drops, "DROP TABLE IF EXISTS `%s`;\n", t)
+			}
+		}
+	}
+
+	msg := strings.ReplaceAll(fmt.Sprintf("gt dolt import: %s", filepath.Base(in)), "'", "''")
+	script := fmt.Sprintf("USE `%s`;\n%s%s\nSET FOREIGN_KEY_CHECKS = 1;\nCALL DOLT_COMMIT('-Am', '%s', '--allow-empty');\n",
+		rigDB, drops.String(), dump, msg)
+	if err := runDumpScript(townRoot, script); err != nil {
+		return result, fmt.Errorf("loading %s into %s: %w", filepath.Base(in), rigDB, err)
+	}
+	return result, nil
+}
+
+// listTables returns the names of rigDB's tables.
+func listTables(townRoot, rigDB string) ([]string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, "SHOW TABLES")
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for i, row := range rows {
+		if i > 0 && len(row) > 0 && row[0] != "" {
+			tables = append(tables, row[0])
+		}
+	}
+	return tables, nil
+}
+
+// runDumpScript runs a dump-sized SQL script on the server, or with dolt
+// sql --file when none is running.
+func runDumpScript(townRoot, script string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dumpTimeout)
+	defer cancel()
+	if _, ok, err := serverQuery(ctx, townRoot, "", script); ok {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp("", "dolt-import-*.sql")
+	if err != nil {
+		return fmt.Errorf("creating temp SQL file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(script); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing SQL script: %w", err)
+	}
+	tmpFile.Close()
+
+	stdout, stderr, err := runDolt(ctx, DefaultConfig(townRoot).DataDir, "sql", "--file", tmpFile.Name())
+	if err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
+	}
+	return nil
+}
+
+// runBdImport runs `bd import -i inPath` against the rig's beads directory.
+func runBdImport(beadsDir, inPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dumpTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "bd", "import", "-i", inPath)
+	cmd.Dir = filepath.Dir(beadsDir)
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w (%s)", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// makeRigDatabase creates an empty .dolt-data/<rig>/.dolt so DatabaseExists
+// reports the rig.
+func makeRigDatabase(t *testing.T, townRoot, rig string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(RigDatabaseDir(townRoot, rig), ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportRigDump_SQL(t *testing.T) {
+	townRoot := t.TempDir()
+	makeRigDatabase(t, townRoot, "gastown")
+	out := filepath.Join(t.TempDir(), "gastown.sql")
+
+	var got proc.Cmd
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		got = c
+		return nil, nil, os.WriteFile(out, []byte("CREATE TABLE `issues` (id varchar(255));\n"), 0644)
+	}})()
+
+	result, err := ExportRigDump(townRoot, "gastown", out, DumpFormatSQL, false)
+	if err != nil {
+		t.Fatalf("ExportRigDump: %v", err)
+	}
+	if got.Dir != RigDatabaseDir(townRoot, "gastown") {
+		t.Errorf("dump ran in %s, want the rig database directory", got.Dir)
+	}
+	if !slices.Contains(got.Args, "dump") || !slices.Contains(got.Args, "--no-create-db") || !slices.Contains(got.Args, out) {
+		t.Errorf("args = %v", got.Args)
+	}
+	if result.Bytes == 0 || result.Path != out {
+		t.Errorf("result = %+v", result)
+	}
+
+	// An existing file is not overwritten without force.
+	if _, err := ExportRigDump(townRoot, "gastown", out, DumpFormatSQL, false); err == nil {
+		t.Error("expected error overwriting an existing dump")
+	}
+	if _, err := ExportRigDump(townRoot, "missing", out+".2", DumpFormatSQL, false); err == nil {
+		t.Error("expected error for a missing database")
+	}
+}
+
+func TestImportRigDump_SQL(t *testing.T) {
+	townRoot := t.TempDir()
+	makeRigDatabase(t, townRoot, "gastown")
+	dump := filepath.Join(t.TempDir(), "gastown.sql")
+	if err := os.WriteFile(dump, []byte("CREATE TABLE `issues` (id varchar(255));\nINSERT INTO `issues` VALUES ('gt-1');\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tables := "Tables_in_gastown\nissues\nlabels\n"
+	var script string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if slices.Contains(c.Args, "--file") {
+			data, _ := os.ReadFile(c.Args[len(c.Args)-1])
+			script = string(data)
+			return nil, nil, nil
+		}
+		return []byte(tables), nil, nil
+	}})()
+
+	// A rig with tables needs --replace.
+	if _, err := ImportRigDump(townRoot, "gastown", dump, false); err == nil || !strings.Contains(err.Error(), "--replace") {
+		t.Fatalf("err = %v, want refusal without --replace", err)
+	}
+
+	result, err := ImportRigDump(townRoot, "gastown", dump, true)
+	if err != nil {
+		t.Fatalf("ImportRigDump: %v", err)
+	}
+	if result.Backup == "" {
+		t.Error("expected a backup before replacing tables")
+	}
+	for _, want := range []string{"USE `gastown`;", "DROP TABLE IF EXISTS `issues`;", "DROP TABLE IF EXISTS `labels`;", "INSERT INTO `issues`", "DOLT_COMMIT"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Index(script, "DROP TABLE") > strings.Index(script, "CREATE TABLE") {
+		t.Error("tables should be dropped before the dump is loaded")
+	}
+
+	// An empty database loads without --replace or a backup.
+	tables = "Tables_in_gastown\n"
+	script = ""
+	result, err = ImportRigDump(townRoot, "gastown", dump, false)
+	if err != nil {
+		t.Fatalf("ImportRigDump into empty database: %v", err)
+	}
+	if result.Backup != "" || strings.Contains(script, "DROP TABLE") {
+		t.Errorf("empty database: backup %q, script:\n%s", result.Backup, script)
+	}
+}
+
+func TestDumpFormatForPath(t *testing.T) {
+	if DumpFormatForPath("issues.JSONL") != DumpFormatJSONL || DumpFormatForPath("gastown.sql") != DumpFormatSQL {
+		t.Error("wrong format inferred")
+	}
+}
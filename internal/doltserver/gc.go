@@ -0,0 +1,56 @@
+package doltserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// gcTimeout bounds one database's garbage collection, which rewrites its
+// chunk store and can take minutes on a large database.
+const gcTimeout = 30 * time.Minute
+
+// GCResult is the outcome of garbage-collecting one rig database.
+type GCResult struct {
+	Database    string        `json:"database"`
+	BeforeBytes int64         `json:"before_bytes"`
+	AfterBytes  int64         `json:"after_bytes"`
+	Duration    time.Duration `json:"duration_ns"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// Reclaimed returns the bytes the collection freed (negative if the
+// database grew meanwhile).
+func (r GCResult) Reclaimed() int64 {
+	return r.BeforeBytes - r.AfterBytes
+}
+
+// GCDatabase runs Dolt garbage collection on a rig database and reports
+// its on-disk size before and after. With a running server it calls
+// DOLT_GC(), which closes the server's open connections when it finishes
+// (clients reconnect); otherwise it runs dolt gc in the database directory.
+// A failed collection is reported in the result's Error.
+func GCDatabase(townRoot, rigDB string) GCResult {
+	dir := RigDatabaseDir(townRoot, rigDB)
+	result := GCResult{Database: rigDB, BeforeBytes: dirSize(dir)}
+	start := clk.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), gcTimeout)
+	defer cancel()
+	_, ok, err := serverQuery(ctx, townRoot, rigDB, "CALL DOLT_GC()")
+	if !ok {
+		var stdout, stderr []byte
+		stdout, stderr, err = runDolt(ctx, dir, "gc")
+		if err != nil {
+			err = fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
+		}
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("gc %s: %v", rigDB, err)
+	}
+
+	result.Duration = clk.Since(start)
+	result.AfterBytes = dirSize(dir)
+	return result
+}
@@ -0,0 +1,49 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestGCDatabase(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := RigDatabaseDir(townRoot, "gastown")
+	garbage := filepath.Join(dir, ".dolt", "noms", "oldgen")
+	if err := os.MkdirAll(filepath.Dir(garbage), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(garbage, make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if c.Name != "dolt" || len(c.Args) != 1 || c.Args[0] != "gc" || c.Dir != dir {
+			t.Errorf("unexpected command %s %v in %s", c.Name, c.Args, c.Dir)
+		}
+		return nil, nil, os.Remove(garbage)
+	}})()
+
+	result := GCDatabase(townRoot, "gastown")
+	if result.Error != "" {
+		t.Fatalf("GCDatabase error: %s", result.Error)
+	}
+	if result.BeforeBytes != 4096 || result.AfterBytes != 0 || result.Reclaimed() != 4096 {
+		t.Errorf("result = %+v, want 4096 bytes reclaimed", result)
+	}
+}
+
+func TestGCDatabase_Error(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return nil, []byte("database is locked"), errors.New("exit status 1")
+	}})()
+
+	result := GCDatabase(t.TempDir(), "gastown")
+	if !strings.Contains(result.Error, "database is locked") {
+		t.Errorf("Error = %q, want the dolt output", result.Error)
+	}
+}
@@ -0,0 +1,211 @@
+package doltserver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// hotColumn is a column bd filters or sorts on in its list and ready
+// queries, with a probe query that exercises it.
+type hotColumn struct {
+	Table  string
+	Column string
+	Probe  string
+}
+
+// hotColumns are the columns the index advisor expects to be indexed.
+// Full scans on these are what make bd list and bd ready slow down as a
+// rig's bead count grows.
+var hotColumns = []hotColumn{
+	{"issues", "status", "SELECT COUNT(*) AS n FROM issues WHERE status = 'open'"},
+	{"issues", "assignee", "SELECT COUNT(*) AS n FROM issues WHERE assignee = 'gt-index-probe'"},
+	{"issues", "updated_at", "SELECT id FROM issues ORDER BY updated_at DESC LIMIT 50"},
+	{"labels", "label", "SELECT COUNT(*) AS n FROM labels WHERE label = 'gt-index-probe'"},
+}
+
+// TableIndex is one index on a table, with its columns in index order.
+type TableIndex struct {
+	Table   string   `json:"table"`
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// IndexRecommendation is a missing index on a hot column.
+type IndexRecommendation struct {
+	Table     string `json:"table"`
+	Column    string `json:"column"`
+	Index     string `json:"index"`
+	Statement string `json:"statement"`
+	Applied   bool   `json:"applied"`
+}
+
+// IndexAdvice is the index advisor's result for one database. Before and
+// After are the mean time of one pass over the bench queries; After is
+// only set when recommendations were applied.
+type IndexAdvice struct {
+	Database        string                `json:"database"`
+	Existing        []TableIndex          `json:"existing"`
+	Recommendations []IndexRecommendation `json:"recommendations"`
+	Before          time.Duration         `json:"before_ns"`
+	After           time.Duration         `json:"after_ns,omitempty"`
+	Committed       bool                  `json:"committed"`
+	Error           string                `json:"error,omitempty"`
+}
+
+// AdviseIndexes inspects each database's indexes and recommends one for
+// every hot column (issues.status, issues.assignee, issues.updated_at,
+// labels.label) that no existing index leads with. Latency is measured by
+// running the ready and open-count queries plus a probe per hot column
+// runs times. With apply, the recommended indexes are created in one Dolt
+// commit and the queries are timed again. With no databases given, every
+// database in .dolt-data/ is checked. A failing database is reported in
+// its result and does not stop the others.
+func AdviseIndexes(townRoot string, databases []string, apply bool, runs int) ([]IndexAdvice, error) {
+	if len(databases) == 0 {
+		var err error
+		databases, err = ListDatabases(townRoot)
+		if err != nil {
+			return nil, fmt.Errorf("listing databases: %w", err)
+		}
+	}
+	if runs < 1 {
+		runs = 1
+	}
+	results := make([]IndexAdvice, 0, len(databases))
+	for _, db := range databases {
+		results = append(results, adviseDatabase(townRoot, db, apply, runs))
+	}
+	return results, nil
+}
+
+func adviseDatabase(townRoot, db string, apply bool, runs int) IndexAdvice {
+	advice := IndexAdvice{Database: db, Existing: []TableIndex{}, Recommendations: []IndexRecommendation{}}
+	indexes, err := ListIndexes(townRoot, db)
+	if err != nil {
+		advice.Error = err.Error()
+		return advice
+	}
+	advice.Existing = indexes
+	columns, err := databaseColumns(townRoot, db)
+	if err != nil {
+		advice.Error = err.Error()
+		return advice
+	}
+
+	var probes []string
+	for _, h := range hotColumns {
+		if !columns[h.Table+"."+h.Column] {
+			continue // older bd schema without this table or column
+		}
+		probes = append(probes, h.Probe)
+		if leadingIndex(indexes, h.Table, h.Column) != "" {
+			continue
+		}
+		name := fmt.Sprintf("idx_%s_%s", h.Table, h.Column)
+		advice.Recommendations = append(advice.Recommendations, IndexRecommendation{
+			Table:     h.Table,
+			Column:    h.Column,
+			Index:     name,
+			Statement: fmt.Sprintf("CREATE INDEX `%s` ON `%s` (`%s`)", name, h.Table, h.Column),
+		})
+	}
+	bench := append(append([]string{}, warmQueries...), probes...)
+
+	if advice.Before, err = benchQueries(townRoot, db, bench, runs); err != nil {
+		advice.Error = err.Error()
+		return advice
+	}
+	if !apply || len(advice.Recommendations) == 0 {
+		return advice
+	}
+
+	stmts := make([]string, 0, len(advice.Recommendations)+1)
+	names := make([]string, 0, len(advice.Recommendations))
+	for _, r := range advice.Recommendations {
+		stmts = append(stmts, r.Statement)
+		names = append(names, r.Table+"."+r.Column)
+	}
+	stmts = append(stmts, fmt.Sprintf("CALL DOLT_COMMIT('-Am', 'gt dolt schema indexes: index %s')",
+		sqlEscape(strings.Join(names, ", "))))
+	if _, err := doltQueryCSV(townRoot, db, strings.Join(stmts, "; ")); err != nil {
+		advice.Error = fmt.Sprintf("creating indexes in %s: %v", db, err)
+		return advice
+	}
+	for i := range advice.Recommendations {
+		advice.Recommendations[i].Applied = true
+	}
+	advice.Committed = true
+
+	if advice.After, err = benchQueries(townRoot, db, bench, runs); err != nil {
+		advice.Error = err.Error()
+	}
+	return advice
+}
+
+// ListIndexes returns the indexes on every table in a rig database,
+// ordered by table and index name.
+func ListIndexes(townRoot, rigDB string) ([]TableIndex, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT table_name AS tbl, index_name AS name, column_name AS col FROM information_schema.statistics "+
+			"WHERE table_schema = '%s' ORDER BY table_name, index_name, seq_in_index", sqlEscape(rigDB)))
+	if err != nil {
+		return nil, fmt.Errorf("listing indexes in %s: %w", rigDB, err)
+	}
+	var indexes []TableIndex
+	for _, rec := range csvRecords(rows) {
+		n := len(indexes)
+		if n > 0 && indexes[n-1].Table == rec["tbl"] && indexes[n-1].Name == rec["name"] {
+			indexes[n-1].Columns = append(indexes[n-1].Columns, rec["col"])
+			continue
+		}
+		indexes = append(indexes, TableIndex{Table: rec["tbl"], Name: rec["name"], Columns: []string{rec["col"]}})
+	}
+	return indexes, nil
+}
+
+// databaseColumns returns the set of "table.column" names in rigDB.
+func databaseColumns(townRoot, rigDB string) (map[string]bool, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT table_name AS tbl, column_name AS col FROM information_schema.columns WHERE table_schema = '%s'",
+		sqlEscape(rigDB)))
+	if err != nil {
+		return nil, fmt.Errorf("reading columns in %s: %w", rigDB, err)
+	}
+	columns := make(map[string]bool)
+	for _, rec := range csvRecords(rows) {
+		columns[strings.ToLower(rec["tbl"]+"."+rec["col"])] = true
+	}
+	return columns, nil
+}
+
+// leadingIndex returns the name of an index on table whose first column is
+// column, or "" if there is none. Only the leading column lets the server
+// use an index for a filter or sort on column alone.
+func leadingIndex(indexes []TableIndex, table, column string) string {
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.Table, table) && len(idx.Columns) > 0 && strings.EqualFold(idx.Columns[0], column) {
+			return idx.Name
+		}
+	}
+	return ""
+}
+
+// benchQueries runs queries once to warm the server, then runs them runs
+// more times and returns the mean time of one pass.
+func benchQueries(townRoot, db string, queries []string, runs int) (time.Duration, error) {
+	for _, q := range queries {
+		if _, err := doltQueryCSV(townRoot, db, q); err != nil {
+			return 0, fmt.Errorf("bench query in %s: %w", db, err)
+		}
+	}
+	start := clk.Now()
+	for i := 0; i < runs; i++ {
+		for _, q := range queries {
+			if _, err := doltQueryCSV(townRoot, db, q); err != nil {
+				return 0, fmt.Errorf("bench query in %s: %w", db, err)
+			}
+		}
+	}
+	return clk.Now().Sub(start) / time.Duration(runs), nil
+}
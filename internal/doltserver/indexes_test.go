@@ -0,0 +1,74 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestAdviseIndexes(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	indexed := false
+	var applied string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		switch {
+		case strings.Contains(query, "information_schema.statistics"):
+			out := "tbl,name,col\nissues,PRIMARY,id\nissues,idx_issues_status_priority,status\nissues,idx_issues_status_priority,priority\nlabels,PRIMARY,issue_id\nlabels,PRIMARY,label\n"
+			return []byte(out), nil, nil
+		case strings.Contains(query, "information_schema.columns"):
+			return []byte("tbl,col\nissues,id\nissues,status\nissues,priority\nissues,assignee\nissues,updated_at\nlabels,issue_id\nlabels,label\n"), nil, nil
+		case strings.Contains(query, "CREATE INDEX"):
+			applied = query
+			indexed = true
+			return nil, nil, nil
+		}
+		if indexed {
+			fake.Advance(10 * time.Millisecond)
+		} else {
+			fake.Advance(100 * time.Millisecond)
+		}
+		return []byte("n\n3\n"), nil, nil
+	}})()
+
+	results, err := AdviseIndexes(t.TempDir(), []string{"gastown"}, false, 2)
+	if err != nil {
+		t.Fatalf("AdviseIndexes: %v", err)
+	}
+	a := results[0]
+	if a.Error != "" {
+		t.Fatalf("error = %s", a.Error)
+	}
+	if len(a.Existing) != 3 || len(a.Existing[1].Columns) != 2 {
+		t.Errorf("existing = %+v, want 3 indexes with the composite grouped", a.Existing)
+	}
+	var got []string
+	for _, r := range a.Recommendations {
+		got = append(got, r.Table+"."+r.Column)
+	}
+	if want := "issues.assignee,issues.updated_at,labels.label"; strings.Join(got, ",") != want {
+		t.Errorf("recommendations = %v, want %s", got, want)
+	}
+	// 2 warm queries + 4 probes at 100ms each.
+	if a.Before != 600*time.Millisecond || a.After != 0 || a.Committed {
+		t.Errorf("dry run = %+v, want 600ms before and nothing applied", a)
+	}
+
+	results, err = AdviseIndexes(t.TempDir(), []string{"gastown"}, true, 2)
+	if err != nil {
+		t.Fatalf("AdviseIndexes apply: %v", err)
+	}
+	a = results[0]
+	if !a.Committed || !a.Recommendations[0].Applied || a.After != 60*time.Millisecond {
+		t.Errorf("apply = %+v, want committed with 60ms after", a)
+	}
+	for _, want := range []string{"CREATE INDEX `idx_issues_assignee` ON `issues` (`assignee`)", "CREATE INDEX `idx_labels_label`", "CALL DOLT_COMMIT"} {
+		if !strings.Contains(applied, want) {
+			t.Errorf("apply script missing %q:\n%s", want, applied)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// jsonlExportTimeout bounds a single bd export run. Large rigs take 10-25s.
+const jsonlExportTimeout = 2 * time.Minute
+
+// JSONLExportResult describes the outcome of exporting one rig's issues.jsonl.
+type JSONLExportResult struct {
+	// Database is the rig database name.
+	Database string
+
+	// Path is the issues.jsonl that was written.
+	Path string
+
+	// Changed is true if the export differed from the previous file.
+	Changed bool
+
+	// Committed is true if the new file was committed to git.
+	Committed bool
+}
+
+// ExportJSONL regenerates a rig's .beads/issues.jsonl from its database with
+// `bd export`. The file is only replaced when the contents change. If commit
+// is true and the .beads directory is inside a git work tree (and the file is
+// not ignored), the change is committed on its own, leaving any other staged
+// work untouched.
+//
+// This gives git-based consumers a fresh snapshot for rigs running with
+// sync.mode=dolt-native, where bd no longer exports on every write.
+func ExportJSONL(townRoot, rigDB string, commit bool) (*JSONLExportResult, error) {
+	beadsDir := FindRigBeadsDir(townRoot, rigDB)
+	if beadsDir == "" {
+		return nil, fmt.Errorf("no beads directory for %s", rigDB)
+	}
+	if _, err := os.Stat(beadsDir); err != nil {
+		return nil, fmt.Errorf("beads directory for %s: %w", rigDB, err)
+	}
+
+	result := &JSONLExportResult{
+		Database: rigDB,
+		Path:     filepath.Join(beadsDir, "issues.jsonl"),
+	}
+
+	tmpPath := result.Path + ".export"
+	defer func() { _ = os.Remove(tmpPath) }()
+	if err := runBdExport(beadsDir, tmpPath); err != nil {
+		return nil, fmt.Errorf("exporting %s: %w", rigDB, err)
+	}
+
+	fresh, err := os.ReadFile(tmpPath) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, fmt.Errorf("reading export for %s: %w", rigDB, err)
+	}
+	existing, err := os.ReadFile(result.Path) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", result.Path, err)
+	}
+	if bytes.Equal(fresh, existing) {
+		return result, nil
+	}
+	if err := os.Rename(tmpPath, result.Path); err != nil {
+		return nil, fmt.Errorf("replacing %s: %w", result.Path, err)
+	}
+	result.Changed = true
+
+	if !commit {
+		return result, nil
+	}
+	committed, err := commitJSONL(beadsDir, rigDB)
+	if err != nil {
+		return result, fmt.Errorf("committing issues.jsonl for %s: %w", rigDB, err)
+	}
+	result.Committed = committed
+	return result, nil
+}
+
+// runBdExport runs `bd export -o outPath` against the rig's beads directory.
+func runBdExport(beadsDir, outPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), jsonlExportTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "bd", "export", "-o", outPath)
+	cmd.Dir = filepath.Dir(beadsDir)
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w (%s)", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// commitJSONL commits issues.jsonl in beadsDir. Returns false without error
+// when the directory is not in a git work tree or the file is ignored.
+func commitJSONL(beadsDir, rigDB string) (bool, error) {
+	if _, err := runGitIn(beadsDir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return false, nil
+	}
+	if _, err := runGitIn(beadsDir, "check-ignore", "-q", "issues.jsonl"); err == nil {
+		return false, nil
+	}
+	if _, err := runGitIn(beadsDir, "add", "--", "issues.jsonl"); err != nil {
+		return false, err
+	}
+	// --only commits just this path, even if other changes are staged.
+	msg := fmt.Sprintf("bd: scheduled JSONL export for %s", rigDB)
+	if _, err := runGitIn(beadsDir, "commit", "--only", "-m", msg, "--", "issues.jsonl"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func runGitIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// DoltNativeDatabases returns the databases whose sync mode is dolt-native,
+// i.e. the ones bd no longer exports to JSONL on write. Databases whose mode
+// cannot be read are skipped.
+func DoltNativeDatabases(townRoot string) ([]string, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var native []string
+	for _, db := range databases {
+		if mode, err := GetSyncMode(townRoot, db); err == nil && mode == SyncModeDoltNative {
+			native = append(native, db)
+		}
+	}
+	return native, nil
+}
@@ -0,0 +1,127 @@
+package doltserver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installExportMockBd puts a fake bd on PATH whose `export -o <path>` writes
+// the contents of $MOCK_BD_EXPORT to <path>.
+func installExportMockBd(t *testing.T, contents string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("mock bd uses a shell script")
+	}
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+out=""
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -o) shift; out="$1" ;;
+  esac
+  shift
+done
+printf '%s' "$MOCK_BD_EXPORT" > "$out"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("MOCK_BD_EXPORT", contents)
+}
+
+func gitInit(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	townRoot := t.TempDir()
+	clone := filepath.Join(townRoot, "myrig", "mayor", "rig")
+	beadsDir := filepath.Join(clone, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitInit(t, clone)
+
+	// An unrelated staged file must not be swept into the export commit.
+	other := filepath.Join(clone, "other.txt")
+	if err := os.WriteFile(other, []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runGitIn(clone, "add", "other.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	installExportMockBd(t, `{"id":"gt-1"}`+"\n")
+
+	res, err := ExportJSONL(townRoot, "myrig", true)
+	if err != nil {
+		t.Fatalf("ExportJSONL: %v", err)
+	}
+	if !res.Changed || !res.Committed {
+		t.Fatalf("first export: changed=%v committed=%v, want both true", res.Changed, res.Committed)
+	}
+	data, err := os.ReadFile(filepath.Join(beadsDir, "issues.jsonl"))
+	if err != nil || string(data) != `{"id":"gt-1"}`+"\n" {
+		t.Fatalf("issues.jsonl = %q, %v", data, err)
+	}
+	files, err := runGitIn(clone, "show", "--name-only", "--format=", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != ".beads/issues.jsonl" {
+		t.Errorf("commit contains %q, want only .beads/issues.jsonl", files)
+	}
+	status, _ := runGitIn(clone, "status", "--porcelain", "other.txt")
+	if !strings.HasPrefix(status, "A") {
+		t.Errorf("other.txt should remain staged, status %q", status)
+	}
+
+	// Same contents again: nothing to write or commit.
+	res, err = ExportJSONL(townRoot, "myrig", true)
+	if err != nil {
+		t.Fatalf("second ExportJSONL: %v", err)
+	}
+	if res.Changed || res.Committed {
+		t.Errorf("unchanged export: changed=%v committed=%v, want both false", res.Changed, res.Committed)
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "issues.jsonl.export")); !os.IsNotExist(err) {
+		t.Error("temporary export file was left behind")
+	}
+}
+
+func TestExportJSONL_NoCommitOutsideGit(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, "myrig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", townRoot)
+	installExportMockBd(t, `{"id":"gt-2"}`+"\n")
+
+	res, err := ExportJSONL(townRoot, "myrig", true)
+	if err != nil {
+		t.Fatalf("ExportJSONL: %v", err)
+	}
+	if !res.Changed || res.Committed {
+		t.Errorf("changed=%v committed=%v, want changed only", res.Changed, res.Committed)
+	}
+}
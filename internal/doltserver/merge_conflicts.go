@@ -0,0 +1,153 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Merge conflict strategies for ResolveMergeConflicts.
+const (
+	ConflictsOurs   = "ours"   // main's rows win
+	ConflictsTheirs = "theirs" // the polecat branch's rows win
+)
+
+// MergeConflictError is returned by MergePolecatBranch when merging a
+// polecat branch into main conflicts. The merge has been aborted: main is
+// unchanged and the branch is kept for 'gt dolt conflicts'.
+type MergeConflictError struct {
+	Database  string
+	Branch    string
+	Conflicts []TableConflict
+}
+
+func (e *MergeConflictError) Error() string {
+	tables := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		tables[i] = fmt.Sprintf("%s (%d)", c.Table, c.DataConflicts+c.SchemaConflicts)
+	}
+	where := "conflicts"
+	if len(tables) > 0 {
+		where = "conflicts in " + strings.Join(tables, ", ")
+	}
+	return fmt.Sprintf("merging %s to main in %s: %s; merge aborted, resolve with 'gt dolt conflicts %s %s'",
+		e.Branch, e.Database, where, e.Database, e.Branch)
+}
+
+// detectMergeConflicts replays the merge of branchName into main inside a
+// transaction, reads the conflicts it leaves in dolt_conflicts, and rolls
+// the transaction back so main's working set is untouched.
+func detectMergeConflicts(townRoot, rigDB, branchName string) ([]TableConflict, error) {
+	escaped := strings.ReplaceAll(branchName, "'", "''")
+	script := fmt.Sprintf(`USE %s;
+SET @@autocommit = 0;
+CALL DOLT_CHECKOUT('main');
+CALL DOLT_MERGE('%s');
+SELECT `+"`table`"+`, num_conflicts FROM dolt_conflicts;
+ROLLBACK;
+SET @@autocommit = 1;
+`, rigDB, escaped)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rows, ok, err := serverQuery(ctx, townRoot, "", script)
+	if !ok {
+		var stdout, stderr []byte
+		stdout, stderr, err = runDolt(ctx, DefaultConfig(townRoot).DataDir, "sql", "-r", "csv", "-q", script)
+		if err != nil {
+			err = fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
+		}
+		rows = lastCSVResultSet(stdout, "table")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading merge conflicts of %s in %s: %w", branchName, rigDB, err)
+	}
+
+	var conflicts []TableConflict
+	for _, rec := range csvRecords(rows) {
+		n, _ := strconv.Atoi(rec["num_conflicts"])
+		if n > 0 {
+			conflicts = append(conflicts, TableConflict{Table: rec["table"], DataConflicts: n})
+		}
+	}
+	return conflicts, nil
+}
+
+// lastCSVResultSet picks the last result set whose header starts with
+// firstColumn out of dolt sql's CSV output for a multi-statement query,
+// which prints each result set in turn.
+func lastCSVResultSet(output []byte, firstColumn string) [][]string {
+	r := csv.NewReader(bytes.NewReader(output))
+	r.FieldsPerRecord = -1
+	records, _ := r.ReadAll()
+	var set [][]string
+	for _, rec := range records {
+		switch {
+		case len(rec) > 0 && rec[0] == firstColumn:
+			set = [][]string{rec}
+		case set != nil && len(rec) == len(set[0]):
+			set = append(set, rec)
+		}
+	}
+	return set
+}
+
+// BranchConflicts lists the tables a polecat branch conflicts with main on.
+type BranchConflicts struct {
+	Database  string          `json:"database"`
+	Branch    string          `json:"branch"`
+	Conflicts []TableConflict `json:"conflicts"`
+}
+
+// ListMergeConflicts previews the merge of each polecat branch in rigDB
+// into main and returns the branches that would conflict. Nothing is
+// merged.
+func ListMergeConflicts(townRoot, rigDB string) ([]BranchConflicts, error) {
+	branches, err := ListPolecatBranches(townRoot, rigDB)
+	if err != nil {
+		return nil, fmt.Errorf("listing polecat branches in %s: %w", rigDB, err)
+	}
+	var result []BranchConflicts
+	for _, branch := range branches {
+		conflicts, err := previewMergeConflicts(townRoot, rigDB, branch)
+		if err != nil {
+			return nil, fmt.Errorf("previewing merge of %s in %s: %w", branch, rigDB, err)
+		}
+		if len(conflicts) > 0 {
+			result = append(result, BranchConflicts{Database: rigDB, Branch: branch, Conflicts: conflicts})
+		}
+	}
+	return result, nil
+}
+
+// ResolveMergeConflicts merges a polecat branch into main, resolving every
+// conflict with strategy (ConflictsOurs or ConflictsTheirs), then deletes
+// the branch.
+func ResolveMergeConflicts(townRoot, rigDB, branchName, strategy string) error {
+	if err := validateBranchName(branchName); err != nil {
+		return fmt.Errorf("resolving Dolt merge in %s: %w", rigDB, err)
+	}
+	if strategy != ConflictsOurs && strategy != ConflictsTheirs {
+		return fmt.Errorf("unknown conflict strategy %q (want %s or %s)", strategy, ConflictsOurs, ConflictsTheirs)
+	}
+
+	escaped := strings.ReplaceAll(branchName, "'", "''")
+	script := fmt.Sprintf(`USE %s;
+SET @@autocommit = 0;
+CALL DOLT_CHECKOUT('main');
+CALL DOLT_MERGE('%s');
+CALL DOLT_CONFLICTS_RESOLVE('--%s', '.');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'merge %s (conflicts resolved: %s)');
+SET @@autocommit = 1;
+`, rigDB, escaped, strategy, escaped, strategy)
+	if err := doltSQLScriptWithRetry(townRoot, script); err != nil {
+		return fmt.Errorf("merging %s to main in %s (%s): %w", branchName, rigDB, strategy, err)
+	}
+
+	DeletePolecatBranch(townRoot, rigDB, branchName)
+	return nil
+}
@@ -0,0 +1,77 @@
+package doltserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestMergePolecatBranch_ConflictAborts(t *testing.T) {
+	var scripts []string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		switch {
+		case len(c.Args) > 1 && c.Args[1] == "--file":
+			return nil, []byte("Merge conflict detected, transaction rolled back"), errors.New("exit status 1")
+		case len(c.Args) > 1 && c.Args[1] == "-r":
+			scripts = append(scripts, c.Args[len(c.Args)-1])
+			return []byte("hash,fast_forward,conflicts,message\n,0,2,conflicts found\n" +
+				"table,num_conflicts\nissues,2\nlabels,0\n"), nil, nil
+		}
+		t.Fatalf("unexpected dolt call %v", c.Args)
+		return nil, nil, nil
+	}})()
+
+	err := MergePolecatBranch(t.TempDir(), "gastown", "polecat-toast-123")
+	var conflict *MergeConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want *MergeConflictError", err)
+	}
+	if len(conflict.Conflicts) != 1 || conflict.Conflicts[0] != (TableConflict{Table: "issues", DataConflicts: 2}) {
+		t.Errorf("conflicts = %+v, want issues (2)", conflict.Conflicts)
+	}
+	if !strings.Contains(err.Error(), "gt dolt conflicts gastown polecat-toast-123") {
+		t.Errorf("error should point at gt dolt conflicts: %v", err)
+	}
+	if len(scripts) != 1 || !strings.Contains(scripts[0], "ROLLBACK") || strings.Contains(scripts[0], "DOLT_CONFLICTS_RESOLVE") {
+		t.Errorf("detection script should roll back without resolving: %q", scripts)
+	}
+}
+
+func TestResolveMergeConflicts(t *testing.T) {
+	var script string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if len(c.Args) > 1 && c.Args[1] == "--file" {
+			script = "ran"
+		}
+		return nil, nil, nil
+	}})()
+
+	if err := ResolveMergeConflicts(t.TempDir(), "gastown", "polecat-toast-123", "both"); err == nil {
+		t.Error("unknown strategy should fail")
+	}
+	if err := ResolveMergeConflicts(t.TempDir(), "gastown", "'; DROP", ConflictsOurs); err == nil {
+		t.Error("invalid branch name should fail")
+	}
+	if script != "" {
+		t.Fatal("nothing should run for rejected input")
+	}
+	if err := ResolveMergeConflicts(t.TempDir(), "gastown", "polecat-toast-123", ConflictsTheirs); err != nil {
+		t.Fatalf("ResolveMergeConflicts: %v", err)
+	}
+	if script == "" {
+		t.Error("merge script was not run")
+	}
+}
+
+func TestLastCSVResultSet(t *testing.T) {
+	out := []byte("a,b\n1,2\ntable,num_conflicts\nissues,3\ntable,num_conflicts\nlabels,1\n")
+	got := lastCSVResultSet(out, "table")
+	if len(got) != 2 || got[1][0] != "labels" {
+		t.Errorf("lastCSVResultSet = %v, want the last table set", got)
+	}
+	if got := lastCSVResultSet([]byte("a,b\n1,2\n"), "table"); got != nil {
+		t.Errorf("lastCSVResultSet without a match = %v", got)
+	}
+}
@@ -0,0 +1,243 @@
+package doltserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Outcomes of a metadata.json update.
+const (
+	MetadataUnchanged = "unchanged" // already correct; nothing written
+	MetadataWritten   = "written"   // rewritten in place
+	MetadataCommitted = "committed" // tracked in git; rewritten and committed
+	MetadataProposed  = "proposed"  // tracked or read-only; change left in a proposal file
+)
+
+// MetadataCommitMessage is the commit message used for metadata.json
+// updates in rigs that track it.
+const MetadataCommitMessage = "beads: update metadata.json for Dolt server mode"
+
+// MetadataOptions controls how metadata.json updates are applied.
+type MetadataOptions struct {
+	// Commit commits the update when metadata.json is tracked in git.
+	// Otherwise a tracked file is left alone and the update is proposed.
+	Commit bool
+}
+
+// MetadataResult describes the outcome of a metadata.json update.
+type MetadataResult struct {
+	Rig      string `json:"rig"`
+	Path     string `json:"path"`
+	Action   string `json:"action"`
+	Proposal string `json:"proposal,omitempty"` // set when Action is MetadataProposed
+}
+
+// DefaultMetadataOptions returns the metadata update options configured
+// for a town (settings/config.json commit_beads_metadata).
+func DefaultMetadataOptions(townRoot string) MetadataOptions {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return MetadataOptions{}
+	}
+	return MetadataOptions{Commit: settings.CommitBeadsMetadata}
+}
+
+// MetadataProposalPath returns where a proposed metadata.json for a rig is
+// written when the real file can't be rewritten in place. It lives in the
+// town's gitignored runtime directory so proposing a change never dirties a
+// worktree.
+func MetadataProposalPath(townRoot, rigName string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "metadata-proposals", rigName+".json")
+}
+
+// WriteMetadataFile writes data as beadsDir/metadata.json for rigName,
+// touching the file only when it has to:
+//   - content already equal: nothing is written
+//   - tracked in git: with opts.Commit, rewritten and committed on its own;
+//     otherwise left alone and the change written to MetadataProposalPath
+//   - read-only: the change is proposed
+//   - otherwise: rewritten in place
+//
+// Rigs that track metadata.json would otherwise get a dirty worktree every
+// time gastown patched it, which breaks gt done.
+func WriteMetadataFile(townRoot, rigName, beadsDir string, data []byte, opts MetadataOptions) (*MetadataResult, error) {
+	metadataPath := filepath.Join(beadsDir, "metadata.json")
+	res := &MetadataResult{Rig: rigName, Path: metadataPath}
+	proposal := MetadataProposalPath(townRoot, rigName)
+
+	existing, readErr := os.ReadFile(metadataPath)
+	if readErr == nil && bytes.Equal(existing, data) {
+		res.Action = MetadataUnchanged
+		_ = os.Remove(proposal)
+		return res, nil
+	}
+
+	tracked := readErr == nil && isGitTracked(beadsDir, "metadata.json")
+	readOnly := false
+	if info, err := os.Stat(metadataPath); err == nil && info.Mode().Perm()&0200 == 0 {
+		readOnly = true
+	}
+
+	if readOnly || (tracked && !opts.Commit) {
+		if err := os.MkdirAll(filepath.Dir(proposal), 0755); err != nil {
+			return nil, fmt.Errorf("writing metadata proposal: %w", err)
+		}
+		if err := util.AtomicWriteFile(proposal, data, 0600); err != nil {
+			return nil, fmt.Errorf("writing metadata proposal: %w", err)
+		}
+		res.Action = MetadataProposed
+		res.Proposal = proposal
+		return res, nil
+	}
+
+	if err := util.AtomicWriteFile(metadataPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("writing metadata.json: %w", err)
+	}
+	res.Action = MetadataWritten
+	if tracked {
+		if err := commitFile(beadsDir, "metadata.json", MetadataCommitMessage); err != nil {
+			// Don't leave the worktree dirty: put the old content back.
+			_ = util.AtomicWriteFile(metadataPath, existing, 0600)
+			return nil, fmt.Errorf("committing metadata.json: %w", err)
+		}
+		res.Action = MetadataCommitted
+	}
+	_ = os.Remove(proposal)
+	return res, nil
+}
+
+// MetadataDrift is a metadata.json field whose value differs from what
+// gastown expects for the rig.
+type MetadataDrift struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"` // empty when the field is missing
+}
+
+// MetadataVerification is the outcome of checking one rig's metadata.json
+// without changing it.
+type MetadataVerification struct {
+	Rig     string          `json:"rig"`
+	Path    string          `json:"path"`
+	Missing bool            `json:"missing,omitempty"` // no metadata.json at all
+	Drift   []MetadataDrift `json:"drift,omitempty"`
+}
+
+// OK reports whether the metadata matches what gastown expects.
+func (v *MetadataVerification) OK() bool {
+	return !v.Missing && len(v.Drift) == 0
+}
+
+// expectedMetadata returns the fields gastown requires in a rig's
+// metadata.json, in display order.
+func expectedMetadata(townRoot, rigName string) [][2]string {
+	return append([][2]string{
+		{"database", "dolt"},
+		{"backend", "dolt"},
+		{"dolt_mode", "server"},
+		{"dolt_database", rigName},
+		{"jsonl_export", "issues.jsonl"},
+	}, serverMetadata(townRoot)...)
+}
+
+// serverMetadata returns the metadata.json fields that tell bd how to reach
+// a server configured with a port or user other than the defaults. Both are
+// set together so a rig can't end up with only one of them.
+func serverMetadata(townRoot string) [][2]string {
+	cfg := DefaultConfig(townRoot)
+	if cfg.Port == DefaultPort && cfg.User == DefaultUser {
+		return nil
+	}
+	return [][2]string{
+		{"dolt_server_port", strconv.Itoa(cfg.Port)},
+		{"dolt_server_user", cfg.User},
+	}
+}
+
+// VerifyMetadata compares a rig's metadata.json with the Dolt server
+// configuration gastown expects, field by field, without writing anything.
+// Unlike UpdateMetadata, a dolt_database naming a different database is
+// reported as drift: it is usually a hand edit pointing the rig at another
+// rig's issues.
+func VerifyMetadata(townRoot, rigName string) (*MetadataVerification, error) {
+	beadsDir := FindRigBeadsDir(townRoot, rigName)
+	if beadsDir == "" {
+		return nil, fmt.Errorf("resolving beads directory for rig %q", rigName)
+	}
+	v := &MetadataVerification{Rig: rigName, Path: filepath.Join(beadsDir, "metadata.json")}
+
+	data, err := os.ReadFile(v.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			v.Missing = true
+			return v, nil
+		}
+		return nil, fmt.Errorf("reading metadata.json: %w", err)
+	}
+	var actual map[string]interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", v.Path, err)
+	}
+
+	for _, field := range expectedMetadata(townRoot, rigName) {
+		got := ""
+		if val, ok := actual[field[0]]; ok && val != nil {
+			got = fmt.Sprint(val)
+		}
+		if got != field[1] {
+			v.Drift = append(v.Drift, MetadataDrift{Field: field[0], Expected: field[1], Actual: got})
+		}
+	}
+	return v, nil
+}
+
+// VerifyAllMetadata runs VerifyMetadata for every rig database known to the
+// Dolt server, the same set UpdateAllMetadata updates.
+func VerifyAllMetadata(townRoot string) (results []*MetadataVerification, errs []error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, []error{fmt.Errorf("listing databases: %w", err)}
+	}
+	for _, dbName := range databases {
+		if v, err := VerifyMetadata(townRoot, dbName); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dbName, err))
+		} else {
+			results = append(results, v)
+		}
+	}
+	return results, errs
+}
+
+// isGitTracked reports whether name in dir is tracked by the git repository
+// containing dir. Outside a repository nothing is tracked.
+func isGitTracked(dir, name string) bool {
+	cmd := exec.Command("git", "ls-files", "--error-unmatch", "--", name)
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// commitFile stages and commits a single file, leaving anything else that
+// is staged in the repository out of the commit.
+func commitFile(dir, name, message string) error {
+	for _, args := range [][]string{
+		{"add", "--", name},
+		{"commit", "--no-verify", "-m", message, "--", name},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
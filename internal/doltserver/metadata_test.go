@@ -0,0 +1,206 @@
+package doltserver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// trackedMetadataRig creates a rig whose .beads/metadata.json is committed
+// in git without Dolt server config, and returns its beads dir.
+func trackedMetadataRig(t *testing.T, townRoot string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	clone := filepath.Join(townRoot, "myrig", "mayor", "rig")
+	beadsDir := filepath.Join(clone, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitInit(t, clone)
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"database": "beads.db"}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	git(t, clone, "add", ".")
+	git(t, clone, "commit", "-q", "-m", "init")
+	return beadsDir
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func TestUpdateMetadata_TrackedIsProposed(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := trackedMetadataRig(t, townRoot)
+
+	res, err := UpdateMetadata(townRoot, "myrig", MetadataOptions{})
+	if err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if res.Action != MetadataProposed || res.Proposal != MetadataProposalPath(townRoot, "myrig") {
+		t.Fatalf("result = %+v, want proposed", res)
+	}
+	if status := git(t, filepath.Dir(beadsDir), "status", "--porcelain"); status != "" {
+		t.Errorf("worktree dirty after proposing:\n%s", status)
+	}
+	proposal, err := os.ReadFile(res.Proposal)
+	if err != nil || !strings.Contains(string(proposal), `"dolt_mode": "server"`) {
+		t.Errorf("proposal = %s, %v", proposal, err)
+	}
+}
+
+func TestUpdateMetadata_TrackedCommit(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := trackedMetadataRig(t, townRoot)
+	clone := filepath.Dir(beadsDir)
+
+	// A proposal from an earlier run is cleared once the change lands.
+	if _, err := UpdateMetadata(townRoot, "myrig", MetadataOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// Unrelated staged work must not be swept into the commit.
+	if err := os.WriteFile(filepath.Join(clone, "other.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, clone, "add", "other.txt")
+
+	res, err := UpdateMetadata(townRoot, "myrig", MetadataOptions{Commit: true})
+	if err != nil {
+		t.Fatalf("UpdateMetadata: %v", err)
+	}
+	if res.Action != MetadataCommitted {
+		t.Fatalf("action = %s, want committed", res.Action)
+	}
+	if got := git(t, clone, "log", "-1", "--format=%s"); strings.TrimSpace(got) != MetadataCommitMessage {
+		t.Errorf("last commit = %q", got)
+	}
+	if status := git(t, clone, "status", "--porcelain"); strings.TrimSpace(status) != "A  other.txt" {
+		t.Errorf("status after commit = %q, want only other.txt staged", status)
+	}
+	if _, err := os.Stat(MetadataProposalPath(townRoot, "myrig")); !os.IsNotExist(err) {
+		t.Errorf("stale proposal left behind: %v", err)
+	}
+
+	// Already correct: nothing to write or commit.
+	res, err = UpdateMetadata(townRoot, "myrig", MetadataOptions{Commit: true})
+	if err != nil || res.Action != MetadataUnchanged {
+		t.Errorf("second UpdateMetadata = %+v, %v; want unchanged", res, err)
+	}
+}
+
+func TestUpdateMetadata_ReadOnlyIsProposed(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(beadsDir, "metadata.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0400); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := UpdateMetadata(townRoot, "hq", MetadataOptions{Commit: true})
+	if err != nil || res.Action != MetadataProposed {
+		t.Fatalf("UpdateMetadata = %+v, %v; want proposed", res, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{}` {
+		t.Errorf("read-only metadata.json was rewritten: %s", data)
+	}
+}
+
+func TestVerifyMetadata(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, "myrig", "mayor", "rig", ".beads")
+
+	v, err := VerifyMetadata(townRoot, "myrig")
+	if err != nil {
+		t.Fatalf("VerifyMetadata: %v", err)
+	}
+	if !v.Missing || v.OK() {
+		t.Errorf("no metadata.json: %+v, want missing", v)
+	}
+
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatalf("EnsureMetadata: %v", err)
+	}
+	if v, _ = VerifyMetadata(townRoot, "myrig"); !v.OK() {
+		t.Errorf("after EnsureMetadata: %+v, want OK", v)
+	}
+
+	// A hand edit pointing the rig at another rig's database is drift, and
+	// EnsureMetadata leaves dolt_database alone, so it persists.
+	hand := `{"backend": "dolt", "dolt_mode": "server", "dolt_database": "otherrig", "database": "dolt"}` + "\n"
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(hand), 0600); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(filepath.Join(beadsDir, "metadata.json"))
+	v, err = VerifyMetadata(townRoot, "myrig")
+	if err != nil {
+		t.Fatalf("VerifyMetadata: %v", err)
+	}
+	want := []MetadataDrift{
+		{Field: "dolt_database", Expected: "myrig", Actual: "otherrig"},
+		{Field: "jsonl_export", Expected: "issues.jsonl", Actual: ""},
+	}
+	if len(v.Drift) != len(want) || v.Drift[0] != want[0] || v.Drift[1] != want[1] {
+		t.Errorf("Drift = %+v, want %+v", v.Drift, want)
+	}
+	if after, _ := os.ReadFile(filepath.Join(beadsDir, "metadata.json")); string(after) != string(before) {
+		t.Error("VerifyMetadata modified metadata.json")
+	}
+}
+
+func TestEnsureMetadata_CustomServer(t *testing.T) {
+	townRoot := t.TempDir()
+	writeDoltServerSettings(t, townRoot, &config.DoltServerConfig{Port: 3308, User: "gt"})
+
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatalf("EnsureMetadata: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, "myrig", "mayor", "rig", ".beads", "metadata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"dolt_server_port": 3308`, `"dolt_server_user": "gt"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("metadata.json missing %s:\n%s", want, data)
+		}
+	}
+	if v, _ := VerifyMetadata(townRoot, "myrig"); !v.OK() {
+		t.Errorf("after EnsureMetadata: %+v, want OK", v)
+	}
+
+	// Moving the server again makes the old port drift.
+	writeDoltServerSettings(t, townRoot, &config.DoltServerConfig{Port: 3309, User: "gt"})
+	v, err := VerifyMetadata(townRoot, "myrig")
+	if err != nil {
+		t.Fatalf("VerifyMetadata: %v", err)
+	}
+	if len(v.Drift) != 1 || v.Drift[0] != (MetadataDrift{Field: "dolt_server_port", Expected: "3309", Actual: "3308"}) {
+		t.Errorf("Drift = %+v, want dolt_server_port 3308 → 3309", v.Drift)
+	}
+}
+
+// writeDoltServerSettings saves town settings with the given dolt_server
+// section.
+func writeDoltServerSettings(t *testing.T, townRoot string, s *config.DoltServerConfig) {
+	t.Helper()
+	settings := config.NewTownSettings()
+	settings.DoltServer = s
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+}
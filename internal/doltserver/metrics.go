@@ -0,0 +1,62 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DatabaseMetrics are the per-database figures exported alongside
+// HealthMetrics.
+type DatabaseMetrics struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"size_bytes"`
+
+	// Branches counts all branches including main; PolecatBranches counts
+	// the polecat-<name>-<ts> ones. Both are -1 when they couldn't be read.
+	Branches        int `json:"branches"`
+	PolecatBranches int `json:"polecat_branches"`
+}
+
+// GetDatabaseMetrics returns the on-disk size and branch counts of every
+// rig database. Branch counts that can't be read are -1.
+func GetDatabaseMetrics(townRoot string) ([]DatabaseMetrics, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	metrics := make([]DatabaseMetrics, 0, len(databases))
+	for _, db := range databases {
+		m := DatabaseMetrics{
+			Name:            db,
+			SizeBytes:       dirSize(RigDatabaseDir(townRoot, db)),
+			Branches:        -1,
+			PolecatBranches: -1,
+		}
+		if total, polecat, err := countBranches(townRoot, db); err == nil {
+			m.Branches, m.PolecatBranches = total, polecat
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// countBranches returns the number of branches in rigDB and how many of
+// them are polecat branches.
+func countBranches(townRoot, rigDB string) (total, polecat int, err error) {
+	rows, err := doltQueryCSV(townRoot, rigDB,
+		"SELECT COUNT(*) AS total, COALESCE(SUM(name LIKE 'polecat-%'), 0) AS polecat FROM dolt_branches")
+	if err != nil {
+		return 0, 0, err
+	}
+	recs := csvRecords(rows)
+	if len(recs) != 1 {
+		return 0, 0, fmt.Errorf("counting branches in %s: no result", rigDB)
+	}
+	if total, err = strconv.Atoi(recs[0]["total"]); err != nil {
+		return 0, 0, fmt.Errorf("counting branches in %s: %w", rigDB, err)
+	}
+	if polecat, err = strconv.Atoi(recs[0]["polecat"]); err != nil {
+		return 0, 0, fmt.Errorf("counting branches in %s: %w", rigDB, err)
+	}
+	return total, polecat, nil
+}
@@ -0,0 +1,248 @@
+package doltserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// MetricsSample is one point in the Dolt health history: the figures of
+// HealthMetrics at a point in time, flattened for trend reporting.
+type MetricsSample struct {
+	Time time.Time `json:"time"`
+
+	// Up is false when the server wasn't running; the other figures are
+	// then zero.
+	Up bool `json:"up"`
+
+	LatencyMs      float64 `json:"latency_ms"`
+	Connections    int     `json:"connections"`
+	MaxConnections int     `json:"max_connections"`
+	DiskUsageBytes int64   `json:"disk_usage_bytes"`
+	ReadOnly       bool    `json:"read_only,omitempty"`
+	Healthy        bool    `json:"healthy"`
+}
+
+// NewMetricsSample builds a sample from health metrics taken at t. A nil
+// m records the server as down.
+func NewMetricsSample(t time.Time, m *HealthMetrics) MetricsSample {
+	s := MetricsSample{Time: t}
+	if m == nil {
+		return s
+	}
+	s.Up = true
+	s.LatencyMs = float64(m.QueryLatency) / float64(time.Millisecond)
+	s.Connections = m.Connections
+	s.MaxConnections = m.MaxConnections
+	s.DiskUsageBytes = m.DiskUsageBytes
+	s.ReadOnly = m.ReadOnly
+	s.Healthy = m.Healthy
+	return s
+}
+
+// MetricsHistoryFile returns the path of the Dolt health history file.
+func MetricsHistoryFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-metrics.jsonl")
+}
+
+// RecordMetricsSample appends a sample to the history file.
+func RecordMetricsSample(townRoot string, s MetricsSample) error {
+	path := MetricsHistoryFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: metrics history is not sensitive
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadMetricsHistory reads the samples taken after since, oldest first. A
+// missing file yields no samples; unparseable lines are skipped.
+func LoadMetricsHistory(townRoot string, since time.Time) ([]MetricsSample, error) {
+	f, err := os.Open(MetricsHistoryFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []MetricsSample
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var s MetricsSample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err == nil && s.Time.After(since) {
+			samples = append(samples, s)
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	return samples, scanner.Err()
+}
+
+// PruneMetricsHistory drops samples taken at or before cutoff, keeping
+// the history a rolling window. The file is rewritten atomically.
+// Returns the number of samples dropped.
+func PruneMetricsHistory(townRoot string, cutoff time.Time) (int, error) {
+	path := MetricsHistoryFile(townRoot)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var kept []byte
+	dropped := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var s MetricsSample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil || !s.Time.After(cutoff) {
+			dropped++
+			continue
+		}
+		kept = append(kept, scanner.Bytes()...)
+		kept = append(kept, '\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, kept, 0644); err != nil { //nolint:gosec // G306: metrics history is not sensitive
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	return dropped, nil
+}
+
+// MetricsSummary summarizes a run of samples for capacity planning.
+type MetricsSummary struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Samples int       `json:"samples"`
+
+	// Down and Unhealthy count samples with the server down or outside
+	// its resource limits.
+	Down      int `json:"down"`
+	Unhealthy int `json:"unhealthy"`
+
+	// Latency figures cover samples with the server up.
+	LatencyAvgMs float64 `json:"latency_avg_ms"`
+	LatencyP95Ms float64 `json:"latency_p95_ms"`
+	LatencyMaxMs float64 `json:"latency_max_ms"`
+
+	ConnectionsAvg float64 `json:"connections_avg"`
+	ConnectionsMax int     `json:"connections_max"`
+	MaxConnections int     `json:"max_connections"`
+
+	// DiskFirstBytes and DiskLastBytes are the first and last disk usage
+	// seen with the server up; DiskGrowthPerDay extrapolates between them.
+	DiskFirstBytes   int64   `json:"disk_first_bytes"`
+	DiskLastBytes    int64   `json:"disk_last_bytes"`
+	DiskGrowthPerDay float64 `json:"disk_growth_per_day_bytes"`
+}
+
+// SummarizeMetrics summarizes samples, which must be oldest first.
+// Returns nil for no samples.
+func SummarizeMetrics(samples []MetricsSample) *MetricsSummary {
+	if len(samples) == 0 {
+		return nil
+	}
+	sum := &MetricsSummary{
+		From:    samples[0].Time,
+		To:      samples[len(samples)-1].Time,
+		Samples: len(samples),
+	}
+
+	var latencies []float64
+	var conns int
+	var first, last *MetricsSample
+	for i := range samples {
+		s := &samples[i]
+		if !s.Up {
+			sum.Down++
+			continue
+		}
+		if !s.Healthy {
+			sum.Unhealthy++
+		}
+		latencies = append(latencies, s.LatencyMs)
+		conns += s.Connections
+		if s.Connections > sum.ConnectionsMax {
+			sum.ConnectionsMax = s.Connections
+		}
+		sum.MaxConnections = s.MaxConnections
+		if first == nil {
+			first = s
+		}
+		last = s
+	}
+	if len(latencies) == 0 {
+		return sum
+	}
+
+	var total float64
+	for _, l := range latencies {
+		total += l
+	}
+	sum.LatencyAvgMs = total / float64(len(latencies))
+	sum.ConnectionsAvg = float64(conns) / float64(len(latencies))
+	sort.Float64s(latencies)
+	sum.LatencyMaxMs = latencies[len(latencies)-1]
+	sum.LatencyP95Ms = latencies[(len(latencies)*95+99)/100-1]
+
+	sum.DiskFirstBytes = first.DiskUsageBytes
+	sum.DiskLastBytes = last.DiskUsageBytes
+	if span := last.Time.Sub(first.Time); span >= time.Hour {
+		sum.DiskGrowthPerDay = float64(last.DiskUsageBytes-first.DiskUsageBytes) / span.Hours() * 24
+	}
+	return sum
+}
+
+// BucketMetrics splits samples (oldest first) into n equal time windows
+// and summarizes each, for showing a trend. Empty windows are omitted.
+func BucketMetrics(samples []MetricsSample, n int) []MetricsSummary {
+	if len(samples) == 0 || n <= 0 {
+		return nil
+	}
+	from, to := samples[0].Time, samples[len(samples)-1].Time
+	width := to.Sub(from) / time.Duration(n)
+	if width <= 0 {
+		return []MetricsSummary{*SummarizeMetrics(samples)}
+	}
+
+	var buckets []MetricsSummary
+	start := 0
+	for b := 0; b < n && start < len(samples); b++ {
+		end := from.Add(width * time.Duration(b+1))
+		stop := start
+		for stop < len(samples) && (b == n-1 || samples[stop].Time.Before(end)) {
+			stop++
+		}
+		if stop > start {
+			buckets = append(buckets, *SummarizeMetrics(samples[start:stop]))
+		}
+		start = stop
+	}
+	return buckets
+}
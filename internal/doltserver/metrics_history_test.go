@@ -0,0 +1,85 @@
+package doltserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsHistory_RecordLoadPrune(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		m := &HealthMetrics{QueryLatency: time.Duration(i+1) * time.Millisecond, Connections: i, MaxConnections: 100, Healthy: true}
+		if err := RecordMetricsSample(townRoot, NewMetricsSample(base.Add(time.Duration(i)*time.Hour), m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RecordMetricsSample(townRoot, NewMetricsSample(base.Add(4*time.Hour), nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := LoadMetricsHistory(townRoot, base.Add(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("loaded %d samples after cutoff, want 3", len(samples))
+	}
+	if samples[0].LatencyMs != 3 || samples[2].Up {
+		t.Errorf("samples = %+v", samples)
+	}
+
+	dropped, err := PruneMetricsHistory(townRoot, base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 2 {
+		t.Errorf("dropped %d, want 2", dropped)
+	}
+	samples, _ = LoadMetricsHistory(townRoot, time.Time{})
+	if len(samples) != 3 {
+		t.Errorf("%d samples left after prune, want 3", len(samples))
+	}
+}
+
+func TestSummarizeMetrics(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	samples := []MetricsSample{
+		{Time: base, Up: true, LatencyMs: 2, Connections: 10, MaxConnections: 100, DiskUsageBytes: 1000, Healthy: true},
+		{Time: base.Add(6 * time.Hour), Up: false},
+		{Time: base.Add(12 * time.Hour), Up: true, LatencyMs: 4, Connections: 30, MaxConnections: 100, DiskUsageBytes: 1500, Healthy: false},
+	}
+	sum := SummarizeMetrics(samples)
+	if sum.Samples != 3 || sum.Down != 1 || sum.Unhealthy != 1 {
+		t.Errorf("counts = %d/%d/%d", sum.Samples, sum.Down, sum.Unhealthy)
+	}
+	if sum.LatencyAvgMs != 3 || sum.LatencyMaxMs != 4 || sum.LatencyP95Ms != 4 {
+		t.Errorf("latency avg/p95/max = %v/%v/%v", sum.LatencyAvgMs, sum.LatencyP95Ms, sum.LatencyMaxMs)
+	}
+	if sum.ConnectionsAvg != 20 || sum.ConnectionsMax != 30 {
+		t.Errorf("connections avg/max = %v/%v", sum.ConnectionsAvg, sum.ConnectionsMax)
+	}
+	if sum.DiskGrowthPerDay != 1000 {
+		t.Errorf("disk growth per day = %v, want 1000", sum.DiskGrowthPerDay)
+	}
+	if SummarizeMetrics(nil) != nil {
+		t.Error("SummarizeMetrics(nil) should be nil")
+	}
+}
+
+func TestBucketMetrics(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	var samples []MetricsSample
+	for _, h := range []int{0, 1, 2, 10, 11, 12} {
+		samples = append(samples, MetricsSample{Time: base.Add(time.Duration(h) * time.Hour), Up: true, Healthy: true})
+	}
+	buckets := BucketMetrics(samples, 4)
+	// Windows of 3h: [0,3) has 3 samples, [3,6) and [6,9) are empty,
+	// and the last window takes the rest.
+	if len(buckets) != 2 {
+		t.Fatalf("got %d buckets, want 2: %+v", len(buckets), buckets)
+	}
+	if buckets[0].Samples != 3 || buckets[1].Samples != 3 {
+		t.Errorf("bucket sizes = %d, %d", buckets[0].Samples, buckets[1].Samples)
+	}
+}
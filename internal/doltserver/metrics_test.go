@@ -0,0 +1,48 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestGetDatabaseMetrics(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, db := range []string{"gastown", "beads"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", db, ".dolt"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, ".dolt-data", "gastown", ".dolt", "chunk"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		if strings.HasPrefix(query, "USE beads;") {
+			return nil, []byte("connection refused"), errors.New("exit status 1")
+		}
+		return []byte("total,polecat\n4,2\n"), nil, nil
+	}})()
+
+	metrics, err := GetDatabaseMetrics(townRoot)
+	if err != nil {
+		t.Fatalf("GetDatabaseMetrics: %v", err)
+	}
+	byName := make(map[string]DatabaseMetrics)
+	for _, m := range metrics {
+		byName[m.Name] = m
+	}
+
+	gt := byName["gastown"]
+	if gt.Branches != 4 || gt.PolecatBranches != 2 || gt.SizeBytes < 100 {
+		t.Errorf("gastown = %+v, want 4 branches, 2 polecat, at least 100 bytes", gt)
+	}
+	if b := byName["beads"]; b.Branches != -1 || b.PolecatBranches != -1 {
+		t.Errorf("beads = %+v, want unknown branch counts", b)
+	}
+}
@@ -0,0 +1,590 @@
+package doltserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// DefaultOffsiteRetention is how many snapshots are kept in object storage.
+const DefaultOffsiteRetention = 30
+
+// ErrNoOffsite is returned when no offsite target is configured.
+var ErrNoOffsite = errors.New("no offsite backup target configured (dolt_server.offsite.url in settings/config.json)")
+
+// Offsite backups are stored content-addressed, so a file shared by several
+// snapshots (most of a Dolt database's table files) is uploaded once:
+//
+//	<prefix>/objects/<sha256>           file contents
+//	<prefix>/snapshots/<timestamp>.json OffsiteManifest
+//
+// A snapshot's manifest is uploaded last, so an interrupted upload never
+// shows up as a snapshot.
+const (
+	offsiteObjectsPrefix   = "objects/"
+	offsiteSnapshotsPrefix = "snapshots/"
+)
+
+// OffsiteManifest describes a snapshot in object storage: the local backup's
+// manifest plus the object holding each of its files.
+type OffsiteManifest struct {
+	DataBackup
+	Files []OffsiteFile `json:"files"`
+}
+
+// OffsiteFile is one file of a snapshot.
+type OffsiteFile struct {
+	Path   string      `json:"path"` // Slash-separated, relative to the backup directory
+	SHA256 string      `json:"sha256"`
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+}
+
+// OffsiteUpload reports what UploadDataBackup sent.
+type OffsiteUpload struct {
+	Timestamp     string `json:"timestamp"`
+	URL           string `json:"url"`
+	Files         int    `json:"files"`
+	Uploaded      int    `json:"uploaded"`
+	UploadedBytes int64  `json:"uploaded_bytes"`
+	SkippedBytes  int64  `json:"skipped_bytes"`
+
+	// Pruned lists the snapshots deleted by the offsite retention policy.
+	Pruned []string `json:"pruned,omitempty"`
+}
+
+// ObjectStore is a bucket, container, or directory that offsite backups are
+// kept in. Keys are slash-separated and relative to the target's prefix.
+type ObjectStore interface {
+	Put(key, src string) error
+	Get(key, dst string) error
+	// List returns the keys that start with prefix.
+	List(prefix string) ([]string, error)
+	Delete(key string) error
+}
+
+// OpenObjectStore returns the store for an offsite target. s3, gs, and az
+// targets go through the aws, gcloud, and az CLIs; file targets are plain
+// directories.
+func OpenObjectStore(cfg *config.DoltOffsiteConfig) (ObjectStore, error) {
+	if cfg == nil || cfg.URL == "" {
+		return nil, ErrNoOffsite
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing offsite URL: %w", err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("offsite URL %q has no path", cfg.URL)
+		}
+		return dirStore(filepath.FromSlash(u.Path)), nil
+	case "s3", "gs", "az":
+		if u.Host == "" {
+			return nil, fmt.Errorf("offsite URL %q has no bucket", cfg.URL)
+		}
+		if u.Scheme == "az" && cfg.Account == "" {
+			return nil, fmt.Errorf("az offsite target needs dolt_server.offsite.account")
+		}
+		return &cliStore{scheme: u.Scheme, bucket: u.Host, prefix: prefix, cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unsupported offsite URL %q (want s3://, gs://, az://, or file://)", cfg.URL)
+	}
+}
+
+// UploadDataBackup copies backup timestamp to the town's offsite target,
+// skipping files already stored there, then applies the offsite retention
+// policy.
+func UploadDataBackup(townRoot, timestamp string) (*OffsiteUpload, error) {
+	cfg := DefaultConfig(townRoot).Offsite
+	store, err := OpenObjectStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	b, err := FindDataBackup(townRoot, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	m := OffsiteManifest{DataBackup: *b}
+	m.Path = ""
+	err = filepath.WalkDir(b.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(b.Path, p)
+		if err != nil || rel == dataBackupManifest {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(p)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, OffsiteFile{Path: filepath.ToSlash(rel), SHA256: sum, Size: info.Size(), Mode: info.Mode().Perm()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading backup %s: %w", timestamp, err)
+	}
+
+	existing, err := store.List(offsiteObjectsPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing offsite objects: %w", err)
+	}
+	stored := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		stored[strings.TrimPrefix(key, offsiteObjectsPrefix)] = true
+	}
+
+	up := &OffsiteUpload{Timestamp: timestamp, URL: cfg.URL, Files: len(m.Files)}
+	for _, f := range m.Files {
+		if stored[f.SHA256] {
+			up.SkippedBytes += f.Size
+			continue
+		}
+		if err := store.Put(offsiteObjectsPrefix+f.SHA256, filepath.Join(b.Path, filepath.FromSlash(f.Path))); err != nil {
+			return up, fmt.Errorf("uploading %s: %w", f.Path, err)
+		}
+		stored[f.SHA256] = true
+		up.Uploaded++
+		up.UploadedBytes += f.Size
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return up, err
+	}
+	if err := putBytes(store, offsiteSnapshotsPrefix+timestamp+".json", data); err != nil {
+		return up, fmt.Errorf("uploading manifest: %w", err)
+	}
+
+	up.Pruned, err = pruneOffsiteSnapshots(store, cfg, clk.Now())
+	if err != nil {
+		return up, fmt.Errorf("pruning offsite snapshots: %w", err)
+	}
+	return up, nil
+}
+
+// ListOffsiteSnapshots returns the timestamps of the snapshots at the
+// town's offsite target, newest first.
+func ListOffsiteSnapshots(townRoot string) ([]string, error) {
+	store, err := OpenObjectStore(DefaultConfig(townRoot).Offsite)
+	if err != nil {
+		return nil, err
+	}
+	return listOffsiteSnapshots(store)
+}
+
+// FetchOffsiteSnapshot downloads snapshot timestamp from the town's offsite
+// target into .dolt-backups/<timestamp>, where gt dolt restore can restore
+// it. A backup already present locally is returned as is. Every file is
+// checked against its recorded hash.
+func FetchOffsiteSnapshot(townRoot, timestamp string) (*DataBackup, error) {
+	if err := validateBackupName(timestamp); err != nil {
+		return nil, err
+	}
+	if b, err := FindDataBackup(townRoot, timestamp); err == nil {
+		return b, nil
+	}
+	store, err := OpenObjectStore(DefaultConfig(townRoot).Offsite)
+	if err != nil {
+		return nil, err
+	}
+	m, err := getManifest(store, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("offsite snapshot %s: %w", timestamp, err)
+	}
+
+	dir := filepath.Join(DataBackupsDir(townRoot), timestamp)
+	// Download into a temporary directory so an interrupted fetch is never
+	// mistaken for a complete backup.
+	tmp := dir + ".tmp"
+	_ = os.RemoveAll(tmp)
+	defer func() { _ = os.RemoveAll(tmp) }()
+	for _, f := range m.Files {
+		rel := filepath.FromSlash(f.Path)
+		if !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("offsite snapshot %s: invalid file path %q", timestamp, f.Path)
+		}
+		dst := filepath.Join(tmp, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return nil, err
+		}
+		if err := store.Get(offsiteObjectsPrefix+f.SHA256, dst); err != nil {
+			return nil, fmt.Errorf("downloading %s: %w", f.Path, err)
+		}
+		if sum, err := fileSHA256(dst); err != nil || sum != f.SHA256 {
+			return nil, fmt.Errorf("downloaded %s does not match its hash", f.Path)
+		}
+		if err := os.Chmod(dst, f.Mode.Perm()); err != nil {
+			return nil, err
+		}
+	}
+
+	b := m.DataBackup
+	b.Path = dir
+	b.Timestamp = timestamp
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, dataBackupManifest), data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// pruneOffsiteSnapshots deletes the snapshots beyond the retention count or
+// age, keeping the newest, then the objects no remaining snapshot uses.
+func pruneOffsiteSnapshots(store ObjectStore, cfg *config.DoltOffsiteConfig, now time.Time) ([]string, error) {
+	snapshots, err := listOffsiteSnapshots(store)
+	if err != nil {
+		return nil, err
+	}
+	keep := cfg.Retention
+	if keep <= 0 {
+		keep = DefaultOffsiteRetention
+	}
+	var kept, pruned []string
+	for i, ts := range snapshots {
+		if i > 0 && (i >= keep || offsiteSnapshotExpired(ts, cfg.RetentionDays, now)) {
+			pruned = append(pruned, ts)
+		} else {
+			kept = append(kept, ts)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	// Read the kept manifests before deleting anything: an object is only
+	// garbage if no kept snapshot refers to it.
+	used := make(map[string]bool)
+	for _, ts := range kept {
+		m, err := getManifest(store, ts)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot %s: %w", ts, err)
+		}
+		for _, f := range m.Files {
+			used[f.SHA256] = true
+		}
+	}
+	for _, ts := range pruned {
+		if err := store.Delete(offsiteSnapshotsPrefix + ts + ".json"); err != nil {
+			return nil, fmt.Errorf("deleting snapshot %s: %w", ts, err)
+		}
+	}
+	objects, err := store.List(offsiteObjectsPrefix)
+	if err != nil {
+		return pruned, err
+	}
+	for _, key := range objects {
+		if !used[strings.TrimPrefix(key, offsiteObjectsPrefix)] {
+			if err := store.Delete(key); err != nil {
+				return pruned, fmt.Errorf("deleting %s: %w", key, err)
+			}
+		}
+	}
+	return pruned, nil
+}
+
+// offsiteSnapshotExpired reports whether a snapshot named by its
+// YYYYMMDD-HHMMSS timestamp is older than days. days <= 0 never expires.
+func offsiteSnapshotExpired(timestamp string, days int, now time.Time) bool {
+	if days <= 0 || len(timestamp) < 15 {
+		return false
+	}
+	t, err := time.Parse("20060102-150405", timestamp[:15])
+	if err != nil {
+		return false
+	}
+	return now.Sub(t) > time.Duration(days)*24*time.Hour
+}
+
+func listOffsiteSnapshots(store ObjectStore) ([]string, error) {
+	keys, err := store.List(offsiteSnapshotsPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing offsite snapshots: %w", err)
+	}
+	var snapshots []string
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, offsiteSnapshotsPrefix)
+		if ts, ok := strings.CutSuffix(name, ".json"); ok && !strings.Contains(ts, "/") {
+			snapshots = append(snapshots, ts)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(snapshots)))
+	return snapshots, nil
+}
+
+func getManifest(store ObjectStore, timestamp string) (*OffsiteManifest, error) {
+	f, err := os.CreateTemp("", "gt-offsite-*.json")
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Close()
+	defer func() { _ = os.Remove(f.Name()) }()
+	if err := store.Get(offsiteSnapshotsPrefix+timestamp+".json", f.Name()); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	var m OffsiteManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func putBytes(store ObjectStore, key string, data []byte) error {
+	f, err := os.CreateTemp("", "gt-offsite-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return store.Put(key, f.Name())
+}
+
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// dirStore is an ObjectStore in a local directory (a mounted drive or
+// network share).
+type dirStore string
+
+func (s dirStore) path(key string) string { return filepath.Join(string(s), filepath.FromSlash(key)) }
+
+func (s dirStore) Put(key, src string) error {
+	dst := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	// Write under a temporary name so readers never see a partial object.
+	if err := copyFile(dst+".tmp", src); err != nil {
+		return err
+	}
+	return os.Rename(dst+".tmp", dst)
+}
+
+func (s dirStore) Get(key, dst string) error { return copyFile(dst, s.path(key)) }
+
+func (s dirStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(string(s), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(p, ".tmp") {
+			return nil
+		}
+		rel, err := filepath.Rel(string(s), p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s dirStore) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// cliStore is an ObjectStore in a cloud bucket, reached through the
+// provider's CLI so its credential chain applies.
+type cliStore struct {
+	scheme string // s3, gs, or az
+	bucket string // Bucket, or container for az
+	prefix string
+	cfg    *config.DoltOffsiteConfig
+}
+
+// key returns the full object name of key within the bucket.
+func (s *cliStore) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
+
+func (s *cliStore) url(key string) string {
+	return s.scheme + "://" + s.bucket + "/" + s.key(key)
+}
+
+func (s *cliStore) Put(key, src string) error {
+	switch s.scheme {
+	case "az":
+		_, err := s.run("storage", "blob", "upload", "--file", src, "--name", s.key(key), "--overwrite")
+		return err
+	case "gs":
+		_, err := s.run("storage", "cp", src, s.url(key))
+		return err
+	default:
+		_, err := s.run("s3", "cp", src, s.url(key))
+		return err
+	}
+}
+
+func (s *cliStore) Get(key, dst string) error {
+	switch s.scheme {
+	case "az":
+		_, err := s.run("storage", "blob", "download", "--name", s.key(key), "--file", dst)
+		return err
+	case "gs":
+		_, err := s.run("storage", "cp", s.url(key), dst)
+		return err
+	default:
+		_, err := s.run("s3", "cp", s.url(key), dst)
+		return err
+	}
+}
+
+func (s *cliStore) List(prefix string) ([]string, error) {
+	var names []string
+	switch s.scheme {
+	case "az":
+		out, err := s.run("storage", "blob", "list", "--prefix", s.key(prefix), "--num-results", "*", "--query", "[].name", "--output", "json")
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(out, &names); err != nil {
+			return nil, fmt.Errorf("parsing az output: %w", err)
+		}
+	case "gs":
+		out, err := s.run("storage", "ls", s.url(prefix)+"**")
+		if err != nil {
+			if strings.Contains(err.Error(), "matched no objects") {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if name, ok := strings.CutPrefix(line, "gs://"+s.bucket+"/"); ok {
+				names = append(names, name)
+			}
+		}
+	default:
+		out, err := s.run("s3api", "list-objects-v2", "--bucket", s.bucket, "--prefix", s.key(prefix), "--query", "Contents[].Key", "--output", "json")
+		if err != nil {
+			return nil, err
+		}
+		// null when nothing matches.
+		if err := json.Unmarshal(out, &names); err != nil {
+			return nil, fmt.Errorf("parsing aws output: %w", err)
+		}
+	}
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		if s.prefix != "" {
+			name = strings.TrimPrefix(name, s.prefix+"/")
+		}
+		if strings.HasPrefix(name, prefix) {
+			keys = append(keys, name)
+		}
+	}
+	return keys, nil
+}
+
+func (s *cliStore) Delete(key string) error {
+	switch s.scheme {
+	case "az":
+		_, err := s.run("storage", "blob", "delete", "--name", s.key(key))
+		return err
+	case "gs":
+		_, err := s.run("storage", "rm", s.url(key))
+		return err
+	default:
+		_, err := s.run("s3", "rm", s.url(key))
+		return err
+	}
+}
+
+// run runs the provider CLI with the target's credentials settings and
+// returns its stdout. Errors include the CLI's stderr.
+func (s *cliStore) run(args ...string) ([]byte, error) {
+	c := proc.Cmd{Args: args}
+	env := os.Environ()
+	switch s.scheme {
+	case "az":
+		c.Name = "az"
+		c.Args = append(c.Args, "--account-name", s.cfg.Account, "--container-name", s.bucket, "--only-show-errors")
+	case "gs":
+		c.Name = "gcloud"
+		if s.cfg.CredentialsFile != "" {
+			env = append(env, "CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE="+s.cfg.CredentialsFile)
+		}
+	default:
+		c.Name = "aws"
+		if s.cfg.Profile != "" {
+			c.Args = append(c.Args, "--profile", s.cfg.Profile)
+		}
+		if s.cfg.CredentialsFile != "" {
+			env = append(env, "AWS_SHARED_CREDENTIALS_FILE="+s.cfg.CredentialsFile)
+		}
+		if args[0] == "s3" {
+			c.Args = append(c.Args, "--only-show-errors")
+		}
+	}
+	c.Env = env
+
+	stdout, stderr, err := runner.Run(context.Background(), c)
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return stdout, fmt.Errorf("%s %s: %w: %s", c.Name, strings.Join(args[:2], " "), err, msg)
+		}
+		return stdout, fmt.Errorf("%s %s: %w", c.Name, strings.Join(args[:2], " "), err)
+	}
+	return stdout, nil
+}
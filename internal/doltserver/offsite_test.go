@@ -0,0 +1,204 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// setupOffsiteTown writes town settings with a file:// offsite target and
+// returns the target directory.
+func setupOffsiteTown(t *testing.T, townRoot string, retention int) string {
+	t.Helper()
+	target := filepath.Join(t.TempDir(), "bucket")
+	settings := config.NewTownSettings()
+	settings.DoltServer = &config.DoltServerConfig{
+		Offsite: &config.DoltOffsiteConfig{URL: "file://" + filepath.ToSlash(target), Retention: retention},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+func TestUploadDataBackup_Incremental(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	setupOffsiteTown(t, townRoot, 0)
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	setupDoltDB(t, dataDir, "hq")
+	gtPath := setupDoltDB(t, dataDir, "gastown")
+
+	b1, _, err := CreateDataBackup(townRoot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, err := UploadDataBackup(townRoot, b1.Timestamp)
+	if err != nil {
+		t.Fatalf("UploadDataBackup: %v", err)
+	}
+	// Both databases hold identical manifests, so one object covers them.
+	if up.Files != 2 || up.Uploaded != 1 {
+		t.Errorf("first upload = %+v, want 2 files, 1 uploaded", up)
+	}
+
+	if err := os.WriteFile(filepath.Join(gtPath, ".dolt", "manifest"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fake.Advance(time.Hour)
+	b2, _, err := CreateDataBackup(townRoot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, err = UploadDataBackup(townRoot, b2.Timestamp)
+	if err != nil {
+		t.Fatalf("UploadDataBackup: %v", err)
+	}
+	if up.Uploaded != 1 || up.SkippedBytes != int64(len("test")) {
+		t.Errorf("second upload = %+v, want only the changed file uploaded", up)
+	}
+
+	snaps, err := ListOffsiteSnapshots(townRoot)
+	if err != nil || len(snaps) != 2 || snaps[0] != b2.Timestamp {
+		t.Errorf("ListOffsiteSnapshots = %v, %v; want newest first", snaps, err)
+	}
+}
+
+func TestFetchOffsiteSnapshot(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	setupOffsiteTown(t, townRoot, 0)
+	setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "hq")
+
+	b, _, err := CreateDataBackup(townRoot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UploadDataBackup(townRoot, b.Timestamp); err != nil {
+		t.Fatal(err)
+	}
+	// The laptop died: the local backups are gone.
+	if err := os.RemoveAll(DataBackupsDir(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FetchOffsiteSnapshot(townRoot, b.Timestamp)
+	if err != nil {
+		t.Fatalf("FetchOffsiteSnapshot: %v", err)
+	}
+	if got.Timestamp != b.Timestamp || len(got.Databases) != 1 {
+		t.Errorf("fetched backup = %+v", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(got.Path, "hq", ".dolt", "manifest")); string(data) != "test" {
+		t.Errorf("fetched manifest = %q, want %q", data, "test")
+	}
+	if _, err := FindDataBackup(townRoot, b.Timestamp); err != nil {
+		t.Errorf("fetched backup not restorable: %v", err)
+	}
+
+	if _, err := FetchOffsiteSnapshot(townRoot, "20990101-000000"); err == nil {
+		t.Error("expected error for a missing snapshot")
+	}
+}
+
+func TestUploadDataBackup_Retention(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	target := setupOffsiteTown(t, townRoot, 1)
+	hqPath := setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "hq")
+
+	for _, content := range []string{"one", "two"} {
+		if err := os.WriteFile(filepath.Join(hqPath, ".dolt", "manifest"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		b, _, err := CreateDataBackup(townRoot, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, err := UploadDataBackup(townRoot, b.Timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content == "two" && len(up.Pruned) != 1 {
+			t.Errorf("Pruned = %v, want the first snapshot", up.Pruned)
+		}
+		fake.Advance(time.Hour)
+	}
+
+	// The first snapshot's object is no longer used and is collected.
+	objects, _ := os.ReadDir(filepath.Join(target, "objects"))
+	if len(objects) != 1 {
+		t.Errorf("%d objects left, want 1", len(objects))
+	}
+}
+
+func TestOffsiteSnapshotExpired(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	if !offsiteSnapshotExpired("20260301-120000", 7, now) {
+		t.Error("9-day-old snapshot should expire after 7 days")
+	}
+	if offsiteSnapshotExpired("20260308-120000", 7, now) {
+		t.Error("2-day-old snapshot should not expire")
+	}
+	if offsiteSnapshotExpired("20200101-000000", 0, now) {
+		t.Error("RetentionDays 0 should never expire")
+	}
+}
+
+func TestOpenObjectStore(t *testing.T) {
+	for _, url := range []string{"", "ftp://host/x", "s3://", "az://container/x"} {
+		if _, err := OpenObjectStore(&config.DoltOffsiteConfig{URL: url}); err == nil {
+			t.Errorf("OpenObjectStore(%q) succeeded, want error", url)
+		}
+	}
+	if _, err := OpenObjectStore(nil); err != ErrNoOffsite {
+		t.Errorf("OpenObjectStore(nil) = %v, want ErrNoOffsite", err)
+	}
+}
+
+func TestCLIStoreS3(t *testing.T) {
+	fake := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if c.Args[0] == "s3api" {
+			return []byte(`["gt/town/objects/abc", "gt/town/snapshots/20260310-120000.json"]`), nil, nil
+		}
+		return nil, nil, nil
+	}}
+	defer SetRunner(fake)()
+
+	store, err := OpenObjectStore(&config.DoltOffsiteConfig{URL: "s3://bucket/gt/town", Profile: "backup", CredentialsFile: "/secrets/aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("objects/abc", "/tmp/file"); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := store.List("snapshots/")
+	if err != nil || len(keys) != 1 || keys[0] != "snapshots/20260310-120000.json" {
+		t.Errorf("List = %v, %v", keys, err)
+	}
+
+	calls := fake.Calls()
+	put := strings.Join(append([]string{calls[0].Name}, calls[0].Args...), " ")
+	if put != "aws s3 cp /tmp/file s3://bucket/gt/town/objects/abc --profile backup --only-show-errors" {
+		t.Errorf("put command = %q", put)
+	}
+	var credEnv bool
+	for _, e := range calls[0].Env {
+		credEnv = credEnv || e == "AWS_SHARED_CREDENTIALS_FILE=/secrets/aws"
+	}
+	if !credEnv {
+		t.Error("put command lacks AWS_SHARED_CREDENTIALS_FILE")
+	}
+	if list := calls[1]; list.Args[0] != "s3api" || list.Args[5] != "gt/town/snapshots/" {
+		t.Errorf("list command = %v", list.Args)
+	}
+}
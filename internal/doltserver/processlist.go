@@ -0,0 +1,73 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Process is one client connection to the Dolt server, as listed by
+// information_schema.PROCESSLIST.
+type Process struct {
+	ID       int64  `json:"id"`
+	User     string `json:"user"`
+	Host     string `json:"host"`
+	Database string `json:"database,omitempty"`
+	Command  string `json:"command"`
+	Seconds  int    `json:"seconds"` // time in the current state
+	State    string `json:"state,omitempty"`
+	Info     string `json:"info,omitempty"` // the statement being run, if any
+}
+
+// processListQuery lists every connection except the one asking, longest
+// running first.
+const processListQuery = "SELECT ID AS id, USER AS user, HOST AS host, DB AS db, COMMAND AS command, " +
+	"TIME AS seconds, STATE AS state, INFO AS info FROM information_schema.PROCESSLIST " +
+	"WHERE ID <> CONNECTION_ID() ORDER BY TIME DESC, ID"
+
+// ListProcesses returns the server's client connections, longest running
+// first.
+func ListProcesses(townRoot string) ([]Process, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, ok, err := serverQuery(ctx, townRoot, "", processListQuery)
+	if !ok {
+		config := DefaultConfig(townRoot)
+		var output, stderr []byte
+		output, stderr, err = runDolt(ctx, config.DataDir, "sql", "-r", "csv", "-q", processListQuery)
+		if err != nil {
+			return nil, fmt.Errorf("listing processes: %w (output: %s)", err, strings.TrimSpace(string(stderr)))
+		}
+		r := csv.NewReader(bytes.NewReader(output))
+		r.FieldsPerRecord = -1
+		rows, err = r.ReadAll()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing processes: %w", err)
+	}
+
+	var procs []Process
+	for _, rec := range csvRecords(rows) {
+		id, err := strconv.ParseInt(rec["id"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("listing processes: bad id %q", rec["id"])
+		}
+		seconds, _ := strconv.Atoi(rec["seconds"])
+		procs = append(procs, Process{
+			ID:       id,
+			User:     rec["user"],
+			Host:     rec["host"],
+			Database: rec["db"],
+			Command:  rec["command"],
+			Seconds:  seconds,
+			State:    rec["state"],
+			Info:     rec["info"],
+		})
+	}
+	return procs, nil
+}
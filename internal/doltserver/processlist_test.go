@@ -0,0 +1,34 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestListProcesses(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		if !strings.Contains(query, "information_schema.PROCESSLIST") {
+			t.Errorf("unexpected query %q", query)
+		}
+		return []byte("id,user,host,db,command,seconds,state,info\n" +
+			"42,root,127.0.0.1:5123,gastown,Query,12,,\"SELECT *\nFROM issues\"\n" +
+			"7,root,127.0.0.1:5100,,Sleep,3,,\n"), nil, nil
+	}})()
+
+	procs, err := ListProcesses(t.TempDir())
+	if err != nil {
+		t.Fatalf("ListProcesses: %v", err)
+	}
+	if len(procs) != 2 {
+		t.Fatalf("got %d processes, want 2", len(procs))
+	}
+	if p := procs[0]; p.ID != 42 || p.Database != "gastown" || p.Seconds != 12 || p.Info != "SELECT *\nFROM issues" {
+		t.Errorf("procs[0] = %+v", p)
+	}
+	if p := procs[1]; p.ID != 7 || p.Command != "Sleep" || p.Database != "" {
+		t.Errorf("procs[1] = %+v", p)
+	}
+}
@@ -0,0 +1,169 @@
+package doltserver
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// DeletedRigDatabase is a database in .dolt-data/ that no rig in rigs.json
+// uses: no registered rig has its name and no rig's metadata.json points at
+// it. It is what removing a rig leaves behind.
+type DeletedRigDatabase struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+
+	// Set by PruneDeletedRigDatabases.
+	Backup  string `json:"backup,omitempty"`
+	Removed bool   `json:"removed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PrunedDatabasesDir returns where PruneDeletedRigDatabases writes the final
+// backup of each database it removes.
+func PrunedDatabasesDir(townRoot string) string {
+	return filepath.Join(townRoot, "exports", "pruned-databases")
+}
+
+// FindDeletedRigDatabases returns the orphaned databases (see
+// FindOrphanedDatabases) that are not named after a registered rig. An
+// unreferenced database that shares a registered rig's name belongs to a
+// rig with broken metadata, not a deleted one, and is left out. The town
+// database (hq) is never reported. Errors if rigs.json is missing, since
+// every database would then look deleted.
+func FindDeletedRigDatabases(townRoot string) ([]DeletedRigDatabase, error) {
+	rigs, err := rigsconfig.Load(townRoot)
+	if err != nil {
+		if errors.Is(err, rigsconfig.ErrNotFound) {
+			return nil, fmt.Errorf("no rigs.json at %s; refusing to treat databases as deleted", rigsconfig.Path(townRoot))
+		}
+		return nil, err
+	}
+
+	orphans, err := FindOrphanedDatabases(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []DeletedRigDatabase
+	for _, o := range orphans {
+		if o.Name == "hq" {
+			continue
+		}
+		if _, ok := rigs.Rigs[o.Name]; ok {
+			continue
+		}
+		deleted = append(deleted, DeletedRigDatabase{Name: o.Name, Path: o.Path, SizeBytes: o.SizeBytes})
+	}
+	return deleted, nil
+}
+
+// PruneDeletedRigDatabases finds databases of deleted rigs, writes a final
+// backup of each to PrunedDatabasesDir, and drops it. A database whose
+// backup fails is kept. With dryRun, only reports what would be pruned.
+func PruneDeletedRigDatabases(townRoot string, dryRun bool) ([]DeletedRigDatabase, error) {
+	deleted, err := FindDeletedRigDatabases(townRoot)
+	if err != nil || dryRun {
+		return deleted, err
+	}
+	for i := range deleted {
+		db := &deleted[i]
+		backup, err := BackupDatabaseDir(townRoot, db.Name)
+		if err != nil {
+			db.Error = fmt.Sprintf("backup failed, database kept: %v", err)
+			continue
+		}
+		db.Backup = backup
+		if err := RemoveDatabase(townRoot, db.Name); err != nil {
+			db.Error = err.Error()
+			continue
+		}
+		db.Removed = true
+	}
+	return deleted, nil
+}
+
+// BackupDatabaseDir writes a gzipped tarball of a database directory,
+// history included, to PrunedDatabasesDir and returns its path. Restore by
+// extracting it into .dolt-data/ and restarting the server.
+func BackupDatabaseDir(townRoot, dbName string) (string, error) {
+	dbPath := RigDatabaseDir(townRoot, dbName)
+	if _, err := os.Stat(filepath.Join(dbPath, ".dolt")); err != nil {
+		return "", fmt.Errorf("database %q not found at %s", dbName, dbPath)
+	}
+	outDir := PrunedDatabasesDir(townRoot)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return "", err
+	}
+	out := filepath.Join(outDir, fmt.Sprintf("%s-%s.tar.gz", dbName, clk.Now().UTC().Format("20060102-150405")))
+
+	tmp := out + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	werr := writeTarDir(tw, filepath.Dir(dbPath), dbPath)
+	err = errors.Join(werr, tw.Close(), gz.Close(), f.Close())
+	if err != nil {
+		return "", fmt.Errorf("backing up %s: %w", dbName, err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// writeTarDir adds dir and everything under it to tw, with names relative
+// to base. A symlinked dir (a linked database) is archived as the directory
+// it points to, under its own name.
+func writeTarDir(tw *tar.Writer, base, dir string) error {
+	name, err := filepath.Rel(base, dir)
+	if err != nil {
+		return err
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil // Sockets, symlinks: nothing dolt needs
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.Join(name, rel)
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		src, err := os.Open(path) //nolint:gosec // G304: walking a database directory
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+}
@@ -0,0 +1,102 @@
+package doltserver
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestFindDeletedRigDatabases(t *testing.T) {
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+
+	setupDoltDB(t, dataDir, "hq")
+	setupDoltDB(t, dataDir, "gastown")
+	setupDoltDB(t, dataDir, "wyvern")  // Registered rig, metadata points elsewhere
+	setupDoltDB(t, dataDir, "removed") // Rig removed from rigs.json
+
+	setupRigsJSON(t, townRoot, []string{"gastown", "wyvern"})
+	setupRigMetadata(t, townRoot, "gastown", "gastown")
+	setupRigMetadata(t, townRoot, "wyvern", "beads_wy")
+
+	deleted, err := FindDeletedRigDatabases(townRoot)
+	if err != nil {
+		t.Fatalf("FindDeletedRigDatabases: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Name != "removed" {
+		t.Errorf("deleted = %+v, want only 'removed' (hq and registered rigs are kept)", deleted)
+	}
+}
+
+func TestFindDeletedRigDatabases_RequiresRigsJSON(t *testing.T) {
+	townRoot := t.TempDir()
+	setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "gastown")
+
+	if _, err := FindDeletedRigDatabases(townRoot); err == nil {
+		t.Error("expected error without rigs.json")
+	}
+}
+
+func TestPruneDeletedRigDatabases(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+	townRoot := t.TempDir()
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	setupDoltDB(t, dataDir, "gastown")
+	removedPath := setupDoltDB(t, dataDir, "removed")
+	setupRigsJSON(t, townRoot, []string{"gastown"})
+	setupRigMetadata(t, townRoot, "gastown", "gastown")
+
+	dry, err := PruneDeletedRigDatabases(townRoot, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(dry) != 1 || dry[0].Removed || dry[0].Backup != "" {
+		t.Fatalf("dry run = %+v, want 'removed' reported only", dry)
+	}
+	if _, err := os.Stat(removedPath); err != nil {
+		t.Fatalf("dry run removed the database: %v", err)
+	}
+
+	pruned, err := PruneDeletedRigDatabases(townRoot, false)
+	if err != nil {
+		t.Fatalf("PruneDeletedRigDatabases: %v", err)
+	}
+	if len(pruned) != 1 || !pruned[0].Removed || pruned[0].Error != "" {
+		t.Fatalf("pruned = %+v, want 'removed' removed", pruned)
+	}
+	if _, err := os.Stat(removedPath); !os.IsNotExist(err) {
+		t.Errorf("database directory still exists: %v", err)
+	}
+	want := filepath.Join(PrunedDatabasesDir(townRoot), "removed-20260310-120000.tar.gz")
+	if pruned[0].Backup != want {
+		t.Errorf("backup = %s, want %s", pruned[0].Backup, want)
+	}
+
+	// The backup holds the database directory, ready to extract into .dolt-data.
+	f, err := os.Open(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	names := map[string]bool{}
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names[hdr.Name] = true
+	}
+	if !names["removed/.dolt/manifest"] {
+		t.Errorf("backup entries = %v, want removed/.dolt/manifest", names)
+	}
+}
@@ -0,0 +1,350 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRemote is the remote gt dolt push and pull use unless told
+// otherwise. gt dolt sync pushes to it too.
+const DefaultRemote = "origin"
+
+// remoteTimeout bounds one push, fetch, or merge, which move a database's
+// history over the network.
+const remoteTimeout = 10 * time.Minute
+
+var validRemoteNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// remoteSchemes are the URL schemes Dolt can use as a remote: DoltHub
+// (https), S3 (aws), GCS (gs), OCI, and a local or mounted directory (file).
+var remoteSchemes = []string{"https", "http", "aws", "gs", "oci", "file"}
+
+// Remote is a Dolt remote configured on a rig database.
+type Remote struct {
+	Database string `json:"database"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+}
+
+// NormalizeRemoteURL turns the remote forms gt accepts into a URL Dolt
+// understands:
+//   - dolthub:<org>/<repo> becomes the DoltHub remote URL
+//   - an absolute path becomes a file:// URL
+//   - URLs with a Dolt remote scheme (https, aws, gs, oci, file) are kept
+func NormalizeRemoteURL(spec string) (string, error) {
+	switch {
+	case strings.ContainsAny(spec, "'\\`"):
+		return "", fmt.Errorf("invalid remote %q: contains quotes or backslashes", spec)
+	case strings.HasPrefix(spec, "dolthub:"):
+		org, repo, ok := strings.Cut(strings.TrimPrefix(spec, "dolthub:"), "/")
+		if !ok || org == "" || repo == "" {
+			return "", fmt.Errorf("invalid remote %q: want dolthub:<org>/<repo>", spec)
+		}
+		return DoltHubRemoteURL(org, repo), nil
+	case filepath.IsAbs(spec):
+		return "file://" + filepath.ToSlash(filepath.Clean(spec)), nil
+	}
+	scheme, _, ok := strings.Cut(spec, "://")
+	if ok {
+		for _, s := range remoteSchemes {
+			if scheme == s {
+				return spec, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("invalid remote %q: want dolthub:<org>/<repo>, an absolute path, or a %s URL",
+		spec, strings.Join(remoteSchemes, "/"))
+}
+
+func validateRemoteName(name string) error {
+	if !validRemoteNameRe.MatchString(name) {
+		return fmt.Errorf("invalid remote name %q", name)
+	}
+	return nil
+}
+
+// remoteQuery runs query against rigDB on the server, or with dolt sql in
+// the database directory when no server is running, with remoteTimeout.
+func remoteQuery(townRoot, rigDB, query string) ([][]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	if rows, ok, err := serverQuery(ctx, townRoot, rigDB, query); ok {
+		return rows, err
+	}
+	output, stderr, err := runDolt(ctx, RigDatabaseDir(townRoot, rigDB), "sql", "-r", "csv", "-q", query)
+	if err != nil {
+		return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)+string(stderr)))
+	}
+	r := csv.NewReader(bytes.NewReader(output))
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// ListRemotes returns the remotes configured on a rig database.
+func ListRemotes(townRoot, rigDB string) ([]Remote, error) {
+	rows, err := remoteQuery(townRoot, rigDB, "SELECT name, url FROM dolt_remotes ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("listing remotes of %s: %w", rigDB, err)
+	}
+	var remotes []Remote
+	for _, rec := range csvRecords(rows) {
+		remotes = append(remotes, Remote{Database: rigDB, Name: rec["name"], URL: rec["url"]})
+	}
+	return remotes, nil
+}
+
+// SetRemote points a rig database's remote name at url (see
+// NormalizeRemoteURL), replacing any existing remote of that name.
+func SetRemote(townRoot, rigDB, name, url string) (*Remote, error) {
+	if err := validateRemoteName(name); err != nil {
+		return nil, err
+	}
+	url, err := NormalizeRemoteURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := RemoveRemote(townRoot, rigDB, name); err != nil {
+		return nil, err
+	}
+	if _, err := remoteQuery(townRoot, rigDB, fmt.Sprintf("CALL DOLT_REMOTE('add', '%s', '%s')", name, url)); err != nil {
+		return nil, fmt.Errorf("adding remote %s to %s: %w", name, rigDB, err)
+	}
+	return &Remote{Database: rigDB, Name: name, URL: url}, nil
+}
+
+// RemoveRemote removes a remote from a rig database. Removing a remote
+// that isn't configured is not an error.
+func RemoveRemote(townRoot, rigDB, name string) error {
+	if err := validateRemoteName(name); err != nil {
+		return err
+	}
+	remotes, err := ListRemotes(townRoot, rigDB)
+	if err != nil {
+		return err
+	}
+	for _, r := range remotes {
+		if r.Name != name {
+			continue
+		}
+		if _, err := remoteQuery(townRoot, rigDB, fmt.Sprintf("CALL DOLT_REMOTE('remove', '%s')", name)); err != nil {
+			return fmt.Errorf("removing remote %s from %s: %w", name, rigDB, err)
+		}
+	}
+	return nil
+}
+
+// hasRemote reports whether a rig database has the named remote.
+func hasRemote(townRoot, rigDB, name string) (bool, error) {
+	remotes, err := ListRemotes(townRoot, rigDB)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range remotes {
+		if r.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RemoteSyncResult is the outcome of pushing or pulling one rig database.
+type RemoteSyncResult struct {
+	Database string `json:"database"`
+	Remote   string `json:"remote"`
+	Skipped  bool   `json:"skipped,omitempty"` // the database has no such remote
+
+	// Incoming and Outgoing count the commits the remote has that the
+	// local main doesn't, and the reverse, when last compared.
+	Incoming int `json:"incoming"`
+	Outgoing int `json:"outgoing"`
+
+	Pushed      bool `json:"pushed,omitempty"`
+	Merged      bool `json:"merged,omitempty"`
+	FastForward bool `json:"fast_forward,omitempty"`
+
+	// Conflicts lists the tables a pull would conflict on. A pull with
+	// conflicts changes nothing.
+	Conflicts []TableConflict `json:"conflicts,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// UpToDate reports whether local main and the remote hold the same commits.
+func (r RemoteSyncResult) UpToDate() bool {
+	return r.Error == "" && !r.Skipped && r.Incoming == 0 && r.Outgoing == 0 && !r.Pushed && !r.Merged
+}
+
+// TableConflict is a table a merge would conflict on.
+type TableConflict struct {
+	Table           string `json:"table"`
+	DataConflicts   int    `json:"data_conflicts"`
+	SchemaConflicts int    `json:"schema_conflicts"`
+}
+
+// PushRig commits a rig database's working changes and pushes main to
+// remote. Without force, a push the remote rejects because it has commits
+// main lacks is reported with a hint to pull first.
+func PushRig(townRoot, rigDB, remote string, force bool) RemoteSyncResult {
+	result := RemoteSyncResult{Database: rigDB, Remote: remote}
+	if !checkRemote(townRoot, &result) {
+		return result
+	}
+
+	if _, err := remoteQuery(townRoot, rigDB, "CALL DOLT_COMMIT('-Am', 'gt dolt push: commit working changes')"); err != nil {
+		if lower := strings.ToLower(err.Error()); !strings.Contains(lower, "nothing to commit") && !strings.Contains(lower, "no changes") {
+			result.Error = fmt.Sprintf("committing working changes: %v", err)
+			return result
+		}
+	}
+	if err := fetchRemote(townRoot, rigDB, remote); err == nil {
+		countDivergence(townRoot, &result)
+	}
+
+	push := fmt.Sprintf("CALL DOLT_PUSH('%s', 'main')", remote)
+	if force {
+		push = fmt.Sprintf("CALL DOLT_PUSH('--force', '%s', 'main')", remote)
+	}
+	if _, err := remoteQuery(townRoot, rigDB, push); err != nil {
+		result.Error = err.Error()
+		if !force && result.Incoming > 0 {
+			result.Error = fmt.Sprintf("%s has %d commit(s) not in main; pull first (or push with --force): %v",
+				remote, result.Incoming, err)
+		}
+		return result
+	}
+	result.Pushed = true
+	return result
+}
+
+// PullRig fetches remote and merges its main into the rig database's main.
+// A fast-forward is applied directly. When the histories have diverged the
+// merge is previewed first, and if any table would conflict nothing is
+// merged and the conflicts are reported.
+func PullRig(townRoot, rigDB, remote string) RemoteSyncResult {
+	result := RemoteSyncResult{Database: rigDB, Remote: remote}
+	if !checkRemote(townRoot, &result) {
+		return result
+	}
+	if err := fetchRemote(townRoot, rigDB, remote); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err := countDivergence(townRoot, &result); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if result.Incoming == 0 {
+		return result
+	}
+
+	if result.Outgoing > 0 {
+		conflicts, err := previewMergeConflicts(townRoot, rigDB, remote+"/main")
+		if err != nil {
+			result.Error = fmt.Sprintf("main and %s/main have diverged and the merge could not be previewed: %v", remote, err)
+			return result
+		}
+		if len(conflicts) > 0 {
+			result.Conflicts = conflicts
+			result.Error = fmt.Sprintf("merging %s/main would conflict in %d table(s); nothing was merged", remote, len(conflicts))
+			return result
+		}
+	}
+
+	if _, err := remoteQuery(townRoot, rigDB, fmt.Sprintf("CALL DOLT_MERGE('%s/main')", remote)); err != nil {
+		result.Error = fmt.Sprintf("merging %s/main: %v", remote, err)
+		return result
+	}
+	result.Merged = true
+	result.FastForward = result.Outgoing == 0
+	return result
+}
+
+// checkRemote validates result's remote and marks the result skipped when
+// the database doesn't have it. Returns false if there is nothing to do.
+func checkRemote(townRoot string, result *RemoteSyncResult) bool {
+	if err := validateRemoteName(result.Remote); err != nil {
+		result.Error = err.Error()
+		return false
+	}
+	ok, err := hasRemote(townRoot, result.Database, result.Remote)
+	if err != nil {
+		result.Error = err.Error()
+		return false
+	}
+	result.Skipped = !ok
+	return ok
+}
+
+func fetchRemote(townRoot, rigDB, remote string) error {
+	if _, err := remoteQuery(townRoot, rigDB, fmt.Sprintf("CALL DOLT_FETCH('%s')", remote)); err != nil {
+		return fmt.Errorf("fetching %s: %w", remote, err)
+	}
+	return nil
+}
+
+// countDivergence fills in result's Incoming and Outgoing from the last
+// fetch. A remote without a main branch yet counts as nothing incoming.
+func countDivergence(townRoot string, result *RemoteSyncResult) error {
+	tracking := result.Remote + "/main"
+	if ok, err := remoteBranchExists(townRoot, result.Database, tracking); err != nil || !ok {
+		if err == nil {
+			result.Outgoing, err = countCommits(townRoot, result.Database, "main")
+		}
+		return err
+	}
+	var err error
+	if result.Incoming, err = countCommits(townRoot, result.Database, "main.."+tracking); err != nil {
+		return err
+	}
+	result.Outgoing, err = countCommits(townRoot, result.Database, tracking+"..main")
+	return err
+}
+
+func remoteBranchExists(townRoot, rigDB, tracking string) (bool, error) {
+	rows, err := remoteQuery(townRoot, rigDB, fmt.Sprintf(
+		"SELECT COUNT(*) AS n FROM dolt_remote_branches WHERE name = 'remotes/%s'", tracking))
+	if err != nil {
+		return false, fmt.Errorf("listing remote branches: %w", err)
+	}
+	recs := csvRecords(rows)
+	return len(recs) == 1 && recs[0]["n"] != "0", nil
+}
+
+func countCommits(townRoot, rigDB, revisions string) (int, error) {
+	rows, err := remoteQuery(townRoot, rigDB, fmt.Sprintf("SELECT COUNT(*) AS n FROM dolt_log('%s')", revisions))
+	if err != nil {
+		return 0, fmt.Errorf("comparing %s: %w", revisions, err)
+	}
+	recs := csvRecords(rows)
+	if len(recs) != 1 {
+		return 0, fmt.Errorf("comparing %s: unexpected result", revisions)
+	}
+	return strconv.Atoi(recs[0]["n"])
+}
+
+// previewMergeConflicts lists the tables that merging from into main
+// would conflict on, without merging.
+func previewMergeConflicts(townRoot, rigDB, from string) ([]TableConflict, error) {
+	rows, err := remoteQuery(townRoot, rigDB, fmt.Sprintf(
+		"SELECT `table`, num_data_conflicts, num_schema_conflicts FROM dolt_preview_merge_conflicts_summary('main', '%s')", from))
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []TableConflict
+	for _, rec := range csvRecords(rows) {
+		data, _ := strconv.Atoi(rec["num_data_conflicts"])
+		schema, _ := strconv.Atoi(rec["num_schema_conflicts"])
+		if data+schema == 0 {
+			continue
+		}
+		conflicts = append(conflicts, TableConflict{Table: rec["table"], DataConflicts: data, SchemaConflicts: schema})
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,129 @@
+package doltserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	tests := map[string]string{
+		"dolthub:acme/gastown":        DoltHubRemoteURL("acme", "gastown"),
+		"/mnt/backup/gastown/":        "file:///mnt/backup/gastown",
+		"aws://[tbl:bucket]/gastown":  "aws://[tbl:bucket]/gastown",
+		"gs://bucket/gastown":         "gs://bucket/gastown",
+		"file:///mnt/backup/gastown":  "file:///mnt/backup/gastown",
+		"https://doltremoteapi.x/a/b": "https://doltremoteapi.x/a/b",
+	}
+	for spec, want := range tests {
+		if got, err := NormalizeRemoteURL(spec); err != nil || got != want {
+			t.Errorf("NormalizeRemoteURL(%q) = %q, %v; want %q", spec, got, err, want)
+		}
+	}
+	for _, bad := range []string{"dolthub:acme", "relative/dir", "ftp://host/db", "file:///x'; DROP"} {
+		if _, err := NormalizeRemoteURL(bad); err == nil {
+			t.Errorf("NormalizeRemoteURL(%q) should fail", bad)
+		}
+	}
+}
+
+// fakeRemoteSQL answers the queries push and pull make through dolt sql,
+// recording the calls that change anything.
+type fakeRemoteSQL struct {
+	remotes   string // dolt_remotes rows
+	tracking  bool   // origin/main exists
+	incoming  string
+	outgoing  string
+	conflicts string // dolt_preview_merge_conflicts_summary rows
+	calls     []string
+}
+
+func (f *fakeRemoteSQL) handle(c proc.Cmd) ([]byte, []byte, error) {
+	q := c.Args[len(c.Args)-1]
+	switch {
+	case strings.Contains(q, "FROM dolt_remotes"):
+		return []byte("name,url\n" + f.remotes), nil, nil
+	case strings.Contains(q, "dolt_remote_branches"):
+		if f.tracking {
+			return []byte("n\n1\n"), nil, nil
+		}
+		return []byte("n\n0\n"), nil, nil
+	case strings.Contains(q, "dolt_log('main..origin/main')"):
+		return []byte("n\n" + f.incoming + "\n"), nil, nil
+	case strings.Contains(q, "dolt_log("):
+		return []byte("n\n" + f.outgoing + "\n"), nil, nil
+	case strings.Contains(q, "dolt_preview_merge_conflicts_summary"):
+		return []byte("table,num_data_conflicts,num_schema_conflicts\n" + f.conflicts), nil, nil
+	case strings.HasPrefix(q, "CALL DOLT_COMMIT"):
+		return nil, []byte("nothing to commit"), errExit
+	}
+	f.calls = append(f.calls, q)
+	return nil, nil, nil
+}
+
+var errExit = errors.New("exit status 1")
+
+func TestPullRig(t *testing.T) {
+	tests := []struct {
+		name       string
+		fake       fakeRemoteSQL
+		wantMerged bool
+		wantFF     bool
+		wantErr    string
+	}{
+		{"up to date", fakeRemoteSQL{tracking: true, incoming: "0", outgoing: "0"}, false, false, ""},
+		{"fast-forward", fakeRemoteSQL{tracking: true, incoming: "3", outgoing: "0"}, true, true, ""},
+		{"clean merge", fakeRemoteSQL{tracking: true, incoming: "3", outgoing: "2"}, true, false, ""},
+		{"conflict", fakeRemoteSQL{tracking: true, incoming: "3", outgoing: "2",
+			conflicts: "issues,2,0\nlabels,0,0\n"}, false, false, "would conflict in 1 table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.fake
+			f.remotes = "origin,file:///mnt/backup/gastown\n"
+			defer SetRunner(&proc.FakeRunner{Handler: f.handle})()
+
+			r := PullRig(t.TempDir(), "gastown", DefaultRemote)
+			if tt.wantErr != "" {
+				if !strings.Contains(r.Error, tt.wantErr) {
+					t.Fatalf("Error = %q, want %q", r.Error, tt.wantErr)
+				}
+			} else if r.Error != "" {
+				t.Fatalf("Error = %q", r.Error)
+			}
+			if r.Merged != tt.wantMerged || r.FastForward != tt.wantFF {
+				t.Errorf("merged %v, fast-forward %v; want %v, %v", r.Merged, r.FastForward, tt.wantMerged, tt.wantFF)
+			}
+			merged := false
+			for _, c := range f.calls {
+				merged = merged || strings.HasPrefix(c, "CALL DOLT_MERGE('origin/main')")
+			}
+			if merged != tt.wantMerged {
+				t.Errorf("DOLT_MERGE called = %v, want %v (calls %v)", merged, tt.wantMerged, f.calls)
+			}
+			if tt.name == "conflict" && (len(r.Conflicts) != 1 || r.Conflicts[0].Table != "issues") {
+				t.Errorf("conflicts = %+v, want issues only", r.Conflicts)
+			}
+		})
+	}
+}
+
+func TestPushRig(t *testing.T) {
+	f := &fakeRemoteSQL{}
+	defer SetRunner(&proc.FakeRunner{Handler: f.handle})()
+
+	if r := PushRig(t.TempDir(), "gastown", DefaultRemote, false); !r.Skipped || r.Error != "" {
+		t.Fatalf("push without remote = %+v, want skipped", r)
+	}
+
+	f.remotes = "origin,file:///mnt/backup/gastown\n"
+	r := PushRig(t.TempDir(), "gastown", DefaultRemote, false)
+	if !r.Pushed || r.Error != "" || r.Outgoing != 0 {
+		t.Fatalf("push = %+v, want pushed", r)
+	}
+	if last := f.calls[len(f.calls)-1]; last != "CALL DOLT_PUSH('origin', 'main')" {
+		t.Errorf("last call = %q", last)
+	}
+}
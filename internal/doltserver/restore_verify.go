@@ -0,0 +1,334 @@
+package doltserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// Restore verification checks.
+const (
+	RestoreCheckClone  = "clone"
+	RestoreCheckFsck   = "fsck"
+	RestoreCheckQuery  = "query"
+	RestoreCheckBdList = "bd_list"
+)
+
+// restoreStepTimeout bounds each step (clone, fsck, query, server start, bd list).
+const restoreStepTimeout = 5 * time.Minute
+
+// RestoreCheck is the outcome of one step of a restore verification.
+type RestoreCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// RestoreVerification records one attempt to restore a database's backup
+// (its origin remote) into a scratch directory and use it.
+type RestoreVerification struct {
+	Time     time.Time      `json:"time"`
+	Database string         `json:"database"`
+	Remote   string         `json:"remote"`
+	Passed   bool           `json:"passed"`
+	Issues   int64          `json:"issues"` // Rows in the restored issues table
+	Duration time.Duration  `json:"duration_ns"`
+	Checks   []RestoreCheck `json:"checks"`
+}
+
+// Failed returns the first failed check, or nil.
+func (v *RestoreVerification) Failed() *RestoreCheck {
+	for i := range v.Checks {
+		if !v.Checks[i].OK && !v.Checks[i].Skipped {
+			return &v.Checks[i]
+		}
+	}
+	return nil
+}
+
+// RestoreVerifyFile returns the path of the restore verification history.
+func RestoreVerifyFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-restore-verify.jsonl")
+}
+
+// BackedUpDatabases returns the databases that have an origin remote to
+// restore from, with their remote URLs.
+func BackedUpDatabases(townRoot string) (map[string]string, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	dataDir := DefaultConfig(townRoot).DataDir
+	remotes := make(map[string]string)
+	for _, db := range databases {
+		if url, err := HasRemote(filepath.Join(dataDir, db)); err == nil && url != "" {
+			remotes[db] = url
+		}
+	}
+	return remotes, nil
+}
+
+// PickRestoreTarget chooses a random backed-up database. Returns an empty
+// name if no database has a remote.
+func PickRestoreTarget(townRoot string) (string, string, error) {
+	remotes, err := BackedUpDatabases(townRoot)
+	if err != nil {
+		return "", "", err
+	}
+	if len(remotes) == 0 {
+		return "", "", nil
+	}
+	names := make([]string, 0, len(remotes))
+	for db := range remotes {
+		names = append(names, db)
+	}
+	db := names[rand.Intn(len(names))] //nolint:gosec // G404: not security-sensitive
+	return db, remotes[db], nil
+}
+
+// VerifyRestore clones rigDB's backup from remote into a fresh scratch
+// directory and checks that it is usable: dolt fsck passes, the issues
+// table can be queried, and bd list works against a throwaway sql-server
+// serving the clone. The scratch directory is removed afterwards unless
+// keep is set. Failures are recorded in the result rather than returned;
+// the error is only for problems setting up the scratch directory.
+func VerifyRestore(rigDB, remote string, keep bool) (*RestoreVerification, error) {
+	start := clk.Now()
+	v := &RestoreVerification{Time: start.UTC(), Database: rigDB, Remote: remote}
+	finish := func() (*RestoreVerification, error) {
+		v.Duration = clk.Since(start)
+		v.Passed = v.Failed() == nil
+		return v, nil
+	}
+
+	scratch, err := os.MkdirTemp("", "gt-restore-verify-")
+	if err != nil {
+		return nil, fmt.Errorf("creating scratch directory: %w", err)
+	}
+	if !keep {
+		defer func() { _ = os.RemoveAll(scratch) }()
+	}
+	dataDir := filepath.Join(scratch, "data")
+	cloneDir := filepath.Join(dataDir, rigDB)
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating scratch directory: %w", err)
+	}
+
+	// 1. Restore: clone the backup.
+	if out, err := runDoltIn(dataDir, "clone", remote, rigDB); err != nil {
+		v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckClone, Detail: errDetail(err, out)})
+		return finish()
+	}
+	v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckClone, OK: true, Detail: scratch})
+
+	// 2. Integrity: dolt fsck (skipped on dolt versions without it).
+	out, err := runDoltIn(cloneDir, "fsck")
+	switch {
+	case err == nil:
+		v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckFsck, OK: true})
+	case isUnknownDoltCommand(out):
+		v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckFsck, Skipped: true, Detail: "dolt fsck not supported by this dolt version"})
+	default:
+		v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckFsck, Detail: errDetail(err, out)})
+	}
+
+	// 3. Query: the restored issues table is readable.
+	out, err = runDoltIn(cloneDir, "sql", "-r", "csv", "-q", "SELECT COUNT(*) FROM issues")
+	if err != nil {
+		v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckQuery, Detail: errDetail(err, out)})
+		return finish()
+	}
+	v.Issues = parseCount(out)
+	v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckQuery, OK: true, Detail: fmt.Sprintf("%d issues", v.Issues)})
+
+	// 4. bd list against a scratch server.
+	v.Checks = append(v.Checks, bdListRestored(scratch, dataDir, rigDB))
+	return finish()
+}
+
+// bdListRestored serves dataDir on a free local port and runs bd list
+// against rigDB through a scratch .beads directory.
+func bdListRestored(scratch, dataDir, rigDB string) RestoreCheck {
+	check := RestoreCheck{Name: RestoreCheckBdList}
+	if _, err := exec.LookPath("bd"); err != nil {
+		check.Skipped = true
+		check.Detail = "bd not found in PATH"
+		return check
+	}
+
+	port, err := freePort()
+	if err != nil {
+		check.Detail = fmt.Sprintf("finding a free port: %v", err)
+		return check
+	}
+	server := exec.Command("dolt", "sql-server", "--host", "127.0.0.1", "--port", strconv.Itoa(port), "--data-dir", dataDir)
+	server.Dir = dataDir
+	var serverLog bytes.Buffer
+	server.Stdout = &serverLog
+	server.Stderr = &serverLog
+	if err := server.Start(); err != nil {
+		check.Detail = fmt.Sprintf("starting scratch sql-server: %v", err)
+		return check
+	}
+	defer func() {
+		_ = server.Process.Kill()
+		_ = server.Wait()
+	}()
+	if err := waitForPort(port, 30*time.Second); err != nil {
+		check.Detail = errDetail(fmt.Errorf("scratch sql-server did not start: %w", err), serverLog.Bytes())
+		return check
+	}
+
+	beadsDir := filepath.Join(scratch, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"database":         "dolt",
+		"backend":          "dolt",
+		"dolt_mode":        "server",
+		"dolt_database":    rigDB,
+		"dolt_server_host": "127.0.0.1",
+		"dolt_server_port": port,
+	})
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), metadata, 0600); err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), restoreStepTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "bd", "list", "--limit", "5")
+	cmd.Dir = scratch
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	out, err := proc.CombinedOutputContext(ctx, cmd)
+	if err != nil {
+		check.Detail = errDetail(err, out)
+		return check
+	}
+	check.OK = true
+	return check
+}
+
+// RecordRestoreVerification appends a result to the history file.
+func RecordRestoreVerification(townRoot string, v *RestoreVerification) error {
+	path := RestoreVerifyFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: history is not sensitive
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadRestoreVerifications reads all recorded results, oldest first. A
+// missing file yields none; unparseable lines are skipped.
+func LoadRestoreVerifications(townRoot string) ([]RestoreVerification, error) {
+	f, err := os.Open(RestoreVerifyFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []RestoreVerification
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var v RestoreVerification
+		if err := json.Unmarshal(scanner.Bytes(), &v); err == nil {
+			history = append(history, v)
+		}
+	}
+	return history, scanner.Err()
+}
+
+// runDoltIn runs a dolt command in dir with the step timeout, returning its
+// combined output.
+func runDoltIn(dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), restoreStepTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "dolt", args...)
+	cmd.Dir = dir
+	return proc.CombinedOutputContext(ctx, cmd)
+}
+
+// isUnknownDoltCommand reports whether dolt rejected the subcommand itself.
+func isUnknownDoltCommand(out []byte) bool {
+	s := strings.ToLower(string(out))
+	return strings.Contains(s, "is not a valid command") || strings.Contains(s, "unknown command")
+}
+
+// parseCount reads a single COUNT(*) value from dolt's CSV output.
+func parseCount(out []byte) int64 {
+	rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil || len(rows) < 2 || len(rows[1]) == 0 {
+		return 0
+	}
+	n, _ := strconv.ParseInt(strings.TrimSpace(rows[1][0]), 10, 64)
+	return n
+}
+
+// errDetail combines an error with the last line of command output.
+func errDetail(err error, out []byte) string {
+	msg := strings.TrimSpace(string(out))
+	if i := strings.LastIndex(msg, "\n"); i >= 0 {
+		msg = strings.TrimSpace(msg[i+1:])
+	}
+	if msg == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v: %s", err, msg)
+}
+
+// freePort asks the kernel for an unused local TCP port.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitForPort waits until something accepts connections on the local port.
+func waitForPort(port int, timeout time.Duration) error {
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	deadline := clk.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		if clk.Now().After(deadline) {
+			return err
+		}
+		clk.Sleep(250 * time.Millisecond)
+	}
+}
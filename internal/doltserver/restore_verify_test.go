@@ -0,0 +1,76 @@
+package doltserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRestoreVerificationHistoryRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	history, err := LoadRestoreVerifications(townRoot)
+	if err != nil || len(history) != 0 {
+		t.Fatalf("LoadRestoreVerifications(empty) = %v, %v; want none", history, err)
+	}
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*RestoreVerification{
+		{Time: t0, Database: "gastown", Passed: true, Issues: 42, Checks: []RestoreCheck{{Name: RestoreCheckClone, OK: true}}},
+		{Time: t0.Add(24 * time.Hour), Database: "beads", Checks: []RestoreCheck{{Name: RestoreCheckClone, Detail: "remote not found"}}},
+	}
+	for _, v := range runs {
+		if err := RecordRestoreVerification(townRoot, v); err != nil {
+			t.Fatalf("RecordRestoreVerification: %v", err)
+		}
+	}
+
+	history, err = LoadRestoreVerifications(townRoot)
+	if err != nil {
+		t.Fatalf("LoadRestoreVerifications: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d results, want 2", len(history))
+	}
+	if history[0].Database != "gastown" || !history[0].Passed || history[0].Issues != 42 {
+		t.Errorf("first result = %+v", history[0])
+	}
+	if failed := history[1].Failed(); failed == nil || failed.Name != RestoreCheckClone {
+		t.Errorf("second result Failed() = %+v, want clone", failed)
+	}
+}
+
+func TestRestoreVerificationFailed(t *testing.T) {
+	v := &RestoreVerification{Checks: []RestoreCheck{
+		{Name: RestoreCheckClone, OK: true},
+		{Name: RestoreCheckFsck, Skipped: true},
+		{Name: RestoreCheckQuery, OK: true},
+	}}
+	if failed := v.Failed(); failed != nil {
+		t.Errorf("Failed() = %+v, want nil (skipped checks don't fail)", failed)
+	}
+
+	v.Checks = append(v.Checks, RestoreCheck{Name: RestoreCheckBdList})
+	if failed := v.Failed(); failed == nil || failed.Name != RestoreCheckBdList {
+		t.Errorf("Failed() = %+v, want bd_list", failed)
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	if got := parseCount([]byte("COUNT(*)\n1234\n")); got != 1234 {
+		t.Errorf("parseCount = %d, want 1234", got)
+	}
+	if got := parseCount([]byte("")); got != 0 {
+		t.Errorf("parseCount(empty) = %d, want 0", got)
+	}
+}
+
+func TestErrDetail(t *testing.T) {
+	err := errors.New("exit status 1")
+	if got := errDetail(err, []byte("cloning...\nfatal: repository not found\n")); got != "exit status 1: fatal: repository not found" {
+		t.Errorf("errDetail = %q", got)
+	}
+	if got := errDetail(err, nil); got != "exit status 1" {
+		t.Errorf("errDetail(no output) = %q", got)
+	}
+}
@@ -0,0 +1,336 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// serverMetadataFields are the metadata.json fields that point bd at a rig
+// database on the Dolt server.
+var serverMetadataFields = []string{"dolt_database", "dolt_mode", "dolt_server_port", "dolt_server_user"}
+
+// RigInUseError is returned by DropRig and RenameRig when a rig database
+// still has work in flight.
+type RigInUseError struct {
+	Rig         string
+	Branches    []string // polecat branches not yet merged or dropped
+	Connections int      // server connections using the database
+}
+
+func (e *RigInUseError) Error() string {
+	var parts []string
+	if len(e.Branches) > 0 {
+		parts = append(parts, fmt.Sprintf("%d polecat branch(es) (%s)", len(e.Branches), strings.Join(e.Branches, ", ")))
+	}
+	if e.Connections > 0 {
+		parts = append(parts, fmt.Sprintf("%d open connection(s)", e.Connections))
+	}
+	return fmt.Sprintf("rig database %q is in use: %s", e.Rig, strings.Join(parts, ", "))
+}
+
+// validateRigName checks that a rig name is usable as a database name.
+func validateRigName(rigName string) error {
+	if rigName == "" {
+		return fmt.Errorf("rig name cannot be empty")
+	}
+	for _, r := range rigName {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-') {
+			return fmt.Errorf("invalid rig name %q: must contain only alphanumeric, underscore, or dash", rigName)
+		}
+	}
+	return nil
+}
+
+// checkRigIdle returns a *RigInUseError if rigDB has polecat branches or, with
+// the server running, open connections.
+func checkRigIdle(townRoot, rigDB string) error {
+	branches, err := ListPolecatBranches(townRoot, rigDB)
+	if err != nil {
+		return fmt.Errorf("listing polecat branches: %w", err)
+	}
+	inUse := &RigInUseError{Rig: rigDB, Branches: branches}
+	if running, _, _ := IsRunning(townRoot); running {
+		procs, err := ListProcesses(townRoot)
+		if err != nil {
+			return err
+		}
+		for _, p := range procs {
+			if p.Database == rigDB {
+				inUse.Connections++
+			}
+		}
+	}
+	if len(inUse.Branches) > 0 || inUse.Connections > 0 {
+		return inUse
+	}
+	return nil
+}
+
+// DropRigResult describes a dropped rig database.
+type DropRigResult struct {
+	Rig      string          `json:"rig"`
+	Backup   string          `json:"backup"`
+	Metadata *MetadataResult `json:"metadata,omitempty"` // nil when metadata.json didn't point at the database
+}
+
+// DropRig backs up and removes a rig's database, then removes the server
+// fields from the rig's metadata.json so bd stops looking for it. Unless
+// force is set, it refuses while the database has polecat branches or open
+// connections. The rig itself stays registered; see gt rig remove.
+//
+// The backup is written as for PruneDeletedRigDatabases; nothing is
+// removed if it fails.
+func DropRig(townRoot, rigName string, force bool) (*DropRigResult, error) {
+	if err := validateRigName(rigName); err != nil {
+		return nil, err
+	}
+	if rigName == "hq" {
+		return nil, fmt.Errorf("refusing to drop the town database (hq)")
+	}
+	if !DatabaseExists(townRoot, rigName) {
+		return nil, fmt.Errorf("database %q not found in %s", rigName, DefaultConfig(townRoot).DataDir)
+	}
+	if !force {
+		if err := checkRigIdle(townRoot, rigName); err != nil {
+			return nil, err
+		}
+	}
+
+	backup, err := BackupDatabaseDir(townRoot, rigName)
+	if err != nil {
+		return nil, fmt.Errorf("backing up before drop: %w", err)
+	}
+	result := &DropRigResult{Rig: rigName, Backup: backup}
+	if err := RemoveDatabase(townRoot, rigName); err != nil {
+		return result, err
+	}
+
+	result.Metadata, err = patchRigMetadata(townRoot, rigName, rigName, func(meta map[string]interface{}) {
+		for _, field := range serverMetadataFields {
+			delete(meta, field)
+		}
+	})
+	if err != nil {
+		return result, fmt.Errorf("database dropped but metadata.json cleanup failed: %w", err)
+	}
+	return result, nil
+}
+
+// RenameRigResult describes a renamed rig.
+type RenameRigResult struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	RigDir    string          `json:"rig_dir,omitempty"` // new rig directory, when the rig is registered
+	Metadata  *MetadataResult `json:"metadata,omitempty"`
+	Routes    int             `json:"routes"`    // routes.jsonl entries rewritten
+	Worktrees int             `json:"worktrees"` // git worktrees repaired after the move
+	Warnings  []string        `json:"warnings,omitempty"`
+}
+
+// RenameRig renames a rig and its database: .dolt-data/<from> becomes
+// .dolt-data/<to> and metadata.json's dolt_database follows. When the rig
+// is registered in rigs.json, its entry and directory are renamed too,
+// routes.jsonl paths are rewritten, and the rig's git worktrees are
+// repaired to point at their new location.
+//
+// The Dolt server must be stopped (it holds the database directory open)
+// and the rig must have no polecat branches. Failures after the database
+// and directory have moved are reported as warnings rather than undone.
+func RenameRig(townRoot, from, to string) (*RenameRigResult, error) {
+	for _, name := range []string{from, to} {
+		if err := validateRigName(name); err != nil {
+			return nil, err
+		}
+		if name == "hq" {
+			return nil, fmt.Errorf("refusing to rename the town database (hq)")
+		}
+	}
+	if from == to {
+		return nil, fmt.Errorf("rig is already named %q", to)
+	}
+	if running, _, _ := IsRunning(townRoot); running {
+		return nil, fmt.Errorf("the Dolt server is running; stop it before renaming a rig database")
+	}
+	if !DatabaseExists(townRoot, from) {
+		return nil, fmt.Errorf("database %q not found in %s", from, DefaultConfig(townRoot).DataDir)
+	}
+	newDBDir := RigDatabaseDir(townRoot, to)
+	if _, err := os.Stat(newDBDir); err == nil {
+		return nil, fmt.Errorf("database %q already exists at %s", to, newDBDir)
+	}
+	if err := checkRigIdle(townRoot, from); err != nil {
+		return nil, err
+	}
+
+	rigs, err := rigsconfig.Load(townRoot)
+	if err != nil && !errors.Is(err, rigsconfig.ErrNotFound) {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	registered := false
+	if rigs != nil {
+		_, registered = rigs.Rigs[from]
+	}
+	oldRigDir, newRigDir := filepath.Join(townRoot, from), filepath.Join(townRoot, to)
+	if registered {
+		if err := rigsconfig.ValidateName(to); err != nil {
+			return nil, err
+		}
+		if _, taken := rigs.Rigs[to]; taken {
+			return nil, fmt.Errorf("rig %q is already registered", to)
+		}
+		if _, err := os.Stat(newRigDir); err == nil {
+			return nil, fmt.Errorf("%s already exists", newRigDir)
+		}
+	}
+
+	if err := os.Rename(RigDatabaseDir(townRoot, from), newDBDir); err != nil {
+		return nil, fmt.Errorf("renaming database directory: %w", err)
+	}
+	result := &RenameRigResult{From: from, To: to}
+	metadataRig := from
+
+	if registered {
+		if _, err := os.Stat(oldRigDir); err == nil {
+			if err := os.Rename(oldRigDir, newRigDir); err != nil {
+				_ = os.Rename(newDBDir, RigDatabaseDir(townRoot, from))
+				return nil, fmt.Errorf("renaming rig directory: %w", err)
+			}
+			result.RigDir = newRigDir
+			metadataRig = to
+			n, err := repairRigWorktrees(newRigDir)
+			result.Worktrees = n
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("repairing worktrees: %v", err))
+			}
+		}
+
+		rigs.Rigs[to] = rigs.Rigs[from]
+		delete(rigs.Rigs, from)
+		if err := rigsconfig.Save(townRoot, rigs); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("updating rigs.json: %v", err))
+		}
+
+		n, err := renameRigRoutes(townRoot, from, to)
+		result.Routes = n
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("updating routes.jsonl: %v", err))
+		}
+	}
+
+	result.Metadata, err = patchRigMetadata(townRoot, metadataRig, from, func(meta map[string]interface{}) {
+		meta["dolt_database"] = to
+	})
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("updating metadata.json: %v", err))
+	}
+	return result, nil
+}
+
+// patchRigMetadata applies patch to rigName's metadata.json if its
+// dolt_database is rigDB, writing it with the town's metadata options.
+// Returns nil if the file doesn't point at rigDB.
+func patchRigMetadata(townRoot, rigName, rigDB string, patch func(map[string]interface{})) (*MetadataResult, error) {
+	beadsDir := FindRigBeadsDir(townRoot, rigName)
+	if beadsDir == "" || readExistingDoltDatabase(beadsDir) != rigDB {
+		return nil, nil
+	}
+	existing, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json"))
+	if err != nil {
+		return nil, err
+	}
+	meta := make(map[string]interface{})
+	if err := json.Unmarshal(existing, &meta); err != nil {
+		return nil, fmt.Errorf("parsing metadata.json: %w", err)
+	}
+	patch(meta)
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling metadata: %w", err)
+	}
+	return WriteMetadataFile(townRoot, rigName, beadsDir, append(data, '\n'), DefaultMetadataOptions(townRoot))
+}
+
+// renameRigRoutes rewrites town routes.jsonl paths under from/ to to/ and
+// returns how many changed.
+func renameRigRoutes(townRoot, from, to string) (int, error) {
+	beadsDir := filepath.Join(townRoot, ".beads")
+	routes, err := beads.LoadRoutes(beadsDir)
+	if err != nil || len(routes) == 0 {
+		return 0, err
+	}
+	var n int
+	for i, r := range routes {
+		switch {
+		case r.Path == from:
+			routes[i].Path = to
+		case strings.HasPrefix(r.Path, from+"/"):
+			routes[i].Path = to + strings.TrimPrefix(r.Path, from)
+		default:
+			continue
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, beads.WriteRoutes(beadsDir, routes)
+}
+
+// repairRigWorktrees re-links the git worktrees of a moved rig directory
+// with their repository (.repo.git, or the mayor clone for older rigs),
+// whose recorded paths still point at the old location. Returns how many
+// worktrees were repaired.
+func repairRigWorktrees(rigDir string) (int, error) {
+	repo := filepath.Join(rigDir, ".repo.git")
+	if _, err := os.Stat(repo); err != nil {
+		repo = filepath.Join(rigDir, "mayor", "rig")
+		if _, err := os.Stat(filepath.Join(repo, ".git")); err != nil {
+			return 0, nil
+		}
+	}
+
+	// A worktree has a .git file (a directory is a full clone). Polecat
+	// worktrees sit a few levels down; nothing inside a checkout is one.
+	var worktrees []string
+	err := filepath.WalkDir(rigDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != rigDir && (d.Name() == ".repo.git" || strings.Count(strings.TrimPrefix(path, rigDir), string(filepath.Separator)) > 4) {
+			return filepath.SkipDir
+		}
+		info, statErr := os.Lstat(filepath.Join(path, ".git"))
+		if statErr != nil {
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			worktrees = append(worktrees, path)
+		}
+		if path != rigDir {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil || len(worktrees) == 0 {
+		return 0, err
+	}
+
+	cmd := exec.Command("git", append([]string{"worktree", "repair"}, worktrees...)...)
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return 0, fmt.Errorf("git worktree repair: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return len(worktrees), nil
+}
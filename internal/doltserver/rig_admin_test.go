@@ -0,0 +1,130 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// fakeBranches answers dolt_branches queries with the given branch names.
+func fakeBranches(branches ...string) func() {
+	return SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		out := "name\n"
+		for _, b := range branches {
+			out += b + "\n"
+		}
+		return []byte(out), nil, nil
+	}})
+}
+
+func TestDropRig(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+	defer fakeBranches()()
+	townRoot := t.TempDir()
+	dbPath := setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "gastown")
+	setupRigMetadata(t, townRoot, "gastown", "gastown")
+
+	result, err := DropRig(townRoot, "gastown", false)
+	if err != nil {
+		t.Fatalf("DropRig: %v", err)
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Errorf("database directory still exists: %v", err)
+	}
+	if want := filepath.Join(PrunedDatabasesDir(townRoot), "gastown-20260310-120000.tar.gz"); result.Backup != want {
+		t.Errorf("backup = %s, want %s", result.Backup, want)
+	}
+	if result.Metadata == nil || result.Metadata.Action != MetadataWritten {
+		t.Fatalf("metadata = %+v, want written", result.Metadata)
+	}
+	beadsDir := FindRigBeadsDir(townRoot, "gastown")
+	if db := readExistingDoltDatabase(beadsDir); db != "" {
+		t.Errorf("metadata.json still points at %q", db)
+	}
+
+	if _, err := DropRig(townRoot, "gastown", false); err == nil {
+		t.Error("dropping a missing database succeeded")
+	}
+	if _, err := DropRig(townRoot, "hq", true); err == nil {
+		t.Error("dropping hq succeeded")
+	}
+}
+
+func TestDropRig_InUse(t *testing.T) {
+	defer fakeBranches("polecat-alpha-1773144000")()
+	townRoot := t.TempDir()
+	dbPath := setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "gastown")
+
+	_, err := DropRig(townRoot, "gastown", false)
+	var inUse *RigInUseError
+	if !errors.As(err, &inUse) || len(inUse.Branches) != 1 {
+		t.Fatalf("DropRig error = %v, want RigInUseError with one branch", err)
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		t.Fatalf("database removed despite live branch: %v", err)
+	}
+
+	if _, err := DropRig(townRoot, "gastown", true); err != nil {
+		t.Fatalf("DropRig --force: %v", err)
+	}
+}
+
+func TestRenameRig(t *testing.T) {
+	defer fakeBranches()()
+	townRoot := t.TempDir()
+	setupDoltDB(t, filepath.Join(townRoot, ".dolt-data"), "gastown")
+	setupRigsJSON(t, townRoot, []string{"gastown", "beads"})
+	setupRigMetadata(t, townRoot, "gastown", "gastown")
+	townBeads := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(townBeads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := beads.WriteRoutes(townBeads, []beads.Route{
+		{Prefix: "gt-", Path: "gastown/mayor/rig"},
+		{Prefix: "bd-", Path: "beads/mayor/rig"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RenameRig(townRoot, "gastown", "beads"); err == nil {
+		t.Fatal("renaming onto a registered rig succeeded")
+	}
+
+	result, err := RenameRig(townRoot, "gastown", "town")
+	if err != nil {
+		t.Fatalf("RenameRig: %v", err)
+	}
+	if len(result.Warnings) > 0 {
+		t.Errorf("warnings: %v", result.Warnings)
+	}
+	if !DatabaseExists(townRoot, "town") || DatabaseExists(townRoot, "gastown") {
+		t.Error("database directory was not renamed")
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "town", "mayor", "rig")); err != nil {
+		t.Errorf("rig directory was not renamed: %v", err)
+	}
+	if db := readExistingDoltDatabase(FindRigBeadsDir(townRoot, "town")); db != "town" {
+		t.Errorf("metadata dolt_database = %q, want town", db)
+	}
+	names, err := rigsconfig.Names(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "beads" || names[1] != "town" {
+		t.Errorf("rigs = %v, want [beads town]", names)
+	}
+	routes, err := beads.LoadRoutes(townBeads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Routes != 1 || routes[0].Path != "town/mayor/rig" || routes[1].Path != "beads/mayor/rig" {
+		t.Errorf("routes = %+v (%d rewritten), want gt- moved to town", routes, result.Routes)
+	}
+}
@@ -0,0 +1,158 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RigLinksTable is the town-level table in the hq database that records
+// dependencies between beads in different rigs. Each rig's beads database
+// only knows its own issues, so a backend bead blocking a frontend bead
+// can't be expressed as an ordinary bd dependency.
+const RigLinksTable = "rig_links"
+
+// rigLinksDB is the database holding RigLinksTable.
+const rigLinksDB = "hq"
+
+// BeadRef names a bead in a specific rig, written rig:bead-id.
+type BeadRef struct {
+	Rig  string `json:"rig"`
+	Bead string `json:"bead"`
+}
+
+// ParseBeadRef parses a rig:bead-id reference.
+func ParseBeadRef(s string) (BeadRef, error) {
+	rig, bead, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || rig == "" || bead == "" || strings.ContainsAny(rig+bead, " \t/:") {
+		return BeadRef{}, fmt.Errorf("invalid bead reference %q: want rig:bead-id (e.g. backend:be-abc12)", s)
+	}
+	return BeadRef{Rig: rig, Bead: bead}, nil
+}
+
+func (r BeadRef) String() string {
+	return r.Rig + ":" + r.Bead
+}
+
+// RigLink records that Blocker must close before Blocked can be worked.
+type RigLink struct {
+	Blocker   BeadRef `json:"blocker"`
+	Blocked   BeadRef `json:"blocked"`
+	CreatedBy string  `json:"created_by,omitempty"`
+	CreatedAt string  `json:"created_at,omitempty"`
+}
+
+// AddRigLink records a link, creating RigLinksTable on first use. Adding a
+// link that already exists is a no-op; added reports whether it was new.
+func AddRigLink(townRoot string, link RigLink) (added bool, err error) {
+	if link.Blocker == link.Blocked {
+		return false, fmt.Errorf("%s cannot block itself", link.Blocker)
+	}
+	if err := ensureRigLinksTable(townRoot); err != nil {
+		return false, err
+	}
+	links, err := ListRigLinks(townRoot)
+	if err != nil {
+		return false, err
+	}
+	for _, l := range links {
+		if l.Blocker == link.Blocker && l.Blocked == link.Blocked {
+			return false, nil
+		}
+	}
+
+	msg := fmt.Sprintf("gt bead link: %s blocks %s", link.Blocker, link.Blocked)
+	script := fmt.Sprintf("INSERT INTO `%s` (blocker_rig, blocker_bead, blocked_rig, blocked_bead, created_by, created_at) "+
+		"VALUES (%s, %s, %s, %s, %s, '%s'); CALL DOLT_COMMIT('-Am', %s)",
+		RigLinksTable, sqlString(link.Blocker.Rig), sqlString(link.Blocker.Bead),
+		sqlString(link.Blocked.Rig), sqlString(link.Blocked.Bead), sqlString(link.CreatedBy),
+		clk.Now().UTC().Format("2006-01-02 15:04:05"), sqlString(msg))
+	if _, err := doltQueryCSV(townRoot, rigLinksDB, script); err != nil {
+		return false, fmt.Errorf("adding link %s → %s: %w", link.Blocker, link.Blocked, err)
+	}
+	return true, nil
+}
+
+// RemoveRigLink deletes the link from blocker to blocked, reporting whether
+// there was one.
+func RemoveRigLink(townRoot string, blocker, blocked BeadRef) (bool, error) {
+	links, err := ListRigLinks(townRoot)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	for _, l := range links {
+		if l.Blocker == blocker && l.Blocked == blocked {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, nil
+	}
+
+	msg := fmt.Sprintf("gt bead unlink: %s no longer blocks %s", blocker, blocked)
+	script := fmt.Sprintf("DELETE FROM `%s` WHERE blocker_rig = %s AND blocker_bead = %s AND blocked_rig = %s AND blocked_bead = %s; "+
+		"CALL DOLT_COMMIT('-Am', %s)",
+		RigLinksTable, sqlString(blocker.Rig), sqlString(blocker.Bead),
+		sqlString(blocked.Rig), sqlString(blocked.Bead), sqlString(msg))
+	if _, err := doltQueryCSV(townRoot, rigLinksDB, script); err != nil {
+		return false, fmt.Errorf("removing link %s → %s: %w", blocker, blocked, err)
+	}
+	return true, nil
+}
+
+// ListRigLinks returns every cross-rig link, or none if no link was ever
+// added or the town has no hq database.
+func ListRigLinks(townRoot string) ([]RigLink, error) {
+	if _, err := os.Stat(filepath.Join(DefaultConfig(townRoot).DataDir, rigLinksDB)); err != nil {
+		return nil, nil
+	}
+	exists, err := tableExists(townRoot, rigLinksDB, RigLinksTable)
+	if err != nil || !exists {
+		return nil, err
+	}
+	rows, err := doltQueryCSV(townRoot, rigLinksDB, fmt.Sprintf(
+		"SELECT blocker_rig, blocker_bead, blocked_rig, blocked_bead, created_by, created_at FROM `%s` "+
+			"ORDER BY blocker_rig, blocker_bead, blocked_rig, blocked_bead", RigLinksTable))
+	if err != nil {
+		return nil, fmt.Errorf("listing cross-rig links: %w", err)
+	}
+	var links []RigLink
+	for _, rec := range csvRecords(rows) {
+		links = append(links, RigLink{
+			Blocker:   BeadRef{Rig: rec["blocker_rig"], Bead: rec["blocker_bead"]},
+			Blocked:   BeadRef{Rig: rec["blocked_rig"], Bead: rec["blocked_bead"]},
+			CreatedBy: rec["created_by"],
+			CreatedAt: rec["created_at"],
+		})
+	}
+	return links, nil
+}
+
+// BlockersOf returns the beads linked as blocking beadID. Bead prefixes are
+// unique across the town, so the ID alone identifies the bead.
+func BlockersOf(links []RigLink, beadID string) []BeadRef {
+	var out []BeadRef
+	for _, l := range links {
+		if l.Blocked.Bead == beadID {
+			out = append(out, l.Blocker)
+		}
+	}
+	return out
+}
+
+// ensureRigLinksTable creates RigLinksTable in the hq database if needed.
+func ensureRigLinksTable(townRoot string) error {
+	_, err := doltQueryCSV(townRoot, rigLinksDB, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS `%s` ("+
+			"blocker_rig VARCHAR(64) NOT NULL, blocker_bead VARCHAR(128) NOT NULL, "+
+			"blocked_rig VARCHAR(64) NOT NULL, blocked_bead VARCHAR(128) NOT NULL, "+
+			"created_by VARCHAR(255), created_at DATETIME, "+
+			"PRIMARY KEY (blocker_rig, blocker_bead, blocked_rig, blocked_bead))", RigLinksTable))
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", RigLinksTable, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestParseBeadRef(t *testing.T) {
+	ref, err := ParseBeadRef("backend:be-abc12")
+	if err != nil {
+		t.Fatalf("ParseBeadRef: %v", err)
+	}
+	if ref != (BeadRef{Rig: "backend", Bead: "be-abc12"}) || ref.String() != "backend:be-abc12" {
+		t.Errorf("ref = %+v", ref)
+	}
+	for _, bad := range []string{"be-abc12", ":be-abc12", "backend:", "back end:be-1", "a:b:c", "gastown/polecats:x"} {
+		if _, err := ParseBeadRef(bad); err == nil {
+			t.Errorf("ParseBeadRef(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestAddRigLink(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", "hq"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+
+	var queries []string
+	rows := "blocker_rig,blocker_bead,blocked_rig,blocked_bead,created_by,created_at\n"
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		queries = append(queries, query)
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			return []byte("n\n1\n"), nil, nil
+		case strings.Contains(query, "SELECT blocker_rig"):
+			return []byte(rows), nil, nil
+		}
+		return nil, nil, nil
+	}})()
+
+	link := RigLink{
+		Blocker:   BeadRef{Rig: "backend", Bead: "be-1"},
+		Blocked:   BeadRef{Rig: "frontend", Bead: "fe-2"},
+		CreatedBy: "mayor/",
+	}
+	added, err := AddRigLink(townRoot, link)
+	if err != nil || !added {
+		t.Fatalf("AddRigLink = %v, %v; want added", added, err)
+	}
+	insert := queries[len(queries)-1]
+	for _, want := range []string{
+		"INSERT INTO `rig_links`",
+		"VALUES ('backend', 'be-1', 'frontend', 'fe-2', 'mayor/', '2026-03-10 12:00:00')",
+		"CALL DOLT_COMMIT",
+	} {
+		if !strings.Contains(insert, want) {
+			t.Errorf("insert missing %q:\n%s", want, insert)
+		}
+	}
+
+	// The same link again is a no-op.
+	rows += "backend,be-1,frontend,fe-2,mayor/,2026-03-10 12:00:00\n"
+	n := len(queries)
+	added, err = AddRigLink(townRoot, link)
+	if err != nil || added {
+		t.Fatalf("second AddRigLink = %v, %v; want not added", added, err)
+	}
+	for _, q := range queries[n:] {
+		if strings.Contains(q, "INSERT") {
+			t.Errorf("duplicate link was inserted: %s", q)
+		}
+	}
+
+	if _, err := AddRigLink(townRoot, RigLink{Blocker: link.Blocker, Blocked: link.Blocker}); err == nil {
+		t.Error("expected error linking a bead to itself")
+	}
+}
+
+func TestListRigLinks_NoHQDatabase(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		t.Errorf("unexpected dolt call: %v", c.Args)
+		return nil, nil, nil
+	}})()
+	links, err := ListRigLinks(t.TempDir())
+	if err != nil || links != nil {
+		t.Errorf("ListRigLinks = %v, %v; want none", links, err)
+	}
+}
+
+func TestBlockersOf(t *testing.T) {
+	links := []RigLink{
+		{Blocker: BeadRef{"backend", "be-1"}, Blocked: BeadRef{"frontend", "fe-2"}},
+		{Blocker: BeadRef{"infra", "in-3"}, Blocked: BeadRef{"frontend", "fe-2"}},
+		{Blocker: BeadRef{"backend", "be-1"}, Blocked: BeadRef{"mobile", "mo-4"}},
+	}
+	got := BlockersOf(links, "fe-2")
+	if len(got) != 2 || got[0].Bead != "be-1" || got[1].Bead != "in-3" {
+		t.Errorf("BlockersOf(fe-2) = %v", got)
+	}
+	if got := BlockersOf(links, "be-1"); len(got) != 0 {
+		t.Errorf("BlockersOf(be-1) = %v, want none", got)
+	}
+}
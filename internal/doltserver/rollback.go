@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Backup represents a discovered migration backup directory.
@@ -71,6 +72,53 @@ func FindBackups(townRoot string) ([]Backup, error) {
 	return backups, nil
 }
 
+// BackupForMigration snapshots the .beads directory holding each migration's
+// source database into <townRoot>/migration-backup-<YYYYMMDD-HHMMSS>/, in the
+// layout RestoreFromBackup expects: town-beads/ for the hq database and
+// <rigname>-beads/ for each rig. A metadata.json records when and by what
+// the backup was taken. Returns the backup path.
+//
+// On failure the partial backup directory is removed so FindBackups never
+// offers it to gt dolt rollback.
+func BackupForMigration(townRoot string, migrations []Migration) (string, error) {
+	now := time.Now()
+	backupPath := filepath.Join(townRoot, "migration-backup-"+now.Format("20060102-150405"))
+	if err := os.Mkdir(backupPath, 0755); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	var sources []string
+	for _, m := range migrations {
+		// SourcePath is <beads>/dolt/<db>; back up the whole .beads directory
+		// so metadata.json and config are restored alongside the database.
+		beadsDir := filepath.Dir(filepath.Dir(m.SourcePath))
+		name := m.RigName + "-beads"
+		if m.RigName == "hq" {
+			name = "town-beads"
+		}
+		if err := copyDir(filepath.Join(backupPath, name), beadsDir); err != nil {
+			_ = os.RemoveAll(backupPath)
+			return "", fmt.Errorf("backing up %s: %w", beadsDir, err)
+		}
+		sources = append(sources, beadsDir)
+	}
+
+	meta := map[string]interface{}{
+		"created_at": now.UTC().Format(time.RFC3339),
+		"created_by": "gt dolt migrate",
+		"sources":    sources,
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(backupPath, "metadata.json"), data, 0644)
+	}
+	if err != nil {
+		_ = os.RemoveAll(backupPath)
+		return "", fmt.Errorf("writing backup metadata: %w", err)
+	}
+	return backupPath, nil
+}
+
 // RollbackResult tracks what was restored during rollback.
 type RollbackResult struct {
 	BackupPath    string
@@ -81,8 +129,8 @@ type RollbackResult struct {
 }
 
 // RestoreFromBackup restores .beads directories from a migration backup.
-// The backup directory is expected to have the structure created by the
-// migration formula's backup step:
+// The backup directory is expected to have the structure created by
+// BackupForMigration (or the migration formula's backup step):
 //
 //	migration-backup-TIMESTAMP/
 //	├── town-beads/          → restored to <townRoot>/.beads
@@ -311,3 +311,54 @@ func TestCopyDir(t *testing.T) {
 		t.Errorf("nested.txt = %q, want world", string(data))
 	}
 }
+
+func TestBackupForMigration_RoundTripsWithRestore(t *testing.T) {
+	townRoot := t.TempDir()
+	townDB := filepath.Join(townRoot, ".beads", "dolt", "beads_hq")
+	rigDB := filepath.Join(townRoot, "gastown", ".beads", "dolt", "beads_gt")
+	for _, dir := range []string{townDB, rigDB} {
+		if err := os.MkdirAll(filepath.Join(dir, ".dolt"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		beadsDir := filepath.Dir(filepath.Dir(dir))
+		if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"backend":"sqlite"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backupPath, err := BackupForMigration(townRoot, []Migration{
+		{RigName: "hq", SourcePath: townDB},
+		{RigName: "gastown", SourcePath: rigDB},
+	})
+	if err != nil {
+		t.Fatalf("BackupForMigration: %v", err)
+	}
+	for _, p := range []string{"town-beads/dolt/beads_hq/.dolt", "gastown-beads/dolt/beads_gt/.dolt", "gastown-beads/metadata.json"} {
+		if _, err := os.Stat(filepath.Join(backupPath, p)); err != nil {
+			t.Errorf("backup missing %s: %v", p, err)
+		}
+	}
+
+	backups, err := FindBackups(townRoot)
+	if err != nil || len(backups) != 1 || backups[0].Path != backupPath {
+		t.Fatalf("FindBackups = %v, %v; want %s", backups, err, backupPath)
+	}
+	if backups[0].Metadata["created_by"] != "gt dolt migrate" {
+		t.Errorf("metadata = %v", backups[0].Metadata)
+	}
+
+	// Simulate the migration moving the rig database away, then roll back.
+	if err := os.RemoveAll(filepath.Join(townRoot, "gastown", ".beads", "dolt")); err != nil {
+		t.Fatal(err)
+	}
+	result, err := RestoreFromBackup(townRoot, backupPath)
+	if err != nil {
+		t.Fatalf("RestoreFromBackup: %v", err)
+	}
+	if !result.RestoredTown || len(result.RestoredRigs) != 1 || result.RestoredRigs[0] != "gastown" {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(rigDB, ".dolt")); err != nil {
+		t.Errorf("rig database not restored: %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShadowBaselineTag tags the commit in a shadow database holding the copy
+// of the source database, so diffs and promotion see only what the formula
+// run changed.
+const ShadowBaselineTag = "shadow-baseline"
+
+// shadowNameRe matches shadow database names: <source>_shadow_<timestamp>.
+var shadowNameRe = regexp.MustCompile(`^(.+)_shadow_(\d{14})$`)
+
+// Shadow is a throwaway copy of a rig database on the town's Dolt server,
+// used to run formulas without touching production beads.
+type Shadow struct {
+	Source    string    `json:"source"`
+	Database  string    `json:"database"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ParseShadow returns the shadow described by a database name, or nil if
+// name is not a shadow database.
+func ParseShadow(name string) *Shadow {
+	m := shadowNameRe.FindStringSubmatch(name)
+	if m == nil {
+		return nil
+	}
+	created, err := time.Parse("20060102150405", m[2])
+	if err != nil {
+		return nil
+	}
+	return &Shadow{Source: m[1], Database: name, CreatedAt: created}
+}
+
+// IsShadowDatabase reports whether name is a shadow database. Shadows are
+// left out of ListDatabases so patrols, backups, and exports skip them.
+func IsShadowDatabase(name string) bool {
+	return ParseShadow(name) != nil
+}
+
+// ListShadows returns the shadow databases in the town's data directory,
+// oldest first.
+func ListShadows(townRoot string) ([]*Shadow, error) {
+	dataDir := DefaultConfig(townRoot).DataDir
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var shadows []*Shadow
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if s := ParseShadow(entry.Name()); s != nil {
+			shadows = append(shadows, s)
+		}
+	}
+	sort.Slice(shadows, func(i, j int) bool { return shadows[i].CreatedAt.Before(shadows[j].CreatedAt) })
+	return shadows, nil
+}
+
+// CreateShadow copies every table of rigDB into a new database on the
+// running server, commits the copy, and tags it ShadowBaselineTag.
+// Tables are copied with CREATE TABLE ... LIKE, which keeps columns and
+// indexes but not foreign keys, so cascading deletes do not happen in the
+// shadow; deleting a bead there leaves its labels and dependencies behind.
+func CreateShadow(townRoot, rigDB string) (*Shadow, error) {
+	if IsShadowDatabase(rigDB) {
+		return nil, fmt.Errorf("%s is already a shadow database", rigDB)
+	}
+	tables, err := baseTables(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("database %s has no tables to copy", rigDB)
+	}
+
+	now := clk.Now().UTC()
+	s := &Shadow{
+		Source:    rigDB,
+		Database:  fmt.Sprintf("%s_shadow_%s", rigDB, now.Format("20060102150405")),
+		CreatedAt: now.Truncate(time.Second),
+	}
+
+	var script strings.Builder
+	fmt.Fprintf(&script, "CREATE DATABASE `%s`;\nUSE `%s`;\n", s.Database, s.Database)
+	for _, table := range tables {
+		fmt.Fprintf(&script, "CREATE TABLE `%s` LIKE `%s`.`%s`;\n", table, rigDB, table)
+		fmt.Fprintf(&script, "INSERT INTO `%s` SELECT * FROM `%s`.`%s`;\n", table, rigDB, table)
+	}
+	fmt.Fprintf(&script, "CALL DOLT_COMMIT('-Am', 'gt shadow: copy of %s');\n", rigDB)
+	fmt.Fprintf(&script, "CALL DOLT_TAG('%s');\n", ShadowBaselineTag)
+
+	if err := doltSQLScript(townRoot, script.String()); err != nil {
+		// Don't leave a half-copied shadow behind.
+		_ = doltSQLScript(townRoot, fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", s.Database))
+		return nil, fmt.Errorf("copying %s into %s: %w", rigDB, s.Database, err)
+	}
+	return s, nil
+}
+
+// baseTables returns the user tables of rigDB, skipping views and Dolt
+// system tables.
+func baseTables(townRoot, rigDB string) ([]string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT table_name AS name FROM information_schema.tables "+
+			"WHERE table_schema = '%s' AND table_type = 'BASE TABLE' ORDER BY table_name", sqlEscape(rigDB)))
+	if err != nil {
+		return nil, fmt.Errorf("listing tables in %s: %w", rigDB, err)
+	}
+	var tables []string
+	for _, rec := range csvRecords(rows) {
+		if name := rec["name"]; name != "" && !strings.HasPrefix(name, "dolt_") {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// ShadowTableDiff counts the rows a shadow run changed in one table.
+type ShadowTableDiff struct {
+	Table    string `json:"table"`
+	Added    int64  `json:"added"`
+	Modified int64  `json:"modified"`
+	Removed  int64  `json:"removed"`
+}
+
+// ShadowReport describes everything changed in a shadow database since its
+// baseline, committed or not.
+type ShadowReport struct {
+	Shadow
+	Tables []ShadowTableDiff `json:"tables"`
+	Beads  []BeadChange      `json:"beads"`
+}
+
+// Changed reports whether the shadow run changed any rows.
+func (r *ShadowReport) Changed() bool {
+	return len(r.Tables) > 0
+}
+
+// DiffShadow reports the rows changed in s since its baseline, per table,
+// and the bead creations, status changes, and closes among them.
+func DiffShadow(townRoot string, s *Shadow) (*ShadowReport, error) {
+	report := &ShadowReport{Shadow: *s, Tables: []ShadowTableDiff{}, Beads: []BeadChange{}}
+
+	rows, err := doltQueryCSV(townRoot, s.Database, fmt.Sprintf(
+		"SELECT table_name, rows_added, rows_modified, rows_deleted FROM DOLT_DIFF_STAT('%s', 'WORKING')",
+		ShadowBaselineTag))
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s: %w", s.Database, err)
+	}
+	for _, rec := range csvRecords(rows) {
+		d := ShadowTableDiff{Table: rec["table_name"]}
+		d.Added, _ = strconv.ParseInt(rec["rows_added"], 10, 64)
+		d.Modified, _ = strconv.ParseInt(rec["rows_modified"], 10, 64)
+		d.Removed, _ = strconv.ParseInt(rec["rows_deleted"], 10, 64)
+		if d.Added+d.Modified+d.Removed > 0 {
+			report.Tables = append(report.Tables, d)
+		}
+	}
+
+	beads, err := BeadChangesBetween(townRoot, s.Database, ShadowBaselineTag, "WORKING")
+	if err != nil {
+		return nil, err
+	}
+	report.Beads = append(report.Beads, beads...)
+	return report, nil
+}
+
+// PromoteShadow replays the row changes made in s since its baseline onto
+// the source database in one transaction and commits them, returning the
+// number of statements applied. Schema changes are refused. If the source
+// changed the same rows in the meantime the replay fails and the source is
+// left as it was.
+func PromoteShadow(townRoot string, s *Shadow) (int, error) {
+	rows, err := doltQueryCSV(townRoot, s.Database, fmt.Sprintf(
+		"SELECT diff_type, table_name, statement FROM DOLT_PATCH('%s', 'WORKING') ORDER BY statement_order",
+		ShadowBaselineTag))
+	if err != nil {
+		return 0, fmt.Errorf("reading changes in %s: %w", s.Database, err)
+	}
+	var statements []string
+	for _, rec := range csvRecords(rows) {
+		if rec["diff_type"] == "schema" {
+			return 0, fmt.Errorf("%s changed the schema of %s; only row changes can be promoted", s.Database, rec["table_name"])
+		}
+		stmt := strings.TrimSuffix(strings.TrimSpace(rec["statement"]), ";")
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	if len(statements) == 0 {
+		return 0, nil
+	}
+
+	// The patch is ordered by table, not by dependency, so a label can be
+	// inserted before its bead. Foreign key checks are off for the replay;
+	// the shadow started as a consistent copy of the source.
+	var script strings.Builder
+	fmt.Fprintf(&script, "USE `%s`;\nSET FOREIGN_KEY_CHECKS = 0;\nSTART TRANSACTION;\n", s.Source)
+	for _, stmt := range statements {
+		script.WriteString(stmt + ";\n")
+	}
+	script.WriteString("COMMIT;\nSET FOREIGN_KEY_CHECKS = 1;\n")
+	fmt.Fprintf(&script, "CALL DOLT_COMMIT('-Am', 'gt shadow: promote %s');\n", s.Database)
+
+	if err := doltSQLScript(townRoot, script.String()); err != nil {
+		return 0, fmt.Errorf("promoting %s into %s: %w", s.Database, s.Source, err)
+	}
+	return len(statements), nil
+}
+
+// DiscardShadow drops a shadow database. Refuses names that are not
+// shadow databases, so a typo can't drop a rig.
+func DiscardShadow(townRoot, database string) error {
+	if !IsShadowDatabase(database) {
+		return fmt.Errorf("%s is not a shadow database", database)
+	}
+	if err := doltSQLScript(townRoot, fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", database)); err != nil {
+		return fmt.Errorf("dropping %s: %w", database, err)
+	}
+	return nil
+}
+
+// WriteShadowBeadsDir creates dir/.beads with metadata pointing bd at the
+// shadow database on the town's server, and returns the .beads path for
+// BEADS_DIR. Formula files are copied from formulasDir when it is set, so
+// bd cook in the scratch workspace finds them.
+func WriteShadowBeadsDir(townRoot string, s *Shadow, dir, formulasDir string) (string, error) {
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(filepath.Join(beadsDir, "formulas"), 0755); err != nil {
+		return "", err
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"database":         "dolt",
+		"backend":          "dolt",
+		"dolt_mode":        "server",
+		"dolt_database":    s.Database,
+		"dolt_server_host": "127.0.0.1",
+		"dolt_server_port": DefaultConfig(townRoot).Port,
+	})
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), metadata, 0600); err != nil {
+		return "", err
+	}
+	if formulasDir == "" {
+		return beadsDir, nil
+	}
+	entries, err := os.ReadDir(formulasDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return beadsDir, nil
+		}
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(formulasDir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(beadsDir, "formulas", entry.Name()), data, 0644); err != nil { //nolint:gosec // G306: formulas are not sensitive
+			return "", err
+		}
+	}
+	return beadsDir, nil
+}
@@ -0,0 +1,214 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// scriptOf returns the SQL of a dolt sql --file command, or "".
+func scriptOf(c proc.Cmd) string {
+	for i, a := range c.Args {
+		if a == "--file" && i+1 < len(c.Args) {
+			data, _ := os.ReadFile(c.Args[i+1])
+			return string(data)
+		}
+	}
+	return ""
+}
+
+func TestParseShadow(t *testing.T) {
+	s := ParseShadow("my_rig_shadow_20260310120000")
+	if s == nil || s.Source != "my_rig" || !s.CreatedAt.Equal(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("ParseShadow = %+v", s)
+	}
+	for _, name := range []string{"gastown", "gastown_shadow_", "gastown_shadow_2026", "shadow_20260310120000"} {
+		if IsShadowDatabase(name) {
+			t.Errorf("IsShadowDatabase(%q) = true", name)
+		}
+	}
+}
+
+func TestListDatabases_SkipsShadows(t *testing.T) {
+	townRoot := t.TempDir()
+	dataDir := DefaultConfig(townRoot).DataDir
+	for _, db := range []string{"gastown", "gastown_shadow_20260310120000"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, db, ".dolt"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dbs, err := ListDatabases(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dbs) != 1 || dbs[0] != "gastown" {
+		t.Errorf("ListDatabases = %v, want [gastown]", dbs)
+	}
+	shadows, err := ListShadows(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shadows) != 1 || shadows[0].Source != "gastown" {
+		t.Errorf("ListShadows = %+v, want one shadow of gastown", shadows)
+	}
+}
+
+func TestCreateShadow(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+	var script string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if s := scriptOf(c); s != "" {
+			script = s
+			return nil, nil, nil
+		}
+		return []byte("name\ndependencies\ndolt_ignore\nissues\n"), nil, nil
+	}})()
+
+	s, err := CreateShadow(t.TempDir(), "gastown")
+	if err != nil {
+		t.Fatalf("CreateShadow: %v", err)
+	}
+	if s.Database != "gastown_shadow_20260310120000" || s.Source != "gastown" {
+		t.Errorf("shadow = %+v", s)
+	}
+	for _, want := range []string{
+		"CREATE DATABASE `gastown_shadow_20260310120000`;",
+		"CREATE TABLE `dependencies` LIKE `gastown`.`dependencies`;",
+		"INSERT INTO `issues` SELECT * FROM `gastown`.`issues`;",
+		"CALL DOLT_TAG('shadow-baseline');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("shadow script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "dolt_ignore") {
+		t.Errorf("shadow script copies a dolt system table:\n%s", script)
+	}
+
+	if _, err := CreateShadow(t.TempDir(), s.Database); err == nil {
+		t.Error("expected error shadowing a shadow")
+	}
+}
+
+func TestDiffShadow(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		switch {
+		case strings.Contains(query, "DOLT_DIFF_STAT('shadow-baseline', 'WORKING')"):
+			return []byte("table_name,rows_added,rows_modified,rows_deleted\n" +
+				"issues,3,1,0\nlabels,0,0,0\n"), nil, nil
+		case strings.Contains(query, "DOLT_DIFF('shadow-baseline', 'WORKING', 'issues')"):
+			return []byte("diff_type,from_id,to_id,from_status,to_status,to_title\n" +
+				"added,,gt-a1,,open,Step one\n" +
+				"modified,gt-b2,gt-b2,open,closed,Root\n"), nil, nil
+		}
+		return nil, nil, nil
+	}})()
+
+	s := ParseShadow("gastown_shadow_20260310120000")
+	report, err := DiffShadow(t.TempDir(), s)
+	if err != nil {
+		t.Fatalf("DiffShadow: %v", err)
+	}
+	if !report.Changed() || len(report.Tables) != 1 || report.Tables[0].Added != 3 || report.Tables[0].Modified != 1 {
+		t.Errorf("tables = %+v, want issues with 3 added, 1 modified", report.Tables)
+	}
+	if len(report.Beads) != 2 || report.Beads[0].Kind != ChangeCreated || report.Beads[1].Kind != ChangeClosed {
+		t.Errorf("beads = %+v, want one created and one closed", report.Beads)
+	}
+}
+
+func TestPromoteShadow(t *testing.T) {
+	var script string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if s := scriptOf(c); s != "" {
+			script = s
+			return nil, nil, nil
+		}
+		return []byte("diff_type,table_name,statement\n" +
+			"data,issues,\"INSERT INTO `issues` (`id`,`title`) VALUES ('gt-a1','Step one');\"\n" +
+			"data,labels,\"DELETE FROM `labels` WHERE `issue_id` = 'gt-b2' AND `label` = 'x';\"\n"), nil, nil
+	}})()
+
+	s := ParseShadow("gastown_shadow_20260310120000")
+	n, err := PromoteShadow(t.TempDir(), s)
+	if err != nil {
+		t.Fatalf("PromoteShadow: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("applied %d statements, want 2", n)
+	}
+	for _, want := range []string{
+		"USE `gastown`;",
+		"START TRANSACTION;\nINSERT INTO `issues` (`id`,`title`) VALUES ('gt-a1','Step one');\nDELETE FROM `labels`",
+		"COMMIT;",
+		"CALL DOLT_COMMIT('-Am', 'gt shadow: promote gastown_shadow_20260310120000');",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("promote script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestPromoteShadow_RefusesSchemaChanges(t *testing.T) {
+	var scripts int
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if scriptOf(c) != "" {
+			scripts++
+			return nil, nil, nil
+		}
+		return []byte("diff_type,table_name,statement\nschema,issues,ALTER TABLE `issues` ADD COLUMN x INT;\n"), nil, nil
+	}})()
+
+	if _, err := PromoteShadow(t.TempDir(), ParseShadow("gastown_shadow_20260310120000")); err == nil {
+		t.Error("expected error promoting a schema change")
+	}
+	if scripts != 0 {
+		t.Errorf("ran %d scripts against the source, want 0", scripts)
+	}
+}
+
+func TestDiscardShadow_RefusesRigDatabases(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		t.Errorf("unexpected dolt call: %v", c.Args)
+		return nil, nil, nil
+	}})()
+	if err := DiscardShadow(t.TempDir(), "gastown"); err == nil {
+		t.Error("expected error discarding a rig database")
+	}
+}
+
+func TestWriteShadowBeadsDir(t *testing.T) {
+	townRoot := t.TempDir()
+	formulas := t.TempDir()
+	if err := os.WriteFile(filepath.Join(formulas, "mol-test.formula.toml"), []byte("formula = \"mol-test\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	beadsDir, err := WriteShadowBeadsDir(townRoot, ParseShadow("gastown_shadow_20260310120000"), dir, formulas)
+	if err != nil {
+		t.Fatalf("WriteShadowBeadsDir: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta["dolt_database"] != "gastown_shadow_20260310120000" || meta["dolt_mode"] != "server" {
+		t.Errorf("metadata = %v", meta)
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "formulas", "mol-test.formula.toml")); err != nil {
+		t.Errorf("formula not copied: %v", err)
+	}
+}
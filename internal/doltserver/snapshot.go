@@ -0,0 +1,57 @@
+package doltserver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DatabaseSnapshot records a database's state when it was tagged.
+type DatabaseSnapshot struct {
+	Database string `json:"database"`
+	Commit   string `json:"commit,omitempty"`
+
+	// Uncommitted is the number of tables with working set changes, which
+	// the tag does not capture.
+	Uncommitted int    `json:"uncommitted,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// TagDatabases tags HEAD of every database on the server with tag, so its
+// state can be restored later with a reset to the tag. Nothing is committed:
+// working set changes are only counted. A database that can't be tagged is
+// reported in its snapshot and the rest are still tagged.
+func TagDatabases(townRoot, tag, message string) ([]DatabaseSnapshot, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	var snaps []DatabaseSnapshot
+	for _, db := range databases {
+		snap := DatabaseSnapshot{Database: db}
+		if err := tagDatabase(townRoot, db, tag, message, &snap); err != nil {
+			snap.Error = err.Error()
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+func tagDatabase(townRoot, db, tag, message string, snap *DatabaseSnapshot) error {
+	rows, err := doltQueryCSV(townRoot, db,
+		"SELECT HASHOF('HEAD') AS head, (SELECT COUNT(*) FROM dolt_status) AS dirty")
+	if err != nil {
+		return err
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 {
+		return fmt.Errorf("no HEAD for %s", db)
+	}
+	snap.Commit = recs[0]["head"]
+	snap.Uncommitted, _ = strconv.Atoi(recs[0]["dirty"])
+
+	if _, err := doltQueryCSV(townRoot, db, fmt.Sprintf("CALL DOLT_TAG(%s, %s, '-m', %s)",
+		sqlString(tag), sqlString(snap.Commit), sqlString(message))); err != nil {
+		return fmt.Errorf("tagging %s: %w", db, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestTagDatabases(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, db := range []string{"gastown", "hq"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", db, ".dolt"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var tags []string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		switch {
+		case strings.Contains(query, "USE hq;") && strings.Contains(query, "DOLT_TAG"):
+			return nil, []byte("tag already exists"), errors.New("exit status 1")
+		case strings.Contains(query, "DOLT_TAG"):
+			tags = append(tags, query)
+			return nil, nil, nil
+		case strings.Contains(query, "HASHOF"):
+			return []byte("head,dirty\nabc123,2\n"), nil, nil
+		}
+		return nil, nil, nil
+	}})()
+
+	snaps, err := TagDatabases(townRoot, "gt-panic-1", "frozen")
+	if err != nil {
+		t.Fatalf("TagDatabases: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("snapshots = %+v, want 2", snaps)
+	}
+	if s := snaps[0]; s.Database != "gastown" || s.Commit != "abc123" || s.Uncommitted != 2 || s.Error != "" {
+		t.Errorf("gastown snapshot = %+v", s)
+	}
+	if s := snaps[1]; s.Database != "hq" || !strings.Contains(s.Error, "tagging hq") {
+		t.Errorf("hq snapshot = %+v, want tag error", s)
+	}
+	if len(tags) != 1 || !strings.Contains(tags[0], "CALL DOLT_TAG('gt-panic-1', 'abc123', '-m', 'frozen')") {
+		t.Errorf("tag queries = %v", tags)
+	}
+}
@@ -0,0 +1,295 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// SplitBrainReport is the result of cross-checking one rig's bead storage:
+// what metadata.json tells bd to use, which copies of the database exist,
+// and whether their issue counts agree. A rig is split-brained when bd can
+// write to a copy the server doesn't serve, usually an embedded
+// .beads/dolt/ database left behind by an interrupted migration or a
+// metadata.json reverted to embedded mode.
+type SplitBrainReport struct {
+	Rig      string `json:"rig"`
+	BeadsDir string `json:"beads_dir"`
+
+	// DoltMode is metadata.json's dolt_mode; empty when the file or the
+	// field is missing.
+	DoltMode string `json:"dolt_mode"`
+
+	// ServerDB is true when .dolt-data/<rig> exists.
+	ServerDB bool `json:"server_db"`
+
+	// Embedded is the embedded database still under the beads directory,
+	// or "" if there is none.
+	Embedded string `json:"embedded,omitempty"`
+
+	// ServerIssues and EmbeddedIssues are the issue counts of each copy,
+	// -1 when the copy is missing or couldn't be read.
+	ServerIssues   int64 `json:"server_issues"`
+	EmbeddedIssues int64 `json:"embedded_issues"`
+
+	// Problems describe each way the copies diverge; empty when the rig
+	// is consistent.
+	Problems []string `json:"problems,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Divergent reports whether the rig's copies disagree.
+func (r *SplitBrainReport) Divergent() bool {
+	return len(r.Problems) > 0
+}
+
+// Reconcilable reports whether ReconcileSplitBrain can merge the rig: both
+// an embedded and a server copy exist.
+func (r *SplitBrainReport) Reconcilable() bool {
+	return r.Embedded != "" && r.ServerDB
+}
+
+// VerifySplitBrain checks the town database ("hq") and every registered
+// rig, or only the named rigs, for split-brain storage.
+func VerifySplitBrain(townRoot string, rigs []string) ([]*SplitBrainReport, error) {
+	if len(rigs) == 0 {
+		names, err := rigsconfig.Names(townRoot)
+		if err != nil {
+			return nil, fmt.Errorf("listing rigs: %w", err)
+		}
+		rigs = append([]string{"hq"}, names...)
+	}
+	reports := make([]*SplitBrainReport, 0, len(rigs))
+	for _, rig := range rigs {
+		reports = append(reports, checkSplitBrain(townRoot, rig))
+	}
+	return reports, nil
+}
+
+func checkSplitBrain(townRoot, rig string) *SplitBrainReport {
+	r := &SplitBrainReport{
+		Rig:            rig,
+		BeadsDir:       FindRigBeadsDir(townRoot, rig),
+		ServerDB:       DatabaseExists(townRoot, rig),
+		ServerIssues:   -1,
+		EmbeddedIssues: -1,
+	}
+	if data, err := os.ReadFile(filepath.Join(r.BeadsDir, "metadata.json")); err == nil {
+		var meta map[string]interface{}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			r.Error = fmt.Sprintf("parsing metadata.json: %v", err)
+			return r
+		}
+		if mode, ok := meta["dolt_mode"].(string); ok {
+			r.DoltMode = mode
+		}
+	}
+	r.Embedded = findLocalDoltDB(r.BeadsDir)
+
+	if r.ServerDB {
+		n, err := countIssues(func(q string) ([][]string, error) { return doltQueryCSV(townRoot, rig, q) })
+		if err != nil {
+			r.Error = fmt.Sprintf("counting server issues: %v", err)
+		}
+		r.ServerIssues = n
+	}
+	if r.Embedded != "" {
+		n, err := countIssues(func(q string) ([][]string, error) { return embeddedQueryCSV(r.Embedded, q) })
+		if err != nil && r.Error == "" {
+			r.Error = fmt.Sprintf("counting embedded issues: %v", err)
+		}
+		r.EmbeddedIssues = n
+	}
+
+	switch {
+	case r.ServerDB && r.DoltMode != "server":
+		mode := r.DoltMode
+		if mode == "" {
+			mode = "unset"
+		}
+		r.Problems = append(r.Problems, fmt.Sprintf("metadata.json dolt_mode is %s but the server has a %s database", mode, rig))
+	case !r.ServerDB && r.DoltMode == "server":
+		r.Problems = append(r.Problems, fmt.Sprintf("metadata.json points bd at the server but .dolt-data/%s does not exist", rig))
+	}
+	if r.ServerDB && r.Embedded != "" {
+		r.Problems = append(r.Problems, "embedded database still present at "+r.Embedded)
+	}
+	if r.ServerIssues >= 0 && r.EmbeddedIssues >= 0 && r.ServerIssues != r.EmbeddedIssues {
+		r.Problems = append(r.Problems, fmt.Sprintf("issue counts differ: server %d, embedded %d", r.ServerIssues, r.EmbeddedIssues))
+	}
+	return r
+}
+
+// countIssues returns the issues row count using query, or -1 on error.
+func countIssues(query func(string) ([][]string, error)) (int64, error) {
+	rows, err := query("SELECT COUNT(*) AS n FROM issues")
+	if err != nil {
+		return -1, err
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 {
+		return -1, fmt.Errorf("no count returned")
+	}
+	return strconv.ParseInt(recs[0]["n"], 10, 64)
+}
+
+// embeddedQueryCSV runs query with dolt sql inside an embedded database
+// directory, which the server does not serve.
+func embeddedQueryCSV(dbDir, query string) ([][]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	output, stderr, err := runDolt(ctx, dbDir, "sql", "-r", "csv", "-q", query)
+	if err != nil {
+		return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stderr)))
+	}
+	r := csv.NewReader(bytes.NewReader(output))
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// SplitBrainReconcile describes a merge of an embedded copy into the server.
+type SplitBrainReconcile struct {
+	Rig string `json:"rig"`
+
+	// Imported is how many issues the server gained.
+	Imported int64 `json:"imported"`
+
+	// Backup is the archive of the server database taken before the merge.
+	Backup string `json:"backup"`
+
+	// Retired is where the embedded copy was moved so bd can't write to it.
+	Retired string `json:"retired"`
+}
+
+// ReconcileSplitBrain merges a rig's embedded copy into its server
+// database. The embedded copy is exported with dolt dump and loaded into a
+// scratch database on the server; rows the server lacks are then copied
+// into every table both databases share, matching on primary key, so rows
+// already on the server are never overwritten. The server database is
+// backed up first and the merge is one Dolt commit.
+//
+// Afterwards the embedded database is moved out of the beads directory
+// (to dolt.split-brain-<timestamp>/) and metadata.json is pointed at the
+// server, so bd stops writing to the stale copy.
+func ReconcileSplitBrain(townRoot, rig string) (*SplitBrainReconcile, error) {
+	if err := validateRigName(rig); err != nil {
+		return nil, err
+	}
+	report := checkSplitBrain(townRoot, rig)
+	if !report.Reconcilable() {
+		return nil, fmt.Errorf("%s has no embedded and server copy to reconcile", rig)
+	}
+	result := &SplitBrainReconcile{Rig: rig}
+	before, err := countIssues(func(q string) ([][]string, error) { return doltQueryCSV(townRoot, rig, q) })
+	if err != nil {
+		return nil, fmt.Errorf("counting server issues: %w", err)
+	}
+
+	if result.Backup, err = BackupDatabaseDir(townRoot, rig); err != nil {
+		return nil, fmt.Errorf("backing up %s: %w", rig, err)
+	}
+
+	dump, err := os.CreateTemp("", "gt-split-brain-*.sql")
+	if err != nil {
+		return nil, err
+	}
+	dump.Close()
+	defer os.Remove(dump.Name())
+	ctx, cancel := context.WithTimeout(context.Background(), dumpTimeout)
+	defer cancel()
+	if stdout, stderr, err := runDolt(ctx, report.Embedded,
+		"dump", "-r", "sql", "-fn", dump.Name(), "-f", "--no-create-db"); err != nil {
+		return nil, fmt.Errorf("dumping embedded copy: %w (output: %s)", err, strings.TrimSpace(string(stdout)+string(stderr)))
+	}
+	data, err := os.ReadFile(dump.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	scratch := fmt.Sprintf("%s_splitbrain_%s", rig, clk.Now().UTC().Format("20060102150405"))
+	if err := runDumpScript(townRoot, fmt.Sprintf("CREATE DATABASE `%s`;\nUSE `%s`;\n%s\n", scratch, scratch, data)); err != nil {
+		return nil, fmt.Errorf("loading embedded copy: %w", err)
+	}
+	defer func() { _ = runDumpScript(townRoot, fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;\n", scratch)) }()
+
+	script, err := splitBrainMergeScript(townRoot, rig, scratch)
+	if err != nil {
+		return nil, err
+	}
+	if err := runDumpScript(townRoot, script); err != nil {
+		return nil, fmt.Errorf("merging into %s: %w", rig, err)
+	}
+
+	after, err := countIssues(func(q string) ([][]string, error) { return doltQueryCSV(townRoot, rig, q) })
+	if err != nil {
+		return nil, fmt.Errorf("counting server issues: %w", err)
+	}
+	result.Imported = after - before
+
+	doltDir := filepath.Dir(report.Embedded)
+	result.Retired = fmt.Sprintf("%s.split-brain-%s", doltDir, clk.Now().UTC().Format("20060102-150405"))
+	if err := os.Rename(doltDir, result.Retired); err != nil {
+		return result, fmt.Errorf("retiring embedded copy: %w", err)
+	}
+	if report.DoltMode != "server" {
+		if err := EnsureMetadata(townRoot, rig); err != nil {
+			return result, fmt.Errorf("updating metadata.json: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// splitBrainMergeScript returns the SQL that copies rows missing from rig
+// out of scratch, for every table both have, over the columns both have.
+func splitBrainMergeScript(townRoot, rig, scratch string) (string, error) {
+	scratchTables, err := listTables(townRoot, scratch)
+	if err != nil {
+		return "", fmt.Errorf("listing tables in embedded copy: %w", err)
+	}
+	serverTables, err := listTables(townRoot, rig)
+	if err != nil {
+		return "", fmt.Errorf("listing tables in %s: %w", rig, err)
+	}
+	onServer := make(map[string]bool, len(serverTables))
+	for _, t := range serverTables {
+		onServer[t] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "USE `%s`;\nSET FOREIGN_KEY_CHECKS = 0;\n", rig)
+	for _, table := range scratchTables {
+		if !onServer[table] || strings.HasPrefix(table, "dolt_") {
+			continue
+		}
+		serverCols, err := tableColumns(townRoot, rig, table)
+		if err != nil {
+			return "", err
+		}
+		scratchCols, err := tableColumns(townRoot, scratch, table)
+		if err != nil {
+			return "", err
+		}
+		// Auto-increment keys are copied too: rows from before the split
+		// have the same keys on both sides and must not be duplicated.
+		var cols []string
+		for _, c := range sharedColumns(scratchCols, serverCols) {
+			cols = append(cols, "`"+c.Name+"`")
+		}
+		if len(cols) == 0 {
+			continue
+		}
+		list := strings.Join(cols, ", ")
+		fmt.Fprintf(&b, "INSERT IGNORE INTO `%s` (%s) SELECT %s FROM `%s`.`%s`;\n", table, list, list, scratch, table)
+	}
+	fmt.Fprintf(&b, "SET FOREIGN_KEY_CHECKS = 1;\nCALL DOLT_COMMIT('-Am', 'gt dolt verify-sync: merge embedded copy of %s', '--allow-empty');\n", rig)
+	return b.String(), nil
+}
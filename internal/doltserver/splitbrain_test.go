@@ -0,0 +1,95 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestVerifySplitBrain(t *testing.T) {
+	townRoot := t.TempDir()
+	makeRigDatabase(t, townRoot, "gastown")
+	makeRigDatabase(t, townRoot, "beads")
+
+	// gastown: metadata reverted to embedded, embedded copy still present.
+	gtBeads := filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads")
+	embedded := filepath.Join(gtBeads, "dolt", "beads_gt")
+	if err := os.MkdirAll(filepath.Join(embedded, ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gtBeads, "metadata.json"), []byte(`{"dolt_mode":"embedded"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// beads: consistent server-mode rig.
+	bdBeads := filepath.Join(townRoot, "beads", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(bdBeads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bdBeads, "metadata.json"), []byte(`{"dolt_mode":"server"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if c.Dir == embedded {
+			return []byte("n\n12\n"), nil, nil
+		}
+		return []byte("n\n10\n"), nil, nil
+	}})()
+
+	reports, err := VerifySplitBrain(townRoot, []string{"gastown", "beads"})
+	if err != nil {
+		t.Fatalf("VerifySplitBrain: %v", err)
+	}
+	gt := reports[0]
+	if !gt.Divergent() || !gt.Reconcilable() || gt.DoltMode != "embedded" {
+		t.Fatalf("gastown = %+v, want divergent and reconcilable", gt)
+	}
+	if gt.ServerIssues != 10 || gt.EmbeddedIssues != 12 {
+		t.Errorf("counts = %d/%d, want 10/12", gt.ServerIssues, gt.EmbeddedIssues)
+	}
+	if len(gt.Problems) != 3 || !strings.Contains(gt.Problems[2], "server 10, embedded 12") {
+		t.Errorf("problems = %q, want mode, embedded copy and count", gt.Problems)
+	}
+	if bd := reports[1]; bd.Divergent() || bd.EmbeddedIssues != -1 {
+		t.Errorf("beads = %+v, want consistent", bd)
+	}
+}
+
+func TestSplitBrainMergeScript(t *testing.T) {
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		scratch := strings.HasPrefix(query, "USE gastown_splitbrain")
+		switch {
+		case strings.Contains(query, "SHOW TABLES") && scratch:
+			return []byte("Tables\nissues\nlabels\nlegacy\n"), nil, nil
+		case strings.Contains(query, "SHOW TABLES"):
+			return []byte("Tables\nissues\nlabels\n"), nil, nil
+		case strings.Contains(query, "table_name = 'issues'") && scratch:
+			return []byte("name,extra\nid,\ntitle,\n"), nil, nil
+		case strings.Contains(query, "table_name = 'issues'"):
+			return []byte("name,extra\nid,\ntitle,\nowner,\n"), nil, nil
+		default:
+			return []byte("name,extra\nissue_id,\nlabel,\n"), nil, nil
+		}
+	}})()
+
+	script, err := splitBrainMergeScript(t.TempDir(), "gastown", "gastown_splitbrain_1")
+	if err != nil {
+		t.Fatalf("splitBrainMergeScript: %v", err)
+	}
+	for _, want := range []string{
+		"INSERT IGNORE INTO `issues` (`id`, `title`) SELECT `id`, `title` FROM `gastown_splitbrain_1`.`issues`;",
+		"INSERT IGNORE INTO `labels` (`issue_id`, `label`)",
+		"CALL DOLT_COMMIT(",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "legacy") {
+		t.Errorf("script copies a table the server lacks:\n%s", script)
+	}
+}
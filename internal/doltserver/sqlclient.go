@@ -0,0 +1,182 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Queries go over the MySQL protocol to the running server through a
+// connection pool kept per town, instead of starting a dolt process per
+// query. Without a server (embedded mode) they fall back to the dolt CLI.
+var (
+	sqlPoolsMu sync.Mutex
+	sqlPools   = map[string]*sql.DB{}
+)
+
+// serverPool returns the connection pool for townRoot's Dolt server, or nil
+// if no server started by gt dolt start is running there. The server is
+// found from its PID file rather than by probing the port, so a town
+// without one never talks to some other server on the same port.
+func serverPool(townRoot string) (*sql.DB, error) {
+	config := DefaultConfig(townRoot)
+	pid := readPIDFile(config.PidFile)
+	if pid <= 0 || !procs.Alive(pid) {
+		return nil, nil
+	}
+
+	sqlPoolsMu.Lock()
+	defer sqlPoolsMu.Unlock()
+	if db, ok := sqlPools[townRoot]; ok {
+		return db, nil
+	}
+	// Rows are scanned as strings to match dolt sql -r csv, so times are
+	// left unparsed.
+	cfg, err := dsnConfig(townRoot, DSNOptions{Timeout: 5 * time.Second, MultiStatements: true})
+	if err != nil {
+		return nil, err
+	}
+	db, err := openPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sqlPools[townRoot] = db
+	return db, nil
+}
+
+// serverQuery runs query against rigDB (none if empty) on townRoot's server
+// and returns the last result set as rows of strings, header first, the
+// shape `dolt sql -r csv` output parses to. NULLs come back as "". ok is
+// false when no server is running and the caller should use the CLI.
+func serverQuery(ctx context.Context, townRoot, rigDB, query string) (rows [][]string, ok bool, err error) {
+	db, err := serverPool(townRoot)
+	if db == nil {
+		return nil, false, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	defer func() {
+		// USE, DOLT_CHECKOUT, SET and open transactions outlive the query.
+		// Discard the connection rather than hand that state to whoever
+		// takes it from the pool next.
+		if !leavesNoSessionState(rigDB, query) {
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}()
+
+	// Select the database on the pooled connection itself so every
+	// statement in a multi-statement query sees it.
+	if rigDB != "" {
+		if _, err := conn.ExecContext(ctx, "USE `"+strings.Trim(rigDB, "`")+"`"); err != nil {
+			return nil, true, err
+		}
+	}
+	res, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, true, err
+	}
+	defer res.Close()
+
+	for {
+		cols, err := res.Columns()
+		if err != nil {
+			return nil, true, err
+		}
+		if len(cols) > 0 {
+			set := [][]string{cols}
+			for res.Next() {
+				vals := make([]sql.NullString, len(cols))
+				ptrs := make([]any, len(cols))
+				for i := range vals {
+					ptrs[i] = &vals[i]
+				}
+				if err := res.Scan(ptrs...); err != nil {
+					return nil, true, err
+				}
+				row := make([]string, len(cols))
+				for i, v := range vals {
+					row[i] = v.String
+				}
+				set = append(set, row)
+			}
+			rows = set
+		}
+		if !res.NextResultSet() {
+			break
+		}
+	}
+	return rows, true, res.Err()
+}
+
+// leavesNoSessionState reports whether the connection that ran query against
+// rigDB can go back to the pool: only a single SELECT or SHOW with no
+// database selected is known to leave the session as it found it.
+func leavesNoSessionState(rigDB, query string) bool {
+	if rigDB != "" {
+		return false
+	}
+	q := strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if strings.Contains(q, ";") || strings.Contains(strings.ToUpper(q), "DOLT_CHECKOUT") {
+		return false
+	}
+	fields := strings.Fields(q)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "SHOW":
+		return true
+	}
+	return false
+}
+
+// Query runs query against database (none if empty) and returns the last
+// result set as rows of strings, header first; a statement with no result
+// set returns no rows. It goes through the running server if there is one,
+// otherwise through the dolt CLI inside the database's directory. Used by
+// gt dolt sql -q, so the query is the caller's and runs as given.
+func Query(ctx context.Context, townRoot, database, query string) ([][]string, error) {
+	if rows, ok, err := serverQuery(ctx, townRoot, database, query); ok {
+		return rows, err
+	}
+
+	dir := DefaultConfig(townRoot).DataDir
+	if database != "" {
+		dir = RigDatabaseDir(townRoot, database)
+		if _, err := os.Stat(filepath.Join(dir, ".dolt")); err != nil {
+			return nil, fmt.Errorf("database %q not found in %s", database, DefaultConfig(townRoot).DataDir)
+		}
+	}
+	output, stderr, err := runDolt(ctx, dir, "sql", "-r", "csv", "-q", query)
+	if err != nil {
+		if len(stderr) > 0 {
+			return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stderr)))
+		}
+		return nil, err
+	}
+	r := csv.NewReader(bytes.NewReader(output))
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// closeServerPools closes every pooled server connection. Called when the
+// server stops so a restarted server gets fresh connections.
+func closeServerPools() {
+	sqlPoolsMu.Lock()
+	defer sqlPoolsMu.Unlock()
+	for town, db := range sqlPools {
+		_ = db.Close()
+		delete(sqlPools, town)
+	}
+}
@@ -0,0 +1,194 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestServerPool(t *testing.T) {
+	townRoot := t.TempDir()
+	defer SetProcessTable(proc.NewFakeTable(4242))()
+	defer closeServerPools()
+
+	if db, err := serverPool(townRoot); db != nil || err != nil {
+		t.Fatalf("serverPool without PID file = %v, %v; want nil", db, err)
+	}
+
+	pidFile := DefaultConfig(townRoot).PidFile
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pidFile, []byte("999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if db, _ := serverPool(townRoot); db != nil {
+		t.Fatal("serverPool with a dead PID returned a pool")
+	}
+
+	if err := os.WriteFile(pidFile, []byte("4242\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := serverPool(townRoot)
+	if err != nil || db == nil {
+		t.Fatalf("serverPool with a live PID = %v, %v; want a pool", db, err)
+	}
+	if again, _ := serverPool(townRoot); again != db {
+		t.Error("serverPool did not reuse the pool")
+	}
+
+	closeServerPools()
+	if again, _ := serverPool(townRoot); again == db {
+		t.Error("serverPool returned a closed pool")
+	}
+}
+
+// branchConnector opens fake server connections that track the branch a
+// session has checked out, like a Dolt server does per connection.
+type branchConnector struct{ opened atomic.Int32 }
+
+func (c *branchConnector) Connect(context.Context) (driver.Conn, error) {
+	c.opened.Add(1)
+	return &branchConn{branch: "main"}, nil
+}
+
+func (c *branchConnector) Driver() driver.Driver { return nil }
+
+type branchConn struct{ branch string }
+
+func (c *branchConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *branchConn) Close() error                        { return nil }
+func (c *branchConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *branchConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+// QueryContext runs each statement of a script and returns the last one's
+// result: CALL DOLT_CHECKOUT('b') switches branch, anything else reports it.
+func (c *branchConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	for _, stmt := range strings.Split(query, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if b, ok := strings.CutPrefix(stmt, "CALL DOLT_CHECKOUT('"); ok {
+			c.branch = strings.TrimSuffix(b, "')")
+		}
+	}
+	return &branchRows{branch: c.branch}, nil
+}
+
+type branchRows struct {
+	branch string
+	done   bool
+}
+
+func (r *branchRows) Columns() []string { return []string{"branch"} }
+func (r *branchRows) Close() error      { return nil }
+func (r *branchRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.branch
+	return nil
+}
+
+func TestServerQuery_ScriptDoesNotLeakSessionState(t *testing.T) {
+	townRoot := t.TempDir()
+	defer SetProcessTable(proc.NewFakeTable(4242))()
+	defer closeServerPools()
+
+	pidFile := DefaultConfig(townRoot).PidFile
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pidFile, []byte("4242\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	connector := &branchConnector{}
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(1)
+	sqlPoolsMu.Lock()
+	sqlPools[townRoot] = db
+	sqlPoolsMu.Unlock()
+
+	ctx := context.Background()
+	query := func(q string) string {
+		t.Helper()
+		rows, ok, err := serverQuery(ctx, townRoot, "", q)
+		if !ok || err != nil || len(rows) != 2 {
+			t.Fatalf("serverQuery(%q) = %v, ok %v, %v", q, rows, ok, err)
+		}
+		return rows[1][0]
+	}
+
+	if got := query("SELECT active_branch()"); got != "main" {
+		t.Fatalf("branch = %q, want main", got)
+	}
+	if got := query("SELECT active_branch()"); got != "main" || connector.opened.Load() != 1 {
+		t.Errorf("plain SELECT did not reuse its connection (opened %d)", connector.opened.Load())
+	}
+
+	if err := doltSQLScript(townRoot, "CALL DOLT_CHECKOUT('polecat-toast'); SELECT 1"); err != nil {
+		t.Fatalf("doltSQLScript: %v", err)
+	}
+	if got := query("SELECT active_branch()"); got != "main" {
+		t.Errorf("query after a checkout script saw branch %q, want main", got)
+	}
+}
+
+func TestDoltQueryCSV_EmbeddedUsesCLI(t *testing.T) {
+	fake := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return []byte("n\n3\n"), nil, nil
+	}}
+	defer SetRunner(fake)()
+
+	townRoot := t.TempDir()
+	if _, ok, err := serverQuery(context.Background(), townRoot, "gastown", "SELECT 1"); ok || err != nil {
+		t.Fatalf("serverQuery without a server = ok %v, %v; want CLI fallback", ok, err)
+	}
+	rows, err := doltQueryCSV(townRoot, "gastown", "SELECT COUNT(*) AS n FROM issues")
+	if err != nil {
+		t.Fatalf("doltQueryCSV: %v", err)
+	}
+	if len(rows) != 2 || rows[1][0] != "3" || len(fake.Calls()) != 1 {
+		t.Errorf("rows = %v after %d dolt calls, want one CLI query", rows, len(fake.Calls()))
+	}
+}
+
+func TestQuery_EmbeddedRunsInDatabaseDir(t *testing.T) {
+	fake := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		return []byte("id,title\ngt-1,First\n"), nil, nil
+	}}
+	defer SetRunner(fake)()
+
+	townRoot := t.TempDir()
+	dbPath := setupDoltDB(t, DefaultConfig(townRoot).DataDir, "gastown")
+
+	rows, err := Query(context.Background(), townRoot, "gastown", "SELECT id, title FROM issues")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(rows) != 2 || rows[1][0] != "gt-1" {
+		t.Errorf("rows = %v, want header and one row", rows)
+	}
+	calls := fake.Calls()
+	if len(calls) != 1 || calls[0].Dir != dbPath {
+		t.Errorf("dolt calls = %+v, want one in %s", calls, dbPath)
+	}
+
+	if _, err := Query(context.Background(), townRoot, "missing", "SELECT 1"); err == nil {
+		t.Error("Query on a missing database succeeded")
+	}
+	if len(fake.Calls()) != 1 {
+		t.Error("Query ran dolt for a missing database")
+	}
+}
@@ -0,0 +1,135 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultStartupTimeout is how long Start waits for a new server to
+	// answer queries before giving up.
+	DefaultStartupTimeout = 30 * time.Second
+
+	startupPollInterval = 250 * time.Millisecond
+
+	// startupLogLines is how much of dolt.log a StartupError carries.
+	startupLogLines = 20
+)
+
+// Startup phases a StartupError can fail in.
+const (
+	StartupPhaseExited = "exited" // the server process exited
+	StartupPhasePort   = "port"   // nothing accepted connections on the port
+	StartupPhaseQuery  = "query"  // the port was open but SELECT 1 failed
+)
+
+// StartupError reports a server that did not become ready, with the end of
+// its log so the cause is visible without opening dolt.log.
+type StartupError struct {
+	Phase   string
+	Port    int
+	Elapsed time.Duration
+	Err     error
+	LogFile string
+	LogTail []string
+}
+
+func (e *StartupError) Error() string {
+	var b strings.Builder
+	switch e.Phase {
+	case StartupPhaseExited:
+		fmt.Fprintf(&b, "Dolt server exited during startup after %v: %v", e.Elapsed.Round(time.Millisecond), e.Err)
+	case StartupPhasePort:
+		fmt.Fprintf(&b, "Dolt server not listening on port %d after %v: %v", e.Port, e.Elapsed.Round(time.Millisecond), e.Err)
+	default:
+		fmt.Fprintf(&b, "Dolt server not answering queries after %v: %v", e.Elapsed.Round(time.Millisecond), e.Err)
+	}
+	if len(e.LogTail) > 0 {
+		fmt.Fprintf(&b, "\nLast lines of %s:", e.LogFile)
+		for _, line := range e.LogTail {
+			b.WriteString("\n  " + line)
+		}
+	} else {
+		b.WriteString(" (check logs with 'gt dolt logs')")
+	}
+	return b.String()
+}
+
+func (e *StartupError) Unwrap() error { return e.Err }
+
+// waitForReady polls until the server answers: the port accepts a
+// connection and then query succeeds. It fails as soon as exited delivers,
+// or with the last failure once timeout has passed. It returns the phase
+// that failed.
+func waitForReady(timeout time.Duration, exited <-chan error, dial, query func() error) (string, time.Duration, error) {
+	start := clk.Now()
+	for {
+		select {
+		case err := <-exited:
+			if err == nil {
+				err = errors.New("exit status 0")
+			}
+			return StartupPhaseExited, clk.Since(start), err
+		default:
+		}
+
+		phase, err := StartupPhasePort, dial()
+		if err == nil {
+			phase, err = StartupPhaseQuery, query()
+			if err == nil {
+				return "", clk.Since(start), nil
+			}
+		}
+		if clk.Since(start) >= timeout {
+			return phase, clk.Since(start), err
+		}
+		clk.Sleep(startupPollInterval)
+	}
+}
+
+// dialPort checks that something accepts connections on the local port.
+func dialPort(port int) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// logTail returns up to n trailing non-empty lines of the file at path.
+// Only the last 64KB is read, so a large log costs nothing extra.
+func logTail(path string, n int) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	const maxRead = 64 * 1024
+	if info, err := f.Stat(); err == nil && info.Size() > maxRead {
+		if _, err := f.Seek(-maxRead, io.SeekEnd); err != nil {
+			return nil
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimRight(line, "\r "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
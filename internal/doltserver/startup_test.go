@@ -0,0 +1,89 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestWaitForReady(t *testing.T) {
+	defer SetClock(clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)))()
+	refused := errors.New("connection refused")
+
+	t.Run("ready once the port opens", func(t *testing.T) {
+		dials := 0
+		phase, _, err := waitForReady(time.Minute, nil,
+			func() error {
+				if dials++; dials < 3 {
+					return refused
+				}
+				return nil
+			},
+			func() error { return nil })
+		if err != nil || phase != "" || dials != 3 {
+			t.Errorf("got phase %q, err %v after %d dials; want ready on the third", phase, err, dials)
+		}
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		exited := make(chan error, 1)
+		exited <- errors.New("exit status 1")
+		phase, _, err := waitForReady(time.Minute, exited,
+			func() error { return refused }, func() error { return nil })
+		if phase != StartupPhaseExited || err == nil || err.Error() != "exit status 1" {
+			t.Errorf("got phase %q, err %v; want exited", phase, err)
+		}
+	})
+
+	t.Run("times out on queries", func(t *testing.T) {
+		phase, elapsed, err := waitForReady(5*time.Second, nil,
+			func() error { return nil }, func() error { return errors.New("database locked") })
+		if phase != StartupPhaseQuery || err == nil || elapsed < 5*time.Second {
+			t.Errorf("got phase %q, err %v after %v; want query failure after 5s", phase, err, elapsed)
+		}
+	})
+}
+
+func TestLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dolt.log")
+	var b strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&b, "line %d\n\n", i)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tail := logTail(path, 3)
+	if want := []string{"line 28", "line 29", "line 30"}; strings.Join(tail, ",") != strings.Join(want, ",") {
+		t.Errorf("logTail = %q, want %q", tail, want)
+	}
+	if got := logTail(filepath.Join(t.TempDir(), "missing.log"), 3); got != nil {
+		t.Errorf("logTail of missing file = %q, want nil", got)
+	}
+}
+
+func TestStartupErrorMessage(t *testing.T) {
+	err := &StartupError{
+		Phase:   StartupPhaseExited,
+		Elapsed: 300 * time.Millisecond,
+		Err:     errors.New("exit status 1"),
+		LogFile: "/town/daemon/dolt.log",
+		LogTail: []string{"error: port 3307 already in use"},
+	}
+	msg := err.Error()
+	for _, want := range []string{"exited during startup after 300ms", "/town/daemon/dolt.log", "port 3307 already in use"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+	if !errors.Is(err, err.Err) {
+		t.Error("StartupError should unwrap to its cause")
+	}
+}
@@ -0,0 +1,132 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// The state file and PID file are written by several gt processes at once
+// (gt dolt start and stop, the daemon's watchdog, drains), so every write
+// goes through a read-modify-write under daemon/dolt-state.lock. This is a
+// separate lock from dolt.lock, which Start holds for the whole startup.
+//
+// stateMu serializes goroutines in this process: flock alone can't be relied
+// on to exclude a second lock taken by the same process.
+var stateMu sync.Mutex
+
+// ErrStateConflict is returned by SaveState when the state file was
+// rewritten since the state being saved was loaded.
+var ErrStateConflict = errors.New("dolt state changed since it was loaded")
+
+// stateLockFile returns the path of the lock guarding the state and PID files.
+func stateLockFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-state.lock")
+}
+
+// lockState takes the state lock, creating the daemon directory if needed.
+// Caller must call the returned unlock function.
+func lockState(townRoot string) (func(), error) {
+	lockPath := stateLockFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, err
+	}
+	stateMu.Lock()
+	fl := flock.New(lockPath)
+	if err := fl.Lock(); err != nil {
+		stateMu.Unlock()
+		return nil, fmt.Errorf("acquiring dolt state lock: %w", err)
+	}
+	return func() {
+		_ = fl.Unlock()
+		stateMu.Unlock()
+	}, nil
+}
+
+// readState reads the state file as written, or an empty state if there
+// is none.
+func readState(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StateFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// UpdateState applies fn to the current state and saves the result, holding
+// the state lock throughout so concurrent updates from other processes are
+// applied one after another instead of overwriting each other. The saved
+// state's Version is one more than the version fn was given. If fn returns
+// an error nothing is written.
+func UpdateState(townRoot string, fn func(*State) error) (*State, error) {
+	unlock, err := lockState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := readState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	version := state.Version
+	if err := fn(state); err != nil {
+		return nil, err
+	}
+	state.Version = version + 1
+	if err := util.AtomicWriteJSON(StateFile(townRoot), state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// readPIDFile returns the PID recorded in the PID file, or 0 if there is
+// none or it can't be parsed.
+func readPIDFile(pidFile string) int {
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
+
+// writePIDFile records pid in the PID file under the state lock.
+func writePIDFile(townRoot, pidFile string, pid int) error {
+	unlock, err := lockState(townRoot)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return util.AtomicWriteFile(pidFile, []byte(strconv.Itoa(pid)), 0644)
+}
+
+// removePIDFile removes the PID file under the state lock. With a nonzero
+// stale PID it is only removed if it still records that PID, so a file
+// another process just rewrote for a new server survives.
+func removePIDFile(townRoot, pidFile string, stale int) {
+	unlock, err := lockState(townRoot)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	if stale != 0 && readPIDFile(pidFile) != stale {
+		return
+	}
+	_ = os.Remove(pidFile)
+}
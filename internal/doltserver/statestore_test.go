@@ -0,0 +1,101 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestUpdateStateConcurrent(t *testing.T) {
+	townRoot := t.TempDir()
+	const writers = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := UpdateState(townRoot, func(s *State) error {
+				s.Databases = append(s.Databases, "db")
+				return nil
+			}); err != nil {
+				t.Errorf("UpdateState: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if len(state.Databases) != writers || state.Version != writers {
+		t.Errorf("got %d databases at version %d, want %d of each (lost updates)", len(state.Databases), state.Version, writers)
+	}
+}
+
+func TestUpdateStateErrorWritesNothing(t *testing.T) {
+	townRoot := t.TempDir()
+	boom := errors.New("boom")
+	if _, err := UpdateState(townRoot, func(s *State) error {
+		s.Running = true
+		return boom
+	}); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if _, err := os.Stat(StateFile(townRoot)); !os.IsNotExist(err) {
+		t.Errorf("state file written despite error: %v", err)
+	}
+}
+
+func TestSaveStateConflict(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := SaveState(townRoot, &State{Port: 3307}); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	stale, _ := LoadState(townRoot)
+	if err := SetDraining(townRoot, true); err != nil {
+		t.Fatalf("SetDraining: %v", err)
+	}
+
+	stale.Running = true
+	if err := SaveState(townRoot, stale); !errors.Is(err, ErrStateConflict) {
+		t.Fatalf("SaveState with stale version = %v, want ErrStateConflict", err)
+	}
+	if !IsDraining(townRoot) {
+		t.Error("stale save overwrote the drain flag")
+	}
+
+	fresh, _ := LoadState(townRoot)
+	fresh.Running = true
+	if err := SaveState(townRoot, fresh); err != nil {
+		t.Fatalf("SaveState with current version: %v", err)
+	}
+	if fresh.Version != 3 {
+		t.Errorf("Version = %d after three writes, want 3", fresh.Version)
+	}
+	// A zero-version state replaces the file outright.
+	if err := SaveState(townRoot, &State{}); err != nil {
+		t.Fatalf("SaveState replacement: %v", err)
+	}
+}
+
+func TestRemovePIDFileKeepsRewrittenFile(t *testing.T) {
+	townRoot := t.TempDir()
+	pidFile := filepath.Join(townRoot, "daemon", "dolt.pid")
+	if err := writePIDFile(townRoot, pidFile, 4242); err != nil {
+		t.Fatalf("writePIDFile: %v", err)
+	}
+
+	// Another process replaced the stale PID with its new server's.
+	removePIDFile(townRoot, pidFile, 1111)
+	if got := readPIDFile(pidFile); got != 4242 {
+		t.Fatalf("PID file = %d, want 4242 kept", got)
+	}
+	removePIDFile(townRoot, pidFile, 4242)
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Errorf("PID file not removed: %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // SyncOptions controls the behavior of SyncDatabases.
@@ -44,7 +46,7 @@ type SyncResult struct {
 func HasRemote(dbDir string) (string, error) {
 	cmd := exec.Command("dolt", "remote", "-v")
 	cmd.Dir = dbDir
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		return "", fmt.Errorf("dolt remote -v: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
@@ -72,14 +74,14 @@ func CommitWorkingSet(dbDir string) error {
 	// Stage all changes
 	addCmd := exec.Command("dolt", "add", ".")
 	addCmd.Dir = dbDir
-	if output, err := addCmd.CombinedOutput(); err != nil {
+	if output, err := proc.CombinedOutput(addCmd); err != nil {
 		return fmt.Errorf("dolt add: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
 
 	// Commit (may fail with "nothing to commit" which is fine)
 	commitCmd := exec.Command("dolt", "commit", "-m", "gt dolt sync: auto-commit working changes")
 	commitCmd.Dir = dbDir
-	output, err := commitCmd.CombinedOutput()
+	output, err := proc.CombinedOutput(commitCmd)
 	if err != nil {
 		msg := strings.TrimSpace(string(output))
 		// "nothing to commit" or "no changes added" is success — no changes to push
@@ -103,7 +105,7 @@ func PushDatabase(dbDir string, force bool) error {
 
 	cmd := exec.Command("dolt", args...)
 	cmd.Dir = dbDir
-	output, err := cmd.CombinedOutput()
+	output, err := proc.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("dolt push: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
@@ -0,0 +1,247 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/chaos"
+)
+
+// JSONL sync modes understood by bd (the sync.mode config key).
+const (
+	SyncModeGitPortable       = "git-portable"
+	SyncModeRealtime          = "realtime"
+	SyncModeDoltNative        = "dolt-native"
+	SyncModeBeltAndSuspenders = "belt-and-suspenders"
+)
+
+// SyncModes lists all valid sync modes.
+var SyncModes = []string{SyncModeGitPortable, SyncModeRealtime, SyncModeDoltNative, SyncModeBeltAndSuspenders}
+
+// syncModeKey is the key bd uses for the sync mode in both the database
+// config table and .beads/config.yaml.
+const syncModeKey = "sync.mode"
+
+// ValidateSyncMode returns an error if mode is not a known sync mode.
+func ValidateSyncMode(mode string) error {
+	for _, m := range SyncModes {
+		if mode == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sync mode %q (valid: %s)", mode, strings.Join(SyncModes, ", "))
+}
+
+// GetSyncMode reads the sync mode for a rig database from its config table
+// via the Dolt server. Returns "" if the mode has never been set.
+func GetSyncMode(townRoot, rigDB string) (string, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB,
+		fmt.Sprintf("SELECT `value` AS sync_mode FROM config WHERE `key` = '%s'", syncModeKey))
+	if err != nil {
+		return "", fmt.Errorf("reading sync mode for %s: %w", rigDB, err)
+	}
+	for i, row := range rows {
+		if len(row) > 0 && row[0] == "sync_mode" && i+1 < len(rows) && len(rows[i+1]) > 0 {
+			return strings.TrimSpace(rows[i+1][0]), nil
+		}
+	}
+	return "", nil
+}
+
+// SetSyncMode writes the sync mode for a rig database through the Dolt server
+// and mirrors it into the rig's .beads/config.yaml so the two stay in step.
+func SetSyncMode(townRoot, rigDB, mode string) error {
+	if err := ValidateSyncMode(mode); err != nil {
+		return err
+	}
+	query := fmt.Sprintf("REPLACE INTO config (`key`, `value`) VALUES ('%s', '%s')", syncModeKey, mode)
+	if err := doltSQLWithRetry(townRoot, rigDB, query); err != nil {
+		return fmt.Errorf("setting sync mode for %s: %w", rigDB, err)
+	}
+
+	if beadsDir := FindRigBeadsDir(townRoot, rigDB); beadsDir != "" {
+		if err := WriteConfigSyncMode(beadsDir, mode); err != nil {
+			return fmt.Errorf("updating config.yaml for %s: %w", rigDB, err)
+		}
+	}
+	return nil
+}
+
+// SyncModeStatus compares a rig's database sync mode with its config.yaml.
+type SyncModeStatus struct {
+	// Database is the rig database name.
+	Database string
+
+	// BeadsDir is the rig's .beads directory (empty if not found).
+	BeadsDir string
+
+	// DBMode is the mode stored in the database config table.
+	DBMode string
+
+	// ConfigMode is the mode in .beads/config.yaml.
+	ConfigMode string
+
+	// Error is non-nil if either side could not be read.
+	Error error
+}
+
+// Mismatch reports whether config.yaml and the database disagree.
+// A mode set on only one side counts as a mismatch.
+func (s SyncModeStatus) Mismatch() bool {
+	return s.Error == nil && s.DBMode != s.ConfigMode
+}
+
+// GetSyncModeStatus reads both sides of the sync mode for one database.
+func GetSyncModeStatus(townRoot, rigDB string) SyncModeStatus {
+	status := SyncModeStatus{Database: rigDB, BeadsDir: FindRigBeadsDir(townRoot, rigDB)}
+	status.DBMode, status.Error = GetSyncMode(townRoot, rigDB)
+	if status.Error != nil || status.BeadsDir == "" {
+		return status
+	}
+	status.ConfigMode, status.Error = ReadConfigSyncMode(status.BeadsDir)
+	return status
+}
+
+// ListSyncModeStatuses returns the sync mode status of every database on
+// the server.
+func ListSyncModeStatuses(townRoot string) ([]SyncModeStatus, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]SyncModeStatus, 0, len(databases))
+	for _, db := range databases {
+		statuses = append(statuses, GetSyncModeStatus(townRoot, db))
+	}
+	return statuses, nil
+}
+
+// ReadConfigSyncMode reads sync.mode from a .beads/config.yaml.
+// Returns "" if the file or key is absent.
+func ReadConfigSyncMode(beadsDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(beadsDir, "config.yaml")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return parseConfigSyncMode(data), nil
+}
+
+// WriteConfigSyncMode sets sync.mode in a .beads/config.yaml, preserving
+// the rest of the file.
+func WriteConfigSyncMode(beadsDir, mode string) error {
+	path := filepath.Join(beadsDir, "config.yaml")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(path, setConfigSyncMode(data, mode), 0644) //nolint:gosec // G306: config.yaml is git-tracked
+}
+
+var (
+	flatSyncModeRe   = regexp.MustCompile(`^sync\.mode:\s*(.*)$`)
+	syncBlockRe      = regexp.MustCompile(`^sync:\s*(#.*)?$`)
+	nestedSyncModeRe = regexp.MustCompile(`^(\s+)mode:\s*(.*)$`)
+)
+
+// parseConfigSyncMode extracts sync.mode from config.yaml content. Both the
+// flat form (sync.mode: x) and the nested form (sync:\n  mode: x) are
+// recognized.
+func parseConfigSyncMode(data []byte) string {
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		if m := flatSyncModeRe.FindStringSubmatch(lines[i]); m != nil {
+			return yamlScalar(m[1])
+		}
+		if !syncBlockRe.MatchString(lines[i]) {
+			continue
+		}
+		for j := i + 1; j < len(lines) && isIndentedOrBlank(lines[j]); j++ {
+			if m := nestedSyncModeRe.FindStringSubmatch(lines[j]); m != nil {
+				return yamlScalar(m[2])
+			}
+		}
+	}
+	return ""
+}
+
+// setConfigSyncMode returns config.yaml content with sync.mode set to mode.
+func setConfigSyncMode(data []byte, mode string) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		if flatSyncModeRe.MatchString(lines[i]) {
+			lines[i] = "sync.mode: " + mode
+			return []byte(strings.Join(lines, "\n"))
+		}
+		if !syncBlockRe.MatchString(lines[i]) {
+			continue
+		}
+		j := i + 1
+		for ; j < len(lines) && isIndentedOrBlank(lines[j]); j++ {
+			if m := nestedSyncModeRe.FindStringSubmatch(lines[j]); m != nil {
+				lines[j] = m[1] + "mode: " + mode
+				return []byte(strings.Join(lines, "\n"))
+			}
+		}
+		// sync: block without a mode key - insert one.
+		out := append([]string{}, lines[:i+1]...)
+		out = append(out, "  mode: "+mode)
+		out = append(out, lines[i+1:]...)
+		return []byte(strings.Join(out, "\n"))
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(&buf, "sync:\n  mode: %s\n", mode)
+	return buf.Bytes()
+}
+
+func isIndentedOrBlank(line string) bool {
+	return strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t'
+}
+
+// yamlScalar strips comments and quotes from a simple YAML scalar.
+func yamlScalar(s string) string {
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	return strings.Trim(s, `"'`)
+}
+
+// doltQueryCSV runs a query against a rig database and returns the CSV rows.
+func doltQueryCSV(townRoot, rigDB, query string) ([][]string, error) {
+	if err := chaos.Inject(chaos.DoltConn); err != nil {
+		return nil, err
+	}
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if rows, ok, err := serverQuery(ctx, townRoot, rigDB, query); ok {
+		return rows, err
+	}
+	output, stderr, err := runDolt(ctx, config.DataDir, "sql", "-r", "csv", "-q", fmt.Sprintf("USE %s; %s", rigDB, query))
+	if err != nil {
+		if len(stderr) > 0 {
+			return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(stderr)))
+		}
+		return nil, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(output))
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
@@ -0,0 +1,92 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateSyncMode(t *testing.T) {
+	for _, m := range SyncModes {
+		if err := ValidateSyncMode(m); err != nil {
+			t.Errorf("ValidateSyncMode(%q) = %v", m, err)
+		}
+	}
+	if err := ValidateSyncMode("sometimes"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestParseConfigSyncMode(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"absent", "prefix: gt\n", ""},
+		{"flat", "prefix: gt\nsync.mode: dolt-native\n", "dolt-native"},
+		{"flat quoted with comment", "sync.mode: \"realtime\" # fast\n", "realtime"},
+		{"nested", "sync:\n  branch: beads-sync\n  mode: git-portable\nprefix: gt\n", "git-portable"},
+		{"mode outside sync block", "sync:\n  branch: x\nother:\n  mode: realtime\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseConfigSyncMode([]byte(tt.yaml)); got != tt.want {
+				t.Errorf("parseConfigSyncMode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetConfigSyncMode(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"empty", "", "sync:\n  mode: dolt-native\n"},
+		{"append", "prefix: gt", "prefix: gt\nsync:\n  mode: dolt-native\n"},
+		{"replace flat", "sync.mode: realtime\nprefix: gt\n", "sync.mode: dolt-native\nprefix: gt\n"},
+		{"replace nested", "sync:\n    mode: realtime\n", "sync:\n    mode: dolt-native\n"},
+		{"insert into block", "sync:\n  branch: b\nprefix: gt\n", "sync:\n  mode: dolt-native\n  branch: b\nprefix: gt\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(setConfigSyncMode([]byte(tt.yaml), "dolt-native"))
+			if got != tt.want {
+				t.Errorf("setConfigSyncMode =\n%q\nwant\n%q", got, tt.want)
+			}
+			if mode := parseConfigSyncMode([]byte(got)); mode != "dolt-native" {
+				t.Errorf("round-trip mode = %q", mode)
+			}
+		})
+	}
+}
+
+func TestWriteConfigSyncMode_PreservesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("prefix: gt\nissue-prefix: gt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteConfigSyncMode(dir, SyncModeRealtime); err != nil {
+		t.Fatalf("WriteConfigSyncMode: %v", err)
+	}
+	mode, err := ReadConfigSyncMode(dir)
+	if err != nil || mode != SyncModeRealtime {
+		t.Errorf("ReadConfigSyncMode = %q, %v", mode, err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data[:len("prefix: gt\nissue-prefix: gt\n")]) != "prefix: gt\nissue-prefix: gt\n" {
+		t.Errorf("existing content not preserved: %q", data)
+	}
+}
+
+func TestSyncModeStatus_Mismatch(t *testing.T) {
+	if (SyncModeStatus{DBMode: "realtime", ConfigMode: "realtime"}).Mismatch() {
+		t.Error("equal modes should not mismatch")
+	}
+	if !(SyncModeStatus{DBMode: "dolt-native"}).Mismatch() {
+		t.Error("mode set on one side only should mismatch")
+	}
+}
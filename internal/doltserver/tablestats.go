@@ -0,0 +1,296 @@
+package doltserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// TableStat is the size of one table in a rig database.
+type TableStat struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+
+	// Rows is the exact row count.
+	Rows int64 `json:"rows"`
+
+	// Bytes is the table's data length as reported by information_schema.
+	// Dolt shares chunk storage between tables, so this is an estimate.
+	Bytes int64 `json:"bytes"`
+}
+
+// TableStatsSnapshot is one database's table stats at a point in time.
+type TableStatsSnapshot struct {
+	Time     time.Time   `json:"time"`
+	Database string      `json:"database"`
+	Tables   []TableStat `json:"tables"`
+}
+
+// GetTableStats returns row counts and sizes for every table in a rig database.
+func GetTableStats(townRoot, rigDB string) ([]TableStat, error) {
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"SELECT table_name AS name, COALESCE(data_length, 0) AS bytes FROM information_schema.tables "+
+			"WHERE table_schema = '%s' AND table_type = 'BASE TABLE' ORDER BY table_name", rigDB))
+	if err != nil {
+		return nil, fmt.Errorf("listing tables in %s: %w", rigDB, err)
+	}
+	recs := csvRecords(rows)
+	if len(recs) == 0 {
+		return nil, nil
+	}
+
+	stats := make([]TableStat, 0, len(recs))
+	counts := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		bytes, _ := strconv.ParseInt(rec["bytes"], 10, 64)
+		stats = append(stats, TableStat{Database: rigDB, Table: rec["name"], Bytes: bytes})
+		counts = append(counts, fmt.Sprintf("SELECT '%s' AS name, COUNT(*) AS n FROM `%s`", rec["name"], rec["name"]))
+	}
+
+	// information_schema row counts are estimates; count exactly in one round-trip.
+	rows, err = doltQueryCSV(townRoot, rigDB, strings.Join(counts, " UNION ALL "))
+	if err != nil {
+		return nil, fmt.Errorf("counting rows in %s: %w", rigDB, err)
+	}
+	n := make(map[string]int64, len(recs))
+	for _, rec := range csvRecords(rows) {
+		n[rec["name"]], _ = strconv.ParseInt(rec["n"], 10, 64)
+	}
+	for i := range stats {
+		stats[i].Rows = n[stats[i].Table]
+	}
+	return stats, nil
+}
+
+// TableStatsFile returns the path of the table stats history file.
+func TableStatsFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-table-stats.jsonl")
+}
+
+// RecordTableStats appends a snapshot to the history file.
+func RecordTableStats(townRoot string, snap TableStatsSnapshot) error {
+	path := TableStatsFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: stats history is not sensitive
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadTableStatsHistory reads all snapshots from the history file, oldest
+// first. A missing file yields no snapshots; unparseable lines are skipped.
+func LoadTableStatsHistory(townRoot string) ([]TableStatsSnapshot, error) {
+	f, err := os.Open(TableStatsFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []TableStatsSnapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var snap TableStatsSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err == nil {
+			history = append(history, snap)
+		}
+	}
+	return history, scanner.Err()
+}
+
+// BaselineSnapshot returns the most recent snapshot of rigDB taken at or
+// before cutoff, falling back to the oldest snapshot after it. Returns nil if
+// the database has no history.
+func BaselineSnapshot(history []TableStatsSnapshot, rigDB string, cutoff time.Time) *TableStatsSnapshot {
+	var baseline *TableStatsSnapshot
+	for i := range history {
+		snap := &history[i]
+		if snap.Database != rigDB {
+			continue
+		}
+		if !snap.Time.After(cutoff) {
+			baseline = snap
+			continue
+		}
+		if baseline == nil {
+			return snap
+		}
+		break
+	}
+	return baseline
+}
+
+// Find returns the stats for table in the snapshot, if present.
+func (s *TableStatsSnapshot) Find(table string) (TableStat, bool) {
+	if s == nil {
+		return TableStat{}, false
+	}
+	for _, t := range s.Tables {
+		if t.Table == table {
+			return t, true
+		}
+	}
+	return TableStat{}, false
+}
+
+// TableAlert is a table that exceeded a configured limit.
+type TableAlert struct {
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	Message  string `json:"message"`
+}
+
+// CheckTableAlerts returns the alerts triggered by stats.
+func CheckTableAlerts(alerts []config.DoltTableAlert, stats []TableStat) []TableAlert {
+	var hits []TableAlert
+	for _, a := range alerts {
+		for _, s := range stats {
+			if s.Table != a.Table || !alertAppliesTo(a, s.Database) {
+				continue
+			}
+			if a.MaxRows > 0 && s.Rows > a.MaxRows {
+				hits = append(hits, TableAlert{
+					Database: s.Database,
+					Table:    s.Table,
+					Message:  fmt.Sprintf("%d rows exceeds limit of %d", s.Rows, a.MaxRows),
+				})
+			}
+			if a.MaxBytes > 0 && s.Bytes > a.MaxBytes {
+				hits = append(hits, TableAlert{
+					Database: s.Database,
+					Table:    s.Table,
+					Message:  fmt.Sprintf("%s exceeds limit of %s", formatBytes(s.Bytes), formatBytes(a.MaxBytes)),
+				})
+			}
+		}
+	}
+	return hits
+}
+
+func alertAppliesTo(a config.DoltTableAlert, rigDB string) bool {
+	if len(a.Databases) == 0 {
+		return true
+	}
+	for _, db := range a.Databases {
+		if db == rigDB {
+			return true
+		}
+	}
+	return false
+}
+
+// Prune targets: tables with rows that are known safe to delete after a
+// retention period.
+const (
+	// PruneWisps deletes closed wisps (ephemeral issues) from the issues table.
+	PruneWisps = "wisps"
+
+	// PruneEvents deletes old audit entries from the events table.
+	PruneEvents = "events"
+)
+
+// Default retention periods for prune targets.
+const (
+	DefaultClosedWispRetentionDays = 7
+	DefaultEventRetentionDays      = 30
+)
+
+// PruneTargets lists the known-safe prune targets.
+var PruneTargets = []string{PruneWisps, PruneEvents}
+
+// RetentionDays returns the retention period for a prune target from cfg,
+// or the default when unset.
+func RetentionDays(cfg *config.DoltStatsConfig, target string) int {
+	var days int
+	if cfg != nil && cfg.Retention != nil {
+		switch target {
+		case PruneWisps:
+			days = cfg.Retention.ClosedWispDays
+		case PruneEvents:
+			days = cfg.Retention.EventDays
+		}
+	}
+	if days > 0 {
+		return days
+	}
+	if target == PruneWisps {
+		return DefaultClosedWispRetentionDays
+	}
+	return DefaultEventRetentionDays
+}
+
+// PruneResult describes rows pruned (or prunable) for one target.
+type PruneResult struct {
+	Database  string `json:"database"`
+	Target    string `json:"target"`
+	Days      int    `json:"retention_days"`
+	Rows      int64  `json:"rows"`
+	Committed bool   `json:"committed,omitempty"`
+}
+
+// pruneCondition returns the table and WHERE clause selecting rows of a
+// prune target older than days.
+func pruneCondition(target string, days int) (string, string, error) {
+	cutoff := fmt.Sprintf("DATE_SUB(NOW(), INTERVAL %d DAY)", days)
+	switch target {
+	case PruneWisps:
+		return "issues", fmt.Sprintf("ephemeral = 1 AND status = 'closed' AND closed_at < %s", cutoff), nil
+	case PruneEvents:
+		return "events", fmt.Sprintf("created_at < %s", cutoff), nil
+	default:
+		return "", "", fmt.Errorf("unknown prune target %q (want %s)", target, strings.Join(PruneTargets, " or "))
+	}
+}
+
+// PruneTable deletes rows of a prune target older than days from a rig
+// database and commits the deletion. With dryRun, only counts the rows.
+// Rows referencing pruned wisps (labels, dependencies, events) are removed
+// by the beads schema's ON DELETE CASCADE foreign keys.
+func PruneTable(townRoot, rigDB, target string, days int, dryRun bool) (*PruneResult, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("retention must be at least 1 day, got %d", days)
+	}
+	table, where, err := pruneCondition(target, days)
+	if err != nil {
+		return nil, err
+	}
+	result := &PruneResult{Database: rigDB, Target: target, Days: days}
+
+	rows, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf("SELECT COUNT(*) AS n FROM `%s` WHERE %s", table, where))
+	if err != nil {
+		return nil, fmt.Errorf("counting %s in %s: %w", target, rigDB, err)
+	}
+	if recs := csvRecords(rows); len(recs) > 0 {
+		result.Rows, _ = strconv.ParseInt(recs[0]["n"], 10, 64)
+	}
+	if dryRun || result.Rows == 0 {
+		return result, nil
+	}
+
+	msg := fmt.Sprintf("gt dolt prune: %d %s older than %d days", result.Rows, target, days)
+	if _, err := doltQueryCSV(townRoot, rigDB, fmt.Sprintf(
+		"DELETE FROM `%s` WHERE %s; CALL DOLT_COMMIT('-Am', '%s')", table, where, msg)); err != nil {
+		return nil, fmt.Errorf("pruning %s in %s: %w", target, rigDB, err)
+	}
+	result.Committed = true
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestTableStatsHistoryRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	history, err := LoadTableStatsHistory(townRoot)
+	if err != nil || len(history) != 0 {
+		t.Fatalf("LoadTableStatsHistory(empty) = %v, %v; want none", history, err)
+	}
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, rows := range []int64{10, 25} {
+		snap := TableStatsSnapshot{
+			Time:     t0.Add(time.Duration(i) * time.Hour),
+			Database: "gastown",
+			Tables:   []TableStat{{Database: "gastown", Table: "events", Rows: rows}},
+		}
+		if err := RecordTableStats(townRoot, snap); err != nil {
+			t.Fatalf("RecordTableStats: %v", err)
+		}
+	}
+
+	history, err = LoadTableStatsHistory(townRoot)
+	if err != nil {
+		t.Fatalf("LoadTableStatsHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(history))
+	}
+	if got, _ := history[1].Find("events"); got.Rows != 25 {
+		t.Errorf("second snapshot events rows = %d, want 25", got.Rows)
+	}
+}
+
+func TestBaselineSnapshot(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []TableStatsSnapshot{
+		{Time: t0, Database: "gastown"},
+		{Time: t0.Add(time.Hour), Database: "beads"},
+		{Time: t0.Add(2 * time.Hour), Database: "gastown"},
+		{Time: t0.Add(4 * time.Hour), Database: "gastown"},
+	}
+
+	tests := []struct {
+		name   string
+		db     string
+		cutoff time.Time
+		want   time.Time // zero for nil
+	}{
+		{"latest at or before cutoff", "gastown", t0.Add(3 * time.Hour), t0.Add(2 * time.Hour)},
+		{"exact match", "gastown", t0.Add(2 * time.Hour), t0.Add(2 * time.Hour)},
+		{"falls back to oldest after cutoff", "gastown", t0.Add(-time.Hour), t0},
+		{"other database", "beads", t0.Add(5 * time.Hour), t0.Add(time.Hour)},
+		{"no history", "hq", t0.Add(5 * time.Hour), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BaselineSnapshot(history, tt.db, tt.cutoff)
+			switch {
+			case tt.want.IsZero() && got != nil:
+				t.Errorf("BaselineSnapshot() = %v, want nil", got.Time)
+			case !tt.want.IsZero() && (got == nil || !got.Time.Equal(tt.want)):
+				t.Errorf("BaselineSnapshot() = %+v, want snapshot at %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTableAlerts(t *testing.T) {
+	stats := []TableStat{
+		{Database: "gastown", Table: "events", Rows: 2_000_000, Bytes: 1 << 30},
+		{Database: "beads", Table: "events", Rows: 500},
+		{Database: "gastown", Table: "issues", Rows: 5_000},
+	}
+	alerts := []config.DoltTableAlert{
+		{Table: "events", MaxRows: 1_000_000},
+		{Table: "events", MaxBytes: 1 << 20, Databases: []string{"gastown"}},
+		{Table: "issues", MaxRows: 10_000},
+	}
+
+	hits := CheckTableAlerts(alerts, stats)
+	if len(hits) != 2 {
+		t.Fatalf("got %d alerts, want 2: %+v", len(hits), hits)
+	}
+	for _, h := range hits {
+		if h.Database != "gastown" || h.Table != "events" {
+			t.Errorf("unexpected alert %+v", h)
+		}
+	}
+	if !strings.Contains(hits[0].Message, "2000000 rows") {
+		t.Errorf("row alert message = %q", hits[0].Message)
+	}
+
+	if hits := CheckTableAlerts(nil, stats); len(hits) != 0 {
+		t.Errorf("CheckTableAlerts(nil) = %+v, want none", hits)
+	}
+}
+
+func TestRetentionDays(t *testing.T) {
+	if got := RetentionDays(nil, PruneWisps); got != DefaultClosedWispRetentionDays {
+		t.Errorf("default wisp retention = %d, want %d", got, DefaultClosedWispRetentionDays)
+	}
+	if got := RetentionDays(nil, PruneEvents); got != DefaultEventRetentionDays {
+		t.Errorf("default event retention = %d, want %d", got, DefaultEventRetentionDays)
+	}
+
+	cfg := &config.DoltStatsConfig{Retention: &config.DoltRetentionConfig{EventDays: 90}}
+	if got := RetentionDays(cfg, PruneEvents); got != 90 {
+		t.Errorf("configured event retention = %d, want 90", got)
+	}
+	if got := RetentionDays(cfg, PruneWisps); got != DefaultClosedWispRetentionDays {
+		t.Errorf("unset wisp retention = %d, want default", got)
+	}
+}
+
+func TestPruneCondition(t *testing.T) {
+	table, where, err := pruneCondition(PruneWisps, 7)
+	if err != nil || table != "issues" {
+		t.Fatalf("pruneCondition(wisps) = %q, %v", table, err)
+	}
+	for _, want := range []string{"ephemeral = 1", "status = 'closed'", "INTERVAL 7 DAY"} {
+		if !strings.Contains(where, want) {
+			t.Errorf("wisps condition %q missing %q", where, want)
+		}
+	}
+
+	if table, _, err := pruneCondition(PruneEvents, 30); err != nil || table != "events" {
+		t.Errorf("pruneCondition(events) = %q, %v", table, err)
+	}
+	if _, _, err := pruneCondition("issues", 30); err == nil {
+		t.Error("expected error for unknown target")
+	}
+	if _, err := PruneTable(t.TempDir(), "gastown", PruneEvents, 0, true); err == nil {
+		t.Error("expected error for zero-day retention")
+	}
+}
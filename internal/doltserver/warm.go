@@ -0,0 +1,60 @@
+package doltserver
+
+import (
+	"fmt"
+	"time"
+)
+
+// warmQueries are run against each database to load the chunk indexes
+// bd touches first: the open-bead count behind status views and the ready
+// query agents poll for work.
+var warmQueries = []string{
+	"SELECT COUNT(*) AS n FROM issues WHERE status = 'open'",
+	"SELECT i.id FROM issues i WHERE i.status = 'open' AND NOT EXISTS (" +
+		"SELECT 1 FROM dependencies d JOIN issues b ON b.id = d.depends_on_id " +
+		"WHERE d.issue_id = i.id AND d.type = 'blocks' AND b.status <> 'closed') " +
+		"ORDER BY i.priority, i.created_at LIMIT 50",
+}
+
+// WarmResult is the outcome of warming one database.
+type WarmResult struct {
+	Database string        `json:"database"`
+	Duration time.Duration `json:"duration_ns"`
+	Queries  int           `json:"queries"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// WarmDatabases runs representative bd queries against each database so
+// the server loads their chunk indexes before agents do. The first queries
+// after a start are slow while Dolt reads indexes from disk; paying that
+// cost up front keeps it off the agents' critical path. With no databases
+// given, warms every database in .dolt-data/. A failing database is
+// reported in its result and does not stop the others.
+func WarmDatabases(townRoot string, databases []string) ([]WarmResult, error) {
+	if len(databases) == 0 {
+		var err error
+		databases, err = ListDatabases(townRoot)
+		if err != nil {
+			return nil, fmt.Errorf("listing databases: %w", err)
+		}
+	}
+	results := make([]WarmResult, 0, len(databases))
+	for _, db := range databases {
+		results = append(results, warmDatabase(townRoot, db))
+	}
+	return results, nil
+}
+
+func warmDatabase(townRoot, db string) WarmResult {
+	res := WarmResult{Database: db}
+	start := clk.Now()
+	for _, q := range warmQueries {
+		if _, err := doltQueryCSV(townRoot, db, q); err != nil {
+			res.Error = err.Error()
+			break
+		}
+		res.Queries++
+	}
+	res.Duration = clk.Now().Sub(start)
+	return res
+}
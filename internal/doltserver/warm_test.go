@@ -0,0 +1,43 @@
+package doltserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestWarmDatabases(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	defer SetClock(fake)()
+	var queries []string
+	defer SetRunner(&proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		query := c.Args[len(c.Args)-1]
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "USE broken;") {
+			return nil, []byte("table not found: issues"), errors.New("exit status 1")
+		}
+		fake.Advance(2 * time.Second)
+		return []byte("n\n3\n"), nil, nil
+	}})()
+
+	results, err := WarmDatabases(t.TempDir(), []string{"gastown", "broken"})
+	if err != nil {
+		t.Fatalf("WarmDatabases: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want 2", results)
+	}
+	if r := results[0]; r.Database != "gastown" || r.Queries != len(warmQueries) || r.Error != "" || r.Duration != 4*time.Second {
+		t.Errorf("gastown = %+v, want %d queries in 4s", r, len(warmQueries))
+	}
+	if r := results[1]; r.Queries != 0 || !strings.Contains(r.Error, "table not found") {
+		t.Errorf("broken = %+v, want error and no queries", r)
+	}
+	if !strings.Contains(queries[1], "d.type = 'blocks'") {
+		t.Errorf("second query is not the ready query: %s", queries[1])
+	}
+}
@@ -54,6 +54,9 @@ const (
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
+	// Dolt server watchdog events (emitted by the daemon)
+	TypeDoltRestart = "dolt_restart"
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
@@ -68,6 +71,15 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Bead change feed events (emitted by the daemon from dolt_diff)
+	TypeBeadCreated       = "bead_created"
+	TypeBeadStatusChanged = "bead_status_changed"
+	TypeBeadClosed        = "bead_closed"
+
+	// Backup restore verification events (gt dolt verify-backup)
+	TypeBackupVerified     = "backup_verified"
+	TypeBackupVerifyFailed = "backup_verify_failed"
 )
 
 // EventsFile is the name of the raw events log.
@@ -332,3 +344,36 @@ func SessionPayload(sessionID, role, topic, cwd string) map[string]interface{} {
 	}
 	return p
 }
+
+// BeadChangePayload creates a payload for bead change feed events.
+// rig: rig database the bead lives in
+// fromStatus/toStatus: status before and after (fromStatus empty on create)
+// commit: Dolt commit containing the change
+func BeadChangePayload(rig, beadID, title, fromStatus, toStatus, commit string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"bead":   beadID,
+		"status": toStatus,
+		"commit": commit,
+	}
+	if title != "" {
+		p["title"] = title
+	}
+	if fromStatus != "" {
+		p["from_status"] = fromStatus
+	}
+	return p
+}
+
+// BackupVerifyPayload creates a payload for backup restore verification events.
+// failedCheck: the first check that failed (empty on success)
+func BackupVerifyPayload(database, failedCheck, message string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":     database,
+		"message": message,
+	}
+	if failedCheck != "" {
+		p["check"] = failedCheck
+	}
+	return p
+}
@@ -0,0 +1,290 @@
+// Package execrec provides a record/replay executor for subprocess interactions.
+//
+// Much of gt wraps external tools (bd, dolt, tmux, git). Tests for that code
+// historically relied on shell-script fakes placed on PATH, which drift from
+// the real tools' output over time. execrec lets the same call sites run in
+// one of three modes:
+//
+//   - live:   commands run normally (the default)
+//   - record: commands run normally and each interaction (argv, stdout,
+//     stderr, exit code) is appended to a JSON fixture file
+//   - replay: commands are not run; results are served from a fixture file
+//
+// The mode is selected with GT_EXEC_MODE and the fixture path with
+// GT_EXEC_FIXTURE:
+//
+//	GT_EXEC_MODE=record GT_EXEC_FIXTURE=/tmp/bd.json gt daemon run
+//	GT_EXEC_MODE=replay GT_EXEC_FIXTURE=testdata/bd.json go test ./...
+package execrec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/proc"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Environment variables that select the executor returned by FromEnv.
+const (
+	EnvMode    = "GT_EXEC_MODE"
+	EnvFixture = "GT_EXEC_FIXTURE"
+)
+
+// Modes accepted in GT_EXEC_MODE.
+const (
+	ModeLive   = "live"
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// Interaction is a single recorded subprocess invocation.
+type Interaction struct {
+	// Name is the base name of the executable (e.g. "bd", not "/usr/local/bin/bd"),
+	// so fixtures are portable across machines with different install paths.
+	Name string `json:"name"`
+
+	// Args are the command arguments, excluding the executable.
+	Args []string `json:"args"`
+
+	// Stdout is the captured standard output.
+	Stdout string `json:"stdout,omitempty"`
+
+	// Stderr is the captured standard error of a failed command. Stderr of
+	// successful commands is not recorded.
+	Stderr string `json:"stderr,omitempty"`
+
+	// ExitCode is the process exit code (0 on success).
+	ExitCode int `json:"exit_code"`
+}
+
+// Fixture is the on-disk format of a recording.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Executor runs subprocesses. Implementations must be safe for concurrent use.
+type Executor interface {
+	// Output runs name with args in dir and returns stdout.
+	// A non-zero exit returns a non-nil error; stdout is still returned.
+	Output(dir, name string, args ...string) ([]byte, error)
+}
+
+// ExitError is returned by replayed interactions that exited non-zero.
+type ExitError struct {
+	Code   int
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the exit code of a failed command, handling both live
+// (*exec.ExitError) and replayed (*ExitError) failures. Returns 0 for nil
+// and -1 for errors that are not exit errors (e.g. executable not found).
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var replayErr *ExitError
+	if errors.As(err, &replayErr) {
+		return replayErr.Code
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return execErr.ExitCode()
+	}
+	return -1
+}
+
+// exitStderr returns the stderr carried by a failed command's error, if any.
+func exitStderr(err error) string {
+	var replayErr *ExitError
+	if errors.As(err, &replayErr) {
+		return replayErr.Stderr
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return string(execErr.Stderr)
+	}
+	return ""
+}
+
+// Live runs commands for real, within the per-process subprocess limit
+// (see proc.Acquire).
+type Live struct{}
+
+// Output implements Executor. On a non-zero exit the returned
+// *exec.ExitError carries the command's stderr.
+func (Live) Output(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...) //nolint:gosec // G204: callers construct args internally
+	cmd.Dir = dir
+	return proc.Output(cmd)
+}
+
+// Recorder runs commands through an inner executor and records each
+// interaction. The fixture is rewritten after every call so that recordings
+// from short-lived processes are not lost.
+type Recorder struct {
+	inner Executor
+	path  string
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder returns a Recorder that runs commands live and writes the
+// recording to path. Existing interactions in path are preserved and
+// appended to, so multiple processes can contribute to one fixture.
+func NewRecorder(path string) *Recorder {
+	r := &Recorder{inner: Live{}, path: path}
+	if existing, err := LoadFixture(path); err == nil {
+		r.fixture = *existing
+	}
+	return r
+}
+
+// Output implements Executor.
+func (r *Recorder) Output(dir, name string, args ...string) ([]byte, error) {
+	out, err := r.inner.Output(dir, name, args...)
+
+	code := ExitCode(err)
+	if code < 0 {
+		// Executable not found or similar: nothing meaningful to replay.
+		return out, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+		Name:     filepath.Base(name),
+		Args:     slices.Clone(args),
+		Stdout:   string(out),
+		Stderr:   exitStderr(err),
+		ExitCode: code,
+	})
+	if saveErr := r.saveLocked(); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: execrec: saving fixture %s: %v\n", r.path, saveErr)
+	}
+	return out, err
+}
+
+// Interactions returns a copy of the interactions recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.fixture.Interactions)
+}
+
+func (r *Recorder) saveLocked() error {
+	return util.EnsureDirAndWriteJSON(r.path, r.fixture)
+}
+
+// Replayer serves command results from a fixture without running anything.
+// Each recorded interaction is consumed at most once, in recording order,
+// so repeated identical calls can return different results.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a Replayer for the given interactions.
+func NewReplayer(interactions ...Interaction) *Replayer {
+	return &Replayer{
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// LoadReplayer reads a fixture file and returns a Replayer for it.
+func LoadReplayer(path string) (*Replayer, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(fixture.Interactions...), nil
+}
+
+// Output implements Executor. It returns an error if no unused interaction
+// matches the command, which surfaces code paths that drifted from the fixture.
+func (p *Replayer) Output(_ string, name string, args ...string) ([]byte, error) {
+	base := filepath.Base(name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, in := range p.interactions {
+		if p.used[i] || in.Name != base || !slices.Equal(in.Args, args) {
+			continue
+		}
+		p.used[i] = true
+		if in.ExitCode != 0 {
+			return []byte(in.Stdout), &ExitError{Code: in.ExitCode, Stderr: in.Stderr}
+		}
+		return []byte(in.Stdout), nil
+	}
+	return nil, fmt.Errorf("execrec: no recorded interaction for %s %v", base, args)
+}
+
+// Unused returns interactions that were never replayed. Tests can assert this
+// is empty to catch code paths that stopped calling a recorded command.
+func (p *Replayer) Unused() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var unused []Interaction
+	for i, in := range p.interactions {
+		if !p.used[i] {
+			unused = append(unused, in)
+		}
+	}
+	return unused
+}
+
+// LoadFixture reads a fixture file from disk.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// FromEnv returns the executor selected by GT_EXEC_MODE and GT_EXEC_FIXTURE.
+// With no mode set it returns Live. Record and replay modes require a
+// fixture path.
+func FromEnv() (Executor, error) {
+	mode := os.Getenv(EnvMode)
+	path := os.Getenv(EnvFixture)
+
+	switch mode {
+	case "", ModeLive:
+		return Live{}, nil
+	case ModeRecord:
+		if path == "" {
+			return Live{}, fmt.Errorf("%s=%s requires %s", EnvMode, mode, EnvFixture)
+		}
+		return NewRecorder(path), nil
+	case ModeReplay:
+		if path == "" {
+			return Live{}, fmt.Errorf("%s=%s requires %s", EnvMode, mode, EnvFixture)
+		}
+		replayer, err := LoadReplayer(path)
+		if err != nil {
+			return Live{}, fmt.Errorf("loading replay fixture: %w", err)
+		}
+		return replayer, nil
+	default:
+		return Live{}, fmt.Errorf("unknown %s %q (want %s, %s, or %s)", EnvMode, mode, ModeLive, ModeRecord, ModeReplay)
+	}
+}
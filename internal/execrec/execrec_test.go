@@ -0,0 +1,148 @@
+package execrec
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	rec := NewRecorder(fixture)
+	out, err := rec.Output("", "echo", "hello")
+	if err != nil {
+		t.Fatalf("echo: %v", err)
+	}
+	if string(out) != "hello\n" {
+		t.Errorf("stdout = %q, want %q", out, "hello\n")
+	}
+	_, err = rec.Output("", "sh", "-c", "echo oops >&2; exit 3")
+	if ExitCode(err) != 3 {
+		t.Fatalf("ExitCode = %d, want 3 (err=%v)", ExitCode(err), err)
+	}
+	if got := len(rec.Interactions()); got != 2 {
+		t.Fatalf("recorded %d interactions, want 2", got)
+	}
+
+	replayer, err := LoadReplayer(fixture)
+	if err != nil {
+		t.Fatalf("LoadReplayer: %v", err)
+	}
+	// Absolute executable paths match recordings by base name.
+	out, err = replayer.Output("/elsewhere", "/usr/bin/echo", "hello")
+	if err != nil || string(out) != "hello\n" {
+		t.Errorf("replayed echo = %q, %v", out, err)
+	}
+	_, err = replayer.Output("", "sh", "-c", "echo oops >&2; exit 3")
+	exitErr, ok := err.(*ExitError)
+	if !ok {
+		t.Fatalf("replayed failure err = %T %v, want *ExitError", err, err)
+	}
+	if exitErr.Code != 3 || exitErr.Stderr != "oops\n" {
+		t.Errorf("ExitError = %+v", exitErr)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Unused = %+v, want none", unused)
+	}
+}
+
+func TestRecorder_AppendsToExistingFixture(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses echo")
+	}
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	if _, err := NewRecorder(fixture).Output("", "echo", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRecorder(fixture).Output("", "echo", "two"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFixture(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Interactions) != 2 {
+		t.Errorf("fixture has %d interactions, want 2", len(loaded.Interactions))
+	}
+}
+
+func TestRecorder_RunsThroughInner(t *testing.T) {
+	inner := NewReplayer(Interaction{Name: "bd", Args: []string{"show", "x"}, Stderr: "not found\n", ExitCode: 1})
+	rec := &Recorder{inner: inner, path: filepath.Join(t.TempDir(), "fixture.json")}
+
+	if _, err := rec.Output("", "/usr/local/bin/bd", "show", "x"); ExitCode(err) != 1 {
+		t.Fatalf("ExitCode = %d, want 1 (err=%v)", ExitCode(err), err)
+	}
+	got := rec.Interactions()
+	if len(got) != 1 || got[0].Name != "bd" || got[0].Stderr != "not found\n" {
+		t.Errorf("Interactions = %+v", got)
+	}
+	if unused := inner.Unused(); len(unused) != 0 {
+		t.Errorf("inner executor not used: %+v", unused)
+	}
+}
+
+func TestReplayer_ConsumesInOrder(t *testing.T) {
+	replayer := NewReplayer(
+		Interaction{Name: "bd", Args: []string{"ready"}, Stdout: "first"},
+		Interaction{Name: "bd", Args: []string{"ready"}, Stdout: "second"},
+	)
+	for _, want := range []string{"first", "second"} {
+		out, err := replayer.Output("", "bd", "ready")
+		if err != nil || string(out) != want {
+			t.Errorf("Output = %q, %v; want %q", out, err, want)
+		}
+	}
+	if _, err := replayer.Output("", "bd", "ready"); err == nil {
+		t.Error("expected error once fixture is exhausted")
+	}
+}
+
+func TestReplayer_ArgsMustMatch(t *testing.T) {
+	replayer := NewReplayer(Interaction{Name: "bd", Args: []string{"show", "gt-1"}})
+	if _, err := replayer.Output("", "bd", "show", "gt-2"); err == nil {
+		t.Error("expected error for mismatched args")
+	}
+	if len(replayer.Unused()) != 1 {
+		t.Error("mismatched call should not consume the interaction")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	tests := []struct {
+		name    string
+		mode    string
+		path    string
+		wantErr bool
+		check   func(Executor) bool
+	}{
+		{"default is live", "", "", false, func(e Executor) bool { _, ok := e.(Live); return ok }},
+		{"explicit live", ModeLive, "", false, func(e Executor) bool { _, ok := e.(Live); return ok }},
+		{"record", ModeRecord, fixture, false, func(e Executor) bool { _, ok := e.(*Recorder); return ok }},
+		{"record without fixture", ModeRecord, "", true, nil},
+		{"replay missing file", ModeReplay, fixture, true, nil},
+		{"unknown mode", "bogus", "", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvMode, tt.mode)
+			t.Setenv(EnvFixture, tt.path)
+			exec, err := FromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if exec == nil {
+				t.Fatal("FromEnv must always return a usable executor")
+			}
+			if tt.check != nil && !tt.check(exec) {
+				t.Errorf("FromEnv returned %T", exec)
+			}
+		})
+	}
+}
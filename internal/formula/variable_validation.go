@@ -159,3 +159,19 @@ func (f *Formula) ValidateTemplateVariables() error {
 	return nil
 }
 
+// MissingVars returns the required [vars] that have no default and are not
+// in provided, sorted. Instantiating the formula without them would leave
+// {{var}} placeholders unexpanded, so callers should refuse and list them.
+func (f *Formula) MissingVars(provided map[string]string) []string {
+	var missing []string
+	for name, v := range f.Vars {
+		if !v.Required || v.Default != "" {
+			continue
+		}
+		if _, ok := provided[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
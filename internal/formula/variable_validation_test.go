@@ -228,3 +228,20 @@ func TestAllEmbeddedFormulas_VariableValidation(t *testing.T) {
 		t.Errorf("Formulas with undefined template variables:\n%s", strings.Join(failures, "\n"))
 	}
 }
+
+func TestMissingVars(t *testing.T) {
+	f := &Formula{Vars: map[string]Var{
+		"issue":       {Required: true},
+		"base_branch": {Required: true, Default: "main"},
+		"assignee":    {Required: true},
+		"notes":       {},
+	}}
+
+	got := f.MissingVars(map[string]string{"issue": "gt-123"})
+	if len(got) != 1 || got[0] != "assignee" {
+		t.Errorf("MissingVars() = %v, want [assignee]", got)
+	}
+	if got := f.MissingVars(map[string]string{"issue": "gt-123", "assignee": "toast"}); len(got) != 0 {
+		t.Errorf("MissingVars() = %v, want none", got)
+	}
+}
@@ -1033,6 +1033,12 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// DiffStat returns the `git diff --stat` summary of the changes branch
+// makes relative to its merge base with base.
+func (g *Git) DiffStat(base, branch string) (string, error) {
+	return g.run("diff", "--stat", base+"..."+branch)
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -0,0 +1,156 @@
+// Package idempotency makes retried gt operations safe to repeat.
+//
+// A script that retries gt sling or gt nudge after a timeout can't tell
+// whether the first attempt went through, and retrying blindly slings the
+// bead twice or nudges the agent twice. Callers pass an idempotency key
+// (explicit, or derived from the operation and its arguments) and wrap the
+// operation in Begin and Complete:
+//
+//   - Begin claims the key. If the key was already claimed within the TTL
+//     it returns the earlier record instead, and the caller reports that
+//     record's result rather than running the operation again.
+//   - Complete records the operation's result against the key.
+//   - Release drops the claim after a failure, so a retry runs for real.
+//
+// Records live in the town's runtime directory and expire after the TTL.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultTTL is how long a key suppresses repeats.
+const DefaultTTL = 10 * time.Minute
+
+var clk clock.Clock = clock.Real{}
+
+// SetClock replaces the clock used for expiry (for tests).
+func SetClock(c clock.Clock) (restore func()) {
+	prev := clk
+	clk = c
+	return func() { clk = prev }
+}
+
+// Record is what a key remembers about the operation that claimed it.
+type Record struct {
+	Key       string    `json:"key"`
+	Op        string    `json:"op"`
+	Result    string    `json:"result,omitempty"`
+	Pending   bool      `json:"pending,omitempty"` // claimed but not yet completed
+	At        time.Time `json:"at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// String describes the record for a skipped repeat.
+func (r *Record) String() string {
+	if r.Pending {
+		return fmt.Sprintf("same %s still in progress (started %s)", r.Op, r.At.Local().Format("15:04:05"))
+	}
+	s := fmt.Sprintf("same %s already ran at %s", r.Op, r.At.Local().Format("15:04:05"))
+	if r.Result != "" {
+		s += ": " + r.Result
+	}
+	return s
+}
+
+type store struct {
+	Records map[string]*Record `json:"records"`
+}
+
+// DeriveKey builds a key from an operation and the arguments that identify
+// it, for callers that don't pass one explicitly.
+func DeriveKey(op string, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return op + ":" + hex.EncodeToString(sum[:8])
+}
+
+// storePath returns the path of the key records.
+func storePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "idempotency.json")
+}
+
+// withStore runs fn with the records loaded under an exclusive lock and
+// saves the result. Expired records are pruned on every access.
+func withStore(townRoot string, fn func(s *store)) error {
+	path := storePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking idempotency keys: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	s := store{Records: make(map[string]*Record)}
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 { //nolint:gosec // G304: path is constructed internally
+		// A corrupt file only costs us dedup history; start over.
+		_ = json.Unmarshal(data, &s)
+		if s.Records == nil {
+			s.Records = make(map[string]*Record)
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading idempotency keys: %w", err)
+	}
+
+	now := clk.Now()
+	for key, r := range s.Records {
+		if !now.Before(r.ExpiresAt) {
+			delete(s.Records, key)
+		}
+	}
+	fn(&s)
+	return util.AtomicWriteJSON(path, &s)
+}
+
+// Begin claims key for op for ttl. If the key is already claimed it
+// returns the existing record and the caller should skip the operation;
+// otherwise it returns nil and the caller runs the operation, then calls
+// Complete or Release.
+func Begin(townRoot, key, op string, ttl time.Duration) (*Record, error) {
+	now := clk.Now()
+	var existing *Record
+	err := withStore(townRoot, func(s *store) {
+		if r, ok := s.Records[key]; ok {
+			existing = r
+			return
+		}
+		s.Records[key] = &Record{Key: key, Op: op, Pending: true, At: now, ExpiresAt: now.Add(ttl)}
+	})
+	return existing, err
+}
+
+// Complete records result for a key claimed with Begin. The TTL runs from
+// completion, so a slow operation still suppresses repeats for the full
+// window afterwards.
+func Complete(townRoot, key, result string) error {
+	now := clk.Now()
+	return withStore(townRoot, func(s *store) {
+		r, ok := s.Records[key]
+		if !ok {
+			return // expired while running; nothing to suppress
+		}
+		ttl := r.ExpiresAt.Sub(r.At)
+		r.Result, r.Pending = result, false
+		r.At, r.ExpiresAt = now, now.Add(ttl)
+	})
+}
+
+// Release drops a key claimed with Begin whose operation failed.
+func Release(townRoot, key string) error {
+	return withStore(townRoot, func(s *store) {
+		delete(s.Records, key)
+	})
+}
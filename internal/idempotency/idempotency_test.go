@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestBeginComplete(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	key := DeriveKey("sling", "gt-abc", "gastown")
+
+	if r, err := Begin(townRoot, key, "sling", time.Minute); err != nil || r != nil {
+		t.Fatalf("first Begin = %v, %v; want nil, nil", r, err)
+	}
+	r, err := Begin(townRoot, key, "sling", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r == nil || !r.Pending {
+		t.Fatalf("Begin while in progress = %+v, want the pending record", r)
+	}
+
+	fake.Advance(30 * time.Second)
+	if err := Complete(townRoot, key, "gt-abc → gastown/polecats/Toast"); err != nil {
+		t.Fatal(err)
+	}
+	r, err = Begin(townRoot, key, "sling", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r == nil || r.Pending || !strings.Contains(r.String(), "gastown/polecats/Toast") {
+		t.Fatalf("Begin after Complete = %+v, want the completed result", r)
+	}
+
+	// The TTL runs from completion.
+	fake.Advance(59 * time.Second)
+	if r, _ := Begin(townRoot, key, "sling", time.Minute); r == nil {
+		t.Error("key expired before the TTL had passed since completion")
+	}
+	fake.Advance(time.Second)
+	if r, _ := Begin(townRoot, key, "sling", time.Minute); r != nil {
+		t.Errorf("Begin after the TTL = %+v, want a fresh claim", r)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := Begin(townRoot, "nudge:k", "nudge", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := Release(townRoot, "nudge:k"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := Begin(townRoot, "nudge:k", "nudge", time.Minute); err != nil || r != nil {
+		t.Errorf("Begin after Release = %v, %v; want a fresh claim", r, err)
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	a := DeriveKey("nudge", "gastown/alpha", "hello")
+	if !strings.HasPrefix(a, "nudge:") {
+		t.Errorf("DeriveKey = %q, want nudge: prefix", a)
+	}
+	if a != DeriveKey("nudge", "gastown/alpha", "hello") {
+		t.Error("DeriveKey is not stable")
+	}
+	if a == DeriveKey("nudge", "gastown/alphahello") {
+		t.Error("DeriveKey ignores part boundaries")
+	}
+	if a == DeriveKey("sling", "gastown/alpha", "hello") {
+		t.Error("DeriveKey ignores the operation")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 const (
@@ -61,7 +63,7 @@ func runBdCommand(ctx context.Context, args []string, workDir, beadsDir string,
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	runErr := cmd.Run()
+	runErr := proc.RunCmdContext(ctx, cmd)
 
 	if runErr != nil {
 		return nil, &bdError{
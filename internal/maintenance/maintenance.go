@@ -0,0 +1,85 @@
+// Package maintenance records whether the town is frozen for maintenance.
+//
+// While maintenance mode is on, the daemon skips its heartbeat, so it
+// neither restarts agents nor triggers spawns, and new polecats are refused.
+// gt panic turns it on; gt panic release turns it off.
+package maintenance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is the maintenance mode file contents.
+type State struct {
+	// Reason explains why the town was frozen.
+	Reason string `json:"reason,omitempty"`
+
+	// Since is when maintenance mode was turned on.
+	Since time.Time `json:"since"`
+
+	// By identifies who turned it on (e.g., "human", "mayor").
+	By string `json:"by,omitempty"`
+
+	// Bundle is the support bundle captured when the town was frozen.
+	Bundle string `json:"bundle,omitempty"`
+
+	// DoltTag is the tag marking each database's HEAD when the town was
+	// frozen.
+	DoltTag string `json:"dolt_tag,omitempty"`
+}
+
+// File returns the path to the maintenance mode file.
+func File(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "maintenance.json")
+}
+
+// Load returns the maintenance state, or nil if maintenance mode is off.
+func Load(townRoot string) (*State, error) {
+	data, err := os.ReadFile(File(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// IsActive reports whether maintenance mode is on. An unreadable file
+// counts as on, so a damaged file doesn't quietly unfreeze the town.
+func IsActive(townRoot string) bool {
+	state, err := Load(townRoot)
+	return err != nil || state != nil
+}
+
+// Enable turns maintenance mode on, replacing any earlier state.
+func Enable(townRoot string, state *State) error {
+	path := File(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if state.Since.IsZero() {
+		state.Since = time.Now().UTC()
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Disable turns maintenance mode off.
+func Disable(townRoot string) error {
+	err := os.Remove(File(townRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
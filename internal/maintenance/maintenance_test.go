@@ -0,0 +1,47 @@
+package maintenance
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnableDisable(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if IsActive(townRoot) {
+		t.Fatal("maintenance mode on in a fresh town")
+	}
+	if err := Enable(townRoot, &State{Reason: "runaway costs", By: "human", DoltTag: "gt-panic-1"}); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if !IsActive(townRoot) {
+		t.Fatal("maintenance mode off after Enable")
+	}
+	state, err := Load(townRoot)
+	if err != nil || state.Reason != "runaway costs" || state.DoltTag != "gt-panic-1" || state.Since.IsZero() {
+		t.Errorf("Load = %+v, %v", state, err)
+	}
+
+	if err := Disable(townRoot); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if IsActive(townRoot) {
+		t.Error("maintenance mode on after Disable")
+	}
+	if err := Disable(townRoot); err != nil {
+		t.Errorf("second Disable: %v", err)
+	}
+}
+
+func TestIsActive_DamagedFile(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Enable(townRoot, &State{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(File(townRoot), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !IsActive(townRoot) {
+		t.Error("damaged maintenance file should count as active")
+	}
+}
@@ -0,0 +1,278 @@
+// Package molstats computes formula effectiveness statistics from past
+// molecule runs: how often each proto's molecules complete, how long their
+// steps take, which steps get skipped or fail, and how often the resulting
+// work is rejected in review.
+//
+// Callers assemble Runs from the activity feed and the beads databases;
+// this package only aggregates them.
+package molstats
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// StepOutcome classifies how a molecule step ended.
+type StepOutcome string
+
+const (
+	StepDone    StepOutcome = "done"
+	StepSkipped StepOutcome = "skipped"
+	StepFailed  StepOutcome = "failed"
+	StepOpen    StepOutcome = "open"
+)
+
+// Step is one step of a molecule run.
+type Step struct {
+	ID          string
+	Title       string
+	Status      string // bd status: open, in_progress, closed, ...
+	CloseReason string
+	ClosedAt    time.Time
+}
+
+// Outcome classifies the step. Closed steps whose close reason starts with
+// "skip" were skipped and ones mentioning "fail" failed. A step still open
+// after its run completed was never done and counts as failed.
+func (s Step) Outcome(runCompleted bool) StepOutcome {
+	if s.Status != "closed" {
+		if runCompleted {
+			return StepFailed
+		}
+		return StepOpen
+	}
+	reason := strings.ToLower(strings.TrimSpace(s.CloseReason))
+	switch {
+	case strings.HasPrefix(reason, "skip"):
+		return StepSkipped
+	case strings.Contains(reason, "fail"):
+		return StepFailed
+	}
+	return StepDone
+}
+
+// Run is one molecule poured from a formula.
+type Run struct {
+	Formula    string
+	Molecule   string    // wisp root ID
+	Bead       string    // bead that was slung (base bead, or the wisp root itself)
+	Started    time.Time // when the work was slung
+	Finished   time.Time // when gt done ran; zero if it hasn't
+	Steps      []Step    // nil if the wisp no longer exists
+	Reviews    int       // merge attempts on the run's branch
+	Rejections int       // merge attempts that failed
+}
+
+// Completed reports whether the run finished: gt done ran, or every step closed.
+func (r Run) Completed() bool {
+	if !r.Finished.IsZero() {
+		return true
+	}
+	if len(r.Steps) == 0 {
+		return false
+	}
+	for _, s := range r.Steps {
+		if s.Status != "closed" {
+			return false
+		}
+	}
+	return true
+}
+
+// ProtoStats summarizes the runs of one formula.
+type ProtoStats struct {
+	Formula        string      `json:"formula"`
+	Runs           int         `json:"runs"`
+	Completed      int         `json:"completed"`
+	CompletionRate float64     `json:"completion_rate"`
+	AvgSeconds     float64     `json:"avg_seconds,omitempty"` // mean slung-to-done time of finished runs
+	Reviewed       int         `json:"reviewed"`              // runs whose branch reached the merge queue
+	Rejected       int         `json:"rejected"`              // reviewed runs with at least one failed merge
+	RejectionRate  float64     `json:"rejection_rate"`
+	Steps          []StepStats `json:"steps,omitempty"`
+}
+
+// StepStats summarizes one step of a formula across its runs. Steps are
+// matched across runs by title, since step IDs differ per molecule.
+type StepStats struct {
+	Title      string  `json:"title"`
+	Runs       int     `json:"runs"`
+	Done       int     `json:"done"`
+	Skipped    int     `json:"skipped"`
+	Failed     int     `json:"failed"`
+	AvgSeconds float64 `json:"avg_seconds,omitempty"` // mean time from the previous step closing to this one
+
+	// Rejection rate of reviewed runs that missed (skipped or failed) this
+	// step, and of those that did it. A large gap suggests the step matters.
+	RejectionRateMissed float64 `json:"rejection_rate_missed"`
+	RejectionRateDone   float64 `json:"rejection_rate_done"`
+	ReviewedMissed      int     `json:"reviewed_missed"`
+	ReviewedDone        int     `json:"reviewed_done"`
+}
+
+// Missed is the number of runs that skipped or failed the step.
+func (s StepStats) Missed() int {
+	return s.Skipped + s.Failed
+}
+
+// MostSkipped returns the step skipped most often, or nil if none was.
+func (p ProtoStats) MostSkipped() *StepStats {
+	return p.most(func(s StepStats) int { return s.Skipped })
+}
+
+// MostFailed returns the step that failed most often, or nil if none did.
+func (p ProtoStats) MostFailed() *StepStats {
+	return p.most(func(s StepStats) int { return s.Failed })
+}
+
+func (p ProtoStats) most(count func(StepStats) int) *StepStats {
+	var best *StepStats
+	for i := range p.Steps {
+		if n := count(p.Steps[i]); n > 0 && (best == nil || n > count(*best)) {
+			best = &p.Steps[i]
+		}
+	}
+	return best
+}
+
+// stepAccum gathers a step's figures before they are turned into rates.
+type stepAccum struct {
+	StepStats
+	seconds        float64
+	timed          int
+	rejectedMissed int
+	rejectedDone   int
+}
+
+// Compute aggregates runs into per-formula statistics, sorted by number of
+// runs (most first), then formula name. Steps keep the order in which they
+// first appear in the runs.
+func Compute(runs []Run) []ProtoStats {
+	type protoAccum struct {
+		ProtoStats
+		seconds float64
+		timed   int
+		steps   []*stepAccum
+		byTitle map[string]*stepAccum
+	}
+	protos := make(map[string]*protoAccum)
+
+	for _, run := range runs {
+		p := protos[run.Formula]
+		if p == nil {
+			p = &protoAccum{ProtoStats: ProtoStats{Formula: run.Formula}, byTitle: make(map[string]*stepAccum)}
+			protos[run.Formula] = p
+		}
+		p.Runs++
+		completed := run.Completed()
+		if completed {
+			p.Completed++
+		}
+		if !run.Finished.IsZero() && !run.Started.IsZero() {
+			p.seconds += run.Finished.Sub(run.Started).Seconds()
+			p.timed++
+		}
+		reviewed := run.Reviews > 0
+		rejected := run.Rejections > 0
+		if reviewed {
+			p.Reviewed++
+			if rejected {
+				p.Rejected++
+			}
+		}
+
+		durations := stepDurations(run)
+		for _, step := range run.Steps {
+			s := p.byTitle[step.Title]
+			if s == nil {
+				s = &stepAccum{StepStats: StepStats{Title: step.Title}}
+				p.byTitle[step.Title] = s
+				p.steps = append(p.steps, s)
+			}
+			s.Runs++
+			outcome := step.Outcome(completed)
+			switch outcome {
+			case StepDone:
+				s.Done++
+				if d, ok := durations[step.ID]; ok {
+					s.seconds += d.Seconds()
+					s.timed++
+				}
+			case StepSkipped:
+				s.Skipped++
+			case StepFailed:
+				s.Failed++
+			}
+			if !reviewed {
+				continue
+			}
+			switch outcome {
+			case StepDone:
+				s.ReviewedDone++
+				if rejected {
+					s.rejectedDone++
+				}
+			case StepSkipped, StepFailed:
+				s.ReviewedMissed++
+				if rejected {
+					s.rejectedMissed++
+				}
+			}
+		}
+	}
+
+	stats := make([]ProtoStats, 0, len(protos))
+	for _, p := range protos {
+		p.CompletionRate = rate(p.Completed, p.Runs)
+		p.RejectionRate = rate(p.Rejected, p.Reviewed)
+		if p.timed > 0 {
+			p.AvgSeconds = p.seconds / float64(p.timed)
+		}
+		for _, s := range p.steps {
+			if s.timed > 0 {
+				s.AvgSeconds = s.seconds / float64(s.timed)
+			}
+			s.RejectionRateMissed = rate(s.rejectedMissed, s.ReviewedMissed)
+			s.RejectionRateDone = rate(s.rejectedDone, s.ReviewedDone)
+			p.Steps = append(p.Steps, s.StepStats)
+		}
+		stats = append(stats, p.ProtoStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Runs != stats[j].Runs {
+			return stats[i].Runs > stats[j].Runs
+		}
+		return stats[i].Formula < stats[j].Formula
+	})
+	return stats
+}
+
+// stepDurations returns how long each closed step of a run took, measured
+// from the previous step closing (or the run starting, for the first).
+func stepDurations(run Run) map[string]time.Duration {
+	closed := make([]Step, 0, len(run.Steps))
+	for _, s := range run.Steps {
+		if s.Status == "closed" && !s.ClosedAt.IsZero() {
+			closed = append(closed, s)
+		}
+	}
+	sort.SliceStable(closed, func(i, j int) bool { return closed[i].ClosedAt.Before(closed[j].ClosedAt) })
+
+	durations := make(map[string]time.Duration, len(closed))
+	prev := run.Started
+	for _, s := range closed {
+		if !prev.IsZero() && !s.ClosedAt.Before(prev) {
+			durations[s.ID] = s.ClosedAt.Sub(prev)
+		}
+		prev = s.ClosedAt
+	}
+	return durations
+}
+
+func rate(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) / float64(of)
+}
@@ -0,0 +1,98 @@
+package molstats
+
+import (
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func at(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+
+func TestStepOutcome(t *testing.T) {
+	tests := []struct {
+		step      Step
+		completed bool
+		want      StepOutcome
+	}{
+		{Step{Status: "closed"}, true, StepDone},
+		{Step{Status: "closed", CloseReason: "Skipped: not applicable"}, true, StepSkipped},
+		{Step{Status: "closed", CloseReason: "tests failed, giving up"}, true, StepFailed},
+		{Step{Status: "open"}, true, StepFailed},
+		{Step{Status: "in_progress"}, false, StepOpen},
+	}
+	for _, tt := range tests {
+		if got := tt.step.Outcome(tt.completed); got != tt.want {
+			t.Errorf("Outcome(%+v, %v) = %s, want %s", tt.step, tt.completed, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	runs := []Run{
+		{
+			Formula: "mol-polecat-work", Molecule: "gt-wisp-a", Started: at(0), Finished: at(60),
+			Reviews: 1,
+			Steps: []Step{
+				{ID: "a1", Title: "Implement", Status: "closed", ClosedAt: at(40)},
+				{ID: "a2", Title: "Run tests", Status: "closed", ClosedAt: at(50)},
+			},
+		},
+		{
+			Formula: "mol-polecat-work", Molecule: "gt-wisp-b", Started: at(0), Finished: at(30),
+			Reviews: 2, Rejections: 1,
+			Steps: []Step{
+				{ID: "b1", Title: "Implement", Status: "closed", ClosedAt: at(20)},
+				{ID: "b2", Title: "Run tests", Status: "closed", CloseReason: "skip: no tests", ClosedAt: at(21)},
+			},
+		},
+		{
+			Formula: "mol-polecat-work", Molecule: "gt-wisp-c", Started: at(0),
+			Steps: []Step{
+				{ID: "c1", Title: "Implement", Status: "in_progress"},
+				{ID: "c2", Title: "Run tests", Status: "open"},
+			},
+		},
+		{Formula: "mol-deacon-patrol", Molecule: "hq-wisp-d", Started: at(0)},
+	}
+
+	stats := Compute(runs)
+	if len(stats) != 2 || stats[0].Formula != "mol-polecat-work" || stats[1].Formula != "mol-deacon-patrol" {
+		t.Fatalf("Compute formulas = %+v, want polecat-work then deacon-patrol", stats)
+	}
+
+	p := stats[0]
+	if p.Runs != 3 || p.Completed != 2 {
+		t.Errorf("runs/completed = %d/%d, want 3/2", p.Runs, p.Completed)
+	}
+	if p.AvgSeconds != 45*60 {
+		t.Errorf("AvgSeconds = %v, want 2700", p.AvgSeconds)
+	}
+	if p.Reviewed != 2 || p.Rejected != 1 || p.RejectionRate != 0.5 {
+		t.Errorf("reviewed/rejected/rate = %d/%d/%v, want 2/1/0.5", p.Reviewed, p.Rejected, p.RejectionRate)
+	}
+
+	if len(p.Steps) != 2 || p.Steps[0].Title != "Implement" || p.Steps[1].Title != "Run tests" {
+		t.Fatalf("steps = %+v", p.Steps)
+	}
+	impl, tests := p.Steps[0], p.Steps[1]
+	if impl.Done != 2 || impl.AvgSeconds != 30*60 {
+		t.Errorf("Implement done/avg = %d/%v, want 2/1800", impl.Done, impl.AvgSeconds)
+	}
+	if tests.Done != 1 || tests.Skipped != 1 || tests.AvgSeconds != 10*60 {
+		t.Errorf("Run tests done/skipped/avg = %d/%d/%v, want 1/1/600", tests.Done, tests.Skipped, tests.AvgSeconds)
+	}
+	if tests.RejectionRateMissed != 1 || tests.RejectionRateDone != 0 {
+		t.Errorf("Run tests rejection missed/done = %v/%v, want 1/0", tests.RejectionRateMissed, tests.RejectionRateDone)
+	}
+	if s := p.MostSkipped(); s == nil || s.Title != "Run tests" {
+		t.Errorf("MostSkipped = %+v, want Run tests", s)
+	}
+	if s := p.MostFailed(); s != nil {
+		t.Errorf("MostFailed = %+v, want nil", s)
+	}
+
+	if d := stats[1]; d.Runs != 1 || d.Completed != 0 || d.Steps != nil {
+		t.Errorf("deacon-patrol stats = %+v, want 1 incomplete run without steps", d)
+	}
+}
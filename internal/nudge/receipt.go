@@ -0,0 +1,231 @@
+package nudge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Receipt statuses.
+const (
+	// ReceiptSent means the nudge was typed into the target's pane.
+	ReceiptSent = "sent"
+	// ReceiptQueued means the nudge was written to the target's queue and
+	// will be injected at its next turn boundary.
+	ReceiptQueued = "queued"
+	// ReceiptFailed means delivery failed; Error says why.
+	ReceiptFailed = "failed"
+)
+
+// ReceiptRetention is how long receipts are kept before RecordReceipt
+// prunes them.
+const ReceiptRetention = 7 * 24 * time.Hour
+
+// Receipt records the delivery of one nudge and, when the sender asked
+// for it, the target's acknowledgement via gt ack.
+type Receipt struct {
+	ID      string    `json:"id"`
+	Target  string    `json:"target"`
+	Session string    `json:"session"`
+	Sender  string    `json:"sender"`
+	Message string    `json:"message"`
+	Mode    string    `json:"mode"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+
+	// AckBy is the deadline for gt ack; zero when no ack was required.
+	AckBy   time.Time `json:"ack_by,omitempty"`
+	AckedAt time.Time `json:"acked_at,omitempty"`
+	AckedBy string    `json:"acked_by,omitempty"`
+
+	// Escalation is the escalation bead raised for a missed ack.
+	Escalation string `json:"escalation,omitempty"`
+}
+
+// AckRequired reports whether the sender asked for an acknowledgement.
+func (r *Receipt) AckRequired() bool {
+	return !r.AckBy.IsZero()
+}
+
+// Overdue reports whether an acknowledgement was required and its
+// deadline passed at now without one, for a nudge that was delivered.
+func (r *Receipt) Overdue(now time.Time) bool {
+	return r.AckRequired() && r.AckedAt.IsZero() && r.Status != ReceiptFailed && now.After(r.AckBy)
+}
+
+// receiptDir returns the receipt directory: <townRoot>/.runtime/nudge_receipts/
+func receiptDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "nudge_receipts")
+}
+
+// NewReceiptID returns a short unique nudge ID, such as nudge-1a2b3c4d.
+func NewReceiptID() string {
+	return "nudge-" + randomSuffix()
+}
+
+// validReceiptID reports whether id has the NewReceiptID form, so it is
+// safe to use as a file name.
+func validReceiptID(id string) bool {
+	hex := strings.TrimPrefix(id, "nudge-")
+	if hex == id || hex == "" {
+		return false
+	}
+	for _, c := range hex {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordReceipt writes r, setting SentAt if unset, and prunes receipts
+// older than ReceiptRetention.
+func RecordReceipt(townRoot string, r *Receipt) error {
+	if r.SentAt.IsZero() {
+		r.SentAt = time.Now()
+	}
+	if err := writeReceipt(townRoot, r); err != nil {
+		return err
+	}
+	_, _ = PruneReceipts(townRoot, time.Now().Add(-ReceiptRetention))
+	return nil
+}
+
+func writeReceipt(townRoot string, r *Receipt) error {
+	if !validReceiptID(r.ID) {
+		return fmt.Errorf("invalid nudge ID %q", r.ID)
+	}
+	dir := receiptDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating receipt dir: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling receipt: %w", err)
+	}
+	tmp := filepath.Join(dir, r.ID+".json.tmp."+randomSuffix())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing receipt: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, r.ID+".json")); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing receipt: %w", err)
+	}
+	return nil
+}
+
+// LoadReceipt reads the receipt for nudge id.
+func LoadReceipt(townRoot, id string) (*Receipt, error) {
+	if !validReceiptID(id) {
+		return nil, fmt.Errorf("invalid nudge ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(receiptDir(townRoot), id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("nudge %s not found", id)
+		}
+		return nil, err
+	}
+	var r Receipt
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing receipt %s: %w", id, err)
+	}
+	return &r, nil
+}
+
+// Ack records that by acknowledged nudge id. Acknowledging twice keeps the
+// first acknowledgement. Returns the updated receipt.
+func Ack(townRoot, id, by string) (*Receipt, error) {
+	r, err := LoadReceipt(townRoot, id)
+	if err != nil {
+		return nil, err
+	}
+	if !r.AckedAt.IsZero() {
+		return r, nil
+	}
+	r.AckedAt = time.Now()
+	r.AckedBy = by
+	if err := writeReceipt(townRoot, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MarkEscalated records the escalation bead raised for receipt id's
+// missed acknowledgement.
+func MarkEscalated(townRoot, id, escalation string) error {
+	r, err := LoadReceipt(townRoot, id)
+	if err != nil {
+		return err
+	}
+	r.Escalation = escalation
+	return writeReceipt(townRoot, r)
+}
+
+// ListReceipts returns all receipts, oldest first. Unreadable files are
+// skipped.
+func ListReceipts(townRoot string) ([]*Receipt, error) {
+	entries, err := os.ReadDir(receiptDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading receipts: %w", err)
+	}
+	var receipts []*Receipt
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		if r, err := LoadReceipt(townRoot, id); err == nil {
+			receipts = append(receipts, r)
+		}
+	}
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i].SentAt.Before(receipts[j].SentAt)
+	})
+	return receipts, nil
+}
+
+// OverdueReceipts returns the receipts whose required acknowledgement is
+// past due at now, oldest first.
+func OverdueReceipts(townRoot string, now time.Time) ([]*Receipt, error) {
+	receipts, err := ListReceipts(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var overdue []*Receipt
+	for _, r := range receipts {
+		if r.Overdue(now) {
+			overdue = append(overdue, r)
+		}
+	}
+	return overdue, nil
+}
+
+// PruneReceipts removes receipts sent before cutoff and returns how many
+// were removed.
+func PruneReceipts(townRoot string, cutoff time.Time) (int, error) {
+	receipts, err := ListReceipts(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, r := range receipts {
+		if !r.SentAt.Before(cutoff) {
+			break
+		}
+		if err := os.Remove(filepath.Join(receiptDir(townRoot), r.ID+".json")); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
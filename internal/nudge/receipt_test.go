@@ -0,0 +1,76 @@
+package nudge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReceiptAckLifecycle(t *testing.T) {
+	townRoot := t.TempDir()
+	sent := time.Now().Add(-time.Hour)
+
+	acked := &Receipt{ID: NewReceiptID(), Target: "gastown/alpha", Status: ReceiptSent, SentAt: sent, AckBy: sent.Add(10 * time.Minute)}
+	missed := &Receipt{ID: NewReceiptID(), Target: "gastown/bravo", Status: ReceiptQueued, SentAt: sent, AckBy: sent.Add(10 * time.Minute)}
+	failed := &Receipt{ID: NewReceiptID(), Target: "gastown/charlie", Status: ReceiptFailed, SentAt: sent, AckBy: sent.Add(10 * time.Minute)}
+	noAck := &Receipt{ID: NewReceiptID(), Target: "gastown/delta", Status: ReceiptSent, SentAt: sent}
+	for _, r := range []*Receipt{acked, missed, failed, noAck} {
+		if err := RecordReceipt(townRoot, r); err != nil {
+			t.Fatalf("RecordReceipt: %v", err)
+		}
+	}
+
+	r, err := Ack(townRoot, acked.ID, "gastown/alpha")
+	if err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if r.AckedBy != "gastown/alpha" || r.AckedAt.IsZero() {
+		t.Errorf("ack not recorded: %+v", r)
+	}
+	first := r.AckedAt
+	if r, _ = Ack(townRoot, acked.ID, "someone-else"); !r.AckedAt.Equal(first) || r.AckedBy != "gastown/alpha" {
+		t.Errorf("second ack overwrote the first: %+v", r)
+	}
+
+	overdue, err := OverdueReceipts(townRoot, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overdue) != 1 || overdue[0].ID != missed.ID {
+		t.Fatalf("overdue = %+v, want only %s", overdue, missed.ID)
+	}
+
+	if err := MarkEscalated(townRoot, missed.ID, "hq-esc1"); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := LoadReceipt(townRoot, missed.ID); r.Escalation != "hq-esc1" {
+		t.Errorf("escalation = %q", r.Escalation)
+	}
+}
+
+func TestReceiptIDValidation(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, id := range []string{"", "nudge-", "../etc/passwd", "nudge-../x", "hq-abc"} {
+		if _, err := LoadReceipt(townRoot, id); err == nil {
+			t.Errorf("LoadReceipt(%q) accepted", id)
+		}
+	}
+	if _, err := Ack(townRoot, NewReceiptID(), "x"); err == nil {
+		t.Error("Ack of unknown nudge succeeded")
+	}
+}
+
+func TestPruneReceipts(t *testing.T) {
+	townRoot := t.TempDir()
+	old := &Receipt{ID: NewReceiptID(), Status: ReceiptSent, SentAt: time.Now().Add(-2 * ReceiptRetention)}
+	if err := writeReceipt(townRoot, old); err != nil {
+		t.Fatal(err)
+	}
+	// Recording a new receipt prunes the expired one.
+	if err := RecordReceipt(townRoot, &Receipt{ID: NewReceiptID(), Status: ReceiptSent}); err != nil {
+		t.Fatal(err)
+	}
+	receipts, _ := ListReceipts(townRoot)
+	if len(receipts) != 1 || receipts[0].ID == old.ID {
+		t.Errorf("receipts after prune = %+v", receipts)
+	}
+}
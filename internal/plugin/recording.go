@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 const bdCommandTimeout = 30 * time.Second
@@ -92,7 +93,7 @@ func (r *Recorder) RecordRun(record PluginRunRecord) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return "", fmt.Errorf("creating plugin run bead: %s: %w", stderr.String(), err)
 	}
 
@@ -160,7 +161,7 @@ func (r *Recorder) queryRuns(pluginName string, limit int, since string) ([]*Plu
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		// Empty result is OK (no runs found)
 		if stderr.Len() == 0 || stdout.String() == "[]\n" {
 			return nil, nil
@@ -148,6 +148,7 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 		// Use defaults
 		pool = NewNamePool(r.Path, r.Name)
 	}
+	pool.SetTownThemes(LoadTownThemes(filepath.Dir(r.Path)))
 	_ = pool.Load() // non-fatal: state file may not exist for new rigs
 
 	return &Manager{
@@ -460,6 +461,13 @@ func (m *Manager) ClonePath(name string) string {
 	return m.clonePath(name)
 }
 
+// writeWorkspaceEnv writes the polecat's .runtime/env.sh and .envrc into
+// its worktree.
+func (m *Manager) writeWorkspaceEnv(name, clonePath string) error {
+	env := rig.WorkspaceEnv(filepath.Dir(m.rig.Path), m.rig.Name, "polecat", name, clonePath)
+	return rig.WriteWorkspaceEnv(clonePath, env)
+}
+
 // exists checks if a polecat exists.
 func (m *Manager) exists(name string) bool {
 	_, err := os.Stat(m.polecatDir(name))
@@ -705,6 +713,11 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
 	}
 
+	// Write .runtime/env.sh and .envrc for humans working in the worktree
+	if err := m.writeWorkspaceEnv(name, clonePath); err != nil {
+		fmt.Printf("Warning: could not write workspace env: %v\n", err)
+	}
+
 	// Install runtime settings in the shared polecats parent directory.
 	// Settings are passed to Claude Code via --settings flag.
 	townRoot := filepath.Dir(m.rig.Path)
@@ -991,6 +1004,10 @@ func forceRemoveDir(dir string) error {
 // The rig prefix is added by SessionName to create full session names like "gt-<rig>-51".
 // After allocation, kills any lingering tmux session for the name (gt-pqf9x)
 // to prevent "session already running" errors when reusing names from dead polecats.
+//
+// Names reserved by other spawns or still owning a polecat Dolt branch are
+// skipped. The allocated name is reserved until its workspace exists, so a
+// concurrent spawn can't pick it in the window before the worktree is created.
 func (m *Manager) AllocateName() (string, error) {
 	// Acquire pool lock to prevent concurrent allocations from racing
 	fl, err := m.lockPool()
@@ -1002,6 +1019,9 @@ func (m *Manager) AllocateName() (string, error) {
 	// Reconcile without re-acquiring the pool lock
 	m.reconcilePoolInternal()
 
+	reservations := m.loadReservations()
+	m.markTakenNames(reservations)
+
 	name, err := m.namePool.Allocate()
 	if err != nil {
 		return "", err
@@ -1011,6 +1031,11 @@ func (m *Manager) AllocateName() (string, error) {
 		return "", fmt.Errorf("saving pool state: %w", err)
 	}
 
+	m.addReservation(reservations, name, reservationHolder())
+	if err := m.saveReservations(reservations); err != nil {
+		return "", fmt.Errorf("saving name reservation: %w", err)
+	}
+
 	// Kill any lingering tmux session for this name (gt-pqf9x).
 	// ReconcilePool kills sessions for names without directories, but a name
 	// can be allocated after its directory was cleaned up while the tmux session
@@ -1027,10 +1052,12 @@ func (m *Manager) AllocateName() (string, error) {
 }
 
 // ReleaseName releases a name back to the pool.
-// This is called when a polecat is removed.
+// This is called when a polecat is removed, or when a spawn fails after
+// allocating, so it also drops any reservation for the name.
 func (m *Manager) ReleaseName(name string) {
 	m.namePool.Release(name)
-	_ = m.namePool.Save() // non-fatal: state file update
+	_ = m.namePool.Save()          // non-fatal: state file update
+	_ = m.ReleaseReservation(name) // non-fatal: reservation expires anyway
 }
 
 // RepairWorktree repairs a stale polecat by removing it and creating a fresh worktree.
@@ -1177,6 +1204,10 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		fmt.Printf("Warning: could not update .gitignore: %v\n", err)
 	}
 
+	if err := m.writeWorkspaceEnv(name, newClonePath); err != nil {
+		fmt.Printf("Warning: could not write workspace env: %v\n", err)
+	}
+
 	// NOTE: Slash commands inherited from town level - no per-workspace copies needed.
 
 	// Create or reopen agent bead for ZFC compliance
@@ -6,8 +6,10 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -107,6 +109,10 @@ type NamePool struct {
 	// MaxSize is the maximum number of themed names before overflow.
 	MaxSize int `json:"max_size"`
 
+	// townThemes are the town's namepool_themes, consulted after
+	// CustomNames and before the built-in themes.
+	townThemes map[string][]string
+
 	// stateFile is the path to persist pool state.
 	stateFile string
 }
@@ -148,9 +154,11 @@ func NewNamePoolWithConfig(rigPath, rigName, theme string, customNames []string,
 func (p *NamePool) getNames() []string {
 	var names []string
 
-	// Custom names take precedence
+	// Custom names take precedence, then town themes, then built-in themes
 	if len(p.CustomNames) > 0 {
 		names = p.CustomNames
+	} else if themeNames, ok := p.townThemes[p.Theme]; ok && len(themeNames) > 0 {
+		names = themeNames
 	} else if themeNames, ok := BuiltinThemes[p.Theme]; ok {
 		// Look up built-in theme
 		names = themeNames
@@ -363,12 +371,15 @@ func (p *NamePool) SetTheme(theme string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if _, ok := BuiltinThemes[theme]; !ok {
-		return fmt.Errorf("unknown theme: %s (available: mad-max, minerals, wasteland)", theme)
+	newNames, ok := p.townThemes[theme]
+	if !ok {
+		newNames, ok = BuiltinThemes[theme]
+	}
+	if !ok {
+		return fmt.Errorf("unknown theme: %s (available: %s)", theme, strings.Join(ListThemesWith(p.townThemes), ", "))
 	}
 
 	// Preserve names that exist in both themes
-	newNames := BuiltinThemes[theme]
 	newInUse := make(map[string]bool)
 	for name := range p.InUse {
 		for _, n := range newNames {
@@ -395,6 +406,35 @@ func ListThemes() []string {
 	return themes
 }
 
+// ListThemesWith returns the built-in themes plus the given town themes.
+func ListThemesWith(townThemes map[string][]string) []string {
+	themes := ListThemes()
+	for theme := range townThemes {
+		if _, builtin := BuiltinThemes[theme]; !builtin {
+			themes = append(themes, theme)
+		}
+	}
+	sort.Strings(themes)
+	return themes
+}
+
+// LoadTownThemes returns the namepool_themes from the town settings, or nil
+// if none are configured or the settings can't be read.
+func LoadTownThemes(townRoot string) map[string][]string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.NamepoolThemes
+}
+
+// SetTownThemes makes the town's themes available to the pool.
+func (p *NamePool) SetTownThemes(themes map[string][]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.townThemes = themes
+}
+
 // ThemeForRig returns a deterministic theme for a rig based on its name.
 // This provides variety across rigs without requiring manual configuration.
 func ThemeForRig(rigName string) string {
@@ -412,6 +452,15 @@ func ThemeForRig(rigName string) string {
 
 // GetThemeNames returns the names in a specific theme.
 func GetThemeNames(theme string) ([]string, error) {
+	return GetThemeNamesWith(theme, nil)
+}
+
+// GetThemeNamesWith returns the names in a town or built-in theme. Town
+// themes take precedence over built-in themes of the same name.
+func GetThemeNamesWith(theme string, townThemes map[string][]string) ([]string, error) {
+	if names, ok := townThemes[theme]; ok {
+		return names, nil
+	}
 	if names, ok := BuiltinThemes[theme]; ok {
 		return names, nil
 	}
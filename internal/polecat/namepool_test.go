@@ -525,3 +525,68 @@ func TestNamePool_ReservedNamesInCustomNames(t *testing.T) {
 		t.Errorf("expected alpha, beta, gamma to be allocated, got %v", allocated)
 	}
 }
+
+func TestNamePool_TownThemes(t *testing.T) {
+	tmpDir := t.TempDir()
+	townThemes := map[string][]string{"birds": {"heron", "wren", "kestrel"}}
+
+	pool := NewNamePoolWithConfig(tmpDir, "testrig", "birds", nil, 10)
+	pool.SetTownThemes(townThemes)
+
+	name, err := pool.Allocate()
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if name != "heron" {
+		t.Errorf("first name = %q, want heron", name)
+	}
+
+	if err := pool.SetTheme("minerals"); err != nil {
+		t.Fatalf("SetTheme(minerals): %v", err)
+	}
+	if err := pool.SetTheme("birds"); err != nil {
+		t.Fatalf("SetTheme(birds): %v", err)
+	}
+	if err := pool.SetTheme("fish"); err == nil {
+		t.Error("SetTheme(fish) should fail for unknown theme")
+	}
+
+	themes := ListThemesWith(townThemes)
+	found := false
+	for _, theme := range themes {
+		if theme == "birds" {
+			found = true
+		}
+	}
+	if !found || len(themes) != len(BuiltinThemes)+1 {
+		t.Errorf("ListThemesWith = %v, want built-in themes plus birds", themes)
+	}
+
+	names, err := GetThemeNamesWith("birds", townThemes)
+	if err != nil || len(names) != 3 {
+		t.Errorf("GetThemeNamesWith(birds) = %v, %v", names, err)
+	}
+	if _, err := GetThemeNames("birds"); err == nil {
+		t.Error("GetThemeNames should not know town themes")
+	}
+}
+
+func TestLoadTownThemes(t *testing.T) {
+	townRoot := t.TempDir()
+	if themes := LoadTownThemes(townRoot); themes != nil {
+		t.Errorf("LoadTownThemes without settings = %v, want nil", themes)
+	}
+
+	settingsDir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"town-settings","version":1,"namepool_themes":{"birds":["heron","wren"]}}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	themes := LoadTownThemes(townRoot)
+	if len(themes["birds"]) != 2 {
+		t.Errorf("LoadTownThemes = %v, want birds theme with 2 names", themes)
+	}
+}
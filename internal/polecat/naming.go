@@ -0,0 +1,223 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultReservationTTL is how long a name reservation lasts. A spawn that
+// crashes between allocating a name and creating the workspace leaves its
+// reservation behind; it expires after this long.
+const DefaultReservationTTL = 10 * time.Minute
+
+// Reasons a polecat name is unavailable.
+const (
+	CollisionReserved    = "reserved for an infrastructure agent"
+	CollisionWorkspace   = "workspace exists"
+	CollisionSession     = "tmux session is running"
+	CollisionDoltBranch  = "Dolt branch with this name's prefix exists"
+	CollisionReservation = "reserved by another spawn"
+)
+
+// NameCollisionError reports why a polecat name can't be used in a rig.
+type NameCollisionError struct {
+	Rig    string
+	Name   string
+	Reason string
+}
+
+func (e *NameCollisionError) Error() string {
+	return fmt.Sprintf("polecat name %q unavailable in rig %s: %s", e.Name, e.Rig, e.Reason)
+}
+
+// NameReservation holds a name between allocation and the polecat's
+// workspace being created, so a concurrent spawn can't pick the same name.
+type NameReservation struct {
+	Name       string    `json:"name"`
+	Holder     string    `json:"holder"`
+	ReservedAt time.Time `json:"reserved_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// reservationsFile returns the path of the rig's name reservations.
+func (m *Manager) reservationsFile() string {
+	return filepath.Join(m.rig.Path, ".runtime", "namepool-reservations.json")
+}
+
+// loadReservations reads the rig's reservations, dropping expired ones and
+// ones whose workspace now exists (the workspace itself marks the name used).
+// Caller must hold the pool lock.
+func (m *Manager) loadReservations() map[string]NameReservation {
+	reservations := make(map[string]NameReservation)
+	data, err := os.ReadFile(m.reservationsFile())
+	if err != nil {
+		return reservations
+	}
+	var list []NameReservation
+	if err := json.Unmarshal(data, &list); err != nil {
+		return reservations
+	}
+	now := time.Now()
+	for _, r := range list {
+		if now.Before(r.ExpiresAt) && !m.exists(r.Name) {
+			reservations[r.Name] = r
+		}
+	}
+	return reservations
+}
+
+// saveReservations writes the rig's reservations. Caller must hold the pool lock.
+func (m *Manager) saveReservations(reservations map[string]NameReservation) error {
+	list := make([]NameReservation, 0, len(reservations))
+	for _, r := range reservations {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	if err := os.MkdirAll(filepath.Dir(m.reservationsFile()), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(m.reservationsFile(), list)
+}
+
+// polecatBranchNames returns the names that still have a Dolt branch in the
+// rig database. Returns nil if the rig has no database or it can't be queried.
+func (m *Manager) polecatBranchNames() map[string]bool {
+	townRoot := filepath.Dir(m.rig.Path)
+	if _, err := os.Stat(filepath.Join(doltserver.DefaultConfig(townRoot).DataDir, m.rig.Name)); err != nil {
+		return nil
+	}
+	names, err := doltserver.ListPolecatBranchNames(townRoot, m.rig.Name)
+	if err != nil {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
+
+// nameCollision returns why name is unavailable, or "" if it is free.
+// branches is the result of polecatBranchNames. Caller must hold the pool lock.
+func (m *Manager) nameCollision(name string, reservations map[string]NameReservation, branches map[string]bool) string {
+	switch {
+	case ReservedInfraAgentNames[name]:
+		return CollisionReserved
+	case m.exists(name):
+		return CollisionWorkspace
+	case reservations[name].Name != "":
+		return CollisionReservation
+	case branches[name]:
+		return CollisionDoltBranch
+	}
+	if m.tmux != nil {
+		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
+		if alive, _ := m.tmux.HasSession(sessionName); alive && !isSessionProcessDead(m.tmux, sessionName) {
+			return CollisionSession
+		}
+	}
+	return ""
+}
+
+// CheckName reports whether name can be given to a new polecat in this rig,
+// checking reserved names, workspaces, reservations, Dolt branch prefixes,
+// and live tmux sessions. Returns nil if the name is free.
+func (m *Manager) CheckName(name string) (*NameCollisionError, error) {
+	fl, err := m.lockPool()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if reason := m.nameCollision(name, m.loadReservations(), m.polecatBranchNames()); reason != "" {
+		return &NameCollisionError{Rig: m.rig.Name, Name: name, Reason: reason}, nil
+	}
+	return nil, nil
+}
+
+// ReserveName reserves a specific name for holder until the polecat's
+// workspace is created or DefaultReservationTTL passes. Returns a
+// *NameCollisionError if the name is unavailable.
+func (m *Manager) ReserveName(name, holder string) error {
+	fl, err := m.lockPool()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	reservations := m.loadReservations()
+	if reason := m.nameCollision(name, reservations, m.polecatBranchNames()); reason != "" {
+		return &NameCollisionError{Rig: m.rig.Name, Name: name, Reason: reason}
+	}
+	m.addReservation(reservations, name, holder)
+	return m.saveReservations(reservations)
+}
+
+// ReleaseReservation drops a name's reservation, e.g. when a spawn fails
+// before creating the workspace.
+func (m *Manager) ReleaseReservation(name string) error {
+	fl, err := m.lockPool()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	reservations := m.loadReservations()
+	if _, ok := reservations[name]; !ok {
+		return nil
+	}
+	delete(reservations, name)
+	return m.saveReservations(reservations)
+}
+
+// Reservations returns the rig's active name reservations, sorted by name.
+func (m *Manager) Reservations() ([]NameReservation, error) {
+	fl, err := m.lockPool()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	reservations := m.loadReservations()
+	list := make([]NameReservation, 0, len(reservations))
+	for _, r := range reservations {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (m *Manager) addReservation(reservations map[string]NameReservation, name, holder string) {
+	now := time.Now().UTC()
+	reservations[name] = NameReservation{
+		Name:       name,
+		Holder:     holder,
+		ReservedAt: now,
+		ExpiresAt:  now.Add(DefaultReservationTTL),
+	}
+}
+
+// markTakenNames marks pool names that are reserved or still have a Dolt
+// branch as in use, so Allocate skips them. Called after reconciling from
+// workspaces and sessions. Caller must hold the pool lock.
+func (m *Manager) markTakenNames(reservations map[string]NameReservation) {
+	for name := range reservations {
+		m.namePool.MarkInUse(name)
+	}
+	for name := range m.polecatBranchNames() {
+		m.namePool.MarkInUse(name)
+	}
+}
+
+// reservationHolder identifies this process in reservations.
+func reservationHolder() string {
+	return fmt.Sprintf("pid %d", os.Getpid())
+}
@@ -0,0 +1,132 @@
+package polecat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func newNamingTestManager(t *testing.T) *Manager {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "test-rig")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := &rig.Rig{Name: "test-rig", Path: root}
+	return NewManager(r, git.NewGit(root), nil)
+}
+
+func TestAllocateName_SkipsReservedAndReserves(t *testing.T) {
+	m := newNamingTestManager(t)
+
+	if err := m.ReserveName("furiosa", "other spawn"); err != nil {
+		t.Fatalf("ReserveName: %v", err)
+	}
+
+	name, err := m.AllocateName()
+	if err != nil {
+		t.Fatalf("AllocateName: %v", err)
+	}
+	if name == "furiosa" {
+		t.Error("AllocateName returned a name reserved by another spawn")
+	}
+
+	reservations, err := m.Reservations()
+	if err != nil {
+		t.Fatalf("Reservations: %v", err)
+	}
+	held := make(map[string]bool)
+	for _, r := range reservations {
+		held[r.Name] = true
+	}
+	if !held["furiosa"] || !held[name] {
+		t.Errorf("reservations = %v, want furiosa and %s", reservations, name)
+	}
+
+	// A second allocation must not hand out either reserved name.
+	second, err := m.AllocateName()
+	if err != nil {
+		t.Fatalf("AllocateName: %v", err)
+	}
+	if second == name || second == "furiosa" {
+		t.Errorf("second AllocateName = %q, collides with a reservation", second)
+	}
+
+	m.ReleaseName(name)
+	if collision, err := m.CheckName(name); err != nil || collision != nil {
+		t.Errorf("CheckName(%s) after release = %v, %v; want free", name, collision, err)
+	}
+}
+
+func TestCheckName_Collisions(t *testing.T) {
+	m := newNamingTestManager(t)
+
+	if err := os.MkdirAll(m.polecatDir("nux"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ReserveName("slit", "spawn"); err != nil {
+		t.Fatalf("ReserveName: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		reason string
+	}{
+		{"witness", CollisionReserved},
+		{"nux", CollisionWorkspace},
+		{"slit", CollisionReservation},
+		{"toast", ""},
+	}
+	for _, tt := range tests {
+		collision, err := m.CheckName(tt.name)
+		if err != nil {
+			t.Fatalf("CheckName(%s): %v", tt.name, err)
+		}
+		got := ""
+		if collision != nil {
+			got = collision.Reason
+		}
+		if got != tt.reason {
+			t.Errorf("CheckName(%s) reason = %q, want %q", tt.name, got, tt.reason)
+		}
+	}
+
+	var collision *NameCollisionError
+	if err := m.ReserveName("nux", "spawn"); !errors.As(err, &collision) {
+		t.Errorf("ReserveName(nux) = %v, want NameCollisionError", err)
+	}
+}
+
+func TestReservations_DropExpiredAndCreated(t *testing.T) {
+	m := newNamingTestManager(t)
+
+	now := time.Now().UTC()
+	list := []NameReservation{
+		{Name: "expired", Holder: "old", ReservedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
+		{Name: "created", Holder: "spawn", ReservedAt: now, ExpiresAt: now.Add(time.Minute)},
+		{Name: "pending", Holder: "spawn", ReservedAt: now, ExpiresAt: now.Add(time.Minute)},
+	}
+	if err := os.MkdirAll(filepath.Dir(m.reservationsFile()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.AtomicWriteJSON(m.reservationsFile(), list); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(m.polecatDir("created"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	reservations, err := m.Reservations()
+	if err != nil {
+		t.Fatalf("Reservations: %v", err)
+	}
+	if len(reservations) != 1 || reservations[0].Name != "pending" {
+		t.Errorf("Reservations = %v, want only pending", reservations)
+	}
+}
@@ -0,0 +1,169 @@
+package proc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeTable is a Table for tests. Processes are alive until terminated or
+// killed; every Terminate and Kill is recorded. Command lines, listening
+// ports, and open files are whatever SetCmdline, Listen, and Open set.
+// Safe for concurrent use.
+type FakeTable struct {
+	mu        sync.Mutex
+	alive     map[int]bool
+	calls     []string
+	cmdlines  map[int]string
+	listeners map[int]int
+	open      map[string][]int
+
+	// IgnoreTerminate keeps processes alive after Terminate, to exercise
+	// force-kill fallbacks.
+	IgnoreTerminate bool
+}
+
+// NewFakeTable returns a FakeTable with the given PIDs alive.
+func NewFakeTable(pids ...int) *FakeTable {
+	t := &FakeTable{
+		alive:     make(map[int]bool),
+		cmdlines:  make(map[int]string),
+		listeners: make(map[int]int),
+		open:      make(map[string][]int),
+	}
+	for _, pid := range pids {
+		t.alive[pid] = true
+	}
+	return t
+}
+
+// Start marks pid alive.
+func (t *FakeTable) Start(pid int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.alive[pid] = true
+}
+
+// Alive implements Table.
+func (t *FakeTable) Alive(pid int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.alive[pid]
+}
+
+// Terminate implements Table.
+func (t *FakeTable) Terminate(pid int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls = append(t.calls, fmt.Sprintf("terminate %d", pid))
+	if !t.alive[pid] {
+		return fmt.Errorf("process %d not found", pid)
+	}
+	if !t.IgnoreTerminate {
+		delete(t.alive, pid)
+	}
+	return nil
+}
+
+// Kill implements Table.
+func (t *FakeTable) Kill(pid int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls = append(t.calls, fmt.Sprintf("kill %d", pid))
+	if !t.alive[pid] {
+		return fmt.Errorf("process %d not found", pid)
+	}
+	delete(t.alive, pid)
+	return nil
+}
+
+// SetCmdline sets the command line Cmdline reports for pid.
+func (t *FakeTable) SetCmdline(pid int, cmdline string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cmdlines[pid] = cmdline
+}
+
+// Listen makes pid the listener on port.
+func (t *FakeTable) Listen(port, pid int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.listeners[port] = pid
+}
+
+// Open records pids as holding path open.
+func (t *FakeTable) Open(path string, pids ...int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.open[path] = append(t.open[path], pids...)
+}
+
+// Cmdline implements Table. Dead processes have no command line.
+func (t *FakeTable) Cmdline(pid int) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.alive[pid] {
+		return "", fmt.Errorf("process %d not found", pid)
+	}
+	return t.cmdlines[pid], nil
+}
+
+// ListenerPID implements Table. Listeners that have died are ignored.
+func (t *FakeTable) ListenerPID(port int) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if pid := t.listeners[port]; t.alive[pid] {
+		return pid, nil
+	}
+	return 0, nil
+}
+
+// OpenedBy implements Table, ignoring holders that have died.
+func (t *FakeTable) OpenedBy(path string) ([]int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var pids []int
+	for _, pid := range t.open[path] {
+		if t.alive[pid] {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// Calls returns the recorded Terminate and Kill calls ("terminate 42",
+// "kill 42"), in order.
+func (t *FakeTable) Calls() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.calls...)
+}
+
+// FakeRunner is a Runner for tests. Each command is recorded and answered
+// by Handler; with no Handler, commands succeed with no output. Safe for
+// concurrent use.
+type FakeRunner struct {
+	Handler func(c Cmd) (stdout, stderr []byte, err error)
+
+	mu    sync.Mutex
+	calls []Cmd
+}
+
+// Run implements Runner.
+func (r *FakeRunner) Run(_ context.Context, c Cmd) ([]byte, []byte, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, c)
+	handler := r.Handler
+	r.mu.Unlock()
+	if handler == nil {
+		return nil, nil, nil
+	}
+	return handler(c)
+}
+
+// Calls returns the commands run so far, in order.
+func (r *FakeRunner) Calls() []Cmd {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Cmd(nil), r.calls...)
+}
@@ -0,0 +1,18 @@
+//go:build unix && !linux && !darwin
+
+package proc
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Cmdline implements Table using ps, which every BSD has.
+func (OS) Cmdline(pid int) (string, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,44 @@
+//go:build darwin
+
+package proc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Cmdline implements Table using the kern.procargs2 sysctl, which holds
+// argc, the executable path, and then the arguments, NUL-separated.
+func (OS) Cmdline(pid int) (string, error) {
+	buf, err := unix.SysctlRaw("kern.procargs2", pid)
+	if err != nil {
+		return "", err
+	}
+	if len(buf) < 4 {
+		return "", fmt.Errorf("kern.procargs2 for %d: short buffer", pid)
+	}
+	argc := int(binary.LittleEndian.Uint32(buf[:4]))
+	rest := buf[4:]
+
+	// Skip the executable path and the NUL padding after it.
+	i := bytes.IndexByte(rest, 0)
+	if i < 0 {
+		return "", fmt.Errorf("kern.procargs2 for %d: malformed", pid)
+	}
+	rest = bytes.TrimLeft(rest[i:], "\x00")
+
+	args := make([]string, 0, argc)
+	for len(args) < argc && len(rest) > 0 {
+		i := bytes.IndexByte(rest, 0)
+		if i < 0 {
+			i = len(rest)
+		}
+		args = append(args, string(rest[:i]))
+		rest = rest[min(i+1, len(rest)):]
+	}
+	return strings.Join(args, " "), nil
+}
@@ -0,0 +1,117 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procRoot is where procfs is mounted. Tests point it at a fake tree.
+var procRoot = "/proc"
+
+// Cmdline implements Table by reading /proc/<pid>/cmdline.
+func (OS) Cmdline(pid int) (string, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return "", err
+	}
+	args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	return strings.Join(args, " "), nil
+}
+
+// ListenerPID implements Table by finding the listening socket for port in
+// /proc/net/tcp and tcp6, then the process holding that socket open.
+// Sockets of other users' processes can't be matched without privileges.
+func (OS) ListenerPID(port int) (int, error) {
+	inodes := make(map[string]bool)
+	for _, table := range []string{"tcp", "tcp6"} {
+		if err := listeningInodes(filepath.Join(procRoot, "net", table), port, inodes); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	if len(inodes) == 0 {
+		return 0, nil
+	}
+	targets := make(map[string]bool, len(inodes))
+	for inode := range inodes {
+		targets["socket:["+inode+"]"] = true
+	}
+	pids, err := pidsWithFD(targets)
+	if err != nil || len(pids) == 0 {
+		return 0, err
+	}
+	return pids[0], nil
+}
+
+// OpenedBy implements Table by scanning /proc/<pid>/fd for links to path.
+func (OS) OpenedBy(path string) ([]int, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	targets := map[string]bool{abs: true}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		targets[resolved] = true
+	}
+	return pidsWithFD(targets)
+}
+
+// listeningInodes adds the socket inodes listening on port in a
+// /proc/net/tcp-format table to inodes.
+func listeningInodes(table string, port int, inodes map[string]bool) error {
+	f, err := os.Open(table)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	const stateListen = "0A"
+	want := fmt.Sprintf(":%04X", port)
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != stateListen || !strings.HasSuffix(fields[1], want) {
+			continue
+		}
+		if fields[9] != "0" {
+			inodes[fields[9]] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// pidsWithFD returns the PIDs with an open file descriptor whose link
+// target is in targets, in /proc order. Processes whose fds can't be read
+// are skipped.
+func pidsWithFD(targets map[string]bool) ([]int, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procRoot, e.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			if link, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil && targets[link] {
+				pids = append(pids, pid)
+				break
+			}
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,95 @@
+//go:build linux
+
+package proc
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFakeProc builds a procfs tree with one process, 100, whose fd 3 is
+// socket inode 5555 listening on port 3307 and whose fd 4 is lockPath.
+func writeFakeProc(t *testing.T, lockPath string) string {
+	t.Helper()
+	root := t.TempDir()
+	fdDir := filepath.Join(root, "100", "fd")
+	if err := os.MkdirAll(fdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"100/cmdline": "dolt\x00sql-server\x00--port\x003307\x00",
+		"net/tcp": "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+			"   0: 0100007F:0CEB 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 5555 1 0000000000000000 100 0 0 10 0\n" +
+			"   1: 0100007F:0CEB 0100007F:A000 01 00000000:00000000 00:00000000 00000000  1000        0 6666 1 0000000000000000 100 0 0 10 0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("socket:[5555]", filepath.Join(fdDir, "3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(lockPath, filepath.Join(fdDir, "4")); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestOSInspectFakeProc(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "LOCK")
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	prev := procRoot
+	procRoot = writeFakeProc(t, lockPath)
+	defer func() { procRoot = prev }()
+
+	if got, err := (OS{}).Cmdline(100); err != nil || got != "dolt sql-server --port 3307" {
+		t.Errorf("Cmdline = %q, %v", got, err)
+	}
+	if pid, err := (OS{}).ListenerPID(3307); err != nil || pid != 100 {
+		t.Errorf("ListenerPID(3307) = %d, %v; want 100", pid, err)
+	}
+	if pid, err := (OS{}).ListenerPID(3308); err != nil || pid != 0 {
+		t.Errorf("ListenerPID(3308) = %d, %v; want 0", pid, err)
+	}
+	if pids, err := (OS{}).OpenedBy(lockPath); err != nil || len(pids) != 1 || pids[0] != 100 {
+		t.Errorf("OpenedBy = %v, %v; want [100]", pids, err)
+	}
+	if pids, err := (OS{}).OpenedBy(lockPath + ".other"); err != nil || len(pids) != 0 {
+		t.Errorf("OpenedBy(other) = %v, %v; want none", pids, err)
+	}
+}
+
+func TestOSInspectSelf(t *testing.T) {
+	cmdline, err := (OS{}).Cmdline(os.Getpid())
+	if err != nil || !strings.Contains(cmdline, filepath.Base(os.Args[0])) {
+		t.Errorf("Cmdline(self) = %q, %v", cmdline, err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+	if pid, err := (OS{}).ListenerPID(port); err != nil || pid != os.Getpid() {
+		t.Errorf("ListenerPID(%d) = %d, %v; want self", port, pid, err)
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "held"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if pids, err := (OS{}).OpenedBy(f.Name()); err != nil || len(pids) != 1 || pids[0] != os.Getpid() {
+		t.Errorf("OpenedBy = %v, %v; want self", pids, err)
+	}
+}
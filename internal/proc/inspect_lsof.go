@@ -0,0 +1,48 @@
+//go:build unix && !linux
+
+package proc
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Without procfs there is no portable way to map sockets and files to
+// processes from pure Go, so ListenerPID and OpenedBy fall back to lsof,
+// which ships with macOS and the BSDs.
+
+// ListenerPID implements Table using lsof.
+func (OS) ListenerPID(port int) (int, error) {
+	pids, err := lsofPIDs("-nP", "-iTCP:"+strconv.Itoa(port), "-sTCP:LISTEN", "-t")
+	if err != nil || len(pids) == 0 {
+		return 0, err
+	}
+	return pids[0], nil
+}
+
+// OpenedBy implements Table using lsof.
+func (OS) OpenedBy(path string) ([]int, error) {
+	return lsofPIDs("-t", path)
+}
+
+// lsofPIDs runs lsof with args and parses the PIDs it prints. lsof exits 1
+// when nothing matches, which is not an error here.
+func lsofPIDs(args ...string) ([]int, error) {
+	out, err := exec.Command("lsof", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pids []int
+	for _, line := range strings.Fields(string(out)) {
+		if pid, err := strconv.Atoi(line); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,98 @@
+//go:build windows
+
+package proc
+
+import (
+	"encoding/binary"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetExtendedTCPTable = windows.NewLazySystemDLL("iphlpapi.dll").NewProc("GetExtendedTcpTable")
+
+const (
+	tcpTableOwnerPIDListener = 3 // TCP_TABLE_OWNER_PID_LISTENER
+	mibTCPRowOwnerPIDSize    = 24
+	mibTCP6RowOwnerPIDSize   = 56
+)
+
+// Cmdline implements Table. Reading another process's arguments needs its
+// PEB, so only the executable path is returned.
+func (OS) Cmdline(pid int) (string, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer windows.CloseHandle(h) //nolint:errcheck // best-effort close
+
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(buf[:size]), nil
+}
+
+// ListenerPID implements Table using GetExtendedTcpTable for IPv4 and IPv6
+// listeners.
+func (OS) ListenerPID(port int) (int, error) {
+	for _, af := range []struct {
+		family  uint32
+		rowSize int
+		portOff int
+		pidOff  int
+	}{
+		{windows.AF_INET, mibTCPRowOwnerPIDSize, 8, 20},
+		{windows.AF_INET6, mibTCP6RowOwnerPIDSize, 20, 52},
+	} {
+		table, err := extendedTCPTable(af.family)
+		if err != nil {
+			return 0, err
+		}
+		if len(table) < 4 {
+			continue
+		}
+		n := int(binary.LittleEndian.Uint32(table[:4]))
+		for i := 0; i < n; i++ {
+			off := 4 + i*af.rowSize
+			if off+af.rowSize > len(table) {
+				break
+			}
+			row := table[off : off+af.rowSize]
+			// The port is in network byte order in the low 16 bits.
+			if int(binary.BigEndian.Uint16(row[af.portOff:af.portOff+2])) == port {
+				return int(binary.LittleEndian.Uint32(row[af.pidOff : af.pidOff+4])), nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// OpenedBy implements Table. Windows has no cheap way to list a file's
+// holders; an open file can't be deleted there anyway.
+func (OS) OpenedBy(path string) ([]int, error) {
+	return nil, ErrUnsupported
+}
+
+// extendedTCPTable returns the raw listener table for an address family.
+func extendedTCPTable(family uint32) ([]byte, error) {
+	var size uint32
+	for {
+		var buf []byte
+		var ptr uintptr
+		if size > 0 {
+			buf = make([]byte, size)
+			ptr = uintptr(unsafe.Pointer(&buf[0]))
+		}
+		r, _, _ := procGetExtendedTCPTable.Call(ptr, uintptr(unsafe.Pointer(&size)), 0, uintptr(family), tcpTableOwnerPIDListener, 0)
+		switch windows.Errno(r) {
+		case 0:
+			return buf, nil
+		case windows.ERROR_INSUFFICIENT_BUFFER:
+			continue
+		default:
+			return nil, windows.Errno(r)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package proc
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultLimit is how many bd and dolt processes one gt binary runs at once
+// unless SetLimit says otherwise. Each opens its own Dolt connection, so
+// mass patrol runs and daemon heartbeats would otherwise launch enough of
+// them to exhaust the server's max connections.
+const DefaultLimit = 8
+
+// limitedCommands are the commands that share the limit.
+var limitedCommands = map[string]bool{"bd": true, "dolt": true}
+
+var (
+	limitMu sync.Mutex
+	limit   = DefaultLimit
+	slots   = make(chan struct{}, DefaultLimit)
+)
+
+// SetLimit sets how many bd and dolt processes may run at once; n <= 0
+// restores DefaultLimit. Processes already running keep their slot in the
+// previous limit.
+func SetLimit(n int) {
+	if n <= 0 {
+		n = DefaultLimit
+	}
+	limitMu.Lock()
+	defer limitMu.Unlock()
+	if n != limit {
+		limit = n
+		slots = make(chan struct{}, n)
+	}
+}
+
+// Limit returns how many bd and dolt processes may run at once.
+func Limit() int {
+	limitMu.Lock()
+	defer limitMu.Unlock()
+	return limit
+}
+
+// Limited reports whether running name takes a slot of the limit.
+func Limited(name string) bool {
+	base := strings.TrimSuffix(filepath.Base(name), ".exe")
+	return limitedCommands[base]
+}
+
+// Acquire waits for a slot to run name and returns the func that frees it.
+// Commands other than bd and dolt don't wait. Returns ctx's error if it is
+// done first.
+func Acquire(ctx context.Context, name string) (release func(), err error) {
+	if !Limited(name) {
+		return func() {}, nil
+	}
+	limitMu.Lock()
+	s := slots
+	limitMu.Unlock()
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// acquireCmd waits for a slot to run cmd, giving up when ctx is done.
+func acquireCmd(ctx context.Context, cmd *exec.Cmd) (func(), error) {
+	return Acquire(ctx, cmd.Path)
+}
+
+// RunCmd is cmd.Run, waiting for a slot first if cmd runs bd or dolt. Use
+// it (or Output and CombinedOutput) instead of calling exec.Cmd's methods
+// directly when launching bd or dolt. For a cmd made with
+// exec.CommandContext use RunCmdContext, so the wait for a slot is bounded
+// by the same context as the command.
+func RunCmd(cmd *exec.Cmd) error {
+	return RunCmdContext(context.Background(), cmd)
+}
+
+// RunCmdContext is RunCmd, returning ctx's error if ctx is done before a
+// slot frees up. ctx should be the one cmd was made with.
+func RunCmdContext(ctx context.Context, cmd *exec.Cmd) error {
+	release, err := acquireCmd(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return cmd.Run()
+}
+
+// Output is cmd.Output under the limit; see RunCmd.
+func Output(cmd *exec.Cmd) ([]byte, error) {
+	return OutputContext(context.Background(), cmd)
+}
+
+// OutputContext is Output with the slot wait bounded by ctx; see
+// RunCmdContext.
+func OutputContext(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	release, err := acquireCmd(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return cmd.Output()
+}
+
+// CombinedOutput is cmd.CombinedOutput under the limit; see RunCmd.
+func CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	return CombinedOutputContext(context.Background(), cmd)
+}
+
+// CombinedOutputContext is CombinedOutput with the slot wait bounded by
+// ctx; see RunCmdContext.
+func CombinedOutputContext(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	release, err := acquireCmd(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return cmd.CombinedOutput()
+}
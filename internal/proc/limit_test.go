@@ -0,0 +1,116 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimited(t *testing.T) {
+	for name, want := range map[string]bool{
+		"bd":                true,
+		"/usr/local/bin/bd": true,
+		"dolt.exe":          true,
+		"git":               false,
+		"bdx":               false,
+	} {
+		if got := Limited(name); got != want {
+			t.Errorf("Limited(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestAcquireCapsConcurrency(t *testing.T) {
+	SetLimit(2)
+	defer SetLimit(0)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := Acquire(context.Background(), "bd")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrency = %d, want 2", got)
+	}
+}
+
+func TestAcquireHonorsContext(t *testing.T) {
+	SetLimit(1)
+	defer SetLimit(0)
+
+	release, err := Acquire(context.Background(), "dolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// Unlimited commands never wait.
+	other, err := Acquire(context.Background(), "git")
+	if err != nil {
+		t.Fatalf("Acquire(git) = %v", err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, "bd"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire with a full limit = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestOutputContextBoundsSlotWait(t *testing.T) {
+	SetLimit(1)
+	defer SetLimit(0)
+
+	release, err := Acquire(context.Background(), "bd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// The slot wait gives up with the command's context; bd never runs.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "bd", "version")
+	if _, err := OutputContext(ctx, cmd); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("OutputContext with a full limit = %v, want DeadlineExceeded", err)
+	}
+	if cmd.Process != nil {
+		t.Error("command started without a slot")
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	defer SetLimit(0)
+	SetLimit(3)
+	if Limit() != 3 {
+		t.Errorf("Limit() = %d, want 3", Limit())
+	}
+	SetLimit(-1)
+	if Limit() != DefaultLimit {
+		t.Errorf("Limit() after SetLimit(-1) = %d, want %d", Limit(), DefaultLimit)
+	}
+}
@@ -0,0 +1,78 @@
+// Package proc abstracts the process table and command execution so code
+// that checks, signals, or runs processes can be tested without real ones.
+//
+// Production code holds a Table and a Runner, defaulting to OS and Exec.
+// Tests substitute FakeTable and FakeRunner. For replaying recorded output
+// of external tools, see the execrec package.
+package proc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// ErrUnsupported is returned by Table methods the platform cannot answer.
+var ErrUnsupported = errors.New("not supported on this platform")
+
+// Table looks up, inspects, and signals processes by PID.
+type Table interface {
+	// Alive reports whether pid is a running process.
+	Alive(pid int) bool
+
+	// Terminate asks pid to exit: SIGTERM on Unix, a kill on Windows.
+	Terminate(pid int) error
+
+	// Kill stops pid immediately.
+	Kill(pid int) error
+
+	// Cmdline returns pid's command line with arguments separated by
+	// spaces. On Windows only the executable path is available.
+	Cmdline(pid int) (string, error)
+
+	// ListenerPID returns the PID of the process listening on local TCP
+	// port, or 0 if nothing is.
+	ListenerPID(port int) (int, error)
+
+	// OpenedBy returns the PIDs of processes that have path open.
+	OpenedBy(path string) ([]int, error)
+}
+
+// Cmd is a command to run to completion.
+type Cmd struct {
+	Name string
+	Args []string
+	Dir  string
+	Env  []string // nil inherits the current environment
+}
+
+// Runner runs commands to completion.
+type Runner interface {
+	// Run runs c until it exits or ctx is done and returns its stdout and
+	// stderr. A non-zero exit returns a non-nil error along with the output.
+	Run(ctx context.Context, c Cmd) (stdout, stderr []byte, err error)
+}
+
+// Exec runs commands for real.
+type Exec struct{}
+
+// Run implements Runner. bd and dolt commands wait for a slot of the
+// process limit (see SetLimit) first.
+func (Exec) Run(ctx context.Context, c Cmd) ([]byte, []byte, error) {
+	release, err := Acquire(ctx, c.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...) //nolint:gosec // G204: callers construct args internally
+	cmd.Dir = c.Dir
+	if c.Env != nil {
+		cmd.Env = c.Env
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
@@ -0,0 +1,104 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFakeTable(t *testing.T) {
+	table := NewFakeTable(10, 20)
+
+	if !table.Alive(10) || table.Alive(30) {
+		t.Fatal("Alive does not reflect the starting PIDs")
+	}
+	if err := table.Terminate(10); err != nil {
+		t.Fatalf("Terminate: %v", err)
+	}
+	if table.Alive(10) {
+		t.Error("process alive after Terminate")
+	}
+	if err := table.Kill(10); err == nil {
+		t.Error("Kill of a dead process should fail")
+	}
+
+	table.IgnoreTerminate = true
+	_ = table.Terminate(20)
+	if !table.Alive(20) {
+		t.Error("IgnoreTerminate should keep the process alive")
+	}
+	_ = table.Kill(20)
+
+	want := []string{"terminate 10", "kill 10", "terminate 20", "kill 20"}
+	if got := table.Calls(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Calls = %v, want %v", got, want)
+	}
+}
+
+func TestFakeTableInspect(t *testing.T) {
+	table := NewFakeTable(10)
+	table.SetCmdline(10, "dolt sql-server")
+	table.Listen(3307, 10)
+	table.Open("/lock", 10, 20)
+
+	if got, err := table.Cmdline(10); err != nil || got != "dolt sql-server" {
+		t.Errorf("Cmdline = %q, %v", got, err)
+	}
+	if pid, _ := table.ListenerPID(3307); pid != 10 {
+		t.Errorf("ListenerPID = %d, want 10", pid)
+	}
+	if pids, _ := table.OpenedBy("/lock"); len(pids) != 1 || pids[0] != 10 {
+		t.Errorf("OpenedBy = %v, want [10] (20 is not alive)", pids)
+	}
+
+	_ = table.Kill(10)
+	if _, err := table.Cmdline(10); err == nil {
+		t.Error("Cmdline of a dead process should fail")
+	}
+	if pid, _ := table.ListenerPID(3307); pid != 0 {
+		t.Errorf("ListenerPID after kill = %d, want 0", pid)
+	}
+}
+
+func TestFakeRunner(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := &FakeRunner{Handler: func(c Cmd) ([]byte, []byte, error) {
+		if c.Name == "fail" {
+			return nil, []byte("bad"), errBoom
+		}
+		return []byte(strings.Join(c.Args, " ")), nil, nil
+	}}
+
+	out, _, err := r.Run(context.Background(), Cmd{Name: "echo", Args: []string{"a", "b"}})
+	if err != nil || string(out) != "a b" {
+		t.Errorf("Run = %q, %v", out, err)
+	}
+	if _, stderr, err := r.Run(context.Background(), Cmd{Name: "fail"}); !errors.Is(err, errBoom) || string(stderr) != "bad" {
+		t.Errorf("Run(fail) = %q, %v", stderr, err)
+	}
+	if calls := r.Calls(); len(calls) != 2 || calls[1].Name != "fail" {
+		t.Errorf("Calls = %v", calls)
+	}
+}
+
+func TestOSAliveSelf(t *testing.T) {
+	if !(OS{}).Alive(os.Getpid()) {
+		t.Error("current process should be alive")
+	}
+}
+
+func TestExecRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	stdout, stderr, err := Exec{}.Run(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "echo out; echo err >&2; exit 3"}})
+	if err == nil {
+		t.Fatal("expected exit error")
+	}
+	if strings.TrimSpace(string(stdout)) != "out" || strings.TrimSpace(string(stderr)) != "err" {
+		t.Errorf("stdout=%q stderr=%q", stdout, stderr)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package proc
+
+import (
+	"os"
+	"syscall"
+)
+
+// OS is the operating system's process table.
+type OS struct{}
+
+// Alive implements Table. On Unix, FindProcess always succeeds, so liveness
+// is checked by sending signal 0.
+func (OS) Alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// Terminate implements Table by sending SIGTERM.
+func (OS) Terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGTERM)
+}
+
+// Kill implements Table by sending SIGKILL.
+func (OS) Kill(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package proc
+
+import "os"
+
+// OS is the operating system's process table.
+type OS struct{}
+
+// Alive implements Table. On Windows, FindProcess opens a handle to the
+// process and fails if it has exited.
+func (OS) Alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+// Terminate implements Table. Windows has no SIGTERM, so this kills.
+func (OS) Terminate(pid int) error {
+	return OS{}.Kill(pid)
+}
+
+// Kill implements Table.
+func (OS) Kill(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
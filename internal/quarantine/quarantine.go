@@ -0,0 +1,257 @@
+// Package quarantine isolates misbehaving polecats without destroying the
+// evidence of what they did.
+//
+// A quarantined polecat is frozen rather than nuked: its processes are
+// stopped, its Dolt branch is renamed so it can no longer write beads, and
+// its workspace and transcript are snapshotted for review. The record at
+// <town>/daemon/quarantine/<rig>/<polecat>/quarantine.json marks the polecat
+// as quarantined; while it exists the daemon will not restart the polecat
+// and the witness will not nuke it. Releasing a polecat moves the record
+// aside but keeps its snapshots.
+package quarantine
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// AgentState is the agent_state recorded on a quarantined polecat's bead.
+const AgentState = "quarantined"
+
+// recordFile is the name of the active quarantine record.
+const recordFile = "quarantine.json"
+
+// timeFormat is used for snapshot and released-record names; it sorts
+// chronologically.
+const timeFormat = "20060102T150405Z"
+
+// Record describes a quarantined polecat.
+type Record struct {
+	Rig              string    `json:"rig"`
+	Polecat          string    `json:"polecat"`
+	Reason           string    `json:"reason,omitempty"`
+	By               string    `json:"by,omitempty"`
+	Time             time.Time `json:"time"`
+	Session          string    `json:"session,omitempty"`
+	HookBead         string    `json:"hook_bead,omitempty"`
+	AgentBead        string    `json:"agent_bead,omitempty"`
+	Frozen           bool      `json:"frozen"`                      // session processes were stopped
+	DoltBranch       string    `json:"dolt_branch,omitempty"`       // branch the polecat was writing to
+	QuarantineBranch string    `json:"quarantine_branch,omitempty"` // what DoltBranch was renamed to
+	Snapshot         string    `json:"snapshot,omitempty"`          // directory holding the evidence
+
+	// Path is where the record is stored (set by Save, Load and List).
+	Path string `json:"-"`
+}
+
+// Dir returns the directory holding all quarantine records.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "quarantine")
+}
+
+func polecatDir(townRoot, rig, polecat string) string {
+	return filepath.Join(Dir(townRoot), rig, polecat)
+}
+
+// SnapshotDir returns the directory a record's evidence is written to.
+func SnapshotDir(townRoot string, r *Record) string {
+	return filepath.Join(polecatDir(townRoot, r.Rig, r.Polecat), r.Time.UTC().Format(timeFormat))
+}
+
+// Save writes the record, marking the polecat quarantined.
+func Save(townRoot string, r *Record) error {
+	if r.Rig == "" || r.Polecat == "" {
+		return fmt.Errorf("quarantine record needs a rig and polecat")
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	dir := polecatDir(townRoot, r.Rig, r.Polecat)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating quarantine dir: %w", err)
+	}
+	path := filepath.Join(dir, recordFile)
+	if err := util.AtomicWriteJSON(path, r); err != nil {
+		return fmt.Errorf("writing quarantine record: %w", err)
+	}
+	r.Path = path
+	return nil
+}
+
+// Load reads the active record for a polecat. Returns an error wrapping
+// os.ErrNotExist if the polecat is not quarantined.
+func Load(townRoot, rig, polecat string) (*Record, error) {
+	return load(filepath.Join(polecatDir(townRoot, rig, polecat), recordFile))
+}
+
+func load(path string) (*Record, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the quarantine dir
+	if err != nil {
+		return nil, err
+	}
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	r.Path = path
+	return &r, nil
+}
+
+// IsQuarantined reports whether a polecat has an active quarantine record.
+// An unreadable record still counts: failing open would let the daemon
+// restart a polecat someone meant to freeze.
+func IsQuarantined(townRoot, rig, polecat string) bool {
+	_, err := os.Stat(filepath.Join(polecatDir(townRoot, rig, polecat), recordFile))
+	return err == nil
+}
+
+// List returns active records, newest first. If rig is non-empty, only that
+// rig's polecats are returned. Unreadable records are skipped.
+func List(townRoot, rig string) ([]*Record, error) {
+	root := Dir(townRoot)
+	if rig != "" {
+		root = filepath.Join(root, rig)
+	}
+	var records []*Record
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || d.Name() != recordFile {
+			return nil
+		}
+		if r, err := load(path); err == nil {
+			records = append(records, r)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.After(records[j].Time)
+	})
+	return records, nil
+}
+
+// Release lifts a polecat's quarantine by renaming its record to
+// released-<time>.json. Snapshots are left in place for later review.
+func Release(townRoot, rig, polecat string) (*Record, error) {
+	r, err := Load(townRoot, rig, polecat)
+	if err != nil {
+		return nil, err
+	}
+	released := filepath.Join(filepath.Dir(r.Path), "released-"+time.Now().UTC().Format(timeFormat)+".json")
+	if err := os.Rename(r.Path, released); err != nil {
+		return nil, fmt.Errorf("releasing quarantine: %w", err)
+	}
+	r.Path = released
+	return r, nil
+}
+
+// WriteTranscript saves a session's scrollback into a snapshot directory.
+func WriteTranscript(dir, transcript string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "transcript.txt"), []byte(transcript), 0644) //nolint:gosec // G306: evidence is not secret
+}
+
+// SnapshotWorkspace preserves a polecat's workspace in dir: a gzipped
+// tarball of the whole tree plus its git status, diff against HEAD and
+// recent log, so the changes can be reviewed without unpacking. Git output
+// is best-effort; the tarball is not.
+func SnapshotWorkspace(workspace, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	gitOutputs := map[string][]string{
+		"git-status.txt": {"status", "--porcelain=v1", "--branch"},
+		"git-diff.txt":   {"diff", "HEAD"},
+		"git-log.txt":    {"log", "--oneline", "-n", "50"},
+	}
+	for name, args := range gitOutputs {
+		cmd := exec.Command("git", append([]string{"-C", workspace}, args...)...) //nolint:gosec // G204: fixed git subcommands
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			out = append(out, []byte("\nerror: "+err.Error()+"\n")...)
+		}
+		_ = os.WriteFile(filepath.Join(dir, name), out, 0644) //nolint:gosec // G306: evidence is not secret
+	}
+	return writeTarball(filepath.Join(dir, "workspace.tar.gz"), workspace)
+}
+
+func writeTarball(out, root string) error {
+	tmp := out + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	werr := writeTarDir(tw, root)
+	if err := errors.Join(werr, tw.Close(), gz.Close(), f.Close()); err != nil {
+		return fmt.Errorf("archiving %s: %w", root, err)
+	}
+	return os.Rename(tmp, out)
+}
+
+// writeTarDir adds everything under root to tw, with names relative to
+// root. Symlinks are recorded as links, not followed.
+func writeTarDir(tw *tar.Writer, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		var link string
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case !info.IsDir() && !info.Mode().IsRegular():
+			return nil // Sockets, pipes, devices
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(path) //nolint:gosec // G304: walking a polecat workspace
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+}
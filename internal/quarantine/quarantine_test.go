@@ -0,0 +1,110 @@
+package quarantine
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRelease(t *testing.T) {
+	townRoot := t.TempDir()
+	if IsQuarantined(townRoot, "gastown", "Toast") {
+		t.Fatal("IsQuarantined before Save = true")
+	}
+	if _, err := Load(townRoot, "gastown", "Toast"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load before Save: err = %v, want ErrNotExist", err)
+	}
+
+	r := &Record{Rig: "gastown", Polecat: "Toast", Reason: "spamming beads", DoltBranch: "polecat-toast-1700000000"}
+	if err := Save(townRoot, r); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !IsQuarantined(townRoot, "gastown", "Toast") {
+		t.Fatal("IsQuarantined after Save = false")
+	}
+	got, err := Load(townRoot, "gastown", "Toast")
+	if err != nil || got.Reason != "spamming beads" || got.DoltBranch != r.DoltBranch {
+		t.Fatalf("Load = %+v, %v", got, err)
+	}
+
+	older := &Record{Rig: "beads", Polecat: "Nux", Time: r.Time.Add(-time.Hour)}
+	if err := Save(townRoot, older); err != nil {
+		t.Fatal(err)
+	}
+	snap := SnapshotDir(townRoot, r)
+	if err := WriteTranscript(snap, "rm -rf everything\n"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := List(townRoot, "")
+	if err != nil || len(records) != 2 || records[0].Polecat != "Toast" {
+		t.Fatalf("List = %+v, %v; want Toast then Nux", records, err)
+	}
+	if records, _ := List(townRoot, "beads"); len(records) != 1 || records[0].Polecat != "Nux" {
+		t.Errorf("List(beads) = %+v", records)
+	}
+
+	if _, err := Release(townRoot, "gastown", "Toast"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if IsQuarantined(townRoot, "gastown", "Toast") {
+		t.Error("IsQuarantined after Release = true")
+	}
+	if _, err := os.Stat(filepath.Join(snap, "transcript.txt")); err != nil {
+		t.Errorf("Release removed the snapshot: %v", err)
+	}
+	if records, _ := List(townRoot, ""); len(records) != 1 {
+		t.Errorf("List after Release = %+v, want only Nux", records)
+	}
+}
+
+func TestSnapshotWorkspace(t *testing.T) {
+	ws := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(ws, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ws, "src", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src/main.go", filepath.Join(ws, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "snap")
+	if err := SnapshotWorkspace(ws, dir); err != nil {
+		t.Fatalf("SnapshotWorkspace: %v", err)
+	}
+	for _, name := range []string{"git-status.txt", "git-diff.txt", "git-log.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "workspace.tar.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	sort.Strings(names)
+	want := []string{"link", "src/", "src/main.go"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Errorf("tarball entries = %v, want %v", names, want)
+	}
+}
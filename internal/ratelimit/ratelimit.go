@@ -0,0 +1,335 @@
+// Package ratelimit throttles nudges, mail and escalations per recipient.
+//
+// Aggressive patrols can message a struggling agent every cycle, burying
+// its context in repeats. Every sender goes through Allow, which applies
+// two limits per recipient:
+//
+//   - a cooldown per message kind: after a nudge to an agent, further
+//     nudges to it are suppressed until the cooldown has passed
+//   - deduplication: a message identical to one already sent to the same
+//     recipient within the dedup window is suppressed
+//
+// The town's settings/config.json tunes both:
+//
+//	"rate_limit": {
+//	  "cooldowns": {"nudge": "30s", "mail": "0", "escalation": "5m"},
+//	  "dedup_window": "10m"
+//	}
+//
+// Suppressed messages are counted per recipient and day so patrol digests
+// can report how much noise was held back.
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Kind is a class of message with its own cooldown.
+type Kind string
+
+// Message kinds.
+const (
+	KindNudge      Kind = "nudge"
+	KindMail       Kind = "mail"
+	KindEscalation Kind = "escalation"
+)
+
+// Reasons a message is suppressed.
+const (
+	ReasonCooldown  = "cooldown"
+	ReasonDuplicate = "duplicate"
+)
+
+// DefaultCooldowns are used for kinds the town doesn't configure.
+var DefaultCooldowns = map[Kind]time.Duration{
+	KindNudge:      30 * time.Second,
+	KindMail:       0,
+	KindEscalation: 5 * time.Minute,
+}
+
+// DefaultDedupWindow is used when the town doesn't configure one.
+const DefaultDedupWindow = 10 * time.Minute
+
+// suppressedRetentionDays is how many days of suppressed counts are kept.
+const suppressedRetentionDays = 7
+
+// dayFormat keys suppressed counts by local date.
+const dayFormat = "2006-01-02"
+
+var clk clock.Clock = clock.Real{}
+
+// SetClock replaces the clock used for cooldowns and windows (for tests).
+func SetClock(c clock.Clock) (restore func()) {
+	prev := clk
+	clk = c
+	return func() { clk = prev }
+}
+
+// Limits are the effective rate limits for a town.
+type Limits struct {
+	Cooldowns   map[Kind]time.Duration
+	DedupWindow time.Duration
+}
+
+// LimitsFrom resolves a rate limit config to effective limits. A nil config
+// gives the defaults; a disabled one gives no limits.
+func LimitsFrom(cfg *config.RateLimitConfig) Limits {
+	limits := Limits{Cooldowns: make(map[Kind]time.Duration), DedupWindow: DefaultDedupWindow}
+	if cfg != nil && cfg.Disabled {
+		limits.DedupWindow = 0
+		return limits
+	}
+	for kind, d := range DefaultCooldowns {
+		limits.Cooldowns[kind] = d
+	}
+	if cfg == nil {
+		return limits
+	}
+	for kind, s := range cfg.Cooldowns {
+		limits.Cooldowns[Kind(kind)] = parseDuration(s, limits.Cooldowns[Kind(kind)])
+	}
+	if cfg.DedupWindow != "" {
+		limits.DedupWindow = parseDuration(cfg.DedupWindow, DefaultDedupWindow)
+	}
+	return limits
+}
+
+// parseDuration parses a non-negative duration, returning fallback for
+// invalid values. "0" is valid and disables a limit.
+func parseDuration(s string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
+}
+
+// LoadLimits reads the rate limit section of the town settings.
+func LoadLimits(townRoot string) (Limits, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return Limits{}, fmt.Errorf("loading town settings: %w", err)
+	}
+	return LimitsFrom(settings.RateLimit), nil
+}
+
+// Decision is the outcome of Allow.
+type Decision struct {
+	Allowed    bool
+	Reason     string        // ReasonCooldown or ReasonDuplicate when suppressed
+	RetryAfter time.Duration // how long until the same message would be allowed
+}
+
+// String describes a suppression for display.
+func (d Decision) String() string {
+	switch d.Reason {
+	case ReasonCooldown:
+		return fmt.Sprintf("rate limited, retry in %s", d.RetryAfter.Round(time.Second))
+	case ReasonDuplicate:
+		return fmt.Sprintf("duplicate of a recent message, retry in %s", d.RetryAfter.Round(time.Second))
+	}
+	return "allowed"
+}
+
+type sentRecord struct {
+	Kind Kind      `json:"kind"`
+	Hash string    `json:"hash"`
+	At   time.Time `json:"at"`
+}
+
+type recipientState struct {
+	Sent       []sentRecord            `json:"sent,omitempty"`
+	Suppressed map[string]map[Kind]int `json:"suppressed,omitempty"` // day -> kind -> count
+}
+
+type store struct {
+	Recipients map[string]*recipientState `json:"recipients"`
+}
+
+// storePath returns the path of the limiter state.
+func storePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "ratelimit.json")
+}
+
+// withStore runs fn with the limiter state loaded under an exclusive lock
+// and saves the result. State older than any limit needs is pruned on
+// every access.
+func withStore(townRoot string, limits Limits, fn func(s *store)) error {
+	path := storePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking rate limiter: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	s := store{Recipients: make(map[string]*recipientState)}
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 { //nolint:gosec // G304: path is constructed internally
+		// A corrupt state file only costs us rate-limit history; start over.
+		_ = json.Unmarshal(data, &s)
+		if s.Recipients == nil {
+			s.Recipients = make(map[string]*recipientState)
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading rate limiter state: %w", err)
+	}
+
+	prune(&s, limits, clk.Now())
+	fn(&s)
+	return util.AtomicWriteJSON(path, &s)
+}
+
+// prune drops sends that no limit can still match and suppressed counts
+// past retention.
+func prune(s *store, limits Limits, now time.Time) {
+	keep := limits.DedupWindow
+	for _, d := range limits.Cooldowns {
+		if d > keep {
+			keep = d
+		}
+	}
+	oldestDay := now.AddDate(0, 0, -suppressedRetentionDays).Format(dayFormat)
+	for name, st := range s.Recipients {
+		live := st.Sent[:0]
+		for _, r := range st.Sent {
+			if now.Sub(r.At) < keep {
+				live = append(live, r)
+			}
+		}
+		st.Sent = live
+		for day := range st.Suppressed {
+			if day < oldestDay {
+				delete(st.Suppressed, day)
+			}
+		}
+		if len(st.Sent) == 0 && len(st.Suppressed) == 0 {
+			delete(s.Recipients, name)
+		}
+	}
+}
+
+func hashMessage(message string) string {
+	sum := sha256.Sum256([]byte(message))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Allow decides whether a message of kind may be sent to recipient now,
+// under the town's configured limits, and records the outcome: an allowed
+// message starts the recipient's cooldown and dedup window, a suppressed
+// one is counted. Callers that bypass the limiter (e.g. --force, urgent
+// messages) should not call Allow at all.
+func Allow(townRoot string, kind Kind, recipient, message string) (Decision, error) {
+	limits, err := LoadLimits(townRoot)
+	if err != nil {
+		return Decision{Allowed: true}, err
+	}
+	return AllowWith(townRoot, limits, kind, recipient, message)
+}
+
+// AllowWith is Allow with explicit limits.
+func AllowWith(townRoot string, limits Limits, kind Kind, recipient, message string) (Decision, error) {
+	if limits.DedupWindow == 0 && limits.Cooldowns[kind] == 0 {
+		return Decision{Allowed: true}, nil
+	}
+	now := clk.Now()
+	hash := hashMessage(message)
+	var d Decision
+	err := withStore(townRoot, limits, func(s *store) {
+		st := s.Recipients[recipient]
+		if st == nil {
+			st = &recipientState{}
+			s.Recipients[recipient] = st
+		}
+		d = decide(st, limits, now, kind, hash)
+		if d.Allowed {
+			st.Sent = append(st.Sent, sentRecord{Kind: kind, Hash: hash, At: now})
+			return
+		}
+		day := now.Format(dayFormat)
+		if st.Suppressed == nil {
+			st.Suppressed = make(map[string]map[Kind]int)
+		}
+		if st.Suppressed[day] == nil {
+			st.Suppressed[day] = make(map[Kind]int)
+		}
+		st.Suppressed[day][kind]++
+	})
+	if err != nil {
+		// Never lose a message because the limiter's state is unavailable.
+		return Decision{Allowed: true}, err
+	}
+	return d, nil
+}
+
+func decide(st *recipientState, limits Limits, now time.Time, kind Kind, hash string) Decision {
+	var lastOfKind time.Time
+	for _, r := range st.Sent {
+		if r.Kind != kind {
+			continue
+		}
+		if limits.DedupWindow > 0 && r.Hash == hash && now.Sub(r.At) < limits.DedupWindow {
+			return Decision{Reason: ReasonDuplicate, RetryAfter: limits.DedupWindow - now.Sub(r.At)}
+		}
+		if r.At.After(lastOfKind) {
+			lastOfKind = r.At
+		}
+	}
+	if cooldown := limits.Cooldowns[kind]; cooldown > 0 && !lastOfKind.IsZero() && now.Sub(lastOfKind) < cooldown {
+		return Decision{Reason: ReasonCooldown, RetryAfter: cooldown - now.Sub(lastOfKind)}
+	}
+	return Decision{Allowed: true}
+}
+
+// SuppressedCount is how many messages to one recipient were suppressed.
+type SuppressedCount struct {
+	Recipient string       `json:"recipient"`
+	Total     int          `json:"total"`
+	ByKind    map[Kind]int `json:"by_kind"`
+}
+
+// Suppressed returns per-recipient suppressed counts for the local date of
+// day, sorted by recipient. Only recipients with suppressions are returned.
+func Suppressed(townRoot string, day time.Time) ([]SuppressedCount, error) {
+	data, err := os.ReadFile(storePath(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading rate limiter state: %w", err)
+	}
+	var s store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing rate limiter state: %w", err)
+	}
+
+	key := day.Format(dayFormat)
+	var counts []SuppressedCount
+	for recipient, st := range s.Recipients {
+		byKind := st.Suppressed[key]
+		if len(byKind) == 0 {
+			continue
+		}
+		c := SuppressedCount{Recipient: recipient, ByKind: byKind}
+		for _, n := range byKind {
+			c.Total += n
+		}
+		counts = append(counts, c)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Recipient < counts[j].Recipient })
+	return counts, nil
+}
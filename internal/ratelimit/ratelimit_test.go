@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestAllow_CooldownAndDedup(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local))
+	defer SetClock(fake)()
+	townRoot := t.TempDir()
+	limits := LimitsFrom(&config.RateLimitConfig{
+		Cooldowns:   map[string]string{"nudge": "1m"},
+		DedupWindow: "10m",
+	})
+
+	allow := func(kind Kind, recipient, msg string) Decision {
+		t.Helper()
+		d, err := AllowWith(townRoot, limits, kind, recipient, msg)
+		if err != nil {
+			t.Fatalf("AllowWith: %v", err)
+		}
+		return d
+	}
+
+	if d := allow(KindNudge, "gastown/Toast", "check your mail"); !d.Allowed {
+		t.Fatalf("first nudge suppressed: %+v", d)
+	}
+	if d := allow(KindNudge, "gastown/Toast", "different message"); d.Allowed || d.Reason != ReasonCooldown {
+		t.Errorf("second nudge within cooldown = %+v, want cooldown", d)
+	}
+	if d := allow(KindNudge, "gastown/Nux", "check your mail"); !d.Allowed {
+		t.Errorf("nudge to another recipient suppressed: %+v", d)
+	}
+
+	fake.Advance(2 * time.Minute)
+	if d := allow(KindNudge, "gastown/Toast", "check your mail"); d.Allowed || d.Reason != ReasonDuplicate || d.RetryAfter != 8*time.Minute {
+		t.Errorf("repeat after cooldown = %+v, want duplicate retrying in 8m", d)
+	}
+	if d := allow(KindNudge, "gastown/Toast", "new instructions"); !d.Allowed {
+		t.Errorf("new message after cooldown suppressed: %+v", d)
+	}
+	// Mail has no cooldown by default, but identical mail is still deduplicated.
+	if d := allow(KindMail, "gastown/Toast", "subject\nbody"); !d.Allowed {
+		t.Errorf("first mail suppressed: %+v", d)
+	}
+	if d := allow(KindMail, "gastown/Toast", "subject\nother body"); !d.Allowed {
+		t.Errorf("distinct mail suppressed: %+v", d)
+	}
+	if d := allow(KindMail, "gastown/Toast", "subject\nbody"); d.Allowed {
+		t.Error("duplicate mail allowed")
+	}
+
+	fake.Advance(11 * time.Minute)
+	if d := allow(KindNudge, "gastown/Toast", "check your mail"); !d.Allowed {
+		t.Errorf("repeat after dedup window suppressed: %+v", d)
+	}
+
+	counts, err := Suppressed(townRoot, fake.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 1 || counts[0].Recipient != "gastown/Toast" || counts[0].Total != 3 ||
+		counts[0].ByKind[KindNudge] != 2 || counts[0].ByKind[KindMail] != 1 {
+		t.Errorf("Suppressed = %+v, want 3 for gastown/Toast", counts)
+	}
+	if counts, _ := Suppressed(townRoot, fake.Now().AddDate(0, 0, -1)); len(counts) != 0 {
+		t.Errorf("Suppressed(yesterday) = %+v, want none", counts)
+	}
+}
+
+func TestLimitsFrom(t *testing.T) {
+	def := LimitsFrom(nil)
+	if def.Cooldowns[KindNudge] != 30*time.Second || def.Cooldowns[KindEscalation] != 5*time.Minute || def.DedupWindow != DefaultDedupWindow {
+		t.Errorf("defaults = %+v", def)
+	}
+
+	cfg := LimitsFrom(&config.RateLimitConfig{
+		Cooldowns:   map[string]string{"nudge": "0", "escalation": "bogus"},
+		DedupWindow: "0",
+	})
+	if cfg.Cooldowns[KindNudge] != 0 || cfg.Cooldowns[KindEscalation] != 5*time.Minute || cfg.DedupWindow != 0 {
+		t.Errorf("configured = %+v", cfg)
+	}
+
+	off := LimitsFrom(&config.RateLimitConfig{Disabled: true})
+	townRoot := t.TempDir()
+	for i := 0; i < 3; i++ {
+		if d, _ := AllowWith(townRoot, off, KindNudge, "mayor", "same"); !d.Allowed {
+			t.Fatalf("disabled limiter suppressed message %d", i)
+		}
+	}
+}
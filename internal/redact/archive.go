@@ -0,0 +1,99 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// reportSuffix is appended to a transcript's name (minus .jsonl) for its
+// redaction report.
+const reportSuffix = ".redactions.json"
+
+// TranscriptsDir returns the directory holding archived transcripts,
+// one subdirectory per session.
+func TranscriptsDir(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "transcripts")
+}
+
+// ArchivedTranscript describes an archived transcript. It is also the
+// content of the redaction report written beside the transcript.
+type ArchivedTranscript struct {
+	Session    string    `json:"session"`
+	Role       string    `json:"role,omitempty"`
+	Source     string    `json:"source"`
+	Path       string    `json:"path"`
+	ArchivedAt time.Time `json:"archived_at"`
+	Redactions Report    `json:"redactions"`
+}
+
+// ArchiveTranscript redacts the transcript at srcPath with r and writes it
+// to TranscriptsDir/<session>/, with its redaction report beside it.
+// Archiving the same transcript again replaces the earlier copy.
+func ArchiveTranscript(townRoot, session, role, srcPath string, r *Redactor) (*ArchivedTranscript, error) {
+	data, err := os.ReadFile(srcPath) //nolint:gosec // G304: path is the agent's own transcript
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	redacted, report := r.Redact(data)
+
+	dir := filepath.Join(TranscriptsDir(townRoot), session)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating transcript archive: %w", err)
+	}
+	dest := filepath.Join(dir, filepath.Base(srcPath))
+	if err := util.AtomicWriteFile(dest, redacted, 0600); err != nil {
+		return nil, fmt.Errorf("writing archived transcript: %w", err)
+	}
+
+	archived := &ArchivedTranscript{
+		Session:    session,
+		Role:       role,
+		Source:     srcPath,
+		Path:       dest,
+		ArchivedAt: time.Now().UTC(),
+		Redactions: report,
+	}
+	if err := util.AtomicWriteJSONWithPerm(reportPath(dest), archived, 0600); err != nil {
+		return nil, fmt.Errorf("writing redaction report: %w", err)
+	}
+	return archived, nil
+}
+
+// ListArchivedTranscripts returns the reports of all archived transcripts,
+// newest first.
+func ListArchivedTranscripts(townRoot string) ([]ArchivedTranscript, error) {
+	reports, err := filepath.Glob(filepath.Join(TranscriptsDir(townRoot), "*", "*"+reportSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []ArchivedTranscript
+	for _, path := range reports {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town runtime dir
+		if err != nil {
+			continue
+		}
+		var a ArchivedTranscript
+		if err := json.Unmarshal(data, &a); err != nil {
+			continue
+		}
+		archived = append(archived, a)
+	}
+	sort.Slice(archived, func(i, j int) bool {
+		return archived[i].ArchivedAt.After(archived[j].ArchivedAt)
+	})
+	return archived, nil
+}
+
+// reportPath returns the redaction report path for an archived transcript.
+func reportPath(transcript string) string {
+	return strings.TrimSuffix(transcript, ".jsonl") + reportSuffix
+}
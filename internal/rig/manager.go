@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/templates/commands"
 )
 
@@ -85,6 +86,7 @@ type RigConfig struct {
 	GitURL        string       `json:"git_url"`                  // repository URL
 	LocalRepo     string       `json:"local_repo,omitempty"`     // optional local reference repo
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
+	VCS           string       `json:"vcs,omitempty"`            // crew clone backend: git (default) or jj
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
 }
@@ -454,7 +456,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		if !bdDatabaseExists(sourceBeadsDir) {
 			cmd := exec.Command("bd", "init", "--prefix", opts.BeadsPrefix, "--backend", "dolt", "--server") // opts.BeadsPrefix validated earlier
 			cmd.Dir = mayorRigPath
-			if output, err := cmd.CombinedOutput(); err != nil {
+			if output, err := proc.CombinedOutput(cmd); err != nil {
 				fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
 			}
 		}
@@ -465,11 +467,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		// the server-side database has issue_prefix set for this workspace.
 		configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 		configCmd.Dir = mayorRigPath
-		_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+		_, _ = proc.CombinedOutput(configCmd) // Ignore errors - older beads don't need this
 
 		prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", opts.BeadsPrefix)
 		prefixSetCmd.Dir = mayorRigPath
-		if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+		if prefixOutput, prefixErr := proc.CombinedOutput(prefixSetCmd); prefixErr != nil {
 			fmt.Printf("  Warning: Could not set issue_prefix: %v (%s)\n", prefixErr, strings.TrimSpace(string(prefixOutput)))
 		}
 	}
@@ -516,13 +518,13 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		prefixCmd := exec.Command("bd", "config", "set", "issue_prefix", opts.BeadsPrefix)
 		prefixCmd.Dir = rigPath
 		prefixCmd.Env = append(os.Environ(), "BEADS_DIR="+resolvedBeadsDir)
-		if out, err := prefixCmd.CombinedOutput(); err != nil {
+		if out, err := proc.CombinedOutput(prefixCmd); err != nil {
 			fmt.Printf("  Warning: Could not set issue_prefix on rig database: %v (%s)\n", err, strings.TrimSpace(string(out)))
 		}
 		typesCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 		typesCmd.Dir = rigPath
 		typesCmd.Env = append(os.Environ(), "BEADS_DIR="+resolvedBeadsDir)
-		_, _ = typesCmd.CombinedOutput()
+		_, _ = proc.CombinedOutput(typesCmd)
 	}
 
 	// Auto-create DoltHub remote for the rig's beads database.
@@ -765,7 +767,7 @@ func (m *Manager) InitBeads(rigPath, prefix string) error {
 	cmd := exec.Command("bd", "init", "--prefix", prefix, "--backend", "dolt", "--server")
 	cmd.Dir = rigPath
 	cmd.Env = filteredEnv
-	_, bdInitErr := cmd.CombinedOutput()
+	_, bdInitErr := proc.CombinedOutput(cmd)
 	if bdInitErr != nil {
 		// bd might not be installed or failed, create minimal structure
 		// Note: beads currently expects YAML format for config
@@ -783,14 +785,14 @@ func (m *Manager) InitBeads(rigPath, prefix string) error {
 		configCmd.Dir = rigPath
 		configCmd.Env = filteredEnv
 		// Ignore errors - older beads versions don't need this
-		_, _ = configCmd.CombinedOutput()
+		_, _ = proc.CombinedOutput(configCmd)
 
 		// Explicitly set issue_prefix config (bd init --prefix may not persist it in newer versions).
 		// Without this, bd create and gt sling fail with "issue_prefix config is missing".
 		prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix)
 		prefixSetCmd.Dir = rigPath
 		prefixSetCmd.Env = filteredEnv
-		if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+		if prefixOutput, prefixErr := proc.CombinedOutput(prefixSetCmd); prefixErr != nil {
 			return fmt.Errorf("bd config set issue_prefix failed: %s", strings.TrimSpace(string(prefixOutput)))
 		}
 	}
@@ -802,7 +804,7 @@ func (m *Manager) InitBeads(rigPath, prefix string) error {
 	migrateCmd.Dir = rigPath
 	migrateCmd.Env = filteredEnv
 	// Ignore errors - fingerprint is optional for functionality
-	_, _ = migrateCmd.CombinedOutput()
+	_, _ = proc.CombinedOutput(migrateCmd)
 
 	// Ensure issues.jsonl exists to prevent bd auto-export from corrupting other files.
 	// Without issues.jsonl, bd's auto-export might write issues to other .jsonl files.
@@ -1036,12 +1038,12 @@ func bdDatabaseExists(beadsDir string) bool {
 	// metadata.json may be tracked in git from another workspace where
 	// the Dolt server had this database, but this is a fresh server.
 	if meta.DoltMode == "server" && meta.DoltDatabase != "" {
-		// Walk up from beadsDir to find the town root (.dolt-data lives there).
+		// Walk up from beadsDir to find the town root, whose settings locate the data dir.
 		townRoot := beads.FindTownRoot(filepath.Dir(beadsDir))
 		if townRoot == "" {
 			return true // Can't find town root — assume it exists
 		}
-		dbDir := filepath.Join(townRoot, ".dolt-data", meta.DoltDatabase)
+		dbDir := filepath.Join(doltserver.DefaultConfig(townRoot).DataDir, meta.DoltDatabase)
 		if _, err := os.Stat(dbDir); os.IsNotExist(err) {
 			return false // Database doesn't exist on this server
 		}
@@ -1270,7 +1272,7 @@ func (m *Manager) seedPatrolMolecules(rigPath string) error {
 	// Use bd command to seed molecules (more reliable than internal API)
 	cmd := exec.Command("bd", "mol", "seed", "--patrol")
 	cmd.Dir = rigPath
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmd(cmd); err != nil {
 		// Fallback: bd mol seed might not support --patrol yet
 		// Try creating them individually via bd create
 		return m.seedPatrolMoleculesManually(rigPath)
@@ -1303,7 +1305,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 		// Check if already exists by title
 		checkCmd := exec.Command("bd", "list", "--type=molecule", "--format=json")
 		checkCmd.Dir = rigPath
-		output, _ := checkCmd.Output()
+		output, _ := proc.Output(checkCmd)
 		if strings.Contains(string(output), mol.title) {
 			continue // Already exists
 		}
@@ -1316,7 +1318,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 			"--priority=2",
 		)
 		cmd.Dir = rigPath
-		if err := cmd.Run(); err != nil {
+		if err := proc.RunCmd(cmd); err != nil {
 			// Non-fatal, continue with others
 			continue
 		}
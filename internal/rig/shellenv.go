@@ -0,0 +1,139 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// WorkspaceEnvFile is the sourceable shell file, relative to a crew or
+// polecat worktree, that sets the workspace's Gas Town variables. It lives
+// under .runtime/, which EnsureGitignorePatterns already ignores.
+const WorkspaceEnvFile = ".runtime/env.sh"
+
+// WorkspaceEnvrc is the direnv file that loads WorkspaceEnvFile on cd.
+const WorkspaceEnvrc = ".envrc"
+
+// workspaceEnvMarker identifies files gt generated, so a repo's own .envrc
+// is never overwritten.
+const workspaceEnvMarker = "# Generated by gt"
+
+// WorkspaceEnv returns the variables a human shell in a crew or polecat
+// workspace needs: the agent environment contract for role ("crew" or
+// "polecat"), minus the runtime-only NODE_OPTIONS. Per-assignment polecat
+// variables (BD_BRANCH, GT_BRANCH) are left to the agent session.
+func WorkspaceEnv(townRoot, rigName, role, name, workDir string) map[string]string {
+	cfg := config.AgentEnvConfig{
+		Role:      role,
+		Rig:       rigName,
+		AgentName: name,
+		TownRoot:  townRoot,
+	}
+	if role == "polecat" {
+		cfg.WorkDir = workDir
+	}
+	return config.WithoutEnv(config.AgentEnv(cfg), "NODE_OPTIONS")
+}
+
+// RenderWorkspaceEnv returns the contents of WorkspaceEnvFile for env:
+// one export per variable, sorted, and an unset for each variable the
+// contract forbids.
+func RenderWorkspaceEnv(env map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s for %s. Do not edit: gt crew pristine and\n", workspaceEnvMarker, env["GT_ROLE"])
+	b.WriteString("# gt doctor --fix rewrite it. Source it, or let direnv load it via .envrc.\n")
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "export %s=%s\n", k, config.ShellQuote(env[k]))
+	}
+	for _, k := range config.ForbiddenAgentEnv {
+		fmt.Fprintf(&b, "unset %s\n", k)
+	}
+	return b.String()
+}
+
+// renderWorkspaceEnvrc returns the direnv file that sources WorkspaceEnvFile.
+func renderWorkspaceEnvrc() string {
+	return fmt.Sprintf("%s: loads the Gas Town environment for this workspace.\nsource_env %s\n",
+		workspaceEnvMarker, WorkspaceEnvFile)
+}
+
+// WriteWorkspaceEnv writes WorkspaceEnvFile and WorkspaceEnvrc into the
+// worktree. An .envrc that gt didn't generate is left alone, and a
+// generated one is excluded from git so it never shows as untracked.
+func WriteWorkspaceEnv(worktreePath string, env map[string]string) error {
+	envPath := filepath.Join(worktreePath, WorkspaceEnvFile)
+	if err := os.MkdirAll(filepath.Dir(envPath), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(envPath), err)
+	}
+	if err := os.WriteFile(envPath, []byte(RenderWorkspaceEnv(env)), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", WorkspaceEnvFile, err)
+	}
+
+	envrcPath := filepath.Join(worktreePath, WorkspaceEnvrc)
+	if data, err := os.ReadFile(envrcPath); err == nil && !strings.HasPrefix(string(data), workspaceEnvMarker) {
+		return nil // the repo's own .envrc
+	}
+	if err := os.WriteFile(envrcPath, []byte(renderWorkspaceEnvrc()), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", WorkspaceEnvrc, err)
+	}
+	return excludeFromGit(worktreePath, "/"+WorkspaceEnvrc)
+}
+
+// CheckWorkspaceEnv compares the worktree's WorkspaceEnvFile with the one
+// WriteWorkspaceEnv would write for env. Returns "" if it is current, or a
+// short description of the problem.
+func CheckWorkspaceEnv(worktreePath string, env map[string]string) string {
+	data, err := os.ReadFile(filepath.Join(worktreePath, WorkspaceEnvFile))
+	if os.IsNotExist(err) {
+		return "missing " + WorkspaceEnvFile
+	}
+	if err != nil {
+		return err.Error()
+	}
+	if string(data) != RenderWorkspaceEnv(env) {
+		return "stale " + WorkspaceEnvFile
+	}
+	return ""
+}
+
+// excludeFromGit adds pattern to the worktree's info/exclude, resolved
+// through git so linked worktrees share their repo's file.
+func excludeFromGit(worktreePath, pattern string) error {
+	out, err := exec.Command("git", "-C", worktreePath, "rev-parse", "--git-path", "info/exclude").Output()
+	if err != nil {
+		return nil // not a git worktree
+	}
+	excludePath := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(excludePath) {
+		excludePath = filepath.Join(worktreePath, excludePath)
+	}
+
+	content, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, []byte(pattern+"\n")...)
+	return os.WriteFile(excludePath, content, 0644)
+}
@@ -0,0 +1,80 @@
+package rig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteWorkspaceEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	worktree := filepath.Join(townRoot, "gastown", "crew", "jane")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q", worktree).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v (%s)", err, out)
+	}
+
+	env := WorkspaceEnv(townRoot, "gastown", "crew", "jane", "")
+	if _, ok := env["NODE_OPTIONS"]; ok {
+		t.Error("NODE_OPTIONS should not be exported to human shells")
+	}
+	if problem := CheckWorkspaceEnv(worktree, env); !strings.HasPrefix(problem, "missing") {
+		t.Errorf("before write: problem = %q, want missing", problem)
+	}
+
+	if err := WriteWorkspaceEnv(worktree, env); err != nil {
+		t.Fatalf("WriteWorkspaceEnv: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(worktree, WorkspaceEnvFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"export GT_ROLE=gastown/crew/jane\n", "export GT_ROOT=" + townRoot, "unset BEADS_DIR\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("env.sh missing %q:\n%s", want, data)
+		}
+	}
+	if problem := CheckWorkspaceEnv(worktree, env); problem != "" {
+		t.Errorf("after write: problem = %q", problem)
+	}
+
+	// The generated .envrc loads env.sh and is invisible to git.
+	envrc, err := os.ReadFile(filepath.Join(worktree, WorkspaceEnvrc))
+	if err != nil || !strings.Contains(string(envrc), "source_env "+WorkspaceEnvFile) {
+		t.Errorf(".envrc = %q, %v", envrc, err)
+	}
+	out, err := exec.Command("git", "-C", worktree, "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), WorkspaceEnvrc) {
+		t.Errorf(".envrc not excluded from git:\n%s", out)
+	}
+
+	// A renamed worker's file is stale.
+	if problem := CheckWorkspaceEnv(worktree, WorkspaceEnv(townRoot, "gastown", "crew", "joe", "")); !strings.HasPrefix(problem, "stale") {
+		t.Errorf("renamed: problem = %q, want stale", problem)
+	}
+}
+
+func TestWriteWorkspaceEnv_KeepsRepoEnvrc(t *testing.T) {
+	worktree := t.TempDir()
+	repoEnvrc := "use flake\n"
+	if err := os.WriteFile(filepath.Join(worktree, WorkspaceEnvrc), []byte(repoEnvrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteWorkspaceEnv(worktree, WorkspaceEnv(worktree, "gastown", "polecat", "toast", worktree)); err != nil {
+		t.Fatalf("WriteWorkspaceEnv: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(worktree, WorkspaceEnvrc))
+	if string(data) != repoEnvrc {
+		t.Errorf("repo .envrc overwritten: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, WorkspaceEnvFile)); err != nil {
+		t.Errorf("env.sh not written: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package rig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// spawnCheckTimeout is the default time limit for a spawn check command.
+const spawnCheckTimeout = 60 * time.Second
+
+// spawnCheckOutputLines is how many trailing output lines a failed check
+// keeps for the report.
+const spawnCheckOutputLines = 10
+
+// SpawnCheckResult is the outcome of one spawn check.
+type SpawnCheckResult struct {
+	Name   string
+	Passed bool
+	Detail string // why the check failed, including trailing output
+	Hint   string // how to fix it, from the check's config
+}
+
+// SpawnValidation is the outcome of validating a new polecat workspace.
+type SpawnValidation struct {
+	Results []SpawnCheckResult
+}
+
+// Failed returns the checks that did not pass.
+func (v *SpawnValidation) Failed() []SpawnCheckResult {
+	var failed []SpawnCheckResult
+	for _, r := range v.Results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Report formats the failed checks for display, one per line with any
+// detail and hint indented beneath it.
+func (v *SpawnValidation) Report() string {
+	var b strings.Builder
+	for _, r := range v.Failed() {
+		fmt.Fprintf(&b, "  ✗ %s\n", r.Name)
+		for _, line := range strings.Split(r.Detail, "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "      %s\n", line)
+			}
+		}
+		if r.Hint != "" {
+			fmt.Fprintf(&b, "      hint: %s\n", r.Hint)
+		}
+	}
+	return b.String()
+}
+
+// ValidateSpawnWorkspace runs a rig's spawn checks against a freshly created
+// worktree, before an agent is launched in it. Two sources of checks run, in
+// order:
+//   - checks declared in the rig's settings/config.json under "spawn"
+//   - executables in <rigPath>/.runtime/spawn-checks/, in alphabetical order
+//
+// Unlike setup hooks, which only warn, every check runs and any failure is
+// reported so the caller can refuse to start the agent. Check scripts get
+// the same environment as setup hooks (GT_WORKTREE_PATH, GT_RIG_PATH).
+func ValidateSpawnWorkspace(rigPath, worktreePath string) (*SpawnValidation, error) {
+	v := &SpawnValidation{}
+
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings != nil && settings.Spawn != nil {
+		for _, check := range settings.Spawn.Checks {
+			v.Results = append(v.Results, runSpawnCheck(check, rigPath, worktreePath))
+		}
+	}
+
+	scripts, err := spawnCheckScripts(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, script := range scripts {
+		r := runSpawnCommand(script, nil, spawnCheckTimeout, rigPath, worktreePath)
+		r.Name = filepath.Base(script)
+		v.Results = append(v.Results, r)
+	}
+	return v, nil
+}
+
+// spawnCheckScripts lists the executable files in .runtime/spawn-checks/.
+// A non-executable file is a misconfiguration, not something to skip
+// silently, so it is returned as an error.
+func spawnCheckScripts(rigPath string) ([]string, error) {
+	dir := filepath.Join(rigPath, ".runtime", "spawn-checks")
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading spawn-checks dir: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var scripts []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat spawn check %s: %w", entry.Name(), err)
+		}
+		if info.Mode().Perm()&0111 == 0 {
+			return nil, fmt.Errorf("spawn check %s is not executable (chmod +x %s)",
+				entry.Name(), filepath.Join(dir, entry.Name()))
+		}
+		scripts = append(scripts, filepath.Join(dir, entry.Name()))
+	}
+	return scripts, nil
+}
+
+// runSpawnCheck runs one declared check.
+func runSpawnCheck(check config.SpawnCheck, rigPath, worktreePath string) SpawnCheckResult {
+	if check.Path != "" {
+		r := SpawnCheckResult{Name: check.Name, Hint: check.Hint, Passed: true}
+		if _, err := os.Stat(filepath.Join(worktreePath, check.Path)); err != nil {
+			r.Passed = false
+			if os.IsNotExist(err) {
+				r.Detail = fmt.Sprintf("%s is missing", check.Path)
+			} else {
+				r.Detail = fmt.Sprintf("%s: %v", check.Path, err)
+			}
+		}
+		return r
+	}
+
+	timeout := spawnCheckTimeout
+	if check.Timeout != "" {
+		if d, err := time.ParseDuration(check.Timeout); err == nil && d > 0 {
+			timeout = d
+		}
+	}
+	r := runSpawnCommand("sh", []string{"-c", check.Run}, timeout, rigPath, worktreePath)
+	r.Name = check.Name
+	r.Hint = check.Hint
+	return r
+}
+
+// runSpawnCommand runs a check command in the worktree and captures its
+// output for the report.
+func runSpawnCommand(name string, args []string, timeout time.Duration, rigPath, worktreePath string) SpawnCheckResult {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("GT_WORKTREE_PATH=%s", worktreePath),
+		fmt.Sprintf("GT_RIG_PATH=%s", rigPath),
+	)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	if err == nil {
+		return SpawnCheckResult{Passed: true}
+	}
+	detail := err.Error()
+	if ctx.Err() == context.DeadlineExceeded {
+		detail = fmt.Sprintf("timed out after %s", timeout)
+	}
+	if tail := tailLines(out.String(), spawnCheckOutputLines); tail != "" {
+		detail += "\n" + tail
+	}
+	return SpawnCheckResult{Detail: detail}
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
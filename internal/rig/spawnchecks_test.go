@@ -0,0 +1,95 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeSpawnSettings(t *testing.T, rigDir string, checks ...config.SpawnCheck) {
+	t.Helper()
+	settings := config.NewRigSettings()
+	settings.Spawn = &config.SpawnConfig{Checks: checks}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigDir), settings); err != nil {
+		t.Fatalf("saving rig settings: %v", err)
+	}
+}
+
+func TestValidateSpawnWorkspace_NoChecks(t *testing.T) {
+	v, err := ValidateSpawnWorkspace(t.TempDir(), t.TempDir())
+	if err != nil {
+		t.Fatalf("ValidateSpawnWorkspace() = %v", err)
+	}
+	if len(v.Results) != 0 {
+		t.Errorf("Results = %+v, want none", v.Results)
+	}
+}
+
+func TestValidateSpawnWorkspace_DeclaredChecks(t *testing.T) {
+	rigDir := t.TempDir()
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(worktree, ".env"), []byte("X=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeSpawnSettings(t, rigDir,
+		config.SpawnCheck{Name: "env", Path: ".env"},
+		config.SpawnCheck{Name: "deps", Path: "node_modules", Hint: "add npm ci to a setup hook"},
+		config.SpawnCheck{Name: "worktree-env", Run: `test "$GT_WORKTREE_PATH" = "$PWD"`},
+		config.SpawnCheck{Name: "lint", Run: "echo config broken; exit 3"},
+	)
+
+	v, err := ValidateSpawnWorkspace(rigDir, worktree)
+	if err != nil {
+		t.Fatalf("ValidateSpawnWorkspace() = %v", err)
+	}
+	if len(v.Results) != 4 {
+		t.Fatalf("ran %d checks, want 4", len(v.Results))
+	}
+	failed := v.Failed()
+	if len(failed) != 2 || failed[0].Name != "deps" || failed[1].Name != "lint" {
+		t.Fatalf("Failed() = %+v, want deps and lint", failed)
+	}
+	report := v.Report()
+	for _, want := range []string{"✗ deps", "node_modules is missing", "hint: add npm ci", "✗ lint", "exit status 3", "config broken"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestValidateSpawnWorkspace_Scripts(t *testing.T) {
+	rigDir := t.TempDir()
+	checksDir := filepath.Join(rigDir, ".runtime", "spawn-checks")
+	if err := os.MkdirAll(checksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(checksDir, "01-ok.sh"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(checksDir, "02-fail.sh"), []byte("#!/bin/sh\necho submodules not initialized\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := ValidateSpawnWorkspace(rigDir, t.TempDir())
+	if err != nil {
+		t.Fatalf("ValidateSpawnWorkspace() = %v", err)
+	}
+	failed := v.Failed()
+	if len(v.Results) != 2 || len(failed) != 1 || failed[0].Name != "02-fail.sh" {
+		t.Fatalf("Results = %+v, want 02-fail.sh to fail", v.Results)
+	}
+	if !strings.Contains(failed[0].Detail, "submodules not initialized") {
+		t.Errorf("Detail = %q, want script output", failed[0].Detail)
+	}
+
+	// A non-executable check is a configuration error, not a skip.
+	if err := os.WriteFile(filepath.Join(checksDir, "03-noexec.sh"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateSpawnWorkspace(rigDir, t.TempDir()); err == nil || !strings.Contains(err.Error(), "not executable") {
+		t.Errorf("ValidateSpawnWorkspace() error = %v, want not executable", err)
+	}
+}
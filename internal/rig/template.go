@@ -0,0 +1,372 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// RigTemplate is a declarative description of the beads a new rig database
+// starts with, loaded from settings/rig-templates/<name>.toml in the town.
+//
+// Example:
+//
+//	description = "Standard rig"
+//	molecules = ["mol-polecat-work", "mol-witness-patrol"]
+//
+//	[[handoffs]]
+//	role = "witness"
+//	description = "Patrol {{rig}} and report stuck polecats to the mayor."
+//
+//	[welcome]
+//	title = "Welcome to {{rig}}"
+//	description = "Start here."
+//
+//	[[welcome.children]]
+//	title = "Read the {{rig}} README and record build/test commands"
+//
+// "{{rig}}" and "{{prefix}}" in titles and descriptions are replaced with the
+// rig name and beads prefix.
+type RigTemplate struct {
+	Name        string `toml:"-"`
+	Description string `toml:"description"`
+
+	// Molecules are formulas cooked into molecule protos in the new database.
+	Molecules []string `toml:"molecules"`
+
+	// Handoffs are pinned handoff beads created for each role.
+	Handoffs []HandoffTemplate `toml:"handoffs"`
+
+	// Welcome is an epic (with child tasks) that orients the rig's first agents.
+	Welcome *EpicTemplate `toml:"welcome"`
+}
+
+// HandoffTemplate seeds a role's handoff bead.
+type HandoffTemplate struct {
+	Role        string `toml:"role"`
+	Description string `toml:"description"`
+}
+
+// EpicTemplate seeds an epic and its children.
+type EpicTemplate struct {
+	Title       string         `toml:"title"`
+	Description string         `toml:"description"`
+	Priority    *int           `toml:"priority"`
+	Children    []BeadTemplate `toml:"children"`
+}
+
+// BeadTemplate seeds a single bead.
+type BeadTemplate struct {
+	Title       string `toml:"title"`
+	Description string `toml:"description"`
+	Type        string `toml:"type"` // default "task"
+	Priority    *int   `toml:"priority"`
+}
+
+// defaultSeedPriority is used when a template bead has no priority.
+const defaultSeedPriority = 2
+
+// RigTemplatesDir returns the directory holding the town's rig templates.
+func RigTemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "rig-templates")
+}
+
+// RigTemplatePath returns the path of the named rig template.
+func RigTemplatePath(townRoot, name string) string {
+	return filepath.Join(RigTemplatesDir(townRoot), name+".toml")
+}
+
+// ListRigTemplates returns the names of the town's rig templates.
+func ListRigTemplates(townRoot string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(RigTemplatesDir(townRoot), "*.toml"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), ".toml"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadRigTemplate loads and validates the named rig template.
+func LoadRigTemplate(townRoot, name string) (*RigTemplate, error) {
+	path := RigTemplatePath(townRoot, name)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is in the town settings dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			available, _ := ListRigTemplates(townRoot)
+			if len(available) == 0 {
+				return nil, fmt.Errorf("rig template %q not found (no templates in %s)", name, RigTemplatesDir(townRoot))
+			}
+			return nil, fmt.Errorf("rig template %q not found (available: %s)", name, strings.Join(available, ", "))
+		}
+		return nil, fmt.Errorf("reading rig template: %w", err)
+	}
+	return ParseRigTemplate(name, data)
+}
+
+// ParseRigTemplate parses and validates a rig template.
+func ParseRigTemplate(name string, data []byte) (*RigTemplate, error) {
+	var tmpl RigTemplate
+	if err := toml.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("parsing rig template %q: %w", name, err)
+	}
+	tmpl.Name = name
+	if err := tmpl.Validate(); err != nil {
+		return nil, fmt.Errorf("rig template %q: %w", name, err)
+	}
+	return &tmpl, nil
+}
+
+// Validate checks that every seeded bead has what it needs.
+func (t *RigTemplate) Validate() error {
+	for i, m := range t.Molecules {
+		if strings.TrimSpace(m) == "" {
+			return fmt.Errorf("molecules[%d] is empty", i)
+		}
+	}
+	seen := make(map[string]bool)
+	for i, h := range t.Handoffs {
+		if h.Role == "" {
+			return fmt.Errorf("handoffs[%d] has no role", i)
+		}
+		if seen[h.Role] {
+			return fmt.Errorf("duplicate handoff for role %q", h.Role)
+		}
+		seen[h.Role] = true
+	}
+	if t.Welcome != nil {
+		if t.Welcome.Title == "" {
+			return fmt.Errorf("welcome epic has no title")
+		}
+		if err := validPriority(t.Welcome.Priority); err != nil {
+			return fmt.Errorf("welcome epic: %w", err)
+		}
+		for i, c := range t.Welcome.Children {
+			if c.Title == "" {
+				return fmt.Errorf("welcome.children[%d] has no title", i)
+			}
+			if err := validPriority(c.Priority); err != nil {
+				return fmt.Errorf("welcome.children[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validPriority(p *int) error {
+	if p != nil && (*p < 0 || *p > 4) {
+		return fmt.Errorf("priority %d out of range 0-4", *p)
+	}
+	return nil
+}
+
+// Expand returns a copy of the template with {{rig}} and {{prefix}}
+// replaced in titles and descriptions.
+func (t *RigTemplate) Expand(rigName, prefix string) *RigTemplate {
+	r := strings.NewReplacer("{{rig}}", rigName, "{{prefix}}", prefix)
+	out := &RigTemplate{
+		Name:        t.Name,
+		Description: r.Replace(t.Description),
+		Molecules:   append([]string(nil), t.Molecules...),
+	}
+	for _, h := range t.Handoffs {
+		out.Handoffs = append(out.Handoffs, HandoffTemplate{Role: h.Role, Description: r.Replace(h.Description)})
+	}
+	if t.Welcome != nil {
+		w := &EpicTemplate{
+			Title:       r.Replace(t.Welcome.Title),
+			Description: r.Replace(t.Welcome.Description),
+			Priority:    t.Welcome.Priority,
+		}
+		for _, c := range t.Welcome.Children {
+			w.Children = append(w.Children, BeadTemplate{
+				Title:       r.Replace(c.Title),
+				Description: r.Replace(c.Description),
+				Type:        c.Type,
+				Priority:    c.Priority,
+			})
+		}
+		out.Welcome = w
+	}
+	return out
+}
+
+// SeedResult reports what SeedRigFromTemplate created. Beads that already
+// existed are listed in Existing rather than recreated.
+type SeedResult struct {
+	Template  string   `json:"template"`
+	Prefix    string   `json:"prefix"`
+	Molecules []string `json:"molecules,omitempty"`
+	Handoffs  []string `json:"handoffs,omitempty"`
+	Welcome   string   `json:"welcome,omitempty"`
+	Children  []string `json:"children,omitempty"`
+	Existing  []string `json:"existing,omitempty"`
+}
+
+// SeedRigFromTemplate initializes the beads schema in the rig database
+// behind beadsDir and seeds it from tmpl: molecule protos, pinned handoff
+// beads, and the welcome epic. It is safe to re-run; handoff beads and the
+// welcome epic are found by title and not duplicated. The Dolt server must
+// be running. An empty prefix is derived from the rig name.
+func SeedRigFromTemplate(townRoot, rigName, beadsDir, prefix string, tmpl *RigTemplate) (*SeedResult, error) {
+	if prefix == "" {
+		prefix = deriveBeadsPrefix(rigName)
+	}
+	tmpl = tmpl.Expand(rigName, prefix)
+	result := &SeedResult{Template: tmpl.Name, Prefix: prefix}
+
+	if err := initSeedSchema(beadsDir, prefix); err != nil {
+		return result, err
+	}
+
+	for _, formula := range tmpl.Molecules {
+		if err := cookFormula(townRoot, beadsDir, formula); err != nil {
+			return result, err
+		}
+		result.Molecules = append(result.Molecules, formula)
+	}
+
+	bd := beads.NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir)
+	for _, h := range tmpl.Handoffs {
+		existing, err := bd.FindHandoffBead(h.Role)
+		if err != nil {
+			return result, err
+		}
+		if existing != nil {
+			result.Existing = append(result.Existing, existing.ID)
+			continue
+		}
+		issue, err := bd.GetOrCreateHandoffBead(h.Role)
+		if err != nil {
+			return result, fmt.Errorf("creating %s handoff bead: %w", h.Role, err)
+		}
+		if h.Description != "" {
+			desc := h.Description
+			if err := bd.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+				return result, fmt.Errorf("setting %s handoff content: %w", h.Role, err)
+			}
+		}
+		result.Handoffs = append(result.Handoffs, issue.ID)
+	}
+
+	if tmpl.Welcome != nil {
+		if err := seedWelcomeEpic(bd, tmpl.Welcome, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// seedWelcomeEpic creates the welcome epic and its children unless an epic
+// with the same title already exists.
+func seedWelcomeEpic(bd *beads.Beads, w *EpicTemplate, result *SeedResult) error {
+	epics, err := bd.List(beads.ListOptions{Status: "all", Label: "gt:epic", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing epics: %w", err)
+	}
+	for _, e := range epics {
+		if e.Title == w.Title {
+			result.Existing = append(result.Existing, e.ID)
+			return nil
+		}
+	}
+
+	epic, err := bd.Create(beads.CreateOptions{
+		Title:       w.Title,
+		Type:        "epic",
+		Priority:    priorityOr(w.Priority),
+		Description: w.Description,
+	})
+	if err != nil {
+		return fmt.Errorf("creating welcome epic: %w", err)
+	}
+	result.Welcome = epic.ID
+
+	for _, c := range w.Children {
+		typ := c.Type
+		if typ == "" {
+			typ = "task"
+		}
+		child, err := bd.Create(beads.CreateOptions{
+			Title:       c.Title,
+			Type:        typ,
+			Priority:    priorityOr(c.Priority),
+			Description: c.Description,
+			Parent:      epic.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("creating welcome task %q: %w", c.Title, err)
+		}
+		result.Children = append(result.Children, child.ID)
+	}
+	return nil
+}
+
+func priorityOr(p *int) int {
+	if p == nil {
+		return defaultSeedPriority
+	}
+	return *p
+}
+
+// initSeedSchema runs bd init against the rig database so it has the beads
+// schema, then sets the issue prefix and Gas Town custom types. A database
+// that is already initialized is left as is.
+func initSeedSchema(beadsDir, prefix string) error {
+	env := append(filterBeadsDirEnv(os.Environ()), "BEADS_DIR="+beadsDir)
+	workDir := filepath.Dir(beadsDir)
+
+	// --server keeps dolt_mode=server in metadata.json (see Manager.InitBeads).
+	cmd := exec.Command("bd", "init", "--prefix", prefix, "--backend", "dolt", "--server") //nolint:gosec // G204: prefix is validated by bd
+	cmd.Dir = workDir
+	cmd.Env = env
+	if output, err := proc.CombinedOutput(cmd); err != nil && !strings.Contains(string(output), "already initialized") {
+		return fmt.Errorf("bd init failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	prefixCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix) //nolint:gosec // G204: see above
+	prefixCmd.Dir = workDir
+	prefixCmd.Env = env
+	if output, err := proc.CombinedOutput(prefixCmd); err != nil {
+		return fmt.Errorf("bd config set issue_prefix failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
+		return fmt.Errorf("ensuring custom types: %w", err)
+	}
+	return nil
+}
+
+// cookFormula cooks a formula into a molecule proto in the rig database.
+// GT_ROOT lets bd find town-level formulas.
+func cookFormula(townRoot, beadsDir, formula string) error {
+	cmd := exec.Command("bd", "cook", formula) //nolint:gosec // G204: formula name comes from the town's template
+	cmd.Dir = filepath.Dir(beadsDir)
+	cmd.Env = append(filterBeadsDirEnv(os.Environ()), "BEADS_DIR="+beadsDir, "GT_ROOT="+townRoot)
+	if output, err := proc.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("cooking formula %s: %s", formula, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// filterBeadsDirEnv drops any inherited BEADS_DIR so bd can't fall back to
+// another database.
+func filterBeadsDirEnv(environ []string) []string {
+	filtered := make([]string, 0, len(environ))
+	for _, e := range environ {
+		if !strings.HasPrefix(e, "BEADS_DIR=") {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,112 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRigTemplate = `
+description = "Standard rig"
+molecules = ["mol-polecat-work"]
+
+[[handoffs]]
+role = "witness"
+description = "Patrol {{rig}}."
+
+[[handoffs]]
+role = "refinery"
+
+[welcome]
+title = "Welcome to {{rig}}"
+priority = 1
+
+[[welcome.children]]
+title = "Record build commands for {{rig}} ({{prefix}})"
+
+[[welcome.children]]
+title = "Triage open bugs"
+type = "bug"
+`
+
+func TestLoadRigTemplate(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(RigTemplatesDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(RigTemplatePath(townRoot, "standard"), []byte(testRigTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := LoadRigTemplate(townRoot, "standard")
+	if err != nil {
+		t.Fatalf("LoadRigTemplate: %v", err)
+	}
+	if tmpl.Name != "standard" || len(tmpl.Molecules) != 1 || len(tmpl.Handoffs) != 2 {
+		t.Errorf("template = %+v", tmpl)
+	}
+	if tmpl.Welcome == nil || len(tmpl.Welcome.Children) != 2 || *tmpl.Welcome.Priority != 1 {
+		t.Errorf("welcome = %+v", tmpl.Welcome)
+	}
+
+	_, err = LoadRigTemplate(townRoot, "missing")
+	if err == nil || !strings.Contains(err.Error(), "available: standard") {
+		t.Errorf("LoadRigTemplate(missing) error = %v, want list of available templates", err)
+	}
+
+	names, err := ListRigTemplates(townRoot)
+	if err != nil || len(names) != 1 || names[0] != "standard" {
+		t.Errorf("ListRigTemplates = %v, %v", names, err)
+	}
+}
+
+func TestRigTemplateExpand(t *testing.T) {
+	tmpl, err := ParseRigTemplate("standard", []byte(testRigTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := tmpl.Expand("myproject", "mp")
+	if got.Welcome.Title != "Welcome to myproject" {
+		t.Errorf("welcome title = %q", got.Welcome.Title)
+	}
+	if got.Welcome.Children[0].Title != "Record build commands for myproject (mp)" {
+		t.Errorf("child title = %q", got.Welcome.Children[0].Title)
+	}
+	if got.Handoffs[0].Description != "Patrol myproject." {
+		t.Errorf("handoff description = %q", got.Handoffs[0].Description)
+	}
+	if tmpl.Welcome.Title != "Welcome to {{rig}}" {
+		t.Errorf("Expand modified the original template: %q", tmpl.Welcome.Title)
+	}
+}
+
+func TestRigTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"handoff without role", "[[handoffs]]\ndescription = \"x\"", "no role"},
+		{"duplicate handoff", "[[handoffs]]\nrole = \"witness\"\n[[handoffs]]\nrole = \"witness\"", "duplicate"},
+		{"welcome without title", "[welcome]\ndescription = \"x\"", "no title"},
+		{"child without title", "[welcome]\ntitle = \"w\"\n[[welcome.children]]\ntype = \"task\"", "children[0] has no title"},
+		{"bad priority", "[welcome]\ntitle = \"w\"\npriority = 7", "out of range"},
+		{"empty molecule", "molecules = [\"\"]", "molecules[0]"},
+		{"invalid toml", "molecules = [", "parsing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRigTemplate("bad", []byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseRigTemplate error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRigTemplatesDir(t *testing.T) {
+	if got := RigTemplatePath("/town", "standard"); got != filepath.Join("/town", "settings", "rig-templates", "standard.toml") {
+		t.Errorf("RigTemplatePath = %s", got)
+	}
+}
@@ -92,3 +92,13 @@ func (r *Rig) DefaultBranch() string {
 	}
 	return cfg.DefaultBranch
 }
+
+// VCS returns the version control backend configured for this rig's crew
+// clones (see package vcs). Falls back to "git" if not configured.
+func (r *Rig) VCS() string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil || cfg.VCS == "" {
+		return "git"
+	}
+	return cfg.VCS
+}
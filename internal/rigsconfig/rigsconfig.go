@@ -0,0 +1,288 @@
+// Package rigsconfig provides typed, cached access to the town's rig
+// registry, mayor/rigs.json.
+//
+// Every package that enumerates rigs should read the registry through here
+// rather than unmarshalling the file itself, so they all agree on which
+// rigs exist, how a missing or malformed file is reported, and what a valid
+// rig name is. Loads are cached per file and revalidated against the file's
+// modification time and size; callers always get their own copy and may
+// modify it freely before passing it to Save.
+package rigsconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ErrNotFound is returned (wrapped) when rigs.json does not exist.
+var ErrNotFound = config.ErrNotFound
+
+// reservedNames are used by town-level infrastructure and cannot be new
+// rigs. Rigs registered under them before they were reserved keep working.
+var reservedNames = []string{"hq", constants.DirMayor}
+
+// ChangeFunc is called with the path and new contents of a registry after
+// it changes. It receives its own copy of the registry.
+type ChangeFunc func(path string, rigs *config.RigsConfig)
+
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	rigs    *config.RigsConfig
+}
+
+var (
+	mu       sync.Mutex
+	cache    = map[string]cacheEntry{}
+	watchers = map[int]ChangeFunc{}
+	nextID   int
+)
+
+// Path returns the path of rigs.json within a town root.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
+}
+
+// Load returns the rig registry of a town. A missing file is reported as an
+// error wrapping ErrNotFound. Entries whose names are not usable as a
+// directory (see skipUnsafeNames) are left out with a warning; they stay in
+// the file.
+func Load(townRoot string) (*config.RigsConfig, error) {
+	return LoadFile(Path(townRoot))
+}
+
+// LoadFile returns the rig registry at path. See Load.
+func LoadFile(path string) (*config.RigsConfig, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading rigs config: %w", err)
+	}
+
+	mu.Lock()
+	cached, ok := cache[path]
+	mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return clone(cached.rigs), nil
+	}
+
+	rigs, err := config.LoadRigsConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	skipUnsafeNames(path, rigs)
+
+	mu.Lock()
+	cache[path] = cacheEntry{modTime: info.ModTime(), size: info.Size(), rigs: rigs}
+	mu.Unlock()
+	if ok {
+		// The file changed on disk since we last read it.
+		notify(path, rigs)
+	}
+	return clone(rigs), nil
+}
+
+// Names returns the sorted names of the rigs registered in a town.
+func Names(townRoot string) ([]string, error) {
+	rigs, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return SortedNames(rigs), nil
+}
+
+// SortedNames returns the rig names in rigs, sorted.
+func SortedNames(rigs *config.RigsConfig) []string {
+	names := make([]string, 0, len(rigs.Rigs))
+	for name := range rigs.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Prefix returns the beads prefix registered for a rig, without a trailing
+// hyphen, or "" if it has none.
+func Prefix(rigs *config.RigsConfig, rigName string) string {
+	entry, ok := rigs.Rigs[rigName]
+	if !ok || entry.BeadsConfig == nil {
+		return ""
+	}
+	return strings.TrimSuffix(entry.BeadsConfig.Prefix, "-")
+}
+
+// RigForPrefix returns the rig whose beads prefix is prefix (with or
+// without a trailing hyphen), or "" if none is.
+func RigForPrefix(rigs *config.RigsConfig, prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "-")
+	if prefix == "" {
+		return ""
+	}
+	for _, name := range SortedNames(rigs) {
+		if Prefix(rigs, name) == prefix {
+			return name
+		}
+	}
+	return ""
+}
+
+// validate checks a registry before it is saved over onDisk (nil if there
+// is no file yet): its version must be supported, every name must be usable
+// as a directory under the town root, and names not already in onDisk must
+// pass ValidateName. Existing rigs are not held to names reserved later.
+func validate(rigs, onDisk *config.RigsConfig) error {
+	if rigs.Version > config.CurrentRigsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", config.ErrInvalidVersion, rigs.Version, config.CurrentRigsVersion)
+	}
+	for name := range rigs.Rigs {
+		check := ValidateName
+		if onDisk != nil {
+			if _, existing := onDisk.Rigs[name]; existing {
+				check = checkDirName
+			}
+		}
+		if err := check(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipUnsafeNames removes rigs whose names fail checkDirName, with a
+// warning, so no caller builds a path from them. One bad entry, e.g.
+// written by hand, should not make every rig in the town unreachable.
+// SaveFile writes skipped entries back, so they are never lost.
+func skipUnsafeNames(path string, rigs *config.RigsConfig) {
+	for name := range rigs.Rigs {
+		if err := checkDirName(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: ignoring rig: %v\n", path, err)
+			delete(rigs.Rigs, name)
+		}
+	}
+}
+
+// ValidateName checks that name can be a new rig: usable as a directory
+// under the town root, and not reserved.
+func ValidateName(name string) error {
+	if err := checkDirName(name); err != nil {
+		return err
+	}
+	for _, reserved := range reservedNames {
+		if strings.EqualFold(name, reserved) {
+			return fmt.Errorf("invalid rig name %q: reserved for town-level infrastructure", name)
+		}
+	}
+	return nil
+}
+
+// checkDirName checks that name is non-empty and a single path element that
+// is not hidden.
+func checkDirName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("invalid rig name: empty")
+	case strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "."):
+		return fmt.Errorf("invalid rig name %q: must be a single directory name", name)
+	}
+	return nil
+}
+
+// Save validates rigs and writes it as the registry of a town, then
+// notifies watchers.
+func Save(townRoot string, rigs *config.RigsConfig) error {
+	return SaveFile(Path(townRoot), rigs)
+}
+
+// SaveFile writes rigs to path. See Save. Entries in the file that Load
+// skipped are written back unchanged.
+func SaveFile(path string, rigs *config.RigsConfig) error {
+	onDisk, err := config.LoadRigsConfig(path)
+	if err != nil {
+		// Nothing to preserve from a missing or unreadable file.
+		onDisk = nil
+	}
+	if err := validate(rigs, onDisk); err != nil {
+		return err
+	}
+	out := rigs
+	if onDisk != nil {
+		out = clone(rigs)
+		for name, entry := range onDisk.Rigs {
+			if checkDirName(name) != nil {
+				out.Rigs[name] = entry
+			}
+		}
+	}
+	if err := config.SaveRigsConfig(path, out); err != nil {
+		return err
+	}
+
+	saved := clone(rigs)
+	mu.Lock()
+	if info, err := os.Stat(path); err == nil {
+		cache[path] = cacheEntry{modTime: info.ModTime(), size: info.Size(), rigs: saved}
+	} else {
+		delete(cache, path)
+	}
+	mu.Unlock()
+	notify(path, saved)
+	return nil
+}
+
+// Watch registers fn to be called after a registry changes: when Save
+// writes it, or when Load finds it modified on disk since the last load.
+// Returns a func that unregisters fn.
+func Watch(fn ChangeFunc) (cancel func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	id := nextID
+	nextID++
+	watchers[id] = fn
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(watchers, id)
+	}
+}
+
+// Invalidate drops any cached copy of a town's registry.
+func Invalidate(townRoot string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(cache, Path(townRoot))
+}
+
+func notify(path string, rigs *config.RigsConfig) {
+	mu.Lock()
+	fns := make([]ChangeFunc, 0, len(watchers))
+	for _, fn := range watchers {
+		fns = append(fns, fn)
+	}
+	mu.Unlock()
+	for _, fn := range fns {
+		fn(path, clone(rigs))
+	}
+}
+
+// clone returns a copy of rigs that shares nothing mutable with it.
+func clone(rigs *config.RigsConfig) *config.RigsConfig {
+	c := &config.RigsConfig{Version: rigs.Version, Rigs: make(map[string]config.RigEntry, len(rigs.Rigs))}
+	for name, entry := range rigs.Rigs {
+		if entry.BeadsConfig != nil {
+			beads := *entry.BeadsConfig
+			entry.BeadsConfig = &beads
+		}
+		c.Rigs[name] = entry
+	}
+	return c
+}
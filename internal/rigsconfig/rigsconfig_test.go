@@ -0,0 +1,157 @@
+package rigsconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeRigs(t *testing.T, townRoot, data string) {
+	t.Helper()
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := Load(townRoot); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load without rigs.json: err = %v, want ErrNotFound", err)
+	}
+
+	writeRigs(t, townRoot, `{"version":1,"rigs":{
+		"wyvern":{"git_url":"https://example.com/wyvern.git","beads":{"repo":"local","prefix":"wy-"}},
+		"gastown":{"git_url":"https://example.com/gastown.git"}}}`)
+
+	names, err := Names(townRoot)
+	if err != nil {
+		t.Fatalf("Names: %v", err)
+	}
+	if want := []string{"gastown", "wyvern"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Names = %v, want %v", names, want)
+	}
+
+	rigs, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Prefix(rigs, "wyvern"); got != "wy" {
+		t.Errorf("Prefix(wyvern) = %q, want wy", got)
+	}
+	if got := RigForPrefix(rigs, "wy-"); got != "wyvern" {
+		t.Errorf("RigForPrefix(wy-) = %q, want wyvern", got)
+	}
+
+	// Callers get their own copy of the cached registry.
+	rigs.Rigs["wyvern"].BeadsConfig.Prefix = "xx"
+	delete(rigs.Rigs, "gastown")
+	again, _ := Load(townRoot)
+	if len(again.Rigs) != 2 || Prefix(again, "wyvern") != "wy" {
+		t.Errorf("modifying a loaded registry changed the cache: %+v", again.Rigs)
+	}
+}
+
+func TestLoad_SkipsUnsafeNames(t *testing.T) {
+	for _, name := range []string{"..", "a/b", ".hidden"} {
+		townRoot := t.TempDir()
+		writeRigs(t, townRoot, `{"version":1,"rigs":{"`+name+`":{"git_url":""},"gastown":{"git_url":""}}}`)
+		names, err := Names(townRoot)
+		if err != nil {
+			t.Errorf("Load with rig name %q: %v", name, err)
+			continue
+		}
+		if want := []string{"gastown"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("Names with rig name %q = %v, want %v", name, names, want)
+		}
+	}
+}
+
+func TestSave_KeepsExistingEntries(t *testing.T) {
+	townRoot := t.TempDir()
+	// "mayor" was accepted by gt rig add before it was reserved; "a/b" was
+	// written by hand and is skipped by Load.
+	writeRigs(t, townRoot, `{"version":1,"rigs":{"a/b":{"git_url":"x"},"mayor":{"git_url":""},"gastown":{"git_url":""}}}`)
+
+	rigs, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"gastown", "mayor"}; !reflect.DeepEqual(SortedNames(rigs), want) {
+		t.Fatalf("Names = %v, want %v", SortedNames(rigs), want)
+	}
+	rigs.Rigs["wyvern"] = config.RigEntry{}
+	if err := Save(townRoot, rigs); err != nil {
+		t.Fatalf("Save after an unrelated change: %v", err)
+	}
+
+	onDisk, err := config.LoadRigsConfig(Path(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a/b", "gastown", "mayor", "wyvern"}; !reflect.DeepEqual(SortedNames(onDisk), want) {
+		t.Errorf("rigs.json after Save = %v, want %v", SortedNames(onDisk), want)
+	}
+
+	// New rigs are held to the reserved names.
+	rigs.Rigs["hq"] = config.RigEntry{}
+	if err := Save(townRoot, rigs); err == nil {
+		t.Error("Save accepted a new rig named hq")
+	}
+}
+
+func TestSaveAndWatch(t *testing.T) {
+	townRoot := t.TempDir()
+	var changes []string
+	cancel := Watch(func(path string, rigs *config.RigsConfig) {
+		if path == Path(townRoot) {
+			changes = append(changes, SortedNames(rigs)...)
+		}
+	})
+	defer cancel()
+
+	rigs := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gastown": {GitURL: "x"}}}
+	if err := Save(townRoot, rigs); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !reflect.DeepEqual(changes, []string{"gastown"}) {
+		t.Errorf("changes after Save = %v, want [gastown]", changes)
+	}
+
+	// An edit made outside Save is picked up and reported on the next load.
+	changes = nil
+	writeRigs(t, townRoot, `{"version":1,"rigs":{"gastown":{"git_url":"x"},"wyvern":{"git_url":"y"}}}`)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(Path(townRoot), future, future); err != nil {
+		t.Fatal(err)
+	}
+	names, err := Names(townRoot)
+	if err != nil || len(names) != 2 {
+		t.Fatalf("Names after external edit = %v, %v", names, err)
+	}
+	if !reflect.DeepEqual(changes, []string{"gastown", "wyvern"}) {
+		t.Errorf("changes after external edit = %v", changes)
+	}
+
+	cancel()
+	changes = nil
+	if err := Save(townRoot, rigs); err != nil {
+		t.Fatal(err)
+	}
+	if changes != nil {
+		t.Errorf("cancelled watcher was called: %v", changes)
+	}
+
+	bad := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"../etc": {}}}
+	if err := Save(townRoot, bad); err == nil {
+		t.Error("Save accepted an invalid rig name")
+	}
+}
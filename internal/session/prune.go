@@ -0,0 +1,205 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// DefaultPruneMinAge is how old an orphan session must be before it is
+// pruned, so sessions started just before their polecat or crew directory
+// is created are left alone.
+const DefaultPruneMinAge = 5 * time.Minute
+
+// OrphanSession is a rig-prefixed tmux session that no known agent owns.
+type OrphanSession struct {
+	Session string `json:"session"`
+	Rig     string `json:"rig,omitempty"`
+	Role    Role   `json:"role"`
+	Name    string `json:"name,omitempty"`
+	Reason  string `json:"reason"`
+
+	// Set by PruneOrphanSessions.
+	Killed  bool   `json:"killed,omitempty"`
+	Skipped string `json:"skipped,omitempty"` // Why a confirmed orphan was kept
+	Error   string `json:"error,omitempty"`
+}
+
+// PruneOptions configures PruneOrphanSessions.
+type PruneOptions struct {
+	// DryRun reports orphans without killing them.
+	DryRun bool
+
+	// IncludeCrew also kills orphaned crew sessions. Crew workers are
+	// human-managed, so they are only reported by default.
+	IncludeCrew bool
+
+	// MinAge keeps orphans younger than this (default DefaultPruneMinAge).
+	MinAge time.Duration
+
+	// Caller is recorded in the session death event (e.g. "gt prune sessions").
+	Caller string
+}
+
+// PruneResult summarizes a PruneOrphanSessions run.
+type PruneResult struct {
+	Checked int             `json:"checked"` // Rig-prefixed sessions examined
+	Orphans []OrphanSession `json:"orphans"`
+}
+
+// FindOrphanSessions returns the sessions among sessions that carry a rig
+// prefix (a prefix registered in rigs.json, or the default "gt") but that no
+// known agent accounts for: the rig is not in rigs.json, or the polecat or
+// crew member has no directory under the rig. Town-level hq- sessions are
+// never reported. Returns the number of rig-prefixed sessions examined.
+func FindOrphanSessions(townRoot string, sessions []string) ([]OrphanSession, int, error) {
+	rigsPath := rigsconfig.Path(townRoot)
+	rigs, err := rigsconfig.Load(townRoot)
+	if err != nil {
+		// Without rigs.json every session would look orphaned.
+		if errors.Is(err, rigsconfig.ErrNotFound) {
+			return nil, 0, fmt.Errorf("no rigs.json at %s; refusing to prune", rigsPath)
+		}
+		return nil, 0, err
+	}
+	registry, err := BuildPrefixRegistryFromFile(rigsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Rigs without a beads prefix use DefaultPrefix, so a "gt-" session may
+	// belong to any of them.
+	var defaultRigs []string
+	for name := range rigs.Rigs {
+		if registry.PrefixForRig(name) == DefaultPrefix {
+			defaultRigs = append(defaultRigs, name)
+		}
+	}
+	sort.Strings(defaultRigs)
+
+	var orphans []OrphanSession
+	checked := 0
+	for _, sess := range sessions {
+		if sess == "" || strings.HasPrefix(sess, HQPrefix) {
+			continue
+		}
+		if !registry.HasPrefix(sess) && !strings.HasPrefix(sess, DefaultPrefix+"-") {
+			continue // Not a Gas Town session
+		}
+		identity, err := ParseSessionNameWithRegistry(sess, registry)
+		if err != nil {
+			continue
+		}
+		checked++
+
+		candidates := []string{identity.Rig}
+		if identity.Prefix == DefaultPrefix && registry.RigForPrefix(DefaultPrefix) == DefaultPrefix {
+			candidates = defaultRigs
+		}
+		reason := ""
+		for _, rig := range candidates {
+			if reason = orphanReason(townRoot, rigs, rig, identity); reason == "" {
+				break
+			}
+		}
+		if len(candidates) == 0 {
+			reason = fmt.Sprintf("no rig uses prefix %q", identity.Prefix)
+		}
+		if reason == "" {
+			continue
+		}
+		rig := identity.Rig
+		if len(candidates) == 1 {
+			rig = candidates[0]
+		}
+		orphans = append(orphans, OrphanSession{
+			Session: sess,
+			Rig:     rig,
+			Role:    identity.Role,
+			Name:    identity.Name,
+			Reason:  reason,
+		})
+	}
+	return orphans, checked, nil
+}
+
+// orphanReason returns why identity is not a known agent of rig, or "" if it is.
+func orphanReason(townRoot string, rigs *config.RigsConfig, rig string, identity *AgentIdentity) string {
+	if _, ok := rigs.Rigs[rig]; !ok {
+		return fmt.Sprintf("rig %q is not in rigs.json", rig)
+	}
+	switch identity.Role {
+	case RolePolecat:
+		if !dirExists(filepath.Join(townRoot, rig, "polecats", identity.Name)) {
+			return fmt.Sprintf("no polecat %q in %s/polecats", identity.Name, rig)
+		}
+	case RoleCrew:
+		if !dirExists(filepath.Join(townRoot, rig, "crew", identity.Name)) {
+			return fmt.Sprintf("no crew member %q in %s/crew", identity.Name, rig)
+		}
+	}
+	return ""
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// PruneOrphanSessions finds orphaned rig sessions (see FindOrphanSessions)
+// and kills them with their processes, logging a session death event for
+// each. Crew sessions (unless IncludeCrew) and sessions younger than MinAge
+// are reported but kept.
+func PruneOrphanSessions(townRoot string, opts PruneOptions) (*PruneResult, error) {
+	if opts.MinAge == 0 {
+		opts.MinAge = DefaultPruneMinAge
+	}
+	if opts.Caller == "" {
+		opts.Caller = "gt prune sessions"
+	}
+
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing tmux sessions: %w", err)
+	}
+	orphans, checked, err := FindOrphanSessions(townRoot, sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PruneResult{Checked: checked, Orphans: orphans}
+	for i := range result.Orphans {
+		o := &result.Orphans[i]
+		if o.Role == RoleCrew && !opts.IncludeCrew {
+			o.Skipped = "crew session"
+			continue
+		}
+		if created, err := SessionCreatedAt(o.Session); err == nil && time.Since(created) < opts.MinAge {
+			o.Skipped = fmt.Sprintf("younger than %s", opts.MinAge)
+			continue
+		}
+		if opts.DryRun {
+			continue
+		}
+
+		// Log pre-death event for crash investigation (before killing)
+		_ = events.LogFeed(events.TypeSessionDeath, o.Session,
+			events.SessionDeathPayload(o.Session, string(o.Role), "orphan prune: "+o.Reason, opts.Caller))
+		if err := t.KillSessionWithProcesses(o.Session); err != nil {
+			o.Error = err.Error()
+			continue
+		}
+		o.Killed = true
+	}
+	return result, nil
+}
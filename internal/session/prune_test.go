@@ -0,0 +1,94 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindOrphanSessions(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigsJSON := `{"version": 1, "rigs": {
+		"gastown": {"git_url": "x"},
+		"beads": {"git_url": "y", "beads": {"prefix": "bd"}}
+	}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"gastown/polecats/furiosa", "gastown/crew/max", "beads/polecats/nux"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions := []string{
+		"hq-mayor",    // Town-level: never considered
+		"scratch",     // Not a Gas Town session
+		"gt-witness",  // Rig agent of gastown (default prefix)
+		"gt-furiosa",  // Known polecat
+		"gt-crew-max", // Known crew member
+		"bd-nux",      // Known polecat of beads
+		"gt-slit",     // Nuked polecat
+		"gt-crew-dag", // Crew dir removed
+		"bd-refinery", // Rig agent of beads
+		"bd-toast",    // Nuked polecat of beads
+	}
+	orphans, checked, err := FindOrphanSessions(townRoot, sessions)
+	if err != nil {
+		t.Fatalf("FindOrphanSessions() error = %v", err)
+	}
+	if checked != 8 {
+		t.Errorf("checked = %d, want 8", checked)
+	}
+
+	got := map[string]OrphanSession{}
+	for _, o := range orphans {
+		got[o.Session] = o
+	}
+	want := map[string]Role{"gt-slit": RolePolecat, "gt-crew-dag": RoleCrew, "bd-toast": RolePolecat}
+	if len(got) != len(want) {
+		t.Errorf("orphans = %+v, want %v", orphans, want)
+	}
+	for sess, role := range want {
+		o, ok := got[sess]
+		if !ok {
+			t.Errorf("%s not reported as orphan", sess)
+			continue
+		}
+		if o.Role != role || o.Reason == "" {
+			t.Errorf("%s = %+v, want role %s with a reason", sess, o, role)
+		}
+	}
+	if o := got["bd-toast"]; o.Rig != "beads" {
+		t.Errorf("bd-toast rig = %q, want beads", o.Rig)
+	}
+}
+
+func TestFindOrphanSessionsRemovedRig(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The only rig has its own prefix, so nothing claims gt- anymore.
+	rigsJSON := `{"version": 1, "rigs": {"beads": {"git_url": "y", "beads": {"prefix": "bd"}}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans, _, err := FindOrphanSessions(townRoot, []string{"gt-witness", "bd-witness"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 1 || orphans[0].Session != "gt-witness" {
+		t.Errorf("orphans = %+v, want only gt-witness", orphans)
+	}
+}
+
+func TestFindOrphanSessionsNoRigsJSON(t *testing.T) {
+	if _, _, err := FindOrphanSessions(t.TempDir(), []string{"gt-witness"}); err == nil {
+		t.Error("expected an error without rigs.json")
+	}
+}
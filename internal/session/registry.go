@@ -2,12 +2,12 @@
 package session
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
+	"errors"
 	"sort"
 	"strings"
 	"sync"
+
+	"github.com/steveyegge/gastown/internal/rigsconfig"
 )
 
 // PrefixRegistry maps beads prefixes to rig names and vice versa.
@@ -104,43 +104,24 @@ func PrefixFor(rigName string) string {
 // BuildPrefixRegistryFromTown reads rigs.json from a town root directory
 // and returns a populated PrefixRegistry.
 func BuildPrefixRegistryFromTown(townRoot string) (*PrefixRegistry, error) {
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	return BuildPrefixRegistryFromFile(rigsPath)
-}
-
-// rigsJSON is the minimal structure for reading rigs.json prefix data.
-type rigsJSON struct {
-	Rigs map[string]rigEntry `json:"rigs"`
-}
-
-type rigEntry struct {
-	Beads *beadsEntry `json:"beads,omitempty"`
-}
-
-type beadsEntry struct {
-	Prefix string `json:"prefix"`
+	return BuildPrefixRegistryFromFile(rigsconfig.Path(townRoot))
 }
 
 // BuildPrefixRegistryFromFile reads a rigs.json file and returns a PrefixRegistry.
 func BuildPrefixRegistryFromFile(path string) (*PrefixRegistry, error) {
 	r := NewPrefixRegistry()
 
-	data, err := os.ReadFile(path)
+	rigs, err := rigsconfig.LoadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, rigsconfig.ErrNotFound) {
 			return r, nil
 		}
 		return nil, err
 	}
 
-	var rigs rigsJSON
-	if err := json.Unmarshal(data, &rigs); err != nil {
-		return nil, err
-	}
-
 	for rigName, entry := range rigs.Rigs {
-		if entry.Beads != nil && entry.Beads.Prefix != "" {
-			r.Register(entry.Beads.Prefix, rigName)
+		if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+			r.Register(entry.BeadsConfig.Prefix, rigName)
 		}
 	}
 
@@ -0,0 +1,108 @@
+package supportbundle
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/redact"
+	"github.com/steveyegge/gastown/internal/rigsconfig"
+)
+
+// Options controls which artifacts are collected and their size limits.
+type Options struct {
+	// MaxArtifactBytes caps each config and state artifact.
+	MaxArtifactBytes int64
+
+	// LogTailBytes is how much of the end of each log to include.
+	LogTailBytes int64
+
+	// EventLines is how many recent events to include.
+	EventLines int
+
+	// Transcripts is how many of the most recently archived session
+	// transcripts to include. 0 includes none.
+	Transcripts int
+}
+
+// DefaultOptions returns the default collection limits.
+func DefaultOptions() Options {
+	return Options{
+		MaxArtifactBytes: DefaultMaxArtifactBytes,
+		LogTailBytes:     DefaultLogTailBytes,
+		EventLines:       DefaultEventLines,
+	}
+}
+
+// CollectTown adds the standard file-based artifacts for a town: daemon and
+// Dolt log tails, runtime state files, redacted configuration, and recent
+// events. Missing files are recorded in the manifest rather than failing.
+func CollectTown(b *Bundle, townRoot string, opts Options) {
+	doltCfg := doltserver.DefaultConfig(townRoot)
+	daemonCfg := daemon.DefaultConfig(townRoot)
+
+	// Logs (tails only)
+	b.AddFileTail("logs/daemon.log", daemonCfg.LogFile, opts.LogTailBytes)
+	b.AddFileTail("logs/dolt.log", doltCfg.LogFile, opts.LogTailBytes)
+
+	// Runtime state
+	b.AddFile("state/daemon-state.json", daemon.StateFile(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("state/dolt-state.json", doltserver.StateFile(townRoot), opts.MaxArtifactBytes)
+
+	// Configuration (secrets redacted by the bundle)
+	b.AddFile("config/town.json", constants.MayorTownPath(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("config/rigs.json", constants.MayorRigsPath(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("config/mayor-config.json", constants.MayorConfigPath(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("config/daemon.json", config.DaemonPatrolConfigPath(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("config/settings.json", config.TownSettingsPath(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("config/escalation.json", config.EscalationConfigPath(townRoot), opts.MaxArtifactBytes)
+	b.AddFile("config/messaging.json", config.MessagingConfigPath(townRoot), opts.MaxArtifactBytes)
+	CollectFeatureFlags(b, townRoot, opts.MaxArtifactBytes)
+
+	// Recent events
+	b.AddFileLastLines("events/events.jsonl", filepath.Join(townRoot, events.EventsFile), opts.EventLines, opts.MaxArtifactBytes)
+
+	if opts.Transcripts > 0 {
+		CollectTranscripts(b, townRoot, opts.Transcripts, opts.LogTailBytes)
+	}
+}
+
+// CollectFeatureFlags adds the feature flags in effect for the town and
+// each rig that overrides them.
+func CollectFeatureFlags(b *Bundle, townRoot string, limit int64) {
+	rigs, _ := rigsconfig.Names(townRoot)
+	data, err := json.MarshalIndent(config.ResolveFeatureFlags(townRoot, rigs), "", "  ")
+	if err != nil {
+		b.addError("config/feature-flags.json", "feature flags", err)
+		return
+	}
+	b.AddBytes("config/feature-flags.json", data, limit)
+}
+
+// CollectTranscripts adds the tails of the n most recently archived session
+// transcripts, with their redaction reports. Archived transcripts were
+// redacted with their role's rules when archived, and are redacted again
+// as they are added.
+func CollectTranscripts(b *Bundle, townRoot string, n int, limit int64) {
+	archived, err := redact.ListArchivedTranscripts(townRoot)
+	if err != nil {
+		b.addError("transcripts", redact.TranscriptsDir(townRoot), err)
+		return
+	}
+	if len(archived) > n {
+		archived = archived[:n]
+	}
+	for _, a := range archived {
+		name := path.Join("transcripts", a.Session, filepath.Base(a.Path))
+		b.AddFileTail(name, a.Path, limit)
+		if report, err := json.MarshalIndent(a, "", "  "); err == nil {
+			b.AddBytes(strings.TrimSuffix(name, ".jsonl")+".redactions.json", report, 0)
+		}
+	}
+}
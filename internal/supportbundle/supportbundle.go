@@ -0,0 +1,268 @@
+// Package supportbundle packages sanitized Gas Town diagnostics into a
+// tarball suitable for attaching to bug reports.
+//
+// A bundle is a gzipped tar containing a manifest.json plus one file per
+// artifact (log tails, state files, redacted config, recent events, doctor
+// output). Every artifact is size-limited and every text artifact is
+// redacted (see package redact) before it is written, so bundles can be
+// shared publicly.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/redact"
+)
+
+// Default size limits.
+const (
+	DefaultMaxArtifactBytes = 1 << 20   // 1 MiB per config/state artifact
+	DefaultLogTailBytes     = 256 << 10 // 256 KiB from the end of each log
+	DefaultEventLines       = 500       // most recent events
+)
+
+// Entry describes one artifact in the bundle manifest.
+type Entry struct {
+	// Name is the path of the artifact inside the bundle.
+	Name string `json:"name"`
+
+	// Source is where the artifact came from (file path or "generated").
+	Source string `json:"source,omitempty"`
+
+	// Size is the number of bytes written to the bundle.
+	Size int64 `json:"size"`
+
+	// OriginalSize is the size before truncation.
+	OriginalSize int64 `json:"original_size"`
+
+	// Truncated is true when the artifact exceeded its size limit.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Redactions is the number of secrets replaced in the artifact.
+	Redactions int `json:"redactions,omitempty"`
+
+	// Error records why an expected artifact could not be collected.
+	Error string `json:"error,omitempty"`
+}
+
+// Manifest is written as manifest.json at the root of the bundle.
+type Manifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"gt_version"`
+	GoVersion string    `json:"go_version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	Entries   []Entry   `json:"artifacts"`
+}
+
+type artifact struct {
+	name string
+	data []byte
+}
+
+// Bundle accumulates artifacts in memory and writes them as a tar.gz.
+type Bundle struct {
+	manifest  Manifest
+	artifacts []artifact
+	redactor  *redact.Redactor
+}
+
+// New creates an empty bundle stamped with the given gt version.
+func New(version string) *Bundle {
+	return &Bundle{
+		manifest: Manifest{
+			CreatedAt: time.Now().UTC(),
+			Version:   version,
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		},
+		redactor: redact.Default(),
+	}
+}
+
+// SetRedactor replaces the redactor applied to artifacts added from now on,
+// e.g. one with the town's configured redaction rules.
+func (b *Bundle) SetRedactor(r *redact.Redactor) {
+	b.redactor = r
+}
+
+// Manifest returns the bundle manifest as collected so far.
+func (b *Bundle) Manifest() Manifest {
+	return b.manifest
+}
+
+// AddBytes adds generated content, redacted and truncated to limit bytes
+// (keeping the head). A limit <= 0 means unlimited.
+func (b *Bundle) AddBytes(name string, data []byte, limit int64) {
+	b.add(name, "generated", data, limit, false)
+}
+
+// AddFile adds a file's content, redacted and truncated to limit bytes
+// (keeping the head). Missing files are recorded in the manifest.
+func (b *Bundle) AddFile(name, srcPath string, limit int64) {
+	data, err := os.ReadFile(srcPath) //nolint:gosec // G304: paths are constructed internally
+	if err != nil {
+		b.addError(name, srcPath, err)
+		return
+	}
+	b.add(name, srcPath, data, limit, false)
+}
+
+// AddFileTail adds the last limit bytes of a file (aligned to a line
+// boundary), redacted. Intended for logs, where recent lines matter most.
+func (b *Bundle) AddFileTail(name, srcPath string, limit int64) {
+	data, err := readTail(srcPath, limit)
+	if err != nil {
+		b.addError(name, srcPath, err)
+		return
+	}
+	b.add(name, srcPath, data.tail, 0, false)
+	b.manifest.Entries[len(b.manifest.Entries)-1].OriginalSize = data.size
+	b.manifest.Entries[len(b.manifest.Entries)-1].Truncated = data.size > int64(len(data.tail))
+}
+
+// AddFileLastLines adds the last n lines of a file, redacted. Used for
+// JSONL event logs where a byte limit would split records.
+func (b *Bundle) AddFileLastLines(name, srcPath string, n int, limit int64) {
+	data, err := os.ReadFile(srcPath) //nolint:gosec // G304: paths are constructed internally
+	if err != nil {
+		b.addError(name, srcPath, err)
+		return
+	}
+	tail := LastLines(data, n)
+	b.add(name, srcPath, tail, limit, true)
+	b.manifest.Entries[len(b.manifest.Entries)-1].OriginalSize = int64(len(data))
+	if len(tail) < len(data) {
+		b.manifest.Entries[len(b.manifest.Entries)-1].Truncated = true
+	}
+}
+
+func (b *Bundle) add(name, source string, data []byte, limit int64, keepTail bool) {
+	entry := Entry{Name: name, Source: source, OriginalSize: int64(len(data))}
+
+	redacted, report := b.redactor.Redact(data)
+	entry.Redactions = report.Total
+
+	if limit > 0 && int64(len(redacted)) > limit {
+		entry.Truncated = true
+		if keepTail {
+			redacted = redacted[int64(len(redacted))-limit:]
+		} else {
+			redacted = redacted[:limit]
+		}
+	}
+	entry.Size = int64(len(redacted))
+
+	b.manifest.Entries = append(b.manifest.Entries, entry)
+	b.artifacts = append(b.artifacts, artifact{name: name, data: redacted})
+}
+
+func (b *Bundle) addError(name, source string, err error) {
+	msg := err.Error()
+	if os.IsNotExist(err) {
+		msg = "not present"
+	}
+	b.manifest.Entries = append(b.manifest.Entries, Entry{Name: name, Source: source, Error: msg})
+}
+
+// Write writes the bundle as a gzipped tar. All entries are placed under
+// a top-level directory named root so extraction does not litter the cwd.
+func (b *Bundle) Write(w io.Writer, root string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+	files := append([]artifact{{name: "manifest.json", data: manifestData}}, b.artifacts...)
+
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    path.Join(root, f.name),
+			Mode:    0644,
+			Size:    int64(len(f.data)),
+			ModTime: b.manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("writing %s header: %w", f.name, err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return fmt.Errorf("writing %s: %w", f.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing tar: %w", err)
+	}
+	return gz.Close()
+}
+
+// Redact replaces credentials in data using the built-in rules and returns
+// the sanitized copy with the number of replacements made.
+func Redact(data []byte) ([]byte, int) {
+	out, report := redact.Default().Redact(data)
+	return out, report.Total
+}
+
+// LastLines returns the last n lines of data (n <= 0 returns all of it).
+func LastLines(data []byte, n int) []byte {
+	if n <= 0 {
+		return data
+	}
+	trimmed := bytes.TrimRight(data, "\n")
+	idx := len(trimmed)
+	for i := 0; i < n; i++ {
+		idx = bytes.LastIndexByte(trimmed[:idx], '\n')
+		if idx < 0 {
+			return data
+		}
+	}
+	return data[idx+1:]
+}
+
+type tailResult struct {
+	tail []byte
+	size int64
+}
+
+// readTail reads the last limit bytes of a file, dropping any partial first line.
+func readTail(srcPath string, limit int64) (*tailResult, error) {
+	f, err := os.Open(srcPath) //nolint:gosec // G304: paths are constructed internally
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if limit <= 0 || size <= limit {
+		data, err := io.ReadAll(f)
+		return &tailResult{tail: data, size: size}, err
+	}
+
+	if _, err := f.Seek(size-limit, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 && i+1 < len(data) {
+		data = data[i+1:]
+	}
+	return &tailResult{tail: data, size: size}, nil
+}
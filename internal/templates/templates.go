@@ -64,6 +64,10 @@ type RoleData struct {
 	IssuePrefix    string   // beads issue prefix
 	MayorSession   string   // e.g., "gt-ai-mayor" - dynamic mayor session name
 	DeaconSession  string   // e.g., "gt-ai-deacon" - dynamic deacon session name
+
+	// Vars are the rig's prompt vars (settings/config.json prompt_vars),
+	// e.g. {{ .Vars.test_command }}.
+	Vars map[string]string
 }
 
 // SpawnData contains information for spawn assignment messages.
@@ -83,6 +83,51 @@ func TestRenderRole_Polecat(t *testing.T) {
 	}
 }
 
+func TestRenderRole_PromptVars(t *testing.T) {
+	tmpl, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, role := range []string{"polecat", "crew"} {
+		data := RoleData{
+			Role:     role,
+			RigName:  "myrig",
+			TownRoot: "/test/town",
+			Polecat:  "TestCat",
+			Vars: map[string]string{
+				"test_command":     "make test",
+				"coding_standards": "https://example.com/style",
+			},
+		}
+		output, err := tmpl.RenderRole(role, data)
+		if err != nil {
+			t.Fatalf("RenderRole(%s) error = %v", role, err)
+		}
+		for _, want := range []string{"## Project Guidance", "- **test_command**: make test", "- **coding_standards**: https://example.com/style"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s output missing %q", role, want)
+			}
+		}
+		// Keys are listed in sorted order.
+		if strings.Index(output, "coding_standards") > strings.Index(output, "test_command") {
+			t.Errorf("%s prompt vars not sorted", role)
+		}
+
+		data.Vars = nil
+		output, err = tmpl.RenderRole(role, data)
+		if err != nil {
+			t.Fatalf("RenderRole(%s) error = %v", role, err)
+		}
+		if strings.Contains(output, "Project Guidance") {
+			t.Errorf("%s output has Project Guidance without prompt vars", role)
+		}
+		if !strings.Contains(output, "\n\n## Gas Town Architecture") {
+			t.Errorf("%s output lost the blank line before Gas Town Architecture", role)
+		}
+	}
+}
+
 func TestRenderRole_Deacon(t *testing.T) {
 	tmpl, err := New()
 	if err != nil {
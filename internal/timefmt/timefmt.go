@@ -0,0 +1,242 @@
+// Package timefmt formats times and durations for CLI output consistently:
+// relative times ("3m ago"), durations rounded to the two largest units
+// ("2h5m", "3d4h"), and timestamps in local time or, with gt --utc, UTC.
+//
+// Everything it prints, Parse and ParseDuration read back, so values copied
+// from output can be passed to flags like --since.
+package timefmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+// Layouts used for output.
+const (
+	DateLayout      = "2006-01-02"
+	TimestampLayout = "2006-01-02 15:04:05"
+	ClockLayout     = "15:04:05"
+)
+
+var (
+	mu  sync.RWMutex
+	utc bool
+	clk clock.Clock = clock.Real{}
+)
+
+// SetUTC makes output use UTC instead of the local time zone (gt --utc).
+func SetUTC(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	utc = on
+}
+
+// UTC reports whether output uses UTC.
+func UTC() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return utc
+}
+
+// SetClock replaces the clock relative times are measured against.
+// Returns a func that restores the previous one.
+func SetClock(c clock.Clock) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := clk
+	clk = c
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		clk = prev
+	}
+}
+
+func now() time.Time {
+	mu.RLock()
+	defer mu.RUnlock()
+	return clk.Now()
+}
+
+// Location returns the time zone output is shown in.
+func Location() *time.Location {
+	if UTC() {
+		return time.UTC
+	}
+	return time.Local
+}
+
+// In converts t to the output time zone.
+func In(t time.Time) time.Time {
+	return t.In(Location())
+}
+
+// Timestamp formats t as "2006-01-02 15:04:05" in the output time zone,
+// with a trailing "Z" in UTC mode. Returns "" for the zero time.
+func Timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	s := In(t).Format(TimestampLayout)
+	if UTC() {
+		s += "Z"
+	}
+	return s
+}
+
+// Date formats t as "2006-01-02" in the output time zone, so day
+// boundaries follow --utc.
+func Date(t time.Time) string {
+	return In(t).Format(DateLayout)
+}
+
+// Clock formats t as "15:04:05" in the output time zone.
+func Clock(t time.Time) string {
+	return In(t).Format(ClockLayout)
+}
+
+// Ago formats t relative to now: "just now", "3m ago", "2h5m ago", or
+// "in 10m" for future times. Returns "never" for the zero time.
+func Ago(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := now().Sub(t)
+	switch {
+	case d < 0 && -d >= time.Second:
+		return "in " + Duration(-d)
+	case d < 10*time.Second:
+		return "just now"
+	default:
+		return Duration(d) + " ago"
+	}
+}
+
+// TimestampAgo formats t as a timestamp followed by its relative time:
+// "2026-03-10 12:00:00 (3m ago)".
+func TimestampAgo(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s)", Timestamp(t), Ago(t))
+}
+
+// Duration formats d with at most its two largest units, rounding down:
+// "45s", "12m", "2h5m", "3d4h". Durations under a second print as "0s"
+// unless they are at least a millisecond ("250ms").
+func Duration(d time.Duration) string {
+	neg := d < 0
+	if neg {
+		d = -d
+	}
+	var s string
+	switch {
+	case d < time.Millisecond:
+		s = "0s"
+	case d < time.Second:
+		s = fmt.Sprintf("%dms", d/time.Millisecond)
+	case d < time.Minute:
+		s = fmt.Sprintf("%ds", d/time.Second)
+	case d < time.Hour:
+		s = twoUnits(int64(d/time.Minute), "m", int64(d%time.Minute/time.Second), "s")
+	case d < 24*time.Hour:
+		s = twoUnits(int64(d/time.Hour), "h", int64(d%time.Hour/time.Minute), "m")
+	default:
+		s = twoUnits(int64(d/(24*time.Hour)), "d", int64(d%(24*time.Hour)/time.Hour), "h")
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// twoUnits formats a major and minor unit, dropping a zero minor unit and
+// the seconds of durations of ten minutes or more.
+func twoUnits(major int64, majorUnit string, minor int64, minorUnit string) string {
+	if minor == 0 || (minorUnit == "s" && major >= 10) {
+		return fmt.Sprintf("%d%s", major, majorUnit)
+	}
+	return fmt.Sprintf("%d%s%d%s", major, majorUnit, minor, minorUnit)
+}
+
+// parseLayouts are tried in order by Parse. Layouts without a zone are
+// read in the output time zone.
+var parseLayouts = []struct {
+	layout string
+	zoned  bool
+}{
+	{time.RFC3339Nano, true},
+	{"2006-01-02 15:04:05Z07:00", true},
+	{"2006-01-02 15:04:05.999999999", false},
+	{"2006-01-02T15:04:05", false},
+	{"2006-01-02 15:04", false},
+	{DateLayout, false},
+}
+
+// Parse reads a time as printed by Timestamp, Date, or in RFC3339. It
+// never depends on the machine's locale: month and zone names are not
+// accepted, and times without an offset are read in the output time zone
+// (UTC with --utc), as Timestamp prints them. A relative "<duration> ago"
+// (as printed by Ago) is also accepted.
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "just now" {
+		return now(), nil
+	}
+	if rel, ok := strings.CutSuffix(s, " ago"); ok {
+		d, err := ParseDuration(rel)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parsing time %q: %w", s, err)
+		}
+		return now().Add(-d), nil
+	}
+	for _, l := range parseLayouts {
+		if l.zoned {
+			if t, err := time.Parse(l.layout, s); err == nil {
+				return t, nil
+			}
+			continue
+		}
+		if t, err := time.ParseInLocation(l.layout, s, Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("parsing time %q: want YYYY-MM-DD, YYYY-MM-DD HH:MM[:SS], RFC3339, or <duration> ago", s)
+}
+
+// ParseDuration reads a duration as printed by Duration, or anything
+// time.ParseDuration accepts. A "d" unit means 24 hours: "3d4h".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	rest := strings.TrimPrefix(s, "-")
+
+	var days time.Duration
+	if i := strings.Index(rest, "d"); i > 0 {
+		n, err := strconv.Atoi(rest[:i])
+		if err == nil {
+			days = time.Duration(n) * 24 * time.Hour
+			rest = rest[i+1:]
+		}
+	}
+	var d time.Duration
+	if rest != "" {
+		var err error
+		d, err = time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	} else if days == 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	d += days
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
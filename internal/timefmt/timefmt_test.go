@@ -0,0 +1,120 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/clock"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{250 * time.Millisecond, "250ms"},
+		{45 * time.Second, "45s"},
+		{3*time.Minute + 20*time.Second, "3m20s"},
+		{12*time.Minute + 20*time.Second, "12m"},
+		{2 * time.Hour, "2h"},
+		{2*time.Hour + 5*time.Minute + 59*time.Second, "2h5m"},
+		{3*24*time.Hour + 4*time.Hour + 30*time.Minute, "3d4h"},
+		{-90 * time.Second, "-1m30s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.d); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration_RoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{
+		45 * time.Second,
+		3*time.Minute + 20*time.Second,
+		2*time.Hour + 5*time.Minute,
+		3*24*time.Hour + 4*time.Hour,
+		7 * 24 * time.Hour,
+		-90 * time.Second,
+	} {
+		got, err := ParseDuration(Duration(d))
+		if err != nil || got != d {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", Duration(d), got, err, d)
+		}
+	}
+	for _, bad := range []string{"", "d", "3x", "ago"} {
+		if _, err := ParseDuration(bad); err == nil {
+			t.Errorf("ParseDuration(%q): expected error", bad)
+		}
+	}
+}
+
+func TestAgo(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	defer SetClock(clock.NewFake(now))()
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Time{}, "never"},
+		{now.Add(-3 * time.Second), "just now"},
+		{now.Add(-3 * time.Minute), "3m ago"},
+		{now.Add(-26 * time.Hour), "1d2h ago"},
+		{now.Add(10 * time.Minute), "in 10m"},
+	}
+	for _, tt := range tests {
+		if got := Ago(tt.t); got != tt.want {
+			t.Errorf("Ago(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+
+	got, err := Parse("2h5m ago")
+	if err != nil || !got.Equal(now.Add(-2*time.Hour-5*time.Minute)) {
+		t.Errorf(`Parse("2h5m ago") = %v, %v`, got, err)
+	}
+}
+
+func TestTimestamp_UTC(t *testing.T) {
+	SetUTC(true)
+	defer SetUTC(false)
+
+	ts := time.Date(2026, 3, 10, 7, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	if got := Timestamp(ts); got != "2026-03-10 12:30:00Z" {
+		t.Errorf("Timestamp = %q, want 2026-03-10 12:30:00Z", got)
+	}
+	if got := Date(time.Date(2026, 3, 10, 22, 0, 0, 0, time.FixedZone("EST", -5*3600))); got != "2026-03-11" {
+		t.Errorf("Date = %q, want the UTC day 2026-03-11", got)
+	}
+	if Timestamp(time.Time{}) != "" {
+		t.Error("Timestamp of zero time should be empty")
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	ts := time.Date(2026, 3, 10, 12, 30, 15, 0, time.UTC)
+	for _, utcMode := range []bool{false, true} {
+		SetUTC(utcMode)
+		got, err := Parse(Timestamp(ts))
+		if err != nil || !got.Equal(ts) {
+			t.Errorf("utc=%v: Parse(%q) = %v, %v; want %v", utcMode, Timestamp(ts), got, err, ts)
+		}
+		day, err := Parse(Date(ts))
+		if err != nil || Date(day) != Date(ts) {
+			t.Errorf("utc=%v: Parse(%q) = %v, %v", utcMode, Date(ts), day, err)
+		}
+	}
+	SetUTC(false)
+
+	for _, s := range []string{"2026-03-10T12:30:15Z", "2026-03-10T12:30:15.5+02:00", "2026-03-10 12:30"} {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+		}
+	}
+	for _, bad := range []string{"10 Mar 2026", "March 10", "yesterday"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): expected error", bad)
+		}
+	}
+}
@@ -516,6 +516,33 @@ func (t *Tmux) KillPaneProcesses(pane string) error {
 	return nil
 }
 
+// SignalPaneProcesses sends a signal to a pane's process and all its
+// descendants. signal is a kill(1) signal name such as "STOP" or "CONT";
+// stopping a pane freezes its agent in place without losing any state.
+// The pane process is signalled first so it cannot spawn new children
+// while its existing ones are being signalled.
+func (t *Tmux) SignalPaneProcesses(pane, signal string) error {
+	pid, err := t.GetPanePID(pane)
+	if err != nil {
+		return fmt.Errorf("getting pane PID: %w", err)
+	}
+
+	// getAllDescendants returns deepest-first; signal top-down instead.
+	descendants := getAllDescendants(pid)
+	pids := []string{pid}
+	for i := len(descendants) - 1; i >= 0; i-- {
+		pids = append(pids, descendants[i])
+	}
+
+	var firstErr error
+	for _, p := range pids {
+		if err := exec.Command("kill", "-"+signal, p).Run(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("sending SIG%s to %s: %w", signal, p, err)
+		}
+	}
+	return firstErr
+}
+
 // KillPaneProcessesExcluding is like KillPaneProcesses but excludes specified PIDs
 // from being killed. This is essential for self-handoff scenarios where the calling
 // process (e.g., gt handoff running inside Claude Code) needs to survive long enough
@@ -1914,6 +1941,23 @@ func (t *Tmux) SetRemainOnExit(pane string, on bool) error {
 	return err
 }
 
+// PaneDeadStatus reports whether a session's pane has exited (only possible
+// with remain-on-exit on) and, if so, the exit status of its process.
+func (t *Tmux) PaneDeadStatus(session string) (dead bool, status int, err error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_dead} #{pane_dead_status}")
+	if err != nil {
+		return false, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 || fields[0] != "1" {
+		return false, 0, nil
+	}
+	if len(fields) > 1 {
+		status, _ = strconv.Atoi(fields[1])
+	}
+	return true, status, nil
+}
+
 // SwitchClient switches the current tmux client to a different session.
 // Used after remote recycle to move the user's view to the recycled session.
 func (t *Tmux) SwitchClient(targetSession string) error {
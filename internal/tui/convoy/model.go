@@ -15,6 +15,8 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // convoyIDPattern validates convoy IDs.
@@ -99,7 +101,7 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -157,7 +159,7 @@ func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return nil, 0, 0
 	}
 
@@ -227,7 +229,7 @@ func refreshIssueStatus(ctx context.Context, tracked []struct {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return nil
 	}
 
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/proc"
 )
 
 // convoyIDPattern validates convoy IDs.
@@ -98,7 +99,7 @@ func listConvoys(beadsDir, status string) ([]convoyListItem, error) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return nil, err
 	}
 
@@ -182,7 +183,7 @@ func getTrackedIssueStatus(beadsDir, convoyID string) []trackedStatus {
 	cmd.Dir = beadsDir
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return nil
 	}
 
@@ -235,7 +236,7 @@ func refreshTrackedStatus(ctx context.Context, deps []struct {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := proc.RunCmdContext(ctx, cmd); err != nil {
 		return nil
 	}
 
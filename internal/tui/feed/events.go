@@ -268,7 +268,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 		case <-ticker.C:
 			for scanner.Scan() {
 				line := scanner.Text()
-				if event := parseGtEventLine(line); event != nil {
+				if event := ParseGtEventLine(line); event != nil {
 					select {
 					case s.events <- *event:
 					default:
@@ -290,8 +290,9 @@ func (s *GtEventsSource) Close() error {
 	return s.file.Close()
 }
 
-// parseGtEventLine parses a line from .events.jsonl
-func parseGtEventLine(line string) *Event {
+// ParseGtEventLine parses a line from .events.jsonl. It returns nil for blank
+// or malformed lines and for events not visible in the feed.
+func ParseGtEventLine(line string) *Event {
 	if strings.TrimSpace(line) == "" {
 		return nil
 	}
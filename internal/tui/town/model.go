@@ -0,0 +1,509 @@
+package town
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// refreshInterval is how often all panes are reloaded.
+	refreshInterval = 5 * time.Second
+
+	// eventTail is the number of recent events requested for the events pane.
+	eventTail = 50
+
+	// peekLines is the number of lines captured when peeking at an agent.
+	peekLines = 200
+)
+
+// Agent is a row in the agents pane.
+type Agent struct {
+	Address    string // e.g., "gastown/witness"
+	Session    string // tmux session name
+	Role       string
+	Running    bool
+	Work       string // Hooked bead title or ID
+	State      string // Agent state from the agent bead
+	UnreadMail int
+}
+
+// ReadyItem is a row in the ready queue pane.
+type ReadyItem struct {
+	ID       string
+	Title    string
+	Priority int
+	Source   string // "town" or rig name
+}
+
+// DoltHealth is the content of the Dolt health pane.
+type DoltHealth struct {
+	Running        bool
+	Healthy        bool
+	ReadOnly       bool
+	Connections    int
+	MaxConnections int
+	Latency        time.Duration
+	Disk           string
+	Warnings       []string
+}
+
+// Event is a row in the recent events pane.
+type Event struct {
+	Time    time.Time
+	Type    string
+	Actor   string
+	Summary string
+}
+
+// Providers supplies the TUI's data and actions. gt tui wires these to the
+// code behind gt status, gt ready, and gt dolt status, so the panes always
+// agree with those commands' JSON output. Nil providers are skipped.
+type Providers struct {
+	Agents func() ([]Agent, error)
+	Ready  func() ([]ReadyItem, error)
+	Dolt   func() (*DoltHealth, error)
+	// Events returns up to n recent events, newest first.
+	Events func(n int) ([]Event, error)
+
+	// Peek returns recent output from a session.
+	Peek func(session string, lines int) (string, error)
+	// Nudge sends a message to a session.
+	Nudge func(session, message string) error
+	// Sling assigns a bead to a target (rig or agent), returning gt's output.
+	Sling func(bead, target string) (string, error)
+}
+
+// pane identifies one of the four panes.
+type pane int
+
+const (
+	paneAgents pane = iota
+	paneReady
+	paneDolt
+	paneEvents
+	numPanes
+)
+
+// mode is what the keyboard is currently driving.
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeNudge       // typing a nudge message
+	modeSling       // typing a sling target
+	modePeek        // viewing captured session output
+)
+
+// Model is the bubbletea model for the town TUI.
+type Model struct {
+	providers Providers
+
+	agents []Agent
+	ready  []ReadyItem
+	dolt   *DoltHealth
+	events []Event
+	errs   [numPanes]error
+
+	focus   pane
+	cursors [numPanes]int
+
+	// Action state
+	mode       mode
+	input      textinput.Model
+	target     string // Session being nudged or bead being slung
+	peekTitle  string
+	peekOutput string
+	status     string // Result of the last action
+	statusErr  bool
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+
+	// mu protects all fields read by View() from concurrent access.
+	// Write lock is held during Update mutations; read lock during View/render.
+	mu sync.RWMutex
+}
+
+// New creates a new town TUI model.
+func New(providers Providers) *Model {
+	input := textinput.New()
+	input.CharLimit = 500
+	return &Model{
+		providers: providers,
+		keys:      DefaultKeyMap(),
+		help:      help.New(),
+		input:     input,
+	}
+}
+
+// Init initializes the model.
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(m.refresh(), tick())
+}
+
+// tickMsg triggers a periodic refresh.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// Fetch results, one per pane.
+type (
+	agentsMsg struct {
+		agents []Agent
+		err    error
+	}
+	readyMsg struct {
+		ready []ReadyItem
+		err   error
+	}
+	doltMsg struct {
+		dolt *DoltHealth
+		err  error
+	}
+	eventsMsg struct {
+		events []Event
+		err    error
+	}
+)
+
+// peekMsg carries captured session output.
+type peekMsg struct {
+	title  string
+	output string
+	err    error
+}
+
+// actionMsg reports the outcome of a nudge or sling.
+type actionMsg struct {
+	status string
+	err    error
+}
+
+// refresh reloads every pane in parallel.
+func (m *Model) refresh() tea.Cmd {
+	p := m.providers
+	var cmds []tea.Cmd
+	if p.Agents != nil {
+		cmds = append(cmds, func() tea.Msg {
+			agents, err := p.Agents()
+			return agentsMsg{agents: agents, err: err}
+		})
+	}
+	if p.Ready != nil {
+		cmds = append(cmds, func() tea.Msg {
+			ready, err := p.Ready()
+			return readyMsg{ready: ready, err: err}
+		})
+	}
+	if p.Dolt != nil {
+		cmds = append(cmds, func() tea.Msg {
+			dolt, err := p.Dolt()
+			return doltMsg{dolt: dolt, err: err}
+		})
+	}
+	if p.Events != nil {
+		cmds = append(cmds, func() tea.Msg {
+			events, err := p.Events(eventTail)
+			return eventsMsg{events: events, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update handles messages.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.mu.Lock()
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.input.Width = msg.Width - 20
+		m.mu.Unlock()
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.refresh(), tick())
+
+	case agentsMsg:
+		m.mu.Lock()
+		m.agents, m.errs[paneAgents] = msg.agents, msg.err
+		m.clampCursorLocked(paneAgents)
+		m.mu.Unlock()
+		return m, nil
+
+	case readyMsg:
+		m.mu.Lock()
+		m.ready, m.errs[paneReady] = msg.ready, msg.err
+		m.clampCursorLocked(paneReady)
+		m.mu.Unlock()
+		return m, nil
+
+	case doltMsg:
+		m.mu.Lock()
+		m.dolt, m.errs[paneDolt] = msg.dolt, msg.err
+		m.mu.Unlock()
+		return m, nil
+
+	case eventsMsg:
+		m.mu.Lock()
+		m.events, m.errs[paneEvents] = msg.events, msg.err
+		m.clampCursorLocked(paneEvents)
+		m.mu.Unlock()
+		return m, nil
+
+	case peekMsg:
+		m.mu.Lock()
+		if msg.err != nil {
+			m.setStatusLocked(fmt.Sprintf("peek %s: %v", msg.title, msg.err), true)
+		} else {
+			m.mode = modePeek
+			m.peekTitle = msg.title
+			m.peekOutput = strings.TrimRight(msg.output, "\n")
+		}
+		m.mu.Unlock()
+		return m, nil
+
+	case actionMsg:
+		m.mu.Lock()
+		if msg.err != nil {
+			m.setStatusLocked(msg.err.Error(), true)
+		} else {
+			m.setStatusLocked(msg.status, false)
+		}
+		m.mu.Unlock()
+		return m, m.refresh()
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleKey dispatches a key press according to the current mode.
+func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return m, tea.Quit
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch m.mode {
+	case modeNudge, modeSling:
+		switch {
+		case key.Matches(msg, m.keys.Cancel):
+			m.endInputLocked()
+			return m, nil
+		case key.Matches(msg, m.keys.Submit):
+			return m, m.submitLocked()
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+
+	case modePeek:
+		if key.Matches(msg, m.keys.Cancel) || key.Matches(msg, m.keys.Quit) {
+			m.mode = modeNormal
+			m.peekOutput = ""
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+
+	case key.Matches(msg, m.keys.NextPane):
+		m.focus = (m.focus + 1) % numPanes
+
+	case key.Matches(msg, m.keys.PrevPane):
+		m.focus = (m.focus + numPanes - 1) % numPanes
+
+	case key.Matches(msg, m.keys.Up):
+		if m.cursors[m.focus] > 0 {
+			m.cursors[m.focus]--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.cursors[m.focus] < m.paneLenLocked(m.focus)-1 {
+			m.cursors[m.focus]++
+		}
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, m.refresh()
+
+	case key.Matches(msg, m.keys.Peek):
+		return m, m.peekLocked()
+
+	case key.Matches(msg, m.keys.Nudge):
+		a, ok := m.selectedAgentLocked()
+		if !ok || m.providers.Nudge == nil {
+			return m, nil
+		}
+		m.beginInputLocked(modeNudge, a.Session, "")
+
+	case key.Matches(msg, m.keys.Sling):
+		item, ok := m.selectedReadyLocked()
+		if !ok || m.providers.Sling == nil {
+			return m, nil
+		}
+		target := item.Source
+		if target == "town" {
+			target = ""
+		}
+		m.beginInputLocked(modeSling, item.ID, target)
+	}
+	return m, nil
+}
+
+// peekLocked captures output from the selected agent.
+// Caller must hold m.mu write lock.
+func (m *Model) peekLocked() tea.Cmd {
+	a, ok := m.selectedAgentLocked()
+	if !ok || m.providers.Peek == nil {
+		return nil
+	}
+	if !a.Running {
+		m.setStatusLocked(fmt.Sprintf("%s is not running", a.Address), true)
+		return nil
+	}
+	peek := m.providers.Peek
+	return func() tea.Msg {
+		output, err := peek(a.Session, peekLines)
+		return peekMsg{title: a.Address, output: output, err: err}
+	}
+}
+
+// beginInputLocked switches to an input mode for target.
+// Caller must hold m.mu write lock.
+func (m *Model) beginInputLocked(md mode, target, value string) {
+	m.mode = md
+	m.target = target
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+// endInputLocked returns to normal mode.
+// Caller must hold m.mu write lock.
+func (m *Model) endInputLocked() {
+	m.mode = modeNormal
+	m.target = ""
+	m.input.Blur()
+	m.input.Reset()
+}
+
+// submitLocked runs the nudge or sling for the current input.
+// Caller must hold m.mu write lock.
+func (m *Model) submitLocked() tea.Cmd {
+	value := strings.TrimSpace(m.input.Value())
+	target, md := m.target, m.mode
+	m.endInputLocked()
+	if value == "" {
+		return nil
+	}
+
+	switch md {
+	case modeNudge:
+		nudge := m.providers.Nudge
+		m.setStatusLocked(fmt.Sprintf("Nudging %s...", target), false)
+		return func() tea.Msg {
+			if err := nudge(target, value); err != nil {
+				return actionMsg{err: fmt.Errorf("nudge %s: %w", target, err)}
+			}
+			return actionMsg{status: fmt.Sprintf("✓ Nudged %s", target)}
+		}
+	case modeSling:
+		sling := m.providers.Sling
+		m.setStatusLocked(fmt.Sprintf("Slinging %s to %s...", target, value), false)
+		return func() tea.Msg {
+			if _, err := sling(target, value); err != nil {
+				return actionMsg{err: fmt.Errorf("sling %s: %w", target, err)}
+			}
+			return actionMsg{status: fmt.Sprintf("✓ Slung %s to %s", target, value)}
+		}
+	}
+	return nil
+}
+
+// setStatusLocked sets the status line. Multi-line messages (such as gt
+// output in an error) are cut to their last line, which usually says why.
+// Caller must hold m.mu write lock.
+func (m *Model) setStatusLocked(status string, isErr bool) {
+	status = strings.TrimSpace(status)
+	if i := strings.LastIndex(status, "\n"); i >= 0 {
+		status = strings.TrimSpace(status[i+1:])
+	}
+	m.status = status
+	m.statusErr = isErr
+}
+
+// selectedAgentLocked returns the agent under the cursor, if the agents pane
+// has focus. Caller must hold m.mu (read or write).
+func (m *Model) selectedAgentLocked() (Agent, bool) {
+	i := m.cursors[paneAgents]
+	if m.focus != paneAgents || i >= len(m.agents) {
+		return Agent{}, false
+	}
+	return m.agents[i], true
+}
+
+// selectedReadyLocked returns the ready item under the cursor, if the ready
+// pane has focus. Caller must hold m.mu (read or write).
+func (m *Model) selectedReadyLocked() (ReadyItem, bool) {
+	i := m.cursors[paneReady]
+	if m.focus != paneReady || i >= len(m.ready) {
+		return ReadyItem{}, false
+	}
+	return m.ready[i], true
+}
+
+// paneLenLocked returns the number of selectable rows in a pane.
+// Caller must hold m.mu (read or write).
+func (m *Model) paneLenLocked(p pane) int {
+	switch p {
+	case paneAgents:
+		return len(m.agents)
+	case paneReady:
+		return len(m.ready)
+	case paneEvents:
+		return len(m.events)
+	}
+	return 0
+}
+
+// clampCursorLocked keeps a pane's cursor within its rows after a refresh.
+// Caller must hold m.mu write lock.
+func (m *Model) clampCursorLocked(p pane) {
+	if n := m.paneLenLocked(p); m.cursors[p] >= n {
+		m.cursors[p] = max(n-1, 0)
+	}
+}
+
+// View renders the model.
+// Acquires read lock to safely access all View-visible fields.
+func (m *Model) View() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.renderView()
+}
@@ -0,0 +1,176 @@
+package town
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testModel() *Model {
+	m := New(Providers{})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(agentsMsg{agents: []Agent{
+		{Address: "mayor", Session: "hq-mayor", Running: true},
+		{Address: "gastown/witness", Session: "gt-gastown-witness", Running: true, Work: "gt-abc"},
+		{Address: "gastown/toast", Session: "gt-gastown-toast"},
+	}})
+	m.Update(readyMsg{ready: []ReadyItem{
+		{ID: "gt-1", Title: "Fix bug", Priority: 1, Source: "gastown"},
+		{ID: "hq-2", Title: "Convoy", Priority: 2, Source: "town"},
+	}})
+	return m
+}
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// TestViewConcurrentWithFetches verifies that pane refreshes via Update and
+// View() can run concurrently without data races.
+func TestViewConcurrentWithFetches(t *testing.T) {
+	m := testModel()
+
+	var wg sync.WaitGroup
+
+	// Writer goroutine: deliver fetch results
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.Update(agentsMsg{agents: []Agent{{Address: "mayor", Running: i%2 == 0}}})
+			m.Update(readyMsg{err: errors.New("bd unavailable")})
+			m.Update(doltMsg{dolt: &DoltHealth{Running: true, Healthy: true, Warnings: []string{"slow"}}})
+			m.Update(eventsMsg{events: []Event{{Time: time.Now(), Type: "sling", Actor: "mayor"}}})
+		}
+	}()
+
+	// Reader goroutine: call View() concurrently
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = m.View()
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestViewConcurrentWithKeys verifies that View and key handling (focus,
+// navigation, input) can run concurrently without data races.
+func TestViewConcurrentWithKeys(t *testing.T) {
+	m := testModel()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.Update(tea.KeyMsg{Type: tea.KeyDown})
+			m.Update(tea.KeyMsg{Type: tea.KeyTab})
+			m.Update(keyRunes("?"))
+			m.Update(tea.WindowSizeMsg{Width: 80 + i, Height: 30 + i})
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = m.View()
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestNudgeAction verifies that n on an agent prompts for a message and
+// enter delivers it to that agent's session.
+func TestNudgeAction(t *testing.T) {
+	var gotSession, gotMessage string
+	m := testModel()
+	m.providers.Nudge = func(session, message string) error {
+		gotSession, gotMessage = session, message
+		return nil
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(keyRunes("n"))
+	if m.mode != modeNudge {
+		t.Fatalf("mode = %v, want nudge", m.mode)
+	}
+	m.Update(keyRunes("check mail"))
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected nudge command")
+	}
+	m.Update(cmd())
+
+	if gotSession != "gt-gastown-witness" || gotMessage != "check mail" {
+		t.Errorf("nudged %q with %q", gotSession, gotMessage)
+	}
+	if m.mode != modeNormal || !strings.Contains(m.status, "Nudged") {
+		t.Errorf("after nudge: mode %v, status %q", m.mode, m.status)
+	}
+}
+
+// TestSlingAction verifies that s on a ready item pre-fills its rig as the
+// target, and that town items start with an empty target.
+func TestSlingAction(t *testing.T) {
+	var gotBead, gotTarget string
+	m := testModel()
+	m.providers.Sling = func(bead, target string) (string, error) {
+		gotBead, gotTarget = bead, target
+		return "", nil
+	}
+
+	// Sling only works from the ready pane.
+	m.Update(keyRunes("s"))
+	if m.mode != modeNormal {
+		t.Fatalf("sling from agents pane entered mode %v", m.mode)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(keyRunes("s"))
+	if m.mode != modeSling || m.input.Value() != "gastown" {
+		t.Fatalf("mode %v, target %q; want sling to gastown", m.mode, m.input.Value())
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if gotBead != "gt-1" || gotTarget != "gastown" {
+		t.Errorf("slung %q to %q", gotBead, gotTarget)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(keyRunes("s"))
+	if m.input.Value() != "" {
+		t.Errorf("town item target = %q, want empty", m.input.Value())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeNormal {
+		t.Errorf("esc left mode %v", m.mode)
+	}
+}
+
+// TestPeekNotRunning verifies that peeking at a stopped agent reports an
+// error instead of capturing.
+func TestPeekNotRunning(t *testing.T) {
+	m := testModel()
+	m.providers.Peek = func(string, int) (string, error) {
+		t.Error("Peek called for stopped agent")
+		return "", nil
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if _, cmd := m.Update(keyRunes("p")); cmd != nil {
+		t.Error("expected no peek command")
+	}
+	if !m.statusErr {
+		t.Errorf("status = %q, want error", m.status)
+	}
+}
@@ -0,0 +1,274 @@
+package town
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the town TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	paneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("8"))
+
+	focusedPaneStyle = paneStyle.
+				BorderForeground(lipgloss.Color("12"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	runningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")) // green
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// paneTitles are the headings of each pane.
+var paneTitles = [numPanes]string{
+	paneAgents: "Agents",
+	paneReady:  "Ready",
+	paneDolt:   "Dolt",
+	paneEvents: "Events",
+}
+
+// renderView renders the entire view.
+// Caller must hold m.mu.
+func (m *Model) renderView() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Gas Town"))
+	if m.status != "" {
+		b.WriteString("  ")
+		if m.statusErr {
+			b.WriteString(errorStyle.Render(m.status))
+		} else {
+			b.WriteString(dimStyle.Render(m.status))
+		}
+	}
+	b.WriteString("\n")
+
+	footer := m.renderFooter()
+	bodyHeight := m.height - 1 - lipgloss.Height(footer)
+
+	if m.mode == modePeek {
+		b.WriteString(m.renderPeek(m.width, bodyHeight))
+	} else {
+		// Each pane is half the body in each direction, minus its border.
+		leftWidth := m.width/2 - 2
+		rightWidth := m.width - m.width/2 - 2
+		topHeight := bodyHeight/2 - 2
+		bottomHeight := bodyHeight - bodyHeight/2 - 2
+		top := lipgloss.JoinHorizontal(lipgloss.Top,
+			m.renderPane(paneAgents, leftWidth, topHeight),
+			m.renderPane(paneReady, rightWidth, topHeight))
+		bottom := lipgloss.JoinHorizontal(lipgloss.Top,
+			m.renderPane(paneDolt, leftWidth, bottomHeight),
+			m.renderPane(paneEvents, rightWidth, bottomHeight))
+		b.WriteString(lipgloss.JoinVertical(lipgloss.Left, top, bottom))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(footer)
+	return b.String()
+}
+
+// renderFooter renders the input prompt or help line.
+// Caller must hold m.mu.
+func (m *Model) renderFooter() string {
+	switch m.mode {
+	case modeNudge:
+		return fmt.Sprintf("Nudge %s: %s", m.target, m.input.View())
+	case modeSling:
+		return fmt.Sprintf("Sling %s to: %s", m.target, m.input.View())
+	case modePeek:
+		return dimStyle.Render("esc:close")
+	}
+	if m.showHelp {
+		return m.help.View(m.keys)
+	}
+	return dimStyle.Render("tab:pane  j/k:navigate  p:peek  n:nudge  s:sling  r:refresh  q:quit  ?:help")
+}
+
+// renderPane renders one bordered pane with the given inner size.
+// Caller must hold m.mu.
+func (m *Model) renderPane(p pane, width, height int) string {
+	if width < 1 || height < 1 {
+		return ""
+	}
+
+	var lines []string
+	if err := m.errs[p]; err != nil {
+		lines = append(lines, errorStyle.Render(truncate(fmt.Sprintf("Error: %v", err), width)))
+	}
+
+	var rows []string
+	switch p {
+	case paneAgents:
+		rows = m.agentRows(width)
+	case paneReady:
+		rows = m.readyRows(width)
+	case paneDolt:
+		lines = append(lines, m.doltLines(width)...)
+	case paneEvents:
+		rows = m.eventRows(width)
+	}
+
+	// Scroll so the cursor stays visible.
+	visible := height - len(lines) - 1
+	cursor := m.cursors[p]
+	start := 0
+	if visible > 0 && cursor >= visible {
+		start = cursor - visible + 1
+	}
+	for i := start; i < len(rows) && len(lines) < height-1; i++ {
+		if m.focus == p && i == cursor {
+			lines = append(lines, selectedStyle.Render(rows[i]))
+		} else {
+			lines = append(lines, rows[i])
+		}
+	}
+
+	title := titleStyle.Render(paneTitles[p])
+	if n := m.paneLenLocked(p); n > 0 {
+		title += dimStyle.Render(fmt.Sprintf(" (%d)", n))
+	}
+	content := title + "\n" + strings.Join(lines, "\n")
+
+	style := paneStyle
+	if m.focus == p {
+		style = focusedPaneStyle
+	}
+	return style.Width(width).Height(height).MaxHeight(height + 2).Render(content)
+}
+
+// agentRows renders the agents pane rows.
+// Caller must hold m.mu.
+func (m *Model) agentRows(width int) []string {
+	if len(m.agents) == 0 && m.errs[paneAgents] == nil {
+		return []string{dimStyle.Render("No agents")}
+	}
+	rows := make([]string, 0, len(m.agents))
+	for _, a := range m.agents {
+		icon := dimStyle.Render("○")
+		if a.Running {
+			icon = runningStyle.Render("●")
+		}
+		detail := a.Work
+		if detail == "" {
+			detail = a.State
+		}
+		if a.UnreadMail > 0 {
+			detail = strings.TrimSpace(fmt.Sprintf("%s 📬%d", detail, a.UnreadMail))
+		}
+		rows = append(rows, icon+" "+truncate(fmt.Sprintf("%-24s %s", a.Address, detail), width-2))
+	}
+	return rows
+}
+
+// readyRows renders the ready pane rows.
+// Caller must hold m.mu.
+func (m *Model) readyRows(width int) []string {
+	if len(m.ready) == 0 && m.errs[paneReady] == nil {
+		return []string{dimStyle.Render("No ready work")}
+	}
+	rows := make([]string, 0, len(m.ready))
+	for _, item := range m.ready {
+		rows = append(rows, truncate(fmt.Sprintf("P%d %-12s %s [%s]", item.Priority, item.ID, item.Title, item.Source), width))
+	}
+	return rows
+}
+
+// doltLines renders the Dolt health pane.
+// Caller must hold m.mu.
+func (m *Model) doltLines(width int) []string {
+	d := m.dolt
+	if d == nil {
+		return nil
+	}
+	if !d.Running {
+		return []string{errorStyle.Render("✗ server not running")}
+	}
+
+	status := runningStyle.Render("✓ healthy")
+	if !d.Healthy {
+		status = warningStyle.Render("⚠ unhealthy")
+	}
+	lines := []string{
+		status,
+		fmt.Sprintf("Connections: %d/%d", d.Connections, d.MaxConnections),
+		fmt.Sprintf("Latency:     %v", d.Latency.Round(1e6)),
+		fmt.Sprintf("Disk:        %s", d.Disk),
+	}
+	if d.ReadOnly {
+		lines = append(lines, errorStyle.Render("✗ read-only"))
+	}
+	for _, w := range d.Warnings {
+		lines = append(lines, warningStyle.Render(truncate("⚠ "+w, width)))
+	}
+	return lines
+}
+
+// eventRows renders the events pane rows.
+// Caller must hold m.mu.
+func (m *Model) eventRows(width int) []string {
+	if len(m.events) == 0 && m.errs[paneEvents] == nil {
+		return []string{dimStyle.Render("No events")}
+	}
+	rows := make([]string, 0, len(m.events))
+	for _, e := range m.events {
+		ts := "--:--:--"
+		if !e.Time.IsZero() {
+			ts = e.Time.Local().Format("15:04:05")
+		}
+		rows = append(rows, truncate(fmt.Sprintf("%s %-10s %s %s", ts, e.Type, e.Actor, e.Summary), width))
+	}
+	return rows
+}
+
+// renderPeek renders captured session output, showing the last lines that fit.
+// Caller must hold m.mu.
+func (m *Model) renderPeek(width, height int) string {
+	innerWidth, innerHeight := width-2, height-3
+	if innerWidth < 1 || innerHeight < 1 {
+		return ""
+	}
+	lines := strings.Split(m.peekOutput, "\n")
+	if len(lines) > innerHeight {
+		lines = lines[len(lines)-innerHeight:]
+	}
+	for i, line := range lines {
+		lines[i] = truncate(line, innerWidth)
+	}
+	content := titleStyle.Render("Peek: "+m.peekTitle) + "\n" + strings.Join(lines, "\n")
+	return focusedPaneStyle.Width(innerWidth).Height(innerHeight + 1).Render(content)
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
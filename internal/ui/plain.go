@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxAgentFieldWidth caps free-text fields (titles, subjects, previews)
+// in agent mode. See TruncateField.
+const MaxAgentFieldWidth = 160
+
+// plainGlyphs maps decorative glyphs to stable ASCII words, so agents and
+// parsers see the same token regardless of terminal or font.
+var plainGlyphs = strings.NewReplacer(
+	// Status icons
+	IconPass, "[ok]",
+	"✔", "[ok]",
+	"✗", "[fail]",
+	IconFail, "[fail]",
+	"❌", "[fail]",
+	IconWarn, "[warn]",
+	IconInfo, "[info]",
+	IconFix, "[fix]",
+	// Issue status icons
+	StatusIconOpen, "[open]",
+	StatusIconInProgress, "[in_progress]",
+	StatusIconBlocked, "*",
+	StatusIconDeferred, "[deferred]",
+	StatusIconPinned, "[pinned]",
+	// Arrows and bullets
+	"→", "->",
+	"←", "<-",
+	"↔", "<->",
+	"↑", "^",
+	"↓", "v",
+	"•", "*",
+	"·", "-",
+	"…", "...",
+	// Tree and box drawing
+	TreeChild, "`- ",
+	"└─", "`-",
+	"├─", "|-",
+	"│", "|",
+	"─", "-",
+	"═", "=",
+)
+
+// ansiSeq matches ANSI CSI and OSC escape sequences.
+var ansiSeq = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// PlainText rewrites s for machine consumption: escape sequences are
+// removed, known glyphs become ASCII words, and remaining emoji are
+// dropped. Other non-ASCII text (names, titles) is kept.
+func PlainText(s string) string {
+	s = ansiSeq.ReplaceAllString(s, "")
+	s = plainGlyphs.Replace(s)
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isEmoji reports whether r is a pictograph or an emoji modifier.
+func isEmoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || // pictographs, emoticons, symbols
+		(r >= 0x2600 && r <= 0x27BF) || // misc symbols, dingbats
+		r == 0xFE0F || r == 0x200D // variation selector, zero-width joiner
+}
+
+// TruncateField caps a free-text field at MaxAgentFieldWidth runes in
+// agent mode, noting how much was cut. Outside agent mode s is returned
+// unchanged.
+func TruncateField(s string) string {
+	if !IsAgentMode() {
+		return s
+	}
+	return truncateRunes(s, MaxAgentFieldWidth)
+}
+
+func truncateRunes(s string, max int) string {
+	n := utf8.RuneCountInString(s)
+	if n <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max]) + fmt.Sprintf("... (+%d chars)", n-max)
+}
+
+// CopyPlain copies src to dst line by line through PlainText. A trailing
+// partial line is flushed at EOF.
+func CopyPlain(dst io.Writer, src io.Reader) error {
+	r := bufio.NewReader(src)
+	for {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			if _, werr := io.WriteString(dst, PlainText(line)); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
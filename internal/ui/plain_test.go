@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := map[string]string{
+		"\x1b[1;32m✓\x1b[0m Dropped database gastown": "[ok] Dropped database gastown",
+		"⚠ Warning: rig is parked":                    "[warn] Warning: rig is parked",
+		"  ✖ gastown ↔ origin: rejected":              "  [fail] gastown <-> origin: rejected",
+		"○ gt-abc · Fix the thing":                    "[open] gt-abc - Fix the thing",
+		"🚚 hq-cv-1: Ship it → main":                   " hq-cv-1: Ship it -> main",
+		"└─ crew/max ─────":                           "`- crew/max -----",
+		"Café naïve 日本語":                              "Café naïve 日本語",
+	}
+	for in, want := range tests {
+		if got := PlainText(in); got != want {
+			t.Errorf("PlainText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTruncateField(t *testing.T) {
+	long := strings.Repeat("é", MaxAgentFieldWidth+5)
+
+	t.Setenv("GT_AGENT_MODE", "0")
+	if got := TruncateField(long); got != long {
+		t.Errorf("TruncateField outside agent mode changed the field")
+	}
+
+	t.Setenv("GT_AGENT_MODE", "1")
+	want := strings.Repeat("é", MaxAgentFieldWidth) + "... (+5 chars)"
+	if got := TruncateField(long); got != want {
+		t.Errorf("TruncateField = %q, want %q", got, want)
+	}
+	if got := TruncateField("short"); got != "short" {
+		t.Errorf("TruncateField(short) = %q", got)
+	}
+}
+
+func TestCopyPlain(t *testing.T) {
+	var out bytes.Buffer
+	if err := CopyPlain(&out, strings.NewReader("✓ one\n→ two")); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "[ok] one\n-> two" {
+		t.Errorf("CopyPlain = %q", got)
+	}
+}
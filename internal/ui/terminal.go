@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 	"golang.org/x/term"
 )
@@ -110,6 +111,11 @@ func ShouldUseColor() bool {
 		return false
 	}
 
+	// Agent mode never uses color
+	if IsAgentMode() {
+		return false
+	}
+
 	// CLICOLOR_FORCE enables color even in non-TTY
 	if _, exists := os.LookupEnv("CLICOLOR_FORCE"); exists {
 		return true
@@ -120,30 +126,67 @@ func ShouldUseColor() bool {
 }
 
 // ShouldUseEmoji determines if emoji decorations should be used.
-// Disabled in non-TTY mode to keep output machine-readable.
+// Disabled in non-TTY mode and agent mode to keep output machine-readable.
 func ShouldUseEmoji() bool {
 	// GT_NO_EMOJI disables emoji output
 	if _, exists := os.LookupEnv("GT_NO_EMOJI"); exists {
 		return false
 	}
+	if IsAgentMode() {
+		return false
+	}
 
 	// default: use emoji only if stdout is a TTY
 	return IsTerminal()
 }
 
+// agentModeFlag is set by the global --agent/--plain flag.
+var agentModeFlag bool
+
+// SetAgentMode forces agent mode on, as the --agent flag does, and drops
+// lipgloss to plain ASCII so styles render without escape codes.
+func SetAgentMode(on bool) {
+	agentModeFlag = on
+	if on {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
 // IsAgentMode returns true if the CLI is running in agent-optimized mode.
 // This is triggered by:
-//   - GT_AGENT_MODE=1 environment variable (explicit)
+//   - the --agent (or --plain) flag
+//   - GT_AGENT_MODE=1 environment variable (explicit; GT_AGENT_MODE=0 opts out)
 //   - CLAUDE_CODE environment variable (auto-detect Claude Code)
+//   - GT_ROLE environment variable (running inside a Gas Town agent session)
 //
 // Agent mode provides ultra-compact output optimized for LLM context windows.
 func IsAgentMode() bool {
-	if os.Getenv("GT_AGENT_MODE") == "1" {
+	if agentModeFlag {
 		return true
 	}
+	switch os.Getenv("GT_AGENT_MODE") {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
 	// auto-detect Claude Code environment
 	if os.Getenv("CLAUDE_CODE") != "" {
 		return true
 	}
+	// auto-detect Gas Town agent sessions
+	if os.Getenv("GT_ROLE") != "" {
+		return true
+	}
 	return false
 }
+
+// ShouldUsePlainOutput reports whether output should be rewritten for
+// machines (see PlainText): in agent mode, or whenever stdout is not a
+// terminal unless GT_AGENT_MODE=0.
+func ShouldUsePlainOutput() bool {
+	if IsAgentMode() {
+		return true
+	}
+	return os.Getenv("GT_AGENT_MODE") != "0" && !IsTerminal()
+}
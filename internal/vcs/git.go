@@ -0,0 +1,21 @@
+package vcs
+
+import "github.com/steveyegge/gastown/internal/git"
+
+// gitBackend is the default backend, delegating to the git package.
+type gitBackend struct{}
+
+func (gitBackend) Name() string { return Git }
+
+func (gitBackend) Clone(url, dest, reference string) error {
+	g := git.NewGit("")
+	if reference != "" {
+		if err := g.CloneWithReference(url, dest, reference); err == nil {
+			return nil
+		}
+		// Fall back to a full clone if the reference is unusable.
+	}
+	return g.Clone(url, dest)
+}
+
+func (gitBackend) Open(dir string) Workspace { return git.NewGit(dir) }
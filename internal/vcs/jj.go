@@ -0,0 +1,100 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+// jjTimeout bounds a single jj command. Clones and fetches can be slow.
+const jjTimeout = 10 * time.Minute
+
+var runner proc.Runner = proc.Exec{}
+
+// SetRunner replaces the runner for jj commands. Returns a func that
+// restores the previous one.
+func SetRunner(r proc.Runner) (restore func()) {
+	prev := runner
+	runner = r
+	return func() { runner = prev }
+}
+
+// runJJ runs jj in dir and returns its trimmed stdout.
+func runJJ(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jjTimeout)
+	defer cancel()
+	stdout, stderr, err := runner.Run(ctx, proc.Cmd{Name: "jj", Args: args, Dir: dir})
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return "", fmt.Errorf("jj %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("jj %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// jjBackend drives colocated jj repositories.
+type jjBackend struct{}
+
+func (jjBackend) Name() string { return JJ }
+
+// Clone makes a colocated clone. jj has no equivalent of git's object
+// reference, so reference is ignored.
+func (jjBackend) Clone(url, dest, _ string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("creating destination parent: %w", err)
+	}
+	_, err := runJJ(filepath.Dir(dest), "git", "clone", "--colocate", url, dest)
+	return err
+}
+
+func (jjBackend) Open(dir string) Workspace { return jjWorkspace{dir: dir} }
+
+type jjWorkspace struct {
+	dir string
+}
+
+// CreateBranch creates a bookmark at the working-copy change.
+func (w jjWorkspace) CreateBranch(name string) error {
+	_, err := runJJ(w.dir, "bookmark", "create", name, "-r", "@")
+	return err
+}
+
+// Checkout starts a new working-copy change on top of name.
+func (w jjWorkspace) Checkout(name string) error {
+	_, err := runJJ(w.dir, "new", name)
+	return err
+}
+
+// HasUncommittedChanges reports whether the working-copy change has a diff.
+// In jj the working copy is always a change, so "uncommitted" means the
+// change is non-empty.
+func (w jjWorkspace) HasUncommittedChanges() (bool, error) {
+	out, err := runJJ(w.dir, "diff", "--summary", "-r", "@")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// Pull fetches from remote and rebases the working copy onto branch's
+// remote bookmark, or the trunk when branch is empty.
+func (w jjWorkspace) Pull(remote, branch string) error {
+	if remote == "" {
+		remote = "origin"
+	}
+	if _, err := runJJ(w.dir, "git", "fetch", "--remote", remote); err != nil {
+		return err
+	}
+	dest := "trunk()"
+	if branch != "" {
+		dest = branch + "@" + remote
+	}
+	_, err := runJJ(w.dir, "rebase", "-d", dest)
+	return err
+}
@@ -0,0 +1,85 @@
+// Package vcs abstracts the version control operations the crew manager
+// needs for crew clones (clone, branch, checkout, status and pull), so a
+// crew member's working copy isn't hard-wired to the git CLI.
+//
+// A rig selects its backend with "vcs" in its config.json:
+//
+//	{"type": "rig", "name": "gastown", "vcs": "jj", ...}
+//
+// Git is the default. The jj backend works on colocated repositories
+// (jj git clone --colocate): the working copy has both .jj and .git, so
+// everything that still talks to git directly keeps working. That includes
+// the rig's own clones (mayor/rig and the shared bare repo), polecats — git
+// worktrees of the shared bare repo, which jj has no equivalent for —
+// remotes, push, and the refinery's merges. Only crew clones go through
+// this package.
+package vcs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Backend names.
+const (
+	Git = "git"
+	JJ  = "jj"
+)
+
+// Backend creates and opens working copies.
+type Backend interface {
+	// Name returns the backend name (Git or JJ).
+	Name() string
+
+	// Clone clones url into dest. reference, if non-empty, is a local
+	// repository to borrow objects from where the backend supports it.
+	Clone(url, dest, reference string) error
+
+	// Open returns the working copy at dir.
+	Open(dir string) Workspace
+}
+
+// Workspace is a single working copy.
+type Workspace interface {
+	// CreateBranch creates a branch (a bookmark in jj) at the current change.
+	CreateBranch(name string) error
+
+	// Checkout makes name the base of the working copy.
+	Checkout(name string) error
+
+	// HasUncommittedChanges reports whether the working copy has changes
+	// that aren't recorded on a branch.
+	HasUncommittedChanges() (bool, error)
+
+	// Pull brings the working copy up to date with remote. An empty branch
+	// means the current branch (git) or the trunk (jj).
+	Pull(remote, branch string) error
+}
+
+var backends = map[string]Backend{
+	Git: gitBackend{},
+	JJ:  jjBackend{},
+}
+
+// For returns the backend with the given name. An empty name selects git.
+func For(name string) (Backend, error) {
+	if name == "" {
+		name = Git
+	}
+	b, ok := backends[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown vcs %q (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return b, nil
+}
+
+// Names returns the supported backend names.
+func Names() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
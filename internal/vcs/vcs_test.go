@@ -0,0 +1,105 @@
+package vcs
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/proc"
+)
+
+func TestFor(t *testing.T) {
+	for name, want := range map[string]string{"": Git, "git": Git, "JJ": JJ} {
+		b, err := For(name)
+		if err != nil || b.Name() != want {
+			t.Errorf("For(%q) = %v, %v; want %s", name, b, err, want)
+		}
+	}
+	if _, err := For("svn"); err == nil || !strings.Contains(err.Error(), "git, jj") {
+		t.Errorf("For(svn) error = %v, want unknown vcs listing supported backends", err)
+	}
+}
+
+func TestJJWorkspace(t *testing.T) {
+	fake := &proc.FakeRunner{Handler: func(c proc.Cmd) ([]byte, []byte, error) {
+		if c.Args[0] == "diff" {
+			return []byte("M src/main.rs\n"), nil, nil
+		}
+		if c.Args[0] == "rebase" {
+			return nil, []byte("Error: conflict\n"), errors.New("exit status 1")
+		}
+		return nil, nil, nil
+	}}
+	defer SetRunner(fake)()
+
+	dir := t.TempDir()
+	b, _ := For(JJ)
+	dest := filepath.Join(dir, "crew", "max")
+	if err := b.Clone("https://example.com/repo.git", dest, "/ignored/reference"); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	w := b.Open(dest)
+	if err := w.CreateBranch("crew/max"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Checkout("crew/max"); err != nil {
+		t.Fatal(err)
+	}
+	if dirty, err := w.HasUncommittedChanges(); err != nil || !dirty {
+		t.Errorf("HasUncommittedChanges = %v, %v; want true", dirty, err)
+	}
+	if err := w.Pull("", ""); err == nil || !strings.Contains(err.Error(), "jj rebase: Error: conflict") {
+		t.Errorf("Pull error = %v, want rebase stderr", err)
+	}
+
+	var got []string
+	for _, c := range fake.Calls() {
+		got = append(got, strings.Join(c.Args, " "))
+	}
+	want := []string{
+		"git clone --colocate https://example.com/repo.git " + dest,
+		"bookmark create crew/max -r @",
+		"new crew/max",
+		"diff --summary -r @",
+		"git fetch --remote origin",
+		"rebase -d trunk()",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("jj commands:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if calls := fake.Calls(); calls[1].Dir != dest {
+		t.Errorf("workspace commands run in %q, want %q", calls[1].Dir, dest)
+	}
+}
+
+func TestGitWorkspace(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	b, _ := For("")
+	w := b.Open(dir)
+	if dirty, err := w.HasUncommittedChanges(); err != nil || dirty {
+		t.Fatalf("clean repo: HasUncommittedChanges = %v, %v", dirty, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if dirty, err := w.HasUncommittedChanges(); err != nil || !dirty {
+		t.Errorf("dirty repo: HasUncommittedChanges = %v, %v", dirty, err)
+	}
+}
@@ -0,0 +1,132 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PinFile is the town's pinned gt version manifest, under the mayor directory.
+const PinFile = "gt-version.json"
+
+// Pin records which gt build a town expects its agents to run.
+type Pin struct {
+	Version  string    `json:"version"`          // Semantic version, e.g. "0.7.0"
+	Commit   string    `json:"commit,omitempty"` // Build commit; checked only when versions match
+	Binary   string    `json:"binary,omitempty"` // Town-local gt binary, absolute or relative to the town root
+	Redirect bool      `json:"redirect,omitempty"`
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+// PinPath returns the path of the town's version manifest.
+func PinPath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", PinFile)
+}
+
+// LoadPin reads the town's version manifest. Returns nil, nil if the town
+// has no pinned version.
+func LoadPin(townRoot string) (*Pin, error) {
+	data, err := os.ReadFile(PinPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pin Pin
+	if err := json.Unmarshal(data, &pin); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", PinFile, err)
+	}
+	return &pin, nil
+}
+
+// SavePin writes the town's version manifest.
+func SavePin(townRoot string, pin *Pin) error {
+	data, err := json.MarshalIndent(pin, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := PinPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: manifest is not secret
+}
+
+// RemovePin deletes the town's version manifest, if any.
+func RemovePin(townRoot string) error {
+	if err := os.Remove(PinPath(townRoot)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// BinaryPath returns the absolute path of the pinned town-local binary, or
+// "" if none is configured.
+func (p *Pin) BinaryPath(townRoot string) string {
+	if p.Binary == "" {
+		return ""
+	}
+	if filepath.IsAbs(p.Binary) {
+		return p.Binary
+	}
+	return filepath.Join(townRoot, p.Binary)
+}
+
+// PinMismatch describes how a running binary differs from the town's pin.
+type PinMismatch struct {
+	Pinned  string // Pinned version (with short commit when that is what differs)
+	Running string // Running version, in the same form
+	Older   bool   // The running binary is older than the pin
+}
+
+// CheckPin compares a running binary's version and commit against pin.
+// Returns nil if they match, or if pin is nil. A commit is compared only
+// when both sides have one and the versions are equal.
+func CheckPin(pin *Pin, running, commit string) *PinMismatch {
+	if pin == nil || pin.Version == "" {
+		return nil
+	}
+	if c := compareSemver(running, pin.Version); c != 0 {
+		return &PinMismatch{Pinned: pin.Version, Running: running, Older: c < 0}
+	}
+	if pin.Commit != "" && commit != "" && !commitsMatch(pin.Commit, commit) {
+		return &PinMismatch{
+			Pinned:  pin.Version + "@" + ShortCommit(pin.Commit),
+			Running: running + "@" + ShortCommit(commit),
+		}
+	}
+	return nil
+}
+
+// compareSemver compares "X.Y.Z" versions, ignoring a leading "v" and any
+// pre-release suffix. Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func compareSemver(a, b string) int {
+	pa, pb := parseSemver(a), parseSemver(b)
+	for i := 0; i < 3; i++ {
+		if pa[i] < pb[i] {
+			return -1
+		}
+		if pa[i] > pb[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseSemver(v string) [3]int {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	split := strings.Split(v, ".")
+	for i := 0; i < 3 && i < len(split); i++ {
+		parts[i], _ = strconv.Atoi(split[i])
+	}
+	return parts
+}
@@ -0,0 +1,60 @@
+package version
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPin(t *testing.T) {
+	pin := &Pin{Version: "0.7.0", Commit: "abc1234def5678"}
+
+	tests := []struct {
+		name    string
+		pin     *Pin
+		running string
+		commit  string
+		want    *PinMismatch
+	}{
+		{"no pin", nil, "0.6.0", "", nil},
+		{"match", pin, "0.7.0", "abc1234def5678", nil},
+		{"match without commit", pin, "v0.7.0", "", nil},
+		{"older", pin, "0.6.2", "", &PinMismatch{Pinned: "0.7.0", Running: "0.6.2", Older: true}},
+		{"newer", pin, "0.10.0", "", &PinMismatch{Pinned: "0.7.0", Running: "0.10.0"}},
+		{"different build", pin, "0.7.0", "fff0000aaa1111", &PinMismatch{Pinned: "0.7.0@abc1234def56", Running: "0.7.0@fff0000aaa11"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckPin(tt.pin, tt.running, tt.commit)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("CheckPin() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if pin, err := LoadPin(townRoot); err != nil || pin != nil {
+		t.Fatalf("LoadPin() on unpinned town = %+v, %v; want nil, nil", pin, err)
+	}
+
+	want := &Pin{Version: "0.7.0", Binary: "bin/gt", Redirect: true}
+	if err := SavePin(townRoot, want); err != nil {
+		t.Fatalf("SavePin() error = %v", err)
+	}
+	got, err := LoadPin(townRoot)
+	if err != nil || got == nil || got.Version != "0.7.0" || !got.Redirect {
+		t.Fatalf("LoadPin() = %+v, %v", got, err)
+	}
+	if bin := got.BinaryPath(townRoot); bin != filepath.Join(townRoot, "bin", "gt") {
+		t.Errorf("BinaryPath() = %q", bin)
+	}
+
+	if err := RemovePin(townRoot); err != nil {
+		t.Fatalf("RemovePin() error = %v", err)
+	}
+	if pin, _ := LoadPin(townRoot); pin != nil {
+		t.Errorf("pin still present after RemovePin: %+v", pin)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/proc"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -217,6 +218,9 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 	}
 	// Ensure the command doesn't wait for stdin
 	cmd.Stdin = nil
+	// The parsers below read gt's human output (status icons and all),
+	// which gt would otherwise rewrite for a non-TTY stdout.
+	cmd.Env = append(os.Environ(), "GT_AGENT_MODE=0")
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -1122,7 +1126,7 @@ func (h *APIHandler) runBdCommand(ctx context.Context, timeout time.Duration, ar
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := proc.RunCmdContext(ctx, cmd)
 
 	output := stdout.String()
 	if stderr.Len() > 0 {
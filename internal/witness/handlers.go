@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/quarantine"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -789,6 +790,13 @@ func extractPolecatFromJSON(output string) string {
 // This kills the tmux session, removes the worktree, and cleans up beads.
 // Should only be called after all safety checks pass.
 func NukePolecat(workDir, rigName, polecatName string) error {
+	// Never destroy a quarantined polecat: its session and workspace are
+	// being held for review. A human nukes it with --force when done.
+	if townRoot, err := workspace.Find(workDir); err == nil && townRoot != "" &&
+		quarantine.IsQuarantined(townRoot, rigName, polecatName) {
+		return fmt.Errorf("%s/%s is quarantined; refusing to nuke", rigName, polecatName)
+	}
+
 	// CRITICAL: Kill the tmux session FIRST and unconditionally.
 	// The session name follows the pattern gt-<rig>-<polecat>.
 	// We do this explicitly here because gt polecat nuke may fail to kill the
@@ -1267,8 +1275,8 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 // StalledResult represents a single stalled polecat detection.
 type StalledResult struct {
 	PolecatName string // e.g., "alpha"
-	StallType   string // "bypass-permissions", "unknown-prompt"
-	Action      string // "auto-dismissed", "escalated"
+	StallType   string // "bypass-permissions", "unknown-prompt", "stale-heartbeat", "overdue-step"
+	Action      string // "auto-dismissed", "escalated", "reported"
 	Error       error
 }
 
@@ -1285,7 +1293,11 @@ type DetectStalledPolecatsResult struct {
 // alive-but-stuck agents that will never make progress without intervention.
 //
 // For each qualifying polecat (live session + alive agent):
-//   - Captures pane content (last 30 lines)
+//   - Checks heartbeat age first: a fresh heartbeat means the agent is
+//     working; a stale one with hooked work is escalated as wedged
+//   - For a working agent, reports the current molecule step if it has run
+//     past its expected_duration (the agent may have forgotten to close it)
+//   - Otherwise (no heartbeat yet), captures pane content (last 30 lines)
 //   - Checks for known stall patterns
 //   - Auto-dismisses known prompts (bypass-permissions) or escalates
 //
@@ -1332,6 +1344,33 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 			continue // Dead agent — zombie detection handles this
 		}
 
+		// Heartbeat age is the primary staleness signal. Agents that have
+		// never sent one fall through to pane pattern matching.
+		prefix := beads.GetPrefixForRig(townRoot, rigName)
+		agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
+		hookBead, lastHeartbeat := getAgentBeadHeartbeat(workDir, agentBeadID)
+		if hb, ok := beads.ParseHeartbeat(lastHeartbeat); ok {
+			if age := time.Since(hb); hookBead != "" && age > beads.HeartbeatStaleThreshold {
+				result.Stalled = append(result.Stalled, StalledResult{
+					PolecatName: polecatName,
+					StallType:   "stale-heartbeat",
+					Action:      "escalated",
+					Error:       fmt.Errorf("no heartbeat for %v with %s hooked", age.Round(time.Minute), hookBead),
+				})
+			} else if hookBead != "" {
+				if over := findOverdueStep(workDir, hookBead); over != nil {
+					result.Stalled = append(result.Stalled, StalledResult{
+						PolecatName: polecatName,
+						StallType:   "overdue-step",
+						Action:      "reported",
+						Error: fmt.Errorf("step %s running %v, expected %v", over.Step.ID,
+							over.Running.Round(time.Minute), over.Expected),
+					})
+				}
+			}
+			continue
+		}
+
 		// Agent is alive. Capture pane to check for known stall patterns.
 		content, err := t.CapturePane(sessionName, 30)
 		if err != nil {
@@ -1379,6 +1418,26 @@ func getAgentBeadState(workDir, agentBeadID string) (agentState, hookBead string
 	return issues[0].AgentState, issues[0].HookBead
 }
 
+// getAgentBeadHeartbeat reads hook_bead and last_heartbeat from an agent bead.
+// Returns empty strings if the bead can't be read.
+func getAgentBeadHeartbeat(workDir, agentBeadID string) (hookBead, lastHeartbeat string) {
+	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
+	if err != nil || output == "" {
+		return "", ""
+	}
+
+	var issues []struct {
+		HookBead    string `json:"hook_bead"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+		return "", ""
+	}
+
+	fields := beads.ParseAgentFields(issues[0].Description)
+	return issues[0].HookBead, fields.LastHeartbeat
+}
+
 // getBeadStatus returns the status of a bead (e.g., "open", "closed", "hooked").
 // Returns empty string if the bead doesn't exist or can't be queried.
 func getBeadStatus(workDir, beadID string) string {
@@ -1741,6 +1800,26 @@ func getAttachedMoleculeID(workDir, beadID string) string {
 	return fields.AttachedMolecule
 }
 
+// findOverdueStep returns the current step of the molecule behind hookBead
+// if it has run past its expected duration. The hook holds either the work
+// bead (with an attached molecule) or a step of the molecule itself.
+func findOverdueStep(workDir, hookBead string) *beads.OverdueStep {
+	b := beads.New(workDir)
+	molID := getAttachedMoleculeID(workDir, hookBead)
+	if molID == "" {
+		issue, err := b.Show(hookBead)
+		if err != nil || issue.Parent == "" {
+			return nil
+		}
+		molID = issue.Parent
+	}
+	steps, err := b.List(beads.ListOptions{Parent: molID, Status: "all", Priority: -1})
+	if err != nil {
+		return nil
+	}
+	return beads.FindOverdueStep(steps, time.Now())
+}
+
 // closeMoleculeWithDescendants closes a molecule and all its descendant step
 // issues using the bd CLI. Returns the total number of issues closed.
 func closeMoleculeWithDescendants(workDir, moleculeID string) (int, error) {
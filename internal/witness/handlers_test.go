@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/quarantine"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -1249,3 +1250,21 @@ esac
 	}
 }
 
+
+func TestNukePolecat_RefusesQuarantined(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := quarantine.Save(townRoot, &quarantine.Record{Rig: "gastown", Polecat: "nux"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NukePolecat(townRoot, "gastown", "nux")
+	if err == nil || !strings.Contains(err.Error(), "quarantined") {
+		t.Fatalf("NukePolecat = %v, want quarantine refusal", err)
+	}
+}
@@ -0,0 +1,77 @@
+package gt
+
+import (
+	"errors"
+
+	"github.com/steveyegge/gastown/internal/cmd"
+)
+
+// SessionCost is the running cost of one Gas Town session.
+type SessionCost struct {
+	Session string
+	Role    string
+	Rig     string
+	Worker  string
+	CostUSD float64
+}
+
+// SessionCosts returns the current cost of each running session (as shown
+// by gt costs) and their total.
+func (t *Town) SessionCosts() ([]SessionCost, float64, error) {
+	costs, total, err := cmd.LiveSessionCosts()
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]SessionCost, 0, len(costs))
+	for _, c := range costs {
+		out = append(out, SessionCost{
+			Session: c.Session,
+			Role:    c.Role,
+			Rig:     c.Rig,
+			Worker:  c.Worker,
+			CostUSD: c.Cost,
+		})
+	}
+	return out, total, nil
+}
+
+// CostDowngrade is a cost policy action applied (or planned) for a session.
+type CostDowngrade struct {
+	Session      string
+	Role         string
+	CostUSD      float64
+	ThresholdUSD float64
+	Action       string // "nudge" or "respawn"
+	Model        string // Model switched to, for nudge
+	Agent        string // Agent respawned with, for respawn
+	Resumed      bool   // Respawned session resumed the conversation
+	Error        string // Set if the downgrade failed
+}
+
+// EnforceCostPolicy applies the town's cost_policy to running sessions (as
+// gt costs enforce does) and returns the downgrades. With dryRun, nothing is
+// changed. Returns no downgrades and no error when no policy is configured.
+func (t *Town) EnforceCostPolicy(dryRun bool) ([]CostDowngrade, error) {
+	downgrades, err := cmd.EnforceCostPolicy(t.Root, dryRun)
+	if errors.Is(err, cmd.ErrNoCostPolicy) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]CostDowngrade, 0, len(downgrades))
+	for _, d := range downgrades {
+		out = append(out, CostDowngrade{
+			Session:      d.Session,
+			Role:         d.Role,
+			CostUSD:      d.CostUSD,
+			ThresholdUSD: d.ThresholdUSD,
+			Action:       d.Action,
+			Model:        d.Model,
+			Agent:        d.Agent,
+			Resumed:      d.Resumed,
+			Error:        d.Error,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,99 @@
+package gt
+
+import (
+	"io"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmd"
+	"github.com/steveyegge/gastown/internal/doctor"
+)
+
+// DoctorOptions configures Doctor.
+type DoctorOptions struct {
+	// Rig also runs the rig-specific checks for this rig.
+	Rig string
+
+	// Fix attempts automatic fixes for checks that support them.
+	Fix bool
+
+	// RestartSessions allows fixes to restart patrol sessions.
+	RestartSessions bool
+
+	// Verbose includes extra details in check results.
+	Verbose bool
+
+	// Progress, if set, receives per-check progress as gt doctor prints it.
+	Progress io.Writer
+}
+
+// Check statuses.
+const (
+	CheckOK      = "ok"
+	CheckWarning = "warning"
+	CheckError   = "error"
+)
+
+// DoctorCheck is the result of one health check.
+type DoctorCheck struct {
+	Name     string
+	Category string
+	Status   string // CheckOK, CheckWarning, or CheckError
+	Message  string
+	Details  []string
+	FixHint  string
+	Fixed    bool
+	Elapsed  time.Duration
+}
+
+// DoctorReport is the result of Doctor.
+type DoctorReport struct {
+	Checks   []DoctorCheck
+	OK       int
+	Warnings int
+	Errors   int
+	Fixed    int
+}
+
+// Doctor runs the town health checks (as gt doctor does). Failing checks are
+// reported in the result, not as an error.
+func (t *Town) Doctor(opts DoctorOptions) (*DoctorReport, error) {
+	r := cmd.RunDoctorChecks(cmd.DoctorOptions{
+		TownRoot:        t.Root,
+		Rig:             opts.Rig,
+		Fix:             opts.Fix,
+		Verbose:         opts.Verbose,
+		RestartSessions: opts.RestartSessions,
+		Output:          opts.Progress,
+	})
+	report := &DoctorReport{
+		OK:       r.Summary.OK,
+		Warnings: r.Summary.Warnings,
+		Errors:   r.Summary.Errors,
+		Fixed:    r.Summary.Fixed,
+	}
+	for _, c := range r.Checks {
+		report.Checks = append(report.Checks, DoctorCheck{
+			Name:     c.Name,
+			Category: c.Category,
+			Status:   checkStatus(c.Status),
+			Message:  c.Message,
+			Details:  c.Details,
+			FixHint:  c.FixHint,
+			Fixed:    c.Fixed,
+			Elapsed:  c.Elapsed,
+		})
+	}
+	return report, nil
+}
+
+// checkStatus maps a doctor status to the API's status constants.
+func checkStatus(s doctor.CheckStatus) string {
+	switch s {
+	case doctor.StatusOK:
+		return CheckOK
+	case doctor.StatusWarning:
+		return CheckWarning
+	default:
+		return CheckError
+	}
+}
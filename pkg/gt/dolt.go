@@ -0,0 +1,176 @@
+package gt
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmd"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// DoltStatus describes the town's Dolt SQL server.
+type DoltStatus struct {
+	Running bool
+	PID     int
+
+	// The fields below are only set when the server is running.
+	Connections    int
+	MaxConnections int
+	DiskUsageBytes int64
+	QueryLatency   time.Duration
+	ReadOnly       bool
+	Healthy        bool
+	Warnings       []string
+}
+
+// DoltStatus reports whether the Dolt server is running and, if so, its
+// health metrics (as shown by gt dolt status).
+func (t *Town) DoltStatus() (*DoltStatus, error) {
+	running, pid, err := doltserver.IsRunning(t.Root)
+	if err != nil {
+		return nil, err
+	}
+	status := &DoltStatus{Running: running, PID: pid}
+	if !running {
+		return status, nil
+	}
+	m := doltserver.GetHealthMetrics(t.Root)
+	status.Connections = m.Connections
+	status.MaxConnections = m.MaxConnections
+	status.DiskUsageBytes = m.DiskUsageBytes
+	status.QueryLatency = m.QueryLatency
+	status.ReadOnly = m.ReadOnly
+	status.Healthy = m.Healthy
+	status.Warnings = m.Warnings
+	return status, nil
+}
+
+// DoltDatabases lists the rig databases in .dolt-data/.
+func (t *Town) DoltDatabases() ([]string, error) {
+	return doltserver.ListDatabases(t.Root)
+}
+
+// DoltStatsOptions configures DoltStats.
+type DoltStatsOptions struct {
+	// Rigs limits the report to these rig databases. Empty means all.
+	Rigs []string
+
+	// Since is how far back to measure growth (default 24h).
+	Since time.Duration
+
+	// NoRecord skips appending this run to the stats history.
+	NoRecord bool
+}
+
+// DoltTableStats is one table's size and growth.
+type DoltTableStats struct {
+	Database   string
+	Table      string
+	Rows       int64
+	Bytes      int64
+	RowGrowth  int64
+	ByteGrowth int64
+}
+
+// DoltTableAlert is a table over a configured dolt_stats limit.
+type DoltTableAlert struct {
+	Database string
+	Table    string
+	Message  string
+}
+
+// DoltStatsReport is the result of DoltStats.
+type DoltStatsReport struct {
+	// Baseline is when the snapshot growth is measured against was taken,
+	// or zero if there is no history yet.
+	Baseline time.Time
+	Tables   []DoltTableStats
+	Alerts   []DoltTableAlert
+
+	// Errors maps databases that couldn't be queried to the error.
+	Errors map[string]string
+}
+
+// DoltStats reports per-table row counts, sizes, growth, and alerts (as
+// shown by gt dolt stats).
+func (t *Town) DoltStats(opts DoltStatsOptions) (*DoltStatsReport, error) {
+	if opts.Since == 0 {
+		opts.Since = 24 * time.Hour
+	}
+	r, err := cmd.CollectDoltStats(t.Root, cmd.DoltStatsOptions{
+		Rigs:     opts.Rigs,
+		Since:    opts.Since,
+		NoRecord: opts.NoRecord,
+	})
+	if err != nil {
+		return nil, err
+	}
+	report := &DoltStatsReport{Errors: r.Errors}
+	if r.Since != nil {
+		report.Baseline = *r.Since
+	}
+	for _, s := range r.Tables {
+		report.Tables = append(report.Tables, DoltTableStats{
+			Database:   s.Database,
+			Table:      s.Table,
+			Rows:       s.Rows,
+			Bytes:      s.Bytes,
+			RowGrowth:  s.RowGrowth,
+			ByteGrowth: s.ByteGrowth,
+		})
+	}
+	for _, a := range r.Alerts {
+		report.Alerts = append(report.Alerts, DoltTableAlert{
+			Database: a.Database,
+			Table:    a.Table,
+			Message:  a.Message,
+		})
+	}
+	return report, nil
+}
+
+// DoltPruneOptions configures DoltPrune.
+type DoltPruneOptions struct {
+	// Rigs limits pruning to these rig databases. Empty means all.
+	Rigs []string
+
+	// Targets selects what to prune ("wisps", "events"). Empty means all.
+	Targets []string
+
+	// Days overrides the configured retention. Zero uses the settings.
+	Days int
+
+	// DryRun counts prunable rows without deleting them.
+	DryRun bool
+}
+
+// DoltPruneResult is the rows pruned (or prunable) for one target.
+type DoltPruneResult struct {
+	Database  string
+	Target    string
+	Days      int
+	Rows      int64
+	Committed bool
+}
+
+// DoltPrune deletes rows past their retention period from known-safe tables
+// (as gt dolt prune does). If some databases fail, the successful results
+// are returned along with an error describing the failures.
+func (t *Town) DoltPrune(opts DoltPruneOptions) ([]DoltPruneResult, error) {
+	results, err := cmd.PruneDolt(t.Root, cmd.DoltPruneOptions{
+		Rigs:    opts.Rigs,
+		Targets: opts.Targets,
+		Days:    opts.Days,
+		DryRun:  opts.DryRun,
+	})
+	var out []DoltPruneResult
+	for _, r := range results {
+		out = append(out, DoltPruneResult{
+			Database:  r.Database,
+			Target:    r.Target,
+			Days:      r.Days,
+			Rows:      r.Rows,
+			Committed: r.Committed,
+		})
+	}
+	return out, err
+}
@@ -0,0 +1,38 @@
+// Package gt is a stable Go API for invoking Gas Town operations in-process,
+// for tools that would otherwise shell out to the gt binary.
+//
+// Open a town, then call its methods:
+//
+//	town, err := gt.Open("/path/inside/town")
+//	if err != nil {
+//		return err
+//	}
+//	report, err := town.Doctor(gt.DoctorOptions{})
+//
+// Results are plain structs defined in this package, so they stay stable as
+// the internal packages behind them change. Operations behave exactly like
+// the corresponding gt commands: they read the same configuration, shell out
+// to the same tools (dolt, bd, tmux), and write the same logs.
+package gt
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Town is a Gas Town workspace.
+type Town struct {
+	// Root is the town root directory.
+	Root string
+}
+
+// Open finds the town containing dir (walking up like gt does from the
+// current directory) and returns it.
+func Open(dir string) (*Town, error) {
+	root, err := workspace.FindOrError(dir)
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return &Town{Root: root}, nil
+}
@@ -0,0 +1,64 @@
+package gt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doctor"
+)
+
+func newTestTown(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestOpen(t *testing.T) {
+	root := newTestTown(t)
+	sub := filepath.Join(root, "gastown", "refinery")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	town, err := Open(sub)
+	if err != nil {
+		t.Fatalf("Open(%s): %v", sub, err)
+	}
+	if town.Root != root {
+		t.Errorf("Root = %q, want %q", town.Root, root)
+	}
+
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("expected error opening a directory outside any town")
+	}
+}
+
+func TestDoltStatsUnknownRig(t *testing.T) {
+	town := &Town{Root: newTestTown(t)}
+	if _, err := town.DoltStats(DoltStatsOptions{Rigs: []string{"nope"}}); err == nil {
+		t.Error("expected error for unknown rig database")
+	}
+	if results, err := town.DoltPrune(DoltPruneOptions{Rigs: []string{"nope"}}); err == nil || results != nil {
+		t.Errorf("DoltPrune() = %v, %v; want error and no results", results, err)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	tests := map[doctor.CheckStatus]string{
+		doctor.StatusOK:      CheckOK,
+		doctor.StatusWarning: CheckWarning,
+		doctor.StatusError:   CheckError,
+	}
+	for in, want := range tests {
+		if got := checkStatus(in); got != want {
+			t.Errorf("checkStatus(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gt
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cmd"
+)
+
+// PatrolDigest is the outcome of aggregating a day's patrol cycles.
+type PatrolDigest struct {
+	// Date is the digested day (YYYY-MM-DD).
+	Date string
+
+	// Cycles is the number of patrol cycles found, and ByRole breaks it
+	// down by patrol role (deacon, witness, refinery).
+	Cycles int
+	ByRole map[string]int
+
+	// BeadID is the "Patrol Report" bead created, or the one that already
+	// existed if Existing is true. Empty for a dry run or when there were
+	// no cycles.
+	BeadID   string
+	Existing bool
+
+	// Deleted is the number of ephemeral source digests deleted.
+	Deleted int
+}
+
+// DigestPatrols aggregates the patrol cycle digests for date into a daily
+// "Patrol Report" bead and deletes the ephemeral sources (as gt patrol
+// digest does). It is a no-op if the report already exists. With dryRun,
+// only the aggregate is computed. If some sources can't be deleted, the
+// digest is returned along with the error.
+func (t *Town) DigestPatrols(date time.Time, dryRun bool) (*PatrolDigest, error) {
+	r, err := cmd.DigestPatrols(t.Root, date, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	digest := &PatrolDigest{
+		Date:    r.Digest.Date,
+		Cycles:  r.Digest.TotalCycles,
+		ByRole:  r.Digest.ByRole,
+		BeadID:  r.BeadID,
+		Deleted: r.Deleted,
+	}
+	if r.ExistingID != "" {
+		digest.BeadID = r.ExistingID
+		digest.Existing = true
+	}
+	return digest, r.DeleteErr
+}